@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log/slog"
 	"os"
@@ -9,15 +10,20 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/saturnino-fabrica-de-software/rekko/internal/api"
 	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
 	"github.com/saturnino-fabrica-de-software/rekko/internal/config"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/database"
 	"github.com/saturnino-fabrica-de-software/rekko/internal/provider"
 	"github.com/saturnino-fabrica-de-software/rekko/internal/provider/deepface"
 	"github.com/saturnino-fabrica-de-software/rekko/internal/provider/mock"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/provider/rekognition"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/ratelimit"
 	"github.com/saturnino-fabrica-de-software/rekko/internal/repository"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/service"
 )
 
 func main() {
@@ -28,6 +34,9 @@ func main() {
 }
 
 func run() error {
+	selfTestOnly := flag.Bool("selftest", false, "run the register/verify/search/delete self-test against the mock provider and exit")
+	flag.Parse()
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
@@ -43,9 +52,15 @@ func run() error {
 		slog.Int("port", cfg.Port),
 	)
 
+	// One tracer logs and counts the slow queries of both the primary and the read replica
+	var tracer pgx.QueryTracer
+	if cfg.DBSlowQueryThreshold > 0 {
+		tracer = database.NewSlowQueryTracer(cfg.DBSlowQueryThreshold, logger)
+	}
+
 	// Connect to database
 	ctx := context.Background()
-	pool, err := pgxpool.New(ctx, cfg.DatabaseURL)
+	pool, err := database.NewPgxPool(ctx, poolConfig(cfg, cfg.DatabaseURL, tracer))
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
@@ -57,23 +72,92 @@ func run() error {
 	}
 	logger.Info("connected to database")
 
+	// Fail fast with an actionable error instead of cryptic SQL errors on the first search
+	if cfg.DBCheckExtensions {
+		if err := database.CheckExtensions(ctx, pool); err != nil {
+			return fmt.Errorf("database not ready: %w", err)
+		}
+	}
+
+	// Refuse to serve against a schema this build's queries were not written for
+	if cfg.DBCheckSchemaVersion {
+		if err := checkSchemaVersion(ctx, pool, cfg.DBMigrationWait, cfg.DBSchemaVersionStrict); err != nil {
+			return fmt.Errorf("database not ready: %w", err)
+		}
+	}
+
+	// Connect to read replica (optional, falls back to primary)
+	var readPool *pgxpool.Pool
+	if cfg.DatabaseReadURL != "" {
+		readPool, err = connectReadReplica(ctx, poolConfig(cfg, cfg.DatabaseReadURL, tracer))
+		if err != nil {
+			logger.Warn("read replica unavailable, using primary for metrics", slog.Any("error", err))
+		} else {
+			defer readPool.Close()
+			logger.Info("connected to read replica")
+		}
+	}
+
 	// Create repositories
 	tenantRepo := repository.NewTenantRepository(pool)
 	apiKeyRepo := repository.NewAPIKeyRepository(pool)
 	faceRepo := repository.NewFaceRepository(pool)
 	verificationRepo := repository.NewVerificationRepository(pool)
 
+	// Self-test: exit with its result for CI smoke tests, or log it and keep booting
+	if *selfTestOnly || cfg.SelfTestOnBoot {
+		report := runSelfTest(ctx, pool, tenantRepo, faceRepo, verificationRepo, logger)
+		if *selfTestOnly {
+			return report.Err()
+		}
+	}
+
 	// Create face provider based on configuration
-	var faceProvider provider.FaceProvider
-	switch cfg.FaceProvider {
+	faceProvider := newFaceProvider(cfg, logger)
+
+	// Optional shadow provider evaluated in parallel for opted-in tenants
+	var shadowProvider provider.FaceProvider
+	switch cfg.ShadowFaceProvider {
 	case "deepface":
 		dfConfig := deepface.DefaultConfig()
-		dfConfig.BaseURL = cfg.DeepFaceURL
-		faceProvider = deepface.NewProvider(dfConfig)
-		logger.Info("using deepface provider", "url", cfg.DeepFaceURL)
-	default:
-		faceProvider = mock.New()
-		logger.Info("using mock face provider")
+		dfConfig.BaseURL = cfg.ShadowDeepFaceURL
+		shadowProvider = deepface.NewProvider(dfConfig)
+		logger.Info("using deepface shadow provider", "url", cfg.ShadowDeepFaceURL)
+	case "mock":
+		shadowProvider = mock.New()
+		logger.Info("using mock shadow provider")
+	}
+
+	// Self-serve tenants provisioned on first key use get their Rekognition collection up front,
+	// deleted tenants have it removed so it stops incurring cost, and face recounts compare
+	// the database with it
+	var (
+		collections      service.CollectionEnsurer
+		collectionPurger service.CollectionDeleter
+		faceCounter      service.CollectionCounter
+	)
+	if cfg.FaceProvider == "rekognition" {
+		client, err := rekognition.NewClient(ctx, rekognition.Config{
+			Region:           cfg.AWSRegion,
+			CollectionPrefix: "rekko-",
+			CostTags:         cfg.RekognitionCostTags,
+			MaxTPS:           cfg.RekognitionMaxTPS,
+			MaxConcurrency:   cfg.RekognitionMaxConcurrency,
+			MaxWait:          cfg.RekognitionMaxWait,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create rekognition client: %w", err)
+		}
+		if cfg.AutoProvisionTenants {
+			collections = client
+		}
+		if cfg.TenantDeletePurgeCollection {
+			collectionPurger = client
+		}
+		faceCounter = client
+	}
+	if cfg.AutoProvisionTenants {
+		logger.Info("tenant auto-provisioning enabled")
 	}
 
 	// Create last used worker for async API key updates
@@ -92,8 +176,14 @@ func run() error {
 		FaceRepo:         faceRepo,
 		VerificationRepo: verificationRepo,
 		FaceProvider:     faceProvider,
+		ShadowProvider:   shadowProvider,
+		Collections:      collections,
+		CollectionPurger: collectionPurger,
+		FaceCounter:      faceCounter,
 		LastUsedWorker:   lastUsedWorker,
 		DB:               pool,
+		ReadDB:           readPool,
+		Config:           cfg,
 	}
 
 	// Setup router with dependencies
@@ -123,11 +213,11 @@ func run() error {
 	}
 
 	// Graceful shutdown with timeout
-	gracefulCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	gracefulCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 	defer cancel()
 
 	logger.Info("shutting down server...")
-	if err := router.Shutdown(); err != nil {
+	if err := router.Shutdown(gracefulCtx); err != nil {
 		logger.Error("shutdown error", slog.Any("error", err))
 	}
 
@@ -136,3 +226,99 @@ func run() error {
 
 	return nil
 }
+
+// newFaceProvider builds the recognition provider, delegating liveness to LIVENESS_PROVIDER when set
+func newFaceProvider(cfg *config.Config, logger *slog.Logger) provider.FaceProvider {
+	var faceProvider provider.FaceProvider
+	switch cfg.FaceProvider {
+	case "deepface":
+		dfConfig := deepface.DefaultConfig()
+		dfConfig.Timeout = cfg.MaxProviderTimeout()
+		dfConfig.BaseURL = cfg.DeepFaceURL
+		faceProvider = deepface.NewProvider(dfConfig)
+		logger.Info("using deepface provider", "url", cfg.DeepFaceURL)
+	default:
+		faceProvider = mock.New()
+		logger.Info("using mock face provider")
+	}
+
+	var liveness provider.LivenessProvider
+	switch cfg.LivenessProvider {
+	case "deepface":
+		dfConfig := deepface.DefaultConfig()
+		dfConfig.Timeout = cfg.MaxProviderTimeout()
+		dfConfig.BaseURL = cfg.LivenessDeepFaceURL
+		liveness = deepface.NewProvider(dfConfig)
+		logger.Info("using deepface liveness provider", "url", cfg.LivenessDeepFaceURL)
+	case "mock":
+		liveness = mock.New()
+		logger.Info("using mock liveness provider")
+	default:
+		return faceProvider
+	}
+
+	return provider.WithLivenessProvider(faceProvider, liveness)
+}
+
+// runSelfTest runs the pipeline self-test for a throwaway tenant against the mock provider
+// and logs each step
+func runSelfTest(ctx context.Context, pool *pgxpool.Pool, tenantRepo *repository.TenantRepository, faceRepo *repository.FaceRepository, verificationRepo *repository.VerificationRepository, logger *slog.Logger) *service.SelfTestReport {
+	faceService := service.NewFaceService(
+		faceRepo,
+		verificationRepo,
+		repository.NewSearchAuditRepository(pool),
+		mock.New(),
+		ratelimit.NewRateLimiter(pool, time.Minute),
+	)
+
+	report := service.NewSelfTest(faceService, tenantRepo).Run(ctx)
+	for _, step := range report.Steps {
+		logger.Info("selftest step",
+			slog.String("step", step.Name),
+			slog.Bool("passed", step.Passed),
+			slog.String("error", step.Error),
+			slog.Duration("duration", step.Duration),
+		)
+	}
+
+	if err := report.Err(); err != nil {
+		logger.Error("selftest failed", slog.Any("error", err), slog.String("tenant_id", report.TenantID.String()))
+	} else {
+		logger.Info("selftest passed")
+	}
+
+	return report
+}
+
+// checkSchemaVersion waits up to wait for a running migration, then checks the schema version
+func checkSchemaVersion(ctx context.Context, pool *pgxpool.Pool, wait time.Duration, strict bool) error {
+	ctx, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+	return database.CheckSchemaVersion(ctx, pool, strict)
+}
+
+// poolConfig applies the configured pool sizing and query tracer to the given database url
+func poolConfig(cfg *config.Config, url string, tracer pgx.QueryTracer) database.PgxPoolConfig {
+	return database.PgxPoolConfig{
+		DSN:             url,
+		MaxConns:        cfg.DBMaxConns,
+		MinConns:        cfg.DBMinConns,
+		MaxConnLifetime: cfg.DBMaxConnLifetime,
+		Tracer:          tracer,
+	}
+}
+
+// connectReadReplica opens and verifies the read replica pool
+func connectReadReplica(ctx context.Context, poolCfg database.PgxPoolConfig) (*pgxpool.Pool, error) {
+	pool, err := database.NewPgxPool(ctx, poolCfg)
+	if err != nil {
+		return nil, fmt.Errorf("connect read replica: %w", err)
+	}
+
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("ping read replica: %w", err)
+	}
+
+	return pool, nil
+}
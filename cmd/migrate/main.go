@@ -20,7 +20,7 @@ func main() {
 
 func run() error {
 	// Flags
-	action := flag.String("action", "up", "Migration action: up, down, version, force")
+	action := flag.String("action", "up", "Migration action: up, down, version, check, force")
 	steps := flag.Int("steps", 0, "Number of migration steps (for force action)")
 	flag.Parse()
 
@@ -80,6 +80,18 @@ func run() error {
 		} else {
 			log.Printf("Current version: %d\n", version)
 		}
+		expected, err := migrator.ExpectedVersion()
+		if err != nil {
+			return fmt.Errorf("failed to get expected version: %w", err)
+		}
+		log.Printf("Expected version: %d\n", expected)
+
+	case "check":
+		// Exits non-zero when the API built from this tree would refuse to start
+		if err := migrator.CheckVersion(cfg.DBSchemaVersionStrict); err != nil {
+			return fmt.Errorf("schema version check failed: %w", err)
+		}
+		log.Println("✓ Schema version is compatible with this build")
 
 	case "force":
 		if *steps == 0 {
@@ -92,7 +104,7 @@ func run() error {
 		log.Println("✓ Migration version forced successfully")
 
 	default:
-		return fmt.Errorf("invalid action: %s (use: up, down, version, force)", *action)
+		return fmt.Errorf("invalid action: %s (use: up, down, version, check, force)", *action)
 	}
 
 	return nil
@@ -4,12 +4,25 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 )
 
+// DB defines the read operations used by metrics queries
+type DB interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+}
+
+// TxDB defines the primary pool operations used by transactional writes
+type TxDB interface {
+	DB
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
 // SuperAdminService defines the interface for super admin operations
 type SuperAdminService interface {
 	// Tenant operations
-	ListAllTenants(ctx context.Context, limit, offset int) ([]TenantWithMetrics, error)
+	ListAllTenants(ctx context.Context, params TenantListParams) ([]TenantWithMetrics, error)
 	GetTenantDetailedMetrics(ctx context.Context, tenantID uuid.UUID) (*TenantMetricsSummary, error)
 	UpdateTenantQuota(ctx context.Context, tenantID uuid.UUID, req UpdateQuotaRequest) error
 
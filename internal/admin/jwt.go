@@ -8,6 +8,13 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	// RoleImpersonation marks read-only tokens a super admin issues to see what a tenant sees
+	RoleImpersonation = "impersonation"
+	// ImpersonationTTL is how long an impersonation token stays valid
+	ImpersonationTTL = 15 * time.Minute
+)
+
 var (
 	// ErrInvalidToken is returned when token validation fails
 	ErrInvalidToken = errors.New("invalid token")
@@ -22,6 +29,8 @@ type AdminClaims struct {
 	UserID uuid.UUID `json:"user_id"`
 	Email  string    `json:"email"`
 	Role   string    `json:"role"`
+	// TenantID is the impersonated tenant, set only on impersonation tokens
+	TenantID *uuid.UUID `json:"tenant_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -30,6 +39,8 @@ type JWTService struct {
 	secretKey []byte
 	issuer    string
 	expiresIn time.Duration
+	// leeway tolerates clock skew between issuer and validator on exp/nbf
+	leeway time.Duration
 }
 
 // NewJWTService creates a new JWT service
@@ -41,6 +52,13 @@ func NewJWTService(secretKey, issuer string, expiresIn time.Duration) *JWTServic
 	}
 }
 
+// WithLeeway accepts tokens whose exp or nbf is off by at most leeway,
+// so small clock differences between servers don't reject valid tokens.
+func (s *JWTService) WithLeeway(leeway time.Duration) *JWTService {
+	s.leeway = leeway
+	return s
+}
+
 // GenerateToken generates a new JWT token for admin user
 func (s *JWTService) GenerateToken(userID uuid.UUID, email, role string) (string, error) {
 	now := time.Now()
@@ -61,6 +79,32 @@ func (s *JWTService) GenerateToken(userID uuid.UUID, email, role string) (string
 	return token.SignedString(s.secretKey)
 }
 
+// GenerateImpersonationToken issues a short-lived read-only token bound to tenantID.
+// The claims keep the super admin's identity so every use can be attributed to them.
+func (s *JWTService) GenerateImpersonationToken(userID uuid.UUID, email string, tenantID uuid.UUID) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ImpersonationTTL)
+	claims := AdminClaims{
+		UserID:   userID,
+		Email:    email,
+		Role:     RoleImpersonation,
+		TenantID: &tenantID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.issuer,
+			Subject:   userID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secretKey)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
 // ValidateToken validates and parses a JWT token
 func (s *JWTService) ValidateToken(tokenString string) (*AdminClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &AdminClaims{}, func(token *jwt.Token) (interface{}, error) {
@@ -69,7 +113,7 @@ func (s *JWTService) ValidateToken(tokenString string) (*AdminClaims, error) {
 			return nil, ErrInvalidToken
 		}
 		return s.secretKey, nil
-	})
+	}, jwt.WithLeeway(s.leeway))
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -92,6 +136,10 @@ func (s *JWTService) RefreshToken(oldToken string) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	// Impersonation tokens are short-lived by design and cannot be extended
+	if claims.Role == RoleImpersonation {
+		return "", ErrInvalidClaims
+	}
 
 	return s.GenerateToken(claims.UserID, claims.Email, claims.Role)
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -83,6 +84,53 @@ func TestJWTService_ValidateToken_ExpiredToken(t *testing.T) {
 	assert.ErrorIs(t, err, ErrExpiredToken)
 }
 
+func TestJWTService_ValidateToken_Leeway(t *testing.T) {
+	service := NewJWTService("test-secret-key", "rekko-test", time.Hour).WithLeeway(30 * time.Second)
+
+	sign := func(t *testing.T, expiresAt, notBefore time.Time) string {
+		t.Helper()
+		claims := AdminClaims{
+			UserID: uuid.New(),
+			Email:  testEmail,
+			Role:   testRole,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    "rekko-test",
+				IssuedAt:  jwt.NewNumericDate(notBefore),
+				ExpiresAt: jwt.NewNumericDate(expiresAt),
+				NotBefore: jwt.NewNumericDate(notBefore),
+			},
+		}
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-secret-key"))
+		require.NoError(t, err)
+		return token
+	}
+
+	now := time.Now()
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		notBefore time.Time
+		wantErr   error
+	}{
+		{name: "expired within leeway", expiresAt: now.Add(-10 * time.Second), notBefore: now.Add(-time.Hour)},
+		{name: "expired past leeway", expiresAt: now.Add(-time.Minute), notBefore: now.Add(-time.Hour), wantErr: ErrExpiredToken},
+		{name: "not yet valid within leeway", expiresAt: now.Add(time.Hour), notBefore: now.Add(10 * time.Second)},
+		{name: "not yet valid past leeway", expiresAt: now.Add(time.Hour), notBefore: now.Add(time.Minute), wantErr: ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := service.ValidateToken(sign(t, tt.expiresAt, tt.notBefore))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testEmail, claims.Email)
+		})
+	}
+}
+
 func TestJWTService_ValidateToken_DifferentSecret(t *testing.T) {
 	service1 := NewJWTService("secret-1", "rekko-test", 1*time.Hour)
 	service2 := NewJWTService("secret-2", "rekko-test", 1*time.Hour)
@@ -127,3 +175,25 @@ func TestJWTService_RefreshToken_InvalidToken(t *testing.T) {
 	_, err := service.RefreshToken("invalid.token")
 	assert.ErrorIs(t, err, ErrInvalidToken)
 }
+
+func TestJWTService_GenerateImpersonationToken(t *testing.T) {
+	service := NewJWTService("test-secret-key", "rekko-test", 24*time.Hour)
+	userID := uuid.New()
+	tenantID := uuid.New()
+
+	token, expiresAt, err := service.GenerateImpersonationToken(userID, testEmail, tenantID)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(ImpersonationTTL), expiresAt, 5*time.Second)
+
+	claims, err := service.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, RoleImpersonation, claims.Role)
+	assert.Equal(t, userID, claims.UserID)
+	assert.Equal(t, testEmail, claims.Email)
+	require.NotNil(t, claims.TenantID)
+	assert.Equal(t, tenantID, *claims.TenantID)
+	assert.WithinDuration(t, expiresAt, claims.ExpiresAt.Time, time.Second, "short-lived regardless of the service expiry")
+
+	_, err = service.RefreshToken(token)
+	assert.ErrorIs(t, err, ErrInvalidClaims, "impersonation tokens cannot be extended")
+}
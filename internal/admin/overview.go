@@ -0,0 +1,98 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/database"
+)
+
+const (
+	// OverviewWindow is the trailing window error rate and RPS are computed over
+	OverviewWindow = 5 * time.Minute
+
+	// overviewCacheTTL bounds how often the overview query runs per tenant,
+	// however many dashboards are streaming it
+	overviewCacheTTL = 2 * time.Second
+)
+
+type cachedOverview struct {
+	overview  *MetricsOverview
+	expiresAt time.Time
+}
+
+// overviewCache shares recent overviews between concurrent streams of the same tenant
+type overviewCache struct {
+	mu      sync.Mutex
+	entries map[uuid.UUID]cachedOverview
+}
+
+func (c *overviewCache) get(tenantID uuid.UUID, now time.Time) (*MetricsOverview, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[tenantID]
+	if !ok || now.After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.overview, true
+}
+
+func (c *overviewCache) put(tenantID uuid.UUID, overview *MetricsOverview, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = make(map[uuid.UUID]cachedOverview)
+	}
+	// Drop expired tenants so the cache only holds tenants being watched
+	for id, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, id)
+		}
+	}
+	c.entries[tenantID] = cachedOverview{overview: overview, expiresAt: now.Add(overviewCacheTTL)}
+}
+
+// GetMetricsOverview returns the tenant's headline metrics, served from a short-lived cache
+// so live dashboards polling or streaming it cost one query per tenant per TTL
+func (s *Service) GetMetricsOverview(ctx context.Context, tenantID uuid.UUID) (*MetricsOverview, error) {
+	now := time.Now()
+	if overview, ok := s.overviews.get(tenantID, now); ok {
+		return overview, nil
+	}
+
+	var totalFaces, totalVerifications, recentFaces, recentVerifications, recentFailures int64
+	err := s.reader().QueryRow(database.WithQueryLabel(ctx, "admin.metrics_overview"), `
+		SELECT
+			(SELECT COUNT(*) FROM faces WHERE tenant_id = $1),
+			(SELECT COUNT(*) FROM verifications WHERE tenant_id = $1),
+			(SELECT COUNT(*) FROM faces WHERE tenant_id = $1 AND created_at >= $2),
+			(SELECT COUNT(*) FROM verifications WHERE tenant_id = $1 AND created_at >= $2),
+			(SELECT COUNT(*) FROM verifications WHERE tenant_id = $1 AND created_at >= $2 AND verified = false)
+	`, tenantID, now.Add(-OverviewWindow)).Scan(&totalFaces, &totalVerifications, &recentFaces, &recentVerifications, &recentFailures)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: failed to query metrics overview: %w", tenantID, err)
+	}
+
+	errorRate := 0.0
+	if recentVerifications > 0 {
+		errorRate = float64(recentFailures) / float64(recentVerifications) * 100
+	}
+
+	overview := &MetricsOverview{
+		TotalFaces:         totalFaces,
+		TotalVerifications: totalVerifications,
+		ErrorRate:          errorRate,
+		RequestsPerSecond:  float64(recentFaces+recentVerifications) / OverviewWindow.Seconds(),
+		Window:             OverviewWindow.String(),
+		GeneratedAt:        now,
+	}
+	s.overviews.put(tenantID, overview, now)
+
+	return overview, nil
+}
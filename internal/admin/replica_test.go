@@ -0,0 +1,659 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_WithReadReplica_UsesReplicaForMetrics(t *testing.T) {
+	replica, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer replica.Close()
+
+	tenantID := uuid.New()
+
+	// Primary pool is nil: any query reaching it would panic
+	svc := NewService(nil, nil, slog.New(slog.NewTextHandler(os.Stdout, nil))).
+		WithReadReplica(replica)
+
+	replica.ExpectQuery("SELECT COUNT").
+		WithArgs(tenantID, false).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(int64(42)))
+	replica.ExpectQuery("SELECT").
+		WithArgs(pgxmock.AnyArg(), tenantID, pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), false).
+		WillReturnRows(pgxmock.NewRows([]string{"period", "registered"}))
+
+	result, err := svc.GetFacesMetrics(context.Background(), tenantID, MetricsParams{Interval: "day", Limit: 10})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(42), result.TotalRegistered)
+	assert.NoError(t, replica.ExpectationsWereMet())
+}
+
+func TestService_GetFacesMetrics_ExcludesTestData(t *testing.T) {
+	replica, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer replica.Close()
+
+	tenantID := uuid.New()
+	svc := NewService(nil, nil, slog.New(slog.NewTextHandler(os.Stdout, nil))).
+		WithReadReplica(replica)
+
+	// Faces registered with test-environment keys are filtered out by the database
+	replica.ExpectQuery(`FROM faces\s+WHERE tenant_id = \$1\s+AND NOT \(\$2 AND is_test\)`).
+		WithArgs(tenantID, true).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(int64(7)))
+	replica.ExpectQuery(`AND created_at BETWEEN \$3 AND \$4\s+AND NOT \(\$7 AND is_test\)`).
+		WithArgs(pgxmock.AnyArg(), tenantID, pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), true).
+		WillReturnRows(pgxmock.NewRows([]string{"period", "registered"}).AddRow("2025-01-02", int64(7)))
+
+	result, err := svc.GetFacesMetrics(context.Background(), tenantID, MetricsParams{Interval: "day", Limit: 10, ExcludeTest: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(7), result.TotalRegistered)
+	require.Len(t, result.Timeline, 1)
+	assert.Equal(t, int64(7), result.Timeline[0].Registered)
+	assert.NoError(t, replica.ExpectationsWereMet())
+}
+
+func TestService_Reader_FallsBackToPrimary(t *testing.T) {
+	svc := NewService(nil, nil, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+
+	assert.Equal(t, DB(svc.db), svc.reader())
+}
+
+func TestService_GetFaceTrend(t *testing.T) {
+	replica, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer replica.Close()
+
+	tenantID := uuid.New()
+	params := MetricsParams{
+		StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		Interval:  "day",
+		Limit:     2,
+	}
+
+	svc := NewService(nil, nil, slog.New(slog.NewTextHandler(os.Stdout, nil))).
+		WithReadReplica(replica)
+
+	// Totals span the whole period while the timeline holds one page of it
+	replica.ExpectQuery(`COUNT\(DISTINCT date_trunc\(\$1, created_at\)\)\s+FROM verifications\s+WHERE tenant_id = \$2\s+AND external_id = \$3`).
+		WithArgs("day", tenantID, "user-123", params.StartDate, params.EndDate, false).
+		WillReturnRows(pgxmock.NewRows([]string{"count", "successes", "failures", "periods"}).
+			AddRow(int64(10), int64(6), int64(4), 5))
+	replica.ExpectQuery(`FROM verifications\s+WHERE tenant_id = \$2\s+AND external_id = \$3`).
+		WithArgs("day", tenantID, "user-123", params.StartDate, params.EndDate, 2, 0, false).
+		WillReturnRows(pgxmock.NewRows([]string{"period", "attempts", "successes", "failures"}).
+			AddRow("2025-01-02", int64(3), int64(2), int64(1)).
+			AddRow("2025-01-05", int64(1), int64(0), int64(1)))
+
+	trend, err := svc.GetFaceTrend(context.Background(), tenantID, "user-123", params)
+	require.NoError(t, err)
+
+	assert.Equal(t, "user-123", trend.ExternalID)
+	require.Len(t, trend.Timeline, 2)
+	assert.Equal(t, "2025-01-02", trend.Timeline[0].Period)
+	assert.Equal(t, int64(2), trend.Timeline[0].Successes)
+	assert.Equal(t, int64(10), trend.TotalAttempts)
+	assert.Equal(t, int64(6), trend.Successes)
+	assert.Equal(t, int64(4), trend.Failures)
+	assert.Equal(t, 5, trend.TotalPeriods)
+	assert.InDelta(t, 0.6, trend.SuccessRate, 0.0001)
+	assert.NoError(t, replica.ExpectationsWereMet())
+}
+
+func TestService_GetFaceTrend_NoAttempts(t *testing.T) {
+	replica, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer replica.Close()
+
+	svc := NewService(nil, nil, slog.New(slog.NewTextHandler(os.Stdout, nil))).
+		WithReadReplica(replica)
+
+	replica.ExpectQuery("FROM verifications").
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), "user-404", pgxmock.AnyArg(), pgxmock.AnyArg(), false).
+		WillReturnRows(pgxmock.NewRows([]string{"count", "successes", "failures", "periods"}).
+			AddRow(int64(0), int64(0), int64(0), 0))
+	replica.ExpectQuery("FROM verifications").
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), "user-404", pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), false).
+		WillReturnRows(pgxmock.NewRows([]string{"period", "attempts", "successes", "failures"}))
+
+	trend, err := svc.GetFaceTrend(context.Background(), uuid.New(), "user-404", MetricsParams{Interval: "day", Limit: 10})
+	require.NoError(t, err)
+
+	assert.NotNil(t, trend.Timeline)
+	assert.Empty(t, trend.Timeline)
+	assert.Zero(t, trend.SuccessRate)
+	assert.NoError(t, replica.ExpectationsWereMet())
+}
+
+func TestService_GetOperationsMetrics_ByFailReason(t *testing.T) {
+	replica, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer replica.Close()
+
+	tenantID := uuid.New()
+	params := MetricsParams{
+		StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		Interval:  "day",
+		Limit:     100,
+	}
+
+	svc := NewService(nil, nil, slog.New(slog.NewTextHandler(os.Stdout, nil))).
+		WithReadReplica(replica)
+
+	replica.ExpectQuery("SELECT COUNT").
+		WithArgs(tenantID, false).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(int64(20)))
+	replica.ExpectQuery("date_trunc").
+		WithArgs("day", tenantID, params.StartDate, params.EndDate, 100, 0, false).
+		WillReturnRows(pgxmock.NewRows([]string{"period", "total", "success", "failure"}).
+			AddRow("2025-01-02", int64(20), int64(12), int64(8)))
+	replica.ExpectQuery(`SELECT fail_reason, COUNT\(\*\)[\s\S]*AND fail_reason IS NOT NULL\s+GROUP BY fail_reason`).
+		WithArgs(tenantID, params.StartDate, params.EndDate, false).
+		WillReturnRows(pgxmock.NewRows([]string{"fail_reason", "count"}).
+			AddRow("match_below_threshold", int64(5)).
+			AddRow("liveness_failed", int64(2)).
+			AddRow("no_face", int64(1)))
+
+	metrics, err := svc.GetOperationsMetrics(context.Background(), tenantID, params)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int64{
+		"match_below_threshold": 5,
+		"liveness_failed":       2,
+		"no_face":               1,
+	}, metrics.ByFailReason)
+	assert.Nil(t, metrics.ByProvider, "providers are only grouped with group_by=provider")
+	assert.NoError(t, replica.ExpectationsWereMet())
+}
+
+func TestService_GetOperationsMetrics_ByProvider(t *testing.T) {
+	replica, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer replica.Close()
+
+	tenantID := uuid.New()
+	params := MetricsParams{
+		StartDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:     time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		Interval:    "day",
+		Limit:       100,
+		ExcludeTest: true,
+		GroupBy:     GroupByProvider,
+	}
+
+	svc := NewService(nil, nil, slog.New(slog.NewTextHandler(os.Stdout, nil))).
+		WithReadReplica(replica)
+
+	replica.ExpectQuery("SELECT COUNT").
+		WithArgs(tenantID, true).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(int64(20)))
+	replica.ExpectQuery("date_trunc").
+		WithArgs("day", tenantID, params.StartDate, params.EndDate, 100, 0, true).
+		WillReturnRows(pgxmock.NewRows([]string{"period", "total", "success", "failure"}))
+	replica.ExpectQuery("SELECT fail_reason").
+		WithArgs(tenantID, params.StartDate, params.EndDate, true).
+		WillReturnRows(pgxmock.NewRows([]string{"fail_reason", "count"}))
+	replica.ExpectQuery(`COALESCE\(provider, \$5\)`).
+		WithArgs(tenantID, params.StartDate, params.EndDate, true, UnknownProvider).
+		WillReturnRows(pgxmock.NewRows([]string{"provider", "total", "success", "failure"}).
+			AddRow("deepface", int64(16), int64(12), int64(4)).
+			AddRow("rekognition", int64(4), int64(4), int64(0)))
+
+	metrics, err := svc.GetOperationsMetrics(context.Background(), tenantID, params)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]ProviderMetrics{
+		"deepface":    {Total: 16, Success: 12, Failure: 4, ErrorRate: 25},
+		"rekognition": {Total: 4, Success: 4},
+	}, metrics.ByProvider)
+	assert.NoError(t, replica.ExpectationsWereMet())
+}
+
+func TestService_GetErrorMetrics_ByProvider(t *testing.T) {
+	replica, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer replica.Close()
+
+	tenantID := uuid.New()
+	params := MetricsParams{
+		StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		Interval:  "day",
+		Limit:     100,
+		GroupBy:   GroupByProvider,
+	}
+
+	svc := NewService(nil, nil, slog.New(slog.NewTextHandler(os.Stdout, nil))).
+		WithReadReplica(replica)
+
+	replica.ExpectQuery("total_errors").
+		WithArgs(tenantID, params.StartDate, params.EndDate, false).
+		WillReturnRows(pgxmock.NewRows([]string{"total_errors", "total_ops"}).AddRow(int64(15), int64(100)))
+	replica.ExpectQuery("date_trunc").
+		WithArgs("day", tenantID, params.StartDate, params.EndDate, 100, 0, false).
+		WillReturnRows(pgxmock.NewRows([]string{"period", "total", "errors", "error_rate"}).
+			AddRow("2025-01-02", int64(100), int64(15), 15.0))
+	replica.ExpectQuery(`COALESCE\(provider, \$5\) as provider[\s\S]*GROUP BY 1`).
+		WithArgs(tenantID, params.StartDate, params.EndDate, false, UnknownProvider).
+		WillReturnRows(pgxmock.NewRows([]string{"provider", "total", "success", "failure"}).
+			AddRow("deepface", int64(80), int64(72), int64(8)).
+			AddRow("mock", int64(10), int64(9), int64(1)).
+			AddRow(UnknownProvider, int64(10), int64(4), int64(6)))
+
+	metrics, err := svc.GetErrorMetrics(context.Background(), tenantID, params)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]ProviderMetrics{
+		"deepface":      {Total: 80, Success: 72, Failure: 8, ErrorRate: 10},
+		"mock":          {Total: 10, Success: 9, Failure: 1, ErrorRate: 10},
+		UnknownProvider: {Total: 10, Success: 4, Failure: 6, ErrorRate: 60},
+	}, metrics.ByProvider)
+	assert.NoError(t, replica.ExpectationsWereMet())
+}
+
+func TestService_GetGateMetrics(t *testing.T) {
+	replica, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer replica.Close()
+
+	tenantID := uuid.New()
+	params := MetricsParams{
+		StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+	}
+
+	svc := NewService(nil, nil, slog.New(slog.NewTextHandler(os.Stdout, nil))).
+		WithReadReplica(replica)
+
+	replica.ExpectQuery(`COALESCE\(host\(client_ip\), \$5\) as gate[\s\S]*GROUP BY gate`).
+		WithArgs(tenantID, params.StartDate, params.EndDate, false, UnknownGate).
+		WillReturnRows(pgxmock.NewRows([]string{"gate", "verifications", "successes", "avg_latency_ms"}).
+			AddRow("10.0.0.11", int64(8), int64(6), 120.5).
+			AddRow("10.0.0.12", int64(4), int64(1), 310.0).
+			AddRow(UnknownGate, int64(2), int64(2), 95.0))
+
+	metrics, err := svc.GetGateMetrics(context.Background(), tenantID, params)
+	require.NoError(t, err)
+
+	require.Len(t, metrics.Gates, 3)
+	assert.Equal(t, "10.0.0.11", metrics.Gates[0].Gate)
+	assert.Equal(t, int64(8), metrics.Gates[0].Verifications)
+	assert.Equal(t, int64(2), metrics.Gates[0].Failures)
+	assert.InDelta(t, 0.75, metrics.Gates[0].SuccessRate, 0.0001)
+	assert.InDelta(t, 120.5, metrics.Gates[0].AvgLatencyMs, 0.0001)
+	assert.Equal(t, int64(3), metrics.Gates[1].Failures)
+	assert.InDelta(t, 0.25, metrics.Gates[1].SuccessRate, 0.0001)
+	assert.Equal(t, UnknownGate, metrics.Gates[2].Gate)
+	assert.InDelta(t, 1.0, metrics.Gates[2].SuccessRate, 0.0001)
+	assert.NoError(t, replica.ExpectationsWereMet())
+}
+
+func TestService_GetGateMetrics_NoVerifications(t *testing.T) {
+	replica, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer replica.Close()
+
+	svc := NewService(nil, nil, slog.New(slog.NewTextHandler(os.Stdout, nil))).
+		WithReadReplica(replica)
+
+	replica.ExpectQuery("FROM verifications").
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), true, UnknownGate).
+		WillReturnRows(pgxmock.NewRows([]string{"gate", "verifications", "successes", "avg_latency_ms"}))
+
+	metrics, err := svc.GetGateMetrics(context.Background(), uuid.New(), MetricsParams{ExcludeTest: true})
+	require.NoError(t, err)
+
+	assert.NotNil(t, metrics.Gates)
+	assert.Empty(t, metrics.Gates)
+	assert.NoError(t, replica.ExpectationsWereMet())
+}
+
+func TestService_GetTopIdentities(t *testing.T) {
+	replica, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer replica.Close()
+
+	tenantID := uuid.New()
+	params := MetricsParams{
+		StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		Limit:     3,
+	}
+	lastSeen := time.Date(2025, 1, 30, 18, 0, 0, 0, time.UTC)
+
+	svc := NewService(nil, nil, slog.New(slog.NewTextHandler(os.Stdout, nil))).
+		WithReadReplica(replica)
+
+	// Only successful verifications are ranked, most verified first
+	replica.ExpectQuery(`FROM verifications\s+WHERE tenant_id = \$1\s+AND verified = true[\s\S]*GROUP BY external_id\s+ORDER BY verifications DESC, last_seen_at DESC, external_id ASC\s+LIMIT \$5[\s\S]*LEFT JOIN faces`).
+		WithArgs(tenantID, params.StartDate, params.EndDate, false, 3).
+		WillReturnRows(pgxmock.NewRows([]string{"external_id", "verifications", "last_seen_at", "metadata"}).
+			AddRow("staff_042", int64(87), lastSeen, map[string]interface{}{"role": "security", "document": "123.456.789-00"}).
+			AddRow("staff_007", int64(41), lastSeen.Add(-time.Hour), map[string]interface{}{"document": "987.654.321-00"}).
+			AddRow("deleted_face", int64(12), lastSeen.Add(-2*time.Hour), map[string]interface{}{}))
+
+	result, err := svc.GetTopIdentities(context.Background(), tenantID, params, []string{"role"})
+	require.NoError(t, err)
+
+	require.Len(t, result.Identities, 3)
+	assert.Equal(t, "staff_042", result.Identities[0].ExternalID)
+	assert.Equal(t, int64(87), result.Identities[0].Verifications)
+	assert.Equal(t, lastSeen, result.Identities[0].LastSeenAt)
+	// Metadata outside the allow-list never leaves the service
+	assert.Equal(t, map[string]interface{}{"role": "security"}, result.Identities[0].Metadata)
+	assert.Nil(t, result.Identities[1].Metadata)
+	assert.Equal(t, "deleted_face", result.Identities[2].ExternalID)
+	assert.Nil(t, result.Identities[2].Metadata)
+	assert.NoError(t, replica.ExpectationsWereMet())
+}
+
+func TestService_GetTopIdentities_NoVerifications(t *testing.T) {
+	replica, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer replica.Close()
+
+	svc := NewService(nil, nil, slog.New(slog.NewTextHandler(os.Stdout, nil))).
+		WithReadReplica(replica)
+
+	replica.ExpectQuery("FROM verifications").
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), true, DefaultTopIdentities).
+		WillReturnRows(pgxmock.NewRows([]string{"external_id", "verifications", "last_seen_at", "metadata"}))
+
+	result, err := svc.GetTopIdentities(context.Background(), uuid.New(), MetricsParams{ExcludeTest: true, Limit: DefaultTopIdentities}, nil)
+	require.NoError(t, err)
+
+	assert.NotNil(t, result.Identities)
+	assert.Empty(t, result.Identities)
+	assert.NoError(t, replica.ExpectationsWereMet())
+}
+
+func TestService_GetLivenessMetrics(t *testing.T) {
+	replica, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer replica.Close()
+
+	tenantID := uuid.New()
+	params := MetricsParams{
+		StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		Interval:  "day",
+		Limit:     100,
+	}
+
+	svc := NewService(nil, nil, slog.New(slog.NewTextHandler(os.Stdout, nil))).
+		WithReadReplica(replica)
+
+	replica.ExpectQuery(`COALESCE\(AVG\(liveness_score\), 0\)[\s\S]*liveness_passed IS NOT NULL`).
+		WithArgs(tenantID, params.StartDate, params.EndDate, false).
+		WillReturnRows(pgxmock.NewRows([]string{"passed", "failed", "avg_liveness_score"}).
+			AddRow(int64(9), int64(3), 0.88))
+	replica.ExpectQuery(`date_trunc\(\$1, created_at\)[\s\S]*liveness_passed IS NOT NULL[\s\S]*GROUP BY period`).
+		WithArgs("day", tenantID, params.StartDate, params.EndDate, 100, 0, false).
+		WillReturnRows(pgxmock.NewRows([]string{"period", "passed", "failed", "avg_liveness_score"}).
+			AddRow("2025-01-01", int64(6), int64(2), 0.9).
+			AddRow("2025-01-02", int64(3), int64(1), 0.82).
+			AddRow("2025-01-03", int64(0), int64(0), 0.0))
+
+	metrics, err := svc.GetLivenessMetrics(context.Background(), tenantID, params)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(9), metrics.Passed)
+	assert.Equal(t, int64(3), metrics.Failed)
+	assert.InDelta(t, 75.0, metrics.PassRate, 0.0001)
+	assert.InDelta(t, 0.88, metrics.AverageLivenessScore, 0.0001)
+
+	require.Len(t, metrics.Timeline, 3)
+	assert.Equal(t, "2025-01-01", metrics.Timeline[0].Period)
+	assert.InDelta(t, 75.0, metrics.Timeline[0].PassRate, 0.0001)
+	assert.InDelta(t, 0.9, metrics.Timeline[0].AverageLivenessScore, 0.0001)
+	assert.InDelta(t, 75.0, metrics.Timeline[1].PassRate, 0.0001)
+	assert.Zero(t, metrics.Timeline[2].PassRate)
+	assert.NoError(t, replica.ExpectationsWereMet())
+}
+
+func TestService_GetLivenessMetrics_NoVerifications(t *testing.T) {
+	replica, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer replica.Close()
+
+	svc := NewService(nil, nil, slog.New(slog.NewTextHandler(os.Stdout, nil))).
+		WithReadReplica(replica)
+
+	replica.ExpectQuery("FROM verifications").
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), true).
+		WillReturnRows(pgxmock.NewRows([]string{"passed", "failed", "avg_liveness_score"}).
+			AddRow(int64(0), int64(0), 0.0))
+	replica.ExpectQuery("GROUP BY period").
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), true).
+		WillReturnRows(pgxmock.NewRows([]string{"period", "passed", "failed", "avg_liveness_score"}))
+
+	metrics, err := svc.GetLivenessMetrics(context.Background(), uuid.New(), MetricsParams{ExcludeTest: true})
+	require.NoError(t, err)
+
+	assert.Zero(t, metrics.PassRate)
+	assert.NotNil(t, metrics.Timeline)
+	assert.Empty(t, metrics.Timeline)
+	assert.NoError(t, replica.ExpectationsWereMet())
+}
+
+func TestProjectMonthlyUsage(t *testing.T) {
+	tests := []struct {
+		name          string
+		monthToDate   int64
+		now           time.Time
+		wantProjected int64
+		wantElapsed   int
+		wantDays      int
+	}{
+		{"first day projects its own rate", 100, time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC), 3100, 1, 31},
+		{"mid month", 15000, time.Date(2025, 4, 10, 12, 0, 0, 0, time.UTC), 45000, 10, 30},
+		{"leap february", 2900, time.Date(2024, 2, 29, 23, 0, 0, 0, time.UTC), 2900, 29, 29},
+		{"rounds to nearest request", 10, time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC), 93, 3, 28},
+		{"no usage", 0, time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC), 0, 15, 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projected, elapsed, days := projectMonthlyUsage(tt.monthToDate, tt.now)
+
+			assert.Equal(t, tt.wantProjected, projected)
+			assert.Equal(t, tt.wantElapsed, elapsed)
+			assert.Equal(t, tt.wantDays, days)
+		})
+	}
+}
+
+func TestService_GetUsageForecast(t *testing.T) {
+	tenantID := uuid.New()
+	// 12 days into a 30-day month
+	now := time.Date(2025, 9, 12, 15, 0, 0, 0, time.UTC)
+	monthStart := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		monthToDate int64
+		settings    map[string]interface{}
+		wantQuota   *int
+		wantExceeds bool
+	}{
+		{"projection exceeds quota", 4800, map[string]interface{}{"max_requests_month": float64(10000)}, intPtr(10000), true},
+		{"projection within quota", 3600, map[string]interface{}{"max_requests_month": float64(10000)}, intPtr(10000), false},
+		{"quota stored as a string", 4800, map[string]interface{}{"max_requests_month": "10000"}, intPtr(10000), true},
+		{"no quota configured", 4800, map[string]interface{}{"verification_threshold": 0.8}, nil, false},
+		{"null settings", 4800, nil, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replica, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer replica.Close()
+
+			svc := NewService(nil, nil, slog.New(slog.NewTextHandler(os.Stdout, nil))).
+				WithReadReplica(replica)
+
+			replica.ExpectQuery(`FROM usage_daily\s+WHERE tenant_id = \$1\s+AND date >= \$2 AND date <= \$3`).
+				WithArgs(tenantID, monthStart, now).
+				WillReturnRows(pgxmock.NewRows([]string{"sum"}).AddRow(tt.monthToDate))
+			replica.ExpectQuery(`SELECT settings FROM tenants WHERE id = \$1`).
+				WithArgs(tenantID).
+				WillReturnRows(pgxmock.NewRows([]string{"settings"}).AddRow(tt.settings))
+
+			forecast, err := svc.GetUsageForecast(context.Background(), tenantID, now)
+			require.NoError(t, err)
+
+			assert.Equal(t, "2025-09", forecast.Month)
+			assert.Equal(t, tt.monthToDate, forecast.MonthToDate)
+			assert.Equal(t, 12, forecast.ElapsedDays)
+			assert.Equal(t, 30, forecast.DaysInMonth)
+			assert.InDelta(t, float64(tt.monthToDate)/12, forecast.DailyAverage, 0.0001)
+			assert.Equal(t, tt.monthToDate/12*30, forecast.ProjectedTotal)
+			assert.Equal(t, tt.wantQuota, forecast.MaxRequestsMonth)
+			assert.Equal(t, tt.wantExceeds, forecast.ExceedsQuota)
+			assert.NoError(t, replica.ExpectationsWereMet())
+		})
+	}
+
+	t.Run("usage query failure", func(t *testing.T) {
+		replica, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer replica.Close()
+
+		svc := NewService(nil, nil, slog.New(slog.NewTextHandler(os.Stdout, nil))).
+			WithReadReplica(replica)
+
+		replica.ExpectQuery("FROM usage_daily").
+			WithArgs(tenantID, monthStart, now).
+			WillReturnError(errors.New("connection refused"))
+
+		_, err = svc.GetUsageForecast(context.Background(), tenantID, now)
+		require.Error(t, err)
+		assert.NoError(t, replica.ExpectationsWereMet())
+	})
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestService_GetMetricsOverview_Cached(t *testing.T) {
+	replica, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer replica.Close()
+
+	tenantID := uuid.New()
+	svc := NewService(nil, nil, slog.New(slog.NewTextHandler(os.Stdout, nil))).
+		WithReadReplica(replica)
+
+	// Only one query is expected: the second read within the TTL is served from cache
+	replica.ExpectQuery(`FROM verifications WHERE tenant_id = \$1 AND created_at >= \$2 AND verified = false`).
+		WithArgs(tenantID, pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"faces", "verifications", "recent_faces", "recent_verifications", "recent_failures"}).
+			AddRow(int64(100), int64(900), int64(30), int64(120), int64(6)))
+
+	overview, err := svc.GetMetricsOverview(context.Background(), tenantID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), overview.TotalFaces)
+	assert.Equal(t, int64(900), overview.TotalVerifications)
+	assert.InDelta(t, 5.0, overview.ErrorRate, 1e-9)
+	assert.InDelta(t, 150.0/OverviewWindow.Seconds(), overview.RequestsPerSecond, 1e-9)
+
+	cached, err := svc.GetMetricsOverview(context.Background(), tenantID)
+	require.NoError(t, err)
+	assert.Same(t, overview, cached)
+	assert.NoError(t, replica.ExpectationsWereMet())
+}
+
+func TestService_ListAllTenants_FiltersAndSort(t *testing.T) {
+	active := true
+	columns := []string{"id", "name", "plan", "is_active", "created_at", "total_faces", "total_requests", "avg_latency", "error_rate"}
+
+	tests := []struct {
+		name      string
+		params    TenantListParams
+		wantOrder string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "no filters sorts newest first",
+			params:    TenantListParams{Limit: 50},
+			wantOrder: `ORDER BY t\.created_at DESC\s+LIMIT`,
+			wantArgs:  []interface{}{50, 0, "", (*bool)(nil), ""},
+		},
+		{
+			name:      "plan",
+			params:    TenantListParams{Limit: 50, Plan: "pro"},
+			wantOrder: `ORDER BY t\.created_at DESC`,
+			wantArgs:  []interface{}{50, 0, "pro", (*bool)(nil), ""},
+		},
+		{
+			name:      "is_active",
+			params:    TenantListParams{Limit: 50, IsActive: &active},
+			wantOrder: `ORDER BY t\.created_at DESC`,
+			wantArgs:  []interface{}{50, 0, "", &active, ""},
+		},
+		{
+			name:      "search escapes LIKE wildcards",
+			params:    TenantListParams{Limit: 50, Search: "acme_50%"},
+			wantOrder: `ORDER BY t\.created_at DESC`,
+			wantArgs:  []interface{}{50, 0, "", (*bool)(nil), `%acme\_50\%%`},
+		},
+		{
+			name:      "sort by faces",
+			params:    TenantListParams{Limit: 10, Offset: 20, Sort: TenantSortFaces},
+			wantOrder: `ORDER BY total_faces DESC, t\.created_at DESC`,
+			wantArgs:  []interface{}{10, 20, "", (*bool)(nil), ""},
+		},
+		{
+			name:      "sort by requests",
+			params:    TenantListParams{Limit: 50, Sort: TenantSortRequests},
+			wantOrder: `ORDER BY total_requests DESC, t\.created_at DESC`,
+			wantArgs:  []interface{}{50, 0, "", (*bool)(nil), ""},
+		},
+		{
+			name:      "sort by error rate",
+			params:    TenantListParams{Limit: 50, Sort: TenantSortErrorRate},
+			wantOrder: `ORDER BY error_rate DESC, t\.created_at DESC`,
+			wantArgs:  []interface{}{50, 0, "", (*bool)(nil), ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replica, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer replica.Close()
+
+			svc := NewService(nil, nil, slog.New(slog.NewTextHandler(os.Stdout, nil))).
+				WithReadReplica(replica)
+
+			tenantID := uuid.New()
+			replica.ExpectQuery(`WHERE \(\$3 = '' OR t\.plan = \$3\)\s+AND \(\$4::boolean IS NULL OR t\.is_active = \$4\)\s+AND \(\$5 = '' OR t\.name ILIKE \$5 OR t\.slug ILIKE \$5\)\s+` + tt.wantOrder).
+				WithArgs(tt.wantArgs...).
+				WillReturnRows(pgxmock.NewRows(columns).
+					AddRow(tenantID, "Acme", "pro", true, "2025-01-02", int64(10), int64(200), 45.5, 2.5))
+
+			tenants, err := svc.ListAllTenants(context.Background(), tt.params)
+			require.NoError(t, err)
+
+			require.Len(t, tenants, 1)
+			assert.Equal(t, tenantID.String(), tenants[0].ID)
+			assert.Equal(t, 2.5, tenants[0].Metrics.ErrorRate)
+			assert.NoError(t, replica.ExpectationsWereMet())
+		})
+	}
+}
@@ -4,11 +4,16 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"math"
 	"runtime"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/saturnino-fabrica-de-software/rekko/internal/database"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
 	"github.com/saturnino-fabrica-de-software/rekko/internal/metrics"
 )
 
@@ -16,7 +21,9 @@ import (
 type Service struct {
 	metricsRepo *metrics.Repository
 	db          *pgxpool.Pool
+	readDB      DB
 	logger      *slog.Logger
+	overviews   overviewCache
 }
 
 // NewService creates a new admin service
@@ -28,15 +35,30 @@ func NewService(metricsRepo *metrics.Repository, db *pgxpool.Pool, logger *slog.
 	}
 }
 
+// WithReadReplica routes read-only aggregate queries to a replica pool
+func (s *Service) WithReadReplica(readDB DB) *Service {
+	s.readDB = readDB
+	return s
+}
+
+// reader returns the replica when configured, falling back to the primary pool
+func (s *Service) reader() DB {
+	if s.readDB != nil {
+		return s.readDB
+	}
+	return s.db
+}
+
 // GetFacesMetrics retrieves metrics about faces
 func (s *Service) GetFacesMetrics(ctx context.Context, tenantID uuid.UUID, params MetricsParams) (*FacesMetrics, error) {
 	// Total registered faces (all time)
 	var totalRegistered int64
-	err := s.db.QueryRow(ctx, `
+	err := s.reader().QueryRow(ctx, `
 		SELECT COUNT(*) 
 		FROM faces 
 		WHERE tenant_id = $1
-	`, tenantID).Scan(&totalRegistered)
+		  AND NOT ($2 AND is_test)
+	`, tenantID, params.ExcludeTest).Scan(&totalRegistered)
 	if err != nil {
 		return nil, fmt.Errorf("tenant %s: failed to count total faces: %w", tenantID, err)
 	}
@@ -45,17 +67,18 @@ func (s *Service) GetFacesMetrics(ctx context.Context, tenantID uuid.UUID, param
 	active := totalRegistered
 
 	// Timeline of registrations
-	rows, err := s.db.Query(ctx, `
+	rows, err := s.reader().Query(ctx, `
 		SELECT 
 			date_trunc($1, created_at) as period,
 			COUNT(*) as registered
 		FROM faces
 		WHERE tenant_id = $2 
 		  AND created_at BETWEEN $3 AND $4
+		  AND NOT ($7 AND is_test)
 		GROUP BY period
 		ORDER BY period ASC
 		LIMIT $5 OFFSET $6
-	`, params.Interval, tenantID, params.StartDate, params.EndDate, params.Limit, params.Offset)
+	`, params.Interval, tenantID, params.StartDate, params.EndDate, params.Limit, params.Offset, params.ExcludeTest)
 	if err != nil {
 		return nil, fmt.Errorf("tenant %s: failed to query faces timeline: %w", tenantID, err)
 	}
@@ -88,11 +111,12 @@ func (s *Service) GetFacesMetrics(ctx context.Context, tenantID uuid.UUID, param
 func (s *Service) GetOperationsMetrics(ctx context.Context, tenantID uuid.UUID, params MetricsParams) (*OperationsMetrics, error) {
 	// Total operations (all time)
 	var totalOperations int64
-	err := s.db.QueryRow(ctx, `
+	err := s.reader().QueryRow(ctx, `
 		SELECT COUNT(*) 
 		FROM verifications 
 		WHERE tenant_id = $1
-	`, tenantID).Scan(&totalOperations)
+		  AND NOT ($2 AND is_test)
+	`, tenantID, params.ExcludeTest).Scan(&totalOperations)
 	if err != nil {
 		return nil, fmt.Errorf("tenant %s: failed to count total operations: %w", tenantID, err)
 	}
@@ -103,7 +127,7 @@ func (s *Service) GetOperationsMetrics(ctx context.Context, tenantID uuid.UUID,
 	}
 
 	// Timeline of operations
-	rows, err := s.db.Query(ctx, `
+	rows, err := s.reader().Query(ctx, `
 		SELECT 
 			date_trunc($1, created_at) as period,
 			COUNT(*) as total,
@@ -112,10 +136,11 @@ func (s *Service) GetOperationsMetrics(ctx context.Context, tenantID uuid.UUID,
 		FROM verifications
 		WHERE tenant_id = $2 
 		  AND created_at BETWEEN $3 AND $4
+		  AND NOT ($7 AND is_test)
 		GROUP BY period
 		ORDER BY period ASC
 		LIMIT $5 OFFSET $6
-	`, params.Interval, tenantID, params.StartDate, params.EndDate, params.Limit, params.Offset)
+	`, params.Interval, tenantID, params.StartDate, params.EndDate, params.Limit, params.Offset, params.ExcludeTest)
 	if err != nil {
 		return nil, fmt.Errorf("tenant %s: failed to query operations timeline: %w", tenantID, err)
 	}
@@ -137,17 +162,175 @@ func (s *Service) GetOperationsMetrics(ctx context.Context, tenantID uuid.UUID,
 		return nil, fmt.Errorf("tenant %s: operations timeline iteration error: %w", tenantID, err)
 	}
 
+	byFailReason, err := s.countFailReasons(ctx, tenantID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	byProvider, err := s.groupByProvider(ctx, tenantID, params)
+	if err != nil {
+		return nil, err
+	}
+
 	return &OperationsMetrics{
 		TotalOperations: totalOperations,
 		ByType:          byType,
+		ByFailReason:    byFailReason,
+		ByProvider:      byProvider,
 		Timeline:        timeline,
 	}, nil
 }
 
+// countFailReasons counts the failed verifications of the period by fail_reason.
+// Failures recorded before the column existed have no reason and are left out.
+func (s *Service) countFailReasons(ctx context.Context, tenantID uuid.UUID, params MetricsParams) (map[string]int64, error) {
+	rows, err := s.reader().Query(ctx, `
+		SELECT fail_reason, COUNT(*)
+		FROM verifications
+		WHERE tenant_id = $1
+		  AND created_at BETWEEN $2 AND $3
+		  AND NOT ($4 AND is_test)
+		  AND fail_reason IS NOT NULL
+		GROUP BY fail_reason
+	`, tenantID, params.StartDate, params.EndDate, params.ExcludeTest)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: failed to query fail reasons: %w", tenantID, err)
+	}
+	defer rows.Close()
+
+	byFailReason := make(map[string]int64)
+	for rows.Next() {
+		var reason string
+		var count int64
+		if err := rows.Scan(&reason, &count); err != nil {
+			return nil, fmt.Errorf("tenant %s: failed to scan fail reasons: %w", tenantID, err)
+		}
+		byFailReason[reason] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("tenant %s: fail reasons iteration error: %w", tenantID, err)
+	}
+
+	return byFailReason, nil
+}
+
+// groupByProvider counts the verifications of the period by the provider that decided them,
+// nil unless params.GroupBy asks for it. Verifications recorded without a provider are
+// grouped under UnknownProvider.
+func (s *Service) groupByProvider(ctx context.Context, tenantID uuid.UUID, params MetricsParams) (map[string]ProviderMetrics, error) {
+	if params.GroupBy != GroupByProvider {
+		return nil, nil
+	}
+
+	rows, err := s.reader().Query(ctx, `
+		SELECT
+			COALESCE(provider, $5) as provider,
+			COUNT(*) as total,
+			COUNT(*) FILTER (WHERE verified = true) as success,
+			COUNT(*) FILTER (WHERE verified = false) as failure
+		FROM verifications
+		WHERE tenant_id = $1
+		  AND created_at BETWEEN $2 AND $3
+		  AND NOT ($4 AND is_test)
+		GROUP BY 1
+	`, tenantID, params.StartDate, params.EndDate, params.ExcludeTest, UnknownProvider)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: failed to query provider metrics: %w", tenantID, err)
+	}
+	defer rows.Close()
+
+	byProvider := make(map[string]ProviderMetrics)
+	for rows.Next() {
+		var name string
+		var entry ProviderMetrics
+		if err := rows.Scan(&name, &entry.Total, &entry.Success, &entry.Failure); err != nil {
+			return nil, fmt.Errorf("tenant %s: failed to scan provider metrics: %w", tenantID, err)
+		}
+		if entry.Total > 0 {
+			entry.ErrorRate = float64(entry.Failure) / float64(entry.Total) * 100
+		}
+		byProvider[name] = entry
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("tenant %s: provider metrics iteration error: %w", tenantID, err)
+	}
+
+	return byProvider, nil
+}
+
+// GetFaceTrend retrieves the verification trend of a single external_id within the period
+func (s *Service) GetFaceTrend(ctx context.Context, tenantID uuid.UUID, externalID string, params MetricsParams) (*FaceTrendMetrics, error) {
+	trend := &FaceTrendMetrics{
+		ExternalID: externalID,
+		Timeline:   make([]FaceTrendEntry, 0),
+	}
+
+	// Totals cover the whole period, not just the page of the timeline
+	err := s.reader().QueryRow(ctx, `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE verified = true),
+			COUNT(*) FILTER (WHERE verified = false),
+			COUNT(DISTINCT date_trunc($1, created_at))
+		FROM verifications
+		WHERE tenant_id = $2
+		  AND external_id = $3
+		  AND created_at BETWEEN $4 AND $5
+		  AND NOT ($6 AND is_test)
+	`, params.Interval, tenantID, externalID, params.StartDate, params.EndDate, params.ExcludeTest).
+		Scan(&trend.TotalAttempts, &trend.Successes, &trend.Failures, &trend.TotalPeriods)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: failed to query face trend totals: %w", tenantID, err)
+	}
+
+	rows, err := s.reader().Query(ctx, `
+		SELECT
+			date_trunc($1, created_at) as period,
+			COUNT(*) as attempts,
+			COUNT(*) FILTER (WHERE verified = true) as successes,
+			COUNT(*) FILTER (WHERE verified = false) as failures
+		FROM verifications
+		WHERE tenant_id = $2
+		  AND external_id = $3
+		  AND created_at BETWEEN $4 AND $5
+		  AND NOT ($8 AND is_test)
+		GROUP BY period
+		ORDER BY period ASC
+		LIMIT $6 OFFSET $7
+	`, params.Interval, tenantID, externalID, params.StartDate, params.EndDate, params.Limit, params.Offset, params.ExcludeTest)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: failed to query face trend: %w", tenantID, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry FaceTrendEntry
+		var period interface{}
+		err := rows.Scan(&period, &entry.Attempts, &entry.Successes, &entry.Failures)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: failed to scan face trend: %w", tenantID, err)
+		}
+		entry.Period = fmt.Sprint(period)
+		trend.Timeline = append(trend.Timeline, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("tenant %s: face trend iteration error: %w", tenantID, err)
+	}
+
+	if trend.TotalAttempts > 0 {
+		trend.SuccessRate = float64(trend.Successes) / float64(trend.TotalAttempts)
+	}
+
+	return trend, nil
+}
+
 // GetRequestsMetrics retrieves metrics about HTTP requests (approximated from faces + verifications)
 func (s *Service) GetRequestsMetrics(ctx context.Context, tenantID uuid.UUID, params MetricsParams) (*RequestsMetrics, error) {
 	// Timeline combining faces and verifications
-	rows, err := s.db.Query(ctx, `
+	rows, err := s.reader().Query(ctx, `
 		WITH face_timeline AS (
 			SELECT 
 				date_trunc($1, created_at) as period,
@@ -155,6 +338,7 @@ func (s *Service) GetRequestsMetrics(ctx context.Context, tenantID uuid.UUID, pa
 			FROM faces
 			WHERE tenant_id = $2 
 			  AND created_at BETWEEN $3 AND $4
+			  AND NOT ($7 AND is_test)
 			GROUP BY period
 		),
 		verification_timeline AS (
@@ -164,6 +348,7 @@ func (s *Service) GetRequestsMetrics(ctx context.Context, tenantID uuid.UUID, pa
 			FROM verifications
 			WHERE tenant_id = $2 
 			  AND created_at BETWEEN $3 AND $4
+			  AND NOT ($7 AND is_test)
 			GROUP BY period
 		)
 		SELECT 
@@ -175,7 +360,7 @@ func (s *Service) GetRequestsMetrics(ctx context.Context, tenantID uuid.UUID, pa
 		FULL OUTER JOIN verification_timeline v ON f.period = v.period
 		ORDER BY period ASC
 		LIMIT $5 OFFSET $6
-	`, params.Interval, tenantID, params.StartDate, params.EndDate, params.Limit, params.Offset)
+	`, params.Interval, tenantID, params.StartDate, params.EndDate, params.Limit, params.Offset, params.ExcludeTest)
 	if err != nil {
 		return nil, fmt.Errorf("tenant %s: failed to query requests timeline: %w", tenantID, err)
 	}
@@ -219,7 +404,7 @@ func (s *Service) GetRequestsMetrics(ctx context.Context, tenantID uuid.UUID, pa
 func (s *Service) GetLatencyMetrics(ctx context.Context, tenantID uuid.UUID, params MetricsParams) (*LatencyMetrics, error) {
 	// Get overall latency percentiles from verifications
 	var avgMs, p50Ms, p95Ms, p99Ms float64
-	err := s.db.QueryRow(ctx, `
+	err := s.reader().QueryRow(ctx, `
 		SELECT
 			COALESCE(AVG(latency_ms), 0) as avg_ms,
 			COALESCE(PERCENTILE_CONT(0.50) WITHIN GROUP (ORDER BY latency_ms), 0) as p50_ms,
@@ -228,13 +413,14 @@ func (s *Service) GetLatencyMetrics(ctx context.Context, tenantID uuid.UUID, par
 		FROM verifications
 		WHERE tenant_id = $1
 		  AND created_at BETWEEN $2 AND $3
-	`, tenantID, params.StartDate, params.EndDate).Scan(&avgMs, &p50Ms, &p95Ms, &p99Ms)
+		  AND NOT ($4 AND is_test)
+	`, tenantID, params.StartDate, params.EndDate, params.ExcludeTest).Scan(&avgMs, &p50Ms, &p95Ms, &p99Ms)
 	if err != nil {
 		return nil, fmt.Errorf("tenant %s: failed to calculate latency percentiles: %w", tenantID, err)
 	}
 
 	// Timeline of latency metrics
-	rows, err := s.db.Query(ctx, `
+	rows, err := s.reader().Query(ctx, `
 		SELECT
 			date_trunc($1, created_at) as period,
 			COALESCE(AVG(latency_ms), 0) as avg_ms,
@@ -244,10 +430,11 @@ func (s *Service) GetLatencyMetrics(ctx context.Context, tenantID uuid.UUID, par
 		FROM verifications
 		WHERE tenant_id = $2
 		  AND created_at BETWEEN $3 AND $4
+		  AND NOT ($7 AND is_test)
 		GROUP BY period
 		ORDER BY period ASC
 		LIMIT $5 OFFSET $6
-	`, params.Interval, tenantID, params.StartDate, params.EndDate, params.Limit, params.Offset)
+	`, params.Interval, tenantID, params.StartDate, params.EndDate, params.Limit, params.Offset, params.ExcludeTest)
 	if err != nil {
 		return nil, fmt.Errorf("tenant %s: failed to query latency timeline: %w", tenantID, err)
 	}
@@ -282,14 +469,14 @@ func (s *Service) GetLatencyMetrics(ctx context.Context, tenantID uuid.UUID, par
 func (s *Service) GetThroughputMetrics(ctx context.Context, tenantID uuid.UUID, params MetricsParams) (*ThroughputMetrics, error) {
 	// Total requests in the period
 	var totalRequests int64
-	err := s.db.QueryRow(ctx, `
+	err := s.reader().QueryRow(ctx, `
 		SELECT COUNT(*)
 		FROM (
-			SELECT created_at FROM faces WHERE tenant_id = $1 AND created_at BETWEEN $2 AND $3
+			SELECT created_at FROM faces WHERE tenant_id = $1 AND created_at BETWEEN $2 AND $3 AND NOT ($4 AND is_test)
 			UNION ALL
-			SELECT created_at FROM verifications WHERE tenant_id = $1 AND created_at BETWEEN $2 AND $3
+			SELECT created_at FROM verifications WHERE tenant_id = $1 AND created_at BETWEEN $2 AND $3 AND NOT ($4 AND is_test)
 		) combined
-	`, tenantID, params.StartDate, params.EndDate).Scan(&totalRequests)
+	`, tenantID, params.StartDate, params.EndDate, params.ExcludeTest).Scan(&totalRequests)
 	if err != nil {
 		return nil, fmt.Errorf("tenant %s: failed to count total requests: %w", tenantID, err)
 	}
@@ -302,13 +489,13 @@ func (s *Service) GetThroughputMetrics(ctx context.Context, tenantID uuid.UUID,
 	requestsPerHour := float64(totalRequests) / hours
 
 	// Timeline with hourly rates
-	rows, err := s.db.Query(ctx, `
+	rows, err := s.reader().Query(ctx, `
 		WITH combined_requests AS (
 			SELECT date_trunc($1, created_at) as period FROM faces 
-			WHERE tenant_id = $2 AND created_at BETWEEN $3 AND $4
+			WHERE tenant_id = $2 AND created_at BETWEEN $3 AND $4 AND NOT ($7 AND is_test)
 			UNION ALL
 			SELECT date_trunc($1, created_at) as period FROM verifications 
-			WHERE tenant_id = $2 AND created_at BETWEEN $3 AND $4
+			WHERE tenant_id = $2 AND created_at BETWEEN $3 AND $4 AND NOT ($7 AND is_test)
 		)
 		SELECT 
 			period,
@@ -318,7 +505,7 @@ func (s *Service) GetThroughputMetrics(ctx context.Context, tenantID uuid.UUID,
 		GROUP BY period
 		ORDER BY period ASC
 		LIMIT $5 OFFSET $6
-	`, params.Interval, tenantID, params.StartDate, params.EndDate, params.Limit, params.Offset)
+	`, params.Interval, tenantID, params.StartDate, params.EndDate, params.Limit, params.Offset, params.ExcludeTest)
 	if err != nil {
 		return nil, fmt.Errorf("tenant %s: failed to query throughput timeline: %w", tenantID, err)
 	}
@@ -357,14 +544,15 @@ func (s *Service) GetThroughputMetrics(ctx context.Context, tenantID uuid.UUID,
 func (s *Service) GetErrorMetrics(ctx context.Context, tenantID uuid.UUID, params MetricsParams) (*ErrorMetrics, error) {
 	// Total errors (verification failures) in the period
 	var totalErrors, totalOps int64
-	err := s.db.QueryRow(ctx, `
+	err := s.reader().QueryRow(ctx, `
 		SELECT 
 			COUNT(*) FILTER (WHERE verified = false) as total_errors,
 			COUNT(*) as total_ops
 		FROM verifications
 		WHERE tenant_id = $1
 		  AND created_at BETWEEN $2 AND $3
-	`, tenantID, params.StartDate, params.EndDate).Scan(&totalErrors, &totalOps)
+		  AND NOT ($4 AND is_test)
+	`, tenantID, params.StartDate, params.EndDate, params.ExcludeTest).Scan(&totalErrors, &totalOps)
 	if err != nil {
 		return nil, fmt.Errorf("tenant %s: failed to count errors: %w", tenantID, err)
 	}
@@ -380,7 +568,7 @@ func (s *Service) GetErrorMetrics(ctx context.Context, tenantID uuid.UUID, param
 	}
 
 	// Timeline of errors
-	rows, err := s.db.Query(ctx, `
+	rows, err := s.reader().Query(ctx, `
 		SELECT 
 			date_trunc($1, created_at) as period,
 			COUNT(*) as total,
@@ -389,10 +577,11 @@ func (s *Service) GetErrorMetrics(ctx context.Context, tenantID uuid.UUID, param
 		FROM verifications
 		WHERE tenant_id = $2 
 		  AND created_at BETWEEN $3 AND $4
+		  AND NOT ($7 AND is_test)
 		GROUP BY period
 		ORDER BY period ASC
 		LIMIT $5 OFFSET $6
-	`, params.Interval, tenantID, params.StartDate, params.EndDate, params.Limit, params.Offset)
+	`, params.Interval, tenantID, params.StartDate, params.EndDate, params.Limit, params.Offset, params.ExcludeTest)
 	if err != nil {
 		return nil, fmt.Errorf("tenant %s: failed to query error timeline: %w", tenantID, err)
 	}
@@ -414,10 +603,16 @@ func (s *Service) GetErrorMetrics(ctx context.Context, tenantID uuid.UUID, param
 		return nil, fmt.Errorf("tenant %s: error timeline iteration error: %w", tenantID, err)
 	}
 
+	byProvider, err := s.groupByProvider(ctx, tenantID, params)
+	if err != nil {
+		return nil, err
+	}
+
 	return &ErrorMetrics{
 		TotalErrors: totalErrors,
 		ErrorRate:   errorRate,
 		ByType:      byType,
+		ByProvider:  byProvider,
 		Timeline:    timeline,
 	}, nil
 }
@@ -426,7 +621,7 @@ func (s *Service) GetErrorMetrics(ctx context.Context, tenantID uuid.UUID, param
 func (s *Service) GetQualityMetrics(ctx context.Context, tenantID uuid.UUID, params MetricsParams) (*QualityMetrics, error) {
 	// Get overall quality statistics
 	var avgQuality, minQuality, maxQuality float64
-	err := s.db.QueryRow(ctx, `
+	err := s.reader().QueryRow(ctx, `
 		SELECT
 			COALESCE(AVG(quality_score), 0) as avg_quality,
 			COALESCE(MIN(quality_score), 0) as min_quality,
@@ -434,13 +629,14 @@ func (s *Service) GetQualityMetrics(ctx context.Context, tenantID uuid.UUID, par
 		FROM faces
 		WHERE tenant_id = $1
 		  AND created_at BETWEEN $2 AND $3
-	`, tenantID, params.StartDate, params.EndDate).Scan(&avgQuality, &minQuality, &maxQuality)
+		  AND NOT ($4 AND is_test)
+	`, tenantID, params.StartDate, params.EndDate, params.ExcludeTest).Scan(&avgQuality, &minQuality, &maxQuality)
 	if err != nil {
 		return nil, fmt.Errorf("tenant %s: failed to calculate quality statistics: %w", tenantID, err)
 	}
 
 	// Timeline of quality metrics
-	rows, err := s.db.Query(ctx, `
+	rows, err := s.reader().Query(ctx, `
 		SELECT
 			date_trunc($1, created_at) as period,
 			COALESCE(AVG(quality_score), 0) as avg_quality,
@@ -449,10 +645,11 @@ func (s *Service) GetQualityMetrics(ctx context.Context, tenantID uuid.UUID, par
 		FROM faces
 		WHERE tenant_id = $2
 		  AND created_at BETWEEN $3 AND $4
+		  AND NOT ($7 AND is_test)
 		GROUP BY period
 		ORDER BY period ASC
 		LIMIT $5 OFFSET $6
-	`, params.Interval, tenantID, params.StartDate, params.EndDate, params.Limit, params.Offset)
+	`, params.Interval, tenantID, params.StartDate, params.EndDate, params.Limit, params.Offset, params.ExcludeTest)
 	if err != nil {
 		return nil, fmt.Errorf("tenant %s: failed to query quality timeline: %w", tenantID, err)
 	}
@@ -486,7 +683,7 @@ func (s *Service) GetQualityMetrics(ctx context.Context, tenantID uuid.UUID, par
 func (s *Service) GetConfidenceMetrics(ctx context.Context, tenantID uuid.UUID, params MetricsParams) (*ConfidenceMetrics, error) {
 	// Get overall confidence statistics
 	var avgConfidence, minConfidence, maxConfidence float64
-	err := s.db.QueryRow(ctx, `
+	err := s.reader().QueryRow(ctx, `
 		SELECT
 			COALESCE(AVG(confidence), 0) as avg_confidence,
 			COALESCE(MIN(confidence), 0) as min_confidence,
@@ -494,13 +691,16 @@ func (s *Service) GetConfidenceMetrics(ctx context.Context, tenantID uuid.UUID,
 		FROM verifications
 		WHERE tenant_id = $1
 		  AND created_at BETWEEN $2 AND $3
-	`, tenantID, params.StartDate, params.EndDate).Scan(&avgConfidence, &minConfidence, &maxConfidence)
+		  AND NOT ($4 AND is_test)
+		  AND fail_reason IS DISTINCT FROM 'no_face'
+		  AND fail_reason IS DISTINCT FROM 'multiple_faces'
+	`, tenantID, params.StartDate, params.EndDate, params.ExcludeTest).Scan(&avgConfidence, &minConfidence, &maxConfidence)
 	if err != nil {
 		return nil, fmt.Errorf("tenant %s: failed to calculate confidence statistics: %w", tenantID, err)
 	}
 
 	// Timeline of confidence metrics
-	rows, err := s.db.Query(ctx, `
+	rows, err := s.reader().Query(ctx, `
 		SELECT
 			date_trunc($1, created_at) as period,
 			COALESCE(AVG(confidence), 0) as avg_confidence,
@@ -509,10 +709,13 @@ func (s *Service) GetConfidenceMetrics(ctx context.Context, tenantID uuid.UUID,
 		FROM verifications
 		WHERE tenant_id = $2
 		  AND created_at BETWEEN $3 AND $4
+		  AND NOT ($7 AND is_test)
+		  AND fail_reason IS DISTINCT FROM 'no_face'
+		  AND fail_reason IS DISTINCT FROM 'multiple_faces'
 		GROUP BY period
 		ORDER BY period ASC
 		LIMIT $5 OFFSET $6
-	`, params.Interval, tenantID, params.StartDate, params.EndDate, params.Limit, params.Offset)
+	`, params.Interval, tenantID, params.StartDate, params.EndDate, params.Limit, params.Offset, params.ExcludeTest)
 	if err != nil {
 		return nil, fmt.Errorf("tenant %s: failed to query confidence timeline: %w", tenantID, err)
 	}
@@ -547,7 +750,7 @@ func (s *Service) GetMatchMetrics(ctx context.Context, tenantID uuid.UUID, param
 	// Get overall match statistics
 	var totalMatches, totalVerifications int64
 	var avgMatchScore float64
-	err := s.db.QueryRow(ctx, `
+	err := s.reader().QueryRow(ctx, `
 		SELECT
 			COUNT(*) FILTER (WHERE verified = true) as total_matches,
 			COUNT(*) as total_verifications,
@@ -555,7 +758,8 @@ func (s *Service) GetMatchMetrics(ctx context.Context, tenantID uuid.UUID, param
 		FROM verifications
 		WHERE tenant_id = $1
 		  AND created_at BETWEEN $2 AND $3
-	`, tenantID, params.StartDate, params.EndDate).Scan(&totalMatches, &totalVerifications, &avgMatchScore)
+		  AND NOT ($4 AND is_test)
+	`, tenantID, params.StartDate, params.EndDate, params.ExcludeTest).Scan(&totalMatches, &totalVerifications, &avgMatchScore)
 	if err != nil {
 		return nil, fmt.Errorf("tenant %s: failed to calculate match statistics: %w", tenantID, err)
 	}
@@ -566,7 +770,7 @@ func (s *Service) GetMatchMetrics(ctx context.Context, tenantID uuid.UUID, param
 	}
 
 	// Timeline of match metrics
-	rows, err := s.db.Query(ctx, `
+	rows, err := s.reader().Query(ctx, `
 		SELECT
 			date_trunc($1, created_at) as period,
 			COUNT(*) FILTER (WHERE verified = true) as matches,
@@ -576,10 +780,11 @@ func (s *Service) GetMatchMetrics(ctx context.Context, tenantID uuid.UUID, param
 		FROM verifications
 		WHERE tenant_id = $2
 		  AND created_at BETWEEN $3 AND $4
+		  AND NOT ($7 AND is_test)
 		GROUP BY period
 		ORDER BY period ASC
 		LIMIT $5 OFFSET $6
-	`, params.Interval, tenantID, params.StartDate, params.EndDate, params.Limit, params.Offset)
+	`, params.Interval, tenantID, params.StartDate, params.EndDate, params.Limit, params.Offset, params.ExcludeTest)
 	if err != nil {
 		return nil, fmt.Errorf("tenant %s: failed to query match timeline: %w", tenantID, err)
 	}
@@ -609,10 +814,185 @@ func (s *Service) GetMatchMetrics(ctx context.Context, tenantID uuid.UUID, param
 	}, nil
 }
 
+// GetLivenessMetrics retrieves pass/fail statistics of the liveness checks run by verifications,
+// separate from their match outcome. Verifications whose policy skipped liveness are not counted.
+func (s *Service) GetLivenessMetrics(ctx context.Context, tenantID uuid.UUID, params MetricsParams) (*LivenessMetrics, error) {
+	// Get overall liveness statistics
+	var passed, failed int64
+	var avgScore float64
+	err := s.reader().QueryRow(ctx, `
+		SELECT
+			COUNT(*) FILTER (WHERE liveness_passed = true) as passed,
+			COUNT(*) FILTER (WHERE liveness_passed = false) as failed,
+			COALESCE(AVG(liveness_score), 0) as avg_liveness_score
+		FROM verifications
+		WHERE tenant_id = $1
+		  AND created_at BETWEEN $2 AND $3
+		  AND NOT ($4 AND is_test)
+		  AND liveness_passed IS NOT NULL
+	`, tenantID, params.StartDate, params.EndDate, params.ExcludeTest).Scan(&passed, &failed, &avgScore)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: failed to calculate liveness statistics: %w", tenantID, err)
+	}
+
+	passRate := 0.0
+	if passed+failed > 0 {
+		passRate = float64(passed) / float64(passed+failed) * 100
+	}
+
+	// Timeline of liveness metrics
+	rows, err := s.reader().Query(ctx, `
+		SELECT
+			date_trunc($1, created_at) as period,
+			COUNT(*) FILTER (WHERE liveness_passed = true) as passed,
+			COUNT(*) FILTER (WHERE liveness_passed = false) as failed,
+			COALESCE(AVG(liveness_score), 0) as avg_liveness_score
+		FROM verifications
+		WHERE tenant_id = $2
+		  AND created_at BETWEEN $3 AND $4
+		  AND NOT ($7 AND is_test)
+		  AND liveness_passed IS NOT NULL
+		GROUP BY period
+		ORDER BY period ASC
+		LIMIT $5 OFFSET $6
+	`, params.Interval, tenantID, params.StartDate, params.EndDate, params.Limit, params.Offset, params.ExcludeTest)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: failed to query liveness timeline: %w", tenantID, err)
+	}
+	defer rows.Close()
+
+	timeline := make([]LivenessTimeline, 0)
+	for rows.Next() {
+		var entry LivenessTimeline
+		var period interface{}
+		err := rows.Scan(&period, &entry.Passed, &entry.Failed, &entry.AverageLivenessScore)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: failed to scan liveness timeline: %w", tenantID, err)
+		}
+		entry.Period = fmt.Sprint(period)
+		if total := entry.Passed + entry.Failed; total > 0 {
+			entry.PassRate = float64(entry.Passed) / float64(total) * 100
+		}
+		timeline = append(timeline, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("tenant %s: liveness timeline iteration error: %w", tenantID, err)
+	}
+
+	return &LivenessMetrics{
+		Passed:               passed,
+		Failed:               failed,
+		PassRate:             passRate,
+		AverageLivenessScore: avgScore,
+		Timeline:             timeline,
+	}, nil
+}
+
+// GetGateMetrics groups verifications by the client IP that requested them, so venues
+// running one device per gate can spot a misbehaving entrance
+func (s *Service) GetGateMetrics(ctx context.Context, tenantID uuid.UUID, params MetricsParams) (*GateMetrics, error) {
+	rows, err := s.reader().Query(ctx, `
+		SELECT
+			COALESCE(host(client_ip), $5) as gate,
+			COUNT(*) as verifications,
+			COUNT(*) FILTER (WHERE verified = true) as successes,
+			COALESCE(AVG(latency_ms), 0) as avg_latency_ms
+		FROM verifications
+		WHERE tenant_id = $1
+		  AND created_at BETWEEN $2 AND $3
+		  AND NOT ($4 AND is_test)
+		GROUP BY gate
+		ORDER BY verifications DESC, gate ASC
+	`, tenantID, params.StartDate, params.EndDate, params.ExcludeTest, UnknownGate)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: failed to query gate metrics: %w", tenantID, err)
+	}
+	defer rows.Close()
+
+	gates := make([]GateStats, 0)
+	for rows.Next() {
+		var entry GateStats
+		if err := rows.Scan(&entry.Gate, &entry.Verifications, &entry.Successes, &entry.AvgLatencyMs); err != nil {
+			return nil, fmt.Errorf("tenant %s: failed to scan gate metrics: %w", tenantID, err)
+		}
+		entry.Failures = entry.Verifications - entry.Successes
+		if entry.Verifications > 0 {
+			entry.SuccessRate = float64(entry.Successes) / float64(entry.Verifications)
+		}
+		gates = append(gates, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("tenant %s: gate metrics iteration error: %w", tenantID, err)
+	}
+
+	return &GateMetrics{Gates: gates}, nil
+}
+
+// GetTopIdentities ranks the external_ids verified successfully most often in the period, e.g.
+// staff passing many times. Only the face metadata keys in metadataKeys are returned.
+func (s *Service) GetTopIdentities(ctx context.Context, tenantID uuid.UUID, params MetricsParams, metadataKeys []string) (*TopIdentities, error) {
+	rows, err := s.reader().Query(ctx, `
+		WITH ranked AS (
+			SELECT
+				external_id,
+				COUNT(*) as verifications,
+				MAX(created_at) as last_seen_at
+			FROM verifications
+			WHERE tenant_id = $1
+			  AND verified = true
+			  AND created_at BETWEEN $2 AND $3
+			  AND NOT ($4 AND is_test)
+			GROUP BY external_id
+			ORDER BY verifications DESC, last_seen_at DESC, external_id ASC
+			LIMIT $5
+		)
+		SELECT r.external_id, r.verifications, r.last_seen_at, COALESCE(f.metadata, '{}'::jsonb)
+		FROM ranked r
+		LEFT JOIN faces f ON f.tenant_id = $1 AND f.external_id = r.external_id
+		ORDER BY r.verifications DESC, r.last_seen_at DESC, r.external_id ASC
+	`, tenantID, params.StartDate, params.EndDate, params.ExcludeTest, params.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: failed to query top identities: %w", tenantID, err)
+	}
+	defer rows.Close()
+
+	identities := make([]IdentityStats, 0)
+	for rows.Next() {
+		var entry IdentityStats
+		var metadata map[string]interface{}
+		if err := rows.Scan(&entry.ExternalID, &entry.Verifications, &entry.LastSeenAt, &metadata); err != nil {
+			return nil, fmt.Errorf("tenant %s: failed to scan top identities: %w", tenantID, err)
+		}
+		entry.Metadata = domain.AllowedMetadata(metadata, metadataKeys)
+		identities = append(identities, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("tenant %s: top identities iteration error: %w", tenantID, err)
+	}
+
+	return &TopIdentities{Identities: identities}, nil
+}
+
 // Super Admin Methods
 
-// ListAllTenants retrieves all tenants with summary metrics
-func (s *Service) ListAllTenants(ctx context.Context, limit, offset int) ([]TenantWithMetrics, error) {
+// likeEscaper escapes the LIKE wildcards of a search term so it matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// ListAllTenants retrieves the tenants matching params with summary metrics
+func (s *Service) ListAllTenants(ctx context.Context, params TenantListParams) ([]TenantWithMetrics, error) {
+	order, ok := tenantSortOrders[params.Sort]
+	if !ok {
+		order = tenantSortOrders[TenantSortCreatedAt]
+	}
+
+	search := ""
+	if params.Search != "" {
+		search = "%" + likeEscaper.Replace(params.Search) + "%"
+	}
+
 	query := `
 		WITH tenant_metrics AS (
 			SELECT 
@@ -642,11 +1022,15 @@ func (s *Service) ListAllTenants(ctx context.Context, limit, offset int) ([]Tena
 			COALESCE(tm.error_rate, 0) as error_rate
 		FROM tenants t
 		LEFT JOIN tenant_metrics tm ON tm.id = t.id
-		ORDER BY t.created_at DESC
+		WHERE ($3 = '' OR t.plan = $3)
+		  AND ($4::boolean IS NULL OR t.is_active = $4)
+		  AND ($5 = '' OR t.name ILIKE $5 OR t.slug ILIKE $5)
+		ORDER BY ` + order + `
 		LIMIT $1 OFFSET $2
 	`
 
-	rows, err := s.db.Query(ctx, query, limit, offset)
+	rows, err := s.reader().Query(database.WithQueryLabel(ctx, "admin.list_tenants"), query,
+		params.Limit, params.Offset, params.Plan, params.IsActive, search)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query tenants: %w", err)
 	}
@@ -689,7 +1073,7 @@ func (s *Service) ListAllTenants(ctx context.Context, limit, offset int) ([]Tena
 func (s *Service) GetTenantDetailedMetrics(ctx context.Context, tenantID uuid.UUID) (*TenantMetricsSummary, error) {
 	var metrics TenantMetricsSummary
 
-	err := s.db.QueryRow(ctx, `
+	err := s.reader().QueryRow(ctx, `
 		SELECT 
 			COUNT(DISTINCT f.id) as total_faces,
 			COUNT(DISTINCT v.id) as total_requests,
@@ -718,6 +1102,60 @@ func (s *Service) GetTenantDetailedMetrics(ctx context.Context, tenantID uuid.UU
 	return &metrics, nil
 }
 
+// GetUsageForecast projects the month-to-date usage (registrations, verifications and liveness
+// checks) to the end of the month at the average daily rate so far, and flags when the projection
+// exceeds the tenant's max_requests_month quota
+func (s *Service) GetUsageForecast(ctx context.Context, tenantID uuid.UUID, now time.Time) (*UsageForecast, error) {
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	var monthToDate int64
+	err := s.reader().QueryRow(ctx, `
+		SELECT COALESCE(SUM(registrations + verifications + liveness_checks), 0)
+		FROM usage_daily
+		WHERE tenant_id = $1
+		  AND date >= $2 AND date <= $3
+	`, tenantID, monthStart, now).Scan(&monthToDate)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: failed to sum month-to-date usage: %w", tenantID, err)
+	}
+
+	var settings map[string]interface{}
+	err = s.reader().QueryRow(ctx, `
+		SELECT settings FROM tenants WHERE id = $1
+	`, tenantID).Scan(&settings)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: failed to get settings: %w", tenantID, err)
+	}
+
+	projected, elapsedDays, daysInMonth := projectMonthlyUsage(monthToDate, now)
+	forecast := &UsageForecast{
+		Month:          monthStart.Format("2006-01"),
+		MonthToDate:    monthToDate,
+		ElapsedDays:    elapsedDays,
+		DaysInMonth:    daysInMonth,
+		DailyAverage:   float64(monthToDate) / float64(elapsedDays),
+		ProjectedTotal: projected,
+	}
+
+	// A missing or zero quota means unlimited
+	tenant := &domain.Tenant{ID: tenantID, Settings: settings}
+	if maxRequests := tenant.GetSettings().MaxRequestsMonth; maxRequests > 0 {
+		forecast.MaxRequestsMonth = &maxRequests
+		forecast.ExceedsQuota = projected > int64(maxRequests)
+	}
+
+	return forecast, nil
+}
+
+// projectMonthlyUsage extrapolates monthToDate linearly over the month of now.
+// The current day counts as elapsed, so the first day projects its own usage.
+func projectMonthlyUsage(monthToDate int64, now time.Time) (projected int64, elapsedDays, daysInMonth int) {
+	elapsedDays = now.Day()
+	daysInMonth = time.Date(now.Year(), now.Month()+1, 0, 0, 0, 0, 0, now.Location()).Day()
+	projected = int64(math.Round(float64(monthToDate) / float64(elapsedDays) * float64(daysInMonth)))
+	return projected, elapsedDays, daysInMonth
+}
+
 // UpdateTenantQuota updates quota settings for a tenant
 func (s *Service) UpdateTenantQuota(ctx context.Context, tenantID uuid.UUID, req UpdateQuotaRequest) error {
 	var settings map[string]interface{}
@@ -797,9 +1235,27 @@ func (s *Service) checkDatabaseHealth(ctx context.Context) ServiceHealth {
 		}
 	}
 
+	extensions, err := database.ExtensionStatus(ctx, s.db)
+	if err != nil {
+		return ServiceHealth{
+			Status:  "unhealthy",
+			Latency: "N/A",
+			Message: err.Error(),
+		}
+	}
+	if err := database.RequireExtensions(extensions); err != nil {
+		return ServiceHealth{
+			Status:     "unhealthy",
+			Latency:    "< 1ms",
+			Message:    err.Error(),
+			Extensions: extensions,
+		}
+	}
+
 	return ServiceHealth{
-		Status:  "healthy",
-		Latency: "< 1ms",
+		Status:     "healthy",
+		Latency:    "< 1ms",
+		Extensions: extensions,
 	}
 }
 
@@ -824,6 +1280,15 @@ func (s *Service) GetSystemMetrics(ctx context.Context) (*SystemMetrics, error)
 		RequestsPerSecond: 0.0, // TODO: implement RPS tracking
 	}
 
+	if tracer, ok := database.PoolSlowQueryTracer(s.db); ok {
+		stats := tracer.Stats()
+		metrics.SlowQueries = &SlowQueryMetrics{
+			ThresholdMs: stats.Threshold.Milliseconds(),
+			Total:       stats.Total,
+			ByQuery:     stats.ByLabel,
+		}
+	}
+
 	return metrics, nil
 }
 
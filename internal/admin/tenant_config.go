@@ -0,0 +1,222 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/webhook"
+)
+
+// TenantConfigVersion is the format written by Export and the only one Import accepts
+const TenantConfigVersion = 1
+
+// TenantConfigService copies configuration between tenants, e.g. from staging to production
+type TenantConfigService struct {
+	db     TxDB
+	logger *slog.Logger
+}
+
+// NewTenantConfigService creates a tenant config service on the primary pool
+func NewTenantConfigService(db TxDB, logger *slog.Logger) *TenantConfigService {
+	return &TenantConfigService{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Export returns the tenant's plan, settings and webhooks with their custom headers and
+// sample rates. Webhook secrets are left out.
+func (s *TenantConfigService) Export(ctx context.Context, tenantID uuid.UUID) (*TenantConfig, error) {
+	config := &TenantConfig{Version: TenantConfigVersion}
+
+	err := s.db.QueryRow(ctx, `
+		SELECT plan, settings FROM tenants WHERE id = $1
+	`, tenantID).Scan(&config.Plan, &config.Settings)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrTenantNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: failed to get settings: %w", tenantID, err)
+	}
+	if config.Settings == nil {
+		config.Settings = make(map[string]interface{})
+	}
+
+	rows, err := s.db.Query(ctx, `
+		SELECT name, url, events, headers, sample_rates, enabled
+		FROM webhooks
+		WHERE tenant_id = $1
+		ORDER BY created_at ASC, name ASC
+	`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: failed to query webhooks: %w", tenantID, err)
+	}
+	defer rows.Close()
+
+	config.Webhooks = make([]TenantConfigWebhook, 0)
+	for rows.Next() {
+		var w TenantConfigWebhook
+		var eventsJSON, headersJSON, sampleRatesJSON []byte
+		if err := rows.Scan(&w.Name, &w.URL, &eventsJSON, &headersJSON, &sampleRatesJSON, &w.Enabled); err != nil {
+			return nil, fmt.Errorf("tenant %s: failed to scan webhook: %w", tenantID, err)
+		}
+		if err := json.Unmarshal(eventsJSON, &w.Events); err != nil {
+			return nil, fmt.Errorf("tenant %s: failed to unmarshal webhook events: %w", tenantID, err)
+		}
+		if err := json.Unmarshal(headersJSON, &w.Headers); err != nil {
+			return nil, fmt.Errorf("tenant %s: failed to unmarshal webhook headers: %w", tenantID, err)
+		}
+		if err := json.Unmarshal(sampleRatesJSON, &w.SampleRates); err != nil {
+			return nil, fmt.Errorf("tenant %s: failed to unmarshal webhook sample rates: %w", tenantID, err)
+		}
+		// Unconfigured webhooks store {}; leave them out of the document
+		if len(w.Headers) == 0 {
+			w.Headers = nil
+		}
+		if len(w.SampleRates) == 0 {
+			w.SampleRates = nil
+		}
+		config.Webhooks = append(config.Webhooks, w)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("tenant %s: webhooks iteration error: %w", tenantID, err)
+	}
+
+	return config, nil
+}
+
+// Import validates config and replaces the tenant's plan, settings and webhooks in one
+// transaction. Imported webhooks get new secrets, returned so receivers can be reconfigured.
+func (s *TenantConfigService) Import(ctx context.Context, tenantID uuid.UUID, config TenantConfig) ([]ImportedWebhook, error) {
+	if err := config.Validate(); err != nil {
+		return nil, domain.ErrValidationFailed.WithError(err)
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: failed to begin import: %w", tenantID, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE tenants
+		SET plan = $1, settings = $2, updated_at = NOW()
+		WHERE id = $3
+	`, config.Plan, config.Settings, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: failed to update settings: %w", tenantID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, domain.ErrTenantNotFound
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM webhooks WHERE tenant_id = $1`, tenantID); err != nil {
+		return nil, fmt.Errorf("tenant %s: failed to remove webhooks: %w", tenantID, err)
+	}
+
+	imported := make([]ImportedWebhook, 0, len(config.Webhooks))
+	for _, w := range config.Webhooks {
+		secret, err := webhook.GenerateSecret()
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: failed to generate webhook secret: %w", tenantID, err)
+		}
+		eventsJSON, err := json.Marshal(w.Events)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: failed to marshal webhook events: %w", tenantID, err)
+		}
+		headers := w.Headers
+		if headers == nil {
+			headers = map[string]string{}
+		}
+		headersJSON, err := json.Marshal(headers)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: failed to marshal webhook headers: %w", tenantID, err)
+		}
+		sampleRates := w.SampleRates
+		if sampleRates == nil {
+			sampleRates = map[string]int{}
+		}
+		sampleRatesJSON, err := json.Marshal(sampleRates)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: failed to marshal webhook sample rates: %w", tenantID, err)
+		}
+
+		created := ImportedWebhook{Name: w.Name, URL: w.URL, Secret: secret}
+		err = tx.QueryRow(ctx, `
+			INSERT INTO webhooks (tenant_id, name, url, secret, events, headers, sample_rates, enabled)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id
+		`, tenantID, w.Name, w.URL, secret, eventsJSON, headersJSON, sampleRatesJSON, w.Enabled).Scan(&created.ID)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: failed to create webhook %q: %w", tenantID, w.Name, err)
+		}
+		imported = append(imported, created)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("tenant %s: failed to commit import: %w", tenantID, err)
+	}
+
+	s.logger.Info("tenant config imported",
+		"tenant_id", tenantID,
+		"plan", config.Plan,
+		"webhooks", len(imported),
+	)
+
+	return imported, nil
+}
+
+// Validate checks the config can be applied as is: known version and plan, usable settings
+// and webhooks with an http(s) URL subscribed to known events, with valid headers and sample rates
+func (c TenantConfig) Validate() error {
+	if c.Version != TenantConfigVersion {
+		return fmt.Errorf("unsupported config version %d, expected %d", c.Version, TenantConfigVersion)
+	}
+	if !domain.IsValidPlan(c.Plan) {
+		return fmt.Errorf("invalid plan %q", c.Plan)
+	}
+	if c.Settings == nil {
+		return errors.New("settings are required")
+	}
+	if err := domain.ValidateTenantSettings(c.Settings); err != nil {
+		return err
+	}
+
+	eventTypes := webhook.EventTypes()
+	for i, w := range c.Webhooks {
+		if strings.TrimSpace(w.Name) == "" {
+			return fmt.Errorf("webhooks[%d]: name is required", i)
+		}
+		u, err := url.Parse(w.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("webhooks[%d]: url must be an absolute http(s) URL", i)
+		}
+		if len(w.Events) == 0 {
+			return fmt.Errorf("webhooks[%d]: at least one event is required", i)
+		}
+		for _, event := range w.Events {
+			if !slices.Contains(eventTypes, event) {
+				return fmt.Errorf("webhooks[%d]: unknown event %q", i, event)
+			}
+		}
+		if err := webhook.ValidateHeaders(w.Headers); err != nil {
+			return fmt.Errorf("webhooks[%d]: %w", i, err)
+		}
+		if err := webhook.ValidateSampleRates(w.SampleRates); err != nil {
+			return fmt.Errorf("webhooks[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
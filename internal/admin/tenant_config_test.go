@@ -0,0 +1,209 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+// captureArg matches any argument and keeps it for later assertions
+type captureArg struct {
+	value interface{}
+}
+
+func (c *captureArg) Match(v interface{}) bool {
+	c.value = v
+	return true
+}
+
+func newTenantConfigService(t *testing.T) (*TenantConfigService, pgxmock.PgxPoolIface) {
+	pool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+	return NewTenantConfigService(pool, slog.New(slog.NewTextHandler(os.Stdout, nil))), pool
+}
+
+func expectExport(pool pgxmock.PgxPoolIface, tenantID uuid.UUID, plan string, settings map[string]interface{}, webhooks [][]interface{}) {
+	pool.ExpectQuery(`SELECT plan, settings FROM tenants WHERE id = \$1`).
+		WithArgs(tenantID).
+		WillReturnRows(pgxmock.NewRows([]string{"plan", "settings"}).AddRow(plan, settings))
+
+	rows := pgxmock.NewRows([]string{"name", "url", "events", "headers", "sample_rates", "enabled"})
+	for _, w := range webhooks {
+		rows.AddRow(w...)
+	}
+	pool.ExpectQuery(`FROM webhooks\s+WHERE tenant_id = \$1`).
+		WithArgs(tenantID).
+		WillReturnRows(rows)
+}
+
+func TestTenantConfigService_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	staging, prod := uuid.New(), uuid.New()
+	settings := map[string]interface{}{
+		"verification_threshold": 0.9,
+		"security_level":         "enhanced",
+		"max_requests_month":     50000.0,
+		"security_levels": map[string]interface{}{
+			"maximum": map[string]interface{}{"verify_threshold": 0.97},
+		},
+	}
+
+	svc, pool := newTenantConfigService(t)
+
+	// 1. Export the staging tenant
+	expectExport(pool, staging, domain.PlanPro, settings, [][]interface{}{
+		{"crm", "https://crm.example.com/hooks", []byte(`["face.registered","face.verified"]`), []byte(`{"X-Api-Key":"gateway-key"}`), []byte(`{"face.verified":10}`), true},
+		{"audit", "https://audit.example.com/rekko", []byte(`["face.deleted"]`), []byte(`{}`), []byte(`{}`), false},
+	})
+
+	exported, err := svc.Export(ctx, staging)
+	require.NoError(t, err)
+	assert.Equal(t, TenantConfigVersion, exported.Version)
+	require.Len(t, exported.Webhooks, 2)
+	assert.Equal(t, map[string]string{"X-Api-Key": "gateway-key"}, exported.Webhooks[0].Headers)
+	assert.Equal(t, map[string]int{"face.verified": 10}, exported.Webhooks[0].SampleRates)
+	assert.Nil(t, exported.Webhooks[1].Headers)
+
+	// 2. Import into production, capturing what is written
+	writtenPlan, writtenSettings := &captureArg{}, &captureArg{}
+	writtenEvents := []*captureArg{{}, {}}
+	writtenSecrets := []*captureArg{{}, {}}
+	writtenHeaders := []*captureArg{{}, {}}
+	writtenSampleRates := []*captureArg{{}, {}}
+
+	pool.ExpectBegin()
+	pool.ExpectExec(`UPDATE tenants`).
+		WithArgs(writtenPlan, writtenSettings, prod).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	pool.ExpectExec(`DELETE FROM webhooks WHERE tenant_id = \$1`).
+		WithArgs(prod).
+		WillReturnResult(pgxmock.NewResult("DELETE", 3))
+	for i, w := range exported.Webhooks {
+		pool.ExpectQuery(`INSERT INTO webhooks`).
+			WithArgs(prod, w.Name, w.URL, writtenSecrets[i], writtenEvents[i], writtenHeaders[i], writtenSampleRates[i], w.Enabled).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	}
+	pool.ExpectCommit()
+
+	imported, err := svc.Import(ctx, prod, *exported)
+	require.NoError(t, err)
+	require.Len(t, imported, 2)
+	assert.Equal(t, "crm", imported[0].Name)
+	assert.NotEmpty(t, imported[0].Secret, "secrets are regenerated on import")
+	assert.Equal(t, imported[0].Secret, writtenSecrets[0].value)
+	assert.NotEqual(t, imported[0].Secret, imported[1].Secret)
+
+	// 3. Export production again from what was written
+	expectExport(pool, prod, writtenPlan.value.(string), writtenSettings.value.(map[string]interface{}), [][]interface{}{
+		{"crm", "https://crm.example.com/hooks", writtenEvents[0].value, writtenHeaders[0].value, writtenSampleRates[0].value, true},
+		{"audit", "https://audit.example.com/rekko", writtenEvents[1].value, writtenHeaders[1].value, writtenSampleRates[1].value, false},
+	})
+
+	roundTripped, err := svc.Export(ctx, prod)
+	require.NoError(t, err)
+	assert.Equal(t, exported, roundTripped)
+	assert.NoError(t, pool.ExpectationsWereMet())
+}
+
+func TestTenantConfigService_Export_TenantNotFound(t *testing.T) {
+	svc, pool := newTenantConfigService(t)
+	tenantID := uuid.New()
+
+	pool.ExpectQuery(`SELECT plan, settings FROM tenants`).
+		WithArgs(tenantID).
+		WillReturnRows(pgxmock.NewRows([]string{"plan", "settings"}))
+
+	_, err := svc.Export(context.Background(), tenantID)
+	assert.ErrorIs(t, err, domain.ErrTenantNotFound)
+}
+
+func TestTenantConfigService_Import(t *testing.T) {
+	valid := func() TenantConfig {
+		return TenantConfig{
+			Version:  TenantConfigVersion,
+			Plan:     domain.PlanStarter,
+			Settings: map[string]interface{}{"search_enabled": true},
+			Webhooks: []TenantConfigWebhook{
+				{Name: "crm", URL: "https://crm.example.com/hooks", Events: []string{"face.registered"}, Enabled: true},
+			},
+		}
+	}
+
+	invalid := []struct {
+		name   string
+		mutate func(*TenantConfig)
+	}{
+		{"unsupported version", func(c *TenantConfig) { c.Version = 2 }},
+		{"unknown plan", func(c *TenantConfig) { c.Plan = "gold" }},
+		{"missing settings", func(c *TenantConfig) { c.Settings = nil }},
+		{"unusable setting", func(c *TenantConfig) { c.Settings["verify_policy"] = "liveness_only" }},
+		{"webhook without name", func(c *TenantConfig) { c.Webhooks[0].Name = " " }},
+		{"relative webhook url", func(c *TenantConfig) { c.Webhooks[0].URL = "/hooks" }},
+		{"non-http webhook url", func(c *TenantConfig) { c.Webhooks[0].URL = "ftp://crm.example.com" }},
+		{"webhook without events", func(c *TenantConfig) { c.Webhooks[0].Events = nil }},
+		{"unknown webhook event", func(c *TenantConfig) { c.Webhooks[0].Events = []string{"face.exploded"} }},
+		{"reserved webhook header", func(c *TenantConfig) { c.Webhooks[0].Headers = map[string]string{"X-Rekko-Event": "x"} }},
+		{"invalid webhook sample rate", func(c *TenantConfig) { c.Webhooks[0].SampleRates = map[string]int{"face.verified": 0} }},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, pool := newTenantConfigService(t)
+			config := valid()
+			tt.mutate(&config)
+
+			_, err := svc.Import(context.Background(), uuid.New(), config)
+
+			var appErr *domain.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, domain.ErrValidationFailed.Code, appErr.Code)
+			assert.NoError(t, pool.ExpectationsWereMet(), "nothing is written")
+		})
+	}
+
+	t.Run("unknown tenant rolls back", func(t *testing.T) {
+		svc, pool := newTenantConfigService(t)
+
+		pool.ExpectBegin()
+		pool.ExpectExec(`UPDATE tenants`).
+			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+		pool.ExpectRollback()
+
+		_, err := svc.Import(context.Background(), uuid.New(), valid())
+
+		assert.ErrorIs(t, err, domain.ErrTenantNotFound)
+		assert.NoError(t, pool.ExpectationsWereMet())
+	})
+
+	t.Run("failed webhook insert rolls back", func(t *testing.T) {
+		svc, pool := newTenantConfigService(t)
+
+		pool.ExpectBegin()
+		pool.ExpectExec(`UPDATE tenants`).
+			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		pool.ExpectExec(`DELETE FROM webhooks`).
+			WithArgs(pgxmock.AnyArg()).
+			WillReturnResult(pgxmock.NewResult("DELETE", 0))
+		pool.ExpectQuery(`INSERT INTO webhooks`).
+			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WillReturnError(errors.New("connection reset"))
+		pool.ExpectRollback()
+
+		_, err := svc.Import(context.Background(), uuid.New(), valid())
+
+		require.Error(t, err)
+		assert.NoError(t, pool.ExpectationsWereMet())
+	})
+}
@@ -1,6 +1,10 @@
 package admin
 
-import "time"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // MetricsParams holds query parameters for metrics endpoints
 type MetricsParams struct {
@@ -9,8 +13,18 @@ type MetricsParams struct {
 	Interval  string // hour, day, week, month
 	Limit     int
 	Offset    int
+	// ExcludeTest leaves out faces and verifications created with test-environment API keys
+	ExcludeTest bool
+	// GroupBy breaks operations and error metrics down further; only GroupByProvider is supported
+	GroupBy string
 }
 
+// GroupByProvider breaks verification metrics down by the provider that decided them
+const GroupByProvider = "provider"
+
+// UnknownProvider groups verifications recorded without a provider
+const UnknownProvider = "unknown"
+
 // MetricsResponse is the standard response wrapper for metrics endpoints
 type MetricsResponse struct {
 	Data       interface{}     `json:"data"`
@@ -53,9 +67,19 @@ type FacesTimeline struct {
 
 // OperationsMetrics contains metrics about operations
 type OperationsMetrics struct {
-	TotalOperations int64                `json:"total_operations"`
-	ByType          map[string]int64     `json:"by_type"`
-	Timeline        []OperationsTimeline `json:"timeline"`
+	TotalOperations int64                      `json:"total_operations"`
+	ByType          map[string]int64           `json:"by_type"`
+	ByFailReason    map[string]int64           `json:"by_fail_reason"`        // failed verifications of the period per fail_reason
+	ByProvider      map[string]ProviderMetrics `json:"by_provider,omitempty"` // verifications of the period per deciding provider, only with group_by=provider
+	Timeline        []OperationsTimeline       `json:"timeline"`
+}
+
+// ProviderMetrics counts the verifications decided by one provider
+type ProviderMetrics struct {
+	Total     int64   `json:"total"`
+	Success   int64   `json:"success"`
+	Failure   int64   `json:"failure"`
+	ErrorRate float64 `json:"error_rate"`
 }
 
 // OperationsTimeline represents a timeline entry for operations metrics
@@ -66,6 +90,38 @@ type OperationsTimeline struct {
 	Failure int64  `json:"failure"`
 }
 
+// FaceTrendMetrics contains the verification trend of a single external_id
+type FaceTrendMetrics struct {
+	ExternalID    string           `json:"external_id"`
+	TotalAttempts int64            `json:"total_attempts"`
+	Successes     int64            `json:"successes"`
+	Failures      int64            `json:"failures"`
+	SuccessRate   float64          `json:"success_rate"`
+	Timeline      []FaceTrendEntry `json:"timeline"`
+	// TotalPeriods counts the timeline entries of the whole period, for pagination
+	TotalPeriods int `json:"-"`
+}
+
+// UsageForecast projects the tenant's month-to-date usage linearly to the end of the month
+type UsageForecast struct {
+	Month            string  `json:"month"` // YYYY-MM
+	MonthToDate      int64   `json:"month_to_date"`
+	ElapsedDays      int     `json:"elapsed_days"`
+	DaysInMonth      int     `json:"days_in_month"`
+	DailyAverage     float64 `json:"daily_average"`
+	ProjectedTotal   int64   `json:"projected_total"`
+	MaxRequestsMonth *int    `json:"max_requests_month"` // nil when the tenant has no monthly quota
+	ExceedsQuota     bool    `json:"exceeds_quota"`
+}
+
+// FaceTrendEntry represents a timeline entry for a single external_id
+type FaceTrendEntry struct {
+	Period    string `json:"period"`
+	Attempts  int64  `json:"attempts"`
+	Successes int64  `json:"successes"`
+	Failures  int64  `json:"failures"`
+}
+
 // RequestsMetrics contains metrics about HTTP requests
 type RequestsMetrics struct {
 	TotalRequests int64              `json:"total_requests"`
@@ -116,10 +172,11 @@ type ThroughputTimeline struct {
 
 // ErrorMetrics contains error rate metrics
 type ErrorMetrics struct {
-	TotalErrors int64            `json:"total_errors"`
-	ErrorRate   float64          `json:"error_rate"`
-	ByType      map[string]int64 `json:"by_type"`
-	Timeline    []ErrorTimeline  `json:"timeline"`
+	TotalErrors int64                      `json:"total_errors"`
+	ErrorRate   float64                    `json:"error_rate"`
+	ByType      map[string]int64           `json:"by_type"`
+	ByProvider  map[string]ProviderMetrics `json:"by_provider,omitempty"` // verifications of the period per deciding provider, only with group_by=provider
+	Timeline    []ErrorTimeline            `json:"timeline"`
 }
 
 // ErrorTimeline represents a timeline entry for error metrics
@@ -179,6 +236,60 @@ type MatchTimeline struct {
 	AverageMatchScore float64 `json:"average_match_score"`
 }
 
+// LivenessMetrics contains pass/fail statistics of the liveness checks run by verifications
+type LivenessMetrics struct {
+	Passed   int64   `json:"passed"`
+	Failed   int64   `json:"failed"`
+	PassRate float64 `json:"pass_rate"`
+	// AverageLivenessScore only covers verifications recorded with a liveness score
+	AverageLivenessScore float64            `json:"average_liveness_score"`
+	Timeline             []LivenessTimeline `json:"timeline"`
+}
+
+// LivenessTimeline represents a timeline entry for liveness metrics
+type LivenessTimeline struct {
+	Period               string  `json:"period"`
+	Passed               int64   `json:"passed"`
+	Failed               int64   `json:"failed"`
+	PassRate             float64 `json:"pass_rate"`
+	AverageLivenessScore float64 `json:"average_liveness_score"`
+}
+
+// GateMetrics contains verification statistics grouped by the client IP (gate) that requested them
+type GateMetrics struct {
+	Gates []GateStats `json:"gates"`
+}
+
+// GateStats represents the verification statistics of a single gate
+type GateStats struct {
+	Gate          string  `json:"gate"`
+	Verifications int64   `json:"verifications"`
+	Successes     int64   `json:"successes"`
+	Failures      int64   `json:"failures"`
+	SuccessRate   float64 `json:"success_rate"`
+	AvgLatencyMs  float64 `json:"avg_latency_ms"`
+}
+
+// UnknownGate labels verifications recorded without a client IP
+const UnknownGate = "unknown"
+
+// DefaultTopIdentities is the number of identities ranked when no limit is given
+const DefaultTopIdentities = 10
+
+// TopIdentities ranks the external_ids with the most successful verifications in a period
+type TopIdentities struct {
+	Identities []IdentityStats `json:"identities"`
+}
+
+// IdentityStats represents the successful verifications of a single external_id
+type IdentityStats struct {
+	ExternalID    string    `json:"external_id"`
+	Verifications int64     `json:"verifications"`
+	LastSeenAt    time.Time `json:"last_seen_at"`
+	// Metadata holds the face metadata allowed by the tenant's search_metadata_keys
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
 // Super Admin Types
 
 // TenantWithMetrics represents a tenant with summary metrics
@@ -191,6 +302,42 @@ type TenantWithMetrics struct {
 	Metrics   TenantMetricsSummary `json:"metrics"`
 }
 
+// Sort orders of the super admin tenant list, all descending
+const (
+	TenantSortCreatedAt = "created_at"
+	TenantSortFaces     = "faces"
+	TenantSortRequests  = "requests"
+	TenantSortErrorRate = "error_rate"
+)
+
+// tenantSortOrders maps each sort to its ORDER BY clause, newest first on ties
+var tenantSortOrders = map[string]string{
+	TenantSortCreatedAt: "t.created_at DESC",
+	TenantSortFaces:     "total_faces DESC, t.created_at DESC",
+	TenantSortRequests:  "total_requests DESC, t.created_at DESC",
+	TenantSortErrorRate: "error_rate DESC, t.created_at DESC",
+}
+
+// IsValidTenantSort reports whether sort is one of the TenantSort* values
+func IsValidTenantSort(sort string) bool {
+	_, ok := tenantSortOrders[sort]
+	return ok
+}
+
+// TenantListParams filters and sorts the super admin tenant list
+type TenantListParams struct {
+	Limit  int
+	Offset int
+	// Plan keeps tenants of one plan, empty for every plan
+	Plan string
+	// IsActive keeps active or inactive tenants, nil for both
+	IsActive *bool
+	// Search keeps tenants whose name or slug contains it, case-insensitive
+	Search string
+	// Sort is one of the TenantSort* values, TenantSortCreatedAt when empty
+	Sort string
+}
+
 // TenantMetricsSummary contains aggregated metrics for a tenant
 type TenantMetricsSummary struct {
 	TotalFaces    int64   `json:"total_faces"`
@@ -213,6 +360,8 @@ type ServiceHealth struct {
 	Status  string `json:"status"`
 	Latency string `json:"latency"`
 	Message string `json:"message,omitempty"`
+	// Extensions reports whether each required PostgreSQL extension is installed
+	Extensions map[string]bool `json:"extensions,omitempty"`
 }
 
 // ProviderHealth represents health of a face recognition provider
@@ -229,6 +378,15 @@ type SystemMetrics struct {
 	Goroutines        int           `json:"goroutines"`
 	DBConnections     DBConnMetrics `json:"db_connections"`
 	RequestsPerSecond float64       `json:"requests_per_second"`
+	// SlowQueries is set when slow query logging is enabled
+	SlowQueries *SlowQueryMetrics `json:"slow_queries,omitempty"`
+}
+
+// SlowQueryMetrics counts the queries that took at least the threshold since startup
+type SlowQueryMetrics struct {
+	ThresholdMs int64            `json:"threshold_ms"`
+	Total       int64            `json:"total"`
+	ByQuery     map[string]int64 `json:"by_query"`
 }
 
 // MemoryMetrics contains Go runtime memory metrics
@@ -253,3 +411,40 @@ type UpdateQuotaRequest struct {
 	MaxRequestsMonth *int     `json:"max_requests_month,omitempty"`
 	ThresholdValue   *float64 `json:"threshold_value,omitempty"`
 }
+
+// TenantConfig is a tenant's portable configuration, exported from one tenant and
+// imported into another. Quotas are part of settings; webhook secrets are never included.
+type TenantConfig struct {
+	Version  int                    `json:"version"`
+	Plan     string                 `json:"plan"`
+	Settings map[string]interface{} `json:"settings"`
+	Webhooks []TenantConfigWebhook  `json:"webhooks"`
+}
+
+// TenantConfigWebhook is a webhook subscription without its signing secret
+type TenantConfigWebhook struct {
+	Name        string            `json:"name"`
+	URL         string            `json:"url"`
+	Events      []string          `json:"events"`
+	Enabled     bool              `json:"enabled"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	SampleRates map[string]int    `json:"sample_rates,omitempty"`
+}
+
+// ImportedWebhook is a webhook created by a config import with its new signing secret
+type ImportedWebhook struct {
+	ID     uuid.UUID `json:"id"`
+	Name   string    `json:"name"`
+	URL    string    `json:"url"`
+	Secret string    `json:"secret"`
+}
+
+// MetricsOverview holds the headline numbers of a live dashboard
+type MetricsOverview struct {
+	TotalFaces         int64     `json:"total_faces"`
+	TotalVerifications int64     `json:"total_verifications"`
+	ErrorRate          float64   `json:"error_rate"` // percentage of failed verifications in the window
+	RequestsPerSecond  float64   `json:"requests_per_second"`
+	Window             string    `json:"window"`
+	GeneratedAt        time.Time `json:"generated_at"`
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/config"
+)
+
+// Deprecated routes, keyed as "METHOD /path" like DEPRECATION_SUNSETS
+const routeAdminFacesCompare = "GET /v1/admin/faces/compare"
+
+// routeDeprecations returns the deprecation notices in force for this deployment, with the
+// Sunset dates set in DEPRECATION_SUNSETS
+func routeDeprecations(cfg *config.Config) map[string]middleware.DeprecationNotice {
+	notices := make(map[string]middleware.DeprecationNotice)
+
+	// Rekognition stores no embeddings, so comparing two registered faces can only ever
+	// answer EMBEDDING_UNAVAILABLE there
+	if cfg.FaceProvider == "rekognition" {
+		notices[routeAdminFacesCompare] = middleware.DeprecationNotice{
+			Since: time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC),
+			Link:  "/swagger/",
+		}
+	}
+
+	for route, notice := range notices {
+		if date, ok := cfg.DeprecationSunsets[route]; ok {
+			// config.Load already rejected malformed dates
+			notice.Sunset, _ = time.Parse(time.DateOnly, date)
+			notices[route] = notice
+		}
+	}
+	return notices
+}
+
+// deprecated prepends the route's deprecation notice, when it has one, to its handlers
+func (r *Router) deprecated(route string, handlers ...fiber.Handler) []fiber.Handler {
+	notice, ok := r.deprecations[route]
+	if !ok {
+		return handlers
+	}
+	return append([]fiber.Handler{middleware.Deprecated(notice)}, handlers...)
+}
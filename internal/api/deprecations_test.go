@@ -0,0 +1,51 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/config"
+)
+
+func TestRouteDeprecations(t *testing.T) {
+	newApp := func(cfg *config.Config) *fiber.App {
+		r := &Router{deprecations: routeDeprecations(cfg)}
+		app := fiber.New()
+		app.Get("/v1/admin/faces/compare", r.deprecated(routeAdminFacesCompare, func(c *fiber.Ctx) error {
+			return c.SendString("compared")
+		})...)
+		return app
+	}
+
+	t.Run("compare is deprecated on rekognition with the configured sunset", func(t *testing.T) {
+		app := newApp(&config.Config{
+			FaceProvider:       "rekognition",
+			DeprecationSunsets: map[string]string{routeAdminFacesCompare: "2027-06-30"},
+		})
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/admin/faces/compare", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "@1792281600", resp.Header.Get(middleware.HeaderDeprecation))
+		assert.Equal(t, "Wed, 30 Jun 2027 00:00:00 GMT", resp.Header.Get(middleware.HeaderSunset))
+		assert.Contains(t, resp.Header.Get(fiber.HeaderLink), `rel="deprecation"`)
+	})
+
+	t.Run("compare is not deprecated on deepface", func(t *testing.T) {
+		app := newApp(&config.Config{
+			FaceProvider:       "deepface",
+			DeprecationSunsets: map[string]string{routeAdminFacesCompare: "2027-06-30"},
+		})
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/admin/faces/compare", nil))
+		require.NoError(t, err)
+		assert.Empty(t, resp.Header.Get(middleware.HeaderDeprecation))
+		assert.Empty(t, resp.Header.Get(middleware.HeaderSunset))
+	})
+}
@@ -2,6 +2,7 @@ package docs
 
 import (
 	"github.com/go-swagno/swagno"
+	"github.com/go-swagno/swagno/components/definition"
 	"github.com/go-swagno/swagno/components/endpoint"
 	"github.com/go-swagno/swagno/components/http/response"
 	"github.com/go-swagno/swagno/components/mime"
@@ -14,14 +15,51 @@ type RegisterFaceResponse struct {
 	ExternalID   string  `json:"external_id" example:"user-123"`
 	QualityScore float64 `json:"quality_score" example:"0.95"`
 	CreatedAt    string  `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	ExpiresAt    string  `json:"expires_at,omitempty" example:"2024-01-08T00:00:00Z"`
+	// EmbeddingHash is only present when the tenant enables embedding_hash
+	EmbeddingHash string `json:"embedding_hash,omitempty" example:"3f79bb7b435b05321651daefd374cdc681dc06faa65e374e38337b88ca046dea"`
 }
 
 // VerifyFaceResponse represents the response for face verification
 type VerifyFaceResponse struct {
+	Verified       bool    `json:"verified" example:"true"`
+	Confidence     float64 `json:"confidence" example:"0.92"`
+	MatchPassed    bool    `json:"match_passed" example:"true"`
+	LivenessPassed *bool   `json:"liveness_passed,omitempty" example:"true"`
+	FailReason     string  `json:"fail_reason,omitempty" example:""`
+	ExternalID     string  `json:"external_id" example:"user-123"`
+	LatencyMs      int64   `json:"latency_ms" example:"45"`
+	// Threshold the match was decided against and its layer: security_level, tenant or default
+	ThresholdApplied float64 `json:"threshold_applied" example:"0.8"`
+	ThresholdSource  string  `json:"threshold_source" example:"tenant"`
+	// Provider experiment arm that decided: primary or alternate; omitted outside experiments
+	ProviderVariant string `json:"provider_variant,omitempty" example:"alternate"`
+}
+
+// VerifyDocumentResponse represents the response for verification against an ID document
+type VerifyDocumentResponse struct {
 	Verified   bool    `json:"verified" example:"true"`
-	Confidence float64 `json:"confidence" example:"0.92"`
-	ExternalID string  `json:"external_id" example:"user-123"`
-	LatencyMs  int64   `json:"latency_ms" example:"45"`
+	Similarity float64 `json:"similarity" example:"0.91"`
+	Threshold  float64 `json:"threshold" example:"0.8"`
+	LatencyMs  int64   `json:"latency_ms" example:"180"`
+}
+
+// FaceExistsResult represents the registration status of a single external_id
+type FaceExistsResult struct {
+	Registered   bool   `json:"registered" example:"true"`
+	RegisteredAt string `json:"registered_at,omitempty" example:"2024-01-01T00:00:00Z"`
+}
+
+// FaceExistsResponse represents the response for a batch existence check
+type FaceExistsResponse struct {
+	Results map[string]FaceExistsResult `json:"results"`
+}
+
+// BulkMetadataResponse represents the response for a bulk metadata patch
+type BulkMetadataResponse struct {
+	Updated     int      `json:"updated" example:"2"`
+	NotFound    int      `json:"not_found" example:"1"`
+	NotFoundIDs []string `json:"not_found_ids" example:"user-789"`
 }
 
 // LivenessCheckResponse represents the response for liveness check
@@ -32,6 +70,11 @@ type LivenessCheckResponse struct {
 	Reasons    []string           `json:"reasons,omitempty" example:"[]"`
 }
 
+// FaceCountResponse represents the response for the anonymized crowd count
+type FaceCountResponse struct {
+	FaceCount int `json:"face_count" example:"12"`
+}
+
 // LivenessChecksData represents individual liveness checks
 type LivenessChecksData struct {
 	EyesOpen     bool `json:"eyes_open" example:"true"`
@@ -42,8 +85,26 @@ type LivenessChecksData struct {
 
 // ErrorResponse represents a standard error response
 type ErrorResponse struct {
-	Code    string `json:"code" example:"VALIDATION_FAILED"`
-	Message string `json:"message" example:"Request validation failed"`
+	Code    string   `json:"code" example:"VALIDATION_FAILED"`
+	Message string   `json:"message" example:"Request validation failed"`
+	Reasons []string `json:"reasons,omitempty" example:"too_dark,face_not_frontal"`
+}
+
+// RateLimitErrorResponse is a quota rejection with the usage that triggered it
+type RateLimitErrorResponse struct {
+	Code    string `json:"code" example:"SEARCH_RATE_LIMIT_EXCEEDED"`
+	Message string `json:"message" example:"Search rate limit exceeded, try again later"`
+	Limit   int    `json:"limit" example:"30"`
+	Current int    `json:"current" example:"31"`
+	ResetAt string `json:"reset_at" example:"2026-01-15T10:31:00Z"`
+}
+
+// MultipleFacesErrorResponse is an image rejected for holding more faces than allowed
+type MultipleFacesErrorResponse struct {
+	Code          string `json:"code" example:"MULTIPLE_FACES"`
+	Message       string `json:"message" example:"3 faces detected, only 1 allowed"`
+	FacesDetected int    `json:"faces_detected" example:"3"`
+	MaxFaces      int    `json:"max_faces" example:"1"`
 }
 
 // EmptyResponse represents no content response (204)
@@ -74,9 +135,19 @@ type OperationsTimeline struct {
 
 // OperationsMetricsData contains operation metrics
 type OperationsMetricsData struct {
-	TotalOperations int64                `json:"total_operations" example:"5000"`
-	ByType          map[string]int64     `json:"by_type"`
-	Timeline        []OperationsTimeline `json:"timeline"`
+	TotalOperations int64                      `json:"total_operations" example:"5000"`
+	ByType          map[string]int64           `json:"by_type"`
+	ByFailReason    map[string]int64           `json:"by_fail_reason"`
+	ByProvider      map[string]ProviderMetrics `json:"by_provider,omitempty"`
+	Timeline        []OperationsTimeline       `json:"timeline"`
+}
+
+// ProviderMetrics counts the verifications decided by one provider (group_by=provider)
+type ProviderMetrics struct {
+	Total     int64   `json:"total" example:"1200"`
+	Success   int64   `json:"success" example:"1150"`
+	Failure   int64   `json:"failure" example:"50"`
+	ErrorRate float64 `json:"error_rate" example:"4.17"`
 }
 
 // RequestsTimeline represents timeline for HTTP requests
@@ -139,10 +210,11 @@ type ErrorTimeline struct {
 
 // ErrorMetricsData contains error rate metrics
 type ErrorMetricsData struct {
-	TotalErrors int64            `json:"total_errors" example:"250"`
-	ErrorRate   float64          `json:"error_rate" example:"2.38"`
-	ByType      map[string]int64 `json:"by_type"`
-	Timeline    []ErrorTimeline  `json:"timeline"`
+	TotalErrors int64                      `json:"total_errors" example:"250"`
+	ErrorRate   float64                    `json:"error_rate" example:"2.38"`
+	ByType      map[string]int64           `json:"by_type"`
+	ByProvider  map[string]ProviderMetrics `json:"by_provider,omitempty"`
+	Timeline    []ErrorTimeline            `json:"timeline"`
 }
 
 // Admin Quality Metrics Types
@@ -196,6 +268,42 @@ type MatchMetricsData struct {
 	Timeline          []MatchTimeline `json:"timeline"`
 }
 
+// LivenessTimeline represents liveness timeline entry
+type LivenessTimeline struct {
+	Period               string  `json:"period" example:"2024-01-01"`
+	Passed               int64   `json:"passed" example:"190"`
+	Failed               int64   `json:"failed" example:"10"`
+	PassRate             float64 `json:"pass_rate" example:"95.0"`
+	AverageLivenessScore float64 `json:"average_liveness_score" example:"0.91"`
+}
+
+// LivenessMetricsData contains liveness check statistics
+type LivenessMetricsData struct {
+	Passed               int64              `json:"passed" example:"4750"`
+	Failed               int64              `json:"failed" example:"250"`
+	PassRate             float64            `json:"pass_rate" example:"95.0"`
+	AverageLivenessScore float64            `json:"average_liveness_score" example:"0.91"`
+	Timeline             []LivenessTimeline `json:"timeline"`
+}
+
+// FaceTrendEntry represents timeline for a single external_id
+type FaceTrendEntry struct {
+	Period    string `json:"period" example:"2024-01-01"`
+	Attempts  int64  `json:"attempts" example:"4"`
+	Successes int64  `json:"successes" example:"3"`
+	Failures  int64  `json:"failures" example:"1"`
+}
+
+// FaceTrendData contains the verification trend of a single external_id
+type FaceTrendData struct {
+	ExternalID    string           `json:"external_id" example:"user-123"`
+	TotalAttempts int64            `json:"total_attempts" example:"12"`
+	Successes     int64            `json:"successes" example:"10"`
+	Failures      int64            `json:"failures" example:"2"`
+	SuccessRate   float64          `json:"success_rate" example:"0.83"`
+	Timeline      []FaceTrendEntry `json:"timeline"`
+}
+
 // Common Admin Types
 
 // PeriodInfo represents the time period for metrics
@@ -283,6 +391,424 @@ type MatchMetricsResponse struct {
 	Pagination *PaginationMeta   `json:"pagination,omitempty"`
 }
 
+// LivenessMetricsResponse wraps liveness metrics
+type LivenessMetricsResponse struct {
+	Data       LivenessMetricsData `json:"data"`
+	Meta       AdminResponseMeta   `json:"meta"`
+	Pagination *PaginationMeta     `json:"pagination,omitempty"`
+}
+
+// GateStats contains the verification statistics of a single gate (client IP)
+type GateStats struct {
+	Gate          string  `json:"gate" example:"10.0.0.11"`
+	Verifications int64   `json:"verifications" example:"320"`
+	Successes     int64   `json:"successes" example:"301"`
+	Failures      int64   `json:"failures" example:"19"`
+	SuccessRate   float64 `json:"success_rate" example:"0.94"`
+	AvgLatencyMs  float64 `json:"avg_latency_ms" example:"182.5"`
+}
+
+// GateMetricsData contains verification statistics grouped by gate
+type GateMetricsData struct {
+	Gates []GateStats `json:"gates"`
+}
+
+// GateMetricsResponse wraps gate metrics
+type GateMetricsResponse struct {
+	Data GateMetricsData   `json:"data"`
+	Meta AdminResponseMeta `json:"meta"`
+}
+
+// IdentityStats represents the successful verifications of a single external_id
+type IdentityStats struct {
+	ExternalID    string                 `json:"external_id" example:"staff_042"`
+	Verifications int64                  `json:"verifications" example:"87"`
+	LastSeenAt    string                 `json:"last_seen_at" example:"2024-01-31T18:02:11Z"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// TopIdentitiesData ranks external_ids by successful verifications
+type TopIdentitiesData struct {
+	Identities []IdentityStats `json:"identities"`
+}
+
+// TopIdentitiesResponse wraps the top identities ranking
+type TopIdentitiesResponse struct {
+	Data TopIdentitiesData `json:"data"`
+	Meta AdminResponseMeta `json:"meta"`
+}
+
+// MetricsOverviewFrame is the data of each overview event on the metrics stream
+type MetricsOverviewFrame struct {
+	TotalFaces         int64   `json:"total_faces" example:"1520"`
+	TotalVerifications int64   `json:"total_verifications" example:"48210"`
+	ErrorRate          float64 `json:"error_rate" example:"2.5"`
+	RequestsPerSecond  float64 `json:"requests_per_second" example:"3.2"`
+	Window             string  `json:"window" example:"5m0s"`
+	GeneratedAt        string  `json:"generated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// FaceTrendResponse wraps the verification trend of a single external_id
+type FaceTrendResponse struct {
+	Data       FaceTrendData     `json:"data"`
+	Meta       AdminResponseMeta `json:"meta"`
+	Pagination *PaginationMeta   `json:"pagination,omitempty"`
+}
+
+// AdminFaceResponse represents a registered face as seen by admins
+type AdminFaceResponse struct {
+	FaceID           string                 `json:"face_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ExternalID       string                 `json:"external_id" example:"user-123"`
+	ProviderFaceID   *string                `json:"provider_face_id" example:"0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0"`
+	EmbeddingHash    *string                `json:"embedding_hash" example:"3f79bb7b435b05321651daefd374cdc681dc06faa65e374e38337b88ca046dea"`
+	EmbeddingModel   string                 `json:"embedding_model,omitempty" example:"deepface/Facenet512"`
+	EmbeddingVersion string                 `json:"embedding_version,omitempty" example:"512d"`
+	Metadata         map[string]interface{} `json:"metadata,omitempty"`
+	QualityScore     float64                `json:"quality_score" example:"0.92"`
+	IsTest           bool                   `json:"is_test" example:"false"`
+	CreatedAt        string                 `json:"created_at" example:"2024-01-15T10:30:00Z"`
+	UpdatedAt        string                 `json:"updated_at" example:"2024-01-15T10:30:00Z"`
+	ExpiresAt        *string                `json:"expires_at,omitempty" example:"2024-01-22T10:30:00Z"`
+}
+
+// FaceExportRecord is one NDJSON line of the faces export
+type FaceExportRecord struct {
+	AdminFaceResponse
+	Embedding []float64 `json:"embedding,omitempty"`
+}
+
+// AdminVerificationResponse represents everything recorded about one verification
+type AdminVerificationResponse struct {
+	VerificationID   string                       `json:"verification_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ExternalID       string                       `json:"external_id" example:"user-123"`
+	FaceID           *string                      `json:"face_id" example:"6ba7b810-9dad-11d1-80b4-00c04fd430c8"`
+	Verified         bool                         `json:"verified" example:"false"`
+	Similarity       float64                      `json:"similarity" example:"0.91"`
+	ThresholdApplied *float64                     `json:"threshold_applied" example:"0.85"`
+	ThresholdSource  string                       `json:"threshold_source,omitempty" example:"segment"`
+	Liveness         *VerificationLivenessDetails `json:"liveness"`
+	FailReason       string                       `json:"fail_reason,omitempty" example:"liveness_failed"`
+	Provider         string                       `json:"provider,omitempty" example:"deepface"`
+	ProviderVariant  string                       `json:"provider_variant,omitempty" example:"alternate"`
+	ClientIP         string                       `json:"client_ip,omitempty" example:"10.0.0.7"`
+	LatencyMs        int64                        `json:"latency_ms" example:"180"`
+	IsTest           bool                         `json:"is_test" example:"false"`
+	CreatedAt        string                       `json:"created_at" example:"2026-03-02T10:00:00Z"`
+	FaceMetadata     map[string]interface{}       `json:"face_metadata,omitempty"`
+}
+
+// VerificationLivenessDetails represents the liveness check of a verification
+type VerificationLivenessDetails struct {
+	Passed bool     `json:"passed" example:"false"`
+	Score  *float64 `json:"score" example:"0.42"`
+}
+
+// FaceCompareResponse represents the similarity between two registered identities
+type FaceCompareResponse struct {
+	A          string  `json:"a" example:"user-123"`
+	B          string  `json:"b" example:"user-456"`
+	Similarity float64 `json:"similarity" example:"0.93"`
+	Threshold  float64 `json:"threshold" example:"0.8"`
+	Match      bool    `json:"match" example:"true"`
+}
+
+// FaceRecountResponse is a fresh face count compared with the provider collection
+type FaceRecountResponse struct {
+	DatabaseCount       int    `json:"database_count" example:"120"`
+	ProviderCount       *int64 `json:"provider_count" example:"118"`
+	Delta               *int64 `json:"delta" example:"-2"`
+	PreviousCachedCount *int   `json:"previous_cached_count" example:"95"`
+	CacheRefreshed      bool   `json:"cache_refreshed" example:"true"`
+	RecountedAt         string `json:"recounted_at" example:"2024-01-15T10:30:00Z"`
+}
+
+// RateLimitBucket represents a tenant's current usage of one rate limit bucket
+type RateLimitBucket struct {
+	Bucket    string `json:"bucket" example:"search"`
+	Limit     int    `json:"limit" example:"30"`
+	Count     int    `json:"count" example:"12"`
+	Remaining int    `json:"remaining,omitempty" example:"18"`
+	ResetAt   string `json:"reset_at,omitempty" example:"2025-01-15T10:31:00Z"`
+}
+
+// RateLimitsResponse lists rate limit usage per bucket
+type RateLimitsResponse struct {
+	Buckets []RateLimitBucket `json:"buckets"`
+}
+
+// ThresholdLayer is one candidate value of a threshold resolution, null when unset
+type ThresholdLayer struct {
+	Source string   `json:"source" example:"tenant"`
+	Value  *float64 `json:"value" example:"0.82"`
+}
+
+// ThresholdResolution explains the threshold an operation applies
+type ThresholdResolution struct {
+	Operation        string           `json:"operation" example:"verify"`
+	ThresholdApplied float64          `json:"threshold_applied" example:"0.82"`
+	Source           string           `json:"source" example:"tenant"`
+	Layers           []ThresholdLayer `json:"layers"`
+}
+
+// ThresholdsResponse lists the effective threshold of each operation
+type ThresholdsResponse struct {
+	SecurityLevel string                `json:"security_level" example:"standard"`
+	Operations    []ThresholdResolution `json:"operations"`
+}
+
+// CalibrationRates are the errors a threshold makes on the labeled set
+type CalibrationRates struct {
+	Threshold       float64 `json:"threshold" example:"0.8"`
+	FalseAccepts    int     `json:"false_accepts" example:"1"`
+	FalseRejects    int     `json:"false_rejects" example:"2"`
+	FalseAcceptRate float64 `json:"false_accept_rate" example:"0.05"`
+	FalseRejectRate float64 `json:"false_reject_rate" example:"0.1"`
+}
+
+// CalibrationScore is the similarity of one labeled pair
+type CalibrationScore struct {
+	Index      int     `json:"index" example:"0"`
+	Match      bool    `json:"match" example:"true"`
+	Similarity float64 `json:"similarity" example:"0.91"`
+	Error      string  `json:"error,omitempty" example:"no face detected in image_b"`
+}
+
+// CalibrationReportResponse is the threshold sweep over a labeled set
+type CalibrationReportResponse struct {
+	Pairs         int                `json:"pairs" example:"40"`
+	Evaluated     int                `json:"evaluated" example:"39"`
+	MatchPairs    int                `json:"match_pairs" example:"20"`
+	NonMatchPairs int                `json:"non_match_pairs" example:"19"`
+	Current       CalibrationRates   `json:"current"`
+	Sweep         []CalibrationRates `json:"sweep"`
+	Scores        []CalibrationScore `json:"scores"`
+}
+
+// RecentVerificationResponse reports a recent successful verification
+type RecentVerificationResponse struct {
+	ExternalID       string  `json:"external_id" example:"user-123"`
+	RecentlyVerified bool    `json:"recently_verified" example:"true"`
+	LastVerifiedAt   *string `json:"last_verified_at" example:"2026-03-02T10:00:00Z"`
+	WithinSeconds    int64   `json:"within_seconds" example:"900"`
+	LivenessRequired bool    `json:"liveness_required" example:"false"`
+}
+
+// SearchAuditEntry is one recorded 1:N search
+type SearchAuditEntry struct {
+	ID                 string   `json:"id" example:"0e8f1c2a-3b4d-4e5f-8a9b-0c1d2e3f4a5b"`
+	ResultsCount       int      `json:"results_count" example:"2"`
+	Threshold          float64  `json:"threshold" example:"0.8"`
+	MaxResults         int      `json:"max_results" example:"10"`
+	LatencyMs          int64    `json:"latency_ms" example:"35"`
+	TopMatchExternalID *string  `json:"top_match_external_id" example:"user-123"`
+	TopMatchSimilarity *float64 `json:"top_match_similarity" example:"0.97"`
+	ClientIP           string   `json:"client_ip" example:"203.0.113.7"`
+	CreatedAt          string   `json:"created_at" example:"2026-03-02T10:00:00Z"`
+}
+
+// SearchAuditsResponse lists search audits with pagination
+type SearchAuditsResponse struct {
+	Searches   []SearchAuditEntry `json:"searches"`
+	Pagination PaginationMeta     `json:"pagination"`
+}
+
+// UsageForecastData is the month-to-date usage projected to the end of the month
+type UsageForecastData struct {
+	Month            string  `json:"month" example:"2024-01"`
+	MonthToDate      int64   `json:"month_to_date" example:"4800"`
+	ElapsedDays      int     `json:"elapsed_days" example:"12"`
+	DaysInMonth      int     `json:"days_in_month" example:"31"`
+	DailyAverage     float64 `json:"daily_average" example:"400"`
+	ProjectedTotal   int64   `json:"projected_total" example:"12400"`
+	MaxRequestsMonth *int    `json:"max_requests_month" example:"10000"`
+	ExceedsQuota     bool    `json:"exceeds_quota" example:"true"`
+}
+
+// UsageForecastResponse wraps the usage forecast
+type UsageForecastResponse struct {
+	Data UsageForecastData `json:"data"`
+	Meta AdminResponseMeta `json:"meta"`
+}
+
+// UsageDetailDoc is the usage of one metered operation against the plan quota
+type UsageDetailDoc struct {
+	Used       int     `json:"used" example:"1200"`
+	Quota      int     `json:"quota" example:"1000"`
+	Percentage float64 `json:"percentage" example:"120"`
+	Overage    int     `json:"overage" example:"200"`
+}
+
+// UsagePlanDoc is the tenant plan the usage is billed against
+type UsagePlanDoc struct {
+	ID                 string  `json:"id" example:"starter"`
+	Name               string  `json:"name" example:"Starter"`
+	MonthlyPrice       float64 `json:"monthly_price" example:"99"`
+	QuotaRegistrations int     `json:"quota_registrations" example:"1000"`
+	QuotaVerifications int     `json:"quota_verifications" example:"10000"`
+	OveragePrice       float64 `json:"overage_price" example:"0.05"`
+	CreatedAt          string  `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt          string  `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// UsageOverageBreakdownDoc splits the overage fee by operation
+type UsageOverageBreakdownDoc struct {
+	Registrations float64 `json:"registrations" example:"10"`
+	Verifications float64 `json:"verifications" example:"0"`
+}
+
+// UsageBillingDoc is the billing summary of the period
+type UsageBillingDoc struct {
+	BaseFee          float64                  `json:"base_fee" example:"99"`
+	OverageFee       float64                  `json:"overage_fee" example:"10"`
+	Total            float64                  `json:"total" example:"109"`
+	OverageBreakdown UsageOverageBreakdownDoc `json:"overage_breakdown"`
+}
+
+// UsageAlertDoc warns that a quota is close to or over its limit
+type UsageAlertDoc struct {
+	Type       string  `json:"type" example:"registrations_exceeded"`
+	Percentage float64 `json:"percentage" example:"120"`
+	Message    string  `json:"message" example:"Registration quota exceeded"`
+}
+
+// UsageResponse is the tenant's usage for a billing period
+type UsageResponse struct {
+	Period         string          `json:"period" example:"2024-01"`
+	Plan           UsagePlanDoc    `json:"plan"`
+	Registrations  UsageDetailDoc  `json:"registrations"`
+	Verifications  UsageDetailDoc  `json:"verifications"`
+	LivenessChecks UsageDetailDoc  `json:"liveness_checks"`
+	Billing        UsageBillingDoc `json:"billing"`
+	Alerts         []UsageAlertDoc `json:"alerts,omitempty"`
+}
+
+// WebhookDoc is a configured webhook; its secret is never returned after creation and its
+// header values are returned as [REDACTED]
+type WebhookDoc struct {
+	ID              string            `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name            string            `json:"name" example:"crm"`
+	URL             string            `json:"url" example:"https://example.com/webhook"`
+	Events          []string          `json:"events" example:"face.registered,face.verified"`
+	Enabled         bool              `json:"enabled" example:"true"`
+	Headers         map[string]string `json:"headers,omitempty"`
+	SampleRates     map[string]int    `json:"sample_rates,omitempty"`
+	LastTriggeredAt string            `json:"last_triggered_at,omitempty" example:"2024-01-01T00:00:00Z"`
+	CreatedAt       string            `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt       string            `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// WebhookListResponse lists the tenant's webhooks
+type WebhookListResponse struct {
+	Webhooks []WebhookDoc `json:"webhooks"`
+}
+
+// CreateWebhookRequest configures a new webhook
+type CreateWebhookRequest struct {
+	Name    string   `json:"name" example:"crm"`
+	URL     string   `json:"url" example:"https://example.com/webhook"`
+	Events  []string `json:"events" example:"face.registered,face.verified"`
+	Enabled bool     `json:"enabled" example:"true"`
+	// Static headers sent with every delivery; X-Rekko-*, Content-Type and User-Agent are reserved
+	Headers map[string]string `json:"headers,omitempty"`
+	// Delivers 1 in N successful events per event type; failures are always delivered
+	SampleRates map[string]int `json:"sample_rates,omitempty"`
+}
+
+// CreateWebhookResponse returns the new webhook and its signing secret, shown only once
+type CreateWebhookResponse struct {
+	Webhook WebhookDoc `json:"webhook"`
+	Secret  string     `json:"secret" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+}
+
+// BulkCreateWebhooksRequest holds up to 50 webhook configs
+type BulkCreateWebhooksRequest struct {
+	Webhooks []CreateWebhookRequest `json:"webhooks"`
+}
+
+// BulkCreatedWebhookDoc is a webhook created in bulk with its signing secret, shown only once
+type BulkCreatedWebhookDoc struct {
+	ID     string `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name   string `json:"name" example:"crm"`
+	URL    string `json:"url" example:"https://example.com/webhook"`
+	Secret string `json:"secret" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+}
+
+// BulkCreateWebhooksResponse lists the webhooks created in bulk, in request order
+type BulkCreateWebhooksResponse struct {
+	Webhooks []BulkCreatedWebhookDoc `json:"webhooks"`
+}
+
+// RotateWebhookSecretRequest optionally sets the grace window of the replaced secret
+type RotateWebhookSecretRequest struct {
+	GracePeriod string `json:"grace_period,omitempty" example:"48h"`
+}
+
+// APIKeyDoc exposes non-secret API key metadata
+type APIKeyDoc struct {
+	ID          string   `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name        string   `json:"name" example:"gate"`
+	KeyPrefix   string   `json:"key_prefix" example:"rk_live_a1b2"`
+	Environment string   `json:"environment" example:"live"`
+	Scopes      []string `json:"scopes" example:"faces:export"`
+	IsActive    bool     `json:"is_active" example:"true"`
+	LastUsedAt  string   `json:"last_used_at,omitempty" example:"2024-01-01T00:00:00Z"`
+	CreatedAt   string   `json:"created_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// APIKeysListResponse lists the tenant's API keys and its widget public key
+type APIKeysListResponse struct {
+	PublicKey string      `json:"public_key" example:"pk_live_abc123"`
+	Keys      []APIKeyDoc `json:"keys"`
+}
+
+// CreateAPIKeyRequest creates a labeled secret key for one integration
+type CreateAPIKeyRequest struct {
+	Label       string   `json:"label" example:"gate"`
+	Environment string   `json:"environment,omitempty" example:"live"`
+	Scopes      []string `json:"scopes,omitempty" example:"faces:export"`
+}
+
+// CreateAPIKeyResponse returns the new key's metadata and plaintext, shown only once
+type CreateAPIKeyResponse struct {
+	APIKey APIKeyDoc `json:"api_key"`
+	Key    string    `json:"key" example:"rk_live_a1b2c3d4e5f6"`
+}
+
+// WebhookSchemaResponse describes the payload of one webhook event type
+type WebhookSchemaResponse struct {
+	EventType      string                 `json:"event_type" example:"face.verified"`
+	PayloadVersion string                 `json:"payload_version" example:"v1"`
+	Sample         map[string]interface{} `json:"sample"`
+	Schema         map[string]interface{} `json:"schema"`
+}
+
+// WebhookTestResultDoc is the test delivery result of one webhook
+type WebhookTestResultDoc struct {
+	WebhookID         string `json:"webhook_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name              string `json:"name" example:"Production Alert"`
+	URL               string `json:"url" example:"https://example.com/webhook"`
+	Enabled           bool   `json:"enabled" example:"true"`
+	OK                bool   `json:"ok" example:"false"`
+	StatusCode        int    `json:"status_code,omitempty" example:"200"`
+	LatencyMs         int64  `json:"latency_ms" example:"84"`
+	TLS               bool   `json:"tls" example:"true"`
+	SignatureEnforced *bool  `json:"signature_enforced"`
+	Error             string `json:"error,omitempty" example:"endpoint accepted a delivery with an invalid signature (HTTP 200)"`
+}
+
+// WebhookValidateAllResponse holds one test delivery result per configured webhook
+type WebhookValidateAllResponse struct {
+	Results []WebhookTestResultDoc `json:"results"`
+	Total   int                    `json:"total" example:"3"`
+	Passed  int                    `json:"passed" example:"2"`
+}
+
+// WebhookRotateSecretResponse returns a webhook's new signing secret, shown only once
+type WebhookRotateSecretResponse struct {
+	Secret                  string `json:"secret" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	PreviousSecretExpiresAt string `json:"previous_secret_expires_at" example:"2026-01-16T10:30:00Z"`
+}
+
 // Super Admin Types
 
 // TenantMetricsSummary contains summary metrics for a tenant
@@ -306,7 +832,23 @@ type TenantWithMetrics struct {
 // ListTenantsResponse wraps list of tenants with metrics
 type ListTenantsResponse struct {
 	Data []TenantWithMetrics `json:"data"`
-	Meta map[string]int      `json:"meta"`
+	Meta ListTenantsMeta     `json:"meta"`
+}
+
+// ListTenantsMeta describes the page and the filters applied to the tenant list
+type ListTenantsMeta struct {
+	Total   int                `json:"total" example:"1"`
+	Limit   int                `json:"limit" example:"50"`
+	Offset  int                `json:"offset" example:"0"`
+	Filters ListTenantsFilters `json:"filters"`
+	Sort    string             `json:"sort" example:"error_rate"`
+}
+
+// ListTenantsFilters echoes the tenant list filters, empty or null when not applied
+type ListTenantsFilters struct {
+	Plan     string `json:"plan" example:"pro"`
+	IsActive *bool  `json:"is_active" example:"true"`
+	Search   string `json:"search" example:"acme"`
 }
 
 // TenantDetailedMetricsResponse wraps detailed tenant metrics
@@ -315,11 +857,65 @@ type TenantDetailedMetricsResponse struct {
 	Meta map[string]string    `json:"meta"`
 }
 
+// FeatureUsageDoc is how much a tenant used one optional feature over the range
+type FeatureUsageDoc struct {
+	Used       bool           `json:"used" example:"true"`
+	Total      int            `json:"total" example:"42"`
+	Counters   map[string]int `json:"counters"`
+	ActiveDays int            `json:"active_days" example:"7"`
+	LastUsedOn *string        `json:"last_used_on" example:"2026-10-17"`
+}
+
+// FeatureUsageFeaturesDoc holds the usage of each optional feature
+type FeatureUsageFeaturesDoc struct {
+	Search   FeatureUsageDoc `json:"search"`
+	Liveness FeatureUsageDoc `json:"liveness"`
+	Widget   FeatureUsageDoc `json:"widget"`
+	Webhooks FeatureUsageDoc `json:"webhooks"`
+}
+
+// FeatureUsageReportDoc summarizes a tenant's usage of search, liveness, widget and webhooks
+type FeatureUsageReportDoc struct {
+	TenantID  string                  `json:"tenant_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	StartDate string                  `json:"start_date" example:"2026-09-18"`
+	EndDate   string                  `json:"end_date" example:"2026-10-18"`
+	Features  FeatureUsageFeaturesDoc `json:"features"`
+}
+
+// FeatureUsageResponse is the response of the tenant feature usage endpoint
+type FeatureUsageResponse struct {
+	Data FeatureUsageReportDoc `json:"data"`
+	Meta map[string]string     `json:"meta"`
+}
+
+// ProviderErrorCountDoc is how often a provider failed with one error code
+type ProviderErrorCountDoc struct {
+	Provider   string `json:"provider" example:"rekognition"`
+	ErrorCode  string `json:"error_code" example:"ThrottlingException"`
+	Count      int    `json:"count" example:"42"`
+	LastSeenAt string `json:"last_seen_at" example:"2026-10-17T14:03:00Z"`
+}
+
+// ProviderErrorsMetaDoc echoes the range of a provider errors report
+type ProviderErrorsMetaDoc struct {
+	StartDate string `json:"start_date" example:"2026-09-18"`
+	EndDate   string `json:"end_date" example:"2026-10-18"`
+	Total     int    `json:"total" example:"57"`
+}
+
+// ProviderErrorsResponse is the response of the provider errors endpoint
+type ProviderErrorsResponse struct {
+	Data []ProviderErrorCountDoc `json:"data"`
+	Meta ProviderErrorsMetaDoc   `json:"meta"`
+}
+
 // ServiceHealth represents health of a single service
 type ServiceHealth struct {
 	Status  string `json:"status" example:"healthy"`
 	Latency string `json:"latency" example:"< 1ms"`
 	Message string `json:"message,omitempty"`
+	// Required PostgreSQL extensions (uuid-ossp, vector) and whether each is installed
+	Extensions map[string]bool `json:"extensions,omitempty"`
 }
 
 // ProviderHealth represents health of a face recognition provider
@@ -351,7 +947,7 @@ type MemoryMetrics struct {
 type DBConnMetrics struct {
 	TotalConns int32 `json:"total_conns" example:"10"`
 	IdleConns  int32 `json:"idle_conns" example:"8"`
-	MaxConns   int32 `json:"max_conns" example:"20"`
+	MaxConns   int32 `json:"max_conns" example:"25"`
 }
 
 // SystemMetricsData contains system-wide metrics
@@ -360,6 +956,15 @@ type SystemMetricsData struct {
 	Goroutines        int           `json:"goroutines" example:"50"`
 	DBConnections     DBConnMetrics `json:"db_connections"`
 	RequestsPerSecond float64       `json:"requests_per_second" example:"125.5"`
+	// Present when DB_SLOW_QUERY_THRESHOLD is set
+	SlowQueries *SlowQueryMetrics `json:"slow_queries,omitempty"`
+}
+
+// SlowQueryMetrics counts the queries that took at least the threshold since startup, per query label
+type SlowQueryMetrics struct {
+	ThresholdMs int64            `json:"threshold_ms" example:"500"`
+	Total       int64            `json:"total" example:"3"`
+	ByQuery     map[string]int64 `json:"by_query"`
 }
 
 // SystemMetricsResponse wraps system metrics
@@ -386,6 +991,75 @@ type UpdateQuotaResponse struct {
 	TenantID string `json:"tenant_id" example:"550e8400-e29b-41d4-a716-446655440000"`
 }
 
+// TenantConfigWebhookDoc represents a webhook in an exported tenant config
+type TenantConfigWebhookDoc struct {
+	Name        string            `json:"name" example:"crm"`
+	URL         string            `json:"url" example:"https://crm.example.com/hooks"`
+	Events      []string          `json:"events" example:"face.registered,face.verified"`
+	Enabled     bool              `json:"enabled" example:"true"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	SampleRates map[string]int    `json:"sample_rates,omitempty"`
+}
+
+// TenantConfigDoc represents a portable tenant configuration
+type TenantConfigDoc struct {
+	Version  int                      `json:"version" example:"1"`
+	Plan     string                   `json:"plan" example:"pro"`
+	Settings map[string]interface{}   `json:"settings"`
+	Webhooks []TenantConfigWebhookDoc `json:"webhooks"`
+}
+
+// ImportedWebhookDoc represents a webhook created by a config import
+type ImportedWebhookDoc struct {
+	ID     string `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name   string `json:"name" example:"crm"`
+	URL    string `json:"url" example:"https://crm.example.com/hooks"`
+	Secret string `json:"secret" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+}
+
+// ImpersonationTokenResponse represents a read-only impersonation token
+type ImpersonationTokenResponse struct {
+	Token     string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	TenantID  string `json:"tenant_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ExpiresAt string `json:"expires_at" example:"2026-03-02T10:15:00Z"`
+	ReadOnly  bool   `json:"read_only" example:"true"`
+}
+
+// ResetRateLimitsResponse represents the response of a tenant rate limit reset
+type ResetRateLimitsResponse struct {
+	Message        string `json:"message" example:"rate limits reset successfully"`
+	TenantID       string `json:"tenant_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	BucketsCleared int    `json:"buckets_cleared" example:"3"`
+}
+
+// TenantDeletionResponse represents what was erased when a tenant was deleted
+type TenantDeletionResponse struct {
+	TenantID          string `json:"tenant_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	FacesDeleted      int64  `json:"faces_deleted" example:"1520"`
+	CollectionDeleted bool   `json:"collection_deleted" example:"true"`
+	CollectionError   string `json:"collection_error,omitempty" example:""`
+}
+
+// SetMaintenanceRequest represents a request to toggle a tenant's maintenance mode
+type SetMaintenanceRequest struct {
+	Enabled    bool   `json:"enabled" example:"true"`
+	RetryAfter string `json:"retry_after,omitempty" example:"15m"`
+}
+
+// MaintenanceResponse represents a tenant's maintenance mode after a toggle
+type MaintenanceResponse struct {
+	TenantID   string `json:"tenant_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Enabled    bool   `json:"enabled" example:"true"`
+	RetryAfter string `json:"retry_after" example:"15m0s"`
+}
+
+// ImportTenantConfigResponse represents the response of a config import
+type ImportTenantConfigResponse struct {
+	Message  string               `json:"message" example:"config imported successfully"`
+	TenantID string               `json:"tenant_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Webhooks []ImportedWebhookDoc `json:"webhooks"`
+}
+
 // Face API Types
 
 // FaceResponse represents a face in responses
@@ -396,6 +1070,16 @@ type FaceResponse struct {
 	Metadata     map[string]interface{} `json:"metadata,omitempty"`
 	CreatedAt    string                 `json:"created_at" example:"2024-01-01T00:00:00Z"`
 	UpdatedAt    string                 `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+	ExpiresAt    string                 `json:"expires_at,omitempty" example:"2024-01-08T00:00:00Z"`
+	// EmbeddingHash is only present when the tenant enables embedding_hash
+	EmbeddingHash string `json:"embedding_hash,omitempty" example:"3f79bb7b435b05321651daefd374cdc681dc06faa65e374e38337b88ca046dea"`
+}
+
+// ListFacesResponse represents a page of registered faces
+type ListFacesResponse struct {
+	Faces      []FaceResponse  `json:"faces"`
+	Total      int             `json:"total" example:"50"`
+	Pagination *PaginationMeta `json:"pagination"`
 }
 
 // SearchMatchResponse represents a single match in search results
@@ -410,6 +1094,28 @@ type SearchResponse struct {
 	Matches   []SearchMatchResponse `json:"matches"`
 	SearchID  string                `json:"search_id" example:"550e8400-e29b-41d4-a716-446655440000"`
 	LatencyMs int64                 `json:"latency_ms" example:"45"`
+	Reason    string                `json:"reason,omitempty" example:"empty_collection"`
+	// Set when the top two matches are closer than the tenant's search_margin
+	Ambiguous bool `json:"ambiguous" example:"false"`
+	// Minimum similarity of the matches and its layer: request, security_level, tenant or default
+	ThresholdApplied float64 `json:"threshold_applied" example:"0.85"`
+	ThresholdSource  string  `json:"threshold_source" example:"request"`
+	// Provider experiment arm that searched: primary or alternate; omitted outside experiments
+	ProviderVariant string `json:"provider_variant,omitempty" example:"alternate"`
+}
+
+// ErrorCatalogEntryDoc describes one error code clients can receive
+type ErrorCatalogEntryDoc struct {
+	Code     string            `json:"code" example:"FACE_NOT_FOUND"`
+	Status   int               `json:"status" example:"404"`
+	Message  string            `json:"message" example:"Face not found"`
+	Messages map[string]string `json:"messages"`
+}
+
+// ErrorCatalogResponse lists every error code with its HTTP status and localized messages
+type ErrorCatalogResponse struct {
+	DefaultLocale string                 `json:"default_locale" example:"en"`
+	Errors        []ErrorCatalogEntryDoc `json:"errors"`
 }
 
 // Widget API Types
@@ -429,6 +1135,7 @@ type WidgetSessionResponse struct {
 // WidgetSearchResponse represents the response for widget search (identify)
 type WidgetSearchResponse struct {
 	Identified bool    `json:"identified" example:"true"`
+	Ambiguous  bool    `json:"ambiguous" example:"false"`
 	ExternalID string  `json:"external_id,omitempty" example:"user-123"`
 	Confidence float64 `json:"confidence,omitempty" example:"0.95"`
 }
@@ -444,21 +1151,34 @@ func NewSwagger() *swagno.Swagger {
 	sw := swagno.New(swagno.Config{
 		Title:       "Rekko Face Recognition API",
 		Version:     "v1.0.0",
-		Description: "FRaaS (Facial Recognition as a Service) API for event access control with multi-tenancy support",
+		Description: "FRaaS (Facial Recognition as a Service) API for event access control with multi-tenancy support. Routes under /admin require an API key with the admin:read scope for GET and admin:write for changes, otherwise they respond 403 FORBIDDEN.",
 		Host:        "localhost:3000",
 		Path:        "/v1",
 	})
 
 	endpoints := []*endpoint.EndPoint{
+		// GET /v1/errors - Error Catalog
+		endpoint.New(
+			endpoint.GET,
+			"/errors",
+			endpoint.WithTags("Errors"),
+			endpoint.WithSummary("List error codes"),
+			endpoint.WithDescription("Returns every error code the API can return, with its HTTP status and message in each supported locale (en, pt-BR), so SDKs can generate typed errors. Some responses replace the message with request details; the code and status always match the catalog. No authentication required."),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(ErrorCatalogResponse{}, "200", "Error catalog"),
+			}),
+		),
+
 		// Faces endpoints
 
-		// POST /v1/faces/register - Register Face
+		// POST /v1/faces - Register Face
 		endpoint.New(
 			endpoint.POST,
-			"/faces/register",
+			"/faces",
 			endpoint.WithTags("Faces"),
 			endpoint.WithSummary("Register a new face"),
-			endpoint.WithDescription("Registers a new face for the given external_id. If the external_id already exists, updates the face embedding."),
+			endpoint.WithDescription("Registers a new face for the given external_id. If the external_id already exists, updates the face embedding. Accepts an optional metadata form field with a JSON object and an optional ttl form field (Go duration, e.g. 168h, at most 8760h) after which the face is deleted and a face.expired webhook sent; without it the tenant's face_ttl applies. NO_FACE_DETECTED may include reasons: face_not_frontal, too_dark, too_blurry, face_unclear, face_too_small, low_face_quality."),
 			endpoint.WithConsume([]mime.MIME{mime.MIME("multipart/form-data")}),
 			endpoint.WithProduce([]mime.MIME{mime.JSON}),
 			endpoint.WithSuccessfulReturns([]response.Response{
@@ -467,9 +1187,12 @@ func NewSwagger() *swagno.Swagger {
 			endpoint.WithErrors([]response.Response{
 				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid request"}, "400", "Bad Request"),
 				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
-				response.New(ErrorResponse{Code: "NO_FACE_DETECTED", Message: "No face detected in image"}, "422", "Unprocessable Entity"),
-				response.New(ErrorResponse{Code: "MULTIPLE_FACES", Message: "Multiple faces detected"}, "422", "Unprocessable Entity"),
+				response.New(ErrorResponse{Code: "METADATA_TOO_LARGE", Message: "Face metadata exceeds the maximum allowed size"}, "413", "Payload Too Large"),
+				response.New(ErrorResponse{Code: "NO_FACE_DETECTED", Message: "No face detected in image", Reasons: []string{"too_dark"}}, "422", "Unprocessable Entity"),
+				response.New(MultipleFacesErrorResponse{Code: "MULTIPLE_FACES", Message: "3 faces detected, only 1 allowed", FacesDetected: 3, MaxFaces: 1}, "422", "Unprocessable Entity"),
+				response.New(ErrorResponse{Code: "FACE_OUT_OF_FRAME", Message: "Face is cut off at the edge of the image"}, "422", "Unprocessable Entity"),
 				response.New(ErrorResponse{Code: "LIVENESS_FAILED", Message: "Liveness check failed"}, "422", "Unprocessable Entity"),
+				response.New(ErrorResponse{Code: "INVALID_EMBEDDING", Message: "Embedding must contain 512 finite values with a magnitude within the allowed range"}, "422", "Unprocessable Entity"),
 				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
 			}),
 			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
@@ -481,7 +1204,7 @@ func NewSwagger() *swagno.Swagger {
 			"/faces/verify",
 			endpoint.WithTags("Faces"),
 			endpoint.WithSummary("Verify a face against a registered identity"),
-			endpoint.WithDescription("Performs 1:1 face verification comparing the provided image against the stored face for the given external_id"),
+			endpoint.WithDescription("Performs 1:1 face verification comparing the provided image against the stored face for the given external_id. The tenant verify_policy (match_only, match_and_liveness, match_or_liveness) decides how match_passed and liveness_passed combine into verified; liveness_passed is omitted under match_only. Unverified responses carry fail_reason (match_below_threshold or liveness_failed); tenants with record_rejected_verifications also record attempts rejected with no_face, multiple_faces or anti_passback. With the tenant's anti_passback window set (e.g. {\"anti_passback\": {\"window\": \"10m\"}}), a verify from a different gate (client IP) than the external_id's last successful verification within the window is rejected with ANTI_PASSBACK_VIOLATION. With the tenant's verify_hysteresis set (e.g. {\"verify_hysteresis\": {\"margin\": 0.03, \"window\": \"10m\"}}), a confidence below the threshold by at most margin still passes the match when the external_id passed a verification at or above the threshold within the window; passes allowed this way do not extend it."),
 			endpoint.WithConsume([]mime.MIME{mime.MIME("multipart/form-data")}),
 			endpoint.WithProduce([]mime.MIME{mime.JSON}),
 			endpoint.WithSuccessfulReturns([]response.Response{
@@ -490,129 +1213,473 @@ func NewSwagger() *swagno.Swagger {
 			endpoint.WithErrors([]response.Response{
 				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid request"}, "400", "Bad Request"),
 				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "ANTI_PASSBACK_VIOLATION", Message: "Already verified at another gate within the anti-passback window"}, "403", "Forbidden"),
 				response.New(ErrorResponse{Code: "FACE_NOT_FOUND", Message: "Face not found for external_id"}, "404", "Not Found"),
-				response.New(ErrorResponse{Code: "NO_FACE_DETECTED", Message: "No face detected in image"}, "422", "Unprocessable Entity"),
-				response.New(ErrorResponse{Code: "MULTIPLE_FACES", Message: "Multiple faces detected"}, "422", "Unprocessable Entity"),
+				response.New(ErrorResponse{Code: "VERIFY_NOT_SUPPORTED_WITHOUT_SOURCE", Message: "Face has no stored embedding and no source image to verify against, the provider does not expose embeddings"}, "409", "Conflict"),
+				response.New(ErrorResponse{Code: "NO_FACE_DETECTED", Message: "No face detected in image", Reasons: []string{"too_dark"}}, "422", "Unprocessable Entity"),
+				response.New(MultipleFacesErrorResponse{Code: "MULTIPLE_FACES", Message: "3 faces detected, only 1 allowed", FacesDetected: 3, MaxFaces: 1}, "422", "Unprocessable Entity"),
+				response.New(ErrorResponse{Code: "FACE_OUT_OF_FRAME", Message: "Face is cut off at the edge of the image"}, "422", "Unprocessable Entity"),
 				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
 			}),
 			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
 		),
 
-		// POST /v1/faces/search - Search Faces (1:N)
+		// POST /v1/faces/verify-document - Verify against an ID document (1:1)
 		endpoint.New(
 			endpoint.POST,
-			"/faces/search",
+			"/faces/verify-document",
 			endpoint.WithTags("Faces"),
-			endpoint.WithSummary("Search for matching faces"),
-			endpoint.WithDescription("Performs 1:N face search to find matching identities in the tenant's database"),
+			endpoint.WithSummary("Verify a live capture against an ID document photo"),
+			endpoint.WithDescription("Performs 1:1 verification of the live capture in the image field against the face in the document field, e.g. the photo of an ID card, without registering either face or recording a verification. The live image goes through the same face checks as /faces/verify and, when the tenant's verify_policy uses liveness, must pass liveness or the request is rejected with LIVENESS_FAILED; the largest face of the document is compared. verified is similarity >= the tenant's verification_threshold. A document without a face is rejected with NO_FACE_IN_DOCUMENT."),
 			endpoint.WithConsume([]mime.MIME{mime.MIME("multipart/form-data")}),
 			endpoint.WithProduce([]mime.MIME{mime.JSON}),
-			endpoint.WithParams(
-				parameter.StrParam("threshold", parameter.Query, parameter.WithDescription("Minimum similarity threshold (0-1, default: tenant setting)")),
-				parameter.IntParam("max_results", parameter.Query, parameter.WithDescription("Maximum number of results (1-50, default: tenant setting)")),
-			),
 			endpoint.WithSuccessfulReturns([]response.Response{
-				response.New(SearchResponse{}, "200", "Search completed successfully"),
+				response.New(VerifyDocumentResponse{}, "200", "Verification completed successfully"),
 			}),
 			endpoint.WithErrors([]response.Response{
 				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid request"}, "400", "Bad Request"),
 				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
-				response.New(ErrorResponse{Code: "SEARCH_NOT_ENABLED", Message: "Search not enabled for tenant"}, "403", "Forbidden"),
-				response.New(ErrorResponse{Code: "INVALID_THRESHOLD", Message: "Threshold must be between 0 and 1"}, "422", "Unprocessable Entity"),
-				response.New(ErrorResponse{Code: "INVALID_MAX_RESULTS", Message: "Max results must be between 1 and 50"}, "422", "Unprocessable Entity"),
-				response.New(ErrorResponse{Code: "NO_FACE_DETECTED", Message: "No face detected in image"}, "422", "Unprocessable Entity"),
-				response.New(ErrorResponse{Code: "SEARCH_RATE_LIMIT_EXCEEDED", Message: "Search rate limit exceeded"}, "429", "Too Many Requests"),
+				response.New(ErrorResponse{Code: "NO_FACE_DETECTED", Message: "No face detected in image", Reasons: []string{"too_dark"}}, "422", "Unprocessable Entity"),
+				response.New(ErrorResponse{Code: "NO_FACE_IN_DOCUMENT", Message: "No face detected in the document image"}, "422", "Unprocessable Entity"),
+				response.New(ErrorResponse{Code: "LIVENESS_FAILED", Message: "Liveness check failed, possible spoofing attempt"}, "422", "Unprocessable Entity"),
+				response.New(MultipleFacesErrorResponse{Code: "MULTIPLE_FACES", Message: "3 faces detected, only 1 allowed", FacesDetected: 3, MaxFaces: 1}, "422", "Unprocessable Entity"),
 				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
 			}),
 			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
 		),
 
-		// GET /v1/faces/:external_id - Get Face
+		// POST /v1/faces/exists - Batch Existence Check
 		endpoint.New(
-			endpoint.GET,
-			"/faces/{external_id}",
+			endpoint.POST,
+			"/faces/exists",
 			endpoint.WithTags("Faces"),
-			endpoint.WithSummary("Get a registered face"),
-			endpoint.WithDescription("Retrieves face information for the given external_id"),
+			endpoint.WithSummary("Check which external_ids have a registered face"),
+			endpoint.WithDescription("Accepts a JSON body with up to 100 external_ids and returns, for each one, whether a face is registered and when. No biometric data is returned."),
+			endpoint.WithConsume([]mime.MIME{mime.JSON}),
 			endpoint.WithProduce([]mime.MIME{mime.JSON}),
-			endpoint.WithParams(
-				parameter.StrParam("external_id", parameter.Path, parameter.WithDescription("External user identifier")),
-			),
 			endpoint.WithSuccessfulReturns([]response.Response{
-				response.New(FaceResponse{}, "200", "Face retrieved successfully"),
+				response.New(FaceExistsResponse{}, "200", "Existence check completed successfully"),
 			}),
 			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid request"}, "400", "Bad Request"),
 				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
-				response.New(ErrorResponse{Code: "FACE_NOT_FOUND", Message: "Face not found"}, "404", "Not Found"),
 				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
 			}),
 			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
 		),
 
-		// DELETE /v1/faces/:external_id - Delete Face
+		// POST /v1/faces/metadata/bulk - Bulk Metadata Patch
 		endpoint.New(
-			endpoint.DELETE,
-			"/faces/{external_id}",
+			endpoint.POST,
+			"/faces/metadata/bulk",
 			endpoint.WithTags("Faces"),
-			endpoint.WithSummary("Delete a registered face"),
-			endpoint.WithDescription("Deletes the face for the given external_id (LGPD compliance)"),
+			endpoint.WithSummary("Bulk tag faces with metadata"),
+			endpoint.WithDescription("Accepts a JSON body with up to 500 external_ids and a metadata object, merged into every matching face in a single update. Top-level keys overwrite existing ones. Returns how many faces were updated and which external_ids were not found."),
+			endpoint.WithConsume([]mime.MIME{mime.JSON}),
 			endpoint.WithProduce([]mime.MIME{mime.JSON}),
-			endpoint.WithParams(
-				parameter.StrParam("external_id", parameter.Path, parameter.WithDescription("External user identifier")),
-			),
 			endpoint.WithSuccessfulReturns([]response.Response{
-				response.New(EmptyResponse{}, "204", "Face deleted successfully"),
+				response.New(BulkMetadataResponse{}, "200", "Metadata applied successfully"),
 			}),
 			endpoint.WithErrors([]response.Response{
 				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
-				response.New(ErrorResponse{Code: "FACE_NOT_FOUND", Message: "Face not found"}, "404", "Not Found"),
+				response.New(ErrorResponse{Code: "METADATA_TOO_LARGE", Message: "Face metadata exceeds the maximum allowed size"}, "413", "Payload Too Large"),
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid request"}, "422", "Unprocessable Entity"),
 				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
 			}),
 			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
 		),
 
-		// POST /v1/faces/liveness - Check Liveness
+		// POST /v1/faces/search - Search Faces (1:N)
 		endpoint.New(
 			endpoint.POST,
-			"/faces/liveness",
+			"/faces/search",
 			endpoint.WithTags("Faces"),
-			endpoint.WithSummary("Check if image contains a live person"),
-			endpoint.WithDescription("Performs passive liveness detection on the provided image to verify it's from a live person"),
+			endpoint.WithSummary("Search for matching faces"),
+			endpoint.WithDescription("Performs 1:N face search to find matching identities in the tenant's database. Match metadata only includes the keys listed in the tenant's search_metadata_keys setting and is omitted when none are"),
 			endpoint.WithConsume([]mime.MIME{mime.MIME("multipart/form-data")}),
 			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("threshold", parameter.Query, parameter.WithDescription("Minimum similarity threshold (0-1, default: tenant setting). The response reports the applied value in threshold_applied and its layer in threshold_source")),
+				parameter.IntParam("max_results", parameter.Query, parameter.WithDescription("Maximum number of results (1-50, default: tenant setting). Tenants with clamp_max_results get larger values clamped to 50 with an X-Rekko-Warning header instead of a 422")),
+			),
 			endpoint.WithSuccessfulReturns([]response.Response{
-				response.New(LivenessCheckResponse{}, "200", "Liveness check completed successfully"),
+				response.New(SearchResponse{}, "200", "Search completed successfully"),
 			}),
 			endpoint.WithErrors([]response.Response{
-				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid image file"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid request"}, "400", "Bad Request"),
 				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "SEARCH_NOT_ENABLED", Message: "Search not enabled for tenant"}, "403", "Forbidden"),
+				response.New(ErrorResponse{Code: "INVALID_THRESHOLD", Message: "Threshold must be between 0 and 1"}, "422", "Unprocessable Entity"),
+				response.New(ErrorResponse{Code: "INVALID_MAX_RESULTS", Message: "Max results must be between 1 and 50"}, "422", "Unprocessable Entity"),
 				response.New(ErrorResponse{Code: "NO_FACE_DETECTED", Message: "No face detected in image"}, "422", "Unprocessable Entity"),
-				response.New(ErrorResponse{Code: "MULTIPLE_FACES", Message: "Multiple faces detected, only one allowed"}, "422", "Unprocessable Entity"),
+				response.New(RateLimitErrorResponse{Code: "SEARCH_RATE_LIMIT_EXCEEDED", Message: "Search rate limit exceeded, try again later"}, "429", "Too Many Requests (Retry-After and X-RateLimit-* headers give the exact reset)"),
+				response.New(ErrorResponse{Code: "AUDIT_WRITE_FAILED", Message: "Search audit could not be recorded, the result was withheld; retry shortly"}, "503", "Service Unavailable (tenants with search_audit_mode sync)"),
 				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
 			}),
 			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
 		),
 
-		// GET /v1/admin/metrics/quality - Quality Metrics
+		// POST /v1/faces/search-by-embedding - Search Faces by Embedding (1:N)
 		endpoint.New(
-			endpoint.GET,
-			"/admin/metrics/quality",
-			endpoint.WithTags("Admin Metrics - Quality"),
-			endpoint.WithSummary("Get quality score metrics"),
-			endpoint.WithDescription("Returns quality score metrics for face registrations"),
+			endpoint.POST,
+			"/faces/search-by-embedding",
+			endpoint.WithTags("Faces"),
+			endpoint.WithSummary("Search for matching faces using an embedding"),
+			endpoint.WithDescription("Performs 1:N search with a 512-dimension embedding extracted on-device, in a JSON body with embedding, threshold and max_results. Skips the provider and liveness checks, so the tenant must enable search_by_embedding_enabled."),
+			endpoint.WithConsume([]mime.MIME{mime.JSON}),
 			endpoint.WithProduce([]mime.MIME{mime.JSON}),
-			endpoint.WithParams(
-				parameter.StrParam("start_date", parameter.Query, parameter.WithDescription("Start date (YYYY-MM-DD)")),
-				parameter.StrParam("end_date", parameter.Query, parameter.WithDescription("End date (YYYY-MM-DD)")),
-				parameter.StrParam("interval", parameter.Query, parameter.WithDescription("Aggregation interval: hour, day, week, month (default: day)")),
-				parameter.IntParam("limit", parameter.Query, parameter.WithDescription("Maximum number of timeline points (default: 100, max: 1000)")),
-				parameter.IntParam("offset", parameter.Query, parameter.WithDescription("Offset for timeline pagination (default: 0)")),
-			),
 			endpoint.WithSuccessfulReturns([]response.Response{
-				response.New(QualityMetricsResponse{}, "200", "Metrics retrieved successfully"),
+				response.New(SearchResponse{}, "200", "Search completed successfully"),
 			}),
 			endpoint.WithErrors([]response.Response{
-				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid date format"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "SEARCH_BY_EMBEDDING_NOT_ENABLED", Message: "Search by embedding is not enabled for this tenant"}, "403", "Forbidden"),
+				response.New(ErrorResponse{Code: "INVALID_EMBEDDING", Message: "Embedding must contain 512 finite values with a magnitude within the allowed range"}, "422", "Unprocessable Entity"),
+				response.New(ErrorResponse{Code: "INVALID_THRESHOLD", Message: "Threshold must be between 0 and 1"}, "422", "Unprocessable Entity"),
+				response.New(RateLimitErrorResponse{Code: "SEARCH_RATE_LIMIT_EXCEEDED", Message: "Search rate limit exceeded, try again later"}, "429", "Too Many Requests (Retry-After and X-RateLimit-* headers give the exact reset)"),
+				response.New(ErrorResponse{Code: "AUDIT_WRITE_FAILED", Message: "Search audit could not be recorded, the result was withheld; retry shortly"}, "503", "Service Unavailable (tenants with search_audit_mode sync)"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/faces - List Faces
+		endpoint.New(
+			endpoint.GET,
+			"/faces",
+			endpoint.WithTags("Faces"),
+			endpoint.WithSummary("List registered faces"),
+			endpoint.WithDescription("Lists the tenant's faces, most recent first, optionally restricted to those registered in [registered_from, registered_to), e.g. to reconcile enrollment against ticket sales. total is the size of the page; pagination.total counts every face in the range."),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("registered_from", parameter.Query, parameter.WithDescription("Only faces registered at or after this RFC3339 timestamp")),
+				parameter.StrParam("registered_to", parameter.Query, parameter.WithDescription("Only faces registered before this RFC3339 timestamp")),
+				parameter.IntParam("limit", parameter.Query, parameter.WithDescription("Maximum number of results (default: 50, max: 100)")),
+				parameter.IntParam("offset", parameter.Query, parameter.WithDescription("Offset for pagination (default: 0)")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(ListFacesResponse{}, "200", "Faces listed successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid request"}, "422", "Unprocessable Entity"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/faces/:external_id - Get Face
+		endpoint.New(
+			endpoint.GET,
+			"/faces/{external_id}",
+			endpoint.WithTags("Faces"),
+			endpoint.WithSummary("Get a registered face"),
+			endpoint.WithDescription("Retrieves face information for the given external_id"),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("external_id", parameter.Path, parameter.WithDescription("External user identifier")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(FaceResponse{}, "200", "Face retrieved successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "FACE_NOT_FOUND", Message: "Face not found"}, "404", "Not Found"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/faces/:external_id/recently-verified - Recent Verification
+		endpoint.New(
+			endpoint.GET,
+			"/faces/{external_id}/recently-verified",
+			endpoint.WithTags("Faces"),
+			endpoint.WithSummary("Check for a recent verification"),
+			endpoint.WithDescription("Reports whether the external_id passed a verification within the window and when it last did, e.g. to re-admit someone at a gate without a new capture. The window is capped by MAX_VERIFICATION_AGE (default 24h)."),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("external_id", parameter.Path, parameter.WithDescription("External user identifier")),
+				parameter.StrParam("within", parameter.Query, parameter.WithDescription("Window as a duration, e.g. 15m or 1h (default: 15m)")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(RecentVerificationResponse{}, "200", "Recent verification status"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid request"}, "422", "Unprocessable Entity"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// DELETE /v1/faces/:external_id - Delete Face
+		endpoint.New(
+			endpoint.DELETE,
+			"/faces/{external_id}",
+			endpoint.WithTags("Faces"),
+			endpoint.WithSummary("Delete a registered face"),
+			endpoint.WithDescription("Deletes the face for the given external_id (LGPD compliance). With idempotent=true a missing face returns 204 instead of 404, so erasure retries are safe."),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("external_id", parameter.Path, parameter.WithDescription("External user identifier")),
+				parameter.StrParam("idempotent", parameter.Query, parameter.WithDescription("Return 204 when no face exists (default: false)")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(EmptyResponse{}, "204", "Face deleted successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "FACE_NOT_FOUND", Message: "Face not found"}, "404", "Not Found"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// POST /v1/faces/liveness - Check Liveness
+		endpoint.New(
+			endpoint.POST,
+			"/faces/liveness",
+			endpoint.WithTags("Faces"),
+			endpoint.WithSummary("Check if image contains a live person"),
+			endpoint.WithDescription("Performs passive liveness detection on the provided image to verify it's from a live person"),
+			endpoint.WithConsume([]mime.MIME{mime.MIME("multipart/form-data")}),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(LivenessCheckResponse{}, "200", "Liveness check completed successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid image file"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "NO_FACE_DETECTED", Message: "No face detected in image"}, "422", "Unprocessable Entity"),
+				response.New(ErrorResponse{Code: "MULTIPLE_FACES", Message: "Multiple faces detected, only one allowed"}, "422", "Unprocessable Entity"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// POST /v1/faces/count - Anonymized crowd count
+		endpoint.New(
+			endpoint.POST,
+			"/faces/count",
+			endpoint.WithTags("Faces"),
+			endpoint.WithSummary("Count faces in an image"),
+			endpoint.WithDescription("Returns only the number of faces detected in the image, e.g. for venue capacity management. No embedding is computed and nothing is stored or audited"),
+			endpoint.WithConsume([]mime.MIME{mime.MIME("multipart/form-data")}),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(FaceCountResponse{}, "200", "Faces counted successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid image file"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "DATA_RESIDENCY_VIOLATION", Message: "Operation would process biometric data outside the tenant's data region"}, "403", "Forbidden"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/usage - Current Usage
+		endpoint.New(
+			endpoint.GET,
+			"/usage",
+			endpoint.WithTags("Usage"),
+			endpoint.WithSummary("Get usage and billing"),
+			endpoint.WithDescription("Returns the tenant's registrations, verifications and liveness checks against the plan quota, with the billing summary and quota alerts. Defaults to the current month."),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("period", parameter.Query, parameter.WithDescription("Billing period (YYYY-MM, default: current month)")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(UsageResponse{}, "200", "Usage retrieved successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "HTTP_ERROR", Message: "Invalid period format, use YYYY-MM"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "HTTP_ERROR", Message: "Failed to retrieve usage data"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/admin/metrics/faces - Faces Metrics
+		endpoint.New(
+			endpoint.GET,
+			"/admin/metrics/faces",
+			endpoint.WithTags("Admin Metrics - Usage"),
+			endpoint.WithSummary("Get face registration metrics"),
+			endpoint.WithDescription("Returns total and active faces with a registration timeline"),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("start_date", parameter.Query, parameter.WithDescription("Start date (YYYY-MM-DD, default: 30 days ago)")),
+				parameter.StrParam("end_date", parameter.Query, parameter.WithDescription("End date (YYYY-MM-DD, default: today)")),
+				parameter.StrParam("interval", parameter.Query, parameter.WithDescription("Aggregation interval: hour, day, week, month (default: day)")),
+				parameter.IntParam("limit", parameter.Query, parameter.WithDescription("Maximum number of timeline points (default: 100, max: 1000)")),
+				parameter.IntParam("offset", parameter.Query, parameter.WithDescription("Offset for timeline pagination (default: 0)")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(FacesMetricsResponse{}, "200", "Metrics retrieved successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid date format"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/admin/metrics/operations - Operations Metrics
+		endpoint.New(
+			endpoint.GET,
+			"/admin/metrics/operations",
+			endpoint.WithTags("Admin Metrics - Usage"),
+			endpoint.WithSummary("Get operation metrics"),
+			endpoint.WithDescription("Returns operations by type and by fail reason with a success/failure timeline, and by deciding provider with group_by=provider"),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("start_date", parameter.Query, parameter.WithDescription("Start date (YYYY-MM-DD, default: 30 days ago)")),
+				parameter.StrParam("end_date", parameter.Query, parameter.WithDescription("End date (YYYY-MM-DD, default: today)")),
+				parameter.StrParam("interval", parameter.Query, parameter.WithDescription("Aggregation interval: hour, day, week, month (default: day)")),
+				parameter.IntParam("limit", parameter.Query, parameter.WithDescription("Maximum number of timeline points (default: 100, max: 1000)")),
+				parameter.IntParam("offset", parameter.Query, parameter.WithDescription("Offset for timeline pagination (default: 0)")),
+				parameter.StrParam("group_by", parameter.Query, parameter.WithDescription("Break verifications down by the deciding provider: provider (optional, adds by_provider)")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(OperationsMetricsResponse{}, "200", "Metrics retrieved successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid date format"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/admin/metrics/requests - Requests Metrics
+		endpoint.New(
+			endpoint.GET,
+			"/admin/metrics/requests",
+			endpoint.WithTags("Admin Metrics - Usage"),
+			endpoint.WithSummary("Get request metrics"),
+			endpoint.WithDescription("Returns HTTP requests by endpoint with a timeline"),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("start_date", parameter.Query, parameter.WithDescription("Start date (YYYY-MM-DD, default: 30 days ago)")),
+				parameter.StrParam("end_date", parameter.Query, parameter.WithDescription("End date (YYYY-MM-DD, default: today)")),
+				parameter.StrParam("interval", parameter.Query, parameter.WithDescription("Aggregation interval: hour, day, week, month (default: day)")),
+				parameter.IntParam("limit", parameter.Query, parameter.WithDescription("Maximum number of timeline points (default: 100, max: 1000)")),
+				parameter.IntParam("offset", parameter.Query, parameter.WithDescription("Offset for timeline pagination (default: 0)")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(RequestsMetricsResponse{}, "200", "Metrics retrieved successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid date format"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/admin/metrics/latency - Latency Metrics
+		endpoint.New(
+			endpoint.GET,
+			"/admin/metrics/latency",
+			endpoint.WithTags("Admin Metrics - Performance"),
+			endpoint.WithSummary("Get latency metrics"),
+			endpoint.WithDescription("Returns average and p50/p95/p99 latency with a timeline"),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("start_date", parameter.Query, parameter.WithDescription("Start date (YYYY-MM-DD, default: 30 days ago)")),
+				parameter.StrParam("end_date", parameter.Query, parameter.WithDescription("End date (YYYY-MM-DD, default: today)")),
+				parameter.StrParam("interval", parameter.Query, parameter.WithDescription("Aggregation interval: hour, day, week, month (default: day)")),
+				parameter.IntParam("limit", parameter.Query, parameter.WithDescription("Maximum number of timeline points (default: 100, max: 1000)")),
+				parameter.IntParam("offset", parameter.Query, parameter.WithDescription("Offset for timeline pagination (default: 0)")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(LatencyMetricsResponse{}, "200", "Metrics retrieved successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid date format"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/admin/metrics/throughput - Throughput Metrics
+		endpoint.New(
+			endpoint.GET,
+			"/admin/metrics/throughput",
+			endpoint.WithTags("Admin Metrics - Performance"),
+			endpoint.WithSummary("Get throughput metrics"),
+			endpoint.WithDescription("Returns total, average and peak requests per hour with a timeline"),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("start_date", parameter.Query, parameter.WithDescription("Start date (YYYY-MM-DD, default: 30 days ago)")),
+				parameter.StrParam("end_date", parameter.Query, parameter.WithDescription("End date (YYYY-MM-DD, default: today)")),
+				parameter.StrParam("interval", parameter.Query, parameter.WithDescription("Aggregation interval: hour, day, week, month (default: day)")),
+				parameter.IntParam("limit", parameter.Query, parameter.WithDescription("Maximum number of timeline points (default: 100, max: 1000)")),
+				parameter.IntParam("offset", parameter.Query, parameter.WithDescription("Offset for timeline pagination (default: 0)")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(ThroughputMetricsResponse{}, "200", "Metrics retrieved successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid date format"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/admin/metrics/errors - Error Metrics
+		endpoint.New(
+			endpoint.GET,
+			"/admin/metrics/errors",
+			endpoint.WithTags("Admin Metrics - Performance"),
+			endpoint.WithSummary("Get error rate metrics"),
+			endpoint.WithDescription("Returns the error count and rate by type with a timeline, and by deciding provider with group_by=provider"),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("start_date", parameter.Query, parameter.WithDescription("Start date (YYYY-MM-DD, default: 30 days ago)")),
+				parameter.StrParam("end_date", parameter.Query, parameter.WithDescription("End date (YYYY-MM-DD, default: today)")),
+				parameter.StrParam("interval", parameter.Query, parameter.WithDescription("Aggregation interval: hour, day, week, month (default: day)")),
+				parameter.IntParam("limit", parameter.Query, parameter.WithDescription("Maximum number of timeline points (default: 100, max: 1000)")),
+				parameter.IntParam("offset", parameter.Query, parameter.WithDescription("Offset for timeline pagination (default: 0)")),
+				parameter.StrParam("group_by", parameter.Query, parameter.WithDescription("Break verifications down by the deciding provider: provider (optional, adds by_provider)")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(ErrorMetricsResponse{}, "200", "Metrics retrieved successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid date format"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/admin/metrics/quality - Quality Metrics
+		endpoint.New(
+			endpoint.GET,
+			"/admin/metrics/quality",
+			endpoint.WithTags("Admin Metrics - Quality"),
+			endpoint.WithSummary("Get quality score metrics"),
+			endpoint.WithDescription("Returns quality score metrics for face registrations"),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("start_date", parameter.Query, parameter.WithDescription("Start date (YYYY-MM-DD)")),
+				parameter.StrParam("end_date", parameter.Query, parameter.WithDescription("End date (YYYY-MM-DD)")),
+				parameter.StrParam("interval", parameter.Query, parameter.WithDescription("Aggregation interval: hour, day, week, month (default: day)")),
+				parameter.IntParam("limit", parameter.Query, parameter.WithDescription("Maximum number of timeline points (default: 100, max: 1000)")),
+				parameter.IntParam("offset", parameter.Query, parameter.WithDescription("Offset for timeline pagination (default: 0)")),
+				parameter.BoolParam("exclude_test", parameter.Query, parameter.WithDescription("Exclude data created with test-environment API keys (default: false)")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(QualityMetricsResponse{}, "200", "Metrics retrieved successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid date format"}, "400", "Bad Request"),
 				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
 				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
 			}),
@@ -622,51 +1689,620 @@ func NewSwagger() *swagno.Swagger {
 		// GET /v1/admin/metrics/confidence - Confidence Metrics
 		endpoint.New(
 			endpoint.GET,
-			"/admin/metrics/confidence",
-			endpoint.WithTags("Admin Metrics - Quality"),
-			endpoint.WithSummary("Get confidence score metrics"),
-			endpoint.WithDescription("Returns confidence score metrics for verifications"),
+			"/admin/metrics/confidence",
+			endpoint.WithTags("Admin Metrics - Quality"),
+			endpoint.WithSummary("Get confidence score metrics"),
+			endpoint.WithDescription("Returns confidence score metrics for verifications"),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("start_date", parameter.Query, parameter.WithDescription("Start date (YYYY-MM-DD)")),
+				parameter.StrParam("end_date", parameter.Query, parameter.WithDescription("End date (YYYY-MM-DD)")),
+				parameter.StrParam("interval", parameter.Query, parameter.WithDescription("Aggregation interval: hour, day, week, month (default: day)")),
+				parameter.IntParam("limit", parameter.Query, parameter.WithDescription("Maximum number of timeline points (default: 100, max: 1000)")),
+				parameter.IntParam("offset", parameter.Query, parameter.WithDescription("Offset for timeline pagination (default: 0)")),
+				parameter.BoolParam("exclude_test", parameter.Query, parameter.WithDescription("Exclude data created with test-environment API keys (default: false)")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(ConfidenceMetricsResponse{}, "200", "Metrics retrieved successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid date format"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/admin/metrics/matches - Match Metrics
+		endpoint.New(
+			endpoint.GET,
+			"/admin/metrics/matches",
+			endpoint.WithTags("Admin Metrics - Quality"),
+			endpoint.WithSummary("Get matching statistics"),
+			endpoint.WithDescription("Returns matching statistics for verifications"),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("start_date", parameter.Query, parameter.WithDescription("Start date (YYYY-MM-DD)")),
+				parameter.StrParam("end_date", parameter.Query, parameter.WithDescription("End date (YYYY-MM-DD)")),
+				parameter.StrParam("interval", parameter.Query, parameter.WithDescription("Aggregation interval: hour, day, week, month (default: day)")),
+				parameter.IntParam("limit", parameter.Query, parameter.WithDescription("Maximum number of timeline points (default: 100, max: 1000)")),
+				parameter.IntParam("offset", parameter.Query, parameter.WithDescription("Offset for timeline pagination (default: 0)")),
+				parameter.BoolParam("exclude_test", parameter.Query, parameter.WithDescription("Exclude data created with test-environment API keys (default: false)")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(MatchMetricsResponse{}, "200", "Metrics retrieved successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid date format"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/admin/metrics/liveness - Liveness Metrics
+		endpoint.New(
+			endpoint.GET,
+			"/admin/metrics/liveness",
+			endpoint.WithTags("Admin Metrics - Quality"),
+			endpoint.WithSummary("Get liveness check statistics"),
+			endpoint.WithDescription("Returns pass/fail counts and average liveness score of the liveness checks run by verifications. Verifications whose policy skipped liveness are not counted"),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("start_date", parameter.Query, parameter.WithDescription("Start date (YYYY-MM-DD)")),
+				parameter.StrParam("end_date", parameter.Query, parameter.WithDescription("End date (YYYY-MM-DD)")),
+				parameter.StrParam("interval", parameter.Query, parameter.WithDescription("Aggregation interval: hour, day, week, month (default: day)")),
+				parameter.IntParam("limit", parameter.Query, parameter.WithDescription("Maximum number of timeline points (default: 100, max: 1000)")),
+				parameter.IntParam("offset", parameter.Query, parameter.WithDescription("Offset for timeline pagination (default: 0)")),
+				parameter.BoolParam("exclude_test", parameter.Query, parameter.WithDescription("Exclude data created with test-environment API keys (default: false)")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(LivenessMetricsResponse{}, "200", "Metrics retrieved successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid date format"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/admin/metrics/by-gate - Verification Metrics per Gate
+		endpoint.New(
+			endpoint.GET,
+			"/admin/metrics/by-gate",
+			endpoint.WithTags("Admin Metrics - Quality"),
+			endpoint.WithSummary("Get verification statistics per gate"),
+			endpoint.WithDescription("Groups verifications by the client IP that requested them, returning counts, success rate and average latency per gate. Verifications recorded without a client IP are reported under the \"unknown\" gate"),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("start_date", parameter.Query, parameter.WithDescription("Start date (YYYY-MM-DD)")),
+				parameter.StrParam("end_date", parameter.Query, parameter.WithDescription("End date (YYYY-MM-DD)")),
+				parameter.BoolParam("exclude_test", parameter.Query, parameter.WithDescription("Exclude data created with test-environment API keys (default: false)")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(GateMetricsResponse{}, "200", "Metrics retrieved successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid date format"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/admin/metrics/top-identities - Most Verified Identities
+		endpoint.New(
+			endpoint.GET,
+			"/admin/metrics/top-identities",
+			endpoint.WithTags("Admin Metrics - Usage"),
+			endpoint.WithSummary("Get the most verified identities"),
+			endpoint.WithDescription("Ranks external_ids by successful verifications in the period, with the count and the last verification. Face metadata is returned only for the keys in the tenant's search_metadata_keys"),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("start_date", parameter.Query, parameter.WithDescription("Start date (YYYY-MM-DD, default: 30 days ago)")),
+				parameter.StrParam("end_date", parameter.Query, parameter.WithDescription("End date (YYYY-MM-DD, default: today)")),
+				parameter.IntParam("limit", parameter.Query, parameter.WithDescription("Number of identities (default: 10, max: 1000)")),
+				parameter.BoolParam("exclude_test", parameter.Query, parameter.WithDescription("Exclude data created with test-environment API keys (default: false)")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(TopIdentitiesResponse{}, "200", "Ranking retrieved successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid date format"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/admin/metrics/stream - Live Metrics Stream
+		endpoint.New(
+			endpoint.GET,
+			"/admin/metrics/stream",
+			endpoint.WithTags("Admin Metrics - Usage"),
+			endpoint.WithSummary("Stream headline metrics"),
+			endpoint.WithDescription("Server-sent events stream for live dashboards. Sends an overview event (faces, verifications, error rate and RPS over the last 5 minutes) right away and then every interval_seconds until the client disconnects"),
+			endpoint.WithProduce([]mime.MIME{"text/event-stream"}),
+			endpoint.WithParams(
+				parameter.IntParam("interval_seconds", parameter.Query, parameter.WithDescription("Seconds between events (1-60, default: 5)")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(MetricsOverviewFrame{}, "200", "Event stream of overview frames"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "interval_seconds must be between 1 and 60"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/admin/faces/:external_id/trend - Per-user Verification Trend
+		endpoint.New(
+			endpoint.GET,
+			"/admin/faces/{external_id}/trend",
+			endpoint.WithTags("Admin Metrics - Usage"),
+			endpoint.WithSummary("Get verification trend for an external_id"),
+			endpoint.WithDescription("Returns a timeline of verification attempts, successes and failures for a single external_id"),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("external_id", parameter.Path, parameter.WithDescription("External user identifier")),
+				parameter.StrParam("start_date", parameter.Query, parameter.WithDescription("Start date (YYYY-MM-DD)")),
+				parameter.StrParam("end_date", parameter.Query, parameter.WithDescription("End date (YYYY-MM-DD)")),
+				parameter.StrParam("interval", parameter.Query, parameter.WithDescription("Aggregation interval: hour, day, week, month (default: day)")),
+				parameter.IntParam("limit", parameter.Query, parameter.WithDescription("Maximum number of timeline points (default: 100, max: 1000)")),
+				parameter.IntParam("offset", parameter.Query, parameter.WithDescription("Offset for timeline pagination (default: 0)")),
+				parameter.BoolParam("exclude_test", parameter.Query, parameter.WithDescription("Exclude data created with test-environment API keys (default: false)")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(FaceTrendResponse{}, "200", "Trend retrieved successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid date format"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/admin/faces/export - Faces Export
+		endpoint.New(
+			endpoint.GET,
+			"/admin/faces/export",
+			endpoint.WithTags("Admin Faces"),
+			endpoint.WithSummary("Export all registered faces as NDJSON"),
+			endpoint.WithDescription("Streams every face of the authenticated tenant, oldest first, as newline-delimited JSON for backups and migrations: one object per line with the fields of GET /admin/faces/{external_id} and, with include_embeddings=true, the embedding. A test key exports the tenant's test faces and a live key its live ones. Faces are read in pages and written as they arrive, so memory stays bounded and a failure mid-export truncates the file. Requires the face_export_enabled tenant setting and an API key with the faces:export scope."),
+			endpoint.WithProduce([]mime.MIME{"application/x-ndjson"}),
+			endpoint.WithParams(
+				parameter.BoolParam("include_embeddings", parameter.Query, parameter.WithDescription("Include each face's embedding (default: false)")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(FaceExportRecord{}, "200", "One face record per line"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "FACE_EXPORT_NOT_ENABLED", Message: "Face export is not enabled for this tenant, or the API key lacks the faces:export scope"}, "403", "Forbidden"),
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid request"}, "422", "Unprocessable Entity"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/admin/faces/:external_id - Get Face
+		endpoint.New(
+			endpoint.GET,
+			"/admin/faces/{external_id}",
+			endpoint.WithTags("Admin Faces"),
+			endpoint.WithSummary("Get a registered face with its provider face ID"),
+			endpoint.WithDescription("Returns a registered face including provider_face_id, the ID the provider assigned when indexing it (the Rekognition FaceId), to cross-reference with the provider console. provider_face_id is null for providers that assign none and for faces registered before it was stored."),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("external_id", parameter.Path, parameter.WithRequired(), parameter.WithDescription("External user identifier")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(AdminFaceResponse{}, "200", "Face retrieved successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "FACE_NOT_FOUND", Message: "Face not found"}, "404", "Not Found"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/admin/faces/compare - Compare Two Identities
+		endpoint.New(
+			endpoint.GET,
+			"/admin/faces/compare",
+			endpoint.WithTags("Admin Faces"),
+			endpoint.WithSummary("Compare the registered faces of two external_ids"),
+			endpoint.WithDescription("Returns the similarity between the stored embeddings of two external_ids, to detect one person behind several accounts. Faces registered with a provider that does not expose embeddings cannot be compared. Deprecated on Rekognition deployments: responses carry the Deprecation header, a Link with rel=\"deprecation\" and, when DEPRECATION_SUNSETS sets a date, the Sunset header."),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("a", parameter.Query, parameter.WithRequired(), parameter.WithDescription("First external_id")),
+				parameter.StrParam("b", parameter.Query, parameter.WithRequired(), parameter.WithDescription("Second external_id")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(FaceCompareResponse{}, "200", "Comparison completed successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "FACE_NOT_FOUND", Message: "Face not found"}, "404", "Not Found"),
+				response.New(ErrorResponse{Code: "EMBEDDING_UNAVAILABLE", Message: "Face has no stored embedding to compare, the provider does not expose embeddings"}, "409", "Conflict"),
+				response.New(ErrorResponse{Code: "EMBEDDING_MODEL_MISMATCH", Message: "Face was registered with a different embedding model, re-register the face to compare it"}, "409", "Conflict"),
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Request validation failed"}, "422", "Unprocessable Entity"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+				response.New(ErrorResponse{Code: "MAINTENANCE", Message: "Face recognition is paused for maintenance, retry later"}, "503", "Service Unavailable"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// POST /v1/admin/faces/recount - Recount Faces
+		endpoint.New(
+			endpoint.POST,
+			"/admin/faces/recount",
+			endpoint.WithTags("Admin Faces"),
+			endpoint.WithSummary("Recompute the tenant's face count"),
+			endpoint.WithDescription("Counts the tenant's faces in the database, replaces the cached count used by the empty-collection check and, with Rekognition, compares it with the faces indexed in the collection. provider_count and delta (provider minus database) are null for providers without a collection."),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(FaceRecountResponse{}, "200", "Faces recounted successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/admin/rate-limits - Current Rate Limit Usage
+		endpoint.New(
+			endpoint.GET,
+			"/admin/rate-limits",
+			endpoint.WithTags("Admin Rate Limits"),
+			endpoint.WithSummary("Get current rate limit usage"),
+			endpoint.WithDescription("Returns the limit, current count, remaining requests and window reset time of the search, verify and register buckets for the authenticated tenant. A limit of 0 means unlimited. Rate-limited endpoints also return X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset headers."),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(RateLimitsResponse{}, "200", "Rate limit usage retrieved successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/admin/config/thresholds - Effective Match Thresholds
+		endpoint.New(
+			endpoint.GET,
+			"/admin/config/thresholds",
+			endpoint.WithTags("Admin Config"),
+			endpoint.WithSummary("Get effective match thresholds"),
+			endpoint.WithDescription("Returns the verify and search thresholds the authenticated tenant applies and the layer each was resolved from. Layers are listed from highest to lowest precedence and the first one set wins: request (search threshold parameter, null here), security_level (security_levels override of the active level), tenant (verification_threshold / search_threshold settings) and default."),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(ThresholdsResponse{}, "200", "Threshold resolution retrieved successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/admin/searches - Search Audit Trail
+		endpoint.New(
+			endpoint.GET,
+			"/admin/searches",
+			endpoint.WithTags("Admin Searches"),
+			endpoint.WithSummary("List search audits"),
+			endpoint.WithDescription("Returns the authenticated tenant's recorded 1:N searches, most recent first. No biometric data is stored or returned. The range defaults to the last 30 days."),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("from", parameter.Query, parameter.WithDescription("Start of range, inclusive (RFC3339, default: 30 days before to)")),
+				parameter.StrParam("to", parameter.Query, parameter.WithDescription("End of range, exclusive (RFC3339, default: now)")),
+				parameter.IntParam("limit", parameter.Query, parameter.WithDescription("Maximum number of audits (default: 50, max: 100)")),
+				parameter.IntParam("offset", parameter.Query, parameter.WithDescription("Offset for pagination (default: 0)")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(SearchAuditsResponse{}, "200", "Search audits retrieved successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid request"}, "422", "Unprocessable Entity"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// POST /v1/admin/calibration/evaluate - Threshold Calibration
+		endpoint.New(
+			endpoint.POST,
+			"/admin/calibration/evaluate",
+			endpoint.WithTags("Admin Calibration"),
+			endpoint.WithSummary("Evaluate thresholds against a labeled set of image pairs"),
+			endpoint.WithDescription("Compares each labeled pair with the tenant's provider and returns the false accept rate (non-match pairs scoring at or above the threshold) and false reject rate (match pairs below it) at each threshold, plus at the current verification threshold. Repeat image_a, image_b and match (true/false) once per pair, up to 50 pairs; they are matched by position. Nothing is stored. Pairs the provider cannot score are reported with an error and left out of the rates."),
+			endpoint.WithConsume([]mime.MIME{mime.MIME("multipart/form-data")}),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("thresholds", parameter.Form, parameter.WithDescription("Comma-separated thresholds between 0 and 1 (default: 0.50 to 0.95 in steps of 0.05)")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(CalibrationReportResponse{}, "200", "Evaluation completed successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "INVALID_IMAGE", Message: "Invalid image format or corrupted file"}, "422", "Unprocessable Entity"),
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Request validation failed"}, "422", "Unprocessable Entity"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/admin/verifications/export - Verification Log Export
+		endpoint.New(
+			endpoint.GET,
+			"/admin/verifications/export",
+			endpoint.WithTags("Admin Verifications"),
+			endpoint.WithSummary("Export verification logs as CSV"),
+			endpoint.WithDescription("Streams the authenticated tenant's raw verification logs, oldest first, as a CSV attachment with the columns external_id, verified, confidence, liveness_passed (empty when no liveness check ran), latency_ms and created_at. Rows are read in pages and written as they arrive, so a failure mid-export truncates the file. The range defaults to the last 30 days."),
+			endpoint.WithProduce([]mime.MIME{"text/csv"}),
+			endpoint.WithParams(
+				parameter.StrParam("from", parameter.Query, parameter.WithDescription("Start of range, inclusive (RFC3339, default: 30 days before to)")),
+				parameter.StrParam("to", parameter.Query, parameter.WithDescription("End of range, exclusive (RFC3339, default: now)")),
+				parameter.StrParam("format", parameter.Query, parameter.WithDescription("Export format, only csv is supported (default: csv)")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New("external_id,verified,confidence,liveness_passed,latency_ms,created_at", "200", "CSV file of verifications"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid request"}, "422", "Unprocessable Entity"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/admin/verifications/:id - Verification Details
+		endpoint.New(
+			endpoint.GET,
+			"/admin/verifications/{id}",
+			endpoint.WithTags("Admin Verifications"),
+			endpoint.WithSummary("Get everything recorded about one verification"),
+			endpoint.WithDescription("Returns one of the tenant's verifications for investigations: similarity, the threshold applied and its source layer, liveness result and score, fail reason, deciding provider (and provider experiment arm), gate IP, latency and time, plus the current metadata of the verified face limited to the tenant's search_metadata_keys. face_id is null once the face was deleted; threshold_applied is null for attempts rejected before matching and verifications recorded before thresholds were stored."),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("id", parameter.Path, parameter.WithRequired(), parameter.WithDescription("Verification ID (verification_id of the verify response)")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(AdminVerificationResponse{}, "200", "Verification retrieved successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "VERIFICATION_NOT_FOUND", Message: "Verification not found"}, "404", "Not Found"),
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "id must be a verification UUID"}, "422", "Unprocessable Entity"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/admin/usage/forecast - Monthly Usage Forecast
+		endpoint.New(
+			endpoint.GET,
+			"/admin/usage/forecast",
+			endpoint.WithTags("Admin Metrics - Usage"),
+			endpoint.WithSummary("Forecast monthly usage"),
+			endpoint.WithDescription("Projects month-to-date usage (registrations, verifications and liveness checks) linearly to the end of the month and flags when the projection exceeds the tenant's max_requests_month quota."),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(UsageForecastResponse{}, "200", "Usage forecast retrieved successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/admin/webhooks - List Webhooks
+		endpoint.New(
+			endpoint.GET,
+			"/admin/webhooks",
+			endpoint.WithTags("Admin Webhooks"),
+			endpoint.WithSummary("List webhooks"),
+			endpoint.WithDescription("Lists the tenant's webhooks. Secrets are never returned; see rotate-secret to replace a lost one."),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(WebhookListResponse{}, "200", "Webhooks"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to list webhooks"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// POST /v1/admin/webhooks - Create Webhook
+		endpoint.New(
+			endpoint.POST,
+			"/admin/webhooks",
+			endpoint.WithTags("Admin Webhooks"),
+			endpoint.WithSummary("Create webhook"),
+			endpoint.WithDescription("Creates a webhook and returns its signing secret; it is not shown again. Deliveries carry X-Rekko-Signature (HMAC-SHA256 of the body with the secret) and X-Rekko-Event. Up to 20 static headers can be added; X-Rekko-*, Content-Type and User-Agent are reserved. Header values are redacted in every response. sample_rates delivers 1 in N successful events per event type."),
+			endpoint.WithConsume([]mime.MIME{mime.JSON}),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithBody(CreateWebhookRequest{}),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(CreateWebhookResponse{}, "201", "Webhook created"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid request body"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to create webhook"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// POST /v1/admin/webhooks/bulk - Bulk create webhooks
+		endpoint.New(
+			endpoint.POST,
+			"/admin/webhooks/bulk",
+			endpoint.WithTags("Admin Webhooks"),
+			endpoint.WithSummary("Create webhooks in bulk"),
+			endpoint.WithDescription("Creates up to 50 webhooks from the webhooks array of configs in one transaction, e.g. from infrastructure as code. Every config is validated first; any invalid one rejects the whole batch with the errors listed by index. Each webhook gets a generated signing secret, returned only in this response"),
+			endpoint.WithConsume([]mime.MIME{mime.JSON}),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithBody(BulkCreateWebhooksRequest{}),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(BulkCreateWebhooksResponse{}, "201", "Webhooks created"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid webhooks, none were created"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to create webhooks"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// DELETE /v1/admin/webhooks/{id} - Delete Webhook
+		endpoint.New(
+			endpoint.DELETE,
+			"/admin/webhooks/{id}",
+			endpoint.WithTags("Admin Webhooks"),
+			endpoint.WithSummary("Delete webhook"),
+			endpoint.WithParams(
+				parameter.StrParam("id", parameter.Path, parameter.WithDescription("Webhook UUID")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(EmptyResponse{}, "204", "Webhook deleted"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid webhook ID"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "NOT_FOUND", Message: "Webhook not found"}, "404", "Not Found"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/admin/webhooks/schema/:event_type - Webhook Payload Preview
+		endpoint.New(
+			endpoint.GET,
+			"/admin/webhooks/schema/{event_type}",
+			endpoint.WithTags("Admin Webhooks"),
+			endpoint.WithSummary("Preview webhook payload"),
+			endpoint.WithDescription("Returns a sample payload and its JSON schema for an event type (face.registered, face.verified, face.search, widget.searched, etc.), built with the same types used for delivery. Unknown event types return 404 with the supported list."),
 			endpoint.WithProduce([]mime.MIME{mime.JSON}),
 			endpoint.WithParams(
-				parameter.StrParam("start_date", parameter.Query, parameter.WithDescription("Start date (YYYY-MM-DD)")),
-				parameter.StrParam("end_date", parameter.Query, parameter.WithDescription("End date (YYYY-MM-DD)")),
-				parameter.StrParam("interval", parameter.Query, parameter.WithDescription("Aggregation interval: hour, day, week, month (default: day)")),
-				parameter.IntParam("limit", parameter.Query, parameter.WithDescription("Maximum number of timeline points (default: 100, max: 1000)")),
-				parameter.IntParam("offset", parameter.Query, parameter.WithDescription("Offset for timeline pagination (default: 0)")),
+				parameter.StrParam("event_type", parameter.Path, parameter.WithDescription("Webhook event type, e.g. face.verified")),
 			),
 			endpoint.WithSuccessfulReturns([]response.Response{
-				response.New(ConfidenceMetricsResponse{}, "200", "Metrics retrieved successfully"),
+				response.New(WebhookSchemaResponse{}, "200", "Sample payload and schema"),
 			}),
 			endpoint.WithErrors([]response.Response{
-				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid date format"}, "400", "Bad Request"),
 				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
-				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+				response.New(ErrorResponse{Code: "NOT_FOUND", Message: "Unknown webhook event type"}, "404", "Not Found"),
 			}),
 			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
 		),
 
-		// GET /v1/admin/metrics/matches - Match Metrics
+		// POST /v1/admin/webhooks/validate-all - Validate All Webhooks
+		endpoint.New(
+			endpoint.POST,
+			"/admin/webhooks/validate-all",
+			endpoint.WithTags("Admin Webhooks"),
+			endpoint.WithSummary("Validate all webhooks"),
+			endpoint.WithDescription("Sends a signed webhook.test event to every configured webhook, then the same event with an invalid signature. A webhook passes when the signed delivery gets a 2xx and the forged one is rejected. Certificate errors are reported as invalid TLS; test deliveries are never queued for retry."),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(WebhookValidateAllResponse{}, "200", "Per-webhook test delivery results"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to validate webhooks"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// POST /v1/admin/webhooks/{id}/rotate-secret - Rotate Webhook Secret
+		endpoint.New(
+			endpoint.POST,
+			"/admin/webhooks/{id}/rotate-secret",
+			endpoint.WithTags("Admin Webhooks"),
+			endpoint.WithSummary("Rotate webhook secret"),
+			endpoint.WithDescription("Replaces the webhook signing secret and returns the new one; it is not shown again. Optional JSON body {\"grace_period\": \"48h\"} (Go duration, default 24h, at most 168h). Until previous_secret_expires_at, deliveries carry X-Rekko-Signature signed with the new secret and X-Rekko-Signature-Previous signed with the old one, so consumers can switch secrets without rejecting deliveries. Rotating again inside the window drops the older secret."),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithConsume([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("id", parameter.Path, parameter.WithDescription("Webhook UUID")),
+			),
+			endpoint.WithBody(RotateWebhookSecretRequest{}),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(WebhookRotateSecretResponse{}, "200", "New secret and end of the grace window"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid grace_period"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "NOT_FOUND", Message: "Webhook not found"}, "404", "Not Found"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to rotate webhook secret"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// GET /v1/admin/api-keys - List API Keys
 		endpoint.New(
 			endpoint.GET,
-			"/admin/metrics/matches",
-			endpoint.WithTags("Admin Metrics - Quality"),
-			endpoint.WithSummary("Get matching statistics"),
-			endpoint.WithDescription("Returns matching statistics for verifications"),
+			"/admin/api-keys",
+			endpoint.WithTags("Admin API Keys"),
+			endpoint.WithSummary("List API keys"),
+			endpoint.WithDescription("Lists metadata of the tenant's secret keys and its widget public key. Plaintext keys and hashes are never returned."),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(APIKeysListResponse{}, "200", "API keys"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "HTTP_ERROR", Message: "Internal Server Error"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// POST /v1/admin/api-keys - Create API Key
+		endpoint.New(
+			endpoint.POST,
+			"/admin/api-keys",
+			endpoint.WithTags("Admin API Keys"),
+			endpoint.WithSummary("Create API key"),
+			endpoint.WithDescription("Issues a labeled secret key (environment test or live, default test) and returns its plaintext; it is not shown again. scopes grants the key the admin API (admin:read for GET, admin:write for changes) and sensitive endpoints such as faces:export for GET /admin/faces/export. Omitted, the key gets admin:read and admin:write; an empty list creates a key limited to the recognition API. Existing keys stay active, so keys can be rotated without downtime."),
+			endpoint.WithConsume([]mime.MIME{mime.JSON}),
 			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithBody(CreateAPIKeyRequest{}),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(CreateAPIKeyResponse{}, "201", "API key created"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "HTTP_ERROR", Message: "label is required and must be at most 100 characters"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "HTTP_ERROR", Message: "Internal Server Error"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
+		),
+
+		// DELETE /v1/admin/api-keys/{id} - Revoke API Key
+		endpoint.New(
+			endpoint.DELETE,
+			"/admin/api-keys/{id}",
+			endpoint.WithTags("Admin API Keys"),
+			endpoint.WithSummary("Revoke API key"),
+			endpoint.WithDescription("Deactivates one of the tenant's keys without affecting the others. The key used for the request cannot revoke itself."),
 			endpoint.WithParams(
-				parameter.StrParam("start_date", parameter.Query, parameter.WithDescription("Start date (YYYY-MM-DD)")),
-				parameter.StrParam("end_date", parameter.Query, parameter.WithDescription("End date (YYYY-MM-DD)")),
-				parameter.StrParam("interval", parameter.Query, parameter.WithDescription("Aggregation interval: hour, day, week, month (default: day)")),
-				parameter.IntParam("limit", parameter.Query, parameter.WithDescription("Maximum number of timeline points (default: 100, max: 1000)")),
-				parameter.IntParam("offset", parameter.Query, parameter.WithDescription("Offset for timeline pagination (default: 0)")),
+				parameter.StrParam("id", parameter.Path, parameter.WithDescription("API key UUID")),
 			),
 			endpoint.WithSuccessfulReturns([]response.Response{
-				response.New(MatchMetricsResponse{}, "200", "Metrics retrieved successfully"),
+				response.New(EmptyResponse{}, "204", "API key revoked"),
 			}),
 			endpoint.WithErrors([]response.Response{
-				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid date format"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "HTTP_ERROR", Message: "invalid API key ID"}, "400", "Bad Request"),
 				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing API key"}, "401", "Unauthorized"),
-				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+				response.New(ErrorResponse{Code: "HTTP_ERROR", Message: "API key not found"}, "404", "Not Found"),
+				response.New(ErrorResponse{Code: "HTTP_ERROR", Message: "cannot revoke the API key used for this request"}, "409", "Conflict"),
 			}),
 			endpoint.WithSecurity([]map[string][]string{{"ApiKeyAuth": {}}}),
 		),
@@ -679,16 +2315,21 @@ func NewSwagger() *swagno.Swagger {
 			"/super/tenants",
 			endpoint.WithTags("Super Admin"),
 			endpoint.WithSummary("List all tenants with metrics"),
-			endpoint.WithDescription("Returns a list of all tenants with summary metrics (requires super admin JWT authentication)"),
+			endpoint.WithDescription("Returns the tenants with summary metrics, filtered by plan, activity and a name/slug search and sorted to surface problem tenants; meta echoes the applied filters (requires super admin JWT authentication)"),
 			endpoint.WithProduce([]mime.MIME{mime.JSON}),
 			endpoint.WithParams(
 				parameter.IntParam("limit", parameter.Query, parameter.WithDescription("Maximum number of tenants (default: 50, max: 100)")),
 				parameter.IntParam("offset", parameter.Query, parameter.WithDescription("Offset for pagination (default: 0)")),
+				parameter.StrParam("plan", parameter.Query, parameter.WithDescription("Only tenants on this plan: starter, pro or enterprise")),
+				parameter.BoolParam("is_active", parameter.Query, parameter.WithDescription("Only active (true) or inactive (false) tenants")),
+				parameter.StrParam("search", parameter.Query, parameter.WithDescription("Case-insensitive substring of the tenant name or slug")),
+				parameter.StrParam("sort", parameter.Query, parameter.WithDescription("Descending sort: created_at, faces, requests or error_rate (default: created_at)")),
 			),
 			endpoint.WithSuccessfulReturns([]response.Response{
 				response.New(ListTenantsResponse{}, "200", "Tenants retrieved successfully"),
 			}),
 			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "invalid sort, expected created_at, faces, requests or error_rate"}, "400", "Bad Request"),
 				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing JWT token"}, "401", "Unauthorized"),
 				response.New(ErrorResponse{Code: "FORBIDDEN", Message: "Insufficient privileges"}, "403", "Forbidden"),
 				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
@@ -719,6 +2360,31 @@ func NewSwagger() *swagno.Swagger {
 			endpoint.WithSecurity([]map[string][]string{{"BearerAuth": {}}}),
 		),
 
+		// GET /v1/super/tenants/{id}/feature-usage - Optional feature usage
+		endpoint.New(
+			endpoint.GET,
+			"/super/tenants/{id}/feature-usage",
+			endpoint.WithTags("Super Admin"),
+			endpoint.WithSummary("Get a tenant's usage of optional features"),
+			endpoint.WithDescription("Summarizes how often the tenant used search, liveness, the widget and webhook deliveries between two dates (at most 366 days), with per-counter totals, active days and the last day each feature ran (requires super admin JWT authentication)"),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("id", parameter.Path, parameter.WithDescription("Tenant UUID")),
+				parameter.StrParam("start_date", parameter.Query, parameter.WithDescription("Start date (YYYY-MM-DD, default: 30 days ago)")),
+				parameter.StrParam("end_date", parameter.Query, parameter.WithDescription("End date (YYYY-MM-DD, default: today)")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(FeatureUsageResponse{}, "200", "Feature usage retrieved successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "start_date must not be after end_date"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing JWT token"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "FORBIDDEN", Message: "Insufficient privileges"}, "403", "Forbidden"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"BearerAuth": {}}}),
+		),
+
 		// POST /v1/super/tenants/:id/quota - Update tenant quota
 		endpoint.New(
 			endpoint.POST,
@@ -743,7 +2409,154 @@ func NewSwagger() *swagno.Swagger {
 			endpoint.WithSecurity([]map[string][]string{{"BearerAuth": {}}}),
 		),
 
-		// GET /v1/super/system/health - System health check
+		// GET /v1/super/tenants/{id}/config/export - Export tenant config
+		endpoint.New(
+			endpoint.GET,
+			"/super/tenants/{id}/config/export",
+			endpoint.WithTags("Super Admin"),
+			endpoint.WithSummary("Export tenant configuration"),
+			endpoint.WithDescription("Exports the tenant plan, settings and webhooks, with their custom headers and sample rates, as a portable JSON document. Webhook secrets are not included (requires super admin JWT authentication)"),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("id", parameter.Path, parameter.WithDescription("Tenant UUID")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(TenantConfigDoc{}, "200", "Tenant configuration"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid tenant ID format"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing JWT token"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "FORBIDDEN", Message: "Insufficient privileges"}, "403", "Forbidden"),
+				response.New(ErrorResponse{Code: "TENANT_NOT_FOUND", Message: "Tenant not found"}, "404", "Not Found"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"BearerAuth": {}}}),
+		),
+
+		// POST /v1/super/tenants/{id}/config/import - Import tenant config
+		endpoint.New(
+			endpoint.POST,
+			"/super/tenants/{id}/config/import",
+			endpoint.WithTags("Super Admin"),
+			endpoint.WithSummary("Import tenant configuration"),
+			endpoint.WithDescription("Validates an exported configuration and replaces the tenant plan, settings and webhooks in one transaction. Imported webhooks get new secrets, returned only in this response (requires super admin JWT authentication)"),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithConsume([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("id", parameter.Path, parameter.WithDescription("Tenant UUID")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(ImportTenantConfigResponse{}, "200", "Configuration imported successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid request body"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing JWT token"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "FORBIDDEN", Message: "Insufficient privileges"}, "403", "Forbidden"),
+				response.New(ErrorResponse{Code: "TENANT_NOT_FOUND", Message: "Tenant not found"}, "404", "Not Found"),
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid tenant configuration"}, "422", "Unprocessable Entity"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"BearerAuth": {}}}),
+		),
+
+		// GET /v1/super/tenants/{id}/impersonate - Read-only tenant impersonation
+		endpoint.New(
+			endpoint.GET,
+			"/super/tenants/{id}/impersonate",
+			endpoint.WithTags("Super Admin"),
+			endpoint.WithSummary("Impersonate a tenant for read-only debugging"),
+			endpoint.WithDescription("Issues a 15 minute token that authenticates as the tenant in place of an API key, for GET requests only. Every request made with it is written to the audit log with the super admin's identity (requires super admin JWT authentication)"),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("id", parameter.Path, parameter.WithDescription("Tenant UUID")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(ImpersonationTokenResponse{}, "200", "Impersonation token issued"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid tenant ID format"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing JWT token"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "FORBIDDEN", Message: "Insufficient privileges"}, "403", "Forbidden"),
+				response.New(ErrorResponse{Code: "TENANT_NOT_FOUND", Message: "Tenant not found"}, "404", "Not Found"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"BearerAuth": {}}}),
+		),
+
+		// POST /v1/super/tenants/{id}/rate-limits/reset - Reset tenant rate limits
+		endpoint.New(
+			endpoint.POST,
+			"/super/tenants/{id}/rate-limits/reset",
+			endpoint.WithTags("Super Admin"),
+			endpoint.WithSummary("Reset a tenant's rate limits"),
+			endpoint.WithDescription("Clears every rate limit counter of the tenant so the next request is allowed regardless of prior usage. Returns the number of buckets cleared (requires super admin JWT authentication)"),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("id", parameter.Path, parameter.WithDescription("Tenant UUID")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(ResetRateLimitsResponse{}, "200", "Rate limits reset"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "Invalid tenant ID format"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing JWT token"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "FORBIDDEN", Message: "Insufficient privileges"}, "403", "Forbidden"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"BearerAuth": {}}}),
+		),
+
+		// DELETE /v1/super/tenants/{id} - Delete tenant
+		endpoint.New(
+			endpoint.DELETE,
+			"/super/tenants/{id}",
+			endpoint.WithTags("Super Admin"),
+			endpoint.WithSummary("Delete a tenant and all its data"),
+			endpoint.WithDescription("Irreversibly deletes the tenant with its faces, verifications, API keys and every other tenant record in one transaction, then deletes the provider collection (best-effort, when TENANT_DELETE_PURGE_COLLECTION is enabled). The tenant slug must be repeated in confirm. Emits a TENANT_DELETED audit event (requires super admin JWT authentication)"),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("id", parameter.Path, parameter.WithDescription("Tenant UUID")),
+				parameter.StrParam("confirm", parameter.Query, parameter.WithRequired(), parameter.WithDescription("Slug of the tenant being deleted")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(TenantDeletionResponse{}, "200", "Tenant deleted"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "confirm must match the tenant slug"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing JWT token"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "FORBIDDEN", Message: "Insufficient privileges"}, "403", "Forbidden"),
+				response.New(ErrorResponse{Code: "TENANT_NOT_FOUND", Message: "Tenant not found"}, "404", "Not Found"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"BearerAuth": {}}}),
+		),
+
+		// POST /v1/super/tenants/{id}/maintenance - Toggle tenant maintenance mode
+		endpoint.New(
+			endpoint.POST,
+			"/super/tenants/{id}/maintenance",
+			endpoint.WithTags("Super Admin"),
+			endpoint.WithSummary("Toggle a tenant's maintenance mode"),
+			endpoint.WithDescription("While enabled, recognition endpoints (register, verify, search, liveness, count, admin compare and calibration, and the widget) return 503 MAINTENANCE with Retry-After (retry_after, default 5m, max 24h), while reads and configuration keep working. Emits a TENANT_MAINTENANCE audit event (requires super admin JWT authentication)"),
+			endpoint.WithConsume([]mime.MIME{mime.JSON}),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("id", parameter.Path, parameter.WithDescription("Tenant UUID")),
+			),
+			endpoint.WithBody(SetMaintenanceRequest{}),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(MaintenanceResponse{}, "200", "Maintenance mode updated"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "enabled is required"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing JWT token"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "FORBIDDEN", Message: "Insufficient privileges"}, "403", "Forbidden"),
+				response.New(ErrorResponse{Code: "TENANT_NOT_FOUND", Message: "Tenant not found"}, "404", "Not Found"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"BearerAuth": {}}}),
+		),
+
 		endpoint.New(
 			endpoint.GET,
 			"/super/system/health",
@@ -782,6 +2595,30 @@ func NewSwagger() *swagno.Swagger {
 			endpoint.WithSecurity([]map[string][]string{{"BearerAuth": {}}}),
 		),
 
+		// GET /v1/super/metrics/provider-errors - Provider errors by code
+		endpoint.New(
+			endpoint.GET,
+			"/super/metrics/provider-errors",
+			endpoint.WithTags("Super Admin"),
+			endpoint.WithSummary("Count failed provider calls by error code"),
+			endpoint.WithDescription("Groups the provider calls that failed between two dates (at most 366 days) by provider and raw error code, most frequent first, to tell outages from throttling. Codes are the provider's own (e.g. ThrottlingException from AWS, HTTP_503 from DeepFace), or TIMEOUT, NETWORK_ERROR and UNKNOWN when it gave none. Images without a face are not provider failures and are not counted (requires super admin JWT authentication)"),
+			endpoint.WithProduce([]mime.MIME{mime.JSON}),
+			endpoint.WithParams(
+				parameter.StrParam("start_date", parameter.Query, parameter.WithDescription("Start date (YYYY-MM-DD, default: 30 days ago)")),
+				parameter.StrParam("end_date", parameter.Query, parameter.WithDescription("End date, inclusive (YYYY-MM-DD, default: today)")),
+			),
+			endpoint.WithSuccessfulReturns([]response.Response{
+				response.New(ProviderErrorsResponse{}, "200", "Provider errors retrieved successfully"),
+			}),
+			endpoint.WithErrors([]response.Response{
+				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "start_date must be before or equal to end_date"}, "400", "Bad Request"),
+				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or missing JWT token"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "FORBIDDEN", Message: "Insufficient privileges"}, "403", "Forbidden"),
+				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
+			}),
+			endpoint.WithSecurity([]map[string][]string{{"BearerAuth": {}}}),
+		),
+
 		// GET /v1/super/providers - Providers status
 		endpoint.New(
 			endpoint.GET,
@@ -819,6 +2656,7 @@ func NewSwagger() *swagno.Swagger {
 				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "public_key and origin are required"}, "400", "Bad Request"),
 				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid public key"}, "401", "Unauthorized"),
 				response.New(ErrorResponse{Code: "INVALID_ORIGIN", Message: "Origin not allowed for this tenant"}, "403", "Forbidden"),
+				response.New(ErrorResponse{Code: "WIDGET_SESSION_RATE_LIMIT_EXCEEDED", Message: "Too many widget sessions created with this public key, try again later"}, "429", "Too Many Requests"),
 				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
 			}),
 		),
@@ -838,8 +2676,8 @@ func NewSwagger() *swagno.Swagger {
 			endpoint.WithErrors([]response.Response{
 				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "session_id, external_id and image are required"}, "400", "Bad Request"),
 				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or expired session"}, "401", "Unauthorized"),
-				response.New(ErrorResponse{Code: "NO_FACE_DETECTED", Message: "No face detected in image"}, "422", "Unprocessable Entity"),
-				response.New(ErrorResponse{Code: "MULTIPLE_FACES", Message: "Multiple faces detected"}, "422", "Unprocessable Entity"),
+				response.New(ErrorResponse{Code: "NO_FACE_DETECTED", Message: "No face detected in image", Reasons: []string{"too_dark"}}, "422", "Unprocessable Entity"),
+				response.New(MultipleFacesErrorResponse{Code: "MULTIPLE_FACES", Message: "3 faces detected, only 1 allowed", FacesDetected: 3, MaxFaces: 1}, "422", "Unprocessable Entity"),
 				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
 			}),
 		),
@@ -859,7 +2697,7 @@ func NewSwagger() *swagno.Swagger {
 			endpoint.WithErrors([]response.Response{
 				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "session_id and image are required"}, "400", "Bad Request"),
 				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or expired session"}, "401", "Unauthorized"),
-				response.New(ErrorResponse{Code: "NO_FACE_DETECTED", Message: "No face detected in image"}, "422", "Unprocessable Entity"),
+				response.New(ErrorResponse{Code: "NO_FACE_DETECTED", Message: "No face detected in image", Reasons: []string{"too_dark"}}, "422", "Unprocessable Entity"),
 				response.New(ErrorResponse{Code: "MULTIPLE_FACES", Message: "Multiple faces detected"}, "422", "Unprocessable Entity"),
 				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
 			}),
@@ -871,7 +2709,7 @@ func NewSwagger() *swagno.Swagger {
 			"/widget/search",
 			endpoint.WithTags("Widget"),
 			endpoint.WithSummary("Search/identify a face via widget"),
-			endpoint.WithDescription("Performs 1:N face search to identify a person without requiring an external_id (Entrada VIP mode)"),
+			endpoint.WithDescription("Performs 1:N face search to identify a person without requiring an external_id (Entrada VIP mode). A top match below the tenant's search_identify_floor is returned as a candidate with identified=false"),
 			endpoint.WithConsume([]mime.MIME{mime.MIME("multipart/form-data")}),
 			endpoint.WithProduce([]mime.MIME{mime.JSON}),
 			endpoint.WithSuccessfulReturns([]response.Response{
@@ -882,7 +2720,8 @@ func NewSwagger() *swagno.Swagger {
 				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or expired session"}, "401", "Unauthorized"),
 				response.New(ErrorResponse{Code: "SEARCH_NOT_ENABLED", Message: "Search not enabled for tenant"}, "403", "Forbidden"),
 				response.New(ErrorResponse{Code: "NO_FACE_DETECTED", Message: "No face detected in image"}, "422", "Unprocessable Entity"),
-				response.New(ErrorResponse{Code: "SEARCH_RATE_LIMIT_EXCEEDED", Message: "Search rate limit exceeded"}, "429", "Too Many Requests"),
+				response.New(RateLimitErrorResponse{Code: "SEARCH_RATE_LIMIT_EXCEEDED", Message: "Search rate limit exceeded, try again later"}, "429", "Too Many Requests (Retry-After and X-RateLimit-* headers give the exact reset)"),
+				response.New(ErrorResponse{Code: "AUDIT_WRITE_FAILED", Message: "Search audit could not be recorded, the result was withheld; retry shortly"}, "503", "Service Unavailable (tenants with search_audit_mode sync)"),
 				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
 			}),
 		),
@@ -893,11 +2732,12 @@ func NewSwagger() *swagno.Swagger {
 			"/widget/check",
 			endpoint.WithTags("Widget"),
 			endpoint.WithSummary("Check if a face is registered"),
-			endpoint.WithDescription("Checks if a face is already registered for the given external_id. Useful for determining whether to show registration or verification UI."),
+			endpoint.WithDescription("Checks if a face is already registered for the given external_id. Useful for determining whether to show registration or verification UI. Tenants that set widget_check_secret only answer for external_ids signed by their backend: signature is the hex-encoded HMAC-SHA256 of the external_id keyed with the secret, so the browser cannot enumerate registered IDs. Deployments may also cap checks per session with WIDGET_CHECK_RATE_LIMIT."),
 			endpoint.WithProduce([]mime.MIME{mime.JSON}),
 			endpoint.WithParams(
 				parameter.StrParam("session_id", parameter.Query, parameter.WithDescription("Widget session ID")),
 				parameter.StrParam("external_id", parameter.Query, parameter.WithDescription("External user identifier to check")),
+				parameter.StrParam("signature", parameter.Query, parameter.WithDescription("HMAC-SHA256 of external_id, hex-encoded; required when the tenant sets widget_check_secret")),
 			),
 			endpoint.WithSuccessfulReturns([]response.Response{
 				response.New(CheckRegistrationResponse{}, "200", "Check completed successfully"),
@@ -905,6 +2745,8 @@ func NewSwagger() *swagno.Swagger {
 			endpoint.WithErrors([]response.Response{
 				response.New(ErrorResponse{Code: "VALIDATION_FAILED", Message: "session_id and external_id are required"}, "400", "Bad Request"),
 				response.New(ErrorResponse{Code: "UNAUTHORIZED", Message: "Invalid or expired session"}, "401", "Unauthorized"),
+				response.New(ErrorResponse{Code: "INVALID_CHECK_SIGNATURE", Message: "external_id must be signed by the tenant backend to check its registration"}, "403", "Forbidden"),
+				response.New(ErrorResponse{Code: "WIDGET_CHECK_RATE_LIMIT_EXCEEDED", Message: "Too many registration checks in this widget session, try again later"}, "429", "Too Many Requests"),
 				response.New(ErrorResponse{Code: "INTERNAL_ERROR", Message: "An unexpected error occurred"}, "500", "Internal Server Error"),
 			}),
 		),
@@ -912,5 +2754,11 @@ func NewSwagger() *swagno.Swagger {
 
 	sw.AddEndpoints(endpoints)
 
+	// swagno references struct values of map fields without defining them, which
+	// leaves a dangling $ref that breaks SDK generators
+	definitions := definition.NewDefinitionGenerator(sw.Definitions)
+	definitions.CreateDefinition(FaceExistsResult{})
+	definitions.CreateDefinition(ProviderMetrics{})
+
 	return sw
 }
@@ -0,0 +1,123 @@
+package docs
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+type specDoc struct {
+	Swagger     string                              `json:"swagger"`
+	BasePath    string                              `json:"basePath"`
+	Paths       map[string]map[string]specOperation `json:"paths"`
+	Definitions map[string]json.RawMessage          `json:"definitions"`
+}
+
+type specOperation struct {
+	OperationID string                     `json:"operationId"`
+	Parameters  []specParameter            `json:"parameters"`
+	Responses   map[string]json.RawMessage `json:"responses"`
+}
+
+type specParameter struct {
+	Name string `json:"name"`
+	In   string `json:"in"`
+}
+
+func loadSpec(t *testing.T) (specDoc, []byte) {
+	t.Helper()
+
+	raw := NewSwagger().MustToJson()
+	var spec specDoc
+	require.NoError(t, json.Unmarshal(raw, &spec))
+	return spec, raw
+}
+
+func TestNewSwagger_ValidSpec(t *testing.T) {
+	spec, raw := loadSpec(t)
+
+	assert.Equal(t, "2.0", spec.Swagger)
+	assert.Equal(t, "/v1", spec.BasePath)
+	require.NotEmpty(t, spec.Paths)
+
+	seen := make(map[string]string)
+	for path, operations := range spec.Paths {
+		for method, op := range operations {
+			where := strings.ToUpper(method) + " " + path
+
+			require.NotEmpty(t, op.OperationID, where)
+			if prev, ok := seen[op.OperationID]; ok {
+				t.Errorf("operationId %q used by %s and %s", op.OperationID, prev, where)
+			}
+			seen[op.OperationID] = where
+
+			assert.NotEmpty(t, op.Responses, "%s has no responses", where)
+
+			declared := make(map[string]bool)
+			for _, p := range op.Parameters {
+				if p.In == "path" {
+					declared[p.Name] = true
+				}
+			}
+			for _, m := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+				assert.True(t, declared[m[1]], "%s does not declare path parameter %q", where, m[1])
+			}
+		}
+	}
+
+	// Every $ref must resolve to a definition, or generated SDKs fail to compile
+	refs := regexp.MustCompile(`"\$ref":\s*"#/definitions/([^"]+)"`).FindAllSubmatch(raw, -1)
+	require.NotEmpty(t, refs)
+	for _, ref := range refs {
+		_, ok := spec.Definitions[string(ref[1])]
+		assert.True(t, ok, "unresolved $ref to %q", ref[1])
+	}
+}
+
+func TestNewSwagger_OperationIDs(t *testing.T) {
+	spec, _ := loadSpec(t)
+
+	ids := make(map[string]bool)
+	for _, operations := range spec.Paths {
+		for _, op := range operations {
+			ids[op.OperationID] = true
+		}
+	}
+
+	expected := []string{
+		"post-/faces",
+		"post-/faces/verify",
+		"post-/faces/search",
+		"delete-/faces/{external_id}",
+		"get-/usage",
+		"get-/errors",
+		"get-/admin/faces/{external_id}",
+		"get-/admin/metrics/faces",
+		"get-/admin/metrics/operations",
+		"get-/admin/metrics/requests",
+		"get-/admin/metrics/latency",
+		"get-/admin/metrics/throughput",
+		"get-/admin/metrics/errors",
+		"get-/admin/metrics/top-identities",
+		"get-/admin/webhooks",
+		"post-/admin/webhooks",
+		"post-/admin/webhooks/bulk",
+		"delete-/admin/webhooks/{id}",
+		"post-/admin/webhooks/{id}/rotate-secret",
+		"get-/admin/api-keys",
+		"post-/admin/api-keys",
+		"delete-/admin/api-keys/{id}",
+		"post-/super/tenants/{id}/maintenance",
+	}
+	for _, id := range expected {
+		assert.True(t, ids[id], "missing operationId %q", id)
+	}
+
+	assert.False(t, ids["post-/faces/register"], "register is served at POST /faces")
+}
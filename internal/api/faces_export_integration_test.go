@@ -0,0 +1,125 @@
+//go:build integration
+
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	adminHandler "github.com/saturnino-fabrica-de-software/rekko/internal/api/handler/admin"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/repository"
+)
+
+func TestIntegration_FacesExport(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+
+	ctx := context.Background()
+	_, err := testDB.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS faces (
+			id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+			tenant_id UUID NOT NULL,
+			external_id VARCHAR(255) NOT NULL,
+			embedding vector(512),
+			embedding_half halfvec(512),
+			embedding_model VARCHAR(100),
+			embedding_version VARCHAR(50),
+			embedding_hash TEXT,
+			provider_face_id TEXT,
+			metadata JSONB DEFAULT '{}',
+			quality_score DECIMAL(5,4),
+			is_test BOOLEAN NOT NULL DEFAULT false,
+			expires_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ DEFAULT NOW(),
+			updated_at TIMESTAMPTZ DEFAULT NOW(),
+			UNIQUE(tenant_id, external_id)
+		)
+	`)
+	if err != nil {
+		t.Fatalf("Create faces table: %v", err)
+	}
+
+	// More faces than one export page, all inserted in one statement so they share created_at
+	// and the export has to page by id as well
+	const seeded = 2500
+	tenantID, otherTenantID := uuid.New(), uuid.New()
+	seed := `
+		INSERT INTO faces (tenant_id, external_id, embedding, embedding_model, embedding_version, quality_score)
+		SELECT $1, 'user-' || g, array_fill(0.5::real, ARRAY[512])::vector(512), 'deepface/Facenet512', '512d', 0.9
+		FROM generate_series(1, $2) g
+	`
+	if _, err := testDB.Exec(ctx, seed, tenantID, seeded); err != nil {
+		t.Fatalf("Seed faces: %v", err)
+	}
+	if _, err := testDB.Exec(ctx, seed, otherTenantID, 10); err != nil {
+		t.Fatalf("Seed other tenant faces: %v", err)
+	}
+	// Test-key enrollments of the same tenant stay out of a live export
+	if _, err := testDB.Exec(ctx, `
+		INSERT INTO faces (tenant_id, external_id, embedding, is_test)
+		SELECT $1, 'test-user-' || g, array_fill(0.5::real, ARRAY[512])::vector(512), true
+		FROM generate_series(1, 10) g
+	`, tenantID); err != nil {
+		t.Fatalf("Seed test faces: %v", err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler(logger)})
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals(middleware.LocalTenant, &domain.Tenant{ID: tenantID, Settings: map[string]interface{}{"face_export_enabled": true}})
+		return c.Next()
+	})
+	app.Get("/v1/admin/faces/export", adminHandler.NewFacesExportHandler(repository.NewFaceRepository(testDB), logger).Export)
+
+	for _, includeEmbeddings := range []bool{false, true} {
+		path := "/v1/admin/faces/export"
+		if includeEmbeddings {
+			path += "?include_embeddings=true"
+		}
+
+		resp, err := app.Test(httptest.NewRequest("GET", path, nil), -1)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		if resp.StatusCode != 200 {
+			t.Fatalf("Status = %d, want 200", resp.StatusCode)
+		}
+
+		lines := 0
+		externalIDs := make(map[string]bool, seeded)
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			var record adminHandler.FaceExportRecord
+			if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+				t.Fatalf("Line %d is not JSON: %v", lines+1, err)
+			}
+			if includeEmbeddings != (len(record.Embedding) == 512) {
+				t.Fatalf("Line %d has %d embedding values, include_embeddings=%v", lines+1, len(record.Embedding), includeEmbeddings)
+			}
+			externalIDs[record.ExternalID] = true
+			lines++
+		}
+		if err := scanner.Err(); err != nil {
+			t.Fatalf("Read export: %v", err)
+		}
+
+		if lines != seeded {
+			t.Errorf("include_embeddings=%v: lines = %d, want %d", includeEmbeddings, lines, seeded)
+		}
+		if len(externalIDs) != seeded {
+			t.Errorf("include_embeddings=%v: distinct external_ids = %d, want %d", includeEmbeddings, len(externalIDs), seeded)
+		}
+	}
+}
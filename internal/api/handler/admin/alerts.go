@@ -60,7 +60,7 @@ type AlertResponse struct {
 func (h *AlertsHandler) List(c *fiber.Ctx) error {
 	tenantID := c.Locals("tenant_id").(uuid.UUID)
 
-	alerts, err := h.repo.ListByTenant(c.Context(), tenantID)
+	alerts, err := h.repo.ListByTenant(c.UserContext(), tenantID)
 	if err != nil {
 		h.logger.Error("failed to list alerts", "tenant_id", tenantID, "error", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -112,7 +112,7 @@ func (h *AlertsHandler) Get(c *fiber.Ctx) error {
 		})
 	}
 
-	a, err := h.repo.GetByID(c.Context(), tenantID, alertID)
+	a, err := h.repo.GetByID(c.UserContext(), tenantID, alertID)
 	if err != nil {
 		h.logger.Error("failed to get alert",
 			"alert_id", alertID,
@@ -170,7 +170,7 @@ func (h *AlertsHandler) Create(c *fiber.Ctx) error {
 		Enabled:         req.Enabled,
 	}
 
-	if err := h.repo.Create(c.Context(), a); err != nil {
+	if err := h.repo.Create(c.UserContext(), a); err != nil {
 		h.logger.Error("failed to create alert", "tenant_id", tenantID, "error", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to create alert",
@@ -219,7 +219,7 @@ func (h *AlertsHandler) Update(c *fiber.Ctx) error {
 		})
 	}
 
-	a, err := h.repo.GetByID(c.Context(), tenantID, alertID)
+	a, err := h.repo.GetByID(c.UserContext(), tenantID, alertID)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "Alert not found",
@@ -251,7 +251,7 @@ func (h *AlertsHandler) Update(c *fiber.Ctx) error {
 		a.Enabled = *req.Enabled
 	}
 
-	if err := h.repo.Update(c.Context(), a); err != nil {
+	if err := h.repo.Update(c.UserContext(), a); err != nil {
 		h.logger.Error("failed to update alert",
 			"alert_id", alertID,
 			"tenant_id", tenantID,
@@ -302,7 +302,7 @@ func (h *AlertsHandler) Delete(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.repo.Delete(c.Context(), tenantID, alertID); err != nil {
+	if err := h.repo.Delete(c.UserContext(), tenantID, alertID); err != nil {
 		h.logger.Error("failed to delete alert",
 			"alert_id", alertID,
 			"tenant_id", tenantID,
@@ -339,7 +339,7 @@ func (h *AlertsHandler) ListHistory(c *fiber.Ctx) error {
 		}
 	}
 
-	history, err := h.repo.ListHistory(c.Context(), tenantID, alertID, limit)
+	history, err := h.repo.ListHistory(c.UserContext(), tenantID, alertID, limit)
 	if err != nil {
 		h.logger.Error("failed to list alert history",
 			"alert_id", alertID,
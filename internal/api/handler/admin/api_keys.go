@@ -1,36 +1,69 @@
 package admin
 
 import (
+	"context"
+	"errors"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
 )
 
+// APIKeyStore manages the API keys that belong to a tenant
+type APIKeyStore interface {
+	ListByTenant(ctx context.Context, tenantID uuid.UUID) ([]domain.APIKey, error)
+	Create(ctx context.Context, key *domain.APIKey) error
+	RevokeByTenant(ctx context.Context, tenantID, id uuid.UUID) error
+}
+
+// PublicKeyGetter returns the widget public key of a tenant
+type PublicKeyGetter interface {
+	GetPublicKey(ctx context.Context, tenantID uuid.UUID) (string, error)
+}
+
 type APIKeysHandler struct {
-	db     *pgxpool.Pool
-	logger *slog.Logger
+	apiKeys APIKeyStore
+	tenants PublicKeyGetter
+	logger  *slog.Logger
 }
 
-func NewAPIKeysHandler(db *pgxpool.Pool, logger *slog.Logger) *APIKeysHandler {
+func NewAPIKeysHandler(apiKeys APIKeyStore, tenants PublicKeyGetter, logger *slog.Logger) *APIKeysHandler {
 	return &APIKeysHandler{
-		db:     db,
-		logger: logger,
+		apiKeys: apiKeys,
+		tenants: tenants,
+		logger:  logger,
 	}
 }
 
+// maxAPIKeyLabelLength matches the api_keys.name column
+const maxAPIKeyLabelLength = 100
+
+// CreateAPIKeyRequest creates a labeled key for one integration (e.g. "mobile app", "gate").
+// Scopes grant the key the admin API and sensitive endpoints such as faces:export. Omitted, the
+// key gets domain.DefaultSecretKeyScopes; an empty list creates a recognition-only key.
+type CreateAPIKeyRequest struct {
+	Label       string   `json:"label"`
+	Environment string   `json:"environment"`
+	Scopes      []string `json:"scopes"`
+}
+
+// APIKeyResponse exposes non-secret API key metadata only.
+// The plaintext key is shown once at creation and the hash is never returned.
+// Name holds the human label given at creation.
 type APIKeyResponse struct {
-	ID          string  `json:"id"`
-	Name        string  `json:"name"`
-	KeyPrefix   string  `json:"key_prefix"`
-	Environment string  `json:"environment"`
-	IsActive    bool    `json:"is_active"`
-	LastUsedAt  *string `json:"last_used_at,omitempty"`
-	CreatedAt   string  `json:"created_at"`
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	KeyPrefix   string   `json:"key_prefix"`
+	Environment string   `json:"environment"`
+	Scopes      []string `json:"scopes"`
+	IsActive    bool     `json:"is_active"`
+	LastUsedAt  *string  `json:"last_used_at,omitempty"`
+	CreatedAt   string   `json:"created_at"`
 }
 
 type APIKeysResponse struct {
@@ -38,6 +71,8 @@ type APIKeysResponse struct {
 	Keys      []APIKeyResponse `json:"keys"`
 }
 
+// List returns metadata for the authenticated tenant's API keys
+// GET /v1/admin/api-keys
 func (h *APIKeysHandler) List(c *fiber.Ctx) error {
 	tenantID, ok := c.Locals(middleware.LocalTenantID).(uuid.UUID)
 	if !ok {
@@ -45,59 +80,144 @@ func (h *APIKeysHandler) List(c *fiber.Ctx) error {
 		return fiber.ErrUnauthorized
 	}
 
-	// Get tenant's public_key
-	var publicKey *string
-	err := h.db.QueryRow(c.Context(), `
-		SELECT public_key FROM tenants WHERE id = $1
-	`, tenantID).Scan(&publicKey)
+	publicKey, err := h.tenants.GetPublicKey(c.UserContext(), tenantID)
 	if err != nil {
 		h.logger.Error("failed to get tenant public key", "error", err, "tenant_id", tenantID)
 		return fiber.ErrInternalServerError
 	}
 
-	// Get all API keys for tenant
-	rows, err := h.db.Query(c.Context(), `
-		SELECT id, name, key_prefix, environment, is_active, last_used_at, created_at
-		FROM api_keys
-		WHERE tenant_id = $1
-		ORDER BY created_at DESC
-	`, tenantID)
+	apiKeys, err := h.apiKeys.ListByTenant(c.UserContext(), tenantID)
 	if err != nil {
 		h.logger.Error("failed to list api keys", "error", err, "tenant_id", tenantID)
 		return fiber.ErrInternalServerError
 	}
-	defer rows.Close()
 
-	keys := make([]APIKeyResponse, 0)
-	for rows.Next() {
-		var key APIKeyResponse
-		var id uuid.UUID
-		var lastUsedAt *time.Time
-		var createdAt time.Time
+	keys := make([]APIKeyResponse, 0, len(apiKeys))
+	for _, k := range apiKeys {
+		keys = append(keys, toAPIKeyResponse(k))
+	}
 
-		err := rows.Scan(&id, &key.Name, &key.KeyPrefix, &key.Environment, &key.IsActive, &lastUsedAt, &createdAt)
-		if err != nil {
-			h.logger.Error("failed to scan api key", "error", err)
-			continue
-		}
+	return c.JSON(APIKeysResponse{
+		PublicKey: publicKey,
+		Keys:      keys,
+	})
+}
 
-		key.ID = id.String()
-		key.CreatedAt = createdAt.Format(time.RFC3339)
-		if lastUsedAt != nil {
-			formatted := lastUsedAt.Format(time.RFC3339)
-			key.LastUsedAt = &formatted
-		}
+// Create issues a new labeled secret key. Existing keys stay active.
+// POST /v1/admin/api-keys
+func (h *APIKeysHandler) Create(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals(middleware.LocalTenantID).(uuid.UUID)
+	if !ok {
+		h.logger.Warn("tenant ID not found in context")
+		return fiber.ErrUnauthorized
+	}
 
-		keys = append(keys, key)
+	var req CreateAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
 	}
 
-	pk := ""
-	if publicKey != nil {
-		pk = *publicKey
+	label := strings.TrimSpace(req.Label)
+	if label == "" || len(label) > maxAPIKeyLabelLength {
+		return fiber.NewError(fiber.StatusBadRequest, "label is required and must be at most 100 characters")
 	}
 
-	return c.JSON(APIKeysResponse{
-		PublicKey: pk,
-		Keys:      keys,
+	env := req.Environment
+	if env == "" {
+		env = domain.EnvTest
+	}
+
+	if err := domain.ValidateScopes(req.Scopes); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	scopes := req.Scopes
+	if scopes == nil {
+		scopes = append([]string(nil), domain.DefaultSecretKeyScopes...)
+	}
+
+	plainKey, hash, prefix, err := domain.GenerateAPIKey(domain.KeyTypeSecret, env)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	key := &domain.APIKey{
+		TenantID:    tenantID,
+		Name:        label,
+		KeyHash:     hash,
+		KeyPrefix:   prefix,
+		Environment: env,
+		IsActive:    true,
+		Scopes:      scopes,
+	}
+
+	if err := h.apiKeys.Create(c.UserContext(), key); err != nil {
+		h.logger.Error("failed to create api key", "error", err, "tenant_id", tenantID)
+		return fiber.ErrInternalServerError
+	}
+
+	h.logger.Info("api key created",
+		"key_id", key.ID,
+		"tenant_id", tenantID,
+		"key_prefix", key.KeyPrefix,
+		"label", key.Name,
+	)
+
+	// The plaintext key is returned only in this response
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"api_key": toAPIKeyResponse(*key),
+		"key":     plainKey,
 	})
 }
+
+// Revoke deactivates one of the tenant's keys without affecting the others
+// DELETE /v1/admin/api-keys/:id
+func (h *APIKeysHandler) Revoke(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals(middleware.LocalTenantID).(uuid.UUID)
+	if !ok {
+		h.logger.Warn("tenant ID not found in context")
+		return fiber.ErrUnauthorized
+	}
+
+	keyID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid API key ID")
+	}
+
+	// Revoking the calling key would lock the caller out mid-rotation
+	if current, ok := c.Locals(middleware.LocalAPIKey).(*domain.APIKey); ok && current.ID == keyID {
+		return fiber.NewError(fiber.StatusConflict, "cannot revoke the API key used for this request")
+	}
+
+	if err := h.apiKeys.RevokeByTenant(c.UserContext(), tenantID, keyID); err != nil {
+		if errors.Is(err, domain.ErrAPIKeyNotFound) {
+			return fiber.NewError(fiber.StatusNotFound, "API key not found")
+		}
+		h.logger.Error("failed to revoke api key", "error", err, "tenant_id", tenantID, "key_id", keyID)
+		return fiber.ErrInternalServerError
+	}
+
+	h.logger.Info("api key revoked", "key_id", keyID, "tenant_id", tenantID)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// toAPIKeyResponse copies only the display fields, never KeyHash
+func toAPIKeyResponse(k domain.APIKey) APIKeyResponse {
+	resp := APIKeyResponse{
+		ID:          k.ID.String(),
+		Name:        k.Name,
+		KeyPrefix:   k.KeyPrefix,
+		Environment: k.Environment,
+		Scopes:      k.Scopes,
+		IsActive:    k.IsActive,
+		CreatedAt:   k.CreatedAt.Format(time.RFC3339),
+	}
+	if resp.Scopes == nil {
+		resp.Scopes = []string{}
+	}
+	if k.LastUsedAt != nil {
+		formatted := k.LastUsedAt.Format(time.RFC3339)
+		resp.LastUsedAt = &formatted
+	}
+	return resp
+}
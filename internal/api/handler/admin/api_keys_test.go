@@ -0,0 +1,381 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+// fakeAPIKeyRepo is an in-memory API key repository shared by the handler and the last used worker
+type fakeAPIKeyRepo struct {
+	mu      sync.Mutex
+	keys    []domain.APIKey
+	listErr error
+}
+
+func (r *fakeAPIKeyRepo) Create(ctx context.Context, key *domain.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys = append(r.keys, *key)
+	return nil
+}
+
+func (r *fakeAPIKeyRepo) GetByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	return nil, domain.ErrAPIKeyNotFound
+}
+
+func (r *fakeAPIKeyRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.APIKey, error) {
+	return nil, domain.ErrAPIKeyNotFound
+}
+
+func (r *fakeAPIKeyRepo) ListByTenant(ctx context.Context, tenantID uuid.UUID) ([]domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.listErr != nil {
+		return nil, r.listErr
+	}
+	var keys []domain.APIKey
+	for _, k := range r.keys {
+		if k.TenantID == tenantID {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+func (r *fakeAPIKeyRepo) UpdateLastUsed(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.keys {
+		if r.keys[i].ID == id {
+			now := time.Now()
+			r.keys[i].LastUsedAt = &now
+			return nil
+		}
+	}
+	return domain.ErrAPIKeyNotFound
+}
+
+func (r *fakeAPIKeyRepo) Revoke(ctx context.Context, id uuid.UUID) error { return nil }
+
+func (r *fakeAPIKeyRepo) RevokeByTenant(ctx context.Context, tenantID, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.keys {
+		if r.keys[i].ID == id && r.keys[i].TenantID == tenantID {
+			r.keys[i].IsActive = false
+			return nil
+		}
+	}
+	return domain.ErrAPIKeyNotFound
+}
+
+func (r *fakeAPIKeyRepo) Delete(ctx context.Context, id uuid.UUID) error { return nil }
+
+type fakePublicKeyGetter struct {
+	publicKey string
+}
+
+func (g *fakePublicKeyGetter) GetPublicKey(ctx context.Context, tenantID uuid.UUID) (string, error) {
+	return g.publicKey, nil
+}
+
+func newTestAPIKey(t *testing.T, tenantID uuid.UUID, name string) (domain.APIKey, string) {
+	t.Helper()
+	plain, hash, prefix, err := domain.GenerateAPIKey(domain.KeyTypeSecret, domain.EnvTest)
+	require.NoError(t, err)
+	return domain.APIKey{
+		ID:          uuid.New(),
+		TenantID:    tenantID,
+		Name:        name,
+		KeyHash:     hash,
+		KeyPrefix:   prefix,
+		Environment: domain.EnvTest,
+		IsActive:    true,
+		CreatedAt:   time.Now(),
+	}, plain
+}
+
+func TestAPIKeysHandler_List(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	tenantID := uuid.New()
+
+	t.Run("returns metadata without secrets", func(t *testing.T) {
+		key, plain := newTestAPIKey(t, tenantID, "backend")
+		otherKey, _ := newTestAPIKey(t, uuid.New(), "other tenant")
+		repo := &fakeAPIKeyRepo{keys: []domain.APIKey{key, otherKey}}
+		handler := NewAPIKeysHandler(repo, &fakePublicKeyGetter{publicKey: "pk_test_abc"}, logger)
+
+		app := setupTestApp(handler.List, tenantID)
+		resp, err := app.Test(httptest.NewRequest("GET", "/test", nil), -1)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.NotContains(t, string(body), plain)
+		assert.NotContains(t, string(body), key.KeyHash)
+		assert.NotContains(t, string(body), "key_hash")
+
+		var result APIKeysResponse
+		require.NoError(t, json.Unmarshal(body, &result))
+		assert.Equal(t, "pk_test_abc", result.PublicKey)
+		require.Len(t, result.Keys, 1)
+		assert.Equal(t, key.ID.String(), result.Keys[0].ID)
+		assert.Equal(t, key.KeyPrefix, result.Keys[0].KeyPrefix)
+		assert.True(t, result.Keys[0].IsActive)
+		assert.Nil(t, result.Keys[0].LastUsedAt)
+	})
+
+	t.Run("last_used_at reflects worker updates", func(t *testing.T) {
+		key, _ := newTestAPIKey(t, tenantID, "backend")
+		repo := &fakeAPIKeyRepo{keys: []domain.APIKey{key}}
+		handler := NewAPIKeysHandler(repo, &fakePublicKeyGetter{}, logger)
+
+		worker := middleware.NewLastUsedWorker(repo, logger, middleware.LastUsedWorkerConfig{
+			BatchInterval: 10 * time.Millisecond,
+		})
+		worker.Start()
+		worker.Enqueue(key.ID)
+		require.Eventually(t, func() bool {
+			repo.mu.Lock()
+			defer repo.mu.Unlock()
+			return repo.keys[0].LastUsedAt != nil
+		}, time.Second, 5*time.Millisecond)
+		worker.Stop()
+
+		app := setupTestApp(handler.List, tenantID)
+		resp, err := app.Test(httptest.NewRequest("GET", "/test", nil), -1)
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var result APIKeysResponse
+		readResponseBody(t, resp, &result)
+		require.Len(t, result.Keys, 1)
+		require.NotNil(t, result.Keys[0].LastUsedAt)
+
+		lastUsed, err := time.Parse(time.RFC3339, *result.Keys[0].LastUsedAt)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), lastUsed, 5*time.Second)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		repo := &fakeAPIKeyRepo{listErr: errors.New("connection refused")}
+		handler := NewAPIKeysHandler(repo, &fakePublicKeyGetter{}, logger)
+
+		app := setupTestApp(handler.List, tenantID)
+		resp, err := app.Test(httptest.NewRequest("GET", "/test", nil), -1)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	})
+
+	t.Run("missing tenant", func(t *testing.T) {
+		handler := NewAPIKeysHandler(&fakeAPIKeyRepo{}, &fakePublicKeyGetter{}, logger)
+
+		app := fiber.New()
+		app.Get("/test", handler.List)
+		resp, err := app.Test(httptest.NewRequest("GET", "/test", nil), -1)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	})
+}
+
+func TestAPIKeysHandler_Create(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	tenantID := uuid.New()
+
+	newApp := func(handler *APIKeysHandler) *fiber.App {
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(middleware.LocalTenantID, tenantID)
+			return c.Next()
+		})
+		app.Post("/api-keys", handler.Create)
+		app.Get("/api-keys", handler.List)
+		return app
+	}
+
+	create := func(t *testing.T, app *fiber.App, body string) *http.Response {
+		t.Helper()
+		req := httptest.NewRequest("POST", "/api-keys", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("multiple labeled keys stay active", func(t *testing.T) {
+		repo := &fakeAPIKeyRepo{}
+		app := newApp(NewAPIKeysHandler(repo, &fakePublicKeyGetter{}, logger))
+
+		var plainKeys []string
+		for _, label := range []string{"mobile app", "gate"} {
+			resp := create(t, app, `{"label": "`+label+`", "environment": "live"}`)
+			require.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+			var result struct {
+				APIKey APIKeyResponse `json:"api_key"`
+				Key    string         `json:"key"`
+			}
+			readResponseBody(t, resp, &result)
+			assert.Equal(t, label, result.APIKey.Name)
+			assert.Equal(t, domain.EnvLive, result.APIKey.Environment)
+			assert.True(t, result.APIKey.IsActive)
+			assert.True(t, domain.IsValidFormat(result.Key))
+			assert.True(t, strings.HasPrefix(result.Key, result.APIKey.KeyPrefix))
+			plainKeys = append(plainKeys, result.Key)
+		}
+
+		require.Len(t, repo.keys, 2)
+		for i, k := range repo.keys {
+			assert.True(t, k.IsActive)
+			assert.Equal(t, tenantID, k.TenantID)
+			assert.Equal(t, domain.HashAPIKey(plainKeys[i]), k.KeyHash)
+		}
+
+		// Plaintext is only returned at creation
+		resp, err := app.Test(httptest.NewRequest("GET", "/api-keys", nil), -1)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		for _, plain := range plainKeys {
+			assert.NotContains(t, string(body), plain)
+		}
+	})
+
+	t.Run("defaults to test environment and the admin scopes", func(t *testing.T) {
+		repo := &fakeAPIKeyRepo{}
+		app := newApp(NewAPIKeysHandler(repo, &fakePublicKeyGetter{}, logger))
+
+		resp := create(t, app, `{"label": "backend"}`)
+		require.Equal(t, fiber.StatusCreated, resp.StatusCode)
+		require.Len(t, repo.keys, 1)
+		assert.Equal(t, domain.EnvTest, repo.keys[0].Environment)
+		assert.Equal(t, []string{domain.ScopeAdminRead, domain.ScopeAdminWrite}, repo.keys[0].Scopes)
+	})
+
+	t.Run("an empty scope list creates a recognition-only key", func(t *testing.T) {
+		repo := &fakeAPIKeyRepo{}
+		app := newApp(NewAPIKeysHandler(repo, &fakePublicKeyGetter{}, logger))
+
+		resp := create(t, app, `{"label": "gate", "scopes": []}`)
+		require.Equal(t, fiber.StatusCreated, resp.StatusCode)
+		require.Len(t, repo.keys, 1)
+		assert.Empty(t, repo.keys[0].Scopes)
+	})
+
+	t.Run("grants requested scopes", func(t *testing.T) {
+		repo := &fakeAPIKeyRepo{}
+		app := newApp(NewAPIKeysHandler(repo, &fakePublicKeyGetter{}, logger))
+
+		resp := create(t, app, `{"label": "backup job", "scopes": ["faces:export"]}`)
+		require.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+		var result struct {
+			APIKey APIKeyResponse `json:"api_key"`
+		}
+		readResponseBody(t, resp, &result)
+		assert.Equal(t, []string{domain.ScopeFacesExport}, result.APIKey.Scopes)
+		require.Len(t, repo.keys, 1)
+		assert.True(t, repo.keys[0].HasScope(domain.ScopeFacesExport))
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		app := newApp(NewAPIKeysHandler(&fakeAPIKeyRepo{}, &fakePublicKeyGetter{}, logger))
+
+		for _, body := range []string{
+			`{"label": "  "}`,
+			`{"label": "` + strings.Repeat("a", 101) + `"}`,
+			`{"label": "gate", "environment": "staging"}`,
+			`{"label": "gate", "scopes": ["faces:delete_all"]}`,
+			`not json`,
+		} {
+			resp := create(t, app, body)
+			assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode, body)
+		}
+	})
+}
+
+func TestAPIKeysHandler_Revoke(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	tenantID := uuid.New()
+
+	mobile, _ := newTestAPIKey(t, tenantID, "mobile app")
+	gate, _ := newTestAPIKey(t, tenantID, "gate")
+	foreign, _ := newTestAPIKey(t, uuid.New(), "other tenant")
+
+	tests := []struct {
+		name       string
+		keyID      string
+		wantStatus int
+		wantActive map[uuid.UUID]bool
+	}{
+		{
+			name:       "revokes one key and keeps the others",
+			keyID:      gate.ID.String(),
+			wantStatus: fiber.StatusNoContent,
+			wantActive: map[uuid.UUID]bool{mobile.ID: true, gate.ID: false, foreign.ID: true},
+		},
+		{
+			name:       "key of another tenant is not found",
+			keyID:      foreign.ID.String(),
+			wantStatus: fiber.StatusNotFound,
+			wantActive: map[uuid.UUID]bool{mobile.ID: true, gate.ID: true, foreign.ID: true},
+		},
+		{
+			name:       "calling key cannot revoke itself",
+			keyID:      mobile.ID.String(),
+			wantStatus: fiber.StatusConflict,
+			wantActive: map[uuid.UUID]bool{mobile.ID: true, gate.ID: true, foreign.ID: true},
+		},
+		{
+			name:       "invalid id",
+			keyID:      "not-a-uuid",
+			wantStatus: fiber.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeAPIKeyRepo{keys: []domain.APIKey{mobile, gate, foreign}}
+			handler := NewAPIKeysHandler(repo, &fakePublicKeyGetter{}, logger)
+
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				current := mobile
+				c.Locals(middleware.LocalTenantID, tenantID)
+				c.Locals(middleware.LocalAPIKey, &current)
+				return c.Next()
+			})
+			app.Delete("/api-keys/:id", handler.Revoke)
+
+			resp, err := app.Test(httptest.NewRequest("DELETE", "/api-keys/"+tt.keyID, nil), -1)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			for _, k := range repo.keys {
+				if want, ok := tt.wantActive[k.ID]; ok {
+					assert.Equal(t, want, k.IsActive, k.Name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+// maxCalibrationImageSize matches the image limit of the face endpoints
+const maxCalibrationImageSize = 10 * 1024 * 1024
+
+// CalibrationEvaluator scores a labeled set of image pairs at a range of thresholds
+type CalibrationEvaluator interface {
+	EvaluateCalibration(ctx context.Context, tenantID uuid.UUID, pairs []domain.CalibrationPair, thresholds []float64, settings domain.TenantSettings) (*domain.CalibrationReport, error)
+}
+
+type CalibrationHandler struct {
+	evaluator CalibrationEvaluator
+	logger    *slog.Logger
+}
+
+func NewCalibrationHandler(evaluator CalibrationEvaluator, logger *slog.Logger) *CalibrationHandler {
+	return &CalibrationHandler{
+		evaluator: evaluator,
+		logger:    logger,
+	}
+}
+
+// Evaluate runs the tenant's provider on labeled image pairs and returns the false accept and
+// false reject rates of a threshold sweep. The multipart body repeats image_a, image_b and match
+// (true/false) once per pair, matched by position; thresholds is an optional comma-separated list.
+// POST /v1/admin/calibration/evaluate
+func (h *CalibrationHandler) Evaluate(c *fiber.Ctx) error {
+	tenant, err := middleware.GetTenant(c)
+	if err != nil {
+		return err
+	}
+	settings := tenant.GetSettings()
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return domain.ErrValidationFailed.WithError(err)
+	}
+
+	pairs, err := calibrationPairs(form, settings.AllowedImageFormats)
+	if err != nil {
+		return err
+	}
+
+	thresholds, err := parseThresholds(c.FormValue("thresholds"))
+	if err != nil {
+		return domain.ErrValidationFailed.WithError(err)
+	}
+
+	report, err := h.evaluator.EvaluateCalibration(c.UserContext(), tenant.ID, pairs, thresholds, settings)
+	if err != nil {
+		var appErr *domain.AppError
+		if errors.As(err, &appErr) {
+			return appErr
+		}
+		h.logger.Error("failed to evaluate calibration set", "error", err, "tenant_id", tenant.ID)
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(report)
+}
+
+// calibrationPairs reads the labeled pairs of the form, matching image_a, image_b and match by position
+func calibrationPairs(form *multipart.Form, allowedFormats []string) ([]domain.CalibrationPair, error) {
+	imagesA := form.File["image_a"]
+	imagesB := form.File["image_b"]
+	labels := form.Value["match"]
+	if len(imagesA) != len(imagesB) || len(imagesA) != len(labels) {
+		return nil, domain.ErrValidationFailed.WithError(fmt.Errorf(
+			"image_a, image_b and match must be repeated once per pair, got %d, %d and %d",
+			len(imagesA), len(imagesB), len(labels)))
+	}
+	if len(imagesA) > domain.MaxCalibrationPairs {
+		return nil, domain.ErrValidationFailed.WithError(fmt.Errorf("at most %d pairs are accepted, got %d", domain.MaxCalibrationPairs, len(imagesA)))
+	}
+
+	pairs := make([]domain.CalibrationPair, len(imagesA))
+	for i := range imagesA {
+		match, err := strconv.ParseBool(labels[i])
+		if err != nil {
+			return nil, domain.ErrValidationFailed.WithError(fmt.Errorf("pair %d: match must be true or false", i))
+		}
+		imageA, err := readCalibrationImage(imagesA[i], allowedFormats)
+		if err != nil {
+			return nil, err
+		}
+		imageB, err := readCalibrationImage(imagesB[i], allowedFormats)
+		if err != nil {
+			return nil, err
+		}
+		pairs[i] = domain.CalibrationPair{ImageA: imageA, ImageB: imageB, Match: match}
+	}
+	return pairs, nil
+}
+
+func readCalibrationImage(file *multipart.FileHeader, allowedFormats []string) ([]byte, error) {
+	if file.Size == 0 || file.Size > maxCalibrationImageSize {
+		return nil, domain.ErrInvalidImage.WithError(nil)
+	}
+
+	if !slices.Contains(allowedFormats, file.Header.Get("Content-Type")) {
+		return nil, &domain.AppError{
+			Code:       domain.ErrInvalidImage.Code,
+			Message:    fmt.Sprintf("Image format not allowed, accepted formats: %s", strings.Join(allowedFormats, ", ")),
+			StatusCode: domain.ErrInvalidImage.StatusCode,
+		}
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return nil, domain.ErrInvalidImage.WithError(err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	image, err := io.ReadAll(f)
+	if err != nil {
+		return nil, domain.ErrInvalidImage.WithError(err)
+	}
+	return image, nil
+}
+
+// parseThresholds reads a comma-separated list of thresholds; empty uses the default sweep
+func parseThresholds(raw string) ([]float64, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(raw, ",")
+	thresholds := make([]float64, 0, len(parts))
+	for _, part := range parts {
+		t, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid threshold %q", part)
+		}
+		thresholds = append(thresholds, t)
+	}
+	return thresholds, nil
+}
@@ -0,0 +1,151 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+type fakeCalibrationEvaluator struct {
+	report *domain.CalibrationReport
+	err    error
+
+	gotPairs      []domain.CalibrationPair
+	gotThresholds []float64
+	gotSettings   domain.TenantSettings
+}
+
+func (f *fakeCalibrationEvaluator) EvaluateCalibration(ctx context.Context, tenantID uuid.UUID, pairs []domain.CalibrationPair, thresholds []float64, settings domain.TenantSettings) (*domain.CalibrationReport, error) {
+	f.gotPairs = pairs
+	f.gotThresholds = thresholds
+	f.gotSettings = settings
+	return f.report, f.err
+}
+
+// calibrationForm is a multipart body with one image_a, image_b and match field per label
+func calibrationForm(t *testing.T, labels []string, thresholds string) (*bytes.Buffer, string) {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for i, label := range labels {
+		for _, field := range []string{"image_a", "image_b"} {
+			h := make(textproto.MIMEHeader)
+			h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%d.jpg"`, field, i))
+			h.Set("Content-Type", "image/jpeg")
+			part, err := writer.CreatePart(h)
+			require.NoError(t, err)
+			_, _ = part.Write([]byte(fmt.Sprintf("%s-%d", field, i)))
+		}
+		require.NoError(t, writer.WriteField("match", label))
+	}
+	if thresholds != "" {
+		require.NoError(t, writer.WriteField("thresholds", thresholds))
+	}
+	require.NoError(t, writer.Close())
+
+	return body, writer.FormDataContentType()
+}
+
+func TestCalibrationHandler_Evaluate(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tenant := &domain.Tenant{ID: uuid.New(), Settings: map[string]interface{}{"verification_threshold": 0.85}}
+
+	newApp := func(h *CalibrationHandler) *fiber.App {
+		app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler(logger)})
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(middleware.LocalTenantID, tenant.ID)
+			c.Locals(middleware.LocalTenant, tenant)
+			return c.Next()
+		})
+		app.Post("/v1/admin/calibration/evaluate", h.Evaluate)
+		return app
+	}
+
+	post := func(app *fiber.App, body *bytes.Buffer, contentType string) *http.Response {
+		req := httptest.NewRequest(http.MethodPost, "/v1/admin/calibration/evaluate", body)
+		req.Header.Set("Content-Type", contentType)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("passes the labeled pairs in order and returns the report", func(t *testing.T) {
+		evaluator := &fakeCalibrationEvaluator{report: &domain.CalibrationReport{
+			Pairs:     2,
+			Evaluated: 2,
+			Sweep:     []domain.ThresholdRates{{Threshold: 0.8, FalseAcceptRate: 0.5}},
+		}}
+		body, contentType := calibrationForm(t, []string{"true", "false"}, "0.8, 0.9")
+
+		resp := post(newApp(NewCalibrationHandler(evaluator, logger)), body, contentType)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var report domain.CalibrationReport
+		readResponseBody(t, resp, &report)
+		assert.Equal(t, 2, report.Evaluated)
+		require.Len(t, report.Sweep, 1)
+		assert.Equal(t, 0.5, report.Sweep[0].FalseAcceptRate)
+
+		require.Len(t, evaluator.gotPairs, 2)
+		assert.Equal(t, []byte("image_a-0"), evaluator.gotPairs[0].ImageA)
+		assert.Equal(t, []byte("image_b-1"), evaluator.gotPairs[1].ImageB)
+		assert.True(t, evaluator.gotPairs[0].Match)
+		assert.False(t, evaluator.gotPairs[1].Match)
+		assert.Equal(t, []float64{0.8, 0.9}, evaluator.gotThresholds)
+		assert.Equal(t, 0.85, evaluator.gotSettings.VerificationThreshold)
+	})
+
+	t.Run("rejects a label without images", func(t *testing.T) {
+		evaluator := &fakeCalibrationEvaluator{}
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		require.NoError(t, writer.WriteField("match", "true"))
+		require.NoError(t, writer.Close())
+
+		resp := post(newApp(NewCalibrationHandler(evaluator, logger)), body, writer.FormDataContentType())
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+		assert.Nil(t, evaluator.gotPairs)
+	})
+
+	t.Run("rejects an invalid label", func(t *testing.T) {
+		evaluator := &fakeCalibrationEvaluator{}
+		body, contentType := calibrationForm(t, []string{"maybe"}, "")
+
+		resp := post(newApp(NewCalibrationHandler(evaluator, logger)), body, contentType)
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+		assert.Nil(t, evaluator.gotPairs)
+	})
+
+	t.Run("rejects an invalid threshold", func(t *testing.T) {
+		evaluator := &fakeCalibrationEvaluator{}
+		body, contentType := calibrationForm(t, []string{"true"}, "high")
+
+		resp := post(newApp(NewCalibrationHandler(evaluator, logger)), body, contentType)
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+		assert.Nil(t, evaluator.gotPairs)
+	})
+
+	t.Run("returns validation errors of the evaluation", func(t *testing.T) {
+		evaluator := &fakeCalibrationEvaluator{err: domain.ErrValidationFailed.WithError(fmt.Errorf("threshold 1.5 must be between 0 and 1"))}
+		body, contentType := calibrationForm(t, []string{"true"}, "1.5")
+
+		resp := post(newApp(NewCalibrationHandler(evaluator, logger)), body, contentType)
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	})
+}
@@ -0,0 +1,56 @@
+package admin
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+// FaceRecounter recomputes a tenant's face count
+type FaceRecounter interface {
+	RecountFaces(ctx context.Context, tenantID uuid.UUID) (*domain.FaceRecount, error)
+}
+
+type FaceRecountHandler struct {
+	faces  FaceRecounter
+	logger *slog.Logger
+}
+
+func NewFaceRecountHandler(faces FaceRecounter, logger *slog.Logger) *FaceRecountHandler {
+	return &FaceRecountHandler{
+		faces:  faces,
+		logger: logger,
+	}
+}
+
+// Recount recomputes the tenant's face count, refreshes the cached count and reports the
+// database count next to the provider collection count
+// POST /v1/admin/faces/recount
+func (h *FaceRecountHandler) Recount(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals(middleware.LocalTenantID).(uuid.UUID)
+	if !ok {
+		h.logger.Warn("tenant ID not found in context")
+		return fiber.ErrUnauthorized
+	}
+
+	recount, err := h.faces.RecountFaces(c.UserContext(), tenantID)
+	if err != nil {
+		h.logger.Error("failed to recount faces", "error", err, "tenant_id", tenantID)
+		return fiber.ErrInternalServerError
+	}
+
+	if recount.Delta != nil && *recount.Delta != 0 {
+		h.logger.Warn("face count differs between database and provider",
+			"tenant_id", tenantID,
+			"database_count", recount.DatabaseCount,
+			"provider_count", *recount.ProviderCount,
+		)
+	}
+
+	return c.JSON(recount)
+}
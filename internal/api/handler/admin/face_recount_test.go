@@ -0,0 +1,103 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+type fakeFaceRecounter struct {
+	result *domain.FaceRecount
+	err    error
+
+	gotTenant uuid.UUID
+}
+
+func (f *fakeFaceRecounter) RecountFaces(ctx context.Context, tenantID uuid.UUID) (*domain.FaceRecount, error) {
+	f.gotTenant = tenantID
+	return f.result, f.err
+}
+
+func TestFaceRecountHandler_Recount(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tenantID := uuid.New()
+
+	newApp := func(h *FaceRecountHandler) *fiber.App {
+		app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler(logger)})
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(middleware.LocalTenantID, tenantID)
+			return c.Next()
+		})
+		app.Post("/v1/admin/faces/recount", h.Recount)
+		return app
+	}
+
+	t.Run("returns database and provider counts with the delta", func(t *testing.T) {
+		providerCount, delta, previous := int64(118), int64(-2), 95
+		recounter := &fakeFaceRecounter{result: &domain.FaceRecount{
+			DatabaseCount:       120,
+			ProviderCount:       &providerCount,
+			Delta:               &delta,
+			PreviousCachedCount: &previous,
+			CacheRefreshed:      true,
+			RecountedAt:         time.Now(),
+		}}
+		app := newApp(NewFaceRecountHandler(recounter, logger))
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/v1/admin/faces/recount", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body map[string]interface{}
+		readResponseBody(t, resp, &body)
+		assert.Equal(t, float64(120), body["database_count"])
+		assert.Equal(t, float64(118), body["provider_count"])
+		assert.Equal(t, float64(-2), body["delta"])
+		assert.Equal(t, float64(95), body["previous_cached_count"])
+		assert.Equal(t, true, body["cache_refreshed"])
+		assert.Equal(t, tenantID, recounter.gotTenant)
+	})
+
+	t.Run("provider counts are null without a provider collection", func(t *testing.T) {
+		app := newApp(NewFaceRecountHandler(&fakeFaceRecounter{result: &domain.FaceRecount{DatabaseCount: 3}}, logger))
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/v1/admin/faces/recount", nil))
+		require.NoError(t, err)
+
+		var body map[string]interface{}
+		readResponseBody(t, resp, &body)
+		assert.Contains(t, body, "provider_count")
+		assert.Nil(t, body["provider_count"])
+		assert.Nil(t, body["delta"])
+	})
+
+	t.Run("count failure", func(t *testing.T) {
+		app := newApp(NewFaceRecountHandler(&fakeFaceRecounter{err: errors.New("connection refused")}, logger))
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/v1/admin/faces/recount", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	})
+
+	t.Run("no tenant in context", func(t *testing.T) {
+		app := fiber.New()
+		app.Post("/test", NewFaceRecountHandler(&fakeFaceRecounter{}, logger).Recount)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/test", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
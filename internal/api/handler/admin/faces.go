@@ -0,0 +1,135 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+// FaceComparer compares the stored faces of two identities
+type FaceComparer interface {
+	Compare(ctx context.Context, tenantID uuid.UUID, externalIDA, externalIDB string, settings domain.TenantSettings) (*domain.FaceComparison, error)
+}
+
+// FaceGetter looks up a registered face
+type FaceGetter interface {
+	GetByExternalID(ctx context.Context, tenantID uuid.UUID, externalID string) (*domain.Face, error)
+}
+
+// FaceInspector is the face service used by the admin face endpoints
+type FaceInspector interface {
+	FaceComparer
+	FaceGetter
+}
+
+type FacesHandler struct {
+	faces  FaceInspector
+	logger *slog.Logger
+}
+
+// AdminFaceResponse is a registered face as seen by admins. Unlike the public face response it
+// includes the provider face ID, to cross-reference the face with the provider console.
+type AdminFaceResponse struct {
+	FaceID           string                 `json:"face_id"`
+	ExternalID       string                 `json:"external_id"`
+	ProviderFaceID   *string                `json:"provider_face_id"`
+	EmbeddingHash    *string                `json:"embedding_hash"`
+	EmbeddingModel   string                 `json:"embedding_model,omitempty"`
+	EmbeddingVersion string                 `json:"embedding_version,omitempty"`
+	Metadata         map[string]interface{} `json:"metadata,omitempty"`
+	QualityScore     float64                `json:"quality_score"`
+	IsTest           bool                   `json:"is_test"`
+	CreatedAt        time.Time              `json:"created_at"`
+	UpdatedAt        time.Time              `json:"updated_at"`
+	ExpiresAt        *time.Time             `json:"expires_at,omitempty"`
+}
+
+func NewFacesHandler(faces FaceInspector, logger *slog.Logger) *FacesHandler {
+	return &FacesHandler{
+		faces:  faces,
+		logger: logger,
+	}
+}
+
+// Compare returns how similar the registered faces of two external_ids are,
+// so fraud teams can spot one person behind several accounts
+// GET /v1/admin/faces/compare?a=&b=
+func (h *FacesHandler) Compare(c *fiber.Ctx) error {
+	tenant, err := middleware.GetTenant(c)
+	if err != nil {
+		h.logger.Warn("tenant not found in context")
+		return fiber.ErrUnauthorized
+	}
+
+	comparison, err := h.faces.Compare(c.UserContext(), tenant.ID, c.Query("a"), c.Query("b"), tenant.GetSettings())
+	if err != nil {
+		var appErr *domain.AppError
+		if errors.As(err, &appErr) {
+			return appErr
+		}
+		h.logger.Error("failed to compare faces", "error", err, "tenant_id", tenant.ID)
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(comparison)
+}
+
+// Get returns a registered face with its provider face ID, null when the provider assigned none,
+// and its embedding hash, null for faces registered before hashes were stored
+// GET /v1/admin/faces/:external_id
+func (h *FacesHandler) Get(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals(middleware.LocalTenantID).(uuid.UUID)
+	if !ok {
+		h.logger.Warn("tenant ID not found in context")
+		return fiber.ErrUnauthorized
+	}
+
+	externalID := strings.TrimSpace(c.Params("external_id"))
+	if externalID == "" {
+		return domain.ErrValidationFailed.WithError(errors.New("external_id is required"))
+	}
+
+	face, err := h.faces.GetByExternalID(c.UserContext(), tenantID, externalID)
+	if err != nil {
+		var appErr *domain.AppError
+		if errors.As(err, &appErr) {
+			return appErr
+		}
+		h.logger.Error("failed to get face", "error", err, "tenant_id", tenantID)
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(adminFaceResponse(face))
+}
+
+// adminFaceResponse formats a face for admins, with null provider face ID and embedding hash
+// when the face has none
+func adminFaceResponse(face *domain.Face) AdminFaceResponse {
+	response := AdminFaceResponse{
+		FaceID:           face.ID.String(),
+		ExternalID:       face.ExternalID,
+		EmbeddingModel:   face.EmbeddingModel,
+		EmbeddingVersion: face.EmbeddingVersion,
+		Metadata:         face.Metadata,
+		QualityScore:     face.QualityScore,
+		IsTest:           face.IsTest,
+		CreatedAt:        face.CreatedAt,
+		UpdatedAt:        face.UpdatedAt,
+		ExpiresAt:        face.ExpiresAt,
+	}
+	if face.ProviderFaceID != "" {
+		response.ProviderFaceID = &face.ProviderFaceID
+	}
+	if face.EmbeddingHash != "" {
+		response.EmbeddingHash = &face.EmbeddingHash
+	}
+	return response
+}
@@ -0,0 +1,107 @@
+package admin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/valyala/fasthttp"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+// faceExportTimeout bounds the database reads of one export
+const faceExportTimeout = 30 * time.Minute
+
+// FaceStreamer reads all of a tenant's faces in registration order without loading them all
+type FaceStreamer interface {
+	StreamAll(ctx context.Context, tenantID uuid.UUID, isTest, includeEmbeddings bool, fn func(*domain.Face) error) error
+}
+
+// FaceExportRecord is one line of the faces export
+type FaceExportRecord struct {
+	AdminFaceResponse
+	Embedding []float64 `json:"embedding,omitempty"`
+}
+
+type FacesExportHandler struct {
+	faces  FaceStreamer
+	logger *slog.Logger
+}
+
+func NewFacesExportHandler(faces FaceStreamer, logger *slog.Logger) *FacesExportHandler {
+	return &FacesExportHandler{
+		faces:  faces,
+		logger: logger,
+	}
+}
+
+// Export streams every face of the authenticated tenant as NDJSON, one face per line, oldest
+// first, for backups and migrations. Embeddings are included with include_embeddings=true.
+// A test key exports test faces and a live key live ones. Tenants must enable face_export
+// and the route requires the faces:export scope. Lines are written as they are read, so a database error
+// after the first page truncates the export instead of failing it.
+// GET /v1/admin/faces/export?include_embeddings=
+func (h *FacesExportHandler) Export(c *fiber.Ctx) error {
+	tenant, ok := c.Locals(middleware.LocalTenant).(*domain.Tenant)
+	if !ok {
+		h.logger.Warn("tenant not found in context")
+		return fiber.ErrUnauthorized
+	}
+
+	if !tenant.GetSettings().Features().FaceExport {
+		return domain.ErrFaceExportNotEnabled
+	}
+
+	includeEmbeddings := false
+	if raw := c.Query("include_embeddings"); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return domain.ErrValidationFailed.WithError(fmt.Errorf("invalid include_embeddings %q, expected true or false", raw))
+		}
+		includeEmbeddings = v
+	}
+
+	tenantID := tenant.ID
+	// The stream writer outlives the request context, so the environment is read now
+	isTest := domain.IsTestMode(c.UserContext())
+	c.Attachment(fmt.Sprintf("faces_%s.ndjson", time.Now().UTC().Format("20060102T150405Z")))
+	c.Set(fiber.HeaderContentType, "application/x-ndjson")
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	// The writer runs after the handler returns, so it must not touch c
+	c.Context().SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
+		ctx, cancel := context.WithTimeout(context.Background(), faceExportTimeout)
+		defer cancel()
+
+		if err := h.writeNDJSON(ctx, w, tenantID, isTest, includeEmbeddings); err != nil {
+			h.logger.Error("face export interrupted", "error", err, "tenant_id", tenantID)
+		}
+	}))
+
+	return nil
+}
+
+// writeNDJSON writes one JSON line per face, flushing as the buffer fills
+func (h *FacesExportHandler) writeNDJSON(ctx context.Context, w *bufio.Writer, tenantID uuid.UUID, isTest, includeEmbeddings bool) error {
+	enc := json.NewEncoder(w)
+	err := h.faces.StreamAll(ctx, tenantID, isTest, includeEmbeddings, func(face *domain.Face) error {
+		record := FaceExportRecord{AdminFaceResponse: adminFaceResponse(face)}
+		if includeEmbeddings {
+			record.Embedding = face.Embedding
+		}
+		return enc.Encode(record)
+	})
+	// Lines written before an error still reach the client
+	if flushErr := w.Flush(); err == nil {
+		err = flushErr
+	}
+	return err
+}
@@ -0,0 +1,184 @@
+package admin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+type fakeFaceStreamer struct {
+	faces []*domain.Face
+	// failAfter makes the stream fail once that many faces were sent; negative never fails
+	failAfter int
+
+	called               bool
+	gotIsTest            bool
+	gotIncludeEmbeddings bool
+}
+
+func (f *fakeFaceStreamer) StreamAll(ctx context.Context, tenantID uuid.UUID, isTest, includeEmbeddings bool, fn func(*domain.Face) error) error {
+	f.called, f.gotIsTest, f.gotIncludeEmbeddings = true, isTest, includeEmbeddings
+	for i, face := range f.faces {
+		if i == f.failAfter {
+			return errors.New("connection reset")
+		}
+		// The repository only reads embeddings when asked to
+		if !includeEmbeddings {
+			stripped := *face
+			stripped.Embedding = nil
+			face = &stripped
+		}
+		if err := fn(face); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readExportLines decodes every NDJSON line of an export response
+func readExportLines(t *testing.T, body io.Reader) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.NoError(t, scanner.Err())
+	return lines
+}
+
+func TestFacesExportHandler_Export(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	enabled := map[string]interface{}{"face_export_enabled": true}
+
+	newApp := func(h *FacesExportHandler, settings map[string]interface{}) *fiber.App {
+		app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler(logger)})
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(middleware.LocalTenant, &domain.Tenant{ID: uuid.New(), Settings: settings})
+			return c.Next()
+		})
+		app.Get("/v1/admin/faces/export", h.Export)
+		return app
+	}
+
+	createdAt := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	faces := []*domain.Face{
+		{ID: uuid.New(), ExternalID: "user_001", Embedding: []float64{0.1, 0.2}, Metadata: map[string]interface{}{"name": "Ana"},
+			QualityScore: 0.93, ProviderFaceID: "rk-1", EmbeddingHash: "abc", CreatedAt: createdAt, UpdatedAt: createdAt},
+		{ID: uuid.New(), ExternalID: "user_002", Embedding: []float64{0.3, 0.4}, QualityScore: 0.88,
+			CreatedAt: createdAt.Add(time.Second), UpdatedAt: createdAt.Add(time.Second)},
+	}
+
+	t.Run("streams one line per face without embeddings", func(t *testing.T) {
+		streamer := &fakeFaceStreamer{faces: faces, failAfter: -1}
+		app := newApp(NewFacesExportHandler(streamer, logger), enabled)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/admin/faces/export", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/x-ndjson", resp.Header.Get(fiber.HeaderContentType))
+		assert.Contains(t, resp.Header.Get(fiber.HeaderContentDisposition), `.ndjson"`)
+		assert.False(t, streamer.gotIncludeEmbeddings)
+		assert.False(t, streamer.gotIsTest, "a live key exports live faces")
+
+		lines := readExportLines(t, resp.Body)
+		require.Len(t, lines, 2)
+		assert.Equal(t, "user_001", lines[0]["external_id"])
+		assert.Equal(t, "rk-1", lines[0]["provider_face_id"])
+		assert.Equal(t, 0.93, lines[0]["quality_score"])
+		assert.Equal(t, map[string]interface{}{"name": "Ana"}, lines[0]["metadata"])
+		assert.NotContains(t, lines[0], "embedding")
+		assert.Equal(t, "user_002", lines[1]["external_id"])
+		assert.Nil(t, lines[1]["provider_face_id"])
+	})
+
+	t.Run("includes embeddings on request", func(t *testing.T) {
+		streamer := &fakeFaceStreamer{faces: faces, failAfter: -1}
+		app := newApp(NewFacesExportHandler(streamer, logger), enabled)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/admin/faces/export?include_embeddings=true", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.True(t, streamer.gotIncludeEmbeddings)
+
+		lines := readExportLines(t, resp.Body)
+		require.Len(t, lines, 2)
+		assert.Equal(t, []interface{}{0.1, 0.2}, lines[0]["embedding"])
+	})
+
+	t.Run("test key exports test faces", func(t *testing.T) {
+		streamer := &fakeFaceStreamer{failAfter: -1}
+		app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler(logger)})
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(middleware.LocalTenant, &domain.Tenant{ID: uuid.New(), Settings: enabled})
+			c.SetUserContext(domain.WithTestMode(c.UserContext()))
+			return c.Next()
+		})
+		app.Get("/v1/admin/faces/export", NewFacesExportHandler(streamer, logger).Export)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/admin/faces/export", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		_, _ = io.ReadAll(resp.Body)
+		assert.True(t, streamer.gotIsTest)
+	})
+
+	t.Run("stream failure truncates the export", func(t *testing.T) {
+		streamer := &fakeFaceStreamer{faces: faces, failAfter: 1}
+		app := newApp(NewFacesExportHandler(streamer, logger), enabled)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/admin/faces/export", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		lines := readExportLines(t, resp.Body)
+		require.Len(t, lines, 1)
+		assert.Equal(t, "user_001", lines[0]["external_id"])
+	})
+
+	t.Run("tenant without face_export is refused", func(t *testing.T) {
+		streamer := &fakeFaceStreamer{faces: faces, failAfter: -1}
+		app := newApp(NewFacesExportHandler(streamer, logger), nil)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/admin/faces/export", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.False(t, streamer.called)
+	})
+
+	t.Run("malformed include_embeddings", func(t *testing.T) {
+		streamer := &fakeFaceStreamer{faces: faces, failAfter: -1}
+		app := newApp(NewFacesExportHandler(streamer, logger), enabled)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/admin/faces/export?include_embeddings=maybe", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+		assert.False(t, streamer.called)
+	})
+
+	t.Run("no tenant in context", func(t *testing.T) {
+		app := fiber.New()
+		app.Get("/test", NewFacesExportHandler(&fakeFaceStreamer{failAfter: -1}, logger).Export)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/test", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
@@ -0,0 +1,186 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+type fakeFaceComparer struct {
+	result *domain.FaceComparison
+	face   *domain.Face
+	err    error
+
+	gotTenant     uuid.UUID
+	gotA          string
+	gotB          string
+	gotSettings   domain.TenantSettings
+	gotExternalID string
+}
+
+func (f *fakeFaceComparer) Compare(ctx context.Context, tenantID uuid.UUID, externalIDA, externalIDB string, settings domain.TenantSettings) (*domain.FaceComparison, error) {
+	f.gotTenant, f.gotA, f.gotB, f.gotSettings = tenantID, externalIDA, externalIDB, settings
+	return f.result, f.err
+}
+
+func (f *fakeFaceComparer) GetByExternalID(ctx context.Context, tenantID uuid.UUID, externalID string) (*domain.Face, error) {
+	f.gotTenant, f.gotExternalID = tenantID, externalID
+	return f.face, f.err
+}
+
+func TestFacesHandler_Compare(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tenantID := uuid.New()
+	tenant := &domain.Tenant{ID: tenantID, Settings: map[string]interface{}{"verification_threshold": 0.9}}
+
+	newApp := func(h *FacesHandler) *fiber.App {
+		app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler(logger)})
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(middleware.LocalTenant, tenant)
+			return c.Next()
+		})
+		app.Get("/v1/admin/faces/compare", h.Compare)
+		return app
+	}
+
+	t.Run("returns similarity", func(t *testing.T) {
+		comparer := &fakeFaceComparer{result: &domain.FaceComparison{
+			ExternalIDA: "user_a",
+			ExternalIDB: "user_b",
+			Similarity:  0.94,
+			Threshold:   0.8,
+			Match:       true,
+		}}
+		app := newApp(NewFacesHandler(comparer, logger))
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/admin/faces/compare?a=user_a&b=user_b", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body domain.FaceComparison
+		readResponseBody(t, resp, &body)
+		assert.Equal(t, 0.94, body.Similarity)
+		assert.True(t, body.Match)
+		assert.Equal(t, tenantID, comparer.gotTenant)
+		assert.Equal(t, "user_a", comparer.gotA)
+		assert.Equal(t, "user_b", comparer.gotB)
+		assert.Equal(t, 0.9, comparer.gotSettings.VerificationThreshold)
+	})
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"face not found", domain.ErrFaceNotFound, http.StatusNotFound},
+		{"missing embedding", domain.ErrEmbeddingUnavailable, http.StatusConflict},
+		{"invalid ids", domain.ErrValidationFailed.WithError(errors.New("both external_ids are required")), http.StatusUnprocessableEntity},
+		{"provider failure", errors.New("deepface unavailable"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newApp(NewFacesHandler(&fakeFaceComparer{err: tt.err}, logger))
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/admin/faces/compare?a=user_a&b=user_b", nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+
+	t.Run("no tenant in context", func(t *testing.T) {
+		app := fiber.New()
+		app.Get("/test", NewFacesHandler(&fakeFaceComparer{}, logger).Compare)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/test?a=x&b=y", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
+
+func TestFacesHandler_Get(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tenantID := uuid.New()
+
+	newApp := func(h *FacesHandler) *fiber.App {
+		app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler(logger)})
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(middleware.LocalTenantID, tenantID)
+			return c.Next()
+		})
+		app.Get("/v1/admin/faces/:external_id", h.Get)
+		return app
+	}
+
+	t.Run("includes the provider face id", func(t *testing.T) {
+		faces := &fakeFaceComparer{face: &domain.Face{
+			ID:             uuid.New(),
+			TenantID:       tenantID,
+			ExternalID:     "user_a",
+			QualityScore:   0.91,
+			ProviderFaceID: "0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0",
+			EmbeddingHash:  "3f79bb7b435b05321651daefd374cdc681dc06faa65e374e38337b88ca046dea",
+		}}
+		app := newApp(NewFacesHandler(faces, logger))
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/admin/faces/user_a", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body AdminFaceResponse
+		readResponseBody(t, resp, &body)
+		assert.Equal(t, "user_a", body.ExternalID)
+		require.NotNil(t, body.ProviderFaceID)
+		assert.Equal(t, "0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0", *body.ProviderFaceID)
+		require.NotNil(t, body.EmbeddingHash)
+		assert.Equal(t, "3f79bb7b435b05321651daefd374cdc681dc06faa65e374e38337b88ca046dea", *body.EmbeddingHash)
+		assert.Equal(t, tenantID, faces.gotTenant)
+		assert.Equal(t, "user_a", faces.gotExternalID)
+	})
+
+	t.Run("provider face id is null when none was stored", func(t *testing.T) {
+		app := newApp(NewFacesHandler(&fakeFaceComparer{face: &domain.Face{ID: uuid.New(), ExternalID: "user_a"}}, logger))
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/admin/faces/user_a", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body map[string]interface{}
+		readResponseBody(t, resp, &body)
+		assert.Contains(t, body, "provider_face_id")
+		assert.Nil(t, body["provider_face_id"])
+		assert.Contains(t, body, "embedding_hash")
+		assert.Nil(t, body["embedding_hash"])
+	})
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"face not found", domain.ErrFaceNotFound, http.StatusNotFound},
+		{"database failure", errors.New("connection reset"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newApp(NewFacesHandler(&fakeFaceComparer{err: tt.err}, logger))
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/admin/faces/user_a", nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
@@ -39,7 +39,7 @@ func (h *MetricsPerformanceHandler) GetLatencyMetrics(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
-	metrics, err := h.adminService.GetLatencyMetrics(c.Context(), tenantID, params)
+	metrics, err := h.adminService.GetLatencyMetrics(c.UserContext(), tenantID, params)
 	if err != nil {
 		h.logger.Error("failed to get latency metrics", "error", err, "tenant_id", tenantID)
 		return fiber.ErrInternalServerError
@@ -74,7 +74,7 @@ func (h *MetricsPerformanceHandler) GetThroughputMetrics(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
-	metrics, err := h.adminService.GetThroughputMetrics(c.Context(), tenantID, params)
+	metrics, err := h.adminService.GetThroughputMetrics(c.UserContext(), tenantID, params)
 	if err != nil {
 		h.logger.Error("failed to get throughput metrics", "error", err, "tenant_id", tenantID)
 		return fiber.ErrInternalServerError
@@ -109,7 +109,7 @@ func (h *MetricsPerformanceHandler) GetErrorMetrics(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
-	metrics, err := h.adminService.GetErrorMetrics(c.Context(), tenantID, params)
+	metrics, err := h.adminService.GetErrorMetrics(c.UserContext(), tenantID, params)
 	if err != nil {
 		h.logger.Error("failed to get error metrics", "error", err, "tenant_id", tenantID)
 		return fiber.ErrInternalServerError
@@ -173,11 +173,18 @@ func parseMetricsParams(c *fiber.Ctx) (admin.MetricsParams, error) {
 		return admin.MetricsParams{}, fiber.NewError(fiber.StatusBadRequest, "start_date must be before or equal to end_date")
 	}
 
+	groupBy := c.Query("group_by")
+	if groupBy != "" && groupBy != admin.GroupByProvider {
+		return admin.MetricsParams{}, fiber.NewError(fiber.StatusBadRequest, "invalid group_by, expected provider")
+	}
+
 	return admin.MetricsParams{
-		StartDate: start,
-		EndDate:   end,
-		Interval:  interval,
-		Limit:     limit,
-		Offset:    offset,
+		StartDate:   start,
+		EndDate:     end,
+		Interval:    interval,
+		Limit:       limit,
+		Offset:      offset,
+		ExcludeTest: c.QueryBool("exclude_test", false),
+		GroupBy:     groupBy,
 	}, nil
 }
@@ -39,7 +39,7 @@ func (h *MetricsQualityHandler) GetQualityMetrics(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
-	metrics, err := h.adminService.GetQualityMetrics(c.Context(), tenantID, params)
+	metrics, err := h.adminService.GetQualityMetrics(c.UserContext(), tenantID, params)
 	if err != nil {
 		h.logger.Error("failed to get quality metrics", "error", err, "tenant_id", tenantID)
 		return fiber.ErrInternalServerError
@@ -60,6 +60,71 @@ func (h *MetricsQualityHandler) GetQualityMetrics(c *fiber.Ctx) error {
 	})
 }
 
+// GetLivenessMetrics handles GET /v1/admin/metrics/liveness
+func (h *MetricsQualityHandler) GetLivenessMetrics(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals(middleware.LocalTenantID).(uuid.UUID)
+	if !ok {
+		h.logger.Warn("tenant ID not found in context")
+		return fiber.ErrUnauthorized
+	}
+
+	params, err := parseMetricsParams(c)
+	if err != nil {
+		h.logger.Debug("invalid metrics params", "error", err, "tenant_id", tenantID)
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	metrics, err := h.adminService.GetLivenessMetrics(c.UserContext(), tenantID, params)
+	if err != nil {
+		h.logger.Error("failed to get liveness metrics", "error", err, "tenant_id", tenantID)
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(admin.MetricsResponse{
+		Data: metrics,
+		Meta: admin.ResponseMeta{
+			TenantID:    tenantID.String(),
+			Period:      admin.Period{Start: params.StartDate.Format("2006-01-02"), End: params.EndDate.Format("2006-01-02")},
+			GeneratedAt: time.Now(),
+		},
+		Pagination: &admin.PaginationMeta{
+			Total:  len(metrics.Timeline),
+			Limit:  params.Limit,
+			Offset: params.Offset,
+		},
+	})
+}
+
+// GetGateMetrics handles GET /v1/admin/metrics/by-gate
+func (h *MetricsQualityHandler) GetGateMetrics(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals(middleware.LocalTenantID).(uuid.UUID)
+	if !ok {
+		h.logger.Warn("tenant ID not found in context")
+		return fiber.ErrUnauthorized
+	}
+
+	params, err := parseMetricsParams(c)
+	if err != nil {
+		h.logger.Debug("invalid metrics params", "error", err, "tenant_id", tenantID)
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	metrics, err := h.adminService.GetGateMetrics(c.UserContext(), tenantID, params)
+	if err != nil {
+		h.logger.Error("failed to get gate metrics", "error", err, "tenant_id", tenantID)
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(admin.MetricsResponse{
+		Data: metrics,
+		Meta: admin.ResponseMeta{
+			TenantID:    tenantID.String(),
+			Period:      admin.Period{Start: params.StartDate.Format("2006-01-02"), End: params.EndDate.Format("2006-01-02")},
+			GeneratedAt: time.Now(),
+		},
+	})
+}
+
 // GetConfidenceMetrics handles GET /v1/admin/metrics/confidence
 func (h *MetricsQualityHandler) GetConfidenceMetrics(c *fiber.Ctx) error {
 	tenantID, ok := c.Locals(middleware.LocalTenantID).(uuid.UUID)
@@ -74,7 +139,7 @@ func (h *MetricsQualityHandler) GetConfidenceMetrics(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
-	metrics, err := h.adminService.GetConfidenceMetrics(c.Context(), tenantID, params)
+	metrics, err := h.adminService.GetConfidenceMetrics(c.UserContext(), tenantID, params)
 	if err != nil {
 		h.logger.Error("failed to get confidence metrics", "error", err, "tenant_id", tenantID)
 		return fiber.ErrInternalServerError
@@ -109,7 +174,7 @@ func (h *MetricsQualityHandler) GetMatchMetrics(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
-	metrics, err := h.adminService.GetMatchMetrics(c.Context(), tenantID, params)
+	metrics, err := h.adminService.GetMatchMetrics(c.UserContext(), tenantID, params)
 	if err != nil {
 		h.logger.Error("failed to get match metrics", "error", err, "tenant_id", tenantID)
 		return fiber.ErrInternalServerError
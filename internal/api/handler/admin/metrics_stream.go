@@ -0,0 +1,111 @@
+package admin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/valyala/fasthttp"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/admin"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+)
+
+// Stream refresh interval bounds, in seconds
+const (
+	defaultStreamIntervalSeconds = 5
+	minStreamIntervalSeconds     = 1
+	maxStreamIntervalSeconds     = 60
+)
+
+// MetricsOverviewReader reads a tenant's headline metrics
+type MetricsOverviewReader interface {
+	GetMetricsOverview(ctx context.Context, tenantID uuid.UUID) (*admin.MetricsOverview, error)
+}
+
+type MetricsStreamHandler struct {
+	overviews MetricsOverviewReader
+	logger    *slog.Logger
+}
+
+func NewMetricsStreamHandler(overviews MetricsOverviewReader, logger *slog.Logger) *MetricsStreamHandler {
+	return &MetricsStreamHandler{
+		overviews: overviews,
+		logger:    logger,
+	}
+}
+
+// Stream pushes the headline metrics as server-sent events so live dashboards
+// don't have to poll the metrics endpoints
+// GET /v1/admin/metrics/stream?interval_seconds=5
+func (h *MetricsStreamHandler) Stream(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals(middleware.LocalTenantID).(uuid.UUID)
+	if !ok {
+		h.logger.Warn("tenant ID not found in context")
+		return fiber.ErrUnauthorized
+	}
+
+	seconds := defaultStreamIntervalSeconds
+	if raw := c.Query("interval_seconds"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < minStreamIntervalSeconds || v > maxStreamIntervalSeconds {
+			return fiber.NewError(fiber.StatusBadRequest,
+				fmt.Sprintf("interval_seconds must be between %d and %d", minStreamIntervalSeconds, maxStreamIntervalSeconds))
+		}
+		seconds = v
+	}
+	interval := time.Duration(seconds) * time.Second
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	// The writer runs after the handler returns, so it must not touch c
+	c.Context().SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := h.writeOverview(w, tenantID, interval); err != nil {
+				// A failed flush means the client went away
+				h.logger.Debug("metrics stream closed", "tenant_id", tenantID, "reason", err)
+				return
+			}
+			<-ticker.C
+		}
+	}))
+
+	return nil
+}
+
+// writeOverview writes one overview frame and flushes it to the client
+func (h *MetricsStreamHandler) writeOverview(w *bufio.Writer, tenantID uuid.UUID, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	overview, err := h.overviews.GetMetricsOverview(ctx, tenantID)
+	if err != nil {
+		// Keep the stream open; the next tick may succeed
+		h.logger.Error("failed to get metrics overview", "error", err, "tenant_id", tenantID)
+		if _, err := fmt.Fprint(w, ": overview unavailable\n\n"); err != nil {
+			return err
+		}
+		return w.Flush()
+	}
+
+	data, err := json.Marshal(overview)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "event: overview\ndata: %s\n\n", data); err != nil {
+		return err
+	}
+	return w.Flush()
+}
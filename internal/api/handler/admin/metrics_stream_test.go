@@ -0,0 +1,95 @@
+package admin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/admin"
+)
+
+// countingOverviewReader returns an overview whose face count grows on every read
+type countingOverviewReader struct {
+	reads atomic.Int64
+}
+
+func (r *countingOverviewReader) GetMetricsOverview(ctx context.Context, tenantID uuid.UUID) (*admin.MetricsOverview, error) {
+	n := r.reads.Add(1)
+	return &admin.MetricsOverview{TotalFaces: n, ErrorRate: 1.5, RequestsPerSecond: 0.2, GeneratedAt: time.Now()}, nil
+}
+
+// readEvent reads one SSE frame and returns its event name and data
+func readEvent(t *testing.T, r *bufio.Reader) (string, string) {
+	t.Helper()
+	var event, data string
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "":
+			return event, data
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestMetricsStreamHandler_Stream(t *testing.T) {
+	tenantID := uuid.New()
+	overviews := &countingOverviewReader{}
+	handler := NewMetricsStreamHandler(overviews, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	app := setupTestApp(handler.Stream, tenantID)
+
+	// SSE needs a real connection: app.Test waits for the response to end
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = app.Listener(ln) }()
+
+	resp, err := http.Get("http://" + ln.Addr().String() + "/test?interval_seconds=1")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(resp.Body)
+	for want := int64(1); want <= 2; want++ {
+		event, data := readEvent(t, reader)
+		assert.Equal(t, "overview", event)
+
+		var overview admin.MetricsOverview
+		require.NoError(t, json.Unmarshal([]byte(data), &overview))
+		assert.Equal(t, want, overview.TotalFaces)
+		assert.Equal(t, 1.5, overview.ErrorRate)
+	}
+
+	// Once the client disconnects the stream ends and shutdown does not wait on it
+	require.NoError(t, resp.Body.Close())
+	assert.NoError(t, app.ShutdownWithTimeout(5*time.Second))
+}
+
+func TestMetricsStreamHandler_InvalidInterval(t *testing.T) {
+	handler := NewMetricsStreamHandler(&countingOverviewReader{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	app := setupTestApp(handler.Stream, uuid.New())
+
+	for _, interval := range []string{"0", "61", "abc"} {
+		resp, err := app.Test(httptest.NewRequest("GET", "/test?interval_seconds="+interval, nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode, "interval_seconds=%s", interval)
+	}
+}
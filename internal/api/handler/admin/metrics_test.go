@@ -105,6 +105,12 @@ func TestParseMetricsParams_InvalidDates(t *testing.T) {
 			expectedStatus: 400,
 			expectedError:  "start_date must be before or equal to end_date",
 		},
+		{
+			name:           "unsupported group_by",
+			queryParams:    "?group_by=gate",
+			expectedStatus: 400,
+			expectedError:  "invalid group_by",
+		},
 	}
 
 	for _, tt := range tests {
@@ -145,12 +151,17 @@ func TestMetricsHandlers_NoTenantID(t *testing.T) {
 		{"GetFacesMetrics", usageHandler.GetFacesMetrics},
 		{"GetOperationsMetrics", usageHandler.GetOperationsMetrics},
 		{"GetRequestsMetrics", usageHandler.GetRequestsMetrics},
+		{"GetTopIdentities", usageHandler.GetTopIdentities},
+		{"GetFaceTrend", usageHandler.GetFaceTrend},
+		{"GetUsageForecast", usageHandler.GetUsageForecast},
 		{"GetLatencyMetrics", perfHandler.GetLatencyMetrics},
 		{"GetThroughputMetrics", perfHandler.GetThroughputMetrics},
 		{"GetErrorMetrics", perfHandler.GetErrorMetrics},
 		{"GetQualityMetrics", qualityHandler.GetQualityMetrics},
 		{"GetConfidenceMetrics", qualityHandler.GetConfidenceMetrics},
 		{"GetMatchMetrics", qualityHandler.GetMatchMetrics},
+		{"GetGateMetrics", qualityHandler.GetGateMetrics},
+		{"GetLivenessMetrics", qualityHandler.GetLivenessMetrics},
 	}
 
 	for _, tt := range tests {
@@ -167,6 +178,37 @@ func TestMetricsHandlers_NoTenantID(t *testing.T) {
 	}
 }
 
+// TestGetFaceTrend_Validation tests request validation before any query runs
+func TestGetFaceTrend_Validation(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	handler := &MetricsUsageHandler{logger: logger}
+	tenantID := uuid.New()
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"invalid start_date", "/faces/user-123/trend?start_date=invalid"},
+		{"start after end", "/faces/user-123/trend?start_date=2025-02-01&end_date=2025-01-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				c.Locals(middleware.LocalTenantID, tenantID)
+				return c.Next()
+			})
+			app.Get("/faces/:external_id/trend", handler.GetFaceTrend)
+
+			resp, err := app.Test(httptest.NewRequest("GET", tt.path, nil), -1)
+			require.NoError(t, err)
+
+			assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+		})
+	}
+}
+
 // TestMetricsUsageHandler_Constructor tests handler constructors
 func TestMetricsUsageHandler_Constructor(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
@@ -250,6 +292,25 @@ func TestSharedParseMetricsParams(t *testing.T) {
 
 		assert.Equal(t, 400, resp.StatusCode)
 	})
+
+	t.Run("exclude_test filters test data", func(t *testing.T) {
+		var params admin.MetricsParams
+		app := setupTestApp(func(c *fiber.Ctx) error {
+			var err error
+			params, err = parseMetricsParams(c)
+			return err
+		}, tenantID)
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/test", nil), -1)
+		require.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.False(t, params.ExcludeTest, "test data is included by default")
+
+		resp, err = app.Test(httptest.NewRequest("GET", "/test?exclude_test=true", nil), -1)
+		require.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.True(t, params.ExcludeTest)
+	})
 }
 
 // TestHandlerErrorTypes tests different error scenarios
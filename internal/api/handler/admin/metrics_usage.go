@@ -2,6 +2,7 @@ package admin
 
 import (
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/saturnino-fabrica-de-software/rekko/internal/admin"
 	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
 )
 
 // MetricsUsageHandler handles metrics usage endpoints
@@ -39,7 +41,7 @@ func (h *MetricsUsageHandler) GetFacesMetrics(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
-	metrics, err := h.adminService.GetFacesMetrics(c.Context(), tenantID, params)
+	metrics, err := h.adminService.GetFacesMetrics(c.UserContext(), tenantID, params)
 	if err != nil {
 		h.logger.Error("failed to get faces metrics", "error", err, "tenant_id", tenantID)
 		return fiber.ErrInternalServerError
@@ -74,7 +76,7 @@ func (h *MetricsUsageHandler) GetOperationsMetrics(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
-	metrics, err := h.adminService.GetOperationsMetrics(c.Context(), tenantID, params)
+	metrics, err := h.adminService.GetOperationsMetrics(c.UserContext(), tenantID, params)
 	if err != nil {
 		h.logger.Error("failed to get operations metrics", "error", err, "tenant_id", tenantID)
 		return fiber.ErrInternalServerError
@@ -109,7 +111,7 @@ func (h *MetricsUsageHandler) GetRequestsMetrics(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
-	metrics, err := h.adminService.GetRequestsMetrics(c.Context(), tenantID, params)
+	metrics, err := h.adminService.GetRequestsMetrics(c.UserContext(), tenantID, params)
 	if err != nil {
 		h.logger.Error("failed to get requests metrics", "error", err, "tenant_id", tenantID)
 		return fiber.ErrInternalServerError
@@ -130,6 +132,106 @@ func (h *MetricsUsageHandler) GetRequestsMetrics(c *fiber.Ctx) error {
 	})
 }
 
+// GetFaceTrend handles GET /v1/admin/faces/:external_id/trend
+func (h *MetricsUsageHandler) GetFaceTrend(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals(middleware.LocalTenantID).(uuid.UUID)
+	if !ok {
+		h.logger.Warn("tenant ID not found in context")
+		return fiber.ErrUnauthorized
+	}
+
+	externalID := strings.TrimSpace(c.Params("external_id"))
+	if externalID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "external_id is required")
+	}
+
+	params, err := h.parseMetricsParams(c)
+	if err != nil {
+		h.logger.Debug("invalid metrics params", "error", err, "tenant_id", tenantID)
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	trend, err := h.adminService.GetFaceTrend(c.UserContext(), tenantID, externalID, params)
+	if err != nil {
+		h.logger.Error("failed to get face trend", "error", err, "tenant_id", tenantID)
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(admin.MetricsResponse{
+		Data: trend,
+		Meta: admin.ResponseMeta{
+			TenantID:    tenantID.String(),
+			Period:      admin.Period{Start: params.StartDate.Format("2006-01-02"), End: params.EndDate.Format("2006-01-02")},
+			GeneratedAt: time.Now(),
+		},
+		Pagination: &admin.PaginationMeta{
+			Total:  trend.TotalPeriods,
+			Limit:  params.Limit,
+			Offset: params.Offset,
+		},
+	})
+}
+
+// GetTopIdentities handles GET /v1/admin/metrics/top-identities
+func (h *MetricsUsageHandler) GetTopIdentities(c *fiber.Ctx) error {
+	tenant, ok := c.Locals(middleware.LocalTenant).(*domain.Tenant)
+	if !ok {
+		h.logger.Warn("tenant not found in context")
+		return fiber.ErrUnauthorized
+	}
+
+	params, err := h.parseMetricsParams(c)
+	if err != nil {
+		h.logger.Debug("invalid metrics params", "error", err, "tenant_id", tenant.ID)
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if c.Query("limit") == "" || params.Limit == 0 {
+		params.Limit = admin.DefaultTopIdentities
+	}
+
+	// Metadata is held to the same allow-list as search matches
+	identities, err := h.adminService.GetTopIdentities(c.UserContext(), tenant.ID, params, tenant.GetSettings().SearchMetadataKeys)
+	if err != nil {
+		h.logger.Error("failed to get top identities", "error", err, "tenant_id", tenant.ID)
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(admin.MetricsResponse{
+		Data: identities,
+		Meta: admin.ResponseMeta{
+			TenantID:    tenant.ID.String(),
+			Period:      admin.Period{Start: params.StartDate.Format("2006-01-02"), End: params.EndDate.Format("2006-01-02")},
+			GeneratedAt: time.Now(),
+		},
+	})
+}
+
+// GetUsageForecast handles GET /v1/admin/usage/forecast
+func (h *MetricsUsageHandler) GetUsageForecast(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals(middleware.LocalTenantID).(uuid.UUID)
+	if !ok {
+		h.logger.Warn("tenant ID not found in context")
+		return fiber.ErrUnauthorized
+	}
+
+	now := time.Now().UTC()
+	forecast, err := h.adminService.GetUsageForecast(c.UserContext(), tenantID, now)
+	if err != nil {
+		h.logger.Error("failed to get usage forecast", "error", err, "tenant_id", tenantID)
+		return fiber.ErrInternalServerError
+	}
+
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return c.JSON(admin.MetricsResponse{
+		Data: forecast,
+		Meta: admin.ResponseMeta{
+			TenantID:    tenantID.String(),
+			Period:      admin.Period{Start: monthStart.Format("2006-01-02"), End: monthStart.AddDate(0, 1, -1).Format("2006-01-02")},
+			GeneratedAt: now,
+		},
+	})
+}
+
 // parseMetricsParams parses and validates query parameters
 func (h *MetricsUsageHandler) parseMetricsParams(c *fiber.Ctx) (admin.MetricsParams, error) {
 	startDate := c.Query("start_date", time.Now().AddDate(0, 0, -30).Format("2006-01-02"))
@@ -172,11 +274,17 @@ func (h *MetricsUsageHandler) parseMetricsParams(c *fiber.Ctx) (admin.MetricsPar
 		return admin.MetricsParams{}, fiber.NewError(fiber.StatusBadRequest, "start_date must be before or equal to end_date")
 	}
 
+	groupBy := c.Query("group_by")
+	if groupBy != "" && groupBy != admin.GroupByProvider {
+		return admin.MetricsParams{}, fiber.NewError(fiber.StatusBadRequest, "invalid group_by, expected provider")
+	}
+
 	return admin.MetricsParams{
 		StartDate: start,
 		EndDate:   end,
 		Interval:  interval,
 		Limit:     limit,
 		Offset:    offset,
+		GroupBy:   groupBy,
 	}, nil
 }
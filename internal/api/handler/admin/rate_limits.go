@@ -0,0 +1,69 @@
+package admin
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+// EndpointUsageReader reports per-endpoint request counts of the API rate limiter
+type EndpointUsageReader interface {
+	Usage(key, path string) (limit, count int, resetAt time.Time)
+}
+
+// Endpoint paths counted by the API rate limiter for each bucket
+const (
+	verifyPath   = "/v1/faces/verify"
+	registerPath = "/v1/faces"
+)
+
+type RateLimitsHandler struct {
+	search    middleware.SearchWindowReader
+	endpoints EndpointUsageReader
+	logger    *slog.Logger
+}
+
+func NewRateLimitsHandler(search middleware.SearchWindowReader, endpoints EndpointUsageReader, logger *slog.Logger) *RateLimitsHandler {
+	return &RateLimitsHandler{
+		search:    search,
+		endpoints: endpoints,
+		logger:    logger,
+	}
+}
+
+// RateLimitsResponse lists the tenant's current usage per rate limit bucket
+type RateLimitsResponse struct {
+	Buckets []domain.RateLimitState `json:"buckets"`
+}
+
+// Get returns the limit, current count and window reset of each bucket for the authenticated tenant
+// GET /v1/admin/rate-limits
+func (h *RateLimitsHandler) Get(c *fiber.Ctx) error {
+	tenant, err := middleware.GetTenant(c)
+	if err != nil {
+		return err
+	}
+
+	// Search is limited by the tenant's search_rate_limit in the shared store
+	searchCount, searchReset, err := h.search.SearchWindow(c.UserContext(), tenant.ID)
+	if err != nil {
+		h.logger.Error("failed to read search rate limit", "error", err, "tenant_id", tenant.ID)
+		return fiber.ErrInternalServerError
+	}
+
+	key := tenant.ID.String()
+	verifyLimit, verifyCount, verifyReset := h.endpoints.Usage(key, verifyPath)
+	registerLimit, registerCount, registerReset := h.endpoints.Usage(key, registerPath)
+
+	return c.JSON(RateLimitsResponse{
+		Buckets: []domain.RateLimitState{
+			domain.NewRateLimitState(domain.RateLimitBucketSearch, max(tenant.GetSettings().SearchRateLimit, 0), searchCount, searchReset),
+			domain.NewRateLimitState(domain.RateLimitBucketVerify, verifyLimit, verifyCount, verifyReset),
+			domain.NewRateLimitState(domain.RateLimitBucketRegister, registerLimit, registerCount, registerReset),
+		},
+	})
+}
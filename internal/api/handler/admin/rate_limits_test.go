@@ -0,0 +1,122 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+type fakeSearchWindow struct {
+	count   int
+	resetAt time.Time
+	err     error
+}
+
+func (f *fakeSearchWindow) SearchWindow(ctx context.Context, tenantID uuid.UUID) (int, time.Time, error) {
+	return f.count, f.resetAt, f.err
+}
+
+func TestRateLimitsHandler_Get(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tenant := &domain.Tenant{ID: uuid.New(), Settings: map[string]interface{}{"search_rate_limit": 30.0}}
+
+	newApp := func(search middleware.SearchWindowReader) (*fiber.App, *middleware.RateLimiter) {
+		limiter := middleware.NewRateLimiter(middleware.RateLimiterConfig{
+			Max:    10,
+			Window: time.Minute,
+			KeyGenerator: func(c *fiber.Ctx) string {
+				return tenant.ID.String()
+			},
+		})
+		t.Cleanup(limiter.Stop)
+
+		app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler(logger)})
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(middleware.LocalTenantID, tenant.ID)
+			c.Locals(middleware.LocalTenant, tenant)
+			return c.Next()
+		})
+		app.Use(limiter.Handler())
+		app.Post("/v1/faces/verify", func(c *fiber.Ctx) error {
+			return c.SendString("OK")
+		})
+		app.Get("/v1/admin/rate-limits", NewRateLimitsHandler(search, limiter, logger).Get)
+		return app, limiter
+	}
+
+	getBuckets := func(t *testing.T, app *fiber.App) map[string]domain.RateLimitState {
+		t.Helper()
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/admin/rate-limits", nil))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body RateLimitsResponse
+		readResponseBody(t, resp, &body)
+
+		buckets := make(map[string]domain.RateLimitState, len(body.Buckets))
+		for _, b := range body.Buckets {
+			buckets[b.Bucket] = b
+		}
+		return buckets
+	}
+
+	t.Run("remaining decreases after calls", func(t *testing.T) {
+		app, _ := newApp(&fakeSearchWindow{})
+
+		before := getBuckets(t, app)[domain.RateLimitBucketVerify]
+		assert.Equal(t, 10, before.Limit)
+		assert.Equal(t, 0, before.Count)
+		require.NotNil(t, before.Remaining)
+		assert.Equal(t, 10, *before.Remaining)
+		assert.Nil(t, before.ResetAt)
+
+		for i := 0; i < 3; i++ {
+			resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/v1/faces/verify", nil))
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+		}
+
+		after := getBuckets(t, app)[domain.RateLimitBucketVerify]
+		assert.Equal(t, 3, after.Count)
+		require.NotNil(t, after.Remaining)
+		assert.Equal(t, 7, *after.Remaining)
+		assert.NotNil(t, after.ResetAt)
+	})
+
+	t.Run("search bucket reads the rate limit store", func(t *testing.T) {
+		resetAt := time.Now().Add(40 * time.Second).UTC().Truncate(time.Second)
+		app, _ := newApp(&fakeSearchWindow{count: 12, resetAt: resetAt})
+
+		buckets := getBuckets(t, app)
+
+		search := buckets[domain.RateLimitBucketSearch]
+		assert.Equal(t, 30, search.Limit)
+		assert.Equal(t, 12, search.Count)
+		require.NotNil(t, search.Remaining)
+		assert.Equal(t, 18, *search.Remaining)
+		require.NotNil(t, search.ResetAt)
+		assert.True(t, resetAt.Equal(*search.ResetAt))
+		assert.Contains(t, buckets, domain.RateLimitBucketRegister)
+	})
+
+	t.Run("store error returns 500", func(t *testing.T) {
+		app, _ := newApp(&fakeSearchWindow{err: errors.New("connection refused")})
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/admin/rate-limits", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	})
+}
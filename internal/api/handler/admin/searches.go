@@ -0,0 +1,141 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/admin"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+// defaultSearchAuditWindow is the range listed when from is omitted
+const defaultSearchAuditWindow = 30 * 24 * time.Hour
+
+// SearchAuditLister reads the audit trail of a tenant's 1:N searches
+type SearchAuditLister interface {
+	List(ctx context.Context, tenantID uuid.UUID, filter domain.SearchAuditFilter) ([]*domain.SearchAudit, int, error)
+}
+
+type SearchesHandler struct {
+	audits SearchAuditLister
+	logger *slog.Logger
+}
+
+func NewSearchesHandler(audits SearchAuditLister, logger *slog.Logger) *SearchesHandler {
+	return &SearchesHandler{
+		audits: audits,
+		logger: logger,
+	}
+}
+
+// SearchAuditResponse is one recorded search. No biometric data is stored or returned.
+type SearchAuditResponse struct {
+	ID                 string   `json:"id"`
+	ResultsCount       int      `json:"results_count"`
+	Threshold          float64  `json:"threshold"`
+	MaxResults         int      `json:"max_results"`
+	LatencyMs          int64    `json:"latency_ms"`
+	TopMatchExternalID *string  `json:"top_match_external_id"`
+	TopMatchSimilarity *float64 `json:"top_match_similarity"`
+	ClientIP           string   `json:"client_ip"`
+	CreatedAt          string   `json:"created_at"`
+}
+
+type SearchAuditsResponse struct {
+	Searches   []SearchAuditResponse `json:"searches"`
+	Pagination admin.PaginationMeta  `json:"pagination"`
+}
+
+// List returns the authenticated tenant's search audits, most recent first.
+// from and to are RFC3339 timestamps; the range defaults to the last 30 days.
+// GET /v1/admin/searches?from=&to=&limit=&offset=
+func (h *SearchesHandler) List(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals(middleware.LocalTenantID).(uuid.UUID)
+	if !ok {
+		h.logger.Warn("tenant ID not found in context")
+		return fiber.ErrUnauthorized
+	}
+
+	filter, err := parseSearchAuditFilter(c)
+	if err != nil {
+		return err
+	}
+
+	audits, total, err := h.audits.List(c.UserContext(), tenantID, filter)
+	if err != nil {
+		h.logger.Error("failed to list search audits", "error", err, "tenant_id", tenantID)
+		return fiber.ErrInternalServerError
+	}
+
+	searches := make([]SearchAuditResponse, 0, len(audits))
+	for _, a := range audits {
+		searches = append(searches, SearchAuditResponse{
+			ID:                 a.ID.String(),
+			ResultsCount:       a.ResultsCount,
+			Threshold:          a.Threshold,
+			MaxResults:         a.MaxResults,
+			LatencyMs:          a.LatencyMs,
+			TopMatchExternalID: a.TopMatchExternalID,
+			TopMatchSimilarity: a.TopMatchSimilarity,
+			ClientIP:           a.ClientIP,
+			CreatedAt:          a.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return c.JSON(SearchAuditsResponse{
+		Searches: searches,
+		Pagination: admin.PaginationMeta{
+			Total:  total,
+			Limit:  filter.Limit,
+			Offset: filter.Offset,
+		},
+	})
+}
+
+// parseSearchAuditFilter reads the time range and pagination query parameters
+func parseSearchAuditFilter(c *fiber.Ctx) (domain.SearchAuditFilter, error) {
+	filter := domain.SearchAuditFilter{
+		To:     time.Now().UTC(),
+		Limit:  c.QueryInt("limit", domain.DefaultSearchAuditLimit),
+		Offset: c.QueryInt("offset", 0),
+	}
+
+	if raw := c.Query("to"); raw != "" {
+		to, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return filter, domain.ErrValidationFailed.WithError(fmt.Errorf("invalid to, expected RFC3339 timestamp: %w", err))
+		}
+		filter.To = to
+	}
+
+	filter.From = filter.To.Add(-defaultSearchAuditWindow)
+	if raw := c.Query("from"); raw != "" {
+		from, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return filter, domain.ErrValidationFailed.WithError(fmt.Errorf("invalid from, expected RFC3339 timestamp: %w", err))
+		}
+		filter.From = from
+	}
+
+	if !filter.From.Before(filter.To) {
+		return filter, domain.ErrValidationFailed.WithError(errors.New("from must be before to"))
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = domain.DefaultSearchAuditLimit
+	}
+	if filter.Limit > domain.MaxSearchAuditLimit {
+		filter.Limit = domain.MaxSearchAuditLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	return filter, nil
+}
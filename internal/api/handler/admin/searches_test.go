@@ -0,0 +1,177 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+type fakeSearchAuditLister struct {
+	audits []*domain.SearchAudit
+	total  int
+	err    error
+
+	called    bool
+	gotTenant uuid.UUID
+	gotFilter domain.SearchAuditFilter
+}
+
+func (f *fakeSearchAuditLister) List(ctx context.Context, tenantID uuid.UUID, filter domain.SearchAuditFilter) ([]*domain.SearchAudit, int, error) {
+	f.called, f.gotTenant, f.gotFilter = true, tenantID, filter
+	return f.audits, f.total, f.err
+}
+
+func TestSearchesHandler_List(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tenantID := uuid.New()
+
+	newApp := func(h *SearchesHandler) *fiber.App {
+		app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler(logger)})
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(middleware.LocalTenantID, tenantID)
+			return c.Next()
+		})
+		app.Get("/v1/admin/searches", h.List)
+		return app
+	}
+
+	t.Run("returns audits with pagination", func(t *testing.T) {
+		externalID, similarity := "user_001", 0.97
+		createdAt := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+		lister := &fakeSearchAuditLister{
+			audits: []*domain.SearchAudit{
+				{
+					ID:                 uuid.New(),
+					TenantID:           tenantID,
+					ResultsCount:       2,
+					TopMatchExternalID: &externalID,
+					TopMatchSimilarity: &similarity,
+					Threshold:          0.8,
+					MaxResults:         10,
+					LatencyMs:          35,
+					ClientIP:           "203.0.113.7",
+					CreatedAt:          createdAt,
+				},
+				{
+					ID:         uuid.New(),
+					TenantID:   tenantID,
+					Threshold:  0.9,
+					MaxResults: 5,
+					LatencyMs:  20,
+					CreatedAt:  createdAt.Add(-time.Hour),
+				},
+			},
+			total: 12,
+		}
+		app := newApp(NewSearchesHandler(lister, logger))
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet,
+			"/v1/admin/searches?from=2026-03-01T00:00:00Z&to=2026-03-03T00:00:00Z&limit=2&offset=4", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body SearchAuditsResponse
+		readResponseBody(t, resp, &body)
+		require.Len(t, body.Searches, 2)
+		assert.Equal(t, 2, body.Searches[0].ResultsCount)
+		assert.Equal(t, "user_001", *body.Searches[0].TopMatchExternalID)
+		assert.Equal(t, 0.97, *body.Searches[0].TopMatchSimilarity)
+		assert.Equal(t, 0.8, body.Searches[0].Threshold)
+		assert.Equal(t, 10, body.Searches[0].MaxResults)
+		assert.Equal(t, int64(35), body.Searches[0].LatencyMs)
+		assert.Equal(t, "203.0.113.7", body.Searches[0].ClientIP)
+		assert.Equal(t, "2026-03-02T10:00:00Z", body.Searches[0].CreatedAt)
+		assert.Nil(t, body.Searches[1].TopMatchExternalID)
+		assert.Nil(t, body.Searches[1].TopMatchSimilarity)
+		assert.Equal(t, 12, body.Pagination.Total)
+		assert.Equal(t, 2, body.Pagination.Limit)
+		assert.Equal(t, 4, body.Pagination.Offset)
+
+		assert.Equal(t, tenantID, lister.gotTenant)
+		assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), lister.gotFilter.From)
+		assert.Equal(t, time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC), lister.gotFilter.To)
+		assert.Equal(t, 2, lister.gotFilter.Limit)
+		assert.Equal(t, 4, lister.gotFilter.Offset)
+	})
+
+	t.Run("defaults to the last 30 days", func(t *testing.T) {
+		lister := &fakeSearchAuditLister{}
+		app := newApp(NewSearchesHandler(lister, logger))
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/admin/searches", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body SearchAuditsResponse
+		readResponseBody(t, resp, &body)
+		assert.NotNil(t, body.Searches)
+		assert.Empty(t, body.Searches)
+
+		assert.WithinDuration(t, time.Now(), lister.gotFilter.To, time.Minute)
+		assert.Equal(t, 30*24*time.Hour, lister.gotFilter.To.Sub(lister.gotFilter.From))
+		assert.Equal(t, domain.DefaultSearchAuditLimit, lister.gotFilter.Limit)
+		assert.Equal(t, 0, lister.gotFilter.Offset)
+	})
+
+	t.Run("caps limit and clamps negative offset", func(t *testing.T) {
+		lister := &fakeSearchAuditLister{}
+		app := newApp(NewSearchesHandler(lister, logger))
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/admin/searches?limit=5000&offset=-3", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, domain.MaxSearchAuditLimit, lister.gotFilter.Limit)
+		assert.Equal(t, 0, lister.gotFilter.Offset)
+	})
+
+	invalid := []struct {
+		name  string
+		query string
+	}{
+		{"malformed from", "?from=yesterday"},
+		{"malformed to", "?to=2026-03-01"},
+		{"from after to", "?from=2026-03-03T00:00:00Z&to=2026-03-01T00:00:00Z"},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			lister := &fakeSearchAuditLister{}
+			app := newApp(NewSearchesHandler(lister, logger))
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/admin/searches"+tt.query, nil))
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+			assert.False(t, lister.called)
+		})
+	}
+
+	t.Run("repository failure", func(t *testing.T) {
+		app := newApp(NewSearchesHandler(&fakeSearchAuditLister{err: errors.New("connection refused")}, logger))
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/admin/searches", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	})
+
+	t.Run("no tenant in context", func(t *testing.T) {
+		app := fiber.New()
+		app.Get("/test", NewSearchesHandler(&fakeSearchAuditLister{}, logger).List)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/test", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
@@ -0,0 +1,39 @@
+package admin
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+type ThresholdsHandler struct {
+	logger *slog.Logger
+}
+
+func NewThresholdsHandler(logger *slog.Logger) *ThresholdsHandler {
+	return &ThresholdsHandler{logger: logger}
+}
+
+// ThresholdsResponse explains how each operation's match threshold resolves for the tenant
+type ThresholdsResponse struct {
+	SecurityLevel domain.SecurityLevel         `json:"security_level"`
+	Operations    []domain.ThresholdResolution `json:"operations"`
+}
+
+// Get returns the effective verify and search thresholds of the authenticated tenant,
+// with the layer each one was resolved from
+// GET /v1/admin/config/thresholds
+func (h *ThresholdsHandler) Get(c *fiber.Ctx) error {
+	tenant, err := middleware.GetTenant(c)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(ThresholdsResponse{
+		SecurityLevel: tenant.GetSettings().SecurityLevel,
+		Operations:    tenant.ThresholdResolutions(),
+	})
+}
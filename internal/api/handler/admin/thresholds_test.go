@@ -0,0 +1,72 @@
+package admin
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+func TestThresholdsHandler_Get(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tenant := &domain.Tenant{ID: uuid.New(), Settings: map[string]interface{}{
+		"security_level":   "enhanced",
+		"search_threshold": 0.9,
+		"security_levels": map[string]interface{}{
+			"enhanced": map[string]interface{}{"verify_threshold": 0.88},
+		},
+	}}
+
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler(logger)})
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals(middleware.LocalTenantID, tenant.ID)
+		c.Locals(middleware.LocalTenant, tenant)
+		return c.Next()
+	})
+	app.Get("/v1/admin/config/thresholds", NewThresholdsHandler(logger).Get)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/admin/config/thresholds", nil))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body ThresholdsResponse
+	readResponseBody(t, resp, &body)
+
+	assert.Equal(t, domain.SecurityEnhanced, body.SecurityLevel)
+	require.Len(t, body.Operations, 2)
+
+	verify := body.Operations[0]
+	assert.Equal(t, domain.ThresholdOperationVerify, verify.Operation)
+	assert.Equal(t, 0.88, verify.ThresholdApplied)
+	assert.Equal(t, domain.ThresholdSourceSecurityLevel, verify.Source)
+
+	search := body.Operations[1]
+	assert.Equal(t, domain.ThresholdOperationSearch, search.Operation)
+	assert.Equal(t, 0.9, search.ThresholdApplied)
+	assert.Equal(t, domain.ThresholdSourceTenant, search.Source)
+
+	// Layers are reported in resolution order, unset ones with a null value
+	sources := make([]domain.ThresholdSource, len(search.Layers))
+	for i, layer := range search.Layers {
+		sources[i] = layer.Source
+	}
+	assert.Equal(t, []domain.ThresholdSource{
+		domain.ThresholdSourceRequest,
+		domain.ThresholdSourceSecurityLevel,
+		domain.ThresholdSourceTenant,
+		domain.ThresholdSourceDefault,
+	}, sources)
+	assert.Nil(t, search.Layers[0].Value)
+	assert.Nil(t, search.Layers[1].Value)
+	require.NotNil(t, search.Layers[3].Value)
+	assert.Equal(t, 0.85, *search.Layers[3].Value)
+}
@@ -0,0 +1,118 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+// VerificationGetter looks up a single verification of a tenant
+type VerificationGetter interface {
+	GetByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.Verification, error)
+}
+
+type VerificationsHandler struct {
+	verifications VerificationGetter
+	logger        *slog.Logger
+}
+
+// AdminVerificationResponse is everything recorded about one verification, for investigations
+type AdminVerificationResponse struct {
+	VerificationID string `json:"verification_id"`
+	ExternalID     string `json:"external_id"`
+	// FaceID is null once the verified face was deleted
+	FaceID     *string `json:"face_id"`
+	Verified   bool    `json:"verified"`
+	Similarity float64 `json:"similarity"`
+	// ThresholdApplied is null for attempts rejected before matching and verifications
+	// recorded before thresholds were stored
+	ThresholdApplied *float64               `json:"threshold_applied"`
+	ThresholdSource  domain.ThresholdSource `json:"threshold_source,omitempty"`
+	// Liveness is null when the verify policy ran no liveness check
+	Liveness        *VerificationLivenessResponse `json:"liveness"`
+	FailReason      string                        `json:"fail_reason,omitempty"`
+	Provider        string                        `json:"provider,omitempty"`
+	ProviderVariant string                        `json:"provider_variant,omitempty"`
+	// ClientIP is the gate that requested the verification
+	ClientIP  string    `json:"client_ip,omitempty"`
+	LatencyMs int64     `json:"latency_ms"`
+	IsTest    bool      `json:"is_test"`
+	CreatedAt time.Time `json:"created_at"`
+	// FaceMetadata holds the face metadata keys in the tenant's search_metadata_keys
+	FaceMetadata map[string]interface{} `json:"face_metadata,omitempty"`
+}
+
+// VerificationLivenessResponse is the liveness check of a verification
+type VerificationLivenessResponse struct {
+	Passed bool `json:"passed"`
+	// Score is null for verifications recorded before liveness scores were stored
+	Score *float64 `json:"score"`
+}
+
+func NewVerificationsHandler(verifications VerificationGetter, logger *slog.Logger) *VerificationsHandler {
+	return &VerificationsHandler{
+		verifications: verifications,
+		logger:        logger,
+	}
+}
+
+// Get returns the full record of one of the tenant's verifications with the allow-listed
+// metadata of the verified face
+// GET /v1/admin/verifications/:id
+func (h *VerificationsHandler) Get(c *fiber.Ctx) error {
+	tenant, ok := c.Locals(middleware.LocalTenant).(*domain.Tenant)
+	if !ok {
+		h.logger.Warn("tenant not found in context")
+		return fiber.ErrUnauthorized
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return domain.ErrValidationFailed.WithError(errors.New("id must be a verification UUID"))
+	}
+
+	v, err := h.verifications.GetByID(c.UserContext(), tenant.ID, id)
+	if err != nil {
+		var appErr *domain.AppError
+		if errors.As(err, &appErr) {
+			return appErr
+		}
+		h.logger.Error("failed to get verification", "error", err, "tenant_id", tenant.ID, "verification_id", id)
+		return fiber.ErrInternalServerError
+	}
+
+	response := AdminVerificationResponse{
+		VerificationID:  v.ID.String(),
+		ExternalID:      v.ExternalID,
+		Verified:        v.Verified,
+		Similarity:      v.Confidence,
+		ThresholdSource: v.ThresholdSource,
+		FailReason:      v.FailReason,
+		Provider:        v.Provider,
+		ProviderVariant: v.ProviderVariant,
+		ClientIP:        v.ClientIP,
+		LatencyMs:       v.LatencyMs,
+		IsTest:          v.IsTest,
+		CreatedAt:       v.CreatedAt,
+		FaceMetadata:    domain.AllowedMetadata(v.FaceMetadata, tenant.GetSettings().SearchMetadataKeys),
+	}
+	if v.FaceID != nil {
+		faceID := v.FaceID.String()
+		response.FaceID = &faceID
+	}
+	if v.ThresholdApplied > 0 {
+		response.ThresholdApplied = &v.ThresholdApplied
+	}
+	if v.LivenessPassed != nil {
+		response.Liveness = &VerificationLivenessResponse{Passed: *v.LivenessPassed, Score: v.LivenessScore}
+	}
+
+	return c.JSON(response)
+}
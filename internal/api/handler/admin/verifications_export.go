@@ -0,0 +1,146 @@
+package admin
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/valyala/fasthttp"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+const (
+	// defaultVerificationExportWindow is the range exported when from is omitted
+	defaultVerificationExportWindow = 30 * 24 * time.Hour
+	// verificationExportTimeout bounds the database reads of one export
+	verificationExportTimeout = 10 * time.Minute
+)
+
+// verificationExportHeader lists the CSV columns, in order
+var verificationExportHeader = []string{"external_id", "verified", "confidence", "liveness_passed", "latency_ms", "created_at"}
+
+// VerificationStreamer reads a tenant's verifications in creation order without loading them all
+type VerificationStreamer interface {
+	StreamRange(ctx context.Context, tenantID uuid.UUID, from, to time.Time, fn func(*domain.Verification) error) error
+}
+
+type VerificationsExportHandler struct {
+	verifications VerificationStreamer
+	logger        *slog.Logger
+}
+
+func NewVerificationsExportHandler(verifications VerificationStreamer, logger *slog.Logger) *VerificationsExportHandler {
+	return &VerificationsExportHandler{
+		verifications: verifications,
+		logger:        logger,
+	}
+}
+
+// Export streams the authenticated tenant's raw verification logs as a CSV attachment, oldest first.
+// from and to are RFC3339 timestamps; the range defaults to the last 30 days. Rows are written as
+// they are read, so a database error after the first page truncates the file instead of failing it.
+// GET /v1/admin/verifications/export?from=&to=&format=csv
+func (h *VerificationsExportHandler) Export(c *fiber.Ctx) error {
+	tenantID, ok := c.Locals(middleware.LocalTenantID).(uuid.UUID)
+	if !ok {
+		h.logger.Warn("tenant ID not found in context")
+		return fiber.ErrUnauthorized
+	}
+
+	if format := c.Query("format", "csv"); format != "csv" {
+		return domain.ErrValidationFailed.WithError(fmt.Errorf("unsupported format %q, expected csv", format))
+	}
+
+	from, to, err := parseExportRange(c)
+	if err != nil {
+		return err
+	}
+
+	c.Attachment(fmt.Sprintf("verifications_%s_%s.csv", from.UTC().Format("20060102T150405Z"), to.UTC().Format("20060102T150405Z")))
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	// The writer runs after the handler returns, so it must not touch c
+	c.Context().SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
+		ctx, cancel := context.WithTimeout(context.Background(), verificationExportTimeout)
+		defer cancel()
+
+		if err := h.writeCSV(ctx, w, tenantID, from, to); err != nil {
+			h.logger.Error("verification export interrupted", "error", err, "tenant_id", tenantID)
+		}
+	}))
+
+	return nil
+}
+
+// writeCSV writes the header and one record per verification, flushing as the buffer fills
+func (h *VerificationsExportHandler) writeCSV(ctx context.Context, w *bufio.Writer, tenantID uuid.UUID, from, to time.Time) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(verificationExportHeader); err != nil {
+		return err
+	}
+
+	err := h.verifications.StreamRange(ctx, tenantID, from, to, func(v *domain.Verification) error {
+		return cw.Write(verificationRecord(v))
+	})
+	cw.Flush()
+	if err != nil {
+		return err
+	}
+	if err := cw.Error(); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// verificationRecord formats a verification as CSV fields; liveness_passed is empty when no
+// liveness check ran
+func verificationRecord(v *domain.Verification) []string {
+	liveness := ""
+	if v.LivenessPassed != nil {
+		liveness = strconv.FormatBool(*v.LivenessPassed)
+	}
+	return []string{
+		v.ExternalID,
+		strconv.FormatBool(v.Verified),
+		strconv.FormatFloat(v.Confidence, 'f', -1, 64),
+		liveness,
+		strconv.FormatInt(v.LatencyMs, 10),
+		v.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+// parseExportRange reads the from and to query parameters
+func parseExportRange(c *fiber.Ctx) (time.Time, time.Time, error) {
+	to := time.Now().UTC()
+	if raw := c.Query("to"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return time.Time{}, time.Time{}, domain.ErrValidationFailed.WithError(fmt.Errorf("invalid to, expected RFC3339 timestamp: %w", err))
+		}
+		to = parsed
+	}
+
+	from := to.Add(-defaultVerificationExportWindow)
+	if raw := c.Query("from"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return time.Time{}, time.Time{}, domain.ErrValidationFailed.WithError(fmt.Errorf("invalid from, expected RFC3339 timestamp: %w", err))
+		}
+		from = parsed
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, domain.ErrValidationFailed.WithError(errors.New("from must be before to"))
+	}
+	return from, to, nil
+}
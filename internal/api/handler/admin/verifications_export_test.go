@@ -0,0 +1,150 @@
+package admin
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+type fakeVerificationStreamer struct {
+	verifications []*domain.Verification
+	// failAfter makes the stream fail once that many rows were sent; negative never fails
+	failAfter int
+
+	called  bool
+	gotFrom time.Time
+	gotTo   time.Time
+}
+
+func (f *fakeVerificationStreamer) StreamRange(ctx context.Context, tenantID uuid.UUID, from, to time.Time, fn func(*domain.Verification) error) error {
+	f.called, f.gotFrom, f.gotTo = true, from, to
+	for i, v := range f.verifications {
+		if i == f.failAfter {
+			return errors.New("connection reset")
+		}
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestVerificationsExportHandler_Export(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tenantID := uuid.New()
+
+	newApp := func(h *VerificationsExportHandler) *fiber.App {
+		app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler(logger)})
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(middleware.LocalTenantID, tenantID)
+			return c.Next()
+		})
+		app.Get("/v1/admin/verifications/export", h.Export)
+		return app
+	}
+
+	createdAt := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	live := true
+	verifications := []*domain.Verification{
+		{ExternalID: "user_001", Verified: true, Confidence: 0.97, LivenessPassed: &live, LatencyMs: 120, CreatedAt: createdAt},
+		{ExternalID: "user,\"quoted\"", Verified: false, Confidence: 0.41, LatencyMs: 95, CreatedAt: createdAt.Add(1500 * time.Millisecond)},
+	}
+
+	t.Run("streams rows as CSV attachment", func(t *testing.T) {
+		streamer := &fakeVerificationStreamer{verifications: verifications, failAfter: -1}
+		app := newApp(NewVerificationsExportHandler(streamer, logger))
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet,
+			"/v1/admin/verifications/export?from=2026-03-01T00:00:00Z&to=2026-03-03T00:00:00Z&format=csv", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/csv; charset=utf-8", resp.Header.Get(fiber.HeaderContentType))
+		assert.Equal(t, `attachment; filename="verifications_20260301T000000Z_20260303T000000Z.csv"`, resp.Header.Get(fiber.HeaderContentDisposition))
+
+		records, err := csv.NewReader(resp.Body).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"external_id", "verified", "confidence", "liveness_passed", "latency_ms", "created_at"},
+			{"user_001", "true", "0.97", "true", "120", "2026-03-02T10:00:00Z"},
+			{"user,\"quoted\"", "false", "0.41", "", "95", "2026-03-02T10:00:01.5Z"},
+		}, records)
+
+		assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), streamer.gotFrom)
+		assert.Equal(t, time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC), streamer.gotTo)
+	})
+
+	t.Run("empty range has only the header", func(t *testing.T) {
+		streamer := &fakeVerificationStreamer{failAfter: -1}
+		app := newApp(NewVerificationsExportHandler(streamer, logger))
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/admin/verifications/export", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		records, err := csv.NewReader(resp.Body).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{verificationExportHeader}, records)
+
+		assert.WithinDuration(t, time.Now(), streamer.gotTo, time.Minute)
+		assert.Equal(t, 30*24*time.Hour, streamer.gotTo.Sub(streamer.gotFrom))
+	})
+
+	t.Run("stream failure truncates the file", func(t *testing.T) {
+		streamer := &fakeVerificationStreamer{verifications: verifications, failAfter: 1}
+		app := newApp(NewVerificationsExportHandler(streamer, logger))
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/admin/verifications/export", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		records, err := csv.NewReader(resp.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, "user_001", records[1][0])
+	})
+
+	invalid := []struct {
+		name  string
+		query string
+	}{
+		{"unsupported format", "?format=xlsx"},
+		{"malformed from", "?from=yesterday"},
+		{"malformed to", "?to=2026-03-01"},
+		{"from after to", "?from=2026-03-03T00:00:00Z&to=2026-03-01T00:00:00Z"},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			streamer := &fakeVerificationStreamer{failAfter: -1}
+			app := newApp(NewVerificationsExportHandler(streamer, logger))
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/admin/verifications/export"+tt.query, nil))
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+			assert.False(t, streamer.called)
+		})
+	}
+
+	t.Run("no tenant in context", func(t *testing.T) {
+		app := fiber.New()
+		app.Get("/test", NewVerificationsExportHandler(&fakeVerificationStreamer{failAfter: -1}, logger).Export)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/test", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
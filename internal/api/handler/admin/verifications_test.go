@@ -0,0 +1,149 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+type fakeVerificationGetter struct {
+	verification *domain.Verification
+	err          error
+
+	gotTenant uuid.UUID
+	gotID     uuid.UUID
+}
+
+func (f *fakeVerificationGetter) GetByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.Verification, error) {
+	f.gotTenant, f.gotID = tenantID, id
+	return f.verification, f.err
+}
+
+func TestVerificationsHandler_Get(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tenant := &domain.Tenant{
+		ID:       uuid.New(),
+		Settings: map[string]interface{}{"search_metadata_keys": []interface{}{"ticket_type"}},
+	}
+
+	newApp := func(h *VerificationsHandler) *fiber.App {
+		app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler(logger)})
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(middleware.LocalTenant, tenant)
+			c.Locals(middleware.LocalTenantID, tenant.ID)
+			return c.Next()
+		})
+		app.Get("/v1/admin/verifications/:id", h.Get)
+		return app
+	}
+
+	t.Run("returns the full record with allow-listed face metadata", func(t *testing.T) {
+		id, faceID := uuid.New(), uuid.New()
+		livenessPassed, livenessScore := false, 0.42
+		getter := &fakeVerificationGetter{verification: &domain.Verification{
+			ID:               id,
+			TenantID:         tenant.ID,
+			FaceID:           &faceID,
+			ExternalID:       "user-123",
+			Confidence:       0.91,
+			LivenessPassed:   &livenessPassed,
+			LivenessScore:    &livenessScore,
+			FailReason:       domain.FailReasonLivenessFailed,
+			LatencyMs:        180,
+			ClientIP:         "10.0.0.7",
+			Provider:         "deepface",
+			ThresholdApplied: 0.85,
+			ThresholdSource:  domain.ThresholdSourceSegment,
+			CreatedAt:        time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC),
+			FaceMetadata:     map[string]interface{}{"ticket_type": "staff", "cpf": "123.456.789-00"},
+		}}
+		app := newApp(NewVerificationsHandler(getter, logger))
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/admin/verifications/"+id.String(), nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body AdminVerificationResponse
+		readResponseBody(t, resp, &body)
+		assert.Equal(t, tenant.ID, getter.gotTenant)
+		assert.Equal(t, id, getter.gotID)
+		assert.Equal(t, id.String(), body.VerificationID)
+		require.NotNil(t, body.FaceID)
+		assert.Equal(t, faceID.String(), *body.FaceID)
+		assert.False(t, body.Verified)
+		assert.Equal(t, 0.91, body.Similarity)
+		require.NotNil(t, body.ThresholdApplied)
+		assert.Equal(t, 0.85, *body.ThresholdApplied)
+		assert.Equal(t, domain.ThresholdSourceSegment, body.ThresholdSource)
+		require.NotNil(t, body.Liveness)
+		assert.False(t, body.Liveness.Passed)
+		assert.Equal(t, 0.42, *body.Liveness.Score)
+		assert.Equal(t, domain.FailReasonLivenessFailed, body.FailReason)
+		assert.Equal(t, "deepface", body.Provider)
+		assert.Equal(t, "10.0.0.7", body.ClientIP)
+		assert.Equal(t, int64(180), body.LatencyMs)
+		assert.Equal(t, map[string]interface{}{"ticket_type": "staff"}, body.FaceMetadata)
+	})
+
+	t.Run("deleted face and no liveness check", func(t *testing.T) {
+		id := uuid.New()
+		getter := &fakeVerificationGetter{verification: &domain.Verification{ID: id, ExternalID: "user-123", Verified: true, Confidence: 0.93}}
+		app := newApp(NewVerificationsHandler(getter, logger))
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/admin/verifications/"+id.String(), nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body map[string]interface{}
+		readResponseBody(t, resp, &body)
+		assert.Nil(t, body["face_id"])
+		assert.Nil(t, body["liveness"])
+		assert.Nil(t, body["threshold_applied"])
+		assert.NotContains(t, body, "face_metadata")
+	})
+
+	tests := []struct {
+		name       string
+		id         string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "not found", id: uuid.NewString(), err: domain.ErrVerificationNotFound, wantStatus: http.StatusNotFound, wantCode: "VERIFICATION_NOT_FOUND"},
+		{name: "invalid id", id: "not-a-uuid", wantStatus: http.StatusUnprocessableEntity, wantCode: "VALIDATION_FAILED"},
+		{name: "repository failure", id: uuid.NewString(), err: errors.New("connection reset"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newApp(NewVerificationsHandler(&fakeVerificationGetter{err: tt.err}, logger))
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/admin/verifications/"+tt.id, nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			if tt.wantCode != "" {
+				var body struct {
+					Error struct {
+						Code string `json:"code"`
+					} `json:"error"`
+				}
+				readResponseBody(t, resp, &body)
+				assert.Equal(t, tt.wantCode, body.Error.Code)
+			}
+		})
+	}
+}
@@ -1,9 +1,10 @@
 package admin
 
 import (
-	"crypto/rand"
-	"encoding/hex"
+	"errors"
+	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -27,23 +28,29 @@ type CreateWebhookRequest struct {
 	URL     string   `json:"url" validate:"required,url,max=2048"`
 	Events  []string `json:"events" validate:"required,min=1"`
 	Enabled bool     `json:"enabled"`
+	// Headers are static headers sent with every delivery, e.g. a gateway API key
+	Headers map[string]string `json:"headers"`
+	// SampleRates delivers 1 in N successful events per event type; failures are always delivered
+	SampleRates map[string]int `json:"sample_rates"`
 }
 
 type WebhookResponse struct {
-	ID              uuid.UUID `json:"id"`
-	Name            string    `json:"name"`
-	URL             string    `json:"url"`
-	Events          []string  `json:"events"`
-	Enabled         bool      `json:"enabled"`
-	LastTriggeredAt *string   `json:"last_triggered_at,omitempty"`
-	CreatedAt       string    `json:"created_at"`
-	UpdatedAt       string    `json:"updated_at"`
+	ID              uuid.UUID         `json:"id"`
+	Name            string            `json:"name"`
+	URL             string            `json:"url"`
+	Events          []string          `json:"events"`
+	Enabled         bool              `json:"enabled"`
+	Headers         map[string]string `json:"headers,omitempty"`
+	SampleRates     map[string]int    `json:"sample_rates,omitempty"`
+	LastTriggeredAt *string           `json:"last_triggered_at,omitempty"`
+	CreatedAt       string            `json:"created_at"`
+	UpdatedAt       string            `json:"updated_at"`
 }
 
 func (h *WebhooksHandler) List(c *fiber.Ctx) error {
 	tenantID := c.Locals("tenant_id").(uuid.UUID)
 
-	webhooks, err := h.service.GetWebhooksByTenant(c.Context(), tenantID)
+	webhooks, err := h.service.GetWebhooksByTenant(c.UserContext(), tenantID)
 	if err != nil {
 		h.logger.Error("failed to list webhooks", "tenant_id", tenantID, "error", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -65,6 +72,8 @@ func (h *WebhooksHandler) List(c *fiber.Ctx) error {
 			URL:             w.URL,
 			Events:          w.Events,
 			Enabled:         w.Enabled,
+			Headers:         webhook.RedactHeaders(w.Headers),
+			SampleRates:     w.SampleRates,
 			LastTriggeredAt: lastTriggered,
 			CreatedAt:       w.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 			UpdatedAt:       w.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
@@ -86,7 +95,18 @@ func (h *WebhooksHandler) Create(c *fiber.Ctx) error {
 		})
 	}
 
-	secret, err := generateSecret(32)
+	if err := webhook.ValidateHeaders(req.Headers); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err := webhook.ValidateSampleRates(req.SampleRates); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	secret, err := webhook.GenerateSecret()
 	if err != nil {
 		h.logger.Error("failed to generate secret", "error", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -95,15 +115,17 @@ func (h *WebhooksHandler) Create(c *fiber.Ctx) error {
 	}
 
 	w := &webhook.Webhook{
-		TenantID: tenantID,
-		Name:     req.Name,
-		URL:      req.URL,
-		Secret:   secret,
-		Events:   req.Events,
-		Enabled:  req.Enabled,
+		TenantID:    tenantID,
+		Name:        req.Name,
+		URL:         req.URL,
+		Secret:      secret,
+		Events:      req.Events,
+		Enabled:     req.Enabled,
+		Headers:     req.Headers,
+		SampleRates: req.SampleRates,
 	}
 
-	if err := h.service.CreateWebhook(c.Context(), w); err != nil {
+	if err := h.service.CreateWebhook(c.UserContext(), w); err != nil {
 		h.logger.Error("failed to create webhook", "tenant_id", tenantID, "error", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to create webhook",
@@ -118,18 +140,110 @@ func (h *WebhooksHandler) Create(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 		"webhook": WebhookResponse{
-			ID:        w.ID,
-			Name:      w.Name,
-			URL:       w.URL,
-			Events:    w.Events,
-			Enabled:   w.Enabled,
-			CreatedAt: w.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt: w.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			ID:      w.ID,
+			Name:    w.Name,
+			URL:     w.URL,
+			Events:  w.Events,
+			Enabled: w.Enabled,
+			Headers: webhook.RedactHeaders(w.Headers),
+
+			SampleRates: w.SampleRates,
+			CreatedAt:   w.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			UpdatedAt:   w.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		},
 		"secret": secret,
 	})
 }
 
+// BulkCreateWebhooksRequest holds the configs of the webhooks to create
+type BulkCreateWebhooksRequest struct {
+	Webhooks []CreateWebhookRequest `json:"webhooks"`
+}
+
+// BulkCreatedWebhook is a webhook created by BulkCreate with its secret, which is not shown again
+type BulkCreatedWebhook struct {
+	ID     uuid.UUID `json:"id"`
+	Name   string    `json:"name"`
+	URL    string    `json:"url"`
+	Secret string    `json:"secret"`
+}
+
+// BulkCreate creates up to webhook.MaxBulkWebhooks webhooks from the webhooks array in one
+// transaction, e.g. from infrastructure as code. Every config is validated first and any
+// invalid one rejects the whole batch, listing the errors by index.
+// POST /v1/admin/webhooks/bulk
+func (h *WebhooksHandler) BulkCreate(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(uuid.UUID)
+
+	var body BulkCreateWebhooksRequest
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	reqs := body.Webhooks
+	if len(reqs) == 0 || len(reqs) > webhook.MaxBulkWebhooks {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": fmt.Sprintf("Between 1 and %d webhooks are allowed", webhook.MaxBulkWebhooks),
+		})
+	}
+
+	webhooks := make([]*webhook.Webhook, len(reqs))
+	var invalid []string
+	for i, req := range reqs {
+		webhooks[i] = &webhook.Webhook{
+			TenantID:    tenantID,
+			Name:        req.Name,
+			URL:         req.URL,
+			Events:      req.Events,
+			Enabled:     req.Enabled,
+			Headers:     req.Headers,
+			SampleRates: req.SampleRates,
+		}
+		if err := webhook.ValidateWebhook(webhooks[i]); err != nil {
+			invalid = append(invalid, fmt.Sprintf("webhooks[%d]: %v", i, err))
+		}
+	}
+	if len(invalid) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":  "Invalid webhooks, none were created",
+			"errors": invalid,
+		})
+	}
+
+	for _, w := range webhooks {
+		secret, err := webhook.GenerateSecret()
+		if err != nil {
+			h.logger.Error("failed to generate secret", "error", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to generate webhook secret",
+			})
+		}
+		w.Secret = secret
+	}
+
+	if err := h.service.CreateWebhooks(c.UserContext(), webhooks); err != nil {
+		h.logger.Error("failed to bulk create webhooks", "tenant_id", tenantID, "count", len(webhooks), "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create webhooks",
+		})
+	}
+
+	created := make([]BulkCreatedWebhook, len(webhooks))
+	for i, w := range webhooks {
+		created[i] = BulkCreatedWebhook{ID: w.ID, Name: w.Name, URL: w.URL, Secret: w.Secret}
+	}
+
+	h.logger.Info("webhooks bulk created",
+		"tenant_id", tenantID,
+		"count", len(created),
+	)
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"webhooks": created,
+	})
+}
+
 func (h *WebhooksHandler) Delete(c *fiber.Ctx) error {
 	tenantID := c.Locals("tenant_id").(uuid.UUID)
 
@@ -141,7 +255,7 @@ func (h *WebhooksHandler) Delete(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.service.DeleteWebhook(c.Context(), tenantID, webhookID); err != nil {
+	if err := h.service.DeleteWebhook(c.UserContext(), tenantID, webhookID); err != nil {
 		h.logger.Error("failed to delete webhook",
 			"webhook_id", webhookID,
 			"tenant_id", tenantID,
@@ -160,10 +274,143 @@ func (h *WebhooksHandler) Delete(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusNoContent).Send(nil)
 }
 
-func generateSecret(length int) (string, error) {
-	bytes := make([]byte, length)
-	if _, err := rand.Read(bytes); err != nil {
-		return "", err
+// RotateSecretRequest optionally sets how long the replaced secret keeps signing deliveries,
+// as a Go duration (e.g. "48h"); defaults to webhook.DefaultSecretGracePeriod
+type RotateSecretRequest struct {
+	GracePeriod string `json:"grace_period"`
+}
+
+// RotateSecretResponse returns the new secret; it is not shown again
+type RotateSecretResponse struct {
+	Secret                  string `json:"secret"`
+	PreviousSecretExpiresAt string `json:"previous_secret_expires_at"`
+}
+
+// RotateSecret replaces the webhook's signing secret. Until previous_secret_expires_at,
+// deliveries also carry X-Rekko-Signature-Previous signed with the old secret.
+// POST /v1/admin/webhooks/:id/rotate-secret
+func (h *WebhooksHandler) RotateSecret(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(uuid.UUID)
+
+	webhookID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid webhook ID",
+		})
+	}
+
+	var req RotateSecretRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	grace := webhook.DefaultSecretGracePeriod
+	if req.GracePeriod != "" {
+		grace, err = time.ParseDuration(req.GracePeriod)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid grace_period",
+			})
+		}
+	}
+	if err := webhook.ValidateGracePeriod(grace); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	secret, expiresAt, err := h.service.RotateSecret(c.UserContext(), tenantID, webhookID, grace)
+	if errors.Is(err, webhook.ErrWebhookNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Webhook not found",
+		})
+	}
+	if err != nil {
+		h.logger.Error("failed to rotate webhook secret",
+			"webhook_id", webhookID,
+			"tenant_id", tenantID,
+			"error", err,
+		)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to rotate webhook secret",
+		})
+	}
+
+	h.logger.Info("webhook secret rotated",
+		"webhook_id", webhookID,
+		"tenant_id", tenantID,
+		"previous_secret_expires_at", expiresAt,
+	)
+
+	return c.JSON(RotateSecretResponse{
+		Secret:                  secret,
+		PreviousSecretExpiresAt: expiresAt.Format("2006-01-02T15:04:05Z07:00"),
+	})
+}
+
+// ValidateAllResponse holds one test delivery result per configured webhook
+type ValidateAllResponse struct {
+	Results []webhook.TestDeliveryResult `json:"results"`
+	Total   int                          `json:"total"`
+	Passed  int                          `json:"passed"`
+}
+
+// ValidateAll sends a test delivery to every webhook of the tenant and reports, per webhook,
+// whether the URL is reachable over valid TLS and the endpoint verifies signatures.
+// POST /v1/admin/webhooks/validate-all
+func (h *WebhooksHandler) ValidateAll(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(uuid.UUID)
+
+	results, err := h.service.ValidateAll(c.UserContext(), tenantID)
+	if err != nil {
+		h.logger.Error("failed to validate webhooks", "tenant_id", tenantID, "error", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to validate webhooks",
+		})
+	}
+
+	passed := 0
+	for _, r := range results {
+		if r.OK {
+			passed++
+		}
+	}
+
+	h.logger.Info("webhooks validated",
+		"tenant_id", tenantID,
+		"total", len(results),
+		"passed", passed,
+	)
+
+	return c.JSON(ValidateAllResponse{
+		Results: results,
+		Total:   len(results),
+		Passed:  passed,
+	})
+}
+
+// Schema returns a sample payload and JSON schema for an event type
+func (h *WebhooksHandler) Schema(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(uuid.UUID)
+	eventType := c.Params("event_type")
+
+	sample, ok := webhook.SamplePayload(tenantID, eventType)
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error":       "Unknown webhook event type",
+			"event_types": webhook.EventTypes(),
+		})
 	}
-	return hex.EncodeToString(bytes), nil
+	schema, _ := webhook.EventSchema(eventType)
+
+	return c.JSON(fiber.Map{
+		"event_type":      eventType,
+		"payload_version": webhook.PayloadVersion,
+		"sample":          sample,
+		"schema":          schema,
+	})
 }
@@ -0,0 +1,238 @@
+package admin
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/webhook"
+)
+
+func TestWebhooksHandler_Schema(t *testing.T) {
+	tenantID := uuid.New()
+	handler := NewWebhooksHandler(nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals(middleware.LocalTenantID, tenantID)
+		return c.Next()
+	})
+	app.Get("/v1/admin/webhooks/schema/:event_type", handler.Schema)
+
+	t.Run("returns sample and schema for a known event", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/admin/webhooks/schema/face.verified", nil))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body struct {
+			EventType      string                 `json:"event_type"`
+			PayloadVersion string                 `json:"payload_version"`
+			Sample         webhook.EventPayload   `json:"sample"`
+			Schema         map[string]interface{} `json:"schema"`
+		}
+		readResponseBody(t, resp, &body)
+
+		assert.Equal(t, webhook.EventFaceVerified, body.EventType)
+		assert.Equal(t, webhook.PayloadVersion, body.PayloadVersion)
+		assert.Equal(t, webhook.EventFaceVerified, body.Sample.Type)
+		assert.Equal(t, tenantID, body.Sample.TenantID)
+		assert.Equal(t, webhook.EventFaceVerified, body.Schema["title"])
+		assert.Contains(t, body.Schema["properties"], "data")
+	})
+
+	t.Run("unknown event lists the supported types", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/v1/admin/webhooks/schema/face.exploded", nil))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		var body struct {
+			EventTypes []string `json:"event_types"`
+		}
+		readResponseBody(t, resp, &body)
+		assert.Equal(t, webhook.EventTypes(), body.EventTypes)
+	})
+}
+
+func TestWebhooksHandler_Create_InvalidHeaders(t *testing.T) {
+	// Validation happens before the service is used
+	handler := NewWebhooksHandler(nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	app := setupTestApp(handler.Create, uuid.New())
+
+	body := `{"name":"crm","url":"https://crm.example.com/hooks","events":["face.registered"],"headers":{"X-Rekko-Event":"forged"}}`
+	req := httptest.NewRequest(http.MethodGet, "/test", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestWebhooksHandler_Create_InvalidSampleRates(t *testing.T) {
+	// Validation happens before the service is used
+	handler := NewWebhooksHandler(nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	app := setupTestApp(handler.Create, uuid.New())
+
+	body := `{"name":"gate","url":"https://gate.example.com/hooks","events":["face.verified"],"sample_rates":{"face.verified":0}}`
+	req := httptest.NewRequest(http.MethodGet, "/test", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestWebhooksHandler_Create_RedactsHeaders(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tenantID := uuid.New()
+	pool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer pool.Close()
+	now := time.Now()
+
+	pool.ExpectQuery(`INSERT INTO webhooks`).
+		WithArgs(tenantID, "crm", pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), []byte(`{"X-Api-Key":"gateway-key"}`), pgxmock.AnyArg(), true).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(uuid.New(), now, now))
+	handler := NewWebhooksHandler(webhook.NewServiceWithDB(pool, logger), logger)
+	app := setupTestApp(handler.Create, tenantID)
+
+	body := `{"name":"crm","url":"https://crm.example.com/hooks","events":["face.registered"],"enabled":true,"headers":{"X-Api-Key":"gateway-key"}}`
+	req := httptest.NewRequest(http.MethodGet, "/test", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var created struct {
+		Webhook WebhookResponse `json:"webhook"`
+	}
+	readResponseBody(t, resp, &created)
+	// The configured value is stored but never echoed back
+	assert.Equal(t, map[string]string{"X-Api-Key": webhook.RedactedHeaderValue}, created.Webhook.Headers)
+	assert.NoError(t, pool.ExpectationsWereMet())
+}
+
+func TestWebhooksHandler_RotateSecret_InvalidInput(t *testing.T) {
+	// Validation happens before the service is used
+	handler := NewWebhooksHandler(nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	tests := []struct {
+		name string
+		id   string
+		body string
+	}{
+		{name: "invalid webhook id", id: "not-a-uuid", body: ""},
+		{name: "unparseable grace period", id: uuid.NewString(), body: `{"grace_period":"tomorrow"}`},
+		{name: "negative grace period", id: uuid.NewString(), body: `{"grace_period":"-1h"}`},
+		{name: "grace period above max", id: uuid.NewString(), body: `{"grace_period":"200h"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Post("/webhooks/:id/rotate-secret", func(c *fiber.Ctx) error {
+				c.Locals("tenant_id", uuid.New())
+				return handler.RotateSecret(c)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks/"+tt.id+"/rotate-secret", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+	}
+}
+
+func TestWebhooksHandler_BulkCreate(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tenantID := uuid.New()
+
+	doBulk := func(handler *WebhooksHandler, body string) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, "/test", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := setupTestApp(handler.BulkCreate, tenantID).Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("creates the webhooks and returns their secrets once", func(t *testing.T) {
+		pool, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer pool.Close()
+		ids := []uuid.UUID{uuid.New(), uuid.New()}
+		now := time.Now()
+
+		pool.ExpectBegin()
+		for i, name := range []string{"crm", "gate"} {
+			pool.ExpectQuery(`INSERT INTO webhooks`).
+				WithArgs(tenantID, name, pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), true).
+				WillReturnRows(pgxmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(ids[i], now, now))
+		}
+		pool.ExpectCommit()
+		handler := NewWebhooksHandler(webhook.NewServiceWithDB(pool, logger), logger)
+
+		resp := doBulk(handler, `{"webhooks": [
+			{"name":"crm","url":"https://crm.example.com/hooks","events":["face.registered"],"enabled":true,"headers":{"X-Api-Key":"abc"}},
+			{"name":"gate","url":"https://gate.example.com/hooks","events":["face.verified"],"enabled":true,"sample_rates":{"face.verified":10}}
+		]}`)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var body struct {
+			Webhooks []BulkCreatedWebhook `json:"webhooks"`
+		}
+		readResponseBody(t, resp, &body)
+		require.Len(t, body.Webhooks, 2)
+		assert.Equal(t, ids[0], body.Webhooks[0].ID)
+		assert.Equal(t, "gate", body.Webhooks[1].Name)
+		assert.Len(t, body.Webhooks[0].Secret, 64)
+		assert.NotEqual(t, body.Webhooks[0].Secret, body.Webhooks[1].Secret)
+		assert.NoError(t, pool.ExpectationsWereMet())
+	})
+
+	t.Run("one invalid config aborts the batch", func(t *testing.T) {
+		// Validation happens before the service is used
+		handler := NewWebhooksHandler(nil, logger)
+
+		resp := doBulk(handler, `{"webhooks": [
+			{"name":"crm","url":"https://crm.example.com/hooks","events":["face.registered"]},
+			{"name":"gate","url":"gate.example.com","events":["face.verified"]},
+			{"name":"erp","url":"https://erp.example.com/hooks","events":["face.exploded"]}
+		]}`)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		var body struct {
+			Errors []string `json:"errors"`
+		}
+		readResponseBody(t, resp, &body)
+		require.Len(t, body.Errors, 2)
+		assert.Contains(t, body.Errors[0], "webhooks[1]")
+		assert.Contains(t, body.Errors[1], "webhooks[2]")
+	})
+
+	t.Run("rejects missing, empty and oversized batches", func(t *testing.T) {
+		handler := NewWebhooksHandler(nil, logger)
+
+		items := make([]string, webhook.MaxBulkWebhooks+1)
+		for i := range items {
+			items[i] = `{"name":"crm","url":"https://crm.example.com/hooks","events":["face.registered"]}`
+		}
+		for _, body := range []string{`{"name":"crm"}`, `{"webhooks": []}`, `{"webhooks": [` + strings.Join(items, ",") + `]}`, `not json`} {
+			resp := doBulk(handler, body)
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+// ErrorsHandler serves the catalog of API error codes
+type ErrorsHandler struct{}
+
+func NewErrorsHandler() *ErrorsHandler {
+	return &ErrorsHandler{}
+}
+
+// ErrorCatalogEntry describes one error code clients can receive
+type ErrorCatalogEntry struct {
+	Code    string `json:"code"`
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	// Messages holds the message by locale, including the default "en"
+	Messages map[string]string `json:"messages"`
+}
+
+// ErrorCatalogResponse lists every error code with its HTTP status and messages
+type ErrorCatalogResponse struct {
+	DefaultLocale string              `json:"default_locale"`
+	Errors        []ErrorCatalogEntry `json:"errors"`
+}
+
+// List returns the error catalog derived from the domain errors, so SDKs can generate typed errors.
+// Some responses replace the message with request details (e.g. the accepted image formats);
+// the code and status always match the catalog.
+// GET /v1/errors
+func (h *ErrorsHandler) List(c *fiber.Ctx) error {
+	catalog := domain.Catalog()
+	entries := make([]ErrorCatalogEntry, 0, len(catalog))
+	for _, e := range catalog {
+		entries = append(entries, ErrorCatalogEntry{
+			Code:     e.Code,
+			Status:   e.StatusCode,
+			Message:  e.Message,
+			Messages: e.Messages(),
+		})
+	}
+
+	c.Set(fiber.HeaderCacheControl, "public, max-age=3600")
+	return c.JSON(ErrorCatalogResponse{
+		DefaultLocale: domain.DefaultLocale,
+		Errors:        entries,
+	})
+}
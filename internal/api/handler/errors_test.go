@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+func TestErrorsHandler_List(t *testing.T) {
+	app := fiber.New()
+	app.Get("/v1/errors", NewErrorsHandler().List)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/v1/errors", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var body ErrorCatalogResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "en", body.DefaultLocale)
+	require.Len(t, body.Errors, len(domain.Catalog()))
+
+	byCode := make(map[string]ErrorCatalogEntry, len(body.Errors))
+	for _, e := range body.Errors {
+		byCode[e.Code] = e
+	}
+	notFound := byCode[domain.ErrFaceNotFound.Code]
+	assert.Equal(t, 404, notFound.Status)
+	assert.Equal(t, domain.ErrFaceNotFound.Message, notFound.Message)
+	assert.Equal(t, domain.ErrFaceNotFound.Message, notFound.Messages["en"])
+	assert.Equal(t, "Face não encontrada", notFound.Messages["pt-BR"])
+}
+
+// TestErrorCatalog_CoversHandlerErrors fails when a handler returns a domain error that
+// GET /v1/errors does not list
+func TestErrorCatalog_CoversHandlerErrors(t *testing.T) {
+	catalogFile, err := parser.ParseFile(token.NewFileSet(), "../../domain/errors_catalog.go", nil, 0)
+	require.NoError(t, err)
+	listed := make(map[string]bool)
+	ast.Inspect(catalogFile, func(n ast.Node) bool {
+		if fn, ok := n.(*ast.FuncDecl); ok && fn.Name.Name == "Catalog" {
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				if ident, ok := n.(*ast.Ident); ok {
+					listed[ident.Name] = true
+				}
+				return true
+			})
+		}
+		return true
+	})
+	require.NotEmpty(t, listed)
+
+	// domain.Err* referenced by this package and its admin/super subpackages
+	used := make(map[string]string)
+	fset := token.NewFileSet()
+	err = filepath.WalkDir(".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return err
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectorExpr)
+			if !ok || !strings.HasPrefix(sel.Sel.Name, "Err") {
+				return true
+			}
+			if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "domain" {
+				used[sel.Sel.Name] = path
+			}
+			return true
+		})
+		return nil
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, used)
+
+	for name, path := range used {
+		assert.True(t, listed[name], "domain.%s used in %s is missing from domain.Catalog()", name, path)
+	}
+}
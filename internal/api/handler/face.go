@@ -2,10 +2,12 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -15,27 +17,28 @@ import (
 
 	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
 	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/webhook"
 )
 
 const (
 	maxImageSize = 10 * 1024 * 1024 // 10MB
 )
 
-var validImageTypes = map[string]bool{
-	"image/jpeg": true,
-	"image/png":  true,
-	"image/webp": true,
-}
-
 // FaceService interface for the service
 type FaceService interface {
-	Register(ctx context.Context, tenantID uuid.UUID, externalID string, imageBytes []byte, requireLiveness bool, livenessThreshold float64) (*domain.Face, error)
-	Verify(ctx context.Context, tenantID uuid.UUID, externalID string, imageBytes []byte) (*domain.Verification, error)
+	Register(ctx context.Context, tenantID uuid.UUID, externalID string, imageBytes []byte, metadata map[string]interface{}, settings domain.TenantSettings) (*domain.Face, error)
+	Verify(ctx context.Context, tenantID uuid.UUID, externalID string, imageBytes []byte, settings domain.TenantSettings) (*domain.Verification, error)
+	VerifyDocument(ctx context.Context, tenantID uuid.UUID, liveImage, documentImage []byte, settings domain.TenantSettings) (*domain.DocumentVerification, error)
 	Delete(ctx context.Context, tenantID uuid.UUID, externalID string) error
-	CheckLiveness(ctx context.Context, imageBytes []byte, threshold float64) (*domain.LivenessResult, error)
+	CheckLiveness(ctx context.Context, tenantID uuid.UUID, imageBytes []byte, settings domain.TenantSettings) (*domain.LivenessResult, error)
+	CountFaces(ctx context.Context, tenantID uuid.UUID, imageBytes []byte, settings domain.TenantSettings) (int, error)
 	Search(ctx context.Context, tenant *domain.Tenant, imageBytes []byte, threshold float64, maxResults int, clientIP string) (*domain.SearchResult, error)
+	SearchByEmbedding(ctx context.Context, tenant *domain.Tenant, embedding []float64, threshold float64, maxResults int, clientIP string) (*domain.SearchResult, error)
 	GetByExternalID(ctx context.Context, tenantID uuid.UUID, externalID string) (*domain.Face, error)
-	List(ctx context.Context, tenantID uuid.UUID, limit, offset int) ([]*domain.Face, error)
+	List(ctx context.Context, tenantID uuid.UUID, filter domain.FaceListFilter) ([]*domain.Face, int, error)
+	ExistsBatch(ctx context.Context, tenantID uuid.UUID, externalIDs []string) (map[string]domain.RegistrationCheck, error)
+	RecentlyVerified(ctx context.Context, tenantID uuid.UUID, externalID string, within time.Duration, settings domain.TenantSettings) (*domain.RecentVerification, error)
+	BulkUpdateMetadata(ctx context.Context, tenantID uuid.UUID, externalIDs []string, patch map[string]interface{}) (*domain.BulkMetadataResult, error)
 }
 
 // UsageTracker interface for tracking usage metrics
@@ -83,9 +86,9 @@ func (h *FaceHandler) trackUsage(tenantID uuid.UUID, field string) {
 }
 
 // dispatchFaceEvent dispatches a face event to webhooks (best-effort, async)
-func (h *FaceHandler) dispatchFaceEvent(tenantID uuid.UUID, eventType string, data interface{}) {
+func (h *FaceHandler) dispatchFaceEvent(requestID string, tenantID uuid.UUID, eventType string, data interface{}) {
 	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := context.WithTimeout(webhook.WithRequestID(context.Background(), requestID), 5*time.Second)
 		defer cancel()
 
 		if err := h.webhookService.Dispatch(ctx, tenantID, eventType, data); err != nil {
@@ -104,14 +107,35 @@ type RegisterResponse struct {
 	ExternalID   string  `json:"external_id"`
 	QualityScore float64 `json:"quality_score"`
 	CreatedAt    string  `json:"created_at"`
+	// ExpiresAt is only present for faces registered with a TTL
+	ExpiresAt *string `json:"expires_at,omitempty"`
+	// EmbeddingHash is only present when the tenant enables embedding_hash
+	EmbeddingHash string `json:"embedding_hash,omitempty"`
 }
 
 // VerifyResponse response for verify endpoint
 type VerifyResponse struct {
 	Verified       bool    `json:"verified"`
 	Confidence     float64 `json:"confidence"`
+	MatchPassed    bool    `json:"match_passed"`
+	LivenessPassed *bool   `json:"liveness_passed,omitempty"` // omitted when the verify policy skips liveness
+	FailReason     string  `json:"fail_reason,omitempty"`     // match_below_threshold or liveness_failed, omitted when verified
 	VerificationID string  `json:"verification_id"`
 	LatencyMs      int64   `json:"latency_ms"`
+	// ThresholdApplied is the threshold the match was decided against, resolved from ThresholdSource
+	ThresholdApplied float64                `json:"threshold_applied"`
+	ThresholdSource  domain.ThresholdSource `json:"threshold_source"`
+	// ProviderVariant is the provider experiment arm that decided, omitted outside experiments
+	ProviderVariant string `json:"provider_variant,omitempty"`
+}
+
+// VerifyDocumentResponse response for the document verify endpoint. Nothing is stored, so
+// there is no verification_id.
+type VerifyDocumentResponse struct {
+	Verified   bool    `json:"verified"`
+	Similarity float64 `json:"similarity"`
+	Threshold  float64 `json:"threshold"`
+	LatencyMs  int64   `json:"latency_ms"`
 }
 
 // LivenessResponse response for liveness check endpoint
@@ -130,19 +154,34 @@ type LivenessChecksResponse struct {
 	SingleFace   bool `json:"single_face"`
 }
 
+// CountResponse response for the crowd count endpoint. It deliberately carries
+// no bounding boxes or face attributes, only the headcount.
+type CountResponse struct {
+	FaceCount int `json:"face_count"`
+}
+
 // SearchResponse response for search endpoint
 type SearchResponse struct {
 	Matches    []SearchMatchResponse `json:"matches"`
 	TotalFaces int                   `json:"total_faces"`
 	LatencyMs  int64                 `json:"latency_ms"`
 	SearchID   string                `json:"search_id"`
+	Reason     string                `json:"reason,omitempty"` // e.g. empty_collection when no search was needed
+	// Ambiguous is set when the top two matches are closer than the tenant's search_margin
+	Ambiguous bool `json:"ambiguous"`
+	// ThresholdApplied is the minimum similarity of the matches, resolved from ThresholdSource
+	ThresholdApplied float64                `json:"threshold_applied"`
+	ThresholdSource  domain.ThresholdSource `json:"threshold_source"`
+	// ProviderVariant is the provider experiment arm that searched, omitted outside experiments
+	ProviderVariant string `json:"provider_variant,omitempty"`
 }
 
 // SearchMatchResponse represents a single match in search results
 type SearchMatchResponse struct {
-	ExternalID string  `json:"external_id"`
-	FaceID     string  `json:"face_id"`
-	Similarity float64 `json:"similarity"`
+	ExternalID string                 `json:"external_id"`
+	FaceID     string                 `json:"face_id"`
+	Similarity float64                `json:"similarity"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"` // only the tenant's search_metadata_keys
 }
 
 // FaceResponse response for get face endpoint
@@ -152,6 +191,9 @@ type FaceResponse struct {
 	QualityScore float64 `json:"quality_score"`
 	CreatedAt    string  `json:"created_at"`
 	UpdatedAt    string  `json:"updated_at"`
+	ExpiresAt    *string `json:"expires_at,omitempty"`
+	// EmbeddingHash is only present when the tenant enables embedding_hash
+	EmbeddingHash string `json:"embedding_hash,omitempty"`
 }
 
 // Register POST /v1/faces - register a new face
@@ -162,70 +204,124 @@ func (h *FaceHandler) Register(c *fiber.Ctx) error {
 		return err
 	}
 
-	// 2. Extract external_id from form
+	// 2. Extract liveness and image settings from tenant
+	settings := tenant.GetSettings()
+	if err := rejectUnknownFields(c, settings.StrictMultipartFields, "external_id", "metadata", "image", "ttl"); err != nil {
+		return err
+	}
+
+	// 3. Extract external_id from form
 	externalID := strings.TrimSpace(c.FormValue("external_id"))
 	if externalID == "" {
 		return domain.ErrValidationFailed.WithError(errors.New("external_id is required"))
 	}
 
-	// 3. Extract and validate image
-	imageBytes, err := extractAndValidateImage(c)
+	// 4. Extract optional metadata (JSON object)
+	var metadata map[string]interface{}
+	if raw := strings.TrimSpace(c.FormValue("metadata")); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &metadata); err != nil {
+			return domain.ErrValidationFailed.WithError(errors.New("metadata must be a JSON object"))
+		}
+	}
+
+	// 5. Extract optional ttl (Go duration), overriding the tenant's face_ttl for this face
+	if raw := strings.TrimSpace(c.FormValue("ttl")); raw != "" {
+		ttl, err := domain.ParseFaceTTL(raw)
+		if err != nil {
+			return domain.ErrValidationFailed.WithError(err)
+		}
+		settings.FaceTTL = ttl
+	}
+
+	// 6. Extract and validate image
+	imageBytes, err := extractAndValidateImage(c, settings.AllowedImageFormats)
 	if err != nil {
 		return fmt.Errorf("register face: %w", err)
 	}
 
-	// 4. Extract liveness settings from tenant
-	settings := extractTenantSettings(tenant)
-
-	// 5. Call service to register
-	face, err := h.service.Register(c.Context(), tenant.ID, externalID, imageBytes, settings.RequireLiveness, settings.LivenessThreshold)
+	// 7. Call service to register
+	face, err := h.service.Register(c.UserContext(), tenant.ID, externalID, imageBytes, metadata, settings)
 	if err != nil {
 		return err
 	}
 
-	// 6. Track usage (async, best-effort)
+	// 8. Track usage (async, best-effort)
 	h.trackUsage(tenant.ID, "registrations")
 
-	// 7. Dispatch webhook event (async, best-effort)
-	h.dispatchFaceEvent(tenant.ID, "face.registered", map[string]interface{}{
-		"face_id":       face.ID.String(),
-		"external_id":   face.ExternalID,
-		"quality_score": face.QualityScore,
-	})
-
-	// 8. Return response
-	return c.Status(fiber.StatusCreated).JSON(RegisterResponse{
+	// 9. Dispatch webhook event (async, best-effort)
+	h.dispatchFaceEvent(middleware.GetRequestID(c), tenant.ID, webhook.EventFaceRegistered, webhook.FaceRegisteredData{
 		FaceID:       face.ID.String(),
 		ExternalID:   face.ExternalID,
 		QualityScore: face.QualityScore,
-		CreatedAt:    face.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	})
+
+	// 10. Return response
+	return c.Status(fiber.StatusCreated).JSON(RegisterResponse{
+		FaceID:        face.ID.String(),
+		ExternalID:    face.ExternalID,
+		QualityScore:  face.QualityScore,
+		CreatedAt:     face.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		ExpiresAt:     formatExpiresAt(face.ExpiresAt),
+		EmbeddingHash: responseEmbeddingHash(settings.Features(), face),
 	})
 }
 
+// responseEmbeddingHash returns the face's embedding hash when the tenant enables embedding_hash
+func responseEmbeddingHash(features domain.Features, face *domain.Face) string {
+	if !features.EmbeddingHash {
+		return ""
+	}
+	return face.EmbeddingHash
+}
+
+// tenantFeatures returns the feature flags of the authenticated tenant, all off when the
+// context carries only the tenant ID
+func tenantFeatures(c *fiber.Ctx) domain.Features {
+	tenant, err := middleware.GetTenant(c)
+	if err != nil {
+		return domain.Features{}
+	}
+	return tenant.GetSettings().Features()
+}
+
+// formatExpiresAt formats the expiry of a face registered with a TTL, nil when it never expires
+func formatExpiresAt(expiresAt *time.Time) *string {
+	if expiresAt == nil {
+		return nil
+	}
+	formatted := expiresAt.Format("2006-01-02T15:04:05Z")
+	return &formatted
+}
+
 // Verify POST /v1/faces/verify - verify face 1:1
 func (h *FaceHandler) Verify(c *fiber.Ctx) error {
 	start := time.Now()
 
-	// 1. Extract tenant_id from context
-	tenantID, err := middleware.GetTenantID(c)
+	// 1. Extract tenant from context
+	tenant, err := middleware.GetTenant(c)
 	if err != nil {
 		return err
 	}
+	tenantID := tenant.ID
 
 	// 2. Extract external_id from form
+	settings := tenant.GetSettings()
+	if err := rejectUnknownFields(c, settings.StrictMultipartFields, "external_id", "image"); err != nil {
+		return err
+	}
 	externalID := strings.TrimSpace(c.FormValue("external_id"))
 	if externalID == "" {
 		return domain.ErrValidationFailed.WithError(errors.New("external_id is required"))
 	}
 
 	// 3. Extract and validate image
-	imageBytes, err := extractAndValidateImage(c)
+	imageBytes, err := extractAndValidateImage(c, settings.AllowedImageFormats)
 	if err != nil {
 		return fmt.Errorf("verify face: %w", err)
 	}
 
 	// 4. Call service to verify
-	verification, err := h.service.Verify(c.Context(), tenantID, externalID, imageBytes)
+	verification, err := h.service.Verify(c.UserContext(), tenantID, externalID, imageBytes, settings)
 	if err != nil {
 		return err
 	}
@@ -235,23 +331,79 @@ func (h *FaceHandler) Verify(c *fiber.Ctx) error {
 
 	// 6. Dispatch webhook event (async, best-effort)
 	elapsed := time.Since(start)
-	h.dispatchFaceEvent(tenantID, "face.verified", map[string]interface{}{
-		"verified":    verification.Verified,
-		"confidence":  verification.Confidence,
-		"external_id": externalID,
-		"latency_ms":  elapsed.Milliseconds(),
+	h.dispatchFaceEvent(middleware.GetRequestID(c), tenantID, webhook.EventFaceVerified, webhook.FaceVerifiedData{
+		Verified:   verification.Verified,
+		Confidence: verification.Confidence,
+		ExternalID: externalID,
+		LatencyMs:  elapsed.Milliseconds(),
 	})
+	if suggestion := verification.ReenrollSuggested; suggestion != nil {
+		h.dispatchFaceEvent(middleware.GetRequestID(c), tenantID, webhook.EventFaceReenrollSuggested, webhook.FaceReenrollSuggestedData{
+			ExternalID:        externalID,
+			EnrolledQuality:   suggestion.EnrolledQuality,
+			Margin:            suggestion.Margin,
+			RecentConfidences: suggestion.RecentConfidences,
+		})
+	}
 
 	// 7. Return response
 	return c.JSON(VerifyResponse{
 		Verified:       verification.Verified,
 		Confidence:     verification.Confidence,
+		MatchPassed:    verification.MatchPassed,
+		LivenessPassed: verification.LivenessPassed,
+		FailReason:     verification.FailReason,
 		VerificationID: verification.ID.String(),
 		LatencyMs:      verification.LatencyMs,
+
+		ThresholdApplied: verification.ThresholdApplied,
+		ThresholdSource:  verification.ThresholdSource,
+		ProviderVariant:  verification.ProviderVariant,
+	})
+}
+
+// VerifyDocument POST /v1/faces/verify-document - 1:1 match of a live capture against an ID
+// document photo, without enrolling or storing either image
+func (h *FaceHandler) VerifyDocument(c *fiber.Ctx) error {
+	// 1. Extract tenant from context
+	tenant, err := middleware.GetTenant(c)
+	if err != nil {
+		return err
+	}
+
+	// 2. Extract and validate both images
+	settings := tenant.GetSettings()
+	if err := rejectUnknownFields(c, settings.StrictMultipartFields, "image", "document"); err != nil {
+		return err
+	}
+	liveImage, err := extractAndValidateFormImage(c, "image", settings.AllowedImageFormats)
+	if err != nil {
+		return fmt.Errorf("verify document: %w", err)
+	}
+	documentImage, err := extractAndValidateFormImage(c, "document", settings.AllowedImageFormats)
+	if err != nil {
+		return fmt.Errorf("verify document: %w", err)
+	}
+
+	// 3. Compare; nothing is stored
+	result, err := h.service.VerifyDocument(c.UserContext(), tenant.ID, liveImage, documentImage, settings)
+	if err != nil {
+		return err
+	}
+
+	// 4. Track usage (async, best-effort)
+	h.trackUsage(tenant.ID, "verifications")
+
+	return c.JSON(VerifyDocumentResponse{
+		Verified:   result.Verified,
+		Similarity: result.Similarity,
+		Threshold:  result.Threshold,
+		LatencyMs:  result.LatencyMs,
 	})
 }
 
 // Delete DELETE /v1/faces/:external_id - delete face (LGPD)
+// With ?idempotent=true a missing face also returns 204, so erasure retries are safe
 func (h *FaceHandler) Delete(c *fiber.Ctx) error {
 	// 1. Extract tenant_id from context
 	tenantID, err := middleware.GetTenantID(c)
@@ -266,13 +418,17 @@ func (h *FaceHandler) Delete(c *fiber.Ctx) error {
 	}
 
 	// 3. Call service to delete
-	if err := h.service.Delete(c.Context(), tenantID, externalID); err != nil {
+	if err := h.service.Delete(c.UserContext(), tenantID, externalID); err != nil {
+		// Nothing was deleted, so no webhook is dispatched
+		if errors.Is(err, domain.ErrFaceNotFound) && c.QueryBool("idempotent") {
+			return c.SendStatus(fiber.StatusNoContent)
+		}
 		return err
 	}
 
 	// 4. Dispatch webhook event (async, best-effort)
-	h.dispatchFaceEvent(tenantID, "face.deleted", map[string]interface{}{
-		"external_id": externalID,
+	h.dispatchFaceEvent(middleware.GetRequestID(c), tenantID, webhook.EventFaceDeleted, webhook.FaceDeletedData{
+		ExternalID: externalID,
 	})
 
 	// 5. Return 204 No Content
@@ -294,21 +450,55 @@ func (h *FaceHandler) GetByExternalID(c *fiber.Ctx) error {
 	}
 
 	// 3. Call service to get face
-	face, err := h.service.GetByExternalID(c.Context(), tenantID, externalID)
+	face, err := h.service.GetByExternalID(c.UserContext(), tenantID, externalID)
 	if err != nil {
 		return err
 	}
 
 	// 4. Return response
 	return c.JSON(FaceResponse{
-		FaceID:       face.ID.String(),
-		ExternalID:   face.ExternalID,
-		QualityScore: face.QualityScore,
-		CreatedAt:    face.CreatedAt.Format("2006-01-02T15:04:05Z"),
-		UpdatedAt:    face.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+		FaceID:        face.ID.String(),
+		ExternalID:    face.ExternalID,
+		QualityScore:  face.QualityScore,
+		CreatedAt:     face.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:     face.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+		ExpiresAt:     formatExpiresAt(face.ExpiresAt),
+		EmbeddingHash: responseEmbeddingHash(tenantFeatures(c), face),
 	})
 }
 
+// RecentlyVerified GET /v1/faces/:external_id/recently-verified?within=15m - check for a recent successful verification
+// within is a Go duration (default 15m), capped by MAX_VERIFICATION_AGE
+func (h *FaceHandler) RecentlyVerified(c *fiber.Ctx) error {
+	// 1. Extract tenant from context
+	tenant, err := middleware.GetTenant(c)
+	if err != nil {
+		return err
+	}
+
+	// 2. Extract external_id and window
+	externalID := strings.TrimSpace(c.Params("external_id"))
+	if externalID == "" {
+		return domain.ErrValidationFailed.WithError(errors.New("external_id is required"))
+	}
+
+	within := domain.DefaultRecentVerificationWindow
+	if raw := c.Query("within"); raw != "" {
+		within, err = time.ParseDuration(raw)
+		if err != nil {
+			return domain.ErrValidationFailed.WithError(fmt.Errorf("invalid within: %w", err))
+		}
+	}
+
+	// 3. Call service
+	result, err := h.service.RecentlyVerified(c.UserContext(), tenant.ID, externalID, within, tenant.GetSettings())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(result)
+}
+
 // CheckLiveness POST /v1/faces/liveness - check if image contains a live person
 func (h *FaceHandler) CheckLiveness(c *fiber.Ctx) error {
 	// 1. Extract tenant from context
@@ -317,17 +507,20 @@ func (h *FaceHandler) CheckLiveness(c *fiber.Ctx) error {
 		return err
 	}
 
-	// 2. Extract and validate image
-	imageBytes, err := extractAndValidateImage(c)
+	// 2. Get image formats and liveness threshold from tenant settings
+	settings := tenant.GetSettings()
+	if err := rejectUnknownFields(c, settings.StrictMultipartFields, "image"); err != nil {
+		return err
+	}
+
+	// 3. Extract and validate image
+	imageBytes, err := extractAndValidateImage(c, settings.AllowedImageFormats)
 	if err != nil {
 		return fmt.Errorf("check liveness: %w", err)
 	}
 
-	// 3. Get liveness threshold from tenant settings
-	settings := extractTenantSettings(tenant)
-
 	// 4. Call provider to check liveness
-	result, err := h.service.CheckLiveness(c.Context(), imageBytes, settings.LivenessThreshold)
+	result, err := h.service.CheckLiveness(c.UserContext(), tenant.ID, imageBytes, settings)
 	if err != nil {
 		return err
 	}
@@ -349,6 +542,33 @@ func (h *FaceHandler) CheckLiveness(c *fiber.Ctx) error {
 	})
 }
 
+// Count POST /v1/faces/count - anonymized headcount of the faces in an image
+func (h *FaceHandler) Count(c *fiber.Ctx) error {
+	// 1. Extract tenant from context
+	tenant, err := middleware.GetTenant(c)
+	if err != nil {
+		return err
+	}
+
+	// 2. Extract and validate image
+	settings := tenant.GetSettings()
+	if err := rejectUnknownFields(c, settings.StrictMultipartFields, "image"); err != nil {
+		return err
+	}
+	imageBytes, err := extractAndValidateImage(c, settings.AllowedImageFormats)
+	if err != nil {
+		return fmt.Errorf("count faces: %w", err)
+	}
+
+	// 3. Detect faces; no identity is computed and nothing is stored
+	count, err := h.service.CountFaces(c.UserContext(), tenant.ID, imageBytes, settings)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(CountResponse{FaceCount: count})
+}
+
 // Search POST /v1/faces/search - search for similar faces (1:N)
 func (h *FaceHandler) Search(c *fiber.Ctx) error {
 	// 1. Extract tenant from context
@@ -358,7 +578,11 @@ func (h *FaceHandler) Search(c *fiber.Ctx) error {
 	}
 
 	// 2. Extract and validate image
-	imageBytes, err := extractAndValidateImage(c)
+	settings := tenant.GetSettings()
+	if err := rejectUnknownFields(c, settings.StrictMultipartFields, "image", "threshold", "max_results"); err != nil {
+		return err
+	}
+	imageBytes, err := extractAndValidateImage(c, settings.AllowedImageFormats)
 	if err != nil {
 		return fmt.Errorf("search faces: %w", err)
 	}
@@ -371,7 +595,7 @@ func (h *FaceHandler) Search(c *fiber.Ctx) error {
 	clientIP := c.IP()
 
 	// 5. Call service
-	result, err := h.service.Search(c.Context(), tenant, imageBytes, threshold, maxResults, clientIP)
+	result, err := h.service.Search(c.UserContext(), tenant, imageBytes, threshold, maxResults, clientIP)
 	if err != nil {
 		return err
 	}
@@ -379,44 +603,149 @@ func (h *FaceHandler) Search(c *fiber.Ctx) error {
 	// 6. Track usage (async)
 	h.trackUsage(tenant.ID, "searches")
 
-	// 7. Convert matches to response
+	// 7. Dispatch webhook and return result
+	return h.respondSearch(c, tenant.ID, result, settings.SearchMetadataKeys)
+}
+
+// SearchByEmbeddingRequest request for searching with a caller-supplied embedding
+type SearchByEmbeddingRequest struct {
+	Embedding  []float64 `json:"embedding"`
+	Threshold  float64   `json:"threshold"`
+	MaxResults int       `json:"max_results"`
+}
+
+// SearchByEmbedding POST /v1/faces/search-by-embedding - search with an embedding extracted on-device
+// @Summary Search faces by embedding
+// @Description Runs a 1:N search with a 512-dimension embedding instead of an image. Skips liveness, so it requires search_by_embedding_enabled on the tenant
+// @Tags faces
+// @Accept json
+// @Produce json
+// @Param request body SearchByEmbeddingRequest true "Embedding and search parameters"
+// @Success 200 {object} SearchResponse
+// @Failure 401 {object} domain.AppError
+// @Failure 403 {object} domain.AppError
+// @Failure 422 {object} domain.AppError
+// @Failure 429 {object} domain.AppError
+// @Router /v1/faces/search-by-embedding [post]
+func (h *FaceHandler) SearchByEmbedding(c *fiber.Ctx) error {
+	// 1. Extract tenant from context
+	tenant, err := middleware.GetTenant(c)
+	if err != nil {
+		return err
+	}
+
+	// 2. Parse JSON body
+	var req SearchByEmbeddingRequest
+	if err := c.BodyParser(&req); err != nil {
+		return domain.ErrValidationFailed.WithError(fmt.Errorf("invalid request body: %w", err))
+	}
+
+	// 3. Call service (validates embedding dimension and tenant opt-in)
+	result, err := h.service.SearchByEmbedding(c.UserContext(), tenant, req.Embedding, req.Threshold, req.MaxResults, c.IP())
+	if err != nil {
+		return err
+	}
+
+	// 4. Track usage (async)
+	h.trackUsage(tenant.ID, "searches")
+
+	// 5. Dispatch webhook and return result
+	return h.respondSearch(c, tenant.ID, result, tenant.GetSettings().SearchMetadataKeys)
+}
+
+// respondSearch dispatches the face.search event and writes the search response.
+// Match metadata is limited to metadataKeys, since it may hold PII the caller should not see.
+func (h *FaceHandler) respondSearch(c *fiber.Ctx, tenantID uuid.UUID, result *domain.SearchResult, metadataKeys []string) error {
+	// Convert matches to response
 	matches := make([]SearchMatchResponse, len(result.Matches))
 	for i, m := range result.Matches {
 		matches[i] = SearchMatchResponse{
 			ExternalID: m.ExternalID,
 			FaceID:     m.FaceID.String(),
 			Similarity: m.Similarity,
+			Metadata:   m.AllowedMetadata(metadataKeys),
 		}
 	}
 
-	// 8. Dispatch webhook event (async, best-effort)
-	var topMatch map[string]interface{}
+	// Dispatch webhook event (async, best-effort)
+	var topMatch *webhook.SearchMatch
 	if len(result.Matches) > 0 {
-		topMatch = map[string]interface{}{
-			"face_id":    result.Matches[0].FaceID.String(),
-			"similarity": result.Matches[0].Similarity,
+		topMatch = &webhook.SearchMatch{
+			FaceID:     result.Matches[0].FaceID.String(),
+			Similarity: result.Matches[0].Similarity,
 		}
 	}
 
-	h.dispatchFaceEvent(tenant.ID, "face.search", map[string]interface{}{
-		"matches_count": len(result.Matches),
-		"top_match":     topMatch,
-		"search_id":     result.SearchID.String(),
+	h.dispatchFaceEvent(middleware.GetRequestID(c), tenantID, webhook.EventFaceSearch, webhook.FaceSearchData{
+		MatchesCount: len(result.Matches),
+		TopMatch:     topMatch,
+		SearchID:     result.SearchID.String(),
 	})
 
-	// 9. Return result
+	if result.Warning != "" {
+		c.Set("X-Rekko-Warning", result.Warning)
+	}
+
 	return c.JSON(SearchResponse{
 		Matches:    matches,
 		TotalFaces: result.TotalFaces,
 		LatencyMs:  result.LatencyMs,
 		SearchID:   result.SearchID.String(),
+		Reason:     result.Reason,
+		Ambiguous:  result.Ambiguous,
+
+		ThresholdApplied: result.ThresholdApplied,
+		ThresholdSource:  result.ThresholdSource,
+		ProviderVariant:  result.ProviderVariant,
 	})
 }
 
-// extractAndValidateImage extracts and validates the image from the form
-func extractAndValidateImage(c *fiber.Ctx) ([]byte, error) {
+// rejectUnknownFields fails with VALIDATION_FAILED listing multipart fields outside known,
+// for tenants with strict_multipart_fields. Lenient tenants keep ignoring extras.
+func rejectUnknownFields(c *fiber.Ctx, strict bool, known ...string) error {
+	if !strict {
+		return nil
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		// Not a multipart body; image extraction reports the missing file
+		return nil
+	}
+
+	var unknown []string
+	for name := range form.Value {
+		if !slices.Contains(known, name) {
+			unknown = append(unknown, name)
+		}
+	}
+	for name := range form.File {
+		if !slices.Contains(known, name) {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	slices.Sort(unknown)
+	return (&domain.AppError{
+		Code:       domain.ErrValidationFailed.Code,
+		Message:    fmt.Sprintf("Unexpected form fields: %s", strings.Join(unknown, ", ")),
+		StatusCode: domain.ErrValidationFailed.StatusCode,
+	}).WithReasons(unknown...)
+}
+
+// extractAndValidateImage extracts and validates the image from the form,
+// accepting only the given Content-Types
+func extractAndValidateImage(c *fiber.Ctx, allowedFormats []string) ([]byte, error) {
+	return extractAndValidateFormImage(c, "image", allowedFormats)
+}
+
+// extractAndValidateFormImage is extractAndValidateImage for the file in the given form field
+func extractAndValidateFormImage(c *fiber.Ctx, field string, allowedFormats []string) ([]byte, error) {
 	// 1. Extract file
-	file, err := c.FormFile("image")
+	file, err := c.FormFile(field)
 	if err != nil {
 		return nil, domain.ErrValidationFailed.WithError(err)
 	}
@@ -432,8 +761,12 @@ func extractAndValidateImage(c *fiber.Ctx) ([]byte, error) {
 
 	// 3. Validate Content-Type
 	contentType := file.Header.Get("Content-Type")
-	if !validImageTypes[contentType] {
-		return nil, domain.ErrInvalidImage.WithError(nil)
+	if !slices.Contains(allowedFormats, contentType) {
+		return nil, &domain.AppError{
+			Code:       domain.ErrInvalidImage.Code,
+			Message:    fmt.Sprintf("Image format not allowed, accepted formats: %s", strings.Join(allowedFormats, ", ")),
+			StatusCode: domain.ErrInvalidImage.StatusCode,
+		}
 	}
 
 	// 4. Read image bytes
@@ -453,52 +786,34 @@ func extractAndValidateImage(c *fiber.Ctx) ([]byte, error) {
 	return imageBytes, nil
 }
 
-// extractTenantSettings extracts TenantSettings from tenant Settings map
-func extractTenantSettings(tenant *domain.Tenant) domain.TenantSettings {
-	settings := domain.DefaultTenantSettings()
-
-	if tenant.Settings == nil {
-		return settings
-	}
-
-	// Extract verification_threshold
-	if val, ok := tenant.Settings["verification_threshold"].(float64); ok {
-		settings.VerificationThreshold = val
-	}
-
-	// Extract max_faces_per_user
-	if val, ok := tenant.Settings["max_faces_per_user"].(float64); ok {
-		settings.MaxFacesPerUser = int(val)
-	}
-
-	// Extract require_liveness
-	if val, ok := tenant.Settings["require_liveness"].(bool); ok {
-		settings.RequireLiveness = val
-	}
-
-	// Extract liveness_threshold
-	if val, ok := tenant.Settings["liveness_threshold"].(float64); ok {
-		settings.LivenessThreshold = val
-	}
-
-	return settings
-}
-
 // ListFacesResponse response for listing faces
 type ListFacesResponse struct {
 	Faces []FaceResponse `json:"faces"`
-	Total int            `json:"total"`
+	// Total is the number of faces in this page; Pagination.Total counts the whole range
+	Total      int                 `json:"total"`
+	Pagination FacesPaginationMeta `json:"pagination"`
+}
+
+// FacesPaginationMeta pagination of the face listing
+type FacesPaginationMeta struct {
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
 }
 
 // List GET /v1/faces - list all faces for the tenant
 // @Summary List faces
-// @Description Returns all faces registered for the authenticated tenant
+// @Description Returns the faces registered for the authenticated tenant, most recent first,
+// @Description optionally restricted to those registered in [registered_from, registered_to)
 // @Tags faces
 // @Produce json
+// @Param registered_from query string false "Only faces registered at or after this RFC3339 timestamp"
+// @Param registered_to query string false "Only faces registered before this RFC3339 timestamp"
 // @Param limit query int false "Maximum number of results (default 50, max 100)"
 // @Param offset query int false "Offset for pagination"
 // @Success 200 {object} ListFacesResponse
 // @Failure 401 {object} domain.AppError
+// @Failure 422 {object} domain.AppError
 // @Router /v1/faces [get]
 func (h *FaceHandler) List(c *fiber.Ctx) error {
 	// 1. Get tenant ID from context
@@ -508,28 +823,189 @@ func (h *FaceHandler) List(c *fiber.Ctx) error {
 	}
 
 	// 2. Parse query params
-	limit, _ := strconv.Atoi(c.Query("limit", "50"))
-	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+	filter, err := parseFaceListFilter(c)
+	if err != nil {
+		return err
+	}
 
 	// 3. Call service
-	faces, err := h.service.List(c.Context(), tenantID, limit, offset)
+	faces, total, err := h.service.List(c.UserContext(), tenantID, filter)
 	if err != nil {
 		return err
 	}
 
 	// 4. Convert to response
+	features := tenantFeatures(c)
 	response := make([]FaceResponse, 0, len(faces))
 	for _, face := range faces {
 		response = append(response, FaceResponse{
-			FaceID:       face.ID.String(),
-			ExternalID:   face.ExternalID,
-			QualityScore: face.QualityScore,
-			CreatedAt:    face.CreatedAt.Format("2006-01-02T15:04:05Z"),
+			FaceID:        face.ID.String(),
+			ExternalID:    face.ExternalID,
+			QualityScore:  face.QualityScore,
+			CreatedAt:     face.CreatedAt.Format("2006-01-02T15:04:05Z"),
+			ExpiresAt:     formatExpiresAt(face.ExpiresAt),
+			EmbeddingHash: responseEmbeddingHash(features, face),
 		})
 	}
 
 	return c.JSON(ListFacesResponse{
 		Faces: response,
 		Total: len(response),
+		Pagination: FacesPaginationMeta{
+			Total:  total,
+			Limit:  filter.Limit,
+			Offset: filter.Offset,
+		},
+	})
+}
+
+// parseFaceListFilter reads the registration range and pagination query parameters
+func parseFaceListFilter(c *fiber.Ctx) (domain.FaceListFilter, error) {
+	limit, _ := strconv.Atoi(c.Query("limit", strconv.Itoa(domain.DefaultFaceListLimit)))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+	filter := domain.FaceListFilter{Limit: limit, Offset: offset}
+
+	for _, bound := range []struct {
+		param string
+		dst   **time.Time
+	}{
+		{"registered_from", &filter.RegisteredFrom},
+		{"registered_to", &filter.RegisteredTo},
+	} {
+		raw := c.Query(bound.param)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return filter, domain.ErrValidationFailed.WithError(fmt.Errorf("invalid %s, expected RFC3339 timestamp: %w", bound.param, err))
+		}
+		*bound.dst = &t
+	}
+
+	if filter.RegisteredFrom != nil && filter.RegisteredTo != nil && !filter.RegisteredFrom.Before(*filter.RegisteredTo) {
+		return filter, domain.ErrValidationFailed.WithError(errors.New("registered_from must be before registered_to"))
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = domain.DefaultFaceListLimit
+	}
+	if filter.Limit > domain.MaxFaceListLimit {
+		filter.Limit = domain.MaxFaceListLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	return filter, nil
+}
+
+// ExistsRequest request for the batch existence check endpoint
+type ExistsRequest struct {
+	ExternalIDs []string `json:"external_ids"`
+}
+
+// ExistsResult registration status of a single external_id
+type ExistsResult struct {
+	Registered   bool    `json:"registered"`
+	RegisteredAt *string `json:"registered_at,omitempty"`
+}
+
+// ExistsResponse response for the batch existence check endpoint
+type ExistsResponse struct {
+	Results map[string]ExistsResult `json:"results"`
+}
+
+// Exists POST /v1/faces/exists - check which external_ids have a registered face
+// @Summary Batch check face registration
+// @Description Returns registration status for up to 100 external_ids in a single call
+// @Tags faces
+// @Accept json
+// @Produce json
+// @Param request body ExistsRequest true "External IDs to check"
+// @Success 200 {object} ExistsResponse
+// @Failure 401 {object} domain.AppError
+// @Failure 422 {object} domain.AppError
+// @Router /v1/faces/exists [post]
+func (h *FaceHandler) Exists(c *fiber.Ctx) error {
+	// 1. Get tenant ID from context
+	tenantID, err := middleware.GetTenantID(c)
+	if err != nil {
+		return err
+	}
+
+	// 2. Parse JSON body
+	var req ExistsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return domain.ErrValidationFailed.WithError(fmt.Errorf("invalid request body: %w", err))
+	}
+
+	// 3. Call service (validates and caps the list)
+	checks, err := h.service.ExistsBatch(c.UserContext(), tenantID, req.ExternalIDs)
+	if err != nil {
+		return err
+	}
+
+	// 4. Convert to response
+	results := make(map[string]ExistsResult, len(checks))
+	for externalID, check := range checks {
+		result := ExistsResult{Registered: check.Registered}
+		if check.RegisteredAt != nil {
+			registeredAt := check.RegisteredAt.Format("2006-01-02T15:04:05Z")
+			result.RegisteredAt = &registeredAt
+		}
+		results[externalID] = result
+	}
+
+	return c.JSON(ExistsResponse{Results: results})
+}
+
+// BulkMetadataRequest request for the bulk metadata patch endpoint
+type BulkMetadataRequest struct {
+	ExternalIDs []string               `json:"external_ids"`
+	Metadata    map[string]interface{} `json:"metadata"`
+}
+
+// BulkMetadataResponse response for the bulk metadata patch endpoint
+type BulkMetadataResponse struct {
+	Updated     int      `json:"updated"`
+	NotFound    int      `json:"not_found"`
+	NotFoundIDs []string `json:"not_found_ids"`
+}
+
+// BulkUpdateMetadata POST /v1/faces/metadata/bulk - merge a metadata patch into many faces
+// @Summary Bulk tag faces with metadata
+// @Description Merges the metadata patch into every face matching the given external_ids (up to 500). Top-level keys overwrite existing ones.
+// @Tags faces
+// @Accept json
+// @Produce json
+// @Param request body BulkMetadataRequest true "External IDs and metadata patch"
+// @Success 200 {object} BulkMetadataResponse
+// @Failure 401 {object} domain.AppError
+// @Failure 413 {object} domain.AppError
+// @Failure 422 {object} domain.AppError
+// @Router /v1/faces/metadata/bulk [post]
+func (h *FaceHandler) BulkUpdateMetadata(c *fiber.Ctx) error {
+	// 1. Get tenant ID from context
+	tenantID, err := middleware.GetTenantID(c)
+	if err != nil {
+		return err
+	}
+
+	// 2. Parse JSON body
+	var req BulkMetadataRequest
+	if err := c.BodyParser(&req); err != nil {
+		return domain.ErrValidationFailed.WithError(fmt.Errorf("invalid request body: %w", err))
+	}
+
+	// 3. Call service (validates ids and patch)
+	result, err := h.service.BulkUpdateMetadata(c.UserContext(), tenantID, req.ExternalIDs, req.Metadata)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(BulkMetadataResponse{
+		Updated:     result.Updated,
+		NotFound:    result.NotFound,
+		NotFoundIDs: result.NotFoundIDs,
 	})
 }
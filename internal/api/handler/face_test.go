@@ -4,12 +4,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"mime/multipart"
+	"net/http"
 	"net/http/httptest"
 	"net/textproto"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,9 +21,11 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
 	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/webhook"
 )
 
 // MockFaceService is a mock implementation of FaceService
@@ -27,35 +33,48 @@ type MockFaceService struct {
 	mock.Mock
 }
 
-func (m *MockFaceService) Register(ctx context.Context, tenantID uuid.UUID, externalID string, imageBytes []byte, requireLiveness bool, livenessThreshold float64) (*domain.Face, error) {
-	args := m.Called(ctx, tenantID, externalID, imageBytes, requireLiveness, livenessThreshold)
+func (m *MockFaceService) Register(ctx context.Context, tenantID uuid.UUID, externalID string, imageBytes []byte, metadata map[string]interface{}, settings domain.TenantSettings) (*domain.Face, error) {
+	args := m.Called(ctx, tenantID, externalID, imageBytes, metadata, settings)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.Face), args.Error(1)
 }
 
-func (m *MockFaceService) Verify(ctx context.Context, tenantID uuid.UUID, externalID string, imageBytes []byte) (*domain.Verification, error) {
-	args := m.Called(ctx, tenantID, externalID, imageBytes)
+func (m *MockFaceService) Verify(ctx context.Context, tenantID uuid.UUID, externalID string, imageBytes []byte, settings domain.TenantSettings) (*domain.Verification, error) {
+	args := m.Called(ctx, tenantID, externalID, imageBytes, settings)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.Verification), args.Error(1)
 }
 
+func (m *MockFaceService) VerifyDocument(ctx context.Context, tenantID uuid.UUID, liveImage, documentImage []byte, settings domain.TenantSettings) (*domain.DocumentVerification, error) {
+	args := m.Called(ctx, tenantID, liveImage, documentImage, settings)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.DocumentVerification), args.Error(1)
+}
+
 func (m *MockFaceService) Delete(ctx context.Context, tenantID uuid.UUID, externalID string) error {
 	args := m.Called(ctx, tenantID, externalID)
 	return args.Error(0)
 }
 
-func (m *MockFaceService) CheckLiveness(ctx context.Context, imageBytes []byte, threshold float64) (*domain.LivenessResult, error) {
-	args := m.Called(ctx, imageBytes, threshold)
+func (m *MockFaceService) CheckLiveness(ctx context.Context, tenantID uuid.UUID, imageBytes []byte, settings domain.TenantSettings) (*domain.LivenessResult, error) {
+	args := m.Called(ctx, tenantID, imageBytes, settings)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.LivenessResult), args.Error(1)
 }
 
+func (m *MockFaceService) CountFaces(ctx context.Context, tenantID uuid.UUID, imageBytes []byte, settings domain.TenantSettings) (int, error) {
+	args := m.Called(ctx, tenantID, imageBytes, settings)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockFaceService) Search(ctx context.Context, tenant *domain.Tenant, imageBytes []byte, threshold float64, maxResults int, clientIP string) (*domain.SearchResult, error) {
 	args := m.Called(ctx, tenant, imageBytes, threshold, maxResults, clientIP)
 	if args.Get(0) == nil {
@@ -64,6 +83,54 @@ func (m *MockFaceService) Search(ctx context.Context, tenant *domain.Tenant, ima
 	return args.Get(0).(*domain.SearchResult), args.Error(1)
 }
 
+func (m *MockFaceService) SearchByEmbedding(ctx context.Context, tenant *domain.Tenant, embedding []float64, threshold float64, maxResults int, clientIP string) (*domain.SearchResult, error) {
+	args := m.Called(ctx, tenant, embedding, threshold, maxResults, clientIP)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SearchResult), args.Error(1)
+}
+
+func (m *MockFaceService) GetByExternalID(ctx context.Context, tenantID uuid.UUID, externalID string) (*domain.Face, error) {
+	args := m.Called(ctx, tenantID, externalID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Face), args.Error(1)
+}
+
+func (m *MockFaceService) List(ctx context.Context, tenantID uuid.UUID, filter domain.FaceListFilter) ([]*domain.Face, int, error) {
+	args := m.Called(ctx, tenantID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*domain.Face), args.Int(1), args.Error(2)
+}
+
+func (m *MockFaceService) ExistsBatch(ctx context.Context, tenantID uuid.UUID, externalIDs []string) (map[string]domain.RegistrationCheck, error) {
+	args := m.Called(ctx, tenantID, externalIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]domain.RegistrationCheck), args.Error(1)
+}
+
+func (m *MockFaceService) BulkUpdateMetadata(ctx context.Context, tenantID uuid.UUID, externalIDs []string, patch map[string]interface{}) (*domain.BulkMetadataResult, error) {
+	args := m.Called(ctx, tenantID, externalIDs, patch)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BulkMetadataResult), args.Error(1)
+}
+
+func (m *MockFaceService) RecentlyVerified(ctx context.Context, tenantID uuid.UUID, externalID string, within time.Duration, settings domain.TenantSettings) (*domain.RecentVerification, error) {
+	args := m.Called(ctx, tenantID, externalID, within, settings)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.RecentVerification), args.Error(1)
+}
+
 // MockUsageTracker is a mock implementation of UsageTracker
 type MockUsageTracker struct {
 	mock.Mock
@@ -172,7 +239,7 @@ func TestFaceHandler_Register(t *testing.T) {
 			imageContent: make([]byte, 5000),
 			contentType:  "image/jpeg",
 			setupMock: func(m *MockFaceService) {
-				m.On("Register", mock.Anything, tenantID, "user_001", mock.Anything, mock.AnythingOfType("bool"), mock.AnythingOfType("float64")).Return(&domain.Face{
+				m.On("Register", mock.Anything, tenantID, "user_001", mock.Anything, mock.Anything, mock.AnythingOfType("domain.TenantSettings")).Return(&domain.Face{
 					ID:           faceID,
 					ExternalID:   "user_001",
 					QualityScore: 0.95,
@@ -203,7 +270,7 @@ func TestFaceHandler_Register(t *testing.T) {
 			imageContent: make([]byte, 5000),
 			contentType:  "image/jpeg",
 			setupMock: func(m *MockFaceService) {
-				m.On("Register", mock.Anything, tenantID, "user_001", mock.Anything, mock.AnythingOfType("bool"), mock.AnythingOfType("float64")).Return(nil, domain.ErrFaceExists)
+				m.On("Register", mock.Anything, tenantID, "user_001", mock.Anything, mock.Anything, mock.AnythingOfType("domain.TenantSettings")).Return(nil, domain.ErrFaceExists)
 			},
 			expectedStatus: 409,
 		},
@@ -213,7 +280,7 @@ func TestFaceHandler_Register(t *testing.T) {
 			imageContent: make([]byte, 5000),
 			contentType:  "image/jpeg",
 			setupMock: func(m *MockFaceService) {
-				m.On("Register", mock.Anything, tenantID, "user_001", mock.Anything, mock.AnythingOfType("bool"), mock.AnythingOfType("float64")).Return(nil, domain.ErrNoFaceDetected)
+				m.On("Register", mock.Anything, tenantID, "user_001", mock.Anything, mock.Anything, mock.AnythingOfType("domain.TenantSettings")).Return(nil, domain.ErrNoFaceDetected)
 			},
 			expectedStatus: 422,
 		},
@@ -223,10 +290,20 @@ func TestFaceHandler_Register(t *testing.T) {
 			imageContent: make([]byte, 5000),
 			contentType:  "image/jpeg",
 			setupMock: func(m *MockFaceService) {
-				m.On("Register", mock.Anything, tenantID, "user_001", mock.Anything, mock.AnythingOfType("bool"), mock.AnythingOfType("float64")).Return(nil, domain.ErrMultipleFaces)
+				m.On("Register", mock.Anything, tenantID, "user_001", mock.Anything, mock.Anything, mock.AnythingOfType("domain.TenantSettings")).Return(nil, domain.ErrMultipleFaces)
 			},
 			expectedStatus: 422,
 		},
+		{
+			name:         "metadata too large",
+			externalID:   "user_001",
+			imageContent: make([]byte, 5000),
+			contentType:  "image/jpeg",
+			setupMock: func(m *MockFaceService) {
+				m.On("Register", mock.Anything, tenantID, "user_001", mock.Anything, mock.Anything, mock.AnythingOfType("domain.TenantSettings")).Return(nil, domain.ErrMetadataTooLarge)
+			},
+			expectedStatus: 413,
+		},
 	}
 
 	for _, tt := range tests {
@@ -279,7 +356,7 @@ func TestFaceHandler_Verify(t *testing.T) {
 			externalID:   "user_001",
 			imageContent: make([]byte, 5000),
 			setupMock: func(m *MockFaceService) {
-				m.On("Verify", mock.Anything, tenantID, "user_001", mock.Anything).Return(&domain.Verification{
+				m.On("Verify", mock.Anything, tenantID, "user_001", mock.Anything, mock.AnythingOfType("domain.TenantSettings")).Return(&domain.Verification{
 					ID:         verificationID,
 					Verified:   true,
 					Confidence: 0.92,
@@ -301,7 +378,7 @@ func TestFaceHandler_Verify(t *testing.T) {
 			externalID:   "user_001",
 			imageContent: make([]byte, 5000),
 			setupMock: func(m *MockFaceService) {
-				m.On("Verify", mock.Anything, tenantID, "user_001", mock.Anything).Return(&domain.Verification{
+				m.On("Verify", mock.Anything, tenantID, "user_001", mock.Anything, mock.AnythingOfType("domain.TenantSettings")).Return(&domain.Verification{
 					ID:         verificationID,
 					Verified:   false,
 					Confidence: 0.45,
@@ -322,7 +399,7 @@ func TestFaceHandler_Verify(t *testing.T) {
 			externalID:   "user_999",
 			imageContent: make([]byte, 5000),
 			setupMock: func(m *MockFaceService) {
-				m.On("Verify", mock.Anything, tenantID, "user_999", mock.Anything).Return(nil, domain.ErrFaceNotFound)
+				m.On("Verify", mock.Anything, tenantID, "user_999", mock.Anything, mock.AnythingOfType("domain.TenantSettings")).Return(nil, domain.ErrFaceNotFound)
 			},
 			expectedStatus: 404,
 		},
@@ -338,7 +415,7 @@ func TestFaceHandler_Verify(t *testing.T) {
 			externalID:   "user_001",
 			imageContent: make([]byte, 5000),
 			setupMock: func(m *MockFaceService) {
-				m.On("Verify", mock.Anything, tenantID, "user_001", mock.Anything).Return(nil, domain.ErrNoFaceDetected)
+				m.On("Verify", mock.Anything, tenantID, "user_001", mock.Anything, mock.AnythingOfType("domain.TenantSettings")).Return(nil, domain.ErrNoFaceDetected)
 			},
 			expectedStatus: 422,
 		},
@@ -377,12 +454,234 @@ func TestFaceHandler_Verify(t *testing.T) {
 	}
 }
 
+func TestFaceHandler_Verify_ReenrollSuggested(t *testing.T) {
+	tenantID := uuid.New()
+	suggestion := &domain.ReenrollSuggestion{
+		EnrolledQuality:   0.95,
+		Margin:            0.1,
+		RecentConfidences: []float64{0.82, 0.83, 0.81},
+	}
+
+	tests := []struct {
+		name       string
+		suggestion *domain.ReenrollSuggestion
+		wantEvent  bool
+	}{
+		{name: "dispatches face.reenroll_suggested", suggestion: suggestion, wantEvent: true},
+		{name: "no event without suggestion", suggestion: nil, wantEvent: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockFaceService{}
+			mockService.On("Verify", mock.Anything, tenantID, "user_001", mock.Anything, mock.Anything).Return(&domain.Verification{
+				ID:                uuid.New(),
+				Verified:          true,
+				Confidence:        0.81,
+				ReenrollSuggested: tt.suggestion,
+			}, nil)
+			mockTracker := &MockUsageTracker{}
+			mockTracker.On("IncrementDaily", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			events := make(chan webhook.FaceReenrollSuggestedData, 1)
+			mockWebhook := new(MockWebhookService)
+			mockWebhook.On("Dispatch", mock.Anything, tenantID, webhook.EventFaceVerified, mock.Anything).Return(nil)
+			mockWebhook.On("Dispatch", mock.Anything, tenantID, webhook.EventFaceReenrollSuggested, mock.Anything).
+				Run(func(args mock.Arguments) { events <- args.Get(3).(webhook.FaceReenrollSuggestedData) }).
+				Return(nil).Maybe()
+
+			handler := NewFaceHandler(mockService, mockTracker, mockWebhook, testLogger())
+			app := createTestApp(handler, tenantID)
+			app.Post("/v1/faces/verify", handler.Verify)
+
+			body, contentType, _ := createMultipartRequest("user_001", make([]byte, 5000), "image/jpeg")
+			req := httptest.NewRequest("POST", "/v1/faces/verify", body)
+			req.Header.Set("Content-Type", contentType)
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, 200, resp.StatusCode)
+
+			select {
+			case data := <-events:
+				require.True(t, tt.wantEvent, "unexpected face.reenroll_suggested event")
+				assert.Equal(t, "user_001", data.ExternalID)
+				assert.Equal(t, 0.95, data.EnrolledQuality)
+				assert.Equal(t, []float64{0.82, 0.83, 0.81}, data.RecentConfidences)
+			case <-time.After(500 * time.Millisecond):
+				assert.False(t, tt.wantEvent, "face.reenroll_suggested was not dispatched")
+			}
+		})
+	}
+}
+
+func TestFaceHandler_WebhookPayloadsMatchSamples(t *testing.T) {
+	tenantID := uuid.New()
+
+	mockService := &MockFaceService{}
+	mockService.On("Register", mock.Anything, tenantID, "user_001", mock.Anything, mock.Anything, mock.Anything).Return(&domain.Face{
+		ID:           uuid.New(),
+		ExternalID:   "user_001",
+		QualityScore: 0.9,
+	}, nil)
+	mockService.On("Verify", mock.Anything, tenantID, "user_001", mock.Anything, mock.Anything).Return(&domain.Verification{
+		ID:         uuid.New(),
+		Verified:   true,
+		Confidence: 0.88,
+	}, nil)
+	mockTracker := &MockUsageTracker{}
+	mockTracker.On("IncrementDaily", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	dispatched := make(chan webhook.EventPayload, 2)
+	mockWebhook := new(MockWebhookService)
+	mockWebhook.On("Dispatch", mock.Anything, tenantID, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			ctx := args.Get(0).(context.Context)
+			dispatched <- webhook.NewEventPayload(ctx, tenantID, args.String(2), args.Get(3))
+		}).
+		Return(nil)
+
+	handler := NewFaceHandler(mockService, mockTracker, mockWebhook, testLogger())
+	app := createTestApp(handler, tenantID)
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals(middleware.LocalRequestID, "req-123")
+		return c.Next()
+	})
+	app.Post("/v1/faces", handler.Register)
+	app.Post("/v1/faces/verify", handler.Verify)
+
+	for _, path := range []string{"/v1/faces", "/v1/faces/verify"} {
+		body, contentType, _ := createMultipartRequest("user_001", make([]byte, 5000), "image/jpeg")
+		req := httptest.NewRequest("POST", path, body)
+		req.Header.Set("Content-Type", contentType)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		require.Less(t, resp.StatusCode, 300)
+
+		select {
+		case event := <-dispatched:
+			sample, ok := webhook.SamplePayload(tenantID, event.Type)
+			require.True(t, ok, "no sample for %s", event.Type)
+			assert.Equal(t, jsonShape(t, sample), jsonShape(t, event), "sample for %s differs from dispatched payload", event.Type)
+		case <-time.After(500 * time.Millisecond):
+			t.Fatalf("no webhook dispatched for %s", path)
+		}
+	}
+}
+
+// jsonShape reduces a value to its JSON keys and value kinds, dropping the values themselves
+func jsonShape(t *testing.T, v any) any {
+	t.Helper()
+
+	raw, err := json.Marshal(v)
+	require.NoError(t, err)
+	var decoded any
+	require.NoError(t, json.Unmarshal(raw, &decoded))
+
+	var shape func(any) any
+	shape = func(v any) any {
+		switch v := v.(type) {
+		case map[string]any:
+			out := make(map[string]any, len(v))
+			for k, child := range v {
+				out[k] = shape(child)
+			}
+			return out
+		case []any:
+			if len(v) == 0 {
+				return "array"
+			}
+			return []any{shape(v[0])}
+		case nil:
+			return "null"
+		default:
+			return fmt.Sprintf("%T", v)
+		}
+	}
+	return shape(decoded)
+}
+
+func TestFaceHandler_StrictMultipartFields(t *testing.T) {
+	tenantID := uuid.New()
+
+	newRequest := func(t *testing.T) *http.Request {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		_ = writer.WriteField("external_id", "user_001")
+		// Extra fields a buggy client might send
+		_ = writer.WriteField("externalId", "user_001")
+		_ = writer.WriteField("threshold", "0.9")
+
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", `form-data; name="image"; filename="test.jpg"`)
+		h.Set("Content-Type", "image/jpeg")
+		part, err := writer.CreatePart(h)
+		require.NoError(t, err)
+		_, _ = part.Write(make([]byte, 5000))
+		require.NoError(t, writer.Close())
+
+		req := httptest.NewRequest("POST", "/v1/faces/verify", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		return req
+	}
+
+	tests := []struct {
+		name       string
+		strict     bool
+		wantStatus int
+	}{
+		{name: "strict tenant rejects unknown fields", strict: true, wantStatus: 422},
+		{name: "lenient tenant ignores unknown fields", strict: false, wantStatus: 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockFaceService{}
+			mockService.On("Verify", mock.Anything, tenantID, "user_001", mock.Anything, mock.Anything).Return(&domain.Verification{
+				ID:         uuid.New(),
+				Verified:   true,
+				Confidence: 0.95,
+			}, nil).Maybe()
+			mockTracker := &MockUsageTracker{}
+			mockTracker.On("IncrementDaily", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+			mockWebhook := new(MockWebhookService)
+			mockWebhook.On("Dispatch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			handler := NewFaceHandler(mockService, mockTracker, mockWebhook, testLogger())
+			app := createTestApp(handler, tenantID)
+			app.Use(func(c *fiber.Ctx) error {
+				c.Locals(middleware.LocalTenant, &domain.Tenant{
+					ID:       tenantID,
+					Settings: map[string]interface{}{"strict_multipart_fields": tt.strict},
+				})
+				return c.Next()
+			})
+			app.Post("/v1/faces/verify", handler.Verify)
+
+			resp, err := app.Test(newRequest(t))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			if tt.strict {
+				var appErr domain.AppError
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&appErr))
+				assert.Equal(t, "VALIDATION_FAILED", appErr.Code)
+				assert.Equal(t, []string{"externalId", "threshold"}, appErr.Reasons)
+				assert.Contains(t, appErr.Message, "externalId, threshold")
+				mockService.AssertNotCalled(t, "Verify", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
 func TestFaceHandler_Delete(t *testing.T) {
 	tenantID := uuid.New()
 
 	tests := []struct {
 		name           string
 		externalID     string
+		query          string
 		setupMock      func(*MockFaceService)
 		expectedStatus int
 	}{
@@ -402,6 +701,33 @@ func TestFaceHandler_Delete(t *testing.T) {
 			},
 			expectedStatus: 404,
 		},
+		{
+			name:       "face not found with idempotent=false",
+			externalID: "user_999",
+			query:      "?idempotent=false",
+			setupMock: func(m *MockFaceService) {
+				m.On("Delete", mock.Anything, tenantID, "user_999").Return(domain.ErrFaceNotFound)
+			},
+			expectedStatus: 404,
+		},
+		{
+			name:       "idempotent delete of missing face",
+			externalID: "user_999",
+			query:      "?idempotent=true",
+			setupMock: func(m *MockFaceService) {
+				m.On("Delete", mock.Anything, tenantID, "user_999").Return(domain.ErrFaceNotFound)
+			},
+			expectedStatus: 204,
+		},
+		{
+			name:       "idempotent delete still surfaces other errors",
+			externalID: "user_001",
+			query:      "?idempotent=true",
+			setupMock: func(m *MockFaceService) {
+				m.On("Delete", mock.Anything, tenantID, "user_001").Return(domain.ErrInternal)
+			},
+			expectedStatus: 500,
+		},
 	}
 
 	for _, tt := range tests {
@@ -418,7 +744,7 @@ func TestFaceHandler_Delete(t *testing.T) {
 			app := createTestApp(handler, tenantID)
 			app.Delete("/v1/faces/:external_id", handler.Delete)
 
-			requestURL := "/v1/faces/" + url.PathEscape(tt.externalID)
+			requestURL := "/v1/faces/" + url.PathEscape(tt.externalID) + tt.query
 
 			req := httptest.NewRequest("DELETE", requestURL, nil)
 
@@ -431,123 +757,152 @@ func TestFaceHandler_Delete(t *testing.T) {
 	}
 }
 
-func TestFaceHandler_CheckLiveness(t *testing.T) {
+func TestFaceHandler_Exists(t *testing.T) {
 	tenantID := uuid.New()
+	registeredAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
 
 	tests := []struct {
 		name           string
-		imageContent   []byte
-		contentType    string
+		body           string
 		setupMock      func(*MockFaceService)
 		expectedStatus int
 		checkResponse  func(t *testing.T, body []byte)
 	}{
 		{
-			name:         "successful liveness check - live",
-			imageContent: make([]byte, 5000),
-			contentType:  "image/jpeg",
+			name: "mixed existing and missing ids",
+			body: `{"external_ids": ["user_001", "user_002"]}`,
 			setupMock: func(m *MockFaceService) {
-				m.On("CheckLiveness", mock.Anything, mock.Anything, mock.AnythingOfType("float64")).Return(&domain.LivenessResult{
-					IsLive:     true,
-					Confidence: 0.95,
-					Checks: domain.LivenessChecks{
-						EyesOpen:     true,
-						FacingCamera: true,
-						QualityOK:    true,
-						SingleFace:   true,
-					},
-					Reasons: []string{},
+				m.On("ExistsBatch", mock.Anything, tenantID, []string{"user_001", "user_002"}).Return(map[string]domain.RegistrationCheck{
+					"user_001": {Registered: true, RegisteredAt: &registeredAt},
+					"user_002": {Registered: false},
 				}, nil)
 			},
 			expectedStatus: 200,
 			checkResponse: func(t *testing.T, body []byte) {
-				var resp LivenessResponse
-				err := json.Unmarshal(body, &resp)
-				assert.NoError(t, err)
-				assert.True(t, resp.IsLive)
-				assert.Equal(t, 0.95, resp.Confidence)
-				assert.True(t, resp.Checks.EyesOpen)
-				assert.True(t, resp.Checks.FacingCamera)
-				assert.True(t, resp.Checks.QualityOK)
-				assert.True(t, resp.Checks.SingleFace)
+				var resp ExistsResponse
+				require.NoError(t, json.Unmarshal(body, &resp))
+				require.Len(t, resp.Results, 2)
+
+				assert.True(t, resp.Results["user_001"].Registered)
+				require.NotNil(t, resp.Results["user_001"].RegisteredAt)
+				assert.Equal(t, "2025-03-01T12:00:00Z", *resp.Results["user_001"].RegisteredAt)
+
+				assert.False(t, resp.Results["user_002"].Registered)
+				assert.Nil(t, resp.Results["user_002"].RegisteredAt)
 			},
 		},
 		{
-			name:         "successful liveness check - not live",
-			imageContent: make([]byte, 5000),
-			contentType:  "image/jpeg",
+			name:           "invalid body",
+			body:           `{"external_ids": "user_001"}`,
+			setupMock:      func(m *MockFaceService) {},
+			expectedStatus: 422,
+		},
+		{
+			name: "service validation error",
+			body: `{"external_ids": ["a"]}`,
 			setupMock: func(m *MockFaceService) {
-				m.On("CheckLiveness", mock.Anything, mock.Anything, mock.AnythingOfType("float64")).Return(&domain.LivenessResult{
-					IsLive:     false,
-					Confidence: 0.45,
-					Checks: domain.LivenessChecks{
-						EyesOpen:     false,
-						FacingCamera: true,
-						QualityOK:    true,
-						SingleFace:   true,
-					},
-					Reasons: []string{"eyes closed"},
+				m.On("ExistsBatch", mock.Anything, tenantID, []string{"a"}).Return(nil, domain.ErrValidationFailed)
+			},
+			expectedStatus: 422,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockFaceService{}
+			tt.setupMock(mockService)
+
+			handler := NewFaceHandler(mockService, &MockUsageTracker{}, new(MockWebhookService), testLogger())
+			app := createTestApp(handler, tenantID)
+			app.Post("/v1/faces/exists", handler.Exists)
+
+			req := httptest.NewRequest("POST", "/v1/faces/exists", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if tt.checkResponse != nil {
+				respBody, _ := io.ReadAll(resp.Body)
+				tt.checkResponse(t, respBody)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFaceHandler_BulkUpdateMetadata(t *testing.T) {
+	tenantID := uuid.New()
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*MockFaceService)
+		expectedStatus int
+		checkResponse  func(t *testing.T, body []byte)
+	}{
+		{
+			name: "reports updated and not found",
+			body: `{"external_ids": ["user_001", "user_002", "user_003"], "metadata": {"event": "rock-in-rio"}}`,
+			setupMock: func(m *MockFaceService) {
+				m.On("BulkUpdateMetadata", mock.Anything, tenantID, []string{"user_001", "user_002", "user_003"},
+					map[string]interface{}{"event": "rock-in-rio"}).Return(&domain.BulkMetadataResult{
+					Updated:     2,
+					NotFound:    1,
+					NotFoundIDs: []string{"user_003"},
 				}, nil)
 			},
 			expectedStatus: 200,
 			checkResponse: func(t *testing.T, body []byte) {
-				var resp LivenessResponse
-				err := json.Unmarshal(body, &resp)
-				assert.NoError(t, err)
-				assert.False(t, resp.IsLive)
-				assert.Equal(t, 0.45, resp.Confidence)
-				assert.False(t, resp.Checks.EyesOpen)
-				assert.Contains(t, resp.Reasons, "eyes closed")
+				var resp BulkMetadataResponse
+				require.NoError(t, json.Unmarshal(body, &resp))
+				assert.Equal(t, 2, resp.Updated)
+				assert.Equal(t, 1, resp.NotFound)
+				assert.Equal(t, []string{"user_003"}, resp.NotFoundIDs)
 			},
 		},
 		{
-			name:         "no face detected",
-			imageContent: make([]byte, 5000),
-			contentType:  "image/jpeg",
-			setupMock: func(m *MockFaceService) {
-				m.On("CheckLiveness", mock.Anything, mock.Anything, mock.AnythingOfType("float64")).Return(nil, domain.ErrNoFaceDetected)
-			},
+			name:           "invalid body",
+			body:           `{"external_ids": ["user_001"], "metadata": "vip"}`,
+			setupMock:      func(m *MockFaceService) {},
 			expectedStatus: 422,
 		},
 		{
-			name:         "multiple faces detected",
-			imageContent: make([]byte, 5000),
-			contentType:  "image/jpeg",
+			name: "service validation error",
+			body: `{"external_ids": ["user_001"]}`,
 			setupMock: func(m *MockFaceService) {
-				m.On("CheckLiveness", mock.Anything, mock.Anything, mock.AnythingOfType("float64")).Return(nil, domain.ErrMultipleFaces)
+				m.On("BulkUpdateMetadata", mock.Anything, tenantID, []string{"user_001"}, map[string]interface{}(nil)).
+					Return(nil, domain.ErrValidationFailed)
 			},
 			expectedStatus: 422,
 		},
 		{
-			name:           "missing image",
-			imageContent:   nil,
-			contentType:    "image/jpeg",
-			setupMock:      func(m *MockFaceService) {},
-			expectedStatus: 500,
+			name: "metadata too large",
+			body: `{"external_ids": ["user_001"], "metadata": {"notes": "long"}}`,
+			setupMock: func(m *MockFaceService) {
+				m.On("BulkUpdateMetadata", mock.Anything, tenantID, []string{"user_001"}, map[string]interface{}{"notes": "long"}).
+					Return(nil, domain.ErrMetadataTooLarge)
+			},
+			expectedStatus: 413,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockService := &MockFaceService{}
-			mockTracker := &MockUsageTracker{}
 			tt.setupMock(mockService)
-			// Allow any tracking calls (async, best-effort)
-			mockTracker.On("IncrementDaily", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
 
-			mockWebhook := new(MockWebhookService)
-			mockWebhook.On("Dispatch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
-			handler := NewFaceHandler(mockService, mockTracker, mockWebhook, testLogger())
+			handler := NewFaceHandler(mockService, &MockUsageTracker{}, new(MockWebhookService), testLogger())
 			app := createTestApp(handler, tenantID)
-			app.Post("/v1/faces/liveness", handler.CheckLiveness)
+			app.Post("/v1/faces/metadata/bulk", handler.BulkUpdateMetadata)
 
-			body, contentType, _ := createMultipartRequest("", tt.imageContent, tt.contentType)
-
-			req := httptest.NewRequest("POST", "/v1/faces/liveness", body)
-			req.Header.Set("Content-Type", contentType)
+			req := httptest.NewRequest("POST", "/v1/faces/metadata/bulk", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
 
 			resp, err := app.Test(req)
-			assert.NoError(t, err)
+			require.NoError(t, err)
 			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
 
 			if tt.checkResponse != nil {
@@ -560,6 +915,947 @@ func TestFaceHandler_CheckLiveness(t *testing.T) {
 	}
 }
 
+func TestFaceHandler_RecentlyVerified(t *testing.T) {
+	tenantID := uuid.New()
+	verifiedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(*MockFaceService)
+		expectedStatus int
+		checkResponse  func(t *testing.T, body []byte)
+	}{
+		{
+			name:  "verified within the window",
+			query: "?within=15m",
+			setupMock: func(m *MockFaceService) {
+				m.On("RecentlyVerified", mock.Anything, tenantID, "user_001", 15*time.Minute, mock.Anything).Return(&domain.RecentVerification{
+					ExternalID:       "user_001",
+					RecentlyVerified: true,
+					LastVerifiedAt:   &verifiedAt,
+					WithinSeconds:    900,
+				}, nil)
+			},
+			expectedStatus: 200,
+			checkResponse: func(t *testing.T, body []byte) {
+				var resp domain.RecentVerification
+				require.NoError(t, json.Unmarshal(body, &resp))
+				assert.True(t, resp.RecentlyVerified)
+				require.NotNil(t, resp.LastVerifiedAt)
+				assert.True(t, verifiedAt.Equal(*resp.LastVerifiedAt))
+				assert.Equal(t, int64(900), resp.WithinSeconds)
+			},
+		},
+		{
+			name:  "last verification outside the window",
+			query: "?within=5m",
+			setupMock: func(m *MockFaceService) {
+				m.On("RecentlyVerified", mock.Anything, tenantID, "user_001", 5*time.Minute, mock.Anything).Return(&domain.RecentVerification{
+					ExternalID:     "user_001",
+					LastVerifiedAt: &verifiedAt,
+					WithinSeconds:  300,
+				}, nil)
+			},
+			expectedStatus: 200,
+			checkResponse: func(t *testing.T, body []byte) {
+				var resp map[string]interface{}
+				require.NoError(t, json.Unmarshal(body, &resp))
+				assert.Equal(t, false, resp["recently_verified"])
+				assert.Equal(t, "2026-03-01T12:00:00Z", resp["last_verified_at"])
+			},
+		},
+		{
+			name:  "defaults to 15 minutes",
+			query: "",
+			setupMock: func(m *MockFaceService) {
+				m.On("RecentlyVerified", mock.Anything, tenantID, "user_001", domain.DefaultRecentVerificationWindow, mock.Anything).Return(&domain.RecentVerification{
+					ExternalID:    "user_001",
+					WithinSeconds: 900,
+				}, nil)
+			},
+			expectedStatus: 200,
+		},
+		{
+			name:           "invalid window",
+			query:          "?within=soon",
+			setupMock:      func(m *MockFaceService) {},
+			expectedStatus: 422,
+		},
+		{
+			name:  "window beyond max age",
+			query: "?within=48h",
+			setupMock: func(m *MockFaceService) {
+				m.On("RecentlyVerified", mock.Anything, tenantID, "user_001", 48*time.Hour, mock.Anything).Return(nil, domain.ErrValidationFailed)
+			},
+			expectedStatus: 422,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockFaceService{}
+			tt.setupMock(mockService)
+
+			handler := NewFaceHandler(mockService, &MockUsageTracker{}, new(MockWebhookService), testLogger())
+			app := createTestApp(handler, tenantID)
+			app.Get("/v1/faces/:external_id/recently-verified", handler.RecentlyVerified)
+
+			req := httptest.NewRequest("GET", "/v1/faces/user_001/recently-verified"+tt.query, nil)
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if tt.checkResponse != nil {
+				respBody, _ := io.ReadAll(resp.Body)
+				tt.checkResponse(t, respBody)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFaceHandler_List(t *testing.T) {
+	tenantID := uuid.New()
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)
+	registeredAt := time.Date(2026, 3, 3, 18, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(*MockFaceService)
+		expectedStatus int
+		checkResponse  func(t *testing.T, body []byte)
+	}{
+		{
+			name:  "filters by registration range",
+			query: "?registered_from=2026-03-01T00:00:00Z&registered_to=2026-03-08T00:00:00Z&limit=1&offset=2",
+			setupMock: func(m *MockFaceService) {
+				m.On("List", mock.Anything, tenantID, domain.FaceListFilter{
+					RegisteredFrom: &from,
+					RegisteredTo:   &to,
+					Limit:          1,
+					Offset:         2,
+				}).Return([]*domain.Face{
+					{ID: uuid.New(), ExternalID: "ticket-001", QualityScore: 0.93, CreatedAt: registeredAt},
+				}, 5, nil)
+			},
+			expectedStatus: 200,
+			checkResponse: func(t *testing.T, body []byte) {
+				var resp ListFacesResponse
+				require.NoError(t, json.Unmarshal(body, &resp))
+				require.Len(t, resp.Faces, 1)
+				assert.Equal(t, "ticket-001", resp.Faces[0].ExternalID)
+				assert.Equal(t, "2026-03-03T18:30:00Z", resp.Faces[0].CreatedAt)
+				assert.Equal(t, 1, resp.Total)
+				assert.Equal(t, FacesPaginationMeta{Total: 5, Limit: 1, Offset: 2}, resp.Pagination)
+			},
+		},
+		{
+			name:  "no range lists every face with default pagination",
+			query: "",
+			setupMock: func(m *MockFaceService) {
+				m.On("List", mock.Anything, tenantID, domain.FaceListFilter{Limit: domain.DefaultFaceListLimit}).Return([]*domain.Face{}, 0, nil)
+			},
+			expectedStatus: 200,
+			checkResponse: func(t *testing.T, body []byte) {
+				var resp ListFacesResponse
+				require.NoError(t, json.Unmarshal(body, &resp))
+				assert.Empty(t, resp.Faces)
+				assert.Equal(t, FacesPaginationMeta{Limit: domain.DefaultFaceListLimit}, resp.Pagination)
+			},
+		},
+		{
+			name:  "open-ended range and capped limit",
+			query: "?registered_from=2026-03-01T00:00:00Z&limit=500",
+			setupMock: func(m *MockFaceService) {
+				m.On("List", mock.Anything, tenantID, domain.FaceListFilter{
+					RegisteredFrom: &from,
+					Limit:          domain.MaxFaceListLimit,
+				}).Return([]*domain.Face{}, 0, nil)
+			},
+			expectedStatus: 200,
+		},
+		{
+			name:           "invalid registered_from",
+			query:          "?registered_from=yesterday",
+			setupMock:      func(m *MockFaceService) {},
+			expectedStatus: 422,
+		},
+		{
+			name:           "range must not be empty",
+			query:          "?registered_from=2026-03-08T00:00:00Z&registered_to=2026-03-01T00:00:00Z",
+			setupMock:      func(m *MockFaceService) {},
+			expectedStatus: 422,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockFaceService{}
+			tt.setupMock(mockService)
+
+			handler := NewFaceHandler(mockService, &MockUsageTracker{}, new(MockWebhookService), testLogger())
+			app := createTestApp(handler, tenantID)
+			app.Get("/v1/faces", handler.List)
+
+			req := httptest.NewRequest("GET", "/v1/faces"+tt.query, nil)
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if tt.checkResponse != nil {
+				respBody, _ := io.ReadAll(resp.Body)
+				tt.checkResponse(t, respBody)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFaceHandler_EmbeddingHash(t *testing.T) {
+	tenantID := uuid.New()
+	face := &domain.Face{
+		ID:            uuid.New(),
+		ExternalID:    "ticket-001",
+		QualityScore:  0.93,
+		EmbeddingHash: "3f79bb7b435b05321651daefd374cdc681dc06faa65e374e38337b88ca046dea",
+	}
+
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		want     string
+	}{
+		{"returned when enabled", map[string]interface{}{"feature_flags": map[string]interface{}{"embedding_hash": true}}, face.EmbeddingHash},
+		{"omitted by default", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockFaceService{}
+			mockService.On("GetByExternalID", mock.Anything, tenantID, "ticket-001").Return(face, nil)
+			mockService.On("List", mock.Anything, tenantID, mock.Anything).Return([]*domain.Face{face}, 1, nil)
+
+			handler := NewFaceHandler(mockService, &MockUsageTracker{}, new(MockWebhookService), testLogger())
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				c.Locals(middleware.LocalTenantID, tenantID)
+				c.Locals(middleware.LocalTenant, &domain.Tenant{ID: tenantID, IsActive: true, Settings: tt.settings})
+				return c.Next()
+			})
+			app.Get("/v1/faces", handler.List)
+			app.Get("/v1/faces/:external_id", handler.GetByExternalID)
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/v1/faces/ticket-001", nil))
+			require.NoError(t, err)
+			require.Equal(t, 200, resp.StatusCode)
+			var got FaceResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+			assert.Equal(t, tt.want, got.EmbeddingHash)
+
+			resp, err = app.Test(httptest.NewRequest("GET", "/v1/faces", nil))
+			require.NoError(t, err)
+			require.Equal(t, 200, resp.StatusCode)
+			var list ListFacesResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&list))
+			require.Len(t, list.Faces, 1)
+			assert.Equal(t, tt.want, list.Faces[0].EmbeddingHash)
+		})
+	}
+}
+
+func TestFaceHandler_CheckLiveness(t *testing.T) {
+	tenantID := uuid.New()
+
+	tests := []struct {
+		name           string
+		imageContent   []byte
+		contentType    string
+		setupMock      func(*MockFaceService)
+		expectedStatus int
+		checkResponse  func(t *testing.T, body []byte)
+	}{
+		{
+			name:         "successful liveness check - live",
+			imageContent: make([]byte, 5000),
+			contentType:  "image/jpeg",
+			setupMock: func(m *MockFaceService) {
+				m.On("CheckLiveness", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&domain.LivenessResult{
+					IsLive:     true,
+					Confidence: 0.95,
+					Checks: domain.LivenessChecks{
+						EyesOpen:     true,
+						FacingCamera: true,
+						QualityOK:    true,
+						SingleFace:   true,
+					},
+					Reasons: []string{},
+				}, nil)
+			},
+			expectedStatus: 200,
+			checkResponse: func(t *testing.T, body []byte) {
+				var resp LivenessResponse
+				err := json.Unmarshal(body, &resp)
+				assert.NoError(t, err)
+				assert.True(t, resp.IsLive)
+				assert.Equal(t, 0.95, resp.Confidence)
+				assert.True(t, resp.Checks.EyesOpen)
+				assert.True(t, resp.Checks.FacingCamera)
+				assert.True(t, resp.Checks.QualityOK)
+				assert.True(t, resp.Checks.SingleFace)
+			},
+		},
+		{
+			name:         "successful liveness check - not live",
+			imageContent: make([]byte, 5000),
+			contentType:  "image/jpeg",
+			setupMock: func(m *MockFaceService) {
+				m.On("CheckLiveness", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&domain.LivenessResult{
+					IsLive:     false,
+					Confidence: 0.45,
+					Checks: domain.LivenessChecks{
+						EyesOpen:     false,
+						FacingCamera: true,
+						QualityOK:    true,
+						SingleFace:   true,
+					},
+					Reasons: []string{"eyes closed"},
+				}, nil)
+			},
+			expectedStatus: 200,
+			checkResponse: func(t *testing.T, body []byte) {
+				var resp LivenessResponse
+				err := json.Unmarshal(body, &resp)
+				assert.NoError(t, err)
+				assert.False(t, resp.IsLive)
+				assert.Equal(t, 0.45, resp.Confidence)
+				assert.False(t, resp.Checks.EyesOpen)
+				assert.Contains(t, resp.Reasons, "eyes closed")
+			},
+		},
+		{
+			name:         "no face detected",
+			imageContent: make([]byte, 5000),
+			contentType:  "image/jpeg",
+			setupMock: func(m *MockFaceService) {
+				m.On("CheckLiveness", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, domain.ErrNoFaceDetected)
+			},
+			expectedStatus: 422,
+		},
+		{
+			name:         "multiple faces detected",
+			imageContent: make([]byte, 5000),
+			contentType:  "image/jpeg",
+			setupMock: func(m *MockFaceService) {
+				m.On("CheckLiveness", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, domain.ErrMultipleFaces)
+			},
+			expectedStatus: 422,
+		},
+		{
+			name:           "missing image",
+			imageContent:   nil,
+			contentType:    "image/jpeg",
+			setupMock:      func(m *MockFaceService) {},
+			expectedStatus: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockFaceService{}
+			mockTracker := &MockUsageTracker{}
+			tt.setupMock(mockService)
+			// Allow any tracking calls (async, best-effort)
+			mockTracker.On("IncrementDaily", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			mockWebhook := new(MockWebhookService)
+			mockWebhook.On("Dispatch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+			handler := NewFaceHandler(mockService, mockTracker, mockWebhook, testLogger())
+			app := createTestApp(handler, tenantID)
+			app.Post("/v1/faces/liveness", handler.CheckLiveness)
+
+			body, contentType, _ := createMultipartRequest("", tt.imageContent, tt.contentType)
+
+			req := httptest.NewRequest("POST", "/v1/faces/liveness", body)
+			req.Header.Set("Content-Type", contentType)
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if tt.checkResponse != nil {
+				respBody, _ := io.ReadAll(resp.Body)
+				tt.checkResponse(t, respBody)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFaceHandler_Count(t *testing.T) {
+	tenantID := uuid.New()
+
+	tests := []struct {
+		name           string
+		imageContent   []byte
+		setupMock      func(*MockFaceService)
+		expectedStatus int
+		expectedCount  int
+	}{
+		{
+			name:         "empty gate",
+			imageContent: make([]byte, 5000),
+			setupMock: func(m *MockFaceService) {
+				m.On("CountFaces", mock.Anything, tenantID, mock.Anything, mock.Anything).Return(0, nil)
+			},
+			expectedStatus: 200,
+			expectedCount:  0,
+		},
+		{
+			name:         "single person",
+			imageContent: make([]byte, 5000),
+			setupMock: func(m *MockFaceService) {
+				m.On("CountFaces", mock.Anything, tenantID, mock.Anything, mock.Anything).Return(1, nil)
+			},
+			expectedStatus: 200,
+			expectedCount:  1,
+		},
+		{
+			name:         "crowd",
+			imageContent: make([]byte, 5000),
+			setupMock: func(m *MockFaceService) {
+				m.On("CountFaces", mock.Anything, tenantID, mock.Anything, mock.Anything).Return(37, nil)
+			},
+			expectedStatus: 200,
+			expectedCount:  37,
+		},
+		{
+			name:         "data residency violation",
+			imageContent: make([]byte, 5000),
+			setupMock: func(m *MockFaceService) {
+				m.On("CountFaces", mock.Anything, tenantID, mock.Anything, mock.Anything).Return(0, domain.ErrDataResidencyViolation)
+			},
+			expectedStatus: 403,
+		},
+		{
+			name:           "missing image",
+			imageContent:   nil,
+			setupMock:      func(m *MockFaceService) {},
+			expectedStatus: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockFaceService{}
+			tt.setupMock(mockService)
+
+			handler := NewFaceHandler(mockService, &MockUsageTracker{}, new(MockWebhookService), testLogger())
+			app := createTestApp(handler, tenantID)
+			app.Post("/v1/faces/count", handler.Count)
+
+			body, contentType, _ := createMultipartRequest("", tt.imageContent, "image/jpeg")
+
+			req := httptest.NewRequest("POST", "/v1/faces/count", body)
+			req.Header.Set("Content-Type", contentType)
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if tt.expectedStatus == 200 {
+				var raw map[string]interface{}
+				respBody, _ := io.ReadAll(resp.Body)
+				require.NoError(t, json.Unmarshal(respBody, &raw))
+				assert.Equal(t, map[string]interface{}{"face_count": float64(tt.expectedCount)}, raw, "only the count is returned")
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// createDocumentVerifyRequest builds the multipart body of a document verify, skipping nil images
+func createDocumentVerifyRequest(liveImage, documentImage []byte) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	for field, content := range map[string][]byte{"image": liveImage, "document": documentImage} {
+		if content == nil {
+			continue
+		}
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q; filename="%s.jpg"`, field, field))
+		h.Set("Content-Type", "image/jpeg")
+		part, _ := writer.CreatePart(h)
+		_, _ = part.Write(content)
+	}
+
+	_ = writer.Close()
+	return body, writer.FormDataContentType()
+}
+
+func TestFaceHandler_VerifyDocument(t *testing.T) {
+	tenantID := uuid.New()
+	liveImage := bytes.Repeat([]byte{1}, 5000)
+	documentImage := bytes.Repeat([]byte{2}, 5000)
+
+	tests := []struct {
+		name           string
+		liveImage      []byte
+		documentImage  []byte
+		setupMock      func(*MockFaceService)
+		expectedStatus int
+		checkResponse  func(t *testing.T, body []byte)
+	}{
+		{
+			name:          "match",
+			liveImage:     liveImage,
+			documentImage: documentImage,
+			setupMock: func(m *MockFaceService) {
+				m.On("VerifyDocument", mock.Anything, tenantID, liveImage, documentImage, mock.AnythingOfType("domain.TenantSettings")).
+					Return(&domain.DocumentVerification{Verified: true, Similarity: 0.93, Threshold: 0.8, LatencyMs: 120}, nil)
+			},
+			expectedStatus: 200,
+			checkResponse: func(t *testing.T, body []byte) {
+				var resp VerifyDocumentResponse
+				require.NoError(t, json.Unmarshal(body, &resp))
+				assert.True(t, resp.Verified)
+				assert.Equal(t, 0.93, resp.Similarity)
+				assert.Equal(t, 0.8, resp.Threshold)
+			},
+		},
+		{
+			name:          "no match",
+			liveImage:     liveImage,
+			documentImage: documentImage,
+			setupMock: func(m *MockFaceService) {
+				m.On("VerifyDocument", mock.Anything, tenantID, liveImage, documentImage, mock.AnythingOfType("domain.TenantSettings")).
+					Return(&domain.DocumentVerification{Verified: false, Similarity: 0.42, Threshold: 0.8}, nil)
+			},
+			expectedStatus: 200,
+			checkResponse: func(t *testing.T, body []byte) {
+				var resp VerifyDocumentResponse
+				require.NoError(t, json.Unmarshal(body, &resp))
+				assert.False(t, resp.Verified)
+				assert.Equal(t, 0.42, resp.Similarity)
+			},
+		},
+		{
+			name:          "no face in document",
+			liveImage:     liveImage,
+			documentImage: documentImage,
+			setupMock: func(m *MockFaceService) {
+				m.On("VerifyDocument", mock.Anything, tenantID, liveImage, documentImage, mock.AnythingOfType("domain.TenantSettings")).
+					Return(nil, domain.ErrNoFaceInDocument)
+			},
+			expectedStatus: 422,
+			checkResponse: func(t *testing.T, body []byte) {
+				assert.Contains(t, string(body), "NO_FACE_IN_DOCUMENT")
+			},
+		},
+		{
+			name:           "missing document",
+			liveImage:      liveImage,
+			setupMock:      func(m *MockFaceService) {},
+			expectedStatus: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockFaceService{}
+			mockTracker := &MockUsageTracker{}
+			tt.setupMock(mockService)
+			mockTracker.On("IncrementDaily", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			handler := NewFaceHandler(mockService, mockTracker, new(MockWebhookService), testLogger())
+			app := createTestApp(handler, tenantID)
+			app.Post("/v1/faces/verify-document", handler.VerifyDocument)
+
+			body, contentType := createDocumentVerifyRequest(tt.liveImage, tt.documentImage)
+			req := httptest.NewRequest("POST", "/v1/faces/verify-document", body)
+			req.Header.Set("Content-Type", contentType)
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if tt.checkResponse != nil {
+				respBody, _ := io.ReadAll(resp.Body)
+				tt.checkResponse(t, respBody)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFaceHandler_Register_AllowedImageFormats(t *testing.T) {
+	tests := []struct {
+		name           string
+		settings       map[string]interface{}
+		contentType    string
+		expectedStatus int
+		expectRegister bool
+	}{
+		{
+			name:           "jpeg-only tenant rejects png",
+			settings:       map[string]interface{}{"allowed_image_formats": []interface{}{"jpeg"}},
+			contentType:    "image/png",
+			expectedStatus: fiber.StatusUnprocessableEntity,
+		},
+		{
+			name:           "jpeg-only tenant accepts jpeg",
+			settings:       map[string]interface{}{"allowed_image_formats": []interface{}{"jpeg"}},
+			contentType:    "image/jpeg",
+			expectedStatus: fiber.StatusCreated,
+			expectRegister: true,
+		},
+		{
+			name:           "permissive tenant accepts png",
+			settings:       map[string]interface{}{},
+			contentType:    "image/png",
+			expectedStatus: fiber.StatusCreated,
+			expectRegister: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenantID := uuid.New()
+			mockService := new(MockFaceService)
+			mockTracker := new(MockUsageTracker)
+			mockWebhook := new(MockWebhookService)
+
+			if tt.expectRegister {
+				mockService.On("Register", mock.Anything, tenantID, "user_001", mock.Anything, mock.Anything, mock.AnythingOfType("domain.TenantSettings")).Return(&domain.Face{
+					ID:         uuid.New(),
+					ExternalID: "user_001",
+					CreatedAt:  time.Now(),
+				}, nil)
+				mockTracker.On("IncrementDaily", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+				mockWebhook.On("Dispatch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+			}
+
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				c.Locals(middleware.LocalTenantID, tenantID)
+				c.Locals(middleware.LocalTenant, &domain.Tenant{ID: tenantID, IsActive: true, Settings: tt.settings})
+
+				err := c.Next()
+				var appErr *domain.AppError
+				if errors.As(err, &appErr) {
+					return c.Status(appErr.StatusCode).JSON(appErr)
+				}
+				return err
+			})
+			handler := NewFaceHandler(mockService, mockTracker, mockWebhook, testLogger())
+			app.Post("/v1/faces", handler.Register)
+
+			body, contentType, _ := createMultipartRequest("user_001", make([]byte, 5000), tt.contentType)
+			req := httptest.NewRequest("POST", "/v1/faces", body)
+			req.Header.Set("Content-Type", contentType)
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if !tt.expectRegister {
+				var appErr domain.AppError
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&appErr))
+				assert.Equal(t, domain.ErrInvalidImage.Code, appErr.Code)
+				assert.Contains(t, appErr.Message, "image/jpeg")
+				assert.NotContains(t, appErr.Message, "image/png")
+				mockService.AssertNotCalled(t, "Register", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFaceHandler_Register_TTL(t *testing.T) {
+	tenantID := uuid.New()
+	expiresAt := time.Date(2026, 3, 8, 18, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		ttl            string
+		tenantTTL      string
+		expectedStatus int
+		wantTTL        time.Duration
+	}{
+		{name: "ttl of the registration", ttl: "168h", expectedStatus: fiber.StatusCreated, wantTTL: 7 * 24 * time.Hour},
+		{name: "ttl overrides the tenant's face_ttl", ttl: "2h", tenantTTL: "24h", expectedStatus: fiber.StatusCreated, wantTTL: 2 * time.Hour},
+		{name: "tenant's face_ttl without ttl", tenantTTL: "24h", expectedStatus: fiber.StatusCreated, wantTTL: 24 * time.Hour},
+		{name: "ttl in days is rejected", ttl: "7d", expectedStatus: fiber.StatusUnprocessableEntity},
+		{name: "negative ttl is rejected", ttl: "-1h", expectedStatus: fiber.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockFaceService)
+			mockTracker := new(MockUsageTracker)
+			mockWebhook := new(MockWebhookService)
+			mockTracker.On("IncrementDaily", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+			mockWebhook.On("Dispatch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			if tt.expectedStatus == fiber.StatusCreated {
+				mockService.On("Register", mock.Anything, tenantID, "guest-1", mock.Anything, mock.Anything,
+					mock.MatchedBy(func(s domain.TenantSettings) bool { return s.FaceTTL == tt.wantTTL }),
+				).Return(&domain.Face{
+					ID:         uuid.New(),
+					ExternalID: "guest-1",
+					CreatedAt:  time.Now(),
+					ExpiresAt:  &expiresAt,
+				}, nil)
+			}
+
+			settings := map[string]interface{}{}
+			if tt.tenantTTL != "" {
+				settings["face_ttl"] = tt.tenantTTL
+			}
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				c.Locals(middleware.LocalTenantID, tenantID)
+				c.Locals(middleware.LocalTenant, &domain.Tenant{ID: tenantID, IsActive: true, Settings: settings})
+
+				err := c.Next()
+				var appErr *domain.AppError
+				if errors.As(err, &appErr) {
+					return c.Status(appErr.StatusCode).JSON(appErr)
+				}
+				return err
+			})
+			handler := NewFaceHandler(mockService, mockTracker, mockWebhook, testLogger())
+			app.Post("/v1/faces", handler.Register)
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			_ = writer.WriteField("external_id", "guest-1")
+			if tt.ttl != "" {
+				_ = writer.WriteField("ttl", tt.ttl)
+			}
+			h := make(textproto.MIMEHeader)
+			h.Set("Content-Disposition", `form-data; name="image"; filename="test.jpg"`)
+			h.Set("Content-Type", "image/jpeg")
+			part, err := writer.CreatePart(h)
+			require.NoError(t, err)
+			_, _ = part.Write(make([]byte, 5000))
+			require.NoError(t, writer.Close())
+
+			req := httptest.NewRequest("POST", "/v1/faces", body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if tt.expectedStatus == fiber.StatusCreated {
+				var registered RegisterResponse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&registered))
+				require.NotNil(t, registered.ExpiresAt)
+				assert.Equal(t, "2026-03-08T18:00:00Z", *registered.ExpiresAt)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFaceHandler_SearchByEmbedding(t *testing.T) {
+	tenantID := uuid.New()
+	searchID := uuid.New()
+	faceID := uuid.New()
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*MockFaceService)
+		expectedStatus int
+		checkResponse  func(t *testing.T, body []byte)
+	}{
+		{
+			name: "successful search",
+			body: `{"embedding":[0.1,0.2],"threshold":0.9,"max_results":5}`,
+			setupMock: func(m *MockFaceService) {
+				m.On("SearchByEmbedding", mock.Anything, mock.AnythingOfType("*domain.Tenant"), []float64{0.1, 0.2}, 0.9, 5, mock.AnythingOfType("string")).Return(&domain.SearchResult{
+					SearchID: searchID,
+					Matches: []domain.SearchMatch{
+						{FaceID: faceID, ExternalID: "user_001", Similarity: 0.93},
+					},
+					LatencyMs: 12,
+				}, nil)
+			},
+			expectedStatus: 200,
+			checkResponse: func(t *testing.T, body []byte) {
+				var resp SearchResponse
+				require.NoError(t, json.Unmarshal(body, &resp))
+				require.Len(t, resp.Matches, 1)
+				assert.Equal(t, "user_001", resp.Matches[0].ExternalID)
+				assert.Equal(t, faceID.String(), resp.Matches[0].FaceID)
+				assert.Equal(t, searchID.String(), resp.SearchID)
+			},
+		},
+		{
+			name: "invalid embedding dimension",
+			body: `{"embedding":[0.1]}`,
+			setupMock: func(m *MockFaceService) {
+				m.On("SearchByEmbedding", mock.Anything, mock.AnythingOfType("*domain.Tenant"), []float64{0.1}, float64(0), 0, mock.AnythingOfType("string")).Return(nil, domain.ErrInvalidEmbedding)
+			},
+			expectedStatus: 422,
+		},
+		{
+			name: "not enabled for tenant",
+			body: `{"embedding":[0.1,0.2]}`,
+			setupMock: func(m *MockFaceService) {
+				m.On("SearchByEmbedding", mock.Anything, mock.AnythingOfType("*domain.Tenant"), []float64{0.1, 0.2}, float64(0), 0, mock.AnythingOfType("string")).Return(nil, domain.ErrSearchByEmbeddingNotEnabled)
+			},
+			expectedStatus: 403,
+		},
+		{
+			name:           "malformed body",
+			body:           `{"embedding":"abc"}`,
+			setupMock:      func(m *MockFaceService) {},
+			expectedStatus: 422,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockFaceService{}
+			tt.setupMock(mockService)
+			mockTracker := &MockUsageTracker{}
+			mockTracker.On("IncrementDaily", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+			mockWebhook := new(MockWebhookService)
+			mockWebhook.On("Dispatch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			handler := NewFaceHandler(mockService, mockTracker, mockWebhook, testLogger())
+			app := createTestApp(handler, tenantID)
+			app.Post("/v1/faces/search-by-embedding", handler.SearchByEmbedding)
+
+			req := httptest.NewRequest("POST", "/v1/faces/search-by-embedding", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if tt.checkResponse != nil {
+				respBody, _ := io.ReadAll(resp.Body)
+				tt.checkResponse(t, respBody)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFaceHandler_SearchWarningHeader(t *testing.T) {
+	tenantID := uuid.New()
+	warning := "max_results 80 exceeds the limit of 50 and was clamped"
+
+	mockService := &MockFaceService{}
+	mockService.On("SearchByEmbedding", mock.Anything, mock.AnythingOfType("*domain.Tenant"), []float64{0.1, 0.2}, float64(0), 80, mock.AnythingOfType("string")).Return(&domain.SearchResult{
+		SearchID: uuid.New(),
+		Matches:  []domain.SearchMatch{},
+		Warning:  warning,
+	}, nil)
+	mockTracker := &MockUsageTracker{}
+	mockTracker.On("IncrementDaily", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	mockWebhook := new(MockWebhookService)
+	mockWebhook.On("Dispatch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	handler := NewFaceHandler(mockService, mockTracker, mockWebhook, testLogger())
+	app := createTestApp(handler, tenantID)
+	app.Post("/v1/faces/search-by-embedding", handler.SearchByEmbedding)
+
+	req := httptest.NewRequest("POST", "/v1/faces/search-by-embedding", strings.NewReader(`{"embedding":[0.1,0.2],"max_results":80}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, warning, resp.Header.Get("X-Rekko-Warning"))
+	mockService.AssertExpectations(t)
+}
+
+func TestFaceHandler_SearchMetadataAllowList(t *testing.T) {
+	metadata := map[string]interface{}{
+		"seat":  "A12",
+		"name":  "Maria Silva",
+		"cpf":   "123.456.789-00",
+		"phone": "+55 11 99999-0000",
+	}
+
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		want     map[string]interface{}
+	}{
+		{name: "no allow-list returns no metadata", settings: nil, want: nil},
+		{name: "only allow-listed keys are returned", settings: map[string]interface{}{"search_metadata_keys": []interface{}{"seat", "name", "missing"}}, want: map[string]interface{}{"seat": "A12", "name": "Maria Silva"}},
+		{name: "allow-list without matching keys", settings: map[string]interface{}{"search_metadata_keys": []interface{}{"missing"}}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenantID := uuid.New()
+			result := &domain.SearchResult{
+				SearchID: uuid.New(),
+				Matches: []domain.SearchMatch{
+					{FaceID: uuid.New(), ExternalID: "user_001", Similarity: 0.93, Metadata: metadata},
+				},
+			}
+
+			mockService := &MockFaceService{}
+			mockService.On("SearchByEmbedding", mock.Anything, mock.AnythingOfType("*domain.Tenant"), []float64{0.1, 0.2}, float64(0), 0, mock.AnythingOfType("string")).Return(result, nil)
+			mockTracker := &MockUsageTracker{}
+			mockTracker.On("IncrementDaily", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+			mockWebhook := new(MockWebhookService)
+			mockWebhook.On("Dispatch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				c.Locals(middleware.LocalTenantID, tenantID)
+				c.Locals(middleware.LocalTenant, &domain.Tenant{ID: tenantID, IsActive: true, Settings: tt.settings})
+				return c.Next()
+			})
+			handler := NewFaceHandler(mockService, mockTracker, mockWebhook, testLogger())
+			app.Post("/v1/faces/search-by-embedding", handler.SearchByEmbedding)
+
+			req := httptest.NewRequest("POST", "/v1/faces/search-by-embedding", strings.NewReader(`{"embedding":[0.1,0.2]}`))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			require.Equal(t, 200, resp.StatusCode)
+
+			var body struct {
+				Matches []map[string]interface{} `json:"matches"`
+			}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			require.Len(t, body.Matches, 1)
+			if tt.want == nil {
+				assert.NotContains(t, body.Matches[0], "metadata")
+			} else {
+				assert.Equal(t, tt.want, body.Matches[0]["metadata"])
+			}
+
+			// Filtering happens on the response only; the service result keeps the full metadata
+			assert.Len(t, result.Matches[0].Metadata, 4)
+		})
+	}
+}
+
 func TestExtractAndValidateImage(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -616,7 +1912,7 @@ func TestExtractAndValidateImage(t *testing.T) {
 			})
 
 			app.Post("/test", func(c *fiber.Ctx) error {
-				_, err := extractAndValidateImage(c)
+				_, err := extractAndValidateImage(c, domain.SupportedImageFormats)
 				if err != nil {
 					if appErr, ok := err.(*domain.AppError); ok {
 						return c.Status(appErr.StatusCode).JSON(appErr)
@@ -0,0 +1,73 @@
+package super
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/usage"
+)
+
+// maxFeatureUsageRange is the longest range one feature usage report covers
+const maxFeatureUsageRange = 366 * 24 * time.Hour
+
+// FeatureUsageReader reports which optional features a tenant used
+type FeatureUsageReader interface {
+	GetFeatureUsage(ctx context.Context, tenantID uuid.UUID, startDate, endDate time.Time) (*usage.FeatureUsageReport, error)
+}
+
+type FeatureUsageHandler struct {
+	usage  FeatureUsageReader
+	logger *slog.Logger
+}
+
+func NewFeatureUsageHandler(usage FeatureUsageReader, logger *slog.Logger) *FeatureUsageHandler {
+	return &FeatureUsageHandler{
+		usage:  usage,
+		logger: logger,
+	}
+}
+
+// GetFeatureUsage handles GET /super/tenants/:id/feature-usage?start_date=&end_date=
+// Summarizes how much the tenant used search, liveness, the widget and webhooks between two
+// dates (YYYY-MM-DD, inclusive, default the last 30 days)
+func (h *FeatureUsageHandler) GetFeatureUsage(c *fiber.Ctx) error {
+	tenantIDStr := c.Params("id")
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		h.logger.Debug("invalid tenant id", "id", tenantIDStr)
+		return fiber.NewError(fiber.StatusBadRequest, "invalid tenant ID format")
+	}
+
+	now := time.Now().UTC()
+	start, err := time.Parse("2006-01-02", c.Query("start_date", now.AddDate(0, 0, -30).Format("2006-01-02")))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid start_date format, expected YYYY-MM-DD")
+	}
+	end, err := time.Parse("2006-01-02", c.Query("end_date", now.Format("2006-01-02")))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid end_date format, expected YYYY-MM-DD")
+	}
+	if start.After(end) {
+		return fiber.NewError(fiber.StatusBadRequest, "start_date must be before or equal to end_date")
+	}
+	if end.Sub(start) > maxFeatureUsageRange {
+		return fiber.NewError(fiber.StatusBadRequest, "date range must not exceed 366 days")
+	}
+
+	report, err := h.usage.GetFeatureUsage(c.UserContext(), tenantID, start, end)
+	if err != nil {
+		h.logger.Error("failed to get feature usage", "error", err, "tenant_id", tenantID)
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(fiber.Map{
+		"data": report,
+		"meta": fiber.Map{
+			"tenant_id": tenantID.String(),
+		},
+	})
+}
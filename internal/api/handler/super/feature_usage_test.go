@@ -0,0 +1,122 @@
+package super
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/usage"
+)
+
+type fakeFeatureUsageReader struct {
+	records []usage.UsageRecord
+	err     error
+
+	called   bool
+	gotStart time.Time
+	gotEnd   time.Time
+}
+
+func (f *fakeFeatureUsageReader) GetFeatureUsage(ctx context.Context, tenantID uuid.UUID, startDate, endDate time.Time) (*usage.FeatureUsageReport, error) {
+	f.called, f.gotStart, f.gotEnd = true, startDate, endDate
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &usage.FeatureUsageReport{
+		TenantID:  tenantID,
+		StartDate: startDate.Format("2006-01-02"),
+		EndDate:   endDate.Format("2006-01-02"),
+		Features:  usage.SummarizeFeatures(f.records),
+	}, nil
+}
+
+func TestFeatureUsageHandler_GetFeatureUsage(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tenantID := uuid.New()
+
+	newApp := func(reader FeatureUsageReader) *fiber.App {
+		app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler(logger)})
+		app.Get("/super/tenants/:id/feature-usage", NewFeatureUsageHandler(reader, logger).GetFeatureUsage)
+		return app
+	}
+
+	t.Run("summarizes usage per feature", func(t *testing.T) {
+		reader := &fakeFeatureUsageReader{records: []usage.UsageRecord{
+			{Date: time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC), Searches: 3, WidgetSessions: 1},
+			{Date: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), Searches: 2},
+		}}
+		app := newApp(reader)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet,
+			"/super/tenants/"+tenantID.String()+"/feature-usage?start_date=2026-10-01&end_date=2026-10-15", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), reader.gotStart)
+		assert.Equal(t, time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC), reader.gotEnd)
+
+		var body struct {
+			Data usage.FeatureUsageReport `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, tenantID, body.Data.TenantID)
+		assert.Equal(t, "2026-10-01", body.Data.StartDate)
+		assert.Equal(t, 5, body.Data.Features[usage.FeatureSearch].Total)
+		assert.Equal(t, 2, body.Data.Features[usage.FeatureSearch].ActiveDays)
+		assert.True(t, body.Data.Features[usage.FeatureWidget].Used)
+		assert.False(t, body.Data.Features[usage.FeatureWebhooks].Used)
+		assert.False(t, body.Data.Features[usage.FeatureLiveness].Used)
+	})
+
+	t.Run("defaults to the last 30 days", func(t *testing.T) {
+		reader := &fakeFeatureUsageReader{}
+		app := newApp(reader)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/super/tenants/"+tenantID.String()+"/feature-usage", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, 30*24*time.Hour, reader.gotEnd.Sub(reader.gotStart))
+	})
+
+	invalid := []struct {
+		name string
+		path string
+	}{
+		{"malformed tenant id", "/super/tenants/not-a-uuid/feature-usage"},
+		{"malformed start_date", "/super/tenants/" + tenantID.String() + "/feature-usage?start_date=01/10/2026"},
+		{"malformed end_date", "/super/tenants/" + tenantID.String() + "/feature-usage?end_date=yesterday"},
+		{"start after end", "/super/tenants/" + tenantID.String() + "/feature-usage?start_date=2026-10-15&end_date=2026-10-01"},
+		{"range too long", "/super/tenants/" + tenantID.String() + "/feature-usage?start_date=2024-01-01&end_date=2026-01-01"},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := &fakeFeatureUsageReader{}
+			app := newApp(reader)
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, tt.path, nil))
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			assert.False(t, reader.called)
+		})
+	}
+
+	t.Run("repository error", func(t *testing.T) {
+		app := newApp(&fakeFeatureUsageReader{err: errors.New("connection refused")})
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/super/tenants/"+tenantID.String()+"/feature-usage", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	})
+}
@@ -0,0 +1,97 @@
+package super
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/audit"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+// TenantLookup finds the tenant to impersonate
+type TenantLookup interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Tenant, error)
+}
+
+// ImpersonationTokenIssuer issues read-only tokens bound to a tenant
+type ImpersonationTokenIssuer interface {
+	GenerateImpersonationToken(userID uuid.UUID, email string, tenantID uuid.UUID) (string, time.Time, error)
+}
+
+type ImpersonationHandler struct {
+	tenants     TenantLookup
+	tokens      ImpersonationTokenIssuer
+	auditLogger audit.Logger
+	logger      *slog.Logger
+}
+
+func NewImpersonationHandler(tenants TenantLookup, tokens ImpersonationTokenIssuer, auditLogger audit.Logger, logger *slog.Logger) *ImpersonationHandler {
+	return &ImpersonationHandler{
+		tenants:     tenants,
+		tokens:      tokens,
+		auditLogger: auditLogger,
+		logger:      logger,
+	}
+}
+
+// Impersonate handles GET /super/tenants/:id/impersonate
+// The returned token authenticates the tenant's GET endpoints in place of an API key.
+func (h *ImpersonationHandler) Impersonate(c *fiber.Ctx) error {
+	tenantIDStr := c.Params("id")
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		h.logger.Debug("invalid tenant id", "id", tenantIDStr)
+		return fiber.NewError(fiber.StatusBadRequest, "invalid tenant ID format")
+	}
+
+	adminID, err := middleware.GetAdminUserID(c)
+	if err != nil {
+		return err
+	}
+	adminEmail, _ := middleware.GetAdminEmail(c)
+
+	if _, err := h.tenants.GetByID(c.UserContext(), tenantID); err != nil {
+		h.logger.Warn("impersonated tenant not found", "error", err, "tenant_id", tenantID)
+		return domain.ErrTenantNotFound
+	}
+
+	token, expiresAt, err := h.tokens.GenerateImpersonationToken(adminID, adminEmail, tenantID)
+	if err != nil {
+		h.logger.Error("failed to issue impersonation token", "error", err, "tenant_id", tenantID)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := h.auditLogger.Log(c.UserContext(), audit.Event{
+		TenantID:  tenantID,
+		EventType: audit.EventImpersonationStarted,
+		Success:   true,
+		Metadata: map[string]string{
+			"super_admin_id":    adminID.String(),
+			"super_admin_email": adminEmail,
+			"expires_at":        expiresAt.UTC().Format(time.RFC3339),
+		},
+		IPAddress: c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+	}); err != nil {
+		h.logger.Error("failed to audit impersonation", "error", err, "tenant_id", tenantID)
+		return fiber.ErrInternalServerError
+	}
+
+	h.logger.Info("impersonation token issued",
+		"tenant_id", tenantID,
+		"user_id", adminID,
+		"expires_at", expiresAt,
+	)
+
+	return c.JSON(fiber.Map{
+		"token":      token,
+		"tenant_id":  tenantID.String(),
+		"expires_at": expiresAt.UTC(),
+		"read_only":  true,
+	})
+}
@@ -0,0 +1,130 @@
+package super
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/admin"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/audit"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+type MockTenantLookup struct {
+	mock.Mock
+}
+
+func (m *MockTenantLookup) GetByID(ctx context.Context, id uuid.UUID) (*domain.Tenant, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Tenant), args.Error(1)
+}
+
+type recordingAuditLogger struct {
+	events []audit.Event
+}
+
+func (l *recordingAuditLogger) Log(_ context.Context, event audit.Event) error {
+	l.events = append(l.events, event)
+	return nil
+}
+
+func TestImpersonationHandler_Impersonate(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	jwtService := admin.NewJWTService("test-secret", "rekko-test", time.Hour)
+	adminID := uuid.New()
+	tenantID := uuid.New()
+
+	newApp := func(tenants TenantLookup, auditLogger audit.Logger) *fiber.App {
+		handler := NewImpersonationHandler(tenants, jwtService, auditLogger, logger)
+		app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler(logger)})
+		app.Use(middleware.AdminAuth(middleware.AdminLevelSuper, middleware.AdminAuthDependencies{
+			JWTService: jwtService,
+			Logger:     logger,
+		}))
+		app.Get("/super/tenants/:id/impersonate", handler.Impersonate)
+		return app
+	}
+	superToken, err := jwtService.GenerateToken(adminID, "support@rekko.com", "super_admin")
+	require.NoError(t, err)
+
+	t.Run("issues a read-only token bound to the tenant", func(t *testing.T) {
+		tenants := new(MockTenantLookup)
+		tenants.On("GetByID", mock.Anything, tenantID).Return(&domain.Tenant{ID: tenantID}, nil)
+		auditLogger := &recordingAuditLogger{}
+
+		req := httptest.NewRequest("GET", "/super/tenants/"+tenantID.String()+"/impersonate", nil)
+		req.Header.Set("Authorization", "Bearer "+superToken)
+		resp, err := newApp(tenants, auditLogger).Test(req)
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Token     string    `json:"token"`
+			TenantID  string    `json:"tenant_id"`
+			ExpiresAt time.Time `json:"expires_at"`
+			ReadOnly  bool      `json:"read_only"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, tenantID.String(), result.TenantID)
+		assert.True(t, result.ReadOnly)
+
+		claims, err := jwtService.ValidateToken(result.Token)
+		require.NoError(t, err)
+		assert.Equal(t, admin.RoleImpersonation, claims.Role)
+		assert.Equal(t, adminID, claims.UserID)
+		require.NotNil(t, claims.TenantID)
+		assert.Equal(t, tenantID, *claims.TenantID)
+
+		require.Len(t, auditLogger.events, 1)
+		assert.Equal(t, audit.EventImpersonationStarted, auditLogger.events[0].EventType)
+		assert.Equal(t, tenantID, auditLogger.events[0].TenantID)
+		assert.Equal(t, adminID.String(), auditLogger.events[0].Metadata["super_admin_id"])
+		assert.Equal(t, "support@rekko.com", auditLogger.events[0].Metadata["super_admin_email"])
+	})
+
+	t.Run("impersonation token cannot impersonate again", func(t *testing.T) {
+		token, _, err := jwtService.GenerateImpersonationToken(adminID, "support@rekko.com", tenantID)
+		require.NoError(t, err)
+
+		req := httptest.NewRequest("GET", "/super/tenants/"+uuid.New().String()+"/impersonate", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := newApp(new(MockTenantLookup), &recordingAuditLogger{}).Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("unknown tenant", func(t *testing.T) {
+		tenants := new(MockTenantLookup)
+		tenants.On("GetByID", mock.Anything, tenantID).Return(nil, domain.ErrTenantNotFound)
+		auditLogger := &recordingAuditLogger{}
+
+		req := httptest.NewRequest("GET", "/super/tenants/"+tenantID.String()+"/impersonate", nil)
+		req.Header.Set("Authorization", "Bearer "+superToken)
+		resp, err := newApp(tenants, auditLogger).Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+		assert.Empty(t, auditLogger.events)
+	})
+
+	t.Run("invalid tenant id", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/super/tenants/invalid-uuid/impersonate", nil)
+		req.Header.Set("Authorization", "Bearer "+superToken)
+		resp, err := newApp(new(MockTenantLookup), &recordingAuditLogger{}).Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+}
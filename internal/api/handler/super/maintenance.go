@@ -0,0 +1,127 @@
+package super
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/audit"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+// TenantUpdater loads and saves a tenant
+type TenantUpdater interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Tenant, error)
+	Update(ctx context.Context, tenant *domain.Tenant) error
+}
+
+type MaintenanceHandler struct {
+	tenants     TenantUpdater
+	auditLogger audit.Logger
+	logger      *slog.Logger
+}
+
+func NewMaintenanceHandler(tenants TenantUpdater, auditLogger audit.Logger, logger *slog.Logger) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		tenants:     tenants,
+		auditLogger: auditLogger,
+		logger:      logger,
+	}
+}
+
+// SetMaintenanceRequest toggles the maintenance mode of a tenant
+type SetMaintenanceRequest struct {
+	Enabled *bool `json:"enabled"`
+	// RetryAfter is the duration sent in Retry-After, e.g. "15m" (default 5m)
+	RetryAfter string `json:"retry_after,omitempty"`
+}
+
+// MaintenanceResponse is the maintenance mode of a tenant after the toggle
+type MaintenanceResponse struct {
+	TenantID   uuid.UUID `json:"tenant_id"`
+	Enabled    bool      `json:"enabled"`
+	RetryAfter string    `json:"retry_after"`
+}
+
+// SetMaintenance handles POST /super/tenants/:id/maintenance
+// While enabled, recognition endpoints answer 503 MAINTENANCE and reads keep working.
+func (h *MaintenanceHandler) SetMaintenance(c *fiber.Ctx) error {
+	tenantIDStr := c.Params("id")
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		h.logger.Debug("invalid tenant id", "id", tenantIDStr)
+		return fiber.NewError(fiber.StatusBadRequest, "invalid tenant ID format")
+	}
+
+	var req SetMaintenanceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if req.Enabled == nil {
+		return fiber.NewError(fiber.StatusBadRequest, "enabled is required")
+	}
+	retryAfter := domain.DefaultMaintenanceRetryAfter
+	if req.RetryAfter != "" {
+		if retryAfter, err = domain.ParseMaintenanceRetryAfter(req.RetryAfter); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+	}
+
+	adminID, err := middleware.GetAdminUserID(c)
+	if err != nil {
+		return err
+	}
+	adminEmail, _ := middleware.GetAdminEmail(c)
+
+	tenant, err := h.tenants.GetByID(c.UserContext(), tenantID)
+	if err != nil {
+		h.logger.Warn("tenant for maintenance not found", "error", err, "tenant_id", tenantID)
+		return domain.ErrTenantNotFound
+	}
+
+	if tenant.Settings == nil {
+		tenant.Settings = make(map[string]interface{})
+	}
+	tenant.Settings["maintenance"] = map[string]interface{}{
+		"enabled":     *req.Enabled,
+		"retry_after": retryAfter.String(),
+	}
+
+	if err := h.tenants.Update(c.UserContext(), tenant); err != nil {
+		h.logger.Error("failed to update tenant maintenance", "error", err, "tenant_id", tenantID)
+		return err
+	}
+
+	if err := h.auditLogger.Log(c.UserContext(), audit.Event{
+		TenantID:  tenantID,
+		EventType: audit.EventTenantMaintenance,
+		Success:   true,
+		Metadata: map[string]string{
+			"super_admin_id":    adminID.String(),
+			"super_admin_email": adminEmail,
+			"enabled":           strconv.FormatBool(*req.Enabled),
+			"retry_after":       retryAfter.String(),
+		},
+		IPAddress: c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+	}); err != nil {
+		h.logger.Error("failed to audit tenant maintenance", "error", err, "tenant_id", tenantID)
+	}
+
+	h.logger.Info("tenant maintenance updated",
+		"tenant_id", tenantID,
+		"user_id", adminID,
+		"enabled", *req.Enabled,
+		"retry_after", retryAfter,
+	)
+
+	return c.JSON(MaintenanceResponse{
+		TenantID:   tenantID,
+		Enabled:    *req.Enabled,
+		RetryAfter: retryAfter.String(),
+	})
+}
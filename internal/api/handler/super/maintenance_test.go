@@ -0,0 +1,130 @@
+package super
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/admin"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/audit"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+type MockTenantUpdater struct {
+	MockTenantLookup
+}
+
+func (m *MockTenantUpdater) Update(ctx context.Context, tenant *domain.Tenant) error {
+	args := m.Called(ctx, tenant)
+	return args.Error(0)
+}
+
+func TestMaintenanceHandler_SetMaintenance(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	jwtService := admin.NewJWTService("test-secret", "rekko-test", time.Hour)
+	adminID := uuid.New()
+	tenantID := uuid.New()
+
+	newApp := func(tenants TenantUpdater, auditLogger audit.Logger) *fiber.App {
+		handler := NewMaintenanceHandler(tenants, auditLogger, logger)
+		app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler(logger)})
+		app.Use(middleware.AdminAuth(middleware.AdminLevelSuper, middleware.AdminAuthDependencies{
+			JWTService: jwtService,
+			Logger:     logger,
+		}))
+		app.Post("/super/tenants/:id/maintenance", handler.SetMaintenance)
+		return app
+	}
+	superToken, err := jwtService.GenerateToken(adminID, "support@rekko.com", "super_admin")
+	require.NoError(t, err)
+
+	doPost := func(app *fiber.App, id, body string) *http.Response {
+		req := httptest.NewRequest("POST", "/super/tenants/"+id+"/maintenance", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+superToken)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("enables maintenance keeping other settings", func(t *testing.T) {
+		tenant := &domain.Tenant{ID: tenantID, Settings: map[string]interface{}{"search_enabled": true}}
+		tenants := new(MockTenantUpdater)
+		tenants.On("GetByID", mock.Anything, tenantID).Return(tenant, nil)
+		tenants.On("Update", mock.Anything, tenant).Return(nil)
+		auditLogger := &recordingAuditLogger{}
+
+		resp := doPost(newApp(tenants, auditLogger), tenantID.String(), `{"enabled": true, "retry_after": "15m"}`)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var result MaintenanceResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, MaintenanceResponse{TenantID: tenantID, Enabled: true, RetryAfter: "15m0s"}, result)
+
+		settings := tenant.GetSettings()
+		assert.True(t, settings.SearchEnabled)
+		assert.Equal(t, domain.MaintenanceSettings{Enabled: true, RetryAfter: 15 * time.Minute}, settings.Maintenance)
+
+		require.Len(t, auditLogger.events, 1)
+		event := auditLogger.events[0]
+		assert.Equal(t, audit.EventTenantMaintenance, event.EventType)
+		assert.Equal(t, tenantID, event.TenantID)
+		assert.Equal(t, adminID.String(), event.Metadata["super_admin_id"])
+		assert.Equal(t, "true", event.Metadata["enabled"])
+		tenants.AssertExpectations(t)
+	})
+
+	t.Run("disables maintenance with the default retry after", func(t *testing.T) {
+		tenant := &domain.Tenant{ID: tenantID}
+		tenants := new(MockTenantUpdater)
+		tenants.On("GetByID", mock.Anything, tenantID).Return(tenant, nil)
+		tenants.On("Update", mock.Anything, tenant).Return(nil)
+
+		resp := doPost(newApp(tenants, &recordingAuditLogger{}), tenantID.String(), `{"enabled": false}`)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		assert.Equal(t, domain.MaintenanceSettings{RetryAfter: domain.DefaultMaintenanceRetryAfter}, tenant.GetSettings().Maintenance)
+	})
+
+	t.Run("rejects invalid bodies", func(t *testing.T) {
+		for _, body := range []string{`{}`, `{"enabled": true, "retry_after": "48h"}`, `{"enabled": true, "retry_after": "soon"}`, `not json`} {
+			tenants := new(MockTenantUpdater)
+			auditLogger := &recordingAuditLogger{}
+
+			resp := doPost(newApp(tenants, auditLogger), tenantID.String(), body)
+
+			assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode, body)
+			tenants.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			assert.Empty(t, auditLogger.events)
+		}
+	})
+
+	t.Run("unknown tenant", func(t *testing.T) {
+		tenants := new(MockTenantUpdater)
+		tenants.On("GetByID", mock.Anything, tenantID).Return(nil, domain.ErrTenantNotFound)
+
+		resp := doPost(newApp(tenants, &recordingAuditLogger{}), tenantID.String(), `{"enabled": true}`)
+
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+		tenants.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid tenant id", func(t *testing.T) {
+		resp := doPost(newApp(new(MockTenantUpdater), &recordingAuditLogger{}), "invalid-uuid", `{"enabled": true}`)
+
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+}
@@ -0,0 +1,73 @@
+package super
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+// maxProviderErrorsRange is the longest range one provider errors report covers
+const maxProviderErrorsRange = 366 * 24 * time.Hour
+
+// ProviderErrorCounter groups recorded provider errors by error code
+type ProviderErrorCounter interface {
+	CountByCode(ctx context.Context, start, end time.Time) ([]domain.ProviderErrorCount, error)
+}
+
+type ProviderErrorsHandler struct {
+	errors ProviderErrorCounter
+	logger *slog.Logger
+}
+
+func NewProviderErrorsHandler(errors ProviderErrorCounter, logger *slog.Logger) *ProviderErrorsHandler {
+	return &ProviderErrorsHandler{
+		errors: errors,
+		logger: logger,
+	}
+}
+
+// GetProviderErrors handles GET /super/metrics/provider-errors?start_date=&end_date=
+// Counts the failed provider calls between two dates (YYYY-MM-DD, inclusive, default the last
+// 30 days) per provider and raw error code, most frequent first
+func (h *ProviderErrorsHandler) GetProviderErrors(c *fiber.Ctx) error {
+	now := time.Now().UTC()
+	start, err := time.Parse("2006-01-02", c.Query("start_date", now.AddDate(0, 0, -30).Format("2006-01-02")))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid start_date format, expected YYYY-MM-DD")
+	}
+	end, err := time.Parse("2006-01-02", c.Query("end_date", now.Format("2006-01-02")))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid end_date format, expected YYYY-MM-DD")
+	}
+	if start.After(end) {
+		return fiber.NewError(fiber.StatusBadRequest, "start_date must be before or equal to end_date")
+	}
+	if end.Sub(start) > maxProviderErrorsRange {
+		return fiber.NewError(fiber.StatusBadRequest, "date range must not exceed 366 days")
+	}
+
+	// end_date is inclusive, so count up to the start of the next day
+	counts, err := h.errors.CountByCode(c.UserContext(), start, end.AddDate(0, 0, 1))
+	if err != nil {
+		h.logger.Error("failed to count provider errors", "error", err)
+		return fiber.ErrInternalServerError
+	}
+
+	total := 0
+	for _, count := range counts {
+		total += count.Count
+	}
+
+	return c.JSON(fiber.Map{
+		"data": counts,
+		"meta": fiber.Map{
+			"start_date": start.Format("2006-01-02"),
+			"end_date":   end.Format("2006-01-02"),
+			"total":      total,
+		},
+	})
+}
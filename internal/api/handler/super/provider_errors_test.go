@@ -0,0 +1,114 @@
+package super
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+type fakeProviderErrorCounter struct {
+	counts []domain.ProviderErrorCount
+	err    error
+
+	called   bool
+	gotStart time.Time
+	gotEnd   time.Time
+}
+
+func (f *fakeProviderErrorCounter) CountByCode(ctx context.Context, start, end time.Time) ([]domain.ProviderErrorCount, error) {
+	f.called, f.gotStart, f.gotEnd = true, start, end
+	return f.counts, f.err
+}
+
+func TestProviderErrorsHandler_GetProviderErrors(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	newApp := func(counter ProviderErrorCounter) *fiber.App {
+		app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler(logger)})
+		app.Get("/super/metrics/provider-errors", NewProviderErrorsHandler(counter, logger).GetProviderErrors)
+		return app
+	}
+
+	t.Run("returns the counts per error code", func(t *testing.T) {
+		lastSeen := time.Date(2026, 10, 3, 14, 0, 0, 0, time.UTC)
+		counter := &fakeProviderErrorCounter{counts: []domain.ProviderErrorCount{
+			{Provider: "rekognition", ErrorCode: "ThrottlingException", Count: 12, LastSeenAt: lastSeen},
+			{Provider: "deepface", ErrorCode: "HTTP_503", Count: 3, LastSeenAt: lastSeen},
+		}}
+		app := newApp(counter)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/super/metrics/provider-errors?start_date=2026-10-01&end_date=2026-10-07", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		// end_date is inclusive
+		assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), counter.gotStart)
+		assert.Equal(t, time.Date(2026, 10, 8, 0, 0, 0, 0, time.UTC), counter.gotEnd)
+
+		var body struct {
+			Data []domain.ProviderErrorCount `json:"data"`
+			Meta struct {
+				StartDate string `json:"start_date"`
+				EndDate   string `json:"end_date"`
+				Total     int    `json:"total"`
+			} `json:"meta"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, counter.counts, body.Data)
+		assert.Equal(t, "2026-10-01", body.Meta.StartDate)
+		assert.Equal(t, "2026-10-07", body.Meta.EndDate)
+		assert.Equal(t, 15, body.Meta.Total)
+	})
+
+	t.Run("defaults to the last 30 days", func(t *testing.T) {
+		counter := &fakeProviderErrorCounter{counts: []domain.ProviderErrorCount{}}
+		app := newApp(counter)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/super/metrics/provider-errors", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, 31*24*time.Hour, counter.gotEnd.Sub(counter.gotStart))
+	})
+
+	invalid := []struct {
+		name  string
+		query string
+	}{
+		{"malformed start_date", "?start_date=01/10/2026"},
+		{"malformed end_date", "?end_date=today"},
+		{"start after end", "?start_date=2026-10-07&end_date=2026-10-01"},
+		{"range too long", "?start_date=2024-01-01&end_date=2026-01-01"},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := &fakeProviderErrorCounter{}
+			app := newApp(counter)
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/super/metrics/provider-errors"+tt.query, nil))
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			assert.False(t, counter.called)
+		})
+	}
+
+	t.Run("repository error", func(t *testing.T) {
+		app := newApp(&fakeProviderErrorCounter{err: errors.New("connection refused")})
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/super/metrics/provider-errors", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	})
+}
@@ -22,7 +22,7 @@ func NewProvidersHandler(adminService admin.SuperAdminService, logger *slog.Logg
 
 // GetProvidersStatus handles GET /super/providers
 func (h *ProvidersHandler) GetProvidersStatus(c *fiber.Ctx) error {
-	providers, err := h.adminService.GetProvidersStatus(c.Context())
+	providers, err := h.adminService.GetProvidersStatus(c.UserContext())
 	if err != nil {
 		h.logger.Error("failed to get providers status", "error", err)
 		return fiber.ErrInternalServerError
@@ -0,0 +1,59 @@
+package super
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// TenantRateLimitResetter clears a tenant's counters in the shared rate limit store
+type TenantRateLimitResetter interface {
+	ResetTenant(ctx context.Context, tenantID uuid.UUID) (int, error)
+}
+
+// EndpointRateLimitResetter clears a tenant's per-endpoint windows of the API rate limiter
+type EndpointRateLimitResetter interface {
+	Reset(key string) int
+}
+
+type RateLimitsHandler struct {
+	store     TenantRateLimitResetter
+	endpoints EndpointRateLimitResetter
+	logger    *slog.Logger
+}
+
+func NewRateLimitsHandler(store TenantRateLimitResetter, endpoints EndpointRateLimitResetter, logger *slog.Logger) *RateLimitsHandler {
+	return &RateLimitsHandler{
+		store:     store,
+		endpoints: endpoints,
+		logger:    logger,
+	}
+}
+
+// ResetTenantRateLimits handles POST /super/tenants/:id/rate-limits/reset
+// It lets ops lift a false-positive rate limit without waiting for the window to expire.
+func (h *RateLimitsHandler) ResetTenantRateLimits(c *fiber.Ctx) error {
+	tenantIDStr := c.Params("id")
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		h.logger.Debug("invalid tenant id", "id", tenantIDStr)
+		return fiber.NewError(fiber.StatusBadRequest, "invalid tenant ID format")
+	}
+
+	cleared, err := h.store.ResetTenant(c.UserContext(), tenantID)
+	if err != nil {
+		h.logger.Error("failed to reset rate limits", "error", err, "tenant_id", tenantID)
+		return fiber.ErrInternalServerError
+	}
+	cleared += h.endpoints.Reset(tenantID.String())
+
+	h.logger.Info("tenant rate limits reset", "tenant_id", tenantID, "buckets_cleared", cleared)
+
+	return c.JSON(fiber.Map{
+		"message":         "rate limits reset successfully",
+		"tenant_id":       tenantID.String(),
+		"buckets_cleared": cleared,
+	})
+}
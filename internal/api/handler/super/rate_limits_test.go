@@ -0,0 +1,84 @@
+package super
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+)
+
+type MockRateLimitStore struct {
+	mock.Mock
+}
+
+func (m *MockRateLimitStore) ResetTenant(ctx context.Context, tenantID uuid.UUID) (int, error) {
+	args := m.Called(ctx, tenantID)
+	return args.Int(0), args.Error(1)
+}
+
+type MockEndpointRateLimiter struct {
+	mock.Mock
+}
+
+func (m *MockEndpointRateLimiter) Reset(key string) int {
+	args := m.Called(key)
+	return args.Int(0)
+}
+
+func TestRateLimitsHandler_ResetTenantRateLimits(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tenantID := uuid.New()
+
+	newApp := func(store TenantRateLimitResetter, endpoints EndpointRateLimitResetter) *fiber.App {
+		app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler(logger)})
+		app.Post("/super/tenants/:id/rate-limits/reset", NewRateLimitsHandler(store, endpoints, logger).ResetTenantRateLimits)
+		return app
+	}
+
+	t.Run("returns the buckets cleared in both limiters", func(t *testing.T) {
+		store := new(MockRateLimitStore)
+		store.On("ResetTenant", mock.Anything, tenantID).Return(1, nil)
+		endpoints := new(MockEndpointRateLimiter)
+		endpoints.On("Reset", tenantID.String()).Return(3)
+
+		resp, err := newApp(store, endpoints).Test(httptest.NewRequest("POST", "/super/tenants/"+tenantID.String()+"/rate-limits/reset", nil))
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var result map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, tenantID.String(), result["tenant_id"])
+		assert.Equal(t, float64(4), result["buckets_cleared"])
+		store.AssertExpectations(t)
+		endpoints.AssertExpectations(t)
+	})
+
+	t.Run("invalid tenant id", func(t *testing.T) {
+		resp, err := newApp(new(MockRateLimitStore), new(MockEndpointRateLimiter)).
+			Test(httptest.NewRequest("POST", "/super/tenants/invalid-uuid/rate-limits/reset", nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		store := new(MockRateLimitStore)
+		store.On("ResetTenant", mock.Anything, tenantID).Return(0, errors.New("connection refused"))
+		endpoints := new(MockEndpointRateLimiter)
+
+		resp, err := newApp(store, endpoints).Test(httptest.NewRequest("POST", "/super/tenants/"+tenantID.String()+"/rate-limits/reset", nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+		endpoints.AssertNotCalled(t, "Reset", mock.Anything)
+	})
+}
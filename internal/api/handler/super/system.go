@@ -22,7 +22,7 @@ func NewSystemHandler(adminService admin.SuperAdminService, logger *slog.Logger)
 
 // GetSystemHealth handles GET /super/system/health
 func (h *SystemHandler) GetSystemHealth(c *fiber.Ctx) error {
-	health, err := h.adminService.GetSystemHealth(c.Context())
+	health, err := h.adminService.GetSystemHealth(c.UserContext())
 	if err != nil {
 		h.logger.Error("failed to get system health", "error", err)
 		return fiber.ErrInternalServerError
@@ -41,7 +41,7 @@ func (h *SystemHandler) GetSystemHealth(c *fiber.Ctx) error {
 
 // GetSystemMetrics handles GET /super/system/metrics
 func (h *SystemHandler) GetSystemMetrics(c *fiber.Ctx) error {
-	metrics, err := h.adminService.GetSystemMetrics(c.Context())
+	metrics, err := h.adminService.GetSystemMetrics(c.UserContext())
 	if err != nil {
 		h.logger.Error("failed to get system metrics", "error", err)
 		return fiber.ErrInternalServerError
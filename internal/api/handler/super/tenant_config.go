@@ -0,0 +1,78 @@
+package super
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/admin"
+)
+
+// TenantConfigManager exports and imports a tenant's portable configuration
+type TenantConfigManager interface {
+	Export(ctx context.Context, tenantID uuid.UUID) (*admin.TenantConfig, error)
+	Import(ctx context.Context, tenantID uuid.UUID, config admin.TenantConfig) ([]admin.ImportedWebhook, error)
+}
+
+type TenantConfigHandler struct {
+	configs TenantConfigManager
+	logger  *slog.Logger
+}
+
+func NewTenantConfigHandler(configs TenantConfigManager, logger *slog.Logger) *TenantConfigHandler {
+	return &TenantConfigHandler{
+		configs: configs,
+		logger:  logger,
+	}
+}
+
+// ExportConfig handles GET /super/tenants/:id/config/export
+// The body can be passed unchanged to ImportConfig of another tenant.
+func (h *TenantConfigHandler) ExportConfig(c *fiber.Ctx) error {
+	tenantIDStr := c.Params("id")
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		h.logger.Debug("invalid tenant id", "id", tenantIDStr)
+		return fiber.NewError(fiber.StatusBadRequest, "invalid tenant ID format")
+	}
+
+	config, err := h.configs.Export(c.UserContext(), tenantID)
+	if err != nil {
+		h.logger.Error("failed to export tenant config", "error", err, "tenant_id", tenantID)
+		return err
+	}
+
+	c.Attachment("tenant-" + tenantID.String() + "-config.json")
+	return c.JSON(config)
+}
+
+// ImportConfig handles POST /super/tenants/:id/config/import
+// It replaces the tenant's plan, settings and webhooks; new webhook secrets are returned once.
+func (h *TenantConfigHandler) ImportConfig(c *fiber.Ctx) error {
+	tenantIDStr := c.Params("id")
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		h.logger.Debug("invalid tenant id", "id", tenantIDStr)
+		return fiber.NewError(fiber.StatusBadRequest, "invalid tenant ID format")
+	}
+
+	var config admin.TenantConfig
+	if err := c.BodyParser(&config); err != nil {
+		h.logger.Debug("invalid request body", "error", err)
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	webhooks, err := h.configs.Import(c.UserContext(), tenantID, config)
+	if err != nil {
+		h.logger.Error("failed to import tenant config", "error", err, "tenant_id", tenantID)
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message":   "config imported successfully",
+		"tenant_id": tenantID.String(),
+		"webhooks":  webhooks,
+	})
+}
@@ -0,0 +1,142 @@
+package super
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/admin"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+type MockTenantConfigManager struct {
+	mock.Mock
+}
+
+func (m *MockTenantConfigManager) Export(ctx context.Context, tenantID uuid.UUID) (*admin.TenantConfig, error) {
+	args := m.Called(ctx, tenantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*admin.TenantConfig), args.Error(1)
+}
+
+func (m *MockTenantConfigManager) Import(ctx context.Context, tenantID uuid.UUID, config admin.TenantConfig) ([]admin.ImportedWebhook, error) {
+	args := m.Called(ctx, tenantID, config)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]admin.ImportedWebhook), args.Error(1)
+}
+
+func newTenantConfigApp(configs TenantConfigManager) *fiber.App {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := NewTenantConfigHandler(configs, logger)
+
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler(logger)})
+	app.Get("/super/tenants/:id/config/export", handler.ExportConfig)
+	app.Post("/super/tenants/:id/config/import", handler.ImportConfig)
+	return app
+}
+
+func TestTenantConfigHandler_ExportImport(t *testing.T) {
+	source, target := uuid.New(), uuid.New()
+	config := &admin.TenantConfig{
+		Version:  admin.TenantConfigVersion,
+		Plan:     domain.PlanPro,
+		Settings: map[string]interface{}{"verification_threshold": 0.9, "search_enabled": true},
+		Webhooks: []admin.TenantConfigWebhook{
+			{Name: "crm", URL: "https://crm.example.com/hooks", Events: []string{"face.registered"}, Enabled: true},
+		},
+	}
+
+	configs := new(MockTenantConfigManager)
+	configs.On("Export", mock.Anything, source).Return(config, nil)
+	// The exported document is accepted by import unchanged
+	configs.On("Import", mock.Anything, target, *config).Return([]admin.ImportedWebhook{
+		{ID: uuid.New(), Name: "crm", URL: "https://crm.example.com/hooks", Secret: "new-secret"},
+	}, nil)
+	app := newTenantConfigApp(configs)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/super/tenants/"+source.String()+"/config/export", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Disposition"), "attachment")
+	exported, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.NotContains(t, string(exported), "secret")
+
+	req := httptest.NewRequest("POST", "/super/tenants/"+target.String()+"/config/import", bytes.NewReader(exported))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var result struct {
+		TenantID string                  `json:"tenant_id"`
+		Webhooks []admin.ImportedWebhook `json:"webhooks"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Equal(t, target.String(), result.TenantID)
+	require.Len(t, result.Webhooks, 1)
+	assert.Equal(t, "new-secret", result.Webhooks[0].Secret)
+
+	configs.AssertExpectations(t)
+}
+
+func TestTenantConfigHandler_Errors(t *testing.T) {
+	tenantID := uuid.New()
+
+	t.Run("invalid tenant id", func(t *testing.T) {
+		app := newTenantConfigApp(new(MockTenantConfigManager))
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/super/tenants/invalid-uuid/config/export", nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("unknown tenant", func(t *testing.T) {
+		configs := new(MockTenantConfigManager)
+		configs.On("Export", mock.Anything, tenantID).Return(nil, domain.ErrTenantNotFound)
+		app := newTenantConfigApp(configs)
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/super/tenants/"+tenantID.String()+"/config/export", nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("malformed body", func(t *testing.T) {
+		app := newTenantConfigApp(new(MockTenantConfigManager))
+
+		req := httptest.NewRequest("POST", "/super/tenants/"+tenantID.String()+"/config/import", bytes.NewReader([]byte("{")))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		configs := new(MockTenantConfigManager)
+		configs.On("Import", mock.Anything, tenantID, mock.Anything).
+			Return(nil, domain.ErrValidationFailed.WithError(errors.New("invalid plan \"gold\"")))
+		app := newTenantConfigApp(configs)
+
+		req := httptest.NewRequest("POST", "/super/tenants/"+tenantID.String()+"/config/import", bytes.NewReader([]byte(`{"version":1,"plan":"gold"}`)))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+	})
+}
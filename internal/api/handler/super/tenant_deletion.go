@@ -0,0 +1,98 @@
+package super
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/audit"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/service"
+)
+
+// TenantEraser deletes a tenant and everything stored for it
+type TenantEraser interface {
+	Delete(ctx context.Context, tenantID uuid.UUID) (*service.TenantDeletion, error)
+}
+
+type TenantDeletionHandler struct {
+	tenants     TenantLookup
+	eraser      TenantEraser
+	auditLogger audit.Logger
+	logger      *slog.Logger
+}
+
+func NewTenantDeletionHandler(tenants TenantLookup, eraser TenantEraser, auditLogger audit.Logger, logger *slog.Logger) *TenantDeletionHandler {
+	return &TenantDeletionHandler{
+		tenants:     tenants,
+		eraser:      eraser,
+		auditLogger: auditLogger,
+		logger:      logger,
+	}
+}
+
+// DeleteTenant handles DELETE /super/tenants/:id?confirm=<slug>
+// Deletion is irreversible, so the caller must repeat the tenant slug in confirm.
+func (h *TenantDeletionHandler) DeleteTenant(c *fiber.Ctx) error {
+	tenantIDStr := c.Params("id")
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		h.logger.Debug("invalid tenant id", "id", tenantIDStr)
+		return fiber.NewError(fiber.StatusBadRequest, "invalid tenant ID format")
+	}
+
+	adminID, err := middleware.GetAdminUserID(c)
+	if err != nil {
+		return err
+	}
+	adminEmail, _ := middleware.GetAdminEmail(c)
+
+	tenant, err := h.tenants.GetByID(c.UserContext(), tenantID)
+	if err != nil {
+		h.logger.Warn("tenant to delete not found", "error", err, "tenant_id", tenantID)
+		return domain.ErrTenantNotFound
+	}
+
+	if c.Query("confirm") != tenant.Slug {
+		return fiber.NewError(fiber.StatusBadRequest, "confirm must match the tenant slug")
+	}
+
+	deletion, err := h.eraser.Delete(c.UserContext(), tenantID)
+	if err != nil {
+		h.logger.Error("failed to delete tenant", "error", err, "tenant_id", tenantID)
+		return err
+	}
+
+	// The tenant is already gone, so a failing audit logger must not turn this into an error
+	if err := h.auditLogger.Log(c.UserContext(), audit.Event{
+		TenantID:  tenantID,
+		EventType: audit.EventTenantDeleted,
+		Success:   true,
+		Error:     deletion.CollectionError,
+		Metadata: map[string]string{
+			"super_admin_id":     adminID.String(),
+			"super_admin_email":  adminEmail,
+			"tenant_slug":        tenant.Slug,
+			"faces_deleted":      strconv.FormatInt(deletion.FacesDeleted, 10),
+			"collection_deleted": strconv.FormatBool(deletion.CollectionDeleted),
+		},
+		IPAddress: c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+	}); err != nil {
+		h.logger.Error("failed to audit tenant deletion", "error", err, "tenant_id", tenantID)
+	}
+
+	h.logger.Info("tenant deleted",
+		"tenant_id", tenantID,
+		"tenant_slug", tenant.Slug,
+		"user_id", adminID,
+		"faces_deleted", deletion.FacesDeleted,
+		"collection_deleted", deletion.CollectionDeleted,
+	)
+
+	return c.JSON(deletion)
+}
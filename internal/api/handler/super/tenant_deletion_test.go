@@ -0,0 +1,128 @@
+package super
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/admin"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/audit"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/service"
+)
+
+type MockTenantEraser struct {
+	mock.Mock
+}
+
+func (m *MockTenantEraser) Delete(ctx context.Context, tenantID uuid.UUID) (*service.TenantDeletion, error) {
+	args := m.Called(ctx, tenantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.TenantDeletion), args.Error(1)
+}
+
+func TestTenantDeletionHandler_DeleteTenant(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	jwtService := admin.NewJWTService("test-secret", "rekko-test", time.Hour)
+	adminID := uuid.New()
+	tenantID := uuid.New()
+	tenant := &domain.Tenant{ID: tenantID, Slug: "acme"}
+
+	newApp := func(tenants TenantLookup, eraser TenantEraser, auditLogger audit.Logger) *fiber.App {
+		handler := NewTenantDeletionHandler(tenants, eraser, auditLogger, logger)
+		app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler(logger)})
+		app.Use(middleware.AdminAuth(middleware.AdminLevelSuper, middleware.AdminAuthDependencies{
+			JWTService: jwtService,
+			Logger:     logger,
+		}))
+		app.Delete("/super/tenants/:id", handler.DeleteTenant)
+		return app
+	}
+	superToken, err := jwtService.GenerateToken(adminID, "support@rekko.com", "super_admin")
+	require.NoError(t, err)
+
+	doDelete := func(app *fiber.App, path string) *http.Response {
+		req := httptest.NewRequest("DELETE", path, nil)
+		req.Header.Set("Authorization", "Bearer "+superToken)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("deletes the tenant and audits what was erased", func(t *testing.T) {
+		tenants := new(MockTenantLookup)
+		tenants.On("GetByID", mock.Anything, tenantID).Return(tenant, nil)
+		eraser := new(MockTenantEraser)
+		eraser.On("Delete", mock.Anything, tenantID).Return(&service.TenantDeletion{
+			TenantID:          tenantID,
+			FacesDeleted:      42,
+			CollectionDeleted: true,
+		}, nil)
+		auditLogger := &recordingAuditLogger{}
+
+		resp := doDelete(newApp(tenants, eraser, auditLogger), "/super/tenants/"+tenantID.String()+"?confirm=acme")
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var result service.TenantDeletion
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, tenantID, result.TenantID)
+		assert.Equal(t, int64(42), result.FacesDeleted)
+		assert.True(t, result.CollectionDeleted)
+
+		require.Len(t, auditLogger.events, 1)
+		event := auditLogger.events[0]
+		assert.Equal(t, audit.EventTenantDeleted, event.EventType)
+		assert.Equal(t, tenantID, event.TenantID)
+		assert.Equal(t, adminID.String(), event.Metadata["super_admin_id"])
+		assert.Equal(t, "acme", event.Metadata["tenant_slug"])
+		assert.Equal(t, "42", event.Metadata["faces_deleted"])
+		assert.Equal(t, "true", event.Metadata["collection_deleted"])
+		eraser.AssertExpectations(t)
+	})
+
+	t.Run("wrong or missing confirmation deletes nothing", func(t *testing.T) {
+		for _, query := range []string{"", "?confirm=other"} {
+			tenants := new(MockTenantLookup)
+			tenants.On("GetByID", mock.Anything, tenantID).Return(tenant, nil)
+			eraser := new(MockTenantEraser)
+			auditLogger := &recordingAuditLogger{}
+
+			resp := doDelete(newApp(tenants, eraser, auditLogger), "/super/tenants/"+tenantID.String()+query)
+
+			assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode, query)
+			eraser.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+			assert.Empty(t, auditLogger.events)
+		}
+	})
+
+	t.Run("unknown tenant", func(t *testing.T) {
+		tenants := new(MockTenantLookup)
+		tenants.On("GetByID", mock.Anything, tenantID).Return(nil, domain.ErrTenantNotFound)
+		eraser := new(MockTenantEraser)
+
+		resp := doDelete(newApp(tenants, eraser, &recordingAuditLogger{}), "/super/tenants/"+tenantID.String()+"?confirm=acme")
+
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+		eraser.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid tenant id", func(t *testing.T) {
+		resp := doDelete(newApp(new(MockTenantLookup), new(MockTenantEraser), &recordingAuditLogger{}), "/super/tenants/invalid-uuid")
+
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+}
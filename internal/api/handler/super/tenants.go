@@ -2,11 +2,14 @@ package super
 
 import (
 	"log/slog"
+	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
 	"github.com/saturnino-fabrica-de-software/rekko/internal/admin"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
 )
 
 type TenantsHandler struct {
@@ -21,7 +24,8 @@ func NewTenantsHandler(adminService admin.SuperAdminService, logger *slog.Logger
 	}
 }
 
-// ListTenants handles GET /super/tenants
+// ListTenants handles GET /super/tenants, filtered by plan, is_active and a name/slug search
+// and sorted by created_at (default), faces, requests or error_rate
 func (h *TenantsHandler) ListTenants(c *fiber.Ctx) error {
 	limit := c.QueryInt("limit", 50)
 	offset := c.QueryInt("offset", 0)
@@ -36,7 +40,28 @@ func (h *TenantsHandler) ListTenants(c *fiber.Ctx) error {
 		offset = 0
 	}
 
-	tenants, err := h.adminService.ListAllTenants(c.Context(), limit, offset)
+	params := admin.TenantListParams{
+		Limit:  limit,
+		Offset: offset,
+		Plan:   c.Query("plan"),
+		Search: strings.TrimSpace(c.Query("search")),
+		Sort:   c.Query("sort", admin.TenantSortCreatedAt),
+	}
+	if params.Plan != "" && !domain.IsValidPlan(params.Plan) {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid plan, expected starter, pro or enterprise")
+	}
+	if !admin.IsValidTenantSort(params.Sort) {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid sort, expected created_at, faces, requests or error_rate")
+	}
+	if raw := c.Query("is_active"); raw != "" {
+		isActive, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid is_active, expected true or false")
+		}
+		params.IsActive = &isActive
+	}
+
+	tenants, err := h.adminService.ListAllTenants(c.UserContext(), params)
 	if err != nil {
 		h.logger.Error("failed to list tenants", "error", err)
 		return fiber.ErrInternalServerError
@@ -48,6 +73,12 @@ func (h *TenantsHandler) ListTenants(c *fiber.Ctx) error {
 			"total":  len(tenants),
 			"limit":  limit,
 			"offset": offset,
+			"filters": fiber.Map{
+				"plan":      params.Plan,
+				"is_active": params.IsActive,
+				"search":    params.Search,
+			},
+			"sort": params.Sort,
 		},
 	})
 }
@@ -61,7 +92,7 @@ func (h *TenantsHandler) GetTenantMetrics(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "invalid tenant ID format")
 	}
 
-	metrics, err := h.adminService.GetTenantDetailedMetrics(c.Context(), tenantID)
+	metrics, err := h.adminService.GetTenantDetailedMetrics(c.UserContext(), tenantID)
 	if err != nil {
 		h.logger.Error("failed to get tenant metrics", "error", err, "tenant_id", tenantID)
 		return fiber.ErrInternalServerError
@@ -90,7 +121,7 @@ func (h *TenantsHandler) UpdateTenantQuota(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
 	}
 
-	if err := h.adminService.UpdateTenantQuota(c.Context(), tenantID, req); err != nil {
+	if err := h.adminService.UpdateTenantQuota(c.UserContext(), tenantID, req); err != nil {
 		h.logger.Error("failed to update tenant quota", "error", err, "tenant_id", tenantID)
 		return fiber.ErrInternalServerError
 	}
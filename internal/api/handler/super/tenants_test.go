@@ -20,8 +20,8 @@ type MockAdminService struct {
 	mock.Mock
 }
 
-func (m *MockAdminService) ListAllTenants(ctx context.Context, limit, offset int) ([]admin.TenantWithMetrics, error) {
-	args := m.Called(ctx, limit, offset)
+func (m *MockAdminService) ListAllTenants(ctx context.Context, params admin.TenantListParams) ([]admin.TenantWithMetrics, error) {
+	args := m.Called(ctx, params)
 	return args.Get(0).([]admin.TenantWithMetrics), args.Error(1)
 }
 
@@ -60,7 +60,7 @@ func TestListTenants(t *testing.T) {
 		},
 	}
 
-	mockService.On("ListAllTenants", mock.Anything, 50, 0).Return(mockTenants, nil)
+	mockService.On("ListAllTenants", mock.Anything, admin.TenantListParams{Limit: 50, Sort: admin.TenantSortCreatedAt}).Return(mockTenants, nil)
 
 	app.Get("/super/tenants", handler.ListTenants)
 
@@ -79,6 +79,68 @@ func TestListTenants(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestListTenants_Filters(t *testing.T) {
+	active := true
+	inactive := false
+
+	tests := []struct {
+		name       string
+		query      string
+		want       admin.TenantListParams
+		wantStatus int
+	}{
+		{name: "plan", query: "?plan=enterprise", want: admin.TenantListParams{Limit: 50, Plan: "enterprise", Sort: admin.TenantSortCreatedAt}, wantStatus: fiber.StatusOK},
+		{name: "active", query: "?is_active=true", want: admin.TenantListParams{Limit: 50, IsActive: &active, Sort: admin.TenantSortCreatedAt}, wantStatus: fiber.StatusOK},
+		{name: "inactive", query: "?is_active=false", want: admin.TenantListParams{Limit: 50, IsActive: &inactive, Sort: admin.TenantSortCreatedAt}, wantStatus: fiber.StatusOK},
+		{name: "search", query: "?search=%20acme%20", want: admin.TenantListParams{Limit: 50, Search: "acme", Sort: admin.TenantSortCreatedAt}, wantStatus: fiber.StatusOK},
+		{name: "sort by faces", query: "?sort=faces", want: admin.TenantListParams{Limit: 50, Sort: admin.TenantSortFaces}, wantStatus: fiber.StatusOK},
+		{name: "sort by requests", query: "?sort=requests", want: admin.TenantListParams{Limit: 50, Sort: admin.TenantSortRequests}, wantStatus: fiber.StatusOK},
+		{name: "sort by error rate", query: "?sort=error_rate&limit=10&offset=20", want: admin.TenantListParams{Limit: 10, Offset: 20, Sort: admin.TenantSortErrorRate}, wantStatus: fiber.StatusOK},
+		{name: "unknown plan", query: "?plan=gold", wantStatus: fiber.StatusBadRequest},
+		{name: "invalid is_active", query: "?is_active=maybe", wantStatus: fiber.StatusBadRequest},
+		{name: "unknown sort", query: "?sort=latency", wantStatus: fiber.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			mockService := new(MockAdminService)
+			handler := NewTenantsHandler(mockService, slog.Default())
+			if tt.wantStatus == fiber.StatusOK {
+				mockService.On("ListAllTenants", mock.Anything, tt.want).Return([]admin.TenantWithMetrics{}, nil)
+			}
+
+			app.Get("/super/tenants", handler.ListTenants)
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/super/tenants"+tt.query, nil))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			mockService.AssertExpectations(t)
+			if tt.wantStatus != fiber.StatusOK {
+				mockService.AssertNotCalled(t, "ListAllTenants", mock.Anything, mock.Anything)
+				return
+			}
+
+			var result struct {
+				Meta struct {
+					Filters struct {
+						Plan     string `json:"plan"`
+						IsActive *bool  `json:"is_active"`
+						Search   string `json:"search"`
+					} `json:"filters"`
+					Sort string `json:"sort"`
+				} `json:"meta"`
+			}
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+			assert.Equal(t, tt.want.Plan, result.Meta.Filters.Plan)
+			assert.Equal(t, tt.want.IsActive, result.Meta.Filters.IsActive)
+			assert.Equal(t, tt.want.Search, result.Meta.Filters.Search)
+			assert.Equal(t, tt.want.Sort, result.Meta.Sort)
+		})
+	}
+}
+
 func TestGetTenantMetrics(t *testing.T) {
 	app := fiber.New()
 	mockService := new(MockAdminService)
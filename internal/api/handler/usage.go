@@ -39,9 +39,9 @@ func (h *UsageHandler) GetUsage(c *fiber.Ctx) error {
 
 	var summary *usage.UsageSummary
 	if period == "" {
-		summary, err = h.service.GetCurrentUsage(c.Context(), tenant.ID, tenant.Plan)
+		summary, err = h.service.GetCurrentUsage(c.UserContext(), tenant.ID, tenant.Plan)
 	} else {
-		summary, err = h.service.GetUsageForPeriod(c.Context(), tenant.ID, tenant.Plan, period)
+		summary, err = h.service.GetUsageForPeriod(c.UserContext(), tenant.ID, tenant.Plan, period)
 	}
 
 	if err != nil {
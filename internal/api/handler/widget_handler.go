@@ -11,7 +11,9 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
+	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
 	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/webhook"
 )
 
 // WidgetService interface for the service layer
@@ -21,7 +23,7 @@ type WidgetService interface {
 	Register(ctx context.Context, sessionID uuid.UUID, externalID string, imageBytes []byte) (*domain.Face, error)
 	ValidateLiveness(ctx context.Context, sessionID uuid.UUID, imageBytes []byte) (*domain.LivenessResult, error)
 	Search(ctx context.Context, sessionID uuid.UUID, imageBytes []byte, clientIP string) (*domain.SearchResult, error)
-	CheckRegistration(ctx context.Context, sessionID uuid.UUID, externalID string) (*domain.RegistrationCheck, error)
+	CheckRegistration(ctx context.Context, sessionID uuid.UUID, externalID, signature string) (*domain.RegistrationCheck, error)
 }
 
 // WidgetHandler handles widget-related requests
@@ -98,9 +100,9 @@ func (h *WidgetHandler) trackUsage(tenantID uuid.UUID, field string) {
 }
 
 // dispatchWidgetEvent dispatches a widget event to webhooks (best-effort, async)
-func (h *WidgetHandler) dispatchWidgetEvent(tenantID uuid.UUID, eventType string, data interface{}) {
+func (h *WidgetHandler) dispatchWidgetEvent(requestID string, tenantID uuid.UUID, eventType string, data interface{}) {
 	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := context.WithTimeout(webhook.WithRequestID(context.Background(), requestID), 5*time.Second)
 		defer cancel()
 
 		if err := h.webhookService.Dispatch(ctx, tenantID, eventType, data); err != nil {
@@ -143,7 +145,7 @@ func (h *WidgetHandler) CreateSession(c *fiber.Ctx) error {
 	}
 
 	// 3. Call service to create session
-	session, err := h.service.CreateSession(c.Context(), req.PublicKey, req.Origin)
+	session, err := h.service.CreateSession(c.UserContext(), req.PublicKey, req.Origin)
 	if err != nil {
 		return err
 	}
@@ -152,9 +154,9 @@ func (h *WidgetHandler) CreateSession(c *fiber.Ctx) error {
 	h.trackUsage(session.TenantID, "widget_sessions")
 
 	// 5. Dispatch webhook event (async)
-	h.dispatchWidgetEvent(session.TenantID, "widget.session_created", map[string]interface{}{
-		"session_id": session.ID.String(),
-		"origin":     session.Origin,
+	h.dispatchWidgetEvent(middleware.GetRequestID(c), session.TenantID, webhook.EventWidgetSessionCreated, webhook.WidgetSessionCreatedData{
+		SessionID: session.ID.String(),
+		Origin:    session.Origin,
 	})
 
 	// 6. Return response
@@ -197,29 +199,30 @@ func (h *WidgetHandler) Register(c *fiber.Ctx) error {
 	}
 
 	// 3. Extract and validate image
-	imageBytes, err := extractAndValidateImage(c)
+	// Widget uploads are camera frames encoded by the SDK, so only the supported set applies
+	imageBytes, err := extractAndValidateImage(c, domain.SupportedImageFormats)
 	if err != nil {
 		return fmt.Errorf("widget register: %w", err)
 	}
 
 	// 4. Call service to register
-	face, err := h.service.Register(c.Context(), sessionID, externalID, imageBytes)
+	face, err := h.service.Register(c.UserContext(), sessionID, externalID, imageBytes)
 	if err != nil {
 		return err
 	}
 
 	// 5. Get session for tenant_id (for tracking)
-	session, _ := h.service.ValidateSession(c.Context(), sessionID)
+	session, _ := h.service.ValidateSession(c.UserContext(), sessionID)
 	if session != nil {
 		// 6. Track usage (async)
 		h.trackUsage(session.TenantID, "widget_registrations")
 
 		// 7. Dispatch webhook event (async)
-		h.dispatchWidgetEvent(session.TenantID, "widget.registered", map[string]interface{}{
-			"face_id":       face.ID.String(),
-			"external_id":   face.ExternalID,
-			"quality_score": face.QualityScore,
-			"session_id":    sessionID.String(),
+		h.dispatchWidgetEvent(middleware.GetRequestID(c), session.TenantID, webhook.EventWidgetRegistered, webhook.WidgetRegisteredData{
+			FaceID:       face.ID.String(),
+			ExternalID:   face.ExternalID,
+			QualityScore: face.QualityScore,
+			SessionID:    sessionID.String(),
 		})
 	}
 
@@ -243,6 +246,7 @@ func (h *WidgetHandler) Register(c *fiber.Ctx) error {
 // @Success 200 {object} WidgetLivenessResponse
 // @Failure 400 {object} domain.AppError
 // @Failure 401 {object} domain.AppError
+// @Failure 422 {object} domain.AppError "REPLAY_SUSPECTED with replay_detection"
 // @Router /v1/widget/validate [post]
 func (h *WidgetHandler) ValidateLiveness(c *fiber.Ctx) error {
 	// 1. Extract session_id from form
@@ -257,28 +261,28 @@ func (h *WidgetHandler) ValidateLiveness(c *fiber.Ctx) error {
 	}
 
 	// 2. Extract and validate image
-	imageBytes, err := extractAndValidateImage(c)
+	imageBytes, err := extractAndValidateImage(c, domain.SupportedImageFormats)
 	if err != nil {
 		return fmt.Errorf("widget validate liveness: %w", err)
 	}
 
 	// 3. Call service to validate liveness
-	result, err := h.service.ValidateLiveness(c.Context(), sessionID, imageBytes)
+	result, err := h.service.ValidateLiveness(c.UserContext(), sessionID, imageBytes)
 	if err != nil {
 		return err
 	}
 
 	// 4. Get session for tenant_id (for tracking)
-	session, _ := h.service.ValidateSession(c.Context(), sessionID)
+	session, _ := h.service.ValidateSession(c.UserContext(), sessionID)
 	if session != nil {
 		// 5. Track usage (async)
 		h.trackUsage(session.TenantID, "widget_liveness_checks")
 
 		// 6. Dispatch webhook event (async)
-		h.dispatchWidgetEvent(session.TenantID, "widget.liveness_validated", map[string]interface{}{
-			"is_live":    result.IsLive,
-			"confidence": result.Confidence,
-			"session_id": sessionID.String(),
+		h.dispatchWidgetEvent(middleware.GetRequestID(c), session.TenantID, webhook.EventWidgetLivenessValidated, webhook.WidgetLivenessValidatedData{
+			IsLive:     result.IsLive,
+			Confidence: result.Confidence,
+			SessionID:  sessionID.String(),
 		})
 	}
 
@@ -296,9 +300,12 @@ func (h *WidgetHandler) ValidateLiveness(c *fiber.Ctx) error {
 	})
 }
 
-// WidgetSearchResponse represents the response for widget search (identify) operation
+// WidgetSearchResponse represents the response for widget search (identify) operation.
+// A top match below the tenant's search_identify_floor is returned as a candidate with identified=false.
+// Ambiguous flags a runner-up closer than the tenant's search_margin, for a human to review.
 type WidgetSearchResponse struct {
 	Identified bool    `json:"identified"`
+	Ambiguous  bool    `json:"ambiguous"`
 	ExternalID string  `json:"external_id,omitempty"`
 	Confidence float64 `json:"confidence,omitempty"`
 }
@@ -329,7 +336,7 @@ func (h *WidgetHandler) Search(c *fiber.Ctx) error {
 	}
 
 	// 2. Extract and validate image
-	imageBytes, err := extractAndValidateImage(c)
+	imageBytes, err := extractAndValidateImage(c, domain.SupportedImageFormats)
 	if err != nil {
 		return fmt.Errorf("widget search: %w", err)
 	}
@@ -338,28 +345,28 @@ func (h *WidgetHandler) Search(c *fiber.Ctx) error {
 	clientIP := c.IP()
 
 	// 4. Call service to search
-	result, err := h.service.Search(c.Context(), sessionID, imageBytes, clientIP)
+	result, err := h.service.Search(c.UserContext(), sessionID, imageBytes, clientIP)
 	if err != nil {
 		return err
 	}
 
 	// 5. Get session for tenant_id (for tracking)
-	session, _ := h.service.ValidateSession(c.Context(), sessionID)
+	session, _ := h.service.ValidateSession(c.UserContext(), sessionID)
 	if session != nil {
 		// 6. Track usage (async)
 		h.trackUsage(session.TenantID, "widget_searches")
 
 		// 7. Dispatch webhook event (async)
-		identified := len(result.Matches) > 0
-		eventData := map[string]interface{}{
-			"identified": identified,
-			"session_id": sessionID.String(),
+		eventData := webhook.WidgetSearchedData{
+			Identified: result.Identified,
+			SessionID:  sessionID.String(),
 		}
-		if identified {
-			eventData["external_id"] = result.Matches[0].ExternalID
-			eventData["confidence"] = result.Matches[0].Similarity
+		if eventData.Identified {
+			top := result.Matches[0]
+			eventData.ExternalID = &top.ExternalID
+			eventData.Confidence = &top.Similarity
 		}
-		h.dispatchWidgetEvent(session.TenantID, "widget.searched", eventData)
+		h.dispatchWidgetEvent(middleware.GetRequestID(c), session.TenantID, webhook.EventWidgetSearched, eventData)
 	}
 
 	// 8. Return response
@@ -371,7 +378,8 @@ func (h *WidgetHandler) Search(c *fiber.Ctx) error {
 
 	match := result.Matches[0]
 	return c.JSON(WidgetSearchResponse{
-		Identified: true,
+		Identified: result.Identified,
+		Ambiguous:  result.Ambiguous,
 		ExternalID: match.ExternalID,
 		Confidence: match.Similarity,
 	})
@@ -390,9 +398,12 @@ type CheckRegistrationResponse struct {
 // @Produce json
 // @Param session_id query string true "Widget session ID"
 // @Param external_id query string true "External user ID to check"
+// @Param signature query string false "HMAC-SHA256 of external_id, required when the tenant sets widget_check_secret"
 // @Success 200 {object} CheckRegistrationResponse
 // @Failure 400 {object} domain.AppError
 // @Failure 401 {object} domain.AppError
+// @Failure 403 {object} domain.AppError
+// @Failure 429 {object} domain.AppError
 // @Router /v1/widget/check [get]
 func (h *WidgetHandler) CheckRegistration(c *fiber.Ctx) error {
 	// 1. Extract session_id from query
@@ -413,7 +424,7 @@ func (h *WidgetHandler) CheckRegistration(c *fiber.Ctx) error {
 	}
 
 	// 3. Call service to check registration
-	result, err := h.service.CheckRegistration(c.Context(), sessionID, externalID)
+	result, err := h.service.CheckRegistration(c.UserContext(), sessionID, externalID, strings.TrimSpace(c.Query("signature")))
 	if err != nil {
 		return err
 	}
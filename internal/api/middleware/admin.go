@@ -15,6 +15,8 @@ const (
 	LocalAdminUser = "admin_user"
 	// LocalAdminRole is the key to retrieve admin role from context
 	LocalAdminRole = "admin_role"
+	// LocalAdminEmail is the key to retrieve admin email from context
+	LocalAdminEmail = "admin_email"
 )
 
 // AdminLevel defines the level of admin access required
@@ -75,6 +77,7 @@ func validateSuperAdmin(c *fiber.Ctx, deps AdminAuthDependencies) error {
 	// Store admin info in context
 	c.Locals(LocalAdminUser, claims.UserID)
 	c.Locals(LocalAdminRole, claims.Role)
+	c.Locals(LocalAdminEmail, claims.Email)
 
 	deps.Logger.Debug("super admin authenticated",
 		"user_id", claims.UserID,
@@ -122,6 +125,15 @@ func GetAdminUserID(c *fiber.Ctx) (uuid.UUID, error) {
 	return userID, nil
 }
 
+// GetAdminEmail retrieves admin email from context (for super admin)
+func GetAdminEmail(c *fiber.Ctx) (string, error) {
+	email, ok := c.Locals(LocalAdminEmail).(string)
+	if !ok {
+		return "", domain.ErrUnauthorized
+	}
+	return email, nil
+}
+
 // GetAdminRole retrieves admin role from context
 func GetAdminRole(c *fiber.Ctx) (string, error) {
 	role, ok := c.Locals(LocalAdminRole).(string)
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"log/slog"
 	"strings"
 
@@ -26,6 +27,15 @@ type AuthDependencies struct {
 	APIKeyRepo     repository.APIKeyRepositoryInterface
 	Logger         *slog.Logger
 	LastUsedWorker *LastUsedWorker // Optional: if nil, last_used updates are skipped
+	// Provisioner is optional: if nil, keys without a tenant are rejected
+	Provisioner TenantProvisioner
+	// Impersonation is optional: if nil, super admin impersonation tokens are rejected
+	Impersonation *ImpersonationDependencies
+}
+
+// TenantProvisioner creates the tenant for a pre-issued self-serve API key on first use
+type TenantProvisioner interface {
+	Provision(ctx context.Context, key *domain.APIKey) (uuid.UUID, error)
 }
 
 // Auth creates an authentication middleware using API Key
@@ -38,7 +48,10 @@ func Auth(deps AuthDependencies) fiber.Handler {
 			return domain.ErrUnauthorized
 		}
 
-		// 2. Validate format
+		// 2. Validate format; a super admin impersonation token is not an API key
+		if deps.Impersonation != nil && isJWT(apiKey) {
+			return authenticateImpersonation(c, deps, apiKey)
+		}
 		if !domain.IsValidFormat(apiKey) {
 			deps.Logger.Warn("invalid api key format", "prefix", extractPrefix(apiKey))
 			return domain.ErrInvalidAPIKeyFormat
@@ -48,7 +61,7 @@ func Auth(deps AuthDependencies) fiber.Handler {
 		hash := domain.HashAPIKey(apiKey)
 
 		// 4. Get API Key from repository
-		apiKeyEntity, err := deps.APIKeyRepo.GetByHash(c.Context(), hash)
+		apiKeyEntity, err := deps.APIKeyRepo.GetByHash(c.UserContext(), hash)
 		if err != nil {
 			deps.Logger.Warn("api key not found", "error", err)
 			return domain.ErrUnauthorized
@@ -60,23 +73,42 @@ func Auth(deps AuthDependencies) fiber.Handler {
 			return domain.ErrAPIKeyRevoked
 		}
 
-		// 6. Get tenant
-		tenant, err := deps.TenantRepo.GetByID(c.Context(), apiKeyEntity.TenantID)
+		// 6. Provision the tenant on first use of a pre-issued self-serve key
+		if apiKeyEntity.NeedsProvisioning() {
+			if deps.Provisioner == nil || !apiKeyEntity.AutoProvision {
+				deps.Logger.Warn("api key has no tenant", "key_id", apiKeyEntity.ID, "key_prefix", apiKeyEntity.KeyPrefix)
+				return domain.ErrUnauthorized
+			}
+
+			tenantID, err := deps.Provisioner.Provision(c.UserContext(), apiKeyEntity)
+			if err != nil {
+				deps.Logger.Error("tenant provisioning failed", "key_id", apiKeyEntity.ID, "error", err)
+				return err
+			}
+			apiKeyEntity.TenantID = tenantID
+		}
+
+		// 7. Get tenant
+		tenant, err := deps.TenantRepo.GetByID(c.UserContext(), apiKeyEntity.TenantID)
 		if err != nil {
 			deps.Logger.Warn("tenant not found", "tenant_id", apiKeyEntity.TenantID, "error", err)
 			return domain.ErrUnauthorized
 		}
 
-		// 7. Check tenant is active
+		// 8. Check tenant is active
 		if !tenant.IsActive {
 			deps.Logger.Warn("tenant is inactive", "tenant_id", tenant.ID, "tenant_slug", tenant.Slug)
 			return domain.ErrTenantInactive
 		}
 
-		// 8. Store in context
+		// 9. Store in context
 		c.Locals(LocalTenantID, tenant.ID)
 		c.Locals(LocalTenant, tenant)
 		c.Locals(LocalAPIKey, apiKeyEntity)
+		if apiKeyEntity.Environment == domain.EnvTest {
+			c.SetUserContext(domain.WithTestMode(c.UserContext()))
+		}
+		c.SetUserContext(domain.WithClientIP(c.UserContext(), c.IP()))
 
 		deps.Logger.Debug("authenticated",
 			"tenant_id", tenant.ID,
@@ -85,7 +117,7 @@ func Auth(deps AuthDependencies) fiber.Handler {
 			"environment", apiKeyEntity.Environment,
 		)
 
-		// 9. Update last used in background (non-blocking, after all validations passed)
+		// 10. Update last used in background (non-blocking, after all validations passed)
 		if deps.LastUsedWorker != nil {
 			deps.LastUsedWorker.Enqueue(apiKeyEntity.ID)
 		}
@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"net/http/httptest"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
 )
@@ -122,6 +124,11 @@ func (m *MockAPIKeyRepo) Revoke(ctx context.Context, id uuid.UUID) error {
 	return args.Error(0)
 }
 
+func (m *MockAPIKeyRepo) RevokeByTenant(ctx context.Context, tenantID, id uuid.UUID) error {
+	args := m.Called(ctx, tenantID, id)
+	return args.Error(0)
+}
+
 func (m *MockAPIKeyRepo) Delete(ctx context.Context, id uuid.UUID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -331,6 +338,180 @@ func TestAuth(t *testing.T) {
 	}
 }
 
+func TestAuth_MultipleActiveKeysPerTenant(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tenant := &domain.Tenant{
+		ID:       uuid.New(),
+		Name:     "Test Tenant",
+		Slug:     "test-tenant",
+		IsActive: true,
+		Plan:     domain.PlanStarter,
+	}
+
+	type issuedKey struct {
+		plain  string
+		entity *domain.APIKey
+	}
+
+	mockTenantRepo := &MockTenantRepo{}
+	mockAPIKeyRepo := &MockAPIKeyRepo{}
+	mockTenantRepo.On("GetByID", mock.Anything, tenant.ID).Return(tenant, nil)
+
+	keys := make([]issuedKey, 0, 3)
+	for _, label := range []string{"mobile app", "gate", "backend"} {
+		plain, hash, prefix, err := domain.GenerateAPIKey(domain.KeyTypeSecret, domain.EnvLive)
+		require.NoError(t, err)
+		entity := &domain.APIKey{
+			ID:          uuid.New(),
+			TenantID:    tenant.ID,
+			Name:        label,
+			KeyHash:     hash,
+			KeyPrefix:   prefix,
+			Environment: domain.EnvLive,
+			IsActive:    label != "gate", // gate key was revoked
+		}
+		mockAPIKeyRepo.On("GetByHash", mock.Anything, hash).Return(entity, nil)
+		keys = append(keys, issuedKey{plain: plain, entity: entity})
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(logger)})
+	app.Use(Auth(AuthDependencies{
+		TenantRepo: mockTenantRepo,
+		APIKeyRepo: mockAPIKeyRepo,
+		Logger:     logger,
+	}))
+	app.Get("/test", func(c *fiber.Ctx) error {
+		apiKey, err := GetAPIKey(c)
+		if err != nil {
+			return err
+		}
+		return c.SendString(apiKey.Name)
+	})
+
+	var wg sync.WaitGroup
+	for _, k := range keys {
+		wg.Add(1)
+		go func(k issuedKey) {
+			defer wg.Done()
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+k.plain)
+
+			resp, err := app.Test(req, -1)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			if !k.entity.IsActive {
+				assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode, k.entity.Name)
+				return
+			}
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode, k.entity.Name)
+			body, _ := io.ReadAll(resp.Body)
+			assert.Equal(t, k.entity.Name, string(body), "request is attributed to the key that was used")
+		}(k)
+	}
+	wg.Wait()
+
+	mockAPIKeyRepo.AssertExpectations(t)
+}
+
+func TestAuth_TestEnvironmentKey(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tenant := &domain.Tenant{
+		ID:       uuid.New(),
+		Name:     "Test Tenant",
+		Slug:     "test-tenant",
+		IsActive: true,
+		Plan:     domain.PlanStarter,
+	}
+
+	mockTenantRepo := &MockTenantRepo{}
+	mockAPIKeyRepo := &MockAPIKeyRepo{}
+	mockTenantRepo.On("GetByID", mock.Anything, tenant.ID).Return(tenant, nil)
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(logger)})
+	app.Use(Auth(AuthDependencies{
+		TenantRepo: mockTenantRepo,
+		APIKeyRepo: mockAPIKeyRepo,
+		Logger:     logger,
+	}))
+	app.Get("/test", func(c *fiber.Ctx) error {
+		if domain.IsTestMode(c.UserContext()) {
+			return c.SendString(domain.EnvTest)
+		}
+		return c.SendString(domain.EnvLive)
+	})
+
+	for _, env := range []string{domain.EnvTest, domain.EnvLive} {
+		t.Run(env, func(t *testing.T) {
+			plain, hash, prefix, err := domain.GenerateAPIKey(domain.KeyTypeSecret, env)
+			require.NoError(t, err)
+			mockAPIKeyRepo.On("GetByHash", mock.Anything, hash).Return(&domain.APIKey{
+				ID:          uuid.New(),
+				TenantID:    tenant.ID,
+				KeyHash:     hash,
+				KeyPrefix:   prefix,
+				Environment: env,
+				IsActive:    true,
+			}, nil)
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+plain)
+			resp, err := app.Test(req, -1)
+			require.NoError(t, err)
+			require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+			body, _ := io.ReadAll(resp.Body)
+			assert.Equal(t, env, string(body), "only test keys run in test mode")
+		})
+	}
+}
+
+func TestAuth_RecordsClientIP(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tenant := &domain.Tenant{
+		ID:       uuid.New(),
+		Name:     "Test Tenant",
+		Slug:     "test-tenant",
+		IsActive: true,
+		Plan:     domain.PlanStarter,
+	}
+
+	plain, hash, prefix, err := domain.GenerateAPIKey(domain.KeyTypeSecret, domain.EnvLive)
+	require.NoError(t, err)
+
+	mockTenantRepo := &MockTenantRepo{}
+	mockAPIKeyRepo := &MockAPIKeyRepo{}
+	mockTenantRepo.On("GetByID", mock.Anything, tenant.ID).Return(tenant, nil)
+	mockAPIKeyRepo.On("GetByHash", mock.Anything, hash).Return(&domain.APIKey{
+		ID:          uuid.New(),
+		TenantID:    tenant.ID,
+		KeyHash:     hash,
+		KeyPrefix:   prefix,
+		Environment: domain.EnvLive,
+		IsActive:    true,
+	}, nil)
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(logger)})
+	app.Use(Auth(AuthDependencies{
+		TenantRepo: mockTenantRepo,
+		APIKeyRepo: mockAPIKeyRepo,
+		Logger:     logger,
+	}))
+	app.Get("/test", func(c *fiber.Ctx) error {
+		return c.SendString(domain.ClientIPFrom(c.UserContext()))
+	})
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+plain)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "0.0.0.0", string(body), "verifications are attributed to the calling device")
+}
+
 func TestExtractBearerToken(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -496,3 +677,96 @@ func TestGetAPIKey(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+// fakeProvisioner binds a key to a new tenant the way the provisioning repository does
+type fakeProvisioner struct {
+	mu      sync.Mutex
+	calls   int
+	tenants *MockTenantRepo
+	key     *domain.APIKey
+}
+
+func (f *fakeProvisioner) Provision(ctx context.Context, key *domain.APIKey) (uuid.UUID, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+
+	tenant := domain.NewProvisionedTenant(key)
+	f.tenants.On("GetByID", mock.Anything, tenant.ID).Return(tenant, nil)
+	// Later lookups of the key see it bound to the new tenant
+	f.key.TenantID = tenant.ID
+	f.key.AutoProvision = false
+	return tenant.ID, nil
+}
+
+func TestAuth_AutoProvisioning(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	newApp := func(t *testing.T, key *domain.APIKey, withProvisioner bool) (*fiber.App, string, *fakeProvisioner) {
+		plain, hash, prefix, err := domain.GenerateAPIKey(domain.KeyTypeSecret, domain.EnvLive)
+		require.NoError(t, err)
+		key.KeyHash, key.KeyPrefix = hash, prefix
+
+		mockTenantRepo := &MockTenantRepo{}
+		mockAPIKeyRepo := &MockAPIKeyRepo{}
+		mockAPIKeyRepo.On("GetByHash", mock.Anything, hash).Return(key, nil)
+
+		provisioner := &fakeProvisioner{tenants: mockTenantRepo, key: key}
+		deps := AuthDependencies{TenantRepo: mockTenantRepo, APIKeyRepo: mockAPIKeyRepo, Logger: logger}
+		if withProvisioner {
+			deps.Provisioner = provisioner
+		}
+
+		app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(logger)})
+		app.Use(Auth(deps))
+		app.Get("/test", func(c *fiber.Ctx) error {
+			tenantID, err := GetTenantID(c)
+			if err != nil {
+				return err
+			}
+			return c.SendString(tenantID.String())
+		})
+		return app, plain, provisioner
+	}
+
+	call := func(t *testing.T, app *fiber.App, plain string) (int, string) {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.Header.Set("Authorization", "Bearer "+plain)
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	t.Run("first use provisions the tenant and later requests reuse it", func(t *testing.T) {
+		key := &domain.APIKey{ID: uuid.New(), Name: "Acme signup", IsActive: true, AutoProvision: true}
+		app, plain, provisioner := newApp(t, key, true)
+
+		status, first := call(t, app, plain)
+		require.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, key.TenantID.String(), first)
+
+		status, second := call(t, app, plain)
+		require.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, first, second, "provisioned tenant is reused")
+		assert.Equal(t, 1, provisioner.calls)
+	})
+
+	t.Run("unbound key without the auto_provision flag is rejected", func(t *testing.T) {
+		key := &domain.APIKey{ID: uuid.New(), Name: "orphan", IsActive: true}
+		app, plain, provisioner := newApp(t, key, true)
+
+		status, _ := call(t, app, plain)
+		assert.Equal(t, fiber.StatusUnauthorized, status)
+		assert.Zero(t, provisioner.calls)
+	})
+
+	t.Run("auto_provision key is rejected when provisioning is disabled", func(t *testing.T) {
+		key := &domain.APIKey{ID: uuid.New(), Name: "Acme signup", IsActive: true, AutoProvision: true}
+		app, plain, provisioner := newApp(t, key, false)
+
+		status, _ := call(t, app, plain)
+		assert.Equal(t, fiber.StatusUnauthorized, status)
+		assert.Zero(t, provisioner.calls)
+	})
+}
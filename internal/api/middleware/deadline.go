@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+// HeaderTimeoutMs carries the client's time budget for the request, in milliseconds
+const HeaderTimeoutMs = "X-Rekko-Timeout-Ms"
+
+// Deadline bounds the request's user context by the budget the client sends in X-Rekko-Timeout-Ms,
+// clamped to maxTimeout, so provider and database calls stop once the client has given up.
+// Handlers must pass c.UserContext() downstream for the deadline to apply.
+// Requests without the header run unbounded.
+func Deadline(maxTimeout time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		raw := c.Get(HeaderTimeoutMs)
+		if raw == "" {
+			return c.Next()
+		}
+
+		ms, err := strconv.Atoi(raw)
+		if err != nil || ms <= 0 {
+			return domain.ErrValidationFailed.WithError(fmt.Errorf("%s must be a positive integer, got %q", HeaderTimeoutMs, raw))
+		}
+
+		timeout := time.Duration(ms) * time.Millisecond
+		if maxTimeout > 0 && timeout > maxTimeout {
+			timeout = maxTimeout
+		}
+
+		ctx, cancel := context.WithTimeout(c.UserContext(), timeout)
+		defer cancel()
+		c.SetUserContext(ctx)
+
+		err = c.Next()
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return domain.ErrRequestTimeout.WithError(err)
+		}
+		return err
+	}
+}
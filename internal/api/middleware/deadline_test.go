@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadline(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	// newApp records the deadline the handler sees, as time remaining when it ran
+	newApp := func(maxTimeout time.Duration, remaining *time.Duration, hasDeadline *bool) *fiber.App {
+		app := newTestApp(logger)
+		app.Use(Deadline(maxTimeout))
+		app.Get("/test", func(c *fiber.Ctx) error {
+			deadline, ok := c.UserContext().Deadline()
+			*hasDeadline = ok
+			*remaining = time.Until(deadline)
+			return c.SendStatus(http.StatusNoContent)
+		})
+		return app
+	}
+
+	t.Run("applies the client budget", func(t *testing.T) {
+		var remaining time.Duration
+		var hasDeadline bool
+		app := newApp(30*time.Second, &remaining, &hasDeadline)
+
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set(HeaderTimeoutMs, "1500")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.True(t, hasDeadline)
+		assert.InDelta(t, 1500*time.Millisecond, remaining, float64(200*time.Millisecond))
+	})
+
+	t.Run("clamps the budget to the maximum", func(t *testing.T) {
+		var remaining time.Duration
+		var hasDeadline bool
+		app := newApp(2*time.Second, &remaining, &hasDeadline)
+
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set(HeaderTimeoutMs, "600000")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.True(t, hasDeadline)
+		assert.LessOrEqual(t, remaining, 2*time.Second)
+		assert.Greater(t, remaining, time.Second)
+	})
+
+	t.Run("no header leaves the request unbounded", func(t *testing.T) {
+		var remaining time.Duration
+		hasDeadline := true
+		app := newApp(2*time.Second, &remaining, &hasDeadline)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/test", nil))
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.False(t, hasDeadline)
+	})
+
+	for _, value := range []string{"abc", "0", "-100"} {
+		t.Run("rejects invalid header "+value, func(t *testing.T) {
+			var remaining time.Duration
+			var hasDeadline bool
+			app := newApp(2*time.Second, &remaining, &hasDeadline)
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set(HeaderTimeoutMs, value)
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+		})
+	}
+
+	t.Run("elapsed budget returns REQUEST_TIMEOUT", func(t *testing.T) {
+		app := newTestApp(logger)
+		app.Use(Deadline(time.Second))
+		app.Get("/slow", func(c *fiber.Ctx) error {
+			// Stands in for a provider or database call honoring the context
+			<-c.UserContext().Done()
+			return c.UserContext().Err()
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+		req.Header.Set(HeaderTimeoutMs, "20")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusRequestTimeout, resp.StatusCode)
+
+		var body struct {
+			Error struct {
+				Code string `json:"code"`
+			} `json:"error"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, "REQUEST_TIMEOUT", body.Error.Code)
+	})
+}
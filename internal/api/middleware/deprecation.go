@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// HeaderDeprecation marks a deprecated route with the date it was deprecated (RFC 9745)
+	HeaderDeprecation = "Deprecation"
+	// HeaderSunset is when a deprecated route stops working (RFC 8594)
+	HeaderSunset = "Sunset"
+)
+
+// DeprecationNotice describes a route, or a behavior of it, that clients should migrate away from
+type DeprecationNotice struct {
+	// Since is when the route was deprecated
+	Since time.Time
+	// Sunset is when the route stops working; zero omits the Sunset header
+	Sunset time.Time
+	// Link points to the migration notes, sent as a rel="deprecation" Link; empty omits it
+	Link string
+}
+
+// Deprecated sets the Deprecation, Sunset and Link headers of notice on every response of the
+// route, errors included, so clients and their HTTP tooling can warn before the route goes away
+func Deprecated(notice DeprecationNotice) fiber.Handler {
+	deprecation := fmt.Sprintf("@%d", notice.Since.Unix())
+	var sunset string
+	if !notice.Sunset.IsZero() {
+		sunset = notice.Sunset.UTC().Format(http.TimeFormat)
+	}
+	var link string
+	if notice.Link != "" {
+		link = fmt.Sprintf(`<%s>; rel="deprecation"; type="text/html"`, notice.Link)
+	}
+
+	return func(c *fiber.Ctx) error {
+		c.Set(HeaderDeprecation, deprecation)
+		if sunset != "" {
+			c.Set(HeaderSunset, sunset)
+		}
+		if link != "" {
+			c.Append(fiber.HeaderLink, link)
+		}
+		return c.Next()
+	}
+}
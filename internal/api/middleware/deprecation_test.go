@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+func TestDeprecated(t *testing.T) {
+	since := time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, time.June, 30, 0, 0, 0, 0, time.UTC)
+
+	newApp := func(notice DeprecationNotice) *fiber.App {
+		app := newTestApp(slog.New(slog.NewTextHandler(io.Discard, nil)))
+		app.Get("/faces/compare", Deprecated(notice), func(c *fiber.Ctx) error {
+			return domain.ErrEmbeddingUnavailable
+		})
+		app.Get("/faces", func(c *fiber.Ctx) error {
+			return c.SendString("listed")
+		})
+		return app
+	}
+
+	t.Run("deprecated route carries deprecation headers even on errors", func(t *testing.T) {
+		app := newApp(DeprecationNotice{Since: since, Sunset: sunset, Link: "/swagger/"})
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/faces/compare", nil))
+		require.NoError(t, err)
+		assert.Equal(t, domain.ErrEmbeddingUnavailable.StatusCode, resp.StatusCode)
+		assert.Equal(t, "@1792281600", resp.Header.Get(HeaderDeprecation))
+		assert.Equal(t, "Wed, 30 Jun 2027 00:00:00 GMT", resp.Header.Get(HeaderSunset))
+		assert.Equal(t, `</swagger/>; rel="deprecation"; type="text/html"`, resp.Header.Get(fiber.HeaderLink))
+	})
+
+	t.Run("sunset and link are omitted when unset", func(t *testing.T) {
+		app := newApp(DeprecationNotice{Since: since})
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/faces/compare", nil))
+		require.NoError(t, err)
+		assert.Equal(t, "@1792281600", resp.Header.Get(HeaderDeprecation))
+		assert.Empty(t, resp.Header.Get(HeaderSunset))
+		assert.Empty(t, resp.Header.Get(fiber.HeaderLink))
+	})
+
+	t.Run("other routes carry no deprecation headers", func(t *testing.T) {
+		app := newApp(DeprecationNotice{Since: since, Sunset: sunset, Link: "/swagger/"})
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/faces", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get(HeaderDeprecation))
+		assert.Empty(t, resp.Header.Get(HeaderSunset))
+		assert.Empty(t, resp.Header.Get(fiber.HeaderLink))
+	})
+}
@@ -3,11 +3,18 @@ package middleware
 import (
 	"errors"
 	"log/slog"
+	"math"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
 )
 
+// superAdminOnlyCodes lists error codes whose details are only exposed to super admins
+var superAdminOnlyCodes = map[string]bool{
+	domain.ErrProviderAccessDenied.Code: true,
+}
+
 func ErrorHandler(logger *slog.Logger) fiber.ErrorHandler {
 	return func(c *fiber.Ctx, err error) error {
 		// Check if it's a Fiber error
@@ -24,8 +31,8 @@ func ErrorHandler(logger *slog.Logger) fiber.ErrorHandler {
 		// Check if it's our AppError
 		var appErr *domain.AppError
 		if errors.As(err, &appErr) {
-			// Log internal errors
-			if appErr.StatusCode >= 500 {
+			// Log internal errors; maintenance is switched on deliberately
+			if appErr.StatusCode >= 500 && appErr.Code != domain.ErrMaintenance.Code {
 				logger.Error("internal error",
 					slog.String("code", appErr.Code),
 					slog.String("message", appErr.Message),
@@ -33,11 +40,39 @@ func ErrorHandler(logger *slog.Logger) fiber.ErrorHandler {
 				)
 			}
 
+			// Mask operational details from tenants
+			if superAdminOnlyCodes[appErr.Code] && !isSuperAdmin(c) {
+				return c.Status(domain.ErrInternal.StatusCode).JSON(fiber.Map{
+					"error": fiber.Map{
+						"code":    domain.ErrInternal.Code,
+						"message": domain.ErrInternal.Message,
+					},
+				})
+			}
+
+			body := fiber.Map{
+				"code":    appErr.Code,
+				"message": appErr.Message,
+			}
+			if len(appErr.Reasons) > 0 {
+				body["reasons"] = appErr.Reasons
+			}
+			if usage := appErr.RateLimit; usage != nil {
+				body["limit"] = usage.Limit
+				body["current"] = usage.Current
+				body["reset_at"] = usage.ResetAt.Format(time.RFC3339)
+				setRateLimitHeaders(c, usage)
+			}
+			if faces := appErr.FaceCount; faces != nil {
+				body["faces_detected"] = faces.Detected
+				body["max_faces"] = faces.Allowed
+			}
+			if appErr.RetryAfter > 0 {
+				c.Set("Retry-After", intToString(max(int(math.Ceil(appErr.RetryAfter.Seconds())), 1)))
+			}
+
 			return c.Status(appErr.StatusCode).JSON(fiber.Map{
-				"error": fiber.Map{
-					"code":    appErr.Code,
-					"message": appErr.Message,
-				},
+				"error": body,
 			})
 		}
 
@@ -55,3 +90,18 @@ func ErrorHandler(logger *slog.Logger) fiber.ErrorHandler {
 		})
 	}
 }
+
+// setRateLimitHeaders tells the client exactly when the quota that rejected it resets
+func setRateLimitHeaders(c *fiber.Ctx, usage *domain.RateLimitUsage) {
+	retryAfter := int(math.Ceil(time.Until(usage.ResetAt).Seconds()))
+	c.Set("Retry-After", intToString(max(retryAfter, 1)))
+	c.Set("X-RateLimit-Limit", intToString(usage.Limit))
+	c.Set("X-RateLimit-Remaining", intToString(max(usage.Limit-usage.Current, 0)))
+	c.Set("X-RateLimit-Reset", usage.ResetAt.Format(time.RFC3339))
+}
+
+// isSuperAdmin reports whether the request was authenticated as super admin
+func isSuperAdmin(c *fiber.Ctx) bool {
+	role, ok := c.Locals(LocalAdminRole).(string)
+	return ok && role == string(AdminLevelSuper)
+}
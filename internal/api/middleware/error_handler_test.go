@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+func TestErrorHandler_ProviderAccessDenied(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       string
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:       "super admin sees provider error",
+			role:       string(AdminLevelSuper),
+			wantStatus: http.StatusBadGateway,
+			wantCode:   "PROVIDER_ACCESS_DENIED",
+		},
+		{
+			name:       "tenant gets masked error",
+			role:       "",
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   "INTERNAL_ERROR",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(slog.New(slog.NewTextHandler(io.Discard, nil)))
+			app.Get("/test", func(c *fiber.Ctx) error {
+				if tt.role != "" {
+					c.Locals(LocalAdminRole, tt.role)
+				}
+				return fmt.Errorf("tenant abc: %w", domain.ErrProviderAccessDenied)
+			})
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/test", nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			var body struct {
+				Error struct {
+					Code string `json:"code"`
+				} `json:"error"`
+			}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, tt.wantCode, body.Error.Code)
+		})
+	}
+}
+
+func TestErrorHandler_Reasons(t *testing.T) {
+	app := newTestApp(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	app.Get("/reasons", func(c *fiber.Ctx) error {
+		return fmt.Errorf("register face: %w", domain.ErrNoFaceDetected.WithReasons("too_dark"))
+	})
+	app.Get("/plain", func(c *fiber.Ctx) error {
+		return domain.ErrNoFaceDetected
+	})
+
+	decode := func(t *testing.T, path string) map[string]interface{} {
+		t.Helper()
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+		var body struct {
+			Error map[string]interface{} `json:"error"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return body.Error
+	}
+
+	withReasons := decode(t, "/reasons")
+	assert.Equal(t, "NO_FACE_DETECTED", withReasons["code"])
+	assert.Equal(t, []interface{}{"too_dark"}, withReasons["reasons"])
+
+	plain := decode(t, "/plain")
+	assert.NotContains(t, plain, "reasons")
+}
+
+func TestErrorHandler_FaceCount(t *testing.T) {
+	app := newTestApp(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return fmt.Errorf("register face: %w", domain.ErrMultipleFaces.WithFaceCount(3, 1))
+	})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var body struct {
+		Error map[string]interface{} `json:"error"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "MULTIPLE_FACES", body.Error["code"])
+	assert.Equal(t, "3 faces detected, only 1 allowed", body.Error["message"])
+	assert.Equal(t, float64(3), body.Error["faces_detected"])
+	assert.Equal(t, float64(1), body.Error["max_faces"])
+}
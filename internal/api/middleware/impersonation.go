@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/admin"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/audit"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+// LocalImpersonator is the key to retrieve the impersonating super admin's user ID from context
+const LocalImpersonator = "impersonator"
+
+// ImpersonationDependencies lets Auth accept read-only tokens issued by
+// GET /v1/super/tenants/:id/impersonate in place of an API key
+type ImpersonationDependencies struct {
+	JWTService  *admin.JWTService
+	AuditLogger audit.Logger
+}
+
+// isJWT reports whether token has the three dot-separated segments of a JWT.
+// API keys never contain dots.
+func isJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+// authenticateImpersonation authenticates an impersonation token as its tenant.
+// Only safe methods are allowed and every request is written to the audit log
+// with the super admin's identity.
+func authenticateImpersonation(c *fiber.Ctx, deps AuthDependencies, token string) error {
+	claims, err := deps.Impersonation.JWTService.ValidateToken(token)
+	if err != nil {
+		deps.Logger.Warn("invalid impersonation token", "error", err)
+		return domain.ErrUnauthorized
+	}
+	if claims.Role != admin.RoleImpersonation || claims.TenantID == nil {
+		deps.Logger.Warn("token is not an impersonation token", "role", claims.Role, "user_id", claims.UserID)
+		return domain.ErrUnauthorized
+	}
+
+	// Impersonation is for debugging: it must never change tenant data
+	if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+		deps.Logger.Warn("write attempted with impersonation token",
+			"user_id", claims.UserID,
+			"tenant_id", *claims.TenantID,
+			"method", c.Method(),
+			"path", c.Path(),
+		)
+		logImpersonatedAccess(c, deps, claims, *claims.TenantID, fiber.StatusForbidden)
+		return domain.ErrForbidden.WithError(errors.New("impersonation tokens are read-only"))
+	}
+
+	tenant, err := deps.TenantRepo.GetByID(c.UserContext(), *claims.TenantID)
+	if err != nil {
+		deps.Logger.Warn("impersonated tenant not found", "tenant_id", *claims.TenantID, "error", err)
+		return domain.ErrUnauthorized
+	}
+
+	c.Locals(LocalTenantID, tenant.ID)
+	c.Locals(LocalTenant, tenant)
+	c.Locals(LocalImpersonator, claims.UserID)
+
+	deps.Logger.Debug("impersonation authenticated",
+		"tenant_id", tenant.ID,
+		"user_id", claims.UserID,
+		"email", claims.Email,
+	)
+
+	err = c.Next()
+
+	status := c.Response().StatusCode()
+	if err != nil {
+		status = fiber.StatusInternalServerError
+		var appErr *domain.AppError
+		var fiberErr *fiber.Error
+		if errors.As(err, &appErr) {
+			status = appErr.StatusCode
+		} else if errors.As(err, &fiberErr) {
+			status = fiberErr.Code
+		}
+	}
+	logImpersonatedAccess(c, deps, claims, tenant.ID, status)
+
+	return err
+}
+
+// logImpersonatedAccess attributes an impersonated request to the super admin who issued the token
+func logImpersonatedAccess(c *fiber.Ctx, deps AuthDependencies, claims *admin.AdminClaims, tenantID uuid.UUID, status int) {
+	event := audit.Event{
+		TenantID:  tenantID,
+		EventType: audit.EventImpersonatedAccess,
+		Success:   status < fiber.StatusBadRequest,
+		Metadata: map[string]string{
+			"super_admin_id":    claims.UserID.String(),
+			"super_admin_email": claims.Email,
+			"method":            c.Method(),
+			"path":              c.Path(),
+			"status":            strconv.Itoa(status),
+		},
+		IPAddress: c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+	}
+	if err := deps.Impersonation.AuditLogger.Log(c.UserContext(), event); err != nil {
+		deps.Logger.Error("failed to audit impersonated access", "error", err, "tenant_id", tenantID)
+	}
+}
+
+// GetImpersonator retrieves the impersonating super admin's user ID, if the request is impersonated
+func GetImpersonator(c *fiber.Ctx) (uuid.UUID, bool) {
+	userID, ok := c.Locals(LocalImpersonator).(uuid.UUID)
+	return userID, ok
+}
@@ -0,0 +1,169 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/admin"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/audit"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+// recordingAuditLogger keeps audit events for assertions
+type recordingAuditLogger struct {
+	mu     sync.Mutex
+	events []audit.Event
+}
+
+func (l *recordingAuditLogger) Log(_ context.Context, event audit.Event) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+	return nil
+}
+
+func newImpersonationApp(t *testing.T, tenant *domain.Tenant) (*fiber.App, *admin.JWTService, *recordingAuditLogger) {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	jwtService := admin.NewJWTService("test-secret", "rekko-test", time.Hour)
+	auditLogger := &recordingAuditLogger{}
+
+	tenantRepo := new(MockTenantRepo)
+	tenantRepo.On("GetByID", mock.Anything, tenant.ID).Return(tenant, nil).Maybe()
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(logger)})
+	app.Use(Auth(AuthDependencies{
+		TenantRepo: tenantRepo,
+		APIKeyRepo: new(MockAPIKeyRepo),
+		Logger:     logger,
+		Impersonation: &ImpersonationDependencies{
+			JWTService:  jwtService,
+			AuditLogger: auditLogger,
+		},
+	}))
+	read := func(c *fiber.Ctx) error {
+		tenantID, err := GetTenantID(c)
+		if err != nil {
+			return err
+		}
+		return c.SendString(tenantID.String())
+	}
+	app.Get("/v1/faces", read)
+	app.Get("/v1/admin/metrics/faces", read)
+	app.Post("/v1/faces", read)
+	app.Delete("/v1/faces/:external_id", read)
+
+	return app, jwtService, auditLogger
+}
+
+func TestAuth_Impersonation(t *testing.T) {
+	tenant := &domain.Tenant{ID: uuid.New(), Slug: "acme", IsActive: true}
+	adminID := uuid.New()
+
+	t.Run("reads as the impersonated tenant", func(t *testing.T) {
+		app, jwtService, auditLogger := newImpersonationApp(t, tenant)
+		token, _, err := jwtService.GenerateImpersonationToken(adminID, "support@rekko.com", tenant.ID)
+		require.NoError(t, err)
+
+		for _, path := range []string{"/v1/faces", "/v1/admin/metrics/faces"} {
+			req := httptest.NewRequest("GET", path, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode, path)
+
+			body, _ := io.ReadAll(resp.Body)
+			assert.Equal(t, tenant.ID.String(), string(body))
+		}
+
+		require.Len(t, auditLogger.events, 2)
+		event := auditLogger.events[0]
+		assert.Equal(t, audit.EventImpersonatedAccess, event.EventType)
+		assert.Equal(t, tenant.ID, event.TenantID)
+		assert.True(t, event.Success)
+		assert.Equal(t, adminID.String(), event.Metadata["super_admin_id"])
+		assert.Equal(t, "support@rekko.com", event.Metadata["super_admin_email"])
+		assert.Equal(t, "GET", event.Metadata["method"])
+		assert.Equal(t, "/v1/faces", event.Metadata["path"])
+		assert.Equal(t, "200", event.Metadata["status"])
+	})
+
+	t.Run("writes are forbidden and audited", func(t *testing.T) {
+		app, jwtService, auditLogger := newImpersonationApp(t, tenant)
+		token, _, err := jwtService.GenerateImpersonationToken(adminID, "support@rekko.com", tenant.ID)
+		require.NoError(t, err)
+
+		for _, method := range []string{"POST", "DELETE"} {
+			path := "/v1/faces"
+			if method == "DELETE" {
+				path = "/v1/faces/user-1"
+			}
+			req := httptest.NewRequest(method, path, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, fiber.StatusForbidden, resp.StatusCode, method)
+		}
+
+		require.Len(t, auditLogger.events, 2)
+		for _, event := range auditLogger.events {
+			assert.False(t, event.Success)
+			assert.Equal(t, adminID.String(), event.Metadata["super_admin_id"])
+			assert.Equal(t, "403", event.Metadata["status"])
+		}
+	})
+
+	t.Run("super admin session token is not accepted", func(t *testing.T) {
+		app, jwtService, auditLogger := newImpersonationApp(t, tenant)
+		token, err := jwtService.GenerateToken(adminID, "support@rekko.com", "super_admin")
+		require.NoError(t, err)
+
+		req := httptest.NewRequest("GET", "/v1/faces", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+		assert.Empty(t, auditLogger.events)
+	})
+
+	t.Run("token signed with another secret is rejected", func(t *testing.T) {
+		app, _, _ := newImpersonationApp(t, tenant)
+		token, _, err := admin.NewJWTService("other-secret", "rekko-test", time.Hour).
+			GenerateImpersonationToken(adminID, "support@rekko.com", tenant.ID)
+		require.NoError(t, err)
+
+		req := httptest.NewRequest("GET", "/v1/faces", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("impersonation disabled rejects the token", func(t *testing.T) {
+		logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+		jwtService := admin.NewJWTService("test-secret", "rekko-test", time.Hour)
+		token, _, err := jwtService.GenerateImpersonationToken(adminID, "support@rekko.com", tenant.ID)
+		require.NoError(t, err)
+
+		app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(logger)})
+		app.Use(Auth(AuthDependencies{TenantRepo: new(MockTenantRepo), APIKeyRepo: new(MockAPIKeyRepo), Logger: logger}))
+		app.Get("/v1/faces", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+		req := httptest.NewRequest("GET", "/v1/faces", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.NotEqual(t, fiber.StatusOK, resp.StatusCode)
+	})
+}
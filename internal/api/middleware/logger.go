@@ -28,7 +28,7 @@ func Logger(logger *slog.Logger) fiber.Handler {
 			logLevel = slog.LevelWarn
 		}
 
-		logger.Log(c.Context(), logLevel, "http request",
+		logger.Log(c.UserContext(), logLevel, "http request",
 			slog.String("method", c.Method()),
 			slog.String("path", c.Path()),
 			slog.Int("status", status),
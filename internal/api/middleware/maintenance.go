@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// Maintenance rejects requests of tenants in maintenance mode with 503 MAINTENANCE and
+// Retry-After. It guards the recognition routes only, so configuration and read routes keep
+// working during a provider migration. Must run after Auth.
+func Maintenance() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		tenant, err := GetTenant(c)
+		if err != nil {
+			return err
+		}
+		if err := tenant.GetSettings().Maintenance.Check(); err != nil {
+			return err
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+func TestMaintenance(t *testing.T) {
+	newApp := func(settings map[string]interface{}) *fiber.App {
+		tenant := &domain.Tenant{ID: uuid.New(), Settings: settings}
+		app := newTestApp(slog.New(slog.NewTextHandler(io.Discard, nil)))
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(LocalTenant, tenant)
+			return c.Next()
+		})
+		recognition := Maintenance()
+		app.Post("/faces/verify", recognition, func(c *fiber.Ctx) error {
+			return c.SendString("verified")
+		})
+		app.Post("/faces/search", recognition, func(c *fiber.Ctx) error {
+			return c.SendString("searched")
+		})
+		app.Get("/faces", func(c *fiber.Ctx) error {
+			return c.SendString("listed")
+		})
+		app.Get("/faces/:external_id/exists", func(c *fiber.Ctx) error {
+			return c.SendString("exists")
+		})
+		return app
+	}
+
+	t.Run("recognition endpoints return 503 while reads succeed", func(t *testing.T) {
+		app := newApp(map[string]interface{}{
+			"maintenance": map[string]interface{}{"enabled": true, "retry_after": "15m"},
+		})
+
+		for _, path := range []string{"/faces/verify", "/faces/search"} {
+			resp, err := app.Test(httptest.NewRequest(http.MethodPost, path, nil))
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode, path)
+			assert.Equal(t, "900", resp.Header.Get("Retry-After"), path)
+
+			var body struct {
+				Error struct {
+					Code string `json:"code"`
+				} `json:"error"`
+			}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, "MAINTENANCE", body.Error.Code, path)
+		}
+
+		for _, path := range []string{"/faces", "/faces/user_001/exists"} {
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode, path)
+		}
+	})
+
+	t.Run("default retry after", func(t *testing.T) {
+		app := newApp(map[string]interface{}{
+			"maintenance": map[string]interface{}{"enabled": true},
+		})
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/faces/verify", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, "300", resp.Header.Get("Retry-After"))
+	})
+
+	t.Run("recognition passes when maintenance is off", func(t *testing.T) {
+		app := newApp(map[string]interface{}{
+			"maintenance": map[string]interface{}{"enabled": false, "retry_after": "15m"},
+		})
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/faces/verify", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get("Retry-After"))
+	})
+
+	t.Run("requires an authenticated tenant", func(t *testing.T) {
+		app := newTestApp(slog.New(slog.NewTextHandler(io.Discard, nil)))
+		app.Post("/faces/verify", Maintenance(), func(c *fiber.Ctx) error {
+			return c.SendString("verified")
+		})
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/faces/verify", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
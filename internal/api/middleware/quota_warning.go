@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+// HeaderQuotaWarning lists the quotas whose usage reached a warning percentage
+const HeaderQuotaWarning = "X-Rekko-Quota-Warning"
+
+// QuotaChecker reports the quotas of a tenant whose usage reached a warning percentage
+type QuotaChecker interface {
+	Check(ctx context.Context, tenantID uuid.UUID, settings domain.TenantSettings) []domain.QuotaWarning
+}
+
+// QuotaWarnings sets X-Rekko-Quota-Warning on responses of tenants approaching their
+// max_faces or max_requests_month quota, e.g. "max_faces;threshold=90;used=4600;limit=5000",
+// with one comma separated entry per quota. It only warns, the request always proceeds.
+// Must run after Auth.
+func QuotaWarnings(checker QuotaChecker) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		tenant, ok := c.Locals(LocalTenant).(*domain.Tenant)
+		if !ok {
+			return c.Next()
+		}
+
+		warnings := checker.Check(c.UserContext(), tenant.ID, tenant.GetSettings())
+		if len(warnings) > 0 {
+			entries := make([]string, 0, len(warnings))
+			for _, w := range warnings {
+				entries = append(entries, w.Quota+
+					";threshold="+intToString(w.Threshold)+
+					";used="+intToString(int(w.Used))+
+					";limit="+intToString(int(w.Limit)))
+			}
+			c.Set(HeaderQuotaWarning, strings.Join(entries, ", "))
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+// stubQuotaChecker returns fixed warnings
+type stubQuotaChecker struct {
+	warnings []domain.QuotaWarning
+}
+
+func (s stubQuotaChecker) Check(ctx context.Context, tenantID uuid.UUID, settings domain.TenantSettings) []domain.QuotaWarning {
+	return s.warnings
+}
+
+func TestQuotaWarnings(t *testing.T) {
+	newApp := func(checker QuotaChecker) *fiber.App {
+		tenant := &domain.Tenant{ID: uuid.New()}
+		app := newTestApp(slog.New(slog.NewTextHandler(io.Discard, nil)))
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(LocalTenant, tenant)
+			return c.Next()
+		})
+		app.Use(QuotaWarnings(checker))
+		app.Post("/faces", func(c *fiber.Ctx) error {
+			return c.Status(http.StatusCreated).SendString("registered")
+		})
+		return app
+	}
+
+	t.Run("warnings are reported without blocking the request", func(t *testing.T) {
+		app := newApp(stubQuotaChecker{warnings: []domain.QuotaWarning{
+			domain.NewQuotaWarning(domain.QuotaMaxFaces, 90, 4600, 5000, ""),
+			domain.NewQuotaWarning(domain.QuotaMaxRequestsMonth, 80, 80000, 100000, "2025-03"),
+		}})
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/faces", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t,
+			"max_faces;threshold=90;used=4600;limit=5000, max_requests_month;threshold=80;used=80000;limit=100000",
+			resp.Header.Get(HeaderQuotaWarning))
+	})
+
+	t.Run("no header below the warning percentages", func(t *testing.T) {
+		app := newApp(stubQuotaChecker{})
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/faces", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get(HeaderQuotaWarning))
+	})
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"context"
+	"strings"
 	"sync"
 	"time"
 
@@ -113,14 +115,8 @@ func (rl *RateLimiter) Handler() fiber.Handler {
 		}
 
 		// Get rate limit for this endpoint (or use default)
-		max := rl.config.Max
-		window := rl.config.Window
 		path := c.Path()
-
-		if endpointLimit, exists := rl.config.PerEndpoint[path]; exists {
-			max = endpointLimit.Requests
-			window = endpointLimit.Window
-		}
+		max, window := rl.limitFor(path)
 
 		// Composite key: tenant + endpoint
 		compositeKey := key + ":" + path
@@ -174,6 +170,84 @@ func (rl *RateLimiter) Handler() fiber.Handler {
 	}
 }
 
+// limitFor returns the request limit and window that apply to path
+func (rl *RateLimiter) limitFor(path string) (int, time.Duration) {
+	if endpointLimit, exists := rl.config.PerEndpoint[path]; exists {
+		return endpointLimit.Requests, endpointLimit.Window
+	}
+	return rl.config.Max, rl.config.Window
+}
+
+// Usage returns the limit, the requests counted in the current window and when the window
+// resets for a tenant key on path. Without an open window count is 0 and resetAt is zero.
+func (rl *RateLimiter) Usage(key, path string) (limit, count int, resetAt time.Time) {
+	limit, _ = rl.limitFor(path)
+
+	rl.mu.RLock()
+	defer rl.mu.RUnlock()
+
+	limiter, exists := rl.limiters[key+":"+path]
+	if !exists || time.Now().After(limiter.windowEnd) {
+		return limit, 0, time.Time{}
+	}
+	return limit, limiter.count, limiter.windowEnd
+}
+
+// Reset clears every endpoint window of a tenant key so its next request is allowed.
+// Windows live in this instance's memory; other API instances keep their own.
+// Returns the number of windows cleared.
+func (rl *RateLimiter) Reset(key string) int {
+	prefix := key + ":"
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	cleared := 0
+	for compositeKey := range rl.limiters {
+		if strings.HasPrefix(compositeKey, prefix) {
+			delete(rl.limiters, compositeKey)
+			cleared++
+		}
+	}
+	return cleared
+}
+
+// SearchWindowReader reads a tenant's search count and window reset from the rate limit store
+type SearchWindowReader interface {
+	SearchWindow(ctx context.Context, tenantID uuid.UUID) (int, time.Time, error)
+}
+
+// SearchRateLimitHeaders replaces the generic rate limit headers on search routes with the
+// tenant's search_rate_limit quota, which is what actually rejects searches with 429.
+// It runs after the handler so the headers include the search just counted.
+func SearchRateLimitHeaders(store SearchWindowReader) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+
+		tenant, ok := c.Locals(LocalTenant).(*domain.Tenant)
+		if !ok {
+			return err
+		}
+		limit := tenant.GetSettings().SearchRateLimit
+		if limit <= 0 {
+			return err
+		}
+
+		count, resetAt, stateErr := store.SearchWindow(c.UserContext(), tenant.ID)
+		if stateErr != nil {
+			// Keep the generic headers rather than failing the request
+			return err
+		}
+
+		c.Set("X-RateLimit-Limit", intToString(limit))
+		c.Set("X-RateLimit-Remaining", intToString(max(limit-count, 0)))
+		if !resetAt.IsZero() {
+			c.Set("X-RateLimit-Reset", resetAt.Format(time.RFC3339))
+		}
+		return err
+	}
+}
+
 // cleanup removes stale entries
 func (rl *RateLimiter) cleanup() {
 	ticker := time.NewTicker(5 * time.Minute)
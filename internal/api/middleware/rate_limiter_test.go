@@ -1,14 +1,21 @@
 package middleware
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"io"
+	"log/slog"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
 )
 
 func TestRateLimiter(t *testing.T) {
@@ -381,6 +388,136 @@ func TestRateLimiter_PerEndpoint(t *testing.T) {
 	})
 }
 
+func TestRateLimiter_Usage(t *testing.T) {
+	tenantID := uuid.New()
+	rl := NewRateLimiter(RateLimiterConfig{
+		Max:    5,
+		Window: time.Minute,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return tenantID.String()
+		},
+	})
+	defer rl.Stop()
+
+	app := fiber.New()
+	app.Use(rl.Handler())
+	app.Post("/v1/faces/verify", func(c *fiber.Ctx) error {
+		return c.SendString("OK")
+	})
+
+	limit, count, resetAt := rl.Usage(tenantID.String(), "/v1/faces/verify")
+	assert.Equal(t, 5, limit)
+	assert.Zero(t, count)
+	assert.True(t, resetAt.IsZero())
+
+	for i := 1; i <= 2; i++ {
+		resp, _ := app.Test(httptest.NewRequest("POST", "/v1/faces/verify", nil))
+		assert.Equal(t, intToString(5-i), resp.Header.Get("X-RateLimit-Remaining"))
+
+		_, count, resetAt = rl.Usage(tenantID.String(), "/v1/faces/verify")
+		assert.Equal(t, i, count)
+		assert.False(t, resetAt.IsZero())
+	}
+
+	// Other tenants and endpoints are unaffected
+	_, count, _ = rl.Usage(uuid.New().String(), "/v1/faces/verify")
+	assert.Zero(t, count)
+	_, count, _ = rl.Usage(tenantID.String(), "/v1/faces")
+	assert.Zero(t, count)
+}
+
+type fakeSearchWindow struct {
+	count   int
+	resetAt time.Time
+	err     error
+}
+
+func (f *fakeSearchWindow) SearchWindow(ctx context.Context, tenantID uuid.UUID) (int, time.Time, error) {
+	return f.count, f.resetAt, f.err
+}
+
+func TestSearchRateLimitHeaders(t *testing.T) {
+	tenant := &domain.Tenant{ID: uuid.New(), Settings: map[string]interface{}{"search_rate_limit": 30.0}}
+	resetAt := time.Now().Add(time.Minute).Truncate(time.Second)
+
+	newApp := func(store SearchWindowReader, handlerErr error) *fiber.App {
+		app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(slog.New(slog.NewTextHandler(io.Discard, nil)))})
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(LocalTenant, tenant)
+			c.Set("X-RateLimit-Limit", "1000")
+			c.Set("X-RateLimit-Remaining", "999")
+			return c.Next()
+		})
+		app.Post("/v1/faces/search", SearchRateLimitHeaders(store), func(c *fiber.Ctx) error {
+			if handlerErr != nil {
+				return handlerErr
+			}
+			return c.SendString("OK")
+		})
+		return app
+	}
+
+	t.Run("reports the tenant search quota", func(t *testing.T) {
+		app := newApp(&fakeSearchWindow{count: 12, resetAt: resetAt}, nil)
+
+		resp, err := app.Test(httptest.NewRequest("POST", "/v1/faces/search", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, "30", resp.Header.Get("X-RateLimit-Limit"))
+		assert.Equal(t, "18", resp.Header.Get("X-RateLimit-Remaining"))
+		assert.Equal(t, resetAt.Format(time.RFC3339), resp.Header.Get("X-RateLimit-Reset"))
+	})
+
+	t.Run("sets headers on rejected searches", func(t *testing.T) {
+		app := newApp(&fakeSearchWindow{count: 31, resetAt: resetAt}, domain.ErrSearchRateLimitExceeded)
+
+		resp, err := app.Test(httptest.NewRequest("POST", "/v1/faces/search", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 429, resp.StatusCode)
+		assert.Equal(t, "0", resp.Header.Get("X-RateLimit-Remaining"))
+	})
+
+	t.Run("reports the usage that rejected the search", func(t *testing.T) {
+		// The store already saw a later search; the rejection's own usage wins
+		rejectedAt := domain.ErrSearchRateLimitExceeded.WithRateLimit(30, 31, resetAt)
+		app := newApp(&fakeSearchWindow{count: 33, resetAt: resetAt.Add(time.Second)}, rejectedAt)
+
+		resp, err := app.Test(httptest.NewRequest("POST", "/v1/faces/search", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 429, resp.StatusCode)
+		assert.Equal(t, "30", resp.Header.Get("X-RateLimit-Limit"))
+		assert.Equal(t, "0", resp.Header.Get("X-RateLimit-Remaining"))
+		assert.Equal(t, resetAt.Format(time.RFC3339), resp.Header.Get("X-RateLimit-Reset"))
+
+		retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+		assert.NoError(t, err)
+		assert.InDelta(t, 60, retryAfter, 2)
+
+		var body struct {
+			Error struct {
+				Code    string `json:"code"`
+				Limit   int    `json:"limit"`
+				Current int    `json:"current"`
+				ResetAt string `json:"reset_at"`
+			} `json:"error"`
+		}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, "SEARCH_RATE_LIMIT_EXCEEDED", body.Error.Code)
+		assert.Equal(t, 30, body.Error.Limit)
+		assert.Equal(t, 31, body.Error.Current)
+		assert.Equal(t, resetAt.Format(time.RFC3339), body.Error.ResetAt)
+	})
+
+	t.Run("keeps generic headers when the store fails", func(t *testing.T) {
+		app := newApp(&fakeSearchWindow{err: errors.New("connection refused")}, nil)
+
+		resp, err := app.Test(httptest.NewRequest("POST", "/v1/faces/search", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, "999", resp.Header.Get("X-RateLimit-Remaining"))
+	})
+}
+
 func TestAdminRateLimits(t *testing.T) {
 	limits := AdminRateLimits()
 
@@ -402,3 +539,46 @@ func TestAdminRateLimits(t *testing.T) {
 	assert.Equal(t, 60, limits["/super/system"].Requests)
 	assert.Equal(t, time.Minute, limits["/super/system"].Window)
 }
+
+func TestRateLimiter_Reset(t *testing.T) {
+	tenantID := uuid.New()
+	otherTenantID := uuid.New()
+	current := tenantID
+
+	rl := NewRateLimiter(RateLimiterConfig{
+		Max:    1,
+		Window: time.Minute,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return current.String()
+		},
+	})
+	defer rl.Stop()
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(slog.New(slog.NewTextHandler(io.Discard, nil)))})
+	app.Use(rl.Handler())
+	app.Get("/a", func(c *fiber.Ctx) error { return c.SendString("OK") })
+	app.Get("/b", func(c *fiber.Ctx) error { return c.SendString("OK") })
+
+	request := func(path string) int {
+		resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+		assert.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	// Exhaust both endpoints for the tenant and one for another tenant
+	for _, path := range []string{"/a", "/b"} {
+		assert.Equal(t, 200, request(path))
+		assert.Equal(t, 429, request(path))
+	}
+	current = otherTenantID
+	assert.Equal(t, 200, request("/a"))
+
+	assert.Equal(t, 2, rl.Reset(tenantID.String()))
+
+	current = tenantID
+	assert.Equal(t, 200, request("/a"), "allowed again after reset")
+	assert.Equal(t, 200, request("/b"))
+
+	current = otherTenantID
+	assert.Equal(t, 429, request("/a"), "other tenants keep their windows")
+}
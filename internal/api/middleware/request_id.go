@@ -0,0 +1,13 @@
+package middleware
+
+import "github.com/gofiber/fiber/v2"
+
+// LocalRequestID is the Fiber locals key set by the requestid middleware
+const LocalRequestID = "requestid"
+
+// GetRequestID retrieves the request ID assigned to the current request
+// Returns an empty string when the requestid middleware is not installed
+func GetRequestID(c *fiber.Ctx) string {
+	requestID, _ := c.Locals(LocalRequestID).(string)
+	return requestID
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+// RequireScope rejects requests whose API key was not granted scope with 403 FORBIDDEN.
+// Impersonation tokens carry no API key and are rejected too. Must run after Auth.
+func RequireScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if _, err := GetTenantID(c); err != nil {
+			return err
+		}
+		apiKey, ok := c.Locals(LocalAPIKey).(*domain.APIKey)
+		if !ok || !apiKey.HasScope(scope) {
+			return domain.ErrForbidden.WithError(fmt.Errorf("API key lacks the %s scope", scope))
+		}
+		return c.Next()
+	}
+}
+
+// RequireAdminScope guards the tenant admin API: reads need admin:read and any other method
+// admin:write. Impersonation tokens pass, they are read-only and audited. Must run after Auth.
+func RequireAdminScope() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if _, err := GetTenantID(c); err != nil {
+			return err
+		}
+		if _, ok := c.Locals(LocalImpersonator).(uuid.UUID); ok {
+			return c.Next()
+		}
+
+		scope := domain.ScopeAdminWrite
+		if c.Method() == fiber.MethodGet || c.Method() == fiber.MethodHead {
+			scope = domain.ScopeAdminRead
+		}
+		apiKey, ok := c.Locals(LocalAPIKey).(*domain.APIKey)
+		if !ok || !apiKey.HasScope(scope) {
+			return domain.ErrForbidden.WithError(fmt.Errorf("API key lacks the %s scope", scope))
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+func TestRequireScope(t *testing.T) {
+	newApp := func(apiKey *domain.APIKey) *fiber.App {
+		app := newTestApp(slog.New(slog.NewTextHandler(io.Discard, nil)))
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(LocalTenantID, uuid.New())
+			if apiKey != nil {
+				c.Locals(LocalAPIKey, apiKey)
+			}
+			return c.Next()
+		})
+		app.Get("/admin/faces/export", RequireScope(domain.ScopeFacesExport), func(c *fiber.Ctx) error {
+			return c.SendString("exported")
+		})
+		return app
+	}
+
+	tests := []struct {
+		name       string
+		apiKey     *domain.APIKey
+		wantStatus int
+	}{
+		{"key with the scope", &domain.APIKey{Scopes: []string{domain.ScopeFacesExport}}, http.StatusOK},
+		{"key without the scope", &domain.APIKey{}, http.StatusForbidden},
+		{"impersonation token", nil, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := newApp(tt.apiKey).Test(httptest.NewRequest(http.MethodGet, "/admin/faces/export", nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+
+	t.Run("requires an authenticated tenant", func(t *testing.T) {
+		app := newTestApp(slog.New(slog.NewTextHandler(io.Discard, nil)))
+		app.Get("/admin/faces/export", RequireScope(domain.ScopeFacesExport), func(c *fiber.Ctx) error {
+			return c.SendString("exported")
+		})
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/admin/faces/export", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
+
+func TestRequireAdminScope(t *testing.T) {
+	newApp := func(apiKey *domain.APIKey, impersonated bool) *fiber.App {
+		app := newTestApp(slog.New(slog.NewTextHandler(io.Discard, nil)))
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals(LocalTenantID, uuid.New())
+			if apiKey != nil {
+				c.Locals(LocalAPIKey, apiKey)
+			}
+			if impersonated {
+				c.Locals(LocalImpersonator, uuid.New())
+			}
+			return c.Next()
+		})
+		admin := app.Group("/admin", RequireAdminScope())
+		admin.Get("/webhooks", func(c *fiber.Ctx) error { return c.SendString("listed") })
+		admin.Post("/webhooks", func(c *fiber.Ctx) error { return c.SendString("created") })
+		return app
+	}
+
+	readOnly := &domain.APIKey{Scopes: []string{domain.ScopeAdminRead}}
+	tests := []struct {
+		name         string
+		apiKey       *domain.APIKey
+		impersonated bool
+		method       string
+		wantStatus   int
+	}{
+		{"read with admin:read", readOnly, false, http.MethodGet, http.StatusOK},
+		{"write with admin:read only", readOnly, false, http.MethodPost, http.StatusForbidden},
+		{"write with admin:write", &domain.APIKey{Scopes: domain.DefaultSecretKeyScopes}, false, http.MethodPost, http.StatusOK},
+		{"recognition-only key", &domain.APIKey{Scopes: []string{}}, false, http.MethodGet, http.StatusForbidden},
+		{"key with another scope", &domain.APIKey{Scopes: []string{domain.ScopeFacesExport}}, false, http.MethodGet, http.StatusForbidden},
+		{"impersonation token", nil, true, http.MethodGet, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := newApp(tt.apiKey, tt.impersonated).Test(httptest.NewRequest(tt.method, "/admin/webhooks", nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+
+	t.Run("requires an authenticated tenant", func(t *testing.T) {
+		app := newTestApp(slog.New(slog.NewTextHandler(io.Discard, nil)))
+		app.Get("/admin/webhooks", RequireAdminScope(), func(c *fiber.Ctx) error {
+			return c.SendString("listed")
+		})
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/admin/webhooks", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"time"
 
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/requestid"
@@ -17,9 +18,14 @@ import (
 	adminHandler "github.com/saturnino-fabrica-de-software/rekko/internal/api/handler/admin"
 	superHandler "github.com/saturnino-fabrica-de-software/rekko/internal/api/handler/super"
 	"github.com/saturnino-fabrica-de-software/rekko/internal/api/middleware"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/audit"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/auditexport"
 	"github.com/saturnino-fabrica-de-software/rekko/internal/cache"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/config"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
 	"github.com/saturnino-fabrica-de-software/rekko/internal/metrics"
 	"github.com/saturnino-fabrica-de-software/rekko/internal/provider"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/provider/mock"
 	"github.com/saturnino-fabrica-de-software/rekko/internal/ratelimit"
 	"github.com/saturnino-fabrica-de-software/rekko/internal/repository"
 	"github.com/saturnino-fabrica-de-software/rekko/internal/service"
@@ -34,8 +40,14 @@ type Dependencies struct {
 	FaceRepo         *repository.FaceRepository
 	VerificationRepo *repository.VerificationRepository
 	FaceProvider     provider.FaceProvider
+	ShadowProvider   provider.FaceProvider     // optional, evaluated alongside FaceProvider
+	Collections      service.CollectionEnsurer // optional, creates provider storage for auto-provisioned tenants
+	CollectionPurger service.CollectionDeleter // optional, deletes provider storage of deleted tenants
+	FaceCounter      service.CollectionCounter // optional, counts faces in provider storage for recounts
 	LastUsedWorker   *middleware.LastUsedWorker
 	DB               *pgxpool.Pool
+	ReadDB           *pgxpool.Pool // optional read replica for metrics queries
+	Config           *config.Config
 }
 
 type Router struct {
@@ -43,11 +55,19 @@ type Router struct {
 	logger            *slog.Logger
 	deps              *Dependencies
 	rateLimiter       *middleware.RateLimiter
+	searchRateLimiter *ratelimit.RateLimiter
+	usageTracker      *usage.BufferedTracker
 	wsHub             *ws.Hub
 	webhookWorker     *webhook.Worker
 	cancelWorker      context.CancelFunc
 	cancelHub         context.CancelFunc
 	cancelUsageWorker context.CancelFunc
+	cancelUsageFlush  context.CancelFunc
+	cancelAuditExport context.CancelFunc
+	cancelFaceStats   context.CancelFunc
+	cancelFaceExpiry  context.CancelFunc
+	cancelErrorPrune  context.CancelFunc
+	deprecations      map[string]middleware.DeprecationNotice
 }
 
 func NewRouter(logger *slog.Logger, deps *Dependencies) *Router {
@@ -71,46 +91,97 @@ func (r *Router) Setup() {
 	r.app.Use(cors.New(cors.Config{
 		AllowOrigins: "*",
 		AllowMethods: "GET,POST,PUT,DELETE,OPTIONS",
-		AllowHeaders: "Origin,Content-Type,Accept,Authorization,X-Tenant-ID",
+		AllowHeaders: "Origin,Content-Type,Accept,Authorization,X-Tenant-ID," + middleware.HeaderTimeoutMs,
 	}))
 
-	// Swagger documentation (no auth required)
-	sw := docs.NewSwagger()
-	swagger.SwaggerHandler(r.app, sw.MustToJson())
+	// Swagger documentation (no auth required); the raw spec is also served at
+	// /openapi.json for SDK generators
+	spec := docs.NewSwagger().MustToJson()
+	swagger.SwaggerHandler(r.app, spec)
+	r.app.Get("/openapi.json", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+		return c.Send(spec)
+	})
 
 	// Health check endpoints (no auth required)
 	healthHandler := handler.NewHealthHandler()
 	r.app.Get("/health", healthHandler.Health)
 	r.app.Get("/ready", healthHandler.Ready)
 
+	// Error catalog for SDKs (no auth required)
+	r.app.Get("/v1/errors", handler.NewErrorsHandler().List)
+
 	// API v1 group
 	v1 := r.app.Group("/v1")
 
 	// Only configure routes if dependencies were provided
 	if r.deps != nil {
+		// Honor the client's time budget so provider and database calls stop when it elapses
+		v1.Use(middleware.Deadline(r.deps.Config.MaxRequestTimeout))
+		r.deprecations = routeDeprecations(r.deps.Config)
+
 		// Initialize Webhook Service (needed before widget routes)
 		webhookService := webhook.NewService(r.deps.DB, r.logger)
 
 		// Usage repository (needed for widget and face handlers)
 		usageRepo := usage.NewRepository(r.deps.DB)
 
+		// Buffered usage tracker, flushed periodically and on shutdown
+		r.usageTracker = usage.NewBufferedTracker(usageRepo, r.logger, r.deps.Config.UsageFlushInterval)
+		usageFlushCtx, usageFlushCancel := context.WithCancel(context.Background())
+		r.cancelUsageFlush = usageFlushCancel
+		go r.usageTracker.Run(usageFlushCtx)
+		webhookService.WithUsageTracker(r.usageTracker)
+
 		// Search audit repository
 		searchAuditRepo := repository.NewSearchAuditRepository(r.deps.DB)
 
 		// Rate limiter for search endpoint
-		searchRateLimiter := ratelimit.NewRateLimiter(r.deps.DB, time.Minute)
+		r.searchRateLimiter = ratelimit.NewRateLimiter(r.deps.DB, time.Minute).
+			WithFailPolicy(ratelimit.FailPolicy(r.deps.Config.RateLimitFailPolicy), r.logger)
+
+		// Failed provider calls are audited with their error code, see GET /super/metrics/provider-errors
+		auditLogger := audit.NewSlogLogger(r.logger)
+		providerAudit := audit.NewMultiLogger(auditLogger, repository.NewProviderErrorRepository(r.deps.DB))
 
 		// Face service (needed for widget)
 		faceService := service.NewFaceService(
 			r.deps.FaceRepo,
 			r.deps.VerificationRepo,
 			searchAuditRepo,
-			r.deps.FaceProvider,
-			searchRateLimiter,
-		)
+			provider.WithErrorAudit(r.deps.FaceProvider, r.deps.Config.FaceProvider, providerAudit),
+			r.searchRateLimiter,
+		).WithEmbeddingModel(r.deps.Config.EmbeddingModel).
+			WithEmbeddingNormalization(r.deps.Config.NormalizeEmbeddings).
+			WithMaxEmbeddingMagnitude(r.deps.Config.EmbeddingMaxMagnitude).
+			WithMaxMetadataBytes(r.deps.Config.MaxMetadataBytes).
+			WithMaxImageDimension(r.deps.Config.ImageMaxDimension).
+			WithBlankImageThreshold(r.deps.Config.ImageBlankThreshold).
+			WithDataRegion(r.deps.Config.EffectiveDataRegion()).
+			WithMaxVerificationAge(r.deps.Config.MaxVerificationAge).
+			WithParallelVerify(r.deps.Config.ParallelVerify).
+			WithFaceCountCache(r.deps.Config.FaceCountCacheTTL).
+			WithProviderTimeouts(service.ProviderTimeouts{
+				Default:  r.deps.Config.ProviderTimeout,
+				Register: r.deps.Config.ProviderTimeoutRegister,
+				Verify:   r.deps.Config.ProviderTimeoutVerify,
+				Search:   r.deps.Config.ProviderTimeoutSearch,
+				Detect:   r.deps.Config.ProviderTimeoutDetect,
+			}).
+			WithTestProvider(mock.New())
+		if r.deps.ShadowProvider != nil {
+			faceService.WithShadowProvider(
+				provider.WithErrorAudit(r.deps.ShadowProvider, r.deps.Config.ShadowFaceProvider, providerAudit),
+				repository.NewShadowComparisonRepository(r.deps.DB),
+			)
+		}
+		if r.deps.FaceCounter != nil {
+			faceService.WithCollectionCounter(r.deps.FaceCounter)
+		}
+		r.setupFaceStatsRefresh(faceService)
 
 		// Widget routes (no API Key auth, uses public_key)
-		r.setupWidgetRoutes(faceService, usageRepo, webhookService)
+		r.setupWidgetRoutes(faceService, r.usageTracker, webhookService)
 		// Initialize WebSocket Hub
 		r.wsHub = ws.NewHub()
 		hubCtx, hubCancel := context.WithCancel(context.Background())
@@ -118,20 +189,43 @@ func (r *Router) Setup() {
 		go r.wsHub.Run(hubCtx)
 
 		// Initialize Webhook Worker
-		r.webhookWorker = webhook.NewWorker(r.deps.DB, webhookService, r.logger)
+		r.webhookWorker = webhook.NewWorker(r.deps.DB, webhookService, r.logger).
+			WithTenantConcurrency(r.deps.Config.WebhookTenantConcurrency)
 		ctx, cancel := context.WithCancel(context.Background())
 		r.cancelWorker = cancel
 		go r.webhookWorker.Run(ctx)
 
+		// Delete faces past their TTL
+		r.setupFaceExpiry(faceService, webhookService)
+
+		// Delete provider errors past their retention
+		r.setupProviderErrorRetention()
+
 		// Authenticated routes group
 		authedV1 := v1.Group("")
 
+		// JWT service for super admin authentication and tenant impersonation
+		jwtService := admin.NewJWTService(
+			r.deps.Config.AdminJWTSecret,
+			"rekko-api",
+			24*time.Hour,
+		).WithLeeway(r.deps.Config.ClockSkewLeeway)
+
 		// Auth middleware
 		authDeps := middleware.AuthDependencies{
 			TenantRepo:     r.deps.TenantRepo,
 			APIKeyRepo:     r.deps.APIKeyRepo,
 			Logger:         r.logger,
 			LastUsedWorker: r.deps.LastUsedWorker,
+			// Super admins debug tenants with read-only impersonation tokens
+			Impersonation: &middleware.ImpersonationDependencies{
+				JWTService:  jwtService,
+				AuditLogger: auditLogger,
+			},
+		}
+		// Pre-issued self-serve keys create their tenant on first use (opt-in)
+		if r.deps.Config.AutoProvisionTenants {
+			authDeps.Provisioner = service.NewTenantProvisioner(repository.NewProvisioningRepository(r.deps.DB), r.deps.Collections, r.logger)
 		}
 		authedV1.Use(middleware.Auth(authDeps))
 
@@ -139,16 +233,28 @@ func (r *Router) Setup() {
 		r.rateLimiter = middleware.NewRateLimiter(middleware.DefaultRateLimiterConfig())
 		authedV1.Use(r.rateLimiter.Handler())
 
+		// Quota warnings: header and quota.threshold_reached webhook before max_faces/max_requests_month is reached
+		quotaWarner := usage.NewQuotaWarner(usageRepo, webhookService, r.logger, usage.DefaultQuotaUsageTTL)
+		authedV1.Use(middleware.QuotaWarnings(quotaWarner))
+
 		// Face handler with usage tracking
-		faceHandler := handler.NewFaceHandler(faceService, usageRepo, webhookService, r.logger)
+		faceHandler := handler.NewFaceHandler(faceService, r.usageTracker, webhookService, r.logger)
 
-		// Face routes (authenticated)
+		// Face routes (authenticated); recognition routes are paused for tenants in maintenance
+		recognition := middleware.Maintenance()
 		authedV1.Get("/faces", faceHandler.List)
-		authedV1.Post("/faces", faceHandler.Register)
-		authedV1.Post("/faces/verify", faceHandler.Verify)
-		authedV1.Post("/faces/search", faceHandler.Search)
-		authedV1.Post("/faces/liveness", faceHandler.CheckLiveness)
+		authedV1.Post("/faces", recognition, faceHandler.Register)
+		authedV1.Post("/faces/verify", recognition, faceHandler.Verify)
+		authedV1.Post("/faces/verify-document", recognition, faceHandler.VerifyDocument)
+		authedV1.Post("/faces/exists", faceHandler.Exists)
+		authedV1.Post("/faces/metadata/bulk", faceHandler.BulkUpdateMetadata)
+		searchHeaders := middleware.SearchRateLimitHeaders(r.searchRateLimiter)
+		authedV1.Post("/faces/search", recognition, searchHeaders, faceHandler.Search)
+		authedV1.Post("/faces/search-by-embedding", recognition, searchHeaders, faceHandler.SearchByEmbedding)
+		authedV1.Post("/faces/liveness", recognition, faceHandler.CheckLiveness)
+		authedV1.Post("/faces/count", recognition, faceHandler.Count)
 		authedV1.Get("/faces/:external_id", faceHandler.GetByExternalID)
+		authedV1.Get("/faces/:external_id/recently-verified", faceHandler.RecentlyVerified)
 		authedV1.Delete("/faces/:external_id", faceHandler.Delete)
 
 		// Usage service
@@ -168,29 +274,42 @@ func (r *Router) Setup() {
 		r.cancelUsageWorker = usageWorkerCancel
 		go usageWorker.Run(usageWorkerCtx)
 
+		// Audit export to object storage (opt-in)
+		r.setupAuditExport()
+
 		// WebSocket endpoint (authenticated)
 		authedV1.Get("/ws", ws.UpgradeMiddleware(), ws.Handler(r.wsHub))
 
-		// Admin routes (authenticated)
-		adminGroup := authedV1.Group("/admin")
-		r.setupAdminRoutes(adminGroup, webhookService)
+		// Admin routes (authenticated, API keys need the admin scopes)
+		adminGroup := authedV1.Group("/admin", middleware.RequireAdminScope())
+		r.setupAdminRoutes(adminGroup, faceService, webhookService)
 
 		// Super Admin routes (JWT auth, different from API Key auth)
-		r.setupSuperAdminRoutes(v1)
+		r.setupSuperAdminRoutes(v1, jwtService, auditLogger)
 	}
 }
 
-func (r *Router) setupAdminRoutes(adminGroup fiber.Router, webhookService *webhook.Service) {
+func (r *Router) setupAdminRoutes(adminGroup fiber.Router, faceService *service.FaceService, webhookService *webhook.Service) {
 	// Admin service dependencies
 	metricsRepo := metrics.NewRepository(r.deps.DB)
-	adminService := admin.NewService(metricsRepo, r.deps.DB, r.logger)
+	adminService := r.newAdminService(metricsRepo)
 
 	// Admin handlers
 	usageHandler := adminHandler.NewMetricsUsageHandler(adminService, r.logger)
 	performanceHandler := adminHandler.NewMetricsPerformanceHandler(adminService, r.logger)
 	qualityHandler := adminHandler.NewMetricsQualityHandler(adminService, r.logger)
+	streamHandler := adminHandler.NewMetricsStreamHandler(adminService, r.logger)
 	webhooksHandler := adminHandler.NewWebhooksHandler(webhookService, r.logger)
-	apiKeysHandler := adminHandler.NewAPIKeysHandler(r.deps.DB, r.logger)
+	apiKeysHandler := adminHandler.NewAPIKeysHandler(r.deps.APIKeyRepo, r.deps.TenantRepo, r.logger)
+	facesHandler := adminHandler.NewFacesHandler(faceService, r.logger)
+	faceRecountHandler := adminHandler.NewFaceRecountHandler(faceService, r.logger)
+	rateLimitsHandler := adminHandler.NewRateLimitsHandler(r.searchRateLimiter, r.rateLimiter, r.logger)
+	searchesHandler := adminHandler.NewSearchesHandler(repository.NewSearchAuditRepository(r.deps.DB), r.logger)
+	thresholdsHandler := adminHandler.NewThresholdsHandler(r.logger)
+	calibrationHandler := adminHandler.NewCalibrationHandler(faceService, r.logger)
+	verificationsExportHandler := adminHandler.NewVerificationsExportHandler(repository.NewVerificationRepository(r.readPool()), r.logger)
+	facesExportHandler := adminHandler.NewFacesExportHandler(repository.NewFaceRepository(r.readPool()), r.logger)
+	verificationsHandler := adminHandler.NewVerificationsHandler(repository.NewVerificationRepository(r.deps.DB), r.logger)
 
 	// Metrics group
 	metricsGroup := adminGroup.Group("/metrics")
@@ -199,6 +318,7 @@ func (r *Router) setupAdminRoutes(adminGroup fiber.Router, webhookService *webho
 	metricsGroup.Get("/faces", usageHandler.GetFacesMetrics)
 	metricsGroup.Get("/operations", usageHandler.GetOperationsMetrics)
 	metricsGroup.Get("/requests", usageHandler.GetRequestsMetrics)
+	metricsGroup.Get("/top-identities", usageHandler.GetTopIdentities)
 
 	// Performance metrics
 	metricsGroup.Get("/latency", performanceHandler.GetLatencyMetrics)
@@ -209,27 +329,82 @@ func (r *Router) setupAdminRoutes(adminGroup fiber.Router, webhookService *webho
 	metricsGroup.Get("/quality", qualityHandler.GetQualityMetrics)
 	metricsGroup.Get("/confidence", qualityHandler.GetConfidenceMetrics)
 	metricsGroup.Get("/matches", qualityHandler.GetMatchMetrics)
+	metricsGroup.Get("/by-gate", qualityHandler.GetGateMetrics)
+	metricsGroup.Get("/liveness", qualityHandler.GetLivenessMetrics)
+
+	// Live headline metrics as server-sent events
+	metricsGroup.Get("/stream", streamHandler.Stream)
+
+	// Identity comparison for fraud review
+	adminGroup.Get("/faces/compare", r.deprecated(routeAdminFacesCompare, middleware.Maintenance(), facesHandler.Compare)...)
+
+	// Recompute the face count and refresh its cache
+	adminGroup.Post("/faces/recount", faceRecountHandler.Recount)
+
+	// NDJSON export of every registered face, for backups and migrations
+	adminGroup.Get("/faces/export", middleware.RequireScope(domain.ScopeFacesExport), facesExportHandler.Export)
+
+	// Registered face with its provider face ID
+	adminGroup.Get("/faces/:external_id", facesHandler.Get)
+
+	// Per-user verification trend
+	adminGroup.Get("/faces/:external_id/trend", usageHandler.GetFaceTrend)
+
+	// Projected end-of-month usage against the monthly quota
+	adminGroup.Get("/usage/forecast", usageHandler.GetUsageForecast)
+
+	// Current rate limit usage for the tenant
+	adminGroup.Get("/rate-limits", rateLimitsHandler.Get)
+
+	// Effective match thresholds and where each was resolved from
+	adminGroup.Get("/config/thresholds", thresholdsHandler.Get)
+
+	// FAR/FRR of a threshold sweep over a labeled set of image pairs
+	adminGroup.Post("/calibration/evaluate", middleware.Maintenance(), calibrationHandler.Evaluate)
+
+	// Search audit trail
+	adminGroup.Get("/searches", searchesHandler.List)
+
+	// Raw verification logs for auditors
+	adminGroup.Get("/verifications/export", verificationsExportHandler.Export)
+	adminGroup.Get("/verifications/:id", verificationsHandler.Get)
 
 	// Webhooks routes
 	adminGroup.Get("/webhooks", webhooksHandler.List)
+	adminGroup.Get("/webhooks/schema/:event_type", webhooksHandler.Schema)
 	adminGroup.Post("/webhooks", webhooksHandler.Create)
+	adminGroup.Post("/webhooks/bulk", webhooksHandler.BulkCreate)
+	adminGroup.Post("/webhooks/validate-all", webhooksHandler.ValidateAll)
+	adminGroup.Post("/webhooks/:id/rotate-secret", webhooksHandler.RotateSecret)
 	adminGroup.Delete("/webhooks/:id", webhooksHandler.Delete)
 
 	// API Keys routes
 	adminGroup.Get("/api-keys", apiKeysHandler.List)
+	adminGroup.Post("/api-keys", apiKeysHandler.Create)
+	adminGroup.Delete("/api-keys/:id", apiKeysHandler.Revoke)
 }
 
-func (r *Router) setupSuperAdminRoutes(v1Group fiber.Router) {
-	// Admin service dependencies
-	metricsRepo := metrics.NewRepository(r.deps.DB)
+// readPool returns the read replica when configured, the primary otherwise
+func (r *Router) readPool() *pgxpool.Pool {
+	if r.deps.ReadDB != nil {
+		return r.deps.ReadDB
+	}
+	return r.deps.DB
+}
+
+// newAdminService creates the admin service, routing metrics reads to the replica when configured
+func (r *Router) newAdminService(metricsRepo *metrics.Repository) *admin.Service {
 	adminService := admin.NewService(metricsRepo, r.deps.DB, r.logger)
+	if r.deps.ReadDB != nil {
+		adminService.WithReadReplica(r.deps.ReadDB)
+	}
+	return adminService
+}
 
-	// JWT service for super admin authentication
-	jwtService := admin.NewJWTService(
-		"your-secret-key", // TODO: move to config
-		"rekko-api",
-		24*time.Hour,
-	)
+func (r *Router) setupSuperAdminRoutes(v1Group fiber.Router, jwtService *admin.JWTService, auditLogger audit.Logger) {
+	// Admin service dependencies
+	metricsRepo := metrics.NewRepository(r.deps.DB)
+	adminService := r.newAdminService(metricsRepo)
 
 	// Super admin group with JWT authentication
 	superGroup := v1Group.Group("/super")
@@ -245,21 +420,114 @@ func (r *Router) setupSuperAdminRoutes(v1Group fiber.Router) {
 	superTenantsHandler := superHandler.NewTenantsHandler(adminService, r.logger)
 	superSystemHandler := superHandler.NewSystemHandler(adminService, r.logger)
 	superProvidersHandler := superHandler.NewProvidersHandler(adminService, r.logger)
+	superTenantConfigHandler := superHandler.NewTenantConfigHandler(admin.NewTenantConfigService(r.deps.DB, r.logger), r.logger)
+	superImpersonationHandler := superHandler.NewImpersonationHandler(r.deps.TenantRepo, jwtService, auditLogger, r.logger)
+	superRateLimitsHandler := superHandler.NewRateLimitsHandler(r.searchRateLimiter, r.rateLimiter, r.logger)
+	superTenantDeletionHandler := superHandler.NewTenantDeletionHandler(
+		r.deps.TenantRepo,
+		service.NewTenantDeleter(r.deps.TenantRepo, r.deps.CollectionPurger, r.logger),
+		auditLogger,
+		r.logger,
+	)
+	superMaintenanceHandler := superHandler.NewMaintenanceHandler(r.deps.TenantRepo, auditLogger, r.logger)
+	superFeatureUsageHandler := superHandler.NewFeatureUsageHandler(usage.NewRepository(r.readPool()), r.logger)
+	superProviderErrorsHandler := superHandler.NewProviderErrorsHandler(repository.NewProviderErrorRepository(r.readPool()), r.logger)
 
 	// Tenants routes
 	superGroup.Get("/tenants", superTenantsHandler.ListTenants)
 	superGroup.Get("/tenants/:id/metrics", superTenantsHandler.GetTenantMetrics)
 	superGroup.Post("/tenants/:id/quota", superTenantsHandler.UpdateTenantQuota)
+	superGroup.Get("/tenants/:id/config/export", superTenantConfigHandler.ExportConfig)
+	superGroup.Post("/tenants/:id/config/import", superTenantConfigHandler.ImportConfig)
+	superGroup.Get("/tenants/:id/impersonate", superImpersonationHandler.Impersonate)
+	superGroup.Post("/tenants/:id/rate-limits/reset", superRateLimitsHandler.ResetTenantRateLimits)
+	superGroup.Delete("/tenants/:id", superTenantDeletionHandler.DeleteTenant)
+	superGroup.Post("/tenants/:id/maintenance", superMaintenanceHandler.SetMaintenance)
+	superGroup.Get("/tenants/:id/feature-usage", superFeatureUsageHandler.GetFeatureUsage)
 
 	// System routes
 	superGroup.Get("/system/health", superSystemHandler.GetSystemHealth)
 	superGroup.Get("/system/metrics", superSystemHandler.GetSystemMetrics)
+	superGroup.Get("/metrics/provider-errors", superProviderErrorsHandler.GetProviderErrors)
 
 	// Providers routes
 	superGroup.Get("/providers", superProvidersHandler.GetProvidersStatus)
 }
 
-func (r *Router) setupWidgetRoutes(faceService *service.FaceService, usageRepo *usage.Repository, webhookService *webhook.Service) {
+func (r *Router) setupAuditExport() {
+	cfg := r.deps.Config
+	if !cfg.AuditExportEnabled {
+		return
+	}
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(context.Background(), awsconfig.WithRegion(cfg.AWSRegion))
+	if err != nil {
+		r.logger.Error("failed to load AWS config, audit export disabled", "error", err)
+		return
+	}
+
+	uploader := auditexport.NewS3Uploader(awsCfg.Credentials, cfg.AWSRegion, cfg.AuditExportEndpoint, cfg.AuditExportBucket)
+	exporter := auditexport.NewExporter(
+		auditexport.NewRepository(r.deps.DB),
+		uploader,
+		auditexport.Config{
+			Bucket:   cfg.AuditExportBucket,
+			Prefix:   cfg.AuditExportPrefix,
+			Interval: cfg.AuditExportInterval,
+			Lag:      cfg.AuditExportLag,
+		},
+		r.logger,
+	)
+
+	exportCtx, exportCancel := context.WithCancel(context.Background())
+	r.cancelAuditExport = exportCancel
+	go exporter.Run(exportCtx)
+}
+
+// setupFaceStatsRefresh refreshes faces planner statistics after bulk registration (opt-in)
+func (r *Router) setupFaceStatsRefresh(faceService *service.FaceService) {
+	cfg := r.deps.Config
+	if cfg.FaceStatsRefreshThreshold <= 0 {
+		return
+	}
+
+	refresher := repository.NewFaceStatsRefresher(r.deps.DB, cfg.FaceStatsRefreshThreshold, cfg.FaceStatsRefreshDebounce, r.logger)
+	faceService.WithInsertRecorder(refresher)
+
+	statsCtx, statsCancel := context.WithCancel(context.Background())
+	r.cancelFaceStats = statsCancel
+	go refresher.Run(statsCtx)
+}
+
+// setupFaceExpiry deletes faces registered with a TTL once they expire (FACE_EXPIRY_INTERVAL=0 disables it)
+func (r *Router) setupFaceExpiry(faceService *service.FaceService, webhookService *webhook.Service) {
+	interval := r.deps.Config.FaceExpiryInterval
+	if interval <= 0 {
+		return
+	}
+
+	expirer := service.NewFaceExpirer(r.deps.FaceRepo, faceService, webhookService, r.logger, interval)
+
+	expiryCtx, expiryCancel := context.WithCancel(context.Background())
+	r.cancelFaceExpiry = expiryCancel
+	go expirer.Run(expiryCtx)
+}
+
+// setupProviderErrorRetention deletes provider errors older than PROVIDER_ERROR_RETENTION (0 keeps them)
+func (r *Router) setupProviderErrorRetention() {
+	retention := r.deps.Config.ProviderErrorRetention
+	if retention <= 0 {
+		return
+	}
+
+	pruner := service.NewProviderErrorPruner(repository.NewProviderErrorRepository(r.deps.DB), r.logger, retention)
+
+	pruneCtx, pruneCancel := context.WithCancel(context.Background())
+	r.cancelErrorPrune = pruneCancel
+	go pruner.Run(pruneCtx)
+}
+
+func (r *Router) setupWidgetRoutes(faceService *service.FaceService, usageTracker handler.UsageTracker, webhookService *webhook.Service) {
 	// Widget session repository
 	widgetSessionRepo := repository.NewWidgetSessionRepository(r.deps.DB)
 
@@ -268,10 +536,12 @@ func (r *Router) setupWidgetRoutes(faceService *service.FaceService, usageRepo *
 		widgetSessionRepo,
 		r.deps.TenantRepo,
 		faceService,
-	)
+	).WithSessionRateLimit(r.searchRateLimiter, r.deps.Config.WidgetSessionRateLimit).
+		WithCheckRateLimit(r.searchRateLimiter, r.deps.Config.WidgetCheckRateLimit).
+		WithClockSkewLeeway(r.deps.Config.ClockSkewLeeway)
 
 	// Widget handler
-	widgetHandler := handler.NewWidgetHandler(widgetService, usageRepo, webhookService, r.logger)
+	widgetHandler := handler.NewWidgetHandler(widgetService, usageTracker, webhookService, r.logger)
 
 	// Widget routes (no API Key auth, uses public_key + session)
 	// Created directly on app to avoid v1 auth middleware
@@ -291,7 +561,9 @@ func (r *Router) Listen(addr string) error {
 	return r.app.Listen(addr)
 }
 
-func (r *Router) Shutdown() error {
+// Shutdown stops background workers, drains in-flight requests and flushes
+// buffered usage and rate-limit counters, all bounded by ctx
+func (r *Router) Shutdown(ctx context.Context) error {
 	// Stop WebSocket hub
 	if r.cancelHub != nil {
 		r.cancelHub()
@@ -307,10 +579,55 @@ func (r *Router) Shutdown() error {
 		r.cancelUsageWorker()
 	}
 
+	// Stop audit export worker
+	if r.cancelAuditExport != nil {
+		r.cancelAuditExport()
+	}
+
+	// Stop face statistics refresher
+	if r.cancelFaceStats != nil {
+		r.cancelFaceStats()
+	}
+
+	// Stop face expiry sweep
+	if r.cancelFaceExpiry != nil {
+		r.cancelFaceExpiry()
+	}
+
+	// Stop provider error retention
+	if r.cancelErrorPrune != nil {
+		r.cancelErrorPrune()
+	}
+
+	// Stop periodic usage flush (the final flush runs below)
+	if r.cancelUsageFlush != nil {
+		r.cancelUsageFlush()
+	}
+
 	// Stop rate limiter cleanup goroutine
 	if r.rateLimiter != nil {
 		r.rateLimiter.Stop()
 	}
 
-	return r.app.Shutdown()
+	err := r.app.ShutdownWithContext(ctx)
+
+	// Persist counters only after in-flight requests finished recording them
+	r.flush(ctx)
+
+	return err
+}
+
+// flush persists buffered usage and rate-limit counters (best-effort)
+func (r *Router) flush(ctx context.Context) {
+	if r.usageTracker != nil {
+		if err := r.usageTracker.Flush(ctx); err != nil {
+			r.logger.Error("failed to flush usage on shutdown", "error", err, "pending", r.usageTracker.Pending())
+		}
+	}
+
+	if r.searchRateLimiter != nil {
+		if err := r.searchRateLimiter.Flush(ctx); err != nil {
+			r.logger.Error("failed to flush rate limit counters on shutdown", "error", err)
+		}
+	}
 }
@@ -3,6 +3,7 @@ package audit
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"time"
 
@@ -19,22 +20,33 @@ const (
 	EventFaceSearched   EventType = "FACE_SEARCHED"
 	EventFaceDeleted    EventType = "FACE_DELETED"
 	EventFaceCompared   EventType = "FACE_COMPARED"
+
+	// EventProviderError is a failed provider call, with the provider's raw code in ErrorCode
+	EventProviderError EventType = "PROVIDER_ERROR"
+
+	EventImpersonationStarted EventType = "IMPERSONATION_STARTED"
+	EventImpersonatedAccess   EventType = "IMPERSONATED_ACCESS"
+
+	EventTenantDeleted     EventType = "TENANT_DELETED"
+	EventTenantMaintenance EventType = "TENANT_MAINTENANCE"
 )
 
 // Event represents an audit event for LGPD compliance
 type Event struct {
-	ID         uuid.UUID         `json:"id"`
-	Timestamp  time.Time         `json:"timestamp"`
-	TenantID   uuid.UUID         `json:"tenant_id"`
-	EventType  EventType         `json:"event_type"`
-	ExternalID string            `json:"external_id,omitempty"`
-	FaceID     string            `json:"face_id,omitempty"`
-	Provider   string            `json:"provider"`
-	Success    bool              `json:"success"`
-	Error      string            `json:"error,omitempty"`
-	Metadata   map[string]string `json:"metadata,omitempty"`
-	IPAddress  string            `json:"ip_address,omitempty"`
-	UserAgent  string            `json:"user_agent,omitempty"`
+	ID         uuid.UUID `json:"id"`
+	Timestamp  time.Time `json:"timestamp"`
+	TenantID   uuid.UUID `json:"tenant_id"`
+	EventType  EventType `json:"event_type"`
+	ExternalID string    `json:"external_id,omitempty"`
+	FaceID     string    `json:"face_id,omitempty"`
+	Provider   string    `json:"provider"`
+	Success    bool      `json:"success"`
+	Error      string    `json:"error,omitempty"`
+	// ErrorCode is the provider's own code for Error, e.g. ThrottlingException or HTTP_503
+	ErrorCode string            `json:"error_code,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	IPAddress string            `json:"ip_address,omitempty"`
+	UserAgent string            `json:"user_agent,omitempty"`
 }
 
 // Logger defines the interface for audit logging
@@ -78,12 +90,41 @@ func (l *SlogLogger) Log(ctx context.Context, event Event) error {
 		slog.String("tenant_id", event.TenantID.String()),
 		slog.String("provider", event.Provider),
 		slog.Bool("success", event.Success),
+		slog.String("error_code", event.ErrorCode),
 		slog.String("event_data", string(eventJSON)),
 	)
 
 	return nil
 }
 
+// MultiLogger records each event with every logger, e.g. to the log and to a queryable store
+type MultiLogger struct {
+	loggers []Logger
+}
+
+// NewMultiLogger creates a logger that fans events out to loggers
+func NewMultiLogger(loggers ...Logger) *MultiLogger {
+	return &MultiLogger{loggers: loggers}
+}
+
+// Log records the event with every logger, even when one fails, and joins their errors
+func (l *MultiLogger) Log(ctx context.Context, event Event) error {
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+
+	var errs []error
+	for _, logger := range l.loggers {
+		if err := logger.Log(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // NoOpLogger is a logger that does nothing (for testing or when audit is disabled)
 type NoOpLogger struct{}
 
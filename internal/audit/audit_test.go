@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"strings"
 	"testing"
@@ -216,6 +217,7 @@ func TestSlogLogger_Log_IncludesAllEventTypes(t *testing.T) {
 		EventFaceSearched,
 		EventFaceDeleted,
 		EventFaceCompared,
+		EventProviderError,
 	}
 
 	for _, eventType := range eventTypes {
@@ -372,3 +374,35 @@ func TestEvent_JSONSerialization_OmitsEmptyFields(t *testing.T) {
 	assert.NotContains(t, jsonStr, "ip_address")
 	assert.NotContains(t, jsonStr, "user_agent")
 }
+
+// recordingLogger keeps the events it is given and fails with err
+type recordingLogger struct {
+	events []Event
+	err    error
+}
+
+func (l *recordingLogger) Log(_ context.Context, event Event) error {
+	l.events = append(l.events, event)
+	return l.err
+}
+
+func TestMultiLogger_Log(t *testing.T) {
+	failing := &recordingLogger{err: errors.New("store unavailable")}
+	working := &recordingLogger{}
+	logger := NewMultiLogger(failing, working)
+
+	err := logger.Log(context.Background(), Event{
+		EventType: EventProviderError,
+		Provider:  "deepface",
+		ErrorCode: "HTTP_503",
+	})
+
+	assert.ErrorContains(t, err, "store unavailable")
+	require.Len(t, working.events, 1, "a failing logger does not stop the others")
+	require.Len(t, failing.events, 1)
+	// Every logger records the same event
+	assert.NotEqual(t, uuid.Nil, working.events[0].ID)
+	assert.Equal(t, failing.events[0].ID, working.events[0].ID)
+	assert.Equal(t, failing.events[0].Timestamp, working.events[0].Timestamp)
+	assert.Equal(t, "HTTP_503", working.events[0].ErrorCode)
+}
@@ -0,0 +1,129 @@
+package auditexport
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// DefaultBatchSize is the number of rows read per query
+const DefaultBatchSize = 1000
+
+// DefaultLag is how old a row must be before it is exported. It must exceed the 5s timeout of
+// asynchronous audit writes, so no row still in flight when the watermark passes its created_at.
+const DefaultLag = time.Minute
+
+// Store reads audit rows and tracks export progress
+type Store interface {
+	GetWatermark(ctx context.Context, source Source) (Watermark, error)
+	FetchSince(ctx context.Context, source Source, wm Watermark, lag time.Duration, limit int) ([]Record, error)
+	SaveWatermark(ctx context.Context, source Source, wm Watermark) error
+}
+
+// Exporter periodically copies new audit rows to object storage as partitioned NDJSON
+type Exporter struct {
+	store    Store
+	uploader Uploader
+	config   Config
+	logger   *slog.Logger
+}
+
+// NewExporter creates a new audit exporter
+func NewExporter(store Store, uploader Uploader, config Config, logger *slog.Logger) *Exporter {
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultBatchSize
+	}
+	if config.Interval <= 0 {
+		config.Interval = time.Hour
+	}
+	if config.Lag <= 0 {
+		config.Lag = DefaultLag
+	}
+	return &Exporter{
+		store:    store,
+		uploader: uploader,
+		config:   config,
+		logger:   logger,
+	}
+}
+
+// Run starts the export loop
+func (e *Exporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.config.Interval)
+	defer ticker.Stop()
+
+	e.logger.Info("audit export worker started",
+		"interval", e.config.Interval,
+		"bucket", e.config.Bucket,
+		"prefix", e.config.Prefix,
+		"lag", e.config.Lag,
+	)
+
+	for {
+		select {
+		case <-ctx.Done():
+			e.logger.Info("audit export worker stopped")
+			return
+		case <-ticker.C:
+			if err := e.ExportOnce(ctx); err != nil {
+				e.logger.Error("audit export failed", "error", err)
+			}
+		}
+	}
+}
+
+// ExportOnce exports all rows created since the last watermark for every source
+func (e *Exporter) ExportOnce(ctx context.Context) error {
+	for _, source := range Sources {
+		exported, err := e.exportSource(ctx, source)
+		if err != nil {
+			return fmt.Errorf("export %s: %w", source, err)
+		}
+		if exported > 0 {
+			e.logger.Info("audit export completed", "source", source, "records", exported)
+		}
+	}
+	return nil
+}
+
+// exportSource drains a single source in batches
+// The watermark only advances after every partition in a batch is uploaded
+func (e *Exporter) exportSource(ctx context.Context, source Source) (int, error) {
+	wm, err := e.store.GetWatermark(ctx, source)
+	if err != nil {
+		return 0, err
+	}
+
+	exported := 0
+	for {
+		records, err := e.store.FetchSince(ctx, source, wm, e.config.Lag, e.config.BatchSize)
+		if err != nil {
+			return exported, err
+		}
+		if len(records) == 0 {
+			return exported, nil
+		}
+
+		for _, partition := range partitionRecords(records) {
+			body, err := encodeNDJSON(partition.Records)
+			if err != nil {
+				return exported, err
+			}
+			if err := e.uploader.Put(ctx, objectKey(e.config.Prefix, source, partition), body); err != nil {
+				return exported, err
+			}
+		}
+
+		last := records[len(records)-1]
+		wm = Watermark{CreatedAt: last.CreatedAt, ID: last.ID}
+		if err := e.store.SaveWatermark(ctx, source, wm); err != nil {
+			return exported, err
+		}
+		exported += len(records)
+
+		if len(records) < e.config.BatchSize {
+			return exported, nil
+		}
+	}
+}
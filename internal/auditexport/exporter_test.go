@@ -0,0 +1,200 @@
+package auditexport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStore is an in-memory Store that serves records after the watermark and older than the lag
+type fakeStore struct {
+	records    map[Source][]Record
+	watermarks map[Source]Watermark
+	now        time.Time // zero uses the wall clock
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		records:    make(map[Source][]Record),
+		watermarks: make(map[Source]Watermark),
+	}
+}
+
+func (s *fakeStore) GetWatermark(_ context.Context, source Source) (Watermark, error) {
+	return s.watermarks[source], nil
+}
+
+func (s *fakeStore) FetchSince(_ context.Context, source Source, wm Watermark, lag time.Duration, limit int) ([]Record, error) {
+	now := s.now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	out := make([]Record, 0)
+	for _, rec := range s.records[source] {
+		after := rec.CreatedAt.After(wm.CreatedAt) ||
+			(rec.CreatedAt.Equal(wm.CreatedAt) && rec.ID.String() > wm.ID.String())
+		settled := rec.CreatedAt.Before(now.Add(-lag))
+		if after && settled && len(out) < limit {
+			out = append(out, rec)
+		}
+	}
+	return out, nil
+}
+
+func (s *fakeStore) SaveWatermark(_ context.Context, source Source, wm Watermark) error {
+	s.watermarks[source] = wm
+	return nil
+}
+
+// fakeUploader records uploaded objects and can fail on demand
+type fakeUploader struct {
+	objects map[string][]byte
+	err     error
+}
+
+func (u *fakeUploader) Put(_ context.Context, key string, body []byte) error {
+	if u.err != nil {
+		return u.err
+	}
+	u.objects[key] = body
+	return nil
+}
+
+func newRecord(source Source, tenantID uuid.UUID, createdAt time.Time) Record {
+	return Record{
+		Source:    source,
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		CreatedAt: createdAt,
+		Data:      []byte(`{"verified":true}`),
+	}
+}
+
+func newTestExporter(store Store, uploader Uploader, batchSize int) *Exporter {
+	return NewExporter(store, uploader, Config{Prefix: "audit", BatchSize: batchSize},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestExporter_ExportOnce_AdvancesWatermark(t *testing.T) {
+	store := newFakeStore()
+	tenantID := uuid.New()
+	base := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 5; i++ {
+		store.records[SourceVerifications] = append(store.records[SourceVerifications],
+			newRecord(SourceVerifications, tenantID, base.Add(time.Duration(i)*time.Minute)))
+	}
+	uploader := &fakeUploader{objects: make(map[string][]byte)}
+
+	// Batch size smaller than the backlog forces multiple batches
+	err := newTestExporter(store, uploader, 2).ExportOnce(context.Background())
+	require.NoError(t, err)
+
+	last := store.records[SourceVerifications][4]
+	assert.Equal(t, Watermark{CreatedAt: last.CreatedAt, ID: last.ID}, store.watermarks[SourceVerifications])
+	assert.Len(t, uploader.objects, 3)
+
+	// Second run has nothing new to export
+	uploader.objects = make(map[string][]byte)
+	require.NoError(t, newTestExporter(store, uploader, 2).ExportOnce(context.Background()))
+	assert.Empty(t, uploader.objects)
+
+	// New rows after the watermark are picked up
+	store.records[SourceVerifications] = append(store.records[SourceVerifications],
+		newRecord(SourceVerifications, tenantID, base.Add(time.Hour)))
+	require.NoError(t, newTestExporter(store, uploader, 2).ExportOnce(context.Background()))
+	assert.Len(t, uploader.objects, 1)
+}
+
+func TestExporter_ExportOnce_LateCommitIsNotSkipped(t *testing.T) {
+	store := newFakeStore()
+	tenantID := uuid.New()
+	base := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	uploader := &fakeUploader{objects: make(map[string][]byte)}
+	exporter := NewExporter(store, uploader, Config{Prefix: "audit", BatchSize: 10, Lag: 30 * time.Second},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	// early got its created_at first but is still being written when the export runs
+	early := newRecord(SourceVerifications, tenantID, base.Add(-3*time.Second))
+	late := newRecord(SourceVerifications, tenantID, base.Add(-time.Second))
+	store.records[SourceVerifications] = []Record{late}
+	store.now = base
+
+	// Without the lag late would be exported and the watermark would pass early
+	require.NoError(t, exporter.ExportOnce(context.Background()))
+	assert.Empty(t, uploader.objects)
+	assert.Equal(t, Watermark{}, store.watermarks[SourceVerifications])
+
+	// early commits, and both rows are exported once they are older than the lag
+	store.records[SourceVerifications] = []Record{early, late}
+	store.now = base.Add(time.Minute)
+	require.NoError(t, exporter.ExportOnce(context.Background()))
+
+	require.Len(t, uploader.objects, 1)
+	for _, body := range uploader.objects {
+		assert.Contains(t, string(body), early.ID.String())
+		assert.Contains(t, string(body), late.ID.String())
+	}
+	assert.Equal(t, Watermark{CreatedAt: late.CreatedAt, ID: late.ID}, store.watermarks[SourceVerifications])
+}
+
+func TestExporter_ExportOnce_UploadFailureKeepsWatermark(t *testing.T) {
+	store := newFakeStore()
+	store.records[SourceSearchAudits] = []Record{
+		newRecord(SourceSearchAudits, uuid.New(), time.Now().Add(-time.Hour)),
+	}
+	uploader := &fakeUploader{objects: make(map[string][]byte), err: errors.New("bucket unavailable")}
+
+	err := newTestExporter(store, uploader, 10).ExportOnce(context.Background())
+
+	require.Error(t, err)
+	assert.Equal(t, Watermark{}, store.watermarks[SourceSearchAudits])
+}
+
+func TestPartitionRecords_ByDateAndTenant(t *testing.T) {
+	tenantA := uuid.MustParse("00000000-0000-0000-0000-00000000000a")
+	tenantB := uuid.MustParse("00000000-0000-0000-0000-00000000000b")
+	day1 := time.Date(2025, 1, 10, 23, 59, 0, 0, time.UTC)
+	day2 := time.Date(2025, 1, 11, 0, 1, 0, 0, time.UTC)
+
+	partitions := partitionRecords([]Record{
+		newRecord(SourceVerifications, tenantB, day1),
+		newRecord(SourceVerifications, tenantA, day1),
+		newRecord(SourceVerifications, tenantA, day2),
+		newRecord(SourceVerifications, tenantA, day1),
+	})
+
+	require.Len(t, partitions, 3)
+	assert.Equal(t, "2025-01-10", partitions[0].Date)
+	assert.Equal(t, tenantA, partitions[0].TenantID)
+	assert.Len(t, partitions[0].Records, 2)
+	assert.Equal(t, tenantB, partitions[1].TenantID)
+	assert.Equal(t, "2025-01-11", partitions[2].Date)
+
+	key := objectKey("audit", SourceVerifications, partitions[2])
+	assert.Contains(t, key, "audit/verifications/date=2025-01-11/tenant_id="+tenantA.String()+"/")
+	assert.Contains(t, key, ".ndjson")
+}
+
+func TestEncodeNDJSON(t *testing.T) {
+	id := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	tenantID := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	createdAt := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	body, err := encodeNDJSON([]Record{
+		{Source: SourceSearchAudits, ID: id, TenantID: tenantID, CreatedAt: createdAt, Data: []byte(`{"results_count":1}`)},
+		{Source: SourceSearchAudits, ID: id, TenantID: tenantID, CreatedAt: createdAt, Data: []byte(`{"results_count":0}`)},
+	})
+	require.NoError(t, err)
+
+	want := `{"source":"search_audits","id":"11111111-1111-1111-1111-111111111111","tenant_id":"22222222-2222-2222-2222-222222222222","created_at":"2025-01-10T12:00:00Z","data":{"results_count":1}}` + "\n" +
+		`{"source":"search_audits","id":"11111111-1111-1111-1111-111111111111","tenant_id":"22222222-2222-2222-2222-222222222222","created_at":"2025-01-10T12:00:00Z","data":{"results_count":0}}` + "\n"
+	assert.Equal(t, want, string(body))
+}
@@ -0,0 +1,63 @@
+package auditexport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// partitionRecords groups records by UTC date and tenant, preserving record order
+func partitionRecords(records []Record) []Partition {
+	type partitionKey struct {
+		date     string
+		tenantID uuid.UUID
+	}
+
+	index := make(map[partitionKey]int)
+	partitions := make([]Partition, 0)
+
+	for _, rec := range records {
+		key := partitionKey{date: rec.CreatedAt.UTC().Format("2006-01-02"), tenantID: rec.TenantID}
+		i, exists := index[key]
+		if !exists {
+			i = len(partitions)
+			index[key] = i
+			partitions = append(partitions, Partition{Date: key.date, TenantID: key.tenantID})
+		}
+		partitions[i].Records = append(partitions[i].Records, rec)
+	}
+
+	// Deterministic upload order
+	sort.SliceStable(partitions, func(i, j int) bool {
+		if partitions[i].Date != partitions[j].Date {
+			return partitions[i].Date < partitions[j].Date
+		}
+		return partitions[i].TenantID.String() < partitions[j].TenantID.String()
+	})
+
+	return partitions
+}
+
+// encodeNDJSON serializes records as newline-delimited JSON, one record per line
+func encodeNDJSON(records []Record) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, rec := range records {
+		if err := enc.Encode(rec); err != nil {
+			return nil, fmt.Errorf("encode record %s: %w", rec.ID, err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// objectKey builds the object key for a partition
+// Keys are unique per batch so previously exported objects are never overwritten
+func objectKey(prefix string, source Source, p Partition) string {
+	last := p.Records[len(p.Records)-1]
+	name := fmt.Sprintf("%d-%s.ndjson", last.CreatedAt.UnixNano(), last.ID)
+	return path.Join(prefix, string(source), "date="+p.Date, "tenant_id="+p.TenantID.String(), name)
+}
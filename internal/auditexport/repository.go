@@ -0,0 +1,113 @@
+package auditexport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// DB interface for database operations, compatible with pgxpool.Pool and pgxmock
+type DB interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+}
+
+// Repository reads audit rows and persists export watermarks
+type Repository struct {
+	db DB
+}
+
+// NewRepository creates a new audit export repository
+func NewRepository(db DB) *Repository {
+	return &Repository{db: db}
+}
+
+// GetWatermark returns the last exported position for a source (zero value if never exported)
+func (r *Repository) GetWatermark(ctx context.Context, source Source) (Watermark, error) {
+	query := `
+		SELECT last_created_at, last_id
+		FROM audit_export_watermarks
+		WHERE source = $1
+	`
+
+	var wm Watermark
+	err := r.db.QueryRow(ctx, query, string(source)).Scan(&wm.CreatedAt, &wm.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Watermark{}, nil
+	}
+	if err != nil {
+		return Watermark{}, fmt.Errorf("get watermark for %s: %w", source, err)
+	}
+
+	return wm, nil
+}
+
+// FetchSince returns up to limit rows created after the watermark and at least lag ago by the
+// database clock, ordered by (created_at, id)
+func (r *Repository) FetchSince(ctx context.Context, source Source, wm Watermark, lag time.Duration, limit int) ([]Record, error) {
+	var table string
+	switch source {
+	case SourceVerifications:
+		table = "verifications"
+	case SourceSearchAudits:
+		table = "search_audits"
+	default:
+		return nil, fmt.Errorf("unknown audit source: %s", source)
+	}
+
+	// #nosec G201 - table name comes from the fixed switch above
+	query := fmt.Sprintf(`
+		SELECT t.id, t.tenant_id, t.created_at, row_to_json(t)::text
+		FROM %s t
+		WHERE (t.created_at, t.id) > ($1, $2)
+		  AND t.created_at < NOW() - $4 * INTERVAL '1 millisecond'
+		ORDER BY t.created_at ASC, t.id ASC
+		LIMIT $3
+	`, table)
+
+	rows, err := r.db.Query(ctx, query, wm.CreatedAt, wm.ID, limit, lag.Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s since watermark: %w", source, err)
+	}
+	defer rows.Close()
+
+	records := make([]Record, 0)
+	for rows.Next() {
+		rec := Record{Source: source}
+		var data string
+		if err := rows.Scan(&rec.ID, &rec.TenantID, &rec.CreatedAt, &data); err != nil {
+			return nil, fmt.Errorf("scan %s row: %w", source, err)
+		}
+		rec.Data = []byte(data)
+		records = append(records, rec)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate %s rows: %w", source, err)
+	}
+
+	return records, nil
+}
+
+// SaveWatermark stores the last exported position for a source
+func (r *Repository) SaveWatermark(ctx context.Context, source Source, wm Watermark) error {
+	query := `
+		INSERT INTO audit_export_watermarks (source, last_created_at, last_id, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (source) DO UPDATE SET
+			last_created_at = EXCLUDED.last_created_at,
+			last_id = EXCLUDED.last_id,
+			updated_at = NOW()
+	`
+
+	if _, err := r.db.Exec(ctx, query, string(source), wm.CreatedAt, wm.ID); err != nil {
+		return fmt.Errorf("save watermark for %s: %w", source, err)
+	}
+
+	return nil
+}
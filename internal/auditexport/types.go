@@ -0,0 +1,56 @@
+package auditexport
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Source identifies an audit table that is exported
+type Source string
+
+const (
+	// SourceVerifications exports the verifications audit log
+	SourceVerifications Source = "verifications"
+	// SourceSearchAudits exports the search_audits table (LGPD compliance)
+	SourceSearchAudits Source = "search_audits"
+)
+
+// Sources lists every exported audit table, in export order
+var Sources = []Source{SourceVerifications, SourceSearchAudits}
+
+// Record is a single exported audit row
+type Record struct {
+	Source    Source          `json:"source"`
+	ID        uuid.UUID       `json:"id"`
+	TenantID  uuid.UUID       `json:"tenant_id"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Watermark is the position of the last exported row for a source
+// Rows are ordered by (created_at, id) so ties on created_at are not skipped.
+// Audit rows are written asynchronously and created_at is set before they commit, so only rows
+// older than the export lag are read: a row committing later would fall behind the watermark.
+type Watermark struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// Partition groups records that are written to the same object
+type Partition struct {
+	Date     string
+	TenantID uuid.UUID
+	Records  []Record
+}
+
+// Config holds audit export settings
+type Config struct {
+	Bucket    string
+	Prefix    string
+	Interval  time.Duration
+	BatchSize int
+	// Lag keeps rows younger than it out of the export, see DefaultLag
+	Lag time.Duration
+}
@@ -0,0 +1,87 @@
+package auditexport
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// Uploader writes exported objects to storage
+type Uploader interface {
+	Put(ctx context.Context, key string, body []byte) error
+}
+
+// S3Uploader writes objects to an S3-compatible bucket using SigV4-signed PUT requests
+type S3Uploader struct {
+	httpClient  *http.Client
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+	endpoint    string
+	bucket      string
+	region      string
+}
+
+// NewS3Uploader creates an uploader for the given bucket
+// endpoint is optional; when empty the AWS regional S3 endpoint is used
+func NewS3Uploader(credentials aws.CredentialsProvider, region, endpoint, bucket string) *S3Uploader {
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+	return &S3Uploader{
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		credentials: credentials,
+		signer:      v4.NewSigner(),
+		endpoint:    strings.TrimRight(endpoint, "/"),
+		bucket:      bucket,
+		region:      region,
+	}
+}
+
+// Put uploads body to key using path-style addressing
+// If-None-Match prevents overwriting an object that was already exported
+func (u *S3Uploader) Put(ctx context.Context, key string, body []byte) error {
+	objectURL := fmt.Sprintf("%s/%s/%s", u.endpoint, u.bucket, (&url.URL{Path: key}).EscapedPath())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create put request: %w", err)
+	}
+
+	sum := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(sum[:])
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	req.Header.Set("If-None-Match", "*")
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	creds, err := u.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("retrieve credentials: %w", err)
+	}
+
+	if err := u.signer.SignHTTP(ctx, creds, req, payloadHash, "s3", u.region, time.Now()); err != nil {
+		return fmt.Errorf("sign put request: %w", err)
+	}
+
+	resp, err := u.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("put object %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("put object %s: status %d: %s", key, resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
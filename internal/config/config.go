@@ -2,6 +2,8 @@ package config
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 )
@@ -10,17 +12,133 @@ type Config struct {
 	// Server
 	Port        int    `envconfig:"PORT" default:"3000"`
 	Environment string `envconfig:"ENV" default:"development"`
+	// ShutdownTimeout bounds graceful shutdown, including the final usage and rate-limit flush
+	ShutdownTimeout time.Duration `envconfig:"SHUTDOWN_TIMEOUT" default:"10s"`
+	// MaxRequestTimeout caps the budget clients may request with the X-Rekko-Timeout-Ms header
+	MaxRequestTimeout time.Duration `envconfig:"MAX_REQUEST_TIMEOUT" default:"30s"`
 
 	// Database
-	DatabaseURL string `envconfig:"DATABASE_URL" required:"true"`
+	DatabaseURL     string `envconfig:"DATABASE_URL" required:"true"`
+	DatabaseReadURL string `envconfig:"DATABASE_READ_URL"` // optional read replica for metrics
+	// Pool sizing applied to both the primary and the read replica pools
+	DBMaxConns        int32         `envconfig:"DB_MAX_CONNS" default:"25"`
+	DBMinConns        int32         `envconfig:"DB_MIN_CONNS" default:"0"`
+	DBMaxConnLifetime time.Duration `envconfig:"DB_MAX_CONN_LIFETIME" default:"30m"`
+	// DBCheckExtensions refuses to start when the uuid-ossp or vector extension is missing
+	DBCheckExtensions bool `envconfig:"DB_CHECK_EXTENSIONS" default:"true"`
+	// DBCheckSchemaVersion refuses to start when the schema is dirty or behind the binary's migrations
+	DBCheckSchemaVersion bool `envconfig:"DB_CHECK_SCHEMA_VERSION" default:"true"`
+	// DBSchemaVersionStrict also refuses a schema ahead of the binary, which rolling deploys produce
+	DBSchemaVersionStrict bool `envconfig:"DB_SCHEMA_VERSION_STRICT" default:"false"`
+	// DBMigrationWait bounds how long startup waits for a running migration before the schema version check
+	DBMigrationWait time.Duration `envconfig:"DB_MIGRATION_WAIT" default:"2m"`
+	// DBSlowQueryThreshold logs at warn the queries that take at least this long (0 disables)
+	DBSlowQueryThreshold time.Duration `envconfig:"DB_SLOW_QUERY_THRESHOLD" default:"500ms"`
+
+	// Rate Limiting
+	// RateLimitFailPolicy controls search rate limiting when the store is unavailable: "closed" or "open"
+	RateLimitFailPolicy string `envconfig:"RATE_LIMIT_FAIL_POLICY" default:"closed"`
+	// WidgetSessionRateLimit caps the widget sessions one public key may create per minute (0 disables)
+	WidgetSessionRateLimit int `envconfig:"WIDGET_SESSION_RATE_LIMIT" default:"30"`
+	// WidgetCheckRateLimit caps the registration checks one widget session may make per minute (0 disables)
+	WidgetCheckRateLimit int `envconfig:"WIDGET_CHECK_RATE_LIMIT" default:"0"`
+	// ClockSkewLeeway is how far past expiry (or before nbf) widget sessions and admin JWTs are still accepted
+	ClockSkewLeeway time.Duration `envconfig:"CLOCK_SKEW_LEEWAY" default:"30s"`
+
+	// Usage Metering
+	// UsageFlushInterval is how often buffered usage counters are written to the database
+	UsageFlushInterval time.Duration `envconfig:"USAGE_FLUSH_INTERVAL" default:"5s"`
+
+	// Webhooks
+	// WebhookTenantConcurrency caps the queued deliveries of one tenant that run at the same time
+	WebhookTenantConcurrency int `envconfig:"WEBHOOK_TENANT_CONCURRENCY" default:"2"`
 
 	// Face Recognition Provider
 	FaceProvider string `envconfig:"FACE_PROVIDER" default:"deepface"`
 	DeepFaceURL  string `envconfig:"DEEPFACE_URL" default:"http://localhost:5000"`
 	AWSRegion    string `envconfig:"AWS_REGION" default:"us-east-1"`
+	// RekognitionCostTags are cost allocation tags set on each tenant's Rekognition collection,
+	// as key:value pairs whose values may use {tenant_id} and {collection} (empty disables)
+	RekognitionCostTags map[string]string `envconfig:"REKOGNITION_COST_TAGS"`
+	// DeprecationSunsets sets when deprecated routes stop working, as "METHOD /path":YYYY-MM-DD
+	// pairs sent in their Sunset header (routes without one get no Sunset)
+	DeprecationSunsets map[string]string `envconfig:"DEPRECATION_SUNSETS"`
+	// RekognitionMaxTPS and RekognitionMaxConcurrency pace Rekognition calls under the AWS account
+	// quotas, shared by all tenants (0 disables); calls queue up to RekognitionMaxWait, then fail
+	RekognitionMaxTPS         float64       `envconfig:"REKOGNITION_MAX_TPS" default:"0"`
+	RekognitionMaxConcurrency int           `envconfig:"REKOGNITION_MAX_CONCURRENCY" default:"0"`
+	RekognitionMaxWait        time.Duration `envconfig:"REKOGNITION_MAX_WAIT" default:"2s"`
+	// DataRegion is where this deployment keeps biometric data; tenants pinned elsewhere are refused.
+	// Defaults to AWS_REGION when FACE_PROVIDER=rekognition.
+	DataRegion string `envconfig:"DATA_REGION"`
+	// ShadowFaceProvider runs a candidate provider in parallel for opted-in tenants: "deepface", "mock" or empty (disabled)
+	ShadowFaceProvider string `envconfig:"SHADOW_FACE_PROVIDER"`
+	ShadowDeepFaceURL  string `envconfig:"SHADOW_DEEPFACE_URL" default:"http://localhost:5000"`
+	// LivenessProvider handles passive liveness instead of FACE_PROVIDER: "deepface", "mock" or empty (same provider)
+	LivenessProvider    string `envconfig:"LIVENESS_PROVIDER"`
+	LivenessDeepFaceURL string `envconfig:"LIVENESS_DEEPFACE_URL" default:"http://localhost:5000"`
+	// ProviderTimeout bounds the provider calls of each request; the per-operation values override it (0 = use ProviderTimeout)
+	ProviderTimeout         time.Duration `envconfig:"PROVIDER_TIMEOUT" default:"30s"`
+	ProviderTimeoutSearch   time.Duration `envconfig:"PROVIDER_TIMEOUT_SEARCH"`
+	ProviderTimeoutVerify   time.Duration `envconfig:"PROVIDER_TIMEOUT_VERIFY"`
+	ProviderTimeoutDetect   time.Duration `envconfig:"PROVIDER_TIMEOUT_DETECT"`
+	ProviderTimeoutRegister time.Duration `envconfig:"PROVIDER_TIMEOUT_REGISTER"`
+	// EmbeddingModel overrides the model fingerprint stored with each face (defaults to the provider's model)
+	EmbeddingModel string `envconfig:"EMBEDDING_MODEL"`
+	// NormalizeEmbeddings L2-normalizes embeddings before storage and search, for providers that don't
+	NormalizeEmbeddings bool `envconfig:"NORMALIZE_EMBEDDINGS" default:"false"`
+	// EmbeddingMaxMagnitude is the largest L2 norm accepted for registered and query embeddings
+	EmbeddingMaxMagnitude float64 `envconfig:"EMBEDDING_MAX_MAGNITUDE" default:"1000"`
+	// MaxMetadataBytes caps the serialized JSON size of face metadata
+	MaxMetadataBytes int `envconfig:"MAX_METADATA_BYTES" default:"16384"`
+	// MaxVerificationAge caps the window of recently-verified checks
+	MaxVerificationAge time.Duration `envconfig:"MAX_VERIFICATION_AGE" default:"24h"`
+	// ParallelVerify runs a verify's stored face lookup alongside the provider analysis of the image
+	ParallelVerify bool `envconfig:"PARALLEL_VERIFY" default:"false"`
+	// FaceStatsRefreshThreshold runs ANALYZE faces after this many new registrations (0 disables)
+	FaceStatsRefreshThreshold int `envconfig:"FACE_STATS_REFRESH_THRESHOLD" default:"0"`
+	// FaceStatsRefreshDebounce is the minimum time between statistics refreshes
+	FaceStatsRefreshDebounce time.Duration `envconfig:"FACE_STATS_REFRESH_DEBOUNCE" default:"30s"`
+	// FaceCountCacheTTL is how long a tenant's face count is trusted to skip searching an empty collection (0 disables)
+	FaceCountCacheTTL time.Duration `envconfig:"FACE_COUNT_CACHE_TTL" default:"30s"`
+	// FaceExpiryInterval is how often faces past their expires_at are deleted (0 disables the sweep)
+	FaceExpiryInterval time.Duration `envconfig:"FACE_EXPIRY_INTERVAL" default:"1m"`
+	// ProviderErrorRetention is how long failed provider calls are kept in provider_errors (0 keeps them forever)
+	ProviderErrorRetention time.Duration `envconfig:"PROVIDER_ERROR_RETENTION" default:"2160h"`
+	// ImageMaxDimension downscales uploads whose width or height exceeds it before provider calls (0 disables)
+	ImageMaxDimension int `envconfig:"IMAGE_MAX_DIMENSION" default:"0"`
+	// ImageBlankThreshold rejects uploads whose luminance standard deviation (0-255) is below it as blank.
+	// Opt-in since the check decodes every upload (0 disables)
+	ImageBlankThreshold float64 `envconfig:"IMAGE_BLANK_THRESHOLD" default:"0"`
+
+	// Audit Export (opt-in, S3-compatible storage)
+	AuditExportEnabled  bool          `envconfig:"AUDIT_EXPORT_ENABLED" default:"false"`
+	AuditExportBucket   string        `envconfig:"AUDIT_EXPORT_BUCKET"`
+	AuditExportPrefix   string        `envconfig:"AUDIT_EXPORT_PREFIX" default:"audit"`
+	AuditExportInterval time.Duration `envconfig:"AUDIT_EXPORT_INTERVAL" default:"1h"`
+	AuditExportEndpoint string        `envconfig:"AUDIT_EXPORT_ENDPOINT"` // optional, for S3-compatible providers
+	// AuditExportLag only exports audit rows at least this old, so rows still being written are not skipped
+	AuditExportLag time.Duration `envconfig:"AUDIT_EXPORT_LAG" default:"1m"`
 
 	// Security
 	APIKeySecret string `envconfig:"API_KEY_SECRET" required:"true"`
+	// AdminJWTSecret signs super admin and tenant impersonation tokens
+	AdminJWTSecret string `envconfig:"ADMIN_JWT_SECRET" required:"true"`
+	// AutoProvisionTenants creates the tenant for pre-issued self-serve API keys on first use
+	AutoProvisionTenants bool `envconfig:"AUTO_PROVISION_TENANTS" default:"false"`
+	// TenantDeletePurgeCollection deletes the Rekognition collection when a super admin deletes a tenant
+	TenantDeletePurgeCollection bool `envconfig:"TENANT_DELETE_PURGE_COLLECTION" default:"true"`
+	// SelfTestOnBoot runs the register/verify/search/delete self-test at startup and logs the result
+	SelfTestOnBoot bool `envconfig:"SELFTEST_ON_BOOT" default:"false"`
+}
+
+// placeholderSecrets are example values that must never sign real tokens
+var placeholderSecrets = map[string]bool{
+	"your-secret-key":         true,
+	"change-me":               true,
+	"changeme":                true,
+	"change-me-in-production": true,
+	"secret":                  true,
 }
 
 func Load() (*Config, error) {
@@ -28,9 +146,185 @@ func Load() (*Config, error) {
 	if err := envconfig.Process("", &cfg); err != nil {
 		return nil, fmt.Errorf("load config: %w", err)
 	}
+
+	if cfg.DBMaxConns < 1 {
+		return nil, fmt.Errorf("load config: DB_MAX_CONNS must be at least 1, got %d", cfg.DBMaxConns)
+	}
+
+	if cfg.DBMinConns < 0 || cfg.DBMinConns > cfg.DBMaxConns {
+		return nil, fmt.Errorf("load config: DB_MIN_CONNS must be between 0 and DB_MAX_CONNS (%d), got %d", cfg.DBMaxConns, cfg.DBMinConns)
+	}
+
+	if cfg.DBMaxConnLifetime <= 0 {
+		return nil, fmt.Errorf("load config: DB_MAX_CONN_LIFETIME must be positive, got %s", cfg.DBMaxConnLifetime)
+	}
+
+	if cfg.DBCheckSchemaVersion && cfg.DBMigrationWait <= 0 {
+		return nil, fmt.Errorf("load config: DB_MIGRATION_WAIT must be positive, got %s", cfg.DBMigrationWait)
+	}
+
+	if cfg.DBSlowQueryThreshold < 0 {
+		return nil, fmt.Errorf("load config: DB_SLOW_QUERY_THRESHOLD must not be negative, got %s", cfg.DBSlowQueryThreshold)
+	}
+
+	if cfg.RateLimitFailPolicy != "open" && cfg.RateLimitFailPolicy != "closed" {
+		return nil, fmt.Errorf("load config: invalid RATE_LIMIT_FAIL_POLICY %q", cfg.RateLimitFailPolicy)
+	}
+
+	if cfg.WidgetSessionRateLimit < 0 {
+		return nil, fmt.Errorf("load config: WIDGET_SESSION_RATE_LIMIT must not be negative, got %d", cfg.WidgetSessionRateLimit)
+	}
+	if cfg.WidgetCheckRateLimit < 0 {
+		return nil, fmt.Errorf("load config: WIDGET_CHECK_RATE_LIMIT must not be negative, got %d", cfg.WidgetCheckRateLimit)
+	}
+	if cfg.ClockSkewLeeway < 0 {
+		return nil, fmt.Errorf("load config: CLOCK_SKEW_LEEWAY must not be negative, got %s", cfg.ClockSkewLeeway)
+	}
+
+	if cfg.WebhookTenantConcurrency < 1 {
+		return nil, fmt.Errorf("load config: WEBHOOK_TENANT_CONCURRENCY must be at least 1, got %d", cfg.WebhookTenantConcurrency)
+	}
+
+	if cfg.MaxMetadataBytes <= 0 {
+		return nil, fmt.Errorf("load config: MAX_METADATA_BYTES must be positive, got %d", cfg.MaxMetadataBytes)
+	}
+
+	if cfg.EmbeddingMaxMagnitude <= 0 {
+		return nil, fmt.Errorf("load config: EMBEDDING_MAX_MAGNITUDE must be positive, got %g", cfg.EmbeddingMaxMagnitude)
+	}
+
+	if cfg.ShutdownTimeout <= 0 {
+		return nil, fmt.Errorf("load config: SHUTDOWN_TIMEOUT must be positive, got %s", cfg.ShutdownTimeout)
+	}
+
+	if cfg.MaxRequestTimeout <= 0 {
+		return nil, fmt.Errorf("load config: MAX_REQUEST_TIMEOUT must be positive, got %s", cfg.MaxRequestTimeout)
+	}
+
+	if cfg.UsageFlushInterval <= 0 {
+		return nil, fmt.Errorf("load config: USAGE_FLUSH_INTERVAL must be positive, got %s", cfg.UsageFlushInterval)
+	}
+
+	if cfg.ProviderTimeout <= 0 {
+		return nil, fmt.Errorf("load config: PROVIDER_TIMEOUT must be positive, got %s", cfg.ProviderTimeout)
+	}
+
+	for _, op := range []struct {
+		name    string
+		timeout time.Duration
+	}{
+		{"PROVIDER_TIMEOUT_SEARCH", cfg.ProviderTimeoutSearch},
+		{"PROVIDER_TIMEOUT_VERIFY", cfg.ProviderTimeoutVerify},
+		{"PROVIDER_TIMEOUT_DETECT", cfg.ProviderTimeoutDetect},
+		{"PROVIDER_TIMEOUT_REGISTER", cfg.ProviderTimeoutRegister},
+	} {
+		if op.timeout < 0 {
+			return nil, fmt.Errorf("load config: %s must not be negative, got %s", op.name, op.timeout)
+		}
+	}
+
+	if len(cfg.RekognitionCostTags) > 50 {
+		return nil, fmt.Errorf("load config: REKOGNITION_COST_TAGS accepts at most 50 tags, got %d", len(cfg.RekognitionCostTags))
+	}
+	for key := range cfg.RekognitionCostTags {
+		if strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("load config: REKOGNITION_COST_TAGS has an empty tag key")
+		}
+	}
+
+	for route, date := range cfg.DeprecationSunsets {
+		if _, err := time.Parse(time.DateOnly, date); err != nil {
+			return nil, fmt.Errorf("load config: DEPRECATION_SUNSETS date for %q must be YYYY-MM-DD, got %q", route, date)
+		}
+	}
+
+	if cfg.RekognitionMaxTPS < 0 {
+		return nil, fmt.Errorf("load config: REKOGNITION_MAX_TPS must not be negative, got %v", cfg.RekognitionMaxTPS)
+	}
+	if cfg.RekognitionMaxConcurrency < 0 {
+		return nil, fmt.Errorf("load config: REKOGNITION_MAX_CONCURRENCY must not be negative, got %d", cfg.RekognitionMaxConcurrency)
+	}
+	if cfg.RekognitionMaxWait < 0 {
+		return nil, fmt.Errorf("load config: REKOGNITION_MAX_WAIT must not be negative, got %s", cfg.RekognitionMaxWait)
+	}
+
+	if cfg.ImageMaxDimension < 0 {
+		return nil, fmt.Errorf("load config: IMAGE_MAX_DIMENSION must not be negative, got %d", cfg.ImageMaxDimension)
+	}
+
+	if cfg.ImageBlankThreshold < 0 {
+		return nil, fmt.Errorf("load config: IMAGE_BLANK_THRESHOLD must not be negative, got %g", cfg.ImageBlankThreshold)
+	}
+
+	if cfg.MaxVerificationAge <= 0 {
+		return nil, fmt.Errorf("load config: MAX_VERIFICATION_AGE must be positive, got %s", cfg.MaxVerificationAge)
+	}
+
+	if cfg.FaceStatsRefreshThreshold < 0 {
+		return nil, fmt.Errorf("load config: FACE_STATS_REFRESH_THRESHOLD must not be negative, got %d", cfg.FaceStatsRefreshThreshold)
+	}
+
+	if cfg.FaceStatsRefreshThreshold > 0 && cfg.FaceStatsRefreshDebounce <= 0 {
+		return nil, fmt.Errorf("load config: FACE_STATS_REFRESH_DEBOUNCE must be positive, got %s", cfg.FaceStatsRefreshDebounce)
+	}
+
+	if cfg.FaceExpiryInterval < 0 {
+		return nil, fmt.Errorf("load config: FACE_EXPIRY_INTERVAL must not be negative, got %s", cfg.FaceExpiryInterval)
+	}
+
+	if cfg.ProviderErrorRetention < 0 {
+		return nil, fmt.Errorf("load config: PROVIDER_ERROR_RETENTION must not be negative, got %s", cfg.ProviderErrorRetention)
+	}
+
+	switch cfg.ShadowFaceProvider {
+	case "", "deepface", "mock":
+	default:
+		return nil, fmt.Errorf("load config: invalid SHADOW_FACE_PROVIDER %q", cfg.ShadowFaceProvider)
+	}
+
+	switch cfg.LivenessProvider {
+	case "", "deepface", "mock":
+	default:
+		return nil, fmt.Errorf("load config: invalid LIVENESS_PROVIDER %q", cfg.LivenessProvider)
+	}
+
+	if cfg.FaceProvider == "rekognition" && cfg.DataRegion != "" && !strings.EqualFold(cfg.DataRegion, cfg.AWSRegion) {
+		return nil, fmt.Errorf("load config: DATA_REGION %q must match AWS_REGION %q when FACE_PROVIDER=rekognition", cfg.DataRegion, cfg.AWSRegion)
+	}
+
+	if cfg.AdminJWTSecret == "" || placeholderSecrets[strings.ToLower(cfg.AdminJWTSecret)] {
+		return nil, fmt.Errorf("load config: ADMIN_JWT_SECRET must be set to a random secret, e.g. openssl rand -hex 32")
+	}
+
+	if cfg.AuditExportEnabled && cfg.AuditExportBucket == "" {
+		return nil, fmt.Errorf("load config: AUDIT_EXPORT_BUCKET is required when AUDIT_EXPORT_ENABLED=true")
+	}
+
+	// Audit rows are written asynchronously with a 5s timeout; a shorter lag could skip them
+	if cfg.AuditExportEnabled && cfg.AuditExportLag < 10*time.Second {
+		return nil, fmt.Errorf("load config: AUDIT_EXPORT_LAG must be at least 10s, got %s", cfg.AuditExportLag)
+	}
+
 	return &cfg, nil
 }
 
+// EffectiveDataRegion returns the region biometric data is kept in, empty when unknown
+func (c *Config) EffectiveDataRegion() string {
+	if c.DataRegion != "" {
+		return c.DataRegion
+	}
+	if c.FaceProvider == "rekognition" {
+		return c.AWSRegion
+	}
+	return ""
+}
+
+// MaxProviderTimeout returns the longest configured provider timeout, so provider HTTP clients
+// never cut off an operation whose own timeout is longer than PROVIDER_TIMEOUT
+func (c *Config) MaxProviderTimeout() time.Duration {
+	return max(c.ProviderTimeout, c.ProviderTimeoutSearch, c.ProviderTimeoutVerify, c.ProviderTimeoutDetect, c.ProviderTimeoutRegister)
+}
+
 func (c *Config) IsDevelopment() bool {
 	return c.Environment == "development"
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 )
 
 const (
@@ -43,9 +44,441 @@ func TestLoad(t *testing.T) {
 			check: func(c *Config) bool {
 				return c.Port == 3000 &&
 					c.Environment == envDevelopment &&
-					c.FaceProvider == "deepface"
+					c.FaceProvider == "deepface" &&
+					c.MaxMetadataBytes == 16384 &&
+					c.EmbeddingMaxMagnitude == 1000 &&
+					c.DBMaxConns == 25 &&
+					c.DBMinConns == 0 &&
+					c.FaceStatsRefreshThreshold == 0 &&
+					c.MaxVerificationAge == 24*time.Hour &&
+					!c.ParallelVerify &&
+					c.MaxRequestTimeout == 30*time.Second &&
+					c.ProviderTimeout == 30*time.Second &&
+					c.ProviderTimeoutSearch == 0 &&
+					c.FaceCountCacheTTL == 30*time.Second &&
+					c.FaceExpiryInterval == time.Minute &&
+					c.ProviderErrorRetention == 90*24*time.Hour &&
+					c.ImageBlankThreshold == 0 &&
+					c.LivenessProvider == "" &&
+					c.WebhookTenantConcurrency == 2 &&
+					c.RekognitionMaxTPS == 0 &&
+					c.RekognitionMaxConcurrency == 0 &&
+					c.RekognitionMaxWait == 2*time.Second &&
+					c.WidgetSessionRateLimit == 30 &&
+					c.WidgetCheckRateLimit == 0 &&
+					c.ClockSkewLeeway == 30*time.Second &&
+					c.DBSlowQueryThreshold == 500*time.Millisecond &&
+					c.DBCheckSchemaVersion &&
+					!c.DBSchemaVersionStrict &&
+					c.DBMigrationWait == 2*time.Minute &&
+					c.AuditExportLag == time.Minute &&
+					!c.AutoProvisionTenants
 			},
 		},
+		{
+			name: "loads optional read replica url",
+			envVars: map[string]string{
+				"DATABASE_URL":      "postgres://localhost/test",
+				"DATABASE_READ_URL": "postgres://replica/test",
+				"API_KEY_SECRET":    "secret123",
+			},
+			wantErr: false,
+			check: func(c *Config) bool {
+				return c.DatabaseReadURL == "postgres://replica/test"
+			},
+		},
+		{
+			name: "loads database pool sizing",
+			envVars: map[string]string{
+				"DATABASE_URL":         "postgres://localhost/test",
+				"API_KEY_SECRET":       "secret123",
+				"DB_MAX_CONNS":         "40",
+				"DB_MIN_CONNS":         "5",
+				"DB_MAX_CONN_LIFETIME": "15m",
+			},
+			wantErr: false,
+			check: func(c *Config) bool {
+				return c.DBMaxConns == 40 &&
+					c.DBMinConns == 5 &&
+					c.DBMaxConnLifetime == 15*time.Minute
+			},
+		},
+		{
+			name: "fails with zero max conns",
+			envVars: map[string]string{
+				"DATABASE_URL":   "postgres://localhost/test",
+				"API_KEY_SECRET": "secret123",
+				"DB_MAX_CONNS":   "0",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "fails with min conns above max conns",
+			envVars: map[string]string{
+				"DATABASE_URL":   "postgres://localhost/test",
+				"API_KEY_SECRET": "secret123",
+				"DB_MAX_CONNS":   "5",
+				"DB_MIN_CONNS":   "10",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "fails with non-positive max conn lifetime",
+			envVars: map[string]string{
+				"DATABASE_URL":         "postgres://localhost/test",
+				"API_KEY_SECRET":       "secret123",
+				"DB_MAX_CONN_LIFETIME": "0s",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "fails with invalid rate limit fail policy",
+			envVars: map[string]string{
+				"DATABASE_URL":           "postgres://localhost/test",
+				"API_KEY_SECRET":         "secret123",
+				"RATE_LIMIT_FAIL_POLICY": "sometimes",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "fails with zero webhook tenant concurrency",
+			envVars: map[string]string{
+				"DATABASE_URL":               "postgres://localhost/test",
+				"API_KEY_SECRET":             "secret123",
+				"WEBHOOK_TENANT_CONCURRENCY": "0",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "parses rekognition cost tags",
+			envVars: map[string]string{
+				"DATABASE_URL":          "postgres://localhost/test",
+				"API_KEY_SECRET":        "secret123",
+				"REKOGNITION_COST_TAGS": "rekko-tenant:{tenant_id},team:biometrics",
+			},
+			wantErr: false,
+			check: func(c *Config) bool {
+				return len(c.RekognitionCostTags) == 2 &&
+					c.RekognitionCostTags["rekko-tenant"] == "{tenant_id}" &&
+					c.RekognitionCostTags["team"] == "biometrics"
+			},
+		},
+		{
+			name: "parses deprecation sunsets",
+			envVars: map[string]string{
+				"DATABASE_URL":        "postgres://localhost/test",
+				"API_KEY_SECRET":      "secret123",
+				"DEPRECATION_SUNSETS": "GET /v1/admin/faces/compare:2027-06-30",
+			},
+			wantErr: false,
+			check: func(c *Config) bool {
+				return c.DeprecationSunsets["GET /v1/admin/faces/compare"] == "2027-06-30"
+			},
+		},
+		{
+			name: "fails with malformed deprecation sunset date",
+			envVars: map[string]string{
+				"DATABASE_URL":        "postgres://localhost/test",
+				"API_KEY_SECRET":      "secret123",
+				"DEPRECATION_SUNSETS": "GET /v1/admin/faces/compare:30/06/2027",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "fails with negative rekognition max tps",
+			envVars: map[string]string{
+				"DATABASE_URL":        "postgres://localhost/test",
+				"API_KEY_SECRET":      "secret123",
+				"REKOGNITION_MAX_TPS": "-1",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "fails with negative rekognition max concurrency",
+			envVars: map[string]string{
+				"DATABASE_URL":                "postgres://localhost/test",
+				"API_KEY_SECRET":              "secret123",
+				"REKOGNITION_MAX_CONCURRENCY": "-1",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "fails with an audit export lag shorter than audit writes",
+			envVars: map[string]string{
+				"DATABASE_URL":         "postgres://localhost/test",
+				"API_KEY_SECRET":       "secret123",
+				"AUDIT_EXPORT_ENABLED": "true",
+				"AUDIT_EXPORT_BUCKET":  "rekko-audit",
+				"AUDIT_EXPORT_LAG":     "5s",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "fails without an admin JWT secret",
+			envVars: map[string]string{
+				"DATABASE_URL":     "postgres://localhost/test",
+				"API_KEY_SECRET":   "secret123",
+				"ADMIN_JWT_SECRET": "",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "fails with a placeholder admin JWT secret",
+			envVars: map[string]string{
+				"DATABASE_URL":     "postgres://localhost/test",
+				"API_KEY_SECRET":   "secret123",
+				"ADMIN_JWT_SECRET": "your-secret-key",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "fails with non-positive migration wait",
+			envVars: map[string]string{
+				"DATABASE_URL":      "postgres://localhost/test",
+				"API_KEY_SECRET":    "secret123",
+				"DB_MIGRATION_WAIT": "0s",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "migration wait is ignored without the schema version check",
+			envVars: map[string]string{
+				"DATABASE_URL":            "postgres://localhost/test",
+				"API_KEY_SECRET":          "secret123",
+				"DB_CHECK_SCHEMA_VERSION": "false",
+				"DB_MIGRATION_WAIT":       "0s",
+			},
+			wantErr: false,
+			check: func(c *Config) bool {
+				return !c.DBCheckSchemaVersion
+			},
+		},
+		{
+			name: "fails with negative slow query threshold",
+			envVars: map[string]string{
+				"DATABASE_URL":            "postgres://localhost/test",
+				"API_KEY_SECRET":          "secret123",
+				"DB_SLOW_QUERY_THRESHOLD": "-1s",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "fails with negative clock skew leeway",
+			envVars: map[string]string{
+				"DATABASE_URL":      "postgres://localhost/test",
+				"API_KEY_SECRET":    "secret123",
+				"CLOCK_SKEW_LEEWAY": "-5s",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "fails with negative widget session rate limit",
+			envVars: map[string]string{
+				"DATABASE_URL":              "postgres://localhost/test",
+				"API_KEY_SECRET":            "secret123",
+				"WIDGET_SESSION_RATE_LIMIT": "-1",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "allows disabling the widget session rate limit",
+			envVars: map[string]string{
+				"DATABASE_URL":              "postgres://localhost/test",
+				"API_KEY_SECRET":            "secret123",
+				"WIDGET_SESSION_RATE_LIMIT": "0",
+			},
+			wantErr: false,
+			check: func(c *Config) bool {
+				return c.WidgetSessionRateLimit == 0
+			},
+		},
+		{
+			name: "fails with non-positive max metadata bytes",
+			envVars: map[string]string{
+				"DATABASE_URL":       "postgres://localhost/test",
+				"API_KEY_SECRET":     "secret123",
+				"MAX_METADATA_BYTES": "0",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "fails with non-positive embedding max magnitude",
+			envVars: map[string]string{
+				"DATABASE_URL":            "postgres://localhost/test",
+				"API_KEY_SECRET":          "secret123",
+				"EMBEDDING_MAX_MAGNITUDE": "0",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "fails with non-positive usage flush interval",
+			envVars: map[string]string{
+				"DATABASE_URL":         "postgres://localhost/test",
+				"API_KEY_SECRET":       "secret123",
+				"USAGE_FLUSH_INTERVAL": "0s",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "fails with negative image max dimension",
+			envVars: map[string]string{
+				"DATABASE_URL":        "postgres://localhost/test",
+				"API_KEY_SECRET":      "secret123",
+				"IMAGE_MAX_DIMENSION": "-1",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "fails with negative image blank threshold",
+			envVars: map[string]string{
+				"DATABASE_URL":          "postgres://localhost/test",
+				"API_KEY_SECRET":        "secret123",
+				"IMAGE_BLANK_THRESHOLD": "-1",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "fails with negative face expiry interval",
+			envVars: map[string]string{
+				"DATABASE_URL":         "postgres://localhost/test",
+				"API_KEY_SECRET":       "secret123",
+				"FACE_EXPIRY_INTERVAL": "-1m",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "fails with negative provider error retention",
+			envVars: map[string]string{
+				"DATABASE_URL":             "postgres://localhost/test",
+				"API_KEY_SECRET":           "secret123",
+				"PROVIDER_ERROR_RETENTION": "-1h",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "loads separate liveness provider",
+			envVars: map[string]string{
+				"DATABASE_URL":          "postgres://localhost/test",
+				"API_KEY_SECRET":        "secret123",
+				"LIVENESS_PROVIDER":     "deepface",
+				"LIVENESS_DEEPFACE_URL": "http://antispoof:5000",
+			},
+			wantErr: false,
+			check: func(c *Config) bool {
+				return c.LivenessProvider == "deepface" && c.LivenessDeepFaceURL == "http://antispoof:5000"
+			},
+		},
+		{
+			name: "fails with invalid liveness provider",
+			envVars: map[string]string{
+				"DATABASE_URL":      "postgres://localhost/test",
+				"API_KEY_SECRET":    "secret123",
+				"LIVENESS_PROVIDER": "magic",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "fails with non-positive max verification age",
+			envVars: map[string]string{
+				"DATABASE_URL":         "postgres://localhost/test",
+				"API_KEY_SECRET":       "secret123",
+				"MAX_VERIFICATION_AGE": "0s",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "loads per-operation provider timeouts",
+			envVars: map[string]string{
+				"DATABASE_URL":            "postgres://localhost/test",
+				"API_KEY_SECRET":          "secret123",
+				"PROVIDER_TIMEOUT":        "5s",
+				"PROVIDER_TIMEOUT_SEARCH": "12s",
+				"PROVIDER_TIMEOUT_DETECT": "2s",
+			},
+			wantErr: false,
+			check: func(c *Config) bool {
+				return c.ProviderTimeout == 5*time.Second &&
+					c.ProviderTimeoutSearch == 12*time.Second &&
+					c.ProviderTimeoutDetect == 2*time.Second &&
+					c.ProviderTimeoutVerify == 0 &&
+					c.MaxProviderTimeout() == 12*time.Second
+			},
+		},
+		{
+			name: "fails with non-positive provider timeout",
+			envVars: map[string]string{
+				"DATABASE_URL":     "postgres://localhost/test",
+				"API_KEY_SECRET":   "secret123",
+				"PROVIDER_TIMEOUT": "0s",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "fails with negative per-operation provider timeout",
+			envVars: map[string]string{
+				"DATABASE_URL":            "postgres://localhost/test",
+				"API_KEY_SECRET":          "secret123",
+				"PROVIDER_TIMEOUT_VERIFY": "-1s",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "fails with non-positive max request timeout",
+			envVars: map[string]string{
+				"DATABASE_URL":        "postgres://localhost/test",
+				"API_KEY_SECRET":      "secret123",
+				"MAX_REQUEST_TIMEOUT": "0s",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "fails with negative face stats refresh threshold",
+			envVars: map[string]string{
+				"DATABASE_URL":                 "postgres://localhost/test",
+				"API_KEY_SECRET":               "secret123",
+				"FACE_STATS_REFRESH_THRESHOLD": "-1",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "fails with non-positive face stats refresh debounce when enabled",
+			envVars: map[string]string{
+				"DATABASE_URL":                 "postgres://localhost/test",
+				"API_KEY_SECRET":               "secret123",
+				"FACE_STATS_REFRESH_THRESHOLD": "500",
+				"FACE_STATS_REFRESH_DEBOUNCE":  "0s",
+			},
+			wantErr: true,
+			check:   nil,
+		},
 		{
 			name: "fails when DATABASE_URL missing",
 			envVars: map[string]string{
@@ -54,6 +487,41 @@ func TestLoad(t *testing.T) {
 			wantErr: true,
 			check:   nil,
 		},
+		{
+			name: "fails when data region differs from rekognition region",
+			envVars: map[string]string{
+				"DATABASE_URL":   "postgres://localhost/test",
+				"API_KEY_SECRET": "secret123",
+				"FACE_PROVIDER":  "rekognition",
+				"AWS_REGION":     "us-east-1",
+				"DATA_REGION":    "eu-west-1",
+			},
+			wantErr: true,
+			check:   nil,
+		},
+		{
+			name: "loads shadow provider",
+			envVars: map[string]string{
+				"DATABASE_URL":         "postgres://localhost/test",
+				"API_KEY_SECRET":       "secret123",
+				"SHADOW_FACE_PROVIDER": "deepface",
+				"SHADOW_DEEPFACE_URL":  "http://deepface-next:5000",
+			},
+			wantErr: false,
+			check: func(c *Config) bool {
+				return c.ShadowFaceProvider == "deepface" && c.ShadowDeepFaceURL == "http://deepface-next:5000"
+			},
+		},
+		{
+			name: "fails with unknown shadow provider",
+			envVars: map[string]string{
+				"DATABASE_URL":         "postgres://localhost/test",
+				"API_KEY_SECRET":       "secret123",
+				"SHADOW_FACE_PROVIDER": "azure",
+			},
+			wantErr: true,
+			check:   nil,
+		},
 		{
 			name: "fails when API_KEY_SECRET missing",
 			envVars: map[string]string{
@@ -69,6 +537,13 @@ func TestLoad(t *testing.T) {
 			// Clear environment
 			os.Clearenv()
 
+			// Every case needs a usable admin JWT secret unless it sets its own
+			if _, ok := tt.envVars["ADMIN_JWT_SECRET"]; !ok {
+				if err := os.Setenv("ADMIN_JWT_SECRET", "0b7c1f9e4d2a8b6c3e5f7a9d1c3b5e7f"); err != nil {
+					t.Fatalf("failed to set env var ADMIN_JWT_SECRET: %v", err)
+				}
+			}
+
 			// Set test environment variables
 			for k, v := range tt.envVars {
 				if err := os.Setenv(k, v); err != nil {
@@ -97,6 +572,26 @@ func TestLoad(t *testing.T) {
 	}
 }
 
+func TestConfig_EffectiveDataRegion(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{"explicit region", Config{FaceProvider: "deepface", DataRegion: "sa-east-1"}, "sa-east-1"},
+		{"rekognition falls back to aws region", Config{FaceProvider: "rekognition", AWSRegion: "eu-west-1"}, "eu-west-1"},
+		{"deepface without region is unknown", Config{FaceProvider: "deepface", AWSRegion: "us-east-1"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.EffectiveDataRegion(); got != tt.want {
+				t.Errorf("EffectiveDataRegion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestConfig_IsDevelopment(t *testing.T) {
 	tests := []struct {
 		name string
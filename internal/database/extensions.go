@@ -0,0 +1,79 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// RequiredExtensions are the PostgreSQL extensions Rekko cannot run without:
+// uuid-ossp generates primary keys and vector (pgvector) stores and searches embeddings
+var RequiredExtensions = []string{"uuid-ossp", "vector"}
+
+// ExtensionQuerier is the query surface needed to inspect installed extensions
+type ExtensionQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// MissingExtensionsError reports required extensions that are not installed in the database
+type MissingExtensionsError struct {
+	Missing []string
+}
+
+func (e *MissingExtensionsError) Error() string {
+	creates := make([]string, len(e.Missing))
+	for i, name := range e.Missing {
+		creates[i] = fmt.Sprintf("CREATE EXTENSION IF NOT EXISTS %q;", name)
+	}
+	return fmt.Sprintf(
+		"missing PostgreSQL extensions: %s (install them on the server, e.g. the pgvector package for \"vector\", then run %s as a superuser or apply the migrations)",
+		strings.Join(e.Missing, ", "),
+		strings.Join(creates, " "),
+	)
+}
+
+// ExtensionStatus reports whether each required extension is installed
+func ExtensionStatus(ctx context.Context, db ExtensionQuerier) (map[string]bool, error) {
+	rows, err := db.Query(ctx, `SELECT extname FROM pg_extension WHERE extname = ANY($1)`, RequiredExtensions)
+	if err != nil {
+		return nil, fmt.Errorf("query extensions: %w", err)
+	}
+	installed, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("scan extensions: %w", err)
+	}
+
+	status := make(map[string]bool, len(RequiredExtensions))
+	for _, name := range RequiredExtensions {
+		status[name] = false
+	}
+	for _, name := range installed {
+		status[name] = true
+	}
+	return status, nil
+}
+
+// CheckExtensions returns a *MissingExtensionsError when a required extension is not installed
+func CheckExtensions(ctx context.Context, db ExtensionQuerier) error {
+	status, err := ExtensionStatus(ctx, db)
+	if err != nil {
+		return err
+	}
+	return RequireExtensions(status)
+}
+
+// RequireExtensions returns a *MissingExtensionsError naming the extensions status reports as missing
+func RequireExtensions(status map[string]bool) error {
+	var missing []string
+	for _, name := range RequiredExtensions {
+		if !status[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return &MissingExtensionsError{Missing: missing}
+	}
+	return nil
+}
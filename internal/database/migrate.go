@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"embed"
 	"errors"
@@ -14,9 +15,11 @@ import (
 //go:embed migrations/*.sql
 var migrationsFS embed.FS
 
-// Migrator handles database migrations
+// Migrator handles database migrations. Up, Down and Force hold MigrationLockID exclusively,
+// so API instances starting meanwhile wait for the schema change to finish.
 type Migrator struct {
-	m *migrate.Migrate
+	m  *migrate.Migrate
+	db *sql.DB
 }
 
 // NewMigrator creates a migrator instance
@@ -38,12 +41,12 @@ func NewMigrator(db *sql.DB, dbName string) (*Migrator, error) {
 		return nil, fmt.Errorf("create migrator: %w", err)
 	}
 
-	return &Migrator{m: m}, nil
+	return &Migrator{m: m, db: db}, nil
 }
 
 // Up runs all pending migrations
 func (m *Migrator) Up() error {
-	err := m.m.Up()
+	err := m.locked(m.m.Up)
 	if errors.Is(err, migrate.ErrNoChange) {
 		return nil
 	}
@@ -55,7 +58,7 @@ func (m *Migrator) Up() error {
 
 // Down rolls back the last migration (DEV ONLY)
 func (m *Migrator) Down() error {
-	if err := m.m.Steps(-1); err != nil {
+	if err := m.locked(func() error { return m.m.Steps(-1) }); err != nil {
 		return fmt.Errorf("rollback migration: %w", err)
 	}
 	return nil
@@ -73,14 +76,50 @@ func (m *Migrator) Version() (uint, bool, error) {
 	return version, dirty, nil
 }
 
+// ExpectedVersion returns the version of the latest migration embedded in the binary
+func (m *Migrator) ExpectedVersion() (uint, error) {
+	return ExpectedVersion()
+}
+
+// CheckVersion returns a *SchemaVersionError unless the database is clean and at ExpectedVersion,
+// or ahead of it when not strict
+func (m *Migrator) CheckVersion(strict bool) error {
+	expected, err := m.ExpectedVersion()
+	if err != nil {
+		return err
+	}
+	current, dirty, err := m.Version()
+	if err != nil {
+		return err
+	}
+	return CheckVersion(current, dirty, expected, strict)
+}
+
 // Force sets the migration version without running migrations (DANGEROUS)
 func (m *Migrator) Force(version int) error {
-	if err := m.m.Force(version); err != nil {
+	if err := m.locked(func() error { return m.m.Force(version) }); err != nil {
 		return fmt.Errorf("force version: %w", err)
 	}
 	return nil
 }
 
+// locked runs fn holding MigrationLockID on a dedicated connection, as session locks belong to one
+func (m *Migrator) locked(fn func() error) error {
+	ctx := context.Background()
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("get lock connection: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, MigrationLockID); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer func() { _, _ = conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, MigrationLockID) }()
+
+	return fn()
+}
+
 // Close closes the migrator
 func (m *Migrator) Close() error {
 	srcErr, dbErr := m.m.Close()
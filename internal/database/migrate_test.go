@@ -65,6 +65,15 @@ func TestMigratorIntegration(t *testing.T) {
 		assert.Equal(t, uint(1), version, "should be at version 1")
 	})
 
+	t.Run("CheckVersion passes once all migrations are applied", func(t *testing.T) {
+		migrator, err := database.NewMigrator(db, "rekko_test")
+		require.NoError(t, err)
+		defer func() { _ = migrator.Close() }()
+
+		require.NoError(t, migrator.Up())
+		require.NoError(t, migrator.CheckVersion(true))
+	})
+
 	t.Run("Schema validation after migration", func(t *testing.T) {
 		// Test tenants table schema
 		t.Run("tenants table has correct columns", func(t *testing.T) {
@@ -0,0 +1,6 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_search_audits_created_id;
+DROP INDEX IF EXISTS idx_verifications_created_id;
+
+-- Drop table
+DROP TABLE IF EXISTS audit_export_watermarks;
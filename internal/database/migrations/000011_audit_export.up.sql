@@ -0,0 +1,16 @@
+-- High-water marks for scheduled audit export to object storage
+CREATE TABLE IF NOT EXISTS audit_export_watermarks (
+    source VARCHAR(50) PRIMARY KEY,
+    last_created_at TIMESTAMPTZ NOT NULL,
+    last_id UUID NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Keyset pagination indexes for export (created_at, id)
+CREATE INDEX IF NOT EXISTS idx_verifications_created_id ON verifications(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_search_audits_created_id ON search_audits(created_at, id);
+
+COMMENT ON TABLE audit_export_watermarks IS 'Last exported row per audit source for incremental NDJSON export';
+COMMENT ON COLUMN audit_export_watermarks.source IS 'Audit source: verifications, search_audits';
+COMMENT ON COLUMN audit_export_watermarks.last_created_at IS 'created_at of the last exported row';
+COMMENT ON COLUMN audit_export_watermarks.last_id IS 'id of the last exported row (tie-breaker for equal created_at)';
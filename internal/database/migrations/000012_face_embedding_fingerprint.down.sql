@@ -0,0 +1,2 @@
+ALTER TABLE faces DROP COLUMN IF EXISTS embedding_version;
+ALTER TABLE faces DROP COLUMN IF EXISTS embedding_model;
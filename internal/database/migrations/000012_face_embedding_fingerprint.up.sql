@@ -0,0 +1,6 @@
+-- Embedding fingerprint: which provider model and dimension produced each face embedding
+ALTER TABLE faces ADD COLUMN IF NOT EXISTS embedding_model VARCHAR(100);
+ALTER TABLE faces ADD COLUMN IF NOT EXISTS embedding_version VARCHAR(50);
+
+COMMENT ON COLUMN faces.embedding_model IS 'Provider and model that produced the embedding, e.g. deepface/Facenet512 (NULL for faces registered before fingerprinting)';
+COMMENT ON COLUMN faces.embedding_version IS 'Embedding dimension, e.g. 512d (NULL for faces registered before fingerprinting)';
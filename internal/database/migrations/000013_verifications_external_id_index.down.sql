@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_verifications_tenant_external_created;
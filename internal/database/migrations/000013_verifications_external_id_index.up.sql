@@ -0,0 +1,2 @@
+-- Per external_id verification trend lookups (admin faces trend endpoint)
+CREATE INDEX IF NOT EXISTS idx_verifications_tenant_external_created ON verifications(tenant_id, external_id, created_at);
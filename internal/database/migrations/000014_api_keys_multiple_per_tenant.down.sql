@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_api_keys_tenant_active;
+DROP INDEX IF EXISTS idx_api_keys_tenant_id;
+
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,19 @@
+-- API keys: a tenant may hold several active keys (one per integration), each with a human label
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    key_hash VARCHAR(255) NOT NULL UNIQUE,
+    key_prefix VARCHAR(20) NOT NULL,
+    environment VARCHAR(10) NOT NULL DEFAULT 'test',
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    last_used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- No uniqueness on tenant_id: multiple keys per tenant may be active at the same time
+CREATE INDEX IF NOT EXISTS idx_api_keys_tenant_id ON api_keys(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_api_keys_tenant_active ON api_keys(tenant_id, is_active);
+
+COMMENT ON COLUMN api_keys.name IS 'Human label for the integration using the key, e.g. mobile app, gate, backend';
+COMMENT ON COLUMN api_keys.key_hash IS 'SHA256 of the plaintext key (plaintext is shown only once at creation)';
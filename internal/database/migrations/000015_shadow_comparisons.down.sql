@@ -0,0 +1,2 @@
+-- Remove shadow comparisons table
+DROP TABLE IF EXISTS shadow_comparisons;
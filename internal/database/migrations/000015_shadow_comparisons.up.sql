@@ -0,0 +1,34 @@
+-- Decision deltas between the primary provider and an opt-in shadow provider
+-- Used to evaluate a provider switch on real traffic; does not store biometric data
+
+CREATE TABLE IF NOT EXISTS shadow_comparisons (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    operation VARCHAR(20) NOT NULL,
+    external_id VARCHAR(255),
+    primary_match BOOLEAN NOT NULL,
+    primary_score DECIMAL(5,4),
+    primary_top_match VARCHAR(255),
+    primary_latency_ms INTEGER NOT NULL,
+    shadow_match BOOLEAN,
+    shadow_score DECIMAL(5,4),
+    shadow_top_match VARCHAR(255),
+    shadow_latency_ms INTEGER NOT NULL,
+    shadow_error TEXT,
+    decision_differs BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT valid_shadow_operation CHECK (operation IN ('verify', 'search')),
+    CONSTRAINT valid_primary_latency CHECK (primary_latency_ms >= 0),
+    CONSTRAINT valid_shadow_latency CHECK (shadow_latency_ms >= 0)
+);
+
+CREATE INDEX idx_shadow_comparisons_tenant_created ON shadow_comparisons(tenant_id, created_at DESC);
+
+-- Partial index for reviewing disagreements
+CREATE INDEX idx_shadow_comparisons_differs ON shadow_comparisons(tenant_id, created_at DESC)
+WHERE decision_differs;
+
+COMMENT ON TABLE shadow_comparisons IS 'Primary vs shadow provider decisions for verify/search - does not store biometric data';
+COMMENT ON COLUMN shadow_comparisons.shadow_match IS 'Shadow decision (NULL when the shadow provider failed, see shadow_error)';
+COMMENT ON COLUMN shadow_comparisons.decision_differs IS 'Shadow reached a different match decision or top match than the primary';
@@ -0,0 +1,4 @@
+-- Remove the structured security_levels block; the flat keys remain authoritative
+UPDATE tenants
+SET settings = settings - 'security_levels'
+WHERE settings ? 'security_levels';
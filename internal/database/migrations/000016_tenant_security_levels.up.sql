@@ -0,0 +1,19 @@
+-- Backfill the structured security_levels settings block from the flat threshold keys
+-- Each tenant gets an entry for its current security level; flat keys are kept for older readers
+
+UPDATE tenants
+SET settings = jsonb_set(
+    settings,
+    '{security_levels}',
+    jsonb_build_object(
+        COALESCE(settings->>'security_level', 'standard'),
+        jsonb_strip_nulls(jsonb_build_object(
+            'verify_threshold', settings->'verification_threshold',
+            'search_threshold', settings->'search_threshold',
+            'require_liveness', settings->'require_liveness',
+            'min_quality', settings->'min_quality'
+        ))
+    )
+)
+WHERE NOT settings ? 'security_levels'
+  AND settings ?| ARRAY['verification_threshold', 'search_threshold', 'require_liveness', 'min_quality'];
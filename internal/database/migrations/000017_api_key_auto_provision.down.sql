@@ -0,0 +1,6 @@
+-- Unbound self-serve keys cannot exist without the column; remove them before restoring NOT NULL
+DELETE FROM api_keys WHERE tenant_id IS NULL;
+
+ALTER TABLE api_keys DROP CONSTRAINT IF EXISTS api_keys_tenant_or_auto_provision;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS auto_provision;
+ALTER TABLE api_keys ALTER COLUMN tenant_id SET NOT NULL;
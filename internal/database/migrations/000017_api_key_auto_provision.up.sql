@@ -0,0 +1,11 @@
+-- Self-serve signups: API keys can be pre-issued without a tenant and flagged for
+-- auto-provisioning; the tenant is created and bound on the key's first request
+
+ALTER TABLE api_keys ALTER COLUMN tenant_id DROP NOT NULL;
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS auto_provision BOOLEAN NOT NULL DEFAULT false;
+
+-- Only keys explicitly flagged for provisioning may exist without a tenant
+ALTER TABLE api_keys ADD CONSTRAINT api_keys_tenant_or_auto_provision
+    CHECK (tenant_id IS NOT NULL OR auto_provision);
+
+COMMENT ON COLUMN api_keys.auto_provision IS 'Pre-issued self-serve key; tenant is created on first use when AUTO_PROVISION_TENANTS is enabled';
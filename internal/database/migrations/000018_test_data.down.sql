@@ -0,0 +1,3 @@
+ALTER TABLE search_audits DROP COLUMN IF EXISTS is_test;
+ALTER TABLE verifications DROP COLUMN IF EXISTS is_test;
+ALTER TABLE faces DROP COLUMN IF EXISTS is_test;
@@ -0,0 +1,10 @@
+-- Requests authenticated with test-environment API keys run against the mock provider;
+-- their records are tagged so admin metrics can exclude them
+
+ALTER TABLE faces ADD COLUMN IF NOT EXISTS is_test BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE verifications ADD COLUMN IF NOT EXISTS is_test BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE search_audits ADD COLUMN IF NOT EXISTS is_test BOOLEAN NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN faces.is_test IS 'Registered with a test-environment API key';
+COMMENT ON COLUMN verifications.is_test IS 'Performed with a test-environment API key';
+COMMENT ON COLUMN search_audits.is_test IS 'Performed with a test-environment API key';
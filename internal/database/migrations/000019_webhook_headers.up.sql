@@ -0,0 +1,6 @@
+-- Static headers sent with every delivery of a webhook, for consumer gateways that
+-- require API keys or routing tags on inbound requests
+
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS headers JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+COMMENT ON COLUMN webhooks.headers IS 'Custom request headers (name -> value) added to each delivery';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_verifications_tenant_created_ip;
+ALTER TABLE verifications DROP COLUMN IF EXISTS client_ip;
@@ -0,0 +1,7 @@
+-- Venues run one device per gate, so the caller IP identifies the gate of each verification
+
+ALTER TABLE verifications ADD COLUMN IF NOT EXISTS client_ip INET;
+
+CREATE INDEX IF NOT EXISTS idx_verifications_tenant_created_ip ON verifications(tenant_id, created_at, client_ip);
+
+COMMENT ON COLUMN verifications.client_ip IS 'IP of the API client that requested the verification (the gate)';
@@ -0,0 +1,2 @@
+ALTER TABLE verifications DROP CONSTRAINT IF EXISTS verifications_fail_reason_check;
+ALTER TABLE verifications DROP COLUMN IF EXISTS fail_reason;
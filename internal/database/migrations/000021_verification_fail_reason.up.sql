@@ -0,0 +1,8 @@
+-- Why a verification failed; NULL for verified attempts and for rows recorded before this column
+
+ALTER TABLE verifications ADD COLUMN IF NOT EXISTS fail_reason VARCHAR(32);
+
+ALTER TABLE verifications ADD CONSTRAINT verifications_fail_reason_check
+    CHECK (fail_reason IN ('match_below_threshold', 'liveness_failed', 'no_face', 'multiple_faces'));
+
+COMMENT ON COLUMN verifications.fail_reason IS 'match_below_threshold, liveness_failed, no_face or multiple_faces; NULL when verified';
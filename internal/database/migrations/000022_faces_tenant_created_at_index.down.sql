@@ -0,0 +1,2 @@
+-- Remove the registration date index of faces
+DROP INDEX CONCURRENTLY IF EXISTS idx_faces_tenant_created_at;
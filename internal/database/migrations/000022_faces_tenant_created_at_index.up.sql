@@ -0,0 +1,5 @@
+-- Serves listing a tenant's faces by registration date (GET /v1/faces?registered_from=&registered_to=),
+-- most recent first
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_faces_tenant_created_at
+ON faces (tenant_id, created_at DESC);
@@ -0,0 +1,6 @@
+-- Per-event sampling of webhook deliveries, so busy gates can send 1 in N successful
+-- verifications while failures are always delivered
+
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS sample_rates JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+COMMENT ON COLUMN webhooks.sample_rates IS 'Event type -> N: deliver 1 in N successful events; failures are never sampled out';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_faces_expires_at;
+ALTER TABLE faces DROP COLUMN IF EXISTS expires_at;
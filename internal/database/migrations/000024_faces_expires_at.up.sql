@@ -0,0 +1,11 @@
+-- Optional expiry of registered faces, e.g. for temporary events: faces past expires_at are
+-- deleted by the expiry sweep
+
+ALTER TABLE faces ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN faces.expires_at IS 'Set for faces registered with a TTL; NULL never expires';
+
+-- Only faces with a TTL are indexed, so the sweep stays cheap on tenants that never use it
+CREATE INDEX IF NOT EXISTS idx_faces_expires_at
+ON faces (expires_at)
+WHERE expires_at IS NOT NULL;
@@ -0,0 +1,7 @@
+-- Liveness score of each verification that ran a liveness check, so liveness effectiveness can
+-- be monitored apart from match outcomes; NULL when the verify policy skipped liveness and for
+-- rows recorded before this column
+
+ALTER TABLE verifications ADD COLUMN IF NOT EXISTS liveness_score DECIMAL(5,4);
+
+COMMENT ON COLUMN verifications.liveness_score IS 'Provider liveness confidence (0-1); NULL when no liveness check ran';
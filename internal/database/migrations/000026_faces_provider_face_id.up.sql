@@ -0,0 +1,7 @@
+-- Face ID the provider assigned when indexing the face (e.g. the Rekognition FaceId), so admins
+-- can cross-reference a face with the provider console. There is no backfill: existing rows were
+-- never indexed with an ID that was kept, and re-registering a face stores one.
+
+ALTER TABLE faces ADD COLUMN IF NOT EXISTS provider_face_id TEXT;
+
+COMMENT ON COLUMN faces.provider_face_id IS 'Provider-native face ID from IndexFace; NULL for providers that assign none and faces registered before this column';
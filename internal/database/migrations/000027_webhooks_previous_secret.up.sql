@@ -0,0 +1,9 @@
+-- Secret rotation: the replaced signing secret keeps signing deliveries (in
+-- X-Rekko-Signature-Previous) until previous_secret_expires_at, so consumers can switch to the
+-- new secret without rejecting deliveries
+
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS previous_secret VARCHAR(255);
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS previous_secret_expires_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN webhooks.previous_secret IS 'Secret replaced by the last rotation; NULL when never rotated';
+COMMENT ON COLUMN webhooks.previous_secret_expires_at IS 'End of the grace window in which deliveries are also signed with previous_secret';
@@ -0,0 +1 @@
+ALTER TABLE faces DROP COLUMN IF EXISTS embedding_hash;
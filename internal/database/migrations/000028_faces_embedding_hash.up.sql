@@ -0,0 +1,8 @@
+-- Stable hash of a face's biometric content (SHA-256 of the quantized embedding, or of the
+-- provider face ID for providers without embeddings), so clients can tell whether a
+-- re-enrollment changed the biometric without receiving the embedding. There is no backfill:
+-- re-registering a face stores one.
+
+ALTER TABLE faces ADD COLUMN IF NOT EXISTS embedding_hash TEXT;
+
+COMMENT ON COLUMN faces.embedding_hash IS 'Hex SHA-256 of the quantized embedding or provider face ID; NULL for faces registered before this column';
@@ -0,0 +1 @@
+ALTER TABLE verifications DROP COLUMN IF EXISTS provider;
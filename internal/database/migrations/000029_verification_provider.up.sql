@@ -0,0 +1,7 @@
+-- Provider that decided each verification (e.g. deepface, or mock for test-environment keys),
+-- so match and error metrics can be broken down per provider when several are deployed; NULL
+-- when the provider does not advertise its model and for rows recorded before this column
+
+ALTER TABLE verifications ADD COLUMN IF NOT EXISTS provider VARCHAR(64);
+
+COMMENT ON COLUMN verifications.provider IS 'Provider that produced the decision, e.g. deepface; NULL when unknown';
@@ -0,0 +1,2 @@
+ALTER TABLE search_audits DROP COLUMN IF EXISTS provider_variant;
+ALTER TABLE verifications DROP COLUMN IF EXISTS provider_variant;
@@ -0,0 +1,9 @@
+-- Provider experiment arm of each verification and search (primary or alternate), so the
+-- accuracy of an alternate provider decided on a sampled share of live traffic can be compared
+-- with the primary's; NULL when the tenant runs no provider experiment
+
+ALTER TABLE verifications ADD COLUMN IF NOT EXISTS provider_variant VARCHAR(16);
+ALTER TABLE search_audits ADD COLUMN IF NOT EXISTS provider_variant VARCHAR(16);
+
+COMMENT ON COLUMN verifications.provider_variant IS 'Provider experiment arm that decided the verification: primary or alternate; NULL outside experiments';
+COMMENT ON COLUMN search_audits.provider_variant IS 'Provider experiment arm that ran the search: primary or alternate; NULL outside experiments';
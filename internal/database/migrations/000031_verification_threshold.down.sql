@@ -0,0 +1,2 @@
+ALTER TABLE verifications DROP COLUMN IF EXISTS threshold_source;
+ALTER TABLE verifications DROP COLUMN IF EXISTS threshold_applied;
@@ -0,0 +1,9 @@
+-- Threshold each verification was decided against and the layer it was resolved from
+-- (segment, security_level, tenant or default), so an investigation can tell why a score passed
+-- or failed; NULL for attempts rejected before matching and rows recorded before these columns
+
+ALTER TABLE verifications ADD COLUMN IF NOT EXISTS threshold_applied DECIMAL(5,4);
+ALTER TABLE verifications ADD COLUMN IF NOT EXISTS threshold_source VARCHAR(16);
+
+COMMENT ON COLUMN verifications.threshold_applied IS 'Verification threshold the match was decided against; NULL when no match ran';
+COMMENT ON COLUMN verifications.threshold_source IS 'Layer threshold_applied was resolved from: segment, security_level, tenant or default';
@@ -0,0 +1,6 @@
+-- Keep half-precision faces searchable by moving their embedding back to the full column
+UPDATE faces SET embedding = embedding_half::vector(512), embedding_half = NULL
+WHERE embedding IS NULL AND embedding_half IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_faces_embedding_half_hnsw;
+ALTER TABLE faces DROP COLUMN IF EXISTS embedding_half;
@@ -0,0 +1,13 @@
+-- Half-precision embeddings for tenants with embedding_precision "half": 16-bit floats take half
+-- the storage and index size of vector(512), at a small recall cost. A face stores its embedding
+-- in exactly one of embedding or embedding_half. Requires pgvector 0.7+ (halfvec).
+
+ALTER TABLE faces ADD COLUMN IF NOT EXISTS embedding_half halfvec(512);
+
+-- The column is new and empty, so the index builds instantly without CONCURRENTLY
+CREATE INDEX IF NOT EXISTS idx_faces_embedding_half_hnsw
+ON faces USING hnsw (embedding_half halfvec_cosine_ops)
+WITH (m = 16, ef_construction = 64)
+WHERE embedding_half IS NOT NULL;
+
+COMMENT ON COLUMN faces.embedding_half IS 'Half-precision embedding, set instead of embedding for tenants with embedding_precision half';
@@ -0,0 +1,6 @@
+ALTER TABLE usage_daily DROP COLUMN IF EXISTS webhook_deliveries;
+ALTER TABLE usage_daily DROP COLUMN IF EXISTS widget_searches;
+ALTER TABLE usage_daily DROP COLUMN IF EXISTS widget_liveness_checks;
+ALTER TABLE usage_daily DROP COLUMN IF EXISTS widget_registrations;
+ALTER TABLE usage_daily DROP COLUMN IF EXISTS widget_sessions;
+ALTER TABLE usage_daily DROP COLUMN IF EXISTS searches;
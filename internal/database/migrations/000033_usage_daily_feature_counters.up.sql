@@ -0,0 +1,16 @@
+-- Daily counters of the optional features, so super admins can see which ones each tenant uses.
+-- The API already tracked searches and widget calls, but usage_daily had no columns for them.
+
+ALTER TABLE usage_daily ADD COLUMN IF NOT EXISTS searches INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE usage_daily ADD COLUMN IF NOT EXISTS widget_sessions INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE usage_daily ADD COLUMN IF NOT EXISTS widget_registrations INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE usage_daily ADD COLUMN IF NOT EXISTS widget_liveness_checks INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE usage_daily ADD COLUMN IF NOT EXISTS widget_searches INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE usage_daily ADD COLUMN IF NOT EXISTS webhook_deliveries INTEGER NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN usage_daily.searches IS 'Number of 1:N searches (by image or embedding) on this date';
+COMMENT ON COLUMN usage_daily.widget_sessions IS 'Number of widget sessions created on this date';
+COMMENT ON COLUMN usage_daily.widget_registrations IS 'Number of widget self-enrollments on this date';
+COMMENT ON COLUMN usage_daily.widget_liveness_checks IS 'Number of widget liveness checks on this date';
+COMMENT ON COLUMN usage_daily.widget_searches IS 'Number of widget searches on this date';
+COMMENT ON COLUMN usage_daily.webhook_deliveries IS 'Number of webhook deliveries accepted by the endpoint on this date';
@@ -0,0 +1,2 @@
+-- Remove provider errors table
+DROP TABLE IF EXISTS provider_errors;
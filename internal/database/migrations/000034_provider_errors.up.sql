@@ -0,0 +1,18 @@
+-- Failed face provider calls with the provider's raw error code (PROVIDER_ERROR audit events)
+-- Grouped by code so operators can tell outages from throttling; does not store biometric data
+
+CREATE TABLE IF NOT EXISTS provider_errors (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID REFERENCES tenants(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    operation VARCHAR(50) NOT NULL,
+    error_code VARCHAR(100) NOT NULL,
+    error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_provider_errors_created ON provider_errors(created_at DESC);
+
+COMMENT ON TABLE provider_errors IS 'Failed face provider calls recorded from the audit log - does not store biometric data';
+COMMENT ON COLUMN provider_errors.tenant_id IS 'Tenant of the call, NULL when the provider is shared by every tenant';
+COMMENT ON COLUMN provider_errors.error_code IS 'Provider error code, e.g. ThrottlingException or HTTP_503; TIMEOUT, NETWORK_ERROR or UNKNOWN when the provider gave none';
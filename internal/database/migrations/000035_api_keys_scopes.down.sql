@@ -0,0 +1,2 @@
+-- Remove API key scopes
+ALTER TABLE api_keys DROP COLUMN IF EXISTS scopes;
@@ -0,0 +1,4 @@
+-- Scopes grant an API key access to sensitive endpoints beyond the recognition API
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS scopes TEXT[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN api_keys.scopes IS 'Granted scopes, e.g. faces:export; existing keys start with none';
@@ -0,0 +1,2 @@
+-- Remove face enrollment time
+ALTER TABLE faces DROP COLUMN IF EXISTS enrolled_at;
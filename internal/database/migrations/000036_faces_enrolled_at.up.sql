@@ -0,0 +1,11 @@
+-- When the face's current biometric was enrolled. updated_at also moves on metadata-only
+-- updates, so it can't tell verifications of the current enrollment from older ones.
+ALTER TABLE faces ADD COLUMN IF NOT EXISTS enrolled_at TIMESTAMPTZ;
+
+-- Best available value for existing faces
+UPDATE faces SET enrolled_at = updated_at WHERE enrolled_at IS NULL;
+
+ALTER TABLE faces ALTER COLUMN enrolled_at SET DEFAULT NOW();
+ALTER TABLE faces ALTER COLUMN enrolled_at SET NOT NULL;
+
+COMMENT ON COLUMN faces.enrolled_at IS 'Set on registration and re-registration only; metadata updates leave it';
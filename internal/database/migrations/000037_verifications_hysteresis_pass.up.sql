@@ -0,0 +1,6 @@
+-- Marks verified attempts whose match only passed through verify_hysteresis, so a borderline
+-- allow never counts as the recent full pass that admits the next borderline attempt
+
+ALTER TABLE verifications ADD COLUMN IF NOT EXISTS hysteresis_pass BOOLEAN NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN verifications.hysteresis_pass IS 'Match passed below the threshold under verify_hysteresis; such passes do not extend the window';
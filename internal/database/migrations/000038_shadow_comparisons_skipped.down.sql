@@ -0,0 +1 @@
+ALTER TABLE shadow_comparisons DROP COLUMN IF EXISTS shadow_skipped;
@@ -0,0 +1,6 @@
+-- Shadow runs that could not be compared with the primary, e.g. a shadow provider whose
+-- embedding model differs from the one the tenant's faces were enrolled with
+
+ALTER TABLE shadow_comparisons ADD COLUMN IF NOT EXISTS shadow_skipped VARCHAR(50);
+
+COMMENT ON COLUMN shadow_comparisons.shadow_skipped IS 'Reason the shadow decision was not compared (incompatible_model); NULL when compared or failed';
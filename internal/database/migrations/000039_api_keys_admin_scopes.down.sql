@@ -0,0 +1,3 @@
+UPDATE api_keys SET scopes = array_remove(array_remove(scopes, 'admin:read'), 'admin:write');
+
+COMMENT ON COLUMN api_keys.scopes IS 'Granted scopes, e.g. faces:export; existing keys start with none';
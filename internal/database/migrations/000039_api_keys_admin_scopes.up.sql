@@ -0,0 +1,8 @@
+-- The tenant admin API now requires the admin:read / admin:write scopes. Existing secret keys
+-- reached it before, so they keep that access; public (widget) keys never get it
+
+UPDATE api_keys
+SET scopes = ARRAY(SELECT DISTINCT unnest(scopes || ARRAY['admin:read', 'admin:write']::TEXT[]))
+WHERE key_prefix NOT LIKE 'pk\_%';
+
+COMMENT ON COLUMN api_keys.scopes IS 'Granted scopes: admin:read, admin:write, faces:export; secret keys get the admin scopes unless created with an explicit list';
@@ -6,9 +6,44 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
+// PgxPoolConfig defines pgxpool sizing applied on top of the DSN
+type PgxPoolConfig struct {
+	DSN             string
+	MaxConns        int32         // Max open connections
+	MinConns        int32         // Connections kept open when idle
+	MaxConnLifetime time.Duration // Max connection lifetime
+	// Tracer observes every query of the pool, e.g. a *SlowQueryTracer (optional)
+	Tracer pgx.QueryTracer
+}
+
+// NewPgxPool creates a pgx pool with explicit sizing.
+// Connections are opened lazily; callers should Ping to verify connectivity.
+func NewPgxPool(ctx context.Context, cfg PgxPoolConfig) (*pgxpool.Pool, error) {
+	poolCfg, err := pgxpool.ParseConfig(cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("parse database url: %w", err)
+	}
+
+	poolCfg.MaxConns = cfg.MaxConns
+	poolCfg.MinConns = cfg.MinConns
+	poolCfg.MaxConnLifetime = cfg.MaxConnLifetime
+	if cfg.Tracer != nil {
+		poolCfg.ConnConfig.Tracer = cfg.Tracer
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		return nil, fmt.Errorf("create pool: %w", err)
+	}
+
+	return pool, nil
+}
+
 // PoolConfig defines connection pool settings
 type PoolConfig struct {
 	DSN             string
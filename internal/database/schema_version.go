@@ -0,0 +1,119 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// MigrationLockID is the advisory lock the Migrator holds exclusively while it changes the schema.
+// API instances take it shared before reading the schema version, so an instance starting
+// during a migration waits for it to finish instead of seeing a half-applied schema.
+const MigrationLockID int64 = 0x72656b6b6f // "rekko"
+
+// undefinedTable is the PostgreSQL error code for a missing relation
+const undefinedTable = "42P01"
+
+// SchemaVersionQuerier is the surface needed to read the applied schema version under the migration lock
+type SchemaVersionQuerier interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// SchemaVersionError reports a database schema that does not match the migrations built into the binary
+type SchemaVersionError struct {
+	Current  uint
+	Expected uint
+	Dirty    bool
+}
+
+func (e *SchemaVersionError) Error() string {
+	switch {
+	case e.Dirty:
+		return fmt.Sprintf("database schema is dirty at version %d: a migration failed halfway, fix it and run cmd/migrate -action=force", e.Current)
+	case e.Current < e.Expected:
+		return fmt.Sprintf("database schema is at version %d but this build expects %d: run cmd/migrate -action=up", e.Current, e.Expected)
+	default:
+		return fmt.Sprintf("database schema is at version %d, ahead of the %d this build expects: deploy the build that ran the migrations", e.Current, e.Expected)
+	}
+}
+
+// ExpectedVersion returns the version of the latest migration embedded in the binary
+func ExpectedVersion() (uint, error) {
+	source, err := iofs.New(migrationsFS, "migrations")
+	if err != nil {
+		return 0, fmt.Errorf("create migration source: %w", err)
+	}
+	defer func() { _ = source.Close() }()
+
+	version, err := source.First()
+	if err != nil {
+		return 0, fmt.Errorf("read first migration: %w", err)
+	}
+	for {
+		next, err := source.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("read migration after %d: %w", version, err)
+		}
+		version = next
+	}
+}
+
+// CheckVersion returns a *SchemaVersionError unless the schema is clean and at the expected version.
+// A schema ahead of the binary passes unless strict: during a rolling deploy the new build
+// migrates first, and replicas of the previous build must keep starting against it.
+func CheckVersion(current uint, dirty bool, expected uint, strict bool) error {
+	if dirty || current < expected || (strict && current > expected) {
+		return &SchemaVersionError{Current: current, Expected: expected, Dirty: dirty}
+	}
+	return nil
+}
+
+// SchemaVersion returns the applied migration version, waiting for a running migration to
+// finish first. A database that was never migrated is at version 0.
+func SchemaVersion(ctx context.Context, db SchemaVersionQuerier) (uint, bool, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return 0, false, fmt.Errorf("begin schema version read: %w", err)
+	}
+	// Rolling back releases the lock; nothing is written
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock_shared($1)`, MigrationLockID); err != nil {
+		return 0, false, fmt.Errorf("wait for migration lock: %w", err)
+	}
+
+	var version int64
+	var dirty bool
+	err = tx.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.Is(err, pgx.ErrNoRows), errors.As(err, &pgErr) && pgErr.Code == undefinedTable:
+		return 0, false, nil
+	case err != nil:
+		return 0, false, fmt.Errorf("query schema version: %w", err)
+	}
+	return uint(version), dirty, nil
+}
+
+// CheckSchemaVersion returns a *SchemaVersionError when the database schema is dirty or behind
+// the migrations built into the binary, or ahead of them when strict, so an instance never
+// serves against a schema its queries were not written for
+func CheckSchemaVersion(ctx context.Context, db SchemaVersionQuerier, strict bool) error {
+	expected, err := ExpectedVersion()
+	if err != nil {
+		return err
+	}
+	current, dirty, err := SchemaVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+	return CheckVersion(current, dirty, expected, strict)
+}
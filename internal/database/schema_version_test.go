@@ -0,0 +1,175 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/database"
+)
+
+func TestExpectedVersion(t *testing.T) {
+	entries, err := os.ReadDir("migrations")
+	require.NoError(t, err)
+	var ups uint
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".up.sql") {
+			ups++
+		}
+	}
+
+	version, err := database.ExpectedVersion()
+	require.NoError(t, err)
+	// Migrations are numbered from 1 without gaps
+	assert.Equal(t, ups, version)
+}
+
+func TestCheckVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  uint
+		dirty    bool
+		expected uint
+		strict   bool
+		wantErr  string
+	}{
+		{name: "matches", current: 34, expected: 34},
+		{name: "behind", current: 33, expected: 34, wantErr: "run cmd/migrate -action=up"},
+		{name: "ahead", current: 35, expected: 34},
+		{name: "ahead when strict", current: 35, expected: 34, strict: true, wantErr: "ahead of the 34"},
+		{name: "never migrated", current: 0, expected: 34, wantErr: "at version 0"},
+		{name: "dirty", current: 34, dirty: true, expected: 34, wantErr: "dirty at version 34"},
+		{name: "dirty ahead", current: 35, dirty: true, expected: 34, wantErr: "dirty at version 35"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := database.CheckVersion(tt.current, tt.dirty, tt.expected, tt.strict)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			var skewErr *database.SchemaVersionError
+			require.ErrorAs(t, err, &skewErr)
+			assert.Equal(t, tt.current, skewErr.Current)
+			assert.Equal(t, tt.expected, skewErr.Expected)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestCheckSchemaVersion(t *testing.T) {
+	expected, err := database.ExpectedVersion()
+	require.NoError(t, err)
+
+	expectLockedRead := func(mock pgxmock.PgxPoolIface) {
+		mock.ExpectBegin()
+		mock.ExpectExec("SELECT pg_advisory_xact_lock_shared").
+			WithArgs(database.MigrationLockID).
+			WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	}
+
+	tests := []struct {
+		name      string
+		setupMock func(mock pgxmock.PgxPoolIface)
+		strict    bool
+		wantSkew  *database.SchemaVersionError
+	}{
+		{
+			name: "schema matches the binary",
+			setupMock: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").
+					WillReturnRows(pgxmock.NewRows([]string{"version", "dirty"}).AddRow(int64(expected), false))
+			},
+		},
+		{
+			name: "schema behind the binary",
+			setupMock: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").
+					WillReturnRows(pgxmock.NewRows([]string{"version", "dirty"}).AddRow(int64(expected-1), false))
+			},
+			wantSkew: &database.SchemaVersionError{Current: expected - 1, Expected: expected},
+		},
+		{
+			name: "schema ahead of the binary during a rolling deploy",
+			setupMock: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").
+					WillReturnRows(pgxmock.NewRows([]string{"version", "dirty"}).AddRow(int64(expected+1), false))
+			},
+		},
+		{
+			name: "schema ahead of the binary in strict mode",
+			setupMock: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").
+					WillReturnRows(pgxmock.NewRows([]string{"version", "dirty"}).AddRow(int64(expected+1), false))
+			},
+			strict:   true,
+			wantSkew: &database.SchemaVersionError{Current: expected + 1, Expected: expected},
+		},
+		{
+			name: "dirty schema",
+			setupMock: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").
+					WillReturnRows(pgxmock.NewRows([]string{"version", "dirty"}).AddRow(int64(expected), true))
+			},
+			wantSkew: &database.SchemaVersionError{Current: expected, Expected: expected, Dirty: true},
+		},
+		{
+			name: "never migrated",
+			setupMock: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").
+					WillReturnError(&pgconn.PgError{Code: "42P01", Message: `relation "schema_migrations" does not exist`})
+			},
+			wantSkew: &database.SchemaVersionError{Current: 0, Expected: expected},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+
+			expectLockedRead(mock)
+			tt.setupMock(mock)
+			mock.ExpectRollback()
+
+			err = database.CheckSchemaVersion(context.Background(), mock, tt.strict)
+
+			if tt.wantSkew == nil {
+				require.NoError(t, err)
+			} else {
+				var skewErr *database.SchemaVersionError
+				require.ErrorAs(t, err, &skewErr)
+				assert.Equal(t, tt.wantSkew, skewErr)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Run("migration lock not acquired", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("SELECT pg_advisory_xact_lock_shared").
+			WithArgs(database.MigrationLockID).
+			WillReturnError(context.DeadlineExceeded)
+		mock.ExpectRollback()
+
+		err = database.CheckSchemaVersion(context.Background(), mock, false)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		var skewErr *database.SchemaVersionError
+		assert.False(t, errors.As(err, &skewErr), "a lock failure is not a version skew")
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
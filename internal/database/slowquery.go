@@ -0,0 +1,123 @@
+package database
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// maxQueryLabelLen bounds labels derived from the SQL text
+const maxQueryLabelLen = 80
+
+type queryLabelKey struct{}
+
+type queryStartKey struct{}
+
+// queryStart is what TraceQueryStart hands to TraceQueryEnd through the context
+type queryStart struct {
+	at    time.Time
+	label string
+}
+
+// WithQueryLabel names the queries run with ctx in slow-query logs and stats
+func WithQueryLabel(ctx context.Context, label string) context.Context {
+	return context.WithValue(ctx, queryLabelKey{}, label)
+}
+
+// SlowQueryTracer is a pgx query tracer that logs, at warn, every query slower than a threshold
+// with its label and duration, and counts them per label. Query arguments are never logged,
+// as they carry tenant data such as external IDs and metadata.
+type SlowQueryTracer struct {
+	threshold time.Duration
+	logger    *slog.Logger
+
+	mu      sync.Mutex
+	total   int64
+	byLabel map[string]int64
+}
+
+// NewSlowQueryTracer creates a tracer that reports queries taking at least threshold
+func NewSlowQueryTracer(threshold time.Duration, logger *slog.Logger) *SlowQueryTracer {
+	return &SlowQueryTracer{
+		threshold: threshold,
+		logger:    logger,
+		byLabel:   make(map[string]int64),
+	}
+}
+
+// TraceQueryStart implements pgx.QueryTracer
+func (t *SlowQueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	label, _ := ctx.Value(queryLabelKey{}).(string)
+	if label == "" {
+		label = sqlLabel(data.SQL)
+	}
+	return context.WithValue(ctx, queryStartKey{}, queryStart{at: time.Now(), label: label})
+}
+
+// TraceQueryEnd implements pgx.QueryTracer
+func (t *SlowQueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+
+	duration := time.Since(start.at)
+	if duration < t.threshold {
+		return
+	}
+
+	t.mu.Lock()
+	t.total++
+	t.byLabel[start.label]++
+	t.mu.Unlock()
+
+	attrs := []any{
+		slog.String("query", start.label),
+		slog.Duration("duration", duration),
+		slog.Duration("threshold", t.threshold),
+	}
+	if data.Err != nil {
+		attrs = append(attrs, slog.Any("error", data.Err))
+	}
+	t.logger.WarnContext(ctx, "slow query", attrs...)
+}
+
+// SlowQueryStats counts the queries that exceeded the threshold since startup
+type SlowQueryStats struct {
+	Threshold time.Duration
+	Total     int64
+	ByLabel   map[string]int64
+}
+
+// Stats returns a snapshot of the slow query counts
+func (t *SlowQueryTracer) Stats() SlowQueryStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	byLabel := make(map[string]int64, len(t.byLabel))
+	for label, n := range t.byLabel {
+		byLabel[label] = n
+	}
+	return SlowQueryStats{Threshold: t.threshold, Total: t.total, ByLabel: byLabel}
+}
+
+// PoolSlowQueryTracer returns the slow query tracer of pool, if it has one
+func PoolSlowQueryTracer(pool *pgxpool.Pool) (*SlowQueryTracer, bool) {
+	tracer, ok := pool.Config().ConnConfig.Tracer.(*SlowQueryTracer)
+	return tracer, ok
+}
+
+// sqlLabel collapses the whitespace of a statement and truncates it, so unlabeled queries are
+// still recognizable. Statements use placeholders, so the text holds no argument values.
+func sqlLabel(sql string) string {
+	label := strings.Join(strings.Fields(sql), " ")
+	if len(label) > maxQueryLabelLen {
+		label = label[:maxQueryLabelLen] + "..."
+	}
+	return label
+}
@@ -1,10 +1,12 @@
 package domain
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"math/big"
 	"strings"
 	"time"
@@ -18,12 +20,51 @@ const (
 	EnvLive = "live"
 )
 
+type testModeKey struct{}
+
+// WithTestMode marks ctx as serving a test-environment API key. Such requests use the
+// mock provider and their records are tagged as test data.
+func WithTestMode(ctx context.Context) context.Context {
+	return context.WithValue(ctx, testModeKey{}, true)
+}
+
+// IsTestMode reports whether ctx was marked with WithTestMode
+func IsTestMode(ctx context.Context) bool {
+	testMode, _ := ctx.Value(testModeKey{}).(bool)
+	return testMode
+}
+
+type clientIPKey struct{}
+
+// WithClientIP records the IP of the API client serving ctx, so records can be
+// attributed to the device (e.g. a venue gate) that made the request
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// ClientIPFrom returns the IP recorded with WithClientIP, or "" when unknown
+func ClientIPFrom(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return ip
+}
+
 // Key type constants
 const (
 	KeyTypeSecret = "sk" // Secret key for server-side API access
 	KeyTypePublic = "pk" // Public key for client-side (widget) access
 )
 
+// Scope constants. A key only reaches the endpoints guarded by a scope it was granted.
+const (
+	ScopeFacesExport = "faces:export" // Bulk export of registered faces and embeddings
+	ScopeAdminRead   = "admin:read"   // GET on /v1/admin: metrics, configuration, stored faces
+	ScopeAdminWrite  = "admin:write"  // Changes through /v1/admin: webhooks, API keys, settings
+)
+
+// DefaultSecretKeyScopes are granted to secret keys created without an explicit scope list,
+// which keeps a new key as capable as the keys issued before admin scopes existed
+var DefaultSecretKeyScopes = []string{ScopeAdminRead, ScopeAdminWrite}
+
 const (
 	apiKeyLength = 32
 	base62Chars  = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
@@ -38,6 +79,11 @@ var (
 		KeyTypeSecret: true,
 		KeyTypePublic: true,
 	}
+	validScopes = map[string]bool{
+		ScopeFacesExport: true,
+		ScopeAdminRead:   true,
+		ScopeAdminWrite:  true,
+	}
 )
 
 // APIKey representa uma chave de API para autenticação
@@ -51,6 +97,50 @@ type APIKey struct {
 	IsActive    bool       `json:"is_active"`
 	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
 	CreatedAt   time.Time  `json:"created_at"`
+
+	// Scopes lists the sensitive endpoints the key may call, e.g. faces:export
+	Scopes []string `json:"scopes"`
+
+	// AutoProvision marks a pre-issued self-serve key whose tenant is created on first use
+	AutoProvision bool `json:"auto_provision,omitempty"`
+}
+
+// HasScope reports whether the key was granted scope
+func (a *APIKey) HasScope(scope string) bool {
+	for _, s := range a.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateScopes rejects scopes the API does not define
+func ValidateScopes(scopes []string) error {
+	for _, s := range scopes {
+		if !validScopes[s] {
+			return fmt.Errorf("invalid scope %q", s)
+		}
+	}
+	return nil
+}
+
+// NeedsProvisioning reports whether the key was pre-issued without a tenant
+func (a *APIKey) NeedsProvisioning() bool {
+	return a.TenantID == uuid.Nil
+}
+
+// NewProvisionedTenant builds the tenant created on first use of a self-serve key.
+// The slug is derived from the key ID so concurrent signups never collide.
+func NewProvisionedTenant(key *APIKey) *Tenant {
+	return &Tenant{
+		ID:       uuid.New(),
+		Name:     key.Name,
+		Slug:     "self-serve-" + key.ID.String(),
+		Plan:     PlanStarter,
+		IsActive: true,
+		Settings: make(map[string]interface{}),
+	}
 }
 
 // GenerateAPIKey gera uma nova API key com hash e prefix
@@ -280,3 +280,42 @@ func TestGenerateAPIKey_Uniqueness(t *testing.T) {
 		keys[plainKey] = true
 	}
 }
+
+func TestNewProvisionedTenant(t *testing.T) {
+	key := &APIKey{ID: uuid.New(), Name: "Acme signup", AutoProvision: true}
+
+	tenant := NewProvisionedTenant(key)
+
+	if err := tenant.Validate(); err != nil {
+		t.Fatalf("provisioned tenant is invalid: %v", err)
+	}
+	if tenant.Name != key.Name {
+		t.Errorf("Name = %q, want %q", tenant.Name, key.Name)
+	}
+	if tenant.Slug != "self-serve-"+key.ID.String() {
+		t.Errorf("Slug = %q, want derived from key ID", tenant.Slug)
+	}
+	if !tenant.IsActive || tenant.Plan != PlanStarter {
+		t.Errorf("tenant should be active on the starter plan, got active=%v plan=%q", tenant.IsActive, tenant.Plan)
+	}
+	if !key.NeedsProvisioning() {
+		t.Error("key without tenant should need provisioning")
+	}
+}
+
+func TestAPIKey_HasScope(t *testing.T) {
+	key := &APIKey{Scopes: []string{ScopeFacesExport}}
+	if !key.HasScope(ScopeFacesExport) {
+		t.Error("key should have the granted scope")
+	}
+	if (&APIKey{}).HasScope(ScopeFacesExport) {
+		t.Error("key without scopes should have none")
+	}
+
+	if err := ValidateScopes([]string{ScopeFacesExport}); err != nil {
+		t.Errorf("ValidateScopes() unexpected error: %v", err)
+	}
+	if err := ValidateScopes([]string{"faces:delete_all"}); err == nil {
+		t.Error("ValidateScopes() should reject an unknown scope")
+	}
+}
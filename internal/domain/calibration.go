@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Bounds of a calibration evaluation; each pair costs provider calls for both images
+const (
+	MaxCalibrationPairs      = 50
+	MaxCalibrationThresholds = 100
+)
+
+// CalibrationPair is two face images labeled with whether they show the same person
+type CalibrationPair struct {
+	ImageA []byte
+	ImageB []byte
+	Match  bool
+}
+
+// CalibrationScore is the similarity the provider gave one labeled pair. Pairs the provider
+// could not score carry an Error and are left out of the rates.
+type CalibrationScore struct {
+	Index      int     `json:"index"`
+	Match      bool    `json:"match"`
+	Similarity float64 `json:"similarity"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// ThresholdRates are the errors a threshold would make on the labeled set. A non-match pair
+// scoring at or above the threshold is a false accept, a match pair below it a false reject.
+type ThresholdRates struct {
+	Threshold       float64 `json:"threshold"`
+	FalseAccepts    int     `json:"false_accepts"`
+	FalseRejects    int     `json:"false_rejects"`
+	FalseAcceptRate float64 `json:"false_accept_rate"`
+	FalseRejectRate float64 `json:"false_reject_rate"`
+}
+
+// CalibrationReport is the outcome of evaluating a labeled set
+type CalibrationReport struct {
+	Pairs         int `json:"pairs"`
+	Evaluated     int `json:"evaluated"`
+	MatchPairs    int `json:"match_pairs"`
+	NonMatchPairs int `json:"non_match_pairs"`
+	// Current is the rates of the tenant's verification threshold
+	Current ThresholdRates     `json:"current"`
+	Sweep   []ThresholdRates   `json:"sweep"`
+	Scores  []CalibrationScore `json:"scores"`
+}
+
+// DefaultCalibrationThresholds sweeps 0.50 to 0.95 in steps of 0.05
+func DefaultCalibrationThresholds() []float64 {
+	thresholds := make([]float64, 0, 10)
+	for i := 50; i <= 95; i += 5 {
+		thresholds = append(thresholds, float64(i)/100)
+	}
+	return thresholds
+}
+
+// NormalizeCalibrationThresholds checks that every threshold is within [0, 1] and returns them
+// sorted without duplicates. An empty list falls back to DefaultCalibrationThresholds.
+func NormalizeCalibrationThresholds(thresholds []float64) ([]float64, error) {
+	if len(thresholds) == 0 {
+		return DefaultCalibrationThresholds(), nil
+	}
+	if len(thresholds) > MaxCalibrationThresholds {
+		return nil, fmt.Errorf("at most %d thresholds are accepted, got %d", MaxCalibrationThresholds, len(thresholds))
+	}
+	for _, t := range thresholds {
+		if math.IsNaN(t) || t < 0 || t > 1 {
+			return nil, fmt.Errorf("threshold %v must be between 0 and 1", t)
+		}
+	}
+
+	sorted := slices.Clone(thresholds)
+	slices.Sort(sorted)
+	return slices.Compact(sorted), nil
+}
+
+// RatesAt computes the false accept and false reject rates of the scored pairs at threshold.
+// A rate is 0 when the set has no pair of that label.
+func RatesAt(scores []CalibrationScore, threshold float64) ThresholdRates {
+	rates := ThresholdRates{Threshold: threshold}
+	var matches, nonMatches int
+	for _, s := range scores {
+		if s.Error != "" {
+			continue
+		}
+		accepted := s.Similarity >= threshold
+		if s.Match {
+			matches++
+			if !accepted {
+				rates.FalseRejects++
+			}
+		} else {
+			nonMatches++
+			if accepted {
+				rates.FalseAccepts++
+			}
+		}
+	}
+
+	if nonMatches > 0 {
+		rates.FalseAcceptRate = float64(rates.FalseAccepts) / float64(nonMatches)
+	}
+	if matches > 0 {
+		rates.FalseRejectRate = float64(rates.FalseRejects) / float64(matches)
+	}
+	return rates
+}
+
+// NewCalibrationReport summarizes the scored pairs with the rates at each threshold of the
+// sweep and at the tenant's current verification threshold
+func NewCalibrationReport(scores []CalibrationScore, thresholds []float64, current float64) *CalibrationReport {
+	report := &CalibrationReport{
+		Pairs:   len(scores),
+		Current: RatesAt(scores, current),
+		Sweep:   make([]ThresholdRates, len(thresholds)),
+		Scores:  scores,
+	}
+	for _, s := range scores {
+		if s.Error != "" {
+			continue
+		}
+		report.Evaluated++
+		if s.Match {
+			report.MatchPairs++
+		} else {
+			report.NonMatchPairs++
+		}
+	}
+	for i, t := range thresholds {
+		report.Sweep[i] = RatesAt(scores, t)
+	}
+	return report
+}
@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+// syntheticScores is a labeled set of 4 match pairs and 4 non-match pairs plus one unscored pair
+func syntheticScores() []CalibrationScore {
+	return []CalibrationScore{
+		{Index: 0, Match: true, Similarity: 0.95},
+		{Index: 1, Match: true, Similarity: 0.88},
+		{Index: 2, Match: true, Similarity: 0.81},
+		{Index: 3, Match: true, Similarity: 0.62},
+		{Index: 4, Match: false, Similarity: 0.85},
+		{Index: 5, Match: false, Similarity: 0.70},
+		{Index: 6, Match: false, Similarity: 0.40},
+		{Index: 7, Match: false, Similarity: 0.10},
+		{Index: 8, Match: true, Error: "no face detected in image_b"},
+	}
+}
+
+func TestRatesAt(t *testing.T) {
+	tests := []struct {
+		threshold    float64
+		falseAccepts int
+		falseRejects int
+		far, frr     float64
+	}{
+		{threshold: 0.5, falseAccepts: 2, falseRejects: 0, far: 0.5, frr: 0},
+		{threshold: 0.7, falseAccepts: 2, falseRejects: 1, far: 0.5, frr: 0.25},
+		{threshold: 0.8, falseAccepts: 1, falseRejects: 1, far: 0.25, frr: 0.25},
+		{threshold: 0.85, falseAccepts: 1, falseRejects: 2, far: 0.25, frr: 0.5},
+		{threshold: 0.9, falseAccepts: 0, falseRejects: 3, far: 0, frr: 0.75},
+		{threshold: 1, falseAccepts: 0, falseRejects: 4, far: 0, frr: 1},
+	}
+
+	for _, tt := range tests {
+		got := RatesAt(syntheticScores(), tt.threshold)
+		if got.FalseAccepts != tt.falseAccepts || got.FalseRejects != tt.falseRejects {
+			t.Errorf("RatesAt(%v) = %d false accepts, %d false rejects, want %d, %d",
+				tt.threshold, got.FalseAccepts, got.FalseRejects, tt.falseAccepts, tt.falseRejects)
+		}
+		if math.Abs(got.FalseAcceptRate-tt.far) > 1e-9 || math.Abs(got.FalseRejectRate-tt.frr) > 1e-9 {
+			t.Errorf("RatesAt(%v) = FAR %v, FRR %v, want %v, %v",
+				tt.threshold, got.FalseAcceptRate, got.FalseRejectRate, tt.far, tt.frr)
+		}
+	}
+}
+
+func TestRatesAt_MissingLabel(t *testing.T) {
+	scores := []CalibrationScore{{Match: true, Similarity: 0.5}}
+
+	got := RatesAt(scores, 0.8)
+	if got.FalseAcceptRate != 0 || got.FalseRejectRate != 1 {
+		t.Errorf("got FAR %v, FRR %v, want 0, 1", got.FalseAcceptRate, got.FalseRejectRate)
+	}
+}
+
+func TestNewCalibrationReport(t *testing.T) {
+	report := NewCalibrationReport(syntheticScores(), []float64{0.7, 0.9}, 0.8)
+
+	if report.Pairs != 9 || report.Evaluated != 8 || report.MatchPairs != 4 || report.NonMatchPairs != 4 {
+		t.Errorf("got pairs=%d evaluated=%d match=%d non_match=%d, want 9, 8, 4, 4",
+			report.Pairs, report.Evaluated, report.MatchPairs, report.NonMatchPairs)
+	}
+	if len(report.Sweep) != 2 || report.Sweep[0].Threshold != 0.7 || report.Sweep[1].Threshold != 0.9 {
+		t.Fatalf("unexpected sweep %+v", report.Sweep)
+	}
+	if report.Current.Threshold != 0.8 || report.Current.FalseAccepts != 1 || report.Current.FalseRejects != 1 {
+		t.Errorf("unexpected current rates %+v", report.Current)
+	}
+}
+
+func TestNormalizeCalibrationThresholds(t *testing.T) {
+	got, err := NormalizeCalibrationThresholds(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 10 || got[0] != 0.5 || got[9] != 0.95 {
+		t.Errorf("default sweep = %v", got)
+	}
+
+	got, err = NormalizeCalibrationThresholds([]float64{0.9, 0.7, 0.9})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0] != 0.7 || got[1] != 0.9 {
+		t.Errorf("got %v, want [0.7 0.9]", got)
+	}
+
+	for _, invalid := range [][]float64{{-0.1}, {1.5}, {math.NaN()}, make([]float64, MaxCalibrationThresholds+1)} {
+		if _, err := NormalizeCalibrationThresholds(invalid); err == nil {
+			t.Errorf("NormalizeCalibrationThresholds(%v) succeeded, want error", invalid)
+		}
+	}
+}
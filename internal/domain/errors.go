@@ -2,13 +2,34 @@ package domain
 
 import (
 	"fmt"
+	"time"
 )
 
 type AppError struct {
-	Code       string `json:"code"`
-	Message    string `json:"message"`
-	StatusCode int    `json:"-"`
-	Err        error  `json:"-"`
+	Code       string   `json:"code"`
+	Message    string   `json:"message"`
+	Reasons    []string `json:"reasons,omitempty"`
+	StatusCode int      `json:"-"`
+	Err        error    `json:"-"`
+	// RateLimit is the quota usage that rejected the request, see WithRateLimit
+	RateLimit *RateLimitUsage `json:"-"`
+	// RetryAfter tells the client when to retry a temporarily refused request, see WithRetryAfter
+	RetryAfter time.Duration `json:"-"`
+	// FaceCount is the face count that rejected an image, see WithFaceCount
+	FaceCount *FaceCountLimit `json:"-"`
+}
+
+// FaceCountLimit describes an image rejected for holding more faces than allowed
+type FaceCountLimit struct {
+	Detected int
+	Allowed  int
+}
+
+// RateLimitUsage describes a quota at the moment it rejected a request
+type RateLimitUsage struct {
+	Limit   int
+	Current int
+	ResetAt time.Time
 }
 
 func (e *AppError) Error() string {
@@ -31,6 +52,38 @@ func (e *AppError) WithError(err error) *AppError {
 	}
 }
 
+// WithReasons returns a copy of the error carrying machine-readable reasons for the client
+func (e *AppError) WithReasons(reasons ...string) *AppError {
+	cp := *e
+	cp.Reasons = reasons
+	return &cp
+}
+
+// WithRateLimit returns a copy of the error carrying the quota usage that rejected the request,
+// which the error handler exposes in the body and in Retry-After/X-RateLimit-* headers
+func (e *AppError) WithRateLimit(limit, current int, resetAt time.Time) *AppError {
+	cp := *e
+	cp.RateLimit = &RateLimitUsage{Limit: limit, Current: current, ResetAt: resetAt}
+	return &cp
+}
+
+// WithRetryAfter returns a copy of the error telling the client to retry after d,
+// which the error handler exposes in the Retry-After header
+func (e *AppError) WithRetryAfter(d time.Duration) *AppError {
+	cp := *e
+	cp.RetryAfter = d
+	return &cp
+}
+
+// WithFaceCount returns a copy of the error telling the client how many faces the image held,
+// which the error handler exposes in the message and body
+func (e *AppError) WithFaceCount(detected, allowed int) *AppError {
+	cp := *e
+	cp.Message = fmt.Sprintf("%d faces detected, only %d allowed", detected, allowed)
+	cp.FaceCount = &FaceCountLimit{Detected: detected, Allowed: allowed}
+	return &cp
+}
+
 // Pre-defined errors
 var (
 	ErrInternal = &AppError{
@@ -69,12 +122,42 @@ var (
 		StatusCode: 404,
 	}
 
+	ErrVerificationNotFound = &AppError{
+		Code:       "VERIFICATION_NOT_FOUND",
+		Message:    "Verification not found",
+		StatusCode: 404,
+	}
+
 	ErrFaceExists = &AppError{
 		Code:       "FACE_ALREADY_EXISTS",
 		Message:    "Face already registered for this external_id",
 		StatusCode: 409,
 	}
 
+	ErrMetadataTooLarge = &AppError{
+		Code:       "METADATA_TOO_LARGE",
+		Message:    "Face metadata exceeds the maximum allowed size",
+		StatusCode: 413,
+	}
+
+	ErrEmbeddingModelMismatch = &AppError{
+		Code:       "EMBEDDING_MODEL_MISMATCH",
+		Message:    "Face was registered with a different embedding model, re-register the face to compare it",
+		StatusCode: 409,
+	}
+
+	ErrEmbeddingUnavailable = &AppError{
+		Code:       "EMBEDDING_UNAVAILABLE",
+		Message:    "Face has no stored embedding to compare, the provider does not expose embeddings",
+		StatusCode: 409,
+	}
+
+	ErrVerifyNotSupportedWithoutSource = &AppError{
+		Code:       "VERIFY_NOT_SUPPORTED_WITHOUT_SOURCE",
+		Message:    "Face has no stored embedding and no source image to verify against, the provider does not expose embeddings",
+		StatusCode: 409,
+	}
+
 	ErrFaceBiometricExists = &AppError{
 		Code:       "FACE_BIOMETRIC_EXISTS",
 		Message:    "This face is already registered with another identity",
@@ -93,6 +176,12 @@ var (
 		StatusCode: 422,
 	}
 
+	ErrNoFaceInDocument = &AppError{
+		Code:       "NO_FACE_IN_DOCUMENT",
+		Message:    "No face detected in the document image",
+		StatusCode: 422,
+	}
+
 	ErrMultipleFaces = &AppError{
 		Code:       "MULTIPLE_FACES",
 		Message:    "Multiple faces detected, please provide image with single face",
@@ -105,12 +194,24 @@ var (
 		StatusCode: 422,
 	}
 
+	ErrFaceOutOfFrame = &AppError{
+		Code:       "FACE_OUT_OF_FRAME",
+		Message:    "Face is cut off at the edge of the image, please recapture with the whole face in frame",
+		StatusCode: 422,
+	}
+
 	ErrLivenessFailed = &AppError{
 		Code:       "LIVENESS_FAILED",
 		Message:    "Liveness check failed, possible spoofing attempt",
 		StatusCode: 422,
 	}
 
+	ErrReplaySuspected = &AppError{
+		Code:       "REPLAY_SUSPECTED",
+		Message:    "Liveness frames are too consistent, possible replay attack",
+		StatusCode: 422,
+	}
+
 	ErrLowLivenessConfidence = &AppError{
 		Code:       "LOW_LIVENESS_CONFIDENCE",
 		Message:    "Liveness confidence too low",
@@ -129,6 +230,24 @@ var (
 		StatusCode: 403,
 	}
 
+	ErrTenantExists = &AppError{
+		Code:       "TENANT_ALREADY_EXISTS",
+		Message:    "Tenant with this slug already exists",
+		StatusCode: 409,
+	}
+
+	ErrTenantSlugConflict = &AppError{
+		Code:       "TENANT_SLUG_CONFLICT",
+		Message:    "Tenant with this slug already exists",
+		StatusCode: 409,
+	}
+
+	ErrAPIKeyExists = &AppError{
+		Code:       "API_KEY_ALREADY_EXISTS",
+		Message:    "API key with this hash already exists",
+		StatusCode: 409,
+	}
+
 	ErrAPIKeyNotFound = &AppError{
 		Code:       "API_KEY_NOT_FOUND",
 		Message:    "API key not found",
@@ -159,7 +278,38 @@ var (
 		StatusCode: 422,
 	}
 
+	ErrRequestTimeout = &AppError{
+		Code:       "REQUEST_TIMEOUT",
+		Message:    "Request did not complete within the client's timeout",
+		StatusCode: 408,
+	}
+
+	// Provider errors
+	ErrProviderAccessDenied = &AppError{
+		Code:       "PROVIDER_ACCESS_DENIED",
+		Message:    "Face provider denied access, check the IAM policy attached to the provider credentials",
+		StatusCode: 502,
+	}
+
+	ErrProviderThrottled = &AppError{
+		Code:       "PROVIDER_THROTTLED",
+		Message:    "Face provider is at capacity, retry shortly",
+		StatusCode: 503,
+	}
+
+	ErrMaintenance = &AppError{
+		Code:       "MAINTENANCE",
+		Message:    "Face recognition is paused for maintenance, retry later",
+		StatusCode: 503,
+	}
+
 	// Search errors
+	ErrAuditWriteFailed = &AppError{
+		Code:       "AUDIT_WRITE_FAILED",
+		Message:    "Search audit could not be recorded, the result was withheld; retry shortly",
+		StatusCode: 503,
+	}
+
 	ErrSearchNotEnabled = &AppError{
 		Code:       "SEARCH_NOT_ENABLED",
 		Message:    "Face search is not enabled for this tenant",
@@ -183,4 +333,36 @@ var (
 		Message:    "Max results must be between 1 and 50",
 		StatusCode: 422,
 	}
+
+	ErrSearchByEmbeddingNotEnabled = &AppError{
+		Code:       "SEARCH_BY_EMBEDDING_NOT_ENABLED",
+		Message:    "Search by embedding is not enabled for this tenant",
+		StatusCode: 403,
+	}
+
+	ErrFaceExportNotEnabled = &AppError{
+		Code:       "FACE_EXPORT_NOT_ENABLED",
+		Message:    "Face export is not enabled for this tenant",
+		StatusCode: 403,
+	}
+
+	ErrInvalidEmbedding = &AppError{
+		Code:       "INVALID_EMBEDDING",
+		Message:    "Embedding must contain 512 finite values with a magnitude within the allowed range",
+		StatusCode: 422,
+	}
+
+	// Compliance errors
+	ErrDataResidencyViolation = &AppError{
+		Code:       "DATA_RESIDENCY_VIOLATION",
+		Message:    "Operation would process biometric data outside the tenant's data region",
+		StatusCode: 403,
+	}
+
+	// Access control errors
+	ErrAntiPassbackViolation = &AppError{
+		Code:       "ANTI_PASSBACK_VIOLATION",
+		Message:    "Already verified at another gate within the anti-passback window",
+		StatusCode: 403,
+	}
 )
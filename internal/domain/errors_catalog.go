@@ -0,0 +1,136 @@
+package domain
+
+// DefaultLocale is the locale of AppError.Message
+const DefaultLocale = "en"
+
+// Catalog lists every predefined error clients can receive, for SDKs that generate typed
+// errors from GET /v1/errors. New errors must be added here and to localizedMessages.
+func Catalog() []*AppError {
+	return []*AppError{
+		ErrInternal,
+		ErrBadRequest,
+		ErrUnauthorized,
+		ErrForbidden,
+		ErrNotFound,
+		ErrFaceNotFound,
+		ErrVerificationNotFound,
+		ErrFaceExists,
+		ErrMetadataTooLarge,
+		ErrEmbeddingModelMismatch,
+		ErrEmbeddingUnavailable,
+		ErrVerifyNotSupportedWithoutSource,
+		ErrFaceBiometricExists,
+		ErrInvalidImage,
+		ErrNoFaceDetected,
+		ErrNoFaceInDocument,
+		ErrMultipleFaces,
+		ErrLowQualityImage,
+		ErrFaceOutOfFrame,
+		ErrLivenessFailed,
+		ErrReplaySuspected,
+		ErrLowLivenessConfidence,
+		ErrTenantNotFound,
+		ErrTenantInactive,
+		ErrTenantExists,
+		ErrTenantSlugConflict,
+		ErrAPIKeyExists,
+		ErrAPIKeyNotFound,
+		ErrAPIKeyRevoked,
+		ErrInvalidAPIKeyFormat,
+		ErrRateLimitExceeded,
+		ErrValidationFailed,
+		ErrRequestTimeout,
+		ErrProviderAccessDenied,
+		ErrProviderThrottled,
+		ErrMaintenance,
+		ErrAuditWriteFailed,
+		ErrSearchNotEnabled,
+		ErrSearchRateLimitExceeded,
+		ErrInvalidThreshold,
+		ErrInvalidMaxResults,
+		ErrSearchByEmbeddingNotEnabled,
+		ErrFaceExportNotEnabled,
+		ErrInvalidEmbedding,
+		ErrDataResidencyViolation,
+		ErrAntiPassbackViolation,
+		ErrWidgetSessionNotFound,
+		ErrWidgetSessionExpired,
+		ErrInvalidPublicKey,
+		ErrOriginNotAllowed,
+		ErrInvalidOrigin,
+		ErrWidgetSessionRateLimitExceeded,
+		ErrWidgetCheckRateLimitExceeded,
+		ErrInvalidCheckSignature,
+	}
+}
+
+// Messages returns the error message in every supported locale, keyed by locale
+func (e *AppError) Messages() map[string]string {
+	messages := map[string]string{DefaultLocale: e.Message}
+	for locale, byCode := range localizedMessages {
+		if msg, ok := byCode[e.Code]; ok {
+			messages[locale] = msg
+		}
+	}
+	return messages
+}
+
+// localizedMessages translates the messages of Catalog by locale and error code
+var localizedMessages = map[string]map[string]string{
+	"pt-BR": {
+		"INTERNAL_ERROR":                      "Ocorreu um erro inesperado",
+		"BAD_REQUEST":                         "Requisição inválida",
+		"UNAUTHORIZED":                        "API key inválida ou ausente",
+		"FORBIDDEN":                           "Acesso negado",
+		"NOT_FOUND":                           "Recurso não encontrado",
+		"FACE_NOT_FOUND":                      "Face não encontrada",
+		"VERIFICATION_NOT_FOUND":              "Verificação não encontrada",
+		"FACE_ALREADY_EXISTS":                 "Já existe uma face cadastrada para este external_id",
+		"METADATA_TOO_LARGE":                  "Os metadados da face excedem o tamanho máximo permitido",
+		"EMBEDDING_MODEL_MISMATCH":            "A face foi cadastrada com outro modelo de embedding, recadastre a face para compará-la",
+		"EMBEDDING_UNAVAILABLE":               "A face não tem embedding armazenado para comparação, o provider não expõe embeddings",
+		"VERIFY_NOT_SUPPORTED_WITHOUT_SOURCE": "A face não tem embedding nem imagem de origem armazenados para verificação, o provider não expõe embeddings",
+		"FACE_BIOMETRIC_EXISTS":               "Esta face já está cadastrada com outra identidade",
+		"INVALID_IMAGE":                       "Formato de imagem inválido ou arquivo corrompido",
+		"NO_FACE_DETECTED":                    "Nenhuma face detectada na imagem",
+		"NO_FACE_IN_DOCUMENT":                 "Nenhuma face detectada na imagem do documento",
+		"MULTIPLE_FACES":                      "Várias faces detectadas, envie uma imagem com uma única face",
+		"LOW_QUALITY_IMAGE":                   "Qualidade da imagem baixa demais para um reconhecimento confiável",
+		"FACE_OUT_OF_FRAME":                   "A face está cortada na borda da imagem, capture novamente com a face inteira no quadro",
+		"LIVENESS_FAILED":                     "Prova de vida falhou, possível tentativa de fraude",
+		"REPLAY_SUSPECTED":                    "Os quadros da prova de vida são consistentes demais, possível ataque de replay",
+		"LOW_LIVENESS_CONFIDENCE":             "Confiança da prova de vida baixa demais",
+		"TENANT_NOT_FOUND":                    "Tenant não encontrado",
+		"TENANT_INACTIVE":                     "A conta do tenant está inativa",
+		"TENANT_ALREADY_EXISTS":               "Já existe um tenant com este slug",
+		"TENANT_SLUG_CONFLICT":                "Já existe um tenant com este slug",
+		"API_KEY_ALREADY_EXISTS":              "Já existe uma API key com este hash",
+		"API_KEY_NOT_FOUND":                   "API key não encontrada",
+		"API_KEY_REVOKED":                     "A API key foi revogada",
+		"INVALID_API_KEY_FORMAT":              "Formato de API key inválido",
+		"RATE_LIMIT_EXCEEDED":                 "Limite de requisições excedido, tente novamente mais tarde",
+		"VALIDATION_FAILED":                   "A validação da requisição falhou",
+		"REQUEST_TIMEOUT":                     "A requisição não terminou dentro do timeout do cliente",
+		"PROVIDER_ACCESS_DENIED":              "O provider de faces negou acesso, verifique a política IAM das credenciais do provider",
+		"PROVIDER_THROTTLED":                  "O provider de faces está no limite de capacidade, tente novamente em instantes",
+		"MAINTENANCE":                         "O reconhecimento facial está pausado para manutenção, tente novamente mais tarde",
+		"SEARCH_NOT_ENABLED":                  "A busca de faces não está habilitada para este tenant",
+		"SEARCH_RATE_LIMIT_EXCEEDED":          "Limite de buscas excedido, tente novamente mais tarde",
+		"INVALID_THRESHOLD":                   "O threshold deve estar entre 0 e 1",
+		"INVALID_MAX_RESULTS":                 "max_results deve estar entre 1 e 50",
+		"AUDIT_WRITE_FAILED":                  "Não foi possível registrar a auditoria da busca e o resultado foi retido; tente novamente em instantes",
+		"SEARCH_BY_EMBEDDING_NOT_ENABLED":     "A busca por embedding não está habilitada para este tenant",
+		"FACE_EXPORT_NOT_ENABLED":             "A exportação de faces não está habilitada para este tenant",
+		"INVALID_EMBEDDING":                   "O embedding deve conter 512 valores finitos com magnitude dentro do limite permitido",
+		"DATA_RESIDENCY_VIOLATION":            "A operação processaria dados biométricos fora da região de dados do tenant",
+		"ANTI_PASSBACK_VIOLATION":             "Já verificado em outra catraca dentro da janela de anti-passback",
+		"WIDGET_SESSION_NOT_FOUND":            "Sessão do widget não encontrada ou expirada",
+		"WIDGET_SESSION_EXPIRED":              "A sessão do widget expirou",
+		"INVALID_PUBLIC_KEY":                  "Chave pública inválida ou inativa",
+		"ORIGIN_NOT_ALLOWED":                  "O domínio de origem não é permitido para este tenant",
+		"INVALID_ORIGIN":                      "Formato de origem inválido",
+		"WIDGET_SESSION_RATE_LIMIT_EXCEEDED":  "Sessões de widget demais criadas com esta chave pública, tente novamente mais tarde",
+		"WIDGET_CHECK_RATE_LIMIT_EXCEEDED":    "Consultas de cadastro demais nesta sessão do widget, tente novamente mais tarde",
+		"INVALID_CHECK_SIGNATURE":             "O external_id deve ser assinado pelo backend do tenant para consultar o cadastro",
+	},
+}
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCatalog_CoversDeclaredErrors(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatalf("list domain files: %v", err)
+	}
+
+	// Collect the package-level Err* = &AppError{...} declarations and the names listed in Catalog
+	declared := make(map[string]bool)
+	listed := make(map[string]bool)
+	fset := token.NewFileSet()
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			t.Fatalf("parse %s: %v", name, err)
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.FuncDecl:
+				if node.Name.Name == "Catalog" {
+					ast.Inspect(node.Body, func(n ast.Node) bool {
+						if ident, ok := n.(*ast.Ident); ok {
+							listed[ident.Name] = true
+						}
+						return true
+					})
+				}
+			case *ast.ValueSpec:
+				if len(node.Values) == 1 && isAppErrorLiteral(node.Values[0]) {
+					declared[node.Names[0].Name] = true
+				}
+			}
+			return true
+		})
+	}
+
+	for name := range declared {
+		if !listed[name] {
+			t.Errorf("%s is not in Catalog()", name)
+		}
+	}
+	if len(declared) != len(Catalog()) {
+		t.Errorf("Catalog() has %d errors, domain declares %d", len(Catalog()), len(declared))
+	}
+}
+
+// isAppErrorLiteral reports whether expr is &AppError{...}
+func isAppErrorLiteral(expr ast.Expr) bool {
+	unary, ok := expr.(*ast.UnaryExpr)
+	if !ok || unary.Op != token.AND {
+		return false
+	}
+	lit, ok := unary.X.(*ast.CompositeLit)
+	if !ok {
+		return false
+	}
+	ident, ok := lit.Type.(*ast.Ident)
+	return ok && ident.Name == "AppError"
+}
+
+func TestCatalog_UniqueCodesAndLocalized(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, e := range Catalog() {
+		if seen[e.Code] {
+			t.Errorf("duplicate code %s", e.Code)
+		}
+		seen[e.Code] = true
+
+		if e.StatusCode < 400 || e.StatusCode > 599 {
+			t.Errorf("%s: status %d is not an error status", e.Code, e.StatusCode)
+		}
+
+		messages := e.Messages()
+		if messages[DefaultLocale] != e.Message {
+			t.Errorf("%s: %s message = %q, want %q", e.Code, DefaultLocale, messages[DefaultLocale], e.Message)
+		}
+		for locale := range localizedMessages {
+			if messages[locale] == "" {
+				t.Errorf("%s: missing %s message", e.Code, locale)
+			}
+		}
+	}
+
+	// Translations of removed codes would never be served
+	for locale, byCode := range localizedMessages {
+		for code := range byCode {
+			if !seen[code] {
+				t.Errorf("%s: translation for unknown code %s", locale, code)
+			}
+		}
+	}
+}
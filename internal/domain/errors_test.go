@@ -106,14 +106,23 @@ func TestPredefinedErrors(t *testing.T) {
 		{ErrNotFound, "NOT_FOUND", 404},
 		{ErrFaceNotFound, "FACE_NOT_FOUND", 404},
 		{ErrFaceExists, "FACE_ALREADY_EXISTS", 409},
+		{ErrEmbeddingModelMismatch, "EMBEDDING_MODEL_MISMATCH", 409},
+		{ErrEmbeddingUnavailable, "EMBEDDING_UNAVAILABLE", 409},
+		{ErrVerifyNotSupportedWithoutSource, "VERIFY_NOT_SUPPORTED_WITHOUT_SOURCE", 409},
+		{ErrMetadataTooLarge, "METADATA_TOO_LARGE", 413},
 		{ErrInvalidImage, "INVALID_IMAGE", 422},
 		{ErrNoFaceDetected, "NO_FACE_DETECTED", 422},
+		{ErrNoFaceInDocument, "NO_FACE_IN_DOCUMENT", 422},
 		{ErrMultipleFaces, "MULTIPLE_FACES", 422},
 		{ErrLowQualityImage, "LOW_QUALITY_IMAGE", 422},
 		{ErrLivenessFailed, "LIVENESS_FAILED", 422},
 		{ErrTenantNotFound, "TENANT_NOT_FOUND", 404},
 		{ErrRateLimitExceeded, "RATE_LIMIT_EXCEEDED", 429},
 		{ErrValidationFailed, "VALIDATION_FAILED", 422},
+		{ErrRequestTimeout, "REQUEST_TIMEOUT", 408},
+		{ErrSearchByEmbeddingNotEnabled, "SEARCH_BY_EMBEDDING_NOT_ENABLED", 403},
+		{ErrInvalidEmbedding, "INVALID_EMBEDDING", 422},
+		{ErrDataResidencyViolation, "DATA_RESIDENCY_VIOLATION", 403},
 	}
 
 	for _, tt := range tests {
@@ -127,3 +136,20 @@ func TestPredefinedErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestAppError_WithReasons(t *testing.T) {
+	err := ErrNoFaceDetected.WithReasons("too_dark", "too_blurry")
+
+	if err == ErrNoFaceDetected {
+		t.Fatal("WithReasons should return a copy")
+	}
+	if err.Code != ErrNoFaceDetected.Code || err.StatusCode != ErrNoFaceDetected.StatusCode {
+		t.Errorf("WithReasons changed code/status: %s %d", err.Code, err.StatusCode)
+	}
+	if len(err.Reasons) != 2 || err.Reasons[0] != "too_dark" || err.Reasons[1] != "too_blurry" {
+		t.Errorf("Reasons = %v, want [too_dark too_blurry]", err.Reasons)
+	}
+	if len(ErrNoFaceDetected.Reasons) != 0 {
+		t.Errorf("sentinel was modified: %v", ErrNoFaceDetected.Reasons)
+	}
+}
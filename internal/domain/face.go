@@ -1,21 +1,174 @@
 package domain
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// DefaultMaxMetadataBytes is the default cap on a face's serialized metadata
+const DefaultMaxMetadataBytes = 16 * 1024
+
+// EmbeddingDimension is the length of the embeddings produced by the supported providers
+const EmbeddingDimension = 512
+
+// DefaultMaxEmbeddingMagnitude is the default cap on the L2 norm of an embedding. Provider
+// embeddings stay well below it whether or not they are normalized.
+const DefaultMaxEmbeddingMagnitude = 1000.0
+
+// MinEmbeddingMagnitude rejects zero and near-zero embeddings, whose cosine distance is undefined
+const MinEmbeddingMagnitude = 1e-6
+
+// DefaultRecentVerificationWindow is used when a recently-verified check gives no window
+const DefaultRecentVerificationWindow = 15 * time.Minute
+
+// DefaultMaxVerificationAge caps the window a recently-verified check may ask for
+const DefaultMaxVerificationAge = 24 * time.Hour
+
+// MaxExistsBatchSize caps how many external IDs a single existence check accepts
+const MaxExistsBatchSize = 100
+
+// Page sizes of the face listing
+const (
+	DefaultFaceListLimit = 50
+	MaxFaceListLimit     = 100
+)
+
+// MaxFaceTTL caps how long after registration a face may be set to expire
+const MaxFaceTTL = 365 * 24 * time.Hour
+
+// EmbeddingHashPrecision is the step embeddings are rounded to before hashing, so float noise
+// from re-encoding the same photo does not change the hash
+const EmbeddingHashPrecision = 1e-4
+
+// MaxFacesPerImage is how many faces register and verify accept in one image, unless the
+// tenant's on_multiple_faces policy uses the largest one
+const MaxFacesPerImage = 1
+
+// MaxBulkMetadataBatchSize caps how many external IDs a single bulk metadata patch accepts
+const MaxBulkMetadataBatchSize = 500
+
+// NoFaceReason explains why no usable face was found, so clients can guide the user
+type NoFaceReason string
+
+const (
+	// NoFaceReasonPose - Face turned away from the camera
+	NoFaceReasonPose NoFaceReason = "face_not_frontal"
+	// NoFaceReasonTooDark - Image too dark
+	NoFaceReasonTooDark NoFaceReason = "too_dark"
+	// NoFaceReasonBlurry - Image out of focus or motion blurred
+	NoFaceReasonBlurry NoFaceReason = "too_blurry"
+	// NoFaceReasonLowConfidence - Provider is not confident the region is a face
+	NoFaceReasonLowConfidence NoFaceReason = "face_unclear"
+	// NoFaceReasonTooSmall - Face too far from the camera
+	NoFaceReasonTooSmall NoFaceReason = "face_too_small"
+	// NoFaceReasonLowQuality - Face quality too low for recognition
+	NoFaceReasonLowQuality NoFaceReason = "low_face_quality"
+)
+
 // Face representa uma face cadastrada no sistema
 type Face struct {
-	ID           uuid.UUID              `json:"id"`
-	TenantID     uuid.UUID              `json:"-"`
-	ExternalID   string                 `json:"external_id"`
-	Embedding    []float64              `json:"-"`
-	Metadata     map[string]interface{} `json:"metadata,omitempty"`
-	QualityScore float64                `json:"quality_score"`
-	CreatedAt    time.Time              `json:"created_at"`
-	UpdatedAt    time.Time              `json:"updated_at"`
+	ID               uuid.UUID              `json:"id"`
+	TenantID         uuid.UUID              `json:"-"`
+	ExternalID       string                 `json:"external_id"`
+	Embedding        []float64              `json:"-"`
+	EmbeddingModel   string                 `json:"embedding_model,omitempty"`
+	EmbeddingVersion string                 `json:"embedding_version,omitempty"`
+	Metadata         map[string]interface{} `json:"metadata,omitempty"`
+	QualityScore     float64                `json:"quality_score"`
+	CreatedAt        time.Time              `json:"created_at"`
+	UpdatedAt        time.Time              `json:"updated_at"`
+	// EnrolledAt is when the current biometric was (re-)registered; unlike UpdatedAt, metadata
+	// updates leave it
+	EnrolledAt time.Time `json:"-"`
+	// ExpiresAt is set for faces registered with a TTL; expired faces are deleted by the expiry sweep
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// ProviderFaceID is the ID the provider assigned when indexing the face (the Rekognition FaceId);
+	// empty for providers that assign none. Only admin lookups expose it.
+	ProviderFaceID string `json:"-"`
+	// EmbeddingHash identifies the biometric content of the face without exposing the embedding,
+	// see EmbeddingHash; empty for faces registered before it was stored
+	EmbeddingHash string `json:"-"`
+	// EmbeddingPrecision is the precision the embedding is stored at, from the tenant's
+	// embedding_precision; empty stores it at full precision
+	EmbeddingPrecision EmbeddingPrecision `json:"-"`
+
+	// IsTest marks faces registered with a test-environment API key
+	IsTest bool `json:"-"`
+}
+
+// ParseFaceTTL parses a face TTL given as a Go duration, e.g. "168h" for seven days
+func ParseFaceTTL(raw string) (time.Duration, error) {
+	ttl, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ttl: %w", err)
+	}
+	if ttl <= 0 || ttl > MaxFaceTTL {
+		return 0, fmt.Errorf("ttl must be positive and at most %s, got %s", MaxFaceTTL, ttl)
+	}
+	return ttl, nil
+}
+
+// Fingerprint returns the embedding fingerprint recorded when the face was registered
+func (f *Face) Fingerprint() EmbeddingFingerprint {
+	return EmbeddingFingerprint{Model: f.EmbeddingModel, Version: f.EmbeddingVersion}
+}
+
+// EmbeddingFingerprint identifies the provider model and dimension that produced an embedding.
+// Embeddings are only comparable when they share the same fingerprint.
+type EmbeddingFingerprint struct {
+	Model   string
+	Version string
+}
+
+// NewEmbeddingFingerprint builds a fingerprint for an embedding of the given dimension
+func NewEmbeddingFingerprint(model string, dimension int) EmbeddingFingerprint {
+	return EmbeddingFingerprint{Model: model, Version: strconv.Itoa(dimension) + "d"}
+}
+
+// IsZero reports whether the fingerprint is unknown (faces registered before fingerprinting)
+func (f EmbeddingFingerprint) IsZero() bool {
+	return f.Model == "" && f.Version == ""
+}
+
+// Compatible reports whether embeddings with both fingerprints can be compared.
+// Unknown fingerprints are treated as compatible so legacy faces keep working.
+func (f EmbeddingFingerprint) Compatible(other EmbeddingFingerprint) bool {
+	if f.IsZero() || other.IsZero() {
+		return true
+	}
+	return f == other
+}
+
+// String returns the fingerprint as "model@version"
+func (f EmbeddingFingerprint) String() string {
+	return f.Model + "@" + f.Version
+}
+
+// EmbeddingHash returns the hex SHA-256 of the embedding quantized to EmbeddingHashPrecision,
+// salted with its fingerprint. Identical embeddings of the same model hash identically.
+func EmbeddingHash(embedding []float64, fingerprint EmbeddingFingerprint) string {
+	h := sha256.New()
+	h.Write([]byte(fingerprint.String()))
+	buf := make([]byte, 4)
+	for _, v := range embedding {
+		binary.LittleEndian.PutUint32(buf, uint32(int32(math.Round(v/EmbeddingHashPrecision))))
+		h.Write(buf)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ProviderFaceIDHash stands in for EmbeddingHash on providers without embeddings, which index
+// each registration under a new provider face ID
+func ProviderFaceIDHash(providerFaceID string) string {
+	sum := sha256.Sum256([]byte("provider_face_id:" + providerFaceID))
+	return hex.EncodeToString(sum[:])
 }
 
 // Verification representa um registro de verificação (audit)
@@ -26,9 +179,81 @@ type Verification struct {
 	ExternalID     string     `json:"external_id"`
 	Verified       bool       `json:"verified"`
 	Confidence     float64    `json:"confidence"`
+	MatchPassed    bool       `json:"match_passed"`
 	LivenessPassed *bool      `json:"liveness_passed,omitempty"`
+	LivenessScore  *float64   `json:"liveness_score,omitempty"` // nil when no liveness check ran
+	FailReason     string     `json:"fail_reason,omitempty"`    // one of the FailReason* values, empty when verified
 	LatencyMs      int64      `json:"latency_ms"`
 	CreatedAt      time.Time  `json:"created_at"`
+
+	// IsTest marks verifications performed with a test-environment API key
+	IsTest bool `json:"-"`
+
+	// ClientIP identifies the gate that requested the verification, empty when unknown
+	ClientIP string `json:"-"`
+
+	// Provider names the provider that produced the decision, e.g. "deepface"; empty when unknown
+	Provider string `json:"-"`
+
+	// ProviderVariant is the provider experiment arm that decided the verification, empty outside experiments
+	ProviderVariant string `json:"-"`
+
+	// ReenrollSuggested is set when recent confidences drifted below the enrolled quality
+	ReenrollSuggested *ReenrollSuggestion `json:"-"`
+
+	// ThresholdApplied is the verification threshold the match was decided against
+	ThresholdApplied float64         `json:"-"`
+	ThresholdSource  ThresholdSource `json:"-"`
+
+	// HysteresisPass marks a match that passed below the threshold under verify_hysteresis
+	HysteresisPass bool `json:"-"`
+
+	// FaceMetadata is the current metadata of the verified face, only loaded by GetByID
+	FaceMetadata map[string]interface{} `json:"-"`
+}
+
+// Reasons recorded with every failed verification
+const (
+	FailReasonMatchBelowThreshold = "match_below_threshold"
+	FailReasonLivenessFailed      = "liveness_failed"
+	// FailReasonNoFace, FailReasonMultipleFaces and FailReasonAntiPassback reject the attempt
+	// before matching; they are only recorded for tenants with record_rejected_verifications
+	FailReasonNoFace        = "no_face"
+	FailReasonMultipleFaces = "multiple_faces"
+	FailReasonAntiPassback  = "anti_passback"
+)
+
+// GateVisit is where and when an external_id last passed a verification
+type GateVisit struct {
+	// Gate is the client IP of the verification, empty when it was not recorded
+	Gate string
+	At   time.Time
+}
+
+// ReenrollSuggestion explains why a user should re-register their face,
+// e.g. after a beard or glasses made verifications pass with declining confidence
+type ReenrollSuggestion struct {
+	EnrolledQuality   float64   `json:"enrolled_quality"`
+	Margin            float64   `json:"margin"`
+	RecentConfidences []float64 `json:"recent_confidences"` // most recent first
+}
+
+// SuggestReenroll reports whether the last window confidences (most recent first) all fell below
+// enrolledQuality-margin. It fires once per drift: only when the attempt before the window was
+// still above the floor or does not exist, so confidences may hold up to window+1 entries.
+func SuggestReenroll(enrolledQuality, margin float64, window int, confidences []float64) bool {
+	if window <= 0 || len(confidences) < window {
+		return false
+	}
+
+	floor := enrolledQuality - margin
+	for _, c := range confidences[:window] {
+		if c >= floor {
+			return false
+		}
+	}
+
+	return len(confidences) == window || confidences[window] >= floor
 }
 
 // LivenessResult represents the result of a liveness check
@@ -47,8 +272,66 @@ type LivenessChecks struct {
 	SingleFace   bool `json:"single_face"`
 }
 
+// BulkMetadataResult summarizes a metadata patch applied to a list of external IDs
+type BulkMetadataResult struct {
+	Updated     int
+	NotFound    int
+	NotFoundIDs []string
+}
+
+// FaceListFilter selects a tenant's faces registered in [RegisteredFrom, RegisteredTo);
+// a nil bound leaves that side of the range open
+type FaceListFilter struct {
+	RegisteredFrom *time.Time
+	RegisteredTo   *time.Time
+	Limit          int
+	Offset         int
+}
+
 // RegistrationCheck represents the result of checking if a face is registered
 type RegistrationCheck struct {
 	Registered   bool       `json:"registered"`
 	RegisteredAt *time.Time `json:"registered_at,omitempty"`
 }
+
+// RecentVerification reports whether an external ID passed a verification within a window
+type RecentVerification struct {
+	ExternalID       string     `json:"external_id"`
+	RecentlyVerified bool       `json:"recently_verified"`
+	LastVerifiedAt   *time.Time `json:"last_verified_at"`
+	WithinSeconds    int64      `json:"within_seconds"`
+	// LivenessRequired is set when only verifications that passed liveness are considered
+	LivenessRequired bool `json:"liveness_required"`
+}
+
+// FaceComparison is the similarity between the stored faces of two external IDs
+type FaceComparison struct {
+	ExternalIDA string  `json:"a"`
+	ExternalIDB string  `json:"b"`
+	Similarity  float64 `json:"similarity"`
+	Threshold   float64 `json:"threshold"`
+	Match       bool    `json:"match"`
+}
+
+// DocumentVerification is the 1:1 match of a live capture against an ID document photo.
+// Nothing is stored, so it has no verification ID.
+type DocumentVerification struct {
+	Verified   bool    `json:"verified"`
+	Similarity float64 `json:"similarity"`
+	// Threshold is the tenant's verification threshold the similarity was decided against
+	Threshold float64 `json:"threshold"`
+	LatencyMs int64   `json:"latency_ms"`
+}
+
+// FaceRecount is a fresh count of a tenant's faces, compared with the provider's collection
+type FaceRecount struct {
+	DatabaseCount int `json:"database_count"`
+	// ProviderCount and Delta (provider minus database) are only set for providers that keep
+	// their own collection, like Rekognition
+	ProviderCount *int64 `json:"provider_count"`
+	Delta         *int64 `json:"delta"`
+	// PreviousCachedCount is the cached count the recount replaced, if one was live
+	PreviousCachedCount *int      `json:"previous_cached_count"`
+	CacheRefreshed      bool      `json:"cache_refreshed"`
+	RecountedAt         time.Time `json:"recounted_at"`
+}
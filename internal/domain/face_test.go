@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEmbeddingFingerprint_Compatible(t *testing.T) {
+	facenet := NewEmbeddingFingerprint("deepface/Facenet512", 512)
+
+	tests := []struct {
+		name  string
+		other EmbeddingFingerprint
+		want  bool
+	}{
+		{"same fingerprint", NewEmbeddingFingerprint("deepface/Facenet512", 512), true},
+		{"unknown fingerprint", EmbeddingFingerprint{}, true},
+		{"different model", NewEmbeddingFingerprint("deepface/ArcFace", 512), false},
+		{"different dimension", NewEmbeddingFingerprint("deepface/Facenet512", 128), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := facenet.Compatible(tt.other); got != tt.want {
+				t.Errorf("Compatible() = %v, want %v", got, tt.want)
+			}
+			if got := tt.other.Compatible(facenet); got != tt.want {
+				t.Errorf("Compatible() reversed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestReenroll(t *testing.T) {
+	// Enrolled quality 0.95 with margin 0.1: the floor is 0.85
+	tests := []struct {
+		name        string
+		window      int
+		confidences []float64
+		want        bool
+	}{
+		{"not enough history", 3, []float64{0.80, 0.80}, false},
+		{"full window below the floor", 3, []float64{0.80, 0.82, 0.84}, true},
+		{"drift starts after healthy attempt", 3, []float64{0.80, 0.82, 0.84, 0.93}, true},
+		{"one attempt above the floor", 3, []float64{0.80, 0.90, 0.84}, false},
+		{"at the floor is not drift", 3, []float64{0.80, 0.85, 0.84}, false},
+		{"already suggested for this drift", 3, []float64{0.80, 0.82, 0.84, 0.83}, false},
+		{"disabled window", 0, []float64{0.80}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SuggestReenroll(0.95, 0.1, tt.window, tt.confidences); got != tt.want {
+				t.Errorf("SuggestReenroll() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFaceTTL(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"168h", 7 * 24 * time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{"8760h", MaxFaceTTL, false},
+		{"8761h", 0, true},
+		{"0s", 0, true},
+		{"-1h", 0, true},
+		{"7d", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := ParseFaceTTL(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFaceTTL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFaceTTL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmbeddingHash(t *testing.T) {
+	facenet := NewEmbeddingFingerprint("deepface/Facenet512", 512)
+	embedding := []float64{0.12345, -0.5, 0.99991, 0}
+
+	hash := EmbeddingHash(embedding, facenet)
+	if len(hash) != 64 {
+		t.Fatalf("EmbeddingHash() = %q, want a hex SHA-256", hash)
+	}
+
+	tests := []struct {
+		name        string
+		embedding   []float64
+		fingerprint EmbeddingFingerprint
+		wantSame    bool
+	}{
+		{"identical embedding", []float64{0.12345, -0.5, 0.99991, 0}, facenet, true},
+		{"float noise below the precision", []float64{0.1234500001, -0.49999999, 0.99991, 1e-9}, facenet, true},
+		{"changed component", []float64{0.12345, -0.5, 0.98, 0}, facenet, false},
+		{"different length", []float64{0.12345, -0.5, 0.99991}, facenet, false},
+		{"different model", []float64{0.12345, -0.5, 0.99991, 0}, NewEmbeddingFingerprint("deepface/ArcFace", 512), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EmbeddingHash(tt.embedding, tt.fingerprint)
+			if (got == hash) != tt.wantSame {
+				t.Errorf("EmbeddingHash() = %q, same as original = %v, want %v", got, got == hash, tt.wantSame)
+			}
+		})
+	}
+}
+
+func TestProviderFaceIDHash(t *testing.T) {
+	if ProviderFaceIDHash("face-1") != ProviderFaceIDHash("face-1") {
+		t.Error("ProviderFaceIDHash() differs for the same provider face ID")
+	}
+	if ProviderFaceIDHash("face-1") == ProviderFaceIDHash("face-2") {
+		t.Error("ProviderFaceIDHash() equal for different provider face IDs")
+	}
+}
@@ -0,0 +1,86 @@
+package domain
+
+// Features are the optional behaviors a tenant toggles, resolved with defaults. Services check
+// them through TenantSettings.Features instead of reading the individual settings.
+//
+// Flags are set in the feature_flags block, e.g. {"feature_flags": {"search": true}}, which
+// wins over the flat keys that predate it (search_enabled, shadow_provider_enabled, ...).
+type Features struct {
+	// Search enables 1:N search (flat key: search_enabled, default off)
+	Search bool `json:"search"`
+	// SearchByEmbedding enables search with a client-computed embedding (search_by_embedding_enabled, default off)
+	SearchByEmbedding bool `json:"search_by_embedding"`
+	// SearchLiveness requires search probes to pass liveness_threshold (search_require_liveness,
+	// default off). Always on at the maximum security level.
+	SearchLiveness bool `json:"search_liveness"`
+	// ShadowProvider mirrors searches to the shadow provider (shadow_provider_enabled, default off)
+	ShadowProvider bool `json:"shadow_provider"`
+	// AntiPassback checks verifies against the last gate. It needs anti_passback.window; the flag
+	// can switch a configured window off but not enable one.
+	AntiPassback bool `json:"anti_passback"`
+	// ReenrollCheck suggests re-enrollment when verify confidence drifts (reenroll_check_enabled, default off)
+	ReenrollCheck bool `json:"reenroll_check"`
+	// RecordRejectedVerifications stores rejected verify attempts (record_rejected_verifications, default off)
+	RecordRejectedVerifications bool `json:"record_rejected_verifications"`
+	// ReplayDetection rejects widget liveness sessions with replay-like frames (replay_detection.enabled, default off)
+	ReplayDetection bool `json:"replay_detection"`
+	// EmbeddingHash returns embedding_hash in face responses (embedding_hash_enabled, default off)
+	EmbeddingHash bool `json:"embedding_hash"`
+	// FaceExport enables the NDJSON export of all face records (face_export_enabled, default off)
+	FaceExport bool `json:"face_export"`
+}
+
+// Features returns the resolved feature flags of the tenant
+func (s TenantSettings) Features() Features {
+	return Features{
+		Search:                      s.SearchEnabled,
+		SearchByEmbedding:           s.SearchByEmbedding,
+		SearchLiveness:              s.SearchRequireLiveness || s.SecurityLevel == SecurityMaximum,
+		ShadowProvider:              s.ShadowProviderEnabled,
+		AntiPassback:                s.AntiPassback.Enabled(),
+		ReenrollCheck:               s.ReenrollCheckEnabled,
+		RecordRejectedVerifications: s.RecordRejectedVerifications,
+		ReplayDetection:             s.ReplayDetection.Enabled,
+		EmbeddingHash:               s.EmbeddingHashEnabled,
+		FaceExport:                  s.FaceExportEnabled,
+	}
+}
+
+// parseFeatureFlags applies the feature_flags block over the flat keys already read into s
+func parseFeatureFlags(block settingsReader, s *TenantSettings) {
+	if v, ok := block.Bool("search"); ok {
+		s.SearchEnabled = v
+	}
+	if v, ok := block.Bool("search_by_embedding"); ok {
+		s.SearchByEmbedding = v
+	}
+	if v, ok := block.Bool("search_liveness"); ok {
+		s.SearchRequireLiveness = v
+	}
+	if v, ok := block.Bool("shadow_provider"); ok {
+		s.ShadowProviderEnabled = v
+	}
+	if v, ok := block.Bool("anti_passback"); ok {
+		switch {
+		case !v:
+			s.AntiPassback = AntiPassbackSettings{}
+		case !s.AntiPassback.Enabled():
+			block.warn("anti_passback", "(requires anti_passback.window)")
+		}
+	}
+	if v, ok := block.Bool("reenroll_check"); ok {
+		s.ReenrollCheckEnabled = v
+	}
+	if v, ok := block.Bool("record_rejected_verifications"); ok {
+		s.RecordRejectedVerifications = v
+	}
+	if v, ok := block.Bool("replay_detection"); ok {
+		s.ReplayDetection.Enabled = v
+	}
+	if v, ok := block.Bool("embedding_hash"); ok {
+		s.EmbeddingHashEnabled = v
+	}
+	if v, ok := block.Bool("face_export"); ok {
+		s.FaceExportEnabled = v
+	}
+}
@@ -0,0 +1,164 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestTenantSettings_Features(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		want     Features
+	}{
+		{
+			name:     "defaults",
+			settings: nil,
+			want:     Features{},
+		},
+		{
+			name: "flat keys",
+			settings: map[string]interface{}{
+				"search_enabled":                true,
+				"search_by_embedding_enabled":   true,
+				"search_require_liveness":       true,
+				"shadow_provider_enabled":       true,
+				"reenroll_check_enabled":        true,
+				"record_rejected_verifications": true,
+				"embedding_hash_enabled":        true,
+				"face_export_enabled":           true,
+				"anti_passback":                 map[string]interface{}{"window": "10m"},
+			},
+			want: Features{
+				Search:                      true,
+				SearchByEmbedding:           true,
+				SearchLiveness:              true,
+				ShadowProvider:              true,
+				AntiPassback:                true,
+				ReenrollCheck:               true,
+				RecordRejectedVerifications: true,
+				EmbeddingHash:               true,
+				FaceExport:                  true,
+			},
+		},
+		{
+			name: "feature_flags block",
+			settings: map[string]interface{}{
+				"feature_flags": map[string]interface{}{
+					"search":                        true,
+					"search_by_embedding":           "true",
+					"search_liveness":               true,
+					"shadow_provider":               true,
+					"reenroll_check":                true,
+					"record_rejected_verifications": true,
+					"replay_detection":              true,
+					"embedding_hash":                true,
+					"face_export":                   true,
+				},
+			},
+			want: Features{
+				Search:                      true,
+				SearchByEmbedding:           true,
+				SearchLiveness:              true,
+				ShadowProvider:              true,
+				ReenrollCheck:               true,
+				RecordRejectedVerifications: true,
+				ReplayDetection:             true,
+				EmbeddingHash:               true,
+				FaceExport:                  true,
+			},
+		},
+		{
+			name: "feature_flags win over flat keys",
+			settings: map[string]interface{}{
+				"search_enabled":          true,
+				"shadow_provider_enabled": true,
+				"feature_flags": map[string]interface{}{
+					"search":          false,
+					"shadow_provider": false,
+				},
+			},
+			want: Features{},
+		},
+		{
+			name: "anti_passback flag switches a configured window off",
+			settings: map[string]interface{}{
+				"anti_passback": map[string]interface{}{"window": "10m"},
+				"feature_flags": map[string]interface{}{"anti_passback": false},
+			},
+			want: Features{},
+		},
+		{
+			name: "anti_passback flag cannot enable without a window",
+			settings: map[string]interface{}{
+				"feature_flags": map[string]interface{}{"anti_passback": true},
+			},
+			want: Features{},
+		},
+		{
+			name: "maximum security level always requires search liveness",
+			settings: map[string]interface{}{
+				"security_level": "maximum",
+				"feature_flags":  map[string]interface{}{"search_liveness": false},
+			},
+			want: Features{SearchLiveness: true},
+		},
+		{
+			name: "invalid flag keeps the default",
+			settings: map[string]interface{}{
+				"search_enabled": true,
+				"feature_flags":  map[string]interface{}{"search": "sometimes"},
+			},
+			want: Features{Search: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant := Tenant{ID: uuid.New(), Settings: tt.settings}
+
+			got := tenant.GetSettings().Features()
+
+			if got != tt.want {
+				t.Errorf("Features() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTenantSettings_FeatureFlagsKeepUnderlyingSettings(t *testing.T) {
+	tenant := Tenant{ID: uuid.New(), Settings: map[string]interface{}{
+		"anti_passback": map[string]interface{}{"window": "5m"},
+		"feature_flags": map[string]interface{}{"anti_passback": true, "search": true},
+	}}
+
+	settings := tenant.GetSettings()
+
+	if settings.AntiPassback.Window != 5*time.Minute {
+		t.Errorf("AntiPassback.Window = %v, want 5m", settings.AntiPassback.Window)
+	}
+	if !settings.SearchEnabled {
+		t.Error("SearchEnabled = false, want the feature_flags value")
+	}
+}
+
+func TestValidateTenantSettings_FeatureFlags(t *testing.T) {
+	if err := ValidateTenantSettings(map[string]interface{}{
+		"feature_flags": map[string]interface{}{"search": true, "unknown_flag": true},
+	}); err != nil {
+		t.Errorf("ValidateTenantSettings() error = %v, want nil", err)
+	}
+
+	err := ValidateTenantSettings(map[string]interface{}{
+		"feature_flags": map[string]interface{}{"search": "sometimes", "anti_passback": true},
+	})
+	if err == nil {
+		t.Fatal("ValidateTenantSettings() error = nil, want invalid flags")
+	}
+	want := "invalid tenant settings: feature_flags.anti_passback, feature_flags.search"
+	if err.Error() != want {
+		t.Errorf("ValidateTenantSettings() error = %q, want %q", err.Error(), want)
+	}
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// ProviderErrorCount is how often a provider failed with one error code over a date range
+type ProviderErrorCount struct {
+	Provider  string `json:"provider"`
+	ErrorCode string `json:"error_code"`
+	Count     int    `json:"count"`
+	// LastSeenAt is the last failure with this code in the range
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
@@ -0,0 +1,44 @@
+package domain
+
+// Provider experiment arms a verification or search is tagged with
+const (
+	// ProviderVariantPrimary is decided by the provider serving live traffic
+	ProviderVariantPrimary = "primary"
+	// ProviderVariantAlternate is decided by the alternate (shadow) provider
+	ProviderVariantAlternate = "alternate"
+)
+
+// ProviderExperimentSettings routes a share of live verify and search requests through the
+// alternate provider to A/B test its accuracy. Unlike shadow_provider, the alternate decides
+// the sampled requests; every request of the experiment is tagged with the arm that decided
+// it. Configured as {"provider_experiment": {"percentage": 10}}; off by default.
+type ProviderExperimentSettings struct {
+	// Percentage of requests decided by the alternate provider, 0-100
+	Percentage float64 `json:"percentage"`
+}
+
+// Enabled reports whether any request is routed to the alternate provider
+func (p ProviderExperimentSettings) Enabled() bool {
+	return p.Percentage > 0
+}
+
+// Variant returns the arm of a request for roll, a uniform random number in [0, 1)
+func (p ProviderExperimentSettings) Variant(roll float64) string {
+	if roll*100 < p.Percentage {
+		return ProviderVariantAlternate
+	}
+	return ProviderVariantPrimary
+}
+
+// parseProviderExperiment reads the provider_experiment block, ignoring a percentage outside 0-100
+func parseProviderExperiment(block settingsReader) ProviderExperimentSettings {
+	var cfg ProviderExperimentSettings
+	if v, ok := block.Float("percentage"); ok {
+		if v >= 0 && v <= 100 {
+			cfg.Percentage = v
+		} else {
+			block.warn("percentage", v)
+		}
+	}
+	return cfg
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestProviderExperimentSettings_Variant(t *testing.T) {
+	tests := []struct {
+		name          string
+		percentage    float64
+		wantAlternate int
+	}{
+		{name: "off", percentage: 0, wantAlternate: 0},
+		{name: "ten percent", percentage: 10, wantAlternate: 100},
+		{name: "fractional percent", percentage: 2.5, wantAlternate: 25},
+		{name: "all traffic", percentage: 100, wantAlternate: 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := ProviderExperimentSettings{Percentage: tt.percentage}
+
+			// Evenly spread rolls stand in for the uniform random draw
+			alternate := 0
+			for i := 0; i < 1000; i++ {
+				if cfg.Variant(float64(i)/1000) == ProviderVariantAlternate {
+					alternate++
+				}
+			}
+
+			if alternate != tt.wantAlternate {
+				t.Errorf("alternate = %d of 1000, want %d", alternate, tt.wantAlternate)
+			}
+		})
+	}
+}
+
+func TestTenant_GetSettings_ProviderExperiment(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  float64
+		warn  bool
+	}{
+		{name: "percentage", value: map[string]interface{}{"percentage": 10.0}, want: 10},
+		{name: "string percentage", value: map[string]interface{}{"percentage": "12.5"}, want: 12.5},
+		{name: "above 100", value: map[string]interface{}{"percentage": 150.0}, warn: true},
+		{name: "negative", value: map[string]interface{}{"percentage": -5.0}, warn: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant := Tenant{ID: uuid.New(), Settings: map[string]interface{}{"provider_experiment": tt.value}}
+
+			logs := captureWarnings(t)
+			got := tenant.GetSettings().ProviderExperiment
+
+			if got.Percentage != tt.want {
+				t.Errorf("Percentage = %v, want %v", got.Percentage, tt.want)
+			}
+			if got.Enabled() != (tt.want > 0) {
+				t.Errorf("Enabled() = %v, want %v", got.Enabled(), tt.want > 0)
+			}
+			if warned := strings.Contains(logs.String(), "percentage"); warned != tt.warn {
+				t.Errorf("warned = %v, want %v: %q", warned, tt.warn, logs.String())
+			}
+		})
+	}
+}
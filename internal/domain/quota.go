@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"math"
+	"slices"
+)
+
+// Quotas the soft warnings track, named after their tenant settings
+const (
+	QuotaMaxFaces         = "max_faces"
+	QuotaMaxRequestsMonth = "max_requests_month"
+)
+
+// DefaultQuotaWarningPercentages are the quota usage percentages warned about by default
+var DefaultQuotaWarningPercentages = []int{80, 90}
+
+// QuotaWarningSettings warns a tenant approaching its max_faces or max_requests_month quota
+// before the hard limit rejects requests: once usage reaches one of Percentages, responses carry
+// X-Rekko-Quota-Warning and a quota.threshold_reached webhook is sent the first time each
+// percentage is reached. Configured as {"quota_warnings": {"percentages": [75, 90, 95]}}; on by default for
+// tenants with a quota, {"quota_warnings": {"enabled": false}} turns them off.
+type QuotaWarningSettings struct {
+	Enabled     bool  `json:"enabled"`
+	Percentages []int `json:"percentages"` // ascending, each 1-99
+}
+
+// Level returns the highest warning percentage used has reached of quota, 0 when it reached
+// none, the warnings are off or the quota is unlimited (0 or less)
+func (q QuotaWarningSettings) Level(used, quota int64) int {
+	if !q.Enabled || quota <= 0 {
+		return 0
+	}
+	level := 0
+	for _, percentage := range q.Percentages {
+		if used*100 >= int64(percentage)*quota {
+			level = percentage
+		}
+	}
+	return level
+}
+
+// QuotaWarning reports a quota whose usage reached a warning percentage
+type QuotaWarning struct {
+	// Quota is QuotaMaxFaces or QuotaMaxRequestsMonth
+	Quota string `json:"quota"`
+	// Threshold is the highest warning percentage reached
+	Threshold int   `json:"threshold"`
+	Used      int64 `json:"used"`
+	Limit     int64 `json:"limit"`
+	// Percentage is the share of the quota used, rounded to two decimals
+	Percentage float64 `json:"percentage"`
+	// Period is the month of a max_requests_month warning (YYYY-MM), empty for max_faces
+	Period string `json:"period,omitempty"`
+}
+
+// NewQuotaWarning builds the warning for used of limit at threshold
+func NewQuotaWarning(quota string, threshold int, used, limit int64, period string) QuotaWarning {
+	return QuotaWarning{
+		Quota:      quota,
+		Threshold:  threshold,
+		Used:       used,
+		Limit:      limit,
+		Percentage: math.Round(float64(used)/float64(limit)*10000) / 100,
+		Period:     period,
+	}
+}
+
+// parseQuotaWarnings reads the quota_warnings block over the defaults; a percentages list
+// without any whole number from 1 to 99 is ignored
+func parseQuotaWarnings(block settingsReader, cfg QuotaWarningSettings) QuotaWarningSettings {
+	if v, ok := block.Bool("enabled"); ok {
+		cfg.Enabled = v
+	}
+	if v, ok := block.List("percentages"); ok {
+		if percentages := parseQuotaWarningPercentages(v); len(percentages) > 0 {
+			cfg.Percentages = percentages
+		} else {
+			block.warn("percentages", v)
+		}
+	}
+	return cfg
+}
+
+// parseQuotaWarningPercentages keeps the distinct whole numbers from 1 to 99, ascending
+func parseQuotaWarningPercentages(values []interface{}) []int {
+	percentages := make([]int, 0, len(values))
+	for _, v := range values {
+		f, ok := v.(float64)
+		if !ok || f != math.Trunc(f) || f < 1 || f > 99 {
+			continue
+		}
+		percentages = append(percentages, int(f))
+	}
+	slices.Sort(percentages)
+	return slices.Compact(percentages)
+}
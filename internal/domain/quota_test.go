@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestTenant_GetSettings_QuotaWarnings(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  QuotaWarningSettings
+		warn  bool
+	}{
+		{
+			name:  "custom percentages are sorted and deduplicated",
+			value: map[string]interface{}{"percentages": []interface{}{95.0, 75.0, 95.0}},
+			want:  QuotaWarningSettings{Enabled: true, Percentages: []int{75, 95}},
+		},
+		{
+			name:  "disabled",
+			value: map[string]interface{}{"enabled": false},
+			want:  QuotaWarningSettings{Enabled: false, Percentages: DefaultQuotaWarningPercentages},
+		},
+		{
+			name:  "invalid percentages are skipped",
+			value: map[string]interface{}{"percentages": []interface{}{0.0, 85.5, 100.0, "90", 70.0}},
+			want:  QuotaWarningSettings{Enabled: true, Percentages: []int{70}},
+		},
+		{
+			name:  "no valid percentage keeps the defaults",
+			value: map[string]interface{}{"percentages": []interface{}{150.0}},
+			want:  QuotaWarningSettings{Enabled: true, Percentages: DefaultQuotaWarningPercentages},
+			warn:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant := Tenant{ID: uuid.New(), Settings: map[string]interface{}{"quota_warnings": tt.value}}
+
+			logs := captureWarnings(t)
+			got := tenant.GetSettings().QuotaWarnings
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("QuotaWarnings = %+v, want %+v", got, tt.want)
+			}
+			if warned := strings.Contains(logs.String(), "percentages"); warned != tt.warn {
+				t.Errorf("warned = %v, want %v: %q", warned, tt.warn, logs.String())
+			}
+		})
+	}
+}
+
+func TestTenant_GetSettings_Quotas(t *testing.T) {
+	tenant := Tenant{ID: uuid.New(), Settings: map[string]interface{}{"max_faces": 5000.0, "max_requests_month": -1.0}}
+
+	logs := captureWarnings(t)
+	settings := tenant.GetSettings()
+
+	if settings.MaxFaces != 5000 {
+		t.Errorf("MaxFaces = %d, want 5000", settings.MaxFaces)
+	}
+	if settings.MaxRequestsMonth != 0 {
+		t.Errorf("MaxRequestsMonth = %d, want 0", settings.MaxRequestsMonth)
+	}
+	if !strings.Contains(logs.String(), "setting=max_requests_month") {
+		t.Errorf("expected a warning for max_requests_month: %q", logs.String())
+	}
+}
+
+func TestQuotaWarningSettings_Level(t *testing.T) {
+	cfg := QuotaWarningSettings{Enabled: true, Percentages: []int{80, 90}}
+
+	tests := []struct {
+		name  string
+		cfg   QuotaWarningSettings
+		used  int64
+		quota int64
+		want  int
+	}{
+		{name: "below every percentage", cfg: cfg, used: 799, quota: 1000, want: 0},
+		{name: "at the first percentage", cfg: cfg, used: 800, quota: 1000, want: 80},
+		{name: "between percentages", cfg: cfg, used: 899, quota: 1000, want: 80},
+		{name: "at the last percentage", cfg: cfg, used: 900, quota: 1000, want: 90},
+		{name: "over the quota", cfg: cfg, used: 1200, quota: 1000, want: 90},
+		{name: "unlimited quota", cfg: cfg, used: 1200, quota: 0, want: 0},
+		{name: "disabled", cfg: QuotaWarningSettings{Percentages: []int{80}}, used: 900, quota: 1000, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.Level(tt.used, tt.quota); got != tt.want {
+				t.Errorf("Level(%d, %d) = %d, want %d", tt.used, tt.quota, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// Rate limit buckets reported to tenants
+const (
+	RateLimitBucketSearch   = "search"
+	RateLimitBucketVerify   = "verify"
+	RateLimitBucketRegister = "register"
+)
+
+// RateLimitState is a tenant's current usage of one rate limit bucket
+type RateLimitState struct {
+	Bucket    string     `json:"bucket"`
+	Limit     int        `json:"limit"` // 0 means unlimited
+	Count     int        `json:"count"`
+	Remaining *int       `json:"remaining,omitempty"` // nil when unlimited
+	ResetAt   *time.Time `json:"reset_at,omitempty"`  // nil when no window is open
+}
+
+// NewRateLimitState builds the state of a bucket; a zero resetAt means no window is open
+func NewRateLimitState(bucket string, limit, count int, resetAt time.Time) RateLimitState {
+	state := RateLimitState{Bucket: bucket, Limit: limit, Count: count}
+	if limit > 0 {
+		remaining := max(limit-count, 0)
+		state.Remaining = &remaining
+	}
+	if !resetAt.IsZero() {
+		state.ResetAt = &resetAt
+	}
+	return state
+}
@@ -20,8 +20,51 @@ type SearchResult struct {
 	TotalFaces int           `json:"total_faces"`
 	LatencyMs  int64         `json:"latency_ms"`
 	SearchID   uuid.UUID     `json:"search_id"`
+	// Identified is set when the top match reaches the tenant's search_identify_floor
+	Identified bool `json:"identified"`
+	// Ambiguous is set when the top two matches are closer than the tenant's search_margin
+	Ambiguous bool `json:"ambiguous"`
+	// Warning explains an adjustment made to the request, e.g. a clamped max_results
+	Warning string `json:"-"`
+	// Reason explains a result produced without searching, see SearchReasonEmptyCollection
+	Reason string `json:"reason,omitempty"`
+	// ThresholdApplied is the minimum similarity matches were filtered by, and ThresholdSource its layer
+	ThresholdApplied float64         `json:"threshold_applied"`
+	ThresholdSource  ThresholdSource `json:"threshold_source"`
+	// ProviderVariant is the provider experiment arm that ran the search, empty outside experiments
+	ProviderVariant string `json:"-"`
 }
 
+// AllowedMetadata returns the entries of the match metadata whose keys are in keys,
+// or nil when none are, so search responses only expose what the tenant allow-listed
+func (m SearchMatch) AllowedMetadata(keys []string) map[string]interface{} {
+	return AllowedMetadata(m.Metadata, keys)
+}
+
+// AllowedMetadata returns the entries of face metadata whose keys are in the tenant's
+// search_metadata_keys allow-list, or nil when none are
+func AllowedMetadata(metadata map[string]interface{}, keys []string) map[string]interface{} {
+	var allowed map[string]interface{}
+	for _, key := range keys {
+		value, ok := metadata[key]
+		if !ok {
+			continue
+		}
+		if allowed == nil {
+			allowed = make(map[string]interface{}, len(keys))
+		}
+		allowed[key] = value
+	}
+	return allowed
+}
+
+// SearchReasonEmptyCollection marks a search answered without a provider call
+// because the tenant has no registered faces
+const SearchReasonEmptyCollection = "empty_collection"
+
+// MaxSearchResults is the largest max_results a single search accepts
+const MaxSearchResults = 50
+
 // SearchAudit represents an audit log entry for search operations
 type SearchAudit struct {
 	ID                 uuid.UUID `json:"id"`
@@ -34,4 +77,34 @@ type SearchAudit struct {
 	LatencyMs          int64     `json:"latency_ms"`
 	ClientIP           string    `json:"client_ip"`
 	CreatedAt          time.Time `json:"created_at"`
+
+	// IsTest marks searches performed with a test-environment API key
+	IsTest bool `json:"-"`
+
+	// ProviderVariant is the provider experiment arm that ran the search, empty outside experiments
+	ProviderVariant string `json:"-"`
+}
+
+// Search audit listing defaults
+const (
+	DefaultSearchAuditLimit = 50
+	MaxSearchAuditLimit     = 100
+)
+
+// SearchAuditFilter selects a tenant's search audits created in [From, To)
+type SearchAuditFilter struct {
+	From   time.Time
+	To     time.Time
+	Limit  int
+	Offset int
+}
+
+// AmbiguousMatches reports whether the top two matches are less than margin apart, meaning two
+// enrolled people resemble the probe about equally. Matches are ordered by similarity, and a
+// margin of 0 disables the check.
+func AmbiguousMatches(matches []SearchMatch, margin float64) bool {
+	if margin <= 0 || len(matches) < 2 {
+		return false
+	}
+	return matches[0].Similarity-matches[1].Similarity < margin
 }
@@ -0,0 +1,34 @@
+package domain
+
+import "testing"
+
+func TestAmbiguousMatches(t *testing.T) {
+	matches := func(similarities ...float64) []SearchMatch {
+		out := make([]SearchMatch, len(similarities))
+		for i, s := range similarities {
+			out[i] = SearchMatch{Similarity: s}
+		}
+		return out
+	}
+
+	tests := []struct {
+		name    string
+		matches []SearchMatch
+		margin  float64
+		want    bool
+	}{
+		{"runner-up within the margin", matches(0.93, 0.91), 0.05, true},
+		{"runner-up outside the margin", matches(0.93, 0.85), 0.05, false},
+		{"single match", matches(0.93), 0.05, false},
+		{"no matches", nil, 0.05, false},
+		{"zero margin disables the check", matches(0.93, 0.93), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AmbiguousMatches(tt.matches, tt.margin); got != tt.want {
+				t.Errorf("AmbiguousMatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
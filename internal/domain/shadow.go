@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShadowOperation identifies the decision a shadow comparison was recorded for
+type ShadowOperation string
+
+const (
+	ShadowOperationVerify ShadowOperation = "verify"
+	ShadowOperationSearch ShadowOperation = "search"
+)
+
+// ShadowSkipIncompatibleModel marks a shadow run whose embedding model cannot be compared with
+// the primary's enrolments, the expected state while migrating to a provider with another model
+const ShadowSkipIncompatibleModel = "incompatible_model"
+
+// ShadowComparison records the primary and shadow provider decisions for one request.
+// Only outcomes and scores are kept, never images or embeddings.
+type ShadowComparison struct {
+	ID         uuid.UUID       `json:"id"`
+	TenantID   uuid.UUID       `json:"tenant_id"`
+	Operation  ShadowOperation `json:"operation"`
+	ExternalID *string         `json:"external_id,omitempty"` // verified identity (verify only)
+
+	PrimaryMatch     bool     `json:"primary_match"`
+	PrimaryScore     *float64 `json:"primary_score,omitempty"`
+	PrimaryTopMatch  *string  `json:"primary_top_match,omitempty"` // best match external ID (search only)
+	PrimaryLatencyMs int64    `json:"primary_latency_ms"`
+
+	ShadowMatch     *bool    `json:"shadow_match,omitempty"` // nil when the shadow provider failed or was skipped
+	ShadowScore     *float64 `json:"shadow_score,omitempty"`
+	ShadowTopMatch  *string  `json:"shadow_top_match,omitempty"`
+	ShadowLatencyMs int64    `json:"shadow_latency_ms"`
+	ShadowError     *string  `json:"shadow_error,omitempty"`
+	ShadowSkipped   *string  `json:"shadow_skipped,omitempty"` // e.g. ShadowSkipIncompatibleModel
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Skip records why the shadow decision was not compared with the primary's
+func (c *ShadowComparison) Skip(reason string) {
+	c.ShadowSkipped = &reason
+	c.ShadowMatch = nil
+}
+
+// DecisionDiffers reports whether the shadow provider reached a different decision than the primary.
+// For search a different top match counts as a different decision.
+func (c *ShadowComparison) DecisionDiffers() bool {
+	if c.ShadowMatch == nil {
+		return false
+	}
+	if *c.ShadowMatch != c.PrimaryMatch {
+		return true
+	}
+	if c.Operation == ShadowOperationSearch && c.PrimaryMatch {
+		return c.PrimaryTopMatch == nil || c.ShadowTopMatch == nil || *c.PrimaryTopMatch != *c.ShadowTopMatch
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package domain
+
+import "testing"
+
+func TestShadowComparison_DecisionDiffers(t *testing.T) {
+	yes, no := true, false
+	alice, bob := "alice", "bob"
+
+	tests := []struct {
+		name       string
+		comparison ShadowComparison
+		want       bool
+	}{
+		{
+			name:       "shadow failed",
+			comparison: ShadowComparison{Operation: ShadowOperationVerify, PrimaryMatch: true},
+			want:       false,
+		},
+		{
+			name:       "verify agrees",
+			comparison: ShadowComparison{Operation: ShadowOperationVerify, PrimaryMatch: true, ShadowMatch: &yes},
+			want:       false,
+		},
+		{
+			name:       "verify disagrees",
+			comparison: ShadowComparison{Operation: ShadowOperationVerify, PrimaryMatch: true, ShadowMatch: &no},
+			want:       true,
+		},
+		{
+			name:       "search finds nothing in both",
+			comparison: ShadowComparison{Operation: ShadowOperationSearch, PrimaryMatch: false, ShadowMatch: &no},
+			want:       false,
+		},
+		{
+			name: "search same top match",
+			comparison: ShadowComparison{
+				Operation: ShadowOperationSearch, PrimaryMatch: true, PrimaryTopMatch: &alice,
+				ShadowMatch: &yes, ShadowTopMatch: &alice,
+			},
+			want: false,
+		},
+		{
+			name: "search different top match",
+			comparison: ShadowComparison{
+				Operation: ShadowOperationSearch, PrimaryMatch: true, PrimaryTopMatch: &alice,
+				ShadowMatch: &yes, ShadowTopMatch: &bob,
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.comparison.DecisionDiffers(); got != tt.want {
+				t.Errorf("DecisionDiffers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,7 +2,10 @@ package domain
 
 import (
 	"errors"
+	"fmt"
 	"regexp"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -27,6 +30,49 @@ const (
 	SecurityMaximum SecurityLevel = "maximum"
 )
 
+// MultipleFacesPolicy defines how register/verify handle images with more than one face
+type MultipleFacesPolicy string
+
+const (
+	// MultipleFacesReject - Reject the image with MULTIPLE_FACES (default)
+	MultipleFacesReject MultipleFacesPolicy = "reject"
+	// MultipleFacesUseLargest - Proceed with the largest face (e.g. bystanders in gate cameras)
+	MultipleFacesUseLargest MultipleFacesPolicy = "use_largest"
+)
+
+// VerifyPolicy defines how the match and liveness results combine into the verify decision
+type VerifyPolicy string
+
+const (
+	// VerifyPolicyMatchOnly - Verified when the face matches; liveness is not checked (default)
+	VerifyPolicyMatchOnly VerifyPolicy = "match_only"
+	// VerifyPolicyMatchAndLiveness - Verified only when the face matches and is live
+	VerifyPolicyMatchAndLiveness VerifyPolicy = "match_and_liveness"
+	// VerifyPolicyMatchOrLiveness - Verified when either the face matches or is live
+	VerifyPolicyMatchOrLiveness VerifyPolicy = "match_or_liveness"
+)
+
+// EmbeddingPrecision defines how face embeddings are stored and searched
+type EmbeddingPrecision string
+
+const (
+	// EmbeddingPrecisionFull - 32-bit floats in a pgvector vector column (default)
+	EmbeddingPrecisionFull EmbeddingPrecision = "full"
+	// EmbeddingPrecisionHalf - 16-bit floats in a pgvector halfvec column: half the storage and
+	// index size, at a small recall cost
+	EmbeddingPrecisionHalf EmbeddingPrecision = "half"
+)
+
+// SearchAuditMode defines how the search audit is written
+type SearchAuditMode string
+
+const (
+	// SearchAuditAsync - Audit is written in the background; a failed write doesn't affect the search (default)
+	SearchAuditAsync SearchAuditMode = "async"
+	// SearchAuditSync - Audit is written before responding; a failed write fails the search with AUDIT_WRITE_FAILED
+	SearchAuditSync SearchAuditMode = "sync"
+)
+
 var (
 	validPlans = map[string]bool{
 		PlanStarter:    true,
@@ -37,6 +83,10 @@ var (
 	slugRegex = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
 )
 
+// SupportedImageFormats lists every image Content-Type the API accepts.
+// Tenants may narrow it with the allowed_image_formats setting.
+var SupportedImageFormats = []string{"image/jpeg", "image/png", "image/webp"}
+
 // IsValid checks if the security level is a valid value
 func (s SecurityLevel) IsValid() bool {
 	switch s {
@@ -47,6 +97,83 @@ func (s SecurityLevel) IsValid() bool {
 	}
 }
 
+// IsValid checks if the multiple faces policy is a valid value
+func (p MultipleFacesPolicy) IsValid() bool {
+	switch p {
+	case MultipleFacesReject, MultipleFacesUseLargest:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValid checks if the verify policy is a valid value
+func (p VerifyPolicy) IsValid() bool {
+	switch p {
+	case VerifyPolicyMatchOnly, VerifyPolicyMatchAndLiveness, VerifyPolicyMatchOrLiveness:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValid checks if the embedding precision is a valid value
+func (p EmbeddingPrecision) IsValid() bool {
+	switch p {
+	case EmbeddingPrecisionFull, EmbeddingPrecisionHalf:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValid checks if the search audit mode is a valid value
+func (m SearchAuditMode) IsValid() bool {
+	switch m {
+	case SearchAuditAsync, SearchAuditSync:
+		return true
+	default:
+		return false
+	}
+}
+
+// RequiresLiveness reports whether verify must run a liveness check under this policy
+func (p VerifyPolicy) RequiresLiveness() bool {
+	return p == VerifyPolicyMatchAndLiveness || p == VerifyPolicyMatchOrLiveness
+}
+
+// GuaranteesLiveness reports whether every verified decision under this policy passed liveness.
+// match_or_liveness can verify on the match alone, so it does not.
+func (p VerifyPolicy) GuaranteesLiveness() bool {
+	return p == VerifyPolicyMatchAndLiveness
+}
+
+// Decide combines the component results into the final decision.
+// livenessPassed is ignored by match_only.
+func (p VerifyPolicy) Decide(matchPassed, livenessPassed bool) bool {
+	switch p {
+	case VerifyPolicyMatchAndLiveness:
+		return matchPassed && livenessPassed
+	case VerifyPolicyMatchOrLiveness:
+		return matchPassed || livenessPassed
+	default:
+		return matchPassed
+	}
+}
+
+// FailReason explains a negative Decide, or returns "" when the attempt is verified.
+// A failed match is reported first, since a live but different face is the stronger signal.
+func (p VerifyPolicy) FailReason(matchPassed, livenessPassed bool) string {
+	switch {
+	case p.Decide(matchPassed, livenessPassed):
+		return ""
+	case !matchPassed:
+		return FailReasonMatchBelowThreshold
+	default:
+		return FailReasonLivenessFailed
+	}
+}
+
 // Tenant representa um cliente B2B do sistema
 type Tenant struct {
 	ID        uuid.UUID              `json:"id"`
@@ -61,18 +188,237 @@ type Tenant struct {
 
 // TenantSettings contém configurações específicas do tenant
 type TenantSettings struct {
-	VerificationThreshold float64       `json:"verification_threshold"`
-	MaxFacesPerUser       int           `json:"max_faces_per_user"`
-	RequireLiveness       bool          `json:"require_liveness"`
-	LivenessThreshold     float64       `json:"liveness_threshold"`
-	SearchEnabled         bool          `json:"search_enabled"`
-	SearchRequireLiveness bool          `json:"search_require_liveness"`
-	SearchThreshold       float64       `json:"search_threshold"`
-	SearchMaxResults      int           `json:"search_max_results"`
-	SearchRateLimit       int           `json:"search_rate_limit"`
-	SecurityLevel         SecurityLevel `json:"security_level"`
+	VerificationThreshold float64             `json:"verification_threshold"`
+	MaxFacesPerUser       int                 `json:"max_faces_per_user"`
+	RequireLiveness       bool                `json:"require_liveness"`
+	LivenessThreshold     float64             `json:"liveness_threshold"`
+	SearchEnabled         bool                `json:"search_enabled"`
+	SearchRequireLiveness bool                `json:"search_require_liveness"`
+	SearchThreshold       float64             `json:"search_threshold"`
+	SearchIdentifyFloor   float64             `json:"search_identify_floor"` // top match identifies only at or above; 0 = search_threshold
+	SearchMargin          float64             `json:"search_margin"`         // top-2 similarity gap below which a search is ambiguous; 0 = off
+	SearchMaxResults      int                 `json:"search_max_results"`
+	SearchRateLimit       int                 `json:"search_rate_limit"`
+	SearchByEmbedding     bool                `json:"search_by_embedding_enabled"`
+	ShadowProviderEnabled bool                `json:"shadow_provider_enabled"`
+	SecurityLevel         SecurityLevel       `json:"security_level"`
+	OnMultipleFaces       MultipleFacesPolicy `json:"on_multiple_faces"`
+	VerifyPolicy          VerifyPolicy        `json:"verify_policy"`
+	AllowedImageFormats   []string            `json:"allowed_image_formats"`
+	MinQuality            float64             `json:"min_quality"`
+
+	// SearchAuditMode sync makes compliance-sensitive tenants fail closed: the search audit is
+	// written before the result is returned and a failed write returns AUDIT_WRITE_FAILED
+	SearchAuditMode SearchAuditMode `json:"search_audit_mode"`
+
+	// EmbeddingPrecision half stores embeddings registered from now on as halfvec, for tenants
+	// with millions of faces; searches then cover faces stored at either precision
+	EmbeddingPrecision EmbeddingPrecision `json:"embedding_precision"`
+
+	// FaceEdgeMargin rejects register and verify images whose face box comes closer than this
+	// fraction of the image size to a border, as cut-off faces produce poor embeddings; 0 = off
+	FaceEdgeMargin float64 `json:"face_edge_margin"`
+
+	// FaceTTL expires faces this long after registration unless the registration sets its own
+	// ttl, e.g. for temporary events; 0 = faces never expire
+	FaceTTL time.Duration `json:"face_ttl"`
+
+	// StrictMultipartFields rejects API uploads carrying form fields the endpoint does not read
+	StrictMultipartFields bool `json:"strict_multipart_fields"`
+
+	// ClampMaxResults lowers a max_results above the ceiling to the ceiling instead of rejecting it
+	ClampMaxResults bool `json:"clamp_max_results"`
+
+	// SearchMetadataKeys lists the face metadata keys returned with API search matches; the rest
+	// stays server-side. Empty returns no metadata. The public widget search never returns metadata.
+	SearchMetadataKeys []string `json:"search_metadata_keys"`
+
+	// SearchAmbiguousUnidentified keeps an ambiguous search (see SearchMargin) from counting as
+	// an identification, so a human can decide between the candidates
+	SearchAmbiguousUnidentified bool `json:"search_ambiguous_unidentified"`
+
+	// AntiPassback rejects a verify at another gate right after a successful one, see AntiPassbackSettings
+	AntiPassback AntiPassbackSettings `json:"anti_passback"`
+
+	// VerifyHysteresis accepts a borderline retry of a user who passed recently, see VerifyHysteresisSettings
+	VerifyHysteresis VerifyHysteresisSettings `json:"verify_hysteresis"`
+
+	// Maintenance pauses recognition for the tenant, see MaintenanceSettings
+	Maintenance MaintenanceSettings `json:"maintenance"`
+
+	// ReplayDetection flags widget liveness sessions whose frames look replayed, see ReplayDetectionSettings
+	ReplayDetection ReplayDetectionSettings `json:"replay_detection"`
+
+	// SegmentThresholds overrides thresholds by a face metadata value, see SegmentThresholdSettings
+	SegmentThresholds SegmentThresholdSettings `json:"segment_thresholds"`
+
+	// MaxFaces and MaxRequestsMonth are the tenant's face and monthly request quotas; 0 = unlimited
+	MaxFaces         int `json:"max_faces"`
+	MaxRequestsMonth int `json:"max_requests_month"`
+
+	// ProviderExperiment decides a share of requests with the alternate provider, see ProviderExperimentSettings
+	ProviderExperiment ProviderExperimentSettings `json:"provider_experiment"`
+
+	// QuotaWarnings warns before MaxFaces or MaxRequestsMonth is reached, see QuotaWarningSettings
+	QuotaWarnings QuotaWarningSettings `json:"quota_warnings"`
+
+	// RecordRejectedVerifications stores verify attempts rejected for no face, multiple faces or
+	// anti-passback as failed verifications, so they show up in the verification history and metrics
+	RecordRejectedVerifications bool `json:"record_rejected_verifications"`
+
+	// SecurityLevels holds per-level overrides; the active level's block wins over the flat keys
+	SecurityLevels map[SecurityLevel]SecurityLevelSettings `json:"security_levels,omitempty"`
+
+	// Layers the verification and search thresholds were resolved from, see ThresholdResolutions
+	VerificationThresholdSource ThresholdSource `json:"-"`
+	SearchThresholdSource       ThresholdSource `json:"-"`
+
+	// Re-enrollment suggestion when verify confidence drifts below the enrolled quality
+	ReenrollCheckEnabled bool    `json:"reenroll_check_enabled"`
+	ReenrollMargin       float64 `json:"reenroll_margin"`
+	ReenrollWindow       int     `json:"reenroll_window"`
+
+	// EmbeddingHashEnabled returns each face's embedding_hash in face responses, so clients can
+	// tell whether a re-enrollment changed the biometric
+	EmbeddingHashEnabled bool `json:"embedding_hash_enabled"`
+
+	// FaceExportEnabled allows GET /v1/admin/faces/export, which hands out every face record
+	// and, on request, the embeddings
+	FaceExportEnabled bool `json:"face_export_enabled"`
+
+	// DataRegion pins biometric data to a region (e.g. "eu-west-1"); empty means unrestricted
+	DataRegion string `json:"data_region,omitempty"`
+
+	// Widget self-enrollment liveness, inherits require_liveness/liveness_threshold when unset
+	WidgetRegisterRequireLiveness   bool    `json:"widget_register_require_liveness"`
+	WidgetRegisterLivenessThreshold float64 `json:"widget_register_liveness_threshold"`
+
+	// WidgetCheckSigned requires GET /v1/widget/check to carry a signature of the external_id made
+	// with WidgetCheckSecret (see SignWidgetCheck), so browsers cannot enumerate registered IDs.
+	// It is set whenever widget_check_secret is present; a secret that is too short rejects every check.
+	WidgetCheckSigned bool   `json:"widget_check_signed"`
+	WidgetCheckSecret string `json:"-"`
 }
 
+// MaxAntiPassbackWindow caps the anti-passback window
+const MaxAntiPassbackWindow = 24 * time.Hour
+
+// AntiPassbackSettings stops ticket sharing at venues: once an external_id passes a verify at
+// one gate (client IP), a verify from a different gate within Window is rejected with
+// ANTI_PASSBACK_VIOLATION. Configured as {"anti_passback": {"window": "10m"}}; 0 = off.
+type AntiPassbackSettings struct {
+	Window time.Duration `json:"window"`
+}
+
+// Enabled reports whether verifies are checked against the last gate
+func (a AntiPassbackSettings) Enabled() bool {
+	return a.Window > 0
+}
+
+// Caps of the verification hysteresis
+const (
+	MaxVerifyHysteresisMargin = 0.1
+	MaxVerifyHysteresisWindow = 24 * time.Hour
+)
+
+// VerifyHysteresisSettings stops borderline retries from flapping between allow and deny: a verify
+// whose confidence is below the threshold by at most Margin still passes the match when the
+// external_id passed a verification within Window; a verify passed this way counts for the next
+// one. Configured as {"verify_hysteresis": {"margin": 0.03, "window": "10m"}}; off unless both are set.
+type VerifyHysteresisSettings struct {
+	Margin float64       `json:"margin"`
+	Window time.Duration `json:"window"`
+}
+
+// Enabled reports whether borderline verifies are checked against the last passed one
+func (h VerifyHysteresisSettings) Enabled() bool {
+	return h.Margin > 0 && h.Window > 0
+}
+
+// Retry-After bounds of the maintenance mode
+const (
+	DefaultMaintenanceRetryAfter = 5 * time.Minute
+	MaxMaintenanceRetryAfter     = 24 * time.Hour
+)
+
+// MaintenanceSettings pauses a tenant's provider during a migration or incident: recognition
+// requests get 503 MAINTENANCE with Retry-After, while configuration and read endpoints keep
+// working. Configured as {"maintenance": {"enabled": true, "retry_after": "15m"}}.
+type MaintenanceSettings struct {
+	Enabled    bool          `json:"enabled"`
+	RetryAfter time.Duration `json:"retry_after"`
+}
+
+// Check returns MAINTENANCE for recognition requests while maintenance is on
+func (m MaintenanceSettings) Check() error {
+	if !m.Enabled {
+		return nil
+	}
+	return ErrMaintenance.WithRetryAfter(m.RetryAfter)
+}
+
+// ParseMaintenanceRetryAfter parses a maintenance retry_after, which must be a positive
+// duration within MaxMaintenanceRetryAfter
+func ParseMaintenanceRetryAfter(value string) (time.Duration, error) {
+	d, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid retry_after %q: %w", value, err)
+	}
+	if d <= 0 || d > MaxMaintenanceRetryAfter {
+		return 0, fmt.Errorf("retry_after must be between 1s and %s, got %s", MaxMaintenanceRetryAfter, d)
+	}
+	return d, nil
+}
+
+// Defaults and caps of the replay detection
+const (
+	DefaultReplayMinFrames      = 3
+	DefaultReplayMinScoreStdDev = 0.005
+	DefaultReplayMaxFrameDiff   = 2.0
+	MaxReplayMinFrames          = 10
+)
+
+// ReplayDetectionSettings flags replayed videos and photos in the widget's active liveness flow,
+// which yield suspiciously consistent frames. Once a session has sent MinFrames liveness frames,
+// it is rejected with REPLAY_SUSPECTED when the standard deviation of the liveness scores of the
+// last MinFrames frames is below MinScoreStdDev, or when each of those frames differs from the
+// previous one by less than MaxFrameDiff (mean absolute luminance difference, 0-255, of small
+// grayscale thumbnails). A threshold of 0 disables its check. Configured as
+// {"replay_detection": {"enabled": true, "min_frames": 3, "min_score_stddev": 0.005, "max_frame_diff": 2}}.
+type ReplayDetectionSettings struct {
+	Enabled        bool    `json:"enabled"`
+	MinFrames      int     `json:"min_frames"`
+	MinScoreStdDev float64 `json:"min_score_stddev"`
+	MaxFrameDiff   float64 `json:"max_frame_diff"`
+}
+
+// SecurityLevelSettings overrides thresholds for one security level.
+// Nil fields keep the value from the flat settings keys.
+type SecurityLevelSettings struct {
+	VerifyThreshold *float64 `json:"verify_threshold,omitempty"`
+	SearchThreshold *float64 `json:"search_threshold,omitempty"`
+	RequireLiveness *bool    `json:"require_liveness,omitempty"`
+	MinQuality      *float64 `json:"min_quality,omitempty"`
+}
+
+// Identifies reports whether a search match is strong enough to be shown as an identification
+// rather than a candidate. Matches below search_identify_floor stay candidates.
+func (s TenantSettings) Identifies(similarity float64) bool {
+	return similarity >= s.SearchIdentifyFloor
+}
+
+// IdentifiesResult reports whether a search result is an identification: its top match reaches
+// search_identify_floor, and it is not ambiguous for tenants with search_ambiguous_unidentified
+func (s TenantSettings) IdentifiesResult(result *SearchResult) bool {
+	if len(result.Matches) == 0 || !s.Identifies(result.Matches[0].Similarity) {
+		return false
+	}
+	return !(result.Ambiguous && s.SearchAmbiguousUnidentified)
+}
+
+// MaxReenrollWindow caps how many recent verifications the re-enrollment check reads
+const MaxReenrollWindow = 50
+
 // DefaultTenantSettings retorna configurações padrão
 func DefaultTenantSettings() TenantSettings {
 	return TenantSettings{
@@ -85,56 +431,501 @@ func DefaultTenantSettings() TenantSettings {
 		SearchThreshold:       0.85,
 		SearchMaxResults:      10,
 		SearchRateLimit:       30,
+		SearchByEmbedding:     false,
+		ShadowProviderEnabled: false,
 		SecurityLevel:         SecurityStandard,
+		OnMultipleFaces:       MultipleFacesReject,
+		VerifyPolicy:          VerifyPolicyMatchOnly,
+		AllowedImageFormats:   SupportedImageFormats,
+		MinQuality:            0,
+		SearchAuditMode:       SearchAuditAsync,
+		EmbeddingPrecision:    EmbeddingPrecisionFull,
+
+		VerificationThresholdSource: ThresholdSourceDefault,
+		SearchThresholdSource:       ThresholdSourceDefault,
+
+		ReenrollCheckEnabled: false,
+		ReenrollMargin:       0.1,
+		ReenrollWindow:       5,
+
+		Maintenance:   MaintenanceSettings{RetryAfter: DefaultMaintenanceRetryAfter},
+		QuotaWarnings: QuotaWarningSettings{Enabled: true, Percentages: DefaultQuotaWarningPercentages},
+		ReplayDetection: ReplayDetectionSettings{
+			MinFrames:      DefaultReplayMinFrames,
+			MinScoreStdDev: DefaultReplayMinScoreStdDev,
+			MaxFrameDiff:   DefaultReplayMaxFrameDiff,
+		},
+
+		WidgetRegisterRequireLiveness:   false,
+		WidgetRegisterLivenessThreshold: 0.90,
 	}
 }
 
 // GetSettings returns typed tenant settings with defaults for missing values
 func (t *Tenant) GetSettings() TenantSettings {
-	defaults := DefaultTenantSettings()
-
 	if t.Settings == nil {
-		return defaults
+		return DefaultTenantSettings()
 	}
 
-	// Parse each setting with type assertion and fallback to default
-	if v, ok := t.Settings["verification_threshold"].(float64); ok {
+	// Parse each setting defensively; unusable values keep the default and log a warning
+	return parseSettings(settingsReader{tenantID: t.ID, values: t.Settings})
+}
+
+// ValidateTenantSettings rejects settings that GetSettings would ignore for an unusable value,
+// e.g. before importing a configuration. Unknown keys are accepted.
+func ValidateTenantSettings(settings map[string]interface{}) error {
+	var invalid []string
+	parseSettings(settingsReader{values: settings, invalid: &invalid})
+	if len(invalid) == 0 {
+		return nil
+	}
+
+	slices.Sort(invalid)
+	return fmt.Errorf("invalid tenant settings: %s", strings.Join(slices.Compact(invalid), ", "))
+}
+
+// parseSettings reads every known setting over the defaults
+func parseSettings(r settingsReader) TenantSettings {
+	defaults := DefaultTenantSettings()
+	if v, ok := r.Float("verification_threshold"); ok {
 		defaults.VerificationThreshold = v
+		defaults.VerificationThresholdSource = ThresholdSourceTenant
 	}
-	if v, ok := t.Settings["max_faces_per_user"].(float64); ok {
-		defaults.MaxFacesPerUser = int(v)
+	if v, ok := r.Int("max_faces_per_user"); ok {
+		defaults.MaxFacesPerUser = v
 	}
-	if v, ok := t.Settings["require_liveness"].(bool); ok {
+	if v, ok := r.Bool("require_liveness"); ok {
 		defaults.RequireLiveness = v
 	}
-	if v, ok := t.Settings["liveness_threshold"].(float64); ok {
+	if v, ok := r.Float("liveness_threshold"); ok {
 		defaults.LivenessThreshold = v
 	}
-	if v, ok := t.Settings["search_enabled"].(bool); ok {
+	if v, ok := r.Bool("search_enabled"); ok {
 		defaults.SearchEnabled = v
 	}
-	if v, ok := t.Settings["search_require_liveness"].(bool); ok {
+	if v, ok := r.Bool("search_require_liveness"); ok {
 		defaults.SearchRequireLiveness = v
 	}
-	if v, ok := t.Settings["search_threshold"].(float64); ok {
+	if v, ok := r.Float("search_threshold"); ok {
 		defaults.SearchThreshold = v
+		defaults.SearchThresholdSource = ThresholdSourceTenant
+	}
+	if v := r.Ratio("search_identify_floor"); v != nil {
+		defaults.SearchIdentifyFloor = *v
+	}
+	if v := r.Ratio("search_margin"); v != nil {
+		defaults.SearchMargin = *v
 	}
-	if v, ok := t.Settings["search_max_results"].(float64); ok {
-		defaults.SearchMaxResults = int(v)
+	if v, ok := r.Bool("search_ambiguous_unidentified"); ok {
+		defaults.SearchAmbiguousUnidentified = v
 	}
-	if v, ok := t.Settings["search_rate_limit"].(float64); ok {
-		defaults.SearchRateLimit = int(v)
+	if v, ok := r.Int("search_max_results"); ok {
+		defaults.SearchMaxResults = v
+	}
+	if v, ok := r.Int("search_rate_limit"); ok {
+		defaults.SearchRateLimit = v
+	}
+	if v, ok := r.Bool("search_by_embedding_enabled"); ok {
+		defaults.SearchByEmbedding = v
+	}
+	if v, ok := r.Bool("shadow_provider_enabled"); ok {
+		defaults.ShadowProviderEnabled = v
+	}
+	if v, ok := r.Bool("reenroll_check_enabled"); ok {
+		defaults.ReenrollCheckEnabled = v
+	}
+	if v, ok := r.Bool("embedding_hash_enabled"); ok {
+		defaults.EmbeddingHashEnabled = v
+	}
+	if v, ok := r.Bool("face_export_enabled"); ok {
+		defaults.FaceExportEnabled = v
+	}
+	if v, ok := r.Float("reenroll_margin"); ok {
+		if v >= 0 && v <= 1 {
+			defaults.ReenrollMargin = v
+		} else {
+			r.warn("reenroll_margin", v)
+		}
 	}
-	if v, ok := t.Settings["security_level"].(string); ok {
+	if v, ok := r.Int("reenroll_window"); ok {
+		if v >= 1 && v <= MaxReenrollWindow {
+			defaults.ReenrollWindow = v
+		} else {
+			r.warn("reenroll_window", v)
+		}
+	}
+	if v, ok := r.String("security_level"); ok {
 		secLevel := SecurityLevel(v)
 		if secLevel.IsValid() {
 			defaults.SecurityLevel = secLevel
+		} else {
+			r.warn("security_level", v)
+		}
+	}
+	if v, ok := r.String("on_multiple_faces"); ok {
+		policy := MultipleFacesPolicy(v)
+		if policy.IsValid() {
+			defaults.OnMultipleFaces = policy
+		} else {
+			r.warn("on_multiple_faces", v)
+		}
+	}
+	if v, ok := r.String("verify_policy"); ok {
+		policy := VerifyPolicy(v)
+		if policy.IsValid() {
+			defaults.VerifyPolicy = policy
+		} else {
+			r.warn("verify_policy", v)
+		}
+	}
+	if v, ok := r.String("search_audit_mode"); ok {
+		mode := SearchAuditMode(v)
+		if mode.IsValid() {
+			defaults.SearchAuditMode = mode
+		} else {
+			r.warn("search_audit_mode", v)
+		}
+	}
+	if v, ok := r.String("embedding_precision"); ok {
+		precision := EmbeddingPrecision(v)
+		if precision.IsValid() {
+			defaults.EmbeddingPrecision = precision
+		} else {
+			r.warn("embedding_precision", v)
+		}
+	}
+	if v, ok := r.String("data_region"); ok {
+		defaults.DataRegion = strings.ToLower(strings.TrimSpace(v))
+	}
+	if v, ok := r.List("allowed_image_formats"); ok {
+		formats, unknown := parseImageFormats(v)
+		// A typo must not quietly narrow the allow-list, so every unknown entry is reported
+		for _, u := range unknown {
+			r.warn("allowed_image_formats", u)
+		}
+		if len(formats) > 0 {
+			defaults.AllowedImageFormats = formats
+		}
+	}
+	if v, ok := r.Bool("strict_multipart_fields"); ok {
+		defaults.StrictMultipartFields = v
+	}
+	if v, ok := r.Bool("clamp_max_results"); ok {
+		defaults.ClampMaxResults = v
+	}
+	if v, ok := r.List("search_metadata_keys"); ok {
+		defaults.SearchMetadataKeys = parseMetadataKeys(v)
+	}
+	if v, ok := r.Bool("record_rejected_verifications"); ok {
+		defaults.RecordRejectedVerifications = v
+	}
+	if v, ok := r.Int("max_faces"); ok {
+		if v >= 0 {
+			defaults.MaxFaces = v
+		} else {
+			r.warn("max_faces", v)
+		}
+	}
+	if v, ok := r.Int("max_requests_month"); ok {
+		if v >= 0 {
+			defaults.MaxRequestsMonth = v
+		} else {
+			r.warn("max_requests_month", v)
+		}
+	}
+	if v, ok := r.Float("min_quality"); ok {
+		if v >= 0 && v <= 1 {
+			defaults.MinQuality = v
+		} else {
+			r.warn("min_quality", v)
+		}
+	}
+	// A margin of half the image or more would leave no room for any face
+	if v, ok := r.Float("face_edge_margin"); ok {
+		if v >= 0 && v < 0.5 {
+			defaults.FaceEdgeMargin = v
+		} else {
+			r.warn("face_edge_margin", v)
+		}
+	}
+	if v, ok := r.String("face_ttl"); ok {
+		if ttl, err := ParseFaceTTL(v); err == nil {
+			defaults.FaceTTL = ttl
+		} else {
+			r.warn("face_ttl", v)
+		}
+	}
+
+	if block, ok := r.Map("anti_passback"); ok {
+		defaults.AntiPassback = parseAntiPassback(block)
+	}
+	if block, ok := r.Map("verify_hysteresis"); ok {
+		defaults.VerifyHysteresis = parseVerifyHysteresis(block)
+	}
+	if block, ok := r.Map("maintenance"); ok {
+		defaults.Maintenance = parseMaintenance(block)
+	}
+	if block, ok := r.Map("replay_detection"); ok {
+		defaults.ReplayDetection = parseReplayDetection(block, defaults.ReplayDetection)
+	}
+	if block, ok := r.Map("segment_thresholds"); ok {
+		defaults.SegmentThresholds = parseSegmentThresholds(block)
+	}
+	if block, ok := r.Map("provider_experiment"); ok {
+		defaults.ProviderExperiment = parseProviderExperiment(block)
+	}
+	if block, ok := r.Map("quota_warnings"); ok {
+		defaults.QuotaWarnings = parseQuotaWarnings(block, defaults.QuotaWarnings)
+	}
+
+	// Feature flags win over the flat keys, so they are read after them
+	if block, ok := r.Map("feature_flags"); ok {
+		parseFeatureFlags(block, &defaults)
+	}
+
+	// Structured per-level thresholds override the flat keys for the active level
+	if levels, ok := r.Map("security_levels"); ok {
+		defaults.SecurityLevels = parseSecurityLevels(levels)
+		defaults.applySecurityLevel()
+	}
+
+	// Widget register falls back to the main register liveness settings
+	defaults.WidgetRegisterRequireLiveness = defaults.RequireLiveness
+	defaults.WidgetRegisterLivenessThreshold = defaults.LivenessThreshold
+	if v, ok := r.Bool("widget_register_require_liveness"); ok {
+		defaults.WidgetRegisterRequireLiveness = v
+	}
+	if v, ok := r.Float("widget_register_liveness_threshold"); ok {
+		defaults.WidgetRegisterLivenessThreshold = v
+	}
+
+	// A secret that is present but unusable still requires signatures, so a typo never opens the check
+	if _, ok := r.lookup("widget_check_secret"); ok {
+		defaults.WidgetCheckSigned = true
+		if v, ok := r.String("widget_check_secret"); ok {
+			if len(v) >= MinWidgetCheckSecretLength {
+				defaults.WidgetCheckSecret = v
+			} else {
+				r.warn("widget_check_secret", "(too short)")
+			}
 		}
 	}
 
 	return defaults
 }
 
+// parseAntiPassback reads the anti_passback block, ignoring a window that is not a duration
+// within MaxAntiPassbackWindow
+func parseAntiPassback(block settingsReader) AntiPassbackSettings {
+	var cfg AntiPassbackSettings
+	if v, ok := block.String("window"); ok {
+		window, err := time.ParseDuration(v)
+		if err == nil && window >= 0 && window <= MaxAntiPassbackWindow {
+			cfg.Window = window
+		} else {
+			block.warn("window", v)
+		}
+	}
+	return cfg
+}
+
+// parseMaintenance reads the maintenance block, keeping DefaultMaintenanceRetryAfter for a
+// retry_after that ParseMaintenanceRetryAfter rejects
+func parseMaintenance(block settingsReader) MaintenanceSettings {
+	cfg := MaintenanceSettings{RetryAfter: DefaultMaintenanceRetryAfter}
+	if v, ok := block.Bool("enabled"); ok {
+		cfg.Enabled = v
+	}
+	if v, ok := block.String("retry_after"); ok {
+		if d, err := ParseMaintenanceRetryAfter(v); err == nil {
+			cfg.RetryAfter = d
+		} else {
+			block.warn("retry_after", v)
+		}
+	}
+	return cfg
+}
+
+// parseReplayDetection reads the replay_detection block over the defaults, ignoring min_frames
+// outside 2-MaxReplayMinFrames and negative thresholds
+func parseReplayDetection(block settingsReader, cfg ReplayDetectionSettings) ReplayDetectionSettings {
+	if v, ok := block.Bool("enabled"); ok {
+		cfg.Enabled = v
+	}
+	if v, ok := block.Int("min_frames"); ok {
+		if v >= 2 && v <= MaxReplayMinFrames {
+			cfg.MinFrames = v
+		} else {
+			block.warn("min_frames", v)
+		}
+	}
+	if v, ok := block.Float("min_score_stddev"); ok {
+		if v >= 0 {
+			cfg.MinScoreStdDev = v
+		} else {
+			block.warn("min_score_stddev", v)
+		}
+	}
+	if v, ok := block.Float("max_frame_diff"); ok {
+		if v >= 0 {
+			cfg.MaxFrameDiff = v
+		} else {
+			block.warn("max_frame_diff", v)
+		}
+	}
+	return cfg
+}
+
+// parseSegmentThresholds reads the segment_thresholds block, ignoring thresholds outside 0-1.
+// Without a key no face belongs to a segment, so the overrides are dropped.
+func parseSegmentThresholds(block settingsReader) SegmentThresholdSettings {
+	var cfg SegmentThresholdSettings
+	key, ok := block.String("key")
+	if !ok || strings.TrimSpace(key) == "" {
+		block.warn("key", key)
+		return cfg
+	}
+	cfg.Key = strings.TrimSpace(key)
+
+	segments := func(name string) map[string]float64 {
+		values, ok := block.Map(name)
+		if !ok {
+			return nil
+		}
+		parsed := make(map[string]float64, len(values.values))
+		for segment := range values.values {
+			if threshold := values.Ratio(segment); threshold != nil {
+				parsed[segment] = *threshold
+			}
+		}
+		return parsed
+	}
+	cfg.Verify = segments("verify")
+	cfg.Search = segments("search")
+	return cfg
+}
+
+// parseVerifyHysteresis reads the verify_hysteresis block, ignoring a margin above
+// MaxVerifyHysteresisMargin and a window that is not a duration within MaxVerifyHysteresisWindow
+func parseVerifyHysteresis(block settingsReader) VerifyHysteresisSettings {
+	var cfg VerifyHysteresisSettings
+	if v, ok := block.Float("margin"); ok {
+		if v >= 0 && v <= MaxVerifyHysteresisMargin {
+			cfg.Margin = v
+		} else {
+			block.warn("margin", v)
+		}
+	}
+	if v, ok := block.String("window"); ok {
+		window, err := time.ParseDuration(v)
+		if err == nil && window >= 0 && window <= MaxVerifyHysteresisWindow {
+			cfg.Window = window
+		} else {
+			block.warn("window", v)
+		}
+	}
+	return cfg
+}
+
+// parseSecurityLevels reads the security_levels block, ignoring unknown levels and
+// out-of-range values so they fall back to the flat keys
+func parseSecurityLevels(levels settingsReader) map[SecurityLevel]SecurityLevelSettings {
+	parsed := make(map[SecurityLevel]SecurityLevelSettings)
+	for name := range levels.values {
+		level := SecurityLevel(name)
+		if !level.IsValid() {
+			levels.warn(name, levels.values[name])
+			continue
+		}
+
+		block, ok := levels.Map(name)
+		if !ok {
+			continue
+		}
+
+		var cfg SecurityLevelSettings
+		cfg.VerifyThreshold = block.Ratio("verify_threshold")
+		cfg.SearchThreshold = block.Ratio("search_threshold")
+		cfg.MinQuality = block.Ratio("min_quality")
+		if v, ok := block.Bool("require_liveness"); ok {
+			cfg.RequireLiveness = &v
+		}
+		parsed[level] = cfg
+	}
+	return parsed
+}
+
+// applySecurityLevel resolves the active level's overrides onto the effective settings
+func (s *TenantSettings) applySecurityLevel() {
+	cfg, ok := s.SecurityLevels[s.SecurityLevel]
+	if !ok {
+		return
+	}
+	if cfg.VerifyThreshold != nil {
+		s.VerificationThreshold = *cfg.VerifyThreshold
+		s.VerificationThresholdSource = ThresholdSourceSecurityLevel
+	}
+	if cfg.SearchThreshold != nil {
+		s.SearchThreshold = *cfg.SearchThreshold
+		s.SearchThresholdSource = ThresholdSourceSecurityLevel
+	}
+	if cfg.RequireLiveness != nil {
+		s.RequireLiveness = *cfg.RequireLiveness
+	}
+	if cfg.MinQuality != nil {
+		s.MinQuality = *cfg.MinQuality
+	}
+}
+
+// parseImageFormats normalizes configured formats ("jpeg" or "image/jpeg") to Content-Types,
+// dropping duplicates. Entries outside SupportedImageFormats are returned as unknown.
+func parseImageFormats(values []interface{}) (formats []string, unknown []interface{}) {
+	formats = make([]string, 0, len(values))
+	for _, v := range values {
+		name, ok := v.(string)
+		if !ok {
+			unknown = append(unknown, v)
+			continue
+		}
+		contentType := strings.ToLower(strings.TrimSpace(name))
+		if !strings.HasPrefix(contentType, "image/") {
+			contentType = "image/" + contentType
+		}
+		if !slices.Contains(SupportedImageFormats, contentType) {
+			unknown = append(unknown, v)
+			continue
+		}
+		if !slices.Contains(formats, contentType) {
+			formats = append(formats, contentType)
+		}
+	}
+	return formats, unknown
+}
+
+// parseMetadataKeys keeps the distinct non-empty string keys of an allow-list
+func parseMetadataKeys(values []interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for _, v := range values {
+		key, ok := v.(string)
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if key != "" && !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// ForWidgetRegister returns the settings used for widget self-enrollment,
+// replacing the register liveness gate with the widget-specific one
+func (s TenantSettings) ForWidgetRegister() TenantSettings {
+	s.RequireLiveness = s.WidgetRegisterRequireLiveness
+	s.LivenessThreshold = s.WidgetRegisterLivenessThreshold
+	return s
+}
+
 // Validate verifica se o tenant é válido
 func (t *Tenant) Validate() error {
 	if t.Name == "" {
@@ -0,0 +1,188 @@
+package domain
+
+import (
+	"encoding/json"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// settingsReader extracts typed values from raw tenant settings.
+// JSONB round-trips and manual edits can leave numbers as strings or ints, so values are
+// coerced where unambiguous; anything else is ignored with a warning so the default applies.
+type settingsReader struct {
+	tenantID uuid.UUID
+	values   map[string]interface{}
+	prefix   string // path of a nested block, e.g. "security_levels."
+	// invalid collects unusable settings instead of logging them, see ValidateTenantSettings
+	invalid *[]string
+}
+
+// Float returns the setting as float64, accepting any numeric type or a numeric string
+func (r settingsReader) Float(key string) (float64, bool) {
+	raw, ok := r.lookup(key)
+	if !ok {
+		return 0, false
+	}
+
+	var f float64
+	switch v := raw.(type) {
+	case float64:
+		f = v
+	case float32:
+		f = float64(v)
+	case int:
+		f = float64(v)
+	case int32:
+		f = float64(v)
+	case int64:
+		f = float64(v)
+	case json.Number:
+		parsed, err := v.Float64()
+		if err != nil {
+			r.warn(key, raw)
+			return 0, false
+		}
+		f = parsed
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			r.warn(key, raw)
+			return 0, false
+		}
+		f = parsed
+	default:
+		r.warn(key, raw)
+		return 0, false
+	}
+
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		r.warn(key, raw)
+		return 0, false
+	}
+	return f, true
+}
+
+// Int returns the setting as int, rejecting numbers with a fractional part
+func (r settingsReader) Int(key string) (int, bool) {
+	f, ok := r.Float(key)
+	if !ok {
+		return 0, false
+	}
+	if f != math.Trunc(f) {
+		r.warn(key, r.values[key])
+		return 0, false
+	}
+	return int(f), true
+}
+
+// Bool returns the setting as bool, accepting "true"/"false" strings
+func (r settingsReader) Bool(key string) (bool, bool) {
+	raw, ok := r.lookup(key)
+	if !ok {
+		return false, false
+	}
+
+	switch v := raw.(type) {
+	case bool:
+		return v, true
+	case string:
+		parsed, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			r.warn(key, raw)
+			return false, false
+		}
+		return parsed, true
+	default:
+		r.warn(key, raw)
+		return false, false
+	}
+}
+
+// String returns the setting when it is a string
+func (r settingsReader) String(key string) (string, bool) {
+	raw, ok := r.lookup(key)
+	if !ok {
+		return "", false
+	}
+	v, ok := raw.(string)
+	if !ok {
+		r.warn(key, raw)
+		return "", false
+	}
+	return v, true
+}
+
+// List returns the setting when it is a JSON array
+func (r settingsReader) List(key string) ([]interface{}, bool) {
+	raw, ok := r.lookup(key)
+	if !ok {
+		return nil, false
+	}
+
+	switch v := raw.(type) {
+	case []interface{}:
+		return v, true
+	case []string:
+		list := make([]interface{}, len(v))
+		for i, s := range v {
+			list[i] = s
+		}
+		return list, true
+	default:
+		r.warn(key, raw)
+		return nil, false
+	}
+}
+
+// Ratio returns a pointer to the setting when it is a number between 0 and 1, nil otherwise
+func (r settingsReader) Ratio(key string) *float64 {
+	v, ok := r.Float(key)
+	if !ok {
+		return nil
+	}
+	if v < 0 || v > 1 {
+		r.warn(key, v)
+		return nil
+	}
+	return &v
+}
+
+// Map returns a reader for a nested JSON object setting
+func (r settingsReader) Map(key string) (settingsReader, bool) {
+	raw, ok := r.lookup(key)
+	if !ok {
+		return settingsReader{}, false
+	}
+	v, ok := raw.(map[string]interface{})
+	if !ok {
+		r.warn(key, raw)
+		return settingsReader{}, false
+	}
+	return settingsReader{tenantID: r.tenantID, values: v, prefix: r.prefix + key + ".", invalid: r.invalid}, true
+}
+
+// lookup returns the raw value; missing and null settings silently use the default
+func (r settingsReader) lookup(key string) (interface{}, bool) {
+	v, ok := r.values[key]
+	if !ok || v == nil {
+		return nil, false
+	}
+	return v, true
+}
+
+// warn logs an unusable setting value so falling back to the default is visible
+func (r settingsReader) warn(key string, value interface{}) {
+	if r.invalid != nil {
+		*r.invalid = append(*r.invalid, r.prefix+key)
+		return
+	}
+	slog.Warn("invalid tenant setting, using default",
+		"tenant_id", r.tenantID,
+		"setting", r.prefix+key,
+		"value", value,
+	)
+}
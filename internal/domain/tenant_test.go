@@ -1,6 +1,13 @@
 package domain
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -423,3 +430,750 @@ func TestTenant_GetSettings_SecurityLevel(t *testing.T) {
 		})
 	}
 }
+
+func TestTenant_GetSettings_WidgetRegisterLiveness(t *testing.T) {
+	tests := []struct {
+		name          string
+		settings      map[string]interface{}
+		wantRequire   bool
+		wantThreshold float64
+	}{
+		{
+			name:          "defaults",
+			settings:      map[string]interface{}{},
+			wantRequire:   false,
+			wantThreshold: 0.90,
+		},
+		{
+			name: "inherits main register settings when unset",
+			settings: map[string]interface{}{
+				"require_liveness":   true,
+				"liveness_threshold": 0.7,
+			},
+			wantRequire:   true,
+			wantThreshold: 0.7,
+		},
+		{
+			name: "widget settings override main register settings",
+			settings: map[string]interface{}{
+				"require_liveness":                   false,
+				"liveness_threshold":                 0.5,
+				"widget_register_require_liveness":   true,
+				"widget_register_liveness_threshold": 0.95,
+			},
+			wantRequire:   true,
+			wantThreshold: 0.95,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant := Tenant{Settings: tt.settings}
+
+			got := tenant.GetSettings().ForWidgetRegister()
+			if got.RequireLiveness != tt.wantRequire {
+				t.Errorf("RequireLiveness = %v, want %v", got.RequireLiveness, tt.wantRequire)
+			}
+			if got.LivenessThreshold != tt.wantThreshold {
+				t.Errorf("LivenessThreshold = %v, want %v", got.LivenessThreshold, tt.wantThreshold)
+			}
+		})
+	}
+}
+
+func TestTenant_GetSettings_AllowedImageFormats(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		want     []string
+	}{
+		{
+			name:     "defaults to all supported formats",
+			settings: map[string]interface{}{},
+			want:     SupportedImageFormats,
+		},
+		{
+			name:     "short names are normalized",
+			settings: map[string]interface{}{"allowed_image_formats": []interface{}{"JPEG", " png "}},
+			want:     []string{"image/jpeg", "image/png"},
+		},
+		{
+			name:     "content types are accepted and deduplicated",
+			settings: map[string]interface{}{"allowed_image_formats": []interface{}{"image/jpeg", "jpeg"}},
+			want:     []string{"image/jpeg"},
+		},
+		{
+			name:     "unsupported formats are dropped",
+			settings: map[string]interface{}{"allowed_image_formats": []interface{}{"gif", "image/webp", 42}},
+			want:     []string{"image/webp"},
+		},
+		{
+			name:     "no supported formats falls back to defaults",
+			settings: map[string]interface{}{"allowed_image_formats": []interface{}{"gif", "bmp"}},
+			want:     SupportedImageFormats,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant := Tenant{Settings: tt.settings}
+
+			got := tenant.GetSettings()
+			if !slices.Equal(got.AllowedImageFormats, tt.want) {
+				t.Errorf("AllowedImageFormats = %v, want %v", got.AllowedImageFormats, tt.want)
+			}
+		})
+	}
+}
+
+// captureWarnings routes the default logger to a buffer for the duration of the test
+func captureWarnings(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn})))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	return &buf
+}
+
+func TestTenant_GetSettings_CoercesTypes(t *testing.T) {
+	tenant := Tenant{Settings: map[string]interface{}{
+		"verification_threshold":             "0.85",
+		"liveness_threshold":                 1,
+		"search_threshold":                   json.Number("0.75"),
+		"max_faces_per_user":                 "3",
+		"search_max_results":                 int64(20),
+		"search_rate_limit":                  15.0,
+		"require_liveness":                   "true",
+		"search_enabled":                     " TRUE ",
+		"allowed_image_formats":              []string{"png"},
+		"widget_register_liveness_threshold": float32(0.5),
+		"strict_multipart_fields":            "true",
+		"face_edge_margin":                   "0.02",
+		"face_ttl":                           "168h",
+	}}
+
+	logs := captureWarnings(t)
+	got := tenant.GetSettings()
+
+	if got.VerificationThreshold != 0.85 {
+		t.Errorf("VerificationThreshold = %v, want 0.85", got.VerificationThreshold)
+	}
+	if got.LivenessThreshold != 1.0 {
+		t.Errorf("LivenessThreshold = %v, want 1.0", got.LivenessThreshold)
+	}
+	if got.SearchThreshold != 0.75 {
+		t.Errorf("SearchThreshold = %v, want 0.75", got.SearchThreshold)
+	}
+	if got.MaxFacesPerUser != 3 {
+		t.Errorf("MaxFacesPerUser = %v, want 3", got.MaxFacesPerUser)
+	}
+	if got.SearchMaxResults != 20 {
+		t.Errorf("SearchMaxResults = %v, want 20", got.SearchMaxResults)
+	}
+	if got.SearchRateLimit != 15 {
+		t.Errorf("SearchRateLimit = %v, want 15", got.SearchRateLimit)
+	}
+	if !got.RequireLiveness {
+		t.Error("RequireLiveness = false, want true")
+	}
+	if !got.SearchEnabled {
+		t.Error("SearchEnabled = false, want true")
+	}
+	if !slices.Equal(got.AllowedImageFormats, []string{"image/png"}) {
+		t.Errorf("AllowedImageFormats = %v, want [image/png]", got.AllowedImageFormats)
+	}
+	if got.WidgetRegisterLivenessThreshold != 0.5 {
+		t.Errorf("WidgetRegisterLivenessThreshold = %v, want 0.5", got.WidgetRegisterLivenessThreshold)
+	}
+	if !got.StrictMultipartFields {
+		t.Error("StrictMultipartFields = false, want true")
+	}
+	if got.FaceEdgeMargin != 0.02 {
+		t.Errorf("FaceEdgeMargin = %v, want 0.02", got.FaceEdgeMargin)
+	}
+	if got.FaceTTL != 7*24*time.Hour {
+		t.Errorf("FaceTTL = %v, want 168h", got.FaceTTL)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("unexpected warnings for coercible values: %s", logs.String())
+	}
+}
+
+func TestTenant_GetSettings_MalformedValuesUseDefaults(t *testing.T) {
+	defaults := DefaultTenantSettings()
+
+	tests := []struct {
+		name  string
+		key   string
+		value interface{}
+		check func(TenantSettings) bool
+	}{
+		{
+			name:  "unparseable float string",
+			key:   "verification_threshold",
+			value: "high",
+			check: func(s TenantSettings) bool { return s.VerificationThreshold == defaults.VerificationThreshold },
+		},
+		{
+			name:  "bool as float",
+			key:   "search_threshold",
+			value: true,
+			check: func(s TenantSettings) bool { return s.SearchThreshold == defaults.SearchThreshold },
+		},
+		{
+			name:  "NaN float string",
+			key:   "liveness_threshold",
+			value: "NaN",
+			check: func(s TenantSettings) bool { return s.LivenessThreshold == defaults.LivenessThreshold },
+		},
+		{
+			name:  "fractional int",
+			key:   "max_faces_per_user",
+			value: 2.5,
+			check: func(s TenantSettings) bool { return s.MaxFacesPerUser == defaults.MaxFacesPerUser },
+		},
+		{
+			name:  "object as int",
+			key:   "search_max_results",
+			value: map[string]interface{}{"value": 5},
+			check: func(s TenantSettings) bool { return s.SearchMaxResults == defaults.SearchMaxResults },
+		},
+		{
+			name:  "unparseable bool string",
+			key:   "require_liveness",
+			value: "maybe",
+			check: func(s TenantSettings) bool { return s.RequireLiveness == defaults.RequireLiveness },
+		},
+		{
+			name:  "number as bool",
+			key:   "search_enabled",
+			value: 1.0,
+			check: func(s TenantSettings) bool { return s.SearchEnabled == defaults.SearchEnabled },
+		},
+		{
+			name:  "number as string",
+			key:   "security_level",
+			value: 3.0,
+			check: func(s TenantSettings) bool { return s.SecurityLevel == defaults.SecurityLevel },
+		},
+		{
+			name:  "unknown enum value",
+			key:   "on_multiple_faces",
+			value: "pick_random",
+			check: func(s TenantSettings) bool { return s.OnMultipleFaces == defaults.OnMultipleFaces },
+		},
+		{
+			name:  "unknown verify policy",
+			key:   "verify_policy",
+			value: "liveness_only",
+			check: func(s TenantSettings) bool { return s.VerifyPolicy == defaults.VerifyPolicy },
+		},
+		{
+			name:  "unknown search audit mode",
+			key:   "search_audit_mode",
+			value: "strict",
+			check: func(s TenantSettings) bool { return s.SearchAuditMode == defaults.SearchAuditMode },
+		},
+		{
+			name:  "unknown embedding precision",
+			key:   "embedding_precision",
+			value: "int4",
+			check: func(s TenantSettings) bool { return s.EmbeddingPrecision == defaults.EmbeddingPrecision },
+		},
+		{
+			name:  "edge margin leaving no room for a face",
+			key:   "face_edge_margin",
+			value: 0.5,
+			check: func(s TenantSettings) bool { return s.FaceEdgeMargin == defaults.FaceEdgeMargin },
+		},
+		{
+			name:  "face ttl without a unit",
+			key:   "face_ttl",
+			value: "7",
+			check: func(s TenantSettings) bool { return s.FaceTTL == defaults.FaceTTL },
+		},
+		{
+			name:  "negative face ttl",
+			key:   "face_ttl",
+			value: "-1h",
+			check: func(s TenantSettings) bool { return s.FaceTTL == defaults.FaceTTL },
+		},
+		{
+			name:  "unknown image format",
+			key:   "allowed_image_formats",
+			value: []interface{}{"jpeg", "pgn"},
+			check: func(s TenantSettings) bool {
+				return slices.Equal(s.AllowedImageFormats, []string{"image/jpeg"})
+			},
+		},
+		{
+			name:  "string as list",
+			key:   "allowed_image_formats",
+			value: "jpeg",
+			check: func(s TenantSettings) bool {
+				return slices.Equal(s.AllowedImageFormats, defaults.AllowedImageFormats)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant := Tenant{ID: uuid.New(), Settings: map[string]interface{}{tt.key: tt.value}}
+
+			logs := captureWarnings(t)
+			got := tenant.GetSettings()
+
+			if !tt.check(got) {
+				t.Errorf("%s did not fall back to the default: %+v", tt.key, got)
+			}
+			out := logs.String()
+			if !strings.Contains(out, "invalid tenant setting") || !strings.Contains(out, "setting="+tt.key) {
+				t.Errorf("expected warning for %s, got %q", tt.key, out)
+			}
+			if !strings.Contains(out, tenant.ID.String()) {
+				t.Errorf("warning should include tenant id, got %q", out)
+			}
+		})
+	}
+}
+
+func TestTenant_GetSettings_NullValuesUseDefaultsSilently(t *testing.T) {
+	tenant := Tenant{Settings: map[string]interface{}{
+		"verification_threshold": nil,
+		"require_liveness":       nil,
+	}}
+
+	logs := captureWarnings(t)
+	got := tenant.GetSettings()
+
+	defaults := DefaultTenantSettings()
+	if got.VerificationThreshold != defaults.VerificationThreshold {
+		t.Errorf("VerificationThreshold = %v, want %v", got.VerificationThreshold, defaults.VerificationThreshold)
+	}
+	if got.RequireLiveness != defaults.RequireLiveness {
+		t.Errorf("RequireLiveness = %v, want %v", got.RequireLiveness, defaults.RequireLiveness)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("unexpected warnings for null values: %s", logs.String())
+	}
+}
+
+func TestTenant_GetSettings_SecurityLevels(t *testing.T) {
+	t.Run("active level block overrides flat keys", func(t *testing.T) {
+		tenant := Tenant{Settings: map[string]interface{}{
+			"security_level":         "maximum",
+			"verification_threshold": 0.8,
+			"search_threshold":       0.85,
+			"require_liveness":       false,
+			"min_quality":            0.2,
+			"security_levels": map[string]interface{}{
+				"maximum": map[string]interface{}{
+					"verify_threshold": 0.95,
+					"search_threshold": "0.92",
+					"require_liveness": true,
+					"min_quality":      0.7,
+				},
+			},
+		}}
+
+		got := tenant.GetSettings()
+
+		if got.VerificationThreshold != 0.95 {
+			t.Errorf("VerificationThreshold = %v, want 0.95", got.VerificationThreshold)
+		}
+		if got.SearchThreshold != 0.92 {
+			t.Errorf("SearchThreshold = %v, want 0.92", got.SearchThreshold)
+		}
+		if !got.RequireLiveness {
+			t.Error("RequireLiveness = false, want true")
+		}
+		if got.MinQuality != 0.7 {
+			t.Errorf("MinQuality = %v, want 0.7", got.MinQuality)
+		}
+		// Widget register falls back to the resolved liveness requirement
+		if !got.WidgetRegisterRequireLiveness {
+			t.Error("WidgetRegisterRequireLiveness = false, want true")
+		}
+	})
+
+	t.Run("partial block keeps flat keys and defaults for missing fields", func(t *testing.T) {
+		tenant := Tenant{Settings: map[string]interface{}{
+			"security_level":   "enhanced",
+			"search_threshold": 0.9,
+			"security_levels": map[string]interface{}{
+				"enhanced": map[string]interface{}{
+					"verify_threshold": 0.88,
+				},
+			},
+		}}
+
+		got := tenant.GetSettings()
+		defaults := DefaultTenantSettings()
+
+		if got.VerificationThreshold != 0.88 {
+			t.Errorf("VerificationThreshold = %v, want 0.88", got.VerificationThreshold)
+		}
+		if got.SearchThreshold != 0.9 {
+			t.Errorf("SearchThreshold = %v, want flat value 0.9", got.SearchThreshold)
+		}
+		if got.RequireLiveness != defaults.RequireLiveness {
+			t.Errorf("RequireLiveness = %v, want default %v", got.RequireLiveness, defaults.RequireLiveness)
+		}
+		if got.MinQuality != defaults.MinQuality {
+			t.Errorf("MinQuality = %v, want default %v", got.MinQuality, defaults.MinQuality)
+		}
+	})
+
+	t.Run("blocks for other levels do not apply", func(t *testing.T) {
+		tenant := Tenant{Settings: map[string]interface{}{
+			"security_levels": map[string]interface{}{
+				"maximum": map[string]interface{}{
+					"verify_threshold": 0.99,
+				},
+			},
+		}}
+
+		got := tenant.GetSettings()
+
+		if got.VerificationThreshold != DefaultTenantSettings().VerificationThreshold {
+			t.Errorf("VerificationThreshold = %v, want default", got.VerificationThreshold)
+		}
+		if _, ok := got.SecurityLevels[SecurityMaximum]; !ok {
+			t.Error("SecurityLevels should keep the maximum block")
+		}
+	})
+
+	t.Run("invalid entries warn and fall back", func(t *testing.T) {
+		tenant := Tenant{Settings: map[string]interface{}{
+			"verification_threshold": 0.82,
+			"security_levels": map[string]interface{}{
+				"paranoid": map[string]interface{}{"verify_threshold": 0.99},
+				"standard": map[string]interface{}{"verify_threshold": 1.5},
+			},
+		}}
+
+		logs := captureWarnings(t)
+		got := tenant.GetSettings()
+
+		if got.VerificationThreshold != 0.82 {
+			t.Errorf("VerificationThreshold = %v, want flat value 0.82", got.VerificationThreshold)
+		}
+		if _, ok := got.SecurityLevels["paranoid"]; ok {
+			t.Error("unknown level should be dropped")
+		}
+		for _, setting := range []string{"security_levels.paranoid", "security_levels.standard.verify_threshold"} {
+			if !strings.Contains(logs.String(), "setting="+setting) {
+				t.Errorf("expected warning for %s, got: %s", setting, logs.String())
+			}
+		}
+	})
+}
+
+func TestTenant_GetSettings_AntiPassback(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  time.Duration
+		warn  bool
+	}{
+		{name: "window", value: map[string]interface{}{"window": "10m"}, want: 10 * time.Minute},
+		{name: "zero window disables", value: map[string]interface{}{"window": "0s"}, want: 0},
+		{name: "window without a unit", value: map[string]interface{}{"window": "10"}, warn: true},
+		{name: "negative window", value: map[string]interface{}{"window": "-5m"}, warn: true},
+		{name: "window above max", value: map[string]interface{}{"window": "25h"}, warn: true},
+		{name: "not an object", value: "10m", warn: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant := Tenant{ID: uuid.New(), Settings: map[string]interface{}{"anti_passback": tt.value}}
+
+			logs := captureWarnings(t)
+			got := tenant.GetSettings()
+
+			if got.AntiPassback.Window != tt.want {
+				t.Errorf("AntiPassback.Window = %v, want %v", got.AntiPassback.Window, tt.want)
+			}
+			if got.AntiPassback.Enabled() != (tt.want > 0) {
+				t.Errorf("AntiPassback.Enabled() = %v, want %v", got.AntiPassback.Enabled(), tt.want > 0)
+			}
+			if warned := strings.Contains(logs.String(), "setting=anti_passback"); warned != tt.warn {
+				t.Errorf("warned = %v, want %v: %q", warned, tt.warn, logs.String())
+			}
+		})
+	}
+
+	if DefaultTenantSettings().AntiPassback.Enabled() {
+		t.Error("anti-passback should be off by default")
+	}
+}
+
+func TestTenant_GetSettings_VerifyHysteresis(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       interface{}
+		wantMargin  float64
+		wantWindow  time.Duration
+		wantEnabled bool
+		warn        bool
+	}{
+		{name: "margin and window", value: map[string]interface{}{"margin": 0.03, "window": "10m"}, wantMargin: 0.03, wantWindow: 10 * time.Minute, wantEnabled: true},
+		{name: "margin without window", value: map[string]interface{}{"margin": 0.03}, wantMargin: 0.03},
+		{name: "window without margin", value: map[string]interface{}{"window": "10m"}, wantWindow: 10 * time.Minute},
+		{name: "margin above max", value: map[string]interface{}{"margin": 0.2, "window": "10m"}, wantWindow: 10 * time.Minute, warn: true},
+		{name: "negative margin", value: map[string]interface{}{"margin": -0.01, "window": "10m"}, wantWindow: 10 * time.Minute, warn: true},
+		{name: "window above max", value: map[string]interface{}{"margin": 0.03, "window": "25h"}, wantMargin: 0.03, warn: true},
+		{name: "window without a unit", value: map[string]interface{}{"margin": 0.03, "window": "10"}, wantMargin: 0.03, warn: true},
+		{name: "not an object", value: 0.03, warn: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant := Tenant{ID: uuid.New(), Settings: map[string]interface{}{"verify_hysteresis": tt.value}}
+
+			logs := captureWarnings(t)
+			got := tenant.GetSettings().VerifyHysteresis
+
+			if got.Margin != tt.wantMargin || got.Window != tt.wantWindow {
+				t.Errorf("VerifyHysteresis = %+v, want margin %v window %v", got, tt.wantMargin, tt.wantWindow)
+			}
+			if got.Enabled() != tt.wantEnabled {
+				t.Errorf("VerifyHysteresis.Enabled() = %v, want %v", got.Enabled(), tt.wantEnabled)
+			}
+			if warned := strings.Contains(logs.String(), "setting=verify_hysteresis"); warned != tt.warn {
+				t.Errorf("warned = %v, want %v: %q", warned, tt.warn, logs.String())
+			}
+		})
+	}
+
+	if DefaultTenantSettings().VerifyHysteresis.Enabled() {
+		t.Error("verify hysteresis should be off by default")
+	}
+}
+
+func TestTenant_GetSettings_Maintenance(t *testing.T) {
+	tests := []struct {
+		name           string
+		value          interface{}
+		wantEnabled    bool
+		wantRetryAfter time.Duration
+		warn           bool
+	}{
+		{name: "enabled with retry after", value: map[string]interface{}{"enabled": true, "retry_after": "15m"}, wantEnabled: true, wantRetryAfter: 15 * time.Minute},
+		{name: "enabled with default retry after", value: map[string]interface{}{"enabled": true}, wantEnabled: true, wantRetryAfter: DefaultMaintenanceRetryAfter},
+		{name: "disabled", value: map[string]interface{}{"enabled": false, "retry_after": "1h"}, wantRetryAfter: time.Hour},
+		{name: "retry after above max", value: map[string]interface{}{"enabled": true, "retry_after": "48h"}, wantEnabled: true, wantRetryAfter: DefaultMaintenanceRetryAfter, warn: true},
+		{name: "retry after without a unit", value: map[string]interface{}{"enabled": true, "retry_after": "60"}, wantEnabled: true, wantRetryAfter: DefaultMaintenanceRetryAfter, warn: true},
+		{name: "not an object", value: true, wantRetryAfter: DefaultMaintenanceRetryAfter, warn: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant := Tenant{ID: uuid.New(), Settings: map[string]interface{}{"maintenance": tt.value}}
+
+			logs := captureWarnings(t)
+			got := tenant.GetSettings().Maintenance
+
+			if got.Enabled != tt.wantEnabled || got.RetryAfter != tt.wantRetryAfter {
+				t.Errorf("Maintenance = %+v, want enabled %v retry after %v", got, tt.wantEnabled, tt.wantRetryAfter)
+			}
+			if warned := strings.Contains(logs.String(), "setting=maintenance"); warned != tt.warn {
+				t.Errorf("warned = %v, want %v: %q", warned, tt.warn, logs.String())
+			}
+
+			err := got.Check()
+			if tt.wantEnabled {
+				var appErr *AppError
+				if !errors.As(err, &appErr) || appErr.Code != "MAINTENANCE" || appErr.RetryAfter != tt.wantRetryAfter {
+					t.Errorf("Check() = %v, want MAINTENANCE with retry after %v", err, tt.wantRetryAfter)
+				}
+			} else if err != nil {
+				t.Errorf("Check() = %v, want nil", err)
+			}
+		})
+	}
+
+	if DefaultTenantSettings().Maintenance.Enabled {
+		t.Error("maintenance should be off by default")
+	}
+}
+
+func TestTenant_GetSettings_ReplayDetection(t *testing.T) {
+	defaults := DefaultTenantSettings().ReplayDetection
+
+	tests := []struct {
+		name  string
+		value interface{}
+		want  ReplayDetectionSettings
+		warn  bool
+	}{
+		{name: "enabled with defaults", value: map[string]interface{}{"enabled": true}, want: ReplayDetectionSettings{Enabled: true, MinFrames: 3, MinScoreStdDev: 0.005, MaxFrameDiff: 2}},
+		{name: "custom thresholds", value: map[string]interface{}{"enabled": true, "min_frames": 5, "min_score_stddev": 0.01, "max_frame_diff": 3.5}, want: ReplayDetectionSettings{Enabled: true, MinFrames: 5, MinScoreStdDev: 0.01, MaxFrameDiff: 3.5}},
+		{name: "zero disables a check", value: map[string]interface{}{"enabled": true, "max_frame_diff": 0}, want: ReplayDetectionSettings{Enabled: true, MinFrames: 3, MinScoreStdDev: 0.005}},
+		{name: "single frame", value: map[string]interface{}{"enabled": true, "min_frames": 1}, want: ReplayDetectionSettings{Enabled: true, MinFrames: 3, MinScoreStdDev: 0.005, MaxFrameDiff: 2}, warn: true},
+		{name: "too many frames", value: map[string]interface{}{"min_frames": 11}, want: defaults, warn: true},
+		{name: "negative threshold", value: map[string]interface{}{"min_score_stddev": -0.1}, want: defaults, warn: true},
+		{name: "not an object", value: true, want: defaults, warn: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant := Tenant{ID: uuid.New(), Settings: map[string]interface{}{"replay_detection": tt.value}}
+
+			logs := captureWarnings(t)
+			got := tenant.GetSettings().ReplayDetection
+
+			if got != tt.want {
+				t.Errorf("ReplayDetection = %+v, want %+v", got, tt.want)
+			}
+			if warned := strings.Contains(logs.String(), "setting=replay_detection"); warned != tt.warn {
+				t.Errorf("warned = %v, want %v: %q", warned, tt.warn, logs.String())
+			}
+		})
+	}
+
+	if defaults.Enabled {
+		t.Error("replay detection should be off by default")
+	}
+}
+
+func TestTenant_GetSettings_SegmentThresholds(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  SegmentThresholdSettings
+		warn  bool
+	}{
+		{
+			name:  "verify and search overrides",
+			value: map[string]interface{}{"key": "ticket_type", "verify": map[string]interface{}{"staff": 0.95}, "search": map[string]interface{}{"staff": "0.9"}},
+			want:  SegmentThresholdSettings{Key: "ticket_type", Verify: map[string]float64{"staff": 0.95}, Search: map[string]float64{"staff": 0.9}},
+		},
+		{
+			name:  "threshold outside 0-1 is dropped",
+			value: map[string]interface{}{"key": "ticket_type", "verify": map[string]interface{}{"staff": 1.5, "vip": 0.9}},
+			want:  SegmentThresholdSettings{Key: "ticket_type", Verify: map[string]float64{"vip": 0.9}},
+			warn:  true,
+		},
+		{name: "missing key", value: map[string]interface{}{"verify": map[string]interface{}{"staff": 0.95}}, warn: true},
+		{name: "blank key", value: map[string]interface{}{"key": " ", "verify": map[string]interface{}{"staff": 0.95}}, warn: true},
+		{name: "not an object", value: "staff", warn: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant := Tenant{ID: uuid.New(), Settings: map[string]interface{}{"segment_thresholds": tt.value}}
+
+			logs := captureWarnings(t)
+			got := tenant.GetSettings().SegmentThresholds
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SegmentThresholds = %+v, want %+v", got, tt.want)
+			}
+			if warned := strings.Contains(logs.String(), "setting=segment_thresholds"); warned != tt.warn {
+				t.Errorf("warned = %v, want %v: %q", warned, tt.warn, logs.String())
+			}
+		})
+	}
+}
+
+func TestTenant_GetSettings_WidgetCheckSecret(t *testing.T) {
+	const secret = "backend-secret-0123456789abcdef0123"
+
+	tests := []struct {
+		name       string
+		value      interface{}
+		wantSigned bool
+		wantSecret string
+		warn       bool
+	}{
+		{name: "unset keeps checks open"},
+		{name: "secret enables signed checks", value: secret, wantSigned: true, wantSecret: secret},
+		{name: "short secret rejects every check", value: "short", wantSigned: true, warn: true},
+		{name: "non-string secret rejects every check", value: 42, wantSigned: true, warn: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := map[string]interface{}{}
+			if tt.value != nil {
+				settings["widget_check_secret"] = tt.value
+			}
+			tenant := Tenant{ID: uuid.New(), Settings: settings}
+
+			logs := captureWarnings(t)
+			got := tenant.GetSettings()
+
+			if got.WidgetCheckSigned != tt.wantSigned || got.WidgetCheckSecret != tt.wantSecret {
+				t.Errorf("got signed=%v secret=%q, want %v %q", got.WidgetCheckSigned, got.WidgetCheckSecret, tt.wantSigned, tt.wantSecret)
+			}
+			if warned := strings.Contains(logs.String(), "setting=widget_check_secret"); warned != tt.warn {
+				t.Errorf("warned = %v, want %v: %q", warned, tt.warn, logs.String())
+			}
+			if strings.Contains(logs.String(), "value=short") {
+				t.Errorf("secret leaked to the logs: %q", logs.String())
+			}
+		})
+	}
+}
+
+func TestValidWidgetCheckSignature(t *testing.T) {
+	const secret = "backend-secret-0123456789abcdef0123"
+	signature := SignWidgetCheck(secret, "user_001")
+
+	if !ValidWidgetCheckSignature(secret, "user_001", signature) {
+		t.Error("signature of the backend should be valid")
+	}
+	if ValidWidgetCheckSignature(secret, "user_002", signature) {
+		t.Error("signature should not be valid for another external_id")
+	}
+	if ValidWidgetCheckSignature("", "user_001", SignWidgetCheck("", "user_001")) {
+		t.Error("an empty secret should accept no signature")
+	}
+	if ValidWidgetCheckSignature(secret, "user_001", "zz") {
+		t.Error("a malformed signature should be rejected")
+	}
+}
+
+func TestValidateTenantSettings(t *testing.T) {
+	t.Run("usable settings", func(t *testing.T) {
+		err := ValidateTenantSettings(map[string]interface{}{
+			"verification_threshold": "0.9",
+			"security_level":         "enhanced",
+			"max_requests_month":     10000.0,
+			"unknown_key":            "kept",
+			"security_levels": map[string]interface{}{
+				"maximum": map[string]interface{}{"verify_threshold": 0.95},
+			},
+		})
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("unusable settings are reported without warnings", func(t *testing.T) {
+		logs := captureWarnings(t)
+
+		err := ValidateTenantSettings(map[string]interface{}{
+			"verify_policy":          "liveness_only",
+			"verification_threshold": "high",
+			"security_levels": map[string]interface{}{
+				"paranoid": map[string]interface{}{},
+			},
+		})
+
+		if err == nil {
+			t.Fatal("expected error for unusable settings")
+		}
+		want := "invalid tenant settings: security_levels.paranoid, verification_threshold, verify_policy"
+		if err.Error() != want {
+			t.Errorf("error = %q, want %q", err.Error(), want)
+		}
+		if logs.Len() != 0 {
+			t.Errorf("validation should not log warnings: %s", logs.String())
+		}
+	})
+}
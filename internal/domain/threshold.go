@@ -0,0 +1,125 @@
+package domain
+
+// ThresholdSource names the layer a match threshold was resolved from
+type ThresholdSource string
+
+// Threshold layers, from highest to lowest precedence
+const (
+	// ThresholdSourceSegment is a segment_thresholds override for the matched face's metadata
+	ThresholdSourceSegment ThresholdSource = "segment"
+	// ThresholdSourceRequest is a threshold sent with the request (search only)
+	ThresholdSourceRequest ThresholdSource = "request"
+	// ThresholdSourceSecurityLevel is the security_levels override of the tenant's active level
+	ThresholdSourceSecurityLevel ThresholdSource = "security_level"
+	// ThresholdSourceTenant is the tenant's flat verification_threshold / search_threshold setting
+	ThresholdSourceTenant ThresholdSource = "tenant"
+	// ThresholdSourceDefault is the built-in default of DefaultTenantSettings
+	ThresholdSourceDefault ThresholdSource = "default"
+)
+
+// Operations whose match decision depends on a resolved threshold
+const (
+	ThresholdOperationVerify = "verify"
+	ThresholdOperationSearch = "search"
+)
+
+// ThresholdLayer is one candidate value in a threshold resolution; Value is nil when the layer is unset
+type ThresholdLayer struct {
+	Source ThresholdSource `json:"source"`
+	Value  *float64        `json:"value"`
+}
+
+// ThresholdResolution explains which threshold an operation applies and where it came from
+type ThresholdResolution struct {
+	Operation        string          `json:"operation"`
+	ThresholdApplied float64         `json:"threshold_applied"`
+	Source           ThresholdSource `json:"source"`
+	// Layers lists every candidate from highest to lowest precedence; the first set one wins
+	Layers []ThresholdLayer `json:"layers"`
+}
+
+// SegmentThresholdSettings overrides the verify and search thresholds for faces whose metadata
+// value under Key names a segment, e.g. stricter thresholds for staff than for attendees.
+// Configured as {"segment_thresholds": {"key": "ticket_type", "verify": {"staff": 0.95}, "search": {"staff": 0.95}}}.
+// Only string metadata values select a segment; other faces keep the tenant's thresholds.
+type SegmentThresholdSettings struct {
+	Key    string             `json:"key"`
+	Verify map[string]float64 `json:"verify,omitempty"`
+	Search map[string]float64 `json:"search,omitempty"`
+}
+
+// segment returns the segment named by the face metadata, empty when there is none
+func (s SegmentThresholdSettings) segment(metadata map[string]interface{}) string {
+	if s.Key == "" {
+		return ""
+	}
+	value, _ := metadata[s.Key].(string)
+	return value
+}
+
+// VerifyThresholdFor resolves the verification threshold for a stored face: an override for
+// the face's segment wins over every other layer
+func (s TenantSettings) VerifyThresholdFor(metadata map[string]interface{}) (float64, ThresholdSource) {
+	if threshold, ok := s.SegmentThresholds.Verify[s.SegmentThresholds.segment(metadata)]; ok {
+		return threshold, ThresholdSourceSegment
+	}
+	return s.VerificationThreshold, s.VerificationThresholdSource
+}
+
+// SearchQueryThreshold returns the lowest similarity any search match may have: the resolved
+// threshold or a segment override below it
+func (s SegmentThresholdSettings) SearchQueryThreshold(resolved float64) float64 {
+	for _, threshold := range s.Search {
+		resolved = min(resolved, threshold)
+	}
+	return resolved
+}
+
+// SearchThresholdFor resolves the search threshold for a request, where a requested
+// threshold of 0 or less means "use the tenant's"
+func (s TenantSettings) SearchThresholdFor(requested float64) (float64, ThresholdSource) {
+	if requested > 0 {
+		return requested, ThresholdSourceRequest
+	}
+	return s.SearchThreshold, s.SearchThresholdSource
+}
+
+// ThresholdResolutions reports how the verify and search thresholds of the tenant resolve,
+// without a request override
+func (t *Tenant) ThresholdResolutions() []ThresholdResolution {
+	settings := t.GetSettings()
+	defaults := DefaultTenantSettings()
+	flat := settingsReader{tenantID: t.ID, values: t.Settings}
+	level := settings.SecurityLevels[settings.SecurityLevel]
+
+	flatValue := func(key string) *float64 {
+		if v, ok := flat.Float(key); ok {
+			return &v
+		}
+		return nil
+	}
+
+	return []ThresholdResolution{
+		{
+			Operation:        ThresholdOperationVerify,
+			ThresholdApplied: settings.VerificationThreshold,
+			Source:           settings.VerificationThresholdSource,
+			Layers: []ThresholdLayer{
+				{Source: ThresholdSourceSecurityLevel, Value: level.VerifyThreshold},
+				{Source: ThresholdSourceTenant, Value: flatValue("verification_threshold")},
+				{Source: ThresholdSourceDefault, Value: &defaults.VerificationThreshold},
+			},
+		},
+		{
+			Operation:        ThresholdOperationSearch,
+			ThresholdApplied: settings.SearchThreshold,
+			Source:           settings.SearchThresholdSource,
+			Layers: []ThresholdLayer{
+				{Source: ThresholdSourceRequest},
+				{Source: ThresholdSourceSecurityLevel, Value: level.SearchThreshold},
+				{Source: ThresholdSourceTenant, Value: flatValue("search_threshold")},
+				{Source: ThresholdSourceDefault, Value: &defaults.SearchThreshold},
+			},
+		},
+	}
+}
@@ -0,0 +1,143 @@
+package domain
+
+import "testing"
+
+func TestTenant_ThresholdResolutions(t *testing.T) {
+	tests := []struct {
+		name         string
+		settings     map[string]interface{}
+		wantVerify   float64
+		verifySource ThresholdSource
+		wantSearch   float64
+		searchSource ThresholdSource
+	}{
+		{
+			name:         "no settings use the defaults",
+			wantVerify:   0.8,
+			verifySource: ThresholdSourceDefault,
+			wantSearch:   0.85,
+			searchSource: ThresholdSourceDefault,
+		},
+		{
+			name: "flat keys beat the defaults",
+			settings: map[string]interface{}{
+				"verification_threshold": 0.82,
+				"search_threshold":       0.9,
+			},
+			wantVerify:   0.82,
+			verifySource: ThresholdSourceTenant,
+			wantSearch:   0.9,
+			searchSource: ThresholdSourceTenant,
+		},
+		{
+			name: "active security level beats the flat keys",
+			settings: map[string]interface{}{
+				"security_level":         "maximum",
+				"verification_threshold": 0.82,
+				"search_threshold":       0.9,
+				"security_levels": map[string]interface{}{
+					"maximum": map[string]interface{}{"verify_threshold": 0.95},
+				},
+			},
+			wantVerify:   0.95,
+			verifySource: ThresholdSourceSecurityLevel,
+			wantSearch:   0.9,
+			searchSource: ThresholdSourceTenant,
+		},
+		{
+			name: "inactive security level is ignored",
+			settings: map[string]interface{}{
+				"security_level": "standard",
+				"security_levels": map[string]interface{}{
+					"maximum": map[string]interface{}{"verify_threshold": 0.95, "search_threshold": 0.95},
+				},
+			},
+			wantVerify:   0.8,
+			verifySource: ThresholdSourceDefault,
+			wantSearch:   0.85,
+			searchSource: ThresholdSourceDefault,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant := &Tenant{Settings: tt.settings}
+			settings := tenant.GetSettings()
+			resolutions := tenant.ThresholdResolutions()
+			if len(resolutions) != 2 {
+				t.Fatalf("got %d resolutions, want 2", len(resolutions))
+			}
+
+			verify, search := resolutions[0], resolutions[1]
+			if verify.Operation != ThresholdOperationVerify || search.Operation != ThresholdOperationSearch {
+				t.Fatalf("operations = %s, %s", verify.Operation, search.Operation)
+			}
+			if verify.ThresholdApplied != tt.wantVerify || verify.Source != tt.verifySource {
+				t.Errorf("verify = %v from %s, want %v from %s", verify.ThresholdApplied, verify.Source, tt.wantVerify, tt.verifySource)
+			}
+			if search.ThresholdApplied != tt.wantSearch || search.Source != tt.searchSource {
+				t.Errorf("search = %v from %s, want %v from %s", search.ThresholdApplied, search.Source, tt.wantSearch, tt.searchSource)
+			}
+
+			// The reported source is the first set layer and agrees with what operations apply
+			for _, r := range resolutions {
+				first := firstSetLayer(r.Layers)
+				if first.Source != r.Source || *first.Value != r.ThresholdApplied {
+					t.Errorf("%s: first set layer %s=%v, resolution %s=%v", r.Operation, first.Source, *first.Value, r.Source, r.ThresholdApplied)
+				}
+			}
+			if settings.VerificationThreshold != verify.ThresholdApplied || settings.VerificationThresholdSource != verify.Source {
+				t.Errorf("settings verify = %v from %s, resolution %v from %s", settings.VerificationThreshold, settings.VerificationThresholdSource, verify.ThresholdApplied, verify.Source)
+			}
+			if settings.SearchThreshold != search.ThresholdApplied || settings.SearchThresholdSource != search.Source {
+				t.Errorf("settings search = %v from %s, resolution %v from %s", settings.SearchThreshold, settings.SearchThresholdSource, search.ThresholdApplied, search.Source)
+			}
+		})
+	}
+}
+
+func firstSetLayer(layers []ThresholdLayer) ThresholdLayer {
+	for _, layer := range layers {
+		if layer.Value != nil {
+			return layer
+		}
+	}
+	return ThresholdLayer{}
+}
+
+func TestTenantSettings_SearchThresholdFor(t *testing.T) {
+	settings := (&Tenant{Settings: map[string]interface{}{"search_threshold": 0.9}}).GetSettings()
+
+	if got, source := settings.SearchThresholdFor(0.7); got != 0.7 || source != ThresholdSourceRequest {
+		t.Errorf("request override = %v from %s, want 0.7 from request", got, source)
+	}
+	if got, source := settings.SearchThresholdFor(0); got != 0.9 || source != ThresholdSourceTenant {
+		t.Errorf("no override = %v from %s, want 0.9 from tenant", got, source)
+	}
+}
+
+func TestTenantSettings_SegmentThresholds(t *testing.T) {
+	settings := (&Tenant{Settings: map[string]interface{}{
+		"verification_threshold": 0.8,
+		"segment_thresholds": map[string]interface{}{
+			"key":    "ticket_type",
+			"verify": map[string]interface{}{"staff": 0.95},
+			"search": map[string]interface{}{"staff": 0.95, "guest": 0.7},
+		},
+	}}).GetSettings()
+	staff := map[string]interface{}{"ticket_type": "staff"}
+	general := map[string]interface{}{"ticket_type": "general"}
+
+	if got, source := settings.VerifyThresholdFor(staff); got != 0.95 || source != ThresholdSourceSegment {
+		t.Errorf("staff verify = %v from %s, want 0.95 from segment", got, source)
+	}
+	if got, source := settings.VerifyThresholdFor(general); got != 0.8 || source != ThresholdSourceTenant {
+		t.Errorf("general verify = %v from %s, want 0.8 from tenant", got, source)
+	}
+	if got, source := settings.VerifyThresholdFor(map[string]interface{}{"ticket_type": 1}); got != 0.8 || source != ThresholdSourceTenant {
+		t.Errorf("non-string segment verify = %v from %s, want 0.8 from tenant", got, source)
+	}
+	if got := settings.SegmentThresholds.SearchQueryThreshold(0.85); got != 0.7 {
+		t.Errorf("search query = %v, want the lowest segment threshold 0.7", got)
+	}
+}
@@ -1,11 +1,17 @@
 package domain
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// MinWidgetCheckSecretLength is the shortest widget_check_secret accepted
+const MinWidgetCheckSecretLength = 32
+
 // WidgetSession represents a temporary session for widget authentication
 type WidgetSession struct {
 	ID        uuid.UUID `json:"id"`
@@ -15,9 +21,32 @@ type WidgetSession struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
-// IsExpired checks if the session has expired
-func (s *WidgetSession) IsExpired() bool {
-	return time.Now().After(s.ExpiresAt)
+// IsExpired checks if the session has expired, tolerating up to leeway of clock skew
+func (s *WidgetSession) IsExpired(leeway time.Duration) bool {
+	return time.Now().After(s.ExpiresAt.Add(leeway))
+}
+
+// SignWidgetCheck returns the signature the tenant's backend attaches to an external_id so the
+// widget may check its registration: hex-encoded HMAC-SHA256 of the external_id keyed with the
+// tenant's widget_check_secret
+func SignWidgetCheck(secret, externalID string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(externalID))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidWidgetCheckSignature reports, in constant time, whether signature was produced by
+// SignWidgetCheck for the external_id. An empty secret accepts no signature.
+func ValidWidgetCheckSignature(secret, externalID, signature string) bool {
+	if secret == "" {
+		return false
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	want, _ := hex.DecodeString(SignWidgetCheck(secret, externalID))
+	return hmac.Equal(got, want)
 }
 
 // Widget-specific errors
@@ -51,4 +80,22 @@ var (
 		Message:    "Invalid origin format",
 		StatusCode: 422,
 	}
+
+	ErrWidgetSessionRateLimitExceeded = &AppError{
+		Code:       "WIDGET_SESSION_RATE_LIMIT_EXCEEDED",
+		Message:    "Too many widget sessions created with this public key, try again later",
+		StatusCode: 429,
+	}
+
+	ErrWidgetCheckRateLimitExceeded = &AppError{
+		Code:       "WIDGET_CHECK_RATE_LIMIT_EXCEEDED",
+		Message:    "Too many registration checks in this widget session, try again later",
+		StatusCode: 429,
+	}
+
+	ErrInvalidCheckSignature = &AppError{
+		Code:       "INVALID_CHECK_SIGNATURE",
+		Message:    "external_id must be signed by the tenant backend to check its registration",
+		StatusCode: 403,
+	}
 )
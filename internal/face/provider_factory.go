@@ -30,6 +30,8 @@ const (
 //   - FACE_PROVIDER: "deepface" or "rekognition" (default: "deepface")
 //   - DEEPFACE_URL: DeepFace API URL (default: "http://localhost:5000")
 //   - AWS_REGION: AWS region for Rekognition (default: "us-east-1")
+//   - REKOGNITION_COST_TAGS: cost allocation tags for tenant collections (e.g. "rekko-tenant:{tenant_id}")
+//   - REKOGNITION_MAX_TPS / REKOGNITION_MAX_CONCURRENCY: account-wide call limits (default: 0, unlimited)
 //   - AWS_ACCESS_KEY_ID: AWS credentials (via AWS SDK credential chain)
 //   - AWS_SECRET_ACCESS_KEY: AWS credentials (via AWS SDK credential chain)
 func NewFaceProvider(ctx context.Context, cfg *config.Config, tenantID uuid.UUID) (provider.FaceProvider, error) {
@@ -54,6 +56,10 @@ func createRekognitionProvider(ctx context.Context, cfg *config.Config, tenantID
 	rekogConfig := rekognition.Config{
 		Region:           cfg.AWSRegion,
 		CollectionPrefix: "rekko-",
+		CostTags:         cfg.RekognitionCostTags,
+		MaxTPS:           cfg.RekognitionMaxTPS,
+		MaxConcurrency:   cfg.RekognitionMaxConcurrency,
+		MaxWait:          cfg.RekognitionMaxWait,
 	}
 
 	prov, err := rekognition.NewProvider(ctx, rekogConfig, tenantID)
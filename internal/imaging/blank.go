@@ -0,0 +1,72 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"math"
+)
+
+// ErrBlankImage is returned for images with too little contrast to contain a face,
+// such as frames from a covered camera
+var ErrBlankImage = errors.New("image appears blank")
+
+// blankSampleGrid caps the pixels read per axis, so the check costs the same for any resolution
+const blankSampleGrid = 128
+
+// CheckBlank returns ErrBlankImage when the standard deviation of the image luminance
+// (0-255) is below minStdDev. The check is skipped when minStdDev is not positive or the
+// format cannot be decoded here (e.g. WebP), leaving the decision to the provider.
+func CheckBlank(data []byte, minStdDev float64) error {
+	if minStdDev <= 0 {
+		return nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil
+	}
+	return CheckBlankImage(img, minStdDev)
+}
+
+// CheckBlankImage is CheckBlank for an image that is already decoded
+func CheckBlankImage(img image.Image, minStdDev float64) error {
+	if minStdDev <= 0 {
+		return nil
+	}
+	if LuminanceStdDev(img) < minStdDev {
+		return ErrBlankImage
+	}
+	return nil
+}
+
+// LuminanceStdDev returns the standard deviation of the luminance of img on a 0-255 scale,
+// sampled on a grid of at most blankSampleGrid x blankSampleGrid pixels
+func LuminanceStdDev(img image.Image) float64 {
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return 0
+	}
+	stepX := max(bounds.Dx()/blankSampleGrid, 1)
+	stepY := max(bounds.Dy()/blankSampleGrid, 1)
+
+	var n, sum, sumSq float64
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += stepY {
+		for x := bounds.Min.X; x < bounds.Max.X; x += stepX {
+			l := luma(img.At(x, y))
+			n++
+			sum += l
+			sumSq += l * l
+		}
+	}
+
+	mean := sum / n
+	return math.Sqrt(max(sumSq/n-mean*mean, 0))
+}
+
+// luma returns the ITU-R BT.601 luma of c, scaled from 16-bit channels to 0-255
+func luma(c color.Color) float64 {
+	r, g, b, _ := c.RGBA()
+	return (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)) / 257
+}
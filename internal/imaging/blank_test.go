@@ -0,0 +1,110 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeSolidJPEG(t *testing.T, width, height int, c color.Color) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, nil))
+	return buf.Bytes()
+}
+
+// encodePhotoJPEG mimics a photo: a shaded oval on a lit background with sensor noise
+func encodePhotoJPEG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	rng := rand.New(rand.NewSource(1))
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	cx, cy := float64(width)/2, float64(height)/2
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			dx, dy := (float64(x)-cx)/(cx*0.4), (float64(y)-cy)/(cy*0.6)
+			v := 60 + 80*float64(y)/float64(height)
+			if dx*dx+dy*dy < 1 {
+				v = 190 - 40*dx
+			}
+			v += rng.Float64()*10 - 5
+			img.Set(x, y, color.RGBA{R: uint8(v), G: uint8(v * 0.85), B: uint8(v * 0.7), A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, &jpeg.Options{Quality: JPEGQuality}))
+	return buf.Bytes()
+}
+
+func TestCheckBlank(t *testing.T) {
+	t.Run("solid color image is rejected", func(t *testing.T) {
+		data := encodeSolidJPEG(t, 640, 480, color.RGBA{R: 200, G: 30, B: 30, A: 255})
+
+		err := CheckBlank(data, 2)
+
+		assert.ErrorIs(t, err, ErrBlankImage)
+	})
+
+	t.Run("covered camera frame is rejected", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(1))
+		img := image.NewGray(image.Rect(0, 0, 640, 480))
+		for i := range img.Pix {
+			img.Pix[i] = uint8(3 + rng.Intn(3))
+		}
+		var buf bytes.Buffer
+		require.NoError(t, png.Encode(&buf, img))
+
+		err := CheckBlank(buf.Bytes(), 2)
+
+		assert.ErrorIs(t, err, ErrBlankImage)
+	})
+
+	t.Run("photo is accepted", func(t *testing.T) {
+		err := CheckBlank(encodePhotoJPEG(t, 1280, 720), 2)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("gradient is accepted", func(t *testing.T) {
+		err := CheckBlank(encodeJPEG(t, 320, 240), 2)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("disabled when threshold is zero", func(t *testing.T) {
+		data := encodeSolidJPEG(t, 64, 64, color.Black)
+
+		assert.NoError(t, CheckBlank(data, 0))
+	})
+
+	t.Run("unknown format is passed through", func(t *testing.T) {
+		data := []byte("RIFF....WEBPVP8 not decodable here")
+
+		assert.NoError(t, CheckBlank(data, 2))
+	})
+}
+
+func TestLuminanceStdDev(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 2, 1))
+	img.Pix[0], img.Pix[1] = 0, 200
+
+	assert.InDelta(t, 100, LuminanceStdDev(img), 0.01)
+	assert.Zero(t, LuminanceStdDev(image.NewGray(image.Rectangle{})))
+}
+
+func TestCheckBlankImage(t *testing.T) {
+	assert.ErrorIs(t, CheckBlankImage(image.NewGray(image.Rect(0, 0, 64, 64)), 2), ErrBlankImage)
+	assert.NoError(t, CheckBlankImage(image.NewGray(image.Rect(0, 0, 64, 64)), 0))
+}
@@ -0,0 +1,124 @@
+// Package imaging normalizes uploaded images before they are sent to a face provider
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+)
+
+// JPEGQuality is the encoder quality used when re-encoding downscaled JPEGs
+const JPEGQuality = 90
+
+// Downscale shrinks an image so that neither side exceeds maxDimension, preserving aspect ratio.
+// The original bytes are returned untouched (resized == false) when maxDimension is not positive,
+// the image already fits, or the format cannot be decoded here (e.g. WebP) so the provider decides.
+func Downscale(data []byte, maxDimension int) (out []byte, resized bool, err error) {
+	out, img, err := DownscaleImage(data, maxDimension)
+	return out, img != nil, err
+}
+
+// DownscaleImage is Downscale returning the downscaled image as well, so callers inspecting
+// the pixels do not decode it again. img is nil when the original bytes are returned.
+func DownscaleImage(data []byte, maxDimension int) (out []byte, img image.Image, err error) {
+	if maxDimension <= 0 {
+		return data, nil, nil
+	}
+
+	// Read only the header first so small images are never fully decoded
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return data, nil, nil
+	}
+	if cfg.Width <= maxDimension && cfg.Height <= maxDimension {
+		return data, nil, nil
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode %s image: %w", format, err)
+	}
+
+	width, height := fitWithin(cfg.Width, cfg.Height, maxDimension)
+	dst := resize(src, width, height)
+
+	var buf bytes.Buffer
+	switch format {
+	case "png":
+		err = png.Encode(&buf, dst)
+	default:
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: JPEGQuality})
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("encode %s image: %w", format, err)
+	}
+
+	return buf.Bytes(), dst, nil
+}
+
+// fitWithin scales width and height so the longest side equals maxDimension
+func fitWithin(width, height, maxDimension int) (int, int) {
+	if width >= height {
+		h := height * maxDimension / width
+		return maxDimension, max(h, 1)
+	}
+	w := width * maxDimension / height
+	return max(w, 1), maxDimension
+}
+
+// resize scales src to width x height using bilinear interpolation
+func resize(src image.Image, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	rgba, ok := src.(*image.RGBA)
+	if !ok {
+		rgba = image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+	}
+	srcW, srcH := rgba.Bounds().Dx(), rgba.Bounds().Dy()
+	origin := rgba.Bounds().Min
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	xRatio := float64(srcW) / float64(width)
+	yRatio := float64(srcH) / float64(height)
+
+	for y := 0; y < height; y++ {
+		sy := (float64(y)+0.5)*yRatio - 0.5
+		y0, fy := clampFloor(sy, srcH)
+		y1 := min(y0+1, srcH-1)
+
+		for x := 0; x < width; x++ {
+			sx := (float64(x)+0.5)*xRatio - 0.5
+			x0, fx := clampFloor(sx, srcW)
+			x1 := min(x0+1, srcW-1)
+
+			p00 := rgba.PixOffset(origin.X+x0, origin.Y+y0)
+			p10 := rgba.PixOffset(origin.X+x1, origin.Y+y0)
+			p01 := rgba.PixOffset(origin.X+x0, origin.Y+y1)
+			p11 := rgba.PixOffset(origin.X+x1, origin.Y+y1)
+			d := dst.PixOffset(x, y)
+
+			for c := 0; c < 4; c++ {
+				top := float64(rgba.Pix[p00+c])*(1-fx) + float64(rgba.Pix[p10+c])*fx
+				bottom := float64(rgba.Pix[p01+c])*(1-fx) + float64(rgba.Pix[p11+c])*fx
+				dst.Pix[d+c] = uint8(top*(1-fy) + bottom*fy + 0.5)
+			}
+		}
+	}
+
+	return dst
+}
+
+// clampFloor returns the integer part of v clamped to [0, size-1] and the fractional weight
+func clampFloor(v float64, size int) (int, float64) {
+	if v <= 0 {
+		return 0, 0
+	}
+	i := int(v)
+	if i >= size-1 {
+		return size - 1, 0
+	}
+	return i, v - float64(i)
+}
@@ -0,0 +1,129 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeJPEG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, nil))
+	return buf.Bytes()
+}
+
+func decodeSize(t *testing.T, data []byte) (int, int, string) {
+	t.Helper()
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	require.NoError(t, err)
+	return cfg.Width, cfg.Height, format
+}
+
+func TestDownscale(t *testing.T) {
+	t.Run("large image is downscaled preserving aspect ratio", func(t *testing.T) {
+		data := encodeJPEG(t, 4000, 3000)
+
+		out, resized, err := Downscale(data, 1000)
+
+		require.NoError(t, err)
+		assert.True(t, resized)
+		w, h, format := decodeSize(t, out)
+		assert.Equal(t, 1000, w)
+		assert.Equal(t, 750, h)
+		assert.Equal(t, "jpeg", format)
+		assert.Less(t, len(out), len(data))
+	})
+
+	t.Run("portrait image is limited by height", func(t *testing.T) {
+		data := encodeJPEG(t, 600, 1200)
+
+		out, resized, err := Downscale(data, 400)
+
+		require.NoError(t, err)
+		assert.True(t, resized)
+		w, h, _ := decodeSize(t, out)
+		assert.Equal(t, 200, w)
+		assert.Equal(t, 400, h)
+	})
+
+	t.Run("png stays png", func(t *testing.T) {
+		img := image.NewNRGBA(image.Rect(0, 0, 800, 400))
+		var buf bytes.Buffer
+		require.NoError(t, png.Encode(&buf, img))
+
+		out, resized, err := Downscale(buf.Bytes(), 200)
+
+		require.NoError(t, err)
+		assert.True(t, resized)
+		w, h, format := decodeSize(t, out)
+		assert.Equal(t, 200, w)
+		assert.Equal(t, 100, h)
+		assert.Equal(t, "png", format)
+	})
+
+	t.Run("small image is untouched", func(t *testing.T) {
+		data := encodeJPEG(t, 640, 480)
+
+		out, resized, err := Downscale(data, 1000)
+
+		require.NoError(t, err)
+		assert.False(t, resized)
+		assert.Equal(t, data, out)
+	})
+
+	t.Run("disabled when max dimension is zero", func(t *testing.T) {
+		data := encodeJPEG(t, 2000, 2000)
+
+		out, resized, err := Downscale(data, 0)
+
+		require.NoError(t, err)
+		assert.False(t, resized)
+		assert.Equal(t, data, out)
+	})
+
+	t.Run("unknown format is passed through", func(t *testing.T) {
+		data := []byte("RIFF....WEBPVP8 not decodable here")
+
+		out, resized, err := Downscale(data, 100)
+
+		require.NoError(t, err)
+		assert.False(t, resized)
+		assert.Equal(t, data, out)
+	})
+}
+
+func TestDownscaleImage(t *testing.T) {
+	t.Run("returns the downscaled image", func(t *testing.T) {
+		out, img, err := DownscaleImage(encodeJPEG(t, 4000, 3000), 1000)
+
+		require.NoError(t, err)
+		require.NotNil(t, img)
+		assert.Equal(t, image.Rect(0, 0, 1000, 750), img.Bounds())
+		w, h, _ := decodeSize(t, out)
+		assert.Equal(t, 1000, w)
+		assert.Equal(t, 750, h)
+	})
+
+	t.Run("no image when the original is kept", func(t *testing.T) {
+		data := encodeJPEG(t, 320, 240)
+
+		out, img, err := DownscaleImage(data, 1000)
+
+		require.NoError(t, err)
+		assert.Nil(t, img)
+		assert.Equal(t, data, out)
+	})
+}
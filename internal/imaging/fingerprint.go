@@ -0,0 +1,57 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"math"
+)
+
+// fingerprintSize is the side of the grayscale thumbnail compared between frames. It is small
+// enough to average out sensor noise and JPEG re-encoding, so only real movement changes it.
+const fingerprintSize = 16
+
+// Fingerprint returns a fingerprintSize x fingerprintSize grayscale thumbnail of the image for
+// FrameDiff, or false when the format cannot be decoded here (e.g. WebP)
+func Fingerprint(data []byte) ([]byte, bool) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, false
+	}
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return nil, false
+	}
+
+	var sums, counts [fingerprintSize * fingerprintSize]float64
+	stepX := max(bounds.Dx()/blankSampleGrid, 1)
+	stepY := max(bounds.Dy()/blankSampleGrid, 1)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += stepY {
+		cy := (y - bounds.Min.Y) * fingerprintSize / bounds.Dy()
+		for x := bounds.Min.X; x < bounds.Max.X; x += stepX {
+			cx := (x - bounds.Min.X) * fingerprintSize / bounds.Dx()
+			sums[cy*fingerprintSize+cx] += luma(img.At(x, y))
+			counts[cy*fingerprintSize+cx]++
+		}
+	}
+
+	fingerprint := make([]byte, len(sums))
+	for i := range sums {
+		if counts[i] > 0 {
+			fingerprint[i] = uint8(math.Round(sums[i] / counts[i]))
+		}
+	}
+	return fingerprint, true
+}
+
+// FrameDiff returns the mean absolute difference (0-255) between two fingerprints.
+// Fingerprints of different sizes are as different as possible.
+func FrameDiff(a, b []byte) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 255
+	}
+	var sum float64
+	for i := range a {
+		sum += math.Abs(float64(a[i]) - float64(b[i]))
+	}
+	return sum / float64(len(a))
+}
@@ -0,0 +1,43 @@
+package imaging
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprint(t *testing.T) {
+	t.Run("same scene re-encoded is near-identical", func(t *testing.T) {
+		a, ok := Fingerprint(encodePhotoJPEG(t, 640, 480))
+		require.True(t, ok)
+		b, ok := Fingerprint(encodePhotoJPEG(t, 1280, 960))
+		require.True(t, ok)
+
+		assert.Len(t, a, fingerprintSize*fingerprintSize)
+		assert.Less(t, FrameDiff(a, b), 2.0)
+	})
+
+	t.Run("different scenes differ", func(t *testing.T) {
+		a, ok := Fingerprint(encodePhotoJPEG(t, 640, 480))
+		require.True(t, ok)
+		b, ok := Fingerprint(encodeSolidJPEG(t, 640, 480, color.White))
+		require.True(t, ok)
+
+		assert.Greater(t, FrameDiff(a, b), 50.0)
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		_, ok := Fingerprint([]byte("RIFF....WEBPVP8 not decodable here"))
+
+		assert.False(t, ok)
+	})
+}
+
+func TestFrameDiff(t *testing.T) {
+	assert.Zero(t, FrameDiff([]byte{1, 2, 3}, []byte{1, 2, 3}))
+	assert.InDelta(t, 2, FrameDiff([]byte{0, 10}, []byte{4, 10}), 0.001)
+	assert.Equal(t, 255.0, FrameDiff([]byte{1}, []byte{1, 2}))
+	assert.Equal(t, 255.0, FrameDiff(nil, nil))
+}
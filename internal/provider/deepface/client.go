@@ -137,7 +137,7 @@ func (c *Client) doRequestWithRetry(ctx context.Context, method, path string, bo
 		}
 	}
 
-	return fmt.Errorf("%w: %v", ErrDeepFaceUnavailable, lastErr)
+	return fmt.Errorf("%w: %w", ErrDeepFaceUnavailable, lastErr)
 }
 
 // isClientError checks if the error is a 4xx client error
@@ -188,7 +188,7 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 	}
 
 	if resp.StatusCode >= 400 {
-		return fmt.Errorf("deepface returned status %d: %s", resp.StatusCode, string(respBody))
+		return &StatusError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	if result != nil {
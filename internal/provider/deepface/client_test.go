@@ -278,6 +278,11 @@ func TestClient_RetryExhaustion(t *testing.T) {
 	require.Error(t, err)
 	assert.ErrorIs(t, err, ErrDeepFaceUnavailable)
 	assert.Equal(t, 3, attempts, "expected initial attempt + 2 retries")
+
+	// The last response status is kept for provider error metrics
+	var statusErr *StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, "HTTP_503", statusErr.ErrorCode())
 }
 
 func TestClient_ContextCancellation(t *testing.T) {
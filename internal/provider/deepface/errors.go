@@ -1,6 +1,14 @@
 package deepface
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/provider"
+)
 
 var (
 	ErrDeepFaceUnavailable = errors.New("deepface service unavailable")
@@ -8,4 +16,39 @@ var (
 	ErrInvalidResponse     = errors.New("invalid response from deepface")
 	ErrNoFaceInResponse    = errors.New("no face data in deepface response")
 	ErrInvalidImageFormat  = errors.New("invalid image format for deepface")
+	ErrEmbeddingMismatch   = errors.New("embeddings are empty or differ in dimension")
 )
+
+// StatusError is an error response from the DeepFace service
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("deepface returned status %d: %s", e.StatusCode, e.Body)
+}
+
+// ErrorCode reports the HTTP status as the provider error code, e.g. HTTP_503
+func (e *StatusError) ErrorCode() string {
+	return "HTTP_" + strconv.Itoa(e.StatusCode)
+}
+
+// noFaceDetectedMessage is how DeepFace words the 400 for an image without a face when
+// detection is enforced
+const noFaceDetectedMessage = "face could not be detected"
+
+// noFace returns DeepFace's "no face" outcomes as *provider.NoFaceError, so a faceless upload
+// is reported to the client and is not recorded as a provider failure. Other errors are
+// returned unchanged.
+func noFace(err error) error {
+	if errors.Is(err, ErrNoFaceInResponse) {
+		return &provider.NoFaceError{Err: err}
+	}
+	var status *StatusError
+	if errors.As(err, &status) && status.StatusCode == http.StatusBadRequest &&
+		strings.Contains(strings.ToLower(status.Body), noFaceDetectedMessage) {
+		return &provider.NoFaceError{Err: err}
+	}
+	return err
+}
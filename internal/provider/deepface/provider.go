@@ -29,6 +29,11 @@ func NewProvider(config Config) *Provider {
 	}
 }
 
+// EmbeddingModel returns the DeepFace model that produces embeddings
+func (p *Provider) EmbeddingModel() string {
+	return "deepface/" + p.client.config.Model
+}
+
 // DetectFaces detects faces in the image
 func (p *Provider) DetectFaces(ctx context.Context, image []byte) ([]provider.DetectedFace, error) {
 	// DeepFace requires the data URL prefix to identify the image format
@@ -37,7 +42,7 @@ func (p *Provider) DetectFaces(ctx context.Context, image []byte) ([]provider.De
 
 	resp, err := p.client.Represent(ctx, imageBase64)
 	if err != nil {
-		return nil, fmt.Errorf("detect faces: %w", err)
+		return nil, fmt.Errorf("detect faces: %w", noFace(err))
 	}
 
 	faces := make([]provider.DetectedFace, 0, len(resp.Results))
@@ -59,6 +64,9 @@ func (p *Provider) DetectFaces(ctx context.Context, image []byte) ([]provider.De
 		})
 	}
 
+	// Largest face first so callers can pick the primary subject
+	provider.SortByArea(faces)
+
 	return faces, nil
 }
 
@@ -86,6 +94,18 @@ func calculateQuality(faceArea float64) float64 {
 	return 0.6 + (normalized * 0.35)
 }
 
+// largestResult returns the result with the largest facial area
+// DeepFace does not order results by size, so bystanders may come first
+func largestResult(results []RepresentResult) RepresentResult {
+	largest := results[0]
+	for _, r := range results[1:] {
+		if r.FacialArea.W*r.FacialArea.H > largest.FacialArea.W*largest.FacialArea.H {
+			largest = r
+		}
+	}
+	return largest
+}
+
 // IndexFace extracts face embedding from image
 func (p *Provider) IndexFace(ctx context.Context, image []byte) (string, []float64, error) {
 	// DeepFace requires the data URL prefix to identify the image format
@@ -93,15 +113,15 @@ func (p *Provider) IndexFace(ctx context.Context, image []byte) (string, []float
 
 	resp, err := p.client.Represent(ctx, imageBase64)
 	if err != nil {
-		return "", nil, fmt.Errorf("index face: %w", err)
+		return "", nil, fmt.Errorf("index face: %w", noFace(err))
 	}
 
 	if len(resp.Results) == 0 {
-		return "", nil, ErrNoFaceInResponse
+		return "", nil, noFace(ErrNoFaceInResponse)
 	}
 
-	// Use first face found
-	result := resp.Results[0]
+	// Use largest face found
+	result := largestResult(resp.Results)
 
 	// Generate local UUID as face ID (DeepFace doesn't persist faces)
 	faceID := uuid.New().String()
@@ -110,11 +130,14 @@ func (p *Provider) IndexFace(ctx context.Context, image []byte) (string, []float
 }
 
 // CompareFaces calculates similarity between two embeddings
+// DeepFace has no comparison endpoint, so cosine similarity is computed in-process and
+// Verify needs no call beyond the one that extracted the new embedding.
 func (p *Provider) CompareFaces(ctx context.Context, embedding1, embedding2 []float64) (float64, error) {
-	// DeepFace doesn't have embedding comparison endpoint
-	// We calculate cosine similarity locally
-	similarity := CosineSimilarity(embedding1, embedding2)
-	return similarity, nil
+	// A mismatch would otherwise read as similarity 0 and fail verification silently
+	if len(embedding1) == 0 || len(embedding1) != len(embedding2) {
+		return 0, ErrEmbeddingMismatch
+	}
+	return CosineSimilarity(embedding1, embedding2), nil
 }
 
 // DeleteFace is a no-op for DeepFace (stateless provider)
@@ -180,14 +203,14 @@ func (p *Provider) AnalyzeFace(ctx context.Context, image []byte) (*provider.Fac
 
 	resp, err := p.client.Represent(ctx, imageBase64)
 	if err != nil {
-		return nil, fmt.Errorf("analyze face: %w", err)
+		return nil, fmt.Errorf("analyze face: %w", noFace(err))
 	}
 
 	if len(resp.Results) == 0 {
-		return nil, ErrNoFaceInResponse
+		return nil, noFace(ErrNoFaceInResponse)
 	}
 
-	result := resp.Results[0]
+	result := largestResult(resp.Results)
 	faceArea := float64(result.FacialArea.W * result.FacialArea.H)
 	confidence := calculateConfidence(faceArea)
 	qualityScore := calculateQuality(faceArea)
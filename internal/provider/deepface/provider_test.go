@@ -3,6 +3,7 @@ package deepface
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -275,6 +276,11 @@ func TestProvider_CompareFaces(t *testing.T) {
 			assert.LessOrEqual(t, similarity, tt.wantMax)
 		})
 	}
+
+	t.Run("mismatched dimensions", func(t *testing.T) {
+		_, err := p.CompareFaces(context.Background(), []float64{1.0, 0.0}, []float64{1.0, 0.0, 0.0})
+		assert.ErrorIs(t, err, ErrEmbeddingMismatch)
+	})
 }
 
 // TestProvider_DeleteFace tests face deletion (no-op)
@@ -412,3 +418,48 @@ func TestNormalizeEmbedding(t *testing.T) {
 		})
 	}
 }
+
+// TestProvider_NoFace verifies that DeepFace's "no face" outcomes are reported as
+// *provider.NoFaceError and not as provider failures
+func TestProvider_NoFace(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantNoFace bool
+	}{
+		{name: "empty results", status: http.StatusOK, body: `{"results": []}`, wantNoFace: true},
+		{
+			name:       "enforce_detection rejection",
+			status:     http.StatusBadRequest,
+			body:       `{"error": "Exception while representing: Face could not be detected in numpy array. Please confirm that the picture is a face photo or consider to set enforce_detection param to False."}`,
+			wantNoFace: true,
+		},
+		{name: "other bad request", status: http.StatusBadRequest, body: `{"error": "you must pass img"}`},
+		{name: "server error", status: http.StatusInternalServerError, body: `{"error": "boom"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			config := DefaultConfig()
+			config.BaseURL = server.URL
+			config.RetryCount = 0
+			p := NewProvider(config)
+
+			_, _, indexErr := p.IndexFace(context.Background(), []byte("test-image"))
+			_, analyzeErr := p.AnalyzeFace(context.Background(), []byte("test-image"))
+
+			for _, err := range []error{indexErr, analyzeErr} {
+				require.Error(t, err)
+				var noFace *provider.NoFaceError
+				assert.Equal(t, tt.wantNoFace, errors.As(err, &noFace), err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/audit"
+)
+
+// Fallback provider error codes for failures that carry none
+const (
+	ErrorCodeTimeout = "TIMEOUT"
+	ErrorCodeNetwork = "NETWORK_ERROR"
+	ErrorCodeUnknown = "UNKNOWN"
+)
+
+// errorAuditTimeout bounds recording a failure, which must not hold up the request
+const errorAuditTimeout = 2 * time.Second
+
+// ErrorCode returns the provider's raw code for err, e.g. ThrottlingException from AWS or
+// HTTP_503 from DeepFace. Failures without one are reported as TIMEOUT, NETWORK_ERROR or UNKNOWN.
+func ErrorCode(err error) string {
+	// AWS API errors and DeepFace status errors expose their code this way
+	var coded interface{ ErrorCode() string }
+	if errors.As(err, &coded) && coded.ErrorCode() != "" {
+		return coded.ErrorCode()
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorCodeTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return ErrorCodeTimeout
+		}
+		return ErrorCodeNetwork
+	}
+	return ErrorCodeUnknown
+}
+
+// WithErrorAudit records each failed call of p in the audit log as a PROVIDER_ERROR event with
+// the provider's raw error code, so operators can see which failures dominate during an outage.
+// Images without a usable face and calls abandoned by the client are not provider failures and
+// are not recorded.
+func WithErrorAudit(p FaceProvider, name string, logger audit.Logger) FaceProvider {
+	audited := &errorAudit{FaceProvider: p, name: name, logger: logger}
+	if comparer, ok := p.(imageComparer); ok {
+		return &imageComparingErrorAudit{errorAudit: audited, comparer: comparer}
+	}
+	return audited
+}
+
+// imageComparer is implemented by providers that compare two images directly, like Rekognition
+type imageComparer interface {
+	CompareFaceImages(ctx context.Context, sourceImage, targetImage []byte, similarityThreshold float64) (float64, error)
+}
+
+type errorAudit struct {
+	FaceProvider
+	name   string
+	logger audit.Logger
+}
+
+// record logs err as a PROVIDER_ERROR event when it is a provider failure
+func (p *errorAudit) record(ctx context.Context, operation string, err error) {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+	var noFace *NoFaceError
+	var multipleFaces *MultipleFacesError
+	if errors.As(err, &noFace) || errors.As(err, &multipleFaces) {
+		return
+	}
+
+	// The call's own deadline may be what failed it
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), errorAuditTimeout)
+	defer cancel()
+
+	_ = p.logger.Log(ctx, audit.Event{
+		EventType: audit.EventProviderError,
+		Provider:  p.name,
+		Success:   false,
+		Error:     err.Error(),
+		ErrorCode: ErrorCode(err),
+		Metadata:  map[string]string{"operation": operation},
+	})
+}
+
+// EmbeddingModel forwards the wrapped provider's model, which embedding hides from type assertions
+func (p *errorAudit) EmbeddingModel() string {
+	if modeler, ok := p.FaceProvider.(EmbeddingModeler); ok {
+		return modeler.EmbeddingModel()
+	}
+	return ""
+}
+
+// ProviderName returns the configured name the failures are recorded under
+func (p *errorAudit) ProviderName() string {
+	return p.name
+}
+
+func (p *errorAudit) DetectFaces(ctx context.Context, image []byte) ([]DetectedFace, error) {
+	faces, err := p.FaceProvider.DetectFaces(ctx, image)
+	p.record(ctx, "detect_faces", err)
+	return faces, err
+}
+
+func (p *errorAudit) IndexFace(ctx context.Context, image []byte) (string, []float64, error) {
+	faceID, embedding, err := p.FaceProvider.IndexFace(ctx, image)
+	p.record(ctx, "index_face", err)
+	return faceID, embedding, err
+}
+
+func (p *errorAudit) CompareFaces(ctx context.Context, embedding1, embedding2 []float64) (float64, error) {
+	similarity, err := p.FaceProvider.CompareFaces(ctx, embedding1, embedding2)
+	p.record(ctx, "compare_faces", err)
+	return similarity, err
+}
+
+func (p *errorAudit) DeleteFace(ctx context.Context, faceID string) error {
+	err := p.FaceProvider.DeleteFace(ctx, faceID)
+	p.record(ctx, "delete_face", err)
+	return err
+}
+
+func (p *errorAudit) CheckLiveness(ctx context.Context, image []byte, threshold float64) (*LivenessResult, error) {
+	result, err := p.FaceProvider.CheckLiveness(ctx, image, threshold)
+	p.record(ctx, "check_liveness", err)
+	return result, err
+}
+
+func (p *errorAudit) AnalyzeFace(ctx context.Context, image []byte) (*FaceAnalysis, error) {
+	analysis, err := p.FaceProvider.AnalyzeFace(ctx, image)
+	p.record(ctx, "analyze_face", err)
+	return analysis, err
+}
+
+// imageComparingErrorAudit keeps CompareFaceImages visible for providers that have it
+type imageComparingErrorAudit struct {
+	*errorAudit
+	comparer imageComparer
+}
+
+func (p *imageComparingErrorAudit) CompareFaceImages(ctx context.Context, sourceImage, targetImage []byte, similarityThreshold float64) (float64, error) {
+	similarity, err := p.comparer.CompareFaceImages(ctx, sourceImage, targetImage, similarityThreshold)
+	p.record(ctx, "compare_face_images", err)
+	return similarity, err
+}
@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/audit"
+)
+
+// codedError mimics an AWS API error
+type codedError struct{ code string }
+
+func (e *codedError) Error() string     { return "api error " + e.code }
+func (e *codedError) ErrorCode() string { return e.code }
+
+// timeoutError is a network error that timed out
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// eventRecorder keeps the audit events it is given
+type eventRecorder struct {
+	events []audit.Event
+	ctxErr error
+}
+
+func (r *eventRecorder) Log(ctx context.Context, event audit.Event) error {
+	r.events = append(r.events, event)
+	r.ctxErr = ctx.Err()
+	return nil
+}
+
+type comparingProvider struct {
+	*recordingProvider
+}
+
+func (p comparingProvider) CompareFaceImages(ctx context.Context, sourceImage, targetImage []byte, similarityThreshold float64) (float64, error) {
+	return 0, p.err
+}
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"provider code", fmt.Errorf("index face: %w", &codedError{code: "ThrottlingException"}), "ThrottlingException"},
+		{"deadline", fmt.Errorf("detect faces: %w", context.DeadlineExceeded), ErrorCodeTimeout},
+		{"network timeout", &net.OpError{Op: "dial", Err: timeoutError{}}, ErrorCodeTimeout},
+		{"network", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, ErrorCodeNetwork},
+		{"unknown", errors.New("boom"), ErrorCodeUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ErrorCode(tt.err))
+		})
+	}
+}
+
+func TestWithErrorAudit(t *testing.T) {
+	t.Run("records failures with their code", func(t *testing.T) {
+		recorder := &eventRecorder{}
+		inner := &recordingProvider{err: fmt.Errorf("detect faces: %w", &codedError{code: "ProvisionedThroughputExceededException"})}
+		p := WithErrorAudit(inner, "rekognition", recorder)
+
+		_, err := p.DetectFaces(context.Background(), []byte("image"))
+		require.Error(t, err)
+
+		require.Len(t, recorder.events, 1)
+		event := recorder.events[0]
+		assert.Equal(t, audit.EventProviderError, event.EventType)
+		assert.Equal(t, "rekognition", event.Provider)
+		assert.False(t, event.Success)
+		assert.Equal(t, "ProvisionedThroughputExceededException", event.ErrorCode)
+		assert.Equal(t, "detect_faces", event.Metadata["operation"])
+	})
+
+	t.Run("successful calls are not recorded", func(t *testing.T) {
+		recorder := &eventRecorder{}
+		p := WithErrorAudit(&recordingProvider{analysis: &FaceAnalysis{}}, "deepface", recorder)
+
+		_, _, err := p.IndexFace(context.Background(), []byte("image"))
+		require.NoError(t, err)
+		_, err = p.AnalyzeFace(context.Background(), []byte("image"))
+		require.NoError(t, err)
+
+		assert.Empty(t, recorder.events)
+	})
+
+	t.Run("face rejections and cancellations are not provider failures", func(t *testing.T) {
+		for _, err := range []error{
+			&NoFaceError{Err: errors.New("no face")},
+			&MultipleFacesError{Count: 2, Err: errors.New("two faces")},
+			fmt.Errorf("index face: %w", context.Canceled),
+		} {
+			recorder := &eventRecorder{}
+			p := WithErrorAudit(&recordingProvider{err: err}, "deepface", recorder)
+
+			_, _, _ = p.IndexFace(context.Background(), []byte("image"))
+			assert.Empty(t, recorder.events, err.Error())
+		}
+	})
+
+	t.Run("timed out calls are still recorded", func(t *testing.T) {
+		recorder := &eventRecorder{}
+		p := WithErrorAudit(&recordingProvider{err: context.DeadlineExceeded}, "deepface", recorder)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 0)
+		defer cancel()
+		_, _ = p.CompareFaces(ctx, nil, nil)
+
+		require.Len(t, recorder.events, 1)
+		assert.Equal(t, ErrorCodeTimeout, recorder.events[0].ErrorCode)
+		assert.NoError(t, recorder.ctxErr, "the audit write gets its own deadline")
+	})
+
+	t.Run("keeps optional interfaces", func(t *testing.T) {
+		plain := WithErrorAudit(&recordingProvider{}, "deepface", &eventRecorder{})
+		_, ok := plain.(imageComparer)
+		assert.False(t, ok, "image comparison is not added to providers without it")
+
+		modeled := WithErrorAudit(modeledProvider{&recordingProvider{}}, "deepface", &eventRecorder{})
+		require.Implements(t, (*EmbeddingModeler)(nil), modeled)
+		assert.Equal(t, "deepface/Facenet512", modeled.(EmbeddingModeler).EmbeddingModel())
+
+		recorder := &eventRecorder{}
+		comparing := WithErrorAudit(comparingProvider{&recordingProvider{err: &codedError{code: "InvalidImageFormatException"}}}, "rekognition", recorder)
+		comparer, ok := comparing.(imageComparer)
+		require.True(t, ok)
+		_, _ = comparer.CompareFaceImages(context.Background(), nil, nil, 0)
+		require.Len(t, recorder.events, 1)
+		assert.Equal(t, "compare_face_images", recorder.events[0].Metadata["operation"])
+	})
+}
@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+)
+
+// LivenessProvider performs passive liveness detection.
+// Every FaceProvider is one; dedicated anti-spoofing services only need this method.
+type LivenessProvider interface {
+	CheckLiveness(ctx context.Context, image []byte, threshold float64) (*LivenessResult, error)
+}
+
+// WithLivenessProvider composes recognition and liveness: CheckLiveness and the liveness
+// portion of AnalyzeFace go to liveness, detection, embeddings and comparison stay with recognition.
+func WithLivenessProvider(recognition FaceProvider, liveness LivenessProvider) FaceProvider {
+	composite := &livenessComposite{FaceProvider: recognition, liveness: liveness}
+	if comparer, ok := recognition.(imageComparer); ok {
+		return &imageComparingLivenessComposite{livenessComposite: composite, comparer: comparer}
+	}
+	return composite
+}
+
+type livenessComposite struct {
+	FaceProvider
+	liveness LivenessProvider
+}
+
+// EmbeddingModel forwards the recognition provider's model, which embedding hides from type assertions
+func (p *livenessComposite) EmbeddingModel() string {
+	if modeler, ok := p.FaceProvider.(EmbeddingModeler); ok {
+		return modeler.EmbeddingModel()
+	}
+	return ""
+}
+
+func (p *livenessComposite) CheckLiveness(ctx context.Context, image []byte, threshold float64) (*LivenessResult, error) {
+	return p.liveness.CheckLiveness(ctx, image, threshold)
+}
+
+// AnalyzeFace runs the recognition analysis, then replaces its liveness score and checks
+// with the liveness provider's. Images without a face skip the liveness call.
+func (p *livenessComposite) AnalyzeFace(ctx context.Context, image []byte) (*FaceAnalysis, error) {
+	analysis, err := p.FaceProvider.AnalyzeFace(ctx, image)
+	if err != nil || analysis.FaceCount == 0 {
+		return analysis, err
+	}
+
+	// Threshold 0: only the confidence is used, callers apply their own threshold to it
+	liveness, err := p.liveness.CheckLiveness(ctx, image, 0)
+	if err != nil {
+		return nil, fmt.Errorf("analyze face: liveness: %w", err)
+	}
+
+	analysis.LivenessScore = liveness.Confidence
+	analysis.LivenessChecks = liveness.Checks
+	return analysis, nil
+}
+
+// imageComparingLivenessComposite keeps CompareFaceImages visible when recognition has it, so
+// callers still compare images on Rekognition instead of indexing them into the collection
+type imageComparingLivenessComposite struct {
+	*livenessComposite
+	comparer imageComparer
+}
+
+func (p *imageComparingLivenessComposite) CompareFaceImages(ctx context.Context, sourceImage, targetImage []byte, similarityThreshold float64) (float64, error) {
+	return p.comparer.CompareFaceImages(ctx, sourceImage, targetImage, similarityThreshold)
+}
@@ -0,0 +1,182 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingProvider records which methods were called and returns fixed results
+type recordingProvider struct {
+	name     string
+	calls    []string
+	analysis *FaceAnalysis
+	liveness *LivenessResult
+	err      error
+}
+
+func (p *recordingProvider) DetectFaces(ctx context.Context, image []byte) ([]DetectedFace, error) {
+	p.calls = append(p.calls, "DetectFaces")
+	return []DetectedFace{{Confidence: 0.99}}, p.err
+}
+
+func (p *recordingProvider) IndexFace(ctx context.Context, image []byte) (string, []float64, error) {
+	p.calls = append(p.calls, "IndexFace")
+	return p.name + "-face", []float64{0.1, 0.2}, p.err
+}
+
+func (p *recordingProvider) CompareFaces(ctx context.Context, embedding1, embedding2 []float64) (float64, error) {
+	p.calls = append(p.calls, "CompareFaces")
+	return 0.9, p.err
+}
+
+func (p *recordingProvider) DeleteFace(ctx context.Context, faceID string) error {
+	p.calls = append(p.calls, "DeleteFace")
+	return p.err
+}
+
+func (p *recordingProvider) CheckLiveness(ctx context.Context, image []byte, threshold float64) (*LivenessResult, error) {
+	p.calls = append(p.calls, "CheckLiveness")
+	return p.liveness, p.err
+}
+
+func (p *recordingProvider) AnalyzeFace(ctx context.Context, image []byte) (*FaceAnalysis, error) {
+	p.calls = append(p.calls, "AnalyzeFace")
+	if p.err != nil {
+		return nil, p.err
+	}
+	analysis := *p.analysis
+	return &analysis, nil
+}
+
+type modeledProvider struct {
+	*recordingProvider
+}
+
+func (p modeledProvider) EmbeddingModel() string {
+	return "deepface/Facenet512"
+}
+
+func TestWithLivenessProvider(t *testing.T) {
+	ctx := context.Background()
+	image := []byte("image")
+
+	newProviders := func() (*recordingProvider, *recordingProvider) {
+		recognition := &recordingProvider{
+			name: "recognition",
+			analysis: &FaceAnalysis{
+				Embedding:      []float64{0.1, 0.2},
+				QualityScore:   0.9,
+				LivenessScore:  0.2,
+				LivenessChecks: LivenessChecks{EyesOpen: false},
+				FaceCount:      1,
+			},
+			liveness: &LivenessResult{IsLive: false, Confidence: 0.2},
+		}
+		liveness := &recordingProvider{
+			name:     "liveness",
+			liveness: &LivenessResult{IsLive: true, Confidence: 0.97, Checks: LivenessChecks{EyesOpen: true, FacingCamera: true}},
+		}
+		return recognition, liveness
+	}
+
+	t.Run("liveness calls hit the liveness provider", func(t *testing.T) {
+		recognition, liveness := newProviders()
+		composed := WithLivenessProvider(recognition, liveness)
+
+		result, err := composed.CheckLiveness(ctx, image, 0.9)
+
+		require.NoError(t, err)
+		assert.True(t, result.IsLive)
+		assert.Equal(t, 0.97, result.Confidence)
+		assert.Equal(t, []string{"CheckLiveness"}, liveness.calls)
+		assert.Empty(t, recognition.calls)
+	})
+
+	t.Run("recognition calls hit the recognition provider", func(t *testing.T) {
+		recognition, liveness := newProviders()
+		composed := WithLivenessProvider(recognition, liveness)
+
+		_, err := composed.DetectFaces(ctx, image)
+		require.NoError(t, err)
+		faceID, _, err := composed.IndexFace(ctx, image)
+		require.NoError(t, err)
+		_, err = composed.CompareFaces(ctx, []float64{1}, []float64{1})
+		require.NoError(t, err)
+		require.NoError(t, composed.DeleteFace(ctx, faceID))
+
+		assert.Equal(t, "recognition-face", faceID)
+		assert.Equal(t, []string{"DetectFaces", "IndexFace", "CompareFaces", "DeleteFace"}, recognition.calls)
+		assert.Empty(t, liveness.calls)
+	})
+
+	t.Run("analyze takes liveness from the liveness provider", func(t *testing.T) {
+		recognition, liveness := newProviders()
+		composed := WithLivenessProvider(recognition, liveness)
+
+		analysis, err := composed.AnalyzeFace(ctx, image)
+
+		require.NoError(t, err)
+		assert.Equal(t, []float64{0.1, 0.2}, analysis.Embedding)
+		assert.Equal(t, 0.9, analysis.QualityScore)
+		assert.Equal(t, 0.97, analysis.LivenessScore)
+		assert.Equal(t, LivenessChecks{EyesOpen: true, FacingCamera: true}, analysis.LivenessChecks)
+		assert.Equal(t, []string{"AnalyzeFace"}, recognition.calls)
+		assert.Equal(t, []string{"CheckLiveness"}, liveness.calls)
+	})
+
+	t.Run("analyze without a face skips liveness", func(t *testing.T) {
+		recognition, liveness := newProviders()
+		recognition.analysis.FaceCount = 0
+		composed := WithLivenessProvider(recognition, liveness)
+
+		analysis, err := composed.AnalyzeFace(ctx, image)
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, analysis.FaceCount)
+		assert.Empty(t, liveness.calls)
+	})
+
+	t.Run("liveness failure fails analysis", func(t *testing.T) {
+		recognition, liveness := newProviders()
+		liveness.err = errors.New("anti-spoof service unavailable")
+		composed := WithLivenessProvider(recognition, liveness)
+
+		_, err := composed.AnalyzeFace(ctx, image)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, liveness.err)
+	})
+
+	t.Run("embedding model of the recognition provider is kept", func(t *testing.T) {
+		recognition, liveness := newProviders()
+
+		composed := WithLivenessProvider(modeledProvider{recognition}, liveness)
+		modeler, ok := composed.(EmbeddingModeler)
+		require.True(t, ok)
+		assert.Equal(t, "deepface/Facenet512", modeler.EmbeddingModel())
+
+		assert.Empty(t, WithLivenessProvider(recognition, liveness).(EmbeddingModeler).EmbeddingModel())
+	})
+
+	t.Run("image comparison of the recognition provider is kept", func(t *testing.T) {
+		recognition, liveness := newProviders()
+
+		_, ok := WithLivenessProvider(recognition, liveness).(imageComparer)
+		assert.False(t, ok, "image comparison is not added to providers without it")
+
+		recognition.err = &codedError{code: "InvalidParameterException"}
+		composed := WithLivenessProvider(comparingProvider{recognition}, liveness)
+		comparer, ok := composed.(imageComparer)
+		require.True(t, ok)
+		_, err := comparer.CompareFaceImages(context.Background(), nil, nil, 0)
+		assert.ErrorIs(t, err, recognition.err)
+
+		// Error auditing on top of the composite still sees it
+		_, ok = WithErrorAudit(composed, "rekognition", &eventRecorder{}).(imageComparer)
+		assert.True(t, ok)
+	})
+}
@@ -20,6 +20,11 @@ func New() *Provider {
 	return &Provider{}
 }
 
+// EmbeddingModel retorna o modelo simulado de embeddings
+func (p *Provider) EmbeddingModel() string {
+	return "mock/sha256"
+}
+
 // DetectFaces simula detecção de faces
 func (p *Provider) DetectFaces(ctx context.Context, image []byte) ([]provider.DetectedFace, error) {
 	if len(image) < 1000 {
@@ -52,7 +57,7 @@ func (p *Provider) IndexFace(ctx context.Context, image []byte) (string, []float
 	return faceID, embedding, nil
 }
 
-// CompareFaces calcula similaridade coseno entre embeddings
+// CompareFaces calcula similaridade coseno entre embeddings localmente, sem chamada externa
 func (p *Provider) CompareFaces(ctx context.Context, emb1, emb2 []float64) (float64, error) {
 	if len(emb1) != embeddingDimension || len(emb2) != embeddingDimension {
 		return 0, domain.ErrInvalidImage.WithError(nil)
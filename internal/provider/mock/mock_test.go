@@ -2,6 +2,7 @@ package mock
 
 import (
 	"context"
+	"math"
 	"testing"
 )
 
@@ -127,6 +128,36 @@ func TestProvider_CompareFaces(t *testing.T) {
 	}
 }
 
+func TestProvider_CompareFaces_Cosine(t *testing.T) {
+	p := New()
+	ctx := context.Background()
+
+	x := make([]float64, embeddingDimension)
+	y := make([]float64, embeddingDimension)
+	x[0] = 1
+	y[1] = 1
+
+	identical, err := p.CompareFaces(ctx, x, x)
+	if err != nil {
+		t.Fatalf("CompareFaces() error = %v", err)
+	}
+	if math.Abs(identical-1.0) > 1e-9 {
+		t.Errorf("CompareFaces() identical = %f, want 1.0", identical)
+	}
+
+	orthogonal, err := p.CompareFaces(ctx, x, y)
+	if err != nil {
+		t.Fatalf("CompareFaces() error = %v", err)
+	}
+	if math.Abs(orthogonal) > 1e-9 {
+		t.Errorf("CompareFaces() orthogonal = %f, want ~0.0", orthogonal)
+	}
+
+	if _, err := p.CompareFaces(ctx, x, y[:10]); err == nil {
+		t.Error("CompareFaces() expected error for mismatched dimensions")
+	}
+}
+
 func TestProvider_DeleteFace(t *testing.T) {
 	p := New()
 	ctx := context.Background()
@@ -1,6 +1,11 @@
 package provider
 
-import "context"
+import (
+	"context"
+	"sort"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
 
 // FaceProvider define a interface para provedores de reconhecimento facial
 type FaceProvider interface {
@@ -28,6 +33,47 @@ type FaceProvider interface {
 	AnalyzeFace(ctx context.Context, image []byte) (*FaceAnalysis, error)
 }
 
+// EmbeddingModeler is implemented by providers whose embeddings come from an identifiable model.
+// The model name is stored with each face so embeddings from different models are never compared.
+type EmbeddingModeler interface {
+	// EmbeddingModel returns the provider and model that produce embeddings, e.g. "deepface/Facenet512"
+	EmbeddingModel() string
+}
+
+// Namer is implemented by providers that know the name they were configured with, e.g. "rekognition"
+type Namer interface {
+	ProviderName() string
+}
+
+// NoFaceError reports that no usable face was found, with the reasons when the provider gives them
+type NoFaceError struct {
+	Reasons []domain.NoFaceReason
+	Err     error
+}
+
+func (e *NoFaceError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *NoFaceError) Unwrap() error {
+	return e.Err
+}
+
+// MultipleFacesError reports that the image held more faces than the operation accepts
+type MultipleFacesError struct {
+	// Count is how many faces the provider detected
+	Count int
+	Err   error
+}
+
+func (e *MultipleFacesError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *MultipleFacesError) Unwrap() error {
+	return e.Err
+}
+
 // DetectedFace represents a detected face in the image
 type DetectedFace struct {
 	BoundingBox  BoundingBox `json:"bounding_box"`
@@ -52,6 +98,37 @@ type BoundingBox struct {
 	Height float64 `json:"height"`
 }
 
+// Area returns the bounding box area in the provider's coordinate units
+func (b BoundingBox) Area() float64 {
+	return b.Width * b.Height
+}
+
+// InPixels reports whether the box is in pixels rather than fractions of the image size.
+// Rekognition reports fractions and DeepFace pixels; a fractional box is never wider or taller than 1.
+func (b BoundingBox) InPixels() bool {
+	return b.Width > 1 || b.Height > 1
+}
+
+// Fraction converts a pixel box to fractions of an image of the given size
+func (b BoundingBox) Fraction(imageWidth, imageHeight int) BoundingBox {
+	w, h := float64(imageWidth), float64(imageHeight)
+	return BoundingBox{X: b.X / w, Y: b.Y / h, Width: b.Width / w, Height: b.Height / h}
+}
+
+// WithinMargin reports whether a fractional box keeps at least margin from every image edge.
+// Boxes of faces cut off by the frame start below 0 or end past 1.
+func (b BoundingBox) WithinMargin(margin float64) bool {
+	return b.X >= margin && b.Y >= margin &&
+		b.X+b.Width <= 1-margin && b.Y+b.Height <= 1-margin
+}
+
+// SortByArea orders detected faces by bounding box area, largest first
+func SortByArea(faces []DetectedFace) {
+	sort.SliceStable(faces, func(i, j int) bool {
+		return faces[i].BoundingBox.Area() > faces[j].BoundingBox.Area()
+	})
+}
+
 // LivenessResult represents the result of a liveness check
 type LivenessResult struct {
 	IsLive     bool           `json:"is_live"`
@@ -0,0 +1,37 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoundingBox_WithinMargin(t *testing.T) {
+	tests := []struct {
+		name string
+		box  BoundingBox
+		want bool
+	}{
+		{name: "centered", box: BoundingBox{X: 0.3, Y: 0.2, Width: 0.4, Height: 0.5}, want: true},
+		{name: "starting at the margin", box: BoundingBox{X: 0.05, Y: 0.05, Width: 0.85, Height: 0.85}, want: true},
+		{name: "touching the left edge", box: BoundingBox{X: 0.01, Y: 0.2, Width: 0.4, Height: 0.5}},
+		{name: "cut off at the top", box: BoundingBox{X: 0.3, Y: -0.08, Width: 0.4, Height: 0.5}},
+		{name: "extends past the right edge", box: BoundingBox{X: 0.7, Y: 0.2, Width: 0.4, Height: 0.5}},
+		{name: "too close to the bottom", box: BoundingBox{X: 0.3, Y: 0.5, Width: 0.4, Height: 0.48}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.box.WithinMargin(0.05))
+		})
+	}
+}
+
+func TestBoundingBox_Fraction(t *testing.T) {
+	pixels := BoundingBox{X: 160, Y: 60, Width: 320, Height: 240}
+	assert.True(t, pixels.InPixels())
+
+	box := pixels.Fraction(640, 480)
+	assert.False(t, box.InPixels())
+	assert.Equal(t, BoundingBox{X: 0.25, Y: 0.125, Width: 0.5, Height: 0.5}, box)
+}
@@ -4,12 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/rekognition"
 	"github.com/aws/aws-sdk-go-v2/service/rekognition/types"
 	"github.com/aws/smithy-go"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/provider"
 )
 
 const (
@@ -30,6 +36,7 @@ type RekognitionAPI interface {
 	DeleteCollection(ctx context.Context, params *rekognition.DeleteCollectionInput, optFns ...func(*rekognition.Options)) (*rekognition.DeleteCollectionOutput, error)
 	DescribeCollection(ctx context.Context, params *rekognition.DescribeCollectionInput, optFns ...func(*rekognition.Options)) (*rekognition.DescribeCollectionOutput, error)
 	ListCollections(ctx context.Context, params *rekognition.ListCollectionsInput, optFns ...func(*rekognition.Options)) (*rekognition.ListCollectionsOutput, error)
+	TagResource(ctx context.Context, params *rekognition.TagResourceInput, optFns ...func(*rekognition.Options)) (*rekognition.TagResourceOutput, error)
 }
 
 // Client wraps the AWS Rekognition client and provides collection management operations
@@ -50,7 +57,7 @@ func NewClient(ctx context.Context, cfg Config) (*Client, error) {
 	}
 
 	return &Client{
-		rekognition: rekognition.NewFromConfig(awsCfg),
+		rekognition: limitAPI(rekognition.NewFromConfig(awsCfg), accountLimiter(cfg)),
 		config:      cfg,
 	}, nil
 }
@@ -62,6 +69,7 @@ func (c *Client) CreateCollection(ctx context.Context, tenantID string) error {
 
 	input := &rekognition.CreateCollectionInput{
 		CollectionId: aws.String(collectionID),
+		Tags:         c.config.CollectionTags(tenantID),
 	}
 
 	_, err := c.rekognition.CreateCollection(ctx, input)
@@ -75,7 +83,7 @@ func (c *Client) CreateCollection(ctx context.Context, tenantID string) error {
 			case errCodeInvalidParameter:
 				return fmt.Errorf("tenant %s: invalid collection parameters: %w", tenantID, err)
 			case errCodeAccessDenied:
-				return fmt.Errorf("tenant %s: %w", tenantID, ErrInvalidCredentials)
+				return fmt.Errorf("tenant %s: %w", tenantID, ErrProviderAccessDenied)
 			}
 		}
 		return fmt.Errorf("failed to create collection for tenant %s: %w", tenantID, err)
@@ -102,7 +110,7 @@ func (c *Client) DeleteCollection(ctx context.Context, tenantID string) error {
 			case errCodeResourceNotFound:
 				return fmt.Errorf("tenant %s: %w", tenantID, ErrCollectionNotFound)
 			case errCodeAccessDenied:
-				return fmt.Errorf("tenant %s: %w", tenantID, ErrInvalidCredentials)
+				return fmt.Errorf("tenant %s: %w", tenantID, ErrProviderAccessDenied)
 			}
 		}
 		return fmt.Errorf("failed to delete collection for tenant %s: %w", tenantID, err)
@@ -125,7 +133,7 @@ func (c *Client) ListCollections(ctx context.Context) ([]string, error) {
 		if err != nil {
 			var apiErr smithy.APIError
 			if errors.As(err, &apiErr) && apiErr.ErrorCode() == errCodeAccessDenied {
-				return nil, fmt.Errorf("list collections: %w", ErrInvalidCredentials)
+				return nil, fmt.Errorf("list collections: %w", ErrProviderAccessDenied)
 			}
 			return nil, fmt.Errorf("failed to list collections: %w", err)
 		}
@@ -152,7 +160,7 @@ func (c *Client) CollectionExists(ctx context.Context, tenantID string) (bool, e
 			case errCodeResourceNotFound:
 				return false, nil
 			case errCodeAccessDenied:
-				return false, fmt.Errorf("tenant %s: %w", tenantID, ErrInvalidCredentials)
+				return false, fmt.Errorf("tenant %s: %w", tenantID, ErrProviderAccessDenied)
 			}
 		}
 		return false, fmt.Errorf("failed to check collection for tenant %s: %w", tenantID, err)
@@ -177,7 +185,7 @@ func (c *Client) GetCollectionFaceCount(ctx context.Context, tenantID string) (i
 			case errCodeResourceNotFound:
 				return 0, fmt.Errorf("tenant %s: %w", tenantID, ErrCollectionNotFound)
 			case errCodeAccessDenied:
-				return 0, fmt.Errorf("tenant %s: %w", tenantID, ErrInvalidCredentials)
+				return 0, fmt.Errorf("tenant %s: %w", tenantID, ErrProviderAccessDenied)
 			}
 		}
 		return 0, fmt.Errorf("failed to describe collection for tenant %s: %w", tenantID, err)
@@ -186,7 +194,39 @@ func (c *Client) GetCollectionFaceCount(ctx context.Context, tenantID string) (i
 	return *output.FaceCount, nil
 }
 
-// EnsureCollection creates a collection if it doesn't exist, or does nothing if it already exists
+// TagCollection sets the configured cost allocation tags on an existing collection.
+// It is a no-op when no tags are configured.
+func (c *Client) TagCollection(ctx context.Context, tenantID string) error {
+	tags := c.config.CollectionTags(tenantID)
+	if len(tags) == 0 {
+		return nil
+	}
+
+	output, err := c.rekognition.DescribeCollection(ctx, &rekognition.DescribeCollectionInput{
+		CollectionId: aws.String(c.config.CollectionName(tenantID)),
+	})
+	if err != nil {
+		return fmt.Errorf("tenant %s: describe collection: %w", tenantID, err)
+	}
+
+	_, err = c.rekognition.TagResource(ctx, &rekognition.TagResourceInput{
+		ResourceArn: output.CollectionARN,
+		Tags:        tags,
+	})
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == errCodeAccessDenied {
+			return fmt.Errorf("tenant %s: tag collection: %w", tenantID, ErrProviderAccessDenied)
+		}
+		return fmt.Errorf("tenant %s: tag collection: %w", tenantID, err)
+	}
+
+	return nil
+}
+
+// EnsureCollection creates a collection if it doesn't exist, or does nothing if it already exists.
+// It is idempotent across instances: when two of them initialize the same new tenant at once,
+// both see no collection and the one whose create loses the race also returns nil.
 func (c *Client) EnsureCollection(ctx context.Context, tenantID string) error {
 	exists, err := c.CollectionExists(ctx, tenantID)
 	if err != nil {
@@ -194,11 +234,16 @@ func (c *Client) EnsureCollection(ctx context.Context, tenantID string) error {
 	}
 
 	if exists {
+		// Collections created before cost tags were configured get them now. Missing tags only
+		// affect billing reports, so a failure is logged rather than failing the tenant.
+		if err := c.TagCollection(ctx, tenantID); err != nil {
+			slog.Warn("failed to tag rekognition collection", "tenant_id", tenantID, "error", err)
+		}
 		return nil
 	}
 
 	if err := c.CreateCollection(ctx, tenantID); err != nil {
-		// Ignore if collection was created concurrently
+		// Created by another instance between the check and the create; the winner tagged it
 		if errors.Is(err, ErrCollectionAlreadyExists) {
 			return nil
 		}
@@ -229,24 +274,52 @@ func ParseNoFaceError(err error) error {
 	return err
 }
 
-// ParseIndexFacesError interprets errors from IndexFaces operation
+// noFaceReasons maps IndexFaces rejection reasons to user-facing reasons
+var noFaceReasons = map[types.Reason]domain.NoFaceReason{
+	types.ReasonExtremePose:      domain.NoFaceReasonPose,
+	types.ReasonLowBrightness:    domain.NoFaceReasonTooDark,
+	types.ReasonLowSharpness:     domain.NoFaceReasonBlurry,
+	types.ReasonLowConfidence:    domain.NoFaceReasonLowConfidence,
+	types.ReasonSmallBoundingBox: domain.NoFaceReasonTooSmall,
+	types.ReasonLowFaceQuality:   domain.NoFaceReasonLowQuality,
+}
+
+// ParseIndexFacesError interprets errors from IndexFace operation when no face was indexed.
+// Quality rejections are returned as *provider.NoFaceError carrying every mapped reason, and
+// faces over MaxFaces as *provider.MultipleFacesError counting every detected face.
 func ParseIndexFacesError(unindexedFaces []types.UnindexedFace) error {
 	if len(unindexedFaces) == 0 {
 		return nil
 	}
 
-	// Check the first unindexed face for the reason
+	// Check the first unindexed face for the reasons
 	face := unindexedFaces[0]
-	if len(face.Reasons) > 0 {
-		switch face.Reasons[0] {
-		case types.ReasonExceedsMaxFaces:
-			return ErrMultipleFaces
-		case types.ReasonExtremePose, types.ReasonLowBrightness,
-			types.ReasonLowSharpness, types.ReasonLowConfidence,
-			types.ReasonSmallBoundingBox, types.ReasonLowFaceQuality:
-			return fmt.Errorf("%w: %s", ErrNoFaceDetected, face.Reasons[0])
+	if len(face.Reasons) > 0 && face.Reasons[0] == types.ReasonExceedsMaxFaces {
+		// Nothing was indexed, so every detected face is among the unindexed ones
+		count := len(unindexedFaces)
+		return &provider.MultipleFacesError{
+			Count: count,
+			Err:   fmt.Errorf("%w: %d faces", ErrMultipleFaces, count),
 		}
 	}
 
-	return ErrNoFaceDetected
+	var raw []string
+	var reasons []domain.NoFaceReason
+	for _, r := range face.Reasons {
+		reason, ok := noFaceReasons[r]
+		if !ok || slices.Contains(reasons, reason) {
+			continue
+		}
+		raw = append(raw, string(r))
+		reasons = append(reasons, reason)
+	}
+
+	if len(reasons) == 0 {
+		return ErrNoFaceDetected
+	}
+
+	return &provider.NoFaceError{
+		Reasons: reasons,
+		Err:     fmt.Errorf("%w: %s", ErrNoFaceDetected, strings.Join(raw, ", ")),
+	}
 }
@@ -1,6 +1,19 @@
 package rekognition
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Placeholders expanded in CostTags values
+const (
+	TagPlaceholderTenantID   = "{tenant_id}"
+	TagPlaceholderCollection = "{collection}"
+)
+
+// MaxCostTags is the most tags AWS accepts on a resource
+const MaxCostTags = 50
 
 // Config holds configuration for AWS Rekognition provider
 type Config struct {
@@ -10,6 +23,20 @@ type Config struct {
 	// CollectionPrefix is the prefix used to generate collection names
 	// Collections will be named as: {CollectionPrefix}{tenant_id}
 	CollectionPrefix string
+
+	// CostTags are set on each tenant's collection so AWS cost allocation can split the bill per
+	// tenant. Values may contain {tenant_id} and {collection}. Collections are the only Rekognition
+	// resource that takes tags: the image APIs used here (DetectFaces, IndexFaces, SearchFacesByImage,
+	// CompareFaces) accept neither tags nor a ClientRequestToken.
+	CostTags map[string]string
+
+	// MaxTPS and MaxConcurrency keep calls under the AWS account quotas, shared by every tenant
+	// of the region (0 disables each limit). The first client created for a region sets them.
+	MaxTPS         float64
+	MaxConcurrency int
+
+	// MaxWait is how long a call queues for its turn before failing with ErrProviderThrottled
+	MaxWait time.Duration
 }
 
 // DefaultConfig returns a Config with default values
@@ -17,6 +44,7 @@ func DefaultConfig() Config {
 	return Config{
 		Region:           "us-east-1",
 		CollectionPrefix: "rekko-",
+		MaxWait:          2 * time.Second,
 	}
 }
 
@@ -26,3 +54,20 @@ func DefaultConfig() Config {
 func (c Config) CollectionName(tenantID string) string {
 	return fmt.Sprintf("%s%s", c.CollectionPrefix, tenantID)
 }
+
+// CollectionTags returns the cost allocation tags of a tenant's collection, or nil when none are configured
+func (c Config) CollectionTags(tenantID string) map[string]string {
+	if len(c.CostTags) == 0 {
+		return nil
+	}
+
+	replacer := strings.NewReplacer(
+		TagPlaceholderTenantID, tenantID,
+		TagPlaceholderCollection, c.CollectionName(tenantID),
+	)
+	tags := make(map[string]string, len(c.CostTags))
+	for key, value := range c.CostTags {
+		tags[key] = replacer.Replace(value)
+	}
+	return tags
+}
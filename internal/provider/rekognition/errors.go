@@ -1,6 +1,10 @@
 package rekognition
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
 
 var (
 	// ErrCollectionNotFound indicates that the specified collection does not exist
@@ -9,8 +13,11 @@ var (
 	// ErrCollectionAlreadyExists indicates that a collection with the same name already exists
 	ErrCollectionAlreadyExists = errors.New("rekognition collection already exists")
 
-	// ErrInvalidCredentials indicates that AWS credentials are invalid or missing
-	ErrInvalidCredentials = errors.New("invalid or missing AWS credentials")
+	// ErrProviderAccessDenied indicates that the IAM policy of the credentials denies the operation
+	ErrProviderAccessDenied = domain.ErrProviderAccessDenied
+
+	// ErrProviderThrottled indicates that the call waited too long for the account limits to allow it
+	ErrProviderThrottled = domain.ErrProviderThrottled
 
 	// ErrNoFaceDetected indicates that no face was found in the provided image
 	ErrNoFaceDetected = errors.New("no face detected in image")
@@ -0,0 +1,241 @@
+package rekognition
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/rekognition"
+)
+
+// Limiter paces Rekognition calls under the AWS account quotas. AWS enforces them per account
+// and region, across every tenant, so a single busy tenant could otherwise get the whole
+// deployment throttled. Calls wait their turn for up to maxWait and then fail with
+// ErrProviderThrottled instead of being sent to be rejected by AWS.
+type Limiter struct {
+	slots    chan struct{} // nil when concurrency is unlimited
+	interval time.Duration // gap between call starts, 0 when TPS is unlimited
+	maxWait  time.Duration
+
+	mu   sync.Mutex
+	next time.Time // earliest start of the next call
+}
+
+// NewLimiter returns a Limiter allowing maxTPS calls per second with at most maxConcurrency in
+// flight; zero disables either limit. It returns nil when both are disabled.
+func NewLimiter(maxTPS float64, maxConcurrency int, maxWait time.Duration) *Limiter {
+	if maxTPS <= 0 && maxConcurrency <= 0 {
+		return nil
+	}
+
+	l := &Limiter{maxWait: maxWait}
+	if maxTPS > 0 {
+		l.interval = time.Duration(float64(time.Second) / maxTPS)
+	}
+	if maxConcurrency > 0 {
+		l.slots = make(chan struct{}, maxConcurrency)
+	}
+	return l
+}
+
+// Acquire waits for a concurrency slot and then for the call's turn under the TPS limit.
+// The returned func releases the slot once the call is done. ErrProviderThrottled is returned
+// when the wait would exceed maxWait or outlast ctx.
+func (l *Limiter) Acquire(ctx context.Context) (func(), error) {
+	deadline := time.Now().Add(l.maxWait)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	release := func() {}
+	if l.slots != nil {
+		if err := l.waitSlot(ctx, deadline); err != nil {
+			return nil, err
+		}
+		release = func() { <-l.slots }
+	}
+
+	if l.interval > 0 {
+		if err := l.waitTurn(ctx, deadline); err != nil {
+			release()
+			return nil, err
+		}
+	}
+
+	return release, nil
+}
+
+func (l *Limiter) waitSlot(ctx context.Context, deadline time.Time) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return ErrProviderThrottled
+	case <-ctx.Done():
+		return ErrProviderThrottled
+	}
+}
+
+// waitTurn reserves the next start time and sleeps until it. A start past the deadline is not
+// reserved, so callers that give up do not delay the ones behind them.
+func (l *Limiter) waitTurn(ctx context.Context, deadline time.Time) error {
+	now := time.Now()
+
+	l.mu.Lock()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	if start.After(deadline) {
+		l.mu.Unlock()
+		return ErrProviderThrottled
+	}
+	l.next = start.Add(l.interval)
+	l.mu.Unlock()
+
+	wait := start.Sub(now)
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ErrProviderThrottled
+	}
+}
+
+var (
+	accountLimitersMu sync.Mutex
+	accountLimiters   = make(map[string]*Limiter)
+)
+
+// accountLimiter returns the Limiter shared by every client of the region. Clients are created
+// per tenant but all use the same AWS account, so the first config seen for a region sets its limits.
+func accountLimiter(cfg Config) *Limiter {
+	accountLimitersMu.Lock()
+	defer accountLimitersMu.Unlock()
+
+	if l, ok := accountLimiters[cfg.Region]; ok {
+		return l
+	}
+	l := NewLimiter(cfg.MaxTPS, cfg.MaxConcurrency, cfg.MaxWait)
+	accountLimiters[cfg.Region] = l
+	return l
+}
+
+// limitedAPI paces every Rekognition call through a Limiter
+type limitedAPI struct {
+	api     RekognitionAPI
+	limiter *Limiter
+}
+
+func limitAPI(api RekognitionAPI, limiter *Limiter) RekognitionAPI {
+	if limiter == nil {
+		return api
+	}
+	return &limitedAPI{api: api, limiter: limiter}
+}
+
+func (a *limitedAPI) DetectFaces(ctx context.Context, params *rekognition.DetectFacesInput, optFns ...func(*rekognition.Options)) (*rekognition.DetectFacesOutput, error) {
+	release, err := a.limiter.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return a.api.DetectFaces(ctx, params, optFns...)
+}
+
+func (a *limitedAPI) IndexFaces(ctx context.Context, params *rekognition.IndexFacesInput, optFns ...func(*rekognition.Options)) (*rekognition.IndexFacesOutput, error) {
+	release, err := a.limiter.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return a.api.IndexFaces(ctx, params, optFns...)
+}
+
+func (a *limitedAPI) DeleteFaces(ctx context.Context, params *rekognition.DeleteFacesInput, optFns ...func(*rekognition.Options)) (*rekognition.DeleteFacesOutput, error) {
+	release, err := a.limiter.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return a.api.DeleteFaces(ctx, params, optFns...)
+}
+
+func (a *limitedAPI) CompareFaces(ctx context.Context, params *rekognition.CompareFacesInput, optFns ...func(*rekognition.Options)) (*rekognition.CompareFacesOutput, error) {
+	release, err := a.limiter.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return a.api.CompareFaces(ctx, params, optFns...)
+}
+
+func (a *limitedAPI) SearchFacesByImage(ctx context.Context, params *rekognition.SearchFacesByImageInput, optFns ...func(*rekognition.Options)) (*rekognition.SearchFacesByImageOutput, error) {
+	release, err := a.limiter.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return a.api.SearchFacesByImage(ctx, params, optFns...)
+}
+
+func (a *limitedAPI) CreateCollection(ctx context.Context, params *rekognition.CreateCollectionInput, optFns ...func(*rekognition.Options)) (*rekognition.CreateCollectionOutput, error) {
+	release, err := a.limiter.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return a.api.CreateCollection(ctx, params, optFns...)
+}
+
+func (a *limitedAPI) DeleteCollection(ctx context.Context, params *rekognition.DeleteCollectionInput, optFns ...func(*rekognition.Options)) (*rekognition.DeleteCollectionOutput, error) {
+	release, err := a.limiter.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return a.api.DeleteCollection(ctx, params, optFns...)
+}
+
+func (a *limitedAPI) DescribeCollection(ctx context.Context, params *rekognition.DescribeCollectionInput, optFns ...func(*rekognition.Options)) (*rekognition.DescribeCollectionOutput, error) {
+	release, err := a.limiter.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return a.api.DescribeCollection(ctx, params, optFns...)
+}
+
+func (a *limitedAPI) ListCollections(ctx context.Context, params *rekognition.ListCollectionsInput, optFns ...func(*rekognition.Options)) (*rekognition.ListCollectionsOutput, error) {
+	release, err := a.limiter.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return a.api.ListCollections(ctx, params, optFns...)
+}
+
+func (a *limitedAPI) TagResource(ctx context.Context, params *rekognition.TagResourceInput, optFns ...func(*rekognition.Options)) (*rekognition.TagResourceOutput, error) {
+	release, err := a.limiter.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return a.api.TagResource(ctx, params, optFns...)
+}
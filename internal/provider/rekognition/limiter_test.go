@@ -0,0 +1,169 @@
+package rekognition
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/rekognition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// burst fires n DetectFaces calls at once and returns their errors
+func burst(t *testing.T, api RekognitionAPI, n int) []error {
+	t.Helper()
+
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = api.DetectFaces(context.Background(), &rekognition.DetectFacesInput{})
+		}(i)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+func TestLimiter_PacesBurst(t *testing.T) {
+	var mu sync.Mutex
+	var starts []time.Time
+	mock := &mockRekognitionAPI{
+		detectFacesFunc: func(ctx context.Context, params *rekognition.DetectFacesInput, optFns ...func(*rekognition.Options)) (*rekognition.DetectFacesOutput, error) {
+			mu.Lock()
+			starts = append(starts, time.Now())
+			mu.Unlock()
+			return &rekognition.DetectFacesOutput{}, nil
+		},
+	}
+	api := limitAPI(mock, NewLimiter(20, 0, time.Second))
+
+	errs := burst(t, api, 5)
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+	require.Len(t, starts, 5)
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+	for i := 1; i < len(starts); i++ {
+		assert.GreaterOrEqual(t, starts[i].Sub(starts[i-1]), 40*time.Millisecond, "call %d started too early", i)
+	}
+	assert.GreaterOrEqual(t, starts[4].Sub(starts[0]), 190*time.Millisecond)
+}
+
+func TestLimiter_FailsFastPastMaxWait(t *testing.T) {
+	var calls atomic.Int32
+	mock := &mockRekognitionAPI{
+		detectFacesFunc: func(ctx context.Context, params *rekognition.DetectFacesInput, optFns ...func(*rekognition.Options)) (*rekognition.DetectFacesOutput, error) {
+			calls.Add(1)
+			return &rekognition.DetectFacesOutput{}, nil
+		},
+	}
+	// One call every 100ms; only the calls whose turn comes within 150ms may wait for it
+	api := limitAPI(mock, NewLimiter(10, 0, 150*time.Millisecond))
+
+	start := time.Now()
+	errs := burst(t, api, 5)
+
+	throttled := 0
+	for _, err := range errs {
+		if err != nil {
+			assert.ErrorIs(t, err, ErrProviderThrottled)
+			throttled++
+		}
+	}
+	assert.Equal(t, 3, throttled)
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Less(t, time.Since(start), 500*time.Millisecond, "throttled calls must not wait for their turn")
+}
+
+func TestLimiter_BoundsConcurrency(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	unblock := make(chan struct{})
+	mock := &mockRekognitionAPI{
+		detectFacesFunc: func(ctx context.Context, params *rekognition.DetectFacesInput, optFns ...func(*rekognition.Options)) (*rekognition.DetectFacesOutput, error) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			<-unblock
+			return &rekognition.DetectFacesOutput{}, nil
+		},
+	}
+	api := limitAPI(mock, NewLimiter(0, 2, time.Second))
+
+	done := make(chan []error)
+	go func() {
+		errs := burst(t, api, 6)
+		done <- errs
+	}()
+
+	require.Eventually(t, func() bool { return inFlight.Load() == 2 }, time.Second, 5*time.Millisecond)
+	close(unblock)
+	errs := <-done
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), peak.Load())
+}
+
+func TestLimiter_SlotWaitTimesOut(t *testing.T) {
+	limiter := NewLimiter(0, 1, 50*time.Millisecond)
+
+	release, err := limiter.Acquire(context.Background())
+	require.NoError(t, err)
+
+	_, err = limiter.Acquire(context.Background())
+	assert.ErrorIs(t, err, ErrProviderThrottled)
+
+	release()
+	release, err = limiter.Acquire(context.Background())
+	require.NoError(t, err)
+	release()
+}
+
+func TestLimiter_RespectsContextDeadline(t *testing.T) {
+	limiter := NewLimiter(1, 0, time.Minute)
+
+	release, err := limiter.Acquire(context.Background())
+	require.NoError(t, err)
+	release()
+
+	// The next turn is a second away, past the request's deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = limiter.Acquire(ctx)
+	assert.ErrorIs(t, err, ErrProviderThrottled)
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestLimiter_DisabledPassesThrough(t *testing.T) {
+	assert.Nil(t, NewLimiter(0, 0, time.Second))
+
+	mock := &mockRekognitionAPI{}
+	assert.Same(t, RekognitionAPI(mock), limitAPI(mock, nil))
+}
+
+func TestAccountLimiter_SharedPerRegion(t *testing.T) {
+	cfg := Config{Region: "limiter-test-1", MaxTPS: 5, MaxWait: time.Second}
+
+	first := accountLimiter(cfg)
+	require.NotNil(t, first)
+
+	// Clients created for other tenants of the region share the limiter
+	assert.Same(t, first, accountLimiter(cfg))
+	assert.NotSame(t, first, accountLimiter(Config{Region: "limiter-test-2", MaxTPS: 5}))
+}
@@ -17,6 +17,7 @@ type mockRekognitionAPI struct {
 	deleteCollectionFunc   func(ctx context.Context, params *rekognition.DeleteCollectionInput, optFns ...func(*rekognition.Options)) (*rekognition.DeleteCollectionOutput, error)
 	describeCollectionFunc func(ctx context.Context, params *rekognition.DescribeCollectionInput, optFns ...func(*rekognition.Options)) (*rekognition.DescribeCollectionOutput, error)
 	listCollectionsFunc    func(ctx context.Context, params *rekognition.ListCollectionsInput, optFns ...func(*rekognition.Options)) (*rekognition.ListCollectionsOutput, error)
+	tagResourceFunc        func(ctx context.Context, params *rekognition.TagResourceInput, optFns ...func(*rekognition.Options)) (*rekognition.TagResourceOutput, error)
 }
 
 func (m *mockRekognitionAPI) DetectFaces(ctx context.Context, params *rekognition.DetectFacesInput, optFns ...func(*rekognition.Options)) (*rekognition.DetectFacesOutput, error) {
@@ -81,3 +82,10 @@ func (m *mockRekognitionAPI) ListCollections(ctx context.Context, params *rekogn
 	}
 	return &rekognition.ListCollectionsOutput{}, nil
 }
+
+func (m *mockRekognitionAPI) TagResource(ctx context.Context, params *rekognition.TagResourceInput, optFns ...func(*rekognition.Options)) (*rekognition.TagResourceOutput, error) {
+	if m.tagResourceFunc != nil {
+		return m.tagResourceFunc(ctx, params, optFns...)
+	}
+	return &rekognition.TagResourceOutput{}, nil
+}
@@ -86,6 +86,7 @@ func (p *Provider) logAudit(ctx context.Context, eventType audit.EventType, succ
 
 	if err != nil {
 		event.Error = err.Error()
+		event.ErrorCode = provider.ErrorCode(err)
 	}
 
 	_ = p.auditLogger.Log(ctx, event)
@@ -145,6 +146,9 @@ func (p *Provider) DetectFaces(ctx context.Context, image []byte) ([]provider.De
 		})
 	}
 
+	// Largest face first so callers can pick the primary subject
+	provider.SortByArea(faces)
+
 	p.logAudit(ctx, audit.EventFaceDetected, true, nil, map[string]string{
 		"faces_count": strconv.Itoa(len(faces)),
 		"image_size":  strconv.Itoa(len(image)),
@@ -155,7 +159,7 @@ func (p *Provider) DetectFaces(ctx context.Context, image []byte) ([]provider.De
 
 // IndexFace indexes a face in the tenant's Rekognition collection
 // Returns the Rekognition-generated faceID and nil for embedding (Rekognition does not expose embeddings)
-// Only indexes the first face found in the image; returns error if no face or multiple faces
+// Only indexes the largest face found in the image; returns error if no face or multiple faces
 func (p *Provider) IndexFace(ctx context.Context, image []byte) (string, []float64, error) {
 	if err := validateImage(image); err != nil {
 		p.logAudit(ctx, audit.EventFaceRegistered, false, err, map[string]string{
@@ -171,7 +175,7 @@ func (p *Provider) IndexFace(ctx context.Context, image []byte) (string, []float
 		Image: &types.Image{
 			Bytes: image,
 		},
-		MaxFaces:      aws.Int32(1), // Only index the first face
+		MaxFaces:      aws.Int32(1), // Only index the largest face
 		QualityFilter: types.QualityFilterAuto,
 		DetectionAttributes: []types.Attribute{
 			types.AttributeDefault, // Minimal attributes for indexing
@@ -235,6 +239,9 @@ func (p *Provider) CompareFaces(ctx context.Context, embedding1, embedding2 []fl
 // CompareFaceImages compares two face images using AWS Rekognition CompareFaces API
 // Returns similarity score between 0.0 (completely different) and 1.0 (identical)
 // This is the Rekognition-specific method that should be used instead of CompareFaces
+//
+// The real similarity is returned even below similarityThreshold so it can be used for
+// calibration; callers decide whether it is a match. The threshold only marks the audit entry.
 func (p *Provider) CompareFaceImages(ctx context.Context, sourceImage, targetImage []byte, similarityThreshold float64) (float64, error) {
 	if err := validateImage(sourceImage); err != nil {
 		p.logAudit(ctx, audit.EventFaceCompared, false, err, map[string]string{
@@ -258,7 +265,9 @@ func (p *Provider) CompareFaceImages(ctx context.Context, sourceImage, targetIma
 		TargetImage: &types.Image{
 			Bytes: targetImage,
 		},
-		SimilarityThreshold: aws.Float32(float32(similarityThreshold * 100)), // Convert 0-1 to 0-100
+		// Rekognition drops pairs below the threshold server-side, which would hide the
+		// similarity of near misses, so ask for every pair and apply the threshold here
+		SimilarityThreshold: aws.Float32(0),
 	}
 
 	output, err := p.client.rekognition.CompareFaces(ctx, input)
@@ -278,7 +287,7 @@ func (p *Provider) CompareFaceImages(ctx context.Context, sourceImage, targetIma
 		return 0, fmt.Errorf("tenant %s: compare faces: %w", p.tenantID, err)
 	}
 
-	// If no matches found, return 0 similarity
+	// No face in the target image to compare against
 	if len(output.FaceMatches) == 0 {
 		p.logAudit(ctx, audit.EventFaceCompared, true, nil, map[string]string{
 			"similarity":        "0",
@@ -290,14 +299,19 @@ func (p *Provider) CompareFaceImages(ctx context.Context, sourceImage, targetIma
 	}
 
 	// Return the similarity of the best match (normalized to 0-1 range)
-	bestMatch := output.FaceMatches[0]
-	similarity := float64(*bestMatch.Similarity) / 100.0
+	var best float32
+	for _, match := range output.FaceMatches {
+		if match.Similarity != nil && *match.Similarity > best {
+			best = *match.Similarity
+		}
+	}
+	similarity := float64(best) / 100.0
 
 	p.logAudit(ctx, audit.EventFaceCompared, true, nil, map[string]string{
 		"similarity":        fmt.Sprintf("%.4f", similarity),
 		"source_image_size": strconv.Itoa(len(sourceImage)),
 		"target_image_size": strconv.Itoa(len(targetImage)),
-		"matched":           "true",
+		"matched":           strconv.FormatBool(similarity >= similarityThreshold),
 	})
 
 	return similarity, nil
@@ -326,7 +340,7 @@ func (p *Provider) DeleteFace(ctx context.Context, faceID string) error {
 				})
 				return deleteErr
 			case errCodeAccessDenied:
-				deleteErr := fmt.Errorf("tenant %s: %w", p.tenantID, ErrInvalidCredentials)
+				deleteErr := fmt.Errorf("tenant %s: %w", p.tenantID, ErrProviderAccessDenied)
 				p.logAudit(ctx, audit.EventFaceDeleted, false, deleteErr, map[string]string{
 					"face_id": faceID,
 					"reason":  "access_denied",
@@ -418,7 +432,7 @@ func (p *Provider) SearchFacesByImage(ctx context.Context, image []byte, maxFace
 				})
 				return nil, searchErr
 			case errCodeAccessDenied:
-				searchErr := fmt.Errorf("tenant %s: %w", p.tenantID, ErrInvalidCredentials)
+				searchErr := fmt.Errorf("tenant %s: %w", p.tenantID, ErrProviderAccessDenied)
 				p.logAudit(ctx, audit.EventFaceSearched, false, searchErr, map[string]string{
 					"image_size": strconv.Itoa(len(image)),
 					"max_faces":  strconv.Itoa(maxFaces),
@@ -552,7 +566,7 @@ func (p *Provider) CheckLiveness(ctx context.Context, image []byte, threshold fl
 		return result, nil
 	}
 
-	face := output.FaceDetails[0]
+	face := largestFaceDetail(output.FaceDetails)
 
 	eyesOpen := false
 	if face.EyesOpen != nil && face.EyesOpen.Value {
@@ -624,7 +638,7 @@ func (p *Provider) AnalyzeFace(ctx context.Context, image []byte) (*provider.Fac
 		return nil, fmt.Errorf("tenant %s: %w", p.tenantID, ErrNoFaceDetected)
 	}
 
-	face := output.FaceDetails[0]
+	face := largestFaceDetail(output.FaceDetails)
 
 	eyesOpen := false
 	if face.EyesOpen != nil && face.EyesOpen.Value {
@@ -668,3 +682,24 @@ func (p *Provider) AnalyzeFace(ctx context.Context, image []byte) (*provider.Fac
 		FaceCount: len(output.FaceDetails),
 	}, nil
 }
+
+// largestFaceDetail returns the face with the largest bounding box
+func largestFaceDetail(details []types.FaceDetail) types.FaceDetail {
+	largest := details[0]
+	largestArea := boundingBoxArea(largest.BoundingBox)
+	for _, d := range details[1:] {
+		if area := boundingBoxArea(d.BoundingBox); area > largestArea {
+			largest = d
+			largestArea = area
+		}
+	}
+	return largest
+}
+
+// boundingBoxArea returns the area of a Rekognition bounding box (ratio of image size)
+func boundingBoxArea(box *types.BoundingBox) float32 {
+	if box == nil || box.Width == nil || box.Height == nil {
+		return 0
+	}
+	return *box.Width * *box.Height
+}
@@ -2,15 +2,21 @@ package rekognition
 
 import (
 	"context"
+	"errors"
 	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/rekognition"
 	"github.com/aws/aws-sdk-go-v2/service/rekognition/types"
+	"github.com/aws/smithy-go"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
 	"github.com/saturnino-fabrica-de-software/rekko/internal/provider"
 )
 
@@ -25,6 +31,7 @@ func TestDefaultConfig(t *testing.T) {
 
 	assert.Equal(t, "us-east-1", cfg.Region)
 	assert.Equal(t, "rekko-", cfg.CollectionPrefix)
+	assert.Equal(t, 2*time.Second, cfg.MaxWait)
 }
 
 // TestCollectionName verifies collection name generation
@@ -87,9 +94,9 @@ func TestErrors(t *testing.T) {
 			msg:  "already exists",
 		},
 		{
-			name: "InvalidCredentials",
-			err:  ErrInvalidCredentials,
-			msg:  "invalid",
+			name: "ProviderAccessDenied",
+			err:  ErrProviderAccessDenied,
+			msg:  "denied access",
 		},
 		{
 			name: "NoFaceDetected",
@@ -122,6 +129,8 @@ func TestParseIndexFacesError(t *testing.T) {
 		unindexedFaces  []types.UnindexedFace
 		wantErr         error
 		wantErrContains string
+		wantReasons     []domain.NoFaceReason
+		wantFaceCount   int
 	}{
 		{
 			name:            "no unindexed faces",
@@ -137,7 +146,19 @@ func TestParseIndexFacesError(t *testing.T) {
 				},
 			},
 			wantErr:         ErrMultipleFaces,
-			wantErrContains: "",
+			wantErrContains: "1 faces",
+			wantFaceCount:   1,
+		},
+		{
+			name: "exceeds max faces counts every detected face",
+			unindexedFaces: []types.UnindexedFace{
+				{Reasons: []types.Reason{types.ReasonExceedsMaxFaces}},
+				{Reasons: []types.Reason{types.ReasonExceedsMaxFaces}},
+				{Reasons: []types.Reason{types.ReasonExceedsMaxFaces, types.ReasonSmallBoundingBox}},
+			},
+			wantErr:         ErrMultipleFaces,
+			wantErrContains: "3 faces",
+			wantFaceCount:   3,
 		},
 		{
 			name: "extreme pose",
@@ -148,6 +169,7 @@ func TestParseIndexFacesError(t *testing.T) {
 			},
 			wantErr:         ErrNoFaceDetected,
 			wantErrContains: "EXTREME_POSE",
+			wantReasons:     []domain.NoFaceReason{domain.NoFaceReasonPose},
 		},
 		{
 			name: "low brightness",
@@ -158,6 +180,7 @@ func TestParseIndexFacesError(t *testing.T) {
 			},
 			wantErr:         ErrNoFaceDetected,
 			wantErrContains: "LOW_BRIGHTNESS",
+			wantReasons:     []domain.NoFaceReason{domain.NoFaceReasonTooDark},
 		},
 		{
 			name: "low sharpness",
@@ -168,6 +191,7 @@ func TestParseIndexFacesError(t *testing.T) {
 			},
 			wantErr:         ErrNoFaceDetected,
 			wantErrContains: "LOW_SHARPNESS",
+			wantReasons:     []domain.NoFaceReason{domain.NoFaceReasonBlurry},
 		},
 		{
 			name: "low confidence",
@@ -178,6 +202,7 @@ func TestParseIndexFacesError(t *testing.T) {
 			},
 			wantErr:         ErrNoFaceDetected,
 			wantErrContains: "LOW_CONFIDENCE",
+			wantReasons:     []domain.NoFaceReason{domain.NoFaceReasonLowConfidence},
 		},
 		{
 			name: "small bounding box",
@@ -188,6 +213,7 @@ func TestParseIndexFacesError(t *testing.T) {
 			},
 			wantErr:         ErrNoFaceDetected,
 			wantErrContains: "SMALL_BOUNDING_BOX",
+			wantReasons:     []domain.NoFaceReason{domain.NoFaceReasonTooSmall},
 		},
 		{
 			name: "low face quality",
@@ -198,6 +224,18 @@ func TestParseIndexFacesError(t *testing.T) {
 			},
 			wantErr:         ErrNoFaceDetected,
 			wantErrContains: "LOW_FACE_QUALITY",
+			wantReasons:     []domain.NoFaceReason{domain.NoFaceReasonLowQuality},
+		},
+		{
+			name: "multiple reasons are all reported once",
+			unindexedFaces: []types.UnindexedFace{
+				{
+					Reasons: []types.Reason{types.ReasonLowBrightness, types.ReasonExtremePose, types.ReasonLowBrightness},
+				},
+			},
+			wantErr:         ErrNoFaceDetected,
+			wantErrContains: "LOW_BRIGHTNESS, EXTREME_POSE",
+			wantReasons:     []domain.NoFaceReason{domain.NoFaceReasonTooDark, domain.NoFaceReasonPose},
 		},
 		{
 			name: "no reasons",
@@ -226,6 +264,22 @@ func TestParseIndexFacesError(t *testing.T) {
 			if tt.wantErrContains != "" {
 				assert.Contains(t, err.Error(), tt.wantErrContains)
 			}
+
+			var multipleFaces *provider.MultipleFacesError
+			if tt.wantFaceCount > 0 {
+				require.ErrorAs(t, err, &multipleFaces)
+				assert.Equal(t, tt.wantFaceCount, multipleFaces.Count)
+			} else {
+				assert.False(t, errors.As(err, &multipleFaces), "error should not carry a face count")
+			}
+
+			var noFace *provider.NoFaceError
+			if tt.wantReasons == nil {
+				assert.False(t, errors.As(err, &noFace), "error should not carry reasons")
+				return
+			}
+			require.ErrorAs(t, err, &noFace)
+			assert.Equal(t, tt.wantReasons, noFace.Reasons)
 		})
 	}
 }
@@ -772,12 +826,15 @@ func TestIndexFace_MultipleFaces(t *testing.T) {
 	}
 
 	client := &Client{rekognition: mock, config: DefaultConfig()}
-	provider := &Provider{client: client, tenantID: uuid.New()}
+	p := &Provider{client: client, tenantID: uuid.New()}
 
-	faceID, embedding, err := provider.IndexFace(context.Background(), fakeImageData())
+	faceID, embedding, err := p.IndexFace(context.Background(), fakeImageData())
 
 	require.Error(t, err)
 	assert.ErrorIs(t, err, ErrMultipleFaces)
+	var multipleFaces *provider.MultipleFacesError
+	require.ErrorAs(t, err, &multipleFaces)
+	assert.Equal(t, 1, multipleFaces.Count)
 	assert.Empty(t, faceID)
 	assert.Nil(t, embedding)
 }
@@ -905,6 +962,23 @@ func TestDeleteFace_Error(t *testing.T) {
 	assert.Contains(t, err.Error(), tenantID.String())
 }
 
+// TestDeleteFace_AccessDenied verifies that IAM denials are reported as ErrProviderAccessDenied
+func TestDeleteFace_AccessDenied(t *testing.T) {
+	mock := &mockRekognitionAPI{
+		deleteFacesFunc: func(ctx context.Context, params *rekognition.DeleteFacesInput, optFns ...func(*rekognition.Options)) (*rekognition.DeleteFacesOutput, error) {
+			return nil, &smithy.GenericAPIError{Code: errCodeAccessDenied, Message: "not authorized"}
+		},
+	}
+
+	client := &Client{rekognition: mock, config: DefaultConfig()}
+	provider := &Provider{client: client, tenantID: uuid.New()}
+
+	err := provider.DeleteFace(context.Background(), "some-face")
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrProviderAccessDenied)
+}
+
 // TestSearchFacesByImage_Success verifies successful face search
 func TestSearchFacesByImage_Success(t *testing.T) {
 	mock := &mockRekognitionAPI{
@@ -1067,6 +1141,32 @@ func TestCompareFaceImages_NoMatch(t *testing.T) {
 	assert.Equal(t, 0.0, similarity)
 }
 
+// TestCompareFaceImages_BelowThreshold verifies the real similarity of a near miss is returned
+func TestCompareFaceImages_BelowThreshold(t *testing.T) {
+	var requested *float32
+	mock := &mockRekognitionAPI{
+		compareFacesFunc: func(ctx context.Context, params *rekognition.CompareFacesInput, optFns ...func(*rekognition.Options)) (*rekognition.CompareFacesOutput, error) {
+			requested = params.SimilarityThreshold
+			return &rekognition.CompareFacesOutput{
+				FaceMatches: []types.CompareFacesMatch{
+					{Similarity: ptr(float32(41.0))},
+					{Similarity: ptr(float32(63.5))},
+				},
+			}, nil
+		},
+	}
+
+	client := &Client{rekognition: mock, config: DefaultConfig()}
+	provider := &Provider{client: client, tenantID: uuid.New()}
+
+	similarity, err := provider.CompareFaceImages(context.Background(), fakeImageData(), fakeImageData(), 0.9)
+
+	require.NoError(t, err)
+	require.NotNil(t, requested)
+	assert.Equal(t, float32(0), *requested, "threshold must not filter pairs server-side")
+	assert.InDelta(t, 0.635, similarity, 0.001)
+}
+
 // TestCompareFaceImages_Error verifies error handling during comparison
 func TestCompareFaceImages_Error(t *testing.T) {
 	expectedErr := assert.AnError
@@ -1215,3 +1315,144 @@ func TestValidateImage_TooLarge(t *testing.T) {
 	assert.ErrorIs(t, err, ErrInvalidImage)
 	assert.Contains(t, err.Error(), "too large")
 }
+
+// TestCollectionTags verifies placeholder expansion of the cost allocation tags
+func TestCollectionTags(t *testing.T) {
+	cfg := DefaultConfig()
+	assert.Nil(t, cfg.CollectionTags("tenant-123"), "no tags configured")
+
+	cfg.CostTags = map[string]string{
+		"rekko-tenant":     "{tenant_id}",
+		"rekko-collection": "{collection}",
+		"team":             "biometrics",
+	}
+	assert.Equal(t, map[string]string{
+		"rekko-tenant":     "tenant-123",
+		"rekko-collection": "rekko-tenant-123",
+		"team":             "biometrics",
+	}, cfg.CollectionTags("tenant-123"))
+}
+
+// TestCreateCollection_CostTags verifies the tags are set on the CreateCollection input
+func TestCreateCollection_CostTags(t *testing.T) {
+	var got *rekognition.CreateCollectionInput
+	mock := &mockRekognitionAPI{
+		createCollectionFunc: func(ctx context.Context, params *rekognition.CreateCollectionInput, optFns ...func(*rekognition.Options)) (*rekognition.CreateCollectionOutput, error) {
+			got = params
+			return &rekognition.CreateCollectionOutput{}, nil
+		},
+	}
+
+	cfg := DefaultConfig()
+	cfg.CostTags = map[string]string{"rekko-tenant": "{tenant_id}"}
+	client := &Client{rekognition: mock, config: cfg}
+
+	require.NoError(t, client.CreateCollection(context.Background(), "tenant-123"))
+	require.NotNil(t, got)
+	assert.Equal(t, "rekko-tenant-123", *got.CollectionId)
+	assert.Equal(t, map[string]string{"rekko-tenant": "tenant-123"}, got.Tags)
+}
+
+// TestEnsureCollection_TagsExistingCollection verifies collections created before tagging get tagged
+func TestEnsureCollection_TagsExistingCollection(t *testing.T) {
+	const arn = "arn:aws:rekognition:us-east-1:123456789012:collection/rekko-tenant-123"
+
+	t.Run("tags the existing collection by ARN", func(t *testing.T) {
+		var got *rekognition.TagResourceInput
+		mock := &mockRekognitionAPI{
+			describeCollectionFunc: func(ctx context.Context, params *rekognition.DescribeCollectionInput, optFns ...func(*rekognition.Options)) (*rekognition.DescribeCollectionOutput, error) {
+				return &rekognition.DescribeCollectionOutput{CollectionARN: ptr(arn)}, nil
+			},
+			createCollectionFunc: func(ctx context.Context, params *rekognition.CreateCollectionInput, optFns ...func(*rekognition.Options)) (*rekognition.CreateCollectionOutput, error) {
+				t.Fatal("existing collection must not be recreated")
+				return nil, nil
+			},
+			tagResourceFunc: func(ctx context.Context, params *rekognition.TagResourceInput, optFns ...func(*rekognition.Options)) (*rekognition.TagResourceOutput, error) {
+				got = params
+				return &rekognition.TagResourceOutput{}, nil
+			},
+		}
+
+		cfg := DefaultConfig()
+		cfg.CostTags = map[string]string{"rekko-tenant": "{tenant_id}", "team": "biometrics"}
+		client := &Client{rekognition: mock, config: cfg}
+
+		require.NoError(t, client.EnsureCollection(context.Background(), "tenant-123"))
+		require.NotNil(t, got)
+		assert.Equal(t, arn, *got.ResourceArn)
+		assert.Equal(t, map[string]string{"rekko-tenant": "tenant-123", "team": "biometrics"}, got.Tags)
+	})
+
+	t.Run("tagging failure does not fail the tenant", func(t *testing.T) {
+		mock := &mockRekognitionAPI{
+			describeCollectionFunc: func(ctx context.Context, params *rekognition.DescribeCollectionInput, optFns ...func(*rekognition.Options)) (*rekognition.DescribeCollectionOutput, error) {
+				return &rekognition.DescribeCollectionOutput{CollectionARN: ptr(arn)}, nil
+			},
+			tagResourceFunc: func(ctx context.Context, params *rekognition.TagResourceInput, optFns ...func(*rekognition.Options)) (*rekognition.TagResourceOutput, error) {
+				return nil, &smithy.GenericAPIError{Code: errCodeAccessDenied, Message: "not authorized to perform rekognition:TagResource"}
+			},
+		}
+
+		cfg := DefaultConfig()
+		cfg.CostTags = map[string]string{"rekko-tenant": "{tenant_id}"}
+		client := &Client{rekognition: mock, config: cfg}
+
+		assert.NoError(t, client.EnsureCollection(context.Background(), "tenant-123"))
+		assert.ErrorIs(t, client.TagCollection(context.Background(), "tenant-123"), ErrProviderAccessDenied)
+	})
+
+	t.Run("no tags configured skips tagging", func(t *testing.T) {
+		mock := &mockRekognitionAPI{
+			tagResourceFunc: func(ctx context.Context, params *rekognition.TagResourceInput, optFns ...func(*rekognition.Options)) (*rekognition.TagResourceOutput, error) {
+				t.Fatal("TagResource must not be called without tags")
+				return nil, nil
+			},
+		}
+		client := &Client{rekognition: mock, config: DefaultConfig()}
+
+		assert.NoError(t, client.EnsureCollection(context.Background(), "tenant-123"))
+	})
+}
+
+// TestEnsureCollection_ConcurrentCreate verifies two instances racing to create a new tenant's collection both succeed
+func TestEnsureCollection_ConcurrentCreate(t *testing.T) {
+	// Both checks run before either create, so both instances see no collection
+	var checked sync.WaitGroup
+	checked.Add(2)
+	var creates atomic.Int32
+
+	mock := &mockRekognitionAPI{
+		describeCollectionFunc: func(ctx context.Context, params *rekognition.DescribeCollectionInput, optFns ...func(*rekognition.Options)) (*rekognition.DescribeCollectionOutput, error) {
+			checked.Done()
+			checked.Wait()
+			return nil, &smithy.GenericAPIError{Code: errCodeResourceNotFound, Message: "collection not found"}
+		},
+		createCollectionFunc: func(ctx context.Context, params *rekognition.CreateCollectionInput, optFns ...func(*rekognition.Options)) (*rekognition.CreateCollectionOutput, error) {
+			if creates.Add(1) > 1 {
+				return nil, &smithy.GenericAPIError{Code: errCodeResourceExists, Message: "collection already exists"}
+			}
+			return &rekognition.CreateCollectionOutput{}, nil
+		},
+	}
+	client := &Client{rekognition: mock, config: DefaultConfig()}
+
+	errs := make([]error, 2)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = client.EnsureCollection(context.Background(), "tenant-123")
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(2), creates.Load(), "both instances attempt the create")
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+
+	// A direct create still reports the conflict
+	err := client.CreateCollection(context.Background(), "tenant-123")
+	assert.ErrorIs(t, err, ErrCollectionAlreadyExists)
+}
@@ -0,0 +1,117 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultFallbackMaxKeys bounds the number of keys tracked by the in-memory fallback
+const DefaultFallbackMaxKeys = 10000
+
+// fallbackCounter tracks a fixed window for a single key of a tenant
+type fallbackCounter struct {
+	tenantID  uuid.UUID
+	count     int
+	windowEnd time.Time
+}
+
+// memoryLimiter is a bounded in-memory fixed-window limiter used while the store is degraded
+// Limits are approximate: each API instance counts independently
+type memoryLimiter struct {
+	mu       sync.Mutex
+	counters map[string]*fallbackCounter
+	maxKeys  int
+	window   time.Duration
+}
+
+// newMemoryLimiter creates a fallback limiter that tracks at most maxKeys keys
+func newMemoryLimiter(window time.Duration, maxKeys int) *memoryLimiter {
+	if maxKeys <= 0 {
+		maxKeys = DefaultFallbackMaxKeys
+	}
+	return &memoryLimiter{
+		counters: make(map[string]*fallbackCounter),
+		maxKeys:  maxKeys,
+		window:   window,
+	}
+}
+
+// increment adds one request for key and returns the count in the current window
+func (m *memoryLimiter) increment(key string, tenantID uuid.UUID, now time.Time) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counter, exists := m.counters[key]
+	if exists && now.Before(counter.windowEnd) {
+		counter.count++
+		return counter.count
+	}
+
+	if !exists && len(m.counters) >= m.maxKeys {
+		m.evict(now)
+	}
+
+	m.counters[key] = &fallbackCounter{tenantID: tenantID, count: 1, windowEnd: now.Add(m.window)}
+	return 1
+}
+
+// evict removes expired counters, or the oldest one if none have expired
+// Must be called with mu held
+func (m *memoryLimiter) evict(now time.Time) {
+	var oldestKey string
+	var oldestEnd time.Time
+
+	for key, counter := range m.counters {
+		if !now.Before(counter.windowEnd) {
+			delete(m.counters, key)
+			continue
+		}
+		if oldestKey == "" || counter.windowEnd.Before(oldestEnd) {
+			oldestKey = key
+			oldestEnd = counter.windowEnd
+		}
+	}
+
+	if len(m.counters) >= m.maxKeys && oldestKey != "" {
+		delete(m.counters, oldestKey)
+	}
+}
+
+// drain removes and returns the counters whose window has not expired yet
+func (m *memoryLimiter) drain(now time.Time) map[string]fallbackCounter {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	live := make(map[string]fallbackCounter, len(m.counters))
+	for key, counter := range m.counters {
+		if now.Before(counter.windowEnd) {
+			live[key] = *counter
+		}
+	}
+	m.counters = make(map[string]*fallbackCounter)
+	return live
+}
+
+// resetTenant removes every counter of a tenant and returns how many were tracked
+func (m *memoryLimiter) resetTenant(tenantID uuid.UUID) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	removed := 0
+	for key, counter := range m.counters {
+		if counter.tenantID == tenantID {
+			delete(m.counters, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// size returns the number of tracked keys
+func (m *memoryLimiter) size() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.counters)
+}
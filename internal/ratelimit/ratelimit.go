@@ -2,7 +2,10 @@ package ratelimit
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,10 +20,53 @@ type DB interface {
 	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
 }
 
+const (
+	// searchRateKeyPrefix prefixes the per-tenant search counter key
+	searchRateKeyPrefix = "search_rate:"
+	// widgetSessionRateKeyPrefix prefixes the per-public-key widget session counter key
+	widgetSessionRateKeyPrefix = "widget_session_rate:"
+	// widgetCheckRateKeyPrefix prefixes the per-session widget registration check counter key
+	widgetCheckRateKeyPrefix = "widget_check_rate:"
+)
+
+// ErrLimitExceeded is returned when a key exceeds its limit in the current window
+var ErrLimitExceeded = errors.New("rate limit exceeded")
+
+// LimitError is the ErrLimitExceeded returned by the Check methods, carrying the usage that
+// rejected the request so callers can tell clients when to retry
+type LimitError struct {
+	Limit   int
+	Count   int
+	ResetAt time.Time
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%v: %d/%d requests in window", ErrLimitExceeded, e.Count, e.Limit)
+}
+
+// Is makes errors.Is(err, ErrLimitExceeded) match
+func (e *LimitError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
+
+// FailPolicy defines how the limiter behaves when the store is unavailable
+type FailPolicy string
+
+const (
+	// FailClosed rejects requests while the store is unavailable (default)
+	FailClosed FailPolicy = "closed"
+	// FailOpen falls back to a local in-memory limiter while the store is unavailable
+	FailOpen FailPolicy = "open"
+)
+
 // RateLimiter provides PostgreSQL-based rate limiting with sliding window
 type RateLimiter struct {
-	db     DB
-	window time.Duration
+	db       DB
+	window   time.Duration
+	policy   FailPolicy
+	fallback *memoryLimiter
+	degraded atomic.Bool
+	logger   *slog.Logger
 }
 
 // NewRateLimiter creates a new rate limiter with sliding window
@@ -39,16 +85,44 @@ func NewRateLimiterWithDB(db DB, window time.Duration) *RateLimiter {
 	}
 }
 
+// WithFailPolicy configures the behavior when the store is unavailable
+// With FailOpen, a bounded in-memory limiter enforces approximate limits until the store recovers
+func (r *RateLimiter) WithFailPolicy(policy FailPolicy, logger *slog.Logger) *RateLimiter {
+	r.policy = policy
+	r.logger = logger
+	if policy == FailOpen {
+		r.fallback = newMemoryLimiter(r.window, DefaultFallbackMaxKeys)
+	}
+	return r
+}
+
 // CheckSearchLimit checks if tenant has exceeded search rate limit
-// Returns error if limit exceeded, nil otherwise
+// Returns a *LimitError if limit exceeded, nil otherwise
 func (r *RateLimiter) CheckSearchLimit(ctx context.Context, tenantID uuid.UUID, limit int) error {
+	return r.checkLimit(ctx, searchRateKey(tenantID), tenantID, limit)
+}
+
+// CheckWidgetSessionLimit checks if a widget public key has exceeded the number of sessions
+// it may create per window. The counter belongs to the key's tenant, so ResetTenant clears it.
+func (r *RateLimiter) CheckWidgetSessionLimit(ctx context.Context, tenantID uuid.UUID, publicKey string, limit int) error {
+	return r.checkLimit(ctx, widgetSessionRateKey(publicKey), tenantID, limit)
+}
+
+// CheckWidgetCheckLimit checks if a widget session has exceeded the registration checks it may
+// make per window, which bounds how fast a browser can probe external_ids
+func (r *RateLimiter) CheckWidgetCheckLimit(ctx context.Context, tenantID, sessionID uuid.UUID, limit int) error {
+	return r.checkLimit(ctx, widgetCheckRateKey(sessionID), tenantID, limit)
+}
+
+// checkLimit counts one request for key and returns a *LimitError once the window holds more than limit.
+// A window resets once no request happened for a full window, so the reset is now plus the window.
+func (r *RateLimiter) checkLimit(ctx context.Context, key string, tenantID uuid.UUID, limit int) error {
 	if limit <= 0 {
 		return nil // No limit configured
 	}
 
 	now := time.Now()
 	windowStart := now.Add(-r.window)
-	key := fmt.Sprintf("search_rate:%s", tenantID)
 
 	// Use ON CONFLICT to atomically increment or insert counter
 	query := `
@@ -74,16 +148,101 @@ func (r *RateLimiter) CheckSearchLimit(ctx context.Context, tenantID uuid.UUID,
 	var count int
 	err := r.db.QueryRow(ctx, query, key, windowStart, now, tenantID).Scan(&count)
 	if err != nil {
-		return fmt.Errorf("check rate limit: %w", err)
+		if r.policy != FailOpen {
+			return fmt.Errorf("check rate limit: %w", err)
+		}
+		r.markDegraded(tenantID, err)
+		count = r.fallback.increment(key, tenantID, now)
+	} else {
+		r.markRecovered()
 	}
 
 	if count > limit {
-		return fmt.Errorf("rate limit exceeded: %d/%d requests in window", count, limit)
+		return &LimitError{Limit: limit, Count: count, ResetAt: now.Add(r.window)}
 	}
 
 	return nil
 }
 
+// searchRateKey returns the counter key for a tenant's search limit
+func searchRateKey(tenantID uuid.UUID) string {
+	return searchRateKeyPrefix + tenantID.String()
+}
+
+// widgetSessionRateKey returns the counter key for a widget public key's session creation limit
+func widgetSessionRateKey(publicKey string) string {
+	return widgetSessionRateKeyPrefix + publicKey
+}
+
+// widgetCheckRateKey returns the counter key for a widget session's registration check limit
+func widgetCheckRateKey(sessionID uuid.UUID) string {
+	return widgetCheckRateKeyPrefix + sessionID.String()
+}
+
+// Flush persists counts taken by the in-memory fallback so they survive a restart
+// It is a no-op when no fallback is configured (all counts already live in the store)
+func (r *RateLimiter) Flush(ctx context.Context) error {
+	if r.fallback == nil {
+		return nil
+	}
+
+	now := time.Now()
+	counters := r.fallback.drain(now)
+
+	// Add local counts to the stored window, or start a new one if it expired
+	query := `
+		INSERT INTO rate_limit_counters (key, count, window_start, window_end, tenant_id)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (key)
+		DO UPDATE SET
+			count = CASE
+				WHEN rate_limit_counters.window_end < $3 THEN $2
+				ELSE rate_limit_counters.count + $2
+			END,
+			window_start = CASE
+				WHEN rate_limit_counters.window_end < $3 THEN $3
+				ELSE rate_limit_counters.window_start
+			END,
+			window_end = $4
+	`
+
+	var errs []error
+	for key, counter := range counters {
+		windowStart := counter.windowEnd.Add(-r.window)
+		if _, err := r.db.Exec(ctx, query, key, counter.count, windowStart, now, counter.tenantID); err != nil {
+			errs = append(errs, fmt.Errorf("tenant %s: persist fallback count: %w", counter.tenantID, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("flush rate limit: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// markDegraded logs the transition to the in-memory fallback once per outage
+func (r *RateLimiter) markDegraded(tenantID uuid.UUID, err error) {
+	if r.degraded.CompareAndSwap(false, true) && r.logger != nil {
+		r.logger.Warn("rate limit store unavailable, using in-memory fallback",
+			"tenant_id", tenantID,
+			"error", err,
+		)
+	}
+}
+
+// markRecovered logs when the store becomes available again
+func (r *RateLimiter) markRecovered() {
+	if r.degraded.CompareAndSwap(true, false) && r.logger != nil {
+		r.logger.Info("rate limit store recovered")
+	}
+}
+
+// Degraded reports whether the limiter is currently using the in-memory fallback
+func (r *RateLimiter) Degraded() bool {
+	return r.degraded.Load()
+}
+
 // CleanupExpired removes expired rate limit counters (run via cron)
 func (r *RateLimiter) CleanupExpired(ctx context.Context) (int64, error) {
 	query := `DELETE FROM rate_limit_counters WHERE window_end < NOW() - INTERVAL '1 hour'`
@@ -96,7 +255,7 @@ func (r *RateLimiter) CleanupExpired(ctx context.Context) (int64, error) {
 
 // GetCurrentCount returns the current count for a tenant (for testing/monitoring)
 func (r *RateLimiter) GetCurrentCount(ctx context.Context, tenantID uuid.UUID) (int, error) {
-	key := fmt.Sprintf("search_rate:%s", tenantID)
+	key := searchRateKey(tenantID)
 	windowStart := time.Now().Add(-r.window)
 
 	query := `
@@ -114,9 +273,52 @@ func (r *RateLimiter) GetCurrentCount(ctx context.Context, tenantID uuid.UUID) (
 	return count, nil
 }
 
+// SearchWindow returns the tenant's search count in the current window and when it resets.
+// A window resets once no search happened for a full window, so the reset time is the last
+// search plus the window. Without an open window it returns 0 and the zero time.
+func (r *RateLimiter) SearchWindow(ctx context.Context, tenantID uuid.UUID) (int, time.Time, error) {
+	key := searchRateKey(tenantID)
+	windowStart := time.Now().Add(-r.window)
+
+	query := `
+		SELECT count, window_end
+		FROM rate_limit_counters
+		WHERE key = $1 AND window_end > $2
+	`
+
+	var count int
+	var lastSeen time.Time
+	err := r.db.QueryRow(ctx, query, key, windowStart).Scan(&count, &lastSeen)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, time.Time{}, nil
+	}
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("tenant %s: read search window: %w", tenantID, err)
+	}
+
+	return count, lastSeen.Add(r.window), nil
+}
+
+// ResetTenant deletes every counter of a tenant, including the one held by the in-memory
+// fallback, so its next request starts a fresh window. Returns the number of buckets cleared.
+func (r *RateLimiter) ResetTenant(ctx context.Context, tenantID uuid.UUID) (int, error) {
+	cleared := 0
+	if r.fallback != nil {
+		cleared = r.fallback.resetTenant(tenantID)
+	}
+
+	tag, err := r.db.Exec(ctx, `DELETE FROM rate_limit_counters WHERE tenant_id = $1`, tenantID)
+	if err != nil {
+		return cleared, fmt.Errorf("tenant %s: reset rate limits: %w", tenantID, err)
+	}
+
+	// A key counted by both the fallback and the store is one bucket
+	return max(cleared, int(tag.RowsAffected())), nil
+}
+
 // ResetLimit resets the rate limit for a tenant (admin operation)
 func (r *RateLimiter) ResetLimit(ctx context.Context, tenantID uuid.UUID) error {
-	key := fmt.Sprintf("search_rate:%s", tenantID)
+	key := searchRateKey(tenantID)
 	query := `DELETE FROM rate_limit_counters WHERE key = $1`
 	_, err := r.db.Exec(ctx, query, key)
 	return err
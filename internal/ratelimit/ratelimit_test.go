@@ -2,6 +2,9 @@ package ratelimit
 
 import (
 	"context"
+	"errors"
+	"io"
+	"log/slog"
 	"testing"
 	"time"
 
@@ -82,11 +85,19 @@ func TestRateLimiter_CheckSearchLimit(t *testing.T) {
 					WillReturnRows(rows)
 			}
 
+			before := time.Now()
 			err = rl.CheckSearchLimit(ctx, tt.tenantID, tt.limit)
 
 			if tt.wantErr {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), tt.errMsg)
+				assert.ErrorIs(t, err, ErrLimitExceeded)
+
+				var limitErr *LimitError
+				require.ErrorAs(t, err, &limitErr)
+				assert.Equal(t, tt.limit, limitErr.Limit)
+				assert.Equal(t, tt.mockCount, limitErr.Count)
+				assert.WithinRange(t, limitErr.ResetAt, before.Add(time.Minute), time.Now().Add(time.Minute))
 			} else {
 				require.NoError(t, err)
 			}
@@ -183,6 +194,61 @@ func TestRateLimiter_GetCurrentCount(t *testing.T) {
 	}
 }
 
+func TestRateLimiter_SearchWindow(t *testing.T) {
+	tenantID := uuid.New()
+	lastSeen := time.Now().Add(-10 * time.Second)
+
+	t.Run("open window", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery("SELECT count, window_end").
+			WithArgs("search_rate:"+tenantID.String(), pgxmock.AnyArg()).
+			WillReturnRows(pgxmock.NewRows([]string{"count", "window_end"}).AddRow(7, lastSeen))
+
+		rl := NewRateLimiterWithDB(mock, time.Minute)
+		count, resetAt, err := rl.SearchWindow(context.Background(), tenantID)
+
+		require.NoError(t, err)
+		assert.Equal(t, 7, count)
+		assert.Equal(t, lastSeen.Add(time.Minute), resetAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("no window", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery("SELECT count, window_end").
+			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WillReturnError(pgx.ErrNoRows)
+
+		rl := NewRateLimiterWithDB(mock, time.Minute)
+		count, resetAt, err := rl.SearchWindow(context.Background(), tenantID)
+
+		require.NoError(t, err)
+		assert.Zero(t, count)
+		assert.True(t, resetAt.IsZero())
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery("SELECT count, window_end").
+			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WillReturnError(errors.New("connection refused"))
+
+		rl := NewRateLimiterWithDB(mock, time.Minute)
+		_, _, err = rl.SearchWindow(context.Background(), tenantID)
+
+		require.Error(t, err)
+	})
+}
+
 func TestRateLimiter_ResetLimit(t *testing.T) {
 	mock, err := pgxmock.NewPool()
 	require.NoError(t, err)
@@ -201,3 +267,308 @@ func TestRateLimiter_ResetLimit(t *testing.T) {
 	require.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestRateLimiter_ResetTenant(t *testing.T) {
+	t.Run("next search is allowed after reset", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		rl := NewRateLimiterWithDB(mock, time.Minute)
+		ctx := context.Background()
+		tenantID := uuid.New()
+
+		// Tenant is over its limit
+		mock.ExpectQuery("WITH current_count AS").
+			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), tenantID).
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(31))
+		require.ErrorIs(t, rl.CheckSearchLimit(ctx, tenantID, 30), ErrLimitExceeded)
+
+		mock.ExpectExec(`DELETE FROM rate_limit_counters WHERE tenant_id = \$1`).
+			WithArgs(tenantID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+		cleared, err := rl.ResetTenant(ctx, tenantID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, cleared)
+
+		// The counter row is gone, so the upsert starts a fresh window
+		mock.ExpectQuery("WITH current_count AS").
+			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), tenantID).
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(1))
+		assert.NoError(t, rl.CheckSearchLimit(ctx, tenantID, 30))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("clears the in-memory fallback counter", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+		rl := NewRateLimiterWithDB(mock, time.Minute).WithFailPolicy(FailOpen, logger)
+		ctx := context.Background()
+		tenantID := uuid.New()
+		storeErr := errors.New("connection refused")
+
+		for i := 0; i < 3; i++ {
+			mock.ExpectQuery("WITH current_count AS").
+				WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), tenantID).
+				WillReturnError(storeErr)
+			_ = rl.CheckSearchLimit(ctx, tenantID, 2)
+		}
+
+		mock.ExpectExec(`DELETE FROM rate_limit_counters`).
+			WithArgs(tenantID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+		cleared, err := rl.ResetTenant(ctx, tenantID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, cleared)
+
+		mock.ExpectQuery("WITH current_count AS").
+			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), tenantID).
+			WillReturnError(storeErr)
+		assert.NoError(t, rl.CheckSearchLimit(ctx, tenantID, 2), "fallback window starts over")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		rl := NewRateLimiterWithDB(mock, time.Minute)
+		mock.ExpectExec(`DELETE FROM rate_limit_counters`).WillReturnError(errors.New("connection refused"))
+
+		_, err = rl.ResetTenant(context.Background(), uuid.New())
+		assert.Error(t, err)
+	})
+}
+
+func TestRateLimiter_CheckSearchLimit_FailingStore(t *testing.T) {
+	storeErr := errors.New("connection refused")
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	tests := []struct {
+		name        string
+		policy      FailPolicy
+		limit       int
+		requests    int
+		wantErrAt   int // 1-based request index of first error, 0 = never
+		wantLimited bool
+	}{
+		{
+			name:      "fail closed rejects on store error",
+			policy:    FailClosed,
+			limit:     5,
+			requests:  1,
+			wantErrAt: 1,
+		},
+		{
+			name:      "fail open allows within fallback limit",
+			policy:    FailOpen,
+			limit:     3,
+			requests:  3,
+			wantErrAt: 0,
+		},
+		{
+			name:        "fail open enforces fallback limit",
+			policy:      FailOpen,
+			limit:       3,
+			requests:    4,
+			wantErrAt:   4,
+			wantLimited: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+
+			rl := NewRateLimiterWithDB(mock, time.Minute).WithFailPolicy(tt.policy, logger)
+			tenantID := uuid.New()
+
+			for i := 1; i <= tt.requests; i++ {
+				mock.ExpectQuery("WITH current_count AS").
+					WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), tenantID).
+					WillReturnError(storeErr)
+
+				err = rl.CheckSearchLimit(context.Background(), tenantID, tt.limit)
+				if i == tt.wantErrAt {
+					require.Error(t, err)
+					if tt.wantLimited {
+						assert.ErrorIs(t, err, ErrLimitExceeded)
+					} else {
+						assert.ErrorIs(t, err, storeErr)
+					}
+					break
+				}
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.policy == FailOpen, rl.Degraded())
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRateLimiter_FailOpen_Recovers(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	rl := NewRateLimiterWithDB(mock, time.Minute).
+		WithFailPolicy(FailOpen, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	tenantID := uuid.New()
+
+	mock.ExpectQuery("WITH current_count AS").
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), tenantID).
+		WillReturnError(errors.New("connection refused"))
+	require.NoError(t, rl.CheckSearchLimit(context.Background(), tenantID, 10))
+	assert.True(t, rl.Degraded())
+
+	mock.ExpectQuery("WITH current_count AS").
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), tenantID).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(2))
+	require.NoError(t, rl.CheckSearchLimit(context.Background(), tenantID, 10))
+	assert.False(t, rl.Degraded())
+}
+
+func TestMemoryLimiter_Bounded(t *testing.T) {
+	m := newMemoryLimiter(time.Minute, 2)
+	now := time.Now()
+
+	assert.Equal(t, 1, m.increment("a", uuid.Nil, now))
+	assert.Equal(t, 2, m.increment("a", uuid.Nil, now))
+	assert.Equal(t, 1, m.increment("b", uuid.Nil, now.Add(time.Second)))
+	assert.Equal(t, 1, m.increment("c", uuid.Nil, now.Add(2*time.Second)))
+
+	// Oldest key was evicted to stay within bounds
+	assert.Equal(t, 2, m.size())
+	assert.Equal(t, 1, m.increment("a", uuid.Nil, now.Add(3*time.Second)))
+
+	// Counters reset after the window expires
+	assert.Equal(t, 1, m.increment("c", uuid.Nil, now.Add(2*time.Minute)))
+}
+
+func TestRateLimiter_Flush(t *testing.T) {
+	t.Run("persists fallback counts", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		rl := NewRateLimiterWithDB(mock, time.Minute).
+			WithFailPolicy(FailOpen, slog.New(slog.NewTextHandler(io.Discard, nil)))
+		tenantID := uuid.New()
+
+		for i := 0; i < 3; i++ {
+			mock.ExpectQuery("WITH current_count AS").
+				WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), tenantID).
+				WillReturnError(errors.New("connection refused"))
+			require.NoError(t, rl.CheckSearchLimit(context.Background(), tenantID, 10))
+		}
+
+		mock.ExpectExec("INSERT INTO rate_limit_counters").
+			WithArgs(searchRateKey(tenantID), 3, pgxmock.AnyArg(), pgxmock.AnyArg(), tenantID).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		require.NoError(t, rl.Flush(context.Background()))
+		assert.NoError(t, mock.ExpectationsWereMet())
+
+		// Counters are drained after a flush
+		require.NoError(t, rl.Flush(context.Background()))
+	})
+
+	t.Run("no-op without fallback", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		rl := NewRateLimiterWithDB(mock, time.Minute)
+
+		require.NoError(t, rl.Flush(context.Background()))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRateLimiter_CheckWidgetCheckLimit(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	rl := NewRateLimiterWithDB(mock, time.Minute)
+	tenantID := uuid.New()
+	sessionID := uuid.New()
+
+	mock.ExpectQuery("WITH current_count AS").
+		WithArgs("widget_check_rate:"+sessionID.String(), pgxmock.AnyArg(), pgxmock.AnyArg(), tenantID).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(20))
+	assert.NoError(t, rl.CheckWidgetCheckLimit(context.Background(), tenantID, sessionID, 20))
+
+	mock.ExpectQuery("WITH current_count AS").
+		WithArgs("widget_check_rate:"+sessionID.String(), pgxmock.AnyArg(), pgxmock.AnyArg(), tenantID).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(21))
+	assert.ErrorIs(t, rl.CheckWidgetCheckLimit(context.Background(), tenantID, sessionID, 20), ErrLimitExceeded)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRateLimiter_CheckWidgetSessionLimit(t *testing.T) {
+	t.Run("counts per public key", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		rl := NewRateLimiterWithDB(mock, time.Minute)
+		tenantID := uuid.New()
+
+		mock.ExpectQuery("WITH current_count AS").
+			WithArgs("widget_session_rate:pk_live_abc", pgxmock.AnyArg(), pgxmock.AnyArg(), tenantID).
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(5))
+		assert.NoError(t, rl.CheckWidgetSessionLimit(context.Background(), tenantID, "pk_live_abc", 5))
+
+		mock.ExpectQuery("WITH current_count AS").
+			WithArgs("widget_session_rate:pk_live_abc", pgxmock.AnyArg(), pgxmock.AnyArg(), tenantID).
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(6))
+		assert.ErrorIs(t, rl.CheckWidgetSessionLimit(context.Background(), tenantID, "pk_live_abc", 5), ErrLimitExceeded)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("fallback counts are flushed and reset with the tenant", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		rl := NewRateLimiterWithDB(mock, time.Minute).
+			WithFailPolicy(FailOpen, slog.New(slog.NewTextHandler(io.Discard, nil)))
+		ctx := context.Background()
+		tenantID := uuid.New()
+
+		for i := 0; i < 2; i++ {
+			mock.ExpectQuery("WITH current_count AS").
+				WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), tenantID).
+				WillReturnError(errors.New("connection refused"))
+			require.NoError(t, rl.CheckWidgetSessionLimit(ctx, tenantID, "pk_live_abc", 5))
+		}
+
+		mock.ExpectExec("INSERT INTO rate_limit_counters").
+			WithArgs(widgetSessionRateKey("pk_live_abc"), 2, pgxmock.AnyArg(), pgxmock.AnyArg(), tenantID).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		require.NoError(t, rl.Flush(ctx))
+
+		mock.ExpectQuery("WITH current_count AS").
+			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), tenantID).
+			WillReturnError(errors.New("connection refused"))
+		require.NoError(t, rl.CheckWidgetSessionLimit(ctx, tenantID, "pk_live_abc", 5))
+
+		mock.ExpectExec(`DELETE FROM rate_limit_counters`).
+			WithArgs(tenantID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 0))
+		cleared, err := rl.ResetTenant(ctx, tenantID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, cleared)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
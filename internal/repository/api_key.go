@@ -21,14 +21,18 @@ func NewAPIKeyRepository(pool PgxPool) *APIKeyRepository {
 
 func (r *APIKeyRepository) Create(ctx context.Context, key *domain.APIKey) error {
 	query := `
-		INSERT INTO api_keys (id, tenant_id, name, key_hash, key_prefix, environment, is_active, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		INSERT INTO api_keys (id, tenant_id, name, key_hash, key_prefix, environment, is_active, scopes, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
 		RETURNING created_at
 	`
 
 	if key.ID == uuid.Nil {
 		key.ID = uuid.New()
 	}
+	// scopes is NOT NULL: a nil slice would be sent as NULL
+	if key.Scopes == nil {
+		key.Scopes = []string{}
+	}
 
 	err := r.pool.QueryRow(ctx, query,
 		key.ID,
@@ -38,15 +42,12 @@ func (r *APIKeyRepository) Create(ctx context.Context, key *domain.APIKey) error
 		key.KeyPrefix,
 		key.Environment,
 		key.IsActive,
+		key.Scopes,
 	).Scan(&key.CreatedAt)
 
 	if err != nil {
 		if isUniqueViolation(err) {
-			return &domain.AppError{
-				Code:       "API_KEY_ALREADY_EXISTS",
-				Message:    "API key with this hash already exists",
-				StatusCode: 409,
-			}
+			return domain.ErrAPIKeyExists
 		}
 		return fmt.Errorf("create api key: %w", err)
 	}
@@ -56,7 +57,7 @@ func (r *APIKeyRepository) Create(ctx context.Context, key *domain.APIKey) error
 
 func (r *APIKeyRepository) GetByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
 	query := `
-		SELECT id, tenant_id, name, key_hash, key_prefix, environment, is_active, last_used_at, created_at
+		SELECT id, tenant_id, name, key_hash, key_prefix, environment, is_active, last_used_at, created_at, auto_provision, scopes
 		FROM api_keys
 		WHERE key_hash = $1
 	`
@@ -72,6 +73,8 @@ func (r *APIKeyRepository) GetByHash(ctx context.Context, hash string) (*domain.
 		&key.IsActive,
 		&key.LastUsedAt,
 		&key.CreatedAt,
+		&key.AutoProvision,
+		&key.Scopes,
 	)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -86,7 +89,7 @@ func (r *APIKeyRepository) GetByHash(ctx context.Context, hash string) (*domain.
 
 func (r *APIKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.APIKey, error) {
 	query := `
-		SELECT id, tenant_id, name, key_hash, key_prefix, environment, is_active, last_used_at, created_at
+		SELECT id, tenant_id, name, key_hash, key_prefix, environment, is_active, last_used_at, created_at, auto_provision, scopes
 		FROM api_keys
 		WHERE id = $1
 	`
@@ -102,6 +105,8 @@ func (r *APIKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.A
 		&key.IsActive,
 		&key.LastUsedAt,
 		&key.CreatedAt,
+		&key.AutoProvision,
+		&key.Scopes,
 	)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -116,7 +121,7 @@ func (r *APIKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.A
 
 func (r *APIKeyRepository) ListByTenant(ctx context.Context, tenantID uuid.UUID) ([]domain.APIKey, error) {
 	query := `
-		SELECT id, tenant_id, name, key_hash, key_prefix, environment, is_active, last_used_at, created_at
+		SELECT id, tenant_id, name, key_hash, key_prefix, environment, is_active, last_used_at, created_at, scopes
 		FROM api_keys
 		WHERE tenant_id = $1
 		ORDER BY created_at DESC
@@ -141,6 +146,7 @@ func (r *APIKeyRepository) ListByTenant(ctx context.Context, tenantID uuid.UUID)
 			&key.IsActive,
 			&key.LastUsedAt,
 			&key.CreatedAt,
+			&key.Scopes,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scan api key: %w", err)
@@ -193,6 +199,27 @@ func (r *APIKeyRepository) Revoke(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// RevokeByTenant deactivates a key only if it belongs to the tenant.
+// Other active keys of the tenant are unaffected.
+func (r *APIKeyRepository) RevokeByTenant(ctx context.Context, tenantID, id uuid.UUID) error {
+	query := `
+		UPDATE api_keys
+		SET is_active = false
+		WHERE id = $1 AND tenant_id = $2
+	`
+
+	result, err := r.pool.Exec(ctx, query, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("tenant %s: revoke api key: %w", tenantID, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
 func (r *APIKeyRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	query := `
 		DELETE FROM api_keys
@@ -4,18 +4,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/pgvector/pgvector-go"
 
+	"github.com/saturnino-fabrica-de-software/rekko/internal/database"
 	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
 )
 
 // embeddingSize is the standard face recognition embedding dimension
 const embeddingSize = 512
 
+// faceStreamBatchSize is how many rows one StreamAll query reads
+const faceStreamBatchSize = 1000
+
 // float32Pool reuses float32 slices to reduce allocations in hot paths
 // Each slice is pre-allocated to embeddingSize (512 float32)
 var float32Pool = sync.Pool{
@@ -35,9 +41,9 @@ func NewFaceRepository(pool PgxPool) *FaceRepository {
 
 func (r *FaceRepository) Create(ctx context.Context, face *domain.Face) error {
 	query := `
-		INSERT INTO faces (id, tenant_id, external_id, embedding, metadata, quality_score, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
-		RETURNING created_at, updated_at
+		INSERT INTO faces (id, tenant_id, external_id, embedding, embedding_model, embedding_version, metadata, quality_score, is_test, expires_at, provider_face_id, embedding_hash, embedding_half, created_at, updated_at, enrolled_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7, $8, $9, $10, NULLIF($11, ''), NULLIF($12, ''), $13, NOW(), NOW(), NOW())
+		RETURNING created_at, updated_at, enrolled_at
 	`
 
 	if face.ID == uuid.Nil {
@@ -45,6 +51,7 @@ func (r *FaceRepository) Create(ctx context.Context, face *domain.Face) error {
 	}
 
 	var embedding *pgvector.Vector
+	var embeddingHalf *pgvector.HalfVector
 	if len(face.Embedding) > 0 {
 		var floats []float32
 
@@ -63,8 +70,7 @@ func (r *FaceRepository) Create(ctx context.Context, face *domain.Face) error {
 			floats[i] = float32(v)
 		}
 
-		vec := pgvector.NewVector(floats)
-		embedding = &vec
+		embedding, embeddingHalf = storedEmbedding(floats, face.EmbeddingPrecision)
 	}
 
 	err := r.pool.QueryRow(ctx, query,
@@ -72,9 +78,16 @@ func (r *FaceRepository) Create(ctx context.Context, face *domain.Face) error {
 		face.TenantID,
 		face.ExternalID,
 		embedding,
+		face.EmbeddingModel,
+		face.EmbeddingVersion,
 		face.Metadata,
 		face.QualityScore,
-	).Scan(&face.CreatedAt, &face.UpdatedAt)
+		face.IsTest,
+		face.ExpiresAt,
+		face.ProviderFaceID,
+		face.EmbeddingHash,
+		embeddingHalf,
+	).Scan(&face.CreatedAt, &face.UpdatedAt, &face.EnrolledAt)
 
 	if err != nil {
 		if isUniqueViolation(err) {
@@ -86,31 +99,53 @@ func (r *FaceRepository) Create(ctx context.Context, face *domain.Face) error {
 	return nil
 }
 
-// Update updates an existing face's embedding and quality score
+// storedEmbedding returns the embedding for the column its precision is stored in, leaving the
+// other nil so a face never keeps a stale embedding at the previous precision
+func storedEmbedding(floats []float32, precision domain.EmbeddingPrecision) (*pgvector.Vector, *pgvector.HalfVector) {
+	if precision == domain.EmbeddingPrecisionHalf {
+		half := pgvector.NewHalfVector(floats)
+		return nil, &half
+	}
+	vec := pgvector.NewVector(floats)
+	return &vec, nil
+}
+
+// Update updates an existing face's embedding, fingerprint, quality score, provider face ID and embedding hash,
+// which re-enrolls it. Metadata and expiry are only replaced when the face carries new ones.
 func (r *FaceRepository) Update(ctx context.Context, face *domain.Face) error {
 	query := `
 		UPDATE faces
-		SET embedding = $1, quality_score = $2, updated_at = NOW()
-		WHERE id = $3 AND tenant_id = $4
-		RETURNING updated_at
+		SET embedding = $1, embedding_half = $11, embedding_model = NULLIF($2, ''), embedding_version = NULLIF($3, ''),
+		    quality_score = $4, metadata = COALESCE($7, metadata),
+		    expires_at = COALESCE($8, expires_at), provider_face_id = NULLIF($9, ''),
+		    embedding_hash = NULLIF($10, ''), updated_at = NOW(), enrolled_at = NOW()
+		WHERE id = $5 AND tenant_id = $6
+		RETURNING updated_at, enrolled_at
 	`
 
 	var embedding *pgvector.Vector
+	var embeddingHalf *pgvector.HalfVector
 	if len(face.Embedding) > 0 {
 		floats := make([]float32, len(face.Embedding))
 		for i, v := range face.Embedding {
 			floats[i] = float32(v)
 		}
-		vec := pgvector.NewVector(floats)
-		embedding = &vec
+		embedding, embeddingHalf = storedEmbedding(floats, face.EmbeddingPrecision)
 	}
 
 	err := r.pool.QueryRow(ctx, query,
 		embedding,
+		face.EmbeddingModel,
+		face.EmbeddingVersion,
 		face.QualityScore,
 		face.ID,
 		face.TenantID,
-	).Scan(&face.UpdatedAt)
+		face.Metadata,
+		face.ExpiresAt,
+		face.ProviderFaceID,
+		face.EmbeddingHash,
+		embeddingHalf,
+	).Scan(&face.UpdatedAt, &face.EnrolledAt)
 
 	if err != nil {
 		return fmt.Errorf("update face: %w", err)
@@ -121,7 +156,10 @@ func (r *FaceRepository) Update(ctx context.Context, face *domain.Face) error {
 
 func (r *FaceRepository) GetByExternalID(ctx context.Context, tenantID uuid.UUID, externalID string) (*domain.Face, error) {
 	query := `
-		SELECT id, tenant_id, external_id, embedding, metadata, quality_score, created_at, updated_at
+		SELECT id, tenant_id, external_id, COALESCE(embedding, embedding_half::vector(512)),
+		       COALESCE(embedding_model, ''), COALESCE(embedding_version, ''),
+		       metadata, quality_score, is_test, expires_at, COALESCE(provider_face_id, ''),
+		       COALESCE(embedding_hash, ''), created_at, updated_at, enrolled_at
 		FROM faces
 		WHERE tenant_id = $1 AND external_id = $2
 	`
@@ -134,10 +172,17 @@ func (r *FaceRepository) GetByExternalID(ctx context.Context, tenantID uuid.UUID
 		&face.TenantID,
 		&face.ExternalID,
 		&embedding,
+		&face.EmbeddingModel,
+		&face.EmbeddingVersion,
 		&face.Metadata,
 		&face.QualityScore,
+		&face.IsTest,
+		&face.ExpiresAt,
+		&face.ProviderFaceID,
+		&face.EmbeddingHash,
 		&face.CreatedAt,
 		&face.UpdatedAt,
+		&face.EnrolledAt,
 	)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -177,7 +222,19 @@ func (r *FaceRepository) Delete(ctx context.Context, tenantID uuid.UUID, externa
 
 // SearchByEmbedding searches for similar faces using cosine distance
 // Returns matches above threshold, ordered by similarity (highest first)
-func (r *FaceRepository) SearchByEmbedding(ctx context.Context, tenantID uuid.UUID, embedding []float64, threshold float64, limit int) ([]domain.SearchMatch, error) {
+// Faces fingerprinted with a different embedding model are never compared;
+// faces without a fingerprint (registered before fingerprinting) are still searched.
+// Faces stored at either precision are compared, so a tenant's faces keep matching
+// whatever embedding_precision it had when they were registered. Only faces of the request's
+// environment (test or live key) are searched.
+func (r *FaceRepository) SearchByEmbedding(ctx context.Context, tenantID uuid.UUID, embedding []float64, fingerprint domain.EmbeddingFingerprint, threshold float64, limit int) ([]domain.SearchMatch, error) {
+	return r.SearchByEmbeddingSegmented(ctx, tenantID, embedding, fingerprint, threshold, domain.SegmentThresholdSettings{}, limit)
+}
+
+// SearchByEmbeddingSegmented is SearchByEmbedding where faces in a segment of segments.Search
+// must reach their segment's threshold instead of threshold. The segment is filtered in SQL,
+// so faces below their threshold never take a place within limit.
+func (r *FaceRepository) SearchByEmbeddingSegmented(ctx context.Context, tenantID uuid.UUID, embedding []float64, fingerprint domain.EmbeddingFingerprint, threshold float64, segments domain.SegmentThresholdSettings, limit int) ([]domain.SearchMatch, error) {
 	var floats []float32
 
 	// Use pool for standard embedding size (zero-allocation hot path)
@@ -195,27 +252,72 @@ func (r *FaceRepository) SearchByEmbedding(ctx context.Context, tenantID uuid.UU
 		floats[i] = float32(v)
 	}
 
-	// Create vector
+	// Create vectors for the full and half precision columns
 	vec := pgvector.NewVector(floats)
+	half := pgvector.NewHalfVector(floats)
 
 	// Query usando cosine distance (<=>)
 	// pgvector retorna distância (0 = idêntico, 2 = oposto)
 	// Convertemos para similarity: 1 - (distance / 2)
+	// Each branch is ordered by its own column so both can use their HNSW index.
+	// $3 is the lowest threshold of any face; a face whose string metadata value under the
+	// segment key $9 names a segment in $10 must reach that segment's threshold in $11, the
+	// others $12. Only string values select a segment, as in SegmentThresholdSettings.
 	query := `
-		SELECT id, external_id, metadata,
-		       1 - (embedding <=> $1) / 2 as similarity
-		FROM faces
-		WHERE tenant_id = $2
-		  AND embedding IS NOT NULL
-		  AND 1 - (embedding <=> $1) / 2 >= $3
-		ORDER BY embedding <=> $1
+		SELECT id, external_id, metadata, similarity
+		FROM (
+			(SELECT id, external_id, metadata,
+			        1 - (embedding <=> $1) / 2 as similarity
+			 FROM faces
+			 WHERE tenant_id = $2
+			   AND embedding IS NOT NULL
+			   AND 1 - (embedding <=> $1) / 2 >= $3
+			   AND 1 - (embedding <=> $1) / 2 >= COALESCE(
+			        (SELECT s.threshold FROM unnest($10::text[], $11::float8[]) AS s(name, threshold)
+			         WHERE jsonb_typeof(metadata -> $9::text) = 'string' AND s.name = metadata ->> $9::text), $12)
+			   AND ($5 = '' OR embedding_model IS NULL
+			        OR (embedding_model = $5 AND embedding_version = $6))
+			   AND is_test = $8
+			 ORDER BY embedding <=> $1
+			 LIMIT $4)
+			UNION ALL
+			(SELECT id, external_id, metadata,
+			        1 - (embedding_half <=> $7) / 2 as similarity
+			 FROM faces
+			 WHERE tenant_id = $2
+			   AND embedding_half IS NOT NULL
+			   AND 1 - (embedding_half <=> $7) / 2 >= $3
+			   AND 1 - (embedding_half <=> $7) / 2 >= COALESCE(
+			        (SELECT s.threshold FROM unnest($10::text[], $11::float8[]) AS s(name, threshold)
+			         WHERE jsonb_typeof(metadata -> $9::text) = 'string' AND s.name = metadata ->> $9::text), $12)
+			   AND ($5 = '' OR embedding_model IS NULL
+			        OR (embedding_model = $5 AND embedding_version = $6))
+			   AND is_test = $8
+			 ORDER BY embedding_half <=> $7
+			 LIMIT $4)
+		) candidates
+		ORDER BY similarity DESC
 		LIMIT $4
 	`
 
-	rows, err := r.pool.Query(ctx, query, vec, tenantID, threshold, limit)
+	segmentNames := make([]string, 0, len(segments.Search))
+	segmentThresholds := make([]float64, 0, len(segments.Search))
+	for name, segmentThreshold := range segments.Search {
+		segmentNames = append(segmentNames, name)
+		segmentThresholds = append(segmentThresholds, segmentThreshold)
+	}
+
+	rows, err := r.pool.Query(database.WithQueryLabel(ctx, "faces.search_by_embedding"), query,
+		vec, tenantID, segments.SearchQueryThreshold(threshold), limit, fingerprint.Model, fingerprint.Version, half, domain.IsTestMode(ctx),
+		segments.Key, segmentNames, segmentThresholds, threshold)
 	if err != nil {
 		return nil, fmt.Errorf("search faces by embedding: %w", err)
 	}
+	return collectSearchMatches(rows)
+}
+
+// collectSearchMatches scans search rows of id, external_id, metadata and similarity
+func collectSearchMatches(rows pgx.Rows) ([]domain.SearchMatch, error) {
 	defer rows.Close()
 
 	var matches []domain.SearchMatch
@@ -249,6 +351,102 @@ func (r *FaceRepository) SearchByEmbedding(ctx context.Context, tenantID uuid.UU
 	return matches, nil
 }
 
+// ExistsBatch checks which external IDs have a registered face in a single query
+// Every requested ID is present in the result; missing IDs are reported as not registered
+func (r *FaceRepository) ExistsBatch(ctx context.Context, tenantID uuid.UUID, externalIDs []string) (map[string]domain.RegistrationCheck, error) {
+	query := `
+		SELECT external_id, created_at
+		FROM faces
+		WHERE tenant_id = $1 AND external_id = ANY($2)
+	`
+
+	results := make(map[string]domain.RegistrationCheck, len(externalIDs))
+	for _, id := range externalIDs {
+		results[id] = domain.RegistrationCheck{Registered: false}
+	}
+
+	rows, err := r.pool.Query(ctx, query, tenantID, externalIDs)
+	if err != nil {
+		return nil, fmt.Errorf("check faces exist: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var externalID string
+		var createdAt time.Time
+		if err := rows.Scan(&externalID, &createdAt); err != nil {
+			return nil, fmt.Errorf("scan face existence: %w", err)
+		}
+		results[externalID] = domain.RegistrationCheck{
+			Registered:   true,
+			RegisteredAt: &createdAt,
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate face existence: %w", err)
+	}
+
+	return results, nil
+}
+
+// BulkUpdateMetadata merges patch into the metadata of every listed face in a single statement
+// Top-level keys in patch overwrite existing ones; returns the external IDs that were updated.
+// When any merged document would exceed maxBytes no face is updated and ErrMetadataTooLarge is returned.
+func (r *FaceRepository) BulkUpdateMetadata(ctx context.Context, tenantID uuid.UUID, externalIDs []string, patch map[string]interface{}, maxBytes int) ([]string, error) {
+	query := `
+		WITH targets AS (
+			SELECT id, external_id, COALESCE(metadata, '{}'::jsonb) || $3 AS merged
+			FROM faces
+			WHERE tenant_id = $1 AND external_id = ANY($2)
+		), oversized AS (
+			SELECT external_id FROM targets WHERE octet_length(merged::text) > $4
+		), updated AS (
+			UPDATE faces f
+			SET metadata = t.merged, updated_at = NOW()
+			FROM targets t
+			WHERE f.id = t.id AND NOT EXISTS (SELECT 1 FROM oversized)
+			RETURNING f.external_id
+		)
+		SELECT external_id, false FROM updated
+		UNION ALL
+		SELECT external_id, true FROM oversized
+	`
+
+	rows, err := r.pool.Query(ctx, query, tenantID, externalIDs, patch, maxBytes)
+	if err != nil {
+		return nil, fmt.Errorf("bulk update face metadata: %w", err)
+	}
+	defer rows.Close()
+
+	updated := make([]string, 0, len(externalIDs))
+	var oversized []string
+	for rows.Next() {
+		var externalID string
+		var tooLarge bool
+		if err := rows.Scan(&externalID, &tooLarge); err != nil {
+			return nil, fmt.Errorf("scan updated face: %w", err)
+		}
+		if tooLarge {
+			oversized = append(oversized, externalID)
+			continue
+		}
+		updated = append(updated, externalID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate updated faces: %w", err)
+	}
+
+	if len(oversized) > 0 {
+		return nil, domain.ErrMetadataTooLarge.WithError(
+			fmt.Errorf("merged metadata exceeds %d bytes for %s", maxBytes, strings.Join(oversized, ", ")),
+		)
+	}
+
+	return updated, nil
+}
+
 // CountByTenant returns the total number of faces for a tenant
 func (r *FaceRepository) CountByTenant(ctx context.Context, tenantID uuid.UUID) (int, error) {
 	query := `SELECT COUNT(*) FROM faces WHERE tenant_id = $1`
@@ -262,26 +460,46 @@ func (r *FaceRepository) CountByTenant(ctx context.Context, tenantID uuid.UUID)
 	return count, nil
 }
 
-// List returns all faces for a tenant with pagination
-func (r *FaceRepository) List(ctx context.Context, tenantID uuid.UUID, limit, offset int) ([]*domain.Face, error) {
-	if limit <= 0 {
-		limit = 50
+// List returns the tenant's faces registered in the filter's range, most recent first,
+// together with the total number of faces in that range
+func (r *FaceRepository) List(ctx context.Context, tenantID uuid.UUID, filter domain.FaceListFilter) ([]*domain.Face, int, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = domain.DefaultFaceListLimit
+	}
+	if filter.Limit > domain.MaxFaceListLimit {
+		filter.Limit = domain.MaxFaceListLimit
 	}
-	if limit > 100 {
-		limit = 100
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	var total int
+	err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM faces
+		WHERE tenant_id = $1
+		  AND ($2::timestamptz IS NULL OR created_at >= $2)
+		  AND ($3::timestamptz IS NULL OR created_at < $3)
+	`, tenantID, filter.RegisteredFrom, filter.RegisteredTo).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count faces: %w", err)
 	}
 
 	query := `
-		SELECT id, tenant_id, external_id, embedding, metadata, quality_score, created_at, updated_at
+		SELECT id, tenant_id, external_id, COALESCE(embedding, embedding_half::vector(512)),
+		       COALESCE(embedding_model, ''), COALESCE(embedding_version, ''),
+		       metadata, quality_score, expires_at, COALESCE(embedding_hash, ''), created_at, updated_at
 		FROM faces
 		WHERE tenant_id = $1
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
+		  AND ($2::timestamptz IS NULL OR created_at >= $2)
+		  AND ($3::timestamptz IS NULL OR created_at < $3)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $4 OFFSET $5
 	`
 
-	rows, err := r.pool.Query(ctx, query, tenantID, limit, offset)
+	rows, err := r.pool.Query(ctx, query, tenantID, filter.RegisteredFrom, filter.RegisteredTo, filter.Limit, filter.Offset)
 	if err != nil {
-		return nil, fmt.Errorf("list faces: %w", err)
+		return nil, 0, fmt.Errorf("list faces: %w", err)
 	}
 	defer rows.Close()
 
@@ -295,12 +513,16 @@ func (r *FaceRepository) List(ctx context.Context, tenantID uuid.UUID, limit, of
 			&face.TenantID,
 			&face.ExternalID,
 			&embedding,
+			&face.EmbeddingModel,
+			&face.EmbeddingVersion,
 			&face.Metadata,
 			&face.QualityScore,
+			&face.ExpiresAt,
+			&face.EmbeddingHash,
 			&face.CreatedAt,
 			&face.UpdatedAt,
 		); err != nil {
-			return nil, fmt.Errorf("scan face row: %w", err)
+			return nil, 0, fmt.Errorf("scan face row: %w", err)
 		}
 
 		if embedding != nil && embedding.Slice() != nil {
@@ -313,9 +535,141 @@ func (r *FaceRepository) List(ctx context.Context, tenantID uuid.UUID, limit, of
 		faces = append(faces, face)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate face rows: %w", err)
+	}
+
+	return faces, total, nil
+}
+
+// StreamAll calls fn with every face of the tenant in registration order, reading them in
+// pages so memory stays bounded however large the collection is. Only test or only live faces
+// are read, per isTest. Embeddings are only read when includeEmbeddings is set.
+func (r *FaceRepository) StreamAll(ctx context.Context, tenantID uuid.UUID, isTest, includeEmbeddings bool, fn func(*domain.Face) error) error {
+	query := `
+		SELECT id, external_id,
+		       CASE WHEN $4 THEN COALESCE(embedding, embedding_half::vector(512)) END,
+		       COALESCE(embedding_model, ''), COALESCE(embedding_version, ''),
+		       metadata, quality_score, is_test, expires_at, COALESCE(provider_face_id, ''),
+		       COALESCE(embedding_hash, ''), created_at, updated_at
+		FROM faces
+		WHERE tenant_id = $1 AND is_test = $6 AND (created_at, id) > ($2, $3)
+		ORDER BY created_at ASC, id ASC
+		LIMIT $5
+	`
+
+	// The cursor starts before every face
+	var afterCreatedAt time.Time
+	afterID := uuid.Nil
+	for {
+		batch, err := r.streamBatch(ctx, query, tenantID, afterCreatedAt, afterID, isTest, includeEmbeddings)
+		if err != nil {
+			return err
+		}
+
+		for _, face := range batch {
+			if err := fn(face); err != nil {
+				return err
+			}
+		}
+
+		if len(batch) < faceStreamBatchSize {
+			return nil
+		}
+		last := batch[len(batch)-1]
+		afterCreatedAt, afterID = last.CreatedAt, last.ID
+	}
+}
+
+// streamBatch reads one page of StreamAll; rows are closed before fn sees them
+func (r *FaceRepository) streamBatch(ctx context.Context, query string, tenantID uuid.UUID, afterCreatedAt time.Time, afterID uuid.UUID, isTest, includeEmbeddings bool) ([]*domain.Face, error) {
+	rows, err := r.pool.Query(database.WithQueryLabel(ctx, "faces.stream_all"), query, tenantID, afterCreatedAt, afterID, includeEmbeddings, faceStreamBatchSize, isTest)
+	if err != nil {
+		return nil, fmt.Errorf("stream faces: %w", err)
+	}
+	defer rows.Close()
+
+	batch := make([]*domain.Face, 0, faceStreamBatchSize)
+	for rows.Next() {
+		face := &domain.Face{TenantID: tenantID}
+		var embedding *pgvector.Vector
+		if err := rows.Scan(
+			&face.ID,
+			&face.ExternalID,
+			&embedding,
+			&face.EmbeddingModel,
+			&face.EmbeddingVersion,
+			&face.Metadata,
+			&face.QualityScore,
+			&face.IsTest,
+			&face.ExpiresAt,
+			&face.ProviderFaceID,
+			&face.EmbeddingHash,
+			&face.CreatedAt,
+			&face.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan face row: %w", err)
+		}
+
+		if embedding != nil && embedding.Slice() != nil {
+			face.Embedding = make([]float64, len(embedding.Slice()))
+			for i, v := range embedding.Slice() {
+				face.Embedding[i] = float64(v)
+			}
+		}
+		batch = append(batch, face)
+	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("iterate face rows: %w", err)
 	}
+	return batch, nil
+}
+
+// ListExpired returns up to limit faces of any tenant whose expires_at is at or before now,
+// oldest expiry first, with their provider face IDs. Embeddings are not loaded.
+func (r *FaceRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]*domain.Face, error) {
+	query := `
+		SELECT id, tenant_id, external_id, expires_at, is_test, COALESCE(provider_face_id, '')
+		FROM faces
+		WHERE expires_at <= $1
+		ORDER BY expires_at, id
+		LIMIT $2
+	`
+
+	rows, err := r.pool.Query(ctx, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list expired faces: %w", err)
+	}
+	defer rows.Close()
+
+	var faces []*domain.Face
+	for rows.Next() {
+		face := &domain.Face{}
+		if err := rows.Scan(&face.ID, &face.TenantID, &face.ExternalID, &face.ExpiresAt, &face.IsTest, &face.ProviderFaceID); err != nil {
+			return nil, fmt.Errorf("scan expired face row: %w", err)
+		}
+		faces = append(faces, face)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate expired face rows: %w", err)
+	}
 
 	return faces, nil
 }
+
+// DeleteExpired deletes the face if it is still expired at now, so a re-registration that
+// extended its expiry after it was listed keeps it. Reports whether the face was deleted.
+func (r *FaceRepository) DeleteExpired(ctx context.Context, id uuid.UUID, now time.Time) (bool, error) {
+	query := `
+		DELETE FROM faces
+		WHERE id = $1 AND expires_at <= $2
+	`
+
+	result, err := r.pool.Exec(ctx, query, id, now)
+	if err != nil {
+		return false, fmt.Errorf("delete expired face: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
@@ -46,7 +46,7 @@ func BenchmarkSearchByEmbedding(b *testing.B) {
 	b.ReportAllocs()
 
 	for i := 0; i < b.N; i++ {
-		_, err := repo.SearchByEmbedding(ctx, tenantID, embedding, threshold, limit)
+		_, err := repo.SearchByEmbedding(ctx, tenantID, embedding, domain.EmbeddingFingerprint{}, threshold, limit)
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -84,7 +84,7 @@ func BenchmarkSearchByEmbedding_10kFaces(b *testing.B) {
 	b.ReportAllocs()
 
 	for i := 0; i < b.N; i++ {
-		result, err := repo.SearchByEmbedding(ctx, tenantID, embedding, threshold, limit)
+		result, err := repo.SearchByEmbedding(ctx, tenantID, embedding, domain.EmbeddingFingerprint{}, threshold, limit)
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -125,7 +125,7 @@ func BenchmarkSearchByEmbedding_50kFaces(b *testing.B) {
 	b.ReportAllocs()
 
 	for i := 0; i < b.N; i++ {
-		result, err := repo.SearchByEmbedding(ctx, tenantID, embedding, threshold, limit)
+		result, err := repo.SearchByEmbedding(ctx, tenantID, embedding, domain.EmbeddingFingerprint{}, threshold, limit)
 		if err != nil {
 			b.Fatal(err)
 		}
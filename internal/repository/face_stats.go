@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// FaceStatsRefresher runs ANALYZE on faces once enough rows were inserted since the last run.
+// Planner statistics stay stale after bulk registration until autovacuum catches up, which
+// skews row estimates for the tenant filter and the embedding index on immediate searches.
+type FaceStatsRefresher struct {
+	pool      PgxPool
+	threshold int64
+	debounce  time.Duration
+	logger    *slog.Logger
+
+	pending atomic.Int64
+	trigger chan struct{}
+}
+
+// NewFaceStatsRefresher refreshes statistics after threshold inserts. Runs are at least
+// debounce apart, so consecutive batches are covered by a single ANALYZE.
+func NewFaceStatsRefresher(pool PgxPool, threshold int, debounce time.Duration, logger *slog.Logger) *FaceStatsRefresher {
+	return &FaceStatsRefresher{
+		pool:      pool,
+		threshold: int64(threshold),
+		debounce:  debounce,
+		logger:    logger,
+		trigger:   make(chan struct{}, 1),
+	}
+}
+
+// RecordInserts counts newly inserted faces and wakes Run once the threshold is reached
+func (r *FaceStatsRefresher) RecordInserts(n int) {
+	if r.pending.Add(int64(n)) < r.threshold {
+		return
+	}
+	select {
+	case r.trigger <- struct{}{}:
+	default:
+		// A refresh is already scheduled
+	}
+}
+
+// Run waits for the insert threshold, then for the debounce interval, and refreshes statistics
+func (r *FaceStatsRefresher) Run(ctx context.Context) {
+	r.logger.Info("face stats refresher started", "threshold", r.threshold, "debounce", r.debounce)
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Info("face stats refresher stopped")
+			return
+		case <-r.trigger:
+		}
+
+		// A trigger may be left over from inserts already covered by the previous run
+		if r.pending.Load() < r.threshold {
+			continue
+		}
+
+		timer := time.NewTimer(r.debounce)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			r.logger.Info("face stats refresher stopped")
+			return
+		case <-timer.C:
+		}
+
+		if err := r.Refresh(ctx); err != nil {
+			r.logger.Error("failed to refresh face statistics", "error", err)
+		}
+	}
+}
+
+// Refresh analyzes the faces table now. Inserts recorded meanwhile count toward the next run;
+// on failure the pending count is restored so the next insert retries.
+func (r *FaceStatsRefresher) Refresh(ctx context.Context) error {
+	inserted := r.pending.Swap(0)
+	start := time.Now()
+
+	if _, err := r.pool.Exec(ctx, `ANALYZE faces`); err != nil {
+		r.pending.Add(inserted)
+		return fmt.Errorf("analyze faces: %w", err)
+	}
+
+	r.logger.Debug("face statistics refreshed", "inserted", inserted, "duration", time.Since(start))
+	return nil
+}
@@ -28,6 +28,7 @@ type APIKeyRepositoryInterface interface {
 	ListByTenant(ctx context.Context, tenantID uuid.UUID) ([]domain.APIKey, error)
 	UpdateLastUsed(ctx context.Context, id uuid.UUID) error
 	Revoke(ctx context.Context, id uuid.UUID) error
+	RevokeByTenant(ctx context.Context, tenantID, id uuid.UUID) error
 	Delete(ctx context.Context, id uuid.UUID) error
 }
 
@@ -37,8 +38,11 @@ type FaceRepositoryInterface interface {
 	Update(ctx context.Context, face *domain.Face) error
 	GetByExternalID(ctx context.Context, tenantID uuid.UUID, externalID string) (*domain.Face, error)
 	Delete(ctx context.Context, tenantID uuid.UUID, externalID string) error
-	SearchByEmbedding(ctx context.Context, tenantID uuid.UUID, embedding []float64, threshold float64, limit int) ([]domain.SearchMatch, error)
+	SearchByEmbedding(ctx context.Context, tenantID uuid.UUID, embedding []float64, fingerprint domain.EmbeddingFingerprint, threshold float64, limit int) ([]domain.SearchMatch, error)
+	SearchByEmbeddingSegmented(ctx context.Context, tenantID uuid.UUID, embedding []float64, fingerprint domain.EmbeddingFingerprint, threshold float64, segments domain.SegmentThresholdSettings, limit int) ([]domain.SearchMatch, error)
 	CountByTenant(ctx context.Context, tenantID uuid.UUID) (int, error)
+	ExistsBatch(ctx context.Context, tenantID uuid.UUID, externalIDs []string) (map[string]domain.RegistrationCheck, error)
+	BulkUpdateMetadata(ctx context.Context, tenantID uuid.UUID, externalIDs []string, patch map[string]interface{}, maxBytes int) ([]string, error)
 }
 
 // SearchAuditRepositoryInterface defines operations for search audit logging
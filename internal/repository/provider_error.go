@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/audit"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/database"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+// ProviderErrorRepository stores PROVIDER_ERROR audit events so they can be grouped by code
+type ProviderErrorRepository struct {
+	pool PgxPool
+}
+
+func NewProviderErrorRepository(pool PgxPool) *ProviderErrorRepository {
+	return &ProviderErrorRepository{pool: pool}
+}
+
+// Log implements audit.Logger. Only PROVIDER_ERROR events are stored, others are ignored.
+func (r *ProviderErrorRepository) Log(ctx context.Context, event audit.Event) error {
+	if event.EventType != audit.EventProviderError {
+		return nil
+	}
+
+	query := `
+		INSERT INTO provider_errors (id, tenant_id, provider, operation, error_code, error, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+	// Providers shared by every tenant do not know the tenant of a call
+	var tenantID *uuid.UUID
+	if event.TenantID != uuid.Nil {
+		tenantID = &event.TenantID
+	}
+
+	_, err := r.pool.Exec(ctx, query,
+		event.ID,
+		tenantID,
+		event.Provider,
+		event.Metadata["operation"],
+		event.ErrorCode,
+		event.Error,
+		event.Timestamp,
+	)
+	if err != nil {
+		return fmt.Errorf("record provider error: %w", err)
+	}
+
+	return nil
+}
+
+// CountByCode groups the provider errors recorded in [start, end) by provider and error code,
+// most frequent first
+func (r *ProviderErrorRepository) CountByCode(ctx context.Context, start, end time.Time) ([]domain.ProviderErrorCount, error) {
+	query := `
+		SELECT provider, error_code, COUNT(*), MAX(created_at)
+		FROM provider_errors
+		WHERE created_at >= $1 AND created_at < $2
+		GROUP BY provider, error_code
+		ORDER BY COUNT(*) DESC, provider, error_code
+	`
+
+	rows, err := r.pool.Query(database.WithQueryLabel(ctx, "provider_errors.count_by_code"), query, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("count provider errors: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []domain.ProviderErrorCount{}
+	for rows.Next() {
+		var c domain.ProviderErrorCount
+		if err := rows.Scan(&c.Provider, &c.ErrorCode, &c.Count, &c.LastSeenAt); err != nil {
+			return nil, fmt.Errorf("scan provider error count: %w", err)
+		}
+		counts = append(counts, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate provider error counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// DeleteBefore deletes up to limit provider errors recorded before cutoff and returns how many
+// were deleted. Deleting in batches keeps each statement short on a large backlog.
+func (r *ProviderErrorRepository) DeleteBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	query := `
+		DELETE FROM provider_errors
+		WHERE id IN (
+			SELECT id FROM provider_errors
+			WHERE created_at < $1
+			LIMIT $2
+		)
+	`
+
+	result, err := r.pool.Exec(ctx, query, cutoff, limit)
+	if err != nil {
+		return 0, fmt.Errorf("delete old provider errors: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
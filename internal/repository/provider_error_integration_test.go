@@ -0,0 +1,84 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/audit"
+)
+
+func TestProviderErrorRepository_CountByCode_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	db, cleanup := setupIntegrationTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	_, err := db.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS provider_errors (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tenant_id UUID,
+			provider VARCHAR(50) NOT NULL,
+			operation VARCHAR(50) NOT NULL,
+			error_code VARCHAR(100) NOT NULL,
+			error TEXT,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)
+	`)
+	require.NoError(t, err)
+
+	repo := NewProviderErrorRepository(db)
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	seed := func(provider, code string, at time.Time, n int) {
+		for i := 0; i < n; i++ {
+			require.NoError(t, repo.Log(ctx, audit.Event{
+				Timestamp: at.Add(time.Duration(i) * time.Minute),
+				EventType: audit.EventProviderError,
+				Provider:  provider,
+				ErrorCode: code,
+				Error:     "provider failed",
+				Metadata:  map[string]string{"operation": "index_face"},
+			}))
+		}
+	}
+
+	seed("rekognition", "ThrottlingException", day.Add(10*time.Hour), 5)
+	seed("deepface", "HTTP_503", day.Add(11*time.Hour), 3)
+	seed("rekognition", "InternalServerError", day.Add(12*time.Hour), 1)
+	seed("deepface", "TIMEOUT", day.Add(13*time.Hour), 3)
+	// Outside the range on both ends
+	seed("rekognition", "ThrottlingException", day.Add(-time.Minute), 4)
+	seed("deepface", "HTTP_503", day.AddDate(0, 0, 1), 2)
+	// Other audit events are not stored
+	require.NoError(t, repo.Log(ctx, audit.Event{TenantID: uuid.New(), EventType: audit.EventTenantDeleted, Provider: "rekognition"}))
+
+	counts, err := repo.CountByCode(ctx, day, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+
+	require.Len(t, counts, 4)
+	assert.Equal(t, "rekognition", counts[0].Provider)
+	assert.Equal(t, "ThrottlingException", counts[0].ErrorCode)
+	assert.Equal(t, 5, counts[0].Count)
+	assert.True(t, counts[0].LastSeenAt.Equal(day.Add(10*time.Hour+4*time.Minute)))
+	// Equal counts are ordered by provider and code
+	assert.Equal(t, "deepface", counts[1].Provider)
+	assert.Equal(t, "HTTP_503", counts[1].ErrorCode)
+	assert.Equal(t, 3, counts[1].Count)
+	assert.Equal(t, "TIMEOUT", counts[2].ErrorCode)
+	assert.Equal(t, 3, counts[2].Count)
+	assert.Equal(t, "InternalServerError", counts[3].ErrorCode)
+	assert.Equal(t, 1, counts[3].Count)
+
+	empty, err := repo.CountByCode(ctx, day.AddDate(0, 0, 2), day.AddDate(0, 0, 3))
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
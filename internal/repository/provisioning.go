@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+// PgxTxPool extends PgxPool with transactions, compatible with pgxpool.Pool and pgxmock
+type PgxTxPool interface {
+	PgxPool
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// ProvisioningRepository binds pre-issued self-serve API keys to newly created tenants
+type ProvisioningRepository struct {
+	pool PgxTxPool
+}
+
+func NewProvisioningRepository(pool PgxTxPool) *ProvisioningRepository {
+	return &ProvisioningRepository{pool: pool}
+}
+
+// ProvisionForAPIKey creates tenant and binds the unbound auto-provision key to it in one
+// transaction. setup runs inside the transaction (e.g. to create the provider collection);
+// an error from it rolls everything back. The key row is locked first, so when a concurrent
+// request already bound the key, that tenant's ID is returned and tenant is not created.
+func (r *ProvisioningRepository) ProvisionForAPIKey(ctx context.Context, keyID uuid.UUID, tenant *domain.Tenant, setup func(ctx context.Context, tenant *domain.Tenant) error) (uuid.UUID, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("provision tenant: begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var (
+		boundTenantID *uuid.UUID
+		autoProvision bool
+	)
+	err = tx.QueryRow(ctx, `
+		SELECT tenant_id, auto_provision
+		FROM api_keys
+		WHERE id = $1 AND is_active = true
+		FOR UPDATE
+	`, keyID).Scan(&boundTenantID, &autoProvision)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, domain.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("provision tenant: lock api key: %w", err)
+	}
+
+	if boundTenantID != nil {
+		return *boundTenantID, tx.Commit(ctx)
+	}
+	if !autoProvision {
+		return uuid.Nil, fmt.Errorf("provision tenant: api key %s is not flagged for auto-provisioning", keyID)
+	}
+
+	if tenant.Settings == nil {
+		tenant.Settings = make(map[string]interface{})
+	}
+	err = tx.QueryRow(ctx, `
+		INSERT INTO tenants (id, name, slug, is_active, plan, settings, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		RETURNING created_at, updated_at
+	`, tenant.ID, tenant.Name, tenant.Slug, tenant.IsActive, tenant.Plan, tenant.Settings).Scan(&tenant.CreatedAt, &tenant.UpdatedAt)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("provision tenant: create tenant: %w", err)
+	}
+
+	if setup != nil {
+		if err := setup(ctx, tenant); err != nil {
+			return uuid.Nil, fmt.Errorf("provision tenant %s: setup: %w", tenant.ID, err)
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE api_keys
+		SET tenant_id = $2, auto_provision = false
+		WHERE id = $1
+	`, keyID, tenant.ID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("provision tenant %s: bind api key: %w", tenant.ID, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, fmt.Errorf("provision tenant %s: commit: %w", tenant.ID, err)
+	}
+
+	return tenant.ID, nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"testing"
 	"time"
 
@@ -14,6 +16,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/saturnino-fabrica-de-software/rekko/internal/audit"
 	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
 )
 
@@ -220,12 +223,110 @@ func TestTenantRepository_GetByID(t *testing.T) {
 	}
 }
 
+func TestTenantRepository_DeleteCascade(t *testing.T) {
+	tenantID := uuid.New()
+
+	t.Run("erases faces and the tenant in one transaction", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM faces WHERE tenant_id = \$1`).
+			WithArgs(tenantID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 12))
+		mock.ExpectExec(`DELETE FROM tenants WHERE id = \$1`).
+			WithArgs(tenantID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+
+		facesDeleted, err := NewTenantRepository(mock).DeleteCascade(context.Background(), tenantID)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(12), facesDeleted)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown tenant rolls back", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM faces`).
+			WithArgs(tenantID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 0))
+		mock.ExpectExec(`DELETE FROM tenants`).
+			WithArgs(tenantID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 0))
+		mock.ExpectRollback()
+
+		_, err = NewTenantRepository(mock).DeleteCascade(context.Background(), tenantID)
+
+		assert.ErrorIs(t, err, domain.ErrTenantNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// APIKeyRepository Tests
+
+func TestAPIKeyRepository_RevokeByTenant(t *testing.T) {
+	tenantID := uuid.New()
+	keyID := uuid.New()
+
+	tests := []struct {
+		name      string
+		mockSetup func(mock pgxmock.PgxPoolIface)
+		wantErr   error
+	}{
+		{
+			name: "revokes key of the tenant",
+			mockSetup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec(`UPDATE api_keys\s+SET is_active = false\s+WHERE id = \$1 AND tenant_id = \$2`).
+					WithArgs(keyID, tenantID).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+			},
+		},
+		{
+			name: "key of another tenant is not found",
+			mockSetup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec(`UPDATE api_keys`).
+					WithArgs(keyID, tenantID).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+			},
+			wantErr: domain.ErrAPIKeyNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+
+			tt.mockSetup(mock)
+
+			repo := NewAPIKeyRepository(mock)
+			err = repo.RevokeByTenant(context.Background(), tenantID, keyID)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
 // FaceRepository Tests
 
 func TestFaceRepository_Create(t *testing.T) {
 	tenantID := uuid.New()
 	faceID := uuid.New()
 	now := time.Now()
+	expiresAt := now.Add(7 * 24 * time.Hour)
 
 	tests := []struct {
 		name      string
@@ -236,16 +337,22 @@ func TestFaceRepository_Create(t *testing.T) {
 		{
 			name: "successful creation with embedding",
 			face: &domain.Face{
-				ID:           faceID,
-				TenantID:     tenantID,
-				ExternalID:   "user-123",
-				Embedding:    []float64{0.1, 0.2, 0.3},
-				Metadata:     map[string]interface{}{"source": "mobile"},
-				QualityScore: 0.95,
+				ID:               faceID,
+				TenantID:         tenantID,
+				ExternalID:       "user-123",
+				Embedding:        []float64{0.1, 0.2, 0.3},
+				EmbeddingModel:   "deepface/Facenet512",
+				EmbeddingVersion: "3d",
+				Metadata:         map[string]interface{}{"source": "mobile"},
+				QualityScore:     0.95,
+				IsTest:           true,
+				ExpiresAt:        &expiresAt,
+				ProviderFaceID:   "0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0",
+				EmbeddingHash:    "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
 			},
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
-				rows := pgxmock.NewRows([]string{"created_at", "updated_at"}).
-					AddRow(now, now)
+				rows := pgxmock.NewRows([]string{"created_at", "updated_at", "enrolled_at"}).
+					AddRow(now, now, now)
 
 				mock.ExpectQuery(`INSERT INTO faces`).
 					WithArgs(
@@ -253,8 +360,15 @@ func TestFaceRepository_Create(t *testing.T) {
 						tenantID,
 						"user-123",
 						pgxmock.AnyArg(),
+						"deepface/Facenet512",
+						"3d",
 						map[string]interface{}{"source": "mobile"},
 						0.95,
+						true,
+						&expiresAt,
+						"0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0",
+						"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+						nilArg{},
 					).
 					WillReturnRows(rows)
 			},
@@ -277,6 +391,13 @@ func TestFaceRepository_Create(t *testing.T) {
 						pgxmock.AnyArg(),
 						pgxmock.AnyArg(),
 						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
 					).
 					WillReturnError(errors.New("duplicate key value violates unique constraint (23505)"))
 			},
@@ -299,6 +420,13 @@ func TestFaceRepository_Create(t *testing.T) {
 						pgxmock.AnyArg(),
 						pgxmock.AnyArg(),
 						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
 					).
 					WillReturnError(errors.New("disk full"))
 			},
@@ -313,8 +441,8 @@ func TestFaceRepository_Create(t *testing.T) {
 				QualityScore: 0.8,
 			},
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
-				rows := pgxmock.NewRows([]string{"created_at", "updated_at"}).
-					AddRow(now, now)
+				rows := pgxmock.NewRows([]string{"created_at", "updated_at", "enrolled_at"}).
+					AddRow(now, now, now)
 
 				mock.ExpectQuery(`INSERT INTO faces`).
 					WithArgs(
@@ -322,8 +450,15 @@ func TestFaceRepository_Create(t *testing.T) {
 						tenantID,
 						"user-autoid",
 						pgxmock.AnyArg(),
+						"",
+						"",
 						pgxmock.AnyArg(),
 						0.8,
+						false,
+						pgxmock.AnyArg(),
+						"",
+						"",
+						pgxmock.AnyArg(),
 					).
 					WillReturnRows(rows)
 			},
@@ -361,10 +496,156 @@ func TestFaceRepository_Create(t *testing.T) {
 	}
 }
 
+// nilArg matches a nil argument, including typed nil pointers
+type nilArg struct{}
+
+func (nilArg) Match(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case *pgvector.Vector:
+		return v == nil
+	case *pgvector.HalfVector:
+		return v == nil
+	default:
+		return false
+	}
+}
+
+// halfVectorArg matches a half-precision vector argument holding want
+type halfVectorArg struct{ want []float32 }
+
+func (a halfVectorArg) Match(v interface{}) bool {
+	switch v := v.(type) {
+	case *pgvector.HalfVector:
+		return v != nil && assert.ObjectsAreEqual(a.want, v.Slice())
+	case pgvector.HalfVector:
+		return assert.ObjectsAreEqual(a.want, v.Slice())
+	default:
+		return false
+	}
+}
+
+func TestFaceRepository_Create_HalfPrecision(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	tenantID := uuid.New()
+	now := time.Now()
+	mock.ExpectQuery(`INSERT INTO faces \(.*embedding_hash, embedding_half, created_at, updated_at, enrolled_at\)`).
+		WithArgs(
+			pgxmock.AnyArg(), tenantID, "user-half", nilArg{},
+			pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(),
+			pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(),
+			halfVectorArg{want: []float32{0.25, -0.5, 0.75}},
+		).
+		WillReturnRows(pgxmock.NewRows([]string{"created_at", "updated_at", "enrolled_at"}).AddRow(now, now, now))
+
+	face := &domain.Face{
+		TenantID:           tenantID,
+		ExternalID:         "user-half",
+		Embedding:          []float64{0.25, -0.5, 0.75},
+		EmbeddingPrecision: domain.EmbeddingPrecisionHalf,
+	}
+	require.NoError(t, NewFaceRepository(mock).Create(context.Background(), face))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFaceRepository_SearchByEmbedding(t *testing.T) {
+	tenantID := uuid.New()
+	fingerprint := domain.NewEmbeddingFingerprint("deepface/Facenet512", 3)
+
+	t.Run("merges full and half precision candidates", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		halfID, fullID := uuid.New(), uuid.New()
+		rows := pgxmock.NewRows([]string{"id", "external_id", "metadata", "similarity"}).
+			AddRow(halfID, "user-half", map[string]interface{}{"zone": "vip"}, 0.97).
+			AddRow(fullID, "user-full", map[string]interface{}(nil), 0.91)
+		mock.ExpectQuery(`(?s)ORDER BY embedding <=> \$1.*UNION ALL.*ORDER BY embedding_half <=> \$7.*ORDER BY similarity DESC\s+LIMIT \$4`).
+			WithArgs(pgxmock.AnyArg(), tenantID, 0.8, 10, "deepface/Facenet512", "3d", halfVectorArg{want: []float32{0.25, -0.5, 0.75}}, false, "", []string{}, []float64{}, 0.8).
+			WillReturnRows(rows)
+
+		matches, err := NewFaceRepository(mock).SearchByEmbedding(context.Background(), tenantID, []float64{0.25, -0.5, 0.75}, fingerprint, 0.8, 10)
+		require.NoError(t, err)
+		require.Len(t, matches, 2)
+		assert.Equal(t, "user-half", matches[0].ExternalID)
+		assert.Equal(t, "vip", matches[0].Metadata["zone"])
+		assert.Equal(t, "user-full", matches[1].ExternalID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test keys only search test faces", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(`(?s)AND is_test = \$8.*UNION ALL.*AND is_test = \$8`).
+			WithArgs(pgxmock.AnyArg(), tenantID, 0.8, 10, "", "", pgxmock.AnyArg(), true, "", []string{}, []float64{}, 0.8).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "external_id", "metadata", "similarity"}))
+
+		ctx := domain.WithTestMode(context.Background())
+		_, err = NewFaceRepository(mock).SearchByEmbedding(ctx, tenantID, []float64{0.25, -0.5, 0.75}, domain.EmbeddingFingerprint{}, 0.8, 10)
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("no matches returns an empty slice", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(`UNION ALL`).
+			WithArgs(pgxmock.AnyArg(), tenantID, 0.8, 10, "deepface/Facenet512", "3d", pgxmock.AnyArg(), false, "", []string{}, []float64{}, 0.8).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "external_id", "metadata", "similarity"}))
+
+		matches, err := NewFaceRepository(mock).SearchByEmbedding(context.Background(), tenantID, []float64{0.25, -0.5, 0.75}, fingerprint, 0.8, 10)
+		require.NoError(t, err)
+		assert.NotNil(t, matches)
+		assert.Empty(t, matches)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(`UNION ALL`).
+			WithArgs(pgxmock.AnyArg(), tenantID, 0.8, 10, "deepface/Facenet512", "3d", pgxmock.AnyArg(), false, "", []string{}, []float64{}, 0.8).
+			WillReturnError(errors.New("connection reset"))
+
+		_, err = NewFaceRepository(mock).SearchByEmbedding(context.Background(), tenantID, []float64{0.25, -0.5, 0.75}, fingerprint, 0.8, 10)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "search faces by embedding")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("segments are held to their thresholds in the query", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		// The query floor is the lowest threshold, faces outside a segment need the resolved one
+		segments := domain.SegmentThresholdSettings{Key: "ticket_type", Search: map[string]float64{"guest": 0.7}}
+		mock.ExpectQuery(`(?s)unnest\(\$10::text\[\], \$11::float8\[\]\).*metadata ->> \$9::text\), \$12\).*UNION ALL`).
+			WithArgs(pgxmock.AnyArg(), tenantID, 0.7, 10, "deepface/Facenet512", "3d", pgxmock.AnyArg(), false,
+				"ticket_type", []string{"guest"}, []float64{0.7}, 0.85).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "external_id", "metadata", "similarity"}))
+
+		_, err = NewFaceRepository(mock).SearchByEmbeddingSegmented(context.Background(), tenantID, []float64{0.25, -0.5, 0.75}, fingerprint, 0.85, segments, 10)
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
 func TestFaceRepository_GetByExternalID(t *testing.T) {
 	tenantID := uuid.New()
 	faceID := uuid.New()
 	now := time.Now()
+	expiresAt := now.Add(7 * 24 * time.Hour)
 
 	tests := []struct {
 		name       string
@@ -381,31 +662,44 @@ func TestFaceRepository_GetByExternalID(t *testing.T) {
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
 				embedding := pgvector.NewVector([]float32{0.1, 0.2, 0.3})
 				rows := pgxmock.NewRows([]string{
-					"id", "tenant_id", "external_id", "embedding", "metadata", "quality_score", "created_at", "updated_at",
+					"id", "tenant_id", "external_id", "embedding", "embedding_model", "embedding_version", "metadata", "quality_score", "is_test", "expires_at", "provider_face_id", "embedding_hash", "created_at", "updated_at", "enrolled_at",
 				}).AddRow(
 					faceID,
 					tenantID,
 					"user-123",
 					&embedding,
+					"mock/sha256",
+					"3d",
 					map[string]interface{}{"source": "web"},
 					0.92,
+					false,
+					&expiresAt,
+					"0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0",
+					"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+					now,
 					now,
 					now,
 				)
 
-				mock.ExpectQuery(`SELECT id, tenant_id, external_id, embedding, metadata, quality_score, created_at, updated_at FROM faces WHERE tenant_id = \$1 AND external_id = \$2`).
+				mock.ExpectQuery(`SELECT id, tenant_id, external_id, COALESCE\(embedding, embedding_half::vector\(512\)\), COALESCE\(embedding_model, ''\), COALESCE\(embedding_version, ''\), metadata, quality_score, is_test, expires_at, COALESCE\(provider_face_id, ''\), COALESCE\(embedding_hash, ''\), created_at, updated_at, enrolled_at FROM faces WHERE tenant_id = \$1 AND external_id = \$2`).
 					WithArgs(tenantID, "user-123").
 					WillReturnRows(rows)
 			},
 			want: &domain.Face{
-				ID:           faceID,
-				TenantID:     tenantID,
-				ExternalID:   "user-123",
-				Embedding:    []float64{0.1, 0.2, 0.3},
-				Metadata:     map[string]interface{}{"source": "web"},
-				QualityScore: 0.92,
-				CreatedAt:    now,
-				UpdatedAt:    now,
+				ID:               faceID,
+				TenantID:         tenantID,
+				ExternalID:       "user-123",
+				Embedding:        []float64{0.1, 0.2, 0.3},
+				EmbeddingModel:   "mock/sha256",
+				EmbeddingVersion: "3d",
+				Metadata:         map[string]interface{}{"source": "web"},
+				QualityScore:     0.92,
+				ExpiresAt:        &expiresAt,
+				ProviderFaceID:   "0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0",
+				EmbeddingHash:    "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+				CreatedAt:        now,
+				UpdatedAt:        now,
+				EnrolledAt:       now,
 			},
 			wantErr: nil,
 		},
@@ -414,7 +708,7 @@ func TestFaceRepository_GetByExternalID(t *testing.T) {
 			tenantID:   tenantID,
 			externalID: "user-nonexistent",
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
-				mock.ExpectQuery(`SELECT id, tenant_id, external_id, embedding, metadata, quality_score, created_at, updated_at FROM faces WHERE tenant_id = \$1 AND external_id = \$2`).
+				mock.ExpectQuery(`SELECT id, tenant_id, external_id, COALESCE\(embedding, embedding_half::vector\(512\)\), COALESCE\(embedding_model, ''\), COALESCE\(embedding_version, ''\), metadata, quality_score, is_test, expires_at, COALESCE\(provider_face_id, ''\), COALESCE\(embedding_hash, ''\), created_at, updated_at, enrolled_at FROM faces WHERE tenant_id = \$1 AND external_id = \$2`).
 					WithArgs(tenantID, "user-nonexistent").
 					WillReturnError(pgx.ErrNoRows)
 			},
@@ -426,7 +720,7 @@ func TestFaceRepository_GetByExternalID(t *testing.T) {
 			tenantID:   tenantID,
 			externalID: "user-error",
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
-				mock.ExpectQuery(`SELECT id, tenant_id, external_id, embedding, metadata, quality_score, created_at, updated_at FROM faces WHERE tenant_id = \$1 AND external_id = \$2`).
+				mock.ExpectQuery(`SELECT id, tenant_id, external_id, COALESCE\(embedding, embedding_half::vector\(512\)\), COALESCE\(embedding_model, ''\), COALESCE\(embedding_version, ''\), metadata, quality_score, is_test, expires_at, COALESCE\(provider_face_id, ''\), COALESCE\(embedding_hash, ''\), created_at, updated_at, enrolled_at FROM faces WHERE tenant_id = \$1 AND external_id = \$2`).
 					WithArgs(tenantID, "user-error").
 					WillReturnError(errors.New("timeout"))
 			},
@@ -439,19 +733,26 @@ func TestFaceRepository_GetByExternalID(t *testing.T) {
 			externalID: "user-no-embedding",
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
 				rows := pgxmock.NewRows([]string{
-					"id", "tenant_id", "external_id", "embedding", "metadata", "quality_score", "created_at", "updated_at",
+					"id", "tenant_id", "external_id", "embedding", "embedding_model", "embedding_version", "metadata", "quality_score", "is_test", "expires_at", "provider_face_id", "embedding_hash", "created_at", "updated_at", "enrolled_at",
 				}).AddRow(
 					faceID,
 					tenantID,
 					"user-no-embedding",
 					nil,
+					"",
+					"",
 					nil,
 					0.0,
+					true,
+					nil,
+					"",
+					"",
+					now,
 					now,
 					now,
 				)
 
-				mock.ExpectQuery(`SELECT id, tenant_id, external_id, embedding, metadata, quality_score, created_at, updated_at FROM faces WHERE tenant_id = \$1 AND external_id = \$2`).
+				mock.ExpectQuery(`SELECT id, tenant_id, external_id, COALESCE\(embedding, embedding_half::vector\(512\)\), COALESCE\(embedding_model, ''\), COALESCE\(embedding_version, ''\), metadata, quality_score, is_test, expires_at, COALESCE\(provider_face_id, ''\), COALESCE\(embedding_hash, ''\), created_at, updated_at, enrolled_at FROM faces WHERE tenant_id = \$1 AND external_id = \$2`).
 					WithArgs(tenantID, "user-no-embedding").
 					WillReturnRows(rows)
 			},
@@ -464,6 +765,8 @@ func TestFaceRepository_GetByExternalID(t *testing.T) {
 				QualityScore: 0.0,
 				CreatedAt:    now,
 				UpdatedAt:    now,
+				EnrolledAt:   now,
+				IsTest:       true,
 			},
 			wantErr: nil,
 		},
@@ -494,6 +797,9 @@ func TestFaceRepository_GetByExternalID(t *testing.T) {
 				assert.Equal(t, tt.want.TenantID, got.TenantID)
 				assert.Equal(t, tt.want.ExternalID, got.ExternalID)
 				assert.Equal(t, tt.want.QualityScore, got.QualityScore)
+				assert.Equal(t, tt.want.ExpiresAt, got.ExpiresAt)
+				assert.Equal(t, tt.want.ProviderFaceID, got.ProviderFaceID)
+				assert.Equal(t, tt.want.EmbeddingHash, got.EmbeddingHash)
 
 				if tt.want.Embedding != nil {
 					require.NotNil(t, got.Embedding)
@@ -506,6 +812,192 @@ func TestFaceRepository_GetByExternalID(t *testing.T) {
 	}
 }
 
+func TestFaceRepository_Update(t *testing.T) {
+	tenantID := uuid.New()
+	faceID := uuid.New()
+	now := time.Now()
+
+	t.Run("stores the provider face id and embedding hash", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(`UPDATE faces SET .* provider_face_id = NULLIF\(\$9, ''\),\s+embedding_hash = NULLIF\(\$10, ''\), updated_at = NOW\(\), enrolled_at = NOW\(\) WHERE id = \$5 AND tenant_id = \$6 RETURNING updated_at, enrolled_at`).
+			WithArgs(
+				pgxmock.AnyArg(),
+				"",
+				"",
+				0.9,
+				faceID,
+				tenantID,
+				pgxmock.AnyArg(),
+				pgxmock.AnyArg(),
+				"0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0",
+				"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+				pgxmock.AnyArg(),
+			).
+			WillReturnRows(pgxmock.NewRows([]string{"updated_at", "enrolled_at"}).AddRow(now, now))
+
+		face := &domain.Face{
+			ID:             faceID,
+			TenantID:       tenantID,
+			ExternalID:     "user-123",
+			QualityScore:   0.9,
+			ProviderFaceID: "0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0",
+			EmbeddingHash:  "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+		}
+		require.NoError(t, NewFaceRepository(mock).Update(context.Background(), face))
+		assert.Equal(t, now, face.UpdatedAt)
+		assert.Equal(t, now, face.EnrolledAt, "re-registration re-enrolls the face")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(`UPDATE faces SET`).
+			WithArgs(
+				pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(),
+				pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(),
+				pgxmock.AnyArg(),
+			).
+			WillReturnError(errors.New("timeout"))
+
+		err = NewFaceRepository(mock).Update(context.Background(), &domain.Face{ID: faceID, TenantID: tenantID})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "update face")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestFaceRepository_ExistsBatch(t *testing.T) {
+	tenantID := uuid.New()
+	registeredAt := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	t.Run("mixed existing and missing ids", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		externalIDs := []string{"user-1", "user-2"}
+		rows := pgxmock.NewRows([]string{"external_id", "created_at"}).
+			AddRow("user-1", registeredAt)
+		mock.ExpectQuery(`SELECT external_id, created_at FROM faces WHERE tenant_id = \$1 AND external_id = ANY\(\$2\)`).
+			WithArgs(tenantID, externalIDs).
+			WillReturnRows(rows)
+
+		repo := NewFaceRepository(mock)
+		got, err := repo.ExistsBatch(context.Background(), tenantID, externalIDs)
+
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.True(t, got["user-1"].Registered)
+		require.NotNil(t, got["user-1"].RegisteredAt)
+		assert.Equal(t, registeredAt, *got["user-1"].RegisteredAt)
+		assert.False(t, got["user-2"].Registered)
+		assert.Nil(t, got["user-2"].RegisteredAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(`SELECT external_id, created_at FROM faces`).
+			WithArgs(tenantID, []string{"user-1"}).
+			WillReturnError(errors.New("connection refused"))
+
+		repo := NewFaceRepository(mock)
+		_, err = repo.ExistsBatch(context.Background(), tenantID, []string{"user-1"})
+
+		require.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestFaceRepository_BulkUpdateMetadata(t *testing.T) {
+	tenantID := uuid.New()
+	patch := map[string]interface{}{"event": "rock-in-rio"}
+	const maxBytes = 16 * 1024
+
+	t.Run("returns updated ids", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		externalIDs := []string{"user-1", "user-2", "user-3"}
+		rows := pgxmock.NewRows([]string{"external_id", "oversized"}).
+			AddRow("user-1", false).
+			AddRow("user-3", false)
+		mock.ExpectQuery(`COALESCE\(metadata, '\{\}'::jsonb\) \|\| \$3 AS merged FROM faces WHERE tenant_id = \$1 AND external_id = ANY\(\$2\) .* WHERE octet_length\(merged::text\) > \$4 .* SET metadata = t.merged, updated_at = NOW\(\) FROM targets t WHERE f.id = t.id AND NOT EXISTS \(SELECT 1 FROM oversized\)`).
+			WithArgs(tenantID, externalIDs, patch, maxBytes).
+			WillReturnRows(rows)
+
+		repo := NewFaceRepository(mock)
+		updated, err := repo.BulkUpdateMetadata(context.Background(), tenantID, externalIDs, patch, maxBytes)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"user-1", "user-3"}, updated)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("no matching faces", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(`WITH targets AS`).
+			WithArgs(tenantID, []string{"user-9"}, patch, maxBytes).
+			WillReturnRows(pgxmock.NewRows([]string{"external_id", "oversized"}))
+
+		repo := NewFaceRepository(mock)
+		updated, err := repo.BulkUpdateMetadata(context.Background(), tenantID, []string{"user-9"}, patch, maxBytes)
+
+		require.NoError(t, err)
+		assert.Empty(t, updated)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("merged metadata over the cap", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(`WITH targets AS`).
+			WithArgs(tenantID, []string{"user-1", "user-2"}, patch, maxBytes).
+			WillReturnRows(pgxmock.NewRows([]string{"external_id", "oversized"}).AddRow("user-2", true))
+
+		repo := NewFaceRepository(mock)
+		updated, err := repo.BulkUpdateMetadata(context.Background(), tenantID, []string{"user-1", "user-2"}, patch, maxBytes)
+
+		var appErr *domain.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, domain.ErrMetadataTooLarge.Code, appErr.Code)
+		assert.Contains(t, err.Error(), "user-2")
+		assert.Nil(t, updated)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(`WITH targets AS`).
+			WithArgs(tenantID, []string{"user-1"}, patch, maxBytes).
+			WillReturnError(errors.New("connection refused"))
+
+		repo := NewFaceRepository(mock)
+		_, err = repo.BulkUpdateMetadata(context.Background(), tenantID, []string{"user-1"}, patch, maxBytes)
+
+		require.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
 func TestFaceRepository_Delete(t *testing.T) {
 	tenantID := uuid.New()
 
@@ -578,6 +1070,300 @@ func TestFaceRepository_Delete(t *testing.T) {
 	}
 }
 
+func TestFaceRepository_List(t *testing.T) {
+	tenantID := uuid.New()
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)
+	columns := []string{
+		"id", "tenant_id", "external_id", "embedding", "embedding_model", "embedding_version",
+		"metadata", "quality_score", "expires_at", "embedding_hash", "created_at", "updated_at",
+	}
+
+	t.Run("filters by registration range and paginates", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		faceID := uuid.New()
+		createdAt := from.Add(48 * time.Hour)
+		expiresAt := createdAt.Add(7 * 24 * time.Hour)
+		embedding := pgvector.NewVector([]float32{0.1, 0.2})
+
+		mock.ExpectQuery(`SELECT COUNT\(\*\)\s+FROM faces\s+WHERE tenant_id = \$1\s+AND \(\$2::timestamptz IS NULL OR created_at >= \$2\)\s+AND \(\$3::timestamptz IS NULL OR created_at < \$3\)`).
+			WithArgs(tenantID, &from, &to).
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(12))
+		mock.ExpectQuery(`FROM faces\s+WHERE tenant_id = \$1\s+AND \(\$2::timestamptz IS NULL OR created_at >= \$2\)\s+AND \(\$3::timestamptz IS NULL OR created_at < \$3\)\s+ORDER BY created_at DESC, id DESC\s+LIMIT \$4 OFFSET \$5`).
+			WithArgs(tenantID, &from, &to, 2, 4).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(faceID, tenantID, "user-123", &embedding, "mock/sha256", "3d", map[string]interface{}{"ticket": "A1"}, 0.92, &expiresAt, "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", createdAt, createdAt).
+				AddRow(uuid.New(), tenantID, "user-456", nil, "", "", map[string]interface{}{}, 0.88, nil, "", createdAt.Add(-time.Hour), createdAt.Add(-time.Hour)))
+
+		repo := NewFaceRepository(mock)
+		faces, total, err := repo.List(context.Background(), tenantID, domain.FaceListFilter{
+			RegisteredFrom: &from,
+			RegisteredTo:   &to,
+			Limit:          2,
+			Offset:         4,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 12, total)
+		require.Len(t, faces, 2)
+		assert.Equal(t, faceID, faces[0].ID)
+		assert.Equal(t, "user-123", faces[0].ExternalID)
+		assert.Equal(t, createdAt, faces[0].CreatedAt)
+		assert.Len(t, faces[0].Embedding, 2)
+		assert.Equal(t, &expiresAt, faces[0].ExpiresAt)
+		assert.Equal(t, "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", faces[0].EmbeddingHash)
+		assert.Nil(t, faces[1].Embedding)
+		assert.Nil(t, faces[1].ExpiresAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("open range passes nil bounds", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		var unbounded *time.Time
+		mock.ExpectQuery(`SELECT COUNT\(\*\)\s+FROM faces`).
+			WithArgs(tenantID, unbounded, &to).
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectQuery(`FROM faces`).
+			WithArgs(tenantID, unbounded, &to, domain.DefaultFaceListLimit, 0).
+			WillReturnRows(pgxmock.NewRows(columns))
+
+		repo := NewFaceRepository(mock)
+		faces, total, err := repo.List(context.Background(), tenantID, domain.FaceListFilter{RegisteredTo: &to})
+
+		require.NoError(t, err)
+		assert.Empty(t, faces)
+		assert.Zero(t, total)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	limits := []struct {
+		name       string
+		filter     domain.FaceListFilter
+		wantLimit  int
+		wantOffset int
+	}{
+		{"caps limit", domain.FaceListFilter{Limit: 1000}, domain.MaxFaceListLimit, 0},
+		{"clamps negative offset", domain.FaceListFilter{Limit: 10, Offset: -5}, 10, 0},
+	}
+
+	for _, tt := range limits {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+
+			var unbounded *time.Time
+			mock.ExpectQuery(`SELECT COUNT\(\*\)`).
+				WithArgs(tenantID, unbounded, unbounded).
+				WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(0))
+			mock.ExpectQuery(`FROM faces`).
+				WithArgs(tenantID, unbounded, unbounded, tt.wantLimit, tt.wantOffset).
+				WillReturnRows(pgxmock.NewRows(columns))
+
+			repo := NewFaceRepository(mock)
+			_, _, err = repo.List(context.Background(), tenantID, tt.filter)
+
+			require.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Run("count error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(`SELECT COUNT\(\*\)`).
+			WithArgs(tenantID, &from, &to).
+			WillReturnError(errors.New("connection refused"))
+
+		repo := NewFaceRepository(mock)
+		_, _, err = repo.List(context.Background(), tenantID, domain.FaceListFilter{RegisteredFrom: &from, RegisteredTo: &to})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "count faces")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestFaceRepository_ListExpired(t *testing.T) {
+	now := time.Date(2026, 3, 8, 18, 0, 0, 0, time.UTC)
+
+	t.Run("lists faces expired at now, oldest expiry first", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		tenantID, faceID := uuid.New(), uuid.New()
+		expiresAt := now.Add(-time.Hour)
+
+		mock.ExpectQuery(`SELECT id, tenant_id, external_id, expires_at, is_test, COALESCE\(provider_face_id, ''\)\s+FROM faces\s+WHERE expires_at <= \$1\s+ORDER BY expires_at, id\s+LIMIT \$2`).
+			WithArgs(now, 50).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "tenant_id", "external_id", "expires_at", "is_test", "provider_face_id"}).
+				AddRow(faceID, tenantID, "guest-1", &expiresAt, false, "rk-face-1"))
+
+		repo := NewFaceRepository(mock)
+		faces, err := repo.ListExpired(context.Background(), now, 50)
+
+		require.NoError(t, err)
+		require.Len(t, faces, 1)
+		assert.Equal(t, faceID, faces[0].ID)
+		assert.Equal(t, tenantID, faces[0].TenantID)
+		assert.Equal(t, "guest-1", faces[0].ExternalID)
+		assert.Equal(t, &expiresAt, faces[0].ExpiresAt)
+		assert.Equal(t, "rk-face-1", faces[0].ProviderFaceID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(`FROM faces`).
+			WithArgs(now, 50).
+			WillReturnError(errors.New("timeout"))
+
+		repo := NewFaceRepository(mock)
+		_, err = repo.ListExpired(context.Background(), now, 50)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "list expired faces")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestFaceRepository_DeleteExpired(t *testing.T) {
+	now := time.Date(2026, 3, 8, 18, 0, 0, 0, time.UTC)
+	faceID := uuid.New()
+
+	tests := []struct {
+		name        string
+		affected    int64
+		wantDeleted bool
+	}{
+		{"deletes a face still expired", 1, true},
+		{"keeps a face whose expiry moved meanwhile", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+
+			mock.ExpectExec(`DELETE FROM faces\s+WHERE id = \$1 AND expires_at <= \$2`).
+				WithArgs(faceID, now).
+				WillReturnResult(pgxmock.NewResult("DELETE", tt.affected))
+
+			repo := NewFaceRepository(mock)
+			deleted, err := repo.DeleteExpired(context.Background(), faceID, now)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantDeleted, deleted)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestFaceRepository_StreamAll(t *testing.T) {
+	tenantID := uuid.New()
+	createdAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "external_id", "embedding", "embedding_model", "embedding_version", "metadata",
+		"quality_score", "is_test", "expires_at", "provider_face_id", "embedding_hash", "created_at", "updated_at"}
+
+	t.Run("pages through the collection by keyset", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		// A full first page makes the stream ask for the faces after its last one
+		firstPage := pgxmock.NewRows(columns)
+		var lastID uuid.UUID
+		var lastCreatedAt time.Time
+		for i := 0; i < faceStreamBatchSize; i++ {
+			lastID, lastCreatedAt = uuid.New(), createdAt.Add(time.Duration(i)*time.Second)
+			firstPage.AddRow(lastID, fmt.Sprintf("user-%d", i), nil, "deepface/Facenet512", "512d",
+				map[string]interface{}(nil), 0.9, false, nil, "", "", lastCreatedAt, lastCreatedAt)
+		}
+		secondID := uuid.New()
+		embedding := pgvector.NewVector([]float32{0.25, 0.5})
+
+		mock.ExpectQuery(`SELECT id, external_id,\s+CASE WHEN \$4 THEN COALESCE\(embedding, embedding_half::vector\(512\)\) END,.+FROM faces\s+WHERE tenant_id = \$1 AND is_test = \$6 AND \(created_at, id\) > \(\$2, \$3\)\s+ORDER BY created_at ASC, id ASC\s+LIMIT \$5`).
+			WithArgs(tenantID, time.Time{}, uuid.Nil, true, faceStreamBatchSize, false).
+			WillReturnRows(firstPage)
+		mock.ExpectQuery(`FROM faces`).
+			WithArgs(tenantID, lastCreatedAt, lastID, true, faceStreamBatchSize, false).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(secondID, "user-last", &embedding, "deepface/Facenet512", "512d",
+					map[string]interface{}{"name": "Ana"}, 0.95, false, nil, "rk-face-1", "abc123", lastCreatedAt.Add(time.Second), lastCreatedAt.Add(time.Second)))
+
+		repo := NewFaceRepository(mock)
+		var streamed []*domain.Face
+		err = repo.StreamAll(context.Background(), tenantID, false, true, func(face *domain.Face) error {
+			streamed = append(streamed, face)
+			return nil
+		})
+
+		require.NoError(t, err)
+		require.Len(t, streamed, faceStreamBatchSize+1)
+		last := streamed[len(streamed)-1]
+		assert.Equal(t, secondID, last.ID)
+		assert.Equal(t, tenantID, last.TenantID)
+		assert.Equal(t, "rk-face-1", last.ProviderFaceID)
+		assert.Equal(t, []float64{0.25, 0.5}, last.Embedding)
+		assert.Nil(t, streamed[0].Embedding)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("callback error stops the stream", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(`FROM faces`).
+			WithArgs(tenantID, time.Time{}, uuid.Nil, false, faceStreamBatchSize, true).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(uuid.New(), "user-1", nil, "", "", map[string]interface{}(nil), 0.9, true, nil, "", "", createdAt, createdAt).
+				AddRow(uuid.New(), "user-2", nil, "", "", map[string]interface{}(nil), 0.9, false, nil, "", "", createdAt, createdAt))
+
+		repo := NewFaceRepository(mock)
+		stop := errors.New("client went away")
+		calls := 0
+		err = repo.StreamAll(context.Background(), tenantID, true, false, func(face *domain.Face) error {
+			calls++
+			return stop
+		})
+
+		assert.ErrorIs(t, err, stop)
+		assert.Equal(t, 1, calls)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(`FROM faces`).
+			WillReturnError(errors.New("database unavailable"))
+
+		repo := NewFaceRepository(mock)
+		err = repo.StreamAll(context.Background(), tenantID, false, false, func(face *domain.Face) error {
+			return nil
+		})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "stream faces")
+	})
+}
+
 // VerificationRepository Tests
 
 func TestVerificationRepository_Create(t *testing.T) {
@@ -586,6 +1372,7 @@ func TestVerificationRepository_Create(t *testing.T) {
 	verificationID := uuid.New()
 	now := time.Now()
 	livenessPassed := true
+	livenessScore := 0.97
 
 	tests := []struct {
 		name         string
@@ -603,7 +1390,15 @@ func TestVerificationRepository_Create(t *testing.T) {
 				Verified:       true,
 				Confidence:     0.95,
 				LivenessPassed: &livenessPassed,
+				LivenessScore:  &livenessScore,
 				LatencyMs:      150,
+				ClientIP:       "10.0.0.7",
+				Provider:       "deepface",
+
+				ProviderVariant:  domain.ProviderVariantAlternate,
+				ThresholdApplied: 0.8,
+				ThresholdSource:  domain.ThresholdSourceTenant,
+				HysteresisPass:   true,
 			},
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
 				rows := pgxmock.NewRows([]string{"created_at"}).
@@ -618,7 +1413,16 @@ func TestVerificationRepository_Create(t *testing.T) {
 						true,
 						0.95,
 						&livenessPassed,
+						&livenessScore,
 						int64(150),
+						false,
+						"10.0.0.7",
+						"",
+						"deepface",
+						domain.ProviderVariantAlternate,
+						0.8,
+						"tenant",
+						true,
 					).
 					WillReturnRows(rows)
 			},
@@ -635,6 +1439,7 @@ func TestVerificationRepository_Create(t *testing.T) {
 				Confidence:     0.3,
 				LivenessPassed: nil,
 				LatencyMs:      200,
+				FailReason:     domain.FailReasonMatchBelowThreshold,
 			},
 			mockSetup: func(mock pgxmock.PgxPoolIface) {
 				rows := pgxmock.NewRows([]string{"created_at"}).
@@ -649,7 +1454,16 @@ func TestVerificationRepository_Create(t *testing.T) {
 						false,
 						0.3,
 						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
 						int64(200),
+						false,
+						"",
+						domain.FailReasonMatchBelowThreshold,
+						"",
+						"",
+						0.0,
+						"",
+						false,
 					).
 					WillReturnRows(rows)
 			},
@@ -678,7 +1492,16 @@ func TestVerificationRepository_Create(t *testing.T) {
 						true,
 						0.88,
 						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
 						int64(120),
+						false,
+						"",
+						"",
+						"",
+						"",
+						0.0,
+						"",
+						false,
 					).
 					WillReturnRows(rows)
 			},
@@ -705,6 +1528,15 @@ func TestVerificationRepository_Create(t *testing.T) {
 						pgxmock.AnyArg(),
 						pgxmock.AnyArg(),
 						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						pgxmock.AnyArg(),
+						false,
 					).
 					WillReturnError(errors.New("database unavailable"))
 			},
@@ -737,6 +1569,741 @@ func TestVerificationRepository_Create(t *testing.T) {
 	}
 }
 
+func TestVerificationRepository_RecentConfidences(t *testing.T) {
+	tenantID := uuid.New()
+	since := time.Now().Add(-24 * time.Hour)
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	mock.ExpectQuery(`SELECT confidence\s+FROM verifications`).
+		WithArgs(tenantID, "user-123", since, 4).
+		WillReturnRows(pgxmock.NewRows([]string{"confidence"}).AddRow(0.82).AddRow(0.84))
+
+	repo := NewVerificationRepository(mock)
+	confidences, err := repo.RecentConfidences(context.Background(), tenantID, "user-123", since, 4)
+
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0.82, 0.84}, confidences)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestVerificationRepository_LastVerifiedAt(t *testing.T) {
+	tenantID := uuid.New()
+
+	t.Run("returns latest successful verification", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		verifiedAt := time.Now().Add(-5 * time.Minute)
+		mock.ExpectQuery(`SELECT created_at\s+FROM verifications\s+WHERE tenant_id = \$1 AND external_id = \$2 AND verified = true\s+AND \(NOT \$3 OR liveness_passed IS TRUE\)\s+ORDER BY created_at DESC\s+LIMIT 1`).
+			WithArgs(tenantID, "user-123", false).
+			WillReturnRows(pgxmock.NewRows([]string{"created_at"}).AddRow(verifiedAt))
+
+		repo := NewVerificationRepository(mock)
+		got, err := repo.LastVerifiedAt(context.Background(), tenantID, "user-123", false)
+
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, verifiedAt, *got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("liveness required skips non-live decisions", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(`AND \(NOT \$3 OR liveness_passed IS TRUE\)`).
+			WithArgs(tenantID, "user-123", true).
+			WillReturnError(pgx.ErrNoRows)
+
+		repo := NewVerificationRepository(mock)
+		got, err := repo.LastVerifiedAt(context.Background(), tenantID, "user-123", true)
+
+		require.NoError(t, err)
+		assert.Nil(t, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("never verified", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(`SELECT created_at\s+FROM verifications`).
+			WithArgs(tenantID, "user-123", false).
+			WillReturnError(pgx.ErrNoRows)
+
+		repo := NewVerificationRepository(mock)
+		got, err := repo.LastVerifiedAt(context.Background(), tenantID, "user-123", false)
+
+		require.NoError(t, err)
+		assert.Nil(t, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(`SELECT created_at\s+FROM verifications`).
+			WillReturnError(errors.New("database unavailable"))
+
+		repo := NewVerificationRepository(mock)
+		_, err = repo.LastVerifiedAt(context.Background(), tenantID, "user-123", false)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "last verified at")
+	})
+}
+
+func TestVerificationRepository_GetByID(t *testing.T) {
+	tenantID := uuid.New()
+	verificationID := uuid.New()
+	faceID := uuid.New()
+	columns := []string{
+		"id", "face_id", "external_id", "verified", "confidence", "liveness_passed", "liveness_score",
+		"latency_ms", "is_test", "client_ip", "fail_reason", "provider", "provider_variant",
+		"threshold_applied", "threshold_source", "created_at", "metadata",
+	}
+
+	t.Run("found with face metadata", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		createdAt := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+		livenessPassed, livenessScore := false, 0.42
+		mock.ExpectQuery(`FROM verifications v\s+LEFT JOIN faces f ON f.id = v.face_id AND f.tenant_id = v.tenant_id\s+WHERE v.tenant_id = \$1 AND v.id = \$2`).
+			WithArgs(tenantID, verificationID).
+			WillReturnRows(pgxmock.NewRows(columns).AddRow(
+				verificationID, &faceID, "user-123", false, 0.91, &livenessPassed, &livenessScore,
+				int64(180), false, "10.0.0.7", domain.FailReasonLivenessFailed, "deepface", "",
+				0.85, "segment", createdAt, map[string]interface{}{"ticket_type": "staff"},
+			))
+
+		repo := NewVerificationRepository(mock)
+		got, err := repo.GetByID(context.Background(), tenantID, verificationID)
+
+		require.NoError(t, err)
+		assert.Equal(t, verificationID, got.ID)
+		assert.Equal(t, tenantID, got.TenantID)
+		assert.Equal(t, &faceID, got.FaceID)
+		assert.Equal(t, 0.91, got.Confidence)
+		assert.Equal(t, 0.42, *got.LivenessScore)
+		assert.Equal(t, "10.0.0.7", got.ClientIP)
+		assert.Equal(t, domain.FailReasonLivenessFailed, got.FailReason)
+		assert.Equal(t, "deepface", got.Provider)
+		assert.Equal(t, 0.85, got.ThresholdApplied)
+		assert.Equal(t, domain.ThresholdSourceSegment, got.ThresholdSource)
+		assert.Equal(t, map[string]interface{}{"ticket_type": "staff"}, got.FaceMetadata)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(`FROM verifications v`).
+			WithArgs(tenantID, verificationID).
+			WillReturnError(pgx.ErrNoRows)
+
+		repo := NewVerificationRepository(mock)
+		got, err := repo.GetByID(context.Background(), tenantID, verificationID)
+
+		assert.ErrorIs(t, err, domain.ErrVerificationNotFound)
+		assert.Nil(t, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(`FROM verifications v`).
+			WillReturnError(errors.New("database unavailable"))
+
+		repo := NewVerificationRepository(mock)
+		_, err = repo.GetByID(context.Background(), tenantID, verificationID)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "get verification")
+	})
+}
+
+func TestVerificationRepository_LastVerifiedGate(t *testing.T) {
+	tenantID := uuid.New()
+
+	t.Run("returns gate of latest successful verification", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		verifiedAt := time.Now().Add(-2 * time.Minute)
+		mock.ExpectQuery(`SELECT COALESCE\(host\(client_ip\), ''\), created_at\s+FROM verifications\s+WHERE tenant_id = \$1 AND external_id = \$2 AND verified = true AND is_test = \$3\s+AND \(NOT \$4 OR NOT hysteresis_pass\)\s+ORDER BY created_at DESC\s+LIMIT 1`).
+			WithArgs(tenantID, "user-123", false, true).
+			WillReturnRows(pgxmock.NewRows([]string{"gate", "created_at"}).AddRow("10.0.0.11", verifiedAt))
+
+		repo := NewVerificationRepository(mock)
+		got, err := repo.LastVerifiedGate(context.Background(), tenantID, "user-123", false, true)
+
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, "10.0.0.11", got.Gate)
+		assert.Equal(t, verifiedAt, got.At)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("never verified", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(`FROM verifications`).
+			WithArgs(tenantID, "user-123", true, false).
+			WillReturnError(pgx.ErrNoRows)
+
+		repo := NewVerificationRepository(mock)
+		got, err := repo.LastVerifiedGate(context.Background(), tenantID, "user-123", true, false)
+
+		require.NoError(t, err)
+		assert.Nil(t, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(`FROM verifications`).
+			WillReturnError(errors.New("database unavailable"))
+
+		repo := NewVerificationRepository(mock)
+		_, err = repo.LastVerifiedGate(context.Background(), tenantID, "user-123", false, false)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "last verified gate")
+	})
+}
+
+func TestVerificationRepository_StreamRange(t *testing.T) {
+	tenantID := uuid.New()
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "external_id", "verified", "confidence", "liveness_passed", "latency_ms", "created_at"}
+
+	t.Run("pages through the range by keyset", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		// A full first page makes the stream ask for the rows after its last one
+		firstPage := pgxmock.NewRows(columns)
+		var lastID uuid.UUID
+		var lastCreatedAt time.Time
+		for i := 0; i < verificationStreamBatchSize; i++ {
+			lastID, lastCreatedAt = uuid.New(), from.Add(time.Duration(i)*time.Second)
+			firstPage.AddRow(lastID, "user-123", true, 0.95, nil, int64(80), lastCreatedAt)
+		}
+		live := false
+		secondID := uuid.New()
+
+		mock.ExpectQuery(`SELECT id, external_id, verified, confidence, liveness_passed, latency_ms, created_at\s+FROM verifications\s+WHERE tenant_id = \$1 AND created_at >= \$2 AND created_at < \$3\s+AND \(created_at, id\) > \(\$4, \$5\)\s+ORDER BY created_at ASC, id ASC\s+LIMIT \$6`).
+			WithArgs(tenantID, from, to, from, uuid.Nil, verificationStreamBatchSize).
+			WillReturnRows(firstPage)
+		mock.ExpectQuery(`FROM verifications`).
+			WithArgs(tenantID, from, to, lastCreatedAt, lastID, verificationStreamBatchSize).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(secondID, "user-456", false, 0.42, &live, int64(95), lastCreatedAt.Add(time.Second)))
+
+		repo := NewVerificationRepository(mock)
+		var streamed []*domain.Verification
+		err = repo.StreamRange(context.Background(), tenantID, from, to, func(v *domain.Verification) error {
+			streamed = append(streamed, v)
+			return nil
+		})
+
+		require.NoError(t, err)
+		require.Len(t, streamed, verificationStreamBatchSize+1)
+		last := streamed[len(streamed)-1]
+		assert.Equal(t, secondID, last.ID)
+		assert.Equal(t, tenantID, last.TenantID)
+		assert.Equal(t, "user-456", last.ExternalID)
+		require.NotNil(t, last.LivenessPassed)
+		assert.False(t, *last.LivenessPassed)
+		assert.Nil(t, streamed[0].LivenessPassed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("callback error stops the stream", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(`FROM verifications`).
+			WithArgs(tenantID, from, to, from, uuid.Nil, verificationStreamBatchSize).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(uuid.New(), "user-123", true, 0.95, nil, int64(80), from).
+				AddRow(uuid.New(), "user-456", true, 0.91, nil, int64(70), from.Add(time.Second)))
+
+		repo := NewVerificationRepository(mock)
+		stop := errors.New("client went away")
+		calls := 0
+		err = repo.StreamRange(context.Background(), tenantID, from, to, func(v *domain.Verification) error {
+			calls++
+			return stop
+		})
+
+		assert.ErrorIs(t, err, stop)
+		assert.Equal(t, 1, calls)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(`FROM verifications`).
+			WillReturnError(errors.New("database unavailable"))
+
+		repo := NewVerificationRepository(mock)
+		err = repo.StreamRange(context.Background(), tenantID, from, to, func(v *domain.Verification) error {
+			return nil
+		})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "stream verifications")
+	})
+}
+
+func TestProviderErrorRepository_Log(t *testing.T) {
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("stores provider errors", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		eventID := uuid.New()
+		mock.ExpectExec(`INSERT INTO provider_errors`).
+			WithArgs(eventID, (*uuid.UUID)(nil), "deepface", "index_face", "HTTP_503", "deepface returned status 503", at).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		repo := NewProviderErrorRepository(mock)
+		err = repo.Log(context.Background(), audit.Event{
+			ID:        eventID,
+			Timestamp: at,
+			EventType: audit.EventProviderError,
+			Provider:  "deepface",
+			Error:     "deepface returned status 503",
+			ErrorCode: "HTTP_503",
+			Metadata:  map[string]string{"operation": "index_face"},
+		})
+
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ignores other audit events", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		repo := NewProviderErrorRepository(mock)
+		err = repo.Log(context.Background(), audit.Event{EventType: audit.EventFaceDeleted, Success: false, Error: "not found"})
+
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProviderErrorRepository_CountByCode(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 7)
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	mock.ExpectQuery(`SELECT provider, error_code, COUNT\(\*\), MAX\(created_at\)\s+FROM provider_errors\s+WHERE created_at >= \$1 AND created_at < \$2\s+GROUP BY provider, error_code\s+ORDER BY COUNT\(\*\) DESC`).
+		WithArgs(start, end).
+		WillReturnRows(pgxmock.NewRows([]string{"provider", "error_code", "count", "max"}).
+			AddRow("rekognition", "ThrottlingException", 42, start.Add(3*time.Hour)).
+			AddRow("deepface", "HTTP_503", 7, start.Add(5*time.Hour)))
+
+	repo := NewProviderErrorRepository(mock)
+	counts, err := repo.CountByCode(context.Background(), start, end)
+
+	require.NoError(t, err)
+	assert.Equal(t, []domain.ProviderErrorCount{
+		{Provider: "rekognition", ErrorCode: "ThrottlingException", Count: 42, LastSeenAt: start.Add(3 * time.Hour)},
+		{Provider: "deepface", ErrorCode: "HTTP_503", Count: 7, LastSeenAt: start.Add(5 * time.Hour)},
+	}, counts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProviderErrorRepository_DeleteBefore(t *testing.T) {
+	cutoff := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	mock.ExpectExec(`DELETE FROM provider_errors\s+WHERE id IN \(\s*SELECT id FROM provider_errors\s+WHERE created_at < \$1\s+LIMIT \$2\s*\)`).
+		WithArgs(cutoff, 500).
+		WillReturnResult(pgxmock.NewResult("DELETE", 120))
+
+	repo := NewProviderErrorRepository(mock)
+	deleted, err := repo.DeleteBefore(context.Background(), cutoff, 500)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(120), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestShadowComparisonRepository_Create(t *testing.T) {
+	tenantID := uuid.New()
+	now := time.Now()
+
+	t.Run("records the decision delta", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		externalID := "user-123"
+		primaryScore, shadowScore := 0.91, 0.42
+		shadowMatch := false
+		comparison := &domain.ShadowComparison{
+			TenantID:         tenantID,
+			Operation:        domain.ShadowOperationVerify,
+			ExternalID:       &externalID,
+			PrimaryMatch:     true,
+			PrimaryScore:     &primaryScore,
+			PrimaryLatencyMs: 120,
+			ShadowMatch:      &shadowMatch,
+			ShadowScore:      &shadowScore,
+			ShadowLatencyMs:  300,
+		}
+
+		mock.ExpectQuery(`INSERT INTO shadow_comparisons`).
+			WithArgs(
+				pgxmock.AnyArg(),
+				tenantID,
+				"verify",
+				&externalID,
+				true,
+				&primaryScore,
+				pgxmock.AnyArg(),
+				int64(120),
+				&shadowMatch,
+				&shadowScore,
+				pgxmock.AnyArg(),
+				int64(300),
+				pgxmock.AnyArg(),
+				pgxmock.AnyArg(),
+				true,
+			).
+			WillReturnRows(pgxmock.NewRows([]string{"created_at"}).AddRow(now))
+
+		repo := NewShadowComparisonRepository(mock)
+		err = repo.Create(context.Background(), comparison)
+
+		require.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, comparison.ID)
+		assert.False(t, comparison.CreatedAt.IsZero())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(`INSERT INTO shadow_comparisons`).
+			WillReturnError(errors.New("database unavailable"))
+
+		repo := NewShadowComparisonRepository(mock)
+		err = repo.Create(context.Background(), &domain.ShadowComparison{TenantID: tenantID, Operation: domain.ShadowOperationSearch})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "create shadow comparison")
+	})
+}
+
+func TestSearchAuditRepository_List(t *testing.T) {
+	tenantID := uuid.New()
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)
+	columns := []string{
+		"id", "tenant_id", "results_count", "top_match_external_id", "top_match_similarity",
+		"threshold", "max_results", "latency_ms", "client_ip", "created_at",
+	}
+
+	t.Run("filters by range and paginates", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		externalID, similarity := "user-123", 0.96
+		auditID := uuid.New()
+		createdAt := from.Add(48 * time.Hour)
+
+		mock.ExpectQuery(`SELECT COUNT\(\*\)\s+FROM search_audits\s+WHERE tenant_id = \$1 AND created_at >= \$2 AND created_at < \$3`).
+			WithArgs(tenantID, from, to).
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(7))
+		mock.ExpectQuery(`FROM search_audits\s+WHERE tenant_id = \$1 AND created_at >= \$2 AND created_at < \$3\s+ORDER BY created_at DESC, id DESC\s+LIMIT \$4 OFFSET \$5`).
+			WithArgs(tenantID, from, to, 2, 4).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(auditID, tenantID, 3, &externalID, &similarity, 0.8, 10, int64(42), "203.0.113.7", createdAt).
+				AddRow(uuid.New(), tenantID, 0, nil, nil, 0.9, 5, int64(18), "", createdAt.Add(-time.Hour)))
+
+		repo := NewSearchAuditRepository(mock)
+		audits, total, err := repo.List(context.Background(), tenantID, domain.SearchAuditFilter{From: from, To: to, Limit: 2, Offset: 4})
+
+		require.NoError(t, err)
+		assert.Equal(t, 7, total)
+		require.Len(t, audits, 2)
+		assert.Equal(t, auditID, audits[0].ID)
+		assert.Equal(t, 3, audits[0].ResultsCount)
+		assert.Equal(t, "user-123", *audits[0].TopMatchExternalID)
+		assert.Equal(t, 0.96, *audits[0].TopMatchSimilarity)
+		assert.Equal(t, int64(42), audits[0].LatencyMs)
+		assert.Equal(t, "203.0.113.7", audits[0].ClientIP)
+		assert.Nil(t, audits[1].TopMatchExternalID)
+		assert.Nil(t, audits[1].TopMatchSimilarity)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	limits := []struct {
+		name       string
+		filter     domain.SearchAuditFilter
+		wantLimit  int
+		wantOffset int
+	}{
+		{"defaults limit", domain.SearchAuditFilter{}, domain.DefaultSearchAuditLimit, 0},
+		{"caps limit", domain.SearchAuditFilter{Limit: 1000}, domain.MaxSearchAuditLimit, 0},
+		{"clamps negative offset", domain.SearchAuditFilter{Limit: 10, Offset: -5}, 10, 0},
+	}
+
+	for _, tt := range limits {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mock.Close()
+
+			tt.filter.From, tt.filter.To = from, to
+			mock.ExpectQuery(`SELECT COUNT\(\*\)`).
+				WithArgs(tenantID, from, to).
+				WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(0))
+			mock.ExpectQuery(`FROM search_audits`).
+				WithArgs(tenantID, from, to, tt.wantLimit, tt.wantOffset).
+				WillReturnRows(pgxmock.NewRows(columns))
+
+			repo := NewSearchAuditRepository(mock)
+			audits, total, err := repo.List(context.Background(), tenantID, tt.filter)
+
+			require.NoError(t, err)
+			assert.Empty(t, audits)
+			assert.Zero(t, total)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Run("database error", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectQuery(`SELECT COUNT\(\*\)`).
+			WillReturnError(errors.New("database unavailable"))
+
+		repo := NewSearchAuditRepository(mock)
+		_, _, err = repo.List(context.Background(), tenantID, domain.SearchAuditFilter{From: from, To: to})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "count search audits")
+	})
+}
+
+func TestFaceStatsRefresher(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	t.Run("analyzes once after threshold inserts", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectExec(`ANALYZE faces`).WillReturnResult(pgxmock.NewResult("ANALYZE", 0))
+
+		refresher := NewFaceStatsRefresher(mock, 100, 20*time.Millisecond, logger)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go refresher.Run(ctx)
+
+		// Several batches inside the debounce interval share one ANALYZE
+		for i := 0; i < 5; i++ {
+			refresher.RecordInserts(40)
+		}
+
+		assert.Eventually(t, func() bool {
+			return mock.ExpectationsWereMet() == nil
+		}, time.Second, 5*time.Millisecond)
+
+		// Nothing else is pending, so no second ANALYZE runs
+		time.Sleep(50 * time.Millisecond)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("below threshold does nothing", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		refresher := NewFaceStatsRefresher(mock, 100, time.Millisecond, logger)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go refresher.Run(ctx)
+
+		refresher.RecordInserts(99)
+		time.Sleep(20 * time.Millisecond)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failed refresh keeps inserts pending", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectExec(`ANALYZE faces`).WillReturnError(errors.New("canceling statement due to lock timeout"))
+		mock.ExpectExec(`ANALYZE faces`).WillReturnResult(pgxmock.NewResult("ANALYZE", 0))
+
+		refresher := NewFaceStatsRefresher(mock, 10, time.Millisecond, logger)
+		refresher.RecordInserts(10)
+
+		err = refresher.Refresh(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "analyze faces")
+		assert.Equal(t, int64(10), refresher.pending.Load())
+
+		require.NoError(t, refresher.Refresh(context.Background()))
+		assert.Zero(t, refresher.pending.Load())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProvisioningRepository_ProvisionForAPIKey(t *testing.T) {
+	keyID := uuid.New()
+	lockQuery := `SELECT tenant_id, auto_provision\s+FROM api_keys\s+WHERE id = \$1 AND is_active = true\s+FOR UPDATE`
+	newTenant := func() *domain.Tenant {
+		return &domain.Tenant{ID: uuid.New(), Name: "Acme", Slug: "self-serve-acme", Plan: domain.PlanStarter, IsActive: true}
+	}
+
+	t.Run("first use creates the tenant, runs setup and binds the key", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		tenant := newTenant()
+		now := time.Now()
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockQuery).WithArgs(keyID).
+			WillReturnRows(pgxmock.NewRows([]string{"tenant_id", "auto_provision"}).AddRow(nil, true))
+		mock.ExpectQuery(`INSERT INTO tenants`).
+			WithArgs(tenant.ID, tenant.Name, tenant.Slug, true, domain.PlanStarter, map[string]interface{}{}).
+			WillReturnRows(pgxmock.NewRows([]string{"created_at", "updated_at"}).AddRow(now, now))
+		mock.ExpectExec(`UPDATE api_keys\s+SET tenant_id = \$2, auto_provision = false\s+WHERE id = \$1`).
+			WithArgs(keyID, tenant.ID).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+
+		var setupTenant uuid.UUID
+		repo := NewProvisioningRepository(mock)
+		got, err := repo.ProvisionForAPIKey(context.Background(), keyID, tenant, func(ctx context.Context, tenant *domain.Tenant) error {
+			setupTenant = tenant.ID
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, tenant.ID, got)
+		assert.Equal(t, tenant.ID, setupTenant)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("key already bound by a concurrent request reuses its tenant", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		existing := uuid.New()
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockQuery).WithArgs(keyID).
+			WillReturnRows(pgxmock.NewRows([]string{"tenant_id", "auto_provision"}).AddRow(&existing, false))
+		mock.ExpectCommit()
+
+		repo := NewProvisioningRepository(mock)
+		got, err := repo.ProvisionForAPIKey(context.Background(), keyID, newTenant(), func(ctx context.Context, tenant *domain.Tenant) error {
+			t.Fatal("setup must not run for a bound key")
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, existing, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("setup failure rolls back and leaves the key unbound", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		tenant := newTenant()
+		now := time.Now()
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockQuery).WithArgs(keyID).
+			WillReturnRows(pgxmock.NewRows([]string{"tenant_id", "auto_provision"}).AddRow(nil, true))
+		mock.ExpectQuery(`INSERT INTO tenants`).
+			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WillReturnRows(pgxmock.NewRows([]string{"created_at", "updated_at"}).AddRow(now, now))
+		mock.ExpectRollback()
+
+		repo := NewProvisioningRepository(mock)
+		_, err = repo.ProvisionForAPIKey(context.Background(), keyID, tenant, func(ctx context.Context, tenant *domain.Tenant) error {
+			return errors.New("collection quota exceeded")
+		})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "collection quota exceeded")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("key without the auto_provision flag is refused", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer mock.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockQuery).WithArgs(keyID).
+			WillReturnRows(pgxmock.NewRows([]string{"tenant_id", "auto_provision"}).AddRow(nil, false))
+		mock.ExpectRollback()
+
+		repo := NewProvisioningRepository(mock)
+		_, err = repo.ProvisionForAPIKey(context.Background(), keyID, newTenant(), nil)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not flagged for auto-provisioning")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
 // Helper function to test unique violation detection
 func TestIsUniqueViolation(t *testing.T) {
 	tests := []struct {
@@ -22,8 +22,9 @@ func (r *SearchAuditRepository) Create(ctx context.Context, audit *domain.Search
 	query := `
 		INSERT INTO search_audits (
 			id, tenant_id, results_count, top_match_external_id,
-			top_match_similarity, threshold, max_results, latency_ms, client_ip, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+			top_match_similarity, threshold, max_results, latency_ms, client_ip, is_test,
+			provider_variant, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''), NOW())
 		RETURNING created_at
 	`
 
@@ -41,6 +42,8 @@ func (r *SearchAuditRepository) Create(ctx context.Context, audit *domain.Search
 		audit.MaxResults,
 		audit.LatencyMs,
 		audit.ClientIP,
+		audit.IsTest,
+		audit.ProviderVariant,
 	).Scan(&audit.CreatedAt)
 
 	if err != nil {
@@ -49,3 +52,65 @@ func (r *SearchAuditRepository) Create(ctx context.Context, audit *domain.Search
 
 	return nil
 }
+
+// List returns the tenant's search audits created in [filter.From, filter.To), most recent
+// first, together with the total number of audits in that range
+func (r *SearchAuditRepository) List(ctx context.Context, tenantID uuid.UUID, filter domain.SearchAuditFilter) ([]*domain.SearchAudit, int, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = domain.DefaultSearchAuditLimit
+	}
+	if filter.Limit > domain.MaxSearchAuditLimit {
+		filter.Limit = domain.MaxSearchAuditLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	var total int
+	err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM search_audits
+		WHERE tenant_id = $1 AND created_at >= $2 AND created_at < $3
+	`, tenantID, filter.From, filter.To).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count search audits: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, tenant_id, results_count, top_match_external_id, top_match_similarity,
+		       threshold, max_results, latency_ms, COALESCE(host(client_ip), ''), created_at
+		FROM search_audits
+		WHERE tenant_id = $1 AND created_at >= $2 AND created_at < $3
+		ORDER BY created_at DESC, id DESC
+		LIMIT $4 OFFSET $5
+	`, tenantID, filter.From, filter.To, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list search audits: %w", err)
+	}
+	defer rows.Close()
+
+	audits := make([]*domain.SearchAudit, 0, filter.Limit)
+	for rows.Next() {
+		audit := &domain.SearchAudit{}
+		if err := rows.Scan(
+			&audit.ID,
+			&audit.TenantID,
+			&audit.ResultsCount,
+			&audit.TopMatchExternalID,
+			&audit.TopMatchSimilarity,
+			&audit.Threshold,
+			&audit.MaxResults,
+			&audit.LatencyMs,
+			&audit.ClientIP,
+			&audit.CreatedAt,
+		); err != nil {
+			return nil, 0, fmt.Errorf("scan search audit: %w", err)
+		}
+		audits = append(audits, audit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("list search audits: %w", err)
+	}
+
+	return audits, total, nil
+}
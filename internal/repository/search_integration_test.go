@@ -5,6 +5,10 @@ package repository
 import (
 	"context"
 	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"math/rand"
 	"os"
 	"testing"
 	"time"
@@ -21,7 +25,7 @@ import (
 
 var integrationTestDB *pgxpool.Pool
 
-func setupIntegrationTest(t *testing.T) (*pgxpool.Pool, func()) {
+func setupIntegrationTest(t testing.TB) (*pgxpool.Pool, func()) {
 	t.Helper()
 
 	ctx := context.Background()
@@ -65,8 +69,15 @@ func setupIntegrationTest(t *testing.T) (*pgxpool.Pool, func()) {
 			tenant_id UUID NOT NULL,
 			external_id VARCHAR(255) NOT NULL,
 			embedding vector(512),
+			embedding_half halfvec(512),
+			embedding_model VARCHAR(100),
+			embedding_version VARCHAR(50),
+			embedding_hash TEXT,
+			provider_face_id TEXT,
 			metadata JSONB,
 			quality_score FLOAT NOT NULL DEFAULT 0,
+			is_test BOOLEAN NOT NULL DEFAULT false,
+			expires_at TIMESTAMPTZ,
 			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
 			updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
 			UNIQUE(tenant_id, external_id)
@@ -149,7 +160,7 @@ func TestSearchByEmbedding_Integration(t *testing.T) {
 		threshold := 0.95
 		limit := 10
 
-		matches, err := repo.SearchByEmbedding(ctx, tenantID, queryEmbedding, threshold, limit)
+		matches, err := repo.SearchByEmbedding(ctx, tenantID, queryEmbedding, domain.EmbeddingFingerprint{}, threshold, limit)
 		require.NoError(t, err)
 
 		// Should find identical and very similar
@@ -178,7 +189,7 @@ func TestSearchByEmbedding_Integration(t *testing.T) {
 		threshold := 0.7
 		limit := 10
 
-		matches, err := repo.SearchByEmbedding(ctx, tenantID, queryEmbedding, threshold, limit)
+		matches, err := repo.SearchByEmbedding(ctx, tenantID, queryEmbedding, domain.EmbeddingFingerprint{}, threshold, limit)
 		require.NoError(t, err)
 
 		// Should find identical, very similar, and similar
@@ -198,7 +209,7 @@ func TestSearchByEmbedding_Integration(t *testing.T) {
 		threshold := 0.0
 		limit := 10
 
-		matches, err := repo.SearchByEmbedding(ctx, tenantID, queryEmbedding, threshold, limit)
+		matches, err := repo.SearchByEmbedding(ctx, tenantID, queryEmbedding, domain.EmbeddingFingerprint{}, threshold, limit)
 		require.NoError(t, err)
 
 		// Should find all faces except opposite (which has negative similarity)
@@ -210,7 +221,7 @@ func TestSearchByEmbedding_Integration(t *testing.T) {
 		threshold := 0.5
 		limit := 2
 
-		matches, err := repo.SearchByEmbedding(ctx, tenantID, queryEmbedding, threshold, limit)
+		matches, err := repo.SearchByEmbedding(ctx, tenantID, queryEmbedding, domain.EmbeddingFingerprint{}, threshold, limit)
 		require.NoError(t, err)
 
 		// Should respect limit
@@ -222,7 +233,7 @@ func TestSearchByEmbedding_Integration(t *testing.T) {
 		threshold := 0.95
 		limit := 10
 
-		matches, err := repo.SearchByEmbedding(ctx, tenantID, queryEmbedding, threshold, limit)
+		matches, err := repo.SearchByEmbedding(ctx, tenantID, queryEmbedding, domain.EmbeddingFingerprint{}, threshold, limit)
 		require.NoError(t, err)
 
 		// Should return empty slice, not nil
@@ -245,7 +256,7 @@ func TestSearchByEmbedding_Integration(t *testing.T) {
 
 		// Search should only find faces from the queried tenant
 		queryEmbedding := createNormalizedEmbedding([]float64{1.0, 0.0, 0.0})
-		matches, err := repo.SearchByEmbedding(ctx, tenantID, queryEmbedding, 0.5, 10)
+		matches, err := repo.SearchByEmbedding(ctx, tenantID, queryEmbedding, domain.EmbeddingFingerprint{}, 0.5, 10)
 		require.NoError(t, err)
 
 		for _, match := range matches {
@@ -258,7 +269,7 @@ func TestSearchByEmbedding_Integration(t *testing.T) {
 		threshold := 0.95
 		limit := 10
 
-		matches, err := repo.SearchByEmbedding(ctx, tenantID, queryEmbedding, threshold, limit)
+		matches, err := repo.SearchByEmbedding(ctx, tenantID, queryEmbedding, domain.EmbeddingFingerprint{}, threshold, limit)
 		require.NoError(t, err)
 		require.Greater(t, len(matches), 0, "need at least one match to validate metadata")
 
@@ -271,6 +282,251 @@ func TestSearchByEmbedding_Integration(t *testing.T) {
 	})
 }
 
+// TestSearchByHalfEmbedding_Recall_Integration compares search of faces stored at half precision
+// against the same faces stored at full precision. The ANN index is dropped so both run exact scans and
+// any difference comes from quantization alone.
+func TestSearchByHalfEmbedding_Recall_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	db, cleanup := setupIntegrationTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	_, err := db.Exec(ctx, `DROP INDEX IF EXISTS idx_faces_embedding`)
+	require.NoError(t, err)
+
+	repo := NewFaceRepository(db)
+	fullTenantID, halfTenantID := uuid.New(), uuid.New()
+	rng := rand.New(rand.NewSource(42))
+
+	const faceCount = 2000
+	embeddings := make([][]float64, faceCount)
+	for i := range embeddings {
+		embeddings[i] = randomUnitEmbedding(rng)
+		for tenantID, precision := range map[uuid.UUID]domain.EmbeddingPrecision{
+			fullTenantID: domain.EmbeddingPrecisionFull,
+			halfTenantID: domain.EmbeddingPrecisionHalf,
+		} {
+			err := repo.Create(ctx, &domain.Face{
+				TenantID:           tenantID,
+				ExternalID:         fmt.Sprintf("user-%d", i),
+				Embedding:          embeddings[i],
+				QualityScore:       0.9,
+				EmbeddingPrecision: precision,
+			})
+			require.NoError(t, err)
+		}
+	}
+
+	const queries, k = 50, 10
+	var found, total int
+	for q := 0; q < queries; q++ {
+		// A new photo of a registered person: their stored embedding plus noise
+		query := perturbEmbedding(embeddings[rng.Intn(faceCount)], rng, 0.3)
+
+		full, err := repo.SearchByEmbedding(ctx, fullTenantID, query, domain.EmbeddingFingerprint{}, 0, k)
+		require.NoError(t, err)
+		half, err := repo.SearchByEmbedding(ctx, halfTenantID, query, domain.EmbeddingFingerprint{}, 0, k)
+		require.NoError(t, err)
+
+		require.NotEmpty(t, full)
+		require.NotEmpty(t, half)
+		assert.Equal(t, full[0].ExternalID, half[0].ExternalID, "top match should not change")
+		assert.InDelta(t, full[0].Similarity, half[0].Similarity, 0.001)
+
+		want := make(map[string]bool, len(full))
+		for _, m := range full {
+			want[m.ExternalID] = true
+		}
+		for _, m := range half {
+			if want[m.ExternalID] {
+				found++
+			}
+		}
+		total += len(full)
+	}
+
+	recall := float64(found) / float64(total)
+	t.Logf("half precision recall@%d against full precision: %.3f", k, recall)
+	assert.GreaterOrEqual(t, recall, 0.95)
+}
+
+func TestSearchByEmbedding_MixedPrecision_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	db, cleanup := setupIntegrationTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewFaceRepository(db)
+	tenantID := uuid.New()
+
+	// Registered before the tenant switched to half precision
+	require.NoError(t, repo.Create(ctx, &domain.Face{
+		TenantID:   tenantID,
+		ExternalID: "user-full",
+		Embedding:  createNormalizedEmbedding([]float64{0.9, 0.1, 0.0}),
+	}))
+	require.NoError(t, repo.Create(ctx, &domain.Face{
+		TenantID:           tenantID,
+		ExternalID:         "user-half",
+		Embedding:          createNormalizedEmbedding([]float64{1.0, 0.0, 0.0}),
+		EmbeddingPrecision: domain.EmbeddingPrecisionHalf,
+	}))
+
+	matches, err := repo.SearchByEmbedding(ctx, tenantID, createNormalizedEmbedding([]float64{1.0, 0.0, 0.0}), domain.EmbeddingFingerprint{}, 0.5, 10)
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	assert.Equal(t, "user-half", matches[0].ExternalID)
+	assert.Equal(t, "user-full", matches[1].ExternalID)
+
+	// Half-precision faces still load their embedding, e.g. for 1:1 verification
+	face, err := repo.GetByExternalID(ctx, tenantID, "user-half")
+	require.NoError(t, err)
+	require.Len(t, face.Embedding, 512)
+	assert.InDelta(t, 1.0, face.Embedding[0], 0.001)
+}
+
+// TestSearchByEmbeddingSegmented_Integration checks faces outside a segment that sit between a
+// lower segment threshold and the resolved threshold do not take the place of a segment match
+func TestSearchByEmbeddingSegmented_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	db, cleanup := setupIntegrationTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewFaceRepository(db)
+	tenantID := uuid.New()
+
+	// Similarity 0.8 to the query, below the resolved 0.85
+	for i := 0; i < 3; i++ {
+		require.NoError(t, repo.Create(ctx, &domain.Face{
+			TenantID:   tenantID,
+			ExternalID: fmt.Sprintf("general-%d", i),
+			Embedding:  createNormalizedEmbedding([]float64{0.6, 0.8, 0.0}),
+			Metadata:   map[string]interface{}{"ticket_type": "general"},
+		}))
+	}
+	// Similarity 0.75, above the guest threshold of 0.7
+	require.NoError(t, repo.Create(ctx, &domain.Face{
+		TenantID:   tenantID,
+		ExternalID: "guest",
+		Embedding:  createNormalizedEmbedding([]float64{0.5, 0.866, 0.0}),
+		Metadata:   map[string]interface{}{"ticket_type": "guest"},
+	}))
+
+	segments := domain.SegmentThresholdSettings{Key: "ticket_type", Search: map[string]float64{"guest": 0.7}}
+	matches, err := repo.SearchByEmbeddingSegmented(ctx, tenantID, createNormalizedEmbedding([]float64{1.0, 0.0, 0.0}), domain.EmbeddingFingerprint{}, 0.85, segments, 1)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "guest", matches[0].ExternalID)
+}
+
+// TestSearchByEmbedding_BackToFullPrecision_Integration registers a face while the tenant is at
+// half precision, switches it back to full and checks the face is still found
+func TestSearchByEmbedding_BackToFullPrecision_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	db, cleanup := setupIntegrationTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewFaceRepository(db)
+	tenantID := uuid.New()
+
+	// Registered while the tenant stored embeddings at half precision
+	require.NoError(t, repo.Create(ctx, &domain.Face{
+		TenantID:           tenantID,
+		ExternalID:         "user-half",
+		Embedding:          createNormalizedEmbedding([]float64{1.0, 0.0, 0.0}),
+		EmbeddingPrecision: domain.EmbeddingPrecisionHalf,
+	}))
+	// Registered after it switched back to full
+	require.NoError(t, repo.Create(ctx, &domain.Face{
+		TenantID:           tenantID,
+		ExternalID:         "user-full",
+		Embedding:          createNormalizedEmbedding([]float64{0.9, 0.1, 0.0}),
+		EmbeddingPrecision: domain.EmbeddingPrecisionFull,
+	}))
+
+	matches, err := repo.SearchByEmbedding(ctx, tenantID, createNormalizedEmbedding([]float64{1.0, 0.0, 0.0}), domain.EmbeddingFingerprint{}, 0.5, 10)
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	assert.Equal(t, "user-half", matches[0].ExternalID)
+	assert.Equal(t, "user-full", matches[1].ExternalID)
+}
+
+// BenchmarkSearchByEmbeddingPrecision_Integration compares search latency and embedding storage
+// of full and half precision with an HNSW index on each column, as in production
+func BenchmarkSearchByEmbeddingPrecision_Integration(b *testing.B) {
+	db, cleanup := setupIntegrationTest(b)
+	defer cleanup()
+
+	ctx := context.Background()
+	_, err := db.Exec(ctx, `
+		DROP INDEX IF EXISTS idx_faces_embedding;
+		CREATE INDEX idx_faces_embedding_hnsw ON faces USING hnsw (embedding vector_cosine_ops)
+		WITH (m = 16, ef_construction = 64);
+		CREATE INDEX idx_faces_embedding_half_hnsw ON faces USING hnsw (embedding_half halfvec_cosine_ops)
+		WITH (m = 16, ef_construction = 64) WHERE embedding_half IS NOT NULL;
+	`)
+	require.NoError(b, err)
+
+	repo := NewFaceRepository(db)
+	rng := rand.New(rand.NewSource(42))
+	const faceCount = 5000
+
+	precisions := []struct {
+		precision domain.EmbeddingPrecision
+		column    string
+	}{
+		{domain.EmbeddingPrecisionFull, "embedding"},
+		{domain.EmbeddingPrecisionHalf, "embedding_half"},
+	}
+
+	for _, p := range precisions {
+		b.Run(string(p.precision), func(b *testing.B) {
+			tenantID := uuid.New()
+			for i := 0; i < faceCount; i++ {
+				err := repo.Create(ctx, &domain.Face{
+					TenantID:           tenantID,
+					ExternalID:         fmt.Sprintf("user-%d", i),
+					Embedding:          randomUnitEmbedding(rng),
+					QualityScore:       0.9,
+					EmbeddingPrecision: p.precision,
+				})
+				require.NoError(b, err)
+			}
+
+			var bytesPerFace float64
+			err := db.QueryRow(ctx, fmt.Sprintf(
+				`SELECT AVG(pg_column_size(%s)) FROM faces WHERE tenant_id = $1`, p.column,
+			), tenantID).Scan(&bytesPerFace)
+			require.NoError(b, err)
+
+			query := randomUnitEmbedding(rng)
+			b.ResetTimer()
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				if _, err := repo.SearchByEmbedding(ctx, tenantID, query, domain.EmbeddingFingerprint{}, 0.7, 10); err != nil {
+					b.Fatalf("search failed: %v", err)
+				}
+			}
+			b.ReportMetric(bytesPerFace, "embedding-bytes/face")
+		})
+	}
+}
+
 func TestCountByTenant_Integration(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
@@ -334,6 +590,65 @@ func TestCountByTenant_Integration(t *testing.T) {
 	})
 }
 
+func TestFaceStatsRefresher_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	db, cleanup := setupIntegrationTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	tenantID := uuid.New()
+	const bulkSize = 5000
+
+	// Keep autovacuum from refreshing statistics behind the test's back
+	_, err := db.Exec(ctx, `ALTER TABLE faces SET (autovacuum_enabled = false)`)
+	require.NoError(t, err)
+
+	// Bulk insert, as a batch registration or import would
+	_, err = db.Exec(ctx, `
+		INSERT INTO faces (tenant_id, external_id, embedding, quality_score)
+		SELECT $1, 'bulk-' || i, array_fill(0.1::real, ARRAY[512])::vector, 0.9
+		FROM generate_series(1, $2::int) AS i
+	`, tenantID, bulkSize)
+	require.NoError(t, err)
+
+	reltuples := func() float64 {
+		var n float64
+		err := db.QueryRow(ctx, `SELECT reltuples FROM pg_class WHERE relname = 'faces'`).Scan(&n)
+		require.NoError(t, err)
+		return n
+	}
+	estimatedRows := func() float64 {
+		var plan []map[string]map[string]interface{}
+		err := db.QueryRow(ctx, fmt.Sprintf(
+			`EXPLAIN (FORMAT JSON) SELECT id FROM faces WHERE tenant_id = '%s'`, tenantID,
+		)).Scan(&plan)
+		require.NoError(t, err)
+		return plan[0]["Plan"]["Plan Rows"].(float64)
+	}
+
+	// Never analyzed: the planner has no row count for the table
+	assert.Less(t, reltuples(), float64(bulkSize/2))
+
+	refresher := NewFaceStatsRefresher(db, 1000, 50*time.Millisecond, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go refresher.Run(runCtx)
+
+	refresher.RecordInserts(bulkSize)
+
+	require.Eventually(t, func() bool {
+		return reltuples() == bulkSize
+	}, 10*time.Second, 50*time.Millisecond)
+
+	// Fresh statistics give the tenant filter an accurate and stable estimate
+	first := estimatedRows()
+	assert.InDelta(t, bulkSize, first, bulkSize*0.1)
+	assert.Equal(t, first, estimatedRows())
+}
+
 // createNormalizedEmbedding creates a 512-dimensional normalized embedding
 // from a smaller input vector by padding with zeros
 func createNormalizedEmbedding(values []float64) []float64 {
@@ -360,6 +675,37 @@ func createNormalizedEmbedding(values []float64) []float64 {
 	return embedding
 }
 
+// randomUnitEmbedding creates a random 512-dimensional embedding of unit length
+func randomUnitEmbedding(rng *rand.Rand) []float64 {
+	embedding := make([]float64, 512)
+	for i := range embedding {
+		embedding[i] = rng.NormFloat64()
+	}
+	return unitLength(embedding)
+}
+
+// perturbEmbedding adds gaussian noise scaled by noise to every component and renormalizes
+func perturbEmbedding(embedding []float64, rng *rand.Rand, noise float64) []float64 {
+	perturbed := make([]float64, len(embedding))
+	scale := noise / math.Sqrt(float64(len(embedding)))
+	for i, v := range embedding {
+		perturbed[i] = v + rng.NormFloat64()*scale
+	}
+	return unitLength(perturbed)
+}
+
+func unitLength(embedding []float64) []float64 {
+	var sum float64
+	for _, v := range embedding {
+		sum += v * v
+	}
+	norm := math.Sqrt(sum)
+	for i := range embedding {
+		embedding[i] /= norm
+	}
+	return embedding
+}
+
 func TestMain(m *testing.M) {
 	// Run tests
 	code := m.Run()
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+type ShadowComparisonRepository struct {
+	pool PgxPool
+}
+
+func NewShadowComparisonRepository(pool PgxPool) *ShadowComparisonRepository {
+	return &ShadowComparisonRepository{pool: pool}
+}
+
+// Create inserts a primary/shadow decision comparison
+func (r *ShadowComparisonRepository) Create(ctx context.Context, c *domain.ShadowComparison) error {
+	query := `
+		INSERT INTO shadow_comparisons (
+			id, tenant_id, operation, external_id,
+			primary_match, primary_score, primary_top_match, primary_latency_ms,
+			shadow_match, shadow_score, shadow_top_match, shadow_latency_ms, shadow_error,
+			shadow_skipped, decision_differs, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, NOW())
+		RETURNING created_at
+	`
+
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+
+	err := r.pool.QueryRow(ctx, query,
+		c.ID,
+		c.TenantID,
+		string(c.Operation),
+		c.ExternalID,
+		c.PrimaryMatch,
+		c.PrimaryScore,
+		c.PrimaryTopMatch,
+		c.PrimaryLatencyMs,
+		c.ShadowMatch,
+		c.ShadowScore,
+		c.ShadowTopMatch,
+		c.ShadowLatencyMs,
+		c.ShadowError,
+		c.ShadowSkipped,
+		c.DecisionDiffers(),
+	).Scan(&c.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("create shadow comparison: %w", err)
+	}
+
+	return nil
+}
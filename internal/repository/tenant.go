@@ -12,10 +12,10 @@ import (
 )
 
 type TenantRepository struct {
-	pool PgxPool
+	pool PgxTxPool
 }
 
-func NewTenantRepository(pool PgxPool) *TenantRepository {
+func NewTenantRepository(pool PgxTxPool) *TenantRepository {
 	return &TenantRepository{pool: pool}
 }
 
@@ -133,11 +133,7 @@ func (r *TenantRepository) Create(ctx context.Context, tenant *domain.Tenant) er
 
 	if err != nil {
 		if isUniqueViolation(err) {
-			return &domain.AppError{
-				Code:       "TENANT_ALREADY_EXISTS",
-				Message:    "Tenant with this slug already exists",
-				StatusCode: 409,
-			}
+			return domain.ErrTenantExists
 		}
 		return fmt.Errorf("create tenant: %w", err)
 	}
@@ -171,11 +167,7 @@ func (r *TenantRepository) Update(ctx context.Context, tenant *domain.Tenant) er
 	}
 	if err != nil {
 		if isUniqueViolation(err) {
-			return &domain.AppError{
-				Code:       "TENANT_SLUG_CONFLICT",
-				Message:    "Tenant with this slug already exists",
-				StatusCode: 409,
-			}
+			return domain.ErrTenantSlugConflict
 		}
 		return fmt.Errorf("update tenant: %w", err)
 	}
@@ -201,6 +193,36 @@ func (r *TenantRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// DeleteCascade removes the tenant and all of its data in one transaction, returning how
+// many faces were erased. Faces are deleted explicitly so the count can be audited; every
+// other tenant table goes with the tenant through ON DELETE CASCADE.
+func (r *TenantRepository) DeleteCascade(ctx context.Context, id uuid.UUID) (int64, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("delete tenant cascade: begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	faces, err := tx.Exec(ctx, `DELETE FROM faces WHERE tenant_id = $1`, id)
+	if err != nil {
+		return 0, fmt.Errorf("delete tenant %s cascade: delete faces: %w", id, err)
+	}
+
+	result, err := tx.Exec(ctx, `DELETE FROM tenants WHERE id = $1`, id)
+	if err != nil {
+		return 0, fmt.Errorf("delete tenant %s cascade: delete tenant: %w", id, err)
+	}
+	if result.RowsAffected() == 0 {
+		return 0, domain.ErrTenantNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("delete tenant %s cascade: commit: %w", id, err)
+	}
+
+	return faces.RowsAffected(), nil
+}
+
 // GetByPublicKey retrieves a tenant by its public key (for widget authentication)
 func (r *TenantRepository) GetByPublicKey(ctx context.Context, publicKey string) (*domain.Tenant, error) {
 	query := `
@@ -256,3 +278,25 @@ func (r *TenantRepository) GetAllowedDomains(ctx context.Context, tenantID uuid.
 
 	return domains, nil
 }
+
+// GetPublicKey retrieves the widget public key for a tenant
+// Returns an empty string when the tenant has no public key yet
+func (r *TenantRepository) GetPublicKey(ctx context.Context, tenantID uuid.UUID) (string, error) {
+	query := `
+		SELECT COALESCE(public_key, '')
+		FROM tenants
+		WHERE id = $1
+	`
+
+	var publicKey string
+	err := r.pool.QueryRow(ctx, query, tenantID).Scan(&publicKey)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", domain.ErrTenantNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("get public key: %w", err)
+	}
+
+	return publicKey, nil
+}
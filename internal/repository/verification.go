@@ -2,13 +2,19 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 
 	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
 )
 
+// verificationStreamBatchSize is how many rows one StreamRange query reads
+const verificationStreamBatchSize = 1000
+
 type VerificationRepository struct {
 	pool PgxPool
 }
@@ -19,8 +25,9 @@ func NewVerificationRepository(pool PgxPool) *VerificationRepository {
 
 func (r *VerificationRepository) Create(ctx context.Context, v *domain.Verification) error {
 	query := `
-		INSERT INTO verifications (id, tenant_id, face_id, external_id, verified, confidence, liveness_passed, latency_ms, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+		INSERT INTO verifications (id, tenant_id, face_id, external_id, verified, confidence, liveness_passed, liveness_score, latency_ms, is_test, client_ip, fail_reason, provider, provider_variant, threshold_applied, threshold_source, hysteresis_pass, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, '')::inet, NULLIF($12, ''), NULLIF($13, ''), NULLIF($14, ''),
+		        NULLIF($15::float8, 0), NULLIF($16, ''), $17, NOW())
 		RETURNING created_at
 	`
 
@@ -36,7 +43,16 @@ func (r *VerificationRepository) Create(ctx context.Context, v *domain.Verificat
 		v.Verified,
 		v.Confidence,
 		v.LivenessPassed,
+		v.LivenessScore,
 		v.LatencyMs,
+		v.IsTest,
+		v.ClientIP,
+		v.FailReason,
+		v.Provider,
+		v.ProviderVariant,
+		v.ThresholdApplied,
+		string(v.ThresholdSource),
+		v.HysteresisPass,
 	).Scan(&v.CreatedAt)
 
 	if err != nil {
@@ -45,3 +61,190 @@ func (r *VerificationRepository) Create(ctx context.Context, v *domain.Verificat
 
 	return nil
 }
+
+// GetByID returns one of the tenant's verifications with everything recorded about it and the
+// current metadata of the verified face, nil once the face was deleted. Verifications of other
+// tenants are reported as not found.
+func (r *VerificationRepository) GetByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.Verification, error) {
+	query := `
+		SELECT v.id, v.face_id, v.external_id, v.verified, COALESCE(v.confidence, 0), v.liveness_passed, v.liveness_score,
+		       COALESCE(v.latency_ms, 0), v.is_test, COALESCE(host(v.client_ip), ''), COALESCE(v.fail_reason, ''),
+		       COALESCE(v.provider, ''), COALESCE(v.provider_variant, ''),
+		       COALESCE(v.threshold_applied, 0)::float8, COALESCE(v.threshold_source, ''), v.created_at, f.metadata
+		FROM verifications v
+		LEFT JOIN faces f ON f.id = v.face_id AND f.tenant_id = v.tenant_id
+		WHERE v.tenant_id = $1 AND v.id = $2
+	`
+
+	v := &domain.Verification{TenantID: tenantID}
+	var thresholdSource string
+	err := r.pool.QueryRow(ctx, query, tenantID, id).Scan(
+		&v.ID,
+		&v.FaceID,
+		&v.ExternalID,
+		&v.Verified,
+		&v.Confidence,
+		&v.LivenessPassed,
+		&v.LivenessScore,
+		&v.LatencyMs,
+		&v.IsTest,
+		&v.ClientIP,
+		&v.FailReason,
+		&v.Provider,
+		&v.ProviderVariant,
+		&v.ThresholdApplied,
+		&thresholdSource,
+		&v.CreatedAt,
+		&v.FaceMetadata,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrVerificationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get verification: %w", err)
+	}
+
+	v.ThresholdSource = domain.ThresholdSource(thresholdSource)
+	return v, nil
+}
+
+// RecentConfidences returns the confidences of the latest successful verifications of an
+// external_id since the given time, most recent first
+func (r *VerificationRepository) RecentConfidences(ctx context.Context, tenantID uuid.UUID, externalID string, since time.Time, limit int) ([]float64, error) {
+	query := `
+		SELECT confidence
+		FROM verifications
+		WHERE tenant_id = $1 AND external_id = $2 AND verified = true
+		  AND confidence IS NOT NULL AND created_at >= $3
+		ORDER BY created_at DESC
+		LIMIT $4
+	`
+
+	rows, err := r.pool.Query(ctx, query, tenantID, externalID, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("recent confidences: %w", err)
+	}
+	defer rows.Close()
+
+	confidences := make([]float64, 0, limit)
+	for rows.Next() {
+		var confidence float64
+		if err := rows.Scan(&confidence); err != nil {
+			return nil, fmt.Errorf("scan confidence: %w", err)
+		}
+		confidences = append(confidences, confidence)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("recent confidences: %w", err)
+	}
+
+	return confidences, nil
+}
+
+// LastVerifiedAt returns when the external_id last passed a verification, nil if never.
+// With requireLiveness, verifications that did not pass a liveness check are skipped.
+// Served by idx_verifications_tenant_external_created scanned backwards.
+func (r *VerificationRepository) LastVerifiedAt(ctx context.Context, tenantID uuid.UUID, externalID string, requireLiveness bool) (*time.Time, error) {
+	query := `
+		SELECT created_at
+		FROM verifications
+		WHERE tenant_id = $1 AND external_id = $2 AND verified = true
+		  AND (NOT $3 OR liveness_passed IS TRUE)
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	var lastVerifiedAt time.Time
+	err := r.pool.QueryRow(ctx, query, tenantID, externalID, requireLiveness).Scan(&lastVerifiedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("last verified at: %w", err)
+	}
+
+	return &lastVerifiedAt, nil
+}
+
+// LastVerifiedGate returns the gate (client IP) and time of the external_id's last passed
+// verification in the given environment, nil if never. With fullPassOnly, passes allowed by
+// verify_hysteresis are skipped. Served like LastVerifiedAt.
+func (r *VerificationRepository) LastVerifiedGate(ctx context.Context, tenantID uuid.UUID, externalID string, isTest, fullPassOnly bool) (*domain.GateVisit, error) {
+	query := `
+		SELECT COALESCE(host(client_ip), ''), created_at
+		FROM verifications
+		WHERE tenant_id = $1 AND external_id = $2 AND verified = true AND is_test = $3
+		  AND (NOT $4 OR NOT hysteresis_pass)
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	var visit domain.GateVisit
+	err := r.pool.QueryRow(ctx, query, tenantID, externalID, isTest, fullPassOnly).Scan(&visit.Gate, &visit.At)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("last verified gate: %w", err)
+	}
+
+	return &visit, nil
+}
+
+// StreamRange calls fn for each of the tenant's verifications created in [from, to), oldest first.
+// Rows are read in keyset-paginated batches on (created_at, id), so memory stays bounded however
+// large the range. An error returned by fn stops the stream and is returned as is.
+func (r *VerificationRepository) StreamRange(ctx context.Context, tenantID uuid.UUID, from, to time.Time, fn func(*domain.Verification) error) error {
+	query := `
+		SELECT id, external_id, verified, confidence, liveness_passed, latency_ms, created_at
+		FROM verifications
+		WHERE tenant_id = $1 AND created_at >= $2 AND created_at < $3
+		  AND (created_at, id) > ($4, $5)
+		ORDER BY created_at ASC, id ASC
+		LIMIT $6
+	`
+
+	// The cursor starts before every row of the range
+	afterCreatedAt, afterID := from, uuid.Nil
+	for {
+		batch, err := r.streamBatch(ctx, query, tenantID, from, to, afterCreatedAt, afterID)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range batch {
+			if err := fn(v); err != nil {
+				return err
+			}
+		}
+
+		if len(batch) < verificationStreamBatchSize {
+			return nil
+		}
+		last := batch[len(batch)-1]
+		afterCreatedAt, afterID = last.CreatedAt, last.ID
+	}
+}
+
+// streamBatch reads one page of StreamRange; rows are closed before fn sees them
+func (r *VerificationRepository) streamBatch(ctx context.Context, query string, tenantID uuid.UUID, from, to, afterCreatedAt time.Time, afterID uuid.UUID) ([]*domain.Verification, error) {
+	rows, err := r.pool.Query(ctx, query, tenantID, from, to, afterCreatedAt, afterID, verificationStreamBatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("stream verifications: %w", err)
+	}
+	defer rows.Close()
+
+	batch := make([]*domain.Verification, 0, verificationStreamBatchSize)
+	for rows.Next() {
+		v := &domain.Verification{TenantID: tenantID}
+		if err := rows.Scan(&v.ID, &v.ExternalID, &v.Verified, &v.Confidence, &v.LivenessPassed, &v.LatencyMs, &v.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan verification: %w", err)
+		}
+		batch = append(batch, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate verifications: %w", err)
+	}
+
+	return batch, nil
+}
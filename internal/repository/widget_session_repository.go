@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -72,15 +73,15 @@ func (r *WidgetSessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*d
 	return &session, nil
 }
 
-// DeleteExpired removes all expired sessions
+// DeleteExpired removes all sessions that expired before the given time
 // Returns the number of deleted sessions
-func (r *WidgetSessionRepository) DeleteExpired(ctx context.Context) (int64, error) {
+func (r *WidgetSessionRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
 	query := `
 		DELETE FROM widget_sessions
-		WHERE expires_at < NOW()
+		WHERE expires_at < $1
 	`
 
-	result, err := r.pool.Exec(ctx, query)
+	result, err := r.pool.Exec(ctx, query, before)
 	if err != nil {
 		return 0, fmt.Errorf("delete expired widget sessions: %w", err)
 	}
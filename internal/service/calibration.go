@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/provider"
+)
+
+// imageComparer is implemented by providers that compare two images directly instead of
+// embeddings, like Rekognition
+type imageComparer interface {
+	CompareFaceImages(ctx context.Context, sourceImage, targetImage []byte, similarityThreshold float64) (float64, error)
+}
+
+// EvaluateCalibration scores each labeled pair with the provider and reports the false accept
+// and false reject rates at each threshold, so a tenant can pick its verification threshold
+// from its own images. Nothing is stored: no face is registered and no verification is audited.
+// Pairs the provider cannot score are reported with an error and left out of the rates.
+func (s *FaceService) EvaluateCalibration(ctx context.Context, tenantID uuid.UUID, pairs []domain.CalibrationPair, thresholds []float64, settings domain.TenantSettings) (*domain.CalibrationReport, error) {
+	if len(pairs) == 0 {
+		return nil, domain.ErrValidationFailed.WithError(errors.New("at least one labeled pair is required"))
+	}
+	if len(pairs) > domain.MaxCalibrationPairs {
+		return nil, domain.ErrValidationFailed.WithError(fmt.Errorf("at most %d pairs are accepted, got %d", domain.MaxCalibrationPairs, len(pairs)))
+	}
+
+	thresholds, err := domain.NormalizeCalibrationThresholds(thresholds)
+	if err != nil {
+		return nil, domain.ErrValidationFailed.WithError(err)
+	}
+
+	if err := s.checkDataResidency(tenantID, settings); err != nil {
+		return nil, err
+	}
+
+	scores := make([]domain.CalibrationScore, len(pairs))
+	for i, pair := range pairs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		scores[i] = domain.CalibrationScore{Index: i, Match: pair.Match}
+		similarity, image, err := s.scorePair(ctx, pair)
+		if err != nil {
+			scores[i].Error = calibrationError(tenantID, i, image, err)
+			continue
+		}
+		scores[i].Similarity = similarity
+	}
+
+	return domain.NewCalibrationReport(scores, thresholds, settings.VerificationThreshold), nil
+}
+
+// scorePair compares the two images of a pair within the provider budget of a verify.
+// On failure it also names the image that failed, when only one of them did.
+func (s *FaceService) scorePair(ctx context.Context, pair domain.CalibrationPair) (float64, string, error) {
+	providerCtx, cancel := s.providerContext(ctx, providerOpVerify)
+	defer cancel()
+
+	imageA, err := s.normalizeImage(pair.ImageA)
+	if err != nil {
+		return 0, "image_a", err
+	}
+	imageB, err := s.normalizeImage(pair.ImageB)
+	if err != nil {
+		return 0, "image_b", err
+	}
+	prov := s.providerFor(ctx)
+
+	// The real similarity is needed even for non-matching pairs, so no threshold is applied
+	if comparer, ok := prov.(imageComparer); ok {
+		similarity, err := comparer.CompareFaceImages(providerCtx, imageA, imageB, 0)
+		return similarity, "", err
+	}
+
+	_, embeddingA, err := prov.IndexFace(providerCtx, imageA)
+	if err != nil {
+		return 0, "image_a", err
+	}
+	_, embeddingB, err := prov.IndexFace(providerCtx, imageB)
+	if err != nil {
+		return 0, "image_b", err
+	}
+
+	similarity, err := prov.CompareFaces(providerCtx, embeddingA, embeddingB)
+	return similarity, "", err
+}
+
+// calibrationError describes why a pair could not be scored without exposing provider internals
+func calibrationError(tenantID uuid.UUID, index int, image string, err error) string {
+	var noFace *provider.NoFaceError
+	if errors.As(err, &noFace) || errors.Is(err, domain.ErrNoFaceDetected) {
+		if image != "" {
+			return "no face detected in " + image
+		}
+		return "no face detected"
+	}
+	var appErr *domain.AppError
+	if errors.As(err, &appErr) {
+		return appErr.Message
+	}
+
+	slog.Warn("failed to score calibration pair", "tenant_id", tenantID, "pair", index, "error", err)
+	return "provider failed to compare the pair"
+}
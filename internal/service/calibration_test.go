@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/provider"
+)
+
+// labeledPair is a synthetic calibration pair and the similarity the provider gives it
+type labeledPair struct {
+	match      bool
+	similarity float64
+}
+
+// mockLabeledSet wires IndexFace and CompareFaces so each pair scores its similarity
+func mockLabeledSet(fp *MockFaceProvider, set []labeledPair) []domain.CalibrationPair {
+	pairs := make([]domain.CalibrationPair, len(set))
+	for i, p := range set {
+		imageA := []byte(fmt.Sprintf("pair-%d-a", i))
+		imageB := []byte(fmt.Sprintf("pair-%d-b", i))
+		embeddingA := []float64{float64(i), 0}
+		embeddingB := []float64{float64(i), 1}
+
+		fp.On("IndexFace", mock.Anything, imageA).Return("", embeddingA, nil)
+		fp.On("IndexFace", mock.Anything, imageB).Return("", embeddingB, nil)
+		fp.On("CompareFaces", mock.Anything, embeddingA, embeddingB).Return(p.similarity, nil)
+
+		pairs[i] = domain.CalibrationPair{ImageA: imageA, ImageB: imageB, Match: p.match}
+	}
+	return pairs
+}
+
+func TestFaceService_EvaluateCalibration(t *testing.T) {
+	tenantID := uuid.New()
+	faceProvider := &MockFaceProvider{}
+	pairs := mockLabeledSet(faceProvider, []labeledPair{
+		{match: true, similarity: 0.93},
+		{match: true, similarity: 0.84},
+		{match: true, similarity: 0.66},
+		{match: false, similarity: 0.82},
+		{match: false, similarity: 0.45},
+	})
+
+	svc := NewFaceService(&MockFaceRepository{}, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+	settings := domain.DefaultTenantSettings()
+
+	report, err := svc.EvaluateCalibration(context.Background(), tenantID, pairs, []float64{0.9, 0.6, 0.8}, settings)
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, report.Pairs)
+	assert.Equal(t, 5, report.Evaluated)
+	assert.Equal(t, 3, report.MatchPairs)
+	assert.Equal(t, 2, report.NonMatchPairs)
+
+	require.Len(t, report.Sweep, 3)
+	// 0.6 accepts everything but the 0.45 impostor
+	assert.Equal(t, 0.6, report.Sweep[0].Threshold)
+	assert.InDelta(t, 0.5, report.Sweep[0].FalseAcceptRate, 1e-9)
+	assert.InDelta(t, 0.0, report.Sweep[0].FalseRejectRate, 1e-9)
+	// 0.8 still lets the 0.82 impostor in and rejects the 0.66 genuine pair
+	assert.Equal(t, 0.8, report.Sweep[1].Threshold)
+	assert.InDelta(t, 0.5, report.Sweep[1].FalseAcceptRate, 1e-9)
+	assert.InDelta(t, 1.0/3, report.Sweep[1].FalseRejectRate, 1e-9)
+	// 0.9 keeps every impostor out at the cost of two genuine pairs
+	assert.Equal(t, 0.9, report.Sweep[2].Threshold)
+	assert.InDelta(t, 0.0, report.Sweep[2].FalseAcceptRate, 1e-9)
+	assert.InDelta(t, 2.0/3, report.Sweep[2].FalseRejectRate, 1e-9)
+
+	assert.Equal(t, settings.VerificationThreshold, report.Current.Threshold)
+	assert.Equal(t, 1, report.Current.FalseAccepts)
+	assert.Equal(t, 1, report.Current.FalseRejects)
+
+	faceProvider.AssertExpectations(t)
+}
+
+func TestFaceService_EvaluateCalibration_UnscoredPair(t *testing.T) {
+	tenantID := uuid.New()
+	faceProvider := &MockFaceProvider{}
+	pairs := mockLabeledSet(faceProvider, []labeledPair{
+		{match: true, similarity: 0.97},
+		{match: false, similarity: 0.3},
+	})
+	noFace := domain.CalibrationPair{ImageA: []byte("blank-a"), ImageB: []byte("blank-b"), Match: true}
+	faceProvider.On("IndexFace", mock.Anything, noFace.ImageA).Return("", []float64(nil), &provider.NoFaceError{
+		Reasons: []domain.NoFaceReason{domain.NoFaceReasonTooDark},
+		Err:     domain.ErrNoFaceDetected,
+	})
+	pairs = append(pairs, noFace)
+
+	svc := NewFaceService(&MockFaceRepository{}, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+
+	report, err := svc.EvaluateCalibration(context.Background(), tenantID, pairs, nil, domain.DefaultTenantSettings())
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, report.Pairs)
+	assert.Equal(t, 2, report.Evaluated)
+	assert.Equal(t, "no face detected in image_a", report.Scores[2].Error)
+	assert.Len(t, report.Sweep, len(domain.DefaultCalibrationThresholds()))
+	for _, rates := range report.Sweep {
+		assert.Zero(t, rates.FalseAccepts)
+		assert.Zero(t, rates.FalseRejects)
+	}
+}
+
+// imageComparingProvider compares images directly, like Rekognition
+type imageComparingProvider struct {
+	MockFaceProvider
+}
+
+func (p *imageComparingProvider) CompareFaceImages(ctx context.Context, sourceImage, targetImage []byte, similarityThreshold float64) (float64, error) {
+	args := p.Called(ctx, sourceImage, targetImage, similarityThreshold)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func TestFaceService_EvaluateCalibration_ImageComparer(t *testing.T) {
+	faceProvider := &imageComparingProvider{}
+	pair := domain.CalibrationPair{ImageA: []byte("a"), ImageB: []byte("b"), Match: false}
+	faceProvider.On("CompareFaceImages", mock.Anything, pair.ImageA, pair.ImageB, 0.0).Return(0.12, nil)
+
+	svc := NewFaceService(&MockFaceRepository{}, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+
+	report, err := svc.EvaluateCalibration(context.Background(), uuid.New(), []domain.CalibrationPair{pair}, nil, domain.DefaultTenantSettings())
+	require.NoError(t, err)
+
+	assert.Equal(t, 0.12, report.Scores[0].Similarity)
+	faceProvider.AssertExpectations(t)
+	faceProvider.AssertNotCalled(t, "IndexFace", mock.Anything, mock.Anything)
+}
+
+func TestFaceService_EvaluateCalibration_Validation(t *testing.T) {
+	svc := NewFaceService(&MockFaceRepository{}, &MockVerificationRepository{}, &MockSearchAuditRepository{}, &MockFaceProvider{}, &MockRateLimiter{})
+	pair := domain.CalibrationPair{ImageA: []byte("a"), ImageB: []byte("b")}
+
+	tests := []struct {
+		name       string
+		pairs      []domain.CalibrationPair
+		thresholds []float64
+	}{
+		{name: "no pairs"},
+		{name: "too many pairs", pairs: make([]domain.CalibrationPair, domain.MaxCalibrationPairs+1)},
+		{name: "threshold out of range", pairs: []domain.CalibrationPair{pair}, thresholds: []float64{1.2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.EvaluateCalibration(context.Background(), uuid.New(), tt.pairs, tt.thresholds, domain.DefaultTenantSettings())
+
+			var appErr *domain.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, domain.ErrValidationFailed.Code, appErr.Code)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/provider"
+)
+
+// VerifyDocument matches a live capture against the photo of an ID document uploaded with it,
+// for flows that check an identity without enrolling it. Nothing is stored: no face is
+// registered and no verification is audited. The live image goes through the same face checks
+// as a verify and, when the tenant's verify policy uses liveness, must pass it: a verified
+// document check never rests on a match alone. The document only needs a face, the largest
+// one is compared.
+func (s *FaceService) VerifyDocument(ctx context.Context, tenantID uuid.UUID, liveImage, documentImage []byte, settings domain.TenantSettings) (*domain.DocumentVerification, error) {
+	start := time.Now()
+
+	if err := s.checkDataResidency(tenantID, settings); err != nil {
+		return nil, err
+	}
+
+	providerCtx, cancel := s.providerContext(ctx, providerOpVerify)
+	defer cancel()
+
+	liveImage, err := s.normalizeImage(liveImage)
+	if err != nil {
+		return nil, err
+	}
+	documentImage, err = s.normalizeImage(documentImage)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkLiveFace(providerCtx, tenantID, liveImage, settings); err != nil {
+		return nil, err
+	}
+	// Otherwise a print or screen of the document photo would pass as the live capture
+	if settings.VerifyPolicy.RequiresLiveness() {
+		liveness, err := s.providerFor(ctx).CheckLiveness(providerCtx, liveImage, settings.LivenessThreshold)
+		if err != nil {
+			return nil, providerError(tenantID, "check liveness for document verification", err)
+		}
+		if !liveness.IsLive {
+			return nil, domain.ErrLivenessFailed
+		}
+	}
+
+	documentFaces, err := s.providerFor(ctx).DetectFaces(providerCtx, documentImage)
+	if err != nil {
+		return nil, documentError(tenantID, "detect document faces", err)
+	}
+	if len(documentFaces) == 0 {
+		return nil, domain.ErrNoFaceInDocument
+	}
+
+	similarity, err := s.compareDocument(providerCtx, tenantID, liveImage, documentImage, settings.VerificationThreshold)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.DocumentVerification{
+		Verified:   similarity >= settings.VerificationThreshold,
+		Similarity: similarity,
+		Threshold:  settings.VerificationThreshold,
+		LatencyMs:  time.Since(start).Milliseconds(),
+	}, nil
+}
+
+// checkLiveFace applies the face count, quality and framing checks of a verify to the live image
+func (s *FaceService) checkLiveFace(ctx context.Context, tenantID uuid.UUID, imageBytes []byte, settings domain.TenantSettings) error {
+	detectedFaces, err := s.providerFor(ctx).DetectFaces(ctx, imageBytes)
+	if err != nil {
+		return providerError(tenantID, "detect faces", err)
+	}
+	if len(detectedFaces) == 0 {
+		return domain.ErrNoFaceDetected
+	}
+	if len(detectedFaces) > domain.MaxFacesPerImage && settings.OnMultipleFaces != domain.MultipleFacesUseLargest {
+		return domain.ErrMultipleFaces.WithFaceCount(len(detectedFaces), domain.MaxFacesPerImage)
+	}
+	if detectedFaces[0].QualityScore < settings.MinQuality {
+		return domain.ErrLowQualityImage
+	}
+	return checkFaceInFrame(detectedFaces[0].BoundingBox, imageBytes, settings)
+}
+
+// compareDocument returns the similarity between the live and document faces. Providers that
+// compare images (Rekognition) get both images, since indexing there would store the faces;
+// the others compare embeddings, which they compute without storing anything.
+func (s *FaceService) compareDocument(ctx context.Context, tenantID uuid.UUID, liveImage, documentImage []byte, threshold float64) (float64, error) {
+	prov := s.providerFor(ctx)
+
+	if comparer, ok := prov.(imageComparer); ok {
+		similarity, err := comparer.CompareFaceImages(ctx, liveImage, documentImage, threshold)
+		if err != nil {
+			return 0, providerError(tenantID, "compare document", err)
+		}
+		return similarity, nil
+	}
+
+	_, liveEmbedding, err := prov.IndexFace(ctx, liveImage)
+	if err != nil {
+		return 0, providerError(tenantID, "index live face", err)
+	}
+	_, documentEmbedding, err := prov.IndexFace(ctx, documentImage)
+	if err != nil {
+		return 0, documentError(tenantID, "index document face", err)
+	}
+
+	similarity, err := prov.CompareFaces(ctx, liveEmbedding, documentEmbedding)
+	if err != nil {
+		return 0, fmt.Errorf("tenant %s: compare document: %w", tenantID, err)
+	}
+	return similarity, nil
+}
+
+// documentError is providerError for the document image, whose missing face is reported
+// apart from the live image's
+func documentError(tenantID uuid.UUID, op string, err error) error {
+	var noFace *provider.NoFaceError
+	if errors.As(err, &noFace) {
+		return domain.ErrNoFaceInDocument.WithError(fmt.Errorf("tenant %s: %s: %w", tenantID, op, err))
+	}
+	return providerError(tenantID, op, err)
+}
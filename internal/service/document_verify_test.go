@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/provider"
+)
+
+func TestFaceService_VerifyDocument(t *testing.T) {
+	liveImage := []byte("live")
+	documentImage := []byte("document")
+	liveEmbedding := []float64{1, 0}
+	documentEmbedding := []float64{0.9, 0.1}
+
+	tests := []struct {
+		name         string
+		policy       domain.VerifyPolicy
+		setupMocks   func(fp *MockFaceProvider)
+		wantVerified bool
+		wantErr      *domain.AppError
+	}{
+		{
+			name: "match",
+			setupMocks: func(fp *MockFaceProvider) {
+				fp.On("DetectFaces", mock.Anything, mock.Anything).Return([]provider.DetectedFace{{Confidence: 0.99}}, nil)
+				fp.On("IndexFace", mock.Anything, liveImage).Return("", liveEmbedding, nil)
+				fp.On("IndexFace", mock.Anything, documentImage).Return("", documentEmbedding, nil)
+				fp.On("CompareFaces", mock.Anything, liveEmbedding, documentEmbedding).Return(0.91, nil)
+			},
+			wantVerified: true,
+		},
+		{
+			name: "no match",
+			setupMocks: func(fp *MockFaceProvider) {
+				fp.On("DetectFaces", mock.Anything, mock.Anything).Return([]provider.DetectedFace{{Confidence: 0.99}}, nil)
+				fp.On("IndexFace", mock.Anything, liveImage).Return("", liveEmbedding, nil)
+				fp.On("IndexFace", mock.Anything, documentImage).Return("", documentEmbedding, nil)
+				fp.On("CompareFaces", mock.Anything, liveEmbedding, documentEmbedding).Return(0.41, nil)
+			},
+			wantVerified: false,
+		},
+		{
+			name: "no face in document",
+			setupMocks: func(fp *MockFaceProvider) {
+				fp.On("DetectFaces", mock.Anything, liveImage).Return([]provider.DetectedFace{{Confidence: 0.99}}, nil)
+				fp.On("DetectFaces", mock.Anything, documentImage).Return([]provider.DetectedFace{}, nil)
+			},
+			wantErr: domain.ErrNoFaceInDocument,
+		},
+		{
+			name: "document face lost when indexing",
+			setupMocks: func(fp *MockFaceProvider) {
+				fp.On("DetectFaces", mock.Anything, mock.Anything).Return([]provider.DetectedFace{{Confidence: 0.99}}, nil)
+				fp.On("IndexFace", mock.Anything, liveImage).Return("", liveEmbedding, nil)
+				fp.On("IndexFace", mock.Anything, documentImage).Return("", []float64(nil), &provider.NoFaceError{})
+			},
+			wantErr: domain.ErrNoFaceInDocument,
+		},
+		{
+			name: "no face in live image",
+			setupMocks: func(fp *MockFaceProvider) {
+				fp.On("DetectFaces", mock.Anything, liveImage).Return([]provider.DetectedFace{}, nil)
+			},
+			wantErr: domain.ErrNoFaceDetected,
+		},
+		{
+			name: "several faces in live image",
+			setupMocks: func(fp *MockFaceProvider) {
+				fp.On("DetectFaces", mock.Anything, liveImage).Return([]provider.DetectedFace{{Confidence: 0.99}, {Confidence: 0.97}}, nil)
+			},
+			wantErr: domain.ErrMultipleFaces,
+		},
+		{
+			name:   "live image passes liveness",
+			policy: domain.VerifyPolicyMatchAndLiveness,
+			setupMocks: func(fp *MockFaceProvider) {
+				fp.On("DetectFaces", mock.Anything, mock.Anything).Return([]provider.DetectedFace{{Confidence: 0.99}}, nil)
+				fp.On("CheckLiveness", mock.Anything, liveImage, mock.Anything).Return(&provider.LivenessResult{IsLive: true, Confidence: 0.95}, nil)
+				fp.On("IndexFace", mock.Anything, liveImage).Return("", liveEmbedding, nil)
+				fp.On("IndexFace", mock.Anything, documentImage).Return("", documentEmbedding, nil)
+				fp.On("CompareFaces", mock.Anything, liveEmbedding, documentEmbedding).Return(0.91, nil)
+			},
+			wantVerified: true,
+		},
+		{
+			name:   "spoofed live image",
+			policy: domain.VerifyPolicyMatchOrLiveness,
+			setupMocks: func(fp *MockFaceProvider) {
+				fp.On("DetectFaces", mock.Anything, liveImage).Return([]provider.DetectedFace{{Confidence: 0.99}}, nil)
+				fp.On("CheckLiveness", mock.Anything, liveImage, mock.Anything).Return(&provider.LivenessResult{IsLive: false, Confidence: 0.12}, nil)
+			},
+			wantErr: domain.ErrLivenessFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			faceRepo := &MockFaceRepository{}
+			verificationRepo := &MockVerificationRepository{}
+			faceProvider := &MockFaceProvider{}
+			tt.setupMocks(faceProvider)
+
+			svc := NewFaceService(faceRepo, verificationRepo, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+			settings := domain.DefaultTenantSettings()
+			if tt.policy != "" {
+				settings.VerifyPolicy = tt.policy
+			}
+
+			result, err := svc.VerifyDocument(context.Background(), uuid.New(), liveImage, documentImage, settings)
+			if tt.wantErr != nil {
+				var appErr *domain.AppError
+				require.ErrorAs(t, err, &appErr)
+				assert.Equal(t, tt.wantErr.Code, appErr.Code)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantVerified, result.Verified)
+				assert.Equal(t, settings.VerificationThreshold, result.Threshold)
+			}
+
+			// Nothing is stored
+			faceRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			verificationRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			faceProvider.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFaceService_VerifyDocument_ImageComparer(t *testing.T) {
+	liveImage := []byte("live")
+	documentImage := []byte("document")
+	settings := domain.DefaultTenantSettings()
+
+	faceProvider := &imageComparingProvider{}
+	faceProvider.On("DetectFaces", mock.Anything, mock.Anything).Return([]provider.DetectedFace{{Confidence: 0.99}}, nil)
+	faceProvider.On("CompareFaceImages", mock.Anything, liveImage, documentImage, settings.VerificationThreshold).Return(0.97, nil)
+
+	svc := NewFaceService(&MockFaceRepository{}, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+
+	result, err := svc.VerifyDocument(context.Background(), uuid.New(), liveImage, documentImage, settings)
+	require.NoError(t, err)
+
+	assert.True(t, result.Verified)
+	assert.Equal(t, 0.97, result.Similarity)
+	faceProvider.AssertExpectations(t)
+	// Indexing would add both faces to the Rekognition collection
+	faceProvider.AssertNotCalled(t, "IndexFace", mock.Anything, mock.Anything)
+}
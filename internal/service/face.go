@@ -1,15 +1,23 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"image"
 	"log/slog"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/imaging"
 	"github.com/saturnino-fabrica-de-software/rekko/internal/provider"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/ratelimit"
 )
 
 type FaceRepositoryInterface interface {
@@ -17,13 +25,19 @@ type FaceRepositoryInterface interface {
 	Update(ctx context.Context, face *domain.Face) error
 	GetByExternalID(ctx context.Context, tenantID uuid.UUID, externalID string) (*domain.Face, error)
 	Delete(ctx context.Context, tenantID uuid.UUID, externalID string) error
-	SearchByEmbedding(ctx context.Context, tenantID uuid.UUID, embedding []float64, threshold float64, limit int) ([]domain.SearchMatch, error)
+	SearchByEmbedding(ctx context.Context, tenantID uuid.UUID, embedding []float64, fingerprint domain.EmbeddingFingerprint, threshold float64, limit int) ([]domain.SearchMatch, error)
+	SearchByEmbeddingSegmented(ctx context.Context, tenantID uuid.UUID, embedding []float64, fingerprint domain.EmbeddingFingerprint, threshold float64, segments domain.SegmentThresholdSettings, limit int) ([]domain.SearchMatch, error)
 	CountByTenant(ctx context.Context, tenantID uuid.UUID) (int, error)
-	List(ctx context.Context, tenantID uuid.UUID, limit, offset int) ([]*domain.Face, error)
+	ExistsBatch(ctx context.Context, tenantID uuid.UUID, externalIDs []string) (map[string]domain.RegistrationCheck, error)
+	BulkUpdateMetadata(ctx context.Context, tenantID uuid.UUID, externalIDs []string, patch map[string]interface{}, maxBytes int) ([]string, error)
+	List(ctx context.Context, tenantID uuid.UUID, filter domain.FaceListFilter) ([]*domain.Face, int, error)
 }
 
 type VerificationRepositoryInterface interface {
 	Create(ctx context.Context, v *domain.Verification) error
+	RecentConfidences(ctx context.Context, tenantID uuid.UUID, externalID string, since time.Time, limit int) ([]float64, error)
+	LastVerifiedAt(ctx context.Context, tenantID uuid.UUID, externalID string, requireLiveness bool) (*time.Time, error)
+	LastVerifiedGate(ctx context.Context, tenantID uuid.UUID, externalID string, isTest, fullPassOnly bool) (*domain.GateVisit, error)
 }
 
 type SearchAuditRepositoryInterface interface {
@@ -34,13 +48,50 @@ type RateLimiterInterface interface {
 	CheckSearchLimit(ctx context.Context, tenantID uuid.UUID, limit int) error
 }
 
+// FaceInsertRecorder is notified of newly inserted faces, e.g. to refresh planner statistics
+type FaceInsertRecorder interface {
+	RecordInserts(n int)
+}
+
 type FaceService struct {
-	faceRepo         FaceRepositoryInterface
-	verificationRepo VerificationRepositoryInterface
-	searchAuditRepo  SearchAuditRepositoryInterface
-	provider         provider.FaceProvider
-	rateLimiter      RateLimiterInterface
-	threshold        float64
+	faceRepo          FaceRepositoryInterface
+	verificationRepo  VerificationRepositoryInterface
+	searchAuditRepo   SearchAuditRepositoryInterface
+	provider          provider.FaceProvider
+	rateLimiter       RateLimiterInterface
+	embeddingModel    string
+	maxMetadataBytes  int
+	maxImageDimension int
+	// blankThreshold is the minimum luminance standard deviation of an upload, see WithBlankImageThreshold
+	blankThreshold float64
+	dataRegion     string
+	// normalizeEmbeddings L2-normalizes embeddings before storage and search
+	normalizeEmbeddings bool
+	// maxEmbeddingMagnitude caps the L2 norm of registered and query embeddings
+	maxEmbeddingMagnitude float64
+	// insertRecorder is optional, see WithInsertRecorder
+	insertRecorder FaceInsertRecorder
+	// maxVerificationAge caps the window of RecentlyVerified
+	maxVerificationAge time.Duration
+	// providerTimeouts bounds provider calls per operation, see WithProviderTimeouts
+	providerTimeouts ProviderTimeouts
+	// faceCounts is optional, see WithFaceCountCache
+	faceCounts *faceCountCache
+	// collectionCounter is optional, see WithCollectionCounter
+	collectionCounter CollectionCounter
+	// parallelVerify overlaps the stored face lookup with the probe analysis, see WithParallelVerify
+	parallelVerify bool
+
+	// Optional candidate provider evaluated in parallel, see WithShadowProvider
+	shadowProvider provider.FaceProvider
+	shadowRepo     ShadowComparisonRepositoryInterface
+	shadowModel    string
+	// sampleRoll draws the provider experiment arm of a request, nil uses math/rand
+	sampleRoll func() float64
+
+	// Optional provider for test-environment API keys, see WithTestProvider
+	testProvider provider.FaceProvider
+	testModel    string
 }
 
 func NewFaceService(
@@ -51,135 +102,860 @@ func NewFaceService(
 	rateLimiter RateLimiterInterface,
 ) *FaceService {
 	return &FaceService{
-		faceRepo:         faceRepo,
-		verificationRepo: verificationRepo,
-		searchAuditRepo:  searchAuditRepo,
-		provider:         faceProvider,
-		rateLimiter:      rateLimiter,
-		threshold:        0.8,
+		faceRepo:              faceRepo,
+		verificationRepo:      verificationRepo,
+		searchAuditRepo:       searchAuditRepo,
+		provider:              faceProvider,
+		rateLimiter:           rateLimiter,
+		embeddingModel:        embeddingModelOf(faceProvider),
+		maxMetadataBytes:      domain.DefaultMaxMetadataBytes,
+		maxVerificationAge:    domain.DefaultMaxVerificationAge,
+		maxEmbeddingMagnitude: domain.DefaultMaxEmbeddingMagnitude,
+	}
+}
+
+// embeddingModelOf returns the embedding model advertised by the provider, if any
+func embeddingModelOf(faceProvider provider.FaceProvider) string {
+	if modeler, ok := faceProvider.(provider.EmbeddingModeler); ok {
+		return modeler.EmbeddingModel()
+	}
+	return ""
+}
+
+// WithEmbeddingModel overrides the embedding model recorded with each face.
+// Use it when the provider's model changes behind the same provider configuration.
+func (s *FaceService) WithEmbeddingModel(model string) *FaceService {
+	if model != "" {
+		s.embeddingModel = model
+	}
+	return s
+}
+
+// WithMaxMetadataBytes sets the maximum serialized size of face metadata
+func (s *FaceService) WithMaxMetadataBytes(maxBytes int) *FaceService {
+	if maxBytes > 0 {
+		s.maxMetadataBytes = maxBytes
+	}
+	return s
+}
+
+// WithBlankImageThreshold rejects images whose luminance standard deviation (0-255) is below
+// minStdDev with INVALID_IMAGE before they reach the provider, so frames from a covered camera do
+// not spend a provider call on NO_FACE. Zero disables the check.
+func (s *FaceService) WithBlankImageThreshold(minStdDev float64) *FaceService {
+	if minStdDev >= 0 {
+		s.blankThreshold = minStdDev
+	}
+	return s
+}
+
+// WithMaxImageDimension downscales images whose width or height exceeds maxDimension
+// before they reach the provider. Zero disables downscaling.
+func (s *FaceService) WithMaxImageDimension(maxDimension int) *FaceService {
+	if maxDimension >= 0 {
+		s.maxImageDimension = maxDimension
+	}
+	return s
+}
+
+// WithDataRegion sets the region where this deployment's provider and database keep biometric data.
+// Tenants pinned to a data_region are only served when it matches; an unset region serves none of them.
+func (s *FaceService) WithDataRegion(region string) *FaceService {
+	s.dataRegion = strings.ToLower(strings.TrimSpace(region))
+	return s
+}
+
+// WithEmbeddingNormalization L2-normalizes provider embeddings before they are stored and
+// query embeddings before search. Leave it off for providers that already normalize.
+func (s *FaceService) WithEmbeddingNormalization(enabled bool) *FaceService {
+	s.normalizeEmbeddings = enabled
+	return s
+}
+
+// WithMaxEmbeddingMagnitude sets the largest L2 norm accepted for registered and query embeddings
+func (s *FaceService) WithMaxEmbeddingMagnitude(maxMagnitude float64) *FaceService {
+	if maxMagnitude > 0 {
+		s.maxEmbeddingMagnitude = maxMagnitude
 	}
+	return s
+}
+
+// WithInsertRecorder reports each newly registered face to recorder.
+// Re-registrations update the existing row and are not reported.
+func (s *FaceService) WithInsertRecorder(recorder FaceInsertRecorder) *FaceService {
+	s.insertRecorder = recorder
+	return s
 }
 
-func (s *FaceService) WithThreshold(threshold float64) *FaceService {
-	s.threshold = threshold
+// WithParallelVerify runs the stored face lookup of a verify alongside the provider analysis of
+// the probe image instead of before it, which cuts the lookup from the latency. The provider is
+// then called even for verifies the lookup refuses (unknown external_id, anti-passback); those
+// calls are cancelled as soon as the lookup fails.
+func (s *FaceService) WithParallelVerify(enabled bool) *FaceService {
+	s.parallelVerify = enabled
 	return s
 }
 
-func (s *FaceService) Register(ctx context.Context, tenantID uuid.UUID, externalID string, imageBytes []byte, requireLiveness bool, livenessThreshold float64) (*domain.Face, error) {
+// WithMaxVerificationAge caps the window a recently-verified check may ask for
+func (s *FaceService) WithMaxVerificationAge(maxAge time.Duration) *FaceService {
+	if maxAge > 0 {
+		s.maxVerificationAge = maxAge
+	}
+	return s
+}
+
+// normalizeImage downscales oversized images and rejects blank ones. On downscale failure the
+// original bytes are returned so the provider can still accept or reject the upload itself.
+func (s *FaceService) normalizeImage(imageBytes []byte) ([]byte, error) {
+	out, img, err := imaging.DownscaleImage(imageBytes, s.maxImageDimension)
+	if err != nil {
+		slog.Warn("image downscale failed, sending original", "error", err, "size_bytes", len(imageBytes))
+		out = imageBytes
+	}
+	if img != nil {
+		slog.Debug("image downscaled before provider call",
+			"original_bytes", len(imageBytes),
+			"resized_bytes", len(out),
+			"max_dimension", s.maxImageDimension,
+		)
+	}
+
+	// A downscaled image is checked as already decoded; otherwise the check decodes the upload
+	var blankErr error
+	if img != nil {
+		blankErr = imaging.CheckBlankImage(img, s.blankThreshold)
+	} else {
+		blankErr = imaging.CheckBlank(out, s.blankThreshold)
+	}
+	if blankErr != nil {
+		return nil, &domain.AppError{
+			Code:       domain.ErrInvalidImage.Code,
+			Message:    "Image appears blank, check that the camera is not covered",
+			StatusCode: domain.ErrInvalidImage.StatusCode,
+			Err:        blankErr,
+		}
+	}
+	return out, nil
+}
+
+// validateMetadata rejects metadata whose serialized JSON exceeds the configured cap
+func (s *FaceService) validateMetadata(metadata map[string]interface{}) error {
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return domain.ErrValidationFailed.WithError(fmt.Errorf("invalid metadata: %w", err))
+	}
+
+	if len(encoded) > s.metadataCap() {
+		return domain.ErrMetadataTooLarge
+	}
+
+	return nil
+}
+
+// metadataCap returns the configured metadata size cap in bytes
+func (s *FaceService) metadataCap() int {
+	if s.maxMetadataBytes <= 0 {
+		return domain.DefaultMaxMetadataBytes
+	}
+	return s.maxMetadataBytes
+}
+
+// fingerprint identifies the model that produced an embedding for the request in ctx.
+// Returns a zero fingerprint when the provider does not advertise its model.
+func (s *FaceService) fingerprint(ctx context.Context, embedding []float64) domain.EmbeddingFingerprint {
+	model := s.modelFor(ctx)
+	if model == "" {
+		return domain.EmbeddingFingerprint{}
+	}
+	return domain.NewEmbeddingFingerprint(model, len(embedding))
+}
+
+// checkFaceInFrame rejects a face whose box comes closer than the tenant's face_edge_margin to
+// an image border. Pixel boxes are converted with the image size; when the image can't be
+// decoded the check is skipped and the provider's own quality checks apply.
+func checkFaceInFrame(box provider.BoundingBox, imageBytes []byte, settings domain.TenantSettings) error {
+	if settings.FaceEdgeMargin <= 0 {
+		return nil
+	}
+
+	if box.InPixels() {
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(imageBytes))
+		if err != nil || cfg.Width == 0 || cfg.Height == 0 {
+			slog.Debug("face edge check skipped, image size unknown", "error", err)
+			return nil
+		}
+		box = box.Fraction(cfg.Width, cfg.Height)
+	}
+
+	if !box.WithinMargin(settings.FaceEdgeMargin) {
+		return domain.ErrFaceOutOfFrame
+	}
+	return nil
+}
+
+// providerError wraps a provider failure, turning rejections that carry user feedback
+// into NO_FACE_DETECTED with the reasons the client can act on
+func providerError(tenantID uuid.UUID, op string, err error) error {
+	var noFace *provider.NoFaceError
+	if errors.As(err, &noFace) {
+		reasons := make([]string, len(noFace.Reasons))
+		for i, r := range noFace.Reasons {
+			reasons[i] = string(r)
+		}
+		return domain.ErrNoFaceDetected.WithError(fmt.Errorf("tenant %s: %s: %w", tenantID, op, err)).WithReasons(reasons...)
+	}
+	var multipleFaces *provider.MultipleFacesError
+	if errors.As(err, &multipleFaces) {
+		return domain.ErrMultipleFaces.WithError(fmt.Errorf("tenant %s: %s: %w", tenantID, op, err)).WithFaceCount(multipleFaces.Count, domain.MaxFacesPerImage)
+	}
+	return fmt.Errorf("tenant %s: %s: %w", tenantID, op, err)
+}
+
+func (s *FaceService) Register(ctx context.Context, tenantID uuid.UUID, externalID string, imageBytes []byte, metadata map[string]interface{}, settings domain.TenantSettings) (*domain.Face, error) {
+	// Refuse to send the image to a provider outside the tenant's data region
+	if err := s.checkDataResidency(tenantID, settings); err != nil {
+		return nil, err
+	}
+
+	// Reject oversized metadata before calling the provider
+	if err := s.validateMetadata(metadata); err != nil {
+		return nil, err
+	}
+
+	imageBytes, err := s.normalizeImage(imageBytes)
+	if err != nil {
+		return nil, err
+	}
+
 	// Use AnalyzeFace for a single HTTP call (3 calls -> 1 call optimization)
-	analysis, err := s.provider.AnalyzeFace(ctx, imageBytes)
+	providerCtx, cancel := s.providerContext(ctx, providerOpRegister)
+	analysis, err := s.providerFor(ctx).AnalyzeFace(providerCtx, imageBytes)
+	cancel()
 	if err != nil {
-		return nil, fmt.Errorf("tenant %s: analyze face: %w", tenantID, err)
+		return nil, providerError(tenantID, "analyze face", err)
 	}
 
-	// Validate face count
+	// Validate face count (provider analysis describes the largest face)
 	if analysis.FaceCount == 0 {
 		return nil, domain.ErrNoFaceDetected
 	}
-	if analysis.FaceCount > 1 {
-		return nil, domain.ErrMultipleFaces
+	if analysis.FaceCount > domain.MaxFacesPerImage && settings.OnMultipleFaces != domain.MultipleFacesUseLargest {
+		return nil, domain.ErrMultipleFaces.WithFaceCount(analysis.FaceCount, domain.MaxFacesPerImage)
+	}
+
+	// Reject images below the tenant's minimum quality for its security level
+	if analysis.QualityScore < settings.MinQuality {
+		return nil, domain.ErrLowQualityImage
+	}
+	if err := checkFaceInFrame(analysis.BoundingBox, imageBytes, settings); err != nil {
+		return nil, err
 	}
 
 	// Validate liveness if required
-	if requireLiveness && analysis.LivenessScore < livenessThreshold {
+	if settings.RequireLiveness && analysis.LivenessScore < settings.LivenessThreshold {
 		return nil, domain.ErrLivenessFailed
 	}
 
+	// Refuse corrupt provider embeddings before they can poison search. Providers that do not
+	// expose embeddings (Rekognition) return none, and the face is stored without one.
+	if len(analysis.Embedding) > 0 {
+		if err := s.checkEmbedding(analysis.Embedding); err != nil {
+			return nil, err
+		}
+	}
+
+	// Providers without embeddings match faces in their own collection, so the face is indexed
+	// there and the provider face ID is kept for admin cross-referencing
+	var providerFaceID string
+	if len(analysis.Embedding) == 0 {
+		providerCtx, cancel := s.providerContext(ctx, providerOpRegister)
+		providerFaceID, _, err = s.providerFor(ctx).IndexFace(providerCtx, imageBytes)
+		cancel()
+		if err != nil {
+			return nil, providerError(tenantID, "index face", err)
+		}
+	}
+
 	// Check if external_id already has a face registered
 	// If yes: update (allows re-registration with better photo)
 	// If no: create new
+	embedding := s.prepareEmbedding(analysis.Embedding)
+	fingerprint := s.fingerprint(ctx, embedding)
+	expiresAt := faceExpiry(settings, time.Now())
 	existingFace, err := s.faceRepo.GetByExternalID(ctx, tenantID, externalID)
 	if err == nil && existingFace != nil {
-		// Update existing face with new embedding/quality
-		existingFace.Embedding = analysis.Embedding
-		existingFace.QualityScore = analysis.QualityScore
-		if err := s.faceRepo.Update(ctx, existingFace); err != nil {
-			return nil, fmt.Errorf("tenant %s: update face: %w", tenantID, err)
-		}
-		// Get the updated face to return complete data
-		return s.faceRepo.GetByExternalID(ctx, tenantID, externalID)
+		return s.reRegister(ctx, tenantID, externalID, existingFace, embedding, fingerprint, settings.EmbeddingPrecision, analysis.QualityScore, providerFaceID, metadata, expiresAt)
 	}
 
 	// Create new face
 	face := &domain.Face{
-		TenantID:     tenantID,
-		ExternalID:   externalID,
-		Embedding:    analysis.Embedding,
-		QualityScore: analysis.QualityScore,
+		TenantID:         tenantID,
+		ExternalID:       externalID,
+		Embedding:        embedding,
+		EmbeddingModel:   fingerprint.Model,
+		EmbeddingVersion: fingerprint.Version,
+		Metadata:         metadata,
+		QualityScore:     analysis.QualityScore,
+		IsTest:           domain.IsTestMode(ctx),
+		ExpiresAt:        expiresAt,
+		ProviderFaceID:   providerFaceID,
+		EmbeddingHash:    faceEmbeddingHash(embedding, fingerprint, providerFaceID),
+
+		EmbeddingPrecision: settings.EmbeddingPrecision,
 	}
 
 	if err := s.faceRepo.Create(ctx, face); err != nil {
-		return nil, err
+		if !errors.Is(err, domain.ErrFaceExists) {
+			s.deleteProviderFace(ctx, tenantID, providerFaceID)
+			return nil, err
+		}
+		// A concurrent first registration inserted the face after our lookup:
+		// converge on it through the update path instead of failing
+		existingFace, lookupErr := s.faceRepo.GetByExternalID(ctx, tenantID, externalID)
+		if lookupErr != nil {
+			s.deleteProviderFace(ctx, tenantID, providerFaceID)
+			return nil, err
+		}
+		return s.reRegister(ctx, tenantID, externalID, existingFace, embedding, fingerprint, settings.EmbeddingPrecision, analysis.QualityScore, providerFaceID, metadata, expiresAt)
+	}
+	s.forgetFaceCount(tenantID)
+	if s.insertRecorder != nil {
+		s.insertRecorder.RecordInserts(1)
 	}
 
 	return face, nil
 }
 
-func (s *FaceService) Verify(ctx context.Context, tenantID uuid.UUID, externalID string, imageBytes []byte) (*domain.Verification, error) {
+// faceExpiry returns when a face registered at now expires under the settings' FaceTTL, nil when it never does
+func faceExpiry(settings domain.TenantSettings, now time.Time) *time.Time {
+	if settings.FaceTTL <= 0 {
+		return nil
+	}
+	expiresAt := now.Add(settings.FaceTTL)
+	return &expiresAt
+}
+
+// faceEmbeddingHash hashes the stored embedding, or the provider face ID for providers
+// without embeddings; empty when the face has neither
+func faceEmbeddingHash(embedding []float64, fingerprint domain.EmbeddingFingerprint, providerFaceID string) string {
+	switch {
+	case len(embedding) > 0:
+		return domain.EmbeddingHash(embedding, fingerprint)
+	case providerFaceID != "":
+		return domain.ProviderFaceIDHash(providerFaceID)
+	default:
+		return ""
+	}
+}
+
+// reRegister replaces the embedding of an already registered face,
+// allowing re-registration with a better photo. A non-nil expiresAt restarts the face's TTL.
+// The embedding is stored at the tenant's current precision.
+// The provider face indexed for the previous photo is removed once the new one is stored.
+func (s *FaceService) reRegister(ctx context.Context, tenantID uuid.UUID, externalID string, existingFace *domain.Face, embedding []float64, fingerprint domain.EmbeddingFingerprint, precision domain.EmbeddingPrecision, qualityScore float64, providerFaceID string, metadata map[string]interface{}, expiresAt *time.Time) (*domain.Face, error) {
+	// A test key must not overwrite a live enrollment, nor a live key a test one
+	if !sameEnvironment(ctx, existingFace) {
+		s.deleteProviderFace(ctx, tenantID, providerFaceID)
+		return nil, domain.ErrFaceExists.WithError(fmt.Errorf("tenant %s: external_id %s is registered in another environment", tenantID, externalID))
+	}
+
+	previousProviderFaceID := existingFace.ProviderFaceID
+
+	// Re-registration also migrates the face to the current embedding model
+	existingFace.Embedding = embedding
+	existingFace.EmbeddingModel = fingerprint.Model
+	existingFace.EmbeddingVersion = fingerprint.Version
+	existingFace.EmbeddingPrecision = precision
+	existingFace.QualityScore = qualityScore
+	existingFace.ProviderFaceID = providerFaceID
+	existingFace.EmbeddingHash = faceEmbeddingHash(embedding, fingerprint, providerFaceID)
+	if metadata != nil {
+		existingFace.Metadata = metadata
+	}
+	if expiresAt != nil {
+		existingFace.ExpiresAt = expiresAt
+	}
+	if err := s.faceRepo.Update(ctx, existingFace); err != nil {
+		s.deleteProviderFace(ctx, tenantID, providerFaceID)
+		return nil, fmt.Errorf("tenant %s: update face: %w", tenantID, err)
+	}
+	if previousProviderFaceID != providerFaceID {
+		s.deleteProviderFace(ctx, tenantID, previousProviderFaceID)
+	}
+	// Get the updated face to return complete data
+	return s.faceRepo.GetByExternalID(ctx, tenantID, externalID)
+}
+
+func (s *FaceService) Verify(ctx context.Context, tenantID uuid.UUID, externalID string, imageBytes []byte, settings domain.TenantSettings) (*domain.Verification, error) {
 	start := time.Now()
 
+	if err := s.checkDataResidency(tenantID, settings); err != nil {
+		return nil, err
+	}
+
+	ctx = s.routeProvider(ctx, settings)
+	providerCtx, cancel := s.providerContext(ctx, providerOpVerify)
+	defer cancel()
+
+	var storedFace *domain.Face
+	var probe verifyProbe
+	// An alternate sample is only probed once the stored face shows the alternate can verify it
+	if s.parallelVerify && !alternateRouted(ctx) {
+		var err error
+		storedFace, probe, err = s.loadAndProbeConcurrently(ctx, providerCtx, tenantID, externalID, imageBytes, settings, start)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		storedFace, err = s.loadVerifyFace(ctx, tenantID, externalID, settings, start)
+		if err != nil {
+			return nil, err
+		}
+		if alternateRouted(ctx) && !s.alternateCanVerify(storedFace) {
+			ctx = routePrimary(ctx)
+			providerCtx = routePrimary(providerCtx)
+		}
+		probe = s.probeVerifyImage(providerCtx, tenantID, imageBytes, settings)
+	}
+	// A segment_thresholds override for the stored face replaces the tenant's threshold
+	settings.VerificationThreshold, settings.VerificationThresholdSource = settings.VerifyThresholdFor(storedFace.Metadata)
+	if probe.err != nil {
+		if probe.rejection != "" {
+			s.recordRejectedVerification(ctx, storedFace, probe.rejection, start, settings)
+		}
+		return nil, probe.err
+	}
+	imageBytes, newEmbedding := probe.image, probe.embedding
+
+	// Never compare embeddings produced by different models
+	current := s.fingerprint(ctx, newEmbedding)
+	if !current.Compatible(storedFace.Fingerprint()) {
+		slog.Warn("embedding model mismatch",
+			"tenant_id", tenantID,
+			"external_id", externalID,
+			"stored", storedFace.Fingerprint().String(),
+			"current", current.String(),
+		)
+		return nil, domain.ErrEmbeddingModelMismatch
+	}
+
+	similarity, err := s.providerFor(ctx).CompareFaces(providerCtx, storedFace.Embedding, newEmbedding)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: compare faces: %w", tenantID, err)
+	}
+
+	matchPassed := similarity >= settings.VerificationThreshold
+	hysteresisPass := false
+	if !matchPassed {
+		matchPassed = s.withinHysteresis(ctx, tenantID, externalID, similarity, settings)
+		hysteresisPass = matchPassed
+	}
+
+	// Liveness runs for every policy that uses it, so both component results are always reported
+	var livenessPassed *bool
+	var livenessScore *float64
+	if settings.VerifyPolicy.RequiresLiveness() {
+		liveness, err := s.providerFor(ctx).CheckLiveness(providerCtx, imageBytes, settings.LivenessThreshold)
+		if err != nil {
+			return nil, providerError(tenantID, "check liveness for verification", err)
+		}
+		livenessPassed = &liveness.IsLive
+		livenessScore = &liveness.Confidence
+	}
+
+	livenessOK := livenessPassed != nil && *livenessPassed
+	verified := settings.VerifyPolicy.Decide(matchPassed, livenessOK)
+	latencyMs := time.Since(start).Milliseconds()
+
+	verification := &domain.Verification{
+		TenantID:       tenantID,
+		FaceID:         &storedFace.ID,
+		ExternalID:     externalID,
+		Verified:       verified,
+		Confidence:     similarity,
+		MatchPassed:    matchPassed,
+		LivenessPassed: livenessPassed,
+		LivenessScore:  livenessScore,
+		FailReason:     settings.VerifyPolicy.FailReason(matchPassed, livenessOK),
+		LatencyMs:      latencyMs,
+		IsTest:         domain.IsTestMode(ctx),
+		ClientIP:       domain.ClientIPFrom(ctx),
+		Provider:       s.providerName(ctx),
+
+		ProviderVariant:  providerVariant(ctx),
+		ThresholdApplied: settings.VerificationThreshold,
+		ThresholdSource:  settings.VerificationThresholdSource,
+		HysteresisPass:   hysteresisPass,
+	}
+
+	// Audit log - error is intentionally not returned
+	// The verification result was already determined successfully
+	// In production, this would be logged with proper observability
+	auditErr := s.verificationRepo.Create(ctx, verification)
+
+	// Suggest re-enrollment when confidence drifted below the enrolled quality.
+	// The history includes this attempt, so it is skipped when the audit failed.
+	if auditErr == nil && matchPassed && settings.Features().ReenrollCheck {
+		verification.ReenrollSuggested = s.checkReenroll(ctx, tenantID, externalID, storedFace, settings)
+	}
+
+	// Evaluate the shadow provider without affecting the decision
+	if s.shadowEnabled(ctx, settings) {
+		s.shadowVerify(storedFace, imageBytes, settings.VerificationThreshold, verification)
+	}
+
+	return verification, nil
+}
+
+// loadVerifyFace reads the face a verify compares against and refuses attempts that cannot or
+// may not be verified, before the probe image is matched
+func (s *FaceService) loadVerifyFace(ctx context.Context, tenantID uuid.UUID, externalID string, settings domain.TenantSettings, start time.Time) (*domain.Face, error) {
 	storedFace, err := s.faceRepo.GetByExternalID(ctx, tenantID, externalID)
 	if err != nil {
 		return nil, err
 	}
+	if !sameEnvironment(ctx, storedFace) {
+		return nil, domain.ErrFaceNotFound
+	}
+
+	// Providers that do not expose embeddings (Rekognition) store none, and source images are not
+	// kept to compare the probe against, so there is nothing to verify with
+	if len(storedFace.Embedding) == 0 {
+		return nil, domain.ErrVerifyNotSupportedWithoutSource
+	}
 
-	detectedFaces, err := s.provider.DetectFaces(ctx, imageBytes)
+	// Refuse another gate before spending provider calls on the attempt
+	if err := s.checkAntiPassback(ctx, tenantID, externalID, settings); err != nil {
+		if errors.Is(err, domain.ErrAntiPassbackViolation) {
+			s.recordRejectedVerification(ctx, storedFace, domain.FailReasonAntiPassback, start, settings)
+		}
+		return nil, err
+	}
+
+	return storedFace, nil
+}
+
+// verifyProbe is the face analysis of a verify image
+type verifyProbe struct {
+	// image is the normalized image sent to the provider
+	image     []byte
+	embedding []float64
+	// rejection is the fail reason recorded for err, empty when the rejection is not recorded
+	rejection string
+	err       error
+}
+
+// probeVerifyImage detects and indexes the face of a verify image. It reads no stored data, so
+// it can run alongside loadVerifyFace.
+func (s *FaceService) probeVerifyImage(ctx context.Context, tenantID uuid.UUID, imageBytes []byte, settings domain.TenantSettings) verifyProbe {
+	imageBytes, err := s.normalizeImage(imageBytes)
+	if err != nil {
+		// Recorded like the NO_FACE the provider returned for blank frames before the pre-check
+		return verifyProbe{rejection: domain.FailReasonNoFace, err: err}
+	}
+	probe := verifyProbe{image: imageBytes}
+
+	detectedFaces, err := s.providerFor(ctx).DetectFaces(ctx, imageBytes)
 	if err != nil {
-		return nil, fmt.Errorf("tenant %s: detect faces: %w", tenantID, err)
+		probe.rejection, probe.err = faceRejectionReason(err), providerError(tenantID, "detect faces", err)
+		return probe
 	}
 
 	if len(detectedFaces) == 0 {
-		return nil, domain.ErrNoFaceDetected
+		probe.rejection, probe.err = domain.FailReasonNoFace, domain.ErrNoFaceDetected
+		return probe
 	}
 
-	if len(detectedFaces) > 1 {
-		return nil, domain.ErrMultipleFaces
+	// Providers rank detections by box area and index the largest face
+	if len(detectedFaces) > domain.MaxFacesPerImage && settings.OnMultipleFaces != domain.MultipleFacesUseLargest {
+		probe.rejection, probe.err = domain.FailReasonMultipleFaces, domain.ErrMultipleFaces.WithFaceCount(len(detectedFaces), domain.MaxFacesPerImage)
+		return probe
 	}
 
-	_, newEmbedding, err := s.provider.IndexFace(ctx, imageBytes)
+	if detectedFaces[0].QualityScore < settings.MinQuality {
+		probe.err = domain.ErrLowQualityImage
+		return probe
+	}
+	if err := checkFaceInFrame(detectedFaces[0].BoundingBox, imageBytes, settings); err != nil {
+		probe.err = err
+		return probe
+	}
+
+	_, probe.embedding, err = s.providerFor(ctx).IndexFace(ctx, imageBytes)
 	if err != nil {
-		return nil, fmt.Errorf("tenant %s: index face for verification: %w", tenantID, err)
+		probe.rejection, probe.err = faceRejectionReason(err), providerError(tenantID, "index face for verification", err)
 	}
+	return probe
+}
+
+// loadAndProbeConcurrently runs loadVerifyFace and probeVerifyImage at the same time, see
+// WithParallelVerify. A refused lookup cancels the probe, whose provider calls would be wasted.
+// A rejected probe still waits for the lookup: its error comes first, as in a sequential verify,
+// and its face is needed to record the rejection.
+func (s *FaceService) loadAndProbeConcurrently(ctx, providerCtx context.Context, tenantID uuid.UUID, externalID string, imageBytes []byte, settings domain.TenantSettings, start time.Time) (*domain.Face, verifyProbe, error) {
+	probeCtx, cancelProbe := context.WithCancel(providerCtx)
+	defer cancelProbe()
 
-	similarity, err := s.provider.CompareFaces(ctx, storedFace.Embedding, newEmbedding)
+	// Buffered so a cancelled probe finishes without a reader
+	probes := make(chan verifyProbe, 1)
+	go func() {
+		probes <- s.probeVerifyImage(probeCtx, tenantID, imageBytes, settings)
+	}()
+
+	storedFace, err := s.loadVerifyFace(ctx, tenantID, externalID, settings, start)
 	if err != nil {
-		return nil, fmt.Errorf("tenant %s: compare faces: %w", tenantID, err)
+		return nil, verifyProbe{}, err
 	}
 
-	verified := similarity >= s.threshold
-	latencyMs := time.Since(start).Milliseconds()
+	select {
+	case probe := <-probes:
+		return storedFace, probe, nil
+	case <-ctx.Done():
+		return nil, verifyProbe{}, ctx.Err()
+	}
+}
+
+// checkAntiPassback rejects a verify from a gate other than the one where the external_id last
+// passed a verification, while that verification is within the tenant's anti-passback window.
+// Verifies from an unknown gate, or after a verification at an unknown gate, are not checked.
+func (s *FaceService) checkAntiPassback(ctx context.Context, tenantID uuid.UUID, externalID string, settings domain.TenantSettings) error {
+	if !settings.Features().AntiPassback {
+		return nil
+	}
+	gate := domain.ClientIPFrom(ctx)
+	if gate == "" {
+		return nil
+	}
+
+	// Every admission counts, including borderline ones allowed by hysteresis
+	last, err := s.verificationRepo.LastVerifiedGate(ctx, tenantID, externalID, domain.IsTestMode(ctx), false)
+	if err != nil {
+		return fmt.Errorf("tenant %s: anti-passback: %w", tenantID, err)
+	}
+	if last == nil || last.Gate == "" || last.Gate == gate {
+		return nil
+	}
+	if time.Since(last.At) >= settings.AntiPassback.Window {
+		return nil
+	}
+
+	slog.Info("anti-passback violation",
+		"tenant_id", tenantID,
+		"external_id", externalID,
+		"gate", gate,
+		"last_gate", last.Gate,
+	)
+	return domain.ErrAntiPassbackViolation
+}
+
+// withinHysteresis reports whether a verify just below the threshold passes the match under the
+// tenant's verify_hysteresis, because the external_id passed a verification within the window.
+// Only a pass at the threshold opens the window, so borderline allows cannot chain one another.
+// A failed lookup keeps the denial.
+func (s *FaceService) withinHysteresis(ctx context.Context, tenantID uuid.UUID, externalID string, similarity float64, settings domain.TenantSettings) bool {
+	if !settings.VerifyHysteresis.Enabled() || similarity < settings.VerificationThreshold-settings.VerifyHysteresis.Margin {
+		return false
+	}
+
+	last, err := s.verificationRepo.LastVerifiedGate(ctx, tenantID, externalID, domain.IsTestMode(ctx), true)
+	if err != nil {
+		slog.Warn("verify hysteresis lookup failed", "error", err, "tenant_id", tenantID, "external_id", externalID)
+		return false
+	}
+	if last == nil || time.Since(last.At) >= settings.VerifyHysteresis.Window {
+		return false
+	}
+
+	slog.Info("verify hysteresis allow",
+		"tenant_id", tenantID,
+		"external_id", externalID,
+		"confidence", similarity,
+		"threshold", settings.VerificationThreshold,
+		"margin", settings.VerifyHysteresis.Margin,
+		"last_verified_at", last.At,
+	)
+	return true
+}
+
+// recordRejectedVerification stores a verify attempt rejected before matching as a failed
+// verification with its reason, for tenants with record_rejected_verifications. Like the audit
+// of completed verifications, a storage error does not change the response.
+func (s *FaceService) recordRejectedVerification(ctx context.Context, face *domain.Face, reason string, start time.Time, settings domain.TenantSettings) {
+	if !settings.Features().RecordRejectedVerifications {
+		return
+	}
 
 	verification := &domain.Verification{
-		TenantID:   tenantID,
-		FaceID:     &storedFace.ID,
-		ExternalID: externalID,
-		Verified:   verified,
-		Confidence: similarity,
-		LatencyMs:  latencyMs,
+		TenantID:   face.TenantID,
+		FaceID:     &face.ID,
+		ExternalID: face.ExternalID,
+		FailReason: reason,
+		LatencyMs:  time.Since(start).Milliseconds(),
+		IsTest:     domain.IsTestMode(ctx),
+		ClientIP:   domain.ClientIPFrom(ctx),
+		Provider:   s.providerName(ctx),
+
+		ProviderVariant: providerVariant(ctx),
 	}
+	if err := s.verificationRepo.Create(ctx, verification); err != nil {
+		slog.Warn("failed to record rejected verification",
+			"error", err,
+			"tenant_id", face.TenantID,
+			"external_id", face.ExternalID,
+			"fail_reason", reason,
+		)
+	}
+}
 
-	// Audit log - error is intentionally not returned
-	// The verification result was already determined successfully
-	// In production, this would be logged with proper observability
-	_ = s.verificationRepo.Create(ctx, verification)
+// faceRejectionReason returns the fail reason of a provider error reporting that the image has
+// no usable face or too many faces, and "" for other errors, which are not recorded
+func faceRejectionReason(err error) string {
+	var noFace *provider.NoFaceError
+	if errors.As(err, &noFace) {
+		return domain.FailReasonNoFace
+	}
+	var multipleFaces *provider.MultipleFacesError
+	if errors.As(err, &multipleFaces) {
+		return domain.FailReasonMultipleFaces
+	}
+	return ""
+}
 
-	return verification, nil
+// checkReenroll reads the recent verification history of a face and returns a suggestion
+// when its confidence stayed below the enrolled quality minus the tenant's margin
+func (s *FaceService) checkReenroll(ctx context.Context, tenantID uuid.UUID, externalID string, face *domain.Face, settings domain.TenantSettings) *domain.ReenrollSuggestion {
+	// Only attempts since the last (re-)registration reflect the current enrollment
+	confidences, err := s.verificationRepo.RecentConfidences(ctx, tenantID, externalID, face.EnrolledAt, settings.ReenrollWindow+1)
+	if err != nil {
+		slog.Warn("re-enrollment check failed", "error", err, "tenant_id", tenantID, "external_id", externalID)
+		return nil
+	}
+
+	if !domain.SuggestReenroll(face.QualityScore, settings.ReenrollMargin, settings.ReenrollWindow, confidences) {
+		return nil
+	}
+
+	return &domain.ReenrollSuggestion{
+		EnrolledQuality:   face.QualityScore,
+		Margin:            settings.ReenrollMargin,
+		RecentConfidences: confidences[:settings.ReenrollWindow],
+	}
+}
+
+// Compare returns the similarity between the stored faces of two external IDs,
+// e.g. to spot one person enrolled under several accounts. Match uses the tenant's verification
+// threshold, the stricter segment override of the two faces when they have one.
+func (s *FaceService) Compare(ctx context.Context, tenantID uuid.UUID, externalIDA, externalIDB string, settings domain.TenantSettings) (*domain.FaceComparison, error) {
+	if err := s.checkDataResidency(tenantID, settings); err != nil {
+		return nil, err
+	}
+
+	externalIDA = strings.TrimSpace(externalIDA)
+	externalIDB = strings.TrimSpace(externalIDB)
+	if externalIDA == "" || externalIDB == "" {
+		return nil, domain.ErrValidationFailed.WithError(errors.New("both external_ids are required"))
+	}
+	if externalIDA == externalIDB {
+		return nil, domain.ErrValidationFailed.WithError(errors.New("external_ids must be different"))
+	}
+
+	faceA, err := s.faceRepo.GetByExternalID(ctx, tenantID, externalIDA)
+	if err != nil {
+		return nil, err
+	}
+	faceB, err := s.faceRepo.GetByExternalID(ctx, tenantID, externalIDB)
+	if err != nil {
+		return nil, err
+	}
+	if !sameEnvironment(ctx, faceA) || !sameEnvironment(ctx, faceB) {
+		return nil, domain.ErrFaceNotFound
+	}
+
+	// Providers that do not expose embeddings (Rekognition) store none, and source images are not kept
+	if len(faceA.Embedding) == 0 || len(faceB.Embedding) == 0 {
+		return nil, domain.ErrEmbeddingUnavailable
+	}
+
+	// Never compare embeddings produced by different models
+	if !faceA.Fingerprint().Compatible(faceB.Fingerprint()) {
+		return nil, domain.ErrEmbeddingModelMismatch
+	}
+
+	providerCtx, cancel := s.providerContext(ctx, providerOpVerify)
+	defer cancel()
+
+	similarity, err := s.providerFor(ctx).CompareFaces(providerCtx, faceA.Embedding, faceB.Embedding)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: compare faces: %w", tenantID, err)
+	}
+
+	thresholdA, _ := settings.VerifyThresholdFor(faceA.Metadata)
+	thresholdB, _ := settings.VerifyThresholdFor(faceB.Metadata)
+	threshold := max(thresholdA, thresholdB)
+
+	return &domain.FaceComparison{
+		ExternalIDA: externalIDA,
+		ExternalIDB: externalIDB,
+		Similarity:  similarity,
+		Threshold:   threshold,
+		Match:       similarity >= threshold,
+	}, nil
 }
 
 func (s *FaceService) Delete(ctx context.Context, tenantID uuid.UUID, externalID string) error {
 	// Verify face exists and belongs to tenant before deleting
-	if _, err := s.faceRepo.GetByExternalID(ctx, tenantID, externalID); err != nil {
+	face, err := s.faceRepo.GetByExternalID(ctx, tenantID, externalID)
+	if err != nil {
 		return err
 	}
+	// A test key must not delete a live enrollment, nor a live key a test one
+	if !sameEnvironment(ctx, face) {
+		return domain.ErrFaceNotFound
+	}
 
 	// Delete from database
 	if err := s.faceRepo.Delete(ctx, tenantID, externalID); err != nil {
 		return fmt.Errorf("tenant %s: delete face: %w", tenantID, err)
 	}
+	s.forgetFaceCount(tenantID)
+	s.deleteProviderFace(ctx, tenantID, face.ProviderFaceID)
 
 	return nil
 }
 
+// ForgetDeletedFace removes the provider face of a face deleted outside FaceService, such as
+// by the expiry sweep, and drops the tenant's cached face count
+func (s *FaceService) ForgetDeletedFace(ctx context.Context, face *domain.Face) {
+	// A test face was indexed by the test provider
+	if face.IsTest {
+		ctx = domain.WithTestMode(ctx)
+	}
+	s.forgetFaceCount(face.TenantID)
+	s.deleteProviderFace(ctx, face.TenantID, face.ProviderFaceID)
+}
+
+// deleteProviderFace removes a face from the provider's collection. Failures are only logged:
+// the database row is the source of truth, and an orphaned provider face matches no face.
+func (s *FaceService) deleteProviderFace(ctx context.Context, tenantID uuid.UUID, providerFaceID string) {
+	if providerFaceID == "" {
+		return
+	}
+	providerCtx, cancel := s.providerContext(ctx, providerOpRegister)
+	defer cancel()
+	if err := s.providerFor(ctx).DeleteFace(providerCtx, providerFaceID); err != nil {
+		slog.Warn("failed to delete provider face",
+			"error", err,
+			"tenant_id", tenantID,
+			"provider_face_id", providerFaceID,
+		)
+	}
+}
+
 // GetByExternalID retrieves a face by external ID for a tenant
 func (s *FaceService) GetByExternalID(ctx context.Context, tenantID uuid.UUID, externalID string) (*domain.Face, error) {
 	face, err := s.faceRepo.GetByExternalID(ctx, tenantID, externalID)
@@ -189,13 +965,133 @@ func (s *FaceService) GetByExternalID(ctx context.Context, tenantID uuid.UUID, e
 	return face, nil
 }
 
-func (s *FaceService) List(ctx context.Context, tenantID uuid.UUID, limit, offset int) ([]*domain.Face, error) {
-	return s.faceRepo.List(ctx, tenantID, limit, offset)
+// RecentlyVerified reports whether externalID passed a verification within the window,
+// so a gate can re-admit someone without a new capture. within must not exceed the max verification age.
+// When the tenant's verify policy guarantees liveness, a past decision that skipped or failed
+// liveness never re-admits, even if it was verified under an earlier, laxer policy.
+func (s *FaceService) RecentlyVerified(ctx context.Context, tenantID uuid.UUID, externalID string, within time.Duration, settings domain.TenantSettings) (*domain.RecentVerification, error) {
+	if within <= 0 || within > s.maxVerificationAge {
+		return nil, domain.ErrValidationFailed.WithError(fmt.Errorf("within must be positive and at most %s, got %s", s.maxVerificationAge, within))
+	}
+
+	requireLiveness := settings.VerifyPolicy.GuaranteesLiveness()
+	lastVerifiedAt, err := s.verificationRepo.LastVerifiedAt(ctx, tenantID, externalID, requireLiveness)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: %w", tenantID, err)
+	}
+
+	return &domain.RecentVerification{
+		ExternalID:       externalID,
+		RecentlyVerified: lastVerifiedAt != nil && time.Since(*lastVerifiedAt) <= within,
+		LastVerifiedAt:   lastVerifiedAt,
+		WithinSeconds:    int64(within / time.Second),
+		LivenessRequired: requireLiveness,
+	}, nil
+}
+
+// ExistsBatch reports, for each external ID, whether a face is registered for the tenant
+// Duplicate and blank IDs are ignored; at most domain.MaxExistsBatchSize unique IDs are accepted
+func (s *FaceService) ExistsBatch(ctx context.Context, tenantID uuid.UUID, externalIDs []string) (map[string]domain.RegistrationCheck, error) {
+	unique := uniqueExternalIDs(externalIDs)
+	if len(unique) == 0 {
+		return nil, domain.ErrValidationFailed.WithError(errors.New("external_ids must contain at least one id"))
+	}
+	if len(unique) > domain.MaxExistsBatchSize {
+		return nil, domain.ErrValidationFailed.WithError(
+			fmt.Errorf("external_ids must contain at most %d ids, got %d", domain.MaxExistsBatchSize, len(unique)),
+		)
+	}
+
+	results, err := s.faceRepo.ExistsBatch(ctx, tenantID, unique)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: check faces exist: %w", tenantID, err)
+	}
+
+	return results, nil
+}
+
+// BulkUpdateMetadata merges patch into the metadata of every listed face of the tenant
+// IDs are trimmed and deduplicated as in ExistsBatch; both the patch and each merged document
+// are held to the metadata size cap
+func (s *FaceService) BulkUpdateMetadata(ctx context.Context, tenantID uuid.UUID, externalIDs []string, patch map[string]interface{}) (*domain.BulkMetadataResult, error) {
+	unique := uniqueExternalIDs(externalIDs)
+	if len(unique) == 0 {
+		return nil, domain.ErrValidationFailed.WithError(errors.New("external_ids must contain at least one id"))
+	}
+	if len(unique) > domain.MaxBulkMetadataBatchSize {
+		return nil, domain.ErrValidationFailed.WithError(
+			fmt.Errorf("external_ids must contain at most %d ids, got %d", domain.MaxBulkMetadataBatchSize, len(unique)),
+		)
+	}
+	if len(patch) == 0 {
+		return nil, domain.ErrValidationFailed.WithError(errors.New("metadata must contain at least one key"))
+	}
+	if err := s.validateMetadata(patch); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.faceRepo.BulkUpdateMetadata(ctx, tenantID, unique, patch, s.metadataCap())
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: bulk update metadata: %w", tenantID, err)
+	}
+
+	found := make(map[string]struct{}, len(updated))
+	for _, id := range updated {
+		found[id] = struct{}{}
+	}
+
+	result := &domain.BulkMetadataResult{
+		Updated:     len(updated),
+		NotFoundIDs: []string{},
+	}
+	for _, id := range unique {
+		if _, ok := found[id]; !ok {
+			result.NotFoundIDs = append(result.NotFoundIDs, id)
+		}
+	}
+	result.NotFound = len(result.NotFoundIDs)
+
+	return result, nil
+}
+
+// uniqueExternalIDs trims the IDs and drops blanks and duplicates, keeping first-seen order
+func uniqueExternalIDs(externalIDs []string) []string {
+	seen := make(map[string]struct{}, len(externalIDs))
+	unique := make([]string, 0, len(externalIDs))
+	for _, id := range externalIDs {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+	return unique
+}
+
+func (s *FaceService) List(ctx context.Context, tenantID uuid.UUID, filter domain.FaceListFilter) ([]*domain.Face, int, error) {
+	return s.faceRepo.List(ctx, tenantID, filter)
 }
 
-func (s *FaceService) CheckLiveness(ctx context.Context, imageBytes []byte, threshold float64) (*domain.LivenessResult, error) {
+// CheckLiveness runs the provider's liveness check on the image against the tenant's threshold
+func (s *FaceService) CheckLiveness(ctx context.Context, tenantID uuid.UUID, imageBytes []byte, settings domain.TenantSettings) (*domain.LivenessResult, error) {
+	if err := s.checkDataResidency(tenantID, settings); err != nil {
+		return nil, err
+	}
+
+	imageBytes, err := s.normalizeImage(imageBytes)
+	if err != nil {
+		return nil, err
+	}
+
 	// Call provider to check liveness
-	providerResult, err := s.provider.CheckLiveness(ctx, imageBytes, threshold)
+	providerCtx, cancel := s.providerContext(ctx, providerOpDetect)
+	defer cancel()
+
+	providerResult, err := s.providerFor(ctx).CheckLiveness(providerCtx, imageBytes, settings.LivenessThreshold)
 	if err != nil {
 		return nil, fmt.Errorf("check liveness: %w", err)
 	}
@@ -216,42 +1112,65 @@ func (s *FaceService) CheckLiveness(ctx context.Context, imageBytes []byte, thre
 	return result, nil
 }
 
+// CountFaces returns how many faces the provider detects in the image.
+// Nothing is indexed, stored or audited: only the count leaves this method.
+func (s *FaceService) CountFaces(ctx context.Context, tenantID uuid.UUID, imageBytes []byte, settings domain.TenantSettings) (int, error) {
+	if err := s.checkDataResidency(tenantID, settings); err != nil {
+		return 0, err
+	}
+
+	imageBytes, err := s.normalizeImage(imageBytes)
+	if err != nil {
+		return 0, err
+	}
+
+	providerCtx, cancel := s.providerContext(ctx, providerOpDetect)
+	defer cancel()
+
+	detectedFaces, err := s.providerFor(ctx).DetectFaces(providerCtx, imageBytes)
+	if err != nil {
+		return 0, providerError(tenantID, "detect faces", err)
+	}
+
+	return len(detectedFaces), nil
+}
+
 // Search performs a 1:N face search against all faces in the tenant
 // Returns matches above threshold, ordered by similarity
 func (s *FaceService) Search(ctx context.Context, tenant *domain.Tenant, imageBytes []byte, threshold float64, maxResults int, clientIP string) (*domain.SearchResult, error) {
 	start := time.Now()
 
-	// 1. Extract settings from tenant
+	// 1-5. Check search is enabled, apply defaults, validate and rate limit
 	settings := tenant.GetSettings()
-
-	// 2. Verify if search is enabled
-	if !settings.SearchEnabled {
-		return nil, domain.ErrSearchNotEnabled
+	_, thresholdSource := settings.SearchThresholdFor(threshold)
+	threshold, maxResults, warning, err := s.prepareSearch(ctx, tenant.ID, settings, threshold, maxResults)
+	if err != nil {
+		return nil, err
 	}
 
-	// 3. Apply defaults if not provided
-	if threshold <= 0 {
-		threshold = settings.SearchThreshold
-	}
-	if maxResults <= 0 {
-		maxResults = settings.SearchMaxResults
+	// Nothing can match in an empty collection, so don't pay for the provider call
+	if s.collectionEmpty(ctx, tenant.ID) {
+		result, err := s.emptyCollectionResult(ctx, tenant.ID, threshold, maxResults, settings, clientIP, start, warning)
+		if err != nil {
+			return nil, err
+		}
+		result.ThresholdSource = thresholdSource
+		return result, nil
 	}
 
-	// 4. Validate parameters
-	if threshold < 0 || threshold > 1 {
-		return nil, domain.ErrInvalidThreshold
+	// 6. Analyze face with single HTTP call (optimized from IndexFace + CheckLiveness), with
+	// the alternate provider for the tenant's provider experiment sample
+	ctx = s.routeProvider(ctx, settings)
+	if alternateRouted(ctx) && !s.alternateCanSearch() {
+		ctx = routePrimary(ctx)
 	}
-	if maxResults < 1 || maxResults > 50 {
-		return nil, domain.ErrInvalidMaxResults
-	}
-
-	// 5. Check rate limit
-	if err := s.rateLimiter.CheckSearchLimit(ctx, tenant.ID, settings.SearchRateLimit); err != nil {
-		return nil, domain.ErrSearchRateLimitExceeded
+	imageBytes, err = s.normalizeImage(imageBytes)
+	if err != nil {
+		return nil, err
 	}
-
-	// 6. Analyze face with single HTTP call (optimized from IndexFace + CheckLiveness)
-	analysis, err := s.provider.AnalyzeFace(ctx, imageBytes)
+	providerCtx, cancel := s.providerContext(ctx, providerOpSearch)
+	analysis, err := s.providerFor(ctx).AnalyzeFace(providerCtx, imageBytes)
+	cancel()
 	if err != nil {
 		return nil, fmt.Errorf("tenant %s: analyze face: %w", tenant.ID, err)
 	}
@@ -260,53 +1179,298 @@ func (s *FaceService) Search(ctx context.Context, tenant *domain.Tenant, imageBy
 	if analysis.FaceCount == 0 {
 		return nil, domain.ErrNoFaceDetected
 	}
+	if analysis.QualityScore < settings.MinQuality {
+		return nil, domain.ErrLowQualityImage
+	}
 
-	// 7. Apply liveness check based on SecurityLevel
-	switch settings.SecurityLevel {
-	case domain.SecurityEnhanced:
-		// Basic passive liveness - minimum 0.5 confidence
+	// 7. Apply the liveness check: the search_liveness feature (always on at the maximum
+	// security level) enforces the tenant's threshold, enhanced alone a basic 0.5 minimum
+	switch {
+	case settings.Features().SearchLiveness:
+		if analysis.LivenessScore < settings.LivenessThreshold {
+			return nil, domain.ErrLivenessFailed
+		}
+	case settings.SecurityLevel == domain.SecurityEnhanced:
 		if analysis.LivenessScore < 0.5 {
 			return nil, domain.ErrLowLivenessConfidence
 		}
-	case domain.SecurityMaximum:
-		// Full liveness check with tenant's configured threshold
-		if analysis.LivenessScore < settings.LivenessThreshold {
-			return nil, domain.ErrLivenessFailed
+		// SecurityStandard without search_liveness: no liveness check (fastest path)
+	}
+
+	// 8-11. Search similar faces using the embedding from analysis and audit the result
+	result, err := s.searchEmbedding(ctx, tenant.ID, analysis.Embedding, threshold, maxResults, settings, clientIP, start)
+	if err != nil {
+		return nil, err
+	}
+
+	// 12. Only a top match at or above the identify floor counts as an identification,
+	// and an ambiguous one only if the tenant lets it
+	result.Identified = settings.IdentifiesResult(result)
+	result.Warning = warning
+	result.ThresholdSource = thresholdSource
+
+	// 13. Evaluate the shadow provider without affecting the result
+	if s.shadowEnabled(ctx, settings) {
+		s.shadowSearch(tenant.ID, imageBytes, threshold, maxResults, result)
+	}
+
+	return result, nil
+}
+
+// SearchByEmbedding performs a 1:N search with an embedding extracted by the caller.
+// It skips the provider and therefore liveness, so tenants must opt in explicitly.
+func (s *FaceService) SearchByEmbedding(ctx context.Context, tenant *domain.Tenant, embedding []float64, threshold float64, maxResults int, clientIP string) (*domain.SearchResult, error) {
+	start := time.Now()
+
+	// 1. Verify the tenant opted in to embedding search
+	settings := tenant.GetSettings()
+	if !settings.Features().SearchByEmbedding {
+		return nil, domain.ErrSearchByEmbeddingNotEnabled
+	}
+
+	// 2. Validate embedding before spending rate limit
+	if err := s.validateEmbedding(embedding); err != nil {
+		return nil, err
+	}
+
+	// 3. Check search is enabled, apply defaults, validate and rate limit
+	_, thresholdSource := settings.SearchThresholdFor(threshold)
+	threshold, maxResults, warning, err := s.prepareSearch(ctx, tenant.ID, settings, threshold, maxResults)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Search and audit
+	result, err := s.searchEmbedding(ctx, tenant.ID, embedding, threshold, maxResults, settings, clientIP, start)
+	if err != nil {
+		return nil, err
+	}
+	result.Warning = warning
+	result.ThresholdSource = thresholdSource
+	return result, nil
+}
+
+// checkDataResidency rejects operations for tenants pinned to a region other than the service's
+func (s *FaceService) checkDataResidency(tenantID uuid.UUID, settings domain.TenantSettings) error {
+	if s.inDataRegion(settings) {
+		return nil
+	}
+
+	slog.Warn("data residency violation blocked",
+		"tenant_id", tenantID,
+		"tenant_region", settings.DataRegion,
+		"service_region", s.dataRegion,
+	)
+	return domain.ErrDataResidencyViolation
+}
+
+// inDataRegion reports whether the tenant's data may be processed in the service's region
+func (s *FaceService) inDataRegion(settings domain.TenantSettings) bool {
+	return settings.DataRegion == "" || settings.DataRegion == s.dataRegion
+}
+
+// prepareSearch applies tenant defaults to threshold and maxResults, validates them and
+// consumes one unit of the tenant's search rate limit. Tenants with clamp_max_results get an
+// out-of-range maxResults lowered to the ceiling, explained by the returned warning.
+func (s *FaceService) prepareSearch(ctx context.Context, tenantID uuid.UUID, settings domain.TenantSettings, threshold float64, maxResults int) (float64, int, string, error) {
+	// Verify if search is enabled
+	if !settings.Features().Search {
+		return 0, 0, "", domain.ErrSearchNotEnabled
+	}
+
+	// Verify the tenant's data may be processed in this region
+	if err := s.checkDataResidency(tenantID, settings); err != nil {
+		return 0, 0, "", err
+	}
+
+	// Apply defaults if not provided
+	threshold, _ = settings.SearchThresholdFor(threshold)
+	if maxResults <= 0 {
+		maxResults = settings.SearchMaxResults
+	}
+
+	// Validate parameters
+	if threshold < 0 || threshold > 1 {
+		return 0, 0, "", domain.ErrInvalidThreshold
+	}
+	var warning string
+	if maxResults > domain.MaxSearchResults && settings.ClampMaxResults {
+		warning = fmt.Sprintf("max_results %d exceeds the limit of %d and was clamped", maxResults, domain.MaxSearchResults)
+		maxResults = domain.MaxSearchResults
+	}
+	if maxResults < 1 || maxResults > domain.MaxSearchResults {
+		return 0, 0, "", domain.ErrInvalidMaxResults
+	}
+
+	// Check rate limit
+	if err := s.rateLimiter.CheckSearchLimit(ctx, tenantID, settings.SearchRateLimit); err != nil {
+		var limitErr *ratelimit.LimitError
+		if errors.As(err, &limitErr) {
+			return 0, 0, "", domain.ErrSearchRateLimitExceeded.WithRateLimit(limitErr.Limit, limitErr.Count, limitErr.ResetAt)
 		}
-		// SecurityStandard: no liveness check (fastest path)
+		return 0, 0, "", domain.ErrSearchRateLimitExceeded
 	}
 
-	// 8. Search similar faces in database using embedding from analysis
-	matches, err := s.faceRepo.SearchByEmbedding(ctx, tenant.ID, analysis.Embedding, threshold, maxResults)
+	return threshold, maxResults, warning, nil
+}
+
+// searchEmbedding looks up similar faces and records the search audit as the tenant's search_audit_mode asks.
+// With a search_margin it flags results whose top two matches are closer than it as ambiguous.
+// Matches in a segment_thresholds segment must reach their segment's threshold instead of threshold.
+func (s *FaceService) searchEmbedding(ctx context.Context, tenantID uuid.UUID, embedding []float64, threshold float64, maxResults int, settings domain.TenantSettings, clientIP string, start time.Time) (*domain.SearchResult, error) {
+	// The margin needs the runner-up even when a single result was asked for
+	margin := settings.SearchMargin
+	limit := maxResults
+	if margin > 0 && limit < 2 {
+		limit = 2
+	}
+
+	// Search similar faces in database, holding faces of a segment to its threshold in SQL
+	// so faces below theirs don't take the places of matches within limit
+	embedding = s.prepareEmbedding(embedding)
+	var matches []domain.SearchMatch
+	var err error
+	if len(settings.SegmentThresholds.Search) > 0 {
+		matches, err = s.faceRepo.SearchByEmbeddingSegmented(ctx, tenantID, embedding, s.fingerprint(ctx, embedding), threshold, settings.SegmentThresholds, limit)
+	} else {
+		matches, err = s.faceRepo.SearchByEmbedding(ctx, tenantID, embedding, s.fingerprint(ctx, embedding), threshold, limit)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("tenant %s: search faces: %w", tenant.ID, err)
+		return nil, fmt.Errorf("tenant %s: search faces: %w", tenantID, err)
+	}
+
+	ambiguous := domain.AmbiguousMatches(matches, margin)
+	if len(matches) > maxResults {
+		matches = matches[:maxResults]
+	}
+
+	// Calculate latency
+	latencyMs := time.Since(start).Milliseconds()
+	searchID := uuid.New()
+	isTest := domain.IsTestMode(ctx)
+	variant := providerVariant(ctx)
+
+	if err := s.recordSearchAudit(settings.SearchAuditMode, tenantID, searchID, matches, threshold, maxResults, latencyMs, clientIP, isTest, variant); err != nil {
+		return nil, err
 	}
 
-	// 9. Calculate latency
+	// Return result (TotalFaces removed from hot path - can be added back async if needed)
+	return &domain.SearchResult{
+		Matches:          matches,
+		TotalFaces:       0, // Removed CountByTenant from hot path for performance
+		LatencyMs:        latencyMs,
+		SearchID:         searchID,
+		Ambiguous:        ambiguous,
+		ThresholdApplied: threshold,
+		ProviderVariant:  variant,
+	}, nil
+}
+
+// emptyCollectionResult answers a search of a tenant without faces, still recording its audit
+func (s *FaceService) emptyCollectionResult(ctx context.Context, tenantID uuid.UUID, threshold float64, maxResults int, settings domain.TenantSettings, clientIP string, start time.Time, warning string) (*domain.SearchResult, error) {
 	latencyMs := time.Since(start).Milliseconds()
 	searchID := uuid.New()
+	isTest := domain.IsTestMode(ctx)
+
+	if err := s.recordSearchAudit(settings.SearchAuditMode, tenantID, searchID, nil, threshold, maxResults, latencyMs, clientIP, isTest, ""); err != nil {
+		return nil, err
+	}
+
+	return &domain.SearchResult{
+		Matches:   []domain.SearchMatch{},
+		LatencyMs: latencyMs,
+		SearchID:  searchID,
+		Reason:    domain.SearchReasonEmptyCollection,
+		Warning:   warning,
+
+		ThresholdApplied: threshold,
+	}, nil
+}
+
+// prepareEmbedding applies the configured normalization to an embedding
+func (s *FaceService) prepareEmbedding(embedding []float64) []float64 {
+	if !s.normalizeEmbeddings {
+		return embedding
+	}
+	return l2Normalize(embedding)
+}
+
+// l2Normalize returns a copy of v scaled to unit length. Zero vectors are returned unchanged.
+func l2Normalize(v []float64) []float64 {
+	var sum float64
+	for _, x := range v {
+		sum += x * x
+	}
+	if sum == 0 {
+		return v
+	}
+
+	norm := math.Sqrt(sum)
+	out := make([]float64, len(v))
+	for i, x := range v {
+		out[i] = x / norm
+	}
+	return out
+}
+
+// validateEmbedding checks a caller-supplied embedding has the expected dimension, finite values
+// and a sane magnitude
+func (s *FaceService) validateEmbedding(embedding []float64) error {
+	if len(embedding) != domain.EmbeddingDimension {
+		return domain.ErrInvalidEmbedding
+	}
+	return s.checkEmbedding(embedding)
+}
+
+// checkEmbedding rejects embeddings with NaN/Inf values or an L2 norm outside
+// [domain.MinEmbeddingMagnitude, maxEmbeddingMagnitude]. A norm that overflows counts as too large.
+func (s *FaceService) checkEmbedding(embedding []float64) error {
+	maxMagnitude := s.maxEmbeddingMagnitude
+	if maxMagnitude <= 0 {
+		maxMagnitude = domain.DefaultMaxEmbeddingMagnitude
+	}
+
+	var sum float64
+	for _, v := range embedding {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return domain.ErrInvalidEmbedding
+		}
+		sum += v * v
+	}
+
+	magnitude := math.Sqrt(sum)
+	if magnitude < domain.MinEmbeddingMagnitude || magnitude > maxMagnitude {
+		return domain.ErrInvalidEmbedding
+	}
+	return nil
+}
+
+// recordSearchAudit writes the search audit. In sync mode it waits for the write and fails the
+// search with AUDIT_WRITE_FAILED when it can't be stored; otherwise it writes in the background,
+// best-effort with panic recovery.
+func (s *FaceService) recordSearchAudit(mode domain.SearchAuditMode, tenantID, searchID uuid.UUID, matches []domain.SearchMatch, threshold float64, maxResults int, latencyMs int64, clientIP string, isTest bool, variant string) error {
+	if mode == domain.SearchAuditSync {
+		if err := s.createSearchAudit(tenantID, searchID, matches, threshold, maxResults, latencyMs, clientIP, isTest, variant); err != nil {
+			slog.Error("search audit write failed", "tenant_id", tenantID, "search_id", searchID, "error", err)
+			return domain.ErrAuditWriteFailed
+		}
+		return nil
+	}
 
-	// 10. Create audit log (async, best-effort with panic recovery)
 	go func() {
 		defer func() {
 			if r := recover(); r != nil {
-				slog.Error("panic in search audit", "panic", r, "tenant_id", tenant.ID, "search_id", searchID)
+				slog.Error("panic in search audit", "panic", r, "tenant_id", tenantID, "search_id", searchID)
 			}
 		}()
-		s.createSearchAudit(tenant.ID, searchID, matches, threshold, maxResults, latencyMs, clientIP)
+		// Best-effort audit log creation (errors are intentionally ignored)
+		_ = s.createSearchAudit(tenantID, searchID, matches, threshold, maxResults, latencyMs, clientIP, isTest, variant)
 	}()
-
-	// 11. Return result (TotalFaces removed from hot path - can be added back async if needed)
-	return &domain.SearchResult{
-		Matches:    matches,
-		TotalFaces: 0, // Removed CountByTenant from hot path for performance
-		LatencyMs:  latencyMs,
-		SearchID:   searchID,
-	}, nil
+	return nil
 }
 
-// createSearchAudit creates an audit log entry asynchronously
-func (s *FaceService) createSearchAudit(tenantID, searchID uuid.UUID, matches []domain.SearchMatch, threshold float64, maxResults int, latencyMs int64, clientIP string) {
+// createSearchAudit creates an audit log entry
+func (s *FaceService) createSearchAudit(tenantID, searchID uuid.UUID, matches []domain.SearchMatch, threshold float64, maxResults int, latencyMs int64, clientIP string, isTest bool, variant string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -318,6 +1482,9 @@ func (s *FaceService) createSearchAudit(tenantID, searchID uuid.UUID, matches []
 		MaxResults:   maxResults,
 		LatencyMs:    latencyMs,
 		ClientIP:     clientIP,
+		IsTest:       isTest,
+
+		ProviderVariant: variant,
 	}
 
 	// Add top match if exists
@@ -326,6 +1493,5 @@ func (s *FaceService) createSearchAudit(tenantID, searchID uuid.UUID, matches []
 		audit.TopMatchSimilarity = &matches[0].Similarity
 	}
 
-	// Best-effort audit log creation (errors are intentionally ignored)
-	_ = s.searchAuditRepo.Create(ctx, audit)
+	return s.searchAuditRepo.Create(ctx, audit)
 }
@@ -0,0 +1,77 @@
+//go:build benchguard
+
+package service
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"testing"
+)
+
+// benchBudget is the ceiling a benchmark must stay under; zero disables that check
+type benchBudget struct {
+	MaxNsPerOp     int64 `json:"max_ns_per_op"`
+	MaxAllocsPerOp int64 `json:"max_allocs_per_op"`
+}
+
+// guardedBenchmarks are the hot path benchmarks that can be given a budget
+var guardedBenchmarks = map[string]func(*testing.B){
+	"BenchmarkFaceService_Search":              BenchmarkFaceService_Search,
+	"BenchmarkFaceService_Search_NoMatches":    BenchmarkFaceService_Search_NoMatches,
+	"BenchmarkFaceService_Search_WithLiveness": BenchmarkFaceService_Search_WithLiveness,
+	"BenchmarkFaceService_Verify":              BenchmarkFaceService_Verify,
+	"BenchmarkExtractTenantSettings":           BenchmarkExtractTenantSettings,
+}
+
+// TestBenchmarkBudgets turns the targets documented on the benchmarks into guardrails.
+// It runs every benchmark listed in the budgets file and fails when ns/op or allocs/op
+// exceed its budget. Budgets are read from testdata/bench_budgets.json, or from the file
+// in REKKO_BENCH_BUDGETS so slower CI runners can use their own numbers. Allocation budgets
+// include what the testify mocks allocate, so they sit above the targets in the benchmark docs.
+//
+//	go test -tags=benchguard -run TestBenchmarkBudgets ./internal/service
+func TestBenchmarkBudgets(t *testing.T) {
+	path := os.Getenv("REKKO_BENCH_BUDGETS")
+	if path == "" {
+		path = "testdata/bench_budgets.json"
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read budgets: %v", err)
+	}
+	var budgets map[string]benchBudget
+	if err := json.Unmarshal(data, &budgets); err != nil {
+		t.Fatalf("parse budgets %s: %v", path, err)
+	}
+
+	names := make([]string, 0, len(budgets))
+	for name := range budgets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		budget := budgets[name]
+		t.Run(name, func(t *testing.T) {
+			bench, ok := guardedBenchmarks[name]
+			if !ok {
+				t.Fatalf("no guarded benchmark named %s", name)
+			}
+
+			result := testing.Benchmark(bench)
+			if result.N == 0 {
+				t.Fatalf("benchmark failed to run")
+			}
+			t.Logf("%d ns/op, %d allocs/op (budget %d ns/op, %d allocs/op)",
+				result.NsPerOp(), result.AllocsPerOp(), budget.MaxNsPerOp, budget.MaxAllocsPerOp)
+
+			if budget.MaxNsPerOp > 0 && result.NsPerOp() > budget.MaxNsPerOp {
+				t.Errorf("ns/op %d exceeds budget %d", result.NsPerOp(), budget.MaxNsPerOp)
+			}
+			if budget.MaxAllocsPerOp > 0 && result.AllocsPerOp() > budget.MaxAllocsPerOp {
+				t.Errorf("allocs/op %d exceeds budget %d", result.AllocsPerOp(), budget.MaxAllocsPerOp)
+			}
+		})
+	}
+}
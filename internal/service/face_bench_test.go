@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
@@ -75,7 +76,7 @@ func BenchmarkFaceService_Search(b *testing.B) {
 		},
 	}
 
-	faceRepo.On("SearchByEmbedding", mock.Anything, tenantID, embedding, 0.85, 10).
+	faceRepo.On("SearchByEmbedding", mock.Anything, tenantID, embedding, mock.Anything, 0.85, 10).
 		Return(matches, nil)
 
 	// Audit is async, may or may not be called depending on goroutine timing
@@ -151,7 +152,7 @@ func BenchmarkFaceService_Search_WithAudit(b *testing.B) {
 		},
 	}
 
-	faceRepo.On("SearchByEmbedding", mock.Anything, tenantID, mock.Anything, mock.Anything, mock.Anything).
+	faceRepo.On("SearchByEmbedding", mock.Anything, tenantID, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return(matches, nil)
 
 	// Explicitly expect audit creation
@@ -212,7 +213,7 @@ func BenchmarkFaceService_Search_NoMatches(b *testing.B) {
 		}, nil)
 
 	// No matches found
-	faceRepo.On("SearchByEmbedding", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+	faceRepo.On("SearchByEmbedding", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return([]domain.SearchMatch{}, nil)
 
 	searchAuditRepo.On("Create", mock.Anything, mock.Anything).
@@ -270,7 +271,7 @@ func BenchmarkFaceService_Search_WithLiveness(b *testing.B) {
 			FaceCount:     1,
 		}, nil)
 
-	faceRepo.On("SearchByEmbedding", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+	faceRepo.On("SearchByEmbedding", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return([]domain.SearchMatch{
 			{FaceID: uuid.New(), ExternalID: "user-live", Similarity: 0.93},
 		}, nil)
@@ -333,7 +334,7 @@ func BenchmarkFaceService_Verify(b *testing.B) {
 	b.ReportAllocs()
 
 	for i := 0; i < b.N; i++ {
-		verification, err := svc.Verify(context.Background(), tenantID, externalID, []byte("image"))
+		verification, err := svc.Verify(context.Background(), tenantID, externalID, []byte("image"), domain.DefaultTenantSettings())
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -343,6 +344,47 @@ func BenchmarkFaceService_Verify(b *testing.B) {
 	}
 }
 
+// BenchmarkFaceService_Verify_Latency compares a sequential verify with one that overlaps the
+// stored face lookup with the probe analysis (WithParallelVerify), under simulated latencies:
+// 5ms database lookup, 15ms detection and 15ms indexing
+func BenchmarkFaceService_Verify_Latency(b *testing.B) {
+	tenantID := uuid.New()
+	externalID := "user-verify"
+	storedEmbedding := generateBenchmarkEmbedding(512)
+
+	for _, parallel := range []bool{false, true} {
+		b.Run(fmt.Sprintf("parallel=%v", parallel), func(b *testing.B) {
+			faceRepo := &MockFaceRepository{}
+			verificationRepo := &MockVerificationRepository{}
+			faceProvider := &MockFaceProvider{}
+
+			faceRepo.On("GetByExternalID", mock.Anything, tenantID, externalID).
+				After(5*time.Millisecond).
+				Return(&domain.Face{ID: uuid.New(), TenantID: tenantID, Embedding: storedEmbedding}, nil)
+			faceProvider.On("DetectFaces", mock.Anything, mock.Anything).
+				After(15*time.Millisecond).
+				Return([]provider.DetectedFace{{Confidence: 0.99, QualityScore: 0.95}}, nil)
+			faceProvider.On("IndexFace", mock.Anything, mock.Anything).
+				After(15*time.Millisecond).
+				Return("face-id", storedEmbedding, nil)
+			faceProvider.On("CompareFaces", mock.Anything, storedEmbedding, storedEmbedding).
+				Return(0.95, nil)
+			verificationRepo.On("Create", mock.Anything, mock.Anything).
+				Return(nil)
+
+			svc := NewFaceService(faceRepo, verificationRepo, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{}).
+				WithParallelVerify(parallel)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := svc.Verify(context.Background(), tenantID, externalID, []byte("image"), domain.DefaultTenantSettings()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
 // BenchmarkFaceService_Register benchmarks face registration
 // Not as critical as search/verify, but still important
 // Target: < 20ms (excluding provider calls)
@@ -379,7 +421,7 @@ func BenchmarkFaceService_Register(b *testing.B) {
 
 	for i := 0; i < b.N; i++ {
 		externalID := fmt.Sprintf("user-bench-%d", i)
-		face, err := svc.Register(context.Background(), tenantID, externalID, []byte("image"), false, 0.9)
+		face, err := svc.Register(context.Background(), tenantID, externalID, []byte("image"), nil, domain.DefaultTenantSettings())
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+// CollectionCounter counts the faces indexed in a tenant's provider-side collection (e.g. Rekognition)
+type CollectionCounter interface {
+	GetCollectionFaceCount(ctx context.Context, tenantID string) (int64, error)
+}
+
+// faceCountCache remembers each tenant's non-zero face count for a short time so Search does
+// not count on every request. Zero is never cached: a face registered through another instance
+// would stay hidden from search until the entry expired. Each forget bumps the tenant's
+// generation, so a count read before the invalidation cannot be written back after it.
+type faceCountCache struct {
+	ttl         time.Duration
+	mu          sync.Mutex
+	entries     map[uuid.UUID]faceCountEntry
+	generations map[uuid.UUID]uint64
+}
+
+type faceCountEntry struct {
+	count     int
+	expiresAt time.Time
+}
+
+func (c *faceCountCache) get(tenantID uuid.UUID, now time.Time) (int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[tenantID]
+	if !ok || now.After(entry.expiresAt) {
+		return 0, false
+	}
+	return entry.count, true
+}
+
+// generation returns the tenant's invalidation counter, read before counting and passed to put
+func (c *faceCountCache) generation(tenantID uuid.UUID) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.generations[tenantID]
+}
+
+// put caches count unless it is zero or the tenant was invalidated since generation was read
+func (c *faceCountCache) put(tenantID uuid.UUID, count int, generation uint64, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generations[tenantID] != generation {
+		return
+	}
+	if count == 0 {
+		delete(c.entries, tenantID)
+		return
+	}
+	if c.entries == nil {
+		c.entries = make(map[uuid.UUID]faceCountEntry)
+	}
+	c.entries[tenantID] = faceCountEntry{count: count, expiresAt: now.Add(c.ttl)}
+}
+
+func (c *faceCountCache) forget(tenantID uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, tenantID)
+	if c.generations == nil {
+		c.generations = make(map[uuid.UUID]uint64)
+	}
+	c.generations[tenantID]++
+}
+
+// WithFaceCountCache lets Search return an empty result without calling the provider when the
+// tenant has no faces. Non-zero counts are cached for ttl and dropped whenever this instance
+// registers or deletes a face; an empty tenant is counted again on every search, so faces
+// added through other instances are never hidden. Zero disables the short-circuit.
+func (s *FaceService) WithFaceCountCache(ttl time.Duration) *FaceService {
+	if ttl > 0 {
+		s.faceCounts = &faceCountCache{ttl: ttl}
+	} else {
+		s.faceCounts = nil
+	}
+	return s
+}
+
+// collectionEmpty reports whether the tenant is known to have no faces.
+// Any doubt, including a failed count, lets the search run normally.
+func (s *FaceService) collectionEmpty(ctx context.Context, tenantID uuid.UUID) bool {
+	if s.faceCounts == nil {
+		return false
+	}
+
+	now := time.Now()
+	if count, ok := s.faceCounts.get(tenantID, now); ok {
+		return count == 0
+	}
+
+	generation := s.faceCounts.generation(tenantID)
+	count, err := s.faceRepo.CountByTenant(ctx, tenantID)
+	if err != nil {
+		slog.Warn("face count unavailable, searching anyway", "tenant_id", tenantID, "error", err)
+		return false
+	}
+	s.faceCounts.put(tenantID, count, generation, now)
+	return count == 0
+}
+
+// forgetFaceCount drops the cached count after the tenant's collection changed
+func (s *FaceService) forgetFaceCount(tenantID uuid.UUID) {
+	if s.faceCounts != nil {
+		s.faceCounts.forget(tenantID)
+	}
+}
+
+// WithCollectionCounter lets RecountFaces compare the database count with the provider collection
+func (s *FaceService) WithCollectionCounter(counter CollectionCounter) *FaceService {
+	s.collectionCounter = counter
+	return s
+}
+
+// RecountFaces counts the tenant's faces again and replaces the cached count, so a count that
+// drifted stops skewing the empty-collection check. For providers with their own collection the
+// result also reports the provider's count and how far it is from the database.
+func (s *FaceService) RecountFaces(ctx context.Context, tenantID uuid.UUID) (*domain.FaceRecount, error) {
+	var generation uint64
+	if s.faceCounts != nil {
+		generation = s.faceCounts.generation(tenantID)
+	}
+	count, err := s.faceRepo.CountByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: count faces: %w", tenantID, err)
+	}
+
+	now := time.Now()
+	recount := &domain.FaceRecount{DatabaseCount: count, RecountedAt: now}
+
+	if s.collectionCounter != nil {
+		providerCount, err := s.collectionCounter.GetCollectionFaceCount(ctx, tenantID.String())
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: count provider collection: %w", tenantID, err)
+		}
+		delta := providerCount - int64(count)
+		recount.ProviderCount = &providerCount
+		recount.Delta = &delta
+	}
+
+	if s.faceCounts != nil {
+		if previous, ok := s.faceCounts.get(tenantID, now); ok {
+			recount.PreviousCachedCount = &previous
+		}
+		s.faceCounts.put(tenantID, count, generation, now)
+		recount.CacheRefreshed = true
+	}
+
+	return recount, nil
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/webhook"
+)
+
+// defaultFaceExpiryBatchSize is how many expired faces one sweep query loads
+const defaultFaceExpiryBatchSize = 100
+
+// FaceExpiryRepositoryInterface lists and deletes faces past their expires_at
+type FaceExpiryRepositoryInterface interface {
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]*domain.Face, error)
+	DeleteExpired(ctx context.Context, id uuid.UUID, now time.Time) (bool, error)
+}
+
+// DeletedFaceForgetter drops what a face deleted outside FaceService leaves behind
+type DeletedFaceForgetter interface {
+	ForgetDeletedFace(ctx context.Context, face *domain.Face)
+}
+
+// EventDispatcher delivers events to the webhooks of a tenant
+type EventDispatcher interface {
+	Dispatch(ctx context.Context, tenantID uuid.UUID, eventType string, data interface{}) error
+}
+
+// FaceExpirer periodically deletes faces registered with a TTL once they expire and
+// announces each one with a face.expired webhook. Deleting the row erases the embedding; the
+// face the provider indexed (e.g. in a Rekognition collection) is removed through faces.
+type FaceExpirer struct {
+	repo      FaceExpiryRepositoryInterface
+	faces     DeletedFaceForgetter
+	webhooks  EventDispatcher // optional, nil sends no face.expired events
+	logger    *slog.Logger
+	interval  time.Duration
+	batchSize int
+	now       func() time.Time
+}
+
+func NewFaceExpirer(repo FaceExpiryRepositoryInterface, faces DeletedFaceForgetter, webhooks EventDispatcher, logger *slog.Logger, interval time.Duration) *FaceExpirer {
+	return &FaceExpirer{
+		repo:      repo,
+		faces:     faces,
+		webhooks:  webhooks,
+		logger:    logger,
+		interval:  interval,
+		batchSize: defaultFaceExpiryBatchSize,
+		now:       time.Now,
+	}
+}
+
+// Run sweeps expired faces every interval until ctx is cancelled
+func (e *FaceExpirer) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	e.logger.Info("face expiry worker started", "interval", e.interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			e.logger.Info("face expiry worker stopped")
+			return
+		case <-ticker.C:
+			if _, err := e.Sweep(ctx); err != nil {
+				e.logger.Error("face expiry sweep failed", "error", err)
+			}
+		}
+	}
+}
+
+// Sweep deletes every face whose expires_at has passed and returns how many were deleted.
+// Faces are re-checked on delete, so one re-registered with a new TTL meanwhile is kept.
+// A failed delete is logged and retried on the next sweep.
+func (e *FaceExpirer) Sweep(ctx context.Context) (int, error) {
+	now := e.now()
+	deleted := 0
+
+	for {
+		faces, err := e.repo.ListExpired(ctx, now, e.batchSize)
+		if err != nil {
+			return deleted, err
+		}
+
+		progressed := false
+		for _, face := range faces {
+			ok, err := e.repo.DeleteExpired(ctx, face.ID, now)
+			if err != nil {
+				e.logger.Warn("failed to delete expired face",
+					"error", err,
+					"tenant_id", face.TenantID,
+					"face_id", face.ID,
+				)
+				continue
+			}
+			progressed = true
+			if !ok {
+				continue
+			}
+			deleted++
+			e.faces.ForgetDeletedFace(ctx, face)
+			e.dispatchExpired(ctx, face)
+		}
+
+		// A short batch was the last one; a batch where every delete failed would be listed again
+		if len(faces) < e.batchSize || !progressed {
+			break
+		}
+	}
+
+	if deleted > 0 {
+		e.logger.Info("expired faces deleted", "count", deleted)
+	}
+	return deleted, nil
+}
+
+func (e *FaceExpirer) dispatchExpired(ctx context.Context, face *domain.Face) {
+	if e.webhooks == nil {
+		return
+	}
+
+	data := webhook.FaceExpiredData{
+		FaceID:     face.ID.String(),
+		ExternalID: face.ExternalID,
+	}
+	if face.ExpiresAt != nil {
+		data.ExpiresAt = face.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+
+	dispatchCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := e.webhooks.Dispatch(dispatchCtx, face.TenantID, webhook.EventFaceExpired, data); err != nil {
+		e.logger.Warn("failed to dispatch webhook event",
+			"error", err,
+			"tenant_id", face.TenantID,
+			"event_type", webhook.EventFaceExpired,
+		)
+	}
+}
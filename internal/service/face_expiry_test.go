@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/webhook"
+)
+
+// fakeFaceExpiryRepository keeps faces in memory and applies the expiry conditions of the SQL queries
+type fakeFaceExpiryRepository struct {
+	faces     map[uuid.UUID]*domain.Face
+	deleteErr map[uuid.UUID]error
+	// extendOnList moves a face's expiry after it was listed, like a concurrent re-registration
+	extendOnList map[uuid.UUID]time.Time
+}
+
+func newFakeFaceExpiryRepository(faces ...*domain.Face) *fakeFaceExpiryRepository {
+	repo := &fakeFaceExpiryRepository{
+		faces:        make(map[uuid.UUID]*domain.Face),
+		deleteErr:    make(map[uuid.UUID]error),
+		extendOnList: make(map[uuid.UUID]time.Time),
+	}
+	for _, face := range faces {
+		repo.faces[face.ID] = face
+	}
+	return repo
+}
+
+func (f *fakeFaceExpiryRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]*domain.Face, error) {
+	var expired []*domain.Face
+	for _, face := range f.faces {
+		if face.ExpiresAt != nil && !face.ExpiresAt.After(now) {
+			listed := *face
+			expired = append(expired, &listed)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool { return expired[i].ExpiresAt.Before(*expired[j].ExpiresAt) })
+	if len(expired) > limit {
+		expired = expired[:limit]
+	}
+
+	for _, face := range expired {
+		if extended, ok := f.extendOnList[face.ID]; ok {
+			f.faces[face.ID].ExpiresAt = &extended
+		}
+	}
+	return expired, nil
+}
+
+func (f *fakeFaceExpiryRepository) DeleteExpired(ctx context.Context, id uuid.UUID, now time.Time) (bool, error) {
+	if err := f.deleteErr[id]; err != nil {
+		return false, err
+	}
+	face, ok := f.faces[id]
+	if !ok || face.ExpiresAt == nil || face.ExpiresAt.After(now) {
+		return false, nil
+	}
+	delete(f.faces, id)
+	return true, nil
+}
+
+// fakeFaceForgetter records the faces the expirer asked to forget
+type fakeFaceForgetter struct {
+	forgotten []*domain.Face
+}
+
+func (f *fakeFaceForgetter) ForgetDeletedFace(ctx context.Context, face *domain.Face) {
+	f.forgotten = append(f.forgotten, face)
+}
+
+type dispatchedEvent struct {
+	tenantID  uuid.UUID
+	eventType string
+	data      interface{}
+}
+
+type fakeEventDispatcher struct {
+	events []dispatchedEvent
+}
+
+func (f *fakeEventDispatcher) Dispatch(ctx context.Context, tenantID uuid.UUID, eventType string, data interface{}) error {
+	f.events = append(f.events, dispatchedEvent{tenantID: tenantID, eventType: eventType, data: data})
+	return nil
+}
+
+func expiringFace(tenantID uuid.UUID, externalID string, expiresAt *time.Time) *domain.Face {
+	return &domain.Face{ID: uuid.New(), TenantID: tenantID, ExternalID: externalID, ExpiresAt: expiresAt}
+}
+
+func TestFaceExpirer_Sweep(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	now := time.Date(2026, 3, 8, 18, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		ts := now.Add(d)
+		return &ts
+	}
+	tenantID := uuid.New()
+
+	t.Run("deletes only faces past their own expiry", func(t *testing.T) {
+		expired := expiringFace(tenantID, "guest-expired", at(-time.Hour))
+		expiresNow := expiringFace(tenantID, "guest-now", at(0))
+		stillValid := expiringFace(tenantID, "guest-tomorrow", at(24*time.Hour))
+		permanent := expiringFace(tenantID, "employee", nil)
+		expired.ProviderFaceID = "rk-face-1"
+		repo := newFakeFaceExpiryRepository(expired, expiresNow, stillValid, permanent)
+		webhooks := &fakeEventDispatcher{}
+		forgetter := &fakeFaceForgetter{}
+
+		expirer := NewFaceExpirer(repo, forgetter, webhooks, logger, time.Minute)
+		expirer.now = func() time.Time { return now }
+		deleted, err := expirer.Sweep(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, deleted)
+		assert.NotContains(t, repo.faces, expired.ID)
+		assert.NotContains(t, repo.faces, expiresNow.ID)
+		assert.Contains(t, repo.faces, stillValid.ID)
+		assert.Contains(t, repo.faces, permanent.ID)
+
+		// Provider faces and cached counts are dropped for the deleted faces only
+		require.Len(t, forgetter.forgotten, 2)
+		assert.Equal(t, expired.ID, forgetter.forgotten[0].ID)
+		assert.Equal(t, "rk-face-1", forgetter.forgotten[0].ProviderFaceID)
+		assert.Equal(t, expiresNow.ID, forgetter.forgotten[1].ID)
+
+		require.Len(t, webhooks.events, 2)
+		assert.Equal(t, tenantID, webhooks.events[0].tenantID)
+		assert.Equal(t, webhook.EventFaceExpired, webhooks.events[0].eventType)
+		assert.Equal(t, webhook.FaceExpiredData{
+			FaceID:     expired.ID.String(),
+			ExternalID: "guest-expired",
+			ExpiresAt:  "2026-03-08T17:00:00Z",
+		}, webhooks.events[0].data)
+	})
+
+	t.Run("drains more expired faces than one batch", func(t *testing.T) {
+		repo := newFakeFaceExpiryRepository()
+		for i := 0; i < 5; i++ {
+			face := expiringFace(tenantID, "guest", at(-time.Duration(i+1)*time.Minute))
+			repo.faces[face.ID] = face
+		}
+		webhooks := &fakeEventDispatcher{}
+
+		expirer := NewFaceExpirer(repo, &fakeFaceForgetter{}, webhooks, logger, time.Minute)
+		expirer.now = func() time.Time { return now }
+		expirer.batchSize = 2
+		deleted, err := expirer.Sweep(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, 5, deleted)
+		assert.Empty(t, repo.faces)
+		assert.Len(t, webhooks.events, 5)
+	})
+
+	t.Run("keeps a face re-registered with a new ttl after it was listed", func(t *testing.T) {
+		face := expiringFace(tenantID, "guest-renewed", at(-time.Hour))
+		repo := newFakeFaceExpiryRepository(face)
+		repo.extendOnList[face.ID] = now.Add(7 * 24 * time.Hour)
+		webhooks := &fakeEventDispatcher{}
+		forgetter := &fakeFaceForgetter{}
+
+		expirer := NewFaceExpirer(repo, forgetter, webhooks, logger, time.Minute)
+		expirer.now = func() time.Time { return now }
+		deleted, err := expirer.Sweep(context.Background())
+
+		require.NoError(t, err)
+		assert.Zero(t, deleted)
+		assert.Contains(t, repo.faces, face.ID)
+		assert.Empty(t, forgetter.forgotten)
+		assert.Empty(t, webhooks.events)
+	})
+
+	t.Run("failed deletes are left for the next sweep", func(t *testing.T) {
+		failing := expiringFace(tenantID, "guest-failing", at(-2*time.Hour))
+		other := expiringFace(tenantID, "guest-other", at(-time.Hour))
+		repo := newFakeFaceExpiryRepository(failing, other)
+		repo.deleteErr[failing.ID] = errors.New("connection reset")
+		webhooks := &fakeEventDispatcher{}
+
+		expirer := NewFaceExpirer(repo, &fakeFaceForgetter{}, webhooks, logger, time.Minute)
+		expirer.now = func() time.Time { return now }
+		expirer.batchSize = 1
+		deleted, err := expirer.Sweep(context.Background())
+
+		require.NoError(t, err)
+		assert.Zero(t, deleted)
+		assert.Contains(t, repo.faces, failing.ID)
+		assert.Empty(t, webhooks.events)
+
+		delete(repo.deleteErr, failing.ID)
+		deleted, err = expirer.Sweep(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, deleted)
+		assert.Empty(t, repo.faces)
+	})
+}
+
+func TestFaceService_ForgetDeletedFace(t *testing.T) {
+	tenantID := uuid.New()
+
+	t.Run("removes a live face from the live provider", func(t *testing.T) {
+		liveProvider := &MockFaceProvider{}
+		testProvider := &MockFaceProvider{}
+		liveProvider.On("DeleteFace", mock.Anything, "rk-face-1").Return(nil)
+
+		svc := NewFaceService(&MockFaceRepository{}, &MockVerificationRepository{}, nil, liveProvider, nil).
+			WithTestProvider(testProvider).
+			WithFaceCountCache(time.Minute)
+		svc.faceCounts.put(tenantID, 3, 0, time.Now())
+
+		svc.ForgetDeletedFace(context.Background(), &domain.Face{TenantID: tenantID, ProviderFaceID: "rk-face-1"})
+
+		liveProvider.AssertExpectations(t)
+		testProvider.AssertNotCalled(t, "DeleteFace", mock.Anything, mock.Anything)
+		_, cached := svc.faceCounts.get(tenantID, time.Now())
+		assert.False(t, cached, "the cached face count must be dropped")
+	})
+
+	t.Run("removes a test face from the test provider", func(t *testing.T) {
+		liveProvider := &MockFaceProvider{}
+		testProvider := &MockFaceProvider{}
+		testProvider.On("DeleteFace", mock.Anything, "mock-face-1").Return(nil)
+
+		svc := NewFaceService(&MockFaceRepository{}, &MockVerificationRepository{}, nil, liveProvider, nil).
+			WithTestProvider(testProvider)
+
+		svc.ForgetDeletedFace(context.Background(), &domain.Face{TenantID: tenantID, ProviderFaceID: "mock-face-1", IsTest: true})
+
+		testProvider.AssertExpectations(t)
+		liveProvider.AssertNotCalled(t, "DeleteFace", mock.Anything, mock.Anything)
+	})
+}
@@ -347,7 +347,7 @@ func BenchmarkSearchByEmbedding_Integration(b *testing.B) {
 			b.ReportAllocs()
 
 			for i := 0; i < b.N; i++ {
-				matches, err := repo.SearchByEmbedding(ctx, tenantID, queryEmbedding, 0.7, 10)
+				matches, err := repo.SearchByEmbedding(ctx, tenantID, queryEmbedding, domain.EmbeddingFingerprint{}, 0.7, 10)
 				if err != nil {
 					b.Fatalf("search failed: %v", err)
 				}
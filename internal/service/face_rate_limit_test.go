@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
 	"github.com/saturnino-fabrica-de-software/rekko/internal/provider"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/ratelimit"
 )
 
 func TestFaceService_Search_RateLimiting(t *testing.T) {
@@ -104,7 +106,7 @@ func TestFaceService_Search_RateLimiting(t *testing.T) {
 					LivenessScore: 0.90,
 					FaceCount:     1,
 				}, nil)
-				faceRepo.On("SearchByEmbedding", mock.Anything, tt.tenant.ID, mock.Anything, mock.Anything, mock.Anything).Return([]domain.SearchMatch{}, nil)
+				faceRepo.On("SearchByEmbedding", mock.Anything, tt.tenant.ID, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.SearchMatch{}, nil)
 				searchAuditRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
 			}
 
@@ -114,7 +116,6 @@ func TestFaceService_Search_RateLimiting(t *testing.T) {
 				searchAuditRepo:  searchAuditRepo,
 				provider:         faceProvider,
 				rateLimiter:      rateLimiter,
-				threshold:        0.8,
 			}
 
 			result, err := svc.Search(context.Background(), tt.tenant, []byte("image"), 0.85, 10, "127.0.0.1")
@@ -168,7 +169,6 @@ func TestFaceService_Search_RateLimitCheckedBeforeProviderCall(t *testing.T) {
 		searchAuditRepo:  searchAuditRepo,
 		provider:         faceProvider,
 		rateLimiter:      rateLimiter,
-		threshold:        0.8,
 	}
 
 	result, err := svc.Search(context.Background(), tenant, []byte("image"), 0.85, 10, "127.0.0.1")
@@ -183,3 +183,36 @@ func TestFaceService_Search_RateLimitCheckedBeforeProviderCall(t *testing.T) {
 	faceProvider.AssertNotCalled(t, "AnalyzeFace")
 	faceRepo.AssertNotCalled(t, "SearchByEmbedding")
 }
+
+func TestFaceService_Search_RateLimitUsage(t *testing.T) {
+	tenantID := uuid.New()
+	tenant := &domain.Tenant{
+		ID: tenantID,
+		Settings: map[string]interface{}{
+			"search_enabled":    true,
+			"search_rate_limit": float64(30),
+		},
+	}
+	resetAt := time.Now().Add(time.Minute)
+
+	rateLimiter := &MockRateLimiter{}
+	rateLimiter.On("CheckSearchLimit", mock.Anything, tenantID, 30).
+		Return(&ratelimit.LimitError{Limit: 30, Count: 31, ResetAt: resetAt})
+
+	svc := &FaceService{
+		provider:    &MockFaceProvider{},
+		rateLimiter: rateLimiter,
+	}
+
+	result, err := svc.Search(context.Background(), tenant, []byte("image"), 0.85, 10, "127.0.0.1")
+	assert.Nil(t, result)
+
+	var appErr *domain.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, domain.ErrSearchRateLimitExceeded.Code, appErr.Code)
+	assert.Equal(t, 429, appErr.StatusCode)
+	require.NotNil(t, appErr.RateLimit)
+	assert.Equal(t, 30, appErr.RateLimit.Limit)
+	assert.Equal(t, 31, appErr.RateLimit.Current)
+	assert.Equal(t, resetAt, appErr.RateLimit.ResetAt)
+}
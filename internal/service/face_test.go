@@ -1,9 +1,18 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"math"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -11,6 +20,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/imaging"
 	"github.com/saturnino-fabrica-de-software/rekko/internal/provider"
 )
 
@@ -41,8 +51,16 @@ func (m *MockFaceRepository) Delete(ctx context.Context, tenantID uuid.UUID, ext
 	return args.Error(0)
 }
 
-func (m *MockFaceRepository) SearchByEmbedding(ctx context.Context, tenantID uuid.UUID, embedding []float64, threshold float64, limit int) ([]domain.SearchMatch, error) {
-	args := m.Called(ctx, tenantID, embedding, threshold, limit)
+func (m *MockFaceRepository) SearchByEmbedding(ctx context.Context, tenantID uuid.UUID, embedding []float64, fingerprint domain.EmbeddingFingerprint, threshold float64, limit int) ([]domain.SearchMatch, error) {
+	args := m.Called(ctx, tenantID, embedding, fingerprint, threshold, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.SearchMatch), args.Error(1)
+}
+
+func (m *MockFaceRepository) SearchByEmbeddingSegmented(ctx context.Context, tenantID uuid.UUID, embedding []float64, fingerprint domain.EmbeddingFingerprint, threshold float64, segments domain.SegmentThresholdSettings, limit int) ([]domain.SearchMatch, error) {
+	args := m.Called(ctx, tenantID, embedding, fingerprint, threshold, segments, limit)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -54,6 +72,30 @@ func (m *MockFaceRepository) CountByTenant(ctx context.Context, tenantID uuid.UU
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockFaceRepository) ExistsBatch(ctx context.Context, tenantID uuid.UUID, externalIDs []string) (map[string]domain.RegistrationCheck, error) {
+	args := m.Called(ctx, tenantID, externalIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]domain.RegistrationCheck), args.Error(1)
+}
+
+func (m *MockFaceRepository) BulkUpdateMetadata(ctx context.Context, tenantID uuid.UUID, externalIDs []string, patch map[string]interface{}, maxBytes int) ([]string, error) {
+	args := m.Called(ctx, tenantID, externalIDs, patch, maxBytes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockFaceRepository) List(ctx context.Context, tenantID uuid.UUID, filter domain.FaceListFilter) ([]*domain.Face, int, error) {
+	args := m.Called(ctx, tenantID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*domain.Face), args.Int(1), args.Error(2)
+}
+
 type MockVerificationRepository struct {
 	mock.Mock
 }
@@ -81,6 +123,30 @@ func (m *MockVerificationRepository) Create(ctx context.Context, v *domain.Verif
 	return args.Error(0)
 }
 
+func (m *MockVerificationRepository) RecentConfidences(ctx context.Context, tenantID uuid.UUID, externalID string, since time.Time, limit int) ([]float64, error) {
+	args := m.Called(ctx, tenantID, externalID, since, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]float64), args.Error(1)
+}
+
+func (m *MockVerificationRepository) LastVerifiedAt(ctx context.Context, tenantID uuid.UUID, externalID string, requireLiveness bool) (*time.Time, error) {
+	args := m.Called(ctx, tenantID, externalID, requireLiveness)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockVerificationRepository) LastVerifiedGate(ctx context.Context, tenantID uuid.UUID, externalID string, isTest, fullPassOnly bool) (*domain.GateVisit, error) {
+	args := m.Called(ctx, tenantID, externalID, isTest, fullPassOnly)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.GateVisit), args.Error(1)
+}
+
 type MockFaceProvider struct {
 	mock.Mock
 }
@@ -124,6 +190,28 @@ func (m *MockFaceProvider) AnalyzeFace(ctx context.Context, image []byte) (*prov
 	return args.Get(0).(*provider.FaceAnalysis), args.Error(1)
 }
 
+// unitEmbedding returns a valid embedding of length 1
+func unitEmbedding() []float64 {
+	embedding := make([]float64, domain.EmbeddingDimension)
+	embedding[0] = 1
+	return embedding
+}
+
+// assertAppError checks err is want or, for an AppError, a copy of it carrying request details
+// such as WithFaceCount
+func assertAppError(t *testing.T, err, want error) {
+	t.Helper()
+	var wantAppErr *domain.AppError
+	if !errors.As(want, &wantAppErr) {
+		assert.ErrorIs(t, err, want)
+		return
+	}
+	var appErr *domain.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, wantAppErr.Code, appErr.Code)
+	assert.Equal(t, wantAppErr.StatusCode, appErr.StatusCode)
+}
+
 func TestFaceService_Register(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -140,7 +228,7 @@ func TestFaceService_Register(t *testing.T) {
 			imageBytes: make([]byte, 5000),
 			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider) {
 				fp.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
-					Embedding:     make([]float64, 512),
+					Embedding:     unitEmbedding(),
 					Confidence:    0.99,
 					QualityScore:  0.95,
 					LivenessScore: 0.90,
@@ -186,7 +274,7 @@ func TestFaceService_Register(t *testing.T) {
 				existingFaceID := uuid.New()
 				tenantID := uuid.MustParse("a6646bc1-769f-4bdc-8496-f2e0890abbd0")
 				fp.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
-					Embedding:     make([]float64, 512),
+					Embedding:     unitEmbedding(),
 					Confidence:    0.99,
 					QualityScore:  0.95,
 					LivenessScore: 0.90,
@@ -218,13 +306,12 @@ func TestFaceService_Register(t *testing.T) {
 				verificationRepo: verificationRepo,
 				searchAuditRepo:  searchAuditRepo,
 				provider:         faceProvider,
-				threshold:        0.8,
 			}
 
-			face, err := svc.Register(context.Background(), tt.tenantID, tt.externalID, tt.imageBytes, false, 0.90)
+			face, err := svc.Register(context.Background(), tt.tenantID, tt.externalID, tt.imageBytes, nil, domain.DefaultTenantSettings())
 
 			if tt.wantErr != nil {
-				assert.ErrorIs(t, err, tt.wantErr)
+				assertAppError(t, err, tt.wantErr)
 				assert.Nil(t, face)
 			} else {
 				require.NoError(t, err)
@@ -259,7 +346,7 @@ func TestFaceService_Register_WithLiveness(t *testing.T) {
 			livenessThreshold: 0.90,
 			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider) {
 				fp.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
-					Embedding:     make([]float64, 512),
+					Embedding:     unitEmbedding(),
 					Confidence:    0.99,
 					QualityScore:  0.95,
 					LivenessScore: 0.95,
@@ -280,7 +367,7 @@ func TestFaceService_Register_WithLiveness(t *testing.T) {
 			livenessThreshold: 0.90,
 			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider) {
 				fp.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
-					Embedding:     make([]float64, 512),
+					Embedding:     unitEmbedding(),
 					Confidence:    0.99,
 					QualityScore:  0.95,
 					LivenessScore: 0.70,
@@ -298,7 +385,7 @@ func TestFaceService_Register_WithLiveness(t *testing.T) {
 			livenessThreshold: 0.90,
 			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider) {
 				fp.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
-					Embedding:     make([]float64, 512),
+					Embedding:     unitEmbedding(),
 					Confidence:    0.99,
 					QualityScore:  0.95,
 					LivenessScore: 0.50,
@@ -326,10 +413,13 @@ func TestFaceService_Register_WithLiveness(t *testing.T) {
 				verificationRepo: verificationRepo,
 				searchAuditRepo:  searchAuditRepo,
 				provider:         faceProvider,
-				threshold:        0.8,
 			}
 
-			face, err := svc.Register(context.Background(), tt.tenantID, tt.externalID, tt.imageBytes, tt.requireLiveness, tt.livenessThreshold)
+			settings := domain.DefaultTenantSettings()
+			settings.RequireLiveness = tt.requireLiveness
+			settings.LivenessThreshold = tt.livenessThreshold
+
+			face, err := svc.Register(context.Background(), tt.tenantID, tt.externalID, tt.imageBytes, nil, settings)
 
 			if tt.wantErr != nil {
 				assert.ErrorIs(t, err, tt.wantErr)
@@ -426,10 +516,9 @@ func TestFaceService_Verify(t *testing.T) {
 				verificationRepo: verificationRepo,
 				searchAuditRepo:  searchAuditRepo,
 				provider:         faceProvider,
-				threshold:        0.8,
 			}
 
-			verification, err := svc.Verify(context.Background(), tt.tenantID, tt.externalID, tt.imageBytes)
+			verification, err := svc.Verify(context.Background(), tt.tenantID, tt.externalID, tt.imageBytes, domain.DefaultTenantSettings())
 
 			if tt.wantErr != nil {
 				assert.ErrorIs(t, err, tt.wantErr)
@@ -437,6 +526,8 @@ func TestFaceService_Verify(t *testing.T) {
 				require.NoError(t, err)
 				assert.NotNil(t, verification)
 				assert.Equal(t, tt.wantMatch, verification.Verified)
+				assert.Equal(t, 0.8, verification.ThresholdApplied)
+				assert.Equal(t, domain.ThresholdSourceDefault, verification.ThresholdSource)
 			}
 
 			faceRepo.AssertExpectations(t)
@@ -446,396 +537,3310 @@ func TestFaceService_Verify(t *testing.T) {
 	}
 }
 
-func TestFaceService_Delete(t *testing.T) {
+func TestFaceService_Verify_Policy(t *testing.T) {
+	tenantID := uuid.New()
+	embedding := make([]float64, 512)
+	live, spoofed := true, false
+
 	tests := []struct {
-		name       string
-		tenantID   uuid.UUID
-		externalID string
-		setupMocks func(*MockFaceRepository, *MockVerificationRepository, *MockFaceProvider)
-		wantErr    error
+		name         string
+		policy       domain.VerifyPolicy
+		similarity   float64
+		isLive       bool
+		wantLiveness *bool
+		wantVerified bool
+		wantReason   string
 	}{
-		{
-			name:       "successful deletion",
-			tenantID:   uuid.New(),
-			externalID: "user_001",
-			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider) {
-				fr.On("GetByExternalID", mock.Anything, mock.Anything, "user_001").Return(&domain.Face{
-					ID: uuid.New(),
-				}, nil)
-				fr.On("Delete", mock.Anything, mock.Anything, "user_001").Return(nil)
-			},
-			wantErr: nil,
-		},
-		{
-			name:       "face not found",
-			tenantID:   uuid.New(),
-			externalID: "user_999",
-			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider) {
-				fr.On("GetByExternalID", mock.Anything, mock.Anything, "user_999").Return(nil, domain.ErrFaceNotFound)
-			},
-			wantErr: domain.ErrFaceNotFound,
-		},
+		{name: "match_only ignores liveness", policy: domain.VerifyPolicyMatchOnly, similarity: 0.92, wantVerified: true},
+		{name: "match_only without match", policy: domain.VerifyPolicyMatchOnly, similarity: 0.45, wantVerified: false, wantReason: domain.FailReasonMatchBelowThreshold},
+		{name: "match_and_liveness both pass", policy: domain.VerifyPolicyMatchAndLiveness, similarity: 0.92, isLive: true, wantLiveness: &live, wantVerified: true},
+		{name: "match_and_liveness spoofed match", policy: domain.VerifyPolicyMatchAndLiveness, similarity: 0.92, isLive: false, wantLiveness: &spoofed, wantVerified: false, wantReason: domain.FailReasonLivenessFailed},
+		{name: "match_and_liveness live without match", policy: domain.VerifyPolicyMatchAndLiveness, similarity: 0.45, isLive: true, wantLiveness: &live, wantVerified: false, wantReason: domain.FailReasonMatchBelowThreshold},
+		{name: "match_or_liveness match only", policy: domain.VerifyPolicyMatchOrLiveness, similarity: 0.92, isLive: false, wantLiveness: &spoofed, wantVerified: true},
+		{name: "match_or_liveness liveness only", policy: domain.VerifyPolicyMatchOrLiveness, similarity: 0.45, isLive: true, wantLiveness: &live, wantVerified: true},
+		{name: "match_or_liveness neither", policy: domain.VerifyPolicyMatchOrLiveness, similarity: 0.45, isLive: false, wantLiveness: &spoofed, wantVerified: false, wantReason: domain.FailReasonMatchBelowThreshold},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			faceRepo := &MockFaceRepository{}
 			verificationRepo := &MockVerificationRepository{}
-			searchAuditRepo := &MockSearchAuditRepository{}
 			faceProvider := &MockFaceProvider{}
 
-			tt.setupMocks(faceRepo, verificationRepo, faceProvider)
-
-			svc := &FaceService{
-				faceRepo:         faceRepo,
-				verificationRepo: verificationRepo,
-				searchAuditRepo:  searchAuditRepo,
-				provider:         faceProvider,
-				threshold:        0.8,
+			faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(&domain.Face{
+				ID:        uuid.New(),
+				Embedding: embedding,
+			}, nil)
+			faceProvider.On("DetectFaces", mock.Anything, mock.Anything).Return([]provider.DetectedFace{{Confidence: 0.99}}, nil)
+			faceProvider.On("IndexFace", mock.Anything, mock.Anything).Return("face-id", embedding, nil)
+			faceProvider.On("CompareFaces", mock.Anything, mock.Anything, mock.Anything).Return(tt.similarity, nil)
+			if tt.policy.RequiresLiveness() {
+				faceProvider.On("CheckLiveness", mock.Anything, mock.Anything, 0.90).Return(&provider.LivenessResult{IsLive: tt.isLive, Confidence: 0.87}, nil)
 			}
+			verificationRepo.On("Create", mock.Anything, mock.MatchedBy(func(v *domain.Verification) bool {
+				return v.Verified == tt.wantVerified && v.FailReason == tt.wantReason
+			})).Return(nil)
 
-			err := svc.Delete(context.Background(), tt.tenantID, tt.externalID)
+			svc := NewFaceService(faceRepo, verificationRepo, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
 
-			if tt.wantErr != nil {
-				assert.ErrorIs(t, err, tt.wantErr)
+			settings := domain.DefaultTenantSettings()
+			settings.VerifyPolicy = tt.policy
+
+			verification, err := svc.Verify(context.Background(), tenantID, "user_001", make([]byte, 5000), settings)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.similarity >= settings.VerificationThreshold, verification.MatchPassed)
+			assert.Equal(t, tt.wantLiveness, verification.LivenessPassed)
+			if tt.policy.RequiresLiveness() {
+				require.NotNil(t, verification.LivenessScore)
+				assert.Equal(t, 0.87, *verification.LivenessScore)
 			} else {
-				assert.NoError(t, err)
+				assert.Nil(t, verification.LivenessScore)
 			}
+			assert.Equal(t, tt.wantVerified, verification.Verified)
+			assert.Equal(t, tt.wantReason, verification.FailReason)
 
 			faceRepo.AssertExpectations(t)
+			verificationRepo.AssertExpectations(t)
+			faceProvider.AssertExpectations(t)
 		})
 	}
+
+	t.Run("liveness provider failure fails verification", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(&domain.Face{ID: uuid.New(), Embedding: embedding}, nil)
+		faceProvider.On("DetectFaces", mock.Anything, mock.Anything).Return([]provider.DetectedFace{{Confidence: 0.99}}, nil)
+		faceProvider.On("IndexFace", mock.Anything, mock.Anything).Return("face-id", embedding, nil)
+		faceProvider.On("CompareFaces", mock.Anything, mock.Anything, mock.Anything).Return(0.92, nil)
+		faceProvider.On("CheckLiveness", mock.Anything, mock.Anything, 0.90).Return(nil, errors.New("liveness service unavailable"))
+
+		verificationRepo := &MockVerificationRepository{}
+		svc := NewFaceService(faceRepo, verificationRepo, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+
+		settings := domain.DefaultTenantSettings()
+		settings.VerifyPolicy = domain.VerifyPolicyMatchAndLiveness
+
+		_, err := svc.Verify(context.Background(), tenantID, "user_001", make([]byte, 5000), settings)
+
+		require.Error(t, err)
+		verificationRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
 }
 
-func TestFaceService_Search(t *testing.T) {
+func TestFaceService_Verify_RejectedReasons(t *testing.T) {
+	tenantID := uuid.New()
+	faceID := uuid.New()
+	noFaceErr := &provider.NoFaceError{Err: errors.New("no face in image")}
+
 	tests := []struct {
-		name           string
-		tenant         *domain.Tenant
-		imageBytes     []byte
-		threshold      float64
-		maxResults     int
-		clientIP       string
-		setupMocks     func(*MockFaceRepository, *MockVerificationRepository, *MockFaceProvider, *MockSearchAuditRepository)
-		expectedError  error
-		expectedCount  int
-		validateResult func(*testing.T, *domain.SearchResult)
+		name       string
+		detected   []provider.DetectedFace
+		detectErr  error
+		wantErr    error
+		wantReason string
 	}{
-		{
-			name: "successful search with matches",
-			tenant: &domain.Tenant{
-				ID:   uuid.New(),
-				Name: "Test Tenant",
-				Settings: map[string]interface{}{
-					"search_enabled": true,
-				},
-			},
-			imageBytes: []byte("fake-image-data"),
-			threshold:  0.85,
-			maxResults: 10,
-			clientIP:   "192.168.1.1",
-			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider, ar *MockSearchAuditRepository) {
-				fp.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
-					Embedding:     []float64{0.1, 0.2, 0.3},
-					Confidence:    0.99,
-					QualityScore:  0.95,
-					LivenessScore: 0.90,
-					FaceCount:     1,
-				}, nil)
+		{name: "no face detected", detected: []provider.DetectedFace{}, wantErr: domain.ErrNoFaceDetected, wantReason: domain.FailReasonNoFace},
+		{name: "provider reports no face", detectErr: noFaceErr, wantReason: domain.FailReasonNoFace},
+		{name: "multiple faces", detected: []provider.DetectedFace{{Confidence: 0.99}, {Confidence: 0.97}}, wantErr: domain.ErrMultipleFaces, wantReason: domain.FailReasonMultipleFaces},
+	}
 
-				faceID1 := uuid.New()
-				faceID2 := uuid.New()
-				fr.On("SearchByEmbedding", mock.Anything, mock.Anything, mock.Anything, 0.85, 10).Return([]domain.SearchMatch{
-					{FaceID: faceID1, ExternalID: "user1", Similarity: 0.95, Metadata: map[string]interface{}{"name": "User One"}},
-					{FaceID: faceID2, ExternalID: "user2", Similarity: 0.88, Metadata: map[string]interface{}{"name": "User Two"}},
-				}, nil)
+	for _, tt := range tests {
+		for _, record := range []bool{true, false} {
+			t.Run(fmt.Sprintf("%s record=%v", tt.name, record), func(t *testing.T) {
+				faceRepo := &MockFaceRepository{}
+				verificationRepo := &MockVerificationRepository{}
+				faceProvider := &MockFaceProvider{}
 
-				ar.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
-			},
-			expectedCount: 2,
-			validateResult: func(t *testing.T, result *domain.SearchResult) {
-				assert.Equal(t, 2, len(result.Matches))
-				assert.Equal(t, 0, result.TotalFaces) // TotalFaces removed from hot path
-				assert.GreaterOrEqual(t, result.LatencyMs, int64(0))
-				assert.NotEqual(t, uuid.Nil, result.SearchID)
-				assert.Equal(t, "user1", result.Matches[0].ExternalID)
-				assert.Equal(t, 0.95, result.Matches[0].Similarity)
-			},
-		},
-		{
-			name: "successful search with no matches",
-			tenant: &domain.Tenant{
-				ID: uuid.New(),
-				Settings: map[string]interface{}{
-					"search_enabled": true,
-				},
-			},
-			imageBytes: []byte("fake-image-data"),
-			threshold:  0.95,
-			maxResults: 10,
-			clientIP:   "192.168.1.2",
-			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider, ar *MockSearchAuditRepository) {
-				fp.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
-					Embedding:     []float64{0.5, 0.6, 0.7},
-					Confidence:    0.99,
-					QualityScore:  0.95,
-					LivenessScore: 0.90,
-					FaceCount:     1,
+				faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(&domain.Face{
+					ID:         faceID,
+					TenantID:   tenantID,
+					ExternalID: "user_001",
+					Embedding:  unitEmbedding(),
 				}, nil)
-				fr.On("SearchByEmbedding", mock.Anything, mock.Anything, mock.Anything, 0.95, 10).Return([]domain.SearchMatch{}, nil)
-				ar.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
-			},
-			expectedCount: 0,
-			validateResult: func(t *testing.T, result *domain.SearchResult) {
-				assert.Equal(t, 0, len(result.Matches))
-				assert.Equal(t, 0, result.TotalFaces) // Removed from hot path
-			},
-		},
-		{
-			name: "search not enabled",
-			tenant: &domain.Tenant{
-				ID: uuid.New(),
-				Settings: map[string]interface{}{
-					"search_enabled": false,
-				},
-			},
-			imageBytes: []byte("fake-image-data"),
-			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider, ar *MockSearchAuditRepository) {
-			},
-			expectedError: domain.ErrSearchNotEnabled,
-		},
-		{
-			name: "invalid threshold - too high",
-			tenant: &domain.Tenant{
-				ID: uuid.New(),
-				Settings: map[string]interface{}{
-					"search_enabled": true,
-				},
-			},
-			imageBytes: []byte("fake-image-data"),
-			threshold:  1.5,
-			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider, ar *MockSearchAuditRepository) {
-			},
-			expectedError: domain.ErrInvalidThreshold,
-		},
-		{
-			name: "invalid threshold - negative default from tenant",
-			tenant: &domain.Tenant{
-				ID: uuid.New(),
-				Settings: map[string]interface{}{
-					"search_enabled":   true,
-					"search_threshold": float64(-0.1),
-				},
-			},
-			imageBytes: []byte("fake-image-data"),
-			threshold:  0,
-			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider, ar *MockSearchAuditRepository) {
-			},
-			expectedError: domain.ErrInvalidThreshold,
-		},
-		{
-			name: "invalid max results - too high",
-			tenant: &domain.Tenant{
-				ID: uuid.New(),
-				Settings: map[string]interface{}{
-					"search_enabled": true,
-				},
-			},
-			imageBytes: []byte("fake-image-data"),
-			threshold:  0.8,
-			maxResults: 100,
-			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider, ar *MockSearchAuditRepository) {
-			},
-			expectedError: domain.ErrInvalidMaxResults,
-		},
-		{
-			name: "invalid max results - zero with invalid default",
-			tenant: &domain.Tenant{
-				ID: uuid.New(),
-				Settings: map[string]interface{}{
-					"search_enabled":     true,
-					"search_max_results": float64(0),
-					"search_threshold":   0.8,
-				},
-			},
-			imageBytes: []byte("fake-image-data"),
-			threshold:  0.8,
-			maxResults: 0,
-			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider, ar *MockSearchAuditRepository) {
-			},
-			expectedError: domain.ErrInvalidMaxResults,
-		},
-		{
-			name: "use tenant default settings",
-			tenant: &domain.Tenant{
-				ID: uuid.New(),
-				Settings: map[string]interface{}{
-					"search_enabled":     true,
-					"search_threshold":   float64(0.9),
-					"search_max_results": float64(5),
+				faceProvider.On("DetectFaces", mock.Anything, mock.Anything).Return(tt.detected, tt.detectErr)
+				if record {
+					verificationRepo.On("Create", mock.Anything, mock.MatchedBy(func(v *domain.Verification) bool {
+						return !v.Verified && v.FailReason == tt.wantReason &&
+							v.TenantID == tenantID && v.ExternalID == "user_001" && *v.FaceID == faceID
+					})).Return(nil)
+				}
+
+				svc := NewFaceService(faceRepo, verificationRepo, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+
+				settings := domain.DefaultTenantSettings()
+				settings.RecordRejectedVerifications = record
+
+				_, err := svc.Verify(context.Background(), tenantID, "user_001", make([]byte, 5000), settings)
+
+				require.Error(t, err)
+				if tt.wantErr != nil {
+					assertAppError(t, err, tt.wantErr)
+				}
+				if record {
+					verificationRepo.AssertExpectations(t)
+				} else {
+					verificationRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				}
+				faceProvider.AssertNotCalled(t, "IndexFace", mock.Anything, mock.Anything)
+			})
+		}
+	}
+
+	t.Run("recording failure keeps the rejection error", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		verificationRepo := &MockVerificationRepository{}
+		faceProvider := &MockFaceProvider{}
+
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(&domain.Face{ID: faceID, TenantID: tenantID, ExternalID: "user_001", Embedding: unitEmbedding()}, nil)
+		faceProvider.On("DetectFaces", mock.Anything, mock.Anything).Return([]provider.DetectedFace{}, nil)
+		verificationRepo.On("Create", mock.Anything, mock.Anything).Return(errors.New("db down"))
+
+		svc := NewFaceService(faceRepo, verificationRepo, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+
+		settings := domain.DefaultTenantSettings()
+		settings.RecordRejectedVerifications = true
+
+		_, err := svc.Verify(context.Background(), tenantID, "user_001", make([]byte, 5000), settings)
+
+		assert.ErrorIs(t, err, domain.ErrNoFaceDetected)
+	})
+}
+
+func TestFaceService_Verify_WithoutStoredEmbedding(t *testing.T) {
+	tenantID := uuid.New()
+
+	for _, embedding := range [][]float64{nil, {}} {
+		t.Run(fmt.Sprintf("embedding length %d", len(embedding)), func(t *testing.T) {
+			faceRepo := &MockFaceRepository{}
+			verificationRepo := &MockVerificationRepository{}
+			faceProvider := &MockFaceProvider{}
+
+			faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(&domain.Face{
+				ID:             uuid.New(),
+				TenantID:       tenantID,
+				ExternalID:     "user_001",
+				Embedding:      embedding,
+				ProviderFaceID: "aws-face-1",
+			}, nil)
+
+			svc := NewFaceService(faceRepo, verificationRepo, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+
+			settings := domain.DefaultTenantSettings()
+			settings.RecordRejectedVerifications = true
+
+			verification, err := svc.Verify(context.Background(), tenantID, "user_001", make([]byte, 5000), settings)
+
+			assert.ErrorIs(t, err, domain.ErrVerifyNotSupportedWithoutSource)
+			assert.Nil(t, verification)
+			faceProvider.AssertNotCalled(t, "DetectFaces", mock.Anything, mock.Anything)
+			faceProvider.AssertNotCalled(t, "CompareFaces", mock.Anything, mock.Anything, mock.Anything)
+			verificationRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("face registered with a provider without embeddings", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+		faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{QualityScore: 0.95, FaceCount: 1}, nil)
+		faceProvider.On("IndexFace", mock.Anything, mock.Anything).Return("aws-face-1", []float64(nil), nil)
+
+		var stored *domain.Face
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_002").Return(nil, domain.ErrFaceNotFound).Once()
+		faceRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*domain.Face)
+		}).Return(nil)
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+		_, err := svc.Register(context.Background(), tenantID, "user_002", make([]byte, 5000), nil, domain.DefaultTenantSettings())
+		require.NoError(t, err)
+
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_002").Return(stored, nil)
+		_, err = svc.Verify(context.Background(), tenantID, "user_002", make([]byte, 5000), domain.DefaultTenantSettings())
+
+		assert.ErrorIs(t, err, domain.ErrVerifyNotSupportedWithoutSource)
+		faceProvider.AssertNotCalled(t, "CompareFaces", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestFaceService_Verify_Parallel(t *testing.T) {
+	tenantID := uuid.New()
+	faceID := uuid.New()
+	embedding := unitEmbedding()
+	storedFace := &domain.Face{ID: faceID, TenantID: tenantID, ExternalID: "user_001", Embedding: embedding}
+
+	t.Run("matches the sequential result", func(t *testing.T) {
+		for _, parallel := range []bool{false, true} {
+			faceRepo := &MockFaceRepository{}
+			verificationRepo := &MockVerificationRepository{}
+			faceProvider := &MockFaceProvider{}
+
+			faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").After(5*time.Millisecond).Return(storedFace, nil)
+			faceProvider.On("DetectFaces", mock.Anything, mock.Anything).Return([]provider.DetectedFace{{Confidence: 0.99, QualityScore: 0.95}}, nil)
+			faceProvider.On("IndexFace", mock.Anything, mock.Anything).Return("face-id", embedding, nil)
+			faceProvider.On("CompareFaces", mock.Anything, embedding, embedding).Return(0.93, nil)
+			verificationRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+			svc := NewFaceService(faceRepo, verificationRepo, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{}).
+				WithParallelVerify(parallel)
+
+			verification, err := svc.Verify(context.Background(), tenantID, "user_001", make([]byte, 5000), domain.DefaultTenantSettings())
+
+			require.NoError(t, err, "parallel=%v", parallel)
+			assert.True(t, verification.Verified, "parallel=%v", parallel)
+			assert.Equal(t, 0.93, verification.Confidence, "parallel=%v", parallel)
+			assert.Equal(t, faceID, *verification.FaceID, "parallel=%v", parallel)
+			faceProvider.AssertExpectations(t)
+		}
+	})
+
+	t.Run("refused lookup cancels the probe", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+
+		cancelled := make(chan struct{})
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "unknown").Return(nil, domain.ErrFaceNotFound)
+		faceProvider.On("DetectFaces", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			<-args.Get(0).(context.Context).Done()
+			close(cancelled)
+		}).Return(nil, context.Canceled)
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{}).
+			WithParallelVerify(true)
+
+		_, err := svc.Verify(context.Background(), tenantID, "unknown", make([]byte, 5000), domain.DefaultTenantSettings())
+		assert.ErrorIs(t, err, domain.ErrFaceNotFound)
+
+		select {
+		case <-cancelled:
+		case <-time.After(time.Second):
+			t.Fatal("probe was not cancelled")
+		}
+		faceProvider.AssertNotCalled(t, "IndexFace", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejected probe waits for the lookup to record it", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		verificationRepo := &MockVerificationRepository{}
+		faceProvider := &MockFaceProvider{}
+
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").After(20*time.Millisecond).Return(storedFace, nil)
+		faceProvider.On("DetectFaces", mock.Anything, mock.Anything).Return([]provider.DetectedFace{}, nil)
+		verificationRepo.On("Create", mock.Anything, mock.MatchedBy(func(v *domain.Verification) bool {
+			return !v.Verified && v.FailReason == domain.FailReasonNoFace && *v.FaceID == faceID
+		})).Return(nil)
+
+		svc := NewFaceService(faceRepo, verificationRepo, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{}).
+			WithParallelVerify(true)
+
+		settings := domain.DefaultTenantSettings()
+		settings.RecordRejectedVerifications = true
+
+		_, err := svc.Verify(context.Background(), tenantID, "user_001", make([]byte, 5000), settings)
+
+		assert.ErrorIs(t, err, domain.ErrNoFaceDetected)
+		verificationRepo.AssertExpectations(t)
+	})
+
+	t.Run("lookup error takes precedence over a rejected probe", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "unknown").After(20*time.Millisecond).Return(nil, domain.ErrFaceNotFound)
+		faceProvider.On("DetectFaces", mock.Anything, mock.Anything).Return([]provider.DetectedFace{}, nil)
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{}).
+			WithParallelVerify(true)
+
+		_, err := svc.Verify(context.Background(), tenantID, "unknown", make([]byte, 5000), domain.DefaultTenantSettings())
+		assert.ErrorIs(t, err, domain.ErrFaceNotFound)
+	})
+}
+
+func TestFaceService_Verify_Hysteresis(t *testing.T) {
+	tenantID := uuid.New()
+	embedding := unitEmbedding()
+	recent := &domain.GateVisit{Gate: "10.0.0.11", At: time.Now().Add(-2 * time.Minute)}
+	stale := &domain.GateVisit{Gate: "10.0.0.11", At: time.Now().Add(-20 * time.Minute)}
+	hysteresis := domain.VerifyHysteresisSettings{Margin: 0.03, Window: 10 * time.Minute}
+
+	tests := []struct {
+		name         string
+		hysteresis   domain.VerifyHysteresisSettings
+		similarity   float64
+		last         *domain.GateVisit
+		lastErr      error
+		wantLookup   bool
+		wantVerified bool
+	}{
+		{name: "borderline retry after a recent pass is allowed", hysteresis: hysteresis, similarity: 0.78, last: recent, wantLookup: true, wantVerified: true},
+		{name: "borderline retry without hysteresis is denied", similarity: 0.78, last: recent},
+		{name: "below the margin is denied", hysteresis: hysteresis, similarity: 0.76, last: recent},
+		{name: "last pass outside the window is denied", hysteresis: hysteresis, similarity: 0.78, last: stale, wantLookup: true},
+		{name: "never verified is denied", hysteresis: hysteresis, similarity: 0.78, wantLookup: true},
+		{name: "lookup failure is denied", hysteresis: hysteresis, similarity: 0.78, lastErr: errors.New("db down"), wantLookup: true},
+		{name: "above the threshold needs no lookup", hysteresis: hysteresis, similarity: 0.85, wantVerified: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			faceRepo := &MockFaceRepository{}
+			verificationRepo := &MockVerificationRepository{}
+			faceProvider := &MockFaceProvider{}
+
+			faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(&domain.Face{
+				ID:        uuid.New(),
+				Embedding: embedding,
+			}, nil)
+			faceProvider.On("DetectFaces", mock.Anything, mock.Anything).Return([]provider.DetectedFace{{Confidence: 0.99}}, nil)
+			faceProvider.On("IndexFace", mock.Anything, mock.Anything).Return("face-id", embedding, nil)
+			faceProvider.On("CompareFaces", mock.Anything, mock.Anything, mock.Anything).Return(tt.similarity, nil)
+			if tt.wantLookup {
+				verificationRepo.On("LastVerifiedGate", mock.Anything, tenantID, "user_001", false, true).Return(tt.last, tt.lastErr)
+			}
+			verificationRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+			svc := NewFaceService(faceRepo, verificationRepo, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+
+			settings := domain.DefaultTenantSettings()
+			settings.VerificationThreshold = 0.8
+			settings.VerifyHysteresis = tt.hysteresis
+
+			verification, err := svc.Verify(context.Background(), tenantID, "user_001", make([]byte, 5000), settings)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantVerified, verification.Verified)
+			assert.Equal(t, tt.wantVerified, verification.MatchPassed)
+			assert.Equal(t, tt.similarity, verification.Confidence)
+			if !tt.wantVerified {
+				assert.Equal(t, domain.FailReasonMatchBelowThreshold, verification.FailReason)
+			}
+			// Borderline allows are marked so they never open the window for the next one
+			assert.Equal(t, tt.wantVerified && tt.similarity < settings.VerificationThreshold, verification.HysteresisPass)
+			if !tt.wantLookup {
+				verificationRepo.AssertNotCalled(t, "LastVerifiedGate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			verificationRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFaceService_Verify_SegmentThresholds(t *testing.T) {
+	tenantID := uuid.New()
+	embedding := unitEmbedding()
+
+	tests := []struct {
+		name          string
+		metadata      map[string]interface{}
+		wantVerified  bool
+		wantThreshold float64
+		wantSource    domain.ThresholdSource
+	}{
+		{name: "staff face uses the stricter threshold", metadata: map[string]interface{}{"ticket_type": "staff"}, wantThreshold: 0.95, wantSource: domain.ThresholdSourceSegment},
+		{name: "general face uses the tenant threshold", metadata: map[string]interface{}{"ticket_type": "general"}, wantVerified: true, wantThreshold: 0.8, wantSource: domain.ThresholdSourceTenant},
+		{name: "untagged face uses the tenant threshold", wantVerified: true, wantThreshold: 0.8, wantSource: domain.ThresholdSourceTenant},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			faceRepo := &MockFaceRepository{}
+			verificationRepo := &MockVerificationRepository{}
+			faceProvider := &MockFaceProvider{}
+
+			faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(&domain.Face{
+				ID:        uuid.New(),
+				Embedding: embedding,
+				Metadata:  tt.metadata,
+			}, nil)
+			faceProvider.On("DetectFaces", mock.Anything, mock.Anything).Return([]provider.DetectedFace{{Confidence: 0.99}}, nil)
+			faceProvider.On("IndexFace", mock.Anything, mock.Anything).Return("face-id", embedding, nil)
+			faceProvider.On("CompareFaces", mock.Anything, mock.Anything, mock.Anything).Return(0.9, nil)
+			verificationRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+			svc := NewFaceService(faceRepo, verificationRepo, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+
+			tenant := &domain.Tenant{ID: tenantID, Settings: map[string]interface{}{
+				"verification_threshold": 0.8,
+				"segment_thresholds": map[string]interface{}{
+					"key":    "ticket_type",
+					"verify": map[string]interface{}{"staff": 0.95},
 				},
-			},
-			imageBytes: []byte("fake-image-data"),
-			threshold:  0,
-			maxResults: 0,
-			clientIP:   "192.168.1.3",
-			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider, ar *MockSearchAuditRepository) {
-				fp.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
-					Embedding:     []float64{0.2, 0.3, 0.4},
-					Confidence:    0.99,
-					QualityScore:  0.95,
-					LivenessScore: 0.90,
-					FaceCount:     1,
+			}}
+			settings := tenant.GetSettings()
+
+			verification, err := svc.Verify(context.Background(), tenantID, "user_001", make([]byte, 5000), settings)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantVerified, verification.Verified)
+			assert.Equal(t, tt.wantThreshold, verification.ThresholdApplied)
+			assert.Equal(t, tt.wantSource, verification.ThresholdSource)
+		})
+	}
+}
+
+func TestFaceService_Verify_AntiPassback(t *testing.T) {
+	tenantID := uuid.New()
+	embedding := make([]float64, 512)
+	recent := &domain.GateVisit{Gate: "10.0.0.11", At: time.Now().Add(-2 * time.Minute)}
+	stale := &domain.GateVisit{Gate: "10.0.0.11", At: time.Now().Add(-20 * time.Minute)}
+
+	tests := []struct {
+		name       string
+		gate       string
+		window     time.Duration
+		last       *domain.GateVisit
+		wantLookup bool
+		wantErr    error
+	}{
+		{name: "same gate within window is allowed", gate: "10.0.0.11", window: 10 * time.Minute, last: recent, wantLookup: true},
+		{name: "different gate within window is rejected", gate: "10.0.0.12", window: 10 * time.Minute, last: recent, wantLookup: true, wantErr: domain.ErrAntiPassbackViolation},
+		{name: "different gate after window is allowed", gate: "10.0.0.12", window: 10 * time.Minute, last: stale, wantLookup: true},
+		{name: "never verified is allowed", gate: "10.0.0.12", window: 10 * time.Minute, wantLookup: true},
+		{name: "last gate unknown is allowed", gate: "10.0.0.12", window: 10 * time.Minute, last: &domain.GateVisit{At: time.Now()}, wantLookup: true},
+		{name: "unknown gate is not checked", window: 10 * time.Minute},
+		{name: "disabled is not checked", gate: "10.0.0.12"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			faceRepo := &MockFaceRepository{}
+			verificationRepo := &MockVerificationRepository{}
+			faceProvider := &MockFaceProvider{}
+
+			faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(&domain.Face{
+				ID:        uuid.New(),
+				Embedding: embedding,
+			}, nil)
+			if tt.wantLookup {
+				verificationRepo.On("LastVerifiedGate", mock.Anything, tenantID, "user_001", false, false).Return(tt.last, nil)
+			}
+			if tt.wantErr == nil {
+				faceProvider.On("DetectFaces", mock.Anything, mock.Anything).Return([]provider.DetectedFace{{Confidence: 0.99}}, nil)
+				faceProvider.On("IndexFace", mock.Anything, mock.Anything).Return("face-id", embedding, nil)
+				faceProvider.On("CompareFaces", mock.Anything, mock.Anything, mock.Anything).Return(0.95, nil)
+				verificationRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+			}
+
+			svc := NewFaceService(faceRepo, verificationRepo, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+
+			settings := domain.DefaultTenantSettings()
+			settings.AntiPassback.Window = tt.window
+			ctx := domain.WithClientIP(context.Background(), tt.gate)
+
+			verification, err := svc.Verify(ctx, tenantID, "user_001", make([]byte, 5000), settings)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, verification)
+				faceProvider.AssertNotCalled(t, "DetectFaces", mock.Anything, mock.Anything)
+			} else {
+				require.NoError(t, err)
+				assert.True(t, verification.Verified)
+			}
+			if !tt.wantLookup {
+				verificationRepo.AssertNotCalled(t, "LastVerifiedGate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			verificationRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("violation is recorded for tenants recording rejections", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		verificationRepo := &MockVerificationRepository{}
+
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(&domain.Face{
+			ID:         uuid.New(),
+			TenantID:   tenantID,
+			ExternalID: "user_001",
+			Embedding:  embedding,
+		}, nil)
+		verificationRepo.On("LastVerifiedGate", mock.Anything, tenantID, "user_001", false, false).Return(recent, nil)
+		verificationRepo.On("Create", mock.Anything, mock.MatchedBy(func(v *domain.Verification) bool {
+			return !v.Verified && v.FailReason == domain.FailReasonAntiPassback && v.ClientIP == "10.0.0.12"
+		})).Return(nil)
+
+		svc := NewFaceService(faceRepo, verificationRepo, &MockSearchAuditRepository{}, &MockFaceProvider{}, &MockRateLimiter{})
+
+		settings := domain.DefaultTenantSettings()
+		settings.AntiPassback.Window = 10 * time.Minute
+		settings.RecordRejectedVerifications = true
+
+		_, err := svc.Verify(domain.WithClientIP(context.Background(), "10.0.0.12"), tenantID, "user_001", make([]byte, 5000), settings)
+
+		assert.ErrorIs(t, err, domain.ErrAntiPassbackViolation)
+		verificationRepo.AssertExpectations(t)
+	})
+
+	t.Run("lookup failure fails verification", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		verificationRepo := &MockVerificationRepository{}
+
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(&domain.Face{ID: uuid.New()}, nil)
+		verificationRepo.On("LastVerifiedGate", mock.Anything, tenantID, "user_001", false, false).Return(nil, errors.New("database unavailable"))
+
+		svc := NewFaceService(faceRepo, verificationRepo, &MockSearchAuditRepository{}, &MockFaceProvider{}, &MockRateLimiter{})
+
+		settings := domain.DefaultTenantSettings()
+		settings.AntiPassback.Window = 10 * time.Minute
+
+		_, err := svc.Verify(domain.WithClientIP(context.Background(), "10.0.0.12"), tenantID, "user_001", make([]byte, 5000), settings)
+
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, domain.ErrAntiPassbackViolation)
+		verificationRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFaceService_Verify_ReenrollCheck(t *testing.T) {
+	tenantID := uuid.New()
+	enrolledAt := time.Now().Add(-30 * 24 * time.Hour)
+	embedding := make([]float64, 512)
+	settings := domain.DefaultTenantSettings()
+	settings.ReenrollCheckEnabled = true
+	settings.ReenrollWindow = 3
+	settings.ReenrollMargin = 0.1
+
+	newService := func(confidence float64, history []float64, auditErr error) (*FaceService, *MockVerificationRepository) {
+		faceRepo := &MockFaceRepository{}
+		verificationRepo := &MockVerificationRepository{}
+		faceProvider := &MockFaceProvider{}
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(&domain.Face{
+			ID:           uuid.New(),
+			ExternalID:   "user_001",
+			Embedding:    embedding,
+			QualityScore: 0.95,
+			EnrolledAt:   enrolledAt,
+			// A later metadata edit does not move the re-enrollment cutoff
+			UpdatedAt: time.Now(),
+		}, nil)
+		faceProvider.On("DetectFaces", mock.Anything, mock.Anything).Return([]provider.DetectedFace{{Confidence: 0.99}}, nil)
+		faceProvider.On("IndexFace", mock.Anything, mock.Anything).Return("", embedding, nil)
+		faceProvider.On("CompareFaces", mock.Anything, mock.Anything, mock.Anything).Return(confidence, nil)
+		verificationRepo.On("Create", mock.Anything, mock.Anything).Return(auditErr)
+		verificationRepo.On("RecentConfidences", mock.Anything, tenantID, "user_001", enrolledAt, 4).Return(history, nil).Maybe()
+
+		svc := NewFaceService(faceRepo, verificationRepo, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+		return svc, verificationRepo
+	}
+
+	t.Run("suggests re-enrollment when confidence drifted below the margin", func(t *testing.T) {
+		svc, _ := newService(0.82, []float64{0.82, 0.83, 0.81, 0.93}, nil)
+
+		result, err := svc.Verify(context.Background(), tenantID, "user_001", make([]byte, 5000), settings)
+
+		require.NoError(t, err)
+		assert.True(t, result.Verified)
+		require.NotNil(t, result.ReenrollSuggested)
+		assert.Equal(t, 0.95, result.ReenrollSuggested.EnrolledQuality)
+		assert.Equal(t, []float64{0.82, 0.83, 0.81}, result.ReenrollSuggested.RecentConfidences)
+	})
+
+	t.Run("no suggestion while confidence holds", func(t *testing.T) {
+		svc, _ := newService(0.93, []float64{0.93, 0.82, 0.81}, nil)
+
+		result, err := svc.Verify(context.Background(), tenantID, "user_001", make([]byte, 5000), settings)
+
+		require.NoError(t, err)
+		assert.Nil(t, result.ReenrollSuggested)
+	})
+
+	t.Run("disabled check does not read history", func(t *testing.T) {
+		svc, verificationRepo := newService(0.82, []float64{0.82, 0.83, 0.81}, nil)
+
+		result, err := svc.Verify(context.Background(), tenantID, "user_001", make([]byte, 5000), domain.DefaultTenantSettings())
+
+		require.NoError(t, err)
+		assert.Nil(t, result.ReenrollSuggested)
+		verificationRepo.AssertNotCalled(t, "RecentConfidences", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("failed verifications are not checked", func(t *testing.T) {
+		svc, verificationRepo := newService(0.40, []float64{0.82, 0.83, 0.81}, nil)
+
+		result, err := svc.Verify(context.Background(), tenantID, "user_001", make([]byte, 5000), settings)
+
+		require.NoError(t, err)
+		assert.False(t, result.Verified)
+		assert.Nil(t, result.ReenrollSuggested)
+		verificationRepo.AssertNotCalled(t, "RecentConfidences", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("skipped when the attempt was not recorded", func(t *testing.T) {
+		svc, verificationRepo := newService(0.82, []float64{0.82, 0.83, 0.81}, errors.New("db down"))
+
+		result, err := svc.Verify(context.Background(), tenantID, "user_001", make([]byte, 5000), settings)
+
+		require.NoError(t, err)
+		assert.Nil(t, result.ReenrollSuggested)
+		verificationRepo.AssertNotCalled(t, "RecentConfidences", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestFaceService_MultipleFacesPolicy(t *testing.T) {
+	storedEmbedding := unitEmbedding()
+
+	tests := []struct {
+		name      string
+		policy    domain.MultipleFacesPolicy
+		operation string
+		wantErr   error
+	}{
+		{name: "register rejects by default", policy: domain.MultipleFacesReject, operation: "register", wantErr: domain.ErrMultipleFaces},
+		{name: "register uses largest face", policy: domain.MultipleFacesUseLargest, operation: "register"},
+		{name: "verify rejects by default", policy: domain.MultipleFacesReject, operation: "verify", wantErr: domain.ErrMultipleFaces},
+		{name: "verify uses largest face", policy: domain.MultipleFacesUseLargest, operation: "verify"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			faceRepo := &MockFaceRepository{}
+			verificationRepo := &MockVerificationRepository{}
+			faceProvider := &MockFaceProvider{}
+
+			svc := &FaceService{
+				faceRepo:         faceRepo,
+				verificationRepo: verificationRepo,
+				searchAuditRepo:  &MockSearchAuditRepository{},
+				provider:         faceProvider,
+			}
+
+			settings := domain.DefaultTenantSettings()
+			settings.OnMultipleFaces = tt.policy
+
+			var err error
+			switch tt.operation {
+			case "register":
+				faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+					Embedding:    storedEmbedding,
+					QualityScore: 0.95,
+					FaceCount:    2,
 				}, nil)
-				fr.On("SearchByEmbedding", mock.Anything, mock.Anything, mock.Anything, 0.9, 5).Return([]domain.SearchMatch{
-					{FaceID: uuid.New(), ExternalID: "user3", Similarity: 0.92},
+				if tt.wantErr == nil {
+					faceRepo.On("GetByExternalID", mock.Anything, mock.Anything, "user_001").Return(nil, domain.ErrFaceNotFound)
+					faceRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+				}
+				_, err = svc.Register(context.Background(), uuid.New(), "user_001", make([]byte, 5000), nil, settings)
+			case "verify":
+				faceRepo.On("GetByExternalID", mock.Anything, mock.Anything, "user_001").Return(&domain.Face{
+					ID:        uuid.New(),
+					Embedding: storedEmbedding,
 				}, nil)
-				ar.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+				faceProvider.On("DetectFaces", mock.Anything, mock.Anything).Return([]provider.DetectedFace{
+					{BoundingBox: provider.BoundingBox{Width: 200, Height: 200}, Confidence: 0.99},
+					{BoundingBox: provider.BoundingBox{Width: 40, Height: 40}, Confidence: 0.90},
+				}, nil)
+				if tt.wantErr == nil {
+					faceProvider.On("IndexFace", mock.Anything, mock.Anything).Return("face-id", storedEmbedding, nil)
+					faceProvider.On("CompareFaces", mock.Anything, mock.Anything, mock.Anything).Return(0.92, nil)
+					verificationRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+				}
+				_, err = svc.Verify(context.Background(), uuid.New(), "user_001", make([]byte, 5000), settings)
+			}
+
+			if tt.wantErr != nil {
+				assertAppError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			faceRepo.AssertExpectations(t)
+			faceProvider.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFaceService_MultipleFacesCount(t *testing.T) {
+	assertFaceCount := func(t *testing.T, err error, detected int) {
+		t.Helper()
+		var appErr *domain.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, domain.ErrMultipleFaces.Code, appErr.Code)
+		assert.Equal(t, &domain.FaceCountLimit{Detected: detected, Allowed: domain.MaxFacesPerImage}, appErr.FaceCount)
+		assert.Equal(t, fmt.Sprintf("%d faces detected, only 1 allowed", detected), appErr.Message)
+	}
+
+	t.Run("register reports the analyzed face count", func(t *testing.T) {
+		faceProvider := &MockFaceProvider{}
+		faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{QualityScore: 0.95, FaceCount: 3}, nil)
+
+		svc := NewFaceService(&MockFaceRepository{}, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+		_, err := svc.Register(context.Background(), uuid.New(), "user_001", make([]byte, 5000), nil, domain.DefaultTenantSettings())
+
+		assertFaceCount(t, err, 3)
+	})
+
+	t.Run("register reports the count of a provider index rejection", func(t *testing.T) {
+		faceProvider := &MockFaceProvider{}
+		faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{QualityScore: 0.95, FaceCount: 1}, nil)
+		faceProvider.On("IndexFace", mock.Anything, mock.Anything).Return("", []float64(nil), &provider.MultipleFacesError{
+			Count: 2,
+			Err:   errors.New("multiple faces detected in image: 2 faces"),
+		})
+
+		svc := NewFaceService(&MockFaceRepository{}, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+		_, err := svc.Register(context.Background(), uuid.New(), "user_001", make([]byte, 5000), nil, domain.DefaultTenantSettings())
+
+		assertFaceCount(t, err, 2)
+	})
+
+	t.Run("verify reports the detected face count", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+		faceRepo.On("GetByExternalID", mock.Anything, mock.Anything, "user_001").Return(&domain.Face{ID: uuid.New(), Embedding: unitEmbedding()}, nil)
+		faceProvider.On("DetectFaces", mock.Anything, mock.Anything).Return([]provider.DetectedFace{
+			{Confidence: 0.99}, {Confidence: 0.97}, {Confidence: 0.95}, {Confidence: 0.91},
+		}, nil)
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+		_, err := svc.Verify(context.Background(), uuid.New(), "user_001", make([]byte, 5000), domain.DefaultTenantSettings())
+
+		assertFaceCount(t, err, 4)
+	})
+}
+
+func TestFaceService_EmbeddingFingerprint(t *testing.T) {
+	embedding := unitEmbedding()
+
+	tests := []struct {
+		name        string
+		storedModel string
+		storedVer   string
+		wantErr     error
+	}{
+		{name: "same model compares", storedModel: "deepface/Facenet512", storedVer: "512d"},
+		{name: "legacy face without fingerprint compares", storedModel: "", storedVer: ""},
+		{name: "different model is rejected", storedModel: "deepface/ArcFace", storedVer: "512d", wantErr: domain.ErrEmbeddingModelMismatch},
+		{name: "different dimension is rejected", storedModel: "deepface/Facenet512", storedVer: "128d", wantErr: domain.ErrEmbeddingModelMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			faceRepo := &MockFaceRepository{}
+			verificationRepo := &MockVerificationRepository{}
+			faceProvider := &MockFaceProvider{}
+
+			svc := &FaceService{
+				faceRepo:         faceRepo,
+				verificationRepo: verificationRepo,
+				searchAuditRepo:  &MockSearchAuditRepository{},
+				provider:         faceProvider,
+				embeddingModel:   "deepface/Facenet512",
+			}
+
+			faceRepo.On("GetByExternalID", mock.Anything, mock.Anything, "user_001").Return(&domain.Face{
+				ID:               uuid.New(),
+				Embedding:        embedding,
+				EmbeddingModel:   tt.storedModel,
+				EmbeddingVersion: tt.storedVer,
+			}, nil)
+			faceProvider.On("DetectFaces", mock.Anything, mock.Anything).Return([]provider.DetectedFace{
+				{Confidence: 0.99},
+			}, nil)
+			faceProvider.On("IndexFace", mock.Anything, mock.Anything).Return("face-id", embedding, nil)
+			if tt.wantErr == nil {
+				faceProvider.On("CompareFaces", mock.Anything, mock.Anything, mock.Anything).Return(0.92, nil)
+				verificationRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+			}
+
+			_, err := svc.Verify(context.Background(), uuid.New(), "user_001", make([]byte, 5000), domain.DefaultTenantSettings())
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				faceProvider.AssertNotCalled(t, "CompareFaces", mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				require.NoError(t, err)
+			}
+
+			faceRepo.AssertExpectations(t)
+			faceProvider.AssertExpectations(t)
+		})
+	}
+
+	t.Run("register records fingerprint", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{}).
+			WithEmbeddingModel("deepface/Facenet512")
+
+		faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+			Embedding:    embedding,
+			QualityScore: 0.95,
+			FaceCount:    1,
+		}, nil)
+		faceRepo.On("GetByExternalID", mock.Anything, mock.Anything, "user_001").Return(nil, domain.ErrFaceNotFound)
+		faceRepo.On("Create", mock.Anything, mock.MatchedBy(func(f *domain.Face) bool {
+			return f.EmbeddingModel == "deepface/Facenet512" && f.EmbeddingVersion == "512d"
+		})).Return(nil)
+
+		face, err := svc.Register(context.Background(), uuid.New(), "user_001", make([]byte, 5000), nil, domain.DefaultTenantSettings())
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.EmbeddingFingerprint{Model: "deepface/Facenet512", Version: "512d"}, face.Fingerprint())
+		faceRepo.AssertExpectations(t)
+	})
+
+	t.Run("search only compares matching fingerprint", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+		rateLimiter := &MockRateLimiter{}
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, rateLimiter).
+			WithEmbeddingModel("deepface/Facenet512")
+
+		tenant := &domain.Tenant{
+			ID:       uuid.New(),
+			Settings: map[string]interface{}{"search_enabled": true},
+		}
+
+		rateLimiter.On("CheckSearchLimit", mock.Anything, tenant.ID, mock.Anything).Return(nil)
+		faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+			Embedding: embedding,
+			FaceCount: 1,
+		}, nil)
+		faceRepo.On("SearchByEmbedding", mock.Anything, tenant.ID, mock.Anything,
+			domain.EmbeddingFingerprint{Model: "deepface/Facenet512", Version: "512d"}, mock.Anything, mock.Anything,
+		).Return([]domain.SearchMatch{}, nil)
+
+		_, err := svc.Search(context.Background(), tenant, make([]byte, 5000), 0.8, 10, "127.0.0.1")
+
+		require.NoError(t, err)
+		faceRepo.AssertExpectations(t)
+	})
+}
+
+func TestFaceService_Register_MetadataSize(t *testing.T) {
+	const maxBytes = 64
+	// {"note":"..."} serializes to 11 bytes plus the note length
+	metadataOfSize := func(size int) map[string]interface{} {
+		return map[string]interface{}{"note": strings.Repeat("a", size-11)}
+	}
+
+	tests := []struct {
+		name     string
+		metadata map[string]interface{}
+		wantErr  error
+	}{
+		{name: "no metadata", metadata: nil},
+		{name: "metadata at limit is accepted", metadata: metadataOfSize(maxBytes)},
+		{name: "metadata over limit is rejected", metadata: metadataOfSize(maxBytes + 1), wantErr: domain.ErrMetadataTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			faceRepo := &MockFaceRepository{}
+			faceProvider := &MockFaceProvider{}
+
+			svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{}).
+				WithMaxMetadataBytes(maxBytes)
+
+			if tt.wantErr == nil {
+				faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+					Embedding:    unitEmbedding(),
+					QualityScore: 0.95,
+					FaceCount:    1,
+				}, nil)
+				faceRepo.On("GetByExternalID", mock.Anything, mock.Anything, "user_001").Return(nil, domain.ErrFaceNotFound)
+				faceRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+			}
+
+			face, err := svc.Register(context.Background(), uuid.New(), "user_001", make([]byte, 5000), tt.metadata, domain.DefaultTenantSettings())
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				faceProvider.AssertNotCalled(t, "AnalyzeFace", mock.Anything, mock.Anything)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.metadata, face.Metadata)
+			}
+
+			faceRepo.AssertExpectations(t)
+			faceProvider.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFaceService_FaceEdgeMargin(t *testing.T) {
+	var jpegImage bytes.Buffer
+	require.NoError(t, jpeg.Encode(&jpegImage, image.NewRGBA(image.Rect(0, 0, 640, 480)), nil))
+
+	centered := provider.BoundingBox{X: 0.3, Y: 0.2, Width: 0.4, Height: 0.5}
+	atLeftEdge := provider.BoundingBox{X: 0.0, Y: 0.2, Width: 0.4, Height: 0.5}
+	cutOffBottom := provider.BoundingBox{X: 0.3, Y: 0.6, Width: 0.4, Height: 0.5}
+
+	tests := []struct {
+		name      string
+		operation string
+		box       provider.BoundingBox
+		image     []byte
+		margin    float64
+		wantErr   error
+	}{
+		{name: "register centered face", operation: "register", box: centered, margin: 0.05},
+		{name: "register face at the edge", operation: "register", box: atLeftEdge, margin: 0.05, wantErr: domain.ErrFaceOutOfFrame},
+		{name: "verify centered face", operation: "verify", box: centered, margin: 0.05},
+		{name: "verify face cut off at the bottom", operation: "verify", box: cutOffBottom, margin: 0.05, wantErr: domain.ErrFaceOutOfFrame},
+		{name: "check disabled", operation: "register", box: atLeftEdge},
+		{
+			name:      "pixel box centered in the image",
+			operation: "verify",
+			box:       provider.BoundingBox{X: 200, Y: 100, Width: 240, Height: 280},
+			image:     jpegImage.Bytes(),
+			margin:    0.05,
+		},
+		{
+			name:      "pixel box past the right edge of the image",
+			operation: "register",
+			box:       provider.BoundingBox{X: 500, Y: 100, Width: 160, Height: 200},
+			image:     jpegImage.Bytes(),
+			margin:    0.05,
+			wantErr:   domain.ErrFaceOutOfFrame,
+		},
+		{
+			name:      "pixel box of an undecodable image skips the check",
+			operation: "register",
+			box:       provider.BoundingBox{X: 0, Y: 0, Width: 160, Height: 200},
+			margin:    0.05,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			faceRepo := &MockFaceRepository{}
+			verificationRepo := &MockVerificationRepository{}
+			faceProvider := &MockFaceProvider{}
+			svc := NewFaceService(faceRepo, verificationRepo, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+
+			settings := domain.DefaultTenantSettings()
+			settings.FaceEdgeMargin = tt.margin
+			img := tt.image
+			if img == nil {
+				img = make([]byte, 5000)
+			}
+			embedding := []float64{0.1, 0.2, 0.3}
+
+			var err error
+			switch tt.operation {
+			case "register":
+				faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+					Embedding:    embedding,
+					BoundingBox:  tt.box,
+					QualityScore: 0.95,
+					FaceCount:    1,
+				}, nil)
+				if tt.wantErr == nil {
+					faceRepo.On("GetByExternalID", mock.Anything, mock.Anything, "user_001").Return(nil, domain.ErrFaceNotFound)
+					faceRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+				}
+				_, err = svc.Register(context.Background(), uuid.New(), "user_001", img, nil, settings)
+			case "verify":
+				faceRepo.On("GetByExternalID", mock.Anything, mock.Anything, "user_001").Return(&domain.Face{
+					ID:        uuid.New(),
+					Embedding: embedding,
+				}, nil)
+				faceProvider.On("DetectFaces", mock.Anything, mock.Anything).Return([]provider.DetectedFace{
+					{BoundingBox: tt.box, Confidence: 0.99, QualityScore: 0.95},
+				}, nil)
+				if tt.wantErr == nil {
+					faceProvider.On("IndexFace", mock.Anything, mock.Anything).Return("face-id", embedding, nil)
+					faceProvider.On("CompareFaces", mock.Anything, mock.Anything, mock.Anything).Return(0.92, nil)
+					verificationRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+				}
+				_, err = svc.Verify(context.Background(), uuid.New(), "user_001", img, settings)
+			}
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				faceProvider.AssertNotCalled(t, "IndexFace", mock.Anything, mock.Anything)
+			} else {
+				require.NoError(t, err)
+			}
+			faceRepo.AssertExpectations(t)
+			faceProvider.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFaceService_Register_DownscalesLargeImages(t *testing.T) {
+	encode := func(t *testing.T, width, height int) []byte {
+		t.Helper()
+		var buf bytes.Buffer
+		require.NoError(t, jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height)), nil))
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		name       string
+		width      int
+		height     int
+		wantWidth  int
+		wantHeight int
+	}{
+		{name: "large image is downscaled", width: 2400, height: 1200, wantWidth: 800, wantHeight: 400},
+		{name: "small image is sent as is", width: 640, height: 480, wantWidth: 640, wantHeight: 480},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			faceRepo := &MockFaceRepository{}
+			faceProvider := &MockFaceProvider{}
+			original := encode(t, tt.width, tt.height)
+
+			faceProvider.On("AnalyzeFace", mock.Anything, mock.MatchedBy(func(sent []byte) bool {
+				cfg, _, err := image.DecodeConfig(bytes.NewReader(sent))
+				return err == nil && cfg.Width == tt.wantWidth && cfg.Height == tt.wantHeight
+			})).Return(&provider.FaceAnalysis{
+				Embedding:    unitEmbedding(),
+				QualityScore: 0.95,
+				FaceCount:    1,
+			}, nil)
+			faceRepo.On("GetByExternalID", mock.Anything, mock.Anything, "user_001").Return(nil, domain.ErrFaceNotFound)
+			faceRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+			svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{}).
+				WithMaxImageDimension(800)
+
+			_, err := svc.Register(context.Background(), uuid.New(), "user_001", original, nil, domain.DefaultTenantSettings())
+
+			require.NoError(t, err)
+			faceProvider.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFaceService_RejectsBlankImages(t *testing.T) {
+	encode := func(t *testing.T, textured bool) []byte {
+		t.Helper()
+		img := image.NewRGBA(image.Rect(0, 0, 320, 240))
+		for y := 0; y < 240; y++ {
+			for x := 0; x < 320; x++ {
+				c := color.RGBA{R: 20, G: 20, B: 20, A: 255}
+				if textured {
+					c = color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255}
+				}
+				img.Set(x, y, c)
+			}
+		}
+		var buf bytes.Buffer
+		require.NoError(t, jpeg.Encode(&buf, img, nil))
+		return buf.Bytes()
+	}
+
+	t.Run("blank image is rejected before the provider call", func(t *testing.T) {
+		faceProvider := &MockFaceProvider{}
+		svc := NewFaceService(&MockFaceRepository{}, &MockVerificationRepository{}, nil, faceProvider, nil).
+			WithBlankImageThreshold(2)
+
+		_, err := svc.Register(context.Background(), uuid.New(), "user_001", encode(t, false), nil, domain.DefaultTenantSettings())
+
+		var appErr *domain.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, domain.ErrInvalidImage.Code, appErr.Code)
+		assert.Contains(t, appErr.Message, "blank")
+		assert.ErrorIs(t, err, imaging.ErrBlankImage)
+		faceProvider.AssertNotCalled(t, "AnalyzeFace", mock.Anything, mock.Anything)
+	})
+
+	t.Run("textured image reaches the provider", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+		faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+			Embedding:    unitEmbedding(),
+			QualityScore: 0.95,
+			FaceCount:    1,
+		}, nil)
+		faceRepo.On("GetByExternalID", mock.Anything, mock.Anything, "user_001").Return(nil, domain.ErrFaceNotFound)
+		faceRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{}).
+			WithBlankImageThreshold(2)
+
+		_, err := svc.Register(context.Background(), uuid.New(), "user_001", encode(t, true), nil, domain.DefaultTenantSettings())
+
+		require.NoError(t, err)
+		faceProvider.AssertExpectations(t)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		faceProvider := &MockFaceProvider{}
+		faceProvider.On("DetectFaces", mock.Anything, mock.Anything).Return([]provider.DetectedFace{}, nil)
+		svc := NewFaceService(&MockFaceRepository{}, &MockVerificationRepository{}, nil, faceProvider, nil)
+
+		count, err := svc.CountFaces(context.Background(), uuid.New(), encode(t, false), domain.DefaultTenantSettings())
+
+		require.NoError(t, err)
+		assert.Zero(t, count)
+		faceProvider.AssertExpectations(t)
+	})
+}
+
+type countingInsertRecorder struct {
+	inserted int
+}
+
+func (r *countingInsertRecorder) RecordInserts(n int) {
+	r.inserted += n
+}
+
+func TestFaceService_Register_RecordsInserts(t *testing.T) {
+	embedding := unitEmbedding()
+
+	tests := []struct {
+		name     string
+		existing *domain.Face
+		want     int
+	}{
+		{name: "new face is recorded", existing: nil, want: 1},
+		{name: "re-registration is not recorded", existing: &domain.Face{ID: uuid.New(), ExternalID: "user_001"}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			faceRepo := &MockFaceRepository{}
+			faceProvider := &MockFaceProvider{}
+			recorder := &countingInsertRecorder{}
+
+			svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{}).
+				WithInsertRecorder(recorder)
+
+			faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+				Embedding:    embedding,
+				QualityScore: 0.95,
+				FaceCount:    1,
+			}, nil)
+			if tt.existing != nil {
+				faceRepo.On("GetByExternalID", mock.Anything, mock.Anything, "user_001").Return(tt.existing, nil)
+				faceRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+			} else {
+				faceRepo.On("GetByExternalID", mock.Anything, mock.Anything, "user_001").Return(nil, domain.ErrFaceNotFound)
+				faceRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+			}
+
+			_, err := svc.Register(context.Background(), uuid.New(), "user_001", make([]byte, 5000), nil, domain.DefaultTenantSettings())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, recorder.inserted)
+		})
+	}
+}
+
+// racingFaceRepository stores faces in memory with a unique (tenant, external_id) and
+// holds the first lookups until all registrations have seen "not found"
+type racingFaceRepository struct {
+	MockFaceRepository
+	mu      sync.Mutex
+	faces   map[string]*domain.Face
+	lookups sync.WaitGroup
+	creates int
+	updates int
+}
+
+func (r *racingFaceRepository) GetByExternalID(ctx context.Context, tenantID uuid.UUID, externalID string) (*domain.Face, error) {
+	r.mu.Lock()
+	face, ok := r.faces[externalID]
+	r.mu.Unlock()
+	if !ok {
+		r.lookups.Done()
+		r.lookups.Wait()
+		return nil, domain.ErrFaceNotFound
+	}
+	clone := *face
+	return &clone, nil
+}
+
+func (r *racingFaceRepository) Create(ctx context.Context, face *domain.Face) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.faces[face.ExternalID]; ok {
+		return domain.ErrFaceExists
+	}
+	face.ID = uuid.New()
+	r.faces[face.ExternalID] = face
+	r.creates++
+	return nil
+}
+
+func (r *racingFaceRepository) Update(ctx context.Context, face *domain.Face) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.faces[face.ExternalID] = face
+	r.updates++
+	return nil
+}
+
+func TestFaceService_Register_ConcurrentFirstRegistration(t *testing.T) {
+	const registrations = 2
+	tenantID := uuid.New()
+
+	faceRepo := &racingFaceRepository{faces: make(map[string]*domain.Face)}
+	faceRepo.lookups.Add(registrations)
+	faceProvider := &MockFaceProvider{}
+	faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+		Embedding:    unitEmbedding(),
+		QualityScore: 0.95,
+		FaceCount:    1,
+	}, nil)
+
+	svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+
+	faces := make([]*domain.Face, registrations)
+	errs := make([]error, registrations)
+	var wg sync.WaitGroup
+	for i := 0; i < registrations; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			faces[i], errs[i] = svc.Register(context.Background(), tenantID, "user_001", make([]byte, 5000), nil, domain.DefaultTenantSettings())
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < registrations; i++ {
+		require.NoError(t, errs[i])
+	}
+	assert.Equal(t, faces[0].ID, faces[1].ID, "both registrations converge on the same face")
+	assert.Equal(t, 1, faceRepo.creates)
+	assert.Equal(t, 1, faceRepo.updates)
+}
+
+func TestFaceService_Register_InvalidEmbedding(t *testing.T) {
+	withValue := func(v float64) []float64 {
+		embedding := unitEmbedding()
+		embedding[1] = v
+		return embedding
+	}
+	scaled := func(magnitude float64) []float64 {
+		embedding := make([]float64, domain.EmbeddingDimension)
+		embedding[0] = magnitude
+		return embedding
+	}
+
+	tests := []struct {
+		name         string
+		embedding    []float64
+		maxMagnitude float64
+		wantErr      bool
+	}{
+		{name: "NaN value", embedding: withValue(math.NaN()), wantErr: true},
+		{name: "positive infinity", embedding: withValue(math.Inf(1)), wantErr: true},
+		{name: "negative infinity", embedding: withValue(math.Inf(-1)), wantErr: true},
+		{name: "zero vector", embedding: make([]float64, domain.EmbeddingDimension), wantErr: true},
+		{name: "above default max magnitude", embedding: scaled(domain.DefaultMaxEmbeddingMagnitude + 1), wantErr: true},
+		{name: "norm overflows", embedding: withValue(math.MaxFloat64), wantErr: true},
+		{name: "above configured max magnitude", embedding: scaled(30), maxMagnitude: 25, wantErr: true},
+		{name: "unnormalized within configured max", embedding: scaled(20), maxMagnitude: 25},
+		{name: "unit vector", embedding: unitEmbedding()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenantID := uuid.New()
+			faceRepo := &MockFaceRepository{}
+			faceProvider := &MockFaceProvider{}
+			faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+				Embedding:    tt.embedding,
+				QualityScore: 0.95,
+				FaceCount:    1,
+			}, nil)
+			if !tt.wantErr {
+				faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(nil, domain.ErrFaceNotFound)
+				faceRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+			}
+
+			svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{}).
+				WithMaxEmbeddingMagnitude(tt.maxMagnitude)
+
+			face, err := svc.Register(context.Background(), tenantID, "user_001", make([]byte, 5000), nil, domain.DefaultTenantSettings())
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domain.ErrInvalidEmbedding)
+				assert.Nil(t, face)
+				faceRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				faceRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.embedding, face.Embedding)
+			faceRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFaceService_Register_ProviderFaceID(t *testing.T) {
+	analysis := &provider.FaceAnalysis{QualityScore: 0.95, FaceCount: 1}
+
+	t.Run("indexes and stores the provider face id when the provider exposes no embedding", func(t *testing.T) {
+		tenantID := uuid.New()
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+		faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(analysis, nil)
+		faceProvider.On("IndexFace", mock.Anything, mock.Anything).Return("aws-face-1", []float64(nil), nil)
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(nil, domain.ErrFaceNotFound)
+		faceRepo.On("Create", mock.Anything, mock.MatchedBy(func(f *domain.Face) bool {
+			return f.ProviderFaceID == "aws-face-1" && f.Embedding == nil
+		})).Return(nil)
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+		face, err := svc.Register(context.Background(), tenantID, "user_001", make([]byte, 5000), nil, domain.DefaultTenantSettings())
+
+		require.NoError(t, err)
+		assert.Equal(t, "aws-face-1", face.ProviderFaceID)
+		faceRepo.AssertExpectations(t)
+		faceProvider.AssertExpectations(t)
+	})
+
+	t.Run("re-registration replaces the provider face", func(t *testing.T) {
+		tenantID := uuid.New()
+		existing := &domain.Face{ID: uuid.New(), TenantID: tenantID, ExternalID: "user_001", ProviderFaceID: "aws-face-old"}
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+		faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(analysis, nil)
+		faceProvider.On("IndexFace", mock.Anything, mock.Anything).Return("aws-face-new", []float64(nil), nil)
+		faceProvider.On("DeleteFace", mock.Anything, "aws-face-old").Return(nil)
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(existing, nil)
+		faceRepo.On("Update", mock.Anything, mock.MatchedBy(func(f *domain.Face) bool {
+			return f.ProviderFaceID == "aws-face-new"
+		})).Return(nil)
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+		_, err := svc.Register(context.Background(), tenantID, "user_001", make([]byte, 5000), nil, domain.DefaultTenantSettings())
+
+		require.NoError(t, err)
+		faceRepo.AssertExpectations(t)
+		faceProvider.AssertExpectations(t)
+	})
+
+	t.Run("failed create removes the indexed provider face", func(t *testing.T) {
+		tenantID := uuid.New()
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+		faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(analysis, nil)
+		faceProvider.On("IndexFace", mock.Anything, mock.Anything).Return("aws-face-1", []float64(nil), nil)
+		faceProvider.On("DeleteFace", mock.Anything, "aws-face-1").Return(nil)
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(nil, domain.ErrFaceNotFound)
+		faceRepo.On("Create", mock.Anything, mock.Anything).Return(errors.New("connection reset"))
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+		_, err := svc.Register(context.Background(), tenantID, "user_001", make([]byte, 5000), nil, domain.DefaultTenantSettings())
+
+		require.Error(t, err)
+		faceProvider.AssertExpectations(t)
+	})
+
+	t.Run("providers with embeddings are not indexed", func(t *testing.T) {
+		tenantID := uuid.New()
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+		faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+			Embedding:    unitEmbedding(),
+			QualityScore: 0.95,
+			FaceCount:    1,
+		}, nil)
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(nil, domain.ErrFaceNotFound)
+		faceRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+		face, err := svc.Register(context.Background(), tenantID, "user_001", make([]byte, 5000), nil, domain.DefaultTenantSettings())
+
+		require.NoError(t, err)
+		assert.Empty(t, face.ProviderFaceID)
+		faceProvider.AssertNotCalled(t, "IndexFace", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFaceService_Register_EmbeddingHash(t *testing.T) {
+	register := func(t *testing.T, analysis *provider.FaceAnalysis, providerFaceID string) *domain.Face {
+		t.Helper()
+		tenantID := uuid.New()
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+		faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(analysis, nil)
+		faceProvider.On("IndexFace", mock.Anything, mock.Anything).Return(providerFaceID, []float64(nil), nil)
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(nil, domain.ErrFaceNotFound)
+		faceRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+		face, err := svc.Register(context.Background(), tenantID, "user_001", make([]byte, 5000), nil, domain.DefaultTenantSettings())
+		require.NoError(t, err)
+		return face
+	}
+
+	t.Run("identical embeddings produce identical hashes", func(t *testing.T) {
+		analysis := &provider.FaceAnalysis{Embedding: unitEmbedding(), QualityScore: 0.95, FaceCount: 1}
+
+		first := register(t, analysis, "")
+		second := register(t, analysis, "")
+
+		assert.Len(t, first.EmbeddingHash, 64)
+		assert.Equal(t, first.EmbeddingHash, second.EmbeddingHash)
+		assert.Equal(t, domain.EmbeddingHash(first.Embedding, first.Fingerprint()), first.EmbeddingHash)
+	})
+
+	t.Run("a changed embedding changes the hash", func(t *testing.T) {
+		changed := unitEmbedding()
+		changed[0], changed[1] = changed[1], changed[0]+0.01
+
+		first := register(t, &provider.FaceAnalysis{Embedding: unitEmbedding(), QualityScore: 0.95, FaceCount: 1}, "")
+		second := register(t, &provider.FaceAnalysis{Embedding: changed, QualityScore: 0.95, FaceCount: 1}, "")
+
+		assert.NotEqual(t, first.EmbeddingHash, second.EmbeddingHash)
+	})
+
+	t.Run("providers without embeddings hash the provider face id", func(t *testing.T) {
+		face := register(t, &provider.FaceAnalysis{QualityScore: 0.95, FaceCount: 1}, "aws-face-1")
+
+		assert.Equal(t, domain.ProviderFaceIDHash("aws-face-1"), face.EmbeddingHash)
+	})
+
+	t.Run("re-registration stores the new hash", func(t *testing.T) {
+		tenantID := uuid.New()
+		existing := &domain.Face{ID: uuid.New(), TenantID: tenantID, ExternalID: "user_001", EmbeddingHash: "stale"}
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+		faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+			Embedding:    unitEmbedding(),
+			QualityScore: 0.95,
+			FaceCount:    1,
+		}, nil)
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(existing, nil)
+		faceRepo.On("Update", mock.Anything, mock.MatchedBy(func(f *domain.Face) bool {
+			return f.EmbeddingHash == domain.EmbeddingHash(f.Embedding, f.Fingerprint())
+		})).Return(nil)
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+		_, err := svc.Register(context.Background(), tenantID, "user_001", make([]byte, 5000), nil, domain.DefaultTenantSettings())
+
+		require.NoError(t, err)
+		faceRepo.AssertExpectations(t)
+	})
+}
+
+func TestFaceService_CountFaces(t *testing.T) {
+	tests := []struct {
+		name     string
+		detected []provider.DetectedFace
+		want     int
+	}{
+		{name: "no faces", detected: []provider.DetectedFace{}, want: 0},
+		{name: "one face", detected: []provider.DetectedFace{{Confidence: 0.99}}, want: 1},
+		{name: "many faces", detected: []provider.DetectedFace{{Confidence: 0.99}, {Confidence: 0.97}, {Confidence: 0.91}}, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			faceRepo := &MockFaceRepository{}
+			verificationRepo := &MockVerificationRepository{}
+			searchAuditRepo := &MockSearchAuditRepository{}
+			faceProvider := &MockFaceProvider{}
+			faceProvider.On("DetectFaces", mock.Anything, mock.Anything).Return(tt.detected, nil)
+
+			svc := NewFaceService(faceRepo, verificationRepo, searchAuditRepo, faceProvider, &MockRateLimiter{})
+
+			count, err := svc.CountFaces(context.Background(), uuid.New(), make([]byte, 5000), domain.DefaultTenantSettings())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, count)
+			// Counting never indexes, stores or audits anything
+			faceProvider.AssertNotCalled(t, "IndexFace", mock.Anything, mock.Anything)
+			faceRepo.AssertExpectations(t)
+			verificationRepo.AssertExpectations(t)
+			searchAuditRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("tenant outside the service region", func(t *testing.T) {
+		faceProvider := &MockFaceProvider{}
+		svc := NewFaceService(&MockFaceRepository{}, &MockVerificationRepository{}, nil, faceProvider, nil)
+		settings := domain.DefaultTenantSettings()
+		settings.DataRegion = "eu-west-1"
+
+		_, err := svc.CountFaces(context.Background(), uuid.New(), make([]byte, 5000), settings)
+
+		assert.ErrorIs(t, err, domain.ErrDataResidencyViolation)
+		faceProvider.AssertNotCalled(t, "DetectFaces", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFaceService_RecentlyVerified(t *testing.T) {
+	tenantID := uuid.New()
+	tenMinutesAgo := time.Now().Add(-10 * time.Minute)
+	twoHoursAgo := time.Now().Add(-2 * time.Hour)
+
+	tests := []struct {
+		name         string
+		within       time.Duration
+		lastVerified *time.Time
+		want         bool
+	}{
+		{name: "verified within the window", within: 15 * time.Minute, lastVerified: &tenMinutesAgo, want: true},
+		{name: "verified outside the window", within: 15 * time.Minute, lastVerified: &twoHoursAgo, want: false},
+		{name: "wider window includes older verification", within: 3 * time.Hour, lastVerified: &twoHoursAgo, want: true},
+		{name: "never verified", within: 15 * time.Minute, lastVerified: nil, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verificationRepo := &MockVerificationRepository{}
+			verificationRepo.On("LastVerifiedAt", mock.Anything, tenantID, "user_001", false).Return(tt.lastVerified, nil)
+
+			svc := NewFaceService(&MockFaceRepository{}, verificationRepo, &MockSearchAuditRepository{}, &MockFaceProvider{}, &MockRateLimiter{})
+
+			result, err := svc.RecentlyVerified(context.Background(), tenantID, "user_001", tt.within, domain.DefaultTenantSettings())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.RecentlyVerified)
+			assert.Equal(t, tt.lastVerified, result.LastVerifiedAt)
+			assert.Equal(t, "user_001", result.ExternalID)
+			assert.Equal(t, int64(tt.within/time.Second), result.WithinSeconds)
+		})
+	}
+
+	invalid := []struct {
+		name   string
+		maxAge time.Duration
+		within time.Duration
+	}{
+		{name: "zero window", maxAge: time.Hour, within: 0},
+		{name: "negative window", maxAge: time.Hour, within: -time.Minute},
+		{name: "window beyond max age", maxAge: time.Hour, within: 2 * time.Hour},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			verificationRepo := &MockVerificationRepository{}
+			svc := NewFaceService(&MockFaceRepository{}, verificationRepo, &MockSearchAuditRepository{}, &MockFaceProvider{}, &MockRateLimiter{}).
+				WithMaxVerificationAge(tt.maxAge)
+
+			result, err := svc.RecentlyVerified(context.Background(), tenantID, "user_001", tt.within, domain.DefaultTenantSettings())
+
+			var appErr *domain.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, domain.ErrValidationFailed.Code, appErr.Code)
+			assert.Nil(t, result)
+			verificationRepo.AssertNotCalled(t, "LastVerifiedAt", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestFaceService_RecentlyVerified_LivenessPolicy(t *testing.T) {
+	tenantID := uuid.New()
+	fiveMinutesAgo := time.Now().Add(-5 * time.Minute)
+
+	tests := []struct {
+		name            string
+		policy          domain.VerifyPolicy
+		requireLiveness bool
+	}{
+		{name: "match_only accepts any verified decision", policy: domain.VerifyPolicyMatchOnly, requireLiveness: false},
+		{name: "match_or_liveness accepts any verified decision", policy: domain.VerifyPolicyMatchOrLiveness, requireLiveness: false},
+		{name: "match_and_liveness only accepts live decisions", policy: domain.VerifyPolicyMatchAndLiveness, requireLiveness: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := domain.DefaultTenantSettings()
+			settings.VerifyPolicy = tt.policy
+
+			// The latest verified decision skipped liveness: it is only found when liveness is not required
+			verificationRepo := &MockVerificationRepository{}
+			verificationRepo.On("LastVerifiedAt", mock.Anything, tenantID, "user_001", false).Return(&fiveMinutesAgo, nil).Maybe()
+			verificationRepo.On("LastVerifiedAt", mock.Anything, tenantID, "user_001", true).Return(nil, nil).Maybe()
+
+			svc := NewFaceService(&MockFaceRepository{}, verificationRepo, &MockSearchAuditRepository{}, &MockFaceProvider{}, &MockRateLimiter{})
+
+			result, err := svc.RecentlyVerified(context.Background(), tenantID, "user_001", 15*time.Minute, settings)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.requireLiveness, result.LivenessRequired)
+			assert.Equal(t, !tt.requireLiveness, result.RecentlyVerified, "a non-live decision never satisfies a liveness-required policy")
+			verificationRepo.AssertCalled(t, "LastVerifiedAt", mock.Anything, tenantID, "user_001", tt.requireLiveness)
+		})
+	}
+}
+
+func TestFaceService_ExistsBatch(t *testing.T) {
+	tenantID := uuid.New()
+
+	tooMany := make([]string, domain.MaxExistsBatchSize+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("user_%03d", i)
+	}
+
+	tests := []struct {
+		name        string
+		externalIDs []string
+		setupMocks  func(*MockFaceRepository)
+		wantErr     error
+	}{
+		{
+			name:        "deduplicates and trims ids",
+			externalIDs: []string{"user_001", " user_001 ", "user_002", ""},
+			setupMocks: func(fr *MockFaceRepository) {
+				fr.On("ExistsBatch", mock.Anything, tenantID, []string{"user_001", "user_002"}).Return(map[string]domain.RegistrationCheck{
+					"user_001": {Registered: true},
+					"user_002": {Registered: false},
+				}, nil)
+			},
+		},
+		{
+			name:        "empty list is rejected",
+			externalIDs: []string{" "},
+			setupMocks:  func(fr *MockFaceRepository) {},
+			wantErr:     domain.ErrValidationFailed,
+		},
+		{
+			name:        "list over cap is rejected",
+			externalIDs: tooMany,
+			setupMocks:  func(fr *MockFaceRepository) {},
+			wantErr:     domain.ErrValidationFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			faceRepo := &MockFaceRepository{}
+			tt.setupMocks(faceRepo)
+
+			svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, &MockFaceProvider{}, &MockRateLimiter{})
+
+			results, err := svc.ExistsBatch(context.Background(), tenantID, tt.externalIDs)
+
+			if tt.wantErr != nil {
+				var appErr *domain.AppError
+				require.ErrorAs(t, err, &appErr)
+				assert.Equal(t, tt.wantErr.(*domain.AppError).Code, appErr.Code)
+				faceRepo.AssertNotCalled(t, "ExistsBatch", mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				require.NoError(t, err)
+				assert.True(t, results["user_001"].Registered)
+				assert.False(t, results["user_002"].Registered)
+			}
+
+			faceRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFaceService_BulkUpdateMetadata(t *testing.T) {
+	tenantID := uuid.New()
+	patch := map[string]interface{}{"event": "rock-in-rio"}
+
+	tooMany := make([]string, domain.MaxBulkMetadataBatchSize+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("user_%03d", i)
+	}
+
+	t.Run("counts updated and not found", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceRepo.On("BulkUpdateMetadata", mock.Anything, tenantID, []string{"user_001", "user_002", "user_003"}, patch, domain.DefaultMaxMetadataBytes).
+			Return([]string{"user_002", "user_001"}, nil)
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, &MockFaceProvider{}, &MockRateLimiter{})
+
+		result, err := svc.BulkUpdateMetadata(context.Background(), tenantID, []string{"user_001", " user_002 ", "user_001", "user_003"}, patch)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Updated)
+		assert.Equal(t, 1, result.NotFound)
+		assert.Equal(t, []string{"user_003"}, result.NotFoundIDs)
+		faceRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects a patch whose merge exceeds the size cap", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceRepo.On("BulkUpdateMetadata", mock.Anything, tenantID, []string{"user_001"}, patch, 32).
+			Return(nil, domain.ErrMetadataTooLarge)
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, &MockFaceProvider{}, &MockRateLimiter{}).
+			WithMaxMetadataBytes(32)
+
+		_, err := svc.BulkUpdateMetadata(context.Background(), tenantID, []string{"user_001"}, patch)
+
+		require.ErrorIs(t, err, domain.ErrMetadataTooLarge)
+		faceRepo.AssertExpectations(t)
+	})
+
+	invalid := []struct {
+		name        string
+		externalIDs []string
+		patch       map[string]interface{}
+		wantErr     *domain.AppError
+	}{
+		{"empty list", []string{" "}, patch, domain.ErrValidationFailed},
+		{"list over cap", tooMany, patch, domain.ErrValidationFailed},
+		{"empty patch", []string{"user_001"}, nil, domain.ErrValidationFailed},
+		{"patch over size cap", []string{"user_001"}, map[string]interface{}{"notes": strings.Repeat("x", 64)}, domain.ErrMetadataTooLarge},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			faceRepo := &MockFaceRepository{}
+			svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, &MockFaceProvider{}, &MockRateLimiter{}).
+				WithMaxMetadataBytes(32)
+
+			_, err := svc.BulkUpdateMetadata(context.Background(), tenantID, tt.externalIDs, tt.patch)
+
+			var appErr *domain.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, tt.wantErr.Code, appErr.Code)
+			faceRepo.AssertNotCalled(t, "BulkUpdateMetadata", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestFaceService_Delete(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		tenantID   uuid.UUID
+		externalID string
+		setupMocks func(*MockFaceRepository, *MockVerificationRepository, *MockFaceProvider)
+		wantErr    error
+	}{
+		{
+			name:       "successful deletion",
+			tenantID:   uuid.New(),
+			externalID: "user_001",
+			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider) {
+				fr.On("GetByExternalID", mock.Anything, mock.Anything, "user_001").Return(&domain.Face{
+					ID: uuid.New(),
+				}, nil)
+				fr.On("Delete", mock.Anything, mock.Anything, "user_001").Return(nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name:       "deletion removes the provider face",
+			tenantID:   uuid.New(),
+			externalID: "user_002",
+			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider) {
+				fr.On("GetByExternalID", mock.Anything, mock.Anything, "user_002").Return(&domain.Face{
+					ID:             uuid.New(),
+					ProviderFaceID: "aws-face-1",
+				}, nil)
+				fr.On("Delete", mock.Anything, mock.Anything, "user_002").Return(nil)
+				fp.On("DeleteFace", mock.Anything, "aws-face-1").Return(errors.New("collection not found"))
+			},
+			wantErr: nil,
+		},
+		{
+			name:       "face not found",
+			tenantID:   uuid.New(),
+			externalID: "user_999",
+			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider) {
+				fr.On("GetByExternalID", mock.Anything, mock.Anything, "user_999").Return(nil, domain.ErrFaceNotFound)
+			},
+			wantErr: domain.ErrFaceNotFound,
+		},
+		{
+			name:       "test key cannot delete a live face",
+			ctx:        domain.WithTestMode(context.Background()),
+			tenantID:   uuid.New(),
+			externalID: "user_003",
+			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider) {
+				fr.On("GetByExternalID", mock.Anything, mock.Anything, "user_003").Return(&domain.Face{
+					ID:             uuid.New(),
+					ProviderFaceID: "aws-face-3",
+					IsTest:         false,
+				}, nil)
+			},
+			wantErr: domain.ErrFaceNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			faceRepo := &MockFaceRepository{}
+			verificationRepo := &MockVerificationRepository{}
+			searchAuditRepo := &MockSearchAuditRepository{}
+			faceProvider := &MockFaceProvider{}
+
+			tt.setupMocks(faceRepo, verificationRepo, faceProvider)
+
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			svc := &FaceService{
+				faceRepo:         faceRepo,
+				verificationRepo: verificationRepo,
+				searchAuditRepo:  searchAuditRepo,
+				provider:         faceProvider,
+			}
+
+			err := svc.Delete(ctx, tt.tenantID, tt.externalID)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			faceRepo.AssertExpectations(t)
+			faceProvider.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFaceService_Search(t *testing.T) {
+	tests := []struct {
+		name           string
+		tenant         *domain.Tenant
+		imageBytes     []byte
+		threshold      float64
+		maxResults     int
+		clientIP       string
+		setupMocks     func(*MockFaceRepository, *MockVerificationRepository, *MockFaceProvider, *MockSearchAuditRepository)
+		expectedError  error
+		expectedCount  int
+		validateResult func(*testing.T, *domain.SearchResult)
+	}{
+		{
+			name: "successful search with matches",
+			tenant: &domain.Tenant{
+				ID:   uuid.New(),
+				Name: "Test Tenant",
+				Settings: map[string]interface{}{
+					"search_enabled": true,
+				},
+			},
+			imageBytes: []byte("fake-image-data"),
+			threshold:  0.85,
+			maxResults: 10,
+			clientIP:   "192.168.1.1",
+			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider, ar *MockSearchAuditRepository) {
+				fp.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+					Embedding:     []float64{0.1, 0.2, 0.3},
+					Confidence:    0.99,
+					QualityScore:  0.95,
+					LivenessScore: 0.90,
+					FaceCount:     1,
+				}, nil)
+
+				faceID1 := uuid.New()
+				faceID2 := uuid.New()
+				fr.On("SearchByEmbedding", mock.Anything, mock.Anything, mock.Anything, mock.Anything, 0.85, 10).Return([]domain.SearchMatch{
+					{FaceID: faceID1, ExternalID: "user1", Similarity: 0.95, Metadata: map[string]interface{}{"name": "User One"}},
+					{FaceID: faceID2, ExternalID: "user2", Similarity: 0.88, Metadata: map[string]interface{}{"name": "User Two"}},
+				}, nil)
+
+				ar.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+			},
+			expectedCount: 2,
+			validateResult: func(t *testing.T, result *domain.SearchResult) {
+				assert.Equal(t, 2, len(result.Matches))
+				assert.Equal(t, 0, result.TotalFaces) // TotalFaces removed from hot path
+				assert.GreaterOrEqual(t, result.LatencyMs, int64(0))
+				assert.NotEqual(t, uuid.Nil, result.SearchID)
+				assert.Equal(t, "user1", result.Matches[0].ExternalID)
+				assert.Equal(t, 0.95, result.Matches[0].Similarity)
+			},
+		},
+		{
+			name: "successful search with no matches",
+			tenant: &domain.Tenant{
+				ID: uuid.New(),
+				Settings: map[string]interface{}{
+					"search_enabled": true,
+				},
+			},
+			imageBytes: []byte("fake-image-data"),
+			threshold:  0.95,
+			maxResults: 10,
+			clientIP:   "192.168.1.2",
+			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider, ar *MockSearchAuditRepository) {
+				fp.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+					Embedding:     []float64{0.5, 0.6, 0.7},
+					Confidence:    0.99,
+					QualityScore:  0.95,
+					LivenessScore: 0.90,
+					FaceCount:     1,
+				}, nil)
+				fr.On("SearchByEmbedding", mock.Anything, mock.Anything, mock.Anything, mock.Anything, 0.95, 10).Return([]domain.SearchMatch{}, nil)
+				ar.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+			},
+			expectedCount: 0,
+			validateResult: func(t *testing.T, result *domain.SearchResult) {
+				assert.Equal(t, 0, len(result.Matches))
+				assert.Equal(t, 0, result.TotalFaces) // Removed from hot path
+			},
+		},
+		{
+			name: "search not enabled",
+			tenant: &domain.Tenant{
+				ID: uuid.New(),
+				Settings: map[string]interface{}{
+					"search_enabled": false,
+				},
+			},
+			imageBytes: []byte("fake-image-data"),
+			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider, ar *MockSearchAuditRepository) {
+			},
+			expectedError: domain.ErrSearchNotEnabled,
+		},
+		{
+			name: "invalid threshold - too high",
+			tenant: &domain.Tenant{
+				ID: uuid.New(),
+				Settings: map[string]interface{}{
+					"search_enabled": true,
+				},
+			},
+			imageBytes: []byte("fake-image-data"),
+			threshold:  1.5,
+			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider, ar *MockSearchAuditRepository) {
+			},
+			expectedError: domain.ErrInvalidThreshold,
+		},
+		{
+			name: "invalid threshold - negative default from tenant",
+			tenant: &domain.Tenant{
+				ID: uuid.New(),
+				Settings: map[string]interface{}{
+					"search_enabled":   true,
+					"search_threshold": float64(-0.1),
+				},
+			},
+			imageBytes: []byte("fake-image-data"),
+			threshold:  0,
+			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider, ar *MockSearchAuditRepository) {
+			},
+			expectedError: domain.ErrInvalidThreshold,
+		},
+		{
+			name: "invalid max results - too high",
+			tenant: &domain.Tenant{
+				ID: uuid.New(),
+				Settings: map[string]interface{}{
+					"search_enabled": true,
+				},
+			},
+			imageBytes: []byte("fake-image-data"),
+			threshold:  0.8,
+			maxResults: 100,
+			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider, ar *MockSearchAuditRepository) {
+			},
+			expectedError: domain.ErrInvalidMaxResults,
+		},
+		{
+			name: "invalid max results - zero with invalid default",
+			tenant: &domain.Tenant{
+				ID: uuid.New(),
+				Settings: map[string]interface{}{
+					"search_enabled":     true,
+					"search_max_results": float64(0),
+					"search_threshold":   0.8,
+				},
+			},
+			imageBytes: []byte("fake-image-data"),
+			threshold:  0.8,
+			maxResults: 0,
+			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider, ar *MockSearchAuditRepository) {
+			},
+			expectedError: domain.ErrInvalidMaxResults,
+		},
+		{
+			name: "use tenant default settings",
+			tenant: &domain.Tenant{
+				ID: uuid.New(),
+				Settings: map[string]interface{}{
+					"search_enabled":     true,
+					"search_threshold":   float64(0.9),
+					"search_max_results": float64(5),
+				},
+			},
+			imageBytes: []byte("fake-image-data"),
+			threshold:  0,
+			maxResults: 0,
+			clientIP:   "192.168.1.3",
+			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider, ar *MockSearchAuditRepository) {
+				fp.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+					Embedding:     []float64{0.2, 0.3, 0.4},
+					Confidence:    0.99,
+					QualityScore:  0.95,
+					LivenessScore: 0.90,
+					FaceCount:     1,
+				}, nil)
+				fr.On("SearchByEmbedding", mock.Anything, mock.Anything, mock.Anything, mock.Anything, 0.9, 5).Return([]domain.SearchMatch{
+					{FaceID: uuid.New(), ExternalID: "user3", Similarity: 0.92},
+				}, nil)
+				ar.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+			},
+			expectedCount: 1,
+			validateResult: func(t *testing.T, result *domain.SearchResult) {
+				assert.Equal(t, 1, len(result.Matches))
+				assert.Equal(t, 0, result.TotalFaces) // Removed from hot path
+			},
+		},
+		{
+			name: "liveness check required and passed - SecurityMaximum",
+			tenant: &domain.Tenant{
+				ID: uuid.New(),
+				Settings: map[string]interface{}{
+					"search_enabled":     true,
+					"security_level":     "maximum",
+					"liveness_threshold": float64(0.9),
+				},
+			},
+			imageBytes: []byte("fake-image-data"),
+			threshold:  0.8,
+			maxResults: 10,
+			clientIP:   "192.168.1.4",
+			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider, ar *MockSearchAuditRepository) {
+				fp.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+					Embedding:     []float64{0.3, 0.4, 0.5},
+					Confidence:    0.99,
+					QualityScore:  0.95,
+					LivenessScore: 0.95,
+					FaceCount:     1,
+				}, nil)
+				fr.On("SearchByEmbedding", mock.Anything, mock.Anything, mock.Anything, mock.Anything, 0.8, 10).Return([]domain.SearchMatch{
+					{FaceID: uuid.New(), ExternalID: "user4", Similarity: 0.85},
+				}, nil)
+				ar.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+			},
+			expectedCount: 1,
+		},
+		{
+			name: "liveness check required and failed - SecurityMaximum",
+			tenant: &domain.Tenant{
+				ID: uuid.New(),
+				Settings: map[string]interface{}{
+					"search_enabled":     true,
+					"security_level":     "maximum",
+					"liveness_threshold": float64(0.9),
+				},
+			},
+			imageBytes: []byte("fake-image-data"),
+			threshold:  0.8,
+			maxResults: 10,
+			clientIP:   "192.168.1.5",
+			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider, ar *MockSearchAuditRepository) {
+				fp.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+					Embedding:     []float64{0.3, 0.4, 0.5},
+					Confidence:    0.99,
+					QualityScore:  0.95,
+					LivenessScore: 0.4,
+					FaceCount:     1,
+				}, nil)
+			},
+			expectedError: domain.ErrLivenessFailed,
+		},
+		{
+			name: "search_liveness feature flag fails liveness - SecurityStandard",
+			tenant: &domain.Tenant{
+				ID: uuid.New(),
+				Settings: map[string]interface{}{
+					"liveness_threshold": float64(0.9),
+					"feature_flags": map[string]interface{}{
+						"search":          true,
+						"search_liveness": true,
+					},
+				},
+			},
+			imageBytes: []byte("fake-image-data"),
+			threshold:  0.8,
+			maxResults: 10,
+			clientIP:   "192.168.1.6",
+			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider, ar *MockSearchAuditRepository) {
+				fp.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+					Embedding:     []float64{0.3, 0.4, 0.5},
+					Confidence:    0.99,
+					QualityScore:  0.95,
+					LivenessScore: 0.6,
+					FaceCount:     1,
+				}, nil)
+			},
+			expectedError: domain.ErrLivenessFailed,
+		},
+		{
+			name: "provider fails to analyze face",
+			tenant: &domain.Tenant{
+				ID: uuid.New(),
+				Settings: map[string]interface{}{
+					"search_enabled": true,
+				},
+			},
+			imageBytes: []byte("invalid-image"),
+			threshold:  0.8,
+			maxResults: 10,
+			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider, ar *MockSearchAuditRepository) {
+				fp.On("AnalyzeFace", mock.Anything, mock.Anything).Return(nil, domain.ErrInvalidImage)
+			},
+			expectedError: domain.ErrInvalidImage,
+		},
+		{
+			name: "repository search fails",
+			tenant: &domain.Tenant{
+				ID: uuid.New(),
+				Settings: map[string]interface{}{
+					"search_enabled": true,
+				},
+			},
+			imageBytes: []byte("fake-image-data"),
+			threshold:  0.8,
+			maxResults: 10,
+			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider, ar *MockSearchAuditRepository) {
+				fp.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+					Embedding:     []float64{0.1, 0.2},
+					Confidence:    0.99,
+					QualityScore:  0.95,
+					LivenessScore: 0.90,
+					FaceCount:     1,
+				}, nil)
+				fr.On("SearchByEmbedding", mock.Anything, mock.Anything, mock.Anything, mock.Anything, 0.8, 10).Return(nil, errors.New("database connection failed"))
+			},
+			expectedError: errors.New("database connection failed"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			faceRepo := &MockFaceRepository{}
+			verificationRepo := &MockVerificationRepository{}
+			searchAuditRepo := &MockSearchAuditRepository{}
+			faceProvider := &MockFaceProvider{}
+			rateLimiter := &MockRateLimiter{}
+
+			tt.setupMocks(faceRepo, verificationRepo, faceProvider, searchAuditRepo)
+
+			// Mock rate limiter to always allow
+			rateLimiter.On("CheckSearchLimit", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+			svc := &FaceService{
+				faceRepo:         faceRepo,
+				verificationRepo: verificationRepo,
+				searchAuditRepo:  searchAuditRepo,
+				provider:         faceProvider,
+				rateLimiter:      rateLimiter,
+			}
+
+			result, err := svc.Search(context.Background(), tt.tenant, tt.imageBytes, tt.threshold, tt.maxResults, tt.clientIP)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				if errors.Is(tt.expectedError, domain.ErrSearchNotEnabled) ||
+					errors.Is(tt.expectedError, domain.ErrInvalidThreshold) ||
+					errors.Is(tt.expectedError, domain.ErrInvalidMaxResults) ||
+					errors.Is(tt.expectedError, domain.ErrLivenessFailed) ||
+					errors.Is(tt.expectedError, domain.ErrInvalidImage) {
+					assert.ErrorIs(t, err, tt.expectedError)
+				} else {
+					assert.Contains(t, err.Error(), "search faces")
+				}
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, result)
+				assert.Equal(t, tt.expectedCount, len(result.Matches))
+
+				if tt.validateResult != nil {
+					tt.validateResult(t, result)
+				}
+			}
+
+			faceRepo.AssertExpectations(t)
+			faceProvider.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFaceService_Search_WithCustomThreshold(t *testing.T) {
+	tenantID := uuid.New()
+
+	tests := []struct {
+		name              string
+		requestThreshold  float64
+		tenantThreshold   float64
+		expectedThreshold float64
+		expectedSource    domain.ThresholdSource
+	}{
+		{
+			name:              "use request threshold when provided",
+			requestThreshold:  0.9,
+			tenantThreshold:   0.8,
+			expectedThreshold: 0.9,
+			expectedSource:    domain.ThresholdSourceRequest,
+		},
+		{
+			name:              "use tenant default when request is zero",
+			requestThreshold:  0,
+			tenantThreshold:   0.85,
+			expectedThreshold: 0.85,
+			expectedSource:    domain.ThresholdSourceTenant,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant := &domain.Tenant{
+				ID: tenantID,
+				Settings: map[string]interface{}{
+					"search_enabled":    true,
+					"search_threshold":  tt.tenantThreshold,
+					"search_rate_limit": float64(30),
+				},
+			}
+
+			faceRepo := &MockFaceRepository{}
+			faceProvider := &MockFaceProvider{}
+			searchAuditRepo := &MockSearchAuditRepository{}
+			rateLimiter := &MockRateLimiter{}
+
+			rateLimiter.On("CheckSearchLimit", mock.Anything, tenantID, 30).Return(nil)
+			faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+				Embedding:     []float64{0.1, 0.2},
+				Confidence:    0.99,
+				QualityScore:  0.95,
+				LivenessScore: 0.90,
+				FaceCount:     1,
+			}, nil)
+			faceRepo.On("SearchByEmbedding", mock.Anything, tenantID, mock.Anything, mock.Anything, tt.expectedThreshold, mock.Anything).Return([]domain.SearchMatch{}, nil)
+			searchAuditRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			svc := &FaceService{
+				faceRepo:        faceRepo,
+				searchAuditRepo: searchAuditRepo,
+				provider:        faceProvider,
+				rateLimiter:     rateLimiter,
+			}
+
+			result, err := svc.Search(context.Background(), tenant, []byte("image"), tt.requestThreshold, 10, "127.0.0.1")
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedThreshold, result.ThresholdApplied)
+			assert.Equal(t, tt.expectedSource, result.ThresholdSource)
+			faceRepo.AssertExpectations(t)
+			faceProvider.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFaceService_Search_SegmentThresholds(t *testing.T) {
+	tenantID := uuid.New()
+	tenant := &domain.Tenant{
+		ID: tenantID,
+		Settings: map[string]interface{}{
+			"search_enabled":    true,
+			"search_threshold":  0.85,
+			"search_rate_limit": float64(30),
+			"segment_thresholds": map[string]interface{}{
+				"key":    "ticket_type",
+				"search": map[string]interface{}{"staff": 0.95, "guest": 0.7},
+			},
+		},
+	}
+
+	faceRepo := &MockFaceRepository{}
+	faceProvider := &MockFaceProvider{}
+	searchAuditRepo := &MockSearchAuditRepository{}
+	rateLimiter := &MockRateLimiter{}
+
+	rateLimiter.On("CheckSearchLimit", mock.Anything, tenantID, 30).Return(nil)
+	faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+		Embedding:     []float64{0.1, 0.2},
+		Confidence:    0.99,
+		QualityScore:  0.95,
+		LivenessScore: 0.90,
+		FaceCount:     1,
+	}, nil)
+	// Segments are filtered in the query, which keeps the requested limit
+	segments := domain.SegmentThresholdSettings{Key: "ticket_type", Search: map[string]float64{"staff": 0.95, "guest": 0.7}}
+	faceRepo.On("SearchByEmbeddingSegmented", mock.Anything, tenantID, mock.Anything, mock.Anything, 0.85, segments, 10).Return([]domain.SearchMatch{
+		{FaceID: uuid.New(), ExternalID: "general_001", Similarity: 0.9, Metadata: map[string]interface{}{"ticket_type": "general"}},
+		{FaceID: uuid.New(), ExternalID: "guest_001", Similarity: 0.75, Metadata: map[string]interface{}{"ticket_type": "guest"}},
+	}, nil)
+	searchAuditRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	svc := &FaceService{
+		faceRepo:        faceRepo,
+		searchAuditRepo: searchAuditRepo,
+		provider:        faceProvider,
+		rateLimiter:     rateLimiter,
+	}
+
+	result, err := svc.Search(context.Background(), tenant, []byte("image"), 0, 10, "127.0.0.1")
+
+	require.NoError(t, err)
+	require.Len(t, result.Matches, 2)
+	assert.Equal(t, "general_001", result.Matches[0].ExternalID)
+	assert.Equal(t, "guest_001", result.Matches[1].ExternalID)
+	assert.Equal(t, 0.85, result.ThresholdApplied)
+	faceRepo.AssertExpectations(t)
+}
+
+func TestFaceService_Search_IdentifyFloor(t *testing.T) {
+	tenantID := uuid.New()
+
+	tests := []struct {
+		name           string
+		identifyFloor  interface{}
+		topSimilarity  float64
+		wantIdentified bool
+	}{
+		{name: "no floor identifies any match above the threshold", identifyFloor: nil, topSimilarity: 0.86, wantIdentified: true},
+		{name: "candidate between threshold and floor is not identified", identifyFloor: 0.95, topSimilarity: 0.88, wantIdentified: false},
+		{name: "match at the floor is identified", identifyFloor: 0.95, topSimilarity: 0.95, wantIdentified: true},
+		{name: "out of range floor is ignored", identifyFloor: 1.5, topSimilarity: 0.88, wantIdentified: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := map[string]interface{}{
+				"search_enabled":    true,
+				"search_threshold":  0.85,
+				"search_rate_limit": float64(30),
+			}
+			if tt.identifyFloor != nil {
+				settings["search_identify_floor"] = tt.identifyFloor
+			}
+			tenant := &domain.Tenant{ID: tenantID, Settings: settings}
+
+			faceRepo := &MockFaceRepository{}
+			faceProvider := &MockFaceProvider{}
+			searchAuditRepo := &MockSearchAuditRepository{}
+			rateLimiter := &MockRateLimiter{}
+
+			rateLimiter.On("CheckSearchLimit", mock.Anything, tenantID, 30).Return(nil)
+			faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+				Embedding:    []float64{0.1, 0.2},
+				QualityScore: 0.95,
+				FaceCount:    1,
+			}, nil)
+			faceRepo.On("SearchByEmbedding", mock.Anything, tenantID, mock.Anything, mock.Anything, 0.85, mock.Anything).Return([]domain.SearchMatch{
+				{FaceID: uuid.New(), ExternalID: "user_001", Similarity: tt.topSimilarity},
+			}, nil)
+			searchAuditRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			svc := NewFaceService(faceRepo, &MockVerificationRepository{}, searchAuditRepo, faceProvider, rateLimiter)
+
+			result, err := svc.Search(context.Background(), tenant, []byte("image"), 0, 1, "127.0.0.1")
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantIdentified, result.Identified)
+			require.Len(t, result.Matches, 1, "weak matches are still returned as candidates")
+			assert.Equal(t, "user_001", result.Matches[0].ExternalID)
+		})
+	}
+}
+
+func TestFaceService_Search_Margin(t *testing.T) {
+	tenantID := uuid.New()
+
+	tests := []struct {
+		name             string
+		margin           interface{}
+		unidentified     bool
+		secondSimilarity float64
+		wantAmbiguous    bool
+		wantIdentified   bool
+	}{
+		{name: "no margin is never ambiguous", margin: nil, secondSimilarity: 0.91, wantAmbiguous: false, wantIdentified: true},
+		{name: "runner-up within the margin is ambiguous", margin: 0.03, secondSimilarity: 0.91, wantAmbiguous: true, wantIdentified: true},
+		{name: "clear winner is confident", margin: 0.03, secondSimilarity: 0.86, wantAmbiguous: false, wantIdentified: true},
+		{name: "ambiguous result is not identified when configured", margin: 0.03, unidentified: true, secondSimilarity: 0.91, wantAmbiguous: true, wantIdentified: false},
+		{name: "confident result is still identified when configured", margin: 0.03, unidentified: true, secondSimilarity: 0.86, wantAmbiguous: false, wantIdentified: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := map[string]interface{}{
+				"search_enabled":                true,
+				"search_threshold":              0.85,
+				"search_rate_limit":             float64(30),
+				"search_ambiguous_unidentified": tt.unidentified,
+			}
+			if tt.margin != nil {
+				settings["search_margin"] = tt.margin
+			}
+			tenant := &domain.Tenant{ID: tenantID, Settings: settings}
+
+			faceRepo := &MockFaceRepository{}
+			faceProvider := &MockFaceProvider{}
+			searchAuditRepo := &MockSearchAuditRepository{}
+			rateLimiter := &MockRateLimiter{}
+
+			rateLimiter.On("CheckSearchLimit", mock.Anything, tenantID, 30).Return(nil)
+			faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+				Embedding:    []float64{0.1, 0.2},
+				QualityScore: 0.95,
+				FaceCount:    1,
+			}, nil)
+			faceRepo.On("SearchByEmbedding", mock.Anything, tenantID, mock.Anything, mock.Anything, 0.85, 10).Return([]domain.SearchMatch{
+				{FaceID: uuid.New(), ExternalID: "user_001", Similarity: 0.93},
+				{FaceID: uuid.New(), ExternalID: "user_002", Similarity: tt.secondSimilarity},
+			}, nil)
+			searchAuditRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			svc := NewFaceService(faceRepo, &MockVerificationRepository{}, searchAuditRepo, faceProvider, rateLimiter)
+
+			result, err := svc.Search(context.Background(), tenant, []byte("image"), 0, 10, "127.0.0.1")
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAmbiguous, result.Ambiguous)
+			assert.Equal(t, tt.wantIdentified, result.Identified)
+			require.Len(t, result.Matches, 2, "both candidates are returned for review")
+		})
+	}
+
+	t.Run("single result still compares the runner-up", func(t *testing.T) {
+		tenant := &domain.Tenant{ID: tenantID, Settings: map[string]interface{}{
+			"search_enabled":    true,
+			"search_threshold":  0.85,
+			"search_rate_limit": float64(30),
+			"search_margin":     0.03,
+		}}
+
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+		searchAuditRepo := &MockSearchAuditRepository{}
+		rateLimiter := &MockRateLimiter{}
+
+		rateLimiter.On("CheckSearchLimit", mock.Anything, tenantID, 30).Return(nil)
+		faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+			Embedding:    []float64{0.1, 0.2},
+			QualityScore: 0.95,
+			FaceCount:    1,
+		}, nil)
+		faceRepo.On("SearchByEmbedding", mock.Anything, tenantID, mock.Anything, mock.Anything, 0.85, 2).Return([]domain.SearchMatch{
+			{FaceID: uuid.New(), ExternalID: "user_001", Similarity: 0.93},
+			{FaceID: uuid.New(), ExternalID: "user_002", Similarity: 0.92},
+		}, nil)
+		searchAuditRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, searchAuditRepo, faceProvider, rateLimiter)
+
+		result, err := svc.Search(context.Background(), tenant, []byte("image"), 0, 1, "127.0.0.1")
+
+		require.NoError(t, err)
+		assert.True(t, result.Ambiguous)
+		require.Len(t, result.Matches, 1, "the runner-up is only used for the margin")
+		assert.Equal(t, "user_001", result.Matches[0].ExternalID)
+		faceRepo.AssertExpectations(t)
+	})
+}
+
+func TestFaceService_Search_ClampMaxResults(t *testing.T) {
+	tenantID := uuid.New()
+
+	tests := []struct {
+		name           string
+		clamp          bool
+		maxResults     int
+		wantMaxResults int
+		wantWarning    bool
+		wantErr        error
+	}{
+		{name: "error mode rejects out of range", clamp: false, maxResults: 80, wantErr: domain.ErrInvalidMaxResults},
+		{name: "clamp mode lowers to the ceiling", clamp: true, maxResults: 80, wantMaxResults: domain.MaxSearchResults, wantWarning: true},
+		{name: "clamp mode leaves in range values alone", clamp: true, maxResults: 20, wantMaxResults: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant := &domain.Tenant{ID: tenantID, Settings: map[string]interface{}{
+				"search_enabled":    true,
+				"search_threshold":  0.85,
+				"search_rate_limit": float64(30),
+				"clamp_max_results": tt.clamp,
+			}}
+
+			faceRepo := &MockFaceRepository{}
+			faceProvider := &MockFaceProvider{}
+			searchAuditRepo := &MockSearchAuditRepository{}
+			rateLimiter := &MockRateLimiter{}
+
+			rateLimiter.On("CheckSearchLimit", mock.Anything, tenantID, 30).Return(nil).Maybe()
+			faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+				Embedding:    []float64{0.1, 0.2},
+				QualityScore: 0.95,
+				FaceCount:    1,
+			}, nil).Maybe()
+			faceRepo.On("SearchByEmbedding", mock.Anything, tenantID, mock.Anything, mock.Anything, 0.85, tt.wantMaxResults).Return([]domain.SearchMatch{}, nil).Maybe()
+			searchAuditRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			svc := NewFaceService(faceRepo, &MockVerificationRepository{}, searchAuditRepo, faceProvider, rateLimiter)
+
+			result, err := svc.Search(context.Background(), tenant, []byte("image"), 0, tt.maxResults, "127.0.0.1")
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				faceRepo.AssertNotCalled(t, "SearchByEmbedding", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			faceRepo.AssertExpectations(t)
+			if tt.wantWarning {
+				assert.Contains(t, result.Warning, "clamped")
+			} else {
+				assert.Empty(t, result.Warning)
+			}
+		})
+	}
+}
+
+func TestFaceService_Search_EmptyCollection(t *testing.T) {
+	tenantID := uuid.New()
+	tenant := &domain.Tenant{ID: tenantID, Settings: map[string]interface{}{
+		"search_enabled":    true,
+		"search_threshold":  0.85,
+		"search_rate_limit": float64(30),
+	}}
+
+	t.Run("zero faces skips the provider", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+		searchAuditRepo := &MockSearchAuditRepository{}
+		rateLimiter := &MockRateLimiter{}
+
+		rateLimiter.On("CheckSearchLimit", mock.Anything, tenantID, 30).Return(nil)
+		faceRepo.On("CountByTenant", mock.Anything, tenantID).Return(0, nil).Twice()
+		searchAuditRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, searchAuditRepo, faceProvider, rateLimiter).
+			WithFaceCountCache(time.Minute)
+
+		for i := 0; i < 2; i++ {
+			result, err := svc.Search(context.Background(), tenant, []byte("image"), 0, 10, "127.0.0.1")
+			require.NoError(t, err)
+			assert.Empty(t, result.Matches)
+			assert.Equal(t, domain.SearchReasonEmptyCollection, result.Reason)
+		}
+
+		// A zero count is never cached, each search counts again
+		faceRepo.AssertExpectations(t)
+		faceProvider.AssertNotCalled(t, "AnalyzeFace", mock.Anything, mock.Anything)
+	})
+
+	t.Run("non-empty collection searches normally", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+		searchAuditRepo := &MockSearchAuditRepository{}
+		rateLimiter := &MockRateLimiter{}
+
+		rateLimiter.On("CheckSearchLimit", mock.Anything, tenantID, 30).Return(nil)
+		faceRepo.On("CountByTenant", mock.Anything, tenantID).Return(3, nil).Once()
+		faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+			Embedding:    []float64{0.1, 0.2},
+			QualityScore: 0.95,
+			FaceCount:    1,
+		}, nil)
+		faceRepo.On("SearchByEmbedding", mock.Anything, tenantID, mock.Anything, mock.Anything, 0.85, 10).Return([]domain.SearchMatch{
+			{FaceID: uuid.New(), ExternalID: "user_001", Similarity: 0.9},
+		}, nil)
+		searchAuditRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, searchAuditRepo, faceProvider, rateLimiter).
+			WithFaceCountCache(time.Minute)
+
+		for i := 0; i < 2; i++ {
+			result, err := svc.Search(context.Background(), tenant, []byte("image"), 0, 10, "127.0.0.1")
+			require.NoError(t, err)
+			require.Len(t, result.Matches, 1)
+			assert.Empty(t, result.Reason)
+		}
+		// The second search was answered from the cached count
+		faceRepo.AssertExpectations(t)
+		faceProvider.AssertExpectations(t)
+	})
+
+	t.Run("a count read before an invalidation is not cached", func(t *testing.T) {
+		svc := NewFaceService(&MockFaceRepository{}, &MockVerificationRepository{}, nil, &MockFaceProvider{}, nil).
+			WithFaceCountCache(time.Minute)
+
+		generation := svc.faceCounts.generation(tenantID)
+		svc.forgetFaceCount(tenantID)
+		svc.faceCounts.put(tenantID, 3, generation, time.Now())
+
+		_, cached := svc.faceCounts.get(tenantID, time.Now())
+		assert.False(t, cached, "a stale count must not be written back")
+	})
+
+	t.Run("registering a face makes it searchable", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+		searchAuditRepo := &MockSearchAuditRepository{}
+		rateLimiter := &MockRateLimiter{}
+
+		rateLimiter.On("CheckSearchLimit", mock.Anything, tenantID, 30).Return(nil)
+		faceRepo.On("CountByTenant", mock.Anything, tenantID).Return(0, nil).Once()
+		faceRepo.On("CountByTenant", mock.Anything, tenantID).Return(1, nil).Once()
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(nil, domain.ErrFaceNotFound)
+		faceRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		faceRepo.On("SearchByEmbedding", mock.Anything, tenantID, mock.Anything, mock.Anything, 0.85, 10).Return([]domain.SearchMatch{}, nil)
+		faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+			Embedding:    []float64{0.1, 0.2},
+			QualityScore: 0.95,
+			FaceCount:    1,
+		}, nil)
+		searchAuditRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, searchAuditRepo, faceProvider, rateLimiter).
+			WithFaceCountCache(time.Minute)
+
+		result, err := svc.Search(context.Background(), tenant, []byte("image"), 0, 10, "127.0.0.1")
+		require.NoError(t, err)
+		assert.Equal(t, domain.SearchReasonEmptyCollection, result.Reason)
+
+		_, err = svc.Register(context.Background(), tenantID, "user_001", []byte("image"), nil, domain.DefaultTenantSettings())
+		require.NoError(t, err)
+
+		result, err = svc.Search(context.Background(), tenant, []byte("image"), 0, 10, "127.0.0.1")
+		require.NoError(t, err)
+		assert.Empty(t, result.Reason)
+		faceRepo.AssertExpectations(t)
+	})
+}
+
+type fakeCollectionCounter struct {
+	count int64
+	err   error
+}
+
+func (f *fakeCollectionCounter) GetCollectionFaceCount(ctx context.Context, tenantID string) (int64, error) {
+	return f.count, f.err
+}
+
+func TestFaceService_RecountFaces(t *testing.T) {
+	tenantID := uuid.New()
+	tenant := &domain.Tenant{ID: tenantID, Settings: map[string]interface{}{
+		"search_enabled":    true,
+		"search_rate_limit": float64(30),
+	}}
+
+	t.Run("refreshes a drifted cached count", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		searchAuditRepo := &MockSearchAuditRepository{}
+		rateLimiter := &MockRateLimiter{}
+		faceProvider := &MockFaceProvider{}
+
+		rateLimiter.On("CheckSearchLimit", mock.Anything, tenantID, 30).Return(nil)
+		// Cached as 2, while faces were added through another instance
+		faceRepo.On("CountByTenant", mock.Anything, tenantID).Return(2, nil).Once()
+		faceRepo.On("CountByTenant", mock.Anything, tenantID).Return(4, nil).Once()
+		faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+			Embedding:    []float64{0.1, 0.2},
+			QualityScore: 0.95,
+			FaceCount:    1,
+		}, nil)
+		faceRepo.On("SearchByEmbedding", mock.Anything, tenantID, mock.Anything, mock.Anything, 0.85, 10).Return([]domain.SearchMatch{}, nil)
+		searchAuditRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, searchAuditRepo, faceProvider, rateLimiter).
+			WithFaceCountCache(time.Hour)
+
+		result, err := svc.Search(context.Background(), tenant, []byte("image"), 0, 10, "127.0.0.1")
+		require.NoError(t, err)
+		require.Empty(t, result.Reason)
+
+		recount, err := svc.RecountFaces(context.Background(), tenantID)
+		require.NoError(t, err)
+		assert.Equal(t, 4, recount.DatabaseCount)
+		require.NotNil(t, recount.PreviousCachedCount)
+		assert.Equal(t, 2, *recount.PreviousCachedCount)
+		assert.True(t, recount.CacheRefreshed)
+		assert.Nil(t, recount.ProviderCount, "no provider collection to compare")
+		assert.Nil(t, recount.Delta)
+
+		// The refreshed count is used without counting again
+		result, err = svc.Search(context.Background(), tenant, []byte("image"), 0, 10, "127.0.0.1")
+		require.NoError(t, err)
+		assert.Empty(t, result.Reason)
+		faceRepo.AssertExpectations(t)
+		faceProvider.AssertExpectations(t)
+	})
+
+	t.Run("reports the provider delta", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceRepo.On("CountByTenant", mock.Anything, tenantID).Return(10, nil)
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, &MockFaceProvider{}, &MockRateLimiter{}).
+			WithCollectionCounter(&fakeCollectionCounter{count: 7})
+
+		recount, err := svc.RecountFaces(context.Background(), tenantID)
+		require.NoError(t, err)
+		assert.Equal(t, 10, recount.DatabaseCount)
+		require.NotNil(t, recount.ProviderCount)
+		assert.Equal(t, int64(7), *recount.ProviderCount)
+		require.NotNil(t, recount.Delta)
+		assert.Equal(t, int64(-3), *recount.Delta)
+		assert.False(t, recount.CacheRefreshed, "cache disabled")
+	})
+
+	t.Run("provider error", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceRepo.On("CountByTenant", mock.Anything, tenantID).Return(10, nil)
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, &MockFaceProvider{}, &MockRateLimiter{}).
+			WithCollectionCounter(&fakeCollectionCounter{err: errors.New("collection not found")})
+
+		_, err := svc.RecountFaces(context.Background(), tenantID)
+		assert.Error(t, err)
+	})
+}
+
+func TestFaceService_SearchByEmbedding(t *testing.T) {
+	tenantID := uuid.New()
+	enabledSettings := map[string]interface{}{
+		"search_enabled":              true,
+		"search_by_embedding_enabled": true,
+		"search_rate_limit":           float64(30),
+	}
+
+	t.Run("searches without calling the provider", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+		searchAuditRepo := &MockSearchAuditRepository{}
+		rateLimiter := &MockRateLimiter{}
+
+		embedding := unitEmbedding()
+		matchID := uuid.New()
+		rateLimiter.On("CheckSearchLimit", mock.Anything, tenantID, 30).Return(nil)
+		faceRepo.On("SearchByEmbedding", mock.Anything, tenantID, embedding, mock.Anything, 0.9, 5).Return([]domain.SearchMatch{
+			{FaceID: matchID, ExternalID: "user_001", Similarity: 0.95},
+		}, nil)
+		searchAuditRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, searchAuditRepo, faceProvider, rateLimiter)
+		tenant := &domain.Tenant{ID: tenantID, Settings: enabledSettings}
+
+		result, err := svc.SearchByEmbedding(context.Background(), tenant, embedding, 0.9, 5, "127.0.0.1")
+
+		require.NoError(t, err)
+		require.Len(t, result.Matches, 1)
+		assert.Equal(t, "user_001", result.Matches[0].ExternalID)
+		assert.NotEqual(t, uuid.Nil, result.SearchID)
+		faceRepo.AssertExpectations(t)
+		faceProvider.AssertNotCalled(t, "AnalyzeFace", mock.Anything, mock.Anything)
+	})
+
+	tests := []struct {
+		name      string
+		settings  map[string]interface{}
+		embedding []float64
+		wantErr   error
+	}{
+		{
+			name:      "embedding too short",
+			settings:  enabledSettings,
+			embedding: make([]float64, 128),
+			wantErr:   domain.ErrInvalidEmbedding,
+		},
+		{
+			name:      "embedding too long",
+			settings:  enabledSettings,
+			embedding: make([]float64, domain.EmbeddingDimension+1),
+			wantErr:   domain.ErrInvalidEmbedding,
+		},
+		{
+			name:      "embedding missing",
+			settings:  enabledSettings,
+			embedding: nil,
+			wantErr:   domain.ErrInvalidEmbedding,
+		},
+		{
+			name:      "embedding with non-finite value",
+			settings:  enabledSettings,
+			embedding: append(make([]float64, domain.EmbeddingDimension-1), math.Inf(1)),
+			wantErr:   domain.ErrInvalidEmbedding,
+		},
+		{
+			name:      "embedding with NaN value",
+			settings:  enabledSettings,
+			embedding: append(unitEmbedding()[:domain.EmbeddingDimension-1], math.NaN()),
+			wantErr:   domain.ErrInvalidEmbedding,
+		},
+		{
+			name:      "zero embedding",
+			settings:  enabledSettings,
+			embedding: make([]float64, domain.EmbeddingDimension),
+			wantErr:   domain.ErrInvalidEmbedding,
+		},
+		{
+			name:      "embedding magnitude too large",
+			settings:  enabledSettings,
+			embedding: append(unitEmbedding()[:domain.EmbeddingDimension-1], 2*domain.DefaultMaxEmbeddingMagnitude),
+			wantErr:   domain.ErrInvalidEmbedding,
+		},
+		{
+			name:      "tenant has not opted in",
+			settings:  map[string]interface{}{"search_enabled": true},
+			embedding: unitEmbedding(),
+			wantErr:   domain.ErrSearchByEmbeddingNotEnabled,
+		},
+		{
+			name:      "search disabled",
+			settings:  map[string]interface{}{"search_by_embedding_enabled": true},
+			embedding: unitEmbedding(),
+			wantErr:   domain.ErrSearchNotEnabled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			faceRepo := &MockFaceRepository{}
+			rateLimiter := &MockRateLimiter{}
+
+			svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, &MockFaceProvider{}, rateLimiter)
+			tenant := &domain.Tenant{ID: tenantID, Settings: tt.settings}
+
+			result, err := svc.SearchByEmbedding(context.Background(), tenant, tt.embedding, 0, 0, "127.0.0.1")
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, result)
+			rateLimiter.AssertNotCalled(t, "CheckSearchLimit", mock.Anything, mock.Anything, mock.Anything)
+			faceRepo.AssertNotCalled(t, "SearchByEmbedding", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestL2Normalize(t *testing.T) {
+	magnitude := func(v []float64) float64 {
+		var sum float64
+		for _, x := range v {
+			sum += x * x
+		}
+		return math.Sqrt(sum)
+	}
+	cosine := func(a, b []float64) float64 {
+		var dot float64
+		for i := range a {
+			dot += a[i] * b[i]
+		}
+		return dot / (magnitude(a) * magnitude(b))
+	}
+
+	t.Run("normalized vectors have unit magnitude", func(t *testing.T) {
+		for _, v := range [][]float64{
+			{3, 4},
+			{0.001, -0.002, 0.003},
+			{120, -45, 8, 0, 61},
+		} {
+			got := l2Normalize(v)
+			assert.InDelta(t, 1.0, magnitude(got), 1e-9, "vector %v", v)
+		}
+	})
+
+	t.Run("does not modify the input", func(t *testing.T) {
+		v := []float64{3, 4}
+		got := l2Normalize(v)
+		assert.Equal(t, []float64{3, 4}, v)
+		assert.InDeltaSlice(t, []float64{0.6, 0.8}, got, 1e-9)
+	})
+
+	t.Run("zero vector is returned unchanged", func(t *testing.T) {
+		v := make([]float64, 4)
+		assert.Equal(t, v, l2Normalize(v))
+	})
+
+	t.Run("cosine distances are preserved", func(t *testing.T) {
+		a := []float64{2, 7, -1, 4}
+		b := []float64{0.5, 1.5, -0.1, 1.2}
+		c := []float64{-3, 1, 9, 0}
+
+		na, nb, nc := l2Normalize(a), l2Normalize(b), l2Normalize(c)
+
+		assert.InDelta(t, cosine(a, b), cosine(na, nb), 1e-9)
+		assert.InDelta(t, cosine(a, c), cosine(na, nc), 1e-9)
+		// A scaled copy normalizes to the same vector
+		assert.InDeltaSlice(t, na, l2Normalize([]float64{20, 70, -10, 40}), 1e-9)
+	})
+}
+
+func TestFaceService_EmbeddingNormalization(t *testing.T) {
+	raw := make([]float64, domain.EmbeddingDimension)
+	raw[0], raw[1] = 3, 4
+	normalized := make([]float64, domain.EmbeddingDimension)
+	normalized[0], normalized[1] = 0.6, 0.8
+
+	tests := []struct {
+		name    string
+		enabled bool
+		want    []float64
+	}{
+		{name: "disabled keeps provider embeddings", enabled: false, want: raw},
+		{name: "enabled normalizes embeddings", enabled: true, want: normalized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" on register", func(t *testing.T) {
+			faceRepo := &MockFaceRepository{}
+			faceProvider := &MockFaceProvider{}
+
+			svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{}).
+				WithEmbeddingNormalization(tt.enabled)
+
+			faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+				Embedding:    raw,
+				QualityScore: 0.95,
+				FaceCount:    1,
+			}, nil)
+			faceRepo.On("GetByExternalID", mock.Anything, mock.Anything, "user_001").Return(nil, domain.ErrFaceNotFound)
+			faceRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+			face, err := svc.Register(context.Background(), uuid.New(), "user_001", make([]byte, 5000), nil, domain.DefaultTenantSettings())
+
+			require.NoError(t, err)
+			assert.InDeltaSlice(t, tt.want, face.Embedding, 1e-9)
+		})
+
+		t.Run(tt.name+" on search by embedding", func(t *testing.T) {
+			faceRepo := &MockFaceRepository{}
+			rateLimiter := &MockRateLimiter{}
+			searchAuditRepo := &MockSearchAuditRepository{}
+
+			svc := NewFaceService(faceRepo, &MockVerificationRepository{}, searchAuditRepo, &MockFaceProvider{}, rateLimiter).
+				WithEmbeddingNormalization(tt.enabled)
+			tenant := &domain.Tenant{ID: uuid.New(), Settings: map[string]interface{}{
+				"search_enabled":              true,
+				"search_by_embedding_enabled": true,
+			}}
+
+			var searched []float64
+			rateLimiter.On("CheckSearchLimit", mock.Anything, tenant.ID, mock.Anything).Return(nil)
+			faceRepo.On("SearchByEmbedding", mock.Anything, tenant.ID, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) { searched = args.Get(2).([]float64) }).
+				Return([]domain.SearchMatch{}, nil)
+			searchAuditRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			_, err := svc.SearchByEmbedding(context.Background(), tenant, raw, 0.8, 10, "127.0.0.1")
+
+			require.NoError(t, err)
+			assert.InDeltaSlice(t, tt.want, searched, 1e-9)
+		})
+	}
+}
+
+func TestFaceService_NoFaceReasons(t *testing.T) {
+	tenantID := uuid.New()
+	noFace := &provider.NoFaceError{
+		Reasons: []domain.NoFaceReason{domain.NoFaceReasonTooDark, domain.NoFaceReasonPose},
+		Err:     errors.New("no face detected in image: LOW_BRIGHTNESS, EXTREME_POSE"),
+	}
+
+	assertReasons := func(t *testing.T, err error) {
+		t.Helper()
+		var appErr *domain.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, domain.ErrNoFaceDetected.Code, appErr.Code)
+		assert.Equal(t, domain.ErrNoFaceDetected.StatusCode, appErr.StatusCode)
+		assert.Equal(t, []string{"too_dark", "face_not_frontal"}, appErr.Reasons)
+		assert.ErrorIs(t, err, noFace)
+	}
+
+	t.Run("register surfaces provider reasons", func(t *testing.T) {
+		faceProvider := &MockFaceProvider{}
+		faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(nil, noFace)
+
+		svc := NewFaceService(&MockFaceRepository{}, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+
+		_, err := svc.Register(context.Background(), tenantID, "user_001", make([]byte, 5000), nil, domain.DefaultTenantSettings())
+
+		assertReasons(t, err)
+	})
+
+	t.Run("verify surfaces provider reasons", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(&domain.Face{
+			ID:        uuid.New(),
+			Embedding: make([]float64, 512),
+		}, nil)
+		faceProvider.On("DetectFaces", mock.Anything, mock.Anything).Return([]provider.DetectedFace{{Confidence: 0.99}}, nil)
+		faceProvider.On("IndexFace", mock.Anything, mock.Anything).Return("", []float64(nil), noFace)
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+
+		_, err := svc.Verify(context.Background(), tenantID, "user_001", make([]byte, 5000), domain.DefaultTenantSettings())
+
+		assertReasons(t, err)
+	})
+
+	t.Run("other provider errors are not reported as no face", func(t *testing.T) {
+		faceProvider := &MockFaceProvider{}
+		faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(nil, errors.New("connection refused"))
+
+		svc := NewFaceService(&MockFaceRepository{}, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+
+		_, err := svc.Register(context.Background(), tenantID, "user_001", make([]byte, 5000), nil, domain.DefaultTenantSettings())
+
+		require.Error(t, err)
+		var appErr *domain.AppError
+		assert.False(t, errors.As(err, &appErr))
+	})
+}
+
+func TestFaceService_DataResidency(t *testing.T) {
+	tenantID := uuid.New()
+	pinned := domain.DefaultTenantSettings()
+	pinned.DataRegion = "eu-west-1"
+
+	t.Run("register in the wrong region is blocked before the provider", func(t *testing.T) {
+		faceProvider := &MockFaceProvider{}
+		svc := NewFaceService(&MockFaceRepository{}, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{}).
+			WithDataRegion("us-east-1")
+
+		_, err := svc.Register(context.Background(), tenantID, "user_001", make([]byte, 5000), nil, pinned)
+
+		assert.ErrorIs(t, err, domain.ErrDataResidencyViolation)
+		faceProvider.AssertNotCalled(t, "AnalyzeFace", mock.Anything, mock.Anything)
+	})
+
+	t.Run("verify in the wrong region is blocked before any lookup", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{}).
+			WithDataRegion("us-east-1")
+
+		_, err := svc.Verify(context.Background(), tenantID, "user_001", make([]byte, 5000), pinned)
+
+		assert.ErrorIs(t, err, domain.ErrDataResidencyViolation)
+		faceRepo.AssertNotCalled(t, "GetByExternalID", mock.Anything, mock.Anything, mock.Anything)
+		faceProvider.AssertNotCalled(t, "DetectFaces", mock.Anything, mock.Anything)
+	})
+
+	t.Run("search in the wrong region is blocked before rate limiting", func(t *testing.T) {
+		rateLimiter := &MockRateLimiter{}
+		faceProvider := &MockFaceProvider{}
+		svc := NewFaceService(&MockFaceRepository{}, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, rateLimiter).
+			WithDataRegion("us-east-1")
+		tenant := &domain.Tenant{
+			ID:       tenantID,
+			Settings: map[string]interface{}{"search_enabled": true, "data_region": "eu-west-1"},
+		}
+
+		_, err := svc.Search(context.Background(), tenant, make([]byte, 5000), 0, 0, "127.0.0.1")
+
+		assert.ErrorIs(t, err, domain.ErrDataResidencyViolation)
+		rateLimiter.AssertNotCalled(t, "CheckSearchLimit", mock.Anything, mock.Anything, mock.Anything)
+		faceProvider.AssertNotCalled(t, "AnalyzeFace", mock.Anything, mock.Anything)
+	})
+
+	t.Run("liveness in the wrong region is blocked before the provider", func(t *testing.T) {
+		faceProvider := &MockFaceProvider{}
+		svc := NewFaceService(&MockFaceRepository{}, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{}).
+			WithDataRegion("us-east-1")
+
+		_, err := svc.CheckLiveness(context.Background(), tenantID, make([]byte, 5000), pinned)
+
+		assert.ErrorIs(t, err, domain.ErrDataResidencyViolation)
+		faceProvider.AssertNotCalled(t, "CheckLiveness", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("compare in the wrong region is blocked before any lookup", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{}).
+			WithDataRegion("us-east-1")
+
+		_, err := svc.Compare(context.Background(), tenantID, "user_001", "user_002", pinned)
+
+		assert.ErrorIs(t, err, domain.ErrDataResidencyViolation)
+		faceRepo.AssertNotCalled(t, "GetByExternalID", mock.Anything, mock.Anything, mock.Anything)
+		faceProvider.AssertNotCalled(t, "CompareFaces", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("pinned tenant is refused when the service region is unknown", func(t *testing.T) {
+		svc := NewFaceService(&MockFaceRepository{}, &MockVerificationRepository{}, &MockSearchAuditRepository{}, &MockFaceProvider{}, &MockRateLimiter{})
+
+		_, err := svc.Register(context.Background(), tenantID, "user_001", make([]byte, 5000), nil, pinned)
+
+		assert.ErrorIs(t, err, domain.ErrDataResidencyViolation)
+	})
+
+	t.Run("matching region is allowed", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+		faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+			Embedding:    unitEmbedding(),
+			QualityScore: 0.9,
+			FaceCount:    1,
+		}, nil)
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(nil, domain.ErrFaceNotFound)
+		faceRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{}).
+			WithDataRegion("EU-West-1")
+
+		_, err := svc.Register(context.Background(), tenantID, "user_001", make([]byte, 5000), nil, pinned)
+
+		require.NoError(t, err)
+		faceRepo.AssertExpectations(t)
+	})
+}
+
+func TestFaceService_SecurityLevelSettings(t *testing.T) {
+	tenantID := uuid.New()
+	embedding := make([]float64, 512)
+
+	t.Run("register rejects images below min quality", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+		faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+			Embedding:    embedding,
+			QualityScore: 0.4,
+			FaceCount:    1,
+		}, nil)
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+
+		settings := domain.DefaultTenantSettings()
+		settings.MinQuality = 0.6
+
+		_, err := svc.Register(context.Background(), tenantID, "user_001", make([]byte, 5000), nil, settings)
+
+		assert.ErrorIs(t, err, domain.ErrLowQualityImage)
+		faceRepo.AssertNotCalled(t, "GetByExternalID", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("verify rejects images below min quality", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(&domain.Face{ID: uuid.New(), Embedding: embedding}, nil)
+		faceProvider := &MockFaceProvider{}
+		faceProvider.On("DetectFaces", mock.Anything, mock.Anything).Return([]provider.DetectedFace{{Confidence: 0.99, QualityScore: 0.3}}, nil)
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+
+		settings := domain.DefaultTenantSettings()
+		settings.MinQuality = 0.5
+
+		_, err := svc.Verify(context.Background(), tenantID, "user_001", make([]byte, 5000), settings)
+
+		assert.ErrorIs(t, err, domain.ErrLowQualityImage)
+		faceProvider.AssertNotCalled(t, "IndexFace", mock.Anything, mock.Anything)
+	})
+
+	t.Run("verify uses the resolved threshold for the security level", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(&domain.Face{ID: uuid.New(), Embedding: embedding}, nil)
+		faceProvider := &MockFaceProvider{}
+		faceProvider.On("DetectFaces", mock.Anything, mock.Anything).Return([]provider.DetectedFace{{Confidence: 0.99, QualityScore: 0.9}}, nil)
+		faceProvider.On("IndexFace", mock.Anything, mock.Anything).Return("", embedding, nil)
+		faceProvider.On("CompareFaces", mock.Anything, mock.Anything, mock.Anything).Return(0.9, nil)
+		verificationRepo := &MockVerificationRepository{}
+		verificationRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		svc := NewFaceService(faceRepo, verificationRepo, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+
+		tenant := &domain.Tenant{ID: tenantID, Settings: map[string]interface{}{
+			"security_level": "maximum",
+			"security_levels": map[string]interface{}{
+				"maximum": map[string]interface{}{"verify_threshold": 0.95},
 			},
-			expectedCount: 1,
-			validateResult: func(t *testing.T, result *domain.SearchResult) {
-				assert.Equal(t, 1, len(result.Matches))
-				assert.Equal(t, 0, result.TotalFaces) // Removed from hot path
+		}}
+
+		result, err := svc.Verify(context.Background(), tenantID, "user_001", make([]byte, 5000), tenant.GetSettings())
+
+		require.NoError(t, err)
+		assert.False(t, result.Verified, "0.9 similarity is below the maximum level threshold")
+	})
+}
+
+func TestFaceService_Compare(t *testing.T) {
+	tenantID := uuid.New()
+	embeddingA := []float64{0.1, 0.2, 0.3}
+	embeddingB := []float64{0.3, 0.2, 0.1}
+
+	tests := []struct {
+		name       string
+		a, b       string
+		setupMocks func(*MockFaceRepository, *MockFaceProvider)
+		wantMatch  bool
+		wantSim    float64
+		wantErr    error
+		wantCode   string
+	}{
+		{
+			name: "high similarity pair is a match",
+			a:    "user_a",
+			b:    "user_b",
+			setupMocks: func(fr *MockFaceRepository, fp *MockFaceProvider) {
+				fr.On("GetByExternalID", mock.Anything, tenantID, "user_a").Return(&domain.Face{ExternalID: "user_a", Embedding: embeddingA}, nil)
+				fr.On("GetByExternalID", mock.Anything, tenantID, "user_b").Return(&domain.Face{ExternalID: "user_b", Embedding: embeddingB}, nil)
+				fp.On("CompareFaces", mock.Anything, embeddingA, embeddingB).Return(0.93, nil)
 			},
+			wantMatch: true,
+			wantSim:   0.93,
 		},
 		{
-			name: "liveness check required and passed - SecurityMaximum",
-			tenant: &domain.Tenant{
-				ID: uuid.New(),
-				Settings: map[string]interface{}{
-					"search_enabled":     true,
-					"security_level":     "maximum",
-					"liveness_threshold": float64(0.9),
-				},
-			},
-			imageBytes: []byte("fake-image-data"),
-			threshold:  0.8,
-			maxResults: 10,
-			clientIP:   "192.168.1.4",
-			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider, ar *MockSearchAuditRepository) {
-				fp.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
-					Embedding:     []float64{0.3, 0.4, 0.5},
-					Confidence:    0.99,
-					QualityScore:  0.95,
-					LivenessScore: 0.95,
-					FaceCount:     1,
-				}, nil)
-				fr.On("SearchByEmbedding", mock.Anything, mock.Anything, mock.Anything, 0.8, 10).Return([]domain.SearchMatch{
-					{FaceID: uuid.New(), ExternalID: "user4", Similarity: 0.85},
-				}, nil)
-				ar.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+			name: "low similarity pair is not a match",
+			a:    "user_a",
+			b:    "user_b",
+			setupMocks: func(fr *MockFaceRepository, fp *MockFaceProvider) {
+				fr.On("GetByExternalID", mock.Anything, tenantID, "user_a").Return(&domain.Face{ExternalID: "user_a", Embedding: embeddingA}, nil)
+				fr.On("GetByExternalID", mock.Anything, tenantID, "user_b").Return(&domain.Face{ExternalID: "user_b", Embedding: embeddingB}, nil)
+				fp.On("CompareFaces", mock.Anything, embeddingA, embeddingB).Return(0.21, nil)
 			},
-			expectedCount: 1,
+			wantMatch: false,
+			wantSim:   0.21,
 		},
 		{
-			name: "liveness check required and failed - SecurityMaximum",
-			tenant: &domain.Tenant{
-				ID: uuid.New(),
-				Settings: map[string]interface{}{
-					"search_enabled":     true,
-					"security_level":     "maximum",
-					"liveness_threshold": float64(0.9),
-				},
-			},
-			imageBytes: []byte("fake-image-data"),
-			threshold:  0.8,
-			maxResults: 10,
-			clientIP:   "192.168.1.5",
-			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider, ar *MockSearchAuditRepository) {
-				fp.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
-					Embedding:     []float64{0.3, 0.4, 0.5},
-					Confidence:    0.99,
-					QualityScore:  0.95,
-					LivenessScore: 0.4,
-					FaceCount:     1,
-				}, nil)
+			name: "missing embedding",
+			a:    "user_a",
+			b:    "user_b",
+			setupMocks: func(fr *MockFaceRepository, fp *MockFaceProvider) {
+				fr.On("GetByExternalID", mock.Anything, tenantID, "user_a").Return(&domain.Face{ExternalID: "user_a", Embedding: embeddingA}, nil)
+				fr.On("GetByExternalID", mock.Anything, tenantID, "user_b").Return(&domain.Face{ExternalID: "user_b"}, nil)
 			},
-			expectedError: domain.ErrLivenessFailed,
+			wantErr: domain.ErrEmbeddingUnavailable,
 		},
 		{
-			name: "provider fails to analyze face",
-			tenant: &domain.Tenant{
-				ID: uuid.New(),
-				Settings: map[string]interface{}{
-					"search_enabled": true,
-				},
-			},
-			imageBytes: []byte("invalid-image"),
-			threshold:  0.8,
-			maxResults: 10,
-			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider, ar *MockSearchAuditRepository) {
-				fp.On("AnalyzeFace", mock.Anything, mock.Anything).Return(nil, domain.ErrInvalidImage)
+			name: "different embedding models",
+			a:    "user_a",
+			b:    "user_b",
+			setupMocks: func(fr *MockFaceRepository, fp *MockFaceProvider) {
+				fr.On("GetByExternalID", mock.Anything, tenantID, "user_a").Return(&domain.Face{Embedding: embeddingA, EmbeddingModel: "deepface/Facenet512", EmbeddingVersion: "3d"}, nil)
+				fr.On("GetByExternalID", mock.Anything, tenantID, "user_b").Return(&domain.Face{Embedding: embeddingB, EmbeddingModel: "deepface/ArcFace", EmbeddingVersion: "3d"}, nil)
 			},
-			expectedError: domain.ErrInvalidImage,
+			wantErr: domain.ErrEmbeddingModelMismatch,
 		},
 		{
-			name: "repository search fails",
-			tenant: &domain.Tenant{
-				ID: uuid.New(),
-				Settings: map[string]interface{}{
-					"search_enabled": true,
-				},
-			},
-			imageBytes: []byte("fake-image-data"),
-			threshold:  0.8,
-			maxResults: 10,
-			setupMocks: func(fr *MockFaceRepository, vr *MockVerificationRepository, fp *MockFaceProvider, ar *MockSearchAuditRepository) {
-				fp.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
-					Embedding:     []float64{0.1, 0.2},
-					Confidence:    0.99,
-					QualityScore:  0.95,
-					LivenessScore: 0.90,
-					FaceCount:     1,
-				}, nil)
-				fr.On("SearchByEmbedding", mock.Anything, mock.Anything, mock.Anything, 0.8, 10).Return(nil, errors.New("database connection failed"))
+			name: "unknown external_id",
+			a:    "user_a",
+			b:    "ghost",
+			setupMocks: func(fr *MockFaceRepository, fp *MockFaceProvider) {
+				fr.On("GetByExternalID", mock.Anything, tenantID, "user_a").Return(&domain.Face{Embedding: embeddingA}, nil)
+				fr.On("GetByExternalID", mock.Anything, tenantID, "ghost").Return(nil, domain.ErrFaceNotFound)
 			},
-			expectedError: errors.New("database connection failed"),
+			wantErr: domain.ErrFaceNotFound,
+		},
+		{
+			name:       "same external_id twice",
+			a:          "user_a",
+			b:          " user_a ",
+			setupMocks: func(fr *MockFaceRepository, fp *MockFaceProvider) {},
+			wantCode:   domain.ErrValidationFailed.Code,
+		},
+		{
+			name:       "missing external_id",
+			a:          "user_a",
+			b:          "",
+			setupMocks: func(fr *MockFaceRepository, fp *MockFaceProvider) {},
+			wantCode:   domain.ErrValidationFailed.Code,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			faceRepo := &MockFaceRepository{}
-			verificationRepo := &MockVerificationRepository{}
-			searchAuditRepo := &MockSearchAuditRepository{}
 			faceProvider := &MockFaceProvider{}
-			rateLimiter := &MockRateLimiter{}
-
-			tt.setupMocks(faceRepo, verificationRepo, faceProvider, searchAuditRepo)
-
-			// Mock rate limiter to always allow
-			rateLimiter.On("CheckSearchLimit", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			tt.setupMocks(faceRepo, faceProvider)
 
-			svc := &FaceService{
-				faceRepo:         faceRepo,
-				verificationRepo: verificationRepo,
-				searchAuditRepo:  searchAuditRepo,
-				provider:         faceProvider,
-				rateLimiter:      rateLimiter,
-				threshold:        0.8,
-			}
+			svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
 
-			result, err := svc.Search(context.Background(), tt.tenant, tt.imageBytes, tt.threshold, tt.maxResults, tt.clientIP)
+			result, err := svc.Compare(context.Background(), tenantID, tt.a, tt.b, domain.DefaultTenantSettings())
 
-			if tt.expectedError != nil {
-				assert.Error(t, err)
-				if errors.Is(tt.expectedError, domain.ErrSearchNotEnabled) ||
-					errors.Is(tt.expectedError, domain.ErrInvalidThreshold) ||
-					errors.Is(tt.expectedError, domain.ErrInvalidMaxResults) ||
-					errors.Is(tt.expectedError, domain.ErrLivenessFailed) ||
-					errors.Is(tt.expectedError, domain.ErrInvalidImage) {
-					assert.ErrorIs(t, err, tt.expectedError)
-				} else {
-					assert.Contains(t, err.Error(), "search faces")
-				}
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
 				assert.Nil(t, result)
-			} else {
+			case tt.wantCode != "":
+				var appErr *domain.AppError
+				require.ErrorAs(t, err, &appErr)
+				assert.Equal(t, tt.wantCode, appErr.Code)
+			default:
 				require.NoError(t, err)
-				require.NotNil(t, result)
-				assert.Equal(t, tt.expectedCount, len(result.Matches))
-
-				if tt.validateResult != nil {
-					tt.validateResult(t, result)
-				}
+				assert.Equal(t, tt.wantSim, result.Similarity)
+				assert.Equal(t, tt.wantMatch, result.Match)
+				assert.Equal(t, 0.8, result.Threshold)
 			}
 
 			faceRepo.AssertExpectations(t)
@@ -844,69 +3849,280 @@ func TestFaceService_Search(t *testing.T) {
 	}
 }
 
-func TestFaceService_Search_WithCustomThreshold(t *testing.T) {
+func TestFaceService_Compare_TenantThreshold(t *testing.T) {
 	tenantID := uuid.New()
+	embeddingA := []float64{0.1, 0.2, 0.3}
+	embeddingB := []float64{0.3, 0.2, 0.1}
 
 	tests := []struct {
-		name              string
-		requestThreshold  float64
-		tenantThreshold   float64
-		expectedThreshold float64
+		name          string
+		metadataB     map[string]interface{}
+		wantThreshold float64
 	}{
-		{
-			name:              "use request threshold when provided",
-			requestThreshold:  0.9,
-			tenantThreshold:   0.8,
-			expectedThreshold: 0.9,
-		},
-		{
-			name:              "use tenant default when request is zero",
-			requestThreshold:  0,
-			tenantThreshold:   0.85,
-			expectedThreshold: 0.85,
-		},
+		{name: "tenant threshold above the default", wantThreshold: 0.9},
+		{name: "stricter segment override of either face", metadataB: map[string]interface{}{"ticket_type": "staff"}, wantThreshold: 0.95},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tenant := &domain.Tenant{
-				ID: tenantID,
-				Settings: map[string]interface{}{
-					"search_enabled":    true,
-					"search_threshold":  tt.tenantThreshold,
-					"search_rate_limit": float64(30),
+			faceRepo := &MockFaceRepository{}
+			faceProvider := &MockFaceProvider{}
+			faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_a").Return(&domain.Face{ExternalID: "user_a", Embedding: embeddingA}, nil)
+			faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_b").Return(&domain.Face{ExternalID: "user_b", Embedding: embeddingB, Metadata: tt.metadataB}, nil)
+			faceProvider.On("CompareFaces", mock.Anything, embeddingA, embeddingB).Return(0.85, nil)
+
+			svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+
+			tenant := &domain.Tenant{ID: tenantID, Settings: map[string]interface{}{
+				"verification_threshold": 0.9,
+				"segment_thresholds": map[string]interface{}{
+					"key":    "ticket_type",
+					"verify": map[string]interface{}{"staff": 0.95},
 				},
-			}
+			}}
+
+			result, err := svc.Compare(context.Background(), tenantID, "user_a", "user_b", tenant.GetSettings())
+			require.NoError(t, err)
+
+			// 0.85 clears the 0.8 default but not the tenant's own verify threshold
+			assert.False(t, result.Match)
+			assert.Equal(t, tt.wantThreshold, result.Threshold)
+		})
+	}
+}
+
+func TestFaceService_RegisterFaceTTL(t *testing.T) {
+	tenantID := uuid.New()
+	analysis := &provider.FaceAnalysis{
+		Embedding:    []float64{0.1, 0.2, 0.3},
+		QualityScore: 0.95,
+		FaceCount:    1,
+	}
+
+	t.Run("new face expires after the ttl", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+
+		faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(analysis, nil)
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "guest-1").Return(nil, domain.ErrFaceNotFound)
+		faceRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		settings := domain.DefaultTenantSettings()
+		settings.FaceTTL = 7 * 24 * time.Hour
+		before := time.Now()
+
+		face, err := svc.Register(context.Background(), tenantID, "guest-1", make([]byte, 5000), nil, settings)
+
+		require.NoError(t, err)
+		require.NotNil(t, face.ExpiresAt)
+		assert.False(t, face.ExpiresAt.Before(before.Add(settings.FaceTTL)))
+		assert.False(t, face.ExpiresAt.After(time.Now().Add(settings.FaceTTL)))
+	})
+
+	t.Run("new face without ttl never expires", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+
+		faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(analysis, nil)
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user-1").Return(nil, domain.ErrFaceNotFound)
+		faceRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
 
+		face, err := svc.Register(context.Background(), tenantID, "user-1", make([]byte, 5000), nil, domain.DefaultTenantSettings())
+
+		require.NoError(t, err)
+		assert.Nil(t, face.ExpiresAt)
+	})
+
+	t.Run("re-registration with a ttl restarts it, without keeps it", func(t *testing.T) {
+		previous := time.Now().Add(time.Hour)
+
+		for _, ttl := range []time.Duration{0, 48 * time.Hour} {
 			faceRepo := &MockFaceRepository{}
 			faceProvider := &MockFaceProvider{}
-			searchAuditRepo := &MockSearchAuditRepository{}
+			svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+
+			existing := &domain.Face{ID: uuid.New(), TenantID: tenantID, ExternalID: "guest-2", ExpiresAt: &previous}
+			faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(analysis, nil)
+			faceRepo.On("GetByExternalID", mock.Anything, tenantID, "guest-2").Return(existing, nil)
+			faceRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+			settings := domain.DefaultTenantSettings()
+			settings.FaceTTL = ttl
+
+			face, err := svc.Register(context.Background(), tenantID, "guest-2", make([]byte, 5000), nil, settings)
+
+			require.NoError(t, err)
+			require.NotNil(t, face.ExpiresAt)
+			if ttl == 0 {
+				assert.Equal(t, previous, *face.ExpiresAt)
+			} else {
+				assert.True(t, face.ExpiresAt.After(time.Now().Add(ttl-time.Minute)))
+			}
+		}
+	})
+}
+
+func TestFaceService_Search_AuditMode(t *testing.T) {
+	tenantID := uuid.New()
+	settingsFor := func(mode string) map[string]interface{} {
+		settings := map[string]interface{}{
+			"search_enabled":              true,
+			"search_by_embedding_enabled": true,
+			"search_rate_limit":           float64(30),
+		}
+		if mode != "" {
+			settings["search_audit_mode"] = mode
+		}
+		return settings
+	}
+
+	newService := func(searchAuditRepo *MockSearchAuditRepository) *FaceService {
+		faceRepo := &MockFaceRepository{}
+		rateLimiter := &MockRateLimiter{}
+		rateLimiter.On("CheckSearchLimit", mock.Anything, tenantID, 30).Return(nil)
+		faceRepo.On("SearchByEmbedding", mock.Anything, tenantID, mock.Anything, mock.Anything, 0.9, 5).Return([]domain.SearchMatch{
+			{FaceID: uuid.New(), ExternalID: "user_001", Similarity: 0.95},
+		}, nil)
+		return NewFaceService(faceRepo, &MockVerificationRepository{}, searchAuditRepo, &MockFaceProvider{}, rateLimiter)
+	}
+
+	t.Run("async mode returns the result when the audit write fails", func(t *testing.T) {
+		searchAuditRepo := &MockSearchAuditRepository{}
+		written := make(chan struct{})
+		searchAuditRepo.On("Create", mock.Anything, mock.Anything).
+			Run(func(mock.Arguments) { close(written) }).
+			Return(errors.New("connection refused"))
+
+		svc := newService(searchAuditRepo)
+		tenant := &domain.Tenant{ID: tenantID, Settings: settingsFor("")}
+
+		result, err := svc.SearchByEmbedding(context.Background(), tenant, unitEmbedding(), 0.9, 5, "127.0.0.1")
+
+		require.NoError(t, err)
+		require.Len(t, result.Matches, 1)
+		select {
+		case <-written:
+		case <-time.After(time.Second):
+			t.Fatal("search audit was not written")
+		}
+	})
+
+	t.Run("sync mode writes the audit before returning", func(t *testing.T) {
+		searchAuditRepo := &MockSearchAuditRepository{}
+		searchAuditRepo.On("Create", mock.Anything, mock.MatchedBy(func(audit *domain.SearchAudit) bool {
+			return audit.TenantID == tenantID && audit.ResultsCount == 1
+		})).Return(nil)
+
+		svc := newService(searchAuditRepo)
+		tenant := &domain.Tenant{ID: tenantID, Settings: settingsFor("sync")}
+
+		result, err := svc.SearchByEmbedding(context.Background(), tenant, unitEmbedding(), 0.9, 5, "127.0.0.1")
+
+		require.NoError(t, err)
+		require.Len(t, result.Matches, 1)
+		searchAuditRepo.AssertNumberOfCalls(t, "Create", 1)
+	})
+
+	t.Run("sync mode fails closed when the audit write fails", func(t *testing.T) {
+		searchAuditRepo := &MockSearchAuditRepository{}
+		searchAuditRepo.On("Create", mock.Anything, mock.Anything).Return(errors.New("connection refused"))
+
+		svc := newService(searchAuditRepo)
+		tenant := &domain.Tenant{ID: tenantID, Settings: settingsFor("sync")}
+
+		result, err := svc.SearchByEmbedding(context.Background(), tenant, unitEmbedding(), 0.9, 5, "127.0.0.1")
+
+		assert.ErrorIs(t, err, domain.ErrAuditWriteFailed)
+		assert.Nil(t, result)
+	})
+}
+
+func TestFaceService_EmbeddingPrecision(t *testing.T) {
+	halfSettings := domain.DefaultTenantSettings()
+	halfSettings.EmbeddingPrecision = domain.EmbeddingPrecisionHalf
+
+	analysis := &provider.FaceAnalysis{Embedding: unitEmbedding(), QualityScore: 0.95, FaceCount: 1}
+
+	t.Run("register stores the embedding at the tenant precision", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+		faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(analysis, nil)
+		faceRepo.On("GetByExternalID", mock.Anything, mock.Anything, "user_001").Return(nil, domain.ErrFaceNotFound)
+		faceRepo.On("Create", mock.Anything, mock.MatchedBy(func(f *domain.Face) bool {
+			return f.EmbeddingPrecision == domain.EmbeddingPrecisionHalf
+		})).Return(nil)
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+
+		_, err := svc.Register(context.Background(), uuid.New(), "user_001", make([]byte, 5000), nil, halfSettings)
+
+		require.NoError(t, err)
+		faceRepo.AssertExpectations(t)
+	})
+
+	t.Run("re-register moves the face to the tenant precision", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+		tenantID := uuid.New()
+		faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(analysis, nil)
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(&domain.Face{
+			ID:         uuid.New(),
+			TenantID:   tenantID,
+			ExternalID: "user_001",
+			Embedding:  unitEmbedding(),
+		}, nil)
+		faceRepo.On("Update", mock.Anything, mock.MatchedBy(func(f *domain.Face) bool {
+			return f.EmbeddingPrecision == domain.EmbeddingPrecisionHalf
+		})).Return(nil)
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+
+		_, err := svc.Register(context.Background(), tenantID, "user_001", make([]byte, 5000), nil, halfSettings)
+
+		require.NoError(t, err)
+		faceRepo.AssertExpectations(t)
+	})
+
+	// Searches compare faces stored at either precision, whatever the tenant's current setting
+	tests := []struct {
+		name      string
+		precision interface{}
+	}{
+		{name: "full precision by default", precision: nil},
+		{name: "half precision", precision: "half"},
+		{name: "full precision after half", precision: "full"},
+	}
+
+	for _, tt := range tests {
+		t.Run("search with "+tt.name, func(t *testing.T) {
+			faceRepo := &MockFaceRepository{}
 			rateLimiter := &MockRateLimiter{}
+			searchAuditRepo := &MockSearchAuditRepository{}
+			tenantID := uuid.New()
+			settings := map[string]interface{}{
+				"search_enabled":              true,
+				"search_by_embedding_enabled": true,
+			}
+			if tt.precision != nil {
+				settings["embedding_precision"] = tt.precision
+			}
 
-			rateLimiter.On("CheckSearchLimit", mock.Anything, tenantID, 30).Return(nil)
-			faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
-				Embedding:     []float64{0.1, 0.2},
-				Confidence:    0.99,
-				QualityScore:  0.95,
-				LivenessScore: 0.90,
-				FaceCount:     1,
+			rateLimiter.On("CheckSearchLimit", mock.Anything, tenantID, mock.Anything).Return(nil)
+			faceRepo.On("SearchByEmbedding", mock.Anything, tenantID, mock.Anything, mock.Anything, 0.9, 5).Return([]domain.SearchMatch{
+				{FaceID: uuid.New(), ExternalID: "user_001", Similarity: 0.95},
 			}, nil)
-			faceRepo.On("SearchByEmbedding", mock.Anything, tenantID, mock.Anything, tt.expectedThreshold, mock.Anything).Return([]domain.SearchMatch{}, nil)
 			searchAuditRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
 
-			svc := &FaceService{
-				faceRepo:        faceRepo,
-				searchAuditRepo: searchAuditRepo,
-				provider:        faceProvider,
-				rateLimiter:     rateLimiter,
-				threshold:       0.8,
-			}
+			svc := NewFaceService(faceRepo, &MockVerificationRepository{}, searchAuditRepo, &MockFaceProvider{}, rateLimiter)
+			tenant := &domain.Tenant{ID: tenantID, Settings: settings}
 
-			_, err := svc.Search(context.Background(), tenant, []byte("image"), tt.requestThreshold, 10, "127.0.0.1")
+			result, err := svc.SearchByEmbedding(context.Background(), tenant, unitEmbedding(), 0.9, 5, "127.0.0.1")
 
 			require.NoError(t, err)
+			require.Len(t, result.Matches, 1)
 			faceRepo.AssertExpectations(t)
-			faceProvider.AssertExpectations(t)
 		})
 	}
 }
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const (
+	// defaultProviderErrorPruneInterval is how often provider errors past the retention are deleted
+	defaultProviderErrorPruneInterval = time.Hour
+	// defaultProviderErrorPruneBatchSize is how many provider errors one delete statement removes
+	defaultProviderErrorPruneBatchSize = 1000
+)
+
+// ProviderErrorPruneRepositoryInterface deletes provider errors recorded before a cutoff
+type ProviderErrorPruneRepositoryInterface interface {
+	DeleteBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error)
+}
+
+// ProviderErrorPruner periodically deletes provider errors older than the retention, so the
+// provider_errors table behind GET /super/metrics/provider-errors does not grow without bound
+type ProviderErrorPruner struct {
+	repo      ProviderErrorPruneRepositoryInterface
+	logger    *slog.Logger
+	retention time.Duration
+	interval  time.Duration
+	batchSize int
+	now       func() time.Time
+}
+
+func NewProviderErrorPruner(repo ProviderErrorPruneRepositoryInterface, logger *slog.Logger, retention time.Duration) *ProviderErrorPruner {
+	return &ProviderErrorPruner{
+		repo:      repo,
+		logger:    logger,
+		retention: retention,
+		interval:  defaultProviderErrorPruneInterval,
+		batchSize: defaultProviderErrorPruneBatchSize,
+		now:       time.Now,
+	}
+}
+
+// Run prunes once at start and then every interval until ctx is cancelled
+func (p *ProviderErrorPruner) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	p.logger.Info("provider error retention worker started", "retention", p.retention)
+
+	for {
+		if _, err := p.Prune(ctx); err != nil {
+			p.logger.Error("provider error prune failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			p.logger.Info("provider error retention worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Prune deletes every provider error recorded before now minus the retention, one batch at a
+// time, and returns how many were deleted
+func (p *ProviderErrorPruner) Prune(ctx context.Context) (int64, error) {
+	cutoff := p.now().Add(-p.retention)
+	var deleted int64
+
+	for {
+		n, err := p.repo.DeleteBefore(ctx, cutoff, p.batchSize)
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+
+		// A short batch was the last one
+		if n < int64(p.batchSize) {
+			break
+		}
+	}
+
+	if deleted > 0 {
+		p.logger.Info("old provider errors deleted", "count", deleted, "cutoff", cutoff)
+	}
+	return deleted, nil
+}
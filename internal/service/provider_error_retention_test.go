@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProviderErrorPruneRepository keeps the created_at of provider errors in memory
+type fakeProviderErrorPruneRepository struct {
+	createdAt []time.Time
+	cutoffs   []time.Time
+	err       error
+}
+
+func (f *fakeProviderErrorPruneRepository) DeleteBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	f.cutoffs = append(f.cutoffs, cutoff)
+	if f.err != nil {
+		return 0, f.err
+	}
+
+	var kept []time.Time
+	var deleted int64
+	for _, at := range f.createdAt {
+		if at.Before(cutoff) && deleted < int64(limit) {
+			deleted++
+			continue
+		}
+		kept = append(kept, at)
+	}
+	f.createdAt = kept
+	return deleted, nil
+}
+
+func TestProviderErrorPruner_Prune(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	t.Run("deletes errors older than the retention in batches", func(t *testing.T) {
+		repo := &fakeProviderErrorPruneRepository{}
+		for i := 0; i < 5; i++ {
+			repo.createdAt = append(repo.createdAt, now.AddDate(0, 0, -31-i))
+		}
+		recent := now.AddDate(0, 0, -29)
+		repo.createdAt = append(repo.createdAt, recent)
+
+		pruner := NewProviderErrorPruner(repo, logger, 30*24*time.Hour)
+		pruner.now = func() time.Time { return now }
+		pruner.batchSize = 2
+
+		deleted, err := pruner.Prune(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), deleted)
+		assert.Equal(t, []time.Time{recent}, repo.createdAt)
+		// Two full batches, then a short one
+		assert.Len(t, repo.cutoffs, 3)
+		assert.Equal(t, now.AddDate(0, 0, -30), repo.cutoffs[0])
+	})
+
+	t.Run("returns the repository error", func(t *testing.T) {
+		repo := &fakeProviderErrorPruneRepository{err: errors.New("connection refused")}
+
+		pruner := NewProviderErrorPruner(repo, logger, time.Hour)
+		_, err := pruner.Prune(context.Background())
+
+		assert.EqualError(t, err, "connection refused")
+	})
+}
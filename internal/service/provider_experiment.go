@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"math/rand/v2"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+// providerVariantKey marks a request routed by the tenant's provider experiment
+type providerVariantKey struct{}
+
+// providerVariant returns the experiment arm the request in ctx was routed to, empty when the
+// request is not part of a provider experiment
+func providerVariant(ctx context.Context) string {
+	variant, _ := ctx.Value(providerVariantKey{}).(string)
+	return variant
+}
+
+// alternateRouted reports whether the alternate provider decides the request in ctx
+func alternateRouted(ctx context.Context) bool {
+	return providerVariant(ctx) == domain.ProviderVariantAlternate
+}
+
+// routeProvider samples the request into an arm of the tenant's provider_experiment and marks
+// ctx with it. The alternate is the shadow provider, so requests are left unrouted when none is
+// configured, and test-environment requests always use the test provider.
+func (s *FaceService) routeProvider(ctx context.Context, settings domain.TenantSettings) context.Context {
+	if !settings.ProviderExperiment.Enabled() || s.shadowProvider == nil || s.testMode(ctx) {
+		return ctx
+	}
+	roll := rand.Float64
+	if s.sampleRoll != nil {
+		roll = s.sampleRoll
+	}
+	return context.WithValue(ctx, providerVariantKey{}, settings.ProviderExperiment.Variant(roll()))
+}
+
+// routePrimary moves a request sampled for the alternate back to the primary arm
+func routePrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, providerVariantKey{}, domain.ProviderVariantPrimary)
+}
+
+// alternateCanVerify reports whether the alternate provider's embeddings can be compared with
+// the stored face. Faces enrolled with another model are verified by the primary instead.
+func (s *FaceService) alternateCanVerify(face *domain.Face) bool {
+	return s.shadowFingerprint(face.Embedding).Compatible(face.Fingerprint())
+}
+
+// alternateCanSearch reports whether the alternate provider's embeddings can be searched against
+// the tenant's faces. Searches only match faces of the query's model, so with another model the
+// alternate would find none of the faces the primary enrolled; the primary searches instead.
+func (s *FaceService) alternateCanSearch() bool {
+	return s.shadowModel == s.embeddingModel
+}
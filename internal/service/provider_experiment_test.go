@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/provider"
+)
+
+// sequenceRolls returns the rolls 0.05, 0.15, ... 0.95 in turn, so n requests split exactly
+func sequenceRolls() func() float64 {
+	next := 0
+	return func() float64 {
+		roll := float64(next%10)/10 + 0.05
+		next++
+		return roll
+	}
+}
+
+func TestFaceService_ProviderExperiment_Verify(t *testing.T) {
+	tenantID := uuid.New()
+	embedding := make([]float64, 512)
+	settings := domain.DefaultTenantSettings()
+	settings.ProviderExperiment.Percentage = 30
+
+	setup := func(stored *domain.Face) (*FaceService, *MockVerificationRepository, *MockFaceProvider, *MockFaceProvider) {
+		faceRepo := &MockFaceRepository{}
+		verificationRepo := &MockVerificationRepository{}
+		primary := &MockFaceProvider{}
+		alternate := &MockFaceProvider{}
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(stored, nil)
+		for _, p := range []*MockFaceProvider{primary, alternate} {
+			p.On("DetectFaces", mock.Anything, mock.Anything).Return([]provider.DetectedFace{{Confidence: 0.99, QualityScore: 0.9}}, nil)
+			p.On("IndexFace", mock.Anything, mock.Anything).Return("face-id", embedding, nil)
+		}
+		primary.On("CompareFaces", mock.Anything, mock.Anything, mock.Anything).Return(0.92, nil)
+		alternate.On("CompareFaces", mock.Anything, mock.Anything, mock.Anything).Return(0.75, nil)
+		verificationRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		svc := NewFaceService(faceRepo, verificationRepo, &MockSearchAuditRepository{}, primary, &MockRateLimiter{}).
+			WithShadowProvider(alternate, newRecordingShadowRepository())
+		svc.sampleRoll = sequenceRolls()
+		return svc, verificationRepo, primary, alternate
+	}
+
+	t.Run("the sampled share is decided by the alternate and tagged", func(t *testing.T) {
+		svc, verificationRepo, primary, alternate := setup(&domain.Face{ID: uuid.New(), Embedding: embedding})
+
+		variants := map[string]int{}
+		for i := 0; i < 10; i++ {
+			result, err := svc.Verify(context.Background(), tenantID, "user_001", make([]byte, 5000), settings)
+			require.NoError(t, err)
+
+			variants[result.ProviderVariant]++
+			if result.ProviderVariant == domain.ProviderVariantAlternate {
+				// The alternate's decision is authoritative
+				assert.False(t, result.Verified)
+				assert.Equal(t, 0.75, result.Confidence)
+			} else {
+				assert.True(t, result.Verified)
+				assert.Equal(t, 0.92, result.Confidence)
+			}
+		}
+
+		assert.Equal(t, map[string]int{domain.ProviderVariantAlternate: 3, domain.ProviderVariantPrimary: 7}, variants)
+		alternate.AssertNumberOfCalls(t, "CompareFaces", 3)
+		primary.AssertNumberOfCalls(t, "CompareFaces", 7)
+		verificationRepo.AssertNumberOfCalls(t, "Create", 10)
+		verificationRepo.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(v *domain.Verification) bool {
+			return v.ProviderVariant == domain.ProviderVariantAlternate && !v.Verified
+		}))
+	})
+
+	t.Run("faces enrolled with another model are verified by the primary", func(t *testing.T) {
+		stored := &domain.Face{ID: uuid.New(), Embedding: embedding, EmbeddingModel: "deepface/Facenet512", EmbeddingVersion: "512d"}
+		svc, _, primary, alternate := setup(stored)
+		svc.WithEmbeddingModel("deepface/Facenet512")
+		svc.shadowModel = "deepface/ArcFace"
+		svc.sampleRoll = func() float64 { return 0 }
+
+		result, err := svc.Verify(context.Background(), tenantID, "user_001", make([]byte, 5000), settings)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.ProviderVariantPrimary, result.ProviderVariant)
+		assert.True(t, result.Verified)
+		primary.AssertNumberOfCalls(t, "CompareFaces", 1)
+		alternate.AssertNotCalled(t, "IndexFace", mock.Anything, mock.Anything)
+	})
+
+	t.Run("requests outside an experiment are not tagged", func(t *testing.T) {
+		svc, _, _, alternate := setup(&domain.Face{ID: uuid.New(), Embedding: embedding})
+		svc.sampleRoll = func() float64 { return 0 }
+
+		result, err := svc.Verify(context.Background(), tenantID, "user_001", make([]byte, 5000), domain.DefaultTenantSettings())
+
+		require.NoError(t, err)
+		assert.Empty(t, result.ProviderVariant)
+		alternate.AssertNotCalled(t, "CompareFaces", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestFaceService_ProviderExperiment_Search(t *testing.T) {
+	tenant := &domain.Tenant{
+		ID: uuid.New(),
+		Settings: map[string]interface{}{
+			"search_enabled":      true,
+			"provider_experiment": map[string]interface{}{"percentage": 50.0},
+		},
+	}
+	primaryEmbedding := make([]float64, 512)
+	alternateEmbedding := make([]float64, 512)
+	alternateEmbedding[0] = 1
+
+	faceRepo := &MockFaceRepository{}
+	rateLimiter := &MockRateLimiter{}
+	auditRepo := &MockSearchAuditRepository{}
+	primary := &MockFaceProvider{}
+	alternate := &MockFaceProvider{}
+
+	rateLimiter.On("CheckSearchLimit", mock.Anything, tenant.ID, mock.Anything).Return(nil)
+	audits := make(chan *domain.SearchAudit, 10)
+	auditRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		audits <- args.Get(1).(*domain.SearchAudit)
+	})
+	primary.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{Embedding: primaryEmbedding, FaceCount: 1}, nil)
+	alternate.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{Embedding: alternateEmbedding, FaceCount: 1}, nil)
+	// Both arms search the faces enrolled with the tenant's model
+	fingerprint := domain.NewEmbeddingFingerprint("deepface/Facenet512", 512)
+	faceRepo.On("SearchByEmbedding", mock.Anything, tenant.ID, primaryEmbedding, fingerprint, mock.Anything, mock.Anything).
+		Return([]domain.SearchMatch{{ExternalID: "user_001", Similarity: 0.95}}, nil)
+	faceRepo.On("SearchByEmbedding", mock.Anything, tenant.ID, alternateEmbedding, fingerprint, mock.Anything, mock.Anything).
+		Return([]domain.SearchMatch{{ExternalID: "user_002", Similarity: 0.88}}, nil)
+
+	svc := NewFaceService(faceRepo, &MockVerificationRepository{}, auditRepo, primary, rateLimiter).
+		WithShadowProvider(alternate, newRecordingShadowRepository()).
+		WithEmbeddingModel("deepface/Facenet512")
+	svc.shadowModel = "deepface/Facenet512"
+	svc.sampleRoll = sequenceRolls()
+
+	topMatches := map[string]string{}
+	for i := 0; i < 10; i++ {
+		result, err := svc.Search(context.Background(), tenant, make([]byte, 5000), 0, 0, "127.0.0.1")
+		require.NoError(t, err)
+		require.Len(t, result.Matches, 1)
+		topMatches[result.Matches[0].ExternalID] = result.ProviderVariant
+	}
+
+	assert.Equal(t, map[string]string{"user_001": domain.ProviderVariantPrimary, "user_002": domain.ProviderVariantAlternate}, topMatches)
+	primary.AssertNumberOfCalls(t, "AnalyzeFace", 5)
+	alternate.AssertNumberOfCalls(t, "AnalyzeFace", 5)
+
+	// Search audits keep the arm of every search for the comparison
+	audited := map[string]string{}
+	for i := 0; i < 10; i++ {
+		select {
+		case audit := <-audits:
+			audited[*audit.TopMatchExternalID] = audit.ProviderVariant
+		case <-time.After(2 * time.Second):
+			t.Fatal("search audit was not recorded")
+		}
+	}
+	assert.Equal(t, topMatches, audited)
+}
+
+func TestFaceService_ProviderExperiment_SearchWithAnotherModel(t *testing.T) {
+	tenant := &domain.Tenant{
+		ID: uuid.New(),
+		Settings: map[string]interface{}{
+			"search_enabled":      true,
+			"provider_experiment": map[string]interface{}{"percentage": 100.0},
+		},
+	}
+	embedding := make([]float64, 512)
+
+	faceRepo := &MockFaceRepository{}
+	rateLimiter := &MockRateLimiter{}
+	auditRepo := &MockSearchAuditRepository{}
+	primary := &MockFaceProvider{}
+	alternate := &MockFaceProvider{}
+
+	rateLimiter.On("CheckSearchLimit", mock.Anything, tenant.ID, mock.Anything).Return(nil)
+	auditRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	primary.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{Embedding: embedding, FaceCount: 1}, nil)
+	// An ArcFace query would match none of the Facenet512 faces
+	faceRepo.On("SearchByEmbedding", mock.Anything, tenant.ID, embedding, domain.NewEmbeddingFingerprint("deepface/Facenet512", 512), mock.Anything, mock.Anything).
+		Return([]domain.SearchMatch{{ExternalID: "user_001", Similarity: 0.95}}, nil)
+
+	svc := NewFaceService(faceRepo, &MockVerificationRepository{}, auditRepo, primary, rateLimiter).
+		WithShadowProvider(alternate, newRecordingShadowRepository()).
+		WithEmbeddingModel("deepface/Facenet512")
+	svc.shadowModel = "deepface/ArcFace"
+	svc.sampleRoll = func() float64 { return 0 }
+
+	result, err := svc.Search(context.Background(), tenant, make([]byte, 5000), 0, 0, "127.0.0.1")
+
+	require.NoError(t, err)
+	require.Len(t, result.Matches, 1)
+	assert.Equal(t, "user_001", result.Matches[0].ExternalID)
+	assert.Equal(t, domain.ProviderVariantPrimary, result.ProviderVariant)
+	alternate.AssertNotCalled(t, "AnalyzeFace", mock.Anything, mock.Anything)
+	faceRepo.AssertExpectations(t)
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// providerOperation names the API operation a provider call is made for
+type providerOperation int
+
+const (
+	providerOpRegister providerOperation = iota
+	providerOpVerify
+	providerOpSearch
+	providerOpDetect
+)
+
+// ProviderTimeouts bounds the provider calls of each operation. Detect covers standalone
+// liveness checks. Zero values fall back to Default; a zero Default leaves calls unbounded.
+type ProviderTimeouts struct {
+	Default  time.Duration
+	Register time.Duration
+	Verify   time.Duration
+	Search   time.Duration
+	Detect   time.Duration
+}
+
+// forOperation returns the timeout for op, falling back to Default
+func (t ProviderTimeouts) forOperation(op providerOperation) time.Duration {
+	var timeout time.Duration
+	switch op {
+	case providerOpRegister:
+		timeout = t.Register
+	case providerOpVerify:
+		timeout = t.Verify
+	case providerOpSearch:
+		timeout = t.Search
+	case providerOpDetect:
+		timeout = t.Detect
+	}
+	if timeout <= 0 {
+		return t.Default
+	}
+	return timeout
+}
+
+// WithProviderTimeouts bounds provider calls per operation, e.g. to give 1:N search more time than detection
+func (s *FaceService) WithProviderTimeouts(timeouts ProviderTimeouts) *FaceService {
+	s.providerTimeouts = timeouts
+	return s
+}
+
+// providerContext derives the context for the provider calls of op. Only provider calls use it,
+// so repository work before and after is not charged against the provider budget.
+func (s *FaceService) providerContext(ctx context.Context, op providerOperation) (context.Context, context.CancelFunc) {
+	timeout := s.providerTimeouts.forOperation(op)
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/provider"
+)
+
+func TestProviderTimeouts_ForOperation(t *testing.T) {
+	timeouts := ProviderTimeouts{
+		Default: 5 * time.Second,
+		Search:  20 * time.Second,
+		Detect:  2 * time.Second,
+	}
+
+	assert.Equal(t, 20*time.Second, timeouts.forOperation(providerOpSearch))
+	assert.Equal(t, 2*time.Second, timeouts.forOperation(providerOpDetect))
+	assert.Equal(t, 5*time.Second, timeouts.forOperation(providerOpVerify))
+	assert.Equal(t, 5*time.Second, timeouts.forOperation(providerOpRegister))
+	assert.Zero(t, ProviderTimeouts{}.forOperation(providerOpSearch))
+}
+
+// deadlineWithin matches contexts whose deadline is at most timeout away, and more than timeout minus a second
+func deadlineWithin(timeout time.Duration) interface{} {
+	return mock.MatchedBy(func(ctx context.Context) bool {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			return false
+		}
+		remaining := time.Until(deadline)
+		return remaining <= timeout && remaining > timeout-time.Second
+	})
+}
+
+func TestFaceService_ProviderTimeouts(t *testing.T) {
+	timeouts := ProviderTimeouts{
+		Default: 3 * time.Second,
+		Search:  12 * time.Second,
+	}
+	analysis := &provider.FaceAnalysis{
+		Embedding:    []float64{0.1, 0.2, 0.3},
+		QualityScore: 0.95,
+		FaceCount:    1,
+	}
+
+	t.Run("search uses the search timeout", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+		rateLimiter := &MockRateLimiter{}
+		auditRepo := &MockSearchAuditRepository{}
+
+		faceProvider.On("AnalyzeFace", deadlineWithin(12*time.Second), mock.Anything).Return(analysis, nil)
+		// The repository search is not charged against the provider budget
+		faceRepo.On("SearchByEmbedding", mock.MatchedBy(func(ctx context.Context) bool {
+			_, ok := ctx.Deadline()
+			return !ok
+		}), mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.SearchMatch{}, nil)
+		rateLimiter.On("CheckSearchLimit", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		auditRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, auditRepo, faceProvider, rateLimiter).
+			WithProviderTimeouts(timeouts)
+
+		tenant := &domain.Tenant{ID: uuid.New(), Settings: map[string]interface{}{"search_enabled": true}}
+		_, err := svc.Search(context.Background(), tenant, []byte("image"), 0.85, 10, "203.0.113.7")
+
+		require.NoError(t, err)
+		faceProvider.AssertExpectations(t)
+		faceRepo.AssertExpectations(t)
+	})
+
+	t.Run("register falls back to the default timeout", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		faceProvider := &MockFaceProvider{}
+
+		faceProvider.On("AnalyzeFace", deadlineWithin(3*time.Second), mock.Anything).Return(analysis, nil)
+		faceRepo.On("GetByExternalID", mock.Anything, mock.Anything, "user_001").Return(nil, domain.ErrFaceNotFound)
+		faceRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{}).
+			WithProviderTimeouts(timeouts)
+
+		_, err := svc.Register(context.Background(), uuid.New(), "user_001", []byte("image"), nil, domain.DefaultTenantSettings())
+
+		require.NoError(t, err)
+		faceProvider.AssertExpectations(t)
+	})
+
+	t.Run("no timeouts leave provider calls unbounded", func(t *testing.T) {
+		faceProvider := &MockFaceProvider{}
+		faceProvider.On("CheckLiveness", mock.MatchedBy(func(ctx context.Context) bool {
+			_, ok := ctx.Deadline()
+			return !ok
+		}), mock.Anything, 0.9).Return(&provider.LivenessResult{IsLive: true, Confidence: 0.95}, nil)
+
+		svc := NewFaceService(&MockFaceRepository{}, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+
+		settings := domain.DefaultTenantSettings()
+		settings.LivenessThreshold = 0.9
+
+		_, err := svc.CheckLiveness(context.Background(), uuid.New(), []byte("image"), settings)
+
+		require.NoError(t, err)
+		faceProvider.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+// ProvisioningRepositoryInterface binds self-serve API keys to new tenants
+type ProvisioningRepositoryInterface interface {
+	ProvisionForAPIKey(ctx context.Context, keyID uuid.UUID, tenant *domain.Tenant, setup func(ctx context.Context, tenant *domain.Tenant) error) (uuid.UUID, error)
+}
+
+// CollectionEnsurer creates the provider-side storage for a tenant (e.g. a Rekognition collection)
+type CollectionEnsurer interface {
+	EnsureCollection(ctx context.Context, tenantID string) error
+}
+
+// TenantProvisioner creates the tenant for a pre-issued self-serve API key on first use
+type TenantProvisioner struct {
+	repo        ProvisioningRepositoryInterface
+	collections CollectionEnsurer // optional, nil for providers without per-tenant storage
+	logger      *slog.Logger
+}
+
+func NewTenantProvisioner(repo ProvisioningRepositoryInterface, collections CollectionEnsurer, logger *slog.Logger) *TenantProvisioner {
+	return &TenantProvisioner{
+		repo:        repo,
+		collections: collections,
+		logger:      logger,
+	}
+}
+
+// Provision creates and binds the key's tenant, returning its ID. The collection is created
+// inside the same transaction so a provider failure leaves the key unbound for a retry.
+func (p *TenantProvisioner) Provision(ctx context.Context, key *domain.APIKey) (uuid.UUID, error) {
+	tenant := domain.NewProvisionedTenant(key)
+
+	tenantID, err := p.repo.ProvisionForAPIKey(ctx, key.ID, tenant, func(ctx context.Context, tenant *domain.Tenant) error {
+		if p.collections == nil {
+			return nil
+		}
+		return p.collections.EnsureCollection(ctx, tenant.ID.String())
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if tenantID == tenant.ID {
+		p.logger.Info("tenant auto-provisioned",
+			"tenant_id", tenantID,
+			"tenant_slug", tenant.Slug,
+			"key_id", key.ID,
+			"key_prefix", key.KeyPrefix,
+		)
+	}
+	return tenantID, nil
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+// fakeProvisioningRepository runs setup like the repository transaction, without a database
+type fakeProvisioningRepository struct {
+	bound uuid.UUID
+}
+
+func (f *fakeProvisioningRepository) ProvisionForAPIKey(ctx context.Context, keyID uuid.UUID, tenant *domain.Tenant, setup func(ctx context.Context, tenant *domain.Tenant) error) (uuid.UUID, error) {
+	if f.bound != uuid.Nil {
+		return f.bound, nil
+	}
+	if err := setup(ctx, tenant); err != nil {
+		return uuid.Nil, err
+	}
+	f.bound = tenant.ID
+	return tenant.ID, nil
+}
+
+type fakeCollections struct {
+	ensured []string
+	err     error
+}
+
+func (f *fakeCollections) EnsureCollection(ctx context.Context, tenantID string) error {
+	f.ensured = append(f.ensured, tenantID)
+	return f.err
+}
+
+func TestTenantProvisioner_Provision(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	key := &domain.APIKey{ID: uuid.New(), Name: "Acme signup", AutoProvision: true}
+
+	t.Run("creates the collection for the new tenant once", func(t *testing.T) {
+		repo := &fakeProvisioningRepository{}
+		collections := &fakeCollections{}
+		provisioner := NewTenantProvisioner(repo, collections, logger)
+
+		first, err := provisioner.Provision(context.Background(), key)
+		require.NoError(t, err)
+		second, err := provisioner.Provision(context.Background(), key)
+		require.NoError(t, err)
+
+		assert.Equal(t, first, second)
+		assert.Equal(t, []string{first.String()}, collections.ensured)
+	})
+
+	t.Run("collection failure leaves the key unbound", func(t *testing.T) {
+		repo := &fakeProvisioningRepository{}
+		provisioner := NewTenantProvisioner(repo, &fakeCollections{err: errors.New("throttled")}, logger)
+
+		_, err := provisioner.Provision(context.Background(), key)
+
+		require.Error(t, err)
+		assert.Equal(t, uuid.Nil, repo.bound)
+	})
+
+	t.Run("providers without collections skip setup", func(t *testing.T) {
+		provisioner := NewTenantProvisioner(&fakeProvisioningRepository{}, nil, logger)
+
+		tenantID, err := provisioner.Provision(context.Background(), key)
+
+		require.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, tenantID)
+	})
+}
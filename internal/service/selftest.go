@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+const (
+	selfTestExternalID = "selftest-subject"
+	selfTestImageSize  = 4096
+)
+
+// SelfTestFaces is the slice of FaceService exercised by the self-test
+type SelfTestFaces interface {
+	Register(ctx context.Context, tenantID uuid.UUID, externalID string, imageBytes []byte, metadata map[string]interface{}, settings domain.TenantSettings) (*domain.Face, error)
+	Verify(ctx context.Context, tenantID uuid.UUID, externalID string, imageBytes []byte, settings domain.TenantSettings) (*domain.Verification, error)
+	Search(ctx context.Context, tenant *domain.Tenant, imageBytes []byte, threshold float64, maxResults int, clientIP string) (*domain.SearchResult, error)
+	Delete(ctx context.Context, tenantID uuid.UUID, externalID string) error
+}
+
+// SelfTestTenants creates the throwaway tenant and erases it afterwards
+type SelfTestTenants interface {
+	Create(ctx context.Context, tenant *domain.Tenant) error
+	DeleteCascade(ctx context.Context, id uuid.UUID) (int64, error)
+}
+
+// SelfTestStep is the outcome of one stage of the self-test
+type SelfTestStep struct {
+	Name     string        `json:"name"`
+	Passed   bool          `json:"passed"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// SelfTestReport is the outcome of a full self-test run
+type SelfTestReport struct {
+	TenantID uuid.UUID      `json:"tenant_id"`
+	Passed   bool           `json:"passed"`
+	Steps    []SelfTestStep `json:"steps"`
+}
+
+// Err returns the first failed step as an error, or nil when every step passed
+func (r *SelfTestReport) Err() error {
+	for _, step := range r.Steps {
+		if !step.Passed {
+			return fmt.Errorf("selftest %s: %s", step.Name, step.Error)
+		}
+	}
+	return nil
+}
+
+// SelfTest runs a register → verify → search → delete cycle for a throwaway tenant,
+// so a deployment can prove the database and the face pipeline work end to end.
+// It is meant to run against the mock provider, which never leaves the process.
+type SelfTest struct {
+	faces   SelfTestFaces
+	tenants SelfTestTenants
+}
+
+func NewSelfTest(faces SelfTestFaces, tenants SelfTestTenants) *SelfTest {
+	return &SelfTest{
+		faces:   faces,
+		tenants: tenants,
+	}
+}
+
+// Run executes the cycle, stopping at the first failed step. The throwaway tenant is
+// always deleted, and a failed cleanup fails the run.
+func (t *SelfTest) Run(ctx context.Context) *SelfTestReport {
+	id := uuid.New()
+	tenant := &domain.Tenant{
+		ID:       id,
+		Name:     "Rekko self-test",
+		Slug:     "selftest-" + id.String(),
+		IsActive: true,
+		Plan:     domain.PlanStarter,
+		Settings: map[string]interface{}{"search_enabled": true},
+	}
+	report := &SelfTestReport{TenantID: id}
+
+	if !report.run("create_tenant", func() error {
+		return t.tenants.Create(ctx, tenant)
+	}) {
+		return report.finish()
+	}
+
+	t.runCycle(ctx, report, tenant)
+
+	report.run("delete_tenant", func() error {
+		_, err := t.tenants.DeleteCascade(ctx, id)
+		return err
+	})
+
+	return report.finish()
+}
+
+// runCycle exercises the face pipeline, stopping at the first failed step
+func (t *SelfTest) runCycle(ctx context.Context, report *SelfTestReport, tenant *domain.Tenant) {
+	settings := tenant.GetSettings()
+	image := selfTestImage()
+
+	steps := []struct {
+		name string
+		fn   func() error
+	}{
+		{"register", func() error {
+			_, err := t.faces.Register(ctx, tenant.ID, selfTestExternalID, image, nil, settings)
+			return err
+		}},
+		{"verify", func() error {
+			verification, err := t.faces.Verify(ctx, tenant.ID, selfTestExternalID, image, settings)
+			if err != nil {
+				return err
+			}
+			if !verification.Verified {
+				return fmt.Errorf("registered face not verified (confidence %.4f)", verification.Confidence)
+			}
+			return nil
+		}},
+		{"search", func() error {
+			result, err := t.faces.Search(ctx, tenant, image, 0, 1, "")
+			if err != nil {
+				return err
+			}
+			if len(result.Matches) == 0 || result.Matches[0].ExternalID != selfTestExternalID {
+				return errors.New("registered face not found by search")
+			}
+			return nil
+		}},
+		{"delete", func() error {
+			return t.faces.Delete(ctx, tenant.ID, selfTestExternalID)
+		}},
+	}
+
+	for _, step := range steps {
+		if !report.run(step.name, step.fn) {
+			return
+		}
+	}
+}
+
+// run times fn and records it as a step, reporting whether it passed
+func (r *SelfTestReport) run(name string, fn func() error) bool {
+	start := time.Now()
+	err := fn()
+	step := SelfTestStep{
+		Name:     name,
+		Passed:   err == nil,
+		Duration: time.Since(start),
+	}
+	if err != nil {
+		step.Error = err.Error()
+	}
+	r.Steps = append(r.Steps, step)
+	return step.Passed
+}
+
+func (r *SelfTestReport) finish() *SelfTestReport {
+	r.Passed = r.Err() == nil
+	return r
+}
+
+// selfTestImage returns a fixed payload large enough for the mock provider, which derives
+// the embedding from the bytes, so the same payload always matches itself
+func selfTestImage() []byte {
+	image := make([]byte, selfTestImageSize)
+	for i := range image {
+		image[i] = byte(i * 31)
+	}
+	return image
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+)
+
+// fakeSelfTestFaces keeps one face per external id in memory and fails the named operation
+type fakeSelfTestFaces struct {
+	faces    map[string][]byte
+	failOn   string
+	calls    []string
+	verified bool
+}
+
+func newFakeSelfTestFaces() *fakeSelfTestFaces {
+	return &fakeSelfTestFaces{faces: make(map[string][]byte), verified: true}
+}
+
+func (f *fakeSelfTestFaces) call(op string) error {
+	f.calls = append(f.calls, op)
+	if f.failOn == op {
+		return errors.New(op + " failed")
+	}
+	return nil
+}
+
+func (f *fakeSelfTestFaces) Register(ctx context.Context, tenantID uuid.UUID, externalID string, imageBytes []byte, metadata map[string]interface{}, settings domain.TenantSettings) (*domain.Face, error) {
+	if err := f.call("register"); err != nil {
+		return nil, err
+	}
+	f.faces[externalID] = imageBytes
+	return &domain.Face{TenantID: tenantID, ExternalID: externalID}, nil
+}
+
+func (f *fakeSelfTestFaces) Verify(ctx context.Context, tenantID uuid.UUID, externalID string, imageBytes []byte, settings domain.TenantSettings) (*domain.Verification, error) {
+	if err := f.call("verify"); err != nil {
+		return nil, err
+	}
+	if _, ok := f.faces[externalID]; !ok {
+		return nil, domain.ErrFaceNotFound
+	}
+	return &domain.Verification{Verified: f.verified, Confidence: 0.42}, nil
+}
+
+func (f *fakeSelfTestFaces) Search(ctx context.Context, tenant *domain.Tenant, imageBytes []byte, threshold float64, maxResults int, clientIP string) (*domain.SearchResult, error) {
+	if err := f.call("search"); err != nil {
+		return nil, err
+	}
+	if !tenant.GetSettings().SearchEnabled {
+		return nil, domain.ErrSearchNotEnabled
+	}
+	result := &domain.SearchResult{}
+	for externalID := range f.faces {
+		result.Matches = append(result.Matches, domain.SearchMatch{ExternalID: externalID, Similarity: 1})
+	}
+	return result, nil
+}
+
+func (f *fakeSelfTestFaces) Delete(ctx context.Context, tenantID uuid.UUID, externalID string) error {
+	if err := f.call("delete"); err != nil {
+		return err
+	}
+	delete(f.faces, externalID)
+	return nil
+}
+
+type fakeSelfTestTenants struct {
+	created   []uuid.UUID
+	deleted   []uuid.UUID
+	createErr error
+	deleteErr error
+}
+
+func (f *fakeSelfTestTenants) Create(ctx context.Context, tenant *domain.Tenant) error {
+	if f.createErr != nil {
+		return f.createErr
+	}
+	f.created = append(f.created, tenant.ID)
+	return nil
+}
+
+func (f *fakeSelfTestTenants) DeleteCascade(ctx context.Context, id uuid.UUID) (int64, error) {
+	f.deleted = append(f.deleted, id)
+	return 0, f.deleteErr
+}
+
+func stepNames(report *SelfTestReport) []string {
+	names := make([]string, 0, len(report.Steps))
+	for _, step := range report.Steps {
+		names = append(names, step.Name)
+	}
+	return names
+}
+
+func TestSelfTest_Run(t *testing.T) {
+	t.Run("full cycle passes and removes the throwaway tenant", func(t *testing.T) {
+		faces := newFakeSelfTestFaces()
+		tenants := &fakeSelfTestTenants{}
+
+		report := NewSelfTest(faces, tenants).Run(context.Background())
+
+		require.NoError(t, report.Err())
+		assert.True(t, report.Passed)
+		assert.Equal(t, []string{"create_tenant", "register", "verify", "search", "delete", "delete_tenant"}, stepNames(report))
+		assert.Equal(t, []uuid.UUID{report.TenantID}, tenants.created)
+		assert.Equal(t, []uuid.UUID{report.TenantID}, tenants.deleted)
+		assert.Empty(t, faces.faces)
+	})
+
+	t.Run("failed step stops the cycle but still removes the tenant", func(t *testing.T) {
+		faces := newFakeSelfTestFaces()
+		faces.failOn = "verify"
+		tenants := &fakeSelfTestTenants{}
+
+		report := NewSelfTest(faces, tenants).Run(context.Background())
+
+		assert.False(t, report.Passed)
+		assert.EqualError(t, report.Err(), "selftest verify: verify failed")
+		assert.Equal(t, []string{"register", "verify"}, faces.calls)
+		assert.Equal(t, []string{"create_tenant", "register", "verify", "delete_tenant"}, stepNames(report))
+		assert.Equal(t, []uuid.UUID{report.TenantID}, tenants.deleted)
+	})
+
+	t.Run("unverified face fails", func(t *testing.T) {
+		faces := newFakeSelfTestFaces()
+		faces.verified = false
+
+		report := NewSelfTest(faces, &fakeSelfTestTenants{}).Run(context.Background())
+
+		assert.False(t, report.Passed)
+		assert.ErrorContains(t, report.Err(), "selftest verify: registered face not verified")
+	})
+
+	t.Run("tenant creation failure skips the cycle", func(t *testing.T) {
+		faces := newFakeSelfTestFaces()
+		tenants := &fakeSelfTestTenants{createErr: errors.New("connection refused")}
+
+		report := NewSelfTest(faces, tenants).Run(context.Background())
+
+		assert.False(t, report.Passed)
+		assert.EqualError(t, report.Err(), "selftest create_tenant: connection refused")
+		assert.Empty(t, faces.calls)
+		assert.Empty(t, tenants.deleted)
+	})
+
+	t.Run("failed cleanup fails the run", func(t *testing.T) {
+		tenants := &fakeSelfTestTenants{deleteErr: errors.New("lock timeout")}
+
+		report := NewSelfTest(newFakeSelfTestFaces(), tenants).Run(context.Background())
+
+		assert.False(t, report.Passed)
+		assert.EqualError(t, report.Err(), "selftest delete_tenant: lock timeout")
+	})
+}
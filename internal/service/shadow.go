@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/provider"
+)
+
+// ShadowComparisonRepositoryInterface persists primary/shadow decision deltas
+type ShadowComparisonRepositoryInterface interface {
+	Create(ctx context.Context, comparison *domain.ShadowComparison) error
+}
+
+// shadowTimeout bounds a shadow run so a slow candidate provider cannot pile up goroutines
+const shadowTimeout = 10 * time.Second
+
+// WithShadowProvider runs a candidate provider alongside the primary on verify and search for
+// tenants with shadow_provider_enabled. The shadow never affects the response; its decision
+// is compared with the primary's and recorded through repo. It is also the alternate that
+// decides the sampled share of a tenant's provider_experiment.
+func (s *FaceService) WithShadowProvider(shadow provider.FaceProvider, repo ShadowComparisonRepositoryInterface) *FaceService {
+	s.shadowProvider = shadow
+	s.shadowRepo = repo
+	s.shadowModel = embeddingModelOf(shadow)
+	return s
+}
+
+// shadowEnabled reports whether the shadow provider runs for the request. Test-environment
+// requests are never shadowed, their decisions come from the test provider, and neither are
+// requests the shadow provider already decided as the alternate of a provider experiment.
+// Like every provider call, the shadow only sees data the tenant allows in this region.
+func (s *FaceService) shadowEnabled(ctx context.Context, settings domain.TenantSettings) bool {
+	return settings.Features().ShadowProvider && s.shadowProvider != nil && s.shadowRepo != nil &&
+		!domain.IsTestMode(ctx) && !alternateRouted(ctx) && s.inDataRegion(settings)
+}
+
+// shadowFingerprint identifies embeddings produced by the shadow provider
+func (s *FaceService) shadowFingerprint(embedding []float64) domain.EmbeddingFingerprint {
+	if s.shadowModel == "" {
+		return domain.EmbeddingFingerprint{}
+	}
+	return domain.NewEmbeddingFingerprint(s.shadowModel, len(embedding))
+}
+
+// shadowVerify repeats a verification with the shadow provider against the same stored face
+func (s *FaceService) shadowVerify(storedFace *domain.Face, imageBytes []byte, threshold float64, primary *domain.Verification) {
+	// Copy the primary outcome so the goroutine never reads the returned verification
+	externalID, score := primary.ExternalID, primary.Confidence
+	comparison := &domain.ShadowComparison{
+		TenantID:         primary.TenantID,
+		Operation:        domain.ShadowOperationVerify,
+		ExternalID:       &externalID,
+		PrimaryMatch:     primary.Verified,
+		PrimaryScore:     &score,
+		PrimaryLatencyMs: primary.LatencyMs,
+	}
+
+	s.runShadow(comparison, func(ctx context.Context) error {
+		_, embedding, err := s.shadowProvider.IndexFace(ctx, imageBytes)
+		if err != nil {
+			return err
+		}
+
+		// The stored embedding belongs to the primary model; a different model cannot be compared
+		if !s.shadowFingerprint(embedding).Compatible(storedFace.Fingerprint()) {
+			comparison.Skip(domain.ShadowSkipIncompatibleModel)
+			return nil
+		}
+
+		similarity, err := s.shadowProvider.CompareFaces(ctx, storedFace.Embedding, embedding)
+		if err != nil {
+			return err
+		}
+
+		match := similarity >= threshold
+		comparison.ShadowMatch = &match
+		comparison.ShadowScore = &similarity
+		return nil
+	})
+}
+
+// shadowSearch repeats a search with the shadow provider's embedding of the same image.
+// Only faces stored with a compatible embedding model can match, so a shadow with another
+// model would never match; its runs are recorded as skipped instead of as mismatches.
+func (s *FaceService) shadowSearch(tenantID uuid.UUID, imageBytes []byte, threshold float64, maxResults int, primary *domain.SearchResult) {
+	comparison := &domain.ShadowComparison{
+		TenantID:         tenantID,
+		Operation:        domain.ShadowOperationSearch,
+		PrimaryMatch:     len(primary.Matches) > 0,
+		PrimaryLatencyMs: primary.LatencyMs,
+	}
+	if len(primary.Matches) > 0 {
+		// Copy the top match so the goroutine never reads the response's matches
+		top := primary.Matches[0]
+		comparison.PrimaryTopMatch = &top.ExternalID
+		comparison.PrimaryScore = &top.Similarity
+	}
+
+	s.runShadow(comparison, func(ctx context.Context) error {
+		if !s.alternateCanSearch() {
+			comparison.Skip(domain.ShadowSkipIncompatibleModel)
+			return nil
+		}
+
+		analysis, err := s.shadowProvider.AnalyzeFace(ctx, imageBytes)
+		if err != nil {
+			return err
+		}
+		if analysis.FaceCount == 0 {
+			return domain.ErrNoFaceDetected
+		}
+
+		matches, err := s.faceRepo.SearchByEmbedding(ctx, tenantID, analysis.Embedding, s.shadowFingerprint(analysis.Embedding), threshold, maxResults)
+		if err != nil {
+			return err
+		}
+
+		match := len(matches) > 0
+		comparison.ShadowMatch = &match
+		if match {
+			comparison.ShadowTopMatch = &matches[0].ExternalID
+			comparison.ShadowScore = &matches[0].Similarity
+		}
+		return nil
+	})
+}
+
+// runShadow executes a shadow run asynchronously and records the comparison (best-effort with panic recovery)
+func (s *FaceService) runShadow(comparison *domain.ShadowComparison, run func(ctx context.Context) error) {
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				slog.Error("panic in shadow comparison", "panic", r, "tenant_id", comparison.TenantID, "operation", comparison.Operation)
+			}
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), shadowTimeout)
+		defer cancel()
+
+		start := time.Now()
+		if err := run(ctx); err != nil {
+			msg := err.Error()
+			comparison.ShadowError = &msg
+		}
+		comparison.ShadowLatencyMs = time.Since(start).Milliseconds()
+
+		saveCtx, saveCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer saveCancel()
+		if err := s.shadowRepo.Create(saveCtx, comparison); err != nil {
+			slog.Warn("failed to record shadow comparison", "error", err, "tenant_id", comparison.TenantID)
+		}
+	}()
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/provider"
+)
+
+// recordingShadowRepository hands recorded comparisons to the test over a channel
+type recordingShadowRepository struct {
+	recorded chan *domain.ShadowComparison
+}
+
+func newRecordingShadowRepository() *recordingShadowRepository {
+	return &recordingShadowRepository{recorded: make(chan *domain.ShadowComparison, 1)}
+}
+
+func (r *recordingShadowRepository) Create(_ context.Context, comparison *domain.ShadowComparison) error {
+	r.recorded <- comparison
+	return nil
+}
+
+func (r *recordingShadowRepository) wait(t *testing.T) *domain.ShadowComparison {
+	t.Helper()
+	select {
+	case c := <-r.recorded:
+		return c
+	case <-time.After(2 * time.Second):
+		t.Fatal("shadow comparison was not recorded")
+		return nil
+	}
+}
+
+func TestFaceService_ShadowVerify(t *testing.T) {
+	tenantID := uuid.New()
+	storedEmbedding := make([]float64, 512)
+	settings := domain.DefaultTenantSettings()
+	settings.ShadowProviderEnabled = true
+
+	setupPrimary := func() (*MockFaceRepository, *MockVerificationRepository, *MockFaceProvider) {
+		faceRepo := &MockFaceRepository{}
+		verificationRepo := &MockVerificationRepository{}
+		primary := &MockFaceProvider{}
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(&domain.Face{
+			ID:        uuid.New(),
+			Embedding: storedEmbedding,
+		}, nil)
+		primary.On("DetectFaces", mock.Anything, mock.Anything).Return([]provider.DetectedFace{{Confidence: 0.99}}, nil)
+		primary.On("IndexFace", mock.Anything, mock.Anything).Return("face-id", storedEmbedding, nil)
+		primary.On("CompareFaces", mock.Anything, mock.Anything, mock.Anything).Return(0.92, nil)
+		verificationRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		return faceRepo, verificationRepo, primary
+	}
+
+	t.Run("records a decision delta without changing the primary result", func(t *testing.T) {
+		faceRepo, verificationRepo, primary := setupPrimary()
+		shadow := &MockFaceProvider{}
+		shadow.On("IndexFace", mock.Anything, mock.Anything).Return("", storedEmbedding, nil)
+		shadow.On("CompareFaces", mock.Anything, mock.Anything, mock.Anything).Return(0.40, nil)
+		repo := newRecordingShadowRepository()
+
+		svc := NewFaceService(faceRepo, verificationRepo, &MockSearchAuditRepository{}, primary, &MockRateLimiter{}).
+			WithShadowProvider(shadow, repo)
+
+		result, err := svc.Verify(context.Background(), tenantID, "user_001", make([]byte, 5000), settings)
+
+		require.NoError(t, err)
+		assert.True(t, result.Verified)
+		assert.Equal(t, 0.92, result.Confidence)
+
+		comparison := repo.wait(t)
+		assert.Equal(t, domain.ShadowOperationVerify, comparison.Operation)
+		assert.Equal(t, "user_001", *comparison.ExternalID)
+		assert.True(t, comparison.PrimaryMatch)
+		assert.Equal(t, 0.92, *comparison.PrimaryScore)
+		require.NotNil(t, comparison.ShadowMatch)
+		assert.False(t, *comparison.ShadowMatch)
+		assert.Equal(t, 0.40, *comparison.ShadowScore)
+		assert.True(t, comparison.DecisionDiffers())
+	})
+
+	t.Run("shadow failure is recorded and does not fail the request", func(t *testing.T) {
+		faceRepo, verificationRepo, primary := setupPrimary()
+		shadow := &MockFaceProvider{}
+		shadow.On("IndexFace", mock.Anything, mock.Anything).Return("", []float64(nil), errors.New("shadow unavailable"))
+		repo := newRecordingShadowRepository()
+
+		svc := NewFaceService(faceRepo, verificationRepo, &MockSearchAuditRepository{}, primary, &MockRateLimiter{}).
+			WithShadowProvider(shadow, repo)
+
+		result, err := svc.Verify(context.Background(), tenantID, "user_001", make([]byte, 5000), settings)
+
+		require.NoError(t, err)
+		assert.True(t, result.Verified)
+
+		comparison := repo.wait(t)
+		assert.Nil(t, comparison.ShadowMatch)
+		require.NotNil(t, comparison.ShadowError)
+		assert.Contains(t, *comparison.ShadowError, "shadow unavailable")
+		assert.False(t, comparison.DecisionDiffers())
+	})
+
+	t.Run("a shadow with another embedding model is recorded as skipped", func(t *testing.T) {
+		faceRepo, verificationRepo, primary := setupPrimary()
+		faceRepo.ExpectedCalls = nil
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(&domain.Face{
+			ID:               uuid.New(),
+			Embedding:        storedEmbedding,
+			EmbeddingModel:   "deepface/Facenet512",
+			EmbeddingVersion: "512d",
+		}, nil)
+		shadow := &MockFaceProvider{}
+		shadow.On("IndexFace", mock.Anything, mock.Anything).Return("", storedEmbedding, nil)
+		repo := newRecordingShadowRepository()
+
+		svc := NewFaceService(faceRepo, verificationRepo, &MockSearchAuditRepository{}, primary, &MockRateLimiter{}).
+			WithShadowProvider(shadow, repo).
+			WithEmbeddingModel("deepface/Facenet512")
+		svc.shadowModel = "deepface/ArcFace"
+
+		result, err := svc.Verify(context.Background(), tenantID, "user_001", make([]byte, 5000), settings)
+
+		require.NoError(t, err)
+		assert.True(t, result.Verified)
+
+		comparison := repo.wait(t)
+		assert.Nil(t, comparison.ShadowMatch)
+		assert.Nil(t, comparison.ShadowError)
+		require.NotNil(t, comparison.ShadowSkipped)
+		assert.Equal(t, domain.ShadowSkipIncompatibleModel, *comparison.ShadowSkipped)
+		assert.False(t, comparison.DecisionDiffers())
+		shadow.AssertNotCalled(t, "CompareFaces", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("tenants without opt-in never reach the shadow provider", func(t *testing.T) {
+		faceRepo, verificationRepo, primary := setupPrimary()
+		shadow := &MockFaceProvider{}
+		repo := newRecordingShadowRepository()
+
+		svc := NewFaceService(faceRepo, verificationRepo, &MockSearchAuditRepository{}, primary, &MockRateLimiter{}).
+			WithShadowProvider(shadow, repo)
+
+		_, err := svc.Verify(context.Background(), tenantID, "user_001", make([]byte, 5000), domain.DefaultTenantSettings())
+
+		require.NoError(t, err)
+		select {
+		case <-repo.recorded:
+			t.Fatal("shadow comparison recorded for a tenant without opt-in")
+		case <-time.After(50 * time.Millisecond):
+		}
+		shadow.AssertNotCalled(t, "IndexFace", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFaceService_ShadowSearch(t *testing.T) {
+	tenant := &domain.Tenant{
+		ID:       uuid.New(),
+		Settings: map[string]interface{}{"search_enabled": true, "shadow_provider_enabled": true},
+	}
+	primaryEmbedding := make([]float64, 512)
+	shadowEmbedding := make([]float64, 512)
+	shadowEmbedding[0] = 1
+
+	faceRepo := &MockFaceRepository{}
+	rateLimiter := &MockRateLimiter{}
+	auditRepo := &MockSearchAuditRepository{}
+	primary := &MockFaceProvider{}
+	shadow := &MockFaceProvider{}
+	repo := newRecordingShadowRepository()
+
+	rateLimiter.On("CheckSearchLimit", mock.Anything, tenant.ID, mock.Anything).Return(nil)
+	auditRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	primary.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{Embedding: primaryEmbedding, FaceCount: 1}, nil)
+	shadow.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{Embedding: shadowEmbedding, FaceCount: 1}, nil)
+	faceRepo.On("SearchByEmbedding", mock.Anything, tenant.ID, primaryEmbedding, mock.Anything, mock.Anything, mock.Anything).
+		Return([]domain.SearchMatch{{ExternalID: "user_001", Similarity: 0.95}}, nil)
+	faceRepo.On("SearchByEmbedding", mock.Anything, tenant.ID, shadowEmbedding, mock.Anything, mock.Anything, mock.Anything).
+		Return([]domain.SearchMatch{{ExternalID: "user_002", Similarity: 0.88}}, nil)
+
+	svc := NewFaceService(faceRepo, &MockVerificationRepository{}, auditRepo, primary, rateLimiter).
+		WithShadowProvider(shadow, repo)
+
+	result, err := svc.Search(context.Background(), tenant, make([]byte, 5000), 0, 0, "127.0.0.1")
+
+	require.NoError(t, err)
+	require.Len(t, result.Matches, 1)
+	assert.Equal(t, "user_001", result.Matches[0].ExternalID)
+
+	comparison := repo.wait(t)
+	assert.Equal(t, domain.ShadowOperationSearch, comparison.Operation)
+	assert.Equal(t, "user_001", *comparison.PrimaryTopMatch)
+	assert.Equal(t, "user_002", *comparison.ShadowTopMatch)
+	assert.True(t, comparison.DecisionDiffers())
+}
+
+func TestFaceService_ShadowSearch_IncompatibleModel(t *testing.T) {
+	tenant := &domain.Tenant{
+		ID:       uuid.New(),
+		Settings: map[string]interface{}{"search_enabled": true, "shadow_provider_enabled": true},
+	}
+	embedding := make([]float64, 512)
+	faceRepo := &MockFaceRepository{}
+	rateLimiter := &MockRateLimiter{}
+	auditRepo := &MockSearchAuditRepository{}
+	primary := &MockFaceProvider{}
+	shadow := &MockFaceProvider{}
+	repo := newRecordingShadowRepository()
+
+	rateLimiter.On("CheckSearchLimit", mock.Anything, tenant.ID, mock.Anything).Return(nil)
+	auditRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	primary.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{Embedding: embedding, FaceCount: 1}, nil)
+	faceRepo.On("SearchByEmbedding", mock.Anything, tenant.ID, embedding, mock.Anything, mock.Anything, mock.Anything).
+		Return([]domain.SearchMatch{{ExternalID: "user_001", Similarity: 0.95}}, nil)
+
+	svc := NewFaceService(faceRepo, &MockVerificationRepository{}, auditRepo, primary, rateLimiter).
+		WithShadowProvider(shadow, repo).
+		WithEmbeddingModel("deepface/Facenet512")
+	svc.shadowModel = "deepface/ArcFace"
+
+	result, err := svc.Search(context.Background(), tenant, make([]byte, 5000), 0, 0, "127.0.0.1")
+
+	require.NoError(t, err)
+	require.Len(t, result.Matches, 1)
+
+	// The enrolled faces belong to the primary model, the shadow could never match them
+	comparison := repo.wait(t)
+	assert.Nil(t, comparison.ShadowMatch)
+	require.NotNil(t, comparison.ShadowSkipped)
+	assert.Equal(t, domain.ShadowSkipIncompatibleModel, *comparison.ShadowSkipped)
+	assert.False(t, comparison.DecisionDiffers())
+	shadow.AssertNotCalled(t, "AnalyzeFace", mock.Anything, mock.Anything)
+	faceRepo.AssertNumberOfCalls(t, "SearchByEmbedding", 1)
+}
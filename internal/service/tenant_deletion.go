@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/provider/rekognition"
+)
+
+// TenantDeletionRepositoryInterface erases a tenant and everything stored for it
+type TenantDeletionRepositoryInterface interface {
+	DeleteCascade(ctx context.Context, id uuid.UUID) (facesDeleted int64, err error)
+}
+
+// CollectionDeleter removes the provider-side storage of a tenant (e.g. a Rekognition collection)
+type CollectionDeleter interface {
+	DeleteCollection(ctx context.Context, tenantID string) error
+}
+
+// TenantDeletion reports what was erased when a tenant was deleted
+type TenantDeletion struct {
+	TenantID     uuid.UUID `json:"tenant_id"`
+	FacesDeleted int64     `json:"faces_deleted"`
+	// CollectionDeleted is false when the provider collection is left behind: purging is
+	// disabled, the provider keeps no per-tenant storage, or the provider call failed
+	CollectionDeleted bool   `json:"collection_deleted"`
+	CollectionError   string `json:"collection_error,omitempty"`
+}
+
+// TenantDeleter erases a tenant from the database and, when configured, from the face provider
+type TenantDeleter struct {
+	repo        TenantDeletionRepositoryInterface
+	collections CollectionDeleter // optional, nil keeps the provider collection
+	logger      *slog.Logger
+}
+
+func NewTenantDeleter(repo TenantDeletionRepositoryInterface, collections CollectionDeleter, logger *slog.Logger) *TenantDeleter {
+	return &TenantDeleter{
+		repo:        repo,
+		collections: collections,
+		logger:      logger,
+	}
+}
+
+// Delete removes the tenant, its faces and every other tenant row in one transaction, then
+// deletes the provider collection. The collection is best-effort: once the tenant is gone it
+// cannot be rolled back, so a provider failure is reported in the result instead of failing
+// the deletion. A collection that no longer exists counts as deleted.
+func (d *TenantDeleter) Delete(ctx context.Context, tenantID uuid.UUID) (*TenantDeletion, error) {
+	facesDeleted, err := d.repo.DeleteCascade(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TenantDeletion{
+		TenantID:     tenantID,
+		FacesDeleted: facesDeleted,
+	}
+
+	if d.collections == nil {
+		return result, nil
+	}
+
+	err = d.collections.DeleteCollection(ctx, tenantID.String())
+	switch {
+	case err == nil, errors.Is(err, rekognition.ErrCollectionNotFound):
+		result.CollectionDeleted = true
+	default:
+		result.CollectionError = err.Error()
+		d.logger.Error("failed to delete provider collection of deleted tenant",
+			"error", err,
+			"tenant_id", tenantID,
+		)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/provider/rekognition"
+)
+
+type fakeTenantDeletionRepository struct {
+	deleted      []uuid.UUID
+	facesDeleted int64
+	err          error
+}
+
+func (f *fakeTenantDeletionRepository) DeleteCascade(ctx context.Context, id uuid.UUID) (int64, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+	f.deleted = append(f.deleted, id)
+	return f.facesDeleted, nil
+}
+
+type fakeCollectionDeleter struct {
+	deleted []string
+	err     error
+}
+
+func (f *fakeCollectionDeleter) DeleteCollection(ctx context.Context, tenantID string) error {
+	f.deleted = append(f.deleted, tenantID)
+	return f.err
+}
+
+func TestTenantDeleter_Delete(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tenantID := uuid.New()
+
+	t.Run("erases the database rows and the provider collection", func(t *testing.T) {
+		repo := &fakeTenantDeletionRepository{facesDeleted: 42}
+		collections := &fakeCollectionDeleter{}
+
+		result, err := NewTenantDeleter(repo, collections, logger).Delete(context.Background(), tenantID)
+
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{tenantID}, repo.deleted)
+		assert.Equal(t, []string{tenantID.String()}, collections.deleted)
+		assert.Equal(t, int64(42), result.FacesDeleted)
+		assert.True(t, result.CollectionDeleted)
+		assert.Empty(t, result.CollectionError)
+	})
+
+	t.Run("missing collection counts as deleted", func(t *testing.T) {
+		collections := &fakeCollectionDeleter{err: fmt.Errorf("tenant %s: %w", tenantID, rekognition.ErrCollectionNotFound)}
+
+		result, err := NewTenantDeleter(&fakeTenantDeletionRepository{}, collections, logger).Delete(context.Background(), tenantID)
+
+		require.NoError(t, err)
+		assert.True(t, result.CollectionDeleted)
+	})
+
+	t.Run("provider failure is reported without failing the deletion", func(t *testing.T) {
+		repo := &fakeTenantDeletionRepository{facesDeleted: 3}
+		collections := &fakeCollectionDeleter{err: errors.New("throttled")}
+
+		result, err := NewTenantDeleter(repo, collections, logger).Delete(context.Background(), tenantID)
+
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{tenantID}, repo.deleted)
+		assert.False(t, result.CollectionDeleted)
+		assert.Equal(t, "throttled", result.CollectionError)
+	})
+
+	t.Run("database failure leaves the collection untouched", func(t *testing.T) {
+		collections := &fakeCollectionDeleter{}
+
+		_, err := NewTenantDeleter(&fakeTenantDeletionRepository{err: domain.ErrTenantNotFound}, collections, logger).Delete(context.Background(), tenantID)
+
+		assert.ErrorIs(t, err, domain.ErrTenantNotFound)
+		assert.Empty(t, collections.deleted)
+	})
+
+	t.Run("purging disabled keeps the collection", func(t *testing.T) {
+		repo := &fakeTenantDeletionRepository{}
+
+		result, err := NewTenantDeleter(repo, nil, logger).Delete(context.Background(), tenantID)
+
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{tenantID}, repo.deleted)
+		assert.False(t, result.CollectionDeleted)
+	})
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/provider"
+)
+
+// WithTestProvider routes requests made with test-environment API keys to testProvider,
+// normally the deterministic mock provider, whatever provider serves live traffic
+func (s *FaceService) WithTestProvider(testProvider provider.FaceProvider) *FaceService {
+	s.testProvider = testProvider
+	s.testModel = embeddingModelOf(testProvider)
+	return s
+}
+
+// testMode reports whether ctx serves a test-environment API key routed to the test provider
+func (s *FaceService) testMode(ctx context.Context) bool {
+	return s.testProvider != nil && domain.IsTestMode(ctx)
+}
+
+// providerFor returns the provider for the request in ctx
+func (s *FaceService) providerFor(ctx context.Context) provider.FaceProvider {
+	if s.testMode(ctx) {
+		return s.testProvider
+	}
+	if alternateRouted(ctx) {
+		return s.shadowProvider
+	}
+	return s.provider
+}
+
+// modelFor returns the embedding model of the provider for the request in ctx
+func (s *FaceService) modelFor(ctx context.Context) string {
+	if s.testMode(ctx) {
+		return s.testModel
+	}
+	if alternateRouted(ctx) {
+		return s.shadowModel
+	}
+	return s.embeddingModel
+}
+
+// providerName names the provider deciding the request in ctx: the provider part of its
+// embedding model, e.g. "deepface" for "deepface/Facenet512", or the name it was configured
+// with for providers without a model such as Rekognition; empty when neither is known
+func (s *FaceService) providerName(ctx context.Context) string {
+	if name, _, _ := strings.Cut(s.modelFor(ctx), "/"); name != "" {
+		return name
+	}
+	if namer, ok := s.providerFor(ctx).(provider.Namer); ok {
+		return namer.ProviderName()
+	}
+	return ""
+}
+
+// sameEnvironment reports whether face belongs to the environment of the request in ctx.
+// Test keys never see or replace live faces, and live keys never match test faces.
+func sameEnvironment(ctx context.Context, face *domain.Face) bool {
+	return face.IsTest == domain.IsTestMode(ctx)
+}
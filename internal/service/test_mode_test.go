@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/provider"
+)
+
+func TestFaceService_TestMode(t *testing.T) {
+	tenantID := uuid.New()
+	settings := domain.DefaultTenantSettings()
+	testCtx := domain.WithTestMode(context.Background())
+	analysis := &provider.FaceAnalysis{
+		Embedding:    []float64{0.1, 0.2, 0.3},
+		QualityScore: 0.95,
+		FaceCount:    1,
+	}
+
+	t.Run("test key registers through the test provider and tags the face", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		liveProvider := &MockFaceProvider{}
+		testProvider := &MockFaceProvider{}
+
+		testProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(analysis, nil)
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user-1").Return(nil, domain.ErrFaceNotFound)
+		faceRepo.On("Create", mock.Anything, mock.MatchedBy(func(f *domain.Face) bool {
+			return f.IsTest
+		})).Return(nil)
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, nil, liveProvider, nil).
+			WithTestProvider(testProvider)
+
+		face, err := svc.Register(testCtx, tenantID, "user-1", []byte("image"), nil, settings)
+
+		require.NoError(t, err)
+		assert.True(t, face.IsTest)
+		testProvider.AssertExpectations(t)
+		liveProvider.AssertNotCalled(t, "AnalyzeFace", mock.Anything, mock.Anything)
+		faceRepo.AssertExpectations(t)
+	})
+
+	t.Run("live key keeps using the configured provider", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		liveProvider := &MockFaceProvider{}
+		testProvider := &MockFaceProvider{}
+
+		liveProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(analysis, nil)
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user-1").Return(nil, domain.ErrFaceNotFound)
+		faceRepo.On("Create", mock.Anything, mock.MatchedBy(func(f *domain.Face) bool {
+			return !f.IsTest
+		})).Return(nil)
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, nil, liveProvider, nil).
+			WithTestProvider(testProvider)
+
+		_, err := svc.Register(context.Background(), tenantID, "user-1", []byte("image"), nil, settings)
+
+		require.NoError(t, err)
+		liveProvider.AssertExpectations(t)
+		testProvider.AssertNotCalled(t, "AnalyzeFace", mock.Anything, mock.Anything)
+	})
+
+	t.Run("test key cannot replace a live enrollment", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		testProvider := &MockFaceProvider{}
+
+		testProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(analysis, nil)
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user-1").
+			Return(&domain.Face{ID: uuid.New(), TenantID: tenantID, ExternalID: "user-1"}, nil)
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, nil, &MockFaceProvider{}, nil).
+			WithTestProvider(testProvider)
+
+		_, err := svc.Register(testCtx, tenantID, "user-1", []byte("image"), nil, settings)
+
+		var appErr *domain.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, domain.ErrFaceExists.Code, appErr.Code)
+		faceRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("test key does not see live faces on verify", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		testProvider := &MockFaceProvider{}
+
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user-1").
+			Return(&domain.Face{ID: uuid.New(), TenantID: tenantID, ExternalID: "user-1"}, nil)
+
+		svc := NewFaceService(faceRepo, &MockVerificationRepository{}, nil, &MockFaceProvider{}, nil).
+			WithTestProvider(testProvider)
+
+		_, err := svc.Verify(testCtx, tenantID, "user-1", []byte("image"), settings)
+
+		assert.ErrorIs(t, err, domain.ErrFaceNotFound)
+		testProvider.AssertNotCalled(t, "DetectFaces", mock.Anything, mock.Anything)
+	})
+
+	t.Run("test key verifies test faces and tags the verification", func(t *testing.T) {
+		faceRepo := &MockFaceRepository{}
+		verificationRepo := &MockVerificationRepository{}
+		liveProvider := &MockFaceProvider{}
+		testProvider := &MockFaceProvider{}
+		stored := &domain.Face{ID: uuid.New(), TenantID: tenantID, ExternalID: "user-1", Embedding: []float64{0.1, 0.2, 0.3}, IsTest: true}
+
+		faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user-1").Return(stored, nil)
+		testProvider.On("DetectFaces", mock.Anything, mock.Anything).Return([]provider.DetectedFace{{Confidence: 0.99, QualityScore: 0.95}}, nil)
+		testProvider.On("IndexFace", mock.Anything, mock.Anything).Return("face", []float64{0.1, 0.2, 0.3}, nil)
+		testProvider.On("CompareFaces", mock.Anything, stored.Embedding, mock.Anything).Return(0.97, nil)
+		verificationRepo.On("Create", mock.Anything, mock.MatchedBy(func(v *domain.Verification) bool {
+			return v.IsTest
+		})).Return(nil)
+
+		svc := NewFaceService(faceRepo, verificationRepo, nil, liveProvider, nil).
+			WithTestProvider(testProvider)
+
+		verification, err := svc.Verify(testCtx, tenantID, "user-1", []byte("image"), settings)
+
+		require.NoError(t, err)
+		assert.True(t, verification.Verified)
+		assert.True(t, verification.IsTest)
+		testProvider.AssertExpectations(t)
+		verificationRepo.AssertExpectations(t)
+		liveProvider.AssertNotCalled(t, "CompareFaces", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("verification records the deciding provider", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			ctx   context.Context
+			model string
+			want  string
+		}{
+			{name: "live key", ctx: context.Background(), model: "deepface/Facenet512", want: "deepface"},
+			{name: "test key", ctx: testCtx, model: "deepface/Facenet512", want: "mock"},
+			// Rekognition advertises no embedding model, its configured name is used
+			{name: "live provider without a model", ctx: context.Background(), want: "rekognition"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				faceRepo := &MockFaceRepository{}
+				verificationRepo := &MockVerificationRepository{}
+				faceProvider := &MockFaceProvider{}
+				stored := &domain.Face{ID: uuid.New(), TenantID: tenantID, ExternalID: "user-1", Embedding: []float64{0.1, 0.2, 0.3}, IsTest: domain.IsTestMode(tt.ctx)}
+
+				faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user-1").Return(stored, nil)
+				faceProvider.On("DetectFaces", mock.Anything, mock.Anything).Return([]provider.DetectedFace{{Confidence: 0.99, QualityScore: 0.95}}, nil)
+				faceProvider.On("IndexFace", mock.Anything, mock.Anything).Return("face", []float64{0.1, 0.2, 0.3}, nil)
+				faceProvider.On("CompareFaces", mock.Anything, mock.Anything, mock.Anything).Return(0.97, nil)
+				verificationRepo.On("Create", mock.Anything, mock.MatchedBy(func(v *domain.Verification) bool {
+					return v.Provider == tt.want
+				})).Return(nil)
+
+				svc := NewFaceService(faceRepo, verificationRepo, nil, provider.WithErrorAudit(faceProvider, "rekognition", nil), nil).
+					WithEmbeddingModel(tt.model).
+					WithTestProvider(faceProvider)
+				svc.testModel = "mock/sha256"
+
+				verification, err := svc.Verify(tt.ctx, tenantID, "user-1", []byte("image"), settings)
+
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, verification.Provider)
+				verificationRepo.AssertExpectations(t)
+			})
+		}
+	})
+}
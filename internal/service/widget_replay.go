@@ -0,0 +1,109 @@
+package service
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/imaging"
+)
+
+// replayFrame is what the replay detection keeps of one liveness frame
+type replayFrame struct {
+	score float64
+	// fingerprint is nil when the image format cannot be decoded, which skips the frame diff check
+	fingerprint []byte
+}
+
+// replaySession holds the last liveness frames of a widget session
+type replaySession struct {
+	frames    []replayFrame
+	suspected bool
+	expiresAt time.Time
+}
+
+// replayTracker remembers the recent liveness frames of each widget session to spot replays.
+// State is per instance: frames of one session spread across instances are judged separately.
+type replayTracker struct {
+	mu       sync.Mutex
+	sessions map[uuid.UUID]*replaySession
+}
+
+func newReplayTracker() *replayTracker {
+	return &replayTracker{sessions: make(map[uuid.UUID]*replaySession)}
+}
+
+// observe records a frame of the session and reports whether the session is suspected of a
+// replay. A suspected session stays suspected until it expires.
+func (t *replayTracker) observe(session *domain.WidgetSession, frame replayFrame, cfg domain.ReplayDetectionSettings, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for id, s := range t.sessions {
+		if now.After(s.expiresAt) {
+			delete(t.sessions, id)
+		}
+	}
+
+	s, ok := t.sessions[session.ID]
+	if !ok {
+		s = &replaySession{expiresAt: session.ExpiresAt}
+		t.sessions[session.ID] = s
+	}
+	if s.suspected {
+		return true
+	}
+
+	s.frames = append(s.frames, frame)
+	if len(s.frames) > cfg.MinFrames {
+		s.frames = s.frames[len(s.frames)-cfg.MinFrames:]
+	}
+	s.suspected = replaySuspected(s.frames, cfg)
+	return s.suspected
+}
+
+// suspected reports whether the session was flagged by observe
+func (t *replayTracker) suspected(sessionID uuid.UUID) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, ok := t.sessions[sessionID]
+	return ok && s.suspected
+}
+
+// replaySuspected applies the checks of cfg to the last cfg.MinFrames frames
+func replaySuspected(frames []replayFrame, cfg domain.ReplayDetectionSettings) bool {
+	if cfg.MinFrames < 2 || len(frames) < cfg.MinFrames {
+		return false
+	}
+	frames = frames[len(frames)-cfg.MinFrames:]
+
+	if cfg.MinScoreStdDev > 0 {
+		var sum, sumSq float64
+		for _, f := range frames {
+			sum += f.score
+			sumSq += f.score * f.score
+		}
+		n := float64(len(frames))
+		mean := sum / n
+		if math.Sqrt(max(sumSq/n-mean*mean, 0)) < cfg.MinScoreStdDev {
+			return true
+		}
+	}
+
+	if cfg.MaxFrameDiff > 0 {
+		identical := true
+		for i := 1; i < len(frames) && identical; i++ {
+			prev, cur := frames[i-1].fingerprint, frames[i].fingerprint
+			identical = prev != nil && cur != nil && imaging.FrameDiff(prev, cur) < cfg.MaxFrameDiff
+		}
+		if identical {
+			return true
+		}
+	}
+
+	return false
+}
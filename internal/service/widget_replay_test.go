@@ -0,0 +1,201 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/provider"
+)
+
+// encodeLivenessFrame draws a face-like oval shifted by offset pixels on a shaded background,
+// with sensor noise from seed, so frames with the same offset only differ by noise
+func encodeLivenessFrame(t *testing.T, offset int, seed int64) []byte {
+	t.Helper()
+	const width, height = 320, 240
+	rng := rand.New(rand.NewSource(seed))
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	cx, cy := float64(width/2+offset), float64(height/2)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			dx, dy := (float64(x)-cx)/50, (float64(y)-cy)/70
+			v := 50 + 60*float64(y)/height
+			if dx*dx+dy*dy < 1 {
+				v = 190 - 30*dx
+			}
+			v += rng.Float64()*8 - 4
+			img.Set(x, y, color.RGBA{R: uint8(v), G: uint8(v * 0.85), B: uint8(v * 0.7), A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, nil))
+	return buf.Bytes()
+}
+
+func TestWidgetService_ValidateLiveness_ReplayDetection(t *testing.T) {
+	enabled := map[string]interface{}{
+		"replay_detection": map[string]interface{}{"enabled": true, "min_frames": 3},
+	}
+
+	tests := []struct {
+		name         string
+		settings     map[string]interface{}
+		offsets      []int
+		scores       []float64
+		wantReplayAt int // index of the first frame rejected, -1 when all pass
+	}{
+		{
+			name:         "near-identical frames are flagged",
+			settings:     enabled,
+			offsets:      []int{0, 0, 0, 0},
+			scores:       []float64{0.91, 0.96, 0.93, 0.95},
+			wantReplayAt: 2,
+		},
+		{
+			name:         "constant liveness scores are flagged",
+			settings:     enabled,
+			offsets:      []int{-40, 0, 40},
+			scores:       []float64{0.97, 0.97, 0.97},
+			wantReplayAt: 2,
+		},
+		{
+			name:         "varied frames pass",
+			settings:     enabled,
+			offsets:      []int{-40, -10, 20, 50, 10},
+			scores:       []float64{0.91, 0.96, 0.93, 0.95, 0.92},
+			wantReplayAt: -1,
+		},
+		{
+			name:         "still frames pass while a moving one breaks the streak",
+			settings:     enabled,
+			offsets:      []int{0, 0, 40, 40, 0},
+			scores:       []float64{0.91, 0.96, 0.93, 0.95, 0.92},
+			wantReplayAt: -1,
+		},
+		{
+			name: "frame check disabled with a zero threshold",
+			settings: map[string]interface{}{
+				"replay_detection": map[string]interface{}{"enabled": true, "max_frame_diff": 0},
+			},
+			offsets:      []int{0, 0, 0},
+			scores:       []float64{0.91, 0.96, 0.93},
+			wantReplayAt: -1,
+		},
+		{
+			name:         "off by default",
+			settings:     nil,
+			offsets:      []int{0, 0, 0, 0},
+			scores:       []float64{0.97, 0.97, 0.97, 0.97},
+			wantReplayAt: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionRepo := &MockWidgetSessionRepository{}
+			tenantRepo := &MockTenantRepository{}
+			faceProvider := &MockFaceProvider{}
+			tenantID := uuid.New()
+			sessionID := uuid.New()
+
+			sessionRepo.On("GetByID", mock.Anything, sessionID).Return(&domain.WidgetSession{
+				ID:        sessionID,
+				TenantID:  tenantID,
+				ExpiresAt: time.Now().Add(time.Minute),
+			}, nil)
+			tenantRepo.On("GetByID", mock.Anything, tenantID).Return(&domain.Tenant{
+				ID:       tenantID,
+				Settings: tt.settings,
+			}, nil)
+			for _, score := range tt.scores {
+				faceProvider.On("CheckLiveness", mock.Anything, mock.Anything, mock.Anything).
+					Return(&provider.LivenessResult{IsLive: true, Confidence: score}, nil).Once()
+			}
+
+			faceService := NewFaceService(&MockFaceRepository{}, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+			svc := NewWidgetService(sessionRepo, tenantRepo, faceService)
+
+			for i, offset := range tt.offsets {
+				result, err := svc.ValidateLiveness(context.Background(), sessionID, encodeLivenessFrame(t, offset, int64(i)))
+
+				if tt.wantReplayAt >= 0 && i >= tt.wantReplayAt {
+					assert.ErrorIs(t, err, domain.ErrReplaySuspected, "frame %d", i)
+					continue
+				}
+				require.NoError(t, err, "frame %d", i)
+				assert.True(t, result.IsLive)
+			}
+
+			// A flagged session is not sent to the provider again
+			if tt.wantReplayAt >= 0 {
+				faceProvider.AssertNumberOfCalls(t, "CheckLiveness", tt.wantReplayAt+1)
+			}
+		})
+	}
+}
+
+func TestWidgetService_Register_AfterReplaySuspected(t *testing.T) {
+	sessionRepo := &MockWidgetSessionRepository{}
+	tenantRepo := &MockTenantRepository{}
+	faceRepo := &MockFaceRepository{}
+	faceProvider := &MockFaceProvider{}
+	tenantID := uuid.New()
+	sessionID := uuid.New()
+
+	sessionRepo.On("GetByID", mock.Anything, sessionID).Return(&domain.WidgetSession{
+		ID:        sessionID,
+		TenantID:  tenantID,
+		ExpiresAt: time.Now().Add(time.Minute),
+	}, nil)
+	tenantRepo.On("GetByID", mock.Anything, tenantID).Return(&domain.Tenant{
+		ID: tenantID,
+		Settings: map[string]interface{}{
+			"replay_detection": map[string]interface{}{"enabled": true},
+		},
+	}, nil)
+	faceProvider.On("CheckLiveness", mock.Anything, mock.Anything, mock.Anything).
+		Return(&provider.LivenessResult{IsLive: true, Confidence: 0.98}, nil)
+
+	faceService := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+	svc := NewWidgetService(sessionRepo, tenantRepo, faceService)
+
+	frame := encodeLivenessFrame(t, 0, 1)
+	var err error
+	for range domain.DefaultReplayMinFrames {
+		_, err = svc.ValidateLiveness(context.Background(), sessionID, frame)
+	}
+	require.ErrorIs(t, err, domain.ErrReplaySuspected)
+
+	_, err = svc.Register(context.Background(), sessionID, "user_001", frame)
+
+	assert.ErrorIs(t, err, domain.ErrReplaySuspected)
+	faceProvider.AssertNotCalled(t, "AnalyzeFace", mock.Anything, mock.Anything)
+	faceRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestReplayTracker_ForgetsExpiredSessions(t *testing.T) {
+	tracker := newReplayTracker()
+	cfg := domain.ReplayDetectionSettings{Enabled: true, MinFrames: 2, MinScoreStdDev: 0.01}
+	now := time.Now()
+	expired := &domain.WidgetSession{ID: uuid.New(), ExpiresAt: now.Add(time.Minute)}
+
+	tracker.observe(expired, replayFrame{score: 0.9}, cfg, now)
+	require.True(t, tracker.observe(expired, replayFrame{score: 0.9}, cfg, now))
+
+	active := &domain.WidgetSession{ID: uuid.New(), ExpiresAt: now.Add(time.Hour)}
+	tracker.observe(active, replayFrame{score: 0.9}, cfg, now.Add(2*time.Minute))
+
+	assert.False(t, tracker.suspected(expired.ID))
+	assert.Len(t, tracker.sessions, 1)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/imaging"
 )
 
 const (
@@ -19,7 +20,7 @@ const (
 type WidgetSessionRepositoryInterface interface {
 	Create(ctx context.Context, session *domain.WidgetSession) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.WidgetSession, error)
-	DeleteExpired(ctx context.Context) (int64, error)
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 }
 
@@ -29,10 +30,30 @@ type TenantRepositoryInterface interface {
 	GetAllowedDomains(ctx context.Context, tenantID uuid.UUID) ([]string, error)
 }
 
+// WidgetSessionRateLimiter counts session creations per widget public key
+type WidgetSessionRateLimiter interface {
+	CheckWidgetSessionLimit(ctx context.Context, tenantID uuid.UUID, publicKey string, limit int) error
+}
+
+// WidgetCheckRateLimiter counts registration checks per widget session
+type WidgetCheckRateLimiter interface {
+	CheckWidgetCheckLimit(ctx context.Context, tenantID, sessionID uuid.UUID, limit int) error
+}
+
 type WidgetService struct {
 	sessionRepo WidgetSessionRepositoryInterface
 	tenantRepo  TenantRepositoryInterface
 	faceService *FaceService
+
+	sessionLimiter   WidgetSessionRateLimiter
+	sessionRateLimit int
+
+	checkLimiter   WidgetCheckRateLimiter
+	checkRateLimit int
+
+	replays *replayTracker
+
+	clockSkewLeeway time.Duration
 }
 
 func NewWidgetService(
@@ -44,9 +65,33 @@ func NewWidgetService(
 		sessionRepo: sessionRepo,
 		tenantRepo:  tenantRepo,
 		faceService: faceService,
+		replays:     newReplayTracker(),
 	}
 }
 
+// WithSessionRateLimit caps how many sessions one public key may create per limiter window,
+// independently of how many of them are still active. A limit of 0 disables the cap.
+func (s *WidgetService) WithSessionRateLimit(limiter WidgetSessionRateLimiter, limit int) *WidgetService {
+	s.sessionLimiter = limiter
+	s.sessionRateLimit = limit
+	return s
+}
+
+// WithCheckRateLimit caps how many registration checks one session may make per limiter window.
+// A limit of 0 disables the cap.
+func (s *WidgetService) WithCheckRateLimit(limiter WidgetCheckRateLimiter, limit int) *WidgetService {
+	s.checkLimiter = limiter
+	s.checkRateLimit = limit
+	return s
+}
+
+// WithClockSkewLeeway keeps sessions valid for leeway past ExpiresAt so a device
+// whose clock runs ahead of the server isn't rejected early.
+func (s *WidgetService) WithClockSkewLeeway(leeway time.Duration) *WidgetService {
+	s.clockSkewLeeway = leeway
+	return s
+}
+
 // CreateSession creates a new widget session after validating public key and origin
 func (s *WidgetService) CreateSession(ctx context.Context, publicKey, origin string) (*domain.WidgetSession, error) {
 	// 1. Validate input
@@ -70,20 +115,28 @@ func (s *WidgetService) CreateSession(ctx context.Context, publicKey, origin str
 		return nil, domain.ErrInvalidPublicKey.WithError(err)
 	}
 
-	// 4. Get allowed domains for tenant
+	// 4. Check the session creation rate of the public key, so a leaked key can't be
+	// used to mint sessions in bulk (counted before the origin check, which a script can spoof)
+	if s.sessionLimiter != nil {
+		if err := s.sessionLimiter.CheckWidgetSessionLimit(ctx, tenant.ID, publicKey, s.sessionRateLimit); err != nil {
+			return nil, domain.ErrWidgetSessionRateLimitExceeded.WithError(err)
+		}
+	}
+
+	// 5. Get allowed domains for tenant
 	allowedDomains, err := s.tenantRepo.GetAllowedDomains(ctx, tenant.ID)
 	if err != nil {
 		return nil, fmt.Errorf("tenant %s: get allowed domains: %w", tenant.ID, err)
 	}
 
-	// 5. Validate origin is in allowed domains
+	// 6. Validate origin is in allowed domains
 	if !isOriginAllowed(parsedOrigin, allowedDomains) {
 		return nil, domain.ErrOriginNotAllowed.WithError(
 			fmt.Errorf("origin %s not in allowed domains", parsedOrigin),
 		)
 	}
 
-	// 6. Create session
+	// 7. Create session
 	session := &domain.WidgetSession{
 		TenantID:  tenant.ID,
 		Origin:    parsedOrigin,
@@ -106,7 +159,7 @@ func (s *WidgetService) ValidateSession(ctx context.Context, sessionID uuid.UUID
 	}
 
 	// 2. Check if expired
-	if session.IsExpired() {
+	if session.IsExpired(s.clockSkewLeeway) {
 		return nil, domain.ErrWidgetSessionExpired
 	}
 
@@ -126,10 +179,17 @@ func (s *WidgetService) Register(ctx context.Context, sessionID uuid.UUID, exter
 	if err != nil {
 		return nil, fmt.Errorf("tenant %s: get tenant: %w", session.TenantID, err)
 	}
-	settings := tenant.GetSettings()
+	// Widget enrollment has its own liveness gate so kiosks can be stricter than the API
+	settings := tenant.GetSettings().ForWidgetRegister()
+	if err := settings.Maintenance.Check(); err != nil {
+		return nil, err
+	}
+	if settings.Features().ReplayDetection && s.replays.suspected(session.ID) {
+		return nil, domain.ErrReplaySuspected
+	}
 
 	// 3. Call face service to register using tenant settings
-	face, err := s.faceService.Register(ctx, session.TenantID, externalID, imageBytes, settings.RequireLiveness, settings.LivenessThreshold)
+	face, err := s.faceService.Register(ctx, session.TenantID, externalID, imageBytes, nil, settings)
 	if err != nil {
 		return nil, fmt.Errorf("tenant %s: widget register: %w", session.TenantID, err)
 	}
@@ -157,13 +217,29 @@ func (s *WidgetService) ValidateLiveness(ctx context.Context, sessionID uuid.UUI
 		return nil, fmt.Errorf("tenant %s: get tenant: %w", session.TenantID, err)
 	}
 	settings := tenant.GetSettings()
+	if err := settings.Maintenance.Check(); err != nil {
+		return nil, err
+	}
+	replayDetection := settings.Features().ReplayDetection
+	if replayDetection && s.replays.suspected(session.ID) {
+		return nil, domain.ErrReplaySuspected
+	}
 
 	// 3. Call face service to check liveness with tenant's threshold
-	result, err := s.faceService.CheckLiveness(ctx, imageBytes, settings.LivenessThreshold)
+	result, err := s.faceService.CheckLiveness(ctx, session.TenantID, imageBytes, settings)
 	if err != nil {
 		return nil, fmt.Errorf("tenant %s: widget validate liveness: %w", session.TenantID, err)
 	}
 
+	// 4. Replayed videos and photos yield frames more consistent than a live face does
+	if replayDetection {
+		fingerprint, _ := imaging.Fingerprint(imageBytes)
+		frame := replayFrame{score: result.Confidence, fingerprint: fingerprint}
+		if s.replays.observe(session, frame, settings.ReplayDetection, time.Now()) {
+			return nil, domain.ErrReplaySuspected
+		}
+	}
+
 	return result, nil
 }
 
@@ -185,6 +261,9 @@ func (s *WidgetService) Search(ctx context.Context, sessionID uuid.UUID, imageBy
 
 	// 3. Get tenant settings for search configuration
 	settings := tenant.GetSettings()
+	if err := settings.Maintenance.Check(); err != nil {
+		return nil, err
+	}
 
 	// 4. Perform search using face service
 	// Widget search returns top 1 match only for fast identification
@@ -199,14 +278,34 @@ func (s *WidgetService) Search(ctx context.Context, sessionID uuid.UUID, imageBy
 // CheckRegistration checks if a face is registered for an external_id within a session's tenant
 // This allows clients to check registration status before opening the widget
 // Returns registration info if exists, nil if not registered
-func (s *WidgetService) CheckRegistration(ctx context.Context, sessionID uuid.UUID, externalID string) (*domain.RegistrationCheck, error) {
+//
+// Tenants with a widget_check_secret only answer for external_ids their backend signed (see
+// domain.SignWidgetCheck), so the public endpoint cannot be used to enumerate registered IDs.
+func (s *WidgetService) CheckRegistration(ctx context.Context, sessionID uuid.UUID, externalID, signature string) (*domain.RegistrationCheck, error) {
 	// 1. Validate session
 	session, err := s.ValidateSession(ctx, sessionID)
 	if err != nil {
 		return nil, err
 	}
 
-	// 2. Check if face exists for this external_id
+	// 2. Count the check before verifying the signature, so guessing signatures is limited too
+	if s.checkLimiter != nil {
+		if err := s.checkLimiter.CheckWidgetCheckLimit(ctx, session.TenantID, session.ID, s.checkRateLimit); err != nil {
+			return nil, domain.ErrWidgetCheckRateLimitExceeded.WithError(err)
+		}
+	}
+
+	// 3. Require the backend's signature when the tenant enabled signed checks
+	tenant, err := s.tenantRepo.GetByID(ctx, session.TenantID)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: get tenant: %w", session.TenantID, err)
+	}
+	settings := tenant.GetSettings()
+	if settings.WidgetCheckSigned && !domain.ValidWidgetCheckSignature(settings.WidgetCheckSecret, externalID, signature) {
+		return nil, domain.ErrInvalidCheckSignature
+	}
+
+	// 4. Check if face exists for this external_id
 	face, err := s.faceService.GetByExternalID(ctx, session.TenantID, externalID)
 	if err != nil {
 		// Not found is not an error - just means not registered
@@ -218,17 +317,17 @@ func (s *WidgetService) CheckRegistration(ctx context.Context, sessionID uuid.UU
 		return nil, fmt.Errorf("tenant %s: check registration: %w", session.TenantID, err)
 	}
 
-	// 3. Return registration info
+	// 5. Return registration info
 	return &domain.RegistrationCheck{
 		Registered:   true,
 		RegisteredAt: &face.CreatedAt,
 	}, nil
 }
 
-// CleanupExpiredSessions removes all expired sessions
+// CleanupExpiredSessions removes all expired sessions, keeping those still within the clock skew leeway
 // This should be called periodically (e.g., via cron job)
 func (s *WidgetService) CleanupExpiredSessions(ctx context.Context) (int64, error) {
-	count, err := s.sessionRepo.DeleteExpired(ctx)
+	count, err := s.sessionRepo.DeleteExpired(ctx, time.Now().Add(-s.clockSkewLeeway))
 	if err != nil {
 		return 0, fmt.Errorf("cleanup expired sessions: %w", err)
 	}
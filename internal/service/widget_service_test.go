@@ -0,0 +1,447 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/provider"
+)
+
+type MockWidgetSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockWidgetSessionRepository) Create(ctx context.Context, session *domain.WidgetSession) error {
+	args := m.Called(ctx, session)
+	return args.Error(0)
+}
+
+func (m *MockWidgetSessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.WidgetSession, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WidgetSession), args.Error(1)
+}
+
+func (m *MockWidgetSessionRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockWidgetSessionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockTenantRepository struct {
+	mock.Mock
+}
+
+func (m *MockTenantRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Tenant, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Tenant), args.Error(1)
+}
+
+func (m *MockTenantRepository) GetByPublicKey(ctx context.Context, publicKey string) (*domain.Tenant, error) {
+	args := m.Called(ctx, publicKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Tenant), args.Error(1)
+}
+
+func (m *MockTenantRepository) GetAllowedDomains(ctx context.Context, tenantID uuid.UUID) ([]string, error) {
+	args := m.Called(ctx, tenantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func TestWidgetService_Register_Liveness(t *testing.T) {
+	tests := []struct {
+		name          string
+		settings      map[string]interface{}
+		livenessScore float64
+		wantErr       error
+	}{
+		{
+			name:          "no liveness required by default",
+			settings:      map[string]interface{}{},
+			livenessScore: 0.1,
+		},
+		{
+			name: "widget threshold rejects low liveness",
+			settings: map[string]interface{}{
+				"widget_register_require_liveness":   true,
+				"widget_register_liveness_threshold": 0.95,
+			},
+			livenessScore: 0.9,
+			wantErr:       domain.ErrLivenessFailed,
+		},
+		{
+			name: "widget threshold accepts high liveness",
+			settings: map[string]interface{}{
+				"widget_register_require_liveness":   true,
+				"widget_register_liveness_threshold": 0.95,
+			},
+			livenessScore: 0.97,
+		},
+		{
+			name: "widget is stricter than main register settings",
+			settings: map[string]interface{}{
+				"require_liveness":                   false,
+				"widget_register_require_liveness":   true,
+				"widget_register_liveness_threshold": 0.8,
+			},
+			livenessScore: 0.5,
+			wantErr:       domain.ErrLivenessFailed,
+		},
+		{
+			name: "widget can relax main register settings",
+			settings: map[string]interface{}{
+				"require_liveness":                 true,
+				"liveness_threshold":               0.99,
+				"widget_register_require_liveness": false,
+			},
+			livenessScore: 0.5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionRepo := &MockWidgetSessionRepository{}
+			tenantRepo := &MockTenantRepository{}
+			faceRepo := &MockFaceRepository{}
+			faceProvider := &MockFaceProvider{}
+
+			tenantID := uuid.New()
+			sessionID := uuid.New()
+
+			sessionRepo.On("GetByID", mock.Anything, sessionID).Return(&domain.WidgetSession{
+				ID:        sessionID,
+				TenantID:  tenantID,
+				ExpiresAt: time.Now().Add(time.Minute),
+			}, nil)
+			tenantRepo.On("GetByID", mock.Anything, tenantID).Return(&domain.Tenant{
+				ID:       tenantID,
+				Settings: tt.settings,
+			}, nil)
+			faceProvider.On("AnalyzeFace", mock.Anything, mock.Anything).Return(&provider.FaceAnalysis{
+				Embedding:     unitEmbedding(),
+				QualityScore:  0.95,
+				LivenessScore: tt.livenessScore,
+				FaceCount:     1,
+			}, nil)
+			if tt.wantErr == nil {
+				faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(nil, domain.ErrFaceNotFound)
+				faceRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+			}
+
+			faceService := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+			svc := NewWidgetService(sessionRepo, tenantRepo, faceService)
+
+			face, err := svc.Register(context.Background(), sessionID, "user_001", make([]byte, 5000))
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				faceRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "user_001", face.ExternalID)
+			}
+
+			faceRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWidgetService_Register_Maintenance(t *testing.T) {
+	sessionRepo := &MockWidgetSessionRepository{}
+	tenantRepo := &MockTenantRepository{}
+	faceRepo := &MockFaceRepository{}
+	faceProvider := &MockFaceProvider{}
+	tenantID := uuid.New()
+	sessionID := uuid.New()
+
+	sessionRepo.On("GetByID", mock.Anything, sessionID).Return(&domain.WidgetSession{
+		ID:        sessionID,
+		TenantID:  tenantID,
+		ExpiresAt: time.Now().Add(time.Minute),
+	}, nil)
+	tenantRepo.On("GetByID", mock.Anything, tenantID).Return(&domain.Tenant{
+		ID: tenantID,
+		Settings: map[string]interface{}{
+			"maintenance": map[string]interface{}{"enabled": true, "retry_after": "10m"},
+		},
+	}, nil)
+
+	faceService := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, faceProvider, &MockRateLimiter{})
+	svc := NewWidgetService(sessionRepo, tenantRepo, faceService)
+
+	_, err := svc.Register(context.Background(), sessionID, "user_001", make([]byte, 5000))
+
+	var appErr *domain.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, domain.ErrMaintenance.Code, appErr.Code)
+	assert.Equal(t, 10*time.Minute, appErr.RetryAfter)
+	faceProvider.AssertNotCalled(t, "AnalyzeFace", mock.Anything, mock.Anything)
+	faceRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// countingSessionLimiter is a fixed-window counter per public key that never expires
+type countingSessionLimiter struct {
+	counts map[string]int
+}
+
+func (l *countingSessionLimiter) CheckWidgetSessionLimit(_ context.Context, _ uuid.UUID, publicKey string, limit int) error {
+	if limit <= 0 {
+		return nil
+	}
+	l.counts[publicKey]++
+	if l.counts[publicKey] > limit {
+		return errors.New("rate limit exceeded")
+	}
+	return nil
+}
+
+func TestWidgetService_CreateSession_RateLimit(t *testing.T) {
+	const origin = "https://app.example.com"
+
+	newService := func(limit int, tenants map[string]uuid.UUID) (*WidgetService, *MockWidgetSessionRepository) {
+		sessionRepo := &MockWidgetSessionRepository{}
+		tenantRepo := &MockTenantRepository{}
+		for publicKey, tenantID := range tenants {
+			tenantRepo.On("GetByPublicKey", mock.Anything, publicKey).Return(&domain.Tenant{ID: tenantID}, nil)
+			tenantRepo.On("GetAllowedDomains", mock.Anything, tenantID).Return([]string{"app.example.com"}, nil)
+		}
+		sessionRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		// Sessions are ended right away, so none is ever active when the next one is created
+		sessionRepo.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+		limiter := &countingSessionLimiter{counts: make(map[string]int)}
+		return NewWidgetService(sessionRepo, tenantRepo, nil).WithSessionRateLimit(limiter, limit), sessionRepo
+	}
+
+	t.Run("caps creations even without active sessions", func(t *testing.T) {
+		svc, sessionRepo := newService(3, map[string]uuid.UUID{"pk_live": uuid.New()})
+		ctx := context.Background()
+
+		for i := 0; i < 3; i++ {
+			session, err := svc.CreateSession(ctx, "pk_live", origin)
+			require.NoError(t, err)
+			require.NoError(t, sessionRepo.Delete(ctx, session.ID))
+		}
+
+		_, err := svc.CreateSession(ctx, "pk_live", origin)
+		var appErr *domain.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, "WIDGET_SESSION_RATE_LIMIT_EXCEEDED", appErr.Code)
+		assert.Equal(t, 429, appErr.StatusCode)
+		sessionRepo.AssertNumberOfCalls(t, "Create", 3)
+	})
+
+	t.Run("counts each public key separately", func(t *testing.T) {
+		svc, _ := newService(1, map[string]uuid.UUID{"pk_leaked": uuid.New(), "pk_other": uuid.New()})
+		ctx := context.Background()
+
+		_, err := svc.CreateSession(ctx, "pk_leaked", origin)
+		require.NoError(t, err)
+		_, err = svc.CreateSession(ctx, "pk_leaked", origin)
+		require.Error(t, err)
+
+		_, err = svc.CreateSession(ctx, "pk_other", origin)
+		assert.NoError(t, err)
+	})
+
+	t.Run("zero limit disables the cap", func(t *testing.T) {
+		svc, _ := newService(0, map[string]uuid.UUID{"pk_live": uuid.New()})
+
+		for i := 0; i < 5; i++ {
+			_, err := svc.CreateSession(context.Background(), "pk_live", origin)
+			require.NoError(t, err)
+		}
+	})
+}
+
+// countingCheckLimiter is a fixed-window counter per session that never expires
+type countingCheckLimiter struct {
+	counts map[uuid.UUID]int
+}
+
+func (l *countingCheckLimiter) CheckWidgetCheckLimit(_ context.Context, _, sessionID uuid.UUID, limit int) error {
+	if limit <= 0 {
+		return nil
+	}
+	l.counts[sessionID]++
+	if l.counts[sessionID] > limit {
+		return errors.New("rate limit exceeded")
+	}
+	return nil
+}
+
+func TestWidgetService_CheckRegistration_Signed(t *testing.T) {
+	const secret = "backend-secret-0123456789abcdef0123"
+
+	tests := []struct {
+		name      string
+		settings  map[string]interface{}
+		signature string
+		wantErr   error
+	}{
+		{
+			name:     "open mode answers without a signature",
+			settings: map[string]interface{}{},
+		},
+		{
+			name:      "signed mode accepts the backend signature",
+			settings:  map[string]interface{}{"widget_check_secret": secret},
+			signature: domain.SignWidgetCheck(secret, "user_001"),
+		},
+		{
+			name:     "signed mode rejects an unsigned id",
+			settings: map[string]interface{}{"widget_check_secret": secret},
+			wantErr:  domain.ErrInvalidCheckSignature,
+		},
+		{
+			name:      "signed mode rejects a signature of another id",
+			settings:  map[string]interface{}{"widget_check_secret": secret},
+			signature: domain.SignWidgetCheck(secret, "user_002"),
+			wantErr:   domain.ErrInvalidCheckSignature,
+		},
+		{
+			name:      "signed mode rejects a signature made with another secret",
+			settings:  map[string]interface{}{"widget_check_secret": secret},
+			signature: domain.SignWidgetCheck("another-secret-0123456789abcdef0123", "user_001"),
+			wantErr:   domain.ErrInvalidCheckSignature,
+		},
+		{
+			name:      "signed mode rejects a malformed signature",
+			settings:  map[string]interface{}{"widget_check_secret": secret},
+			signature: "not-hex",
+			wantErr:   domain.ErrInvalidCheckSignature,
+		},
+		{
+			name:      "a secret that is too short rejects every check",
+			settings:  map[string]interface{}{"widget_check_secret": "short"},
+			signature: domain.SignWidgetCheck("short", "user_001"),
+			wantErr:   domain.ErrInvalidCheckSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionRepo := &MockWidgetSessionRepository{}
+			tenantRepo := &MockTenantRepository{}
+			faceRepo := &MockFaceRepository{}
+
+			tenantID := uuid.New()
+			sessionID := uuid.New()
+			registeredAt := time.Now().Add(-time.Hour)
+
+			sessionRepo.On("GetByID", mock.Anything, sessionID).Return(&domain.WidgetSession{
+				ID:        sessionID,
+				TenantID:  tenantID,
+				ExpiresAt: time.Now().Add(time.Minute),
+			}, nil)
+			tenantRepo.On("GetByID", mock.Anything, tenantID).Return(&domain.Tenant{ID: tenantID, Settings: tt.settings}, nil)
+			if tt.wantErr == nil {
+				faceRepo.On("GetByExternalID", mock.Anything, tenantID, "user_001").Return(&domain.Face{ExternalID: "user_001", CreatedAt: registeredAt}, nil)
+			}
+
+			faceService := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, &MockFaceProvider{}, &MockRateLimiter{})
+			svc := NewWidgetService(sessionRepo, tenantRepo, faceService)
+
+			result, err := svc.CheckRegistration(context.Background(), sessionID, "user_001", tt.signature)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				faceRepo.AssertNotCalled(t, "GetByExternalID", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, result.Registered)
+			faceRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWidgetService_CheckRegistration_RateLimit(t *testing.T) {
+	sessionRepo := &MockWidgetSessionRepository{}
+	tenantRepo := &MockTenantRepository{}
+	faceRepo := &MockFaceRepository{}
+
+	tenantID := uuid.New()
+	sessionID := uuid.New()
+	sessionRepo.On("GetByID", mock.Anything, sessionID).Return(&domain.WidgetSession{
+		ID:        sessionID,
+		TenantID:  tenantID,
+		ExpiresAt: time.Now().Add(time.Minute),
+	}, nil)
+	tenantRepo.On("GetByID", mock.Anything, tenantID).Return(&domain.Tenant{ID: tenantID}, nil)
+	faceRepo.On("GetByExternalID", mock.Anything, tenantID, mock.Anything).Return(nil, domain.ErrFaceNotFound)
+
+	faceService := NewFaceService(faceRepo, &MockVerificationRepository{}, &MockSearchAuditRepository{}, &MockFaceProvider{}, &MockRateLimiter{})
+	limiter := &countingCheckLimiter{counts: make(map[uuid.UUID]int)}
+	svc := NewWidgetService(sessionRepo, tenantRepo, faceService).WithCheckRateLimit(limiter, 2)
+
+	for i := 0; i < 2; i++ {
+		result, err := svc.CheckRegistration(context.Background(), sessionID, fmt.Sprintf("guess_%d", i), "")
+		require.NoError(t, err)
+		assert.False(t, result.Registered)
+	}
+
+	_, err := svc.CheckRegistration(context.Background(), sessionID, "guess_2", "")
+	var appErr *domain.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, "WIDGET_CHECK_RATE_LIMIT_EXCEEDED", appErr.Code)
+	assert.Equal(t, 429, appErr.StatusCode)
+	faceRepo.AssertNumberOfCalls(t, "GetByExternalID", 2)
+}
+
+func TestWidgetService_ValidateSession_ClockSkewLeeway(t *testing.T) {
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		wantErr   error
+	}{
+		{name: "not expired", expiresAt: time.Now().Add(time.Minute)},
+		{name: "expired within leeway", expiresAt: time.Now().Add(-10 * time.Second)},
+		{name: "expired past leeway", expiresAt: time.Now().Add(-time.Minute), wantErr: domain.ErrWidgetSessionExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionRepo := &MockWidgetSessionRepository{}
+			sessionID := uuid.New()
+			sessionRepo.On("GetByID", mock.Anything, sessionID).Return(&domain.WidgetSession{
+				ID:        sessionID,
+				TenantID:  uuid.New(),
+				ExpiresAt: tt.expiresAt,
+			}, nil)
+
+			svc := NewWidgetService(sessionRepo, &MockTenantRepository{}, nil).WithClockSkewLeeway(30 * time.Second)
+
+			session, err := svc.ValidateSession(context.Background(), sessionID)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, sessionID, session.ID)
+		})
+	}
+}
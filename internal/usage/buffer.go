@@ -0,0 +1,119 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Incrementer persists daily usage counters
+type Incrementer interface {
+	IncrementDaily(ctx context.Context, tenantID uuid.UUID, date time.Time, field string, amount int) error
+}
+
+// bufferKey identifies one usage_daily counter
+type bufferKey struct {
+	tenantID uuid.UUID
+	date     time.Time
+	field    string
+}
+
+// BufferedTracker aggregates usage increments in memory and persists them in batches
+// Call Flush on shutdown so pending counts are not lost
+type BufferedTracker struct {
+	store    Incrementer
+	logger   *slog.Logger
+	interval time.Duration
+
+	mu      sync.Mutex
+	pending map[bufferKey]int
+}
+
+// NewBufferedTracker creates a tracker that flushes to store every interval
+func NewBufferedTracker(store Incrementer, logger *slog.Logger, interval time.Duration) *BufferedTracker {
+	return &BufferedTracker{
+		store:    store,
+		logger:   logger,
+		interval: interval,
+		pending:  make(map[bufferKey]int),
+	}
+}
+
+// IncrementDaily buffers an increment for the given day and field
+func (t *BufferedTracker) IncrementDaily(ctx context.Context, tenantID uuid.UUID, date time.Time, field string, amount int) error {
+	if _, ok := incrementQueries[field]; !ok {
+		return fmt.Errorf("invalid field: %s", field)
+	}
+
+	year, month, day := date.UTC().Date()
+	key := bufferKey{
+		tenantID: tenantID,
+		date:     time.Date(year, month, day, 0, 0, 0, 0, time.UTC),
+		field:    field,
+	}
+
+	t.mu.Lock()
+	t.pending[key] += amount
+	t.mu.Unlock()
+
+	return nil
+}
+
+// Pending returns the number of buffered counters not yet persisted
+func (t *BufferedTracker) Pending() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.pending)
+}
+
+// Flush persists all buffered counters
+// Counters that fail to persist are kept in the buffer for the next flush
+func (t *BufferedTracker) Flush(ctx context.Context) error {
+	t.mu.Lock()
+	batch := t.pending
+	t.pending = make(map[bufferKey]int)
+	t.mu.Unlock()
+
+	var errs []error
+	for key, amount := range batch {
+		if err := t.store.IncrementDaily(ctx, key.tenantID, key.date, key.field, amount); err != nil {
+			errs = append(errs, err)
+
+			t.mu.Lock()
+			t.pending[key] += amount
+			t.mu.Unlock()
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("flush usage: %d of %d counters failed: %w", len(errs), len(batch), errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// Run flushes buffered counters periodically until ctx is cancelled
+// The final flush is left to the caller so it can run under the shutdown timeout
+func (t *BufferedTracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	t.logger.Info("usage flush worker started", "interval", t.interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			t.logger.Info("usage flush worker stopped", "pending", t.Pending())
+			return
+		case <-ticker.C:
+			if err := t.Flush(ctx); err != nil {
+				t.logger.Error("failed to flush usage", "error", err)
+			}
+		}
+	}
+}
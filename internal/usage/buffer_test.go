@@ -0,0 +1,106 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIncrementer records persisted increments keyed by tenant/date/field
+type fakeIncrementer struct {
+	mu     sync.Mutex
+	counts map[bufferKey]int
+	calls  int
+	err    error
+}
+
+func (f *fakeIncrementer) IncrementDaily(ctx context.Context, tenantID uuid.UUID, date time.Time, field string, amount int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.err != nil {
+		return f.err
+	}
+	if f.counts == nil {
+		f.counts = make(map[bufferKey]int)
+	}
+	f.counts[bufferKey{tenantID: tenantID, date: date, field: field}] += amount
+	return nil
+}
+
+func TestBufferedTracker_Flush(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tenantID := uuid.New()
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	t.Run("buffered counts are persisted on flush", func(t *testing.T) {
+		store := &fakeIncrementer{}
+		tracker := NewBufferedTracker(store, logger, time.Hour)
+
+		require.NoError(t, tracker.IncrementDaily(context.Background(), tenantID, day.Add(9*time.Hour), "registrations", 1))
+		require.NoError(t, tracker.IncrementDaily(context.Background(), tenantID, day.Add(15*time.Hour), "registrations", 2))
+		require.NoError(t, tracker.IncrementDaily(context.Background(), tenantID, day, "verifications", 1))
+
+		// Nothing reaches the store until flushed
+		assert.Zero(t, store.calls)
+		assert.Equal(t, 2, tracker.Pending())
+
+		require.NoError(t, tracker.Flush(context.Background()))
+
+		assert.Equal(t, 2, store.calls, "increments for the same day and field are aggregated")
+		assert.Equal(t, 3, store.counts[bufferKey{tenantID: tenantID, date: day, field: "registrations"}])
+		assert.Equal(t, 1, store.counts[bufferKey{tenantID: tenantID, date: day, field: "verifications"}])
+		assert.Zero(t, tracker.Pending())
+	})
+
+	t.Run("failed counters stay buffered for the next flush", func(t *testing.T) {
+		store := &fakeIncrementer{err: errors.New("connection refused")}
+		tracker := NewBufferedTracker(store, logger, time.Hour)
+
+		require.NoError(t, tracker.IncrementDaily(context.Background(), tenantID, day, "liveness_checks", 4))
+		require.Error(t, tracker.Flush(context.Background()))
+		assert.Equal(t, 1, tracker.Pending())
+
+		store.err = nil
+		require.NoError(t, tracker.Flush(context.Background()))
+		assert.Equal(t, 4, store.counts[bufferKey{tenantID: tenantID, date: day, field: "liveness_checks"}])
+		assert.Zero(t, tracker.Pending())
+	})
+
+	t.Run("feature counters are accepted", func(t *testing.T) {
+		store := &fakeIncrementer{}
+		tracker := NewBufferedTracker(store, logger, time.Hour)
+
+		fields := []string{"searches", "widget_sessions", "widget_registrations", "widget_liveness_checks", "widget_searches", "webhook_deliveries"}
+		for _, field := range fields {
+			require.NoError(t, tracker.IncrementDaily(context.Background(), tenantID, day, field, 1), field)
+		}
+		require.NoError(t, tracker.Flush(context.Background()))
+
+		for _, field := range fields {
+			assert.Equal(t, 1, store.counts[bufferKey{tenantID: tenantID, date: day, field: field}], field)
+		}
+	})
+
+	t.Run("invalid field is rejected", func(t *testing.T) {
+		tracker := NewBufferedTracker(&fakeIncrementer{}, logger, time.Hour)
+
+		err := tracker.IncrementDaily(context.Background(), tenantID, day, "invalid", 1)
+		require.Error(t, err)
+		assert.Zero(t, tracker.Pending())
+	})
+}
+
+func TestRepository_Flush_NoOp(t *testing.T) {
+	// DB-only repository has nothing buffered; a nil pool proves no query runs
+	repo := NewRepository(nil)
+	assert.NoError(t, repo.Flush(context.Background()))
+}
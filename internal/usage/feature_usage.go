@@ -0,0 +1,114 @@
+package usage
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Optional features reported by SummarizeFeatures
+const (
+	FeatureSearch   = "search"
+	FeatureLiveness = "liveness"
+	FeatureWidget   = "widget"
+	FeatureWebhooks = "webhooks"
+)
+
+// featureCounter is one usage_daily counter that counts executions of a feature
+type featureCounter struct {
+	field string
+	value func(UsageRecord) int
+}
+
+// featureCounters maps each optional feature to the usage_daily counters incremented when its
+// path executes
+var featureCounters = map[string][]featureCounter{
+	FeatureSearch: {
+		{"searches", func(r UsageRecord) int { return r.Searches }},
+	},
+	FeatureLiveness: {
+		{"liveness_checks", func(r UsageRecord) int { return r.LivenessChecks }},
+	},
+	FeatureWidget: {
+		{"widget_sessions", func(r UsageRecord) int { return r.WidgetSessions }},
+		{"widget_registrations", func(r UsageRecord) int { return r.WidgetRegistrations }},
+		{"widget_liveness_checks", func(r UsageRecord) int { return r.WidgetLivenessChecks }},
+		{"widget_searches", func(r UsageRecord) int { return r.WidgetSearches }},
+	},
+	FeatureWebhooks: {
+		{"webhook_deliveries", func(r UsageRecord) int { return r.WebhookDeliveries }},
+	},
+}
+
+// FeatureUsage is how much a tenant used one optional feature over a date range
+type FeatureUsage struct {
+	Used bool `json:"used"`
+	// Total counts every execution of the feature, Counters splits it by usage counter
+	Total    int            `json:"total"`
+	Counters map[string]int `json:"counters"`
+	// ActiveDays is the number of days the feature ran at least once
+	ActiveDays int `json:"active_days"`
+	// LastUsedOn is the last day (YYYY-MM-DD) the feature ran, null when it never did
+	LastUsedOn *string `json:"last_used_on"`
+}
+
+// FeatureUsageReport summarizes the optional features a tenant used between two dates, inclusive
+type FeatureUsageReport struct {
+	TenantID  uuid.UUID               `json:"tenant_id"`
+	StartDate string                  `json:"start_date"`
+	EndDate   string                  `json:"end_date"`
+	Features  map[string]FeatureUsage `json:"features"`
+}
+
+// SummarizeFeatures sums daily usage records per optional feature. Every feature is present in
+// the result, unused ones with zero counts.
+func SummarizeFeatures(records []UsageRecord) map[string]FeatureUsage {
+	features := make(map[string]FeatureUsage, len(featureCounters))
+	for feature, counters := range featureCounters {
+		usage := FeatureUsage{Counters: make(map[string]int, len(counters))}
+		for _, counter := range counters {
+			usage.Counters[counter.field] = 0
+		}
+		var lastUsed time.Time
+		for _, record := range records {
+			day := 0
+			for _, counter := range counters {
+				n := counter.value(record)
+				usage.Counters[counter.field] += n
+				day += n
+			}
+			if day == 0 {
+				continue
+			}
+			usage.Total += day
+			usage.ActiveDays++
+			if record.Date.After(lastUsed) {
+				lastUsed = record.Date
+			}
+		}
+		if usage.Total > 0 {
+			usage.Used = true
+			last := lastUsed.Format("2006-01-02")
+			usage.LastUsedOn = &last
+		}
+		features[feature] = usage
+	}
+	return features
+}
+
+// GetFeatureUsage reports the optional features the tenant used between startDate and endDate,
+// inclusive. Counts still buffered by the tracker show up after its next flush.
+func (r *Repository) GetFeatureUsage(ctx context.Context, tenantID uuid.UUID, startDate, endDate time.Time) (*FeatureUsageReport, error) {
+	records, err := r.GetDailyUsage(ctx, tenantID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FeatureUsageReport{
+		TenantID:  tenantID,
+		StartDate: startDate.Format("2006-01-02"),
+		EndDate:   endDate.Format("2006-01-02"),
+		Features:  SummarizeFeatures(records),
+	}, nil
+}
@@ -0,0 +1,75 @@
+package usage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeFeatures(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC) }
+
+	t.Run("counts each feature from its own counters", func(t *testing.T) {
+		records := []UsageRecord{
+			{Date: day(3), Verifications: 40, Searches: 5, WidgetSessions: 2, WidgetRegistrations: 1},
+			{Date: day(1), Verifications: 10, Searches: 7, LivenessChecks: 3, WidgetSearches: 4},
+			{Date: day(2), Registrations: 8, WebhookDeliveries: 12, WidgetLivenessChecks: 2},
+		}
+
+		features := SummarizeFeatures(records)
+
+		require.Len(t, features, 4)
+
+		search := features[FeatureSearch]
+		assert.True(t, search.Used)
+		assert.Equal(t, 12, search.Total)
+		assert.Equal(t, 2, search.ActiveDays)
+		require.NotNil(t, search.LastUsedOn)
+		assert.Equal(t, "2026-10-03", *search.LastUsedOn)
+		assert.Equal(t, map[string]int{"searches": 12}, search.Counters)
+
+		liveness := features[FeatureLiveness]
+		assert.Equal(t, 3, liveness.Total)
+		assert.Equal(t, 1, liveness.ActiveDays)
+		assert.Equal(t, "2026-10-01", *liveness.LastUsedOn)
+
+		widget := features[FeatureWidget]
+		assert.Equal(t, 9, widget.Total)
+		assert.Equal(t, 3, widget.ActiveDays)
+		assert.Equal(t, "2026-10-03", *widget.LastUsedOn)
+		assert.Equal(t, map[string]int{
+			"widget_sessions":        2,
+			"widget_registrations":   1,
+			"widget_liveness_checks": 2,
+			"widget_searches":        4,
+		}, widget.Counters)
+
+		webhooks := features[FeatureWebhooks]
+		assert.Equal(t, 12, webhooks.Total)
+		assert.Equal(t, 1, webhooks.ActiveDays)
+		assert.Equal(t, "2026-10-02", *webhooks.LastUsedOn)
+	})
+
+	t.Run("core usage alone leaves every feature unused", func(t *testing.T) {
+		features := SummarizeFeatures([]UsageRecord{
+			{Date: day(1), Registrations: 100, Verifications: 900},
+		})
+
+		require.Len(t, features, 4)
+		for name, feature := range features {
+			assert.False(t, feature.Used, name)
+			assert.Zero(t, feature.Total, name)
+			assert.Zero(t, feature.ActiveDays, name)
+			assert.Nil(t, feature.LastUsedOn, name)
+		}
+	})
+
+	t.Run("no records", func(t *testing.T) {
+		features := SummarizeFeatures(nil)
+
+		require.Len(t, features, 4)
+		assert.Equal(t, map[string]int{"searches": 0}, features[FeatureSearch].Counters)
+	})
+}
@@ -0,0 +1,171 @@
+package usage
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/webhook"
+)
+
+// DefaultQuotaUsageTTL is how long a tenant's quota usage is reused before it is read again
+const DefaultQuotaUsageTTL = time.Minute
+
+// QuotaUsageReader reads the usage counted against the max_faces and max_requests_month quotas
+type QuotaUsageReader interface {
+	CountFaces(ctx context.Context, tenantID uuid.UUID) (int64, error)
+	MonthRequests(ctx context.Context, tenantID uuid.UUID, monthStart time.Time) (int64, error)
+}
+
+// EventDispatcher delivers events to the webhooks of a tenant
+type EventDispatcher interface {
+	Dispatch(ctx context.Context, tenantID uuid.UUID, eventType string, data interface{}) error
+}
+
+// quotaUsage is a cached read of a tenant's quota usage
+type quotaUsage struct {
+	faces     int64
+	requests  int64
+	period    string
+	expiresAt time.Time
+}
+
+// notifiedKey identifies the quota.threshold_reached level already sent for a tenant quota and period
+type notifiedKey struct {
+	tenantID uuid.UUID
+	quota    string
+	period   string
+}
+
+// QuotaWarner reports tenants approaching their max_faces or max_requests_month quota.
+// Usage is read from the faces table and usage_daily and reused for ttl, so it may lag
+// the buffered usage counters slightly. A quota.threshold_reached webhook is sent once per
+// tenant, quota and month each time usage crosses a higher warning percentage; levels are kept
+// in this instance's memory, so another API instance may send the same warning again.
+// It is a distinct event from the quota.warning alert of Service.CheckQuota, whose payload differs.
+type QuotaWarner struct {
+	reader   QuotaUsageReader
+	webhooks EventDispatcher // optional, nil sends no quota.threshold_reached events
+	logger   *slog.Logger
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu       sync.Mutex
+	usage    map[uuid.UUID]quotaUsage
+	notified map[notifiedKey]int
+}
+
+func NewQuotaWarner(reader QuotaUsageReader, webhooks EventDispatcher, logger *slog.Logger, ttl time.Duration) *QuotaWarner {
+	if ttl <= 0 {
+		ttl = DefaultQuotaUsageTTL
+	}
+	return &QuotaWarner{
+		reader:   reader,
+		webhooks: webhooks,
+		logger:   logger,
+		ttl:      ttl,
+		now:      time.Now,
+		usage:    make(map[uuid.UUID]quotaUsage),
+		notified: make(map[notifiedKey]int),
+	}
+}
+
+// Check returns the warnings for the quotas of a tenant whose usage reached a warning
+// percentage, sending quota.threshold_reached for each newly reached one. It never fails: usage that
+// cannot be read is logged and reported as no warning.
+func (w *QuotaWarner) Check(ctx context.Context, tenantID uuid.UUID, settings domain.TenantSettings) []domain.QuotaWarning {
+	cfg := settings.QuotaWarnings
+	if !cfg.Enabled || (settings.MaxFaces <= 0 && settings.MaxRequestsMonth <= 0) {
+		return nil
+	}
+
+	usage, err := w.read(ctx, tenantID)
+	if err != nil {
+		w.logger.Warn("failed to read quota usage", "tenant_id", tenantID, "error", err)
+		return nil
+	}
+
+	var warnings []domain.QuotaWarning
+	if settings.MaxFaces > 0 {
+		warnings = w.check(ctx, tenantID, cfg, domain.QuotaMaxFaces, usage.faces, int64(settings.MaxFaces), "", warnings)
+	}
+	if settings.MaxRequestsMonth > 0 {
+		warnings = w.check(ctx, tenantID, cfg, domain.QuotaMaxRequestsMonth, usage.requests, int64(settings.MaxRequestsMonth), usage.period, warnings)
+	}
+	return warnings
+}
+
+// check appends the warning for one quota when used reached a warning percentage of limit,
+// sending quota.threshold_reached when that percentage is higher than the last one sent
+func (w *QuotaWarner) check(ctx context.Context, tenantID uuid.UUID, cfg domain.QuotaWarningSettings, quota string, used, limit int64, period string, warnings []domain.QuotaWarning) []domain.QuotaWarning {
+	level := cfg.Level(used, limit)
+	escalated := w.escalate(notifiedKey{tenantID: tenantID, quota: quota, period: period}, level)
+	if level == 0 {
+		return warnings
+	}
+
+	warning := domain.NewQuotaWarning(quota, level, used, limit, period)
+	if escalated && w.webhooks != nil {
+		data := webhook.QuotaThresholdReachedData{
+			Quota:      warning.Quota,
+			Threshold:  warning.Threshold,
+			Used:       warning.Used,
+			Limit:      warning.Limit,
+			Percentage: warning.Percentage,
+			Period:     warning.Period,
+		}
+		if err := w.webhooks.Dispatch(ctx, tenantID, webhook.EventQuotaThresholdReached, data); err != nil {
+			w.logger.Warn("failed to dispatch quota warning", "tenant_id", tenantID, "quota", quota, "error", err)
+		}
+	}
+	return append(warnings, warning)
+}
+
+// read returns the cached usage of a tenant, reading it again once it expired or the month changed
+func (w *QuotaWarner) read(ctx context.Context, tenantID uuid.UUID) (quotaUsage, error) {
+	now := w.now().UTC()
+	period := now.Format("2006-01")
+
+	w.mu.Lock()
+	cached, ok := w.usage[tenantID]
+	w.mu.Unlock()
+	if ok && cached.period == period && now.Before(cached.expiresAt) {
+		return cached, nil
+	}
+
+	faces, err := w.reader.CountFaces(ctx, tenantID)
+	if err != nil {
+		return quotaUsage{}, err
+	}
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	requests, err := w.reader.MonthRequests(ctx, tenantID, monthStart)
+	if err != nil {
+		return quotaUsage{}, err
+	}
+
+	usage := quotaUsage{faces: faces, requests: requests, period: period, expiresAt: now.Add(w.ttl)}
+	w.mu.Lock()
+	w.usage[tenantID] = usage
+	w.mu.Unlock()
+	return usage, nil
+}
+
+// escalate records level as the current warning level of a quota and reports whether it is
+// higher than the previous one. Dropping below a percentage, after a quota increase or a face
+// deletion, lets crossing it again warn again.
+func (w *QuotaWarner) escalate(key notifiedKey, level int) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	previous := w.notified[key]
+	if level == 0 {
+		delete(w.notified, key)
+	} else {
+		w.notified[key] = level
+	}
+	return level > previous
+}
@@ -0,0 +1,169 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saturnino-fabrica-de-software/rekko/internal/domain"
+	"github.com/saturnino-fabrica-de-software/rekko/internal/webhook"
+)
+
+// fakeQuotaUsage serves fixed quota usage
+type fakeQuotaUsage struct {
+	faces    int64
+	requests int64
+	err      error
+	reads    int
+}
+
+func (f *fakeQuotaUsage) CountFaces(ctx context.Context, tenantID uuid.UUID) (int64, error) {
+	f.reads++
+	return f.faces, f.err
+}
+
+func (f *fakeQuotaUsage) MonthRequests(ctx context.Context, tenantID uuid.UUID, monthStart time.Time) (int64, error) {
+	return f.requests, f.err
+}
+
+// fakeDispatcher records dispatched events
+type fakeDispatcher struct {
+	mu     sync.Mutex
+	events []webhook.QuotaThresholdReachedData
+}
+
+func (f *fakeDispatcher) Dispatch(ctx context.Context, tenantID uuid.UUID, eventType string, data interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if eventType == webhook.EventQuotaThresholdReached {
+		f.events = append(f.events, data.(webhook.QuotaThresholdReachedData))
+	}
+	return nil
+}
+
+func TestQuotaWarner_Check(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tenantID := uuid.New()
+	settings := (&domain.Tenant{Settings: map[string]interface{}{
+		"max_faces":          1000,
+		"max_requests_month": 10000,
+	}}).GetSettings()
+
+	t.Run("warns once at each configured percentage", func(t *testing.T) {
+		reader := &fakeQuotaUsage{faces: 500}
+		dispatcher := &fakeDispatcher{}
+		warner := NewQuotaWarner(reader, dispatcher, logger, time.Nanosecond)
+
+		assert.Empty(t, warner.Check(context.Background(), tenantID, settings))
+
+		reader.faces = 800
+		warnings := warner.Check(context.Background(), tenantID, settings)
+		require.Len(t, warnings, 1)
+		assert.Equal(t, domain.QuotaMaxFaces, warnings[0].Quota)
+		assert.Equal(t, 80, warnings[0].Threshold)
+		assert.Equal(t, 80.0, warnings[0].Percentage)
+
+		reader.faces = 850
+		require.Len(t, warner.Check(context.Background(), tenantID, settings), 1)
+
+		reader.faces = 910
+		warnings = warner.Check(context.Background(), tenantID, settings)
+		require.Len(t, warnings, 1)
+		assert.Equal(t, 90, warnings[0].Threshold)
+		require.Len(t, warner.Check(context.Background(), tenantID, settings), 1)
+
+		require.Len(t, dispatcher.events, 2)
+		assert.Equal(t, 80, dispatcher.events[0].Threshold)
+		assert.Equal(t, int64(800), dispatcher.events[0].Used)
+		assert.Equal(t, 90, dispatcher.events[1].Threshold)
+		assert.Equal(t, int64(1000), dispatcher.events[1].Limit)
+	})
+
+	t.Run("monthly requests warning carries the period", func(t *testing.T) {
+		reader := &fakeQuotaUsage{requests: 9500}
+		dispatcher := &fakeDispatcher{}
+		warner := NewQuotaWarner(reader, dispatcher, logger, time.Minute)
+		warner.now = func() time.Time { return time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC) }
+
+		warnings := warner.Check(context.Background(), tenantID, settings)
+		require.Len(t, warnings, 1)
+		assert.Equal(t, domain.QuotaMaxRequestsMonth, warnings[0].Quota)
+		assert.Equal(t, 90, warnings[0].Threshold)
+		assert.Equal(t, "2025-03", warnings[0].Period)
+		require.Len(t, dispatcher.events, 1)
+	})
+
+	t.Run("configured percentages", func(t *testing.T) {
+		custom := (&domain.Tenant{Settings: map[string]interface{}{
+			"max_faces":      1000,
+			"quota_warnings": map[string]interface{}{"percentages": []interface{}{50.0, 95.0}},
+		}}).GetSettings()
+		reader := &fakeQuotaUsage{faces: 900}
+		dispatcher := &fakeDispatcher{}
+		warner := NewQuotaWarner(reader, dispatcher, logger, time.Nanosecond)
+
+		warnings := warner.Check(context.Background(), tenantID, custom)
+		require.Len(t, warnings, 1)
+		assert.Equal(t, 50, warnings[0].Threshold)
+
+		reader.faces = 960
+		warnings = warner.Check(context.Background(), tenantID, custom)
+		require.Len(t, warnings, 1)
+		assert.Equal(t, 95, warnings[0].Threshold)
+		assert.Len(t, dispatcher.events, 2)
+	})
+
+	t.Run("dropping below a percentage warns again on the next crossing", func(t *testing.T) {
+		reader := &fakeQuotaUsage{faces: 820}
+		dispatcher := &fakeDispatcher{}
+		warner := NewQuotaWarner(reader, dispatcher, logger, time.Nanosecond)
+
+		warner.Check(context.Background(), tenantID, settings)
+		reader.faces = 700
+		assert.Empty(t, warner.Check(context.Background(), tenantID, settings))
+		reader.faces = 810
+		warner.Check(context.Background(), tenantID, settings)
+
+		assert.Len(t, dispatcher.events, 2)
+	})
+
+	t.Run("usage is cached for the ttl", func(t *testing.T) {
+		reader := &fakeQuotaUsage{faces: 100}
+		warner := NewQuotaWarner(reader, nil, logger, time.Hour)
+
+		warner.Check(context.Background(), tenantID, settings)
+		warner.Check(context.Background(), tenantID, settings)
+
+		assert.Equal(t, 1, reader.reads)
+	})
+
+	t.Run("no quota or warnings disabled reads nothing", func(t *testing.T) {
+		reader := &fakeQuotaUsage{faces: 1000}
+		warner := NewQuotaWarner(reader, &fakeDispatcher{}, logger, time.Minute)
+
+		assert.Empty(t, warner.Check(context.Background(), tenantID, domain.DefaultTenantSettings()))
+		disabled := (&domain.Tenant{Settings: map[string]interface{}{
+			"max_faces":      1000,
+			"quota_warnings": map[string]interface{}{"enabled": false},
+		}}).GetSettings()
+		assert.Empty(t, warner.Check(context.Background(), tenantID, disabled))
+		assert.Zero(t, reader.reads)
+	})
+
+	t.Run("usage read error reports no warning", func(t *testing.T) {
+		reader := &fakeQuotaUsage{err: errors.New("db down")}
+		dispatcher := &fakeDispatcher{}
+		warner := NewQuotaWarner(reader, dispatcher, logger, time.Minute)
+
+		assert.Empty(t, warner.Check(context.Background(), tenantID, settings))
+		assert.Empty(t, dispatcher.events)
+	})
+}
@@ -83,7 +83,9 @@ func (r *Repository) GetPlanWithOverrides(ctx context.Context, tenantID uuid.UUI
 
 func (r *Repository) GetDailyUsage(ctx context.Context, tenantID uuid.UUID, startDate, endDate time.Time) ([]UsageRecord, error) {
 	query := `
-		SELECT id, tenant_id, date, registrations, verifications, liveness_checks, created_at, updated_at
+		SELECT id, tenant_id, date, registrations, verifications, liveness_checks,
+		       searches, widget_sessions, widget_registrations, widget_liveness_checks, widget_searches,
+		       webhook_deliveries, created_at, updated_at
 		FROM usage_daily
 		WHERE tenant_id = $1 AND date >= $2 AND date <= $3
 		ORDER BY date DESC
@@ -105,6 +107,12 @@ func (r *Repository) GetDailyUsage(ctx context.Context, tenantID uuid.UUID, star
 			&record.Registrations,
 			&record.Verifications,
 			&record.LivenessChecks,
+			&record.Searches,
+			&record.WidgetSessions,
+			&record.WidgetRegistrations,
+			&record.WidgetLivenessChecks,
+			&record.WidgetSearches,
+			&record.WebhookDeliveries,
 			&record.CreatedAt,
 			&record.UpdatedAt,
 		)
@@ -168,6 +176,42 @@ var incrementQueries = map[string]string{
 		ON CONFLICT (tenant_id, date)
 		DO UPDATE SET liveness_checks = usage_daily.liveness_checks + EXCLUDED.liveness_checks, updated_at = NOW()
 	`,
+	"searches": `
+		INSERT INTO usage_daily (tenant_id, date, searches)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (tenant_id, date)
+		DO UPDATE SET searches = usage_daily.searches + EXCLUDED.searches, updated_at = NOW()
+	`,
+	"widget_sessions": `
+		INSERT INTO usage_daily (tenant_id, date, widget_sessions)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (tenant_id, date)
+		DO UPDATE SET widget_sessions = usage_daily.widget_sessions + EXCLUDED.widget_sessions, updated_at = NOW()
+	`,
+	"widget_registrations": `
+		INSERT INTO usage_daily (tenant_id, date, widget_registrations)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (tenant_id, date)
+		DO UPDATE SET widget_registrations = usage_daily.widget_registrations + EXCLUDED.widget_registrations, updated_at = NOW()
+	`,
+	"widget_liveness_checks": `
+		INSERT INTO usage_daily (tenant_id, date, widget_liveness_checks)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (tenant_id, date)
+		DO UPDATE SET widget_liveness_checks = usage_daily.widget_liveness_checks + EXCLUDED.widget_liveness_checks, updated_at = NOW()
+	`,
+	"widget_searches": `
+		INSERT INTO usage_daily (tenant_id, date, widget_searches)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (tenant_id, date)
+		DO UPDATE SET widget_searches = usage_daily.widget_searches + EXCLUDED.widget_searches, updated_at = NOW()
+	`,
+	"webhook_deliveries": `
+		INSERT INTO usage_daily (tenant_id, date, webhook_deliveries)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (tenant_id, date)
+		DO UPDATE SET webhook_deliveries = usage_daily.webhook_deliveries + EXCLUDED.webhook_deliveries, updated_at = NOW()
+	`,
 }
 
 func (r *Repository) IncrementDaily(ctx context.Context, tenantID uuid.UUID, date time.Time, field string, amount int) error {
@@ -184,6 +228,11 @@ func (r *Repository) IncrementDaily(ctx context.Context, tenantID uuid.UUID, dat
 	return nil
 }
 
+// Flush is a no-op: every increment is written to the database immediately
+func (r *Repository) Flush(ctx context.Context) error {
+	return nil
+}
+
 // GetActiveTenantsWithPlan returns all active tenants with their plan IDs for quota checking
 func (r *Repository) GetActiveTenantsWithPlan(ctx context.Context) ([]TenantPlan, error) {
 	query := `
@@ -213,3 +262,29 @@ func (r *Repository) GetActiveTenantsWithPlan(ctx context.Context) ([]TenantPlan
 
 	return tenants, nil
 }
+
+// CountFaces returns how many faces a tenant has registered, the usage of its max_faces quota
+func (r *Repository) CountFaces(ctx context.Context, tenantID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM faces WHERE tenant_id = $1`, tenantID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("tenant %s: count faces: %w", tenantID, err)
+	}
+	return count, nil
+}
+
+// MonthRequests returns the registrations, verifications and liveness checks a tenant made
+// since monthStart, the usage of its max_requests_month quota
+func (r *Repository) MonthRequests(ctx context.Context, tenantID uuid.UUID, monthStart time.Time) (int64, error) {
+	query := `
+		SELECT COALESCE(SUM(registrations + verifications + liveness_checks), 0)
+		FROM usage_daily
+		WHERE tenant_id = $1 AND date >= $2
+	`
+
+	var total int64
+	if err := r.pool.QueryRow(ctx, query, tenantID, monthStart).Scan(&total); err != nil {
+		return 0, fmt.Errorf("tenant %s: month requests: %w", tenantID, err)
+	}
+	return total, nil
+}
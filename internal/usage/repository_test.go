@@ -101,6 +101,14 @@ func TestRepository_IncrementDaily(t *testing.T) {
 			amount:   1,
 			wantErr:  false,
 		},
+		{
+			name:     "increment webhook_deliveries",
+			tenantID: uuid.New(),
+			date:     time.Now().UTC(),
+			field:    "webhook_deliveries",
+			amount:   1,
+			wantErr:  false,
+		},
 		{
 			name:     "invalid field",
 			tenantID: uuid.New(),
@@ -24,8 +24,15 @@ type UsageRecord struct {
 	Registrations  int       `json:"registrations"`
 	Verifications  int       `json:"verifications"`
 	LivenessChecks int       `json:"liveness_checks"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	// Optional feature counters, see featureCounters
+	Searches             int       `json:"searches"`
+	WidgetSessions       int       `json:"widget_sessions"`
+	WidgetRegistrations  int       `json:"widget_registrations"`
+	WidgetLivenessChecks int       `json:"widget_liveness_checks"`
+	WidgetSearches       int       `json:"widget_searches"`
+	WebhookDeliveries    int       `json:"webhook_deliveries"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
 }
 
 type UsageAlert struct {
@@ -0,0 +1,84 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// MaxBulkWebhooks caps how many webhooks one CreateWebhooks call may create
+const MaxBulkWebhooks = 50
+
+// Limits of a webhook configuration
+const (
+	minWebhookNameLength = 3
+	maxWebhookNameLength = 255
+	maxWebhookURLLength  = 2048
+)
+
+var ErrInvalidWebhook = errors.New("invalid webhook")
+
+// ValidateWebhook checks a webhook configuration can be stored as is: a name, an absolute
+// http(s) URL, at least one known event, and valid headers and sample rates
+func ValidateWebhook(webhook *Webhook) error {
+	name := strings.TrimSpace(webhook.Name)
+	if len(name) < minWebhookNameLength || len(name) > maxWebhookNameLength {
+		return fmt.Errorf("%w: name must have %d to %d characters", ErrInvalidWebhook, minWebhookNameLength, maxWebhookNameLength)
+	}
+
+	u, err := url.Parse(webhook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidWebhook)
+	}
+	if len(webhook.URL) > maxWebhookURLLength {
+		return fmt.Errorf("%w: url must have at most %d characters", ErrInvalidWebhook, maxWebhookURLLength)
+	}
+
+	if len(webhook.Events) == 0 {
+		return fmt.Errorf("%w: at least one event is required", ErrInvalidWebhook)
+	}
+	eventTypes := EventTypes()
+	for _, event := range webhook.Events {
+		if !slices.Contains(eventTypes, event) {
+			return fmt.Errorf("%w: unknown event %q", ErrInvalidWebhook, event)
+		}
+	}
+
+	if err := ValidateHeaders(webhook.Headers); err != nil {
+		return err
+	}
+	return ValidateSampleRates(webhook.SampleRates)
+}
+
+// CreateWebhooks validates and inserts webhooks in one transaction, filling their IDs and
+// timestamps. Any invalid webhook or failed insert creates none of them.
+func (s *Service) CreateWebhooks(ctx context.Context, webhooks []*Webhook) error {
+	if len(webhooks) == 0 || len(webhooks) > MaxBulkWebhooks {
+		return fmt.Errorf("%w: between 1 and %d webhooks are allowed, got %d", ErrInvalidWebhook, MaxBulkWebhooks, len(webhooks))
+	}
+	for i, w := range webhooks {
+		if err := ValidateWebhook(w); err != nil {
+			return fmt.Errorf("webhooks[%d]: %w", i, err)
+		}
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin bulk create: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	for i, w := range webhooks {
+		if err := insertWebhook(ctx, tx, w); err != nil {
+			return fmt.Errorf("create webhooks[%d]: %w", i, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit bulk create: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBulkWebhooks(tenantID uuid.UUID, names ...string) []*Webhook {
+	webhooks := make([]*Webhook, len(names))
+	for i, name := range names {
+		webhooks[i] = &Webhook{
+			TenantID: tenantID,
+			Name:     name,
+			URL:      "https://" + name + ".example.com/hooks",
+			Secret:   "secret-" + name,
+			Events:   []string{EventFaceRegistered},
+			Enabled:  true,
+		}
+	}
+	return webhooks
+}
+
+func TestValidateWebhook(t *testing.T) {
+	valid := func() *Webhook {
+		return &Webhook{
+			Name:        "crm",
+			URL:         "https://crm.example.com/hooks",
+			Events:      []string{EventFaceRegistered, EventFaceVerified},
+			Headers:     map[string]string{"X-Api-Key": "abc"},
+			SampleRates: map[string]int{EventFaceVerified: 10},
+		}
+	}
+	require.NoError(t, ValidateWebhook(valid()))
+
+	tests := []struct {
+		name   string
+		mutate func(*Webhook)
+	}{
+		{"short name", func(w *Webhook) { w.Name = " ab " }},
+		{"long name", func(w *Webhook) { w.Name = strings.Repeat("a", 256) }},
+		{"relative url", func(w *Webhook) { w.URL = "/hooks" }},
+		{"non-http url", func(w *Webhook) { w.URL = "ftp://crm.example.com" }},
+		{"long url", func(w *Webhook) { w.URL = "https://crm.example.com/" + strings.Repeat("a", 2048) }},
+		{"no events", func(w *Webhook) { w.Events = nil }},
+		{"unknown event", func(w *Webhook) { w.Events = []string{"face.exploded"} }},
+		{"reserved header", func(w *Webhook) { w.Headers = map[string]string{"X-Rekko-Event": "forged"} }},
+		{"invalid sample rate", func(w *Webhook) { w.SampleRates = map[string]int{EventFaceVerified: 0} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := valid()
+			tt.mutate(w)
+
+			assert.Error(t, ValidateWebhook(w))
+		})
+	}
+}
+
+func TestService_CreateWebhooks(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	newService := func(t *testing.T) (*Service, pgxmock.PgxPoolIface) {
+		pool, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		t.Cleanup(pool.Close)
+		return NewServiceWithDB(pool, logger), pool
+	}
+	expectInsert := func(pool pgxmock.PgxPoolIface, w *Webhook) *pgxmock.ExpectedQuery {
+		return pool.ExpectQuery(`INSERT INTO webhooks`).
+			WithArgs(tenantID, w.Name, w.URL, w.Secret, pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), w.Enabled)
+	}
+
+	t.Run("creates every webhook in one transaction", func(t *testing.T) {
+		svc, pool := newService(t)
+		webhooks := newBulkWebhooks(tenantID, "crm", "gate")
+		ids := []uuid.UUID{uuid.New(), uuid.New()}
+		now := time.Now()
+
+		pool.ExpectBegin()
+		for i, w := range webhooks {
+			expectInsert(pool, w).WillReturnRows(
+				pgxmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(ids[i], now, now),
+			)
+		}
+		pool.ExpectCommit()
+
+		require.NoError(t, svc.CreateWebhooks(ctx, webhooks))
+
+		assert.Equal(t, ids[0], webhooks[0].ID)
+		assert.Equal(t, ids[1], webhooks[1].ID)
+		assert.NoError(t, pool.ExpectationsWereMet())
+	})
+
+	t.Run("an invalid webhook aborts the batch before any insert", func(t *testing.T) {
+		svc, pool := newService(t)
+		webhooks := newBulkWebhooks(tenantID, "crm", "gate", "erp")
+		webhooks[1].URL = "not a url"
+
+		err := svc.CreateWebhooks(ctx, webhooks)
+
+		assert.ErrorIs(t, err, ErrInvalidWebhook)
+		assert.Contains(t, err.Error(), "webhooks[1]")
+		assert.NoError(t, pool.ExpectationsWereMet())
+	})
+
+	t.Run("a failed insert rolls back the batch", func(t *testing.T) {
+		svc, pool := newService(t)
+		webhooks := newBulkWebhooks(tenantID, "crm", "gate")
+		now := time.Now()
+
+		pool.ExpectBegin()
+		expectInsert(pool, webhooks[0]).WillReturnRows(
+			pgxmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(uuid.New(), now, now),
+		)
+		expectInsert(pool, webhooks[1]).WillReturnError(errors.New("connection reset"))
+		pool.ExpectRollback()
+
+		err := svc.CreateWebhooks(ctx, webhooks)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "webhooks[1]")
+		assert.NoError(t, pool.ExpectationsWereMet())
+	})
+
+	t.Run("batch size is bounded", func(t *testing.T) {
+		svc, _ := newService(t)
+
+		assert.ErrorIs(t, svc.CreateWebhooks(ctx, nil), ErrInvalidWebhook)
+
+		names := make([]string, MaxBulkWebhooks+1)
+		for i := range names {
+			names[i] = "hook" + uuid.NewString()[:8]
+		}
+		assert.ErrorIs(t, svc.CreateWebhooks(ctx, newBulkWebhooks(tenantID, names...)), ErrInvalidWebhook)
+	})
+}
@@ -0,0 +1,20 @@
+package webhook
+
+import "context"
+
+type requestIDKey struct{}
+
+// WithRequestID attaches the originating API request ID to ctx so dispatched
+// events can be correlated with the request that triggered them
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	if requestID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID attached with WithRequestID, if any
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
@@ -0,0 +1,223 @@
+package webhook
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event types dispatched to tenant webhooks
+const (
+	EventFaceRegistered          = "face.registered"
+	EventFaceVerified            = "face.verified"
+	EventFaceReenrollSuggested   = "face.reenroll_suggested"
+	EventFaceDeleted             = "face.deleted"
+	EventFaceExpired             = "face.expired"
+	EventFaceSearch              = "face.search"
+	EventWidgetSessionCreated    = "widget.session_created"
+	EventWidgetRegistered        = "widget.registered"
+	EventWidgetLivenessValidated = "widget.liveness_validated"
+	EventWidgetSearched          = "widget.searched"
+	EventQuotaThresholdReached   = "quota.threshold_reached"
+)
+
+// PayloadVersion identifies the envelope and event data shapes below.
+// Bump it together with any breaking change to a data type.
+const PayloadVersion = "v1"
+
+type FaceRegisteredData struct {
+	FaceID       string  `json:"face_id"`
+	ExternalID   string  `json:"external_id"`
+	QualityScore float64 `json:"quality_score"`
+}
+
+type FaceVerifiedData struct {
+	Verified   bool    `json:"verified"`
+	Confidence float64 `json:"confidence"`
+	ExternalID string  `json:"external_id"`
+	LatencyMs  int64   `json:"latency_ms"`
+}
+
+type FaceReenrollSuggestedData struct {
+	ExternalID        string    `json:"external_id"`
+	EnrolledQuality   float64   `json:"enrolled_quality"`
+	Margin            float64   `json:"margin"`
+	RecentConfidences []float64 `json:"recent_confidences"`
+}
+
+type FaceDeletedData struct {
+	ExternalID string `json:"external_id"`
+}
+
+type FaceExpiredData struct {
+	FaceID     string `json:"face_id"`
+	ExternalID string `json:"external_id"`
+	ExpiresAt  string `json:"expires_at"`
+}
+
+type SearchMatch struct {
+	FaceID     string  `json:"face_id"`
+	Similarity float64 `json:"similarity"`
+}
+
+type FaceSearchData struct {
+	MatchesCount int `json:"matches_count"`
+	// TopMatch is null when nothing matched
+	TopMatch *SearchMatch `json:"top_match"`
+	SearchID string       `json:"search_id"`
+}
+
+type WidgetSessionCreatedData struct {
+	SessionID string `json:"session_id"`
+	Origin    string `json:"origin"`
+}
+
+type WidgetRegisteredData struct {
+	FaceID       string  `json:"face_id"`
+	ExternalID   string  `json:"external_id"`
+	QualityScore float64 `json:"quality_score"`
+	SessionID    string  `json:"session_id"`
+}
+
+type WidgetLivenessValidatedData struct {
+	IsLive     bool    `json:"is_live"`
+	Confidence float64 `json:"confidence"`
+	SessionID  string  `json:"session_id"`
+}
+
+type WidgetSearchedData struct {
+	Identified bool   `json:"identified"`
+	SessionID  string `json:"session_id"`
+	// ExternalID and Confidence are only present when a face was identified
+	ExternalID *string  `json:"external_id,omitempty"`
+	Confidence *float64 `json:"confidence,omitempty"`
+}
+
+// QuotaThresholdReachedData reports a max_faces or max_requests_month quota whose usage reached
+// one of the tenant's quota_warnings percentages
+type QuotaThresholdReachedData struct {
+	Quota      string  `json:"quota"`
+	Threshold  int     `json:"threshold"`
+	Used       int64   `json:"used"`
+	Limit      int64   `json:"limit"`
+	Percentage float64 `json:"percentage"`
+	// Period is the month of a max_requests_month warning (YYYY-MM), absent for max_faces
+	Period string `json:"period,omitempty"`
+}
+
+// sampleData holds a representative data value for every event type
+func sampleData() map[string]interface{} {
+	externalID, confidence := "user-123", 0.97
+	return map[string]interface{}{
+		EventFaceRegistered: FaceRegisteredData{
+			FaceID:       "5b0c6a4e-6f2d-4d8e-9a51-2f3c1d7e8b90",
+			ExternalID:   externalID,
+			QualityScore: 0.95,
+		},
+		EventFaceVerified: FaceVerifiedData{
+			Verified:   true,
+			Confidence: 0.93,
+			ExternalID: externalID,
+			LatencyMs:  142,
+		},
+		EventFaceReenrollSuggested: FaceReenrollSuggestedData{
+			ExternalID:        externalID,
+			EnrolledQuality:   0.95,
+			Margin:            0.1,
+			RecentConfidences: []float64{0.82, 0.83, 0.81, 0.84, 0.80},
+		},
+		EventFaceDeleted: FaceDeletedData{
+			ExternalID: externalID,
+		},
+		EventFaceExpired: FaceExpiredData{
+			FaceID:     "5b0c6a4e-6f2d-4d8e-9a51-2f3c1d7e8b90",
+			ExternalID: externalID,
+			ExpiresAt:  "2026-03-08T18:00:00Z",
+		},
+		EventFaceSearch: FaceSearchData{
+			MatchesCount: 1,
+			TopMatch: &SearchMatch{
+				FaceID:     "5b0c6a4e-6f2d-4d8e-9a51-2f3c1d7e8b90",
+				Similarity: confidence,
+			},
+			SearchID: "0e8f1c2a-3b4d-4e5f-8a9b-0c1d2e3f4a5b",
+		},
+		EventWidgetSessionCreated: WidgetSessionCreatedData{
+			SessionID: "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+			Origin:    "https://app.example.com",
+		},
+		EventWidgetRegistered: WidgetRegisteredData{
+			FaceID:       "5b0c6a4e-6f2d-4d8e-9a51-2f3c1d7e8b90",
+			ExternalID:   externalID,
+			QualityScore: 0.95,
+			SessionID:    "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+		},
+		EventWidgetLivenessValidated: WidgetLivenessValidatedData{
+			IsLive:     true,
+			Confidence: 0.91,
+			SessionID:  "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+		},
+		EventWidgetSearched: WidgetSearchedData{
+			Identified: true,
+			SessionID:  "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+			ExternalID: &externalID,
+			Confidence: &confidence,
+		},
+		EventQuotaThresholdReached: QuotaThresholdReachedData{
+			Quota:      "max_requests_month",
+			Threshold:  90,
+			Used:       9200,
+			Limit:      10000,
+			Percentage: 92,
+			Period:     "2026-01",
+		},
+	}
+}
+
+// EventTypes lists every event type with a documented payload, sorted
+func EventTypes() []string {
+	samples := sampleData()
+	types := make([]string, 0, len(samples))
+	for eventType := range samples {
+		types = append(types, eventType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// SamplePayload builds an example envelope for eventType with the same constructor
+// used by Dispatch. Returns false for unknown event types.
+func SamplePayload(tenantID uuid.UUID, eventType string) (EventPayload, bool) {
+	data, ok := sampleData()[eventType]
+	if !ok {
+		return EventPayload{}, false
+	}
+
+	ctx := WithRequestID(context.Background(), "3f6c1a52-8d7e-4b1f-9c2a-6e5d4c3b2a10")
+	event := NewEventPayload(ctx, tenantID, eventType, data)
+	event.Timestamp = time.Date(2026, 1, 4, 12, 34, 56, 0, time.UTC)
+	event.EventID = uuid.MustParse("5d4c3b2a-1f0e-4d9c-8b7a-6f5e4d3c2b1a")
+	event.DeliveryID = uuid.MustParse("9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d")
+	return event, true
+}
+
+// EventSchema describes the envelope for eventType as a JSON Schema object.
+// Returns false for unknown event types.
+func EventSchema(eventType string) (map[string]interface{}, bool) {
+	data, ok := sampleData()[eventType]
+	if !ok {
+		return nil, false
+	}
+
+	schema := jsonSchema(reflect.TypeOf(EventPayload{}))
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = eventType
+
+	properties := schema["properties"].(map[string]interface{})
+	properties["type"] = map[string]interface{}{"type": "string", "const": eventType}
+	properties["data"] = jsonSchema(reflect.TypeOf(data))
+	return schema, true
+}
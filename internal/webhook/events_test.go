@@ -0,0 +1,135 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSamplePayload_MatchesSchema(t *testing.T) {
+	tenantID := uuid.New()
+
+	for _, eventType := range EventTypes() {
+		t.Run(eventType, func(t *testing.T) {
+			sample, ok := SamplePayload(tenantID, eventType)
+			require.True(t, ok)
+			schema, ok := EventSchema(eventType)
+			require.True(t, ok)
+
+			raw, err := json.Marshal(sample)
+			require.NoError(t, err)
+			var decoded interface{}
+			require.NoError(t, json.Unmarshal(raw, &decoded))
+
+			// Round-trip the schema too so it is compared as a client would read it
+			rawSchema, err := json.Marshal(schema)
+			require.NoError(t, err)
+			var decodedSchema map[string]interface{}
+			require.NoError(t, json.Unmarshal(rawSchema, &decodedSchema))
+
+			assert.NoError(t, validate(decodedSchema, decoded, "$"))
+			assert.Equal(t, eventType, sample.Type)
+			assert.Equal(t, tenantID, sample.TenantID)
+			assert.NotEmpty(t, sample.RequestID)
+		})
+	}
+}
+
+func TestSamplePayload_UnknownEvent(t *testing.T) {
+	_, ok := SamplePayload(uuid.New(), "face.unknown")
+	assert.False(t, ok)
+
+	_, ok = EventSchema("face.unknown")
+	assert.False(t, ok)
+}
+
+func TestEventSchema_OptionalAndNullableFields(t *testing.T) {
+	schema, ok := EventSchema(EventWidgetSearched)
+	require.True(t, ok)
+
+	data := schema["properties"].(map[string]interface{})["data"].(map[string]interface{})
+	assert.ElementsMatch(t, []string{"identified", "session_id"}, data["required"])
+
+	schema, ok = EventSchema(EventFaceSearch)
+	require.True(t, ok)
+
+	data = schema["properties"].(map[string]interface{})["data"].(map[string]interface{})
+	topMatch := data["properties"].(map[string]interface{})["top_match"].(map[string]interface{})
+	assert.Equal(t, []string{"object", "null"}, topMatch["type"])
+}
+
+// validate checks the subset of JSON Schema produced by jsonSchema
+func validate(schema map[string]interface{}, value interface{}, path string) error {
+	if want, ok := schema["const"]; ok && want != value {
+		return fmt.Errorf("%s: want %v, got %v", path, want, value)
+	}
+
+	types := []interface{}{schema["type"]}
+	if list, ok := schema["type"].([]interface{}); ok {
+		types = list
+	}
+	if schema["type"] == nil {
+		return nil
+	}
+
+	for _, typ := range types {
+		if matchesType(typ.(string), value) {
+			switch v := value.(type) {
+			case map[string]interface{}:
+				properties, _ := schema["properties"].(map[string]interface{})
+				for _, name := range schema["required"].([]interface{}) {
+					if _, ok := v[name.(string)]; !ok {
+						return fmt.Errorf("%s: missing required %q", path, name)
+					}
+				}
+				for name, child := range v {
+					childSchema, ok := properties[name].(map[string]interface{})
+					if !ok {
+						return fmt.Errorf("%s: unexpected property %q", path, name)
+					}
+					if err := validate(childSchema, child, path+"."+name); err != nil {
+						return err
+					}
+				}
+			case []interface{}:
+				for i, item := range v {
+					if err := validate(schema["items"].(map[string]interface{}), item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: %v does not match type %v", path, value, schema["type"])
+}
+
+func matchesType(typ string, value interface{}) bool {
+	switch typ {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == float64(int64(f))
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "null":
+		return value == nil
+	}
+	return false
+}
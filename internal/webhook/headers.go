@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// MaxCustomHeaders caps how many custom headers a webhook may configure
+const MaxCustomHeaders = 20
+
+var ErrInvalidHeader = errors.New("invalid webhook header")
+
+// reservedHeaders are set by every delivery and cannot be replaced by custom headers
+var reservedHeaders = map[string]bool{
+	"Content-Type":      true,
+	"Content-Length":    true,
+	"User-Agent":        true,
+	"Host":              true,
+	"Connection":        true,
+	"Transfer-Encoding": true,
+}
+
+// ValidateHeaders checks custom webhook headers: RFC 7230 token names, no line breaks in
+// values, and no names that would collide with the delivery's own headers
+func ValidateHeaders(headers map[string]string) error {
+	if len(headers) > MaxCustomHeaders {
+		return fmt.Errorf("%w: at most %d headers are allowed", ErrInvalidHeader, MaxCustomHeaders)
+	}
+	for name, value := range headers {
+		if !isToken(name) {
+			return fmt.Errorf("%w: %q is not a valid header name", ErrInvalidHeader, name)
+		}
+		canonical := http.CanonicalHeaderKey(name)
+		if reservedHeaders[canonical] || strings.HasPrefix(canonical, "X-Rekko-") {
+			return fmt.Errorf("%w: %q is set by Rekko and cannot be overridden", ErrInvalidHeader, name)
+		}
+		if strings.ContainsAny(value, "\r\n\x00") {
+			return fmt.Errorf("%w: value of %q contains a line break", ErrInvalidHeader, name)
+		}
+	}
+	return nil
+}
+
+// RedactedHeaderValue replaces custom header values in API responses
+const RedactedHeaderValue = "[REDACTED]"
+
+// RedactHeaders returns the header names with their values replaced, so a response shows which
+// headers are configured without echoing the credentials they usually carry
+func RedactHeaders(headers map[string]string) map[string]string {
+	if len(headers) == 0 {
+		return nil
+	}
+	redacted := make(map[string]string, len(headers))
+	for name := range headers {
+		redacted[name] = RedactedHeaderValue
+	}
+	return redacted
+}
+
+// isToken reports whether s is a non-empty RFC 7230 token
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
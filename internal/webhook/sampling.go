@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// MaxSampleRate caps the N of a "1 in N" sample rate
+const MaxSampleRate = 10000
+
+var ErrInvalidSampleRate = errors.New("invalid webhook sample rate")
+
+// outcomeData is implemented by event data that carries a success or failure outcome.
+// Failures are never sampled out, so consumers see every rejected verification.
+type outcomeData interface {
+	succeeded() bool
+}
+
+func (d FaceVerifiedData) succeeded() bool            { return d.Verified }
+func (d FaceSearchData) succeeded() bool              { return d.MatchesCount > 0 }
+func (d WidgetLivenessValidatedData) succeeded() bool { return d.IsLive }
+func (d WidgetSearchedData) succeeded() bool          { return d.Identified }
+
+// ValidateSampleRates checks per-event sample rates: known event types and 1 <= N <= MaxSampleRate,
+// where 1 delivers every event
+func ValidateSampleRates(rates map[string]int) error {
+	known := sampleData()
+	for eventType, n := range rates {
+		if _, ok := known[eventType]; !ok {
+			return fmt.Errorf("%w: unknown event type %q", ErrInvalidSampleRate, eventType)
+		}
+		if n < 1 || n > MaxSampleRate {
+			return fmt.Errorf("%w: rate of %q must be between 1 and %d", ErrInvalidSampleRate, eventType, MaxSampleRate)
+		}
+	}
+	return nil
+}
+
+type sampleKey struct {
+	webhookID uuid.UUID
+	eventType string
+}
+
+// sampler decides which dispatched events a webhook receives. With a rate of N it delivers the
+// 1st, (N+1)th, (2N+1)th... successful event of that type, counted per webhook in this process,
+// and always delivers events whose data reports a failure.
+type sampler struct {
+	mu     sync.Mutex
+	counts map[sampleKey]uint64
+}
+
+func newSampler() *sampler {
+	return &sampler{counts: make(map[sampleKey]uint64)}
+}
+
+func (s *sampler) keep(webhook *Webhook, eventType string, data interface{}) bool {
+	n := webhook.SampleRates[eventType]
+	if n <= 1 {
+		return true
+	}
+	if outcome, ok := data.(outcomeData); ok && !outcome.succeeded() {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := sampleKey{webhookID: webhook.ID, eventType: eventType}
+	count := s.counts[key]
+	s.counts[key] = count + 1
+	return count%uint64(n) == 0
+}
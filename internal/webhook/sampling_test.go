@@ -0,0 +1,100 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampler_DeliversOneInNSuccesses(t *testing.T) {
+	s := newSampler()
+	wh := &Webhook{ID: uuid.New(), SampleRates: map[string]int{EventFaceVerified: 4}}
+
+	delivered := 0
+	for i := 0; i < 20; i++ {
+		if s.keep(wh, EventFaceVerified, FaceVerifiedData{Verified: true}) {
+			delivered++
+		}
+	}
+	assert.Equal(t, 5, delivered)
+
+	// The first success of a new webhook is always delivered
+	other := &Webhook{ID: uuid.New(), SampleRates: wh.SampleRates}
+	assert.True(t, s.keep(other, EventFaceVerified, FaceVerifiedData{Verified: true}))
+}
+
+func TestSampler_AlwaysDeliversFailures(t *testing.T) {
+	s := newSampler()
+	wh := &Webhook{ID: uuid.New(), SampleRates: map[string]int{
+		EventFaceVerified:            100,
+		EventFaceSearch:              100,
+		EventWidgetLivenessValidated: 100,
+		EventWidgetSearched:          100,
+	}}
+
+	failures := map[string]interface{}{
+		EventFaceVerified:            FaceVerifiedData{Verified: false},
+		EventFaceSearch:              FaceSearchData{MatchesCount: 0},
+		EventWidgetLivenessValidated: WidgetLivenessValidatedData{IsLive: false},
+		EventWidgetSearched:          WidgetSearchedData{Identified: false},
+	}
+	for eventType, data := range failures {
+		for i := 0; i < 10; i++ {
+			assert.True(t, s.keep(wh, eventType, data), "%s failure %d", eventType, i)
+		}
+	}
+
+	// Failures don't advance the count, so the next success is still the first one
+	assert.True(t, s.keep(wh, EventFaceVerified, FaceVerifiedData{Verified: true}))
+	assert.False(t, s.keep(wh, EventFaceVerified, FaceVerifiedData{Verified: true}))
+}
+
+func TestSampler_Unsampled(t *testing.T) {
+	s := newSampler()
+	wh := &Webhook{ID: uuid.New(), SampleRates: map[string]int{EventFaceVerified: 1}}
+
+	for i := 0; i < 5; i++ {
+		assert.True(t, s.keep(wh, EventFaceVerified, FaceVerifiedData{Verified: true}), "rate 1 delivers everything")
+		assert.True(t, s.keep(wh, EventFaceRegistered, FaceRegisteredData{}), "no rate for the event")
+	}
+}
+
+func TestSampler_EventsWithoutOutcome(t *testing.T) {
+	s := newSampler()
+	wh := &Webhook{ID: uuid.New(), SampleRates: map[string]int{EventFaceRegistered: 2}}
+
+	got := []bool{
+		s.keep(wh, EventFaceRegistered, FaceRegisteredData{}),
+		s.keep(wh, EventFaceRegistered, FaceRegisteredData{}),
+		s.keep(wh, EventFaceRegistered, FaceRegisteredData{}),
+	}
+	assert.Equal(t, []bool{true, false, true}, got)
+}
+
+func TestValidateSampleRates(t *testing.T) {
+	tests := []struct {
+		name    string
+		rates   map[string]int
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"valid", map[string]int{EventFaceVerified: 10, EventFaceSearch: 1}, false},
+		{"max", map[string]int{EventFaceVerified: MaxSampleRate}, false},
+		{"zero", map[string]int{EventFaceVerified: 0}, true},
+		{"negative", map[string]int{EventFaceVerified: -3}, true},
+		{"above max", map[string]int{EventFaceVerified: MaxSampleRate + 1}, true},
+		{"unknown event", map[string]int{"face.teleported": 10}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSampleRates(tt.rates)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidSampleRate)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package webhook
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultTenantConcurrency is how many deliveries of one tenant run at the same time
+	DefaultTenantConcurrency = 2
+	// maxConcurrentDeliveries bounds the deliveries in flight across all tenants
+	maxConcurrentDeliveries = 10
+)
+
+// deliveryScheduler runs queued deliveries in the background, capping how many run at
+// once per tenant and overall. Jobs that don't fit are skipped and stay pending for the
+// next poll, so a tenant with slow endpoints only ever ties up its own slots.
+type deliveryScheduler struct {
+	perTenant int
+	total     int
+
+	mu       sync.Mutex
+	active   map[uuid.UUID]int
+	running  int
+	inFlight map[uuid.UUID]struct{}
+	// finished holds jobs that returned since the last poll, which the queue read may
+	// still have seen as pending
+	finished map[uuid.UUID]struct{}
+	wg       sync.WaitGroup
+}
+
+func newDeliveryScheduler(perTenant, total int) *deliveryScheduler {
+	if perTenant < 1 {
+		perTenant = DefaultTenantConcurrency
+	}
+	return &deliveryScheduler{
+		perTenant: perTenant,
+		total:     total,
+		active:    make(map[uuid.UUID]int),
+		inFlight:  make(map[uuid.UUID]struct{}),
+		finished:  make(map[uuid.UUID]struct{}),
+	}
+}
+
+// poll must be called before reading the queue for the next schedule call
+func (s *deliveryScheduler) poll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.finished = make(map[uuid.UUID]struct{})
+}
+
+// free returns how many more deliveries fit under the global cap
+func (s *deliveryScheduler) free() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.total - s.running
+}
+
+// schedule starts deliver for each job that fits within the caps, in the given order,
+// and returns how many were started. Jobs in flight or finished since poll are ignored;
+// skip, when set, is called for every other job that did not fit.
+func (s *deliveryScheduler) schedule(jobs []*WebhookJob, deliver func(*WebhookJob), skip func(*WebhookJob)) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	started := 0
+	for _, job := range jobs {
+		if _, ok := s.inFlight[job.ID]; ok {
+			continue
+		}
+		if _, ok := s.finished[job.ID]; ok {
+			continue
+		}
+		if s.running >= s.total || s.active[job.TenantID] >= s.perTenant {
+			if skip != nil {
+				skip(job)
+			}
+			continue
+		}
+
+		s.inFlight[job.ID] = struct{}{}
+		s.active[job.TenantID]++
+		s.running++
+		started++
+
+		s.wg.Add(1)
+		go func(job *WebhookJob) {
+			defer s.wg.Done()
+			defer s.release(job)
+			deliver(job)
+		}(job)
+	}
+
+	return started
+}
+
+func (s *deliveryScheduler) release(job *WebhookJob) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.inFlight, job.ID)
+	s.finished[job.ID] = struct{}{}
+	s.running--
+	if s.active[job.TenantID]--; s.active[job.TenantID] <= 0 {
+		delete(s.active, job.TenantID)
+	}
+}
+
+// wait blocks until every started delivery has returned
+func (s *deliveryScheduler) wait() {
+	s.wg.Wait()
+}
@@ -0,0 +1,155 @@
+package webhook
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newJobs(tenantID uuid.UUID, n int) []*WebhookJob {
+	jobs := make([]*WebhookJob, n)
+	for i := range jobs {
+		jobs[i] = &WebhookJob{ID: uuid.New(), TenantID: tenantID}
+	}
+	return jobs
+}
+
+// slowEndpoint blocks the deliveries of one tenant until released, tracking their peak concurrency
+type slowEndpoint struct {
+	tenantID uuid.UUID
+	unblock  chan struct{}
+	running  atomic.Int32
+	peak     atomic.Int32
+}
+
+func (e *slowEndpoint) deliver(job *WebhookJob) bool {
+	if job.TenantID != e.tenantID {
+		return false
+	}
+	n := e.running.Add(1)
+	for {
+		peak := e.peak.Load()
+		if n <= peak || e.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	<-e.unblock
+	e.running.Add(-1)
+	return true
+}
+
+func TestDeliveryScheduler_SlowTenantDoesNotBlockOthers(t *testing.T) {
+	noisy, quiet := uuid.New(), uuid.New()
+	slow := &slowEndpoint{tenantID: noisy, unblock: make(chan struct{})}
+	quietDelivered := make(chan uuid.UUID, 4)
+
+	deliver := func(job *WebhookJob) {
+		if !slow.deliver(job) {
+			quietDelivered <- job.ID
+		}
+	}
+
+	s := newDeliveryScheduler(2, 10)
+	noisyJobs := newJobs(noisy, 5)
+	quietJobs := newJobs(quiet, 1)
+
+	// The noisy tenant's backlog is older, so it comes first in the batch
+	s.poll()
+	started := s.schedule(append(append([]*WebhookJob{}, noisyJobs...), quietJobs...), deliver, nil)
+	assert.Equal(t, 3, started, "two noisy deliveries up to the cap plus the quiet one")
+
+	select {
+	case id := <-quietDelivered:
+		assert.Equal(t, quietJobs[0].ID, id)
+	case <-time.After(time.Second):
+		t.Fatal("quiet tenant delivery blocked behind the slow tenant")
+	}
+
+	// Later events of the quiet tenant keep flowing while the noisy one is still stuck
+	moreQuiet := newJobs(quiet, 2)
+	s.poll()
+	assert.Equal(t, 2, s.schedule(append(append([]*WebhookJob{}, noisyJobs...), moreQuiet...), deliver, nil))
+	for range moreQuiet {
+		select {
+		case <-quietDelivered:
+		case <-time.After(time.Second):
+			t.Fatal("quiet tenant delivery blocked behind the slow tenant")
+		}
+	}
+
+	close(slow.unblock)
+	s.wait()
+	assert.Equal(t, int32(2), slow.peak.Load(), "noisy tenant never exceeds its cap")
+
+	// Capped jobs stayed pending and are picked up by a later poll
+	s.poll()
+	assert.Equal(t, 2, s.schedule(noisyJobs[2:], deliver, nil))
+	s.wait()
+}
+
+func TestDeliveryScheduler_GlobalCap(t *testing.T) {
+	unblock := make(chan struct{})
+	var delivered atomic.Int32
+	deliver := func(job *WebhookJob) {
+		<-unblock
+		delivered.Add(1)
+	}
+
+	s := newDeliveryScheduler(2, 3)
+	var jobs []*WebhookJob
+	for i := 0; i < 4; i++ {
+		jobs = append(jobs, newJobs(uuid.New(), 1)...)
+	}
+
+	s.poll()
+	assert.Equal(t, 3, s.free())
+	var skipped []*WebhookJob
+	assert.Equal(t, 3, s.schedule(jobs, deliver, func(job *WebhookJob) {
+		skipped = append(skipped, job)
+	}))
+	assert.Equal(t, []*WebhookJob{jobs[3]}, skipped, "the job over the cap is handed back")
+	assert.Equal(t, 0, s.free())
+
+	close(unblock)
+	s.wait()
+	assert.Equal(t, int32(3), delivered.Load())
+}
+
+func TestDeliveryScheduler_NeverDeliversTwice(t *testing.T) {
+	tenantID := uuid.New()
+	job := newJobs(tenantID, 1)[0]
+	unblock := make(chan struct{})
+	var mu sync.Mutex
+	calls := 0
+	deliver := func(*WebhookJob) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		<-unblock
+	}
+
+	s := newDeliveryScheduler(5, 10)
+	s.poll()
+	require.Equal(t, 1, s.schedule([]*WebhookJob{job}, deliver, nil))
+
+	// Still pending in the queue while in flight
+	assert.Equal(t, 0, s.schedule([]*WebhookJob{job}, deliver, nil))
+
+	// Finished after the queue was read: the stale pending row is skipped until the next poll
+	close(unblock)
+	s.wait()
+	assert.Equal(t, 0, s.schedule([]*WebhookJob{job}, deliver, nil))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 1, calls)
+}
+
+func TestNewDeliveryScheduler_DefaultsInvalidCap(t *testing.T) {
+	assert.Equal(t, DefaultTenantConcurrency, newDeliveryScheduler(0, 10).perTenant)
+}
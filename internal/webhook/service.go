@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -11,25 +12,73 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// Grace window of a rotated secret, see RotateSecret
+const (
+	DefaultSecretGracePeriod = 24 * time.Hour
+	MaxSecretGracePeriod     = 7 * 24 * time.Hour
+)
+
+var (
+	ErrWebhookNotFound    = errors.New("webhook not found")
+	ErrInvalidGracePeriod = errors.New("invalid secret grace period")
+)
+
+// ValidateGracePeriod checks the window during which a rotated secret keeps signing deliveries
+func ValidateGracePeriod(grace time.Duration) error {
+	if grace <= 0 || grace > MaxSecretGracePeriod {
+		return fmt.Errorf("%w: must be positive and at most %s, got %s", ErrInvalidGracePeriod, MaxSecretGracePeriod, grace)
+	}
+	return nil
+}
+
+// DB is the subset of *pgxpool.Pool used by Service
+type DB interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// UsageTracker counts accepted deliveries per tenant and day
+type UsageTracker interface {
+	IncrementDaily(ctx context.Context, tenantID uuid.UUID, date time.Time, field string, amount int) error
+}
+
 type Service struct {
-	db     *pgxpool.Pool
-	client *http.Client
-	logger *slog.Logger
+	db           DB
+	client       *http.Client
+	logger       *slog.Logger
+	sampler      *sampler
+	usageTracker UsageTracker
 }
 
 func NewService(db *pgxpool.Pool, logger *slog.Logger) *Service {
+	return NewServiceWithDB(db, logger)
+}
+
+// NewServiceWithDB creates a service with a custom DB interface
+func NewServiceWithDB(db DB, logger *slog.Logger) *Service {
 	return &Service{
 		db:     db,
 		logger: logger,
 		client: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		sampler: newSampler(),
 	}
 }
 
+// WithUsageTracker counts the deliveries endpoints accept in the tenant's webhook_deliveries usage
+func (s *Service) WithUsageTracker(tracker UsageTracker) *Service {
+	s.usageTracker = tracker
+	return s
+}
+
 // Dispatch sends an event to all enabled webhooks for the tenant
 func (s *Service) Dispatch(ctx context.Context, tenantID uuid.UUID, eventType string, data interface{}) error {
 	webhooks, err := s.GetWebhooksByEvent(ctx, tenantID, eventType)
@@ -44,12 +93,16 @@ func (s *Service) Dispatch(ctx context.Context, tenantID uuid.UUID, eventType st
 		return nil
 	}
 
+	// One event for all webhooks, so they all see the same event_id
+	event := NewEventPayload(ctx, tenantID, eventType, data)
+
 	for _, wh := range webhooks {
-		event := EventPayload{
-			Type:      eventType,
-			Timestamp: time.Now().UTC(),
-			TenantID:  tenantID,
-			Data:      data,
+		if !s.sampler.keep(wh, eventType, data) {
+			s.logger.Debug("webhook event sampled out",
+				"webhook_id", wh.ID,
+				"event_type", eventType,
+				"sample_rate", wh.SampleRates[eventType])
+			continue
 		}
 
 		// Dispatch asynchronously (best-effort)
@@ -61,12 +114,14 @@ func (s *Service) Dispatch(ctx context.Context, tenantID uuid.UUID, eventType st
 					"webhook_id", w.ID,
 					"webhook_name", w.Name,
 					"event_type", e.Type,
+					"request_id", e.RequestID,
 					"error", err)
 			} else {
 				s.logger.Info("webhook dispatched",
 					"webhook_id", w.ID,
 					"webhook_name", w.Name,
-					"event_type", e.Type)
+					"event_type", e.Type,
+					"request_id", e.RequestID)
 			}
 		}(wh, event)
 	}
@@ -75,26 +130,23 @@ func (s *Service) Dispatch(ctx context.Context, tenantID uuid.UUID, eventType st
 }
 
 func (s *Service) Send(ctx context.Context, webhook *Webhook, event EventPayload) error {
-	payload, err := json.Marshal(event)
+	req, payload, err := newDeliveryRequest(ctx, webhook, event)
 	if err != nil {
-		return fmt.Errorf("marshal event: %w", err)
+		return err
 	}
 
-	signature := Sign(webhook.Secret, payload)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(payload))
-	if err != nil {
-		return fmt.Errorf("create request: %w", err)
+	if err := s.post(req); err != nil {
+		return s.enqueue(ctx, webhook.ID, event.Type, payload, err.Error())
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Rekko-Signature", signature)
-	req.Header.Set("X-Rekko-Event", event.Type)
-	req.Header.Set("User-Agent", "Rekko-Webhook/1.0")
+	return s.delivered(ctx, webhook)
+}
 
+// post makes one delivery attempt, failing on a transport error or an HTTP error status
+func (s *Service) post(req *http.Request) error {
 	resp, err := s.client.Do(req)
 	if err != nil {
-		return s.enqueue(ctx, webhook.ID, event.Type, payload, err.Error())
+		return err
 	}
 	defer func() {
 		_, _ = io.Copy(io.Discard, resp.Body)
@@ -102,12 +154,27 @@ func (s *Service) Send(ctx context.Context, webhook *Webhook, event EventPayload
 	}()
 
 	if resp.StatusCode >= 400 {
-		return s.enqueue(ctx, webhook.ID, event.Type, payload, fmt.Sprintf("HTTP %d", resp.StatusCode))
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
 	}
+	return nil
+}
 
+// delivered records an accepted delivery, first attempt or queued retry alike
+func (s *Service) delivered(ctx context.Context, webhook *Webhook) error {
+	s.trackDelivery(ctx, webhook.TenantID)
 	return s.updateLastTriggered(ctx, webhook.ID)
 }
 
+// trackDelivery counts an accepted delivery (best-effort)
+func (s *Service) trackDelivery(ctx context.Context, tenantID uuid.UUID) {
+	if s.usageTracker == nil {
+		return
+	}
+	if err := s.usageTracker.IncrementDaily(ctx, tenantID, time.Now().UTC(), "webhook_deliveries", 1); err != nil {
+		s.logger.Warn("failed to track webhook delivery", "error", err, "tenant_id", tenantID)
+	}
+}
+
 func (s *Service) enqueue(ctx context.Context, webhookID uuid.UUID, eventType string, payload []byte, errorMsg string) error {
 	query := `
 		INSERT INTO webhook_queue (webhook_id, event_type, payload, next_retry_at, last_error)
@@ -130,7 +197,8 @@ func (s *Service) updateLastTriggered(ctx context.Context, webhookID uuid.UUID)
 
 func (s *Service) GetWebhooksByTenant(ctx context.Context, tenantID uuid.UUID) ([]*Webhook, error) {
 	query := `
-		SELECT id, tenant_id, name, url, secret, events, enabled, last_triggered_at, created_at, updated_at
+		SELECT id, tenant_id, name, url, secret, COALESCE(previous_secret, ''), previous_secret_expires_at,
+		       events, headers, sample_rates, enabled, last_triggered_at, created_at, updated_at
 		FROM webhooks
 		WHERE tenant_id = $1
 		ORDER BY created_at DESC
@@ -145,11 +213,11 @@ func (s *Service) GetWebhooksByTenant(ctx context.Context, tenantID uuid.UUID) (
 	var webhooks []*Webhook
 	for rows.Next() {
 		var w Webhook
-		var eventsJSON []byte
+		var eventsJSON, headersJSON, sampleRatesJSON []byte
 
 		err := rows.Scan(
-			&w.ID, &w.TenantID, &w.Name, &w.URL, &w.Secret,
-			&eventsJSON, &w.Enabled, &w.LastTriggeredAt,
+			&w.ID, &w.TenantID, &w.Name, &w.URL, &w.Secret, &w.PreviousSecret, &w.PreviousSecretExpiresAt,
+			&eventsJSON, &headersJSON, &sampleRatesJSON, &w.Enabled, &w.LastTriggeredAt,
 			&w.CreatedAt, &w.UpdatedAt,
 		)
 		if err != nil {
@@ -159,6 +227,12 @@ func (s *Service) GetWebhooksByTenant(ctx context.Context, tenantID uuid.UUID) (
 		if err := json.Unmarshal(eventsJSON, &w.Events); err != nil {
 			return nil, fmt.Errorf("unmarshal events: %w", err)
 		}
+		if err := json.Unmarshal(headersJSON, &w.Headers); err != nil {
+			return nil, fmt.Errorf("unmarshal headers: %w", err)
+		}
+		if err := json.Unmarshal(sampleRatesJSON, &w.SampleRates); err != nil {
+			return nil, fmt.Errorf("unmarshal sample rates: %w", err)
+		}
 
 		webhooks = append(webhooks, &w)
 	}
@@ -168,7 +242,8 @@ func (s *Service) GetWebhooksByTenant(ctx context.Context, tenantID uuid.UUID) (
 
 func (s *Service) GetWebhooksByEvent(ctx context.Context, tenantID uuid.UUID, eventType string) ([]*Webhook, error) {
 	query := `
-		SELECT id, tenant_id, name, url, secret, events, enabled, last_triggered_at, created_at, updated_at
+		SELECT id, tenant_id, name, url, secret, COALESCE(previous_secret, ''), previous_secret_expires_at,
+		       events, headers, sample_rates, enabled, last_triggered_at, created_at, updated_at
 		FROM webhooks
 		WHERE tenant_id = $1 AND enabled = true AND events @> $2::jsonb
 	`
@@ -184,11 +259,11 @@ func (s *Service) GetWebhooksByEvent(ctx context.Context, tenantID uuid.UUID, ev
 	var webhooks []*Webhook
 	for rows.Next() {
 		var w Webhook
-		var eventsJSON []byte
+		var eventsJSON, headersJSON, sampleRatesJSON []byte
 
 		err := rows.Scan(
-			&w.ID, &w.TenantID, &w.Name, &w.URL, &w.Secret,
-			&eventsJSON, &w.Enabled, &w.LastTriggeredAt,
+			&w.ID, &w.TenantID, &w.Name, &w.URL, &w.Secret, &w.PreviousSecret, &w.PreviousSecretExpiresAt,
+			&eventsJSON, &headersJSON, &sampleRatesJSON, &w.Enabled, &w.LastTriggeredAt,
 			&w.CreatedAt, &w.UpdatedAt,
 		)
 		if err != nil {
@@ -198,6 +273,12 @@ func (s *Service) GetWebhooksByEvent(ctx context.Context, tenantID uuid.UUID, ev
 		if err := json.Unmarshal(eventsJSON, &w.Events); err != nil {
 			return nil, fmt.Errorf("unmarshal events: %w", err)
 		}
+		if err := json.Unmarshal(headersJSON, &w.Headers); err != nil {
+			return nil, fmt.Errorf("unmarshal headers: %w", err)
+		}
+		if err := json.Unmarshal(sampleRatesJSON, &w.SampleRates); err != nil {
+			return nil, fmt.Errorf("unmarshal sample rates: %w", err)
+		}
 
 		webhooks = append(webhooks, &w)
 	}
@@ -206,27 +287,58 @@ func (s *Service) GetWebhooksByEvent(ctx context.Context, tenantID uuid.UUID, ev
 }
 
 func (s *Service) CreateWebhook(ctx context.Context, webhook *Webhook) error {
+	if err := ValidateHeaders(webhook.Headers); err != nil {
+		return err
+	}
+	if err := ValidateSampleRates(webhook.SampleRates); err != nil {
+		return err
+	}
+
+	if err := insertWebhook(ctx, s.db, webhook); err != nil {
+		return fmt.Errorf("create webhook: %w", err)
+	}
+
+	return nil
+}
+
+// rowQuerier runs single-row queries on a pool or a transaction
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// insertWebhook inserts webhook and fills its ID and timestamps
+func insertWebhook(ctx context.Context, db rowQuerier, webhook *Webhook) error {
 	eventsJSON, err := json.Marshal(webhook.Events)
 	if err != nil {
 		return fmt.Errorf("marshal events: %w", err)
 	}
+	headers := webhook.Headers
+	if headers == nil {
+		headers = map[string]string{}
+	}
+	headersJSON, err := json.Marshal(headers)
+	if err != nil {
+		return fmt.Errorf("marshal headers: %w", err)
+	}
+	sampleRates := webhook.SampleRates
+	if sampleRates == nil {
+		sampleRates = map[string]int{}
+	}
+	sampleRatesJSON, err := json.Marshal(sampleRates)
+	if err != nil {
+		return fmt.Errorf("marshal sample rates: %w", err)
+	}
 
 	query := `
-		INSERT INTO webhooks (tenant_id, name, url, secret, events, enabled)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO webhooks (tenant_id, name, url, secret, events, headers, sample_rates, enabled)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING id, created_at, updated_at
 	`
 
-	err = s.db.QueryRow(ctx, query,
+	return db.QueryRow(ctx, query,
 		webhook.TenantID, webhook.Name, webhook.URL,
-		webhook.Secret, eventsJSON, webhook.Enabled,
+		webhook.Secret, eventsJSON, headersJSON, sampleRatesJSON, webhook.Enabled,
 	).Scan(&webhook.ID, &webhook.CreatedAt, &webhook.UpdatedAt)
-
-	if err != nil {
-		return fmt.Errorf("create webhook: %w", err)
-	}
-
-	return nil
 }
 
 func (s *Service) DeleteWebhook(ctx context.Context, tenantID, webhookID uuid.UUID) error {
@@ -238,8 +350,84 @@ func (s *Service) DeleteWebhook(ctx context.Context, tenantID, webhookID uuid.UU
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("webhook not found")
+		return ErrWebhookNotFound
 	}
 
 	return nil
 }
+
+// RotateSecret replaces the webhook's signing secret and returns the new one. Until the returned
+// expiry, deliveries are signed with both secrets (see VerifyDelivery) so consumers can switch
+// without rejecting any; rotating again inside the window drops the older secret right away.
+func (s *Service) RotateSecret(ctx context.Context, tenantID, webhookID uuid.UUID, grace time.Duration) (string, time.Time, error) {
+	if err := ValidateGracePeriod(grace); err != nil {
+		return "", time.Time{}, err
+	}
+
+	secret, err := GenerateSecret()
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("generate secret: %w", err)
+	}
+
+	query := `
+		UPDATE webhooks
+		SET previous_secret = secret, previous_secret_expires_at = NOW() + $3::interval,
+		    secret = $4, updated_at = NOW()
+		WHERE id = $1 AND tenant_id = $2
+		RETURNING previous_secret_expires_at
+	`
+
+	var expiresAt time.Time
+	err = s.db.QueryRow(ctx, query, webhookID, tenantID, grace, secret).Scan(&expiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", time.Time{}, ErrWebhookNotFound
+	}
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("rotate webhook secret: %w", err)
+	}
+
+	return secret, expiresAt, nil
+}
+
+// newDeliveryRequest builds the signed HTTP request for a single delivery attempt
+// Every attempt (including worker retries) gets its own delivery ID but keeps the event ID,
+// which the payload queued for retries carries along
+func newDeliveryRequest(ctx context.Context, webhook *Webhook, event EventPayload) (*http.Request, []byte, error) {
+	if event.EventID == uuid.Nil {
+		// Events built without NewEventPayload, or queued before event IDs existed
+		event.EventID = uuid.New()
+	}
+	event.DeliveryID = uuid.New()
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshal event: %w", err)
+	}
+
+	signature := Sign(webhook.Secret, payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, nil, fmt.Errorf("create request: %w", err)
+	}
+
+	// Custom headers go first so the delivery's own headers always win
+	for name, value := range webhook.Headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Rekko-Signature", signature)
+	if webhook.signsWithPrevious(time.Now()) {
+		req.Header.Set("X-Rekko-Signature-Previous", Sign(webhook.PreviousSecret, payload))
+	}
+	req.Header.Set("X-Rekko-Event", event.Type)
+	req.Header.Set("X-Rekko-Delivery", event.DeliveryID.String())
+	req.Header.Set("User-Agent", "Rekko-Webhook/1.0")
+
+	return req, payload, nil
+}
+
+// signsWithPrevious reports whether the secret replaced by the last rotation is still in its grace window
+func (w *Webhook) signsWithPrevious(now time.Time) bool {
+	return w.PreviousSecret != "" && w.PreviousSecretExpiresAt != nil && now.Before(*w.PreviousSecretExpiresAt)
+}
@@ -0,0 +1,323 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEventPayload_RequestID(t *testing.T) {
+	tenantID := uuid.New()
+
+	t.Run("carries request id from context", func(t *testing.T) {
+		ctx := WithRequestID(context.Background(), "req-123")
+		event := NewEventPayload(ctx, tenantID, "face.registered", map[string]interface{}{"face_id": "abc"})
+
+		assert.Equal(t, "req-123", event.RequestID)
+		assert.Equal(t, "face.registered", event.Type)
+		assert.Equal(t, tenantID, event.TenantID)
+	})
+
+	t.Run("background events have no request id", func(t *testing.T) {
+		event := NewEventPayload(context.Background(), tenantID, "usage.quota_warning", nil)
+		assert.Empty(t, event.RequestID)
+	})
+}
+
+func TestNewDeliveryRequest_IDs(t *testing.T) {
+	wh := &Webhook{ID: uuid.New(), URL: "https://example.com/hook", Secret: "secret"}
+	ctx := WithRequestID(context.Background(), "req-456")
+	event := NewEventPayload(ctx, uuid.New(), "face.verified", map[string]interface{}{"verified": true})
+
+	decode := func(t *testing.T) (EventPayload, string) {
+		t.Helper()
+		req, payload, err := newDeliveryRequest(context.Background(), wh, event)
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		assert.Equal(t, payload, body)
+		assert.True(t, Verify(wh.Secret, body, req.Header.Get("X-Rekko-Signature")))
+
+		var got EventPayload
+		require.NoError(t, json.Unmarshal(body, &got))
+		return got, req.Header.Get("X-Rekko-Delivery")
+	}
+
+	first, firstHeader := decode(t)
+	retry, retryHeader := decode(t)
+
+	assert.Equal(t, "req-456", first.RequestID)
+	assert.Equal(t, "req-456", retry.RequestID)
+
+	assert.NotEqual(t, uuid.Nil, first.EventID)
+	assert.Equal(t, event.EventID, first.EventID)
+	assert.Equal(t, first.EventID, retry.EventID, "event_id must be stable across attempts")
+
+	assert.NotEqual(t, uuid.Nil, first.DeliveryID)
+	assert.NotEqual(t, first.DeliveryID, retry.DeliveryID, "delivery_id must be unique per attempt")
+	assert.Equal(t, first.DeliveryID.String(), firstHeader)
+	assert.Equal(t, retry.DeliveryID.String(), retryHeader)
+}
+
+// payloadCapture matches any queued payload and keeps it, as the worker will read it back
+type payloadCapture struct {
+	payload []byte
+}
+
+func (c *payloadCapture) Match(v interface{}) bool {
+	c.payload, _ = v.([]byte)
+	return c.payload != nil
+}
+
+func TestSend_RetryKeepsEventID(t *testing.T) {
+	var bodies []EventPayload
+	fail := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var got EventPayload
+		if err := json.NewDecoder(r.Body).Decode(&got); err == nil {
+			bodies = append(bodies, got)
+		}
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// deliver sends event to an endpoint that fails, then retries the queued payload the way
+	// the worker does, and returns what the endpoint received on each attempt
+	deliver := func(t *testing.T, event EventPayload) (EventPayload, EventPayload) {
+		t.Helper()
+		pool, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer pool.Close()
+
+		svc := NewServiceWithDB(pool, slog.New(slog.NewTextHandler(io.Discard, nil)))
+		wh := &Webhook{ID: uuid.New(), URL: server.URL, Secret: "secret"}
+		queued := &payloadCapture{}
+		pool.ExpectExec(`INSERT INTO webhook_queue`).
+			WithArgs(wh.ID, event.Type, queued, "HTTP 503").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		pool.ExpectExec(`UPDATE webhooks SET last_triggered_at`).
+			WithArgs(wh.ID).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		bodies, fail = nil, true
+		require.NoError(t, svc.Send(context.Background(), wh, event))
+
+		var retry EventPayload
+		require.NoError(t, json.Unmarshal(queued.payload, &retry))
+		fail = false
+		require.NoError(t, svc.Send(context.Background(), wh, retry))
+
+		require.NoError(t, pool.ExpectationsWereMet())
+		require.Len(t, bodies, 2)
+		return bodies[0], bodies[1]
+	}
+
+	t.Run("retries of one event share its event_id", func(t *testing.T) {
+		event := NewEventPayload(context.Background(), uuid.New(), "face.verified", map[string]interface{}{"verified": true})
+
+		first, retry := deliver(t, event)
+
+		assert.Equal(t, event.EventID, first.EventID)
+		assert.Equal(t, event.EventID, retry.EventID)
+		assert.NotEqual(t, first.DeliveryID, retry.DeliveryID)
+	})
+
+	t.Run("events built without an event_id get one that retries keep", func(t *testing.T) {
+		event := EventPayload{Type: "alert.triggered", TenantID: uuid.New()}
+
+		first, retry := deliver(t, event)
+
+		assert.NotEqual(t, uuid.Nil, first.EventID)
+		assert.Equal(t, first.EventID, retry.EventID)
+	})
+
+	t.Run("distinct events differ", func(t *testing.T) {
+		tenantID := uuid.New()
+		a, _ := deliver(t, NewEventPayload(context.Background(), tenantID, "face.verified", nil))
+		b, _ := deliver(t, NewEventPayload(context.Background(), tenantID, "face.verified", nil))
+
+		assert.NotEqual(t, a.EventID, b.EventID)
+	})
+}
+
+// countingTracker records the usage fields incremented per tenant
+type countingTracker struct {
+	counts map[string]int
+}
+
+func (c *countingTracker) IncrementDaily(ctx context.Context, tenantID uuid.UUID, date time.Time, field string, amount int) error {
+	c.counts[tenantID.String()+"/"+field] += amount
+	return nil
+}
+
+func TestSend_TracksAcceptedDeliveries(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	pool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer pool.Close()
+
+	tracker := &countingTracker{counts: map[string]int{}}
+	svc := NewServiceWithDB(pool, slog.New(slog.NewTextHandler(io.Discard, nil))).WithUsageTracker(tracker)
+	wh := &Webhook{ID: uuid.New(), TenantID: uuid.New(), URL: server.URL, Secret: "secret"}
+	event := NewEventPayload(context.Background(), wh.TenantID, "face.registered", nil)
+	key := wh.TenantID.String() + "/webhook_deliveries"
+
+	pool.ExpectExec(`UPDATE webhooks SET last_triggered_at`).
+		WithArgs(wh.ID).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	require.NoError(t, svc.Send(context.Background(), wh, event))
+	assert.Equal(t, 1, tracker.counts[key])
+
+	// A rejected delivery is queued for retry, not counted
+	status = http.StatusInternalServerError
+	pool.ExpectExec(`INSERT INTO webhook_queue`).
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	require.NoError(t, svc.Send(context.Background(), wh, event))
+	assert.Equal(t, 1, tracker.counts[key])
+
+	// The worker's retry of the queued job is counted once accepted
+	status = http.StatusOK
+	req, _, err := newDeliveryRequest(context.Background(), wh, event)
+	require.NoError(t, err)
+	require.NoError(t, svc.post(req))
+	pool.ExpectExec(`UPDATE webhooks SET last_triggered_at`).
+		WithArgs(wh.ID).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	require.NoError(t, svc.delivered(context.Background(), wh))
+	assert.Equal(t, 2, tracker.counts[key])
+
+	assert.NoError(t, pool.ExpectationsWereMet())
+}
+
+func TestNewDeliveryRequest_CustomHeaders(t *testing.T) {
+	received := make(chan http.Header, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	wh := &Webhook{
+		ID:     uuid.New(),
+		URL:    server.URL,
+		Secret: "secret",
+		Headers: map[string]string{
+			"X-Api-Key":   "gateway-key",
+			"x-route-tag": "biometrics",
+		},
+	}
+	event := NewEventPayload(context.Background(), uuid.New(), "face.registered", nil)
+
+	req, _, err := newDeliveryRequest(context.Background(), wh, event)
+	require.NoError(t, err)
+	resp, err := server.Client().Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	headers := <-received
+	assert.Equal(t, "gateway-key", headers.Get("X-Api-Key"))
+	assert.Equal(t, "biometrics", headers.Get("X-Route-Tag"))
+	assert.Equal(t, "application/json", headers.Get("Content-Type"))
+	assert.Equal(t, "face.registered", headers.Get("X-Rekko-Event"))
+	assert.NotEmpty(t, headers.Get("X-Rekko-Signature"))
+}
+
+func TestNewDeliveryRequest_RotatedSecret(t *testing.T) {
+	event := NewEventPayload(context.Background(), uuid.New(), "face.registered", nil)
+	newDelivery := func(expiresAt time.Time) (http.Header, []byte) {
+		wh := &Webhook{
+			ID:                      uuid.New(),
+			URL:                     "https://crm.example.com/hooks",
+			Secret:                  "new-secret",
+			PreviousSecret:          "old-secret",
+			PreviousSecretExpiresAt: &expiresAt,
+		}
+		req, payload, err := newDeliveryRequest(context.Background(), wh, event)
+		require.NoError(t, err)
+		return req.Header, payload
+	}
+
+	t.Run("consumers on either secret validate during the grace window", func(t *testing.T) {
+		header, payload := newDelivery(time.Now().Add(time.Hour))
+
+		assert.True(t, Verify("new-secret", payload, header.Get("X-Rekko-Signature")))
+		assert.True(t, Verify("old-secret", payload, header.Get("X-Rekko-Signature-Previous")))
+		assert.True(t, VerifyDelivery("new-secret", payload, header))
+		assert.True(t, VerifyDelivery("old-secret", payload, header))
+		assert.False(t, VerifyDelivery("other-secret", payload, header))
+	})
+
+	t.Run("old secret stops validating once the window expires", func(t *testing.T) {
+		header, payload := newDelivery(time.Now().Add(-time.Minute))
+
+		assert.Empty(t, header.Get("X-Rekko-Signature-Previous"))
+		assert.True(t, VerifyDelivery("new-secret", payload, header))
+		assert.False(t, VerifyDelivery("old-secret", payload, header))
+	})
+}
+
+func TestValidateGracePeriod(t *testing.T) {
+	assert.NoError(t, ValidateGracePeriod(DefaultSecretGracePeriod))
+	assert.NoError(t, ValidateGracePeriod(MaxSecretGracePeriod))
+	assert.ErrorIs(t, ValidateGracePeriod(0), ErrInvalidGracePeriod)
+	assert.ErrorIs(t, ValidateGracePeriod(-time.Hour), ErrInvalidGracePeriod)
+	assert.ErrorIs(t, ValidateGracePeriod(MaxSecretGracePeriod+time.Second), ErrInvalidGracePeriod)
+}
+
+func TestValidateHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		wantErr bool
+	}{
+		{name: "none", headers: nil},
+		{name: "valid names", headers: map[string]string{"X-Api-Key": "k", "Routing_Tag": "eu"}},
+		{name: "empty name", headers: map[string]string{"": "v"}, wantErr: true},
+		{name: "space in name", headers: map[string]string{"X Api": "v"}, wantErr: true},
+		{name: "colon in name", headers: map[string]string{"X-Api:": "v"}, wantErr: true},
+		{name: "line break in value", headers: map[string]string{"X-Api-Key": "k\r\nX-Evil: 1"}, wantErr: true},
+		{name: "content type is reserved", headers: map[string]string{"content-type": "text/plain"}, wantErr: true},
+		{name: "rekko headers are reserved", headers: map[string]string{"X-Rekko-Signature": "sha256=forged"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateHeaders(tt.headers)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidHeader)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("too many headers", func(t *testing.T) {
+		headers := make(map[string]string, MaxCustomHeaders+1)
+		for i := 0; i <= MaxCustomHeaders; i++ {
+			headers["X-Header-"+strconv.Itoa(i)] = "v"
+		}
+		assert.ErrorIs(t, ValidateHeaders(headers), ErrInvalidHeader)
+	})
+}
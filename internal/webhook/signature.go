@@ -2,10 +2,24 @@ package webhook
 
 import (
 	"crypto/hmac"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
+	"net/http"
 )
 
+// secretLength is the number of random bytes in a webhook signing secret
+const secretLength = 32
+
+// GenerateSecret returns a new random signing secret, hex encoded
+func GenerateSecret() (string, error) {
+	secret := make([]byte, secretLength)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
 func Sign(secret string, payload []byte) string {
 	mac := hmac.New(sha256.New, []byte(secret))
 	mac.Write(payload)
@@ -16,3 +30,14 @@ func Verify(secret string, payload []byte, signature string) bool {
 	expectedSignature := Sign(secret, payload)
 	return hmac.Equal([]byte(signature), []byte(expectedSignature))
 }
+
+// VerifyDelivery checks a delivery against the consumer's secret. After a secret rotation,
+// deliveries carry X-Rekko-Signature made with the new secret and, during the grace window,
+// X-Rekko-Signature-Previous made with the replaced one, so either secret validates.
+func VerifyDelivery(secret string, payload []byte, header http.Header) bool {
+	if Verify(secret, payload, header.Get("X-Rekko-Signature")) {
+		return true
+	}
+	previous := header.Get("X-Rekko-Signature-Previous")
+	return previous != "" && Verify(secret, payload, previous)
+}
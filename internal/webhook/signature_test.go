@@ -82,3 +82,13 @@ func TestVerify(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateSecret(t *testing.T) {
+	first, err := GenerateSecret()
+	assert.NoError(t, err)
+	second, err := GenerateSecret()
+	assert.NoError(t, err)
+
+	assert.Len(t, first, 2*secretLength, "hex encoded")
+	assert.NotEqual(t, first, second)
+}
@@ -1,27 +1,36 @@
 package webhook
 
 import (
+	"context"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type Webhook struct {
-	ID              uuid.UUID  `json:"id"`
-	TenantID        uuid.UUID  `json:"tenant_id"`
-	Name            string     `json:"name"`
-	URL             string     `json:"url"`
-	Secret          string     `json:"-"`
-	Events          []string   `json:"events"`
-	Enabled         bool       `json:"enabled"`
-	LastTriggeredAt *time.Time `json:"last_triggered_at,omitempty"`
-	CreatedAt       time.Time  `json:"created_at"`
-	UpdatedAt       time.Time  `json:"updated_at"`
+	ID       uuid.UUID `json:"id"`
+	TenantID uuid.UUID `json:"tenant_id"`
+	Name     string    `json:"name"`
+	URL      string    `json:"url"`
+	Secret   string    `json:"-"`
+	// PreviousSecret still signs deliveries until PreviousSecretExpiresAt, see RotateSecret
+	PreviousSecret          string     `json:"-"`
+	PreviousSecretExpiresAt *time.Time `json:"previous_secret_expires_at,omitempty"`
+	Events                  []string   `json:"events"`
+	Enabled                 bool       `json:"enabled"`
+	// Headers are static headers added to every delivery, see ValidateHeaders
+	Headers map[string]string `json:"headers,omitempty"`
+	// SampleRates delivers 1 in N successful events of a type, see ValidateSampleRates
+	SampleRates     map[string]int `json:"sample_rates,omitempty"`
+	LastTriggeredAt *time.Time     `json:"last_triggered_at,omitempty"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
 }
 
 type WebhookJob struct {
 	ID          uuid.UUID  `json:"id"`
 	WebhookID   uuid.UUID  `json:"webhook_id"`
+	TenantID    uuid.UUID  `json:"tenant_id"`
 	EventType   string     `json:"event_type"`
 	Payload     []byte     `json:"payload"`
 	Attempts    int        `json:"attempts"`
@@ -34,8 +43,27 @@ type WebhookJob struct {
 }
 
 type EventPayload struct {
+	// EventID identifies the logical event: every webhook and every retry of it carries the same
+	// ID, so consumers can dedupe the at-least-once deliveries
+	EventID   uuid.UUID   `json:"event_id"`
 	Type      string      `json:"type"`
 	Data      interface{} `json:"data"`
 	TenantID  uuid.UUID   `json:"tenant_id"`
 	Timestamp time.Time   `json:"timestamp"`
+	// RequestID is the API request that originated the event (empty for background events)
+	RequestID string `json:"request_id,omitempty"`
+	// DeliveryID identifies a single delivery attempt, regenerated on every retry
+	DeliveryID uuid.UUID `json:"delivery_id"`
+}
+
+// NewEventPayload builds the event envelope, carrying the request ID attached to ctx
+func NewEventPayload(ctx context.Context, tenantID uuid.UUID, eventType string, data interface{}) EventPayload {
+	return EventPayload{
+		EventID:   uuid.New(),
+		Type:      eventType,
+		Timestamp: time.Now().UTC(),
+		TenantID:  tenantID,
+		Data:      data,
+		RequestID: RequestIDFromContext(ctx),
+	}
 }
@@ -0,0 +1,162 @@
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventWebhookTest is only sent by test deliveries; webhooks cannot subscribe to it
+const EventWebhookTest = "webhook.test"
+
+// maxConcurrentTestDeliveries bounds the test deliveries of one ValidateAll call
+const maxConcurrentTestDeliveries = 5
+
+// forgedSecret signs the delivery a correctly configured endpoint must reject
+const forgedSecret = "rekko-webhook-test-invalid-secret"
+
+type WebhookTestData struct {
+	WebhookID string `json:"webhook_id"`
+	Message   string `json:"message"`
+}
+
+// TestDeliveryResult is the outcome of a test delivery to one webhook
+type TestDeliveryResult struct {
+	WebhookID uuid.UUID `json:"webhook_id"`
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	Enabled   bool      `json:"enabled"`
+	// OK is set when the signed delivery was accepted and the forged one rejected
+	OK         bool  `json:"ok"`
+	StatusCode int   `json:"status_code,omitempty"`
+	LatencyMs  int64 `json:"latency_ms"`
+	// TLS is set when the URL is https and its certificate was accepted
+	TLS bool `json:"tls"`
+	// SignatureEnforced reports whether a delivery with an invalid signature was rejected;
+	// null when the signed delivery already failed
+	SignatureEnforced *bool  `json:"signature_enforced"`
+	Error             string `json:"error,omitempty"`
+}
+
+// TestDelivery sends a signed webhook.test event to the webhook, then the same event with an
+// invalid signature, which the endpoint must reject with a 4xx or 5xx. Failures are reported in
+// the result, never queued for retry, and last_triggered_at is left untouched.
+func (s *Service) TestDelivery(ctx context.Context, webhook *Webhook) TestDeliveryResult {
+	result := TestDeliveryResult{
+		WebhookID: webhook.ID,
+		Name:      webhook.Name,
+		URL:       webhook.URL,
+		Enabled:   webhook.Enabled,
+	}
+
+	event := NewEventPayload(ctx, webhook.TenantID, EventWebhookTest, WebhookTestData{
+		WebhookID: webhook.ID.String(),
+		Message:   "Test delivery from Rekko",
+	})
+
+	start := time.Now()
+	status, err := s.sendTest(ctx, webhook, event, false)
+	result.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Error = describeDeliveryError(err)
+		return result
+	}
+	result.StatusCode = status
+	result.TLS = strings.HasPrefix(strings.ToLower(webhook.URL), "https://")
+	if status < 200 || status >= 300 {
+		result.Error = fmt.Sprintf("endpoint returned HTTP %d", status)
+		return result
+	}
+
+	status, err = s.sendTest(ctx, webhook, event, true)
+	if err != nil {
+		result.Error = "invalid signature delivery: " + describeDeliveryError(err)
+		return result
+	}
+	enforced := status >= 400
+	result.SignatureEnforced = &enforced
+	if !enforced {
+		result.Error = fmt.Sprintf("endpoint accepted a delivery with an invalid signature (HTTP %d)", status)
+		return result
+	}
+
+	result.OK = true
+	return result
+}
+
+// ValidateAll sends a test delivery to every webhook of the tenant, enabled or not, and
+// returns the results in the order of GetWebhooksByTenant
+func (s *Service) ValidateAll(ctx context.Context, tenantID uuid.UUID) ([]TestDeliveryResult, error) {
+	webhooks, err := s.GetWebhooksByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("get webhooks: %w", err)
+	}
+	return s.testDeliveries(ctx, webhooks), nil
+}
+
+func (s *Service) testDeliveries(ctx context.Context, webhooks []*Webhook) []TestDeliveryResult {
+	results := make([]TestDeliveryResult, len(webhooks))
+	slots := make(chan struct{}, maxConcurrentTestDeliveries)
+
+	var wg sync.WaitGroup
+	for i, wh := range webhooks {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, wh *Webhook) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			results[i] = s.TestDelivery(ctx, wh)
+		}(i, wh)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// sendTest performs one test request and returns its status code
+func (s *Service) sendTest(ctx context.Context, webhook *Webhook, event EventPayload, forged bool) (int, error) {
+	req, payload, err := newDeliveryRequest(ctx, webhook, event)
+	if err != nil {
+		return 0, err
+	}
+	if forged {
+		req.Header.Set("X-Rekko-Signature", Sign(forgedSecret, payload))
+		if req.Header.Get("X-Rekko-Signature-Previous") != "" {
+			req.Header.Set("X-Rekko-Signature-Previous", Sign(forgedSecret, payload))
+		}
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	return resp.StatusCode, nil
+}
+
+// describeDeliveryError tells certificate problems apart from unreachable endpoints
+func describeDeliveryError(err error) string {
+	var (
+		unknownAuthority x509.UnknownAuthorityError
+		invalidCert      x509.CertificateInvalidError
+		hostnameErr      x509.HostnameError
+		verificationErr  *tls.CertificateVerificationError
+	)
+	if errors.As(err, &unknownAuthority) || errors.As(err, &invalidCert) ||
+		errors.As(err, &hostnameErr) || errors.As(err, &verificationErr) {
+		return "invalid TLS certificate: " + err.Error()
+	}
+	return "unreachable: " + err.Error()
+}
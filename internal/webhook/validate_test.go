@@ -0,0 +1,132 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// verifyingEndpoint accepts webhook.test deliveries signed with secret and rejects the rest
+func verifyingEndpoint(t *testing.T, secret string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		payload, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		if !Verify(secret, payload, r.Header.Get("X-Rekko-Signature")) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var event EventPayload
+		require.NoError(t, json.Unmarshal(payload, &event))
+		assert.Equal(t, EventWebhookTest, event.Type)
+		assert.Equal(t, EventWebhookTest, r.Header.Get("X-Rekko-Event"))
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestService_TestDeliveries_MixedResults(t *testing.T) {
+	const secret = "whsec"
+
+	verifying := httptest.NewTLSServer(verifyingEndpoint(t, secret))
+	defer verifying.Close()
+	acceptsAll := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer acceptsAll.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	gone := httptest.NewServer(http.NotFoundHandler())
+	gone.Close()
+
+	webhooks := []*Webhook{
+		{ID: uuid.New(), Name: "verifying", URL: verifying.URL, Secret: secret, Enabled: true},
+		{ID: uuid.New(), Name: "accepts-all", URL: acceptsAll.URL, Secret: secret, Enabled: true},
+		{ID: uuid.New(), Name: "failing", URL: failing.URL, Secret: secret},
+		{ID: uuid.New(), Name: "gone", URL: gone.URL, Secret: secret, Enabled: true},
+	}
+
+	// The TLS server's client trusts its certificate and still talks plain HTTP
+	s := &Service{client: verifying.Client(), sampler: newSampler()}
+	results := s.testDeliveries(context.Background(), webhooks)
+	require.Len(t, results, 4)
+
+	ok := results[0]
+	assert.Equal(t, webhooks[0].ID, ok.WebhookID)
+	assert.True(t, ok.OK)
+	assert.True(t, ok.TLS)
+	assert.Equal(t, http.StatusNoContent, ok.StatusCode)
+	require.NotNil(t, ok.SignatureEnforced)
+	assert.True(t, *ok.SignatureEnforced)
+	assert.Empty(t, ok.Error)
+
+	unverified := results[1]
+	assert.False(t, unverified.OK)
+	assert.False(t, unverified.TLS)
+	require.NotNil(t, unverified.SignatureEnforced)
+	assert.False(t, *unverified.SignatureEnforced)
+	assert.Contains(t, unverified.Error, "invalid signature")
+
+	failed := results[2]
+	assert.False(t, failed.OK)
+	assert.False(t, failed.Enabled)
+	assert.Equal(t, http.StatusBadGateway, failed.StatusCode)
+	assert.Nil(t, failed.SignatureEnforced)
+	assert.Equal(t, "endpoint returned HTTP 502", failed.Error)
+
+	unreachable := results[3]
+	assert.False(t, unreachable.OK)
+	assert.Zero(t, unreachable.StatusCode)
+	assert.Contains(t, unreachable.Error, "unreachable")
+}
+
+func TestService_TestDelivery_InvalidCertificate(t *testing.T) {
+	srv := httptest.NewTLSServer(verifyingEndpoint(t, "whsec"))
+	defer srv.Close()
+
+	// A default client doesn't trust the test server's self-signed certificate
+	s := &Service{client: &http.Client{}, sampler: newSampler()}
+	result := s.TestDelivery(context.Background(), &Webhook{ID: uuid.New(), URL: srv.URL, Secret: "whsec"})
+
+	assert.False(t, result.OK)
+	assert.False(t, result.TLS)
+	assert.Contains(t, result.Error, "invalid TLS certificate")
+}
+
+func TestService_TestDelivery_RotatedSecret(t *testing.T) {
+	// Consumer still on the replaced secret, accepting either signature header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		if !VerifyDelivery("old-secret", payload, r.Header) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	expiresAt := time.Now().Add(time.Hour)
+	s := &Service{client: srv.Client(), sampler: newSampler()}
+	result := s.TestDelivery(context.Background(), &Webhook{
+		ID:                      uuid.New(),
+		URL:                     srv.URL,
+		Secret:                  "new-secret",
+		PreviousSecret:          "old-secret",
+		PreviousSecretExpiresAt: &expiresAt,
+	})
+
+	// The forged delivery must not slip through the previous signature header
+	require.NotNil(t, result.SignatureEnforced)
+	assert.True(t, *result.SignatureEnforced)
+	assert.Empty(t, result.Error)
+}
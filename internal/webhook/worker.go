@@ -11,27 +11,44 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// queueBatchSize is how many pending jobs are claimed per poll at most, interleaved across tenants
+const queueBatchSize = 100
+
+// claimTimeout is how long a claimed job may stay processing before another poll reclaims it,
+// so jobs of a worker that died mid-delivery are not lost. Well above the 10s delivery timeout.
+const claimTimeout = 5 * time.Minute
+
 type Worker struct {
-	db      *pgxpool.Pool
-	service *Service
-	logger  *slog.Logger
-	stopCh  chan struct{}
+	db        *pgxpool.Pool
+	service   *Service
+	logger    *slog.Logger
+	stopCh    chan struct{}
+	scheduler *deliveryScheduler
 }
 
 func NewWorker(db *pgxpool.Pool, service *Service, logger *slog.Logger) *Worker {
 	return &Worker{
-		db:      db,
-		service: service,
-		logger:  logger,
-		stopCh:  make(chan struct{}),
+		db:        db,
+		service:   service,
+		logger:    logger,
+		stopCh:    make(chan struct{}),
+		scheduler: newDeliveryScheduler(DefaultTenantConcurrency, maxConcurrentDeliveries),
 	}
 }
 
+// WithTenantConcurrency caps the deliveries of one tenant that run at the same time,
+// so a tenant with many or slow webhooks can't delay the others
+func (w *Worker) WithTenantConcurrency(n int) *Worker {
+	w.scheduler = newDeliveryScheduler(n, maxConcurrentDeliveries)
+	return w
+}
+
 func (w *Worker) Run(ctx context.Context) {
 	ticker := time.NewTicker(5 * time.Second)
 	defer ticker.Stop()
 
-	w.logger.Info("webhook worker started")
+	w.logger.Info("webhook worker started", "tenant_concurrency", w.scheduler.perTenant)
+	defer w.scheduler.wait()
 
 	for {
 		select {
@@ -53,44 +70,101 @@ func (w *Worker) Stop() {
 	close(w.stopCh)
 }
 
+// processQueue claims the due deliveries and starts them in the background. Jobs are claimed
+// round-robin across tenants (each tenant's oldest first, at most its concurrency cap per
+// tenant) by moving them to processing, with SKIP LOCKED so concurrent workers never claim
+// the same job. Claimed jobs that don't fit the caps are put back to pending for the next poll.
 func (w *Worker) processQueue(ctx context.Context) error {
+	// FOR UPDATE can't share a query level with the window function, so rows are locked first
 	query := `
-		SELECT id, webhook_id, event_type, payload, attempts, max_attempts
-		FROM webhook_queue
-		WHERE status = 'pending' AND (next_retry_at IS NULL OR next_retry_at <= NOW())
-		ORDER BY created_at ASC
-		FOR UPDATE SKIP LOCKED
-		LIMIT 10
+		WITH due AS (
+			SELECT q.id, wh.tenant_id, q.created_at
+			FROM webhook_queue q
+			JOIN webhooks wh ON wh.id = q.webhook_id
+			WHERE (q.status = 'pending' AND (q.next_retry_at IS NULL OR q.next_retry_at <= NOW()))
+			   OR (q.status = 'processing' AND q.updated_at < NOW() - $3 * INTERVAL '1 second')
+			FOR UPDATE OF q SKIP LOCKED
+		), claimed AS (
+			SELECT id, tenant_id
+			FROM (
+				SELECT id, tenant_id, created_at,
+				       ROW_NUMBER() OVER (PARTITION BY tenant_id ORDER BY created_at) AS tenant_rank
+				FROM due
+			) ranked
+			WHERE tenant_rank <= $1
+			ORDER BY tenant_rank, created_at
+			LIMIT $2
+		)
+		UPDATE webhook_queue q
+		SET status = 'processing', updated_at = NOW()
+		FROM claimed
+		WHERE q.id = claimed.id
+		RETURNING q.id, q.webhook_id, claimed.tenant_id, q.event_type, q.payload, q.attempts, q.max_attempts
 	`
 
-	rows, err := w.db.Query(ctx, query)
+	w.scheduler.poll()
+	limit := min(w.scheduler.free(), queueBatchSize)
+	if limit <= 0 {
+		return nil
+	}
+	rows, err := w.db.Query(ctx, query, w.scheduler.perTenant, limit, int(claimTimeout.Seconds()))
 	if err != nil {
-		return fmt.Errorf("query webhook queue: %w", err)
+		return fmt.Errorf("claim webhook jobs: %w", err)
 	}
-	defer rows.Close()
 
+	var jobs []*WebhookJob
 	for rows.Next() {
 		var job WebhookJob
 
 		err := rows.Scan(
-			&job.ID, &job.WebhookID, &job.EventType,
+			&job.ID, &job.WebhookID, &job.TenantID, &job.EventType,
 			&job.Payload, &job.Attempts, &job.MaxAttempts,
 		)
 		if err != nil {
 			w.logger.Error("failed to scan webhook job", "error", err)
 			continue
 		}
+		jobs = append(jobs, &job)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("read webhook queue: %w", err)
+	}
 
-		if err := w.processJob(ctx, &job); err != nil {
+	var skipped []uuid.UUID
+	w.scheduler.schedule(jobs, func(job *WebhookJob) {
+		if err := w.processJob(ctx, job); err != nil {
 			w.logger.Error("failed to process webhook job",
 				"job_id", job.ID,
 				"webhook_id", job.WebhookID,
+				"tenant_id", job.TenantID,
 				"attempts", job.Attempts,
 				"error", err,
 			)
 		}
+	}, func(job *WebhookJob) {
+		skipped = append(skipped, job.ID)
+	})
+
+	return w.releaseJobs(ctx, skipped)
+}
+
+// releaseJobs puts claimed jobs that were not started back to pending
+func (w *Worker) releaseJobs(ctx context.Context, jobIDs []uuid.UUID) error {
+	if len(jobIDs) == 0 {
+		return nil
 	}
 
+	query := `
+		UPDATE webhook_queue
+		SET status = 'pending',
+		    updated_at = NOW()
+		WHERE id = ANY($1) AND status = 'processing'
+	`
+
+	if _, err := w.db.Exec(ctx, query, jobIDs); err != nil {
+		return fmt.Errorf("release webhook jobs: %w", err)
+	}
 	return nil
 }
 
@@ -109,26 +183,36 @@ func (w *Worker) processJob(ctx context.Context, job *WebhookJob) error {
 		return w.markFailed(ctx, job.ID, fmt.Sprintf("invalid payload: %v", err))
 	}
 
-	if err := w.service.Send(ctx, webhook, event); err != nil {
+	// Retries are scheduled on this job, Send would queue a new one
+	req, _, err := newDeliveryRequest(ctx, webhook, event)
+	if err != nil {
+		return w.markFailed(ctx, job.ID, err.Error())
+	}
+	if err := w.service.post(req); err != nil {
 		return w.scheduleRetry(ctx, job, err.Error())
 	}
 
-	return w.markComplete(ctx, job.ID)
+	if err := w.markComplete(ctx, job.ID); err != nil {
+		return err
+	}
+	return w.service.delivered(ctx, webhook)
 }
 
 func (w *Worker) getWebhook(ctx context.Context, webhookID uuid.UUID) (*Webhook, error) {
 	query := `
-		SELECT id, tenant_id, name, url, secret, events, enabled, last_triggered_at, created_at, updated_at
+		SELECT id, tenant_id, name, url, secret, COALESCE(previous_secret, ''), previous_secret_expires_at,
+		       events, headers, sample_rates, enabled, last_triggered_at, created_at, updated_at
 		FROM webhooks
 		WHERE id = $1
 	`
 
 	var webhook Webhook
-	var eventsJSON []byte
+	var eventsJSON, headersJSON, sampleRatesJSON []byte
 
 	err := w.db.QueryRow(ctx, query, webhookID).Scan(
 		&webhook.ID, &webhook.TenantID, &webhook.Name, &webhook.URL, &webhook.Secret,
-		&eventsJSON, &webhook.Enabled, &webhook.LastTriggeredAt,
+		&webhook.PreviousSecret, &webhook.PreviousSecretExpiresAt,
+		&eventsJSON, &headersJSON, &sampleRatesJSON, &webhook.Enabled, &webhook.LastTriggeredAt,
 		&webhook.CreatedAt, &webhook.UpdatedAt,
 	)
 	if err != nil {
@@ -138,6 +222,12 @@ func (w *Worker) getWebhook(ctx context.Context, webhookID uuid.UUID) (*Webhook,
 	if err := json.Unmarshal(eventsJSON, &webhook.Events); err != nil {
 		return nil, err
 	}
+	if err := json.Unmarshal(headersJSON, &webhook.Headers); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(sampleRatesJSON, &webhook.SampleRates); err != nil {
+		return nil, err
+	}
 
 	return &webhook, nil
 }
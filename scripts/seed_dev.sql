@@ -65,6 +65,7 @@ INSERT INTO api_keys (
     key_prefix,
     environment,
     is_active,
+    scopes,
     created_at
 )
 VALUES (
@@ -75,6 +76,7 @@ VALUES (
     'rekko_test_devd',
     'test',
     true,
+    ARRAY['admin:read', 'admin:write'],
     NOW()
 );
 